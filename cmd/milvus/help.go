@@ -13,7 +13,7 @@ const (
 
 var (
 	usageLine = fmt.Sprintf("Usage:\n"+
-		"%s\n%s\n%s\n%s\n", runLine, stopLine, mckLine, serverTypeLine)
+		"%s\n%s\n%s\n%s\n%s\n%s\n", runLine, stopLine, mckLine, preflightLine, migrateBinlogLine, serverTypeLine)
 
 	serverTypeLine = `
 [server type]
@@ -42,6 +42,27 @@ milvus stop [server type] [flags]
 [flags]
 	-alias ''
 		Set alias
+`
+	preflightLine = `
+milvus preflight [server type]
+	Validate the config and probe etcd, MQ and object storage by small write/read, without starting the server.
+	Tips: The server type decides which MQ is used, set common.preflight.enabled to check on every startup.
+`
+	migrateBinlogLine = `
+milvus migrate-binlog [flags]
+	Rewrite the binlogs, deltalogs and statslogs of the outdated formats in the current ones, for upgrades.
+	Tips: The progress is checkpointed in etcd, run it again to resume an interrupted migration.
+[flags]
+	-etcdIp ''
+		Ip to connect the ectd server.
+	-etcdRootPath ''
+		The root path of operating the etcd data.
+	-targetRootPath ''
+		The root path to write the migrated objects to, the objects are rewritten in place if empty.
+	-checkpointInterval '100'
+		The number of the objects migrated between two checkpoints.
+	-restart 'false'
+		Discard the checkpoint and migrate from the beginning.
 `
 	mckLine = `
 milvus mck run [flags]
@@ -66,5 +87,12 @@ milvus mck run [flags]
 milvus mck cleanTrash [flags]
 	Clean the back inconsistent data
 	Tips: The flags is the same as its of the 'milvus mck [flags]'
+
+milvus mck orphan [flags]
+	List the binlogs and index files not referenced by any segment or index meta, and delete them after confirmation
+	Tips: The flags is the same as its of the 'milvus mck [flags]'
+[flags]
+	-minAge '24h'
+		Only the objects not modified within this duration are reported
 `
 )
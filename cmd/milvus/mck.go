@@ -30,9 +30,10 @@ import (
 )
 
 const (
-	MckCmd       = "mck"
-	MckTypeRun   = "run"
-	MckTypeClean = "cleanTrash"
+	MckCmd        = "mck"
+	MckTypeRun    = "run"
+	MckTypeClean  = "cleanTrash"
+	MckTypeOrphan = "orphan"
 
 	segmentPrefix     = "datacoord-meta/s"
 	collectionPrefix  = "snapshots/root-coord/collection"
@@ -57,11 +58,13 @@ type mck struct {
 
 	etcdIP          string
 	ectdRootPath    string
+	metaRootPath    string
 	minioAddress    string
 	minioUsername   string
 	minioPassword   string
 	minioUseSSL     string
 	minioBucketName string
+	orphanMinAge    string
 
 	flagStartIndex int
 }
@@ -85,8 +88,7 @@ func (c *mck) execute(args []string, flags *flag.FlagSet) {
 
 	mckType := args[2]
 	c.flagStartIndex = 2
-	isClean := mckType == MckTypeClean
-	if isClean {
+	if mckType == MckTypeClean || mckType == MckTypeOrphan {
 		c.flagStartIndex = 3
 	}
 	c.formatFlags(args, flags)
@@ -98,6 +100,8 @@ func (c *mck) execute(args []string, flags *flag.FlagSet) {
 	case MckTypeClean:
 		c.cleanTrash()
 		return
+	case MckTypeOrphan:
+		c.scanOrphan()
 	default:
 		fmt.Fprintln(os.Stderr, mckLine)
 		return
@@ -202,8 +206,9 @@ func (c *mck) formatFlags(args []string, flags *flag.FlagSet) {
 	flags.StringVar(&c.minioPassword, "minioPassword", "", "Minio password")
 	flags.StringVar(&c.minioUseSSL, "minioUseSSL", "", "Minio to use ssl")
 	flags.StringVar(&c.minioBucketName, "minioBucketName", "", "Minio bucket name")
+	flags.StringVar(&c.orphanMinAge, "minAge", "24h", "Only report orphan objects older than it")
 
-	if err := flags.Parse(os.Args[2:]); err != nil {
+	if err := flags.Parse(os.Args[c.flagStartIndex:]); err != nil {
 		log.Fatal("failed to parse flags", zap.Error(err))
 	}
 	log.Info("args", zap.Strings("args", args))
@@ -230,6 +235,7 @@ func (c *mck) connectEctd() {
 	}
 
 	rootPath := getConfigValue(c.ectdRootPath, c.params.EtcdCfg.MetaRootPath.GetValue(), "ectd_root_path")
+	c.metaRootPath = rootPath
 	c.metaKV = etcdkv.NewEtcdKV(etcdCli, rootPath)
 	log.Info("Etcd root path", zap.String("root_path", rootPath))
 }
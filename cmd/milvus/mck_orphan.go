@@ -0,0 +1,123 @@
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/metastore/kv/binlog"
+	"github.com/milvus-io/milvus/internal/metastore/kv/datacoord"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+)
+
+// scanOrphan lists the objects under the chunk manager root path which are not referenced
+// by any segment or segment index meta, and removes them after the operator confirms.
+func (c *mck) scanOrphan() {
+	c.connectMinio()
+	ctx := context.Background()
+
+	minAge, err := time.ParseDuration(c.orphanMinAge)
+	if err != nil {
+		log.Fatal("invalid orphan min age", zap.String("minAge", c.orphanMinAge), zap.Error(err))
+	}
+
+	isReferenced, err := c.buildReferencedFilter(ctx)
+	if err != nil {
+		log.Fatal("failed to load segment meta", zap.Error(err))
+	}
+
+	// the binlogs of the storage tenants are under the root paths of the tenants
+	rootPath := c.minioChunkManager.RootPath()
+	rootPaths, err := storage.ListRootPaths(ctx, c.minioChunkManager)
+	if err != nil {
+		log.Fatal("failed to list storage tenants", zap.String("rootPath", rootPath), zap.Error(err))
+	}
+	var prefixes []string
+	for _, rootPath := range rootPaths {
+		prefixes = append(prefixes, storage.OrphanScanPrefixes(rootPath)...)
+	}
+	orphans, err := storage.ScanOrphanObjects(ctx, c.minioChunkManager, prefixes, isReferenced, minAge, time.Now())
+	if err != nil {
+		log.Fatal("failed to scan orphan objects", zap.String("rootPath", rootPath), zap.Error(err))
+	}
+	if len(orphans) == 0 {
+		fmt.Printf("No orphan object older than %s under %s\n", minAge, rootPath)
+		return
+	}
+
+	line()
+	fmt.Printf("All orphan objects older than %s\n", minAge)
+	for _, orphan := range orphans {
+		line2()
+		fmt.Printf("Path: %s\tModTime: %s\tAge: %s\n", orphan.FilePath,
+			orphan.ModTime.Format("2006-01-02 15:04:05"), orphan.Age.Truncate(time.Second))
+	}
+	line()
+	fmt.Printf("Found %d orphan objects\n", len(orphans))
+
+	fmt.Print("Delete all orphan objects, [y/N]:")
+	deleteAll := ""
+	fmt.Scanln(&deleteAll)
+	if !strings.EqualFold(deleteAll, "y") {
+		return
+	}
+	if err := storage.RemoveOrphanObjects(ctx, c.minioChunkManager, orphans); err != nil {
+		log.Error("failed to remove orphan objects", zap.Error(err))
+		return
+	}
+	fmt.Printf("Deleted %d orphan objects\n", len(orphans))
+}
+
+// buildReferencedFilter returns a function telling whether an object is referenced by the
+// segment binlogs or the segment index files recorded in the datacoord meta.
+// Both are matched by the paths in the meta rather than by the ids parsed from the paths,
+// so the objects of no meta, e.g. the stale versions of an index build, are reported.
+func (c *mck) buildReferencedFilter(ctx context.Context) (func(string) bool, error) {
+	rootPath := c.minioChunkManager.RootPath()
+	catalog := datacoord.NewCatalog(c.metaKV, rootPath, c.metaRootPath)
+
+	segments, err := catalog.ListSegments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	referenced := make(map[string]struct{})
+	for _, segment := range segments {
+		if err := binlog.DecompressBinLogs(segment); err != nil {
+			return nil, err
+		}
+		for _, fieldBinlogs := range [][]*datapb.FieldBinlog{segment.GetBinlogs(), segment.GetStatslogs(), segment.GetDeltalogs()} {
+			for _, fieldBinlog := range fieldBinlogs {
+				for _, l := range fieldBinlog.GetBinlogs() {
+					referenced[l.GetLogPath()] = struct{}{}
+				}
+			}
+		}
+	}
+
+	segmentIndexes, err := catalog.ListSegmentIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	indexFiles := 0
+	for _, segmentIndex := range segmentIndexes {
+		// the same paths as the ones of the index files returned to the query nodes
+		for _, indexFilePath := range metautil.BuildSegmentIndexFilePaths(rootPath, segmentIndex.BuildID, segmentIndex.IndexVersion,
+			segmentIndex.PartitionID, segmentIndex.SegmentID, segmentIndex.IndexFileKeys) {
+			referenced[indexFilePath] = struct{}{}
+			indexFiles++
+		}
+	}
+	log.Info("loaded meta for orphan scan", zap.Int("segments", len(segments)),
+		zap.Int("binlogs", len(referenced)-indexFiles), zap.Int("indexFiles", indexFiles))
+
+	return func(filePath string) bool {
+		_, ok := referenced[filePath]
+		return ok
+	}, nil
+}
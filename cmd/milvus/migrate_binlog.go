@@ -0,0 +1,114 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package milvus
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/logutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+const (
+	MigrateBinlogCmd = "migrate-binlog"
+
+	migrateBinlogCheckpointPrefix = "migrate-binlog/checkpoint"
+)
+
+// migrateBinlog rewrites the binlogs, deltalogs and statslogs of the outdated formats under the chunk manager
+// root path, the progress is checkpointed in etcd so an interrupted migration is resumed by running it again.
+type migrateBinlog struct {
+	mck
+
+	targetRootPath     string
+	checkpointInterval int
+	restart            bool
+}
+
+func (c *migrateBinlog) execute(args []string, flags *flag.FlagSet) {
+	c.initParam()
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, migrateBinlogLine)
+	}
+
+	logutil.SetupLogger(&log.Config{
+		Level: "info",
+		File: log.FileLogConfig{
+			Filename: fmt.Sprintf("migrate-binlog-%s.log", time.Now().Format("20060102150405.99")),
+		},
+	})
+
+	flags.StringVar(&c.targetRootPath, "targetRootPath", "", "Root path to write the migrated objects, in place if empty")
+	flags.IntVar(&c.checkpointInterval, "checkpointInterval", 100, "Number of objects migrated between two checkpoints")
+	flags.BoolVar(&c.restart, "restart", false, "Discard the checkpoint and migrate from the beginning")
+	c.flagStartIndex = 2
+	c.formatFlags(args, flags)
+	c.connectEctd()
+	c.connectMinio()
+
+	ctx := context.Background()
+	target := c.targetRootPath
+	if target == "" {
+		target = c.minioChunkManager.RootPath()
+	}
+	checkpoint := &etcdBinlogMigrationCheckpoint{
+		metaKV: c.metaKV,
+		key:    path.Join(migrateBinlogCheckpointPrefix, target),
+	}
+	if c.restart {
+		if err := c.metaKV.Remove(checkpoint.key); err != nil {
+			log.Fatal("failed to remove the checkpoint", zap.String("key", checkpoint.key), zap.Error(err))
+		}
+	}
+
+	result, err := storage.NewBinlogMigrator(c.minioChunkManager, c.targetRootPath, checkpoint, c.checkpointInterval).Migrate(ctx)
+	if err != nil {
+		log.Fatal("failed to migrate the binlogs, run it again to resume", zap.Error(err))
+	}
+	line()
+	fmt.Printf("Migrated the binlogs under %s to %s\n", c.minioChunkManager.RootPath(), target)
+	fmt.Printf("Scanned: %d\tMigrated: %d\tCopied: %d\n", result.Scanned, result.Migrated, result.Copied)
+}
+
+// etcdBinlogMigrationCheckpoint keeps the checkpoint of a binlog migration in etcd, by the target root path.
+type etcdBinlogMigrationCheckpoint struct {
+	metaKV kv.MetaKv
+	key    string
+}
+
+func (cp *etcdBinlogMigrationCheckpoint) Load(ctx context.Context) (string, error) {
+	value, err := cp.metaKV.Load(cp.key)
+	if errors.Is(err, merr.ErrIoKeyNotFound) {
+		return "", nil
+	}
+	return value, err
+}
+
+func (cp *etcdBinlogMigrationCheckpoint) Save(ctx context.Context, key string) error {
+	return cp.metaKV.Save(cp.key, key)
+}
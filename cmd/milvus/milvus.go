@@ -53,6 +53,10 @@ func RunMilvus(args []string) {
 		c = &dryRun{}
 	case MckCmd:
 		c = &mck{}
+	case PreflightCmd:
+		c = &preflightCheck{}
+	case MigrateBinlogCmd:
+		c = &migrateBinlog{}
 	default:
 		c = &defaultCommand{}
 	}
@@ -0,0 +1,52 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package milvus
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/milvus-io/milvus/internal/util/preflight"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const PreflightCmd = "preflight"
+
+type preflightCheck struct{}
+
+func (c *preflightCheck) execute(args []string, flags *flag.FlagSet) {
+	if len(args) < 3 {
+		fmt.Fprintln(os.Stderr, preflightLine)
+		return
+	}
+	serverType := args[2]
+	if !typeutil.ServerTypeSet().Contain(serverType) && serverType != typeutil.MixtureRole && serverType != typeutil.EmbeddedRole {
+		fmt.Fprintf(os.Stderr, "Unknown server type = %s\n%s", serverType, preflightLine)
+		os.Exit(-1)
+	}
+
+	paramtable.Init()
+	paramtable.SetRole(serverType)
+	standalone := serverType == typeutil.StandaloneRole || serverType == typeutil.EmbeddedRole
+	results := preflight.Run(context.Background(), paramtable.Get(), standalone)
+	if !preflight.Report(os.Stdout, results) {
+		os.Exit(1)
+	}
+}
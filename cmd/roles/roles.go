@@ -38,6 +38,7 @@ import (
 	"github.com/milvus-io/milvus/internal/util/dependency"
 	kvfactory "github.com/milvus-io/milvus/internal/util/dependency/kv"
 	internalmetrics "github.com/milvus-io/milvus/internal/util/metrics"
+	"github.com/milvus-io/milvus/internal/util/preflight"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/tracer"
@@ -142,6 +143,12 @@ type MilvusRoles struct {
 
 	closed chan struct{}
 	once   sync.Once
+
+	cancel    context.CancelFunc
+	embedEtcd bool
+
+	rootCoord, queryCoord, indexCoord, dataCoord component
+	proxy, dataNode, indexNode, queryNode        component
 }
 
 // NewMilvusRoles creates a new MilvusRoles with private fields initialized.
@@ -294,15 +301,28 @@ func (mr *MilvusRoles) handleSignals() func() {
 	}
 }
 
-// Run Milvus components.
+// Run Milvus components, it blocks until the exit signal received.
 func (mr *MilvusRoles) Run() {
 	// start signal handler, defer close func
 	closeFn := mr.handleSignals()
 	defer closeFn()
 
+	if err := mr.Start(); err != nil {
+		log.Error("failed to start Milvus components", zap.Error(err))
+		mr.Stop()
+		return
+	}
+
+	<-mr.closed
+
+	mr.Stop()
+}
+
+// Start runs the enabled Milvus components, it returns once all the components are running.
+func (mr *MilvusRoles) Start() error {
 	log.Info("starting running Milvus components")
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	var ctx context.Context
+	ctx, mr.cancel = context.WithCancel(context.Background())
 
 	mr.printLDPreLoad()
 
@@ -321,13 +341,15 @@ func (mr *MilvusRoles) Run() {
 		params := paramtable.Get()
 		if params.EtcdCfg.UseEmbedEtcd.GetAsBool() {
 			// Start etcd server.
-			etcd.InitEtcdServer(
+			mr.embedEtcd = true
+			if err := etcd.InitEtcdServer(
 				params.EtcdCfg.UseEmbedEtcd.GetAsBool(),
 				params.EtcdCfg.ConfigPath.GetValue(),
 				params.EtcdCfg.DataDir.GetValue(),
 				params.EtcdCfg.EtcdLogPath.GetValue(),
-				params.EtcdCfg.EtcdLogLevel.GetValue())
-			defer etcd.StopEtcdServer()
+				params.EtcdCfg.EtcdLogLevel.GetValue()); err != nil {
+				return err
+			}
 		}
 		paramtable.SetRole(typeutil.StandaloneRole)
 	} else {
@@ -338,6 +360,12 @@ func (mr *MilvusRoles) Run() {
 		paramtable.SetRole(mr.ServerType)
 	}
 
+	if paramtable.Get().CommonCfg.PreflightEnabled.GetAsBool() {
+		if err := preflight.Check(ctx, paramtable.Get(), mr.Local); err != nil {
+			return err
+		}
+	}
+
 	expr.Init()
 	expr.Register("param", paramtable.Get())
 	http.ServeHTTP()
@@ -346,37 +374,35 @@ func (mr *MilvusRoles) Run() {
 	var wg sync.WaitGroup
 	local := mr.Local
 
-	var rootCoord, queryCoord, indexCoord, dataCoord component
-	var proxy, dataNode, indexNode, queryNode component
 	if mr.EnableRootCoord {
-		rootCoord = mr.runRootCoord(ctx, local, &wg)
+		mr.rootCoord = mr.runRootCoord(ctx, local, &wg)
 	}
 
 	if mr.EnableDataCoord {
-		dataCoord = mr.runDataCoord(ctx, local, &wg)
+		mr.dataCoord = mr.runDataCoord(ctx, local, &wg)
 	}
 
 	if mr.EnableIndexCoord {
-		indexCoord = mr.runIndexCoord(ctx, local, &wg)
+		mr.indexCoord = mr.runIndexCoord(ctx, local, &wg)
 	}
 
 	if mr.EnableQueryCoord {
-		queryCoord = mr.runQueryCoord(ctx, local, &wg)
+		mr.queryCoord = mr.runQueryCoord(ctx, local, &wg)
 	}
 
 	if mr.EnableQueryNode {
-		queryNode = mr.runQueryNode(ctx, local, &wg)
+		mr.queryNode = mr.runQueryNode(ctx, local, &wg)
 	}
 
 	if mr.EnableDataNode {
-		dataNode = mr.runDataNode(ctx, local, &wg)
+		mr.dataNode = mr.runDataNode(ctx, local, &wg)
 	}
 	if mr.EnableIndexNode {
-		indexNode = mr.runIndexNode(ctx, local, &wg)
+		mr.indexNode = mr.runIndexNode(ctx, local, &wg)
 	}
 
 	if mr.EnableProxy {
-		proxy = mr.runProxy(ctx, local, &wg)
+		mr.proxy = mr.runProxy(ctx, local, &wg)
 	}
 
 	wg.Wait()
@@ -386,11 +412,13 @@ func (mr *MilvusRoles) Run() {
 
 	paramtable.SetCreateTime(time.Now())
 	paramtable.SetUpdateTime(time.Now())
+	return nil
+}
 
-	<-mr.closed
-
+// Stop stops the Milvus components started by Start.
+func (mr *MilvusRoles) Stop() {
 	// stop coordinators first
-	coordinators := []component{rootCoord, dataCoord, indexCoord, queryCoord}
+	coordinators := []component{mr.rootCoord, mr.dataCoord, mr.indexCoord, mr.queryCoord}
 	for idx, coord := range coordinators {
 		log.Warn("stop processing")
 		if coord != nil {
@@ -401,7 +429,7 @@ func (mr *MilvusRoles) Run() {
 	log.Info("All coordinators have stopped")
 
 	// stop nodes
-	nodes := []component{queryNode, indexNode, dataNode}
+	nodes := []component{mr.queryNode, mr.indexNode, mr.dataNode}
 	for idx, node := range nodes {
 		if node != nil {
 			log.Info("stop node", zap.Int("idx", idx), zap.Any("node", node))
@@ -410,14 +438,24 @@ func (mr *MilvusRoles) Run() {
 	}
 	log.Info("All nodes have stopped")
 
-	if proxy != nil {
-		proxy.Stop()
+	if mr.proxy != nil {
+		mr.proxy.Stop()
 		log.Info("proxy stopped!")
 	}
 
 	// close reused etcd client
 	kvfactory.CloseEtcdClient()
 
+	if mr.Local {
+		stopRocksmq()
+	}
+	if mr.embedEtcd {
+		etcd.StopEtcdServer()
+	}
+	if mr.cancel != nil {
+		mr.cancel()
+	}
+
 	log.Info("Milvus components graceful stop done")
 }
 
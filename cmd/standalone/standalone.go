@@ -0,0 +1,146 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package standalone starts a standalone Milvus inside the current process, with the embedded etcd,
+// rocksmq and local storage under a data directory, so that a Go application could run Milvus
+// without containers, e.g. in integration tests or edge deployments.
+//
+//	server, err := standalone.Start(standalone.Config{DataDir: "/tmp/milvus"})
+//	if err != nil {
+//		return err
+//	}
+//	defer server.Stop()
+//	// connect to server.Address() by any Milvus SDK
+//
+// The params of milvus.yaml are still loaded if found by MILVUSCONF, and could be overridden by Config.Params.
+// Only one standalone Milvus could be started in a process, even after it's stopped.
+package standalone
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus/cmd/roles"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+var started = atomic.NewBool(false)
+
+// Config is the config of the standalone Milvus.
+type Config struct {
+	// DataDir is the root directory of the etcd data, rocksmq and local storage.
+	DataDir string
+	// Port is the gRPC port of the proxy, default to the one in milvus.yaml if not positive.
+	Port int
+	// Params overrides the params of milvus.yaml, e.g. {"log.level": "warn"}.
+	Params map[string]string
+}
+
+// Server is a running standalone Milvus.
+type Server struct {
+	roles    *roles.MilvusRoles
+	address  string
+	stopOnce sync.Once
+}
+
+// Start starts the standalone Milvus and returns once it's ready to serve.
+// Note that the components panic on fatal errors as the milvus binary does.
+func Start(cfg Config) (*Server, error) {
+	if cfg.DataDir == "" {
+		return nil, merr.WrapErrParameterInvalidMsg("data dir of the standalone Milvus is required")
+	}
+	if !started.CompareAndSwap(false, true) {
+		return nil, merr.WrapErrServiceInternal("standalone Milvus could only be started once in a process")
+	}
+	// it could be started again if it fails before any component is started
+	params, err := setup(cfg)
+	if err != nil {
+		started.Store(false)
+		return nil, err
+	}
+
+	mr := roles.NewMilvusRoles()
+	mr.Local = true
+	mr.EnableRootCoord = true
+	mr.EnableProxy = true
+	mr.EnableQueryCoord = true
+	mr.EnableQueryNode = true
+	mr.EnableDataCoord = true
+	mr.EnableDataNode = true
+	mr.EnableIndexCoord = true
+	mr.EnableIndexNode = true
+	if err := mr.Start(); err != nil {
+		mr.Stop()
+		return nil, err
+	}
+
+	return &Server{
+		roles:   mr,
+		address: fmt.Sprintf("localhost:%d", params.ProxyGrpcServerCfg.Port.GetAsInt()),
+	}, nil
+}
+
+// setup creates the data dir and loads the params of the config.
+func setup(cfg Config) (*paramtable.ComponentParam, error) {
+	if err := os.MkdirAll(cfg.DataDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	paramtable.Init()
+	params := paramtable.Get()
+	for key, value := range overrideParams(cfg, params) {
+		if err := params.Save(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// Address returns the address of the proxy for the SDKs.
+func (s *Server) Address() string {
+	return s.address
+}
+
+// Stop stops the standalone Milvus gracefully, the data is kept in the data dir.
+func (s *Server) Stop() {
+	s.stopOnce.Do(s.roles.Stop)
+}
+
+// overrideParams returns the params to run standalone Milvus under the data dir,
+// the params of the config take precedence.
+func overrideParams(cfg Config, params *paramtable.ComponentParam) map[string]string {
+	overrides := map[string]string{
+		params.EtcdCfg.UseEmbedEtcd.Key:  "true",
+		params.EtcdCfg.DataDir.Key:       filepath.Join(cfg.DataDir, "etcd"),
+		params.MQCfg.Type.Key:            "rocksmq",
+		params.RocksmqCfg.Path.Key:       filepath.Join(cfg.DataDir, "rocksmq"),
+		params.CommonCfg.StorageType.Key: "local",
+		params.LocalStorageCfg.Path.Key:  filepath.Join(cfg.DataDir, "data"),
+	}
+	if cfg.Port > 0 {
+		overrides[params.ProxyGrpcServerCfg.Port.Key] = strconv.Itoa(cfg.Port)
+	}
+	for key, value := range cfg.Params {
+		overrides[key] = value
+	}
+	return overrides
+}
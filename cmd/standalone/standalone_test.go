@@ -0,0 +1,71 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestStart_InvalidConfig(t *testing.T) {
+	_, err := Start(Config{})
+	assert.Error(t, err)
+	assert.False(t, started.Load())
+}
+
+func TestStart_SetupFailed(t *testing.T) {
+	// the data dir is under a file
+	file := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(file, nil, 0o600))
+	for i := 0; i < 2; i++ {
+		_, err := Start(Config{DataDir: filepath.Join(file, "data")})
+		assert.Error(t, err)
+		assert.False(t, started.Load())
+	}
+}
+
+func TestOverrideParams(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+
+	dataDir := t.TempDir()
+	overrides := overrideParams(Config{DataDir: dataDir}, params)
+	assert.Equal(t, "true", overrides[params.EtcdCfg.UseEmbedEtcd.Key])
+	assert.Equal(t, filepath.Join(dataDir, "etcd"), overrides[params.EtcdCfg.DataDir.Key])
+	assert.Equal(t, filepath.Join(dataDir, "rocksmq"), overrides[params.RocksmqCfg.Path.Key])
+	assert.Equal(t, filepath.Join(dataDir, "data"), overrides[params.LocalStorageCfg.Path.Key])
+	assert.Equal(t, "local", overrides[params.CommonCfg.StorageType.Key])
+	_, ok := overrides[params.ProxyGrpcServerCfg.Port.Key]
+	assert.False(t, ok)
+
+	overrides = overrideParams(Config{
+		DataDir: dataDir,
+		Port:    19531,
+		Params: map[string]string{
+			params.CommonCfg.StorageType.Key: "remote",
+			"log.level":                      "warn",
+		},
+	}, params)
+	assert.Equal(t, "19531", overrides[params.ProxyGrpcServerCfg.Port.Key])
+	assert.Equal(t, "remote", overrides[params.CommonCfg.StorageType.Key])
+	assert.Equal(t, "warn", overrides["log.level"])
+}
@@ -0,0 +1,158 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/util/replay"
+	"github.com/milvus-io/milvus/pkg/util"
+	"github.com/milvus-io/milvus/pkg/util/crypto"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+var (
+	file      = flag.String("file", "", "Access log file capturing the requests by $method_request")
+	format    = flag.String("format", "", "Format of the access log, default to the one of the formatter in milvus.yaml")
+	formatter = flag.String("formatter", "replay", "Name of the access log formatter in milvus.yaml capturing the requests")
+
+	target   = flag.String("target", "", "Address of the proxy to replay against, e.g. localhost:19530")
+	baseline = flag.String("baseline", "", "Address of the proxy to diff the results with, empty to replay without diffing")
+	username = flag.String("user", "", "Username if authorization enabled")
+	password = flag.String("password", "", "Password if authorization enabled")
+
+	speed       = flag.Float64("speed", 1, "Replay speed relative to the captured pace, 0 means as fast as possible")
+	concurrency = flag.Int("concurrency", 16, "Maximum number of requests in flight")
+	timeout     = flag.Duration("timeout", 30*time.Second, "Timeout of each request")
+	minRecall   = flag.Float64("minRecall", 1, "Recall below which the results are reported as mismatched")
+	mismatches  = flag.Int("mismatches", 10, "Maximum number of mismatched requests to print")
+)
+
+func main() {
+	flag.Parse()
+	if *file == "" || *target == "" {
+		fmt.Fprintln(os.Stderr, "usage: replay -file <access log> -target <address> [-baseline <address>] [-speed 1]")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+
+	logFormat := *format
+	if logFormat == "" {
+		paramtable.Init()
+		logFormat = paramtable.Get().ProxyCfg.AccessLog.Formatter.GetValue()[*formatter+".format"]
+		if logFormat == "" {
+			fmt.Fprintf(os.Stderr, "access log formatter %s not found, set it by -format\n", *formatter)
+			os.Exit(1)
+		}
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open access log: %s\n", err.Error())
+		os.Exit(1)
+	}
+	defer f.Close()
+	reader, err := replay.NewReader(f, logFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid access log format: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if *username != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, util.HeaderAuthorize, crypto.Base64Encode(*username+":"+*password))
+	}
+
+	targetClient := connect(*target)
+	var baselineClient replay.Target
+	if *baseline != "" {
+		baselineClient = connect(*baseline)
+	}
+	replayer := replay.NewReplayer(targetClient, baselineClient, replay.Config{
+		Speed:       *speed,
+		Concurrency: *concurrency,
+		Timeout:     *timeout,
+		MinRecall:   *minRecall,
+	})
+	report, err := replayer.Replay(ctx, reader)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "replay failed: %s\n", err.Error())
+		os.Exit(1)
+	}
+	printReport(report)
+	if report.Failed > 0 || report.Mismatched > 0 {
+		os.Exit(2)
+	}
+}
+
+func connect(address string) milvuspb.MilvusServiceClient {
+	conn, err := grpc.Dial(address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(math.MaxInt32)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect %s: %s\n", address, err.Error())
+		os.Exit(1)
+	}
+	return milvuspb.NewMilvusServiceClient(conn)
+}
+
+func printReport(report *replay.Report) {
+	fmt.Printf("Replayed: %d\tSkipped lines: %d\tElapsed: %s\n", report.Total, report.Skipped, report.Elapsed)
+	fmt.Printf("Target failed: %d\n", report.Failed)
+	printLatency("Target", report.Latency)
+	printLatency("Captured", report.OriginalLatency)
+	if *baseline != "" {
+		fmt.Printf("Baseline failed: %d\n", report.BaselineFailed)
+		printLatency("Baseline", report.BaselineLatency)
+		fmt.Printf("Compared: %d\tMismatched: %d\tAverage recall: %.4f\n", report.Compared, report.Mismatched, report.AvgRecall)
+	}
+
+	for i, result := range report.Mismatches {
+		if i >= *mismatches {
+			fmt.Printf("... %d more\n", len(report.Mismatches)-i)
+			break
+		}
+		switch {
+		case result.Err != nil:
+			fmt.Printf("\tline %d %s: target failed, %s\n", result.Record.Line, result.Record.Method, result.Err.Error())
+		case result.Diff.Reason != "":
+			fmt.Printf("\tline %d %s: %s\n", result.Record.Line, result.Record.Method, result.Diff.Reason)
+		default:
+			fmt.Printf("\tline %d %s: recall %.4f\n", result.Record.Line, result.Record.Method, result.Diff.Recall)
+		}
+	}
+}
+
+func printLatency(name string, stats replay.LatencyStats) {
+	if stats.Count == 0 {
+		return
+	}
+	fmt.Printf("%s latency: avg %s\tp50 %s\tp99 %s\tmax %s\n", name, stats.Avg, stats.P50, stats.P99, stats.Max)
+}
@@ -0,0 +1,138 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	etcdkv "github.com/milvus-io/milvus/internal/kv/etcd"
+	"github.com/milvus-io/milvus/internal/metastore/kv/binlog"
+	"github.com/milvus-io/milvus/internal/metastore/kv/datacoord"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/util/etcd"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+var (
+	collectionID = flag.Int64("collection", 0, "Collection ID of the segment")
+	partitionID  = flag.Int64("partition", 0, "Partition ID of the segment")
+	segmentID    = flag.Int64("segment", 0, "Segment ID to dump")
+	limit        = flag.Int("limit", 10, "Maximum number of rows and deletes to print, 0 means all")
+	verify       = flag.Bool("verify", false, "Cross-check stats logs against insert and delta logs instead of printing them")
+	expectedRows = flag.Int64("rows", -1, "Expected row count of the segment in verify mode, -1 to skip the check")
+
+	etcdAddr     = flag.String("etcd", "", "Etcd endpoint of the meta, default to the one in milvus.yaml")
+	metaRootPath = flag.String("metaRootPath", "", "Meta root path in etcd, default to the one in milvus.yaml")
+
+	rootPath   = flag.String("rootPath", "", "Root path of the binlogs, default to the one in milvus.yaml")
+	address    = flag.String("minioAddress", "", "Object storage endpoint, default to the one in milvus.yaml")
+	bucketName = flag.String("minioBucketName", "", "Object storage bucket, default to the one in milvus.yaml")
+)
+
+func main() {
+	flag.Parse()
+	if *collectionID <= 0 || *partitionID <= 0 || *segmentID <= 0 {
+		fmt.Fprintln(os.Stderr, "usage: segdump -collection <id> -partition <id> -segment <id> [-limit 10]")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+
+	paramtable.Init()
+	params := paramtable.Get()
+	if *rootPath != "" {
+		params.Save(params.MinioCfg.RootPath.Key, *rootPath)
+		params.Save(params.LocalStorageCfg.Path.Key, *rootPath)
+	}
+	if *address != "" {
+		params.Save(params.MinioCfg.Address.Key, *address)
+	}
+	if *bucketName != "" {
+		params.Save(params.MinioCfg.BucketName.Key, *bucketName)
+	}
+	if *etcdAddr != "" {
+		params.Save(params.EtcdCfg.Endpoints.Key, *etcdAddr)
+	}
+	if *metaRootPath == "" {
+		*metaRootPath = params.EtcdCfg.MetaRootPath.GetValue()
+	}
+
+	ctx := context.Background()
+	cm, err := storage.NewChunkManagerFactoryWithParam(params).NewPersistentStorageChunkManager(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create chunk manager: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	// the binlog paths are read from the meta, the tenants, the path templates and
+	// the content-addressed binlogs don't keep the paths built by the ids
+	etcdCli, err := etcd.GetRemoteEtcdClient(params.EtcdCfg.Endpoints.GetAsStrings())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to etcd: %s\n", err.Error())
+		os.Exit(1)
+	}
+	defer etcdCli.Close()
+	catalog := datacoord.NewCatalog(etcdkv.NewEtcdKV(etcdCli, *metaRootPath), cm.RootPath(), *metaRootPath)
+	segment, err := catalog.LoadSegment(ctx, *collectionID, *partitionID, *segmentID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load segment meta: %s\n", err.Error())
+		os.Exit(1)
+	}
+	if segment == nil {
+		fmt.Printf("segment %d not found in meta\n", *segmentID)
+		return
+	}
+	if err := binlog.DecompressBinLogs(segment); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to decompress binlog paths: %s\n", err.Error())
+		os.Exit(1)
+	}
+	paths := storage.NewSegmentBinlogPaths(segment)
+	if paths.IsEmpty() {
+		fmt.Printf("no binlog recorded for segment %d\n", *segmentID)
+		return
+	}
+
+	if *verify {
+		verifySegment(ctx, cm, paths)
+		return
+	}
+
+	if err := storage.DumpSegmentBinlogs(ctx, cm, paths, os.Stdout, *limit); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func verifySegment(ctx context.Context, cm storage.ChunkManager, paths *storage.SegmentBinlogPaths) {
+	result, err := storage.VerifySegmentBinlogs(ctx, cm, paths, *expectedRows)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to verify segment: %s\n", err.Error())
+		os.Exit(1)
+	}
+	fmt.Printf("Segment ID: %d\tInsert rows: %d\tDelete rows: %d\n", paths.SegmentID, result.InsertRows, result.DeleteRows)
+	if result.OK() {
+		fmt.Println("stats logs are consistent with insert and delta logs")
+		return
+	}
+	fmt.Printf("found %d mismatches:\n", len(result.Mismatches))
+	for _, mismatch := range result.Mismatches {
+		fmt.Printf("\t%s\n", mismatch)
+	}
+	os.Exit(2)
+}
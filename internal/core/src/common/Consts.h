@@ -36,6 +36,27 @@ const milvus::FieldId TimestampFieldID = milvus::FieldId(1);
 const char ORIGIN_SIZE_KEY[] = "original_size";
 const char INDEX_BUILD_ID_KEY[] = "indexBuildID";
 
+// binlog features declared in the descriptor event, see internal/storage/event_data.go
+const char BINLOG_FEATURES_KEY[] = "binlog_features";
+const char BINLOG_REQUIRED_FEATURE[] = "required";
+
+// key value metadata of the parquet binlog, see internal/storage/parquet_binlog.go
+const char PARQUET_BINLOG_MAGIC[] = "PAR1";
+const char PARQUET_BINLOG_COLLECTION_ID_KEY[] = "milvus.collection_id";
+const char PARQUET_BINLOG_PARTITION_ID_KEY[] = "milvus.partition_id";
+const char PARQUET_BINLOG_SEGMENT_ID_KEY[] = "milvus.segment_id";
+const char PARQUET_BINLOG_FIELD_ID_KEY[] = "milvus.field_id";
+const char PARQUET_BINLOG_DATA_TYPE_KEY[] = "milvus.data_type";
+const char PARQUET_BINLOG_START_TS_KEY[] = "milvus.start_timestamp";
+const char PARQUET_BINLOG_END_TS_KEY[] = "milvus.end_timestamp";
+
+// header of the compressed binlog, see internal/storage/binlog_compression.go
+const char COMPRESSED_BINLOG_MAGIC[] = "MVCB";
+const int64_t COMPRESSED_BINLOG_HEADER_SIZE = 4 + 1 + 8;
+const uint8_t COMPRESSED_BINLOG_CODEC_ZSTD = 1;
+const uint8_t COMPRESSED_BINLOG_CODEC_LZ4 = 2;
+const uint8_t COMPRESSED_BINLOG_CODEC_SNAPPY = 3;
+
 const char INDEX_ROOT_PATH[] = "index_files";
 const char RAWDATA_ROOT_PATH[] = "raw_datas";
 const char VEC_OPT_FIELDS[] = "opt_fields";
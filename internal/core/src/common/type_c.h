@@ -89,6 +89,12 @@ typedef struct CStorageConfig {
     bool useIAM;
     bool useVirtualHost;
     int64_t requestTimeoutMs;
+    // the server-side encryption, the storage class and the tags in the form of
+    // the x-amz-tagging header of the objects put, the bucket defaults if null or empty
+    const char* sse_type;
+    const char* sse_kms_key_id;
+    const char* storage_class;
+    const char* object_tagging;
 } CStorageConfig;
 
 typedef struct CTraceConfig {
@@ -36,6 +36,12 @@ class SegmentSealed : public SegmentInternalInterface {
 
     virtual void
     LoadFieldData(FieldId field_id, FieldDataInfo& data) = 0;
+    // load the deletes recorded by the offsets of the deleted rows,
+    // which were inserted before the deletes
+    virtual void
+    LoadDeletedOffsets(const int64_t* offsets,
+                       const Timestamp* timestamps,
+                       int64_t size) = 0;
     virtual void
     MapFieldData(const FieldId field_id, FieldDataInfo& data) = 0;
     virtual void
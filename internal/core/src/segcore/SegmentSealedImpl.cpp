@@ -18,6 +18,7 @@
 #include <algorithm>
 #include <cstdint>
 #include <filesystem>
+#include <limits>
 #include <memory>
 #include <string>
 #include <string_view>
@@ -634,6 +635,24 @@ SegmentSealedImpl::LoadDeletedRecord(const LoadDeletedRecordInfo& info) {
     stats_.mem_size += sizeof(Timestamp) * info.row_count + CalcPksSize(pks);
 }
 
+void
+SegmentSealedImpl::LoadDeletedOffsets(const int64_t* offsets,
+                                      const Timestamp* timestamps,
+                                      int64_t size) {
+    AssertInfo(size > 0, "The row count of deleted offsets is 0");
+    AssertInfo(offsets, "Deleted offsets is null");
+    AssertInfo(timestamps, "Deleted timestamps is null");
+
+    std::unique_lock lck(deleted_offsets_mutex_);
+    deleted_offsets_.reserve(deleted_offsets_.size() + size);
+    for (int64_t i = 0; i < size; ++i) {
+        deleted_offsets_.emplace_back(timestamps[i], offsets[i]);
+    }
+    std::sort(deleted_offsets_.begin(), deleted_offsets_.end());
+
+    stats_.mem_size += (sizeof(Timestamp) + sizeof(int64_t)) * size;
+}
+
 void
 SegmentSealedImpl::AddFieldDataInfoForSealed(
     const LoadFieldDataInfo& field_data_info) {
@@ -701,7 +720,8 @@ SegmentSealedImpl::get_row_count() const {
 int64_t
 SegmentSealedImpl::get_deleted_count() const {
     std::shared_lock lck(mutex_);
-    return deleted_record_.size();
+    std::shared_lock offsets_lck(deleted_offsets_mutex_);
+    return deleted_record_.size() + deleted_offsets_.size();
 }
 
 const Schema&
@@ -713,6 +733,23 @@ void
 SegmentSealedImpl::mask_with_delete(BitsetType& bitset,
                                     int64_t ins_barrier,
                                     Timestamp timestamp) const {
+    {
+        // the rows at the offsets were inserted before deleted,
+        // so the deletes before the timestamp take effect
+        std::shared_lock lck(deleted_offsets_mutex_);
+        auto end = std::upper_bound(
+            deleted_offsets_.begin(),
+            deleted_offsets_.end(),
+            std::make_pair(timestamp, std::numeric_limits<int64_t>::max()));
+        for (auto it = deleted_offsets_.begin(); it != end; ++it) {
+            AssertInfo(static_cast<size_t>(it->second) < bitset.size(),
+                       fmt::format("Deleted offset {} out of bitmap size {}",
+                                   it->second,
+                                   bitset.size()));
+            bitset.set(it->second);
+        }
+    }
+
     auto del_barrier = get_barrier(get_deleted_record(), timestamp);
     if (del_barrier == 0) {
         return;
@@ -54,6 +54,10 @@ class SegmentSealedImpl : public SegmentSealed {
     void
     LoadDeletedRecord(const LoadDeletedRecordInfo& info) override;
     void
+    LoadDeletedOffsets(const int64_t* offsets,
+                       const Timestamp* timestamps,
+                       int64_t size) override;
+    void
     LoadSegmentMeta(
         const milvus::proto::segcore::LoadSegmentMeta& segment_meta) override;
     void
@@ -289,6 +293,10 @@ class SegmentSealedImpl : public SegmentSealed {
     // deleted pks
     mutable DeletedRecord deleted_record_;
 
+    // deleted row offsets with the delete timestamps, sorted by the timestamp
+    std::vector<std::pair<Timestamp, int64_t>> deleted_offsets_;
+    mutable std::shared_mutex deleted_offsets_mutex_;
+
     LoadFieldDataInfo field_data_info_;
 
     SchemaPtr schema_;
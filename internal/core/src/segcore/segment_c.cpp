@@ -328,6 +328,24 @@ LoadDeletedRecord(CSegmentInterface c_segment,
     }
 }
 
+CStatus
+LoadDeletedOffsets(CSegmentInterface c_segment,
+                   const int64_t* offsets,
+                   const uint64_t* timestamps,
+                   int64_t row_count) {
+    try {
+        auto segment_interface =
+            reinterpret_cast<milvus::segcore::SegmentInterface*>(c_segment);
+        auto segment =
+            dynamic_cast<milvus::segcore::SegmentSealed*>(segment_interface);
+        AssertInfo(segment != nullptr, "segment conversion failed");
+        segment->LoadDeletedOffsets(offsets, timestamps, row_count);
+        return milvus::SuccessCStatus();
+    } catch (std::exception& e) {
+        return milvus::FailureCStatus(&e);
+    }
+}
+
 CStatus
 UpdateSealedSegmentIndex(CSegmentInterface c_segment,
                          CLoadIndexInfo c_load_index_info) {
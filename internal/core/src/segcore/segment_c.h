@@ -106,6 +106,12 @@ CStatus
 LoadDeletedRecord(CSegmentInterface c_segment,
                   CLoadDeletedRecordInfo deleted_record_info);
 
+CStatus
+LoadDeletedOffsets(CSegmentInterface c_segment,
+                   const int64_t* offsets,
+                   const uint64_t* timestamps,
+                   int64_t row_count);
+
 CStatus
 UpdateSealedSegmentIndex(CSegmentInterface c_segment,
                          CLoadIndexInfo c_load_index_info);
@@ -99,6 +99,7 @@ AwsChunkManager::AwsChunkManager(const StorageConfig& storage_config) {
         BuildAccessKeyClient(storage_config, config);
     }
 
+    InitObjectPolicy(storage_config);
     PreCheck(storage_config);
 
     LOG_INFO(
@@ -137,6 +138,7 @@ GcpChunkManager::GcpChunkManager(const StorageConfig& storage_config) {
         BuildAccessKeyClient(storage_config, config);
     }
 
+    InitObjectPolicy(storage_config);
     PreCheck(storage_config);
 
     LOG_INFO(
@@ -179,6 +181,7 @@ AliyunChunkManager::AliyunChunkManager(const StorageConfig& storage_config) {
         BuildAccessKeyClient(mutable_config, config);
     }
 
+    InitObjectPolicy(storage_config);
     PreCheck(storage_config);
 
     LOG_INFO(
@@ -222,6 +225,7 @@ TencentCloudChunkManager::TencentCloudChunkManager(
         BuildAccessKeyClient(mutable_config, config);
     }
 
+    InitObjectPolicy(storage_config);
     PreCheck(storage_config);
 
     LOG_INFO(
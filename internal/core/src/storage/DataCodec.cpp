@@ -14,21 +14,40 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+#include <cstring>
+#include <unordered_set>
+
+#include "arrow/io/api.h"
+#include "arrow/util/compression.h"
+#include "parquet/file_reader.h"
 #include "storage/DataCodec.h"
 #include "storage/Event.h"
 #include "storage/Util.h"
 #include "storage/InsertData.h"
 #include "storage/IndexData.h"
 #include "storage/BinlogReader.h"
+#include "storage/PayloadReader.h"
 #include "common/EasyAssert.h"
 #include "common/Consts.h"
 
 namespace milvus::storage {
 
+// binlog features segcore is able to read, the features declared so far are
+// of the deltalogs, which are read by the go readers only
+const std::unordered_set<std::string> SUPPORTED_BINLOG_FEATURES{};
+
 // deserialize remote insert and index file
 std::unique_ptr<DataCodec>
 DeserializeRemoteFileData(BinlogReaderPtr reader) {
     DescriptorEvent descriptor_event(reader);
+    for (auto& [feature, requirement] : descriptor_event.event_data.features) {
+        if (requirement == BINLOG_REQUIRED_FEATURE &&
+            SUPPORTED_BINLOG_FEATURES.count(feature) == 0) {
+            PanicInfo(DataFormatBroken,
+                      fmt::format("binlog requires unsupported feature {}",
+                                  feature));
+        }
+    }
     DataType data_type =
         DataType(descriptor_event.event_data.fix_part.data_type);
     auto descriptor_fix_part = descriptor_event.event_data.fix_part;
@@ -98,9 +117,109 @@ DeserializeLocalFileData(BinlogReaderPtr reader) {
     PanicInfo(NotImplemented, "not supported");
 }
 
+std::unique_ptr<DataCodec>
+DeserializeParquetFileData(const std::shared_ptr<uint8_t[]> input_data,
+                           int64_t length) {
+    auto input =
+        std::make_shared<arrow::io::BufferReader>(input_data.get(), length);
+    auto file_meta = parquet::ParquetFileReader::Open(input)->metadata();
+    auto kv_meta = file_meta->key_value_metadata();
+    AssertInfo(kv_meta != nullptr, "metadata of parquet binlog not found");
+    auto get_int = [&kv_meta](const std::string& key) -> int64_t {
+        auto value = kv_meta->Get(key);
+        AssertInfo(value.ok(),
+                   fmt::format("{} not found in parquet binlog metadata", key));
+        return std::stoll(value.ValueOrDie());
+    };
+
+    FieldDataMeta data_meta{get_int(PARQUET_BINLOG_COLLECTION_ID_KEY),
+                            get_int(PARQUET_BINLOG_PARTITION_ID_KEY),
+                            get_int(PARQUET_BINLOG_SEGMENT_ID_KEY),
+                            get_int(PARQUET_BINLOG_FIELD_ID_KEY)};
+    auto data_type = DataType(get_int(PARQUET_BINLOG_DATA_TYPE_KEY));
+    auto payload_reader =
+        std::make_shared<PayloadReader>(input_data.get(), length, data_type);
+    auto insert_data =
+        std::make_unique<InsertData>(payload_reader->get_field_data());
+    insert_data->SetFieldDataMeta(data_meta);
+    insert_data->SetTimestamps(get_int(PARQUET_BINLOG_START_TS_KEY),
+                               get_int(PARQUET_BINLOG_END_TS_KEY));
+    return insert_data;
+}
+
+std::pair<std::shared_ptr<uint8_t[]>, int64_t>
+DecompressBinlogData(const std::shared_ptr<uint8_t[]> input_data,
+                     int64_t length) {
+    AssertInfo(length >= COMPRESSED_BINLOG_HEADER_SIZE,
+               "compressed binlog header broken");
+    // the uncompressed size beyond the max compression ratio of the codec is
+    // corrupted, see maxCompressionRatios of storage/binlog_compression.go
+    arrow::Compression::type compression;
+    uint64_t max_ratio;
+    switch (input_data[4]) {
+        case COMPRESSED_BINLOG_CODEC_ZSTD:
+            compression = arrow::Compression::ZSTD;
+            max_ratio = 1 << 15;
+            break;
+        case COMPRESSED_BINLOG_CODEC_LZ4:
+            compression = arrow::Compression::LZ4_FRAME;
+            max_ratio = 256;
+            break;
+        case COMPRESSED_BINLOG_CODEC_SNAPPY:
+            compression = arrow::Compression::SNAPPY;
+            max_ratio = 22;
+            break;
+        default:
+            PanicInfo(DataFormatBroken,
+                      fmt::format("unknown codec {} of the compressed binlog",
+                                  input_data[4]));
+    }
+    // the uncompressed size is little endian as the other binlog fields
+    uint64_t size = 0;
+    std::memcpy(&size, input_data.get() + 5, sizeof(size));
+    auto payload_size =
+        static_cast<uint64_t>(length - COMPRESSED_BINLOG_HEADER_SIZE);
+    AssertInfo(size <= payload_size * max_ratio,
+               fmt::format("uncompressed size {} of the compressed binlog "
+                           "exceeds the ratio {} of the {} bytes",
+                           size,
+                           max_ratio,
+                           payload_size));
+
+    auto codec = arrow::util::Codec::Create(compression);
+    AssertInfo(codec.ok(),
+               fmt::format("failed to create codec of the compressed binlog: {}",
+                           codec.status().ToString()));
+    auto output = std::shared_ptr<uint8_t[]>(new uint8_t[size]);
+    auto decompressed = codec.ValueOrDie()->Decompress(
+        payload_size,
+        input_data.get() + COMPRESSED_BINLOG_HEADER_SIZE,
+        size,
+        output.get());
+    AssertInfo(decompressed.ok() && decompressed.ValueOrDie() == size,
+               "failed to decompress the compressed binlog");
+    return {output, static_cast<int64_t>(size)};
+}
+
 std::unique_ptr<DataCodec>
 DeserializeFileData(const std::shared_ptr<uint8_t[]> input_data,
                     int64_t length) {
+    auto compressed_magic_length =
+        static_cast<int64_t>(sizeof(COMPRESSED_BINLOG_MAGIC) - 1);
+    if (length >= compressed_magic_length &&
+        std::memcmp(input_data.get(),
+                    COMPRESSED_BINLOG_MAGIC,
+                    compressed_magic_length) == 0) {
+        auto [data, size] = DecompressBinlogData(input_data, length);
+        return DeserializeFileData(data, size);
+    }
+    auto magic_length =
+        static_cast<int64_t>(sizeof(PARQUET_BINLOG_MAGIC) - 1);
+    if (length >= magic_length &&
+        std::memcmp(input_data.get(), PARQUET_BINLOG_MAGIC, magic_length) ==
+            0) {
+        return DeserializeParquetFileData(input_data, length);
+    }
     auto binlog_reader = std::make_shared<BinlogReader>(input_data, length);
     auto medium_type = ReadMediumType(binlog_reader);
     switch (medium_type) {
@@ -85,4 +85,15 @@ DeserializeRemoteFileData(BinlogReaderPtr reader);
 std::unique_ptr<DataCodec>
 DeserializeLocalFileData(BinlogReaderPtr reader);
 
+// Decompress the binlog compressed by the codec in its header,
+// returns the decompressed binlog and its length
+std::pair<std::shared_ptr<uint8_t[]>, int64_t>
+DecompressBinlogData(const std::shared_ptr<uint8_t[]> input_data,
+                     int64_t length);
+
+// Deserialize the plain parquet file written in the parquet binlog format
+std::unique_ptr<DataCodec>
+DeserializeParquetFileData(const std::shared_ptr<uint8_t[]> input,
+                           int64_t length);
+
 }  // namespace milvus::storage
@@ -164,6 +164,14 @@ DescriptorEventData::DescriptorEventData(BinlogReaderPtr reader) {
     if (json.contains(INDEX_BUILD_ID_KEY)) {
         extras[INDEX_BUILD_ID_KEY] = json[INDEX_BUILD_ID_KEY];
     }
+    if (json.contains(BINLOG_FEATURES_KEY) &&
+        json[BINLOG_FEATURES_KEY].is_object()) {
+        for (auto& item : json[BINLOG_FEATURES_KEY].items()) {
+            features[item.key()] = item.value().is_string()
+                                       ? item.value().get<std::string>()
+                                       : "";
+        }
+    }
 }
 
 std::vector<uint8_t>
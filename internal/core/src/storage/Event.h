@@ -62,6 +62,8 @@ struct DescriptorEventData {
     int32_t extra_length;
     std::vector<uint8_t> extra_bytes;
     std::unordered_map<std::string, std::string> extras;
+    // binlog features to whether they are required or optional
+    std::unordered_map<std::string, std::string> features;
     std::vector<uint8_t> post_header_lengths;
 
     DescriptorEventData() = default;
@@ -29,6 +29,7 @@
 #include <aws/s3/model/HeadObjectRequest.h>
 #include <aws/s3/model/ListObjectsRequest.h>
 #include <aws/s3/model/PutObjectRequest.h>
+#include <aws/s3/model/StorageClassMapper.h>
 
 #include "storage/AliyunSTSClient.h"
 #include "storage/AliyunCredentialsProvider.h"
@@ -241,6 +242,20 @@ MinioChunkManager::PreCheck(const StorageConfig& config) {
     }
 };
 
+void
+MinioChunkManager::InitObjectPolicy(const StorageConfig& storage_config) {
+    // the policy is validated by the Go side, only SSE-S3 and SSE-KMS reach here
+    AssertInfo(storage_config.sse_type.empty() ||
+                   storage_config.sse_type == "SSE-S3" ||
+                   storage_config.sse_type == "SSE-KMS",
+               "unsupported server-side encryption: {}",
+               storage_config.sse_type);
+    sse_type_ = storage_config.sse_type;
+    sse_kms_key_id_ = storage_config.sse_kms_key_id;
+    storage_class_ = storage_config.storage_class;
+    object_tagging_ = storage_config.object_tagging;
+}
+
 void
 MinioChunkManager::BuildAccessKeyClient(
     const StorageConfig& storage_config,
@@ -346,6 +361,7 @@ MinioChunkManager::MinioChunkManager(const StorageConfig& storage_config)
         BuildGoogleCloudClient(storage_config, config);
     }
 
+    InitObjectPolicy(storage_config);
     PreCheck(storage_config);
 
     LOG_INFO(
@@ -570,6 +586,25 @@ MinioChunkManager::PutObjectBuffer(const std::string& bucket_name,
     input_data->write(reinterpret_cast<char*>(buf), size);
     request.SetBody(input_data);
 
+    if (sse_type_ == "SSE-KMS") {
+        request.SetServerSideEncryption(
+            Aws::S3::Model::ServerSideEncryption::aws_kms);
+        if (!sse_kms_key_id_.empty()) {
+            request.SetSSEKMSKeyId(ConvertToAwsString(sse_kms_key_id_));
+        }
+    } else if (sse_type_ == "SSE-S3") {
+        request.SetServerSideEncryption(
+            Aws::S3::Model::ServerSideEncryption::AES256);
+    }
+    if (!storage_class_.empty()) {
+        request.SetStorageClass(
+            Aws::S3::Model::StorageClassMapper::GetStorageClassForName(
+                ConvertToAwsString(storage_class_)));
+    }
+    if (!object_tagging_.empty()) {
+        request.SetTagging(ConvertToAwsString(object_tagging_));
+    }
+
     auto start = std::chrono::system_clock::now();
     auto outcome = client_->PutObject(request);
     internal_storage_request_latency_put.Observe(
@@ -210,6 +210,11 @@ class MinioChunkManager : public ChunkManager {
     void
     PreCheck(const StorageConfig& storage_config);
 
+    // Keep the server-side encryption, the storage class and the tags
+    // applied on every object put.
+    void
+    InitObjectPolicy(const StorageConfig& storage_config);
+
     void
     ShutdownSDKAPI();
     void
@@ -233,6 +238,10 @@ class MinioChunkManager : public ChunkManager {
     std::shared_ptr<Aws::S3::S3Client> client_;
     std::string default_bucket_name_;
     std::string remote_root_path_;
+    std::string sse_type_;
+    std::string sse_kms_key_id_;
+    std::string storage_class_;
+    std::string object_tagging_;
 };
 
 class AwsChunkManager : public MinioChunkManager {
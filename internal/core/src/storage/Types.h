@@ -99,6 +99,12 @@ struct StorageConfig {
     bool useIAM = false;
     bool useVirtualHost = false;
     int64_t requestTimeoutMs = 3000;
+    // the server-side encryption, SSE-S3 or SSE-KMS, the storage class and the tags
+    // in the form of the x-amz-tagging header of the objects put
+    std::string sse_type = "";
+    std::string sse_kms_key_id = "";
+    std::string storage_class = "";
+    std::string object_tagging = "";
 
     std::string
     ToString() const {
@@ -110,7 +116,9 @@ struct StorageConfig {
            << ", region=" << region << ", useSSL=" << std::boolalpha << useSSL
            << ", useIAM=" << std::boolalpha << useIAM
            << ", useVirtualHost=" << std::boolalpha << useVirtualHost
-           << ", requestTimeoutMs=" << requestTimeoutMs << "]";
+           << ", requestTimeoutMs=" << requestTimeoutMs
+           << ", sse_type=" << sse_type << ", storage_class=" << storage_class
+           << "]";
 
         return ss.str();
     }
@@ -75,6 +75,13 @@ InitRemoteChunkManagerSingleton(CStorageConfig c_storage_config) {
         storage_config.useVirtualHost = c_storage_config.useVirtualHost;
         storage_config.region = c_storage_config.region;
         storage_config.requestTimeoutMs = c_storage_config.requestTimeoutMs;
+        auto or_empty = [](const char* s) { return s ? std::string(s) : ""; };
+        storage_config.sse_type = or_empty(c_storage_config.sse_type);
+        storage_config.sse_kms_key_id =
+            or_empty(c_storage_config.sse_kms_key_id);
+        storage_config.storage_class = or_empty(c_storage_config.storage_class);
+        storage_config.object_tagging =
+            or_empty(c_storage_config.object_tagging);
         milvus::storage::RemoteChunkManagerSingleton::GetInstance().Init(
             storage_config);
 
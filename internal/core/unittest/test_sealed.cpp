@@ -953,6 +953,41 @@ TEST(Sealed, OverlapDelete) {
         << std::endl;
 }
 
+TEST(Sealed, LoadDeletedOffsets) {
+    auto dim = 16;
+    auto N = 10;
+    auto metric_type = knowhere::metric::L2;
+    auto schema = std::make_shared<Schema>();
+    auto fakevec_id = schema->AddDebugField(
+        "fakevec", DataType::VECTOR_FLOAT, dim, metric_type);
+    auto counter_id = schema->AddDebugField("counter", DataType::INT64);
+    schema->set_primary_field_id(counter_id);
+
+    auto dataset = DataGen(schema, N);
+    auto segment = CreateSealedSegment(schema);
+    SealedLoadFieldData(dataset, *segment);
+
+    std::vector<int64_t> offsets{7, 1, 3};
+    std::vector<Timestamp> timestamps{30, 10, 20};
+    segment->LoadDeletedOffsets(
+        offsets.data(), timestamps.data(), offsets.size());
+    ASSERT_EQ(segment->get_deleted_count(), offsets.size());
+
+    // only the deletes before the timestamp take effect
+    BitsetType bitset(N, false);
+    segment->mask_with_delete(bitset, N, 20);
+    ASSERT_EQ(bitset.count(), offsets.size() - 1);
+    ASSERT_TRUE(bitset[1]);
+    ASSERT_TRUE(bitset[3]);
+
+    BitsetType all(N, false);
+    segment->mask_with_delete(all, N, MAX_TIMESTAMP);
+    ASSERT_EQ(all.count(), offsets.size());
+
+    ASSERT_ANY_THROW(segment->LoadDeletedOffsets(
+        offsets.data(), timestamps.data(), 0));
+}
+
 auto
 GenMaxFloatVecs(int N, int dim) {
     std::vector<float> vecs;
@@ -0,0 +1,163 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/metastore/kv/binlog"
+	"github.com/milvus-io/milvus/internal/metastore/model"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	backupManifestFile      = "manifest.json"
+	backupFlushWaitInterval = 500 * time.Millisecond
+)
+
+// getBackupManifestPath returns the object path of the manifest of backup @name.
+func getBackupManifestPath(rootPath string, name string) string {
+	return path.Join(rootPath, common.BackupPath, name, backupManifestFile)
+}
+
+func checkBackupName(name string) error {
+	if name == "" {
+		return merr.WrapErrParameterInvalidMsg("backup name is empty")
+	}
+	if strings.ContainsAny(name, "/\\") || name == "." || name == ".." {
+		return merr.WrapErrParameterInvalidMsg("backup name %s is not a valid path element", name)
+	}
+	return nil
+}
+
+// waitForFlushed blocks until all the segments are flushed and all the channels of the collection
+// have consumed the messages before @flushTs, or @ctx is done.
+func (s *Server) waitForFlushed(ctx context.Context, collectionID UniqueID, segmentIDs []UniqueID, flushTs Timestamp) error {
+	ticker := time.NewTicker(backupFlushWaitInterval)
+	defer ticker.Stop()
+	for {
+		resp, err := s.GetFlushState(ctx, &datapb.GetFlushStateRequest{
+			SegmentIDs:   segmentIDs,
+			FlushTs:      flushTs,
+			CollectionID: collectionID,
+		})
+		if err = merr.CheckRPCCall(resp, err); err != nil {
+			return err
+		}
+		if resp.GetFlushed() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "wait for flush")
+		case <-ticker.C:
+		}
+	}
+}
+
+// selectBackupSegments returns the flushed segments of the collection, restricted to @partitionIDs if not empty.
+func (s *Server) selectBackupSegments(collectionID UniqueID, partitionIDs []UniqueID) []*SegmentInfo {
+	partitionSet := typeutil.NewUniqueSet(partitionIDs...)
+	return s.meta.SelectSegments(func(segment *SegmentInfo) bool {
+		return segment.GetCollectionID() == collectionID &&
+			(partitionSet.Len() == 0 || partitionSet.Contain(segment.GetPartitionID())) &&
+			isSegmentHealthy(segment) &&
+			isFlushState(segment.GetState()) &&
+			!segment.GetIsImporting()
+	})
+}
+
+// buildBackupManifest collects the meta of @segments and the binlog and index files they reference.
+// The files are recorded with their etags if the storage tells them, the contents of the binlogs are verified
+// by the CRC-32C checksums in the segment meta on restore. Otherwise each file is read once to compute its checksum.
+func buildBackupManifest(ctx context.Context, cm storage.ChunkManager, m *meta, segments []*SegmentInfo) (*datapb.BackupManifest, error) {
+	manifest := &datapb.BackupManifest{}
+	filePaths := make([]string, 0)
+	for _, segment := range segments {
+		cloned := segment.Clone()
+		if err := binlog.DecompressBinLogs(cloned.SegmentInfo); err != nil {
+			return nil, err
+		}
+		manifest.Segments = append(manifest.Segments, cloned.SegmentInfo)
+		for _, l := range getLogs(cloned) {
+			filePaths = append(filePaths, l.GetLogPath())
+		}
+
+		for _, segIdx := range m.GetSegmentIndexes(segment.GetID()) {
+			if segIdx.IsDeleted || len(segIdx.IndexFileKeys) == 0 {
+				continue
+			}
+			manifest.SegmentIndexes = append(manifest.SegmentIndexes, model.MarshalSegmentIndexModel(segIdx))
+			for _, fileKey := range segIdx.IndexFileKeys {
+				filePaths = append(filePaths, metautil.BuildSegmentIndexFilePath(cm.RootPath(), segIdx.BuildID,
+					segIdx.IndexVersion, segIdx.PartitionID, segIdx.SegmentID, fileKey))
+			}
+		}
+	}
+
+	etagCM, hasEtag := cm.(storage.EtagChunkManager)
+	for _, filePath := range filePaths {
+		if hasEtag {
+			etag, err := etagCM.Etag(ctx, filePath)
+			if err != nil {
+				log.Ctx(ctx).Warn("failed to get file etag for backup", zap.String("path", filePath), zap.Error(err))
+				return nil, err
+			}
+			manifest.Files = append(manifest.Files, &datapb.BackupFile{
+				Path: filePath,
+				Etag: etag,
+			})
+			continue
+		}
+		data, err := cm.Read(ctx, filePath)
+		if err != nil {
+			log.Ctx(ctx).Warn("failed to read file for backup", zap.String("path", filePath), zap.Error(err))
+			return nil, err
+		}
+		checksum := md5.Sum(data)
+		manifest.Files = append(manifest.Files, &datapb.BackupFile{
+			Path:     filePath,
+			Size:     int64(len(data)),
+			Checksum: hex.EncodeToString(checksum[:]),
+		})
+	}
+	return manifest, nil
+}
+
+// writeBackupManifest writes @manifest to @manifestPath as json.
+func writeBackupManifest(ctx context.Context, cm storage.ChunkManager, manifestPath string, manifest *datapb.BackupManifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return cm.Write(ctx, manifestPath, data)
+}
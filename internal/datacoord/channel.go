@@ -32,6 +32,9 @@ type ROChannel interface {
 	GetSchema() *schemapb.CollectionSchema
 	GetCreateTimestamp() Timestamp
 	GetWatchInfo() *datapb.ChannelWatchInfo
+	GetBinlogFormat() string
+	GetStorageTenant() string
+	GetBinlogCompression() string
 }
 
 type RWChannel interface {
@@ -48,6 +51,10 @@ type channelMeta struct {
 	Schema          *schemapb.CollectionSchema
 	CreateTimestamp uint64
 	WatchInfo       *datapb.ChannelWatchInfo
+	BinlogFormat    string
+	StorageTenant   string
+	// BinlogCompression is the codec compressing the insert and delta binlogs, uncompressed if empty
+	BinlogCompression string
 }
 
 func (ch *channelMeta) UpdateWatchInfo(info *datapb.ChannelWatchInfo) {
@@ -78,6 +85,18 @@ func (ch *channelMeta) GetCreateTimestamp() Timestamp {
 	return ch.CreateTimestamp
 }
 
+func (ch *channelMeta) GetBinlogFormat() string {
+	return ch.BinlogFormat
+}
+
+func (ch *channelMeta) GetStorageTenant() string {
+	return ch.StorageTenant
+}
+
+func (ch *channelMeta) GetBinlogCompression() string {
+	return ch.BinlogCompression
+}
+
 // String implement Stringer.
 func (ch *channelMeta) String() string {
 	// schema maybe too large to print
@@ -29,6 +29,7 @@ import (
 
 	"github.com/milvus-io/milvus/internal/kv"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/util/sessionutil"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/mq/msgstream"
@@ -49,6 +50,7 @@ type ChannelManager interface {
 	Match(nodeID int64, channel string) bool
 	FindWatcher(channel string) (int64, error)
 
+	GetNodeChannels(nodeID UniqueID) []RWChannel
 	GetNodeChannelsByCollectionID(collectionID UniqueID) map[UniqueID][]string
 	GetChannelsByCollectionID(collectionID UniqueID) []RWChannel
 	GetCollectionIDByChannel(channel string) (bool, UniqueID)
@@ -69,6 +71,7 @@ type ChannelManagerImpl struct {
 	bgChecker        ChannelBGChecker
 	balancePolicy    BalanceChannelPolicy
 	msgstreamFactory msgstream.Factory
+	featureGate      *sessionutil.FeatureGate
 
 	stateChecker channelStateChecker
 	stopChecker  context.CancelFunc
@@ -88,6 +91,10 @@ func withMsgstreamFactory(f msgstream.Factory) ChannelManagerOpt {
 	return func(c *ChannelManagerImpl) { c.msgstreamFactory = f }
 }
 
+func withFeatureGate(gate *sessionutil.FeatureGate) ChannelManagerOpt {
+	return func(c *ChannelManagerImpl) { c.featureGate = gate }
+}
+
 func withStateChecker() ChannelManagerOpt {
 	return func(c *ChannelManagerImpl) { c.stateChecker = c.watchChannelStatesLoop }
 }
@@ -471,11 +478,15 @@ func (c *ChannelManagerImpl) fillChannelWatchInfoWithState(op *ChannelOp, state
 	for _, ch := range op.Channels {
 		vcInfo := c.h.GetDataVChanPositions(ch, allPartitionID)
 		info := &datapb.ChannelWatchInfo{
-			Vchan:   vcInfo,
-			StartTs: startTs,
-			State:   state,
-			Schema:  ch.GetSchema(),
+			Vchan:             vcInfo,
+			StartTs:           startTs,
+			State:             state,
+			Schema:            ch.GetSchema(),
+			BinlogFormat:      ch.GetBinlogFormat(),
+			StorageTenant:     ch.GetStorageTenant(),
+			BinlogCompression: ch.GetBinlogCompression(),
 		}
+		gateBinlogEncodings(c.featureGate, info)
 
 		// Only set timer for watchInfo not from bufferID
 		if op.NodeID != bufferID {
@@ -488,6 +499,26 @@ func (c *ChannelManagerImpl) fillChannelWatchInfoWithState(op *ChannelOp, state
 	return channelsWithTimer
 }
 
+// gateBinlogEncodings falls back the binlog encodings of the channel watch info to the ones
+// supported by all the readers of the cluster, so that no binlog is written unreadable during rolling upgrade.
+// The encodings are not gated if the gate is nil.
+func gateBinlogEncodings(gate *sessionutil.FeatureGate, info *datapb.ChannelWatchInfo) {
+	info.DeltalogSorted = Params.DataNodeCfg.DeltalogSortedEnabled.GetAsBool()
+	if gate == nil {
+		return
+	}
+	if info.GetBinlogFormat() == common.BinlogFormatParquet && !gate.Support(sessionutil.FeatureParquetBinlog) {
+		info.BinlogFormat = common.BinlogFormatNative
+	}
+	if info.GetBinlogCompression() != "" && info.GetBinlogCompression() != common.BinlogCompressionNone &&
+		!gate.Support(sessionutil.FeatureCompressedBinlog) {
+		info.BinlogCompression = common.BinlogCompressionNone
+	}
+	if info.GetDeltalogSorted() && !gate.Support(sessionutil.FeatureSortedDeltalog) {
+		info.DeltalogSorted = false
+	}
+}
+
 // GetAssignedChannels gets channels info of registered nodes.
 func (c *ChannelManagerImpl) GetAssignedChannels() []*NodeChannelInfo {
 	c.mu.RLock()
@@ -504,6 +535,20 @@ func (c *ChannelManagerImpl) GetBufferChannels() *NodeChannelInfo {
 	return c.store.GetBufferChannelInfo()
 }
 
+// GetNodeChannels gets the channels assigned to the node
+func (c *ChannelManagerImpl) GetNodeChannels(nodeID UniqueID) []RWChannel {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	info := c.store.GetNode(nodeID)
+	if info == nil {
+		return nil
+	}
+	channels := make([]RWChannel, len(info.Channels))
+	copy(channels, info.Channels)
+	return channels
+}
+
 // GetNodeChannelsByCollectionID gets all node channels map of the collection
 func (c *ChannelManagerImpl) GetNodeChannelsByCollectionID(collectionID UniqueID) map[UniqueID][]string {
 	nodeChs := make(map[UniqueID][]string)
@@ -33,7 +33,10 @@ import (
 	"github.com/milvus-io/milvus/internal/kv"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/util/dependency"
+	"github.com/milvus-io/milvus/internal/util/sessionutil"
 	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
 // waitAndStore simulates DataNode's action
@@ -489,6 +492,40 @@ func TestChannelManager(t *testing.T) {
 		chManager.stateTimer.removeTimers([]string{chanToAdd})
 	})
 
+	t.Run("test Watch with binlog format and storage tenant", func(t *testing.T) {
+		defer watchkv.RemoveWithPrefix("")
+		var (
+			collectionID = UniqueID(7)
+			nodeID       = UniqueID(117)
+			chanToAdd    = "new-channel-parquet"
+		)
+
+		chManager, err := NewChannelManager(watchkv, newMockHandler())
+		require.NoError(t, err)
+		chManager.store.Add(nodeID)
+		err = chManager.Watch(context.TODO(), &channelMeta{Name: chanToAdd, CollectionID: collectionID, BinlogFormat: common.BinlogFormatParquet, StorageTenant: "tenant1", BinlogCompression: common.BinlogCompressionLz4})
+		assert.NoError(t, err)
+		waitAndCheckState(t, watchkv, datapb.ChannelWatchState_ToWatch, nodeID, chanToAdd, collectionID)
+		chManager.stateTimer.removeTimers([]string{chanToAdd})
+
+		v, err := watchkv.Load(path.Join(prefix, strconv.FormatInt(nodeID, 10), chanToAdd))
+		require.NoError(t, err)
+		watchInfo, err := parseWatchInfo(chanToAdd, []byte(v))
+		require.NoError(t, err)
+		assert.Equal(t, common.BinlogFormatParquet, watchInfo.GetBinlogFormat())
+		assert.Equal(t, "tenant1", watchInfo.GetStorageTenant())
+		assert.Equal(t, common.BinlogCompressionLz4, watchInfo.GetBinlogCompression())
+
+		// the binlog format and storage tenant are kept after reloaded
+		chManager, err = NewChannelManager(watchkv, newMockHandler())
+		require.NoError(t, err)
+		channels := chManager.GetChannelsByCollectionID(collectionID)
+		require.Len(t, channels, 1)
+		assert.Equal(t, common.BinlogFormatParquet, channels[0].GetBinlogFormat())
+		assert.Equal(t, "tenant1", channels[0].GetStorageTenant())
+		assert.Equal(t, common.BinlogCompressionLz4, channels[0].GetBinlogCompression())
+	})
+
 	t.Run("test Release", func(t *testing.T) {
 		defer watchkv.RemoveWithPrefix("")
 		var (
@@ -1297,3 +1334,41 @@ func TestChannelManager_BackgroundChannelChecker(t *testing.T) {
 		}, 5*time.Second, 1*time.Second)
 	})
 }
+
+func TestGateBinlogEncodings(t *testing.T) {
+	paramtable.Get().Save(Params.DataNodeCfg.DeltalogSortedEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.DataNodeCfg.DeltalogSortedEnabled.Key)
+
+	newInfo := func() *datapb.ChannelWatchInfo {
+		return &datapb.ChannelWatchInfo{
+			BinlogFormat:      common.BinlogFormatParquet,
+			BinlogCompression: common.BinlogCompressionZstd,
+		}
+	}
+	newGate := func(version int32) *sessionutil.FeatureGate {
+		session := sessionutil.NewMockSession(t)
+		session.EXPECT().GetSessions(typeutil.QueryNodeRole).Return(map[string]*sessionutil.Session{
+			"querynode-1": {SessionRaw: sessionutil.SessionRaw{ServerID: 1, ProtocolVersion: version}},
+		}, 0, nil).Once()
+		return sessionutil.NewFeatureGate(session, time.Hour, typeutil.QueryNodeRole)
+	}
+
+	info := newInfo()
+	gateBinlogEncodings(nil, info)
+	assert.Equal(t, common.BinlogFormatParquet, info.GetBinlogFormat())
+	assert.Equal(t, common.BinlogCompressionZstd, info.GetBinlogCompression())
+	assert.True(t, info.GetDeltalogSorted())
+
+	info = newInfo()
+	gateBinlogEncodings(newGate(sessionutil.CurrentProtocolVersion), info)
+	assert.Equal(t, common.BinlogFormatParquet, info.GetBinlogFormat())
+	assert.Equal(t, common.BinlogCompressionZstd, info.GetBinlogCompression())
+	assert.True(t, info.GetDeltalogSorted())
+
+	// the readers of the old versions don't support the new encodings
+	info = newInfo()
+	gateBinlogEncodings(newGate(4), info)
+	assert.Equal(t, common.BinlogFormatNative, info.GetBinlogFormat())
+	assert.Equal(t, common.BinlogCompressionNone, info.GetBinlogCompression())
+	assert.False(t, info.GetDeltalogSorted())
+}
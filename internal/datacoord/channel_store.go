@@ -259,10 +259,13 @@ func (c *ChannelStore) Reload() error {
 
 		c.Add(nodeID)
 		channel := &channelMeta{
-			Name:         cw.GetVchan().GetChannelName(),
-			CollectionID: cw.GetVchan().GetCollectionID(),
-			Schema:       cw.GetSchema(),
-			WatchInfo:    cw,
+			Name:              cw.GetVchan().GetChannelName(),
+			CollectionID:      cw.GetVchan().GetCollectionID(),
+			Schema:            cw.GetSchema(),
+			WatchInfo:         cw,
+			BinlogFormat:      cw.GetBinlogFormat(),
+			StorageTenant:     cw.GetStorageTenant(),
+			BinlogCompression: cw.GetBinlogCompression(),
 		}
 		c.channelsInfo[nodeID].Channels = append(c.channelsInfo[nodeID].Channels, channel)
 		log.Info("channel store reload channel",
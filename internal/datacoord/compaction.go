@@ -30,6 +30,7 @@ import (
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/util/sessionutil"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/tracer"
 	"github.com/milvus-io/milvus/pkg/util/conc"
@@ -57,6 +58,8 @@ type compactionPlanContext interface {
 	// get compaction tasks by signal id
 	getCompactionTasksBySignalID(signalID int64) []*compactionTask
 	removeTasksByChannel(channel string)
+	// listTasks returns the tasks queuing in the order to schedule, the tasks executing and the nodes under flush pressure
+	listTasks() (queuing []*compactionTask, executing []*compactionTask, pressuredNodes []int64)
 }
 
 type compactionTaskState int8
@@ -69,9 +72,30 @@ const (
 	timeout
 )
 
+func (s compactionTaskState) String() string {
+	switch s {
+	case executing:
+		return "executing"
+	case pipelining:
+		return "pipelining"
+	case completed:
+		return "completed"
+	case failed:
+		return "failed"
+	case timeout:
+		return "timeout"
+	default:
+		return "unknown"
+	}
+}
+
 var (
 	errChannelNotWatched = errors.New("channel is not watched")
 	errChannelInBuffer   = errors.New("channel is in buffer")
+
+	errLevelZeroCompactionNotSupported  = errors.New("level zero compaction is not supported by datanode")
+	errClusteringCompactionNotSupported = errors.New("clustering compaction is not supported by datanode")
+	errStatsRefreshNotSupported         = errors.New("stats refresh is not supported by datanode")
 )
 
 type CompactionMeta interface {
@@ -92,15 +116,29 @@ type compactionTask struct {
 	dataNodeID  int64
 	result      *datapb.CompactionPlanResult
 	span        trace.Span
+
+	priority       compactionPriority
+	preemptedTimes int32
+	// stolen is whether the task is executed by a datanode not watching the channel of it
+	stolen bool
+}
+
+// preemptible returns whether the task is preempted and held under flush pressure,
+// the task preempted for the maximum times isn't any more so that it never starves.
+func (t *compactionTask) preemptible() bool {
+	return t.priority.preemptible() && t.preemptedTimes < Params.DataCoordCfg.CompactionPreemptionMaxTimes.GetAsInt32()
 }
 
 func (t *compactionTask) shadowClone(opts ...compactionTaskOpt) *compactionTask {
 	task := &compactionTask{
-		triggerInfo: t.triggerInfo,
-		plan:        t.plan,
-		state:       t.state,
-		dataNodeID:  t.dataNodeID,
-		span:        t.span,
+		triggerInfo:    t.triggerInfo,
+		plan:           t.plan,
+		state:          t.state,
+		dataNodeID:     t.dataNodeID,
+		span:           t.span,
+		priority:       t.priority,
+		preemptedTimes: t.preemptedTimes,
+		stolen:         t.stolen,
 	}
 	for _, opt := range opts {
 		opt(task)
@@ -119,6 +157,8 @@ type compactionPlanHandler struct {
 	chManager ChannelManager
 	scheduler Scheduler
 	sessions  SessionManager
+	// featureGate holds back the binlog encodings of the plans, nil if not gated
+	featureGate *sessionutil.FeatureGate
 
 	stopCh   chan struct{}
 	stopOnce sync.Once
@@ -162,6 +202,11 @@ func (c *compactionPlanHandler) GetCurrentTS() (Timestamp, error) {
 func (c *compactionPlanHandler) schedule() {
 	// schedule queuing tasks
 	tasks := c.scheduler.Schedule()
+	for _, task := range tasks {
+		if task.stolen {
+			c.updateTask(task.plan.GetPlanID(), stealBy(task.dataNodeID))
+		}
+	}
 	if len(tasks) > 0 {
 		c.notifyTasks(tasks)
 		c.scheduler.LogStatus()
@@ -185,6 +230,9 @@ func (c *compactionPlanHandler) start() {
 				return
 			case <-checkResultTicker.C:
 				c.checkResult()
+				c.updateFlushPressure()
+				c.updateStealingNodes()
+				c.preempt()
 			}
 		}
 	}()
@@ -248,8 +296,8 @@ func (c *compactionPlanHandler) stop() {
 }
 
 func (c *compactionPlanHandler) removeTasksByChannel(channel string) {
+	var stolen []*compactionTask
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	for id, task := range c.plans {
 		if task.triggerInfo.channel == channel {
 			log.Info("Compaction handler removing tasks by channel",
@@ -259,8 +307,30 @@ func (c *compactionPlanHandler) removeTasksByChannel(channel string) {
 			)
 			c.scheduler.Finish(task.dataNodeID, task.plan)
 			delete(c.plans, id)
+			if task.stolen {
+				stolen = append(stolen, task)
+			}
 		}
 	}
+	c.mu.Unlock()
+
+	// the nodes stealing the tasks are not watching the channel, which are not aware of the removal
+	for _, task := range stolen {
+		c.dropStolenTask(task)
+	}
+}
+
+// dropStolenTask drops the stolen task from the node executing it, as the node not watching the channel
+// never gets the SyncSegments of the task to release it.
+func (c *compactionPlanHandler) dropStolenTask(task *compactionTask) {
+	err := c.sessions.DropCompactionPlan(context.Background(), task.dataNodeID, &datapb.DropCompactionPlanRequest{
+		PlanID:  task.plan.GetPlanID(),
+		Channel: task.plan.GetChannel(),
+	})
+	if err != nil {
+		log.Warn("failed to drop stolen compaction task", zap.Int64("planID", task.plan.GetPlanID()),
+			zap.Int64("nodeID", task.dataNodeID), zap.Error(err))
+	}
 }
 
 func (c *compactionPlanHandler) updateTask(planID int64, opts ...compactionTaskOpt) {
@@ -277,6 +347,25 @@ func (c *compactionPlanHandler) enqueuePlan(signal *compactionSignal, plan *data
 		log.Error("failed to find watcher", zap.Int64("planID", plan.GetPlanID()), zap.Error(err))
 		return err
 	}
+	if plan.GetType() == datapb.CompactionType_Level0DeleteCompaction &&
+		!c.sessions.SupportFeature(nodeID, sessionutil.FeatureLevelZeroCompaction) {
+		// the datanode is not upgraded yet, the plan will be triggered again after it is
+		log.Warn("failed to enqueue compaction plan", zap.Int64("planID", plan.GetPlanID()),
+			zap.Int64("nodeID", nodeID), zap.Error(errLevelZeroCompactionNotSupported))
+		return errLevelZeroCompactionNotSupported
+	}
+	if plan.GetType() == datapb.CompactionType_ClusteringCompaction &&
+		!c.sessions.SupportFeature(nodeID, sessionutil.FeatureClusteringCompaction) {
+		log.Warn("failed to enqueue compaction plan", zap.Int64("planID", plan.GetPlanID()),
+			zap.Int64("nodeID", nodeID), zap.Error(errClusteringCompactionNotSupported))
+		return errClusteringCompactionNotSupported
+	}
+	if plan.GetType() == datapb.CompactionType_StatsRefreshCompaction &&
+		!c.sessions.SupportFeature(nodeID, sessionutil.FeatureStatsRefresh) {
+		log.Warn("failed to enqueue compaction plan", zap.Int64("planID", plan.GetPlanID()),
+			zap.Int64("nodeID", nodeID), zap.Error(errStatsRefreshNotSupported))
+		return errStatsRefreshNotSupported
+	}
 
 	log := log.With(zap.Int64("planID", plan.GetPlanID()), zap.Int64("nodeID", nodeID))
 	c.setSegmentsCompacting(plan, true)
@@ -289,13 +378,14 @@ func (c *compactionPlanHandler) enqueuePlan(signal *compactionSignal, plan *data
 		state:       pipelining,
 		dataNodeID:  nodeID,
 		span:        span,
+		priority:    classifyCompaction(signal, plan),
 	}
 	c.mu.Lock()
 	c.plans[plan.PlanID] = task
 	c.mu.Unlock()
 
 	c.scheduler.Submit(task)
-	log.Info("Compaction plan submited")
+	log.Info("Compaction plan submited", zap.Stringer("priority", task.priority))
 	return nil
 }
 
@@ -314,13 +404,20 @@ func (c *compactionPlanHandler) RefreshPlan(task *compactionTask) {
 				info.GetDmlPosition().GetTimestamp() < task.triggerInfo.pos.GetTimestamp()
 		})
 
+		// the insert binlogs are required to resolve the deletes to row offsets
+		withBinlogs := Params.DataNodeCfg.DeltalogBitmapEnabled.GetAsBool() &&
+			(c.featureGate == nil || c.featureGate.Support(sessionutil.FeatureDeltalogBitmap))
 		sealedSegBinlogs := lo.Map(sealedSegments, func(info *SegmentInfo, _ int) *datapb.CompactionSegmentBinlogs {
-			return &datapb.CompactionSegmentBinlogs{
+			segBinlogs := &datapb.CompactionSegmentBinlogs{
 				SegmentID:    info.GetID(),
 				Level:        datapb.SegmentLevel_L1,
 				CollectionID: info.GetCollectionID(),
 				PartitionID:  info.GetPartitionID(),
 			}
+			if withBinlogs {
+				segBinlogs.FieldBinlogs = info.GetBinlogs()
+			}
+			return segBinlogs
 		})
 
 		plan.SegmentBinlogs = append(plan.SegmentBinlogs, sealedSegBinlogs...)
@@ -328,17 +425,66 @@ func (c *compactionPlanHandler) RefreshPlan(task *compactionTask) {
 		return
 	}
 
-	if plan.GetType() == datapb.CompactionType_MixCompaction {
+	if plan.GetType() == datapb.CompactionType_MixCompaction || plan.GetType() == datapb.CompactionType_ClusteringCompaction {
 		for _, seg := range plan.GetSegmentBinlogs() {
 			if info := c.meta.GetHealthySegment(seg.GetSegmentID()); info != nil {
 				seg.Deltalogs = info.GetDeltalogs()
 			}
 		}
-		log.Info("Compaction handler refreshed mix compaction plan")
+		if task.stolen {
+			plan.ChannelInfo = c.getStolenChannelInfo(plan)
+		}
+		log.Info("Compaction handler refreshed mix compaction plan", zap.Stringer("type", plan.GetType()))
 		return
 	}
 }
 
+// getStolenChannelInfo returns the channel info for the node not watching the channel to execute the plan,
+// the segments of the plan are the only flushed segments of the vchannel.
+func (c *compactionPlanHandler) getStolenChannelInfo(plan *datapb.CompactionPlan) *datapb.ChannelWatchInfo {
+	var channel RWChannel
+	if ok, collectionID := c.chManager.GetCollectionIDByChannel(plan.GetChannel()); ok {
+		channel, _ = lo.Find(c.chManager.GetChannelsByCollectionID(collectionID), func(ch RWChannel) bool {
+			return ch.GetName() == plan.GetChannel()
+		})
+	}
+	if channel == nil {
+		log.Warn("channel of stolen compaction plan not found", zap.Int64("planID", plan.GetPlanID()), zap.String("channel", plan.GetChannel()))
+		return nil
+	}
+
+	segments := make([]*datapb.SegmentInfo, 0, len(plan.GetSegmentBinlogs()))
+	for _, seg := range plan.GetSegmentBinlogs() {
+		info := c.meta.GetHealthySegment(seg.GetSegmentID())
+		if info == nil {
+			continue
+		}
+		segments = append(segments, &datapb.SegmentInfo{
+			ID:            info.GetID(),
+			CollectionID:  info.GetCollectionID(),
+			PartitionID:   info.GetPartitionID(),
+			InsertChannel: info.GetInsertChannel(),
+			NumOfRows:     info.GetNumOfRows(),
+			State:         commonpb.SegmentState_Flushed,
+			Level:         info.GetLevel(),
+		})
+	}
+
+	info := &datapb.ChannelWatchInfo{
+		Vchan: &datapb.VchannelInfo{
+			CollectionID:    channel.GetCollectionID(),
+			ChannelName:     channel.GetName(),
+			FlushedSegments: segments,
+		},
+		Schema:            channel.GetSchema(),
+		BinlogFormat:      channel.GetBinlogFormat(),
+		StorageTenant:     channel.GetStorageTenant(),
+		BinlogCompression: channel.GetBinlogCompression(),
+	}
+	gateBinlogEncodings(c.featureGate, info)
+	return info
+}
+
 func (c *compactionPlanHandler) notifyTasks(tasks []*compactionTask) {
 	for _, task := range tasks {
 		// avoid closure capture iteration variable
@@ -398,7 +544,7 @@ func (c *compactionPlanHandler) completeCompaction(result *datapb.CompactionPlan
 	nodeID := c.plans[planID].dataNodeID
 	defer c.scheduler.Finish(nodeID, plan)
 	switch plan.GetType() {
-	case datapb.CompactionType_MergeCompaction, datapb.CompactionType_MixCompaction:
+	case datapb.CompactionType_MergeCompaction, datapb.CompactionType_MixCompaction, datapb.CompactionType_ClusteringCompaction:
 		if err := c.handleMergeCompactionResult(plan, result); err != nil {
 			return err
 		}
@@ -406,6 +552,12 @@ func (c *compactionPlanHandler) completeCompaction(result *datapb.CompactionPlan
 		if err := c.handleL0CompactionResult(plan, result); err != nil {
 			return err
 		}
+	case datapb.CompactionType_StatsRefreshCompaction:
+		if err := c.handleStatsRefreshResult(plan, result); err != nil {
+			return err
+		}
+		// the segments refreshed are alive rather than compacted to others, which are compactable again
+		c.setSegmentsCompacting(plan, false)
 	default:
 		return errors.New("unknown compaction type")
 	}
@@ -415,7 +567,9 @@ func (c *compactionPlanHandler) completeCompaction(result *datapb.CompactionPlan
 }
 
 func (c *compactionPlanHandler) handleL0CompactionResult(plan *datapb.CompactionPlan, result *datapb.CompactionPlanResult) error {
-	var operators []UpdateOperator
+	operators := []UpdateOperator{
+		SegmentEventOperator(segmentEventActorCompaction, fmt.Sprintf("compacted by plan %d", plan.GetPlanID())),
+	}
 	for _, seg := range result.GetSegments() {
 		operators = append(operators, UpdateBinlogsOperator(seg.GetSegmentID(), nil, nil, seg.GetDeltalogs()))
 	}
@@ -434,46 +588,91 @@ func (c *compactionPlanHandler) handleL0CompactionResult(plan *datapb.Compaction
 	return c.meta.UpdateSegmentsInfo(operators...)
 }
 
+// handleStatsRefreshResult replaces the statslogs of the segments by the ones rewritten, the segments are kept as is
+// otherwise, so no segment is synced with the datanode.
+func (c *compactionPlanHandler) handleStatsRefreshResult(plan *datapb.CompactionPlan, result *datapb.CompactionPlanResult) error {
+	planned := lo.SliceToMap(plan.GetSegmentBinlogs(), func(b *datapb.CompactionSegmentBinlogs) (int64, struct{}) {
+		return b.GetSegmentID(), struct{}{}
+	})
+	operators := make([]UpdateOperator, 0, len(result.GetSegments()))
+	for _, seg := range result.GetSegments() {
+		if _, ok := planned[seg.GetSegmentID()]; !ok || len(seg.GetField2StatslogPaths()) == 0 {
+			// should never happen
+			return fmt.Errorf("illegal stats refresh result of segment %d: %v", seg.GetSegmentID(), result)
+		}
+		operators = append(operators, UpdateStatslogsOperator(seg.GetSegmentID(), seg.GetField2StatslogPaths()))
+	}
+
+	log.Info("meta update: update statslogs for stats refresh",
+		zap.Int64("planID", plan.GetPlanID()),
+		zap.Int("segments", len(result.GetSegments())),
+	)
+	return c.meta.UpdateSegmentsInfo(operators...)
+}
+
 func (c *compactionPlanHandler) handleMergeCompactionResult(plan *datapb.CompactionPlan, result *datapb.CompactionPlanResult) error {
 	log := log.With(zap.Int64("planID", plan.GetPlanID()))
-	if len(result.GetSegments()) == 0 || len(result.GetSegments()) > 1 {
+	if len(result.GetSegments()) == 0 ||
+		len(result.GetSegments()) > 1 && plan.GetType() != datapb.CompactionType_ClusteringCompaction {
 		// should never happen
 		log.Warn("illegal compaction results")
 		return fmt.Errorf("Illegal compaction results: %v", result)
 	}
 
-	// Merge compaction has one and only one segment
-	newSegmentInfo := c.meta.GetHealthySegment(result.GetSegments()[0].SegmentID)
-	if newSegmentInfo != nil {
+	// Merge compaction has one and only one segment, clustering compaction has one or more
+	var newSegments []*SegmentInfo
+	if newSegmentInfo := c.meta.GetHealthySegment(result.GetSegments()[0].SegmentID); newSegmentInfo != nil {
 		log.Info("meta has already been changed, skip meta change and retry sync segments")
+		newSegments = append(newSegments, newSegmentInfo)
+		for _, segment := range result.GetSegments()[1:] {
+			if info := c.meta.GetHealthySegment(segment.GetSegmentID()); info != nil {
+				newSegments = append(newSegments, info)
+			}
+		}
 	} else {
 		// Also prepare metric updates.
-		newSegments, metricMutation, err := c.meta.CompleteCompactionMutation(plan, result)
+		segments, metricMutation, err := c.meta.CompleteCompactionMutation(plan, result)
 		if err != nil {
 			return err
 		}
 		// Apply metrics after successful meta update.
 		metricMutation.commit()
-		newSegmentInfo = newSegments[0]
+		newSegments = segments
 	}
 
-	nodeID := c.plans[plan.GetPlanID()].dataNodeID
-	req := &datapb.SyncSegmentsRequest{
-		PlanID:        plan.PlanID,
-		CompactedTo:   newSegmentInfo.GetID(),
-		CompactedFrom: newSegmentInfo.GetCompactionFrom(),
-		NumOfRows:     newSegmentInfo.GetNumOfRows(),
-		StatsLogs:     newSegmentInfo.GetStatslogs(),
-		ChannelName:   plan.GetChannel(),
-		PartitionId:   newSegmentInfo.GetPartitionID(),
-		CollectionId:  newSegmentInfo.GetCollectionID(),
+	task := c.plans[plan.GetPlanID()]
+	nodeID := task.dataNodeID
+	if task.stolen {
+		// the segments are synced with the node watching the channel rather than the one executing the task
+		watcher, err := c.chManager.FindWatcher(plan.GetChannel())
+		if err != nil {
+			log.Warn("handleCompactionResult: fail to find watcher of stolen compaction", zap.Error(err))
+			return err
+		}
+		nodeID = watcher
+	}
+	for _, newSegmentInfo := range newSegments {
+		req := &datapb.SyncSegmentsRequest{
+			PlanID:        plan.PlanID,
+			CompactedTo:   newSegmentInfo.GetID(),
+			CompactedFrom: newSegmentInfo.GetCompactionFrom(),
+			NumOfRows:     newSegmentInfo.GetNumOfRows(),
+			StatsLogs:     newSegmentInfo.GetStatslogs(),
+			ChannelName:   plan.GetChannel(),
+			PartitionId:   newSegmentInfo.GetPartitionID(),
+			CollectionId:  newSegmentInfo.GetCollectionID(),
+		}
+
+		log.Info("handleCompactionResult: syncing segments with node", zap.Int64("nodeID", nodeID), zap.Int64("segmentID", newSegmentInfo.GetID()))
+		if err := c.sessions.SyncSegments(nodeID, req); err != nil {
+			log.Warn("handleCompactionResult: fail to sync segments with node",
+				zap.Int64("nodeID", nodeID), zap.Error(err))
+			return err
+		}
 	}
 
-	log.Info("handleCompactionResult: syncing segments with node", zap.Int64("nodeID", nodeID))
-	if err := c.sessions.SyncSegments(nodeID, req); err != nil {
-		log.Warn("handleCompactionResult: fail to sync segments with node",
-			zap.Int64("nodeID", nodeID), zap.Error(err))
-		return err
+	if task.stolen {
+		c.dropStolenTask(task)
 	}
 
 	log.Info("handleCompactionResult: success to handle merge compaction result")
@@ -558,6 +757,110 @@ func (c *compactionPlanHandler) updateCompaction(ts Timestamp) error {
 	return nil
 }
 
+// updateFlushPressure marks the nodes whose watched channels have the flushing segments over the threshold,
+// the preemptible tasks of them are held in the scheduler.
+func (c *compactionPlanHandler) updateFlushPressure() {
+	threshold := Params.DataCoordCfg.CompactionPreemptionFlushingThreshold.GetAsInt()
+	if threshold <= 0 {
+		c.scheduler.SetPressuredNodes(nil)
+		return
+	}
+
+	flushingSegments := c.meta.SelectSegments(func(info *SegmentInfo) bool {
+		return info.GetState() == commonpb.SegmentState_Flushing
+	})
+	flushing := make(map[string]int) // channel -> number of flushing segments
+	for _, info := range flushingSegments {
+		flushing[info.GetInsertChannel()]++
+	}
+
+	nodeFlushing := make(map[int64]int)
+	for channel, num := range flushing {
+		nodeID, err := c.chManager.FindWatcher(channel)
+		if err != nil {
+			continue
+		}
+		nodeFlushing[nodeID] += num
+	}
+
+	var pressured []int64
+	for nodeID, num := range nodeFlushing {
+		if num >= threshold {
+			pressured = append(pressured, nodeID)
+		}
+	}
+	c.scheduler.SetPressuredNodes(pressured)
+}
+
+// updateStealingNodes sets the nodes supporting the compactions of the channels not watched by them
+// as the stealing nodes, if the compaction stealing is enabled.
+func (c *compactionPlanHandler) updateStealingNodes() {
+	if !Params.DataCoordCfg.CompactionStealingEnabled.GetAsBool() {
+		c.scheduler.SetStealingNodes(nil)
+		return
+	}
+
+	nodes := lo.Filter(c.sessions.GetSessionIDs(), func(nodeID int64, _ int) bool {
+		return c.sessions.SupportFeature(nodeID, sessionutil.FeatureCompactionStealing)
+	})
+	c.scheduler.SetStealingNodes(nodes)
+}
+
+// preempt stops the preemptible tasks executing on the nodes under flush pressure and requeues them,
+// the tasks are scheduled again after the pressure falls, or once preempted for the maximum times.
+func (c *compactionPlanHandler) preempt() {
+	pressured := typeutil.NewUniqueSet(c.scheduler.GetPressuredNodes()...)
+	if pressured.Len() == 0 {
+		return
+	}
+
+	_, executingTasks := c.scheduler.ListTasks()
+	for _, t := range executingTasks {
+		if !pressured.Contain(t.dataNodeID) || !t.preemptible() {
+			continue
+		}
+		// the tasks still being notified are not preempted
+		task := c.getCompaction(t.plan.GetPlanID())
+		if task == nil || task.state != executing {
+			continue
+		}
+		// the datanodes of the older versions can't drop the plans
+		if !c.sessions.SupportFeature(task.dataNodeID, sessionutil.FeatureCompactionPreemption) {
+			continue
+		}
+
+		log := log.With(zap.Int64("planID", task.plan.GetPlanID()), zap.Int64("nodeID", task.dataNodeID))
+		err := c.sessions.DropCompactionPlan(context.Background(), task.dataNodeID, &datapb.DropCompactionPlanRequest{
+			PlanID:  task.plan.GetPlanID(),
+			Channel: task.plan.GetChannel(),
+		})
+		if err != nil {
+			log.Warn("failed to preempt compaction task", zap.Error(err))
+			continue
+		}
+		c.scheduler.Requeue(task.dataNodeID, task.plan)
+		c.updateTask(task.plan.GetPlanID(), setState(pipelining), setPreempted())
+		log.Info("Compaction task preempted under flush pressure", zap.Stringer("priority", task.priority))
+	}
+}
+
+func (c *compactionPlanHandler) listTasks() ([]*compactionTask, []*compactionTask, []int64) {
+	queuing, executing := c.scheduler.ListTasks()
+
+	// the scheduler keeps the tasks submitted, the states are of the latest ones
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	latest := func(tasks []*compactionTask) []*compactionTask {
+		return lo.Map(tasks, func(t *compactionTask, _ int) *compactionTask {
+			if task, ok := c.plans[t.plan.GetPlanID()]; ok {
+				return task
+			}
+			return t
+		})
+	}
+	return latest(queuing), latest(executing), c.scheduler.GetPressuredNodes()
+}
+
 func (c *compactionPlanHandler) isTimeout(now Timestamp, start Timestamp, timeout int32) bool {
 	startTime, _ := tsoutil.ParseTS(start)
 	ts, _ := tsoutil.ParseTS(now)
@@ -622,6 +925,20 @@ func setStartTime(startTime uint64) compactionTaskOpt {
 	}
 }
 
+func setPreempted() compactionTaskOpt {
+	return func(task *compactionTask) {
+		task.preemptedTimes++
+	}
+}
+
+// stealBy assigns the task to the datanode stealing it from the one watching the channel
+func stealBy(nodeID int64) compactionTaskOpt {
+	return func(task *compactionTask) {
+		task.dataNodeID = nodeID
+		task.stolen = true
+	}
+}
+
 func setResult(result *datapb.CompactionPlanResult) compactionTaskOpt {
 	return func(task *compactionTask) {
 		task.result = result
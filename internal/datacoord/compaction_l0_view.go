@@ -2,11 +2,13 @@ package datacoord
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/samber/lo"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
 )
 
 // The LevelZeroSegments keeps the min group
@@ -69,12 +71,12 @@ func (v *LevelZeroSegmentsView) Equal(others []*SegmentView) bool {
 
 // Trigger triggers all qualified LevelZeroSegments according to views
 func (v *LevelZeroSegmentsView) Trigger() (CompactionView, string) {
-	// Only choose segments with position less than the earliest growing segment position
-	validSegments := lo.Filter(v.segments, func(view *SegmentView, _ int) bool {
-		return view.dmlPos.GetTimestamp() < v.earliestGrowingSegmentPos.GetTimestamp()
-	})
+	validSegments := v.validSegments()
 
 	targetViews, reason := v.minCountSizeTrigger(validSegments)
+	if len(targetViews) == 0 && v.isIdle() {
+		targetViews, reason = v.forceTrigger(validSegments)
+	}
 	if len(targetViews) > 0 {
 		return &LevelZeroSegmentsView{
 			label:                     v.label,
@@ -86,6 +88,28 @@ func (v *LevelZeroSegmentsView) Trigger() (CompactionView, string) {
 	return nil, ""
 }
 
+// validSegments returns the segments with position less than the earliest growing segment position,
+// the deletes of which could be applied to the sealed segments.
+func (v *LevelZeroSegmentsView) validSegments() []*SegmentView {
+	return lo.Filter(v.segments, func(view *SegmentView, _ int) bool {
+		return view.dmlPos.GetTimestamp() < v.earliestGrowingSegmentPos.GetTimestamp()
+	})
+}
+
+// isIdle returns whether the oldest deletes of the valid segments are older than the max idle time,
+// the segments below the minimum trigger conditions are never compacted otherwise.
+func (v *LevelZeroSegmentsView) isIdle() bool {
+	segments := v.validSegments()
+	maxIdleTime := paramtable.Get().DataCoordCfg.LevelZeroCompactionTriggerMaxIdleTime.GetAsDuration(time.Second)
+	if maxIdleTime <= 0 || len(segments) == 0 {
+		return false
+	}
+	oldest := lo.MinBy(segments, func(a, b *SegmentView) bool {
+		return a.dmlPos.GetTimestamp() < b.dmlPos.GetTimestamp()
+	})
+	return time.Since(tsoutil.PhysicalTime(oldest.dmlPos.GetTimestamp())) >= maxIdleTime
+}
+
 // minCountSizeTrigger tries to trigger LevelZeroCompaction when segmentViews reaches minimum trigger conditions:
 // 1. count >= minDeltaCount, OR
 // 2. size >= minDeltaSize
@@ -2,6 +2,7 @@ package datacoord
 
 import (
 	"testing"
+	"time"
 
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/suite"
@@ -11,6 +12,8 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
 )
 
 func TestLevelZeroSegmentsViewSuite(t *testing.T) {
@@ -81,6 +84,10 @@ func (s *LevelZeroSegmentsViewSuite) TestEqual() {
 }
 
 func (s *LevelZeroSegmentsViewSuite) TestTrigger() {
+	// the positions of the views are not of the physical time
+	paramtable.Get().Save(paramtable.Get().DataCoordCfg.LevelZeroCompactionTriggerMaxIdleTime.Key, "0")
+	defer paramtable.Get().Reset(paramtable.Get().DataCoordCfg.LevelZeroCompactionTriggerMaxIdleTime.Key)
+
 	label := s.v.GetGroupLabel()
 	views := []*SegmentView{
 		genTestL0SegmentView(100, label, 20000),
@@ -254,3 +261,40 @@ func (s *LevelZeroSegmentsViewSuite) TestForceTrigger() {
 		})
 	}
 }
+
+func (s *LevelZeroSegmentsViewSuite) TestIdleTrigger() {
+	paramtable.Get().Save(paramtable.Get().DataCoordCfg.LevelZeroCompactionTriggerMaxIdleTime.Key, "600")
+	defer paramtable.Get().Reset(paramtable.Get().DataCoordCfg.LevelZeroCompactionTriggerMaxIdleTime.Key)
+
+	label := s.v.GetGroupLabel()
+	now := time.Now()
+	s.v.segments = []*SegmentView{
+		genTestL0SegmentView(100, label, tsoutil.ComposeTSByTime(now.Add(-time.Minute), 0)),
+		genTestL0SegmentView(101, label, tsoutil.ComposeTSByTime(now.Add(-2*time.Minute), 0)),
+		// not valid as it is after the earliest growing segment position
+		genTestL0SegmentView(102, label, tsoutil.ComposeTSByTime(now, 0)),
+	}
+	for _, view := range s.v.segments {
+		view.DeltaSize = 1
+		view.DeltalogCount = 1
+	}
+	s.v.earliestGrowingSegmentPos = &msgpb.MsgPosition{Timestamp: tsoutil.ComposeTSByTime(now.Add(-time.Second), 0)}
+
+	// the deletes are below the trigger conditions and not idle long enough
+	s.False(s.v.isIdle())
+	gotView, _ := s.v.Trigger()
+	s.Nil(gotView)
+
+	s.v.segments[1].dmlPos.Timestamp = tsoutil.ComposeTSByTime(now.Add(-time.Hour), 0)
+	s.True(s.v.isIdle())
+	gotView, reason := s.v.Trigger()
+	s.Require().NotNil(gotView)
+	s.ElementsMatch([]int64{100, 101}, lo.Map(gotView.GetSegmentsView(), func(v *SegmentView, _ int) int64 {
+		return v.ID
+	}))
+	s.NotEmpty(reason)
+
+	// never idle if disabled
+	paramtable.Get().Save(paramtable.Get().DataCoordCfg.LevelZeroCompactionTriggerMaxIdleTime.Key, "0")
+	s.False(s.v.isIdle())
+}
@@ -0,0 +1,89 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+)
+
+// compactionPriority is the priority of a compaction task in the scheduler,
+// the tasks of higher priorities are scheduled first.
+type compactionPriority int8
+
+const (
+	// mergePriority is of the compactions merging the small segments, which could be preempted under flush pressure.
+	mergePriority compactionPriority = iota + 1
+	// deltaPriority is of the compactions applying the deletes, the level zero ones and the delta-heavy mix ones.
+	deltaPriority
+	// manualPriority is of the compactions triggered manually.
+	manualPriority
+)
+
+func (p compactionPriority) String() string {
+	switch p {
+	case mergePriority:
+		return "merge"
+	case deltaPriority:
+		return "delta"
+	case manualPriority:
+		return "manual"
+	default:
+		return "unknown"
+	}
+}
+
+// preemptible returns whether the tasks of the priority could be preempted under flush pressure.
+func (p compactionPriority) preemptible() bool {
+	return p < deltaPriority
+}
+
+// classifyCompaction returns the priority of the compaction plan by its trigger and the segments compacted.
+func classifyCompaction(signal *compactionSignal, plan *datapb.CompactionPlan) compactionPriority {
+	if signal != nil && signal.isForce {
+		return manualPriority
+	}
+	if plan.GetType() == datapb.CompactionType_Level0DeleteCompaction || isDeltaHeavy(plan) {
+		return deltaPriority
+	}
+	return mergePriority
+}
+
+// isDeltaHeavy returns whether the deleted entries of the plan reach the delete ratio of a single compaction to its rows.
+func isDeltaHeavy(plan *datapb.CompactionPlan) bool {
+	var rows, deleted int64
+	for _, seg := range plan.GetSegmentBinlogs() {
+		for _, fieldBinlog := range seg.GetFieldBinlogs() {
+			for _, binlog := range fieldBinlog.GetBinlogs() {
+				rows += binlog.GetEntriesNum()
+			}
+			// the rows of a segment are counted by one field
+			break
+		}
+		for _, fieldBinlog := range seg.GetDeltalogs() {
+			for _, binlog := range fieldBinlog.GetBinlogs() {
+				deleted += binlog.GetEntriesNum()
+			}
+		}
+	}
+	if deleted == 0 {
+		return false
+	}
+	if rows == 0 {
+		return true
+	}
+	return float64(deleted)/float64(rows) >= Params.DataCoordCfg.SingleCompactionDeleteRatio.GetAsFloat()
+}
@@ -0,0 +1,56 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+)
+
+func TestClassifyCompaction(t *testing.T) {
+	segment := func(rows, deleted int64) *datapb.CompactionSegmentBinlogs {
+		seg := &datapb.CompactionSegmentBinlogs{
+			FieldBinlogs: []*datapb.FieldBinlog{
+				{FieldID: 100, Binlogs: []*datapb.Binlog{{EntriesNum: rows}}},
+				{FieldID: 101, Binlogs: []*datapb.Binlog{{EntriesNum: rows}}},
+			},
+		}
+		if deleted > 0 {
+			seg.Deltalogs = []*datapb.FieldBinlog{{Binlogs: []*datapb.Binlog{{EntriesNum: deleted}}}}
+		}
+		return seg
+	}
+	mixPlan := func(segments ...*datapb.CompactionSegmentBinlogs) *datapb.CompactionPlan {
+		return &datapb.CompactionPlan{Type: datapb.CompactionType_MixCompaction, SegmentBinlogs: segments}
+	}
+
+	assert.Equal(t, manualPriority, classifyCompaction(&compactionSignal{isForce: true}, mixPlan(segment(100, 0))))
+	assert.Equal(t, deltaPriority, classifyCompaction(&compactionSignal{}, &datapb.CompactionPlan{Type: datapb.CompactionType_Level0DeleteCompaction}))
+	assert.Equal(t, mergePriority, classifyCompaction(&compactionSignal{}, mixPlan(segment(100, 0), segment(100, 0))))
+	assert.Equal(t, mergePriority, classifyCompaction(nil, mixPlan(segment(100, 10), segment(100, 0))))
+	assert.Equal(t, deltaPriority, classifyCompaction(&compactionSignal{}, mixPlan(segment(100, 100), segment(100, 0))))
+	assert.Equal(t, deltaPriority, classifyCompaction(&compactionSignal{}, mixPlan(segment(0, 1))))
+
+	assert.True(t, mergePriority.preemptible())
+	assert.False(t, deltaPriority.preemptible())
+	assert.False(t, manualPriority.preemptible())
+	assert.Equal(t, "manual", manualPriority.String())
+	assert.Equal(t, "unknown", compactionPriority(0).String())
+}
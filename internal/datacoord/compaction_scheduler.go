@@ -2,6 +2,7 @@ package datacoord
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/samber/lo"
@@ -21,6 +22,17 @@ type Scheduler interface {
 	GetTaskCount() int
 	LogStatus()
 
+	// Requeue moves the executing task preempted back to the queue, so that it is scheduled again.
+	Requeue(nodeID int64, plan *datapb.CompactionPlan)
+	// ListTasks returns the queuing tasks in the order to schedule and the executing ones.
+	ListTasks() (queuing []*compactionTask, executing []*compactionTask)
+	// SetPressuredNodes sets the nodes under flush pressure, on which the preemptible tasks are not scheduled.
+	SetPressuredNodes(nodes []int64)
+	GetPressuredNodes() []int64
+	// SetStealingNodes sets the nodes able to execute the tasks of the channels not watched by them,
+	// the idle ones of them steal the merge tasks queuing on the nodes at the parallel limit.
+	SetStealingNodes(nodes []int64)
+
 	// Start()
 	// Stop()
 	// IsFull() bool
@@ -31,24 +43,48 @@ type CompactionScheduler struct {
 	taskNumber    *atomic.Int32
 	queuingTasks  []*compactionTask
 	parallelTasks map[int64][]*compactionTask // parallel by nodeID
-	mu            sync.RWMutex
+	// pressuredNodes are the nodes under flush pressure
+	pressuredNodes typeutil.UniqueSet
+	// stealingNodes are the nodes able to steal the tasks of the others
+	stealingNodes typeutil.UniqueSet
+	// placement picks the stealing node of a task, the first idle one is picked if nil
+	placement segmentPlacement
+	mu        sync.RWMutex
 
 	planHandler *compactionPlanHandler
 }
 
 var _ Scheduler = (*CompactionScheduler)(nil)
 
-func NewCompactionScheduler() *CompactionScheduler {
-	return &CompactionScheduler{
-		taskNumber:    atomic.NewInt32(0),
-		queuingTasks:  make([]*compactionTask, 0),
-		parallelTasks: make(map[int64][]*compactionTask),
+// segmentPlacement picks the node to compact the segments of the plan from the candidate nodes,
+// it returns false if no node is picked.
+type segmentPlacement func(nodeIDs []int64, plan *datapb.CompactionPlan) (int64, bool)
+
+type CompactionSchedulerOpt func(s *CompactionScheduler)
+
+// withSegmentPlacement sets the placement picking the stealing nodes of the tasks.
+func withSegmentPlacement(placement segmentPlacement) CompactionSchedulerOpt {
+	return func(s *CompactionScheduler) { s.placement = placement }
+}
+
+func NewCompactionScheduler(opts ...CompactionSchedulerOpt) *CompactionScheduler {
+	s := &CompactionScheduler{
+		taskNumber:     atomic.NewInt32(0),
+		queuingTasks:   make([]*compactionTask, 0),
+		parallelTasks:  make(map[int64][]*compactionTask),
+		pressuredNodes: typeutil.NewUniqueSet(),
+		stealingNodes:  typeutil.NewUniqueSet(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 func (s *CompactionScheduler) Submit(tasks ...*compactionTask) {
 	s.mu.Lock()
 	s.queuingTasks = append(s.queuingTasks, tasks...)
+	s.sortQueuingTasks()
 	s.mu.Unlock()
 
 	s.taskNumber.Add(int32(len(tasks)))
@@ -75,12 +111,17 @@ func (s *CompactionScheduler) Schedule() []*compactionTask {
 
 	executable := make(map[int64]*compactionTask)
 
-	pickPriorPolicy := func(tasks []*compactionTask, exclusiveChannels []string, executing []string) *compactionTask {
+	pickPriorPolicy := func(tasks []*compactionTask, exclusiveChannels []string, executing []string, pressured bool) *compactionTask {
 		for _, task := range tasks {
 			if lo.Contains(exclusiveChannels, task.plan.GetChannel()) {
 				continue
 			}
 
+			// Hold the preemptible tasks until the flush pressure of the node falls
+			if pressured && task.preemptible() {
+				continue
+			}
+
 			if task.plan.GetType() == datapb.CompactionType_Level0DeleteCompaction {
 				// Channel of LevelZeroCompaction task with no executing compactions
 				if !lo.Contains(executing, task.plan.GetChannel()) {
@@ -99,6 +140,20 @@ func (s *CompactionScheduler) Schedule() []*compactionTask {
 		return nil
 	}
 
+	// the tasks of a channel might be executing on the nodes other than the watcher if stolen
+	var (
+		executing         = typeutil.NewSet[string]()
+		channelsExecPrior = typeutil.NewSet[string]()
+	)
+	for _, parallel := range s.parallelTasks {
+		for _, t := range parallel {
+			executing.Insert(t.plan.GetChannel())
+			if t.plan.GetType() == datapb.CompactionType_Level0DeleteCompaction {
+				channelsExecPrior.Insert(t.plan.GetChannel())
+			}
+		}
+	}
+
 	// pick 1 or 0 task for 1 node
 	for node, tasks := range nodeTasks {
 		parallel := s.parallelTasks[node]
@@ -107,23 +162,14 @@ func (s *CompactionScheduler) Schedule() []*compactionTask {
 			continue
 		}
 
-		var (
-			executing         = typeutil.NewSet[string]()
-			channelsExecPrior = typeutil.NewSet[string]()
-		)
-		for _, t := range parallel {
-			executing.Insert(t.plan.GetChannel())
-			if t.plan.GetType() == datapb.CompactionType_Level0DeleteCompaction {
-				channelsExecPrior.Insert(t.plan.GetChannel())
-			}
-		}
-
-		picked := pickPriorPolicy(tasks, channelsExecPrior.Collect(), executing.Collect())
+		picked := pickPriorPolicy(tasks, channelsExecPrior.Collect(), executing.Collect(), s.pressuredNodes.Contain(node))
 		if picked != nil {
 			executable[node] = picked
 		}
 	}
 
+	stolenFrom := s.steal(executable, channelsExecPrior)
+
 	var pickPlans []int64
 	for node, task := range executable {
 		pickPlans = append(pickPlans, task.plan.PlanID)
@@ -132,10 +178,14 @@ func (s *CompactionScheduler) Schedule() []*compactionTask {
 		} else {
 			s.parallelTasks[node] = append(s.parallelTasks[node], task)
 		}
+		pendingNode := node
+		if owner, ok := stolenFrom[task.plan.PlanID]; ok {
+			pendingNode = owner
+		}
 		metrics.DataCoordCompactionTaskNum.
 			WithLabelValues(fmt.Sprint(node), task.plan.GetType().String(), metrics.Executing).Inc()
 		metrics.DataCoordCompactionTaskNum.
-			WithLabelValues(fmt.Sprint(node), task.plan.GetType().String(), metrics.Pending).Dec()
+			WithLabelValues(fmt.Sprint(pendingNode), task.plan.GetType().String(), metrics.Pending).Dec()
 	}
 
 	s.queuingTasks = lo.Filter(s.queuingTasks, func(t *compactionTask, _ int) bool {
@@ -152,6 +202,70 @@ func (s *CompactionScheduler) Schedule() []*compactionTask {
 	return lo.Values(executable)
 }
 
+// steal picks 1 or 0 task for each idle stealing node from the merge tasks queuing on the nodes at the parallel limit,
+// or held by the flush pressure of the nodes, and returns the nodes the tasks are stolen from by planID.
+// not threadsafe, the caller must hold the lock.
+func (s *CompactionScheduler) steal(executable map[int64]*compactionTask, channelsExecPrior typeutil.Set[string]) map[int64]int64 {
+	stolenFrom := make(map[int64]int64)
+	idle := lo.Filter(s.stealingNodes.Collect(), func(node int64, _ int) bool {
+		_, picked := executable[node]
+		return !picked && len(s.parallelTasks[node]) == 0 && !s.pressuredNodes.Contain(node)
+	})
+	if len(idle) == 0 {
+		return stolenFrom
+	}
+	sort.Slice(idle, func(i, j int) bool { return idle[i] < idle[j] })
+
+	exclusiveChannels := typeutil.NewSet[string]()
+	for _, task := range executable {
+		if task.plan.GetType() == datapb.CompactionType_Level0DeleteCompaction {
+			exclusiveChannels.Insert(task.plan.GetChannel())
+		}
+	}
+	exclusiveChannels.Insert(channelsExecPrior.Collect()...)
+
+	for _, task := range s.queuingTasks {
+		if len(idle) == 0 {
+			break
+		}
+		// the tasks after the queuing LevelZeroCompaction task of the channel are not scheduled before it
+		if task.plan.GetType() == datapb.CompactionType_Level0DeleteCompaction {
+			exclusiveChannels.Insert(task.plan.GetChannel())
+			continue
+		}
+		if exclusiveChannels.Contain(task.plan.GetChannel()) {
+			continue
+		}
+		if task.plan.GetType() != datapb.CompactionType_MixCompaction &&
+			task.plan.GetType() != datapb.CompactionType_ClusteringCompaction {
+			continue
+		}
+		if picked, ok := executable[task.dataNodeID]; ok && picked.plan.PlanID == task.plan.PlanID {
+			continue
+		}
+
+		owner := task.dataNodeID
+		busy := len(s.parallelTasks[owner]) >= calculateParallel()
+		held := s.pressuredNodes.Contain(owner) && task.preemptible()
+		if !busy && !held {
+			continue
+		}
+
+		node := idle[0]
+		if s.placement != nil {
+			if picked, ok := s.placement(idle, task.plan); ok {
+				node = picked
+			}
+		}
+		idle = lo.Without(idle, node)
+		executable[node] = task.shadowClone(stealBy(node))
+		stolenFrom[task.plan.PlanID] = owner
+		log.Info("Compaction task stolen by idle DataNode", zap.Int64("planID", task.plan.PlanID),
+			zap.Int64("owner", owner), zap.Int64("nodeID", node), zap.Bool("busy", busy), zap.Bool("held", held))
+	}
+	return stolenFrom
+}
+
 func (s *CompactionScheduler) Finish(nodeID UniqueID, plan *datapb.CompactionPlan) {
 	planID := plan.GetPlanID()
 	log := log.With(zap.Int64("planID", planID), zap.Int64("nodeID", nodeID))
@@ -187,6 +301,77 @@ func (s *CompactionScheduler) Finish(nodeID UniqueID, plan *datapb.CompactionPla
 	s.LogStatus()
 }
 
+// Requeue moves the executing task back to the head of the tasks of its priority in the queue,
+// the task is counted as preempted once more.
+func (s *CompactionScheduler) Requeue(nodeID UniqueID, plan *datapb.CompactionPlan) {
+	planID := plan.GetPlanID()
+	log := log.With(zap.Int64("planID", planID), zap.Int64("nodeID", nodeID))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := lo.Find(s.parallelTasks[nodeID], func(t *compactionTask) bool {
+		return t.plan.PlanID == planID
+	})
+	if !ok {
+		log.Warn("Compaction scheduler requeue task not executing")
+		return
+	}
+
+	s.parallelTasks[nodeID] = lo.Filter(s.parallelTasks[nodeID], func(t *compactionTask, _ int) bool {
+		return t.plan.PlanID != planID
+	})
+	s.queuingTasks = append([]*compactionTask{task.shadowClone(setPreempted())}, s.queuingTasks...)
+	s.sortQueuingTasks()
+	metrics.DataCoordCompactionTaskNum.
+		WithLabelValues(fmt.Sprint(nodeID), plan.GetType().String(), metrics.Executing).Dec()
+	metrics.DataCoordCompactionTaskNum.
+		WithLabelValues(fmt.Sprint(nodeID), plan.GetType().String(), metrics.Pending).Inc()
+	log.Info("Compaction scheduler requeue task")
+}
+
+// sortQueuingTasks sorts the queuing tasks by the priorities, the tasks of the same priority keep their order.
+// not threadsafe, the caller must hold the lock.
+func (s *CompactionScheduler) sortQueuingTasks() {
+	sort.SliceStable(s.queuingTasks, func(i, j int) bool {
+		return s.queuingTasks[i].priority > s.queuingTasks[j].priority
+	})
+}
+
+func (s *CompactionScheduler) ListTasks() ([]*compactionTask, []*compactionTask) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	queuing := make([]*compactionTask, len(s.queuingTasks))
+	copy(queuing, s.queuingTasks)
+	var executing []*compactionTask
+	for _, tasks := range s.parallelTasks {
+		executing = append(executing, tasks...)
+	}
+	return queuing, executing
+}
+
+func (s *CompactionScheduler) SetPressuredNodes(nodes []int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(nodes) > 0 || s.pressuredNodes.Len() > 0 {
+		log.Info("Compaction scheduler update flush pressured nodes",
+			zap.Int64s("previous", s.pressuredNodes.Collect()), zap.Int64s("current", nodes))
+	}
+	s.pressuredNodes = typeutil.NewUniqueSet(nodes...)
+}
+
+func (s *CompactionScheduler) GetPressuredNodes() []int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.pressuredNodes.Collect()
+}
+
+func (s *CompactionScheduler) SetStealingNodes(nodes []int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stealingNodes = typeutil.NewUniqueSet(nodes...)
+}
+
 func (s *CompactionScheduler) LogStatus() {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
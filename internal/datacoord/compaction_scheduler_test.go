@@ -215,3 +215,157 @@ func (s *SchedulerSuite) TestFinish() {
 		s.MetricsEqual(taskNum, 1)
 	})
 }
+
+func (s *SchedulerSuite) TestSchedulePriority() {
+	s.SetupTest()
+	s.scheduler.Submit(
+		&compactionTask{dataNodeID: 101, priority: mergePriority, plan: &datapb.CompactionPlan{PlanID: 10, Channel: "ch-2", Type: datapb.CompactionType_MixCompaction}},
+		&compactionTask{dataNodeID: 101, priority: deltaPriority, plan: &datapb.CompactionPlan{PlanID: 11, Channel: "ch-2", Type: datapb.CompactionType_MixCompaction}},
+		&compactionTask{dataNodeID: 101, priority: manualPriority, plan: &datapb.CompactionPlan{PlanID: 12, Channel: "ch-2", Type: datapb.CompactionType_MixCompaction}},
+		&compactionTask{dataNodeID: 101, priority: deltaPriority, plan: &datapb.CompactionPlan{PlanID: 13, Channel: "ch-2", Type: datapb.CompactionType_MixCompaction}},
+	)
+
+	queuing, executing := s.scheduler.ListTasks()
+	s.Equal([]int64{12, 11, 13, 10}, lo.Map(queuing, func(t *compactionTask, _ int) int64 { return t.plan.PlanID }))
+	s.Len(executing, 4)
+
+	gotTasks := s.scheduler.Schedule()
+	s.Equal([]int64{12}, lo.Map(gotTasks, func(t *compactionTask, _ int) int64 { return t.plan.PlanID }))
+}
+
+func (s *SchedulerSuite) TestSchedulePressuredNodes() {
+	s.SetupTest()
+	s.scheduler.SetPressuredNodes([]int64{101})
+	s.ElementsMatch([]int64{101}, s.scheduler.GetPressuredNodes())
+
+	s.scheduler.Submit(&compactionTask{dataNodeID: 101, priority: mergePriority, plan: &datapb.CompactionPlan{PlanID: 10, Channel: "ch-2", Type: datapb.CompactionType_MixCompaction}})
+	s.Empty(s.scheduler.Schedule())
+
+	// the tasks of higher priorities are still scheduled
+	s.scheduler.Submit(&compactionTask{dataNodeID: 101, priority: deltaPriority, plan: &datapb.CompactionPlan{PlanID: 11, Channel: "ch-2", Type: datapb.CompactionType_MixCompaction}})
+	gotTasks := s.scheduler.Schedule()
+	s.Equal([]int64{11}, lo.Map(gotTasks, func(t *compactionTask, _ int) int64 { return t.plan.PlanID }))
+
+	s.scheduler.SetPressuredNodes(nil)
+	s.Empty(s.scheduler.GetPressuredNodes())
+	s.scheduler.Finish(101, &datapb.CompactionPlan{PlanID: 3, Type: datapb.CompactionType_MixCompaction})
+	gotTasks = s.scheduler.Schedule()
+	s.Equal([]int64{10}, lo.Map(gotTasks, func(t *compactionTask, _ int) int64 { return t.plan.PlanID }))
+}
+
+func (s *SchedulerSuite) TestScheduleStealing() {
+	planIDs := func(tasks []*compactionTask) []int64 {
+		return lo.Map(tasks, func(t *compactionTask, _ int) int64 { return t.plan.PlanID })
+	}
+
+	s.Run("owner at parallel limit", func() {
+		s.SetupTest()
+		// node 100 is at the parallel limit, which is not idle either
+		s.scheduler.SetStealingNodes([]int64{100, 103, 104})
+		s.scheduler.Submit(
+			&compactionTask{dataNodeID: 100, plan: &datapb.CompactionPlan{PlanID: 10, Channel: "ch-1", Type: datapb.CompactionType_MixCompaction}},
+			&compactionTask{dataNodeID: 100, plan: &datapb.CompactionPlan{PlanID: 11, Channel: "ch-10", Type: datapb.CompactionType_Level0DeleteCompaction}},
+			&compactionTask{dataNodeID: 100, plan: &datapb.CompactionPlan{PlanID: 12, Channel: "ch-10", Type: datapb.CompactionType_MixCompaction}},
+			&compactionTask{dataNodeID: 100, plan: &datapb.CompactionPlan{PlanID: 13, Channel: "ch-3", Type: datapb.CompactionType_MixCompaction}},
+			&compactionTask{dataNodeID: 100, plan: &datapb.CompactionPlan{PlanID: 14, Channel: "ch-11", Type: datapb.CompactionType_MixCompaction}},
+			&compactionTask{dataNodeID: 100, plan: &datapb.CompactionPlan{PlanID: 15, Channel: "ch-12", Type: datapb.CompactionType_MixCompaction}},
+			&compactionTask{dataNodeID: 101, plan: &datapb.CompactionPlan{PlanID: 16, Channel: "ch-2", Type: datapb.CompactionType_MixCompaction}},
+		)
+
+		gotTasks := s.scheduler.Schedule()
+		s.ElementsMatch([]int64{10, 14, 16}, planIDs(gotTasks))
+		for _, task := range gotTasks {
+			switch task.plan.PlanID {
+			case 10:
+				s.EqualValues(103, task.dataNodeID)
+				s.True(task.stolen)
+			case 14:
+				s.EqualValues(104, task.dataNodeID)
+				s.True(task.stolen)
+			case 16:
+				s.EqualValues(101, task.dataNodeID)
+				s.False(task.stolen)
+			}
+		}
+
+		queuing, executing := s.scheduler.ListTasks()
+		// the tasks after the queuing L0 task of the channel, and of the channel with L0 task executing are not stolen
+		s.Equal([]int64{11, 12, 13, 15}, planIDs(queuing))
+		s.Len(executing, 7)
+		s.Equal([]int64{10}, planIDs(s.scheduler.parallelTasks[103]))
+
+		// the nodes with tasks executing are not idle
+		s.Empty(s.scheduler.Schedule())
+
+		s.scheduler.Finish(103, &datapb.CompactionPlan{PlanID: 10, Type: datapb.CompactionType_MixCompaction})
+		s.Equal([]int64{15}, planIDs(s.scheduler.Schedule()))
+	})
+
+	s.Run("owner under flush pressure", func() {
+		s.SetupTest()
+		s.scheduler.SetPressuredNodes([]int64{101})
+		s.scheduler.SetStealingNodes([]int64{103})
+		s.scheduler.Submit(&compactionTask{dataNodeID: 101, priority: mergePriority, plan: &datapb.CompactionPlan{PlanID: 10, Channel: "ch-2", Type: datapb.CompactionType_MixCompaction}})
+
+		gotTasks := s.scheduler.Schedule()
+		s.Require().Len(gotTasks, 1)
+		s.EqualValues(103, gotTasks[0].dataNodeID)
+	})
+
+	s.Run("segment placement", func() {
+		s.SetupTest()
+		var candidates [][]int64
+		s.scheduler.placement = func(nodeIDs []int64, plan *datapb.CompactionPlan) (int64, bool) {
+			candidates = append(candidates, nodeIDs)
+			// picks the last one and none for the channel ch-11
+			return nodeIDs[len(nodeIDs)-1], plan.GetChannel() != "ch-11"
+		}
+		s.scheduler.SetStealingNodes([]int64{103, 104, 105})
+		s.scheduler.Submit(
+			&compactionTask{dataNodeID: 100, plan: &datapb.CompactionPlan{PlanID: 10, Channel: "ch-1", Type: datapb.CompactionType_MixCompaction}},
+			&compactionTask{dataNodeID: 100, plan: &datapb.CompactionPlan{PlanID: 14, Channel: "ch-11", Type: datapb.CompactionType_MixCompaction}},
+		)
+
+		gotTasks := s.scheduler.Schedule()
+		s.ElementsMatch([]int64{10, 14}, planIDs(gotTasks))
+		s.Equal([][]int64{{103, 104, 105}, {103, 104}}, candidates)
+		s.Equal([]int64{10}, planIDs(s.scheduler.parallelTasks[105]))
+		s.Equal([]int64{14}, planIDs(s.scheduler.parallelTasks[103]))
+	})
+
+	s.Run("not stealing", func() {
+		s.SetupTest()
+		s.scheduler.Submit(&compactionTask{dataNodeID: 100, plan: &datapb.CompactionPlan{PlanID: 10, Channel: "ch-1", Type: datapb.CompactionType_MixCompaction}})
+		s.Empty(s.scheduler.Schedule())
+
+		// the stats refresh tasks are never stolen
+		s.scheduler.SetStealingNodes([]int64{103})
+		s.scheduler.Submit(&compactionTask{dataNodeID: 100, plan: &datapb.CompactionPlan{PlanID: 11, Channel: "ch-1", Type: datapb.CompactionType_StatsRefreshCompaction}})
+		s.Equal([]int64{10}, planIDs(s.scheduler.Schedule()))
+	})
+}
+
+func (s *SchedulerSuite) TestRequeue() {
+	s.SetupTest()
+	metrics.DataCoordCompactionTaskNum.Reset()
+	s.scheduler.Submit(&compactionTask{dataNodeID: 100, plan: &datapb.CompactionPlan{PlanID: 10, Channel: "ch-1", Type: datapb.CompactionType_MixCompaction}})
+
+	// requeued to the head of the tasks of the same priority
+	s.scheduler.Requeue(100, &datapb.CompactionPlan{PlanID: 1, Type: datapb.CompactionType_MixCompaction})
+	s.Equal(5, s.scheduler.GetTaskCount())
+	queuing, executing := s.scheduler.ListTasks()
+	s.Equal([]int64{1, 10}, lo.Map(queuing, func(t *compactionTask, _ int) int64 { return t.plan.PlanID }))
+	s.Len(executing, 3)
+
+	taskNum, err := metrics.DataCoordCompactionTaskNum.GetMetricWithLabelValues("100", datapb.CompactionType_MixCompaction.String(), metrics.Executing)
+	s.NoError(err)
+	s.MetricsEqual(taskNum, -1)
+	taskNum, err = metrics.DataCoordCompactionTaskNum.GetMetricWithLabelValues("100", datapb.CompactionType_MixCompaction.String(), metrics.Pending)
+	s.NoError(err)
+	s.MetricsEqual(taskNum, 2)
+
+	// not executing
+	s.scheduler.Requeue(100, &datapb.CompactionPlan{PlanID: 10, Type: datapb.CompactionType_MixCompaction})
+	queuing, _ = s.scheduler.ListTasks()
+	s.Len(queuing, 2)
+}
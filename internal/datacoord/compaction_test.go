@@ -17,6 +17,7 @@
 package datacoord
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -26,8 +27,11 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/util/sessionutil"
 	"github.com/milvus-io/milvus/pkg/util/metautil"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/tsoutil"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
@@ -128,9 +132,9 @@ func (s *CompactionPlanHandlerSuite) TestClean() {
 
 func (s *CompactionPlanHandlerSuite) TestHandleL0CompactionResults() {
 	channel := "Ch-1"
-	s.mockMeta.EXPECT().UpdateSegmentsInfo(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+	s.mockMeta.EXPECT().UpdateSegmentsInfo(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Run(func(operators ...UpdateOperator) {
-			s.Equal(7, len(operators))
+			s.Equal(8, len(operators))
 		}).Return(nil).Once()
 
 	deltalogs := []*datapb.FieldBinlog{getFieldBinlogIDs(101, 3)}
@@ -298,6 +302,202 @@ func (s *CompactionPlanHandlerSuite) TestExecCompactionPlan() {
 	}
 }
 
+func (s *CompactionPlanHandlerSuite) TestExecL0CompactionPlan() {
+	s.mockCm.EXPECT().FindWatcher(mock.Anything).Return(1, nil)
+	s.mockSch.EXPECT().Submit(mock.Anything).Return().Once()
+
+	handler := newCompactionPlanHandler(s.mockSessMgr, s.mockCm, s.mockMeta, s.mockAlloc)
+	handler.scheduler = s.mockSch
+
+	plan := &datapb.CompactionPlan{
+		PlanID:  1,
+		Channel: "ch-1",
+		Type:    datapb.CompactionType_Level0DeleteCompaction,
+	}
+
+	s.Run("datanode not upgraded", func() {
+		s.mockSessMgr.EXPECT().SupportFeature(int64(1), sessionutil.FeatureLevelZeroCompaction).Return(false).Once()
+		err := handler.execCompactionPlan(&compactionSignal{id: 1}, plan)
+		s.ErrorIs(err, errLevelZeroCompactionNotSupported)
+		s.Nil(handler.getCompaction(plan.GetPlanID()))
+	})
+
+	s.Run("normal", func() {
+		s.mockSessMgr.EXPECT().SupportFeature(int64(1), sessionutil.FeatureLevelZeroCompaction).Return(true).Once()
+		err := handler.execCompactionPlan(&compactionSignal{id: 2}, plan)
+		s.NoError(err)
+		s.NotNil(handler.getCompaction(plan.GetPlanID()))
+	})
+}
+
+func (s *CompactionPlanHandlerSuite) TestExecClusteringCompactionPlan() {
+	s.mockCm.EXPECT().FindWatcher(mock.Anything).Return(1, nil)
+	s.mockSch.EXPECT().Submit(mock.Anything).Return().Once()
+
+	handler := newCompactionPlanHandler(s.mockSessMgr, s.mockCm, s.mockMeta, s.mockAlloc)
+	handler.scheduler = s.mockSch
+
+	plan := &datapb.CompactionPlan{
+		PlanID:  1,
+		Channel: "ch-1",
+		Type:    datapb.CompactionType_ClusteringCompaction,
+	}
+
+	s.Run("datanode not upgraded", func() {
+		s.mockSessMgr.EXPECT().SupportFeature(int64(1), sessionutil.FeatureClusteringCompaction).Return(false).Once()
+		err := handler.execCompactionPlan(&compactionSignal{id: 1}, plan)
+		s.ErrorIs(err, errClusteringCompactionNotSupported)
+		s.Nil(handler.getCompaction(plan.GetPlanID()))
+	})
+
+	s.Run("normal", func() {
+		s.mockSessMgr.EXPECT().SupportFeature(int64(1), sessionutil.FeatureClusteringCompaction).Return(true).Once()
+		err := handler.execCompactionPlan(&compactionSignal{id: 2}, plan)
+		s.NoError(err)
+		s.NotNil(handler.getCompaction(plan.GetPlanID()))
+	})
+}
+
+func (s *CompactionPlanHandlerSuite) TestHandleClusteringCompactionResult() {
+	plan := &datapb.CompactionPlan{
+		PlanID: 1,
+		SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{
+			{SegmentID: 1},
+			{SegmentID: 2},
+		},
+		Type: datapb.CompactionType_ClusteringCompaction,
+	}
+	compactionResult := &datapb.CompactionPlanResult{
+		PlanID: plan.PlanID,
+		Segments: []*datapb.CompactionSegment{
+			{SegmentID: 3, NumOfRows: 15},
+			{SegmentID: 4, NumOfRows: 15},
+		},
+	}
+
+	s.Run("multiple results of mix compaction", func() {
+		s.SetupTest()
+		handler := newCompactionPlanHandler(s.mockSessMgr, s.mockCm, s.mockMeta, s.mockAlloc)
+		mixPlan := &datapb.CompactionPlan{PlanID: 1, Type: datapb.CompactionType_MixCompaction}
+		s.Error(handler.handleMergeCompactionResult(mixPlan, compactionResult))
+	})
+
+	s.Run("sync all the result segments", func() {
+		s.SetupTest()
+		s.mockMeta.EXPECT().GetHealthySegment(int64(3)).Return(nil).Once()
+		s.mockMeta.EXPECT().CompleteCompactionMutation(mock.Anything, mock.Anything).Return(
+			[]*SegmentInfo{
+				NewSegmentInfo(&datapb.SegmentInfo{ID: 3, NumOfRows: 15, CompactionFrom: []int64{1, 2}}),
+				NewSegmentInfo(&datapb.SegmentInfo{ID: 4, NumOfRows: 15, CompactionFrom: []int64{1, 2}}),
+			},
+			&segMetricMutation{}, nil).Once()
+		synced := make([]int64, 0)
+		s.mockSessMgr.EXPECT().SyncSegments(int64(111), mock.Anything).RunAndReturn(
+			func(nodeID int64, req *datapb.SyncSegmentsRequest) error {
+				s.ElementsMatch([]int64{1, 2}, req.GetCompactedFrom())
+				synced = append(synced, req.GetCompactedTo())
+				return nil
+			}).Twice()
+
+		handler := newCompactionPlanHandler(s.mockSessMgr, s.mockCm, s.mockMeta, s.mockAlloc)
+		handler.plans[plan.PlanID] = &compactionTask{dataNodeID: 111, plan: plan}
+		s.NoError(handler.handleMergeCompactionResult(plan, compactionResult))
+		s.Equal([]int64{3, 4}, synced)
+	})
+
+	s.Run("retry sync segments", func() {
+		s.SetupTest()
+		s.mockMeta.EXPECT().GetHealthySegment(mock.Anything).RunAndReturn(func(segmentID int64) *SegmentInfo {
+			return NewSegmentInfo(&datapb.SegmentInfo{ID: segmentID, CompactionFrom: []int64{1, 2}})
+		}).Twice()
+		s.mockSessMgr.EXPECT().SyncSegments(int64(111), mock.Anything).Return(nil).Twice()
+
+		handler := newCompactionPlanHandler(s.mockSessMgr, s.mockCm, s.mockMeta, s.mockAlloc)
+		handler.plans[plan.PlanID] = &compactionTask{dataNodeID: 111, plan: plan}
+		s.NoError(handler.handleMergeCompactionResult(plan, compactionResult))
+	})
+}
+
+func (s *CompactionPlanHandlerSuite) TestExecStatsRefreshPlan() {
+	s.mockCm.EXPECT().FindWatcher(mock.Anything).Return(1, nil)
+	s.mockSch.EXPECT().Submit(mock.Anything).Return().Once()
+
+	handler := newCompactionPlanHandler(s.mockSessMgr, s.mockCm, s.mockMeta, s.mockAlloc)
+	handler.scheduler = s.mockSch
+
+	plan := &datapb.CompactionPlan{
+		PlanID:         1,
+		Channel:        "ch-1",
+		Type:           datapb.CompactionType_StatsRefreshCompaction,
+		SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{{SegmentID: 100}},
+	}
+
+	s.Run("datanode not upgraded", func() {
+		s.mockSessMgr.EXPECT().SupportFeature(int64(1), sessionutil.FeatureStatsRefresh).Return(false).Once()
+		err := handler.execCompactionPlan(&compactionSignal{id: 1}, plan)
+		s.ErrorIs(err, errStatsRefreshNotSupported)
+		s.Nil(handler.getCompaction(plan.GetPlanID()))
+	})
+
+	s.Run("normal", func() {
+		s.mockSessMgr.EXPECT().SupportFeature(int64(1), sessionutil.FeatureStatsRefresh).Return(true).Once()
+		s.mockMeta.EXPECT().SetSegmentCompacting(int64(100), true).Return().Once()
+		err := handler.execCompactionPlan(&compactionSignal{id: 2}, plan)
+		s.NoError(err)
+		s.NotNil(handler.getCompaction(plan.GetPlanID()))
+	})
+}
+
+func (s *CompactionPlanHandlerSuite) TestHandleStatsRefreshResult() {
+	plan := &datapb.CompactionPlan{
+		PlanID: 1,
+		SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{
+			{SegmentID: 100, Field2StatslogPaths: []*datapb.FieldBinlog{getFieldBinlogIDs(101, 1)}},
+		},
+		Type: datapb.CompactionType_StatsRefreshCompaction,
+	}
+
+	s.Run("normal", func() {
+		s.SetupTest()
+		s.mockMeta.EXPECT().UpdateSegmentsInfo(mock.Anything).Return(nil).Once()
+		s.mockMeta.EXPECT().SetSegmentCompacting(int64(100), false).Return().Once()
+		s.mockSch.EXPECT().Finish(int64(111), plan).Return().Once()
+
+		handler := newCompactionPlanHandler(s.mockSessMgr, s.mockCm, s.mockMeta, s.mockAlloc)
+		handler.scheduler = s.mockSch
+		handler.plans[plan.PlanID] = &compactionTask{
+			triggerInfo: &compactionSignal{id: 1},
+			state:       executing,
+			plan:        plan,
+			dataNodeID:  111,
+		}
+		// no segment is synced with the datanode
+		err := handler.completeCompaction(&datapb.CompactionPlanResult{
+			PlanID: plan.GetPlanID(),
+			Segments: []*datapb.CompactionSegment{
+				{SegmentID: 100, NumOfRows: 10, Field2StatslogPaths: []*datapb.FieldBinlog{getFieldBinlogIDs(101, 2)}},
+			},
+		})
+		s.NoError(err)
+		s.Equal(completed, handler.getCompaction(plan.GetPlanID()).state)
+	})
+
+	s.Run("illegal results", func() {
+		s.SetupTest()
+		handler := newCompactionPlanHandler(s.mockSessMgr, s.mockCm, s.mockMeta, s.mockAlloc)
+		for _, segment := range []*datapb.CompactionSegment{
+			{SegmentID: 200, Field2StatslogPaths: []*datapb.FieldBinlog{getFieldBinlogIDs(101, 2)}},
+			{SegmentID: 100},
+		} {
+			err := handler.handleStatsRefreshResult(plan, &datapb.CompactionPlanResult{
+				PlanID:   plan.GetPlanID(),
+				Segments: []*datapb.CompactionSegment{segment},
+			})
+			s.Error(err)
+		}
+	})
+}
+
 func (s *CompactionPlanHandlerSuite) TestHandleMergeCompactionResult() {
 	plan := &datapb.CompactionPlan{
 		PlanID: 1,
@@ -558,6 +758,206 @@ func (s *CompactionPlanHandlerSuite) TestUpdateCompaction() {
 	s.Equal(failed, task.state)
 }
 
+func (s *CompactionPlanHandlerSuite) TestUpdateFlushPressure() {
+	paramtable.Get().Save(Params.DataCoordCfg.CompactionPreemptionFlushingThreshold.Key, "2")
+	defer paramtable.Get().Reset(Params.DataCoordCfg.CompactionPreemptionFlushingThreshold.Key)
+
+	s.mockMeta.EXPECT().SelectSegments(mock.Anything).Return([]*SegmentInfo{
+		NewSegmentInfo(&datapb.SegmentInfo{ID: 1, InsertChannel: "ch-1", State: commonpb.SegmentState_Flushing}),
+		NewSegmentInfo(&datapb.SegmentInfo{ID: 2, InsertChannel: "ch-2", State: commonpb.SegmentState_Flushing}),
+		NewSegmentInfo(&datapb.SegmentInfo{ID: 3, InsertChannel: "ch-3", State: commonpb.SegmentState_Flushing}),
+		NewSegmentInfo(&datapb.SegmentInfo{ID: 4, InsertChannel: "ch-4", State: commonpb.SegmentState_Flushing}),
+	}).Once()
+	s.mockCm.EXPECT().FindWatcher("ch-1").Return(100, nil).Once()
+	s.mockCm.EXPECT().FindWatcher("ch-2").Return(100, nil).Once()
+	s.mockCm.EXPECT().FindWatcher("ch-3").Return(101, nil).Once()
+	s.mockCm.EXPECT().FindWatcher("ch-4").Return(0, errChannelNotWatched).Once()
+	handler := newCompactionPlanHandler(nil, s.mockCm, s.mockMeta, nil)
+	handler.updateFlushPressure()
+	s.Equal([]int64{100}, handler.scheduler.GetPressuredNodes())
+
+	// disabled
+	paramtable.Get().Save(Params.DataCoordCfg.CompactionPreemptionFlushingThreshold.Key, "0")
+	handler.updateFlushPressure()
+	s.Empty(handler.scheduler.GetPressuredNodes())
+}
+
+func (s *CompactionPlanHandlerSuite) TestPreempt() {
+	handler := newCompactionPlanHandler(s.mockSessMgr, nil, nil, nil)
+	tasks := []*compactionTask{
+		{triggerInfo: &compactionSignal{id: 1}, dataNodeID: 100, priority: mergePriority, state: executing, plan: &datapb.CompactionPlan{PlanID: 1, Channel: "ch-1"}},
+		{triggerInfo: &compactionSignal{id: 1}, dataNodeID: 100, priority: deltaPriority, state: executing, plan: &datapb.CompactionPlan{PlanID: 2, Channel: "ch-1"}},
+		{triggerInfo: &compactionSignal{id: 1}, dataNodeID: 100, priority: mergePriority, state: executing, plan: &datapb.CompactionPlan{PlanID: 3, Channel: "ch-2"}},
+		{triggerInfo: &compactionSignal{id: 1}, dataNodeID: 101, priority: mergePriority, state: executing, plan: &datapb.CompactionPlan{PlanID: 4, Channel: "ch-3"}},
+		{triggerInfo: &compactionSignal{id: 1}, dataNodeID: 100, priority: mergePriority, state: pipelining, plan: &datapb.CompactionPlan{PlanID: 5, Channel: "ch-4"}},
+		{triggerInfo: &compactionSignal{id: 1}, dataNodeID: 100, priority: mergePriority, state: executing, preemptedTimes: 3, plan: &datapb.CompactionPlan{PlanID: 6, Channel: "ch-5"}},
+		{triggerInfo: &compactionSignal{id: 1}, dataNodeID: 102, priority: mergePriority, state: executing, plan: &datapb.CompactionPlan{PlanID: 7, Channel: "ch-6"}},
+	}
+	for _, task := range tasks {
+		handler.plans[task.plan.GetPlanID()] = task
+	}
+	handler.scheduler.(*CompactionScheduler).parallelTasks = map[int64][]*compactionTask{
+		100: {tasks[0], tasks[1], tasks[2], tasks[4], tasks[5]},
+		101: {tasks[3]},
+		102: {tasks[6]},
+	}
+
+	// no pressure
+	handler.preempt()
+
+	// the datanode 102 doesn't support the preemption, the task 6 is preempted for the maximum times
+	handler.scheduler.SetPressuredNodes([]int64{100, 102})
+	s.mockSessMgr.EXPECT().SupportFeature(mock.Anything, sessionutil.FeatureCompactionPreemption).
+		RunAndReturn(func(nodeID int64, _ sessionutil.Feature) bool { return nodeID == 100 })
+	s.mockSessMgr.EXPECT().DropCompactionPlan(mock.Anything, int64(100), mock.Anything).
+		RunAndReturn(func(ctx context.Context, nodeID int64, req *datapb.DropCompactionPlanRequest) error {
+			if req.GetPlanID() == 3 {
+				return errors.New("mock")
+			}
+			s.EqualValues(1, req.GetPlanID())
+			s.Equal("ch-1", req.GetChannel())
+			return nil
+		}).Twice()
+	handler.preempt()
+
+	queuing, executing, pressured := handler.listTasks()
+	s.ElementsMatch([]int64{100, 102}, pressured)
+	s.Require().Len(queuing, 1)
+	s.EqualValues(1, queuing[0].plan.GetPlanID())
+	s.Equal(pipelining, queuing[0].state)
+	s.EqualValues(1, queuing[0].preemptedTimes)
+	s.ElementsMatch([]int64{2, 3, 4, 5, 6, 7}, lo.Map(executing, func(t *compactionTask, _ int) int64 { return t.plan.GetPlanID() }))
+
+	// the requeued task counts the preemption, it's held no more once preempted for the maximum times
+	requeued, _ := handler.scheduler.ListTasks()
+	s.EqualValues(1, requeued[0].preemptedTimes)
+	s.True(requeued[0].preemptible())
+	requeued[0].preemptedTimes = 3
+	s.False(requeued[0].preemptible())
+}
+
+func (s *CompactionPlanHandlerSuite) TestUpdateStealingNodes() {
+	handler := newCompactionPlanHandler(s.mockSessMgr, nil, nil, nil)
+	stealingNodes := func() []int64 {
+		return handler.scheduler.(*CompactionScheduler).stealingNodes.Collect()
+	}
+
+	// disabled by default
+	handler.updateStealingNodes()
+	s.Empty(stealingNodes())
+
+	paramtable.Get().Save(Params.DataCoordCfg.CompactionStealingEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.DataCoordCfg.CompactionStealingEnabled.Key)
+	s.mockSessMgr.EXPECT().GetSessionIDs().Return([]int64{100, 101, 102}).Once()
+	s.mockSessMgr.EXPECT().SupportFeature(mock.Anything, sessionutil.FeatureCompactionStealing).RunAndReturn(
+		func(nodeID int64, _ sessionutil.Feature) bool {
+			return nodeID != 101
+		}).Times(3)
+	handler.updateStealingNodes()
+	s.ElementsMatch([]int64{100, 102}, stealingNodes())
+}
+
+func (s *CompactionPlanHandlerSuite) TestStolenCompaction() {
+	schema := &schemapb.CollectionSchema{Name: "test"}
+	newPlan := func() *datapb.CompactionPlan {
+		return &datapb.CompactionPlan{
+			PlanID:  1,
+			Channel: "ch-1",
+			SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{
+				{SegmentID: 1},
+				{SegmentID: 2},
+			},
+			Type: datapb.CompactionType_MixCompaction,
+		}
+	}
+	plan := newPlan()
+
+	s.Run("refresh plan", func() {
+		s.SetupTest()
+		s.mockMeta.EXPECT().GetHealthySegment(mock.Anything).RunAndReturn(func(segID int64) *SegmentInfo {
+			return NewSegmentInfo(&datapb.SegmentInfo{
+				ID: segID, CollectionID: 10, PartitionID: 100, InsertChannel: "ch-1", NumOfRows: 1000,
+				State: commonpb.SegmentState_Flushed, Level: datapb.SegmentLevel_L1,
+			})
+		})
+		s.mockCm.EXPECT().GetCollectionIDByChannel("ch-1").Return(true, 10).Once()
+		s.mockCm.EXPECT().GetChannelsByCollectionID(int64(10)).Return([]RWChannel{
+			&channelMeta{Name: "ch-0", CollectionID: 10},
+			&channelMeta{Name: "ch-1", CollectionID: 10, Schema: schema, BinlogFormat: "parquet", StorageTenant: "tenant"},
+		}).Once()
+
+		handler := newCompactionPlanHandler(s.mockSessMgr, s.mockCm, s.mockMeta, s.mockAlloc)
+		task := &compactionTask{triggerInfo: &compactionSignal{id: 1}, plan: newPlan(), dataNodeID: 111, stolen: true}
+		handler.RefreshPlan(task)
+
+		info := task.plan.GetChannelInfo()
+		s.Require().NotNil(info)
+		s.Equal(schema, info.GetSchema())
+		s.Equal("parquet", info.GetBinlogFormat())
+		s.Equal("tenant", info.GetStorageTenant())
+		s.Equal("ch-1", info.GetVchan().GetChannelName())
+		s.EqualValues(10, info.GetVchan().GetCollectionID())
+		s.Require().Len(info.GetVchan().GetFlushedSegments(), 2)
+		for _, segment := range info.GetVchan().GetFlushedSegments() {
+			s.EqualValues(1000, segment.GetNumOfRows())
+			s.Equal(commonpb.SegmentState_Flushed, segment.GetState())
+			s.Empty(segment.GetBinlogs())
+		}
+
+		// the plans not stolen carry no channel info
+		task = &compactionTask{triggerInfo: &compactionSignal{id: 1}, plan: newPlan(), dataNodeID: 111}
+		handler.RefreshPlan(task)
+		s.Nil(task.plan.GetChannelInfo())
+	})
+
+	s.Run("sync segments with watcher", func() {
+		s.SetupTest()
+		s.mockMeta.EXPECT().GetHealthySegment(int64(3)).Return(nil).Once()
+		s.mockMeta.EXPECT().CompleteCompactionMutation(mock.Anything, mock.Anything).Return(
+			[]*SegmentInfo{NewSegmentInfo(&datapb.SegmentInfo{ID: 3, CompactionFrom: []int64{1, 2}})}, &segMetricMutation{}, nil).Once()
+		s.mockCm.EXPECT().FindWatcher("ch-1").Return(100, nil).Once()
+		s.mockSessMgr.EXPECT().SyncSegments(int64(100), mock.Anything).Return(nil).Once()
+		s.mockSessMgr.EXPECT().DropCompactionPlan(mock.Anything, int64(111), mock.Anything).
+			RunAndReturn(func(ctx context.Context, nodeID int64, req *datapb.DropCompactionPlanRequest) error {
+				s.EqualValues(1, req.GetPlanID())
+				return nil
+			}).Once()
+
+		handler := newCompactionPlanHandler(s.mockSessMgr, s.mockCm, s.mockMeta, s.mockAlloc)
+		handler.plans[plan.PlanID] = &compactionTask{dataNodeID: 111, plan: plan, stolen: true}
+		err := handler.handleMergeCompactionResult(plan, &datapb.CompactionPlanResult{
+			PlanID:   plan.PlanID,
+			Segments: []*datapb.CompactionSegment{{SegmentID: 3, NumOfRows: 15}},
+		})
+		s.NoError(err)
+	})
+
+	s.Run("watcher not found", func() {
+		s.SetupTest()
+		s.mockMeta.EXPECT().GetHealthySegment(int64(3)).Return(NewSegmentInfo(&datapb.SegmentInfo{ID: 3})).Once()
+		s.mockCm.EXPECT().FindWatcher("ch-1").Return(0, errChannelNotWatched).Once()
+
+		handler := newCompactionPlanHandler(s.mockSessMgr, s.mockCm, s.mockMeta, s.mockAlloc)
+		handler.plans[plan.PlanID] = &compactionTask{dataNodeID: 111, plan: plan, stolen: true}
+		err := handler.handleMergeCompactionResult(plan, &datapb.CompactionPlanResult{
+			PlanID:   plan.PlanID,
+			Segments: []*datapb.CompactionSegment{{SegmentID: 3, NumOfRows: 15}},
+		})
+		s.ErrorIs(err, errChannelNotWatched)
+	})
+
+	s.Run("remove tasks by channel", func() {
+		s.SetupTest()
+		s.mockSessMgr.EXPECT().DropCompactionPlan(mock.Anything, int64(111), mock.Anything).Return(errors.New("mock")).Once()
+
+		handler := newCompactionPlanHandler(s.mockSessMgr, s.mockCm, s.mockMeta, s.mockAlloc)
+		handler.plans[1] = &compactionTask{triggerInfo: &compactionSignal{channel: "ch-1"}, dataNodeID: 111, plan: plan, stolen: true}
+		handler.plans[2] = &compactionTask{triggerInfo: &compactionSignal{channel: "ch-1"}, dataNodeID: 100, plan: &datapb.CompactionPlan{PlanID: 2}}
+		handler.removeTasksByChannel("ch-1")
+		s.Empty(handler.plans)
+	})
+}
+
 func getFieldBinlogIDs(id int64, logIDs ...int64) *datapb.FieldBinlog {
 	l := &datapb.FieldBinlog{
 		FieldID: id,
@@ -201,8 +201,8 @@ func (t *compactionTrigger) isCollectionAutoCompactionEnabled(coll *collectionIn
 	return enabled
 }
 
-func (t *compactionTrigger) getCompactTime(ts Timestamp, coll *collectionInfo) (*compactTime, error) {
-	collectionTTL, err := getCollectionTTL(coll.Properties)
+func (t *compactionTrigger) getCompactTime(ts Timestamp, coll *collectionInfo, partitionID UniqueID) (*compactTime, error) {
+	collectionTTL, err := getPartitionTTL(coll.Properties, partitionID)
 	if err != nil {
 		return nil, err
 	}
@@ -428,7 +428,7 @@ func (t *compactionTrigger) handleGlobalSignal(signal *compactionSignal) error {
 			return nil
 		}
 
-		ct, err := t.getCompactTime(ts, coll)
+		ct, err := t.getCompactTime(ts, coll, group.partitionID)
 		if err != nil {
 			log.Warn("get compact time failed, skip to handle compaction",
 				zap.Int64("collectionID", group.collectionID),
@@ -437,6 +437,7 @@ func (t *compactionTrigger) handleGlobalSignal(signal *compactionSignal) error {
 			return err
 		}
 
+		group.segments = t.dropExpiredTimeBuckets(group.segments, ct)
 		plans := t.generatePlans(group.segments, signal.isForce, isDiskIndex, ct)
 		for _, plan := range plans {
 			segIDs := fetchSegIDs(plan.GetSegmentBinlogs())
@@ -483,6 +484,37 @@ func (t *compactionTrigger) handleGlobalSignal(signal *compactionSignal) error {
 	return nil
 }
 
+// dropExpiredTimeBuckets drops the segments whose time buckets are expired as a whole by the collection ttl,
+// instead of compacting the expired rows out of them, and returns the other segments.
+func (t *compactionTrigger) dropExpiredTimeBuckets(segments []*SegmentInfo, ct *compactTime) []*SegmentInfo {
+	if ct.collectionTTL <= 0 {
+		return segments
+	}
+	expireTime := tsoutil.PhysicalTime(ct.expireTime).UnixMilli()
+	var remaining []*SegmentInfo
+	var expired []int64
+	for _, segment := range segments {
+		if bucket := segment.GetTimeBucket(); bucket != nil && bucket.GetEnd() <= expireTime {
+			expired = append(expired, segment.GetID())
+			continue
+		}
+		remaining = append(remaining, segment)
+	}
+	if len(expired) == 0 {
+		return segments
+	}
+
+	operators := lo.Map(expired, func(segmentID int64, _ int) UpdateOperator {
+		return UpdateStatusOperator(segmentID, commonpb.SegmentState_Dropped)
+	})
+	if err := t.meta.UpdateSegmentsInfo(operators...); err != nil {
+		log.Warn("failed to drop segments of expired time buckets", zap.Int64s("segmentIDs", expired), zap.Error(err))
+		return remaining
+	}
+	log.Info("segments of expired time buckets dropped", zap.Int64s("segmentIDs", expired))
+	return remaining
+}
+
 // handleSignal processes segment flush caused partition-chan level compaction signal
 func (t *compactionTrigger) handleSignal(signal *compactionSignal) {
 	t.forceMu.Lock()
@@ -503,7 +535,7 @@ func (t *compactionTrigger) handleSignal(signal *compactionSignal) {
 	channel := segment.GetInsertChannel()
 	partitionID := segment.GetPartitionID()
 	collectionID := segment.GetCollectionID()
-	segments := t.getCandidateSegments(channel, partitionID)
+	segments := t.getCandidateSegments(channel, partitionID, segment.GetTimeBucket(), segment.GetPkBucket())
 
 	if len(segments) == 0 {
 		log.Info("the length of segments is 0, skip to handle compaction")
@@ -541,7 +573,7 @@ func (t *compactionTrigger) handleSignal(signal *compactionSignal) {
 		return
 	}
 
-	ct, err := t.getCompactTime(ts, coll)
+	ct, err := t.getCompactTime(ts, coll, partitionID)
 	if err != nil {
 		log.Warn("get compact time failed, skip to handle compaction", zap.Int64("collectionID", segment.GetCollectionID()),
 			zap.Int64("partitionID", partitionID), zap.String("channel", channel))
@@ -624,6 +656,22 @@ func (t *compactionTrigger) generatePlans(segments []*SegmentInfo, force bool, i
 	getSegmentIDs := func(segment *SegmentInfo, _ int) int64 {
 		return segment.GetID()
 	}
+	// the segments of too many deleted rows are rewritten alone to reclaim the space,
+	// without waiting for the others to merge with, the small ones are still merged
+	var mergeCandidates []*SegmentInfo
+	for _, segment := range prioritizedCandidates {
+		if !isDeleteHeavySegment(segment) || t.isSmallSegment(segment) {
+			mergeCandidates = append(mergeCandidates, segment)
+			continue
+		}
+		plan := segmentsToPlan([]*SegmentInfo{segment}, compactTime)
+		log.Info("generate a single compaction plan for delete heavy candidate", zap.Int64("planID", plan.GetPlanID()),
+			zap.Int64("segmentID", segment.GetID()), zap.Int64("numRows", segment.GetNumOfRows()),
+			zap.Float64("deletedRowRatio", segment.getDeleteStats().GetDeletedRowRatio()))
+		plans = append(plans, plan)
+	}
+	prioritizedCandidates = mergeCandidates
+
 	// greedy pick from large segment to small, the goal is to fill each segment to reach 512M
 	// we must ensure all prioritized candidates is in a plan
 	// TODO the compaction selection policy should consider if compaction workload is high
@@ -720,6 +768,7 @@ func (t *compactionTrigger) generatePlans(segments []*SegmentInfo, force bool, i
 					Level:               s.GetLevel(),
 					CollectionID:        s.GetCollectionID(),
 					PartitionID:         s.GetPartitionID(),
+					IsSorted:            s.GetIsSorted(),
 				}
 				plan.TotalRows += s.GetNumOfRows()
 				plan.SegmentBinlogs = append(plan.SegmentBinlogs, segmentBinLogs)
@@ -774,6 +823,7 @@ func segmentsToPlan(segments []*SegmentInfo, compactTime *compactTime) *datapb.C
 			Deltalogs:           s.GetDeltalogs(),
 			CollectionID:        s.GetCollectionID(),
 			PartitionID:         s.GetPartitionID(),
+			IsSorted:            s.GetIsSorted(),
 		}
 		plan.TotalRows += s.GetNumOfRows()
 		plan.SegmentBinlogs = append(plan.SegmentBinlogs, segmentBinlogs)
@@ -813,7 +863,8 @@ func reverseGreedySelect(candidates []*SegmentInfo, free int64, maxSegment int)
 	return candidates, result, free
 }
 
-func (t *compactionTrigger) getCandidateSegments(channel string, partitionID UniqueID) []*SegmentInfo {
+// getCandidateSegments returns the segments could be compacted with the segments of the channel, partition and buckets.
+func (t *compactionTrigger) getCandidateSegments(channel string, partitionID UniqueID, timeBucket *datapb.TimeBucket, pkBucket *datapb.PKBucket) []*SegmentInfo {
 	segments := t.meta.GetSegmentsByChannel(channel)
 	if Params.DataCoordCfg.IndexBasedCompaction.GetAsBool() {
 		segments = FilterInIndexedSegments(t.handler, t.meta, segments...)
@@ -825,6 +876,7 @@ func (t *compactionTrigger) getCandidateSegments(channel string, partitionID Uni
 			!isFlush(s) ||
 			s.GetInsertChannel() != channel ||
 			s.GetPartitionID() != partitionID ||
+			!sameTimeBucket(s.GetTimeBucket(), timeBucket) || !samePKBucket(s.GetPkBucket(), pkBucket) ||
 			s.isCompacting ||
 			s.GetIsImporting() ||
 			s.GetLevel() == datapb.SegmentLevel_L0 {
@@ -836,6 +888,18 @@ func (t *compactionTrigger) getCandidateSegments(channel string, partitionID Uni
 	return res
 }
 
+// isDeleteHeavySegment returns whether the deleted rows of the segment reach the ratio to rewrite it alone.
+func isDeleteHeavySegment(segment *SegmentInfo) bool {
+	stats := segment.getDeleteStats()
+	if stats.GetDeletedRows() == 0 {
+		return false
+	}
+	if stats.GetNumRows() == 0 {
+		return true
+	}
+	return stats.GetDeletedRowRatio() >= Params.DataCoordCfg.SingleCompactionDeleteRatio.GetAsFloat()
+}
+
 func (t *compactionTrigger) isSmallSegment(segment *SegmentInfo) bool {
 	return segment.GetNumOfRows() < int64(float64(segment.GetMaxRowNum())*Params.DataCoordCfg.SegmentSmallProportion.GetAsFloat())
 }
@@ -917,22 +981,15 @@ func (t *compactionTrigger) ShouldDoSingleCompaction(segment *SegmentInfo, isDis
 		return true
 	}
 
-	totalDeletedRows := 0
-	totalDeleteLogSize := int64(0)
-	for _, deltaLogs := range segment.GetDeltalogs() {
-		for _, l := range deltaLogs.GetBinlogs() {
-			totalDeletedRows += int(l.GetEntriesNum())
-			totalDeleteLogSize += l.GetLogSize()
-		}
-	}
-
 	// currently delta log size and delete ratio policy is applied
-	if float64(totalDeletedRows)/float64(segment.GetNumOfRows()) >= Params.DataCoordCfg.SingleCompactionRatioThreshold.GetAsFloat() || totalDeleteLogSize > Params.DataCoordCfg.SingleCompactionDeltaLogMaxSize.GetAsInt64() {
+	deleteStats := segment.getDeleteStats()
+	if isDeleteHeavySegment(segment) || deleteStats.GetDeltalogSize() > Params.DataCoordCfg.SingleCompactionDeltaLogMaxSize.GetAsInt64() {
 		log.Info("total delete entities is too much, trigger compaction",
 			zap.Int64("segmentID", segment.ID),
 			zap.Int64("numRows", segment.GetNumOfRows()),
-			zap.Int("deleted rows", totalDeletedRows),
-			zap.Int64("delete log size", totalDeleteLogSize))
+			zap.Int64("deleted rows", deleteStats.GetDeletedRows()),
+			zap.Float64("deleted row ratio", deleteStats.GetDeletedRowRatio()),
+			zap.Int64("delete log size", deleteStats.GetDeltalogSize()))
 		return true
 	}
 
@@ -46,6 +46,10 @@ var _ compactionPlanContext = (*spyCompactionHandler)(nil)
 
 func (h *spyCompactionHandler) removeTasksByChannel(channel string) {}
 
+func (h *spyCompactionHandler) listTasks() ([]*compactionTask, []*compactionTask, []int64) {
+	return nil, nil, nil
+}
+
 // execCompactionPlan start to execute plan and return immediately
 func (h *spyCompactionHandler) execCompactionPlan(signal *compactionSignal, plan *datapb.CompactionPlan) error {
 	h.spyChan <- plan
@@ -1909,6 +1913,67 @@ func Test_compactionTrigger_shouldDoSingleCompaction(t *testing.T) {
 	assert.False(t, couldDo)
 }
 
+func Test_compactionTrigger_deleteRatio(t *testing.T) {
+	Params.Save(Params.DataCoordCfg.SingleCompactionDeleteRatio.Key, "0.3")
+	defer Params.Reset(Params.DataCoordCfg.SingleCompactionDeleteRatio.Key)
+	trigger := newCompactionTrigger(&meta{}, &compactionPlanHandler{}, newMockAllocator(), newMockHandler(), newIndexEngineVersionManager())
+
+	newSegment := func(id int64, rows int64, deleted int64) *SegmentInfo {
+		segment := &SegmentInfo{
+			SegmentInfo: &datapb.SegmentInfo{
+				ID:             id,
+				CollectionID:   2,
+				PartitionID:    1,
+				LastExpireTime: 100,
+				NumOfRows:      rows,
+				MaxRowNum:      1000,
+				InsertChannel:  "ch1",
+				State:          commonpb.SegmentState_Flushed,
+				Binlogs: []*datapb.FieldBinlog{
+					{FieldID: 1, Binlogs: []*datapb.Binlog{{EntriesNum: rows, LogPath: "log1", LogSize: 100}}},
+				},
+			},
+		}
+		if deleted > 0 {
+			segment.Deltalogs = []*datapb.FieldBinlog{
+				{FieldID: 1, Binlogs: []*datapb.Binlog{{EntriesNum: deleted, LogPath: "deltalog1", LogSize: 10}}},
+			}
+		}
+		return segment
+	}
+
+	t.Run("should do single compaction", func(t *testing.T) {
+		assert.False(t, trigger.ShouldDoSingleCompaction(newSegment(1, 100, 0), false, &compactTime{}))
+		assert.False(t, trigger.ShouldDoSingleCompaction(newSegment(1, 100, 29), false, &compactTime{}))
+		assert.True(t, trigger.ShouldDoSingleCompaction(newSegment(1, 100, 30), false, &compactTime{}))
+		assert.True(t, trigger.ShouldDoSingleCompaction(newSegment(1, 0, 1), false, &compactTime{}))
+	})
+
+	t.Run("delete heavy segment compacted alone", func(t *testing.T) {
+		segments := []*SegmentInfo{
+			newSegment(1, 600, 300),
+			newSegment(2, 100, 40),
+			newSegment(3, 100, 0),
+			newSegment(4, 700, 300),
+		}
+		for _, force := range []bool{false, true} {
+			plans := trigger.generatePlans(segments, force, false, &compactTime{})
+			singles := make(map[int64]int)
+			for _, plan := range plans {
+				segmentIDs := fetchSegIDs(plan.GetSegmentBinlogs())
+				for _, segmentID := range segmentIDs {
+					if segmentID == 1 || segmentID == 4 {
+						assert.Equal(t, []int64{segmentID}, segmentIDs)
+						singles[segmentID]++
+					}
+				}
+			}
+			assert.Equal(t, map[int64]int{1: 1, 4: 1}, singles)
+			assert.Equal(t, 3, len(plans))
+		}
+	})
+}
+
 func Test_compactionTrigger_new(t *testing.T) {
 	type args struct {
 		meta              *meta
@@ -1948,6 +2013,33 @@ func Test_compactionTrigger_handleSignal(t *testing.T) {
 	})
 }
 
+func Test_compactionTrigger_dropExpiredTimeBuckets(t *testing.T) {
+	meta, err := newMemoryMeta()
+	assert.NoError(t, err)
+	now := time.Now()
+	hour := time.Hour.Milliseconds()
+	for _, segment := range []*datapb.SegmentInfo{
+		{ID: 1, CollectionID: 1, State: commonpb.SegmentState_Flushed, TimeBucket: &datapb.TimeBucket{Start: now.UnixMilli() - 3*hour, End: now.UnixMilli() - 2*hour}},
+		{ID: 2, CollectionID: 1, State: commonpb.SegmentState_Flushed, TimeBucket: &datapb.TimeBucket{Start: now.UnixMilli() - hour, End: now.UnixMilli()}},
+		{ID: 3, CollectionID: 1, State: commonpb.SegmentState_Flushed},
+	} {
+		assert.NoError(t, meta.AddSegment(context.TODO(), NewSegmentInfo(segment)))
+	}
+	trigger := newCompactionTrigger(meta, &compactionPlanHandler{scheduler: NewCompactionScheduler()}, newMockAllocator(), newMockHandler(), newMockVersionManager())
+	segments := meta.GetSegmentsOfCollection(1)
+
+	// no ttl
+	remaining := trigger.dropExpiredTimeBuckets(segments, &compactTime{})
+	assert.Len(t, remaining, 3)
+
+	ttl := 90 * time.Minute
+	expireTime := tsoutil.ComposeTSByTime(now.Add(-ttl), 0)
+	remaining = trigger.dropExpiredTimeBuckets(segments, &compactTime{expireTime: expireTime, collectionTTL: ttl})
+	assert.ElementsMatch(t, []int64{2, 3}, lo.Map(remaining, func(segment *SegmentInfo, _ int) int64 { return segment.GetID() }))
+	assert.Equal(t, commonpb.SegmentState_Dropped, meta.GetSegment(1).GetState())
+	assert.Equal(t, commonpb.SegmentState_Flushed, meta.GetSegment(2).GetState())
+}
+
 func Test_compactionTrigger_allocTs(t *testing.T) {
 	got := newCompactionTrigger(&meta{segments: NewSegmentsInfo()}, &compactionPlanHandler{scheduler: NewCompactionScheduler()}, newMockAllocator(), newMockHandler(), newMockVersionManager())
 	ts, err := got.allocTs()
@@ -1996,7 +2088,7 @@ func Test_compactionTrigger_getCompactTime(t *testing.T) {
 		},
 	}
 	now := tsoutil.GetCurrentTime()
-	ct, err := got.getCompactTime(now, coll)
+	ct, err := got.getCompactTime(now, coll, 1)
 	assert.NoError(t, err)
 	assert.NotNil(t, ct)
 }
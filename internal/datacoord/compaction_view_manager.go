@@ -141,7 +141,8 @@ func (m *CompactionViewManager) getChangedLevelZeroViews(collID UniqueID, LevelZ
 			return v.label.Equal(latestView.GetGroupLabel())
 		})
 
-		if !latestView.Equal(views) {
+		// the idle views are notified even if unchanged, to force compacting the deletes left below the trigger conditions
+		if !latestView.Equal(views) || latestView.isIdle() {
 			signals = append(signals, latestView)
 		}
 	}
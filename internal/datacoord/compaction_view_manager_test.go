@@ -146,6 +146,23 @@ func (s *CompactionViewManagerSuite) TestCheck() {
 	s.Empty(s.m.view.collections)
 }
 
+func (s *CompactionViewManagerSuite) TestCheckIdle() {
+	paramtable.Get().Save(Params.DataCoordCfg.EnableLevelZeroSegment.Key, "true")
+	defer paramtable.Get().Reset(Params.DataCoordCfg.EnableLevelZeroSegment.Key)
+
+	s.mockAlloc.EXPECT().allocID(mock.Anything).Return(1, nil)
+	s.mockTriggerManager.EXPECT().Notify(mock.Anything, TriggerTypeLevelZeroView, mock.Anything).Twice()
+
+	// the unchanged views are notified again as the deletes are idle for too long
+	s.m.Check()
+	s.m.Check()
+
+	// the unchanged views are not notified if the idle trigger is disabled
+	paramtable.Get().Save(Params.DataCoordCfg.LevelZeroCompactionTriggerMaxIdleTime.Key, "0")
+	defer paramtable.Get().Reset(Params.DataCoordCfg.LevelZeroCompactionTriggerMaxIdleTime.Key)
+	s.m.Check()
+}
+
 func genTestSegmentInfo(label *CompactionGroupLabel, ID UniqueID, level datapb.SegmentLevel, state commonpb.SegmentState) *SegmentInfo {
 	return &SegmentInfo{
 		SegmentInfo: &datapb.SegmentInfo{
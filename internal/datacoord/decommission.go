@@ -0,0 +1,219 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/metastore/model"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// decommissionManager drains the DataNodes and IndexNodes to decommission in the background:
+//   - the channels of a DataNode are released and reassigned to other DataNodes, the node is
+//     removed from the channel store once the channels are gone and its compaction tasks finish
+//   - an IndexNode gets no new index task, it is drained once its running index tasks finish
+//
+// Decommissions are only kept in memory, they must be requested again if datacoord restarts.
+type decommissionManager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	meta              *meta
+	channelManager    ChannelManager
+	compactionHandler compactionPlanContext
+	indexNodeManager  *IndexNodeManager
+
+	notifyCh chan struct{}
+
+	mu    sync.RWMutex
+	nodes map[UniqueID]*datapb.DecommissionProgress
+}
+
+func newDecommissionManager(ctx context.Context, meta *meta, channelManager ChannelManager,
+	compactionHandler compactionPlanContext, indexNodeManager *IndexNodeManager,
+) *decommissionManager {
+	ctx, cancel := context.WithCancel(ctx)
+	return &decommissionManager{
+		ctx:               ctx,
+		cancel:            cancel,
+		meta:              meta,
+		channelManager:    channelManager,
+		compactionHandler: compactionHandler,
+		indexNodeManager:  indexNodeManager,
+		notifyCh:          make(chan struct{}, 1),
+		nodes:             make(map[UniqueID]*datapb.DecommissionProgress),
+	}
+}
+
+func (m *decommissionManager) start() {
+	m.wg.Add(1)
+	go m.loop()
+}
+
+func (m *decommissionManager) close() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// decommission starts to drain the node of @role, it's a no-op if the node is already decommissioning.
+func (m *decommissionManager) decommission(nodeID UniqueID, role string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if progress, ok := m.nodes[nodeID]; ok {
+		if progress.GetRole() != role {
+			return merr.WrapErrParameterInvalidMsg("node %d is decommissioning as %s", nodeID, progress.GetRole())
+		}
+		return nil
+	}
+
+	if role == typeutil.IndexNodeRole {
+		// stopping index nodes are never picked for new index tasks
+		m.indexNodeManager.StoppingNode(nodeID)
+	}
+	m.nodes[nodeID] = &datapb.DecommissionProgress{
+		NodeID: nodeID,
+		Role:   role,
+		State:  datapb.DecommissionState_Decommissioning,
+	}
+	log.Info("start to decommission node", zap.Int64("nodeID", nodeID), zap.String("role", role))
+
+	select {
+	case m.notifyCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// getProgress returns a copy of the decommission progress of the node, or nil if it's not decommissioned.
+func (m *decommissionManager) getProgress(nodeID UniqueID) *datapb.DecommissionProgress {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	progress, ok := m.nodes[nodeID]
+	if !ok {
+		return nil
+	}
+	return proto.Clone(progress).(*datapb.DecommissionProgress)
+}
+
+func (m *decommissionManager) loop() {
+	defer m.wg.Done()
+	ticker := time.NewTicker(Params.DataCoordCfg.DecommissionCheckInterval.GetAsDuration(time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			log.Info("decommission loop quit")
+			return
+		case <-ticker.C:
+			m.check()
+		case <-m.notifyCh:
+			m.check()
+		}
+	}
+}
+
+func (m *decommissionManager) check() {
+	m.mu.RLock()
+	decommissioning := lo.Filter(lo.Values(m.nodes), func(progress *datapb.DecommissionProgress, _ int) bool {
+		return progress.GetState() == datapb.DecommissionState_Decommissioning
+	})
+	decommissioning = lo.Map(decommissioning, func(progress *datapb.DecommissionProgress, _ int) *datapb.DecommissionProgress {
+		return proto.Clone(progress).(*datapb.DecommissionProgress)
+	})
+	m.mu.RUnlock()
+
+	for _, progress := range decommissioning {
+		switch progress.GetRole() {
+		case typeutil.DataNodeRole:
+			m.drainDataNode(progress)
+		case typeutil.IndexNodeRole:
+			m.drainIndexNode(progress)
+		}
+		m.mu.Lock()
+		m.nodes[progress.GetNodeID()] = progress
+		m.mu.Unlock()
+	}
+}
+
+// drainDataNode releases the channels of the DataNode, the released channels are reassigned
+// to other DataNodes by the channel manager.
+func (m *decommissionManager) drainDataNode(progress *datapb.DecommissionProgress) {
+	nodeID := progress.GetNodeID()
+	log := log.With(zap.Int64("nodeID", nodeID))
+
+	channels := m.channelManager.GetNodeChannels(nodeID)
+	for _, ch := range channels {
+		// the channel is being released, check it again in the next round
+		if ch.GetWatchInfo().GetState() == datapb.ChannelWatchState_ToRelease {
+			continue
+		}
+		if err := m.channelManager.Release(nodeID, ch.GetName()); err != nil {
+			log.Warn("failed to release channel of decommissioning node", zap.String("channel", ch.GetName()), zap.Error(err))
+		}
+	}
+	var tasks []*compactionTask
+	if m.compactionHandler != nil {
+		tasks = lo.Filter(m.compactionHandler.getCompactionTasksBySignalID(0), func(task *compactionTask, _ int) bool {
+			return task.dataNodeID == nodeID && (task.state == executing || task.state == pipelining)
+		})
+	}
+
+	progress.RemainingChannels = int64(len(channels))
+	progress.RemainingTasks = int64(len(tasks))
+	if len(channels) > 0 || len(tasks) > 0 {
+		log.Info("datanode is decommissioning",
+			zap.Int("remainingChannels", len(channels)),
+			zap.Int("remainingTasks", len(tasks)))
+		return
+	}
+
+	// remove the node from the channel store, so no channel will be assigned to it any more
+	if err := m.channelManager.DeleteNode(nodeID); err != nil {
+		log.Warn("failed to remove decommissioned node from channel manager", zap.Error(err))
+		return
+	}
+	progress.State = datapb.DecommissionState_Decommissioned
+	log.Info("datanode is decommissioned, safe to terminate")
+}
+
+// drainIndexNode waits for the running index tasks of the IndexNode.
+func (m *decommissionManager) drainIndexNode(progress *datapb.DecommissionProgress) {
+	nodeID := progress.GetNodeID()
+	tasks := lo.Filter(m.meta.GetMetasByNodeID(nodeID), func(segIdx *model.SegmentIndex, _ int) bool {
+		return segIdx.IndexState == commonpb.IndexState_InProgress
+	})
+
+	progress.RemainingTasks = int64(len(tasks))
+	if len(tasks) > 0 {
+		log.Info("indexnode is decommissioning", zap.Int64("nodeID", nodeID), zap.Int("remainingTasks", len(tasks)))
+		return
+	}
+	progress.State = datapb.DecommissionState_Decommissioned
+	log.Info("indexnode is decommissioned, safe to terminate", zap.Int64("nodeID", nodeID))
+}
@@ -0,0 +1,191 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/metastore/model"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+type DecommissionManagerSuite struct {
+	suite.Suite
+
+	meta             *meta
+	channelManager   *MockChannelManager
+	compaction       *MockCompactionPlanContext
+	indexNodeManager *IndexNodeManager
+	manager          *decommissionManager
+}
+
+func (s *DecommissionManagerSuite) SetupTest() {
+	var err error
+	s.meta, err = newMemoryMeta()
+	s.Require().NoError(err)
+	s.channelManager = NewMockChannelManager(s.T())
+	s.compaction = NewMockCompactionPlanContext(s.T())
+	s.indexNodeManager = NewNodeManager(context.TODO(), nil)
+	s.manager = newDecommissionManager(context.TODO(), s.meta, s.channelManager, s.compaction, s.indexNodeManager)
+}
+
+func (s *DecommissionManagerSuite) TestDecommission() {
+	s.Nil(s.manager.getProgress(1))
+	s.NoError(s.manager.decommission(1, typeutil.DataNodeRole))
+	// decommission again is a no-op
+	s.NoError(s.manager.decommission(1, typeutil.DataNodeRole))
+	s.ErrorIs(s.manager.decommission(1, typeutil.IndexNodeRole), merr.ErrParameterInvalid)
+
+	progress := s.manager.getProgress(1)
+	s.Equal(datapb.DecommissionState_Decommissioning, progress.GetState())
+	s.Equal(typeutil.DataNodeRole, progress.GetRole())
+}
+
+func (s *DecommissionManagerSuite) TestDrainDataNode() {
+	s.Require().NoError(s.manager.decommission(1, typeutil.DataNodeRole))
+
+	watching := &channelMeta{Name: "ch-1", WatchInfo: &datapb.ChannelWatchInfo{State: datapb.ChannelWatchState_ToWatch}}
+	releasing := &channelMeta{Name: "ch-2", WatchInfo: &datapb.ChannelWatchInfo{State: datapb.ChannelWatchState_ToRelease}}
+	s.channelManager.EXPECT().GetNodeChannels(int64(1)).Return([]RWChannel{watching, releasing}).Once()
+	s.channelManager.EXPECT().Release(int64(1), "ch-1").Return(nil).Once()
+	s.compaction.EXPECT().getCompactionTasksBySignalID(int64(0)).Return(nil).Once()
+	s.manager.check()
+	progress := s.manager.getProgress(1)
+	s.Equal(datapb.DecommissionState_Decommissioning, progress.GetState())
+	s.EqualValues(2, progress.GetRemainingChannels())
+
+	// the channels are reassigned, but the compaction task is still running
+	s.channelManager.EXPECT().GetNodeChannels(int64(1)).Return(nil)
+	s.compaction.EXPECT().getCompactionTasksBySignalID(int64(0)).Return([]*compactionTask{
+		{dataNodeID: 1, state: executing},
+		{dataNodeID: 1, state: completed},
+		{dataNodeID: 2, state: executing},
+	}).Once()
+	s.manager.check()
+	progress = s.manager.getProgress(1)
+	s.Equal(datapb.DecommissionState_Decommissioning, progress.GetState())
+	s.EqualValues(0, progress.GetRemainingChannels())
+	s.EqualValues(1, progress.GetRemainingTasks())
+
+	s.compaction.EXPECT().getCompactionTasksBySignalID(int64(0)).Return(nil).Once()
+	s.channelManager.EXPECT().DeleteNode(int64(1)).Return(nil).Once()
+	s.manager.check()
+	progress = s.manager.getProgress(1)
+	s.Equal(datapb.DecommissionState_Decommissioned, progress.GetState())
+	s.EqualValues(0, progress.GetRemainingTasks())
+
+	// decommissioned nodes are not checked any more
+	s.manager.check()
+}
+
+func (s *DecommissionManagerSuite) TestDrainIndexNode() {
+	s.indexNodeManager.setClient(1, mocks.NewMockIndexNodeClient(s.T()))
+	s.indexNodeManager.setClient(2, mocks.NewMockIndexNodeClient(s.T()))
+	s.meta.buildID2SegmentIndex[100] = &model.SegmentIndex{BuildID: 100, NodeID: 1, IndexState: commonpb.IndexState_InProgress}
+	s.meta.buildID2SegmentIndex[101] = &model.SegmentIndex{BuildID: 101, NodeID: 1, IndexState: commonpb.IndexState_Finished}
+
+	s.Require().NoError(s.manager.decommission(1, typeutil.IndexNodeRole))
+	// the decommissioning node gets no new index task
+	s.Len(s.indexNodeManager.GetAllClients(), 1)
+	s.Contains(s.indexNodeManager.GetAllClients(), int64(2))
+
+	s.manager.check()
+	progress := s.manager.getProgress(1)
+	s.Equal(datapb.DecommissionState_Decommissioning, progress.GetState())
+	s.EqualValues(1, progress.GetRemainingTasks())
+
+	s.meta.buildID2SegmentIndex[100].IndexState = commonpb.IndexState_Finished
+	s.manager.check()
+	progress = s.manager.getProgress(1)
+	s.Equal(datapb.DecommissionState_Decommissioned, progress.GetState())
+	s.EqualValues(0, progress.GetRemainingTasks())
+}
+
+func TestDecommissionManager(t *testing.T) {
+	suite.Run(t, new(DecommissionManagerSuite))
+}
+
+type DecommissionServiceSuite struct {
+	suite.Suite
+
+	server *Server
+}
+
+func (s *DecommissionServiceSuite) SetupTest() {
+	s.server = newTestServer(s.T(), nil)
+}
+
+func (s *DecommissionServiceSuite) TearDownTest() {
+	if s.server != nil {
+		closeTestServer(s.T(), s.server)
+	}
+}
+
+func (s *DecommissionServiceSuite) TestClosedServer() {
+	closeTestServer(s.T(), s.server)
+	status, err := s.server.DecommissionNode(context.TODO(), &datapb.DecommissionNodeRequest{})
+	s.NoError(err)
+	s.False(merr.Ok(status))
+
+	resp, err := s.server.GetDecommissionState(context.TODO(), &datapb.GetDecommissionStateRequest{})
+	s.NoError(err)
+	s.False(merr.Ok(resp.GetStatus()))
+	s.server = nil
+}
+
+func (s *DecommissionServiceSuite) TestInvalidParams() {
+	status, err := s.server.DecommissionNode(context.TODO(), &datapb.DecommissionNodeRequest{NodeID: 1, Role: typeutil.QueryNodeRole})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(status), merr.ErrParameterInvalid)
+
+	status, err = s.server.DecommissionNode(context.TODO(), &datapb.DecommissionNodeRequest{NodeID: 999, Role: typeutil.DataNodeRole})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(status), merr.ErrNodeNotFound)
+
+	resp, err := s.server.GetDecommissionState(context.TODO(), &datapb.GetDecommissionStateRequest{NodeID: 999, Role: typeutil.IndexNodeRole})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrNodeNotFound)
+}
+
+func (s *DecommissionServiceSuite) TestDecommissionIndexNode() {
+	s.server.indexNodeManager.setClient(1, mocks.NewMockIndexNodeClient(s.T()))
+
+	resp, err := s.server.GetDecommissionState(context.TODO(), &datapb.GetDecommissionStateRequest{NodeID: 1, Role: typeutil.IndexNodeRole})
+	s.Require().NoError(merr.CheckRPCCall(resp, err))
+	s.Equal(datapb.DecommissionState_DecommissionNone, resp.GetProgress().GetState())
+
+	status, err := s.server.DecommissionNode(context.TODO(), &datapb.DecommissionNodeRequest{NodeID: 1, Role: typeutil.IndexNodeRole})
+	s.Require().NoError(merr.CheckRPCCall(status, err))
+
+	s.Eventually(func() bool {
+		resp, err := s.server.GetDecommissionState(context.TODO(), &datapb.GetDecommissionStateRequest{NodeID: 1, Role: typeutil.IndexNodeRole})
+		s.Require().NoError(merr.CheckRPCCall(resp, err))
+		return resp.GetProgress().GetState() == datapb.DecommissionState_Decommissioned
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestDecommissionService(t *testing.T) {
+	suite.Run(t, new(DecommissionServiceSuite))
+}
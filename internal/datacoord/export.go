@@ -0,0 +1,386 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/metastore/kv/binlog"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/etcdpb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/exportutil/parquet"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/conc"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const exportFileSuffix = ".parquet"
+
+// getExportFilePath returns the object path of the parquet file exported from @segmentID.
+func getExportFilePath(targetPrefix string, segmentID UniqueID) string {
+	return path.Join(targetPrefix, fmt.Sprintf("%d%s", segmentID, exportFileSuffix))
+}
+
+// exportManager runs export jobs in the background. Jobs are only kept in memory,
+// they are lost if datacoord restarts and must be submitted again.
+type exportManager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	meta    *meta
+	handler Handler
+	cm      storage.ChunkManager
+	gc      *garbageCollector
+	pool    *conc.Pool[any]
+
+	mu   sync.RWMutex
+	jobs map[UniqueID]*datapb.ExportJob
+}
+
+func newExportManager(ctx context.Context, meta *meta, handler Handler, cm storage.ChunkManager, gc *garbageCollector, concurrency int) *exportManager {
+	ctx, cancel := context.WithCancel(ctx)
+	return &exportManager{
+		ctx:     ctx,
+		cancel:  cancel,
+		meta:    meta,
+		handler: handler,
+		cm:      cm,
+		gc:      gc,
+		pool:    conc.NewPool[any](concurrency),
+		jobs:    make(map[UniqueID]*datapb.ExportJob),
+	}
+}
+
+// submit records @job as pending and schedules it.
+func (m *exportManager) submit(job *datapb.ExportJob) {
+	job.State = datapb.ExportState_ExportPending
+	m.mu.Lock()
+	m.jobs[job.GetJobID()] = job
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	m.pool.Submit(func() (any, error) {
+		defer m.wg.Done()
+		m.run(job.GetJobID())
+		return nil, nil
+	})
+}
+
+// getJob returns a copy of the job, or nil if it does not exist.
+func (m *exportManager) getJob(jobID UniqueID) *datapb.ExportJob {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return nil
+	}
+	return proto.Clone(job).(*datapb.ExportJob)
+}
+
+func (m *exportManager) updateJob(jobID UniqueID, fn func(job *datapb.ExportJob)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if job, ok := m.jobs[jobID]; ok {
+		fn(job)
+	}
+}
+
+func (m *exportManager) close() {
+	m.cancel()
+	m.wg.Wait()
+	m.pool.Release()
+}
+
+func (m *exportManager) run(jobID UniqueID) {
+	job := m.getJob(jobID)
+	log := log.With(zap.Int64("jobID", jobID),
+		zap.Int64("collectionID", job.GetCollectionID()),
+		zap.Int64("partitionID", job.GetPartitionID()),
+		zap.String("targetPrefix", job.GetTargetPrefix()))
+	m.updateJob(jobID, func(job *datapb.ExportJob) {
+		job.State = datapb.ExportState_ExportRunning
+	})
+	log.Info("export job started")
+
+	start := time.Now()
+	err := m.export(job)
+	if err != nil {
+		log.Warn("export job failed", zap.Error(err))
+		m.updateJob(jobID, func(job *datapb.ExportJob) {
+			job.State = datapb.ExportState_ExportFailed
+			job.Reason = err.Error()
+		})
+		return
+	}
+	log.Info("export job completed", zap.Duration("elapse", time.Since(start)))
+	m.updateJob(jobID, func(job *datapb.ExportJob) {
+		job.State = datapb.ExportState_ExportCompleted
+	})
+}
+
+func (m *exportManager) export(job *datapb.ExportJob) error {
+	coll, err := m.handler.GetCollection(m.ctx, job.GetCollectionID())
+	if err != nil {
+		return err
+	}
+	if coll == nil {
+		return merr.WrapErrCollectionNotFound(job.GetCollectionID())
+	}
+	ttl, err := getPartitionTTL(coll.Properties, job.GetPartitionID())
+	if err != nil {
+		return err
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(coll.Schema)
+	if err != nil {
+		return err
+	}
+
+	segments := m.meta.SelectSegments(func(segment *SegmentInfo) bool {
+		return segment.GetCollectionID() == job.GetCollectionID() &&
+			isSegmentHealthy(segment) &&
+			isFlushState(segment.GetState()) &&
+			!segment.GetIsImporting() &&
+			(segment.GetPartitionID() == job.GetPartitionID() ||
+				// l0 segments may hold the deletes of all partitions
+				(segment.GetLevel() == datapb.SegmentLevel_L0 && segment.GetPartitionID() == common.InvalidPartitionID))
+	})
+	segmentIDs := make([]UniqueID, 0, len(segments))
+	for _, segment := range segments {
+		segmentIDs = append(segmentIDs, segment.GetID())
+	}
+	// keep the binlogs from being recycled while they are read
+	m.gc.FreezeSegments(segmentIDs...)
+	defer m.gc.UnfreezeSegments(segmentIDs...)
+
+	deletes, bitmaps, err := readExportDeletes(m.ctx, m.cm, segments, job.GetExportTs())
+	if err != nil {
+		return err
+	}
+
+	dataSegments := make([]*SegmentInfo, 0, len(segments))
+	for _, segment := range segments {
+		if segment.GetLevel() != datapb.SegmentLevel_L0 {
+			dataSegments = append(dataSegments, segment)
+		}
+	}
+	m.updateJob(job.GetJobID(), func(job *datapb.ExportJob) {
+		job.TotalSegments = int64(len(dataSegments))
+	})
+
+	codec := storage.NewInsertCodecWithSchema(&etcdpb.CollectionMeta{ID: job.GetCollectionID(), Schema: coll.Schema})
+	for _, segment := range dataSegments {
+		if err := m.ctx.Err(); err != nil {
+			return err
+		}
+		filePath := getExportFilePath(job.GetTargetPrefix(), segment.GetID())
+		rows, err := exportSegment(m.ctx, m.cm, codec, coll.Schema, pkField.GetFieldID(), segment, deletes, bitmaps[segment.GetID()], ttl, job.GetExportTs(), filePath)
+		if err != nil {
+			return err
+		}
+		m.updateJob(job.GetJobID(), func(job *datapb.ExportJob) {
+			job.FinishedSegments++
+			if rows > 0 {
+				job.Files = append(job.Files, filePath)
+				job.ExportedRows += rows
+			}
+		})
+	}
+	return nil
+}
+
+// readExportDeletes returns the latest delete timestamp of every primary key deleted
+// in the deltalogs of @segments no later than @exportTs, and the delete bitmaps of the segments
+// whose deletes are recorded by the row offsets.
+func readExportDeletes(ctx context.Context, cm storage.ChunkManager, segments []*SegmentInfo, exportTs Timestamp) (map[any]Timestamp, map[UniqueID]*storage.DeleteBitmap, error) {
+	deletes := make(map[any]Timestamp)
+	bitmaps := make(map[UniqueID]*storage.DeleteBitmap)
+	codec := storage.NewDeleteCodec()
+	for _, segment := range segments {
+		cloned := segment.Clone()
+		if err := binlog.DecompressBinLogs(cloned.SegmentInfo); err != nil {
+			return nil, nil, err
+		}
+		for _, fieldBinlog := range cloned.GetDeltalogs() {
+			for _, l := range fieldBinlog.GetBinlogs() {
+				data, err := cm.Read(ctx, l.GetLogPath())
+				if err != nil {
+					return nil, nil, err
+				}
+				blobs, bitmap, err := codec.DeserializeBitmaps([]*storage.Blob{{Key: l.GetLogPath(), Value: data}})
+				if err != nil {
+					return nil, nil, err
+				}
+				if bitmap != nil {
+					if merged, ok := bitmaps[segment.GetID()]; ok {
+						err = merged.Merge(bitmap)
+					} else {
+						bitmaps[segment.GetID()] = bitmap
+					}
+					if err != nil {
+						return nil, nil, err
+					}
+					continue
+				}
+				_, _, deleteData, err := codec.Deserialize(blobs)
+				if err != nil {
+					return nil, nil, err
+				}
+				for i, pk := range deleteData.Pks {
+					ts := deleteData.Tss[i]
+					if ts > exportTs {
+						continue
+					}
+					if ts > deletes[pk.GetValue()] {
+						deletes[pk.GetValue()] = ts
+					}
+				}
+			}
+		}
+	}
+	return deletes, bitmaps, nil
+}
+
+// exportSegment writes the live rows of @segment to a parquet file at @filePath, rows deleted or
+// expired as of @exportTs are skipped. No file is written if there is no live row.
+func exportSegment(ctx context.Context, cm storage.ChunkManager, codec *storage.InsertCodec, schema *schemapb.CollectionSchema,
+	pkFieldID UniqueID, segment *SegmentInfo, deletes map[any]Timestamp, bitmap *storage.DeleteBitmap, ttl time.Duration, exportTs Timestamp, filePath string,
+) (int64, error) {
+	cloned := segment.Clone()
+	if err := binlog.DecompressBinLogs(cloned.SegmentInfo); err != nil {
+		return 0, err
+	}
+	fieldBinlogs := cloned.GetBinlogs()
+	if len(fieldBinlogs) == 0 {
+		return 0, nil
+	}
+
+	buf := &bytes.Buffer{}
+	writer, err := parquet.NewWriter(buf, schema)
+	if err != nil {
+		return 0, err
+	}
+	var rows, offset int64
+	// the i-th binlogs of all the fields hold the same rows
+	for i := range fieldBinlogs[0].GetBinlogs() {
+		blobs := make([]*storage.Blob, 0, len(fieldBinlogs))
+		for _, fieldBinlog := range fieldBinlogs {
+			if i >= len(fieldBinlog.GetBinlogs()) {
+				return 0, merr.WrapErrSegmentLack(segment.GetID(), fmt.Sprintf("binlog %d of field %d", i, fieldBinlog.GetFieldID()))
+			}
+			logPath := fieldBinlog.GetBinlogs()[i].GetLogPath()
+			data, err := cm.Read(ctx, logPath)
+			if err != nil {
+				return 0, err
+			}
+			blobs = append(blobs, &storage.Blob{Key: logPath, Value: data})
+		}
+		_, _, insertData, err := codec.Deserialize(blobs)
+		if err != nil {
+			return 0, err
+		}
+		filtered, err := filterExportRows(schema, insertData, pkFieldID, deletes, bitmap, offset, ttl, exportTs)
+		if err != nil {
+			return 0, err
+		}
+		offset += int64(insertData.GetRowNum())
+		if filtered.GetRowNum() == 0 {
+			continue
+		}
+		if err := writer.Write(filtered); err != nil {
+			return 0, err
+		}
+		rows += int64(filtered.GetRowNum())
+	}
+	if err := writer.Close(); err != nil {
+		return 0, err
+	}
+	if rows == 0 {
+		return 0, nil
+	}
+	return rows, cm.Write(ctx, filePath, buf.Bytes())
+}
+
+// filterExportRows returns the rows of @data which are inserted no later than @exportTs,
+// not deleted afterwards and not expired by @ttl. The rows of @data start at @baseOffset of the segment.
+func filterExportRows(schema *schemapb.CollectionSchema, data *storage.InsertData, pkFieldID UniqueID,
+	deletes map[any]Timestamp, bitmap *storage.DeleteBitmap, baseOffset int64, ttl time.Duration, exportTs Timestamp,
+) (*storage.InsertData, error) {
+	tsData, ok := data.Data[common.TimeStampField].(*storage.Int64FieldData)
+	if !ok {
+		return nil, merr.WrapErrFieldNotFound(common.TimeStampField, "timestamp field not found in insert data")
+	}
+	pkData, ok := data.Data[pkFieldID]
+	if !ok {
+		return nil, merr.WrapErrFieldNotFound(pkFieldID, "primary key field not found in insert data")
+	}
+	exportTime, _ := tsoutil.ParseTS(exportTs)
+
+	offsets := make([]int, 0, data.GetRowNum())
+	for i, v := range tsData.Data {
+		ts := Timestamp(v)
+		if ts > exportTs {
+			continue
+		}
+		if deleteTs, ok := deletes[pkData.GetRow(i)]; ok && deleteTs > ts {
+			continue
+		}
+		if bitmap != nil {
+			if deleteTs, ok := bitmap.DeletedAt(baseOffset + int64(i)); ok && deleteTs <= exportTs {
+				continue
+			}
+		}
+		// entity expiration is not enabled if ttl <= 0
+		if ttl > 0 {
+			insertTime, _ := tsoutil.ParseTS(ts)
+			if insertTime.Add(ttl).Before(exportTime) {
+				continue
+			}
+		}
+		offsets = append(offsets, i)
+	}
+
+	result, err := storage.NewInsertData(schema)
+	if err != nil {
+		return nil, err
+	}
+	for fieldID, fieldData := range result.Data {
+		src, ok := data.Data[fieldID]
+		if !ok {
+			return nil, merr.WrapErrFieldNotFound(fieldID, "field not found in insert data")
+		}
+		for _, offset := range offsets {
+			if err := fieldData.AppendRow(src.GetRow(offset)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
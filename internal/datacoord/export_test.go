@@ -0,0 +1,280 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/etcdpb"
+	"github.com/milvus-io/milvus/internal/storage"
+	importparquet "github.com/milvus-io/milvus/internal/util/importutilv2/parquet"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+type ExportServiceSuite struct {
+	suite.Suite
+
+	server *Server
+	schema *schemapb.CollectionSchema
+}
+
+func (s *ExportServiceSuite) SetupTest() {
+	s.server = newTestServer(s.T(), nil)
+	s.schema = &schemapb.CollectionSchema{
+		Name: "export",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: common.RowIDField, Name: common.RowIDFieldName, DataType: schemapb.DataType_Int64},
+			{FieldID: common.TimeStampField, Name: common.TimeStampFieldName, DataType: schemapb.DataType_Int64},
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}}},
+		},
+	}
+	s.server.meta.AddCollection(&collectionInfo{
+		ID:         100,
+		Schema:     s.schema,
+		Partitions: []int64{10},
+		Properties: map[string]string{common.CollectionTTLConfigKey: "3600"},
+	})
+}
+
+func (s *ExportServiceSuite) TearDownTest() {
+	if s.server != nil {
+		s.server.meta.chunkManager.RemoveWithPrefix(context.TODO(), s.server.meta.chunkManager.RootPath())
+		closeTestServer(s.T(), s.server)
+	}
+}
+
+// addSegment writes the binlogs of the rows and deletes and adds a flushed segment of collection 100.
+func (s *ExportServiceSuite) addSegment(segmentID, partitionID int64, level datapb.SegmentLevel, pks []int64, tss []time.Time, deletes map[int64]time.Time) {
+	ctx := context.TODO()
+	cm := s.server.meta.chunkManager
+	segment := &datapb.SegmentInfo{
+		ID:            segmentID,
+		CollectionID:  100,
+		PartitionID:   partitionID,
+		InsertChannel: "ch_100v0",
+		NumOfRows:     int64(len(pks)),
+		State:         commonpb.SegmentState_Flushed,
+		Level:         level,
+	}
+
+	if len(pks) > 0 {
+		data := &storage.InsertData{Data: map[storage.FieldID]storage.FieldData{
+			common.RowIDField:     &storage.Int64FieldData{},
+			common.TimeStampField: &storage.Int64FieldData{},
+			100:                   &storage.Int64FieldData{},
+			101:                   &storage.FloatVectorFieldData{Dim: 2},
+		}}
+		for i, pk := range pks {
+			s.Require().NoError(data.Append(map[storage.FieldID]any{
+				common.RowIDField:     pk,
+				common.TimeStampField: int64(tsoutil.ComposeTSByTime(tss[i], 0)),
+				100:                   pk,
+				101:                   []float32{float32(pk), float32(pk)},
+			}))
+		}
+		codec := storage.NewInsertCodecWithSchema(&etcdpb.CollectionMeta{ID: 100, Schema: s.schema})
+		blobs, err := codec.Serialize(partitionID, segmentID, data)
+		s.Require().NoError(err)
+		for i, blob := range blobs {
+			fieldID := s.schema.GetFields()[i].GetFieldID()
+			logPath := metautil.BuildInsertLogPath(cm.RootPath(), 100, partitionID, segmentID, fieldID, int64(i+1))
+			s.Require().NoError(cm.Write(ctx, logPath, blob.GetValue()))
+			segment.Binlogs = append(segment.Binlogs, &datapb.FieldBinlog{
+				FieldID: fieldID,
+				Binlogs: []*datapb.Binlog{{EntriesNum: int64(len(pks)), LogID: int64(i + 1), LogPath: logPath}},
+			})
+		}
+	}
+
+	if len(deletes) > 0 {
+		deleteData := storage.NewDeleteData(nil, nil)
+		for pk, ts := range deletes {
+			deleteData.Append(storage.NewInt64PrimaryKey(pk), tsoutil.ComposeTSByTime(ts, 0))
+		}
+		blob, err := storage.NewDeleteCodec().Serialize(100, partitionID, segmentID, deleteData)
+		s.Require().NoError(err)
+		logPath := metautil.BuildDeltaLogPath(cm.RootPath(), 100, partitionID, segmentID, 99)
+		s.Require().NoError(cm.Write(ctx, logPath, blob.GetValue()))
+		segment.Deltalogs = []*datapb.FieldBinlog{
+			{Binlogs: []*datapb.Binlog{{EntriesNum: int64(len(deletes)), LogID: 99, LogPath: logPath}}},
+		}
+	}
+	s.Require().NoError(s.server.meta.AddSegment(ctx, NewSegmentInfo(segment)))
+}
+
+func (s *ExportServiceSuite) waitForJob(jobID int64) *datapb.ExportJob {
+	var job *datapb.ExportJob
+	s.Eventually(func() bool {
+		resp, err := s.server.GetExportState(context.TODO(), &datapb.GetExportStateRequest{JobID: jobID})
+		s.Require().NoError(merr.CheckRPCCall(resp, err))
+		job = resp.GetJob()
+		return job.GetState() == datapb.ExportState_ExportCompleted || job.GetState() == datapb.ExportState_ExportFailed
+	}, 10*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func (s *ExportServiceSuite) TestClosedServer() {
+	closeTestServer(s.T(), s.server)
+	resp, err := s.server.Export(context.TODO(), &datapb.ExportRequest{})
+	s.NoError(err)
+	s.False(merr.Ok(resp.GetStatus()))
+
+	stateResp, err := s.server.GetExportState(context.TODO(), &datapb.GetExportStateRequest{})
+	s.NoError(err)
+	s.False(merr.Ok(stateResp.GetStatus()))
+	s.server = nil
+}
+
+func (s *ExportServiceSuite) TestInvalidParams() {
+	cases := []struct {
+		tag string
+		req *datapb.ExportRequest
+	}{
+		{"empty_prefix", &datapb.ExportRequest{CollectionID: 100, PartitionID: 10}},
+		{"collection_not_found", &datapb.ExportRequest{CollectionID: 999, PartitionID: 10, TargetPrefix: "export"}},
+		{"partition_not_found", &datapb.ExportRequest{CollectionID: 100, PartitionID: 999, TargetPrefix: "export"}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.tag, func() {
+			resp, err := s.server.Export(context.TODO(), tc.req)
+			s.NoError(err)
+			s.False(merr.Ok(resp.GetStatus()))
+		})
+	}
+
+	resp, err := s.server.GetExportState(context.TODO(), &datapb.GetExportStateRequest{JobID: 999})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrParameterInvalid)
+}
+
+func (s *ExportServiceSuite) TestExport() {
+	now := time.Now()
+	// pk 2 is deleted by the segment itself, pk 3 by a l0 segment of all partitions,
+	// pk 4 is expired and the delete of pk 5 happens before it is inserted
+	s.addSegment(1, 10, datapb.SegmentLevel_L1,
+		[]int64{1, 2, 3, 4, 5},
+		[]time.Time{now.Add(-time.Minute), now.Add(-time.Minute), now.Add(-time.Minute), now.Add(-2 * time.Hour), now.Add(-time.Minute)},
+		map[int64]time.Time{2: now.Add(-time.Second), 5: now.Add(-2 * time.Minute)})
+	// all the rows of segment 2 are deleted, no file is written
+	s.addSegment(2, 10, datapb.SegmentLevel_L1, []int64{6}, []time.Time{now.Add(-time.Minute)}, nil)
+	s.addSegment(3, common.InvalidPartitionID, datapb.SegmentLevel_L0, nil, nil,
+		map[int64]time.Time{3: now.Add(-time.Second), 6: now.Add(-time.Second)})
+	// segments of other partitions are not exported
+	s.addSegment(4, 11, datapb.SegmentLevel_L1, []int64{7}, []time.Time{now.Add(-time.Minute)}, nil)
+
+	cm := s.server.meta.chunkManager
+	targetPrefix := path.Join(cm.RootPath(), "export", "p10")
+	resp, err := s.server.Export(context.TODO(), &datapb.ExportRequest{CollectionID: 100, PartitionID: 10, TargetPrefix: targetPrefix})
+	s.Require().NoError(merr.CheckRPCCall(resp, err))
+
+	job := s.waitForJob(resp.GetJobID())
+	s.Equal(datapb.ExportState_ExportCompleted, job.GetState(), job.GetReason())
+	s.Equal([]string{path.Join(targetPrefix, "1.parquet")}, job.GetFiles())
+	s.EqualValues(2, job.GetExportedRows())
+	s.EqualValues(2, job.GetTotalSegments())
+	s.EqualValues(2, job.GetFinishedSegments())
+	for _, segmentID := range []int64{1, 2, 3} {
+		s.False(s.server.garbageCollector.isFrozen(segmentID))
+	}
+
+	ctx := context.TODO()
+	cmReader, err := cm.Reader(ctx, path.Join(targetPrefix, "1.parquet"))
+	s.Require().NoError(err)
+	reader, err := importparquet.NewReader(ctx, &schemapb.CollectionSchema{Fields: s.schema.GetFields()[2:]}, cmReader, 64*1024*1024)
+	s.Require().NoError(err)
+	defer reader.Close()
+	data, err := reader.Read()
+	s.Require().NoError(err)
+	s.Equal([]int64{1, 5}, data.Data[100].GetRows())
+	s.Equal([]float32{1, 1, 5, 5}, data.Data[101].GetRows())
+}
+
+func (s *ExportServiceSuite) TestExportDeleteBitmap() {
+	now := time.Now()
+	s.addSegment(1, 10, datapb.SegmentLevel_L1, []int64{1, 2, 3},
+		[]time.Time{now.Add(-time.Minute), now.Add(-time.Minute), now.Add(-time.Minute)}, nil)
+
+	// the row at offset 1 is deleted, the delete of offset 2 happens after the export
+	bitmap := storage.NewDeleteBitmap(3)
+	bitmap.Add(1, tsoutil.ComposeTSByTime(now.Add(-time.Second), 0))
+	bitmap.Add(2, tsoutil.ComposeTSByTime(now.Add(time.Hour), 0))
+	blob, err := storage.NewDeleteCodec().SerializeBitmap(100, 10, 1, bitmap)
+	s.Require().NoError(err)
+	cm := s.server.meta.chunkManager
+	logPath := metautil.BuildDeltaLogPath(cm.RootPath(), 100, 10, 1, 99)
+	s.Require().NoError(cm.Write(context.TODO(), logPath, blob.GetValue()))
+	segment := s.server.meta.GetHealthySegment(1).Clone()
+	segment.Deltalogs = []*datapb.FieldBinlog{{Binlogs: []*datapb.Binlog{{EntriesNum: 2, LogID: 99, LogPath: logPath}}}}
+	s.server.meta.segments.SetSegment(1, segment)
+
+	targetPrefix := path.Join(cm.RootPath(), "export", "bitmap")
+	resp, err := s.server.Export(context.TODO(), &datapb.ExportRequest{CollectionID: 100, PartitionID: 10, TargetPrefix: targetPrefix})
+	s.Require().NoError(merr.CheckRPCCall(resp, err))
+	job := s.waitForJob(resp.GetJobID())
+	s.Equal(datapb.ExportState_ExportCompleted, job.GetState(), job.GetReason())
+	s.EqualValues(2, job.GetExportedRows())
+
+	ctx := context.TODO()
+	cmReader, err := cm.Reader(ctx, path.Join(targetPrefix, "1.parquet"))
+	s.Require().NoError(err)
+	reader, err := importparquet.NewReader(ctx, &schemapb.CollectionSchema{Fields: s.schema.GetFields()[2:]}, cmReader, 64*1024*1024)
+	s.Require().NoError(err)
+	defer reader.Close()
+	data, err := reader.Read()
+	s.Require().NoError(err)
+	s.Equal([]int64{1, 3}, data.Data[100].GetRows())
+}
+
+func (s *ExportServiceSuite) TestExportFailed() {
+	// the binlog referenced by the segment is missing
+	s.Require().NoError(s.server.meta.AddSegment(context.TODO(), NewSegmentInfo(&datapb.SegmentInfo{
+		ID:            1,
+		CollectionID:  100,
+		PartitionID:   10,
+		InsertChannel: "ch_100v0",
+		NumOfRows:     1,
+		State:         commonpb.SegmentState_Flushed,
+		Binlogs: []*datapb.FieldBinlog{
+			{FieldID: 100, Binlogs: []*datapb.Binlog{{EntriesNum: 1, LogID: 1, LogPath: "not_exist"}}},
+		},
+	})))
+
+	targetPrefix := path.Join(s.server.meta.chunkManager.RootPath(), "export", "p10")
+	resp, err := s.server.Export(context.TODO(), &datapb.ExportRequest{CollectionID: 100, PartitionID: 10, TargetPrefix: targetPrefix})
+	s.Require().NoError(merr.CheckRPCCall(resp, err))
+
+	job := s.waitForJob(resp.GetJobID())
+	s.Equal(datapb.ExportState_ExportFailed, job.GetState())
+	s.NotEmpty(job.GetReason())
+	s.False(s.server.garbageCollector.isFrozen(1))
+}
+
+func TestExportService(t *testing.T) {
+	suite.Run(t, new(ExportServiceSuite))
+}
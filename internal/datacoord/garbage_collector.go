@@ -61,6 +61,8 @@ type garbageCollector struct {
 	option  GcOption
 	meta    *meta
 	handler Handler
+	// lifecycle expires the binlogs of the dropped collections, nil if the binlogs are removed one by one
+	lifecycle *lifecycleRules
 
 	startOnce  sync.Once
 	stopOnce   sync.Once
@@ -68,6 +70,13 @@ type garbageCollector struct {
 	closeCh    chan struct{}
 	cmdCh      chan gcCmd
 	pauseUntil atomic.Time
+
+	frozenMut      sync.Mutex
+	frozenSegments map[UniqueID]int // segment id -> freeze count
+
+	reconcileMut  sync.Mutex
+	report        atomic.Pointer[datapb.GcReport] // the last reconciliation report
+	lastReconcile time.Time
 }
 type gcCmd struct {
 	cmdType  datapb.GcCommand
@@ -80,12 +89,20 @@ func newGarbageCollector(meta *meta, handler Handler, opt GcOption) *garbageColl
 	log.Info("GC with option", zap.Bool("enabled", opt.enabled), zap.Duration("interval", opt.checkInterval),
 		zap.Duration("missingTolerance", opt.missingTolerance), zap.Duration("dropTolerance", opt.dropTolerance))
 	opt.removeLogPool = conc.NewPool[struct{}](Params.DataCoordCfg.GCRemoveConcurrent.GetAsInt(), conc.WithExpiryDuration(time.Minute))
+	var lifecycle *lifecycleRules
+	if opt.enabled {
+		lifecycle = newLifecycleRules(opt.cli)
+	}
 	return &garbageCollector{
-		meta:    meta,
-		handler: handler,
-		option:  opt,
-		closeCh: make(chan struct{}),
-		cmdCh:   make(chan gcCmd),
+		meta:      meta,
+		handler:   handler,
+		option:    opt,
+		lifecycle: lifecycle,
+		closeCh:   make(chan struct{}),
+		cmdCh:     make(chan gcCmd),
+
+		frozenSegments: make(map[UniqueID]int),
+		lastReconcile:  time.Now(),
 	}
 }
 
@@ -97,8 +114,9 @@ func (gc *garbageCollector) start() {
 			return
 		}
 		gc.startOnce.Do(func() {
-			gc.wg.Add(1)
+			gc.wg.Add(2)
 			go gc.work()
+			go gc.reconcileLoop()
 		})
 	}
 }
@@ -140,6 +158,37 @@ func (gc *garbageCollector) Resume(ctx context.Context) error {
 	}
 }
 
+// FreezeSegments keeps the meta and files of the segments from being recycled until they are unfrozen.
+// Freezes are counted, a segment frozen twice needs to be unfrozen twice.
+func (gc *garbageCollector) FreezeSegments(segmentIDs ...UniqueID) {
+	gc.frozenMut.Lock()
+	defer gc.frozenMut.Unlock()
+	for _, segmentID := range segmentIDs {
+		gc.frozenSegments[segmentID]++
+	}
+	log.Info("garbage collection frozen for segments", zap.Int64s("segmentIDs", segmentIDs))
+}
+
+// UnfreezeSegments releases the freezes added by FreezeSegments.
+func (gc *garbageCollector) UnfreezeSegments(segmentIDs ...UniqueID) {
+	gc.frozenMut.Lock()
+	defer gc.frozenMut.Unlock()
+	for _, segmentID := range segmentIDs {
+		if gc.frozenSegments[segmentID] <= 1 {
+			delete(gc.frozenSegments, segmentID)
+			continue
+		}
+		gc.frozenSegments[segmentID]--
+	}
+	log.Info("garbage collection unfrozen for segments", zap.Int64s("segmentIDs", segmentIDs))
+}
+
+func (gc *garbageCollector) isFrozen(segmentID UniqueID) bool {
+	gc.frozenMut.Lock()
+	defer gc.frozenMut.Unlock()
+	return gc.frozenSegments[segmentID] > 0
+}
+
 // work contains actual looping check logic
 func (gc *garbageCollector) work() {
 	defer gc.wg.Done()
@@ -155,8 +204,11 @@ func (gc *garbageCollector) work() {
 			gc.clearEtcd()
 			gc.recycleUnusedIndexes()
 			gc.recycleUnusedSegIndexes()
+			gc.recycleUploadManifests()
 			gc.scan()
+			gc.lifecycle.recycle(context.TODO())
 			gc.recycleUnusedIndexFiles()
+			gc.meta.eventLog.recycle(context.TODO())
 		case cmd := <-gc.cmdCh:
 			switch cmd.cmdType {
 			case datapb.GcCommand_Pause:
@@ -213,29 +265,24 @@ func (gc *garbageCollector) scan() {
 		return segmentMap, filesMap
 	}
 
-	// walk only data cluster related prefixes
-	prefixes := make([]string, 0, 3)
-	prefixes = append(prefixes, path.Join(gc.option.cli.RootPath(), common.SegmentInsertLogPath))
-	prefixes = append(prefixes, path.Join(gc.option.cli.RootPath(), common.SegmentStatslogPath))
-	prefixes = append(prefixes, path.Join(gc.option.cli.RootPath(), common.SegmentDeltaLogPath))
-	labels := []string{metrics.InsertFileLabel, metrics.StatFileLabel, metrics.DeleteFileLabel}
+	prefixes := gc.listPrefixes(ctx)
 	var removedKeys []string
 
-	for idx, prefix := range prefixes {
+	for _, prefix := range prefixes {
 		startTs := time.Now()
-		infoKeys, modTimes, err := gc.option.cli.ListWithPrefix(ctx, prefix, true)
+		infoKeys, modTimes, err := gc.option.cli.ListWithPrefix(ctx, prefix.prefix, true)
 		if err != nil {
 			log.Error("failed to list files with prefix",
-				zap.String("prefix", prefix),
+				zap.String("prefix", prefix.prefix),
 				zap.Error(err),
 			)
 		}
 		cost := time.Since(startTs)
 		segmentMap, filesMap := getMetaMap()
 		metrics.GarbageCollectorListLatency.
-			WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), labels[idx]).
+			WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), prefix.label).
 			Observe(float64(cost.Milliseconds()))
-		log.Info("gc scan finish list object", zap.String("prefix", prefix), zap.Duration("time spent", cost), zap.Int("keys", len(infoKeys)))
+		log.Info("gc scan finish list object", zap.String("prefix", prefix.prefix), zap.Duration("time spent", cost), zap.Int("keys", len(infoKeys)))
 		for i, infoKey := range infoKeys {
 			total++
 			_, has := filesMap[infoKey]
@@ -244,7 +291,7 @@ func (gc *garbageCollector) scan() {
 				continue
 			}
 
-			segmentID, err := storage.ParseSegmentIDByBinlog(gc.option.cli.RootPath(), infoKey)
+			segmentID, logType, ok, err := prefix.parse(infoKey)
 			if err != nil {
 				missing++
 				log.Warn("parse segment id error",
@@ -252,13 +299,22 @@ func (gc *garbageCollector) scan() {
 					zap.Error(err))
 				continue
 			}
+			if !ok {
+				// not a binlog, e.g. the objects of the other components sharing the dir
+				continue
+			}
 
-			if strings.Contains(prefix, common.SegmentInsertLogPath) &&
+			if strings.Contains(logType, common.SegmentInsertLogPath) &&
 				segmentMap.Contain(segmentID) {
 				valid++
 				continue
 			}
 
+			// expired by the lifecycle rules of the dropped collections
+			if gc.lifecycle.covers(infoKey) {
+				continue
+			}
+
 			// not found in meta, check last modified time exceeds tolerance duration
 			if time.Since(modTimes[i]) > gc.option.missingTolerance {
 				// ignore error since it could be cleaned up next time
@@ -281,6 +337,135 @@ func (gc *garbageCollector) scan() {
 		zap.Strings("removedKeys", removedKeys))
 }
 
+// listRootPaths returns the root path and the root paths of the storage tenants,
+// the binlogs of the storage tenants are under the root paths of the tenants.
+func (gc *garbageCollector) listRootPaths(ctx context.Context) []string {
+	rootPaths, err := storage.ListRootPaths(ctx, gc.option.cli)
+	if err != nil {
+		log.Warn("failed to list storage tenants", zap.Error(err))
+	}
+	return rootPaths
+}
+
+// gcPrefix is a prefix of the binlogs walked by the garbage collector.
+type gcPrefix struct {
+	prefix   string
+	rootPath string
+	label    string
+	// the binlogs of the path layout are under the static dir of the layout, of all the log types
+	layout bool
+}
+
+// listPrefixes returns the prefixes of the binlogs of the data cluster under the root paths.
+func (gc *garbageCollector) listPrefixes(ctx context.Context) []gcPrefix {
+	rootPaths := gc.listRootPaths(ctx)
+
+	// walk only data cluster related prefixes
+	logPaths := []string{common.SegmentInsertLogPath, common.SegmentStatslogPath, common.SegmentDeltaLogPath, common.SegmentQuantizedLogPath}
+	logLabels := []string{metrics.InsertFileLabel, metrics.StatFileLabel, metrics.DeleteFileLabel, metrics.QuantizedFileLabel}
+	prefixes := make([]gcPrefix, 0, len(rootPaths)*(len(logPaths)+1))
+	for _, rootPath := range rootPaths {
+		for i, logPath := range logPaths {
+			prefixes = append(prefixes, gcPrefix{prefix: path.Join(rootPath, logPath), rootPath: rootPath, label: logLabels[i]})
+		}
+	}
+	// the ones written by the former layouts are recycled with the segments only
+	if staticDir := storage.GetPathLayout().StaticDir(); staticDir != "" {
+		for _, rootPath := range rootPaths {
+			prefixes = append(prefixes, gcPrefix{prefix: path.Join(rootPath, staticDir), rootPath: rootPath, label: metrics.AllLabel, layout: true})
+		}
+	}
+	return prefixes
+}
+
+// parse returns the segment id and the log type of the object listed under the prefix,
+// ok is false if the object is not a binlog.
+func (p gcPrefix) parse(key string) (segmentID UniqueID, logType string, ok bool, err error) {
+	if p.layout {
+		info, ok := metautil.ParseLogPath(key)
+		if !ok {
+			return 0, "", false, nil
+		}
+		return info.SegmentID, info.LogType, true, nil
+	}
+	segmentID, err = storage.ParseSegmentIDByBinlog(p.rootPath, key)
+	if err != nil {
+		return 0, "", false, err
+	}
+	return segmentID, p.prefix, true, nil
+}
+
+// recycleUploadManifests removes the binlogs of the incomplete upload groups, i.e. the ones of the manifests aborted
+// or left for longer than the missing tolerance, the binlogs referenced by the meta are kept. The manifests not aborted
+// are recycled no sooner than the scan removes the binlogs missing in the meta, so the slow uploads are not broken.
+// The manifest is removed once the binlogs of it are all removed, so the failed ones are removed next time.
+func (gc *garbageCollector) recycleUploadManifests() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// built on the first manifest to recycle, as it walks all the segments
+	var filesMap typeutil.Set[string]
+	for _, rootPath := range gc.listRootPaths(ctx) {
+		prefix := storage.UploadManifestPrefix(rootPath)
+		manifestKeys, modTimes, err := gc.option.cli.ListWithPrefix(ctx, prefix, true)
+		if err != nil {
+			log.Warn("failed to list upload manifests", zap.String("prefix", prefix), zap.Error(err))
+			continue
+		}
+		for i, manifestKey := range manifestKeys {
+			log := log.With(zap.String("manifest", manifestKey))
+			data, err := gc.option.cli.Read(ctx, manifestKey)
+			if err != nil {
+				log.Warn("failed to read upload manifest", zap.Error(err))
+				continue
+			}
+			manifest, err := storage.UnmarshalUploadManifest(data)
+			if err != nil {
+				log.Warn("failed to parse upload manifest", zap.Error(err))
+				continue
+			}
+			if !manifest.Aborted && time.Since(modTimes[i]) <= gc.option.missingTolerance {
+				continue
+			}
+
+			if filesMap == nil {
+				filesMap = gc.referencedLogPaths()
+			}
+			removed := true
+			for _, key := range manifest.Keys {
+				if filesMap.Contain(key) {
+					continue
+				}
+				if err := gc.option.cli.Remove(ctx, key); err != nil {
+					log.Warn("failed to remove binlog of incomplete upload", zap.String("key", key), zap.Error(err))
+					removed = false
+				}
+			}
+			if !removed {
+				continue
+			}
+			if err := gc.option.cli.Remove(ctx, manifestKey); err != nil {
+				log.Warn("failed to remove upload manifest", zap.Error(err))
+				continue
+			}
+			log.Info("incomplete upload recycled", zap.Bool("aborted", manifest.Aborted), zap.Int("keys", len(manifest.Keys)))
+		}
+	}
+}
+
+// referencedLogPaths returns the paths of the binlogs in the meta.
+func (gc *garbageCollector) referencedLogPaths() typeutil.Set[string] {
+	filesMap := typeutil.NewSet[string]()
+	for _, segment := range gc.meta.GetAllSegmentsUnsafe() {
+		cloned := segment.Clone()
+		binlog.DecompressBinLogs(cloned.SegmentInfo)
+		for _, log := range getLogs(cloned) {
+			filesMap.Insert(log.GetLogPath())
+		}
+	}
+	return filesMap
+}
+
 func (gc *garbageCollector) checkDroppedSegmentGC(segment *SegmentInfo,
 	childSegment *SegmentInfo,
 	indexSet typeutil.UniqueSet,
@@ -363,6 +548,8 @@ func (gc *garbageCollector) clearEtcd() {
 		return dropIDs[i] < dropIDs[j]
 	})
 
+	// whether the channels exist are looked up once a round, as the dropped channels are never recovered
+	channelExists := make(map[string]bool)
 	log.Info("start to GC segments", zap.Int("drop_num", len(dropIDs)))
 	for _, segmentID := range dropIDs {
 		segment, ok := drops[segmentID]
@@ -371,6 +558,11 @@ func (gc *garbageCollector) clearEtcd() {
 			continue
 		}
 
+		if gc.isFrozen(segmentID) {
+			log.Info("skip GC frozen segment", zap.Int64("segmentID", segmentID))
+			continue
+		}
+
 		segInsertChannel := segment.GetInsertChannel()
 		if !gc.checkDroppedSegmentGC(segment, compactTo[segment.GetID()], indexedSet, channelCPs[segInsertChannel]) {
 			continue
@@ -381,7 +573,8 @@ func (gc *garbageCollector) clearEtcd() {
 			zap.Int("insert_logs", len(segment.GetBinlogs())),
 			zap.Int("delta_logs", len(segment.GetDeltalogs())),
 			zap.Int("stats_logs", len(segment.GetStatslogs())))
-		if gc.removeLogs(logs) {
+		removed := gc.expireDroppedCollectionLogs(segment, logs, channelExists) || gc.removeLogs(logs)
+		if removed && gc.removeSegmentInventories(segment, logs) {
 			err := gc.meta.DropSegment(segment.GetID())
 			if err != nil {
 				log.Info("GC segment meta failed to drop segment", zap.Int64("segment id", segment.GetID()), zap.Error(err))
@@ -420,6 +613,24 @@ func getLogs(sinfo *SegmentInfo) []*datapb.Binlog {
 	return logs
 }
 
+// expireDroppedCollectionLogs returns whether the binlogs of the segment are expired by the lifecycle rules,
+// which are installed only if the collection of the segment is dropped. Whether the channels exist are cached in channelExists.
+func (gc *garbageCollector) expireDroppedCollectionLogs(segment *SegmentInfo, logs []*datapb.Binlog, channelExists map[string]bool) bool {
+	if gc.lifecycle == nil {
+		return false
+	}
+	channel := segment.GetInsertChannel()
+	exists, ok := channelExists[channel]
+	if !ok {
+		exists = gc.meta.catalog.ChannelExists(context.Background(), channel)
+		channelExists[channel] = exists
+	}
+	if exists {
+		return false
+	}
+	return gc.lifecycle.expire(context.Background(), logs)
+}
+
 func (gc *garbageCollector) removeLogs(logs []*datapb.Binlog) bool {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -458,6 +669,30 @@ func (gc *garbageCollector) removeLogs(logs []*datapb.Binlog) bool {
 	}
 }
 
+// removeSegmentInventories removes the inventories of the segment under the roots of the binlogs of it,
+// i.e. with the layout dirs of the binlogs if any. Nothing is removed if the inventories are not uploaded.
+func (gc *garbageCollector) removeSegmentInventories(segment *SegmentInfo, logs []*datapb.Binlog) bool {
+	if !Params.DataNodeCfg.UploadInventory.GetAsBool() {
+		return true
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rootPaths := typeutil.NewSet[string]()
+	for _, l := range logs {
+		if info, ok := metautil.ParseLogPath(l.GetLogPath()); ok {
+			rootPaths.Insert(info.Root)
+		}
+	}
+	for _, rootPath := range rootPaths.Collect() {
+		prefix := storage.SegmentInventoryPrefix(rootPath, segment.GetCollectionID(), segment.GetPartitionID(), segment.GetID())
+		if err := gc.option.cli.RemoveWithPrefix(ctx, prefix); err != nil {
+			log.Warn("failed to remove segment inventories", zap.String("prefix", prefix), zap.Error(err))
+			return false
+		}
+	}
+	return true
+}
+
 func (gc *garbageCollector) recycleUnusedIndexes() {
 	log.Info("start recycleUnusedIndexes")
 	deletedIndexes := gc.meta.GetDeletedIndexes()
@@ -473,6 +708,9 @@ func (gc *garbageCollector) recycleUnusedIndexes() {
 func (gc *garbageCollector) recycleUnusedSegIndexes() {
 	segIndexes := gc.meta.GetAllSegIndexes()
 	for _, segIdx := range segIndexes {
+		if gc.isFrozen(segIdx.SegmentID) {
+			continue
+		}
 		if gc.meta.GetSegment(segIdx.SegmentID) == nil || !gc.meta.IsIndexExist(segIdx.CollectionID, segIdx.IndexID) {
 			if err := gc.meta.RemoveSegmentIndex(segIdx.CollectionID, segIdx.PartitionID, segIdx.SegmentID, segIdx.IndexID, segIdx.BuildID); err != nil {
 				log.Warn("delete index meta from etcd failed, wait to retry", zap.Int64("buildID", segIdx.BuildID),
@@ -528,6 +766,11 @@ func (gc *garbageCollector) recycleUnusedIndexFiles() {
 				zap.Int64("buildID", buildID), zap.String("prefix", key))
 			continue
 		}
+		if gc.isFrozen(segIdx.SegmentID) {
+			log.Info("garbageCollector skip recycling index files of frozen segment",
+				zap.Int64("buildID", buildID), zap.Int64("segmentID", segIdx.SegmentID))
+			continue
+		}
 		filesMap := make(map[string]struct{})
 		for _, fileID := range segIdx.IndexFileKeys {
 			filepath := metautil.BuildSegmentIndexFilePath(gc.option.cli.RootPath(), segIdx.BuildID, segIdx.IndexVersion,
@@ -51,6 +51,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/util/funcutil"
 	"github.com/milvus-io/milvus/pkg/util/lock"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
@@ -107,6 +108,93 @@ func validateMinioPrefixElements(t *testing.T, cli *minio.Client, bucketName str
 	assert.ElementsMatch(t, elements, current)
 }
 
+func Test_garbageCollector_scanTenant(t *testing.T) {
+	ctx := context.Background()
+	rootPath := t.TempDir()
+	cli := storage.NewLocalChunkManager(storage.RootPath(rootPath))
+	tenantRootPath := metautil.TenantRootPath(rootPath, "tenant1")
+
+	referenced := metautil.BuildInsertLogPath(tenantRootPath, 10, 100, 1, 0, 1)
+	orphan := metautil.BuildInsertLogPath(tenantRootPath, 10, 100, 2, 0, 2)
+	orphanDelta := metautil.BuildDeltaLogPath(tenantRootPath, 10, 100, 2, 3)
+	for _, key := range []string{referenced, orphan, orphanDelta} {
+		require.NoError(t, cli.Write(ctx, key, []byte("data")))
+	}
+
+	meta, err := newMemoryMeta()
+	require.NoError(t, err)
+	segment := buildSegment(10, 100, 1, "ch", false)
+	segment.State = commonpb.SegmentState_Flushed
+	segment.Binlogs = []*datapb.FieldBinlog{getFieldBinlogPaths(0, referenced)}
+	err = meta.AddSegment(ctx, segment)
+	require.NoError(t, err)
+
+	gc := newGarbageCollector(meta, newMockHandler(), GcOption{
+		cli:              cli,
+		enabled:          true,
+		checkInterval:    time.Minute * 30,
+		missingTolerance: 0,
+		dropTolerance:    0,
+	})
+	gc.scan()
+
+	keys, _, err := cli.ListWithPrefix(ctx, tenantRootPath, true)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{referenced}, keys)
+}
+
+func Test_garbageCollector_recycleUploadManifests(t *testing.T) {
+	ctx := context.Background()
+	rootPath := t.TempDir()
+	cli := storage.NewLocalChunkManager(storage.RootPath(rootPath))
+
+	referenced := metautil.BuildInsertLogPath(rootPath, 10, 100, 1, 0, 1)
+	uploaded := metautil.BuildInsertLogPath(rootPath, 10, 100, 1, 0, 2)
+	pending := metautil.BuildInsertLogPath(rootPath, 10, 100, 1, 0, 3)
+	for _, key := range []string{referenced, uploaded, pending} {
+		require.NoError(t, cli.Write(ctx, key, []byte("data")))
+	}
+	writeManifest := func(manifest *storage.UploadManifest) string {
+		key := storage.NewUploadManifestKey(rootPath, 1)
+		data, err := manifest.Marshal()
+		require.NoError(t, err)
+		require.NoError(t, cli.Write(ctx, key, data))
+		return key
+	}
+	// the binlogs of the aborted upload are removed at once, except the ones referenced by the meta
+	writeManifest(&storage.UploadManifest{Keys: []string{referenced, uploaded, metautil.BuildInsertLogPath(rootPath, 10, 100, 1, 0, 4)}, Aborted: true})
+	// the binlogs of the upload in progress are kept
+	pendingManifest := writeManifest(&storage.UploadManifest{Keys: []string{pending}})
+
+	meta, err := newMemoryMeta()
+	require.NoError(t, err)
+	segment := buildSegment(10, 100, 1, "ch", false)
+	segment.State = commonpb.SegmentState_Flushed
+	segment.Binlogs = []*datapb.FieldBinlog{getFieldBinlogPaths(0, referenced)}
+	err = meta.AddSegment(ctx, segment)
+	require.NoError(t, err)
+
+	gc := newGarbageCollector(meta, newMockHandler(), GcOption{
+		cli:              cli,
+		enabled:          true,
+		checkInterval:    time.Minute * 30,
+		missingTolerance: time.Hour,
+		dropTolerance:    time.Hour,
+	})
+	gc.recycleUploadManifests()
+
+	keys, _, err := cli.ListWithPrefix(ctx, rootPath, true)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{referenced, pending, pendingManifest}, keys)
+
+	// the binlogs of the manifest expired are taken as abandoned
+	gc.option.missingTolerance = 0
+	gc.recycleUploadManifests()
+	keys, _, err = cli.ListWithPrefix(ctx, rootPath, true)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{referenced}, keys)
+}
+
 func Test_garbageCollector_scan(t *testing.T) {
 	bucketName := `datacoord-ut` + strings.ToLower(funcutil.RandomString(8))
 	rootPath := paramtable.Get().MinioCfg.RootPath.GetValue()
@@ -1181,6 +1269,40 @@ func TestGarbageCollector_clearETCD(t *testing.T) {
 	assert.Nil(t, segB)
 }
 
+func TestGarbageCollector_freezeSegments(t *testing.T) {
+	m, err := newMemoryMeta()
+	require.NoError(t, err)
+	err = m.AddSegment(context.TODO(), NewSegmentInfo(&datapb.SegmentInfo{
+		ID:            segID,
+		CollectionID:  collID,
+		PartitionID:   partID,
+		InsertChannel: "dmlChannel",
+		State:         commonpb.SegmentState_Dropped,
+	}))
+	require.NoError(t, err)
+
+	gc := newGarbageCollector(m, newMockHandler(), GcOption{
+		cli:           storage.NewLocalChunkManager(storage.RootPath("/tmp/milvus_test/freeze")),
+		dropTolerance: 0,
+	})
+	gc.FreezeSegments(segID)
+	gc.FreezeSegments(segID)
+	assert.True(t, gc.isFrozen(segID))
+
+	gc.clearEtcd()
+	assert.NotNil(t, m.GetSegment(segID))
+
+	gc.UnfreezeSegments(segID)
+	assert.True(t, gc.isFrozen(segID))
+	gc.clearEtcd()
+	assert.NotNil(t, m.GetSegment(segID))
+
+	gc.UnfreezeSegments(segID)
+	assert.False(t, gc.isFrozen(segID))
+	gc.clearEtcd()
+	assert.Nil(t, m.GetSegment(segID))
+}
+
 func TestGarbageCollector_removelogs(t *testing.T) {
 	paramtable.Init()
 	cm := &mocks.ChunkManager{}
@@ -0,0 +1,227 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/metastore/kv/binlog"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// gcReference is a binlog referenced by the meta.
+type gcReference struct {
+	segmentID UniqueID
+	size      int64
+	dropped   bool
+}
+
+// references returns the binlogs referenced by the segments in the meta, keyed by the paths.
+// The binlogs shared by the segments are taken as dropped only if all the segments are dropped.
+func (gc *garbageCollector) references() map[string]gcReference {
+	refs := make(map[string]gcReference)
+	for _, segment := range gc.meta.GetAllSegmentsUnsafe() {
+		cloned := segment.Clone()
+		binlog.DecompressBinLogs(cloned.SegmentInfo)
+		dropped := segment.GetState() == commonpb.SegmentState_Dropped
+		for _, l := range getLogs(cloned) {
+			if ref, ok := refs[l.GetLogPath()]; ok && !ref.dropped {
+				continue
+			}
+			refs[l.GetLogPath()] = gcReference{segmentID: segment.GetID(), size: l.GetLogSize(), dropped: dropped}
+		}
+	}
+	return refs
+}
+
+// reconcile walks the binlog prefixes of the object storage and diffs them against the meta,
+// the report counts the objects referenced, the orphans referenced by no segment and the binlogs
+// of the segments not dropped which are missing from the storage.
+// If autoDelete, the orphans not modified within the safety window are removed,
+// except the ones of the frozen segments and the ones expired by the lifecycle rules.
+//
+// The references are taken from the meta once before the listing, so the binlogs added during the
+// listing are not taken as missing, and the objects modified since the start are not taken as orphans.
+func (gc *garbageCollector) reconcile(ctx context.Context, autoDelete bool) *datapb.GcReport {
+	gc.reconcileMut.Lock()
+	defer gc.reconcileMut.Unlock()
+
+	start := time.Now()
+	report := &datapb.GcReport{StartTime: start.UnixMilli()}
+	maxReported := Params.DataCoordCfg.GCReconcileMaxReportedObjects.GetAsInt()
+	safetyWindow := Params.DataCoordCfg.GCReconcileSafetyWindow.GetAsDuration(time.Second)
+
+	refs := gc.references()
+	listed := typeutil.NewSet[string]()
+	for _, prefix := range gc.listPrefixes(ctx) {
+		keys, sizes, modTimes, err := storage.ListSizesWithPrefix(ctx, gc.option.cli, prefix.prefix, true)
+		if err != nil {
+			log.Warn("failed to list objects for reconciliation", zap.String("prefix", prefix.prefix), zap.Error(err))
+			report.Incomplete = true
+			continue
+		}
+		for i, key := range keys {
+			report.TotalObjects++
+			listed.Insert(key)
+			if ref, ok := refs[key]; ok {
+				report.ReferencedObjects++
+				report.ReferencedSize += ref.size
+				continue
+			}
+
+			segmentID, _, ok, err := prefix.parse(key)
+			if err != nil || !ok {
+				// not a binlog, never taken as an orphan
+				continue
+			}
+			// expired by the lifecycle rules of the dropped collections
+			if gc.lifecycle.covers(key) {
+				continue
+			}
+			// written after the references taken, it may be referenced meanwhile
+			if !modTimes[i].Before(start) {
+				continue
+			}
+
+			var size int64
+			if sizes != nil {
+				size = sizes[i]
+			} else if size, err = gc.option.cli.Size(ctx, key); err != nil {
+				log.Warn("failed to get the size of orphan object", zap.String("key", key), zap.Error(err))
+				continue
+			}
+			report.OrphanObjects++
+			report.OrphanSize += size
+			if len(report.Orphans) < maxReported {
+				report.Orphans = append(report.Orphans, &datapb.GcReportObject{
+					Path:         key,
+					SegmentID:    segmentID,
+					Size:         size,
+					LastModified: modTimes[i].Unix(),
+				})
+			}
+
+			if !autoDelete || time.Since(modTimes[i]) <= safetyWindow || gc.isFrozen(segmentID) {
+				continue
+			}
+			if err := gc.option.cli.Remove(ctx, key); err != nil {
+				log.Warn("failed to remove orphan object", zap.String("key", key), zap.Error(err))
+				continue
+			}
+			report.RemovedObjects++
+			report.RemovedSize += size
+			log.Info("orphan object removed by reconciliation", zap.String("key", key), zap.Int64("segmentID", segmentID),
+				zap.Int64("size", size), zap.Time("lastModified", modTimes[i]))
+		}
+	}
+
+	// the missing ones are unknown unless all the prefixes are listed
+	if !report.GetIncomplete() {
+		paths := make([]string, 0)
+		for path, ref := range refs {
+			if !ref.dropped && !listed.Contain(path) {
+				paths = append(paths, path)
+			}
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			ref := refs[path]
+			report.MissingObjects++
+			report.MissingSize += ref.size
+			if len(report.Missing) < maxReported {
+				report.Missing = append(report.Missing, &datapb.GcReportObject{
+					Path:      path,
+					SegmentID: ref.segmentID,
+					Size:      ref.size,
+				})
+			}
+		}
+	}
+
+	report.EndTime = time.Now().UnixMilli()
+	gc.report.Store(report)
+	log.Info("garbage collection reconciled",
+		zap.Bool("incomplete", report.GetIncomplete()),
+		zap.Int64("total", report.GetTotalObjects()),
+		zap.Int64("referenced", report.GetReferencedObjects()),
+		zap.Int64("referencedSize", report.GetReferencedSize()),
+		zap.Int64("orphans", report.GetOrphanObjects()),
+		zap.Int64("orphanSize", report.GetOrphanSize()),
+		zap.Int64("removed", report.GetRemovedObjects()),
+		zap.Int64("missing", report.GetMissingObjects()),
+		zap.Int64("missingSize", report.GetMissingSize()))
+	if report.GetMissingObjects() > 0 {
+		log.Warn("binlogs referenced by the meta are missing from the storage",
+			zap.Int64("missing", report.GetMissingObjects()),
+			zap.Strings("samples", lo.Map(report.GetMissing(), func(obj *datapb.GcReportObject, _ int) string { return obj.GetPath() })))
+	}
+	return report
+}
+
+// reconcileLoop reconciles the storage out of the work loop, so that the regular garbage collection
+// is never blocked by walking the whole storage.
+func (gc *garbageCollector) reconcileLoop() {
+	defer gc.wg.Done()
+	ticker := time.NewTicker(gc.option.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if time.Now().Before(gc.pauseUntil.Load()) {
+				continue
+			}
+			gc.reconcileIfDue()
+		case <-gc.closeCh:
+			return
+		}
+	}
+}
+
+// reconcileIfDue reconciles the storage periodically, with the orphans removed if the auto delete is enabled.
+func (gc *garbageCollector) reconcileIfDue() {
+	interval := Params.DataCoordCfg.GCReconcileInterval.GetAsDuration(time.Second)
+	if interval <= 0 || time.Since(gc.lastReconcile) < interval {
+		return
+	}
+	gc.lastReconcile = time.Now()
+	gc.reconcile(context.TODO(), Params.DataCoordCfg.GCReconcileAutoDelete.GetAsBool())
+}
+
+// GetReport returns the last reconciliation report, or a fresh one if refresh.
+func (gc *garbageCollector) GetReport(ctx context.Context, refresh bool) (*datapb.GcReport, error) {
+	if !gc.option.enabled || gc.option.cli == nil {
+		return nil, merr.WrapErrServiceUnavailable("garbage collection not enabled")
+	}
+	if refresh {
+		return gc.reconcile(ctx, false), nil
+	}
+	report := gc.report.Load()
+	if report == nil {
+		return nil, merr.WrapErrServiceUnavailable("storage not reconciled yet, request with refresh")
+	}
+	return report, nil
+}
@@ -0,0 +1,233 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+)
+
+type GcReconcileSuite struct {
+	suite.Suite
+
+	rootPath string
+	cli      storage.ChunkManager
+	gc       *garbageCollector
+
+	referenced  string
+	liveOrphan  string
+	deadOrphan  string
+	missing     string
+	droppedMiss string
+	other       string
+}
+
+func (s *GcReconcileSuite) SetupTest() {
+	ctx := context.Background()
+	s.rootPath = s.T().TempDir()
+	s.cli = storage.NewLocalChunkManager(storage.RootPath(s.rootPath))
+
+	s.referenced = metautil.BuildInsertLogPath(s.rootPath, 10, 100, 1, 0, 1)
+	s.liveOrphan = metautil.BuildInsertLogPath(s.rootPath, 10, 100, 1, 0, 2)
+	s.deadOrphan = metautil.BuildDeltaLogPath(s.rootPath, 10, 100, 2, 3)
+	s.missing = metautil.BuildStatsLogPath(s.rootPath, 10, 100, 1, 0, 4)
+	s.droppedMiss = metautil.BuildInsertLogPath(s.rootPath, 10, 100, 3, 0, 5)
+	s.other = path.Join(s.rootPath, "other", "object")
+	for _, key := range []string{s.referenced, s.liveOrphan, s.deadOrphan, s.other} {
+		s.Require().NoError(s.cli.Write(ctx, key, []byte("data")))
+	}
+
+	meta, err := newMemoryMeta()
+	s.Require().NoError(err)
+	segment := buildSegment(10, 100, 1, "ch", false)
+	segment.State = commonpb.SegmentState_Flushed
+	segment.Binlogs = []*datapb.FieldBinlog{{FieldID: 0, Binlogs: []*datapb.Binlog{{LogPath: s.referenced, LogSize: 4}}}}
+	segment.Statslogs = []*datapb.FieldBinlog{{FieldID: 0, Binlogs: []*datapb.Binlog{{LogPath: s.missing, LogSize: 10}}}}
+	s.Require().NoError(meta.AddSegment(ctx, segment))
+	dropped := buildSegment(10, 100, 3, "ch", false)
+	dropped.State = commonpb.SegmentState_Dropped
+	dropped.Binlogs = []*datapb.FieldBinlog{getFieldBinlogPaths(0, s.droppedMiss)}
+	s.Require().NoError(meta.AddSegment(ctx, dropped))
+
+	s.gc = newGarbageCollector(meta, newMockHandler(), GcOption{
+		cli:              s.cli,
+		enabled:          true,
+		checkInterval:    time.Minute * 30,
+		missingTolerance: time.Hour,
+		dropTolerance:    time.Hour,
+	})
+}
+
+func (s *GcReconcileSuite) listKeys() []string {
+	keys, _, err := s.cli.ListWithPrefix(context.Background(), s.rootPath, true)
+	s.Require().NoError(err)
+	return keys
+}
+
+func (s *GcReconcileSuite) TestReport() {
+	report := s.gc.reconcile(context.Background(), true)
+
+	s.False(report.GetIncomplete())
+	s.EqualValues(3, report.GetTotalObjects())
+	s.EqualValues(1, report.GetReferencedObjects())
+	s.EqualValues(4, report.GetReferencedSize())
+	s.EqualValues(2, report.GetOrphanObjects())
+	s.EqualValues(8, report.GetOrphanSize())
+	s.ElementsMatch([]string{s.liveOrphan, s.deadOrphan}, lo.Map(report.GetOrphans(), func(obj *datapb.GcReportObject, _ int) string {
+		return obj.GetPath()
+	}))
+	s.EqualValues(1, report.GetMissingObjects())
+	s.EqualValues(10, report.GetMissingSize())
+	s.Require().Len(report.GetMissing(), 1)
+	s.Equal(s.missing, report.GetMissing()[0].GetPath())
+	s.EqualValues(1, report.GetMissing()[0].GetSegmentID())
+
+	// within the safety window
+	s.EqualValues(0, report.GetRemovedObjects())
+	s.ElementsMatch([]string{s.referenced, s.liveOrphan, s.deadOrphan, s.other}, s.listKeys())
+}
+
+func (s *GcReconcileSuite) TestAutoDelete() {
+	Params.Save(Params.DataCoordCfg.GCReconcileSafetyWindow.Key, "0")
+	defer Params.Reset(Params.DataCoordCfg.GCReconcileSafetyWindow.Key)
+
+	report := s.gc.reconcile(context.Background(), false)
+	s.EqualValues(2, report.GetOrphanObjects())
+	s.EqualValues(0, report.GetRemovedObjects())
+	s.Len(s.listKeys(), 4)
+
+	s.gc.FreezeSegments(1)
+	report = s.gc.reconcile(context.Background(), true)
+	s.EqualValues(2, report.GetOrphanObjects())
+	s.EqualValues(1, report.GetRemovedObjects())
+	s.EqualValues(4, report.GetRemovedSize())
+	s.ElementsMatch([]string{s.referenced, s.liveOrphan, s.other}, s.listKeys())
+
+	s.gc.UnfreezeSegments(1)
+	report = s.gc.reconcile(context.Background(), true)
+	s.EqualValues(1, report.GetRemovedObjects())
+	s.ElementsMatch([]string{s.referenced, s.other}, s.listKeys())
+}
+
+func (s *GcReconcileSuite) TestMaxReportedObjects() {
+	Params.Save(Params.DataCoordCfg.GCReconcileMaxReportedObjects.Key, "1")
+	defer Params.Reset(Params.DataCoordCfg.GCReconcileMaxReportedObjects.Key)
+
+	report := s.gc.reconcile(context.Background(), false)
+	s.EqualValues(2, report.GetOrphanObjects())
+	s.EqualValues(8, report.GetOrphanSize())
+	s.Len(report.GetOrphans(), 1)
+}
+
+func (s *GcReconcileSuite) TestReconcileIfDue() {
+	// disabled by default
+	s.gc.lastReconcile = time.Time{}
+	s.gc.reconcileIfDue()
+	s.Nil(s.gc.report.Load())
+
+	Params.Save(Params.DataCoordCfg.GCReconcileInterval.Key, "3600")
+	defer Params.Reset(Params.DataCoordCfg.GCReconcileInterval.Key)
+	s.gc.lastReconcile = time.Now()
+	s.gc.reconcileIfDue()
+	s.Nil(s.gc.report.Load())
+
+	s.gc.lastReconcile = time.Time{}
+	s.gc.reconcileIfDue()
+	s.NotNil(s.gc.report.Load())
+	s.Len(s.listKeys(), 4)
+}
+
+func (s *GcReconcileSuite) TestReconcileLoop() {
+	Params.Save(Params.DataCoordCfg.GCReconcileInterval.Key, "3600")
+	defer Params.Reset(Params.DataCoordCfg.GCReconcileInterval.Key)
+	s.gc.option.checkInterval = time.Millisecond * 10
+	s.gc.lastReconcile = time.Time{}
+	s.gc.wg.Add(1)
+	go s.gc.reconcileLoop()
+	defer s.gc.close()
+
+	s.Eventually(func() bool {
+		return s.gc.report.Load() != nil
+	}, time.Second*5, time.Millisecond*10)
+}
+
+func (s *GcReconcileSuite) TestObjectsWrittenDuringReconcile() {
+	// the objects modified since the reconciliation started are never taken as orphans
+	future := time.Now().Add(time.Hour)
+	s.Require().NoError(os.Chtimes(s.liveOrphan, future, future))
+
+	report := s.gc.reconcile(context.Background(), false)
+	s.EqualValues(3, report.GetTotalObjects())
+	s.EqualValues(1, report.GetOrphanObjects())
+	s.Require().Len(report.GetOrphans(), 1)
+	s.Equal(s.deadOrphan, report.GetOrphans()[0].GetPath())
+	s.EqualValues(4, report.GetOrphans()[0].GetSize())
+}
+
+func (s *GcReconcileSuite) TestGetReport() {
+	ctx := context.Background()
+	_, err := s.gc.GetReport(ctx, false)
+	s.ErrorIs(err, merr.ErrServiceUnavailable)
+
+	report, err := s.gc.GetReport(ctx, true)
+	s.NoError(err)
+	s.EqualValues(2, report.GetOrphanObjects())
+
+	last, err := s.gc.GetReport(ctx, false)
+	s.NoError(err)
+	s.Equal(report, last)
+
+	s.gc.option.enabled = false
+	_, err = s.gc.GetReport(ctx, true)
+	s.ErrorIs(err, merr.ErrServiceUnavailable)
+}
+
+func (s *GcReconcileSuite) TestServices() {
+	ctx := context.Background()
+	server := &Server{garbageCollector: s.gc}
+	server.stateCode.Store(commonpb.StateCode_Healthy)
+
+	resp, err := server.GetGcReport(ctx, &datapb.GetGcReportRequest{})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrServiceUnavailable)
+
+	resp, err = server.GetGcReport(ctx, &datapb.GetGcReportRequest{Refresh: true})
+	s.NoError(err)
+	s.True(merr.Ok(resp.GetStatus()))
+	s.EqualValues(3, resp.GetReport().GetTotalObjects())
+
+	server.stateCode.Store(commonpb.StateCode_Abnormal)
+	resp, err = server.GetGcReport(ctx, &datapb.GetGcReportRequest{})
+	s.NoError(err)
+	s.False(merr.Ok(resp.GetStatus()))
+}
+
+func TestGcReconcile(t *testing.T) {
+	suite.Run(t, new(GcReconcileSuite))
+}
@@ -312,7 +312,17 @@ func (ib *indexBuilder) process(buildID UniqueID) bool {
 				RootPath:    Params.LocalStorageCfg.Path.GetValue(),
 				StorageType: Params.CommonCfg.StorageType.GetValue(),
 			}
+		} else if Params.CommonCfg.StorageType.GetValue() == storage.StorageTypeHDFS {
+			storageConfig = &indexpb.StorageConfig{
+				Address:          Params.HDFSCfg.Address.GetValue(),
+				UseSSL:           Params.HDFSCfg.UseSSL.GetAsBool(),
+				BucketName:       Params.HDFSCfg.BaseDir.GetValue(),
+				RootPath:         Params.MinioCfg.RootPath.GetValue(),
+				StorageType:      storage.StorageTypeHDFS,
+				RequestTimeoutMs: Params.HDFSCfg.RequestTimeoutMs.GetAsInt64(),
+			}
 		} else {
+			storageType, cloudProvider := storage.RemoteStorageOf(Params.CommonCfg.StorageType.GetValue(), Params.MinioCfg.CloudProvider.GetValue())
 			storageConfig = &indexpb.StorageConfig{
 				Address:          Params.MinioCfg.Address.GetValue(),
 				AccessKeyID:      Params.MinioCfg.AccessKeyID.GetValue(),
@@ -322,10 +332,10 @@ func (ib *indexBuilder) process(buildID UniqueID) bool {
 				RootPath:         Params.MinioCfg.RootPath.GetValue(),
 				UseIAM:           Params.MinioCfg.UseIAM.GetAsBool(),
 				IAMEndpoint:      Params.MinioCfg.IAMEndpoint.GetValue(),
-				StorageType:      Params.CommonCfg.StorageType.GetValue(),
+				StorageType:      storageType,
 				Region:           Params.MinioCfg.Region.GetValue(),
 				UseVirtualHost:   Params.MinioCfg.UseVirtualHost.GetAsBool(),
-				CloudProvider:    Params.MinioCfg.CloudProvider.GetValue(),
+				CloudProvider:    cloudProvider,
 				RequestTimeoutMs: Params.MinioCfg.RequestTimeoutMs.GetAsInt64(),
 			}
 		}
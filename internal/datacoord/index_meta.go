@@ -29,6 +29,7 @@ import (
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus/internal/metastore/model"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/indexpb"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
@@ -575,6 +576,20 @@ func (m *meta) FinishTask(taskInfo *indexpb.IndexTaskInfo) error {
 	)
 	m.updateIndexTasksMetrics()
 	metrics.FlushedSegmentFileNum.WithLabelValues(metrics.IndexFileLabel).Observe(float64(len(taskInfo.GetIndexFileKeys())))
+	if taskInfo.GetState() == commonpb.IndexState_Finished {
+		segmentState := getSegmentState(m.segments.GetSegment(segIdx.SegmentID))
+		m.eventLog.record(&datapb.SegmentEvent{
+			CollectionID: segIdx.CollectionID,
+			PartitionID:  segIdx.PartitionID,
+			SegmentID:    segIdx.SegmentID,
+			Type:         datapb.SegmentEventType_SegmentIndexed,
+			FromState:    segmentState,
+			ToState:      segmentState,
+			IndexID:      segIdx.IndexID,
+			Actor:        indexNodeActor(segIdx.NodeID),
+			Reason:       fmt.Sprintf("index built by task %d", taskInfo.GetBuildID()),
+		})
+	}
 	return nil
 }
 
@@ -0,0 +1,199 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// lifecycleExpirationDays is the days the binlogs of the dropped collections are expired after they are written,
+// the least the lifecycle rules support.
+const lifecycleExpirationDays = 1
+
+// lifecycleRules expires the binlogs of the dropped collections by the lifecycle rules of the bucket, rather than
+// the garbage collection removing them one by one. A rule of the prefix of each log type of a dropped collection
+// is installed, as the collection ids are never reused, all the binlogs under it are expired, and the rule
+// is uninstalled once there's no object under the prefix.
+type lifecycleRules struct {
+	cli      storage.LifecycleChunkManager
+	maxRules int
+
+	mu     sync.Mutex
+	loaded bool
+	// the rules installed by the prefixes
+	rules map[string]storage.LifecycleRule
+}
+
+// newLifecycleRules returns the lifecycle rules of the bucket of the chunk manager,
+// nil if dataCoord.gc.lifecycleRules is disabled or the storage doesn't support them.
+func newLifecycleRules(cli storage.ChunkManager) *lifecycleRules {
+	if cli == nil || !Params.DataCoordCfg.GCLifecycleRules.GetAsBool() {
+		return nil
+	}
+	lcm, ok := cli.(storage.LifecycleChunkManager)
+	if !ok {
+		log.Warn("lifecycle rules enabled, but not supported by the chunk manager, the binlogs are removed one by one")
+		return nil
+	}
+	return &lifecycleRules{
+		cli:      lcm,
+		maxRules: Params.DataCoordCfg.GCLifecycleMaxRules.GetAsInt(),
+		rules:    make(map[string]storage.LifecycleRule),
+	}
+}
+
+// collectionLogPrefix returns the prefix of the binlogs of the log type and the collection of the binlog.
+func collectionLogPrefix(logPath string) (string, bool) {
+	info, ok := metautil.ParseLogPath(logPath)
+	if !ok {
+		return "", false
+	}
+	return path.Join(info.Root, info.LogType, strconv.FormatInt(info.CollectionID, 10)) + "/", true
+}
+
+// load loads the rules installed from the bucket, the caller shall hold the lock.
+func (r *lifecycleRules) load(ctx context.Context) error {
+	if r.loaded {
+		return nil
+	}
+	rules, err := r.cli.LifecycleRules(ctx)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		r.rules[rule.Prefix] = rule
+	}
+	r.loaded = true
+	return nil
+}
+
+// install replaces the rules of the bucket, the caller shall hold the lock.
+func (r *lifecycleRules) install(ctx context.Context, rules map[string]storage.LifecycleRule) error {
+	sorted := lo.Values(rules)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Prefix < sorted[j].Prefix })
+	if err := r.cli.SetLifecycleRules(ctx, sorted); err != nil {
+		return err
+	}
+	r.rules = rules
+	return nil
+}
+
+// expire installs the rules expiring the binlogs of a dropped collection, returns whether they are all expired
+// by the rules, false if the rules are beyond dataCoord.gc.lifecycleMaxRules or failed to install.
+func (r *lifecycleRules) expire(ctx context.Context, logs []*datapb.Binlog) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.load(ctx); err != nil {
+		log.Warn("failed to load the lifecycle rules", zap.Error(err))
+		return false
+	}
+
+	prefixes := typeutil.NewSet[string]()
+	for _, l := range logs {
+		prefix, ok := collectionLogPrefix(l.GetLogPath())
+		if !ok {
+			return false
+		}
+		if _, ok := r.rules[prefix]; !ok {
+			prefixes.Insert(prefix)
+		}
+	}
+	if prefixes.Len() == 0 {
+		return true
+	}
+	if len(r.rules)+prefixes.Len() > r.maxRules {
+		log.Ctx(ctx).WithRateGroup("GC_LIFECYCLE_RULES_FULL", 1, 60).
+			RatedInfo(60, "lifecycle rules are full, the binlogs are removed one by one", zap.Int("rules", len(r.rules)))
+		return false
+	}
+
+	rules := make(map[string]storage.LifecycleRule, len(r.rules)+prefixes.Len())
+	for prefix, rule := range r.rules {
+		rules[prefix] = rule
+	}
+	for prefix := range prefixes {
+		rules[prefix] = storage.LifecycleRule{Prefix: prefix, Days: lifecycleExpirationDays}
+	}
+	if err := r.install(ctx, rules); err != nil {
+		log.Warn("failed to install the lifecycle rules", zap.Strings("prefixes", prefixes.Collect()), zap.Error(err))
+		return false
+	}
+	log.Info("lifecycle rules installed", zap.Strings("prefixes", prefixes.Collect()))
+	return true
+}
+
+// covers returns whether the object is expired by the rules installed.
+func (r *lifecycleRules) covers(key string) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for prefix := range r.rules {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// recycle uninstalls the rules of the prefixes all the objects under which are expired.
+func (r *lifecycleRules) recycle(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.load(ctx); err != nil {
+		log.Warn("failed to load the lifecycle rules", zap.Error(err))
+		return
+	}
+
+	kept := make(map[string]storage.LifecycleRule, len(r.rules))
+	for prefix, rule := range r.rules {
+		keys, _, err := r.cli.ListWithPrefix(ctx, prefix, false)
+		if err != nil || len(keys) > 0 {
+			kept[prefix] = rule
+		}
+	}
+	if len(kept) == len(r.rules) {
+		return
+	}
+	removed := lo.Without(lo.Keys(r.rules), lo.Keys(kept)...)
+	if err := r.install(ctx, kept); err != nil {
+		log.Warn("failed to uninstall the lifecycle rules", zap.Strings("prefixes", removed), zap.Error(err))
+		return
+	}
+	log.Info("lifecycle rules uninstalled", zap.Strings("prefixes", removed))
+}
@@ -0,0 +1,105 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+)
+
+// fakeLifecycleChunkManager keeps the lifecycle rules in memory.
+type fakeLifecycleChunkManager struct {
+	storage.ChunkManager
+	rules   []storage.LifecycleRule
+	sets    int
+	failSet bool
+}
+
+func (cm *fakeLifecycleChunkManager) LifecycleRules(ctx context.Context) ([]storage.LifecycleRule, error) {
+	return cm.rules, nil
+}
+
+func (cm *fakeLifecycleChunkManager) SetLifecycleRules(ctx context.Context, rules []storage.LifecycleRule) error {
+	if cm.failSet {
+		return errors.New("mock")
+	}
+	cm.sets++
+	cm.rules = rules
+	return nil
+}
+
+func TestLifecycleRules(t *testing.T) {
+	ctx := context.Background()
+	rootPath := t.TempDir()
+	cm := &fakeLifecycleChunkManager{ChunkManager: storage.NewLocalChunkManager(storage.RootPath(rootPath))}
+
+	assert.Nil(t, newLifecycleRules(cm))
+	Params.Save(Params.DataCoordCfg.GCLifecycleRules.Key, "true")
+	defer Params.Reset(Params.DataCoordCfg.GCLifecycleRules.Key)
+	Params.Save(Params.DataCoordCfg.GCLifecycleMaxRules.Key, "3")
+	defer Params.Reset(Params.DataCoordCfg.GCLifecycleMaxRules.Key)
+	assert.Nil(t, newLifecycleRules(storage.NewLocalChunkManager(storage.RootPath(rootPath))))
+
+	// the rules of the former datacoord are loaded
+	cm.rules = []storage.LifecycleRule{{Prefix: rootPath + "/insert_log/5/", Days: 1}}
+	rules := newLifecycleRules(cm)
+	require.NotNil(t, rules)
+
+	insertLog := metautil.BuildInsertLogPath(rootPath, 10, 100, 1, 0, 1)
+	deltaLog := metautil.BuildDeltaLogPath(rootPath, 10, 100, 1, 2)
+	require.NoError(t, cm.Write(ctx, insertLog, []byte("data")))
+	logs := []*datapb.Binlog{{LogPath: insertLog}, {LogPath: deltaLog}}
+	assert.True(t, rules.expire(ctx, logs))
+	assert.ElementsMatch(t, []storage.LifecycleRule{
+		{Prefix: rootPath + "/delta_log/10/", Days: 1},
+		{Prefix: rootPath + "/insert_log/10/", Days: 1},
+		{Prefix: rootPath + "/insert_log/5/", Days: 1},
+	}, cm.rules)
+	assert.True(t, rules.covers(insertLog))
+	assert.False(t, rules.covers(metautil.BuildInsertLogPath(rootPath, 11, 100, 1, 0, 1)))
+
+	// installed already
+	assert.True(t, rules.expire(ctx, logs[:1]))
+	assert.Equal(t, 1, cm.sets)
+	// beyond the max rules
+	assert.False(t, rules.expire(ctx, []*datapb.Binlog{{LogPath: metautil.BuildInsertLogPath(rootPath, 11, 100, 1, 0, 1)}}))
+	// not a binlog
+	assert.False(t, rules.expire(ctx, []*datapb.Binlog{{LogPath: "a/b"}}))
+
+	// the rules of the prefixes of no object are uninstalled
+	rules.recycle(ctx)
+	assert.Equal(t, []storage.LifecycleRule{{Prefix: rootPath + "/insert_log/10/", Days: 1}}, cm.rules)
+	assert.True(t, rules.covers(insertLog))
+	assert.False(t, rules.covers(deltaLog))
+
+	cm.failSet = true
+	assert.False(t, rules.expire(ctx, []*datapb.Binlog{{LogPath: deltaLog}}))
+	assert.False(t, rules.covers(deltaLog))
+
+	var nilRules *lifecycleRules
+	assert.False(t, nilRules.expire(ctx, logs))
+	assert.False(t, nilRules.covers(insertLog))
+	nilRules.recycle(ctx)
+}
@@ -0,0 +1,392 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// majorCompactionMaxFailures is the number of the failed compactions over which a major compaction job fails.
+const majorCompactionMaxFailures = 3
+
+// majorCompactionJob is a major compaction job with the segments still to compact.
+type majorCompactionJob struct {
+	*datapb.MajorCompactionJob
+	pending typeutil.UniqueSet      // segments not compacted yet
+	running map[UniqueID][]UniqueID // segments of each running compaction by plan ID
+}
+
+func (j *majorCompactionJob) isDone() bool {
+	state := j.GetState()
+	return state == datapb.MajorCompactionState_MajorCompactionCompleted ||
+		state == datapb.MajorCompactionState_MajorCompactionFailed
+}
+
+func (j *majorCompactionJob) fail(reason string) {
+	j.State = datapb.MajorCompactionState_MajorCompactionFailed
+	j.Reason = reason
+}
+
+// majorCompactionManager runs the major compaction jobs, each of them merges all the sealed segments of a partition
+// into segments of the target size by mix compactions, regardless of the compaction triggers. The segments of a job
+// are the ones sealed when it starts, they are compacted once even if the compaction results are smaller than the target size.
+// The compactions are throttled: at most MajorCompactionMaxConcurrency compactions of a job run at the same time,
+// and none is submitted while the compaction queue is full.
+//
+// If the collection has the clustering field, the compactions are clustering compactions re-partitioning the rows
+// of up to ClusteringCompactionMaxInputSize segments into the segments of the target size by the value of the field,
+// so the segments out of the range of a filter on the field are pruned by their zone maps.
+//
+// The jobs are only kept in memory, they are lost if datacoord restarts and must be submitted again.
+type majorCompactionManager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	meta              *meta
+	handler           Handler
+	allocator         allocator
+	compactionHandler compactionPlanContext
+
+	mu   sync.Mutex
+	jobs map[UniqueID]*majorCompactionJob
+}
+
+func newMajorCompactionManager(ctx context.Context, meta *meta, handler Handler, allocator allocator,
+	compactionHandler compactionPlanContext,
+) *majorCompactionManager {
+	ctx, cancel := context.WithCancel(ctx)
+	return &majorCompactionManager{
+		ctx:               ctx,
+		cancel:            cancel,
+		meta:              meta,
+		handler:           handler,
+		allocator:         allocator,
+		compactionHandler: compactionHandler,
+		jobs:              make(map[UniqueID]*majorCompactionJob),
+	}
+}
+
+func (m *majorCompactionManager) start() {
+	m.wg.Add(1)
+	go m.loop()
+}
+
+func (m *majorCompactionManager) close() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+func (m *majorCompactionManager) loop() {
+	defer m.wg.Done()
+	ticker := time.NewTicker(Params.DataCoordCfg.MajorCompactionCheckInterval.GetAsDuration(time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			log.Info("major compaction loop quit")
+			return
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+// submit starts @job on the sealed segments of its partition, only one job runs on a partition at the same time.
+func (m *majorCompactionManager) submit(job *datapb.MajorCompactionJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, running := range m.jobs {
+		if !running.isDone() && running.GetCollectionID() == job.GetCollectionID() && running.GetPartitionID() == job.GetPartitionID() {
+			return merr.WrapErrParameterInvalidMsg("major compaction job %d is running on partition %d", running.GetJobID(), job.GetPartitionID())
+		}
+	}
+
+	segments := m.meta.SelectSegments(func(segment *SegmentInfo) bool {
+		return segment.GetCollectionID() == job.GetCollectionID() &&
+			segment.GetPartitionID() == job.GetPartitionID() &&
+			isSegmentHealthy(segment) &&
+			isFlush(segment) &&
+			segment.GetLevel() != datapb.SegmentLevel_L0 &&
+			!segment.GetIsImporting()
+	})
+	if job.GetTargetSize() <= 0 {
+		job.TargetSize = Params.DataCoordCfg.SegmentMaxSize.GetAsInt64() * 1024 * 1024
+	}
+	job.State = datapb.MajorCompactionState_MajorCompactionRunning
+	job.TotalSegments = int64(len(segments))
+	m.jobs[job.GetJobID()] = &majorCompactionJob{
+		MajorCompactionJob: job,
+		pending: typeutil.NewUniqueSet(lo.Map(segments, func(segment *SegmentInfo, _ int) UniqueID {
+			return segment.GetID()
+		})...),
+		running: make(map[UniqueID][]UniqueID),
+	}
+	return nil
+}
+
+// getJob returns a copy of the job, or nil if it does not exist.
+func (m *majorCompactionManager) getJob(jobID UniqueID) *datapb.MajorCompactionJob {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return nil
+	}
+	cloned := proto.Clone(job.MajorCompactionJob).(*datapb.MajorCompactionJob)
+	cloned.ExecutingPlans = int64(len(job.running))
+	return cloned
+}
+
+// alter pauses or resumes the job, a paused job submits no more compactions but waits for the running ones.
+func (m *majorCompactionManager) alter(jobID UniqueID, command datapb.MajorCompactionCommand) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return merr.WrapErrParameterInvalidMsg("major compaction job %d not found", jobID)
+	}
+	if job.isDone() {
+		return merr.WrapErrParameterInvalidMsg("major compaction job %d is %s", jobID, job.GetState())
+	}
+	switch command {
+	case datapb.MajorCompactionCommand_PauseMajorCompaction:
+		job.State = datapb.MajorCompactionState_MajorCompactionPaused
+	case datapb.MajorCompactionCommand_ResumeMajorCompaction:
+		job.State = datapb.MajorCompactionState_MajorCompactionRunning
+	default:
+		return merr.WrapErrParameterInvalidMsg("unknown major compaction command %s", command)
+	}
+	log.Info("major compaction job altered", zap.Int64("jobID", jobID), zap.Stringer("command", command))
+	return nil
+}
+
+func (m *majorCompactionManager) check() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, job := range m.jobs {
+		if job.isDone() {
+			continue
+		}
+		m.refresh(job)
+		if job.GetState() == datapb.MajorCompactionState_MajorCompactionRunning {
+			m.submitPlans(job)
+		}
+		if !job.isDone() && job.pending.Len() == 0 && len(job.running) == 0 {
+			job.State = datapb.MajorCompactionState_MajorCompactionCompleted
+			log.Info("major compaction job completed",
+				zap.Int64("jobID", job.GetJobID()),
+				zap.Int64("compactedSegments", job.GetCompactedSegments()),
+				zap.Int64("completedPlans", job.GetCompletedPlans()),
+				zap.Int64("failedPlans", job.GetFailedPlans()))
+		}
+	}
+}
+
+// refresh collects the results of the running compactions of the job, the segments of the failed ones are compacted again,
+// and the segments no longer healthy, compacted or dropped by others, are counted as compacted.
+func (m *majorCompactionManager) refresh(job *majorCompactionJob) {
+	log := log.With(zap.Int64("jobID", job.GetJobID()))
+	for planID, segmentIDs := range job.running {
+		task := m.compactionHandler.getCompaction(planID)
+		if task != nil && (task.state == executing || task.state == pipelining) {
+			continue
+		}
+		delete(job.running, planID)
+		if task != nil && task.state == completed {
+			job.CompletedPlans++
+			job.CompactedSegments += int64(len(segmentIDs))
+			continue
+		}
+		job.FailedPlans++
+		job.pending.Insert(segmentIDs...)
+		log.Warn("compaction of major compaction job failed", zap.Int64("planID", planID), zap.Int64s("segmentIDs", segmentIDs))
+	}
+	if job.GetFailedPlans() > majorCompactionMaxFailures {
+		job.fail(fmt.Sprintf("%d compactions failed", job.GetFailedPlans()))
+		log.Warn("major compaction job failed", zap.String("reason", job.GetReason()))
+		return
+	}
+
+	for _, segmentID := range job.pending.Collect() {
+		if m.meta.GetHealthySegment(segmentID) == nil {
+			job.pending.Remove(segmentID)
+			job.CompactedSegments++
+		}
+	}
+}
+
+// submitPlans groups the pending segments of each channel into the segments of the target size, from the oldest one,
+// and submits the compactions of the groups until the job or the compaction queue is full.
+func (m *majorCompactionManager) submitPlans(job *majorCompactionJob) {
+	log := log.With(zap.Int64("jobID", job.GetJobID()),
+		zap.Int64("collectionID", job.GetCollectionID()),
+		zap.Int64("partitionID", job.GetPartitionID()))
+	if job.pending.Len() == 0 || len(job.running) >= Params.DataCoordCfg.MajorCompactionMaxConcurrency.GetAsInt() || m.compactionHandler.isFull() {
+		return
+	}
+
+	coll, err := m.handler.GetCollection(m.ctx, job.GetCollectionID())
+	if err != nil {
+		log.Warn("failed to get collection", zap.Error(err))
+		return
+	}
+	if coll == nil {
+		job.fail(merr.WrapErrCollectionNotFound(job.GetCollectionID()).Error())
+		log.Warn("major compaction job failed", zap.String("reason", job.GetReason()))
+		return
+	}
+	ttl, err := getPartitionTTL(coll.Properties, job.GetPartitionID())
+	if err != nil {
+		log.Warn("failed to get collection ttl", zap.Error(err))
+		return
+	}
+
+	clusteringField := getClusteringField(coll)
+	maxSize := job.GetTargetSize()
+	if clusteringField != nil {
+		maxSize = Params.DataCoordCfg.ClusteringCompactionMaxInputSize.GetAsInt64() * 1024 * 1024
+	}
+	for _, group := range m.groupPending(job, maxSize) {
+		segmentIDs := lo.Map(group, func(segment *SegmentInfo, _ int) UniqueID { return segment.GetID() })
+		if len(group) == 1 && len(group[0].GetDeltalogs()) == 0 {
+			// nothing to merge or to reclaim
+			job.pending.Remove(segmentIDs...)
+			job.CompactedSegments++
+			continue
+		}
+		if len(job.running) >= Params.DataCoordCfg.MajorCompactionMaxConcurrency.GetAsInt() || m.compactionHandler.isFull() {
+			return
+		}
+		planID, err := m.compact(job, group, ttl, clusteringField)
+		if err != nil {
+			log.Warn("failed to submit compaction of major compaction job", zap.Int64s("segmentIDs", segmentIDs), zap.Error(err))
+			return
+		}
+		job.pending.Remove(segmentIDs...)
+		job.running[planID] = segmentIDs
+		log.Info("submitted compaction of major compaction job", zap.Int64("planID", planID), zap.Int64s("segmentIDs", segmentIDs))
+	}
+}
+
+// groupPending returns the groups of the pending segments not compacting, the segments of a group are of the same channel,
+// no larger than @maxSize in total, and at most MaxSegmentToMerge of them.
+func (m *majorCompactionManager) groupPending(job *majorCompactionJob, maxSize int64) [][]*SegmentInfo {
+	channelSegments := make(map[string][]*SegmentInfo)
+	for _, segmentID := range job.pending.Collect() {
+		segment := m.meta.GetHealthySegment(segmentID)
+		if segment == nil || segment.isCompacting {
+			continue
+		}
+		channelSegments[segment.GetInsertChannel()] = append(channelSegments[segment.GetInsertChannel()], segment)
+	}
+	channels := lo.Keys(channelSegments)
+	sort.Strings(channels)
+
+	maxNum := Params.DataCoordCfg.MaxSegmentToMerge.GetAsInt()
+	var groups [][]*SegmentInfo
+	for _, channel := range channels {
+		segments := channelSegments[channel]
+		sort.Slice(segments, func(i, j int) bool { return segments[i].GetID() < segments[j].GetID() })
+		var (
+			group []*SegmentInfo
+			size  int64
+		)
+		for _, segment := range segments {
+			if len(group) > 0 && (size+segment.getSegmentSize() > maxSize || len(group) >= maxNum) {
+				groups = append(groups, group)
+				group, size = nil, 0
+			}
+			group = append(group, segment)
+			size += segment.getSegmentSize()
+		}
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// compact submits a mix compaction of the segments, or a clustering compaction if @clusteringField is not nil,
+// it is of the manual priority.
+func (m *majorCompactionManager) compact(job *majorCompactionJob, segments []*SegmentInfo, ttl time.Duration,
+	clusteringField *schemapb.FieldSchema,
+) (UniqueID, error) {
+	plan := segmentsToPlan(segments, &compactTime{collectionTTL: ttl})
+	if clusteringField != nil {
+		// the rows of a result segment are estimated by the average row size of the segments
+		var size int64
+		for _, segment := range segments {
+			size += segment.getSegmentSize()
+		}
+		plan.Type = datapb.CompactionType_ClusteringCompaction
+		plan.ClusteringFieldID = clusteringField.GetFieldID()
+		plan.MaxSegmentRows = plan.GetTotalRows()
+		if size > job.GetTargetSize() {
+			plan.MaxSegmentRows = lo.Max([]int64{1, int64(float64(plan.GetTotalRows()) * float64(job.GetTargetSize()) / float64(size))})
+		}
+	}
+	if err := fillOriginPlan(m.allocator, plan); err != nil {
+		return 0, err
+	}
+	signal := &compactionSignal{
+		id:           job.GetJobID(),
+		isForce:      true,
+		collectionID: job.GetCollectionID(),
+		partitionID:  job.GetPartitionID(),
+		channel:      plan.GetChannel(),
+	}
+	if err := m.compactionHandler.execCompactionPlan(signal, plan); err != nil {
+		return 0, err
+	}
+	return plan.GetPlanID(), nil
+}
+
+// getClusteringField returns the clustering field of the collection, nil if the collection is not clustered
+// or the clustering compaction is disabled. The clustering compaction results in multiple segments,
+// the deletes of which are routed by the level zero segments only.
+func getClusteringField(coll *collectionInfo) *schemapb.FieldSchema {
+	if !Params.DataCoordCfg.ClusteringCompactionEnabled.GetAsBool() || !Params.DataCoordCfg.EnableLevelZeroSegment.GetAsBool() {
+		return nil
+	}
+	name, ok := coll.Properties[common.CollectionClusteringFieldKey]
+	if !ok || name == "" {
+		return nil
+	}
+	field, ok := lo.Find(coll.Schema.GetFields(), func(field *schemapb.FieldSchema) bool { return field.GetName() == name })
+	if !ok {
+		return nil
+	}
+	return field
+}
@@ -0,0 +1,332 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type MajorCompactionManagerSuite struct {
+	suite.Suite
+
+	meta       *meta
+	handler    *NMockHandler
+	compaction *MockCompactionPlanContext
+	manager    *majorCompactionManager
+}
+
+func (s *MajorCompactionManagerSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (s *MajorCompactionManagerSuite) SetupTest() {
+	var err error
+	s.meta, err = newMemoryMeta()
+	s.Require().NoError(err)
+	s.handler = NewNMockHandler(s.T())
+	s.compaction = NewMockCompactionPlanContext(s.T())
+	s.manager = newMajorCompactionManager(context.TODO(), s.meta, s.handler, newMockAllocator(), s.compaction)
+}
+
+func (s *MajorCompactionManagerSuite) addSegment(segmentID int64, channel string, size int64, deltalogs bool) {
+	segment := &datapb.SegmentInfo{
+		ID:            segmentID,
+		CollectionID:  1,
+		PartitionID:   10,
+		InsertChannel: channel,
+		NumOfRows:     100,
+		State:         commonpb.SegmentState_Flushed,
+		Level:         datapb.SegmentLevel_L1,
+		Binlogs: []*datapb.FieldBinlog{
+			{FieldID: 100, Binlogs: []*datapb.Binlog{{EntriesNum: 100, LogID: segmentID, LogSize: size}}},
+		},
+	}
+	if deltalogs {
+		segment.Deltalogs = []*datapb.FieldBinlog{
+			{FieldID: 100, Binlogs: []*datapb.Binlog{{EntriesNum: 10, LogID: segmentID + 1000, LogSize: 1}}},
+		}
+	}
+	s.Require().NoError(s.meta.AddSegment(context.TODO(), NewSegmentInfo(segment)))
+}
+
+// expectCompactions expects the compactions submitted, and returns the segments of them by plan ID.
+func (s *MajorCompactionManagerSuite) expectCompactions(times int) map[int64][]int64 {
+	plans := make(map[int64][]int64)
+	s.compaction.EXPECT().isFull().Return(false)
+	s.handler.EXPECT().GetCollection(mock.Anything, int64(1)).Return(&collectionInfo{ID: 1}, nil).Once()
+	s.compaction.EXPECT().execCompactionPlan(mock.Anything, mock.Anything).
+		RunAndReturn(func(signal *compactionSignal, plan *datapb.CompactionPlan) error {
+			s.True(signal.isForce)
+			s.Equal(datapb.CompactionType_MixCompaction, plan.GetType())
+			plans[plan.GetPlanID()] = lo.Map(plan.GetSegmentBinlogs(), func(binlogs *datapb.CompactionSegmentBinlogs, _ int) int64 {
+				return binlogs.GetSegmentID()
+			})
+			return nil
+		}).Times(times)
+	return plans
+}
+
+func (s *MajorCompactionManagerSuite) TestCompact() {
+	paramtable.Get().Save(Params.DataCoordCfg.MajorCompactionMaxConcurrency.Key, "2")
+	defer paramtable.Get().Reset(Params.DataCoordCfg.MajorCompactionMaxConcurrency.Key)
+
+	s.addSegment(100, "ch-1", 40, false)
+	s.addSegment(101, "ch-1", 40, false)
+	s.addSegment(102, "ch-1", 40, false)
+	s.addSegment(103, "ch-2", 100, false)
+	s.addSegment(104, "ch-2", 100, true)
+	s.Require().NoError(s.manager.submit(&datapb.MajorCompactionJob{JobID: 1, CollectionID: 1, PartitionID: 10, TargetSize: 100}))
+	job := s.manager.getJob(1)
+	s.Equal(datapb.MajorCompactionState_MajorCompactionRunning, job.GetState())
+	s.EqualValues(5, job.GetTotalSegments())
+
+	// only one job runs on a partition
+	s.Error(s.manager.submit(&datapb.MajorCompactionJob{JobID: 2, CollectionID: 1, PartitionID: 10}))
+
+	// 102 left alone and 103 of the target size have nothing to merge or to reclaim
+	plans := s.expectCompactions(2)
+	s.manager.check()
+	s.ElementsMatch([][]int64{{100, 101}, {104}}, lo.Values(plans))
+	job = s.manager.getJob(1)
+	s.EqualValues(2, job.GetExecutingPlans())
+	s.EqualValues(2, job.GetCompactedSegments())
+
+	// the merge completes, the compaction of 104 fails and is submitted again
+	for planID, segmentIDs := range plans {
+		if len(segmentIDs) == 2 {
+			s.compaction.EXPECT().getCompaction(planID).Return(&compactionTask{state: completed}).Once()
+			for _, segmentID := range segmentIDs {
+				s.Require().NoError(s.meta.SetState(segmentID, commonpb.SegmentState_Dropped, "test"))
+			}
+		} else {
+			s.compaction.EXPECT().getCompaction(planID).Return(&compactionTask{state: failed}).Once()
+		}
+	}
+	plans = s.expectCompactions(1)
+	s.manager.check()
+	s.ElementsMatch([][]int64{{104}}, lo.Values(plans))
+	job = s.manager.getJob(1)
+	s.EqualValues(1, job.GetCompletedPlans())
+	s.EqualValues(1, job.GetFailedPlans())
+	s.EqualValues(4, job.GetCompactedSegments())
+
+	for planID := range plans {
+		s.compaction.EXPECT().getCompaction(planID).Return(&compactionTask{state: completed}).Once()
+	}
+	s.manager.check()
+	job = s.manager.getJob(1)
+	s.Equal(datapb.MajorCompactionState_MajorCompactionCompleted, job.GetState())
+	s.EqualValues(5, job.GetCompactedSegments())
+	s.EqualValues(2, job.GetCompletedPlans())
+	s.EqualValues(0, job.GetExecutingPlans())
+
+	// a new job could run on the partition after the last one completes
+	s.NoError(s.manager.submit(&datapb.MajorCompactionJob{JobID: 2, CollectionID: 1, PartitionID: 10}))
+}
+
+func (s *MajorCompactionManagerSuite) TestPauseResume() {
+	s.addSegment(100, "ch-1", 40, true)
+	s.Require().NoError(s.manager.submit(&datapb.MajorCompactionJob{JobID: 1, CollectionID: 1, PartitionID: 10}))
+
+	s.NoError(s.manager.alter(1, datapb.MajorCompactionCommand_PauseMajorCompaction))
+	s.manager.check()
+	s.Equal(datapb.MajorCompactionState_MajorCompactionPaused, s.manager.getJob(1).GetState())
+
+	s.NoError(s.manager.alter(1, datapb.MajorCompactionCommand_ResumeMajorCompaction))
+	plans := s.expectCompactions(1)
+	s.manager.check()
+	s.Len(plans, 1)
+
+	for planID := range plans {
+		s.compaction.EXPECT().getCompaction(planID).Return(&compactionTask{state: completed}).Once()
+	}
+	s.manager.check()
+	s.Equal(datapb.MajorCompactionState_MajorCompactionCompleted, s.manager.getJob(1).GetState())
+
+	s.Error(s.manager.alter(1, datapb.MajorCompactionCommand_PauseMajorCompaction))
+	s.Error(s.manager.alter(2, datapb.MajorCompactionCommand_PauseMajorCompaction))
+}
+
+func (s *MajorCompactionManagerSuite) TestThrottle() {
+	s.addSegment(100, "ch-1", 40, true)
+	s.Require().NoError(s.manager.submit(&datapb.MajorCompactionJob{JobID: 1, CollectionID: 1, PartitionID: 10}))
+
+	s.compaction.EXPECT().isFull().Return(true).Once()
+	s.manager.check()
+	job := s.manager.getJob(1)
+	s.Equal(datapb.MajorCompactionState_MajorCompactionRunning, job.GetState())
+	s.EqualValues(0, job.GetExecutingPlans())
+}
+
+func (s *MajorCompactionManagerSuite) TestFail() {
+	s.addSegment(100, "ch-1", 40, true)
+	s.Require().NoError(s.manager.submit(&datapb.MajorCompactionJob{JobID: 1, CollectionID: 1, PartitionID: 10}))
+
+	for i := 0; i <= majorCompactionMaxFailures; i++ {
+		plans := s.expectCompactions(1)
+		s.manager.check()
+		for planID := range plans {
+			s.compaction.EXPECT().getCompaction(planID).Return(&compactionTask{state: failed}).Once()
+		}
+	}
+	s.manager.check()
+	job := s.manager.getJob(1)
+	s.Equal(datapb.MajorCompactionState_MajorCompactionFailed, job.GetState())
+	s.NotEmpty(job.GetReason())
+
+	// the job fails if the collection is dropped
+	s.addSegment(200, "ch-1", 40, true)
+	s.Require().NoError(s.manager.submit(&datapb.MajorCompactionJob{JobID: 2, CollectionID: 1, PartitionID: 10}))
+	s.handler.EXPECT().GetCollection(mock.Anything, int64(1)).Return(nil, nil).Once()
+	s.manager.check()
+	s.Equal(datapb.MajorCompactionState_MajorCompactionFailed, s.manager.getJob(2).GetState())
+}
+
+func (s *MajorCompactionManagerSuite) TestClustering() {
+	paramtable.Get().Save(Params.DataCoordCfg.EnableLevelZeroSegment.Key, "true")
+	defer paramtable.Get().Reset(Params.DataCoordCfg.EnableLevelZeroSegment.Key)
+
+	s.addSegment(100, "ch-1", 40, false)
+	s.addSegment(101, "ch-1", 40, false)
+	s.addSegment(102, "ch-1", 40, false)
+	s.addSegment(103, "ch-1", 40, false)
+	s.Require().NoError(s.manager.submit(&datapb.MajorCompactionJob{JobID: 1, CollectionID: 1, PartitionID: 10, TargetSize: 100}))
+
+	// the segments within the max input size are clustered at once
+	s.compaction.EXPECT().isFull().Return(false)
+	s.handler.EXPECT().GetCollection(mock.Anything, int64(1)).Return(&collectionInfo{
+		ID: 1,
+		Schema: &schemapb.CollectionSchema{Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "tenant_id", DataType: schemapb.DataType_VarChar},
+		}},
+		Properties: map[string]string{common.CollectionClusteringFieldKey: "tenant_id"},
+	}, nil).Once()
+	s.compaction.EXPECT().execCompactionPlan(mock.Anything, mock.Anything).
+		RunAndReturn(func(signal *compactionSignal, plan *datapb.CompactionPlan) error {
+			s.Equal(datapb.CompactionType_ClusteringCompaction, plan.GetType())
+			s.EqualValues(101, plan.GetClusteringFieldID())
+			s.EqualValues(400, plan.GetTotalRows())
+			s.EqualValues(250, plan.GetMaxSegmentRows())
+			s.Len(plan.GetSegmentBinlogs(), 4)
+			return nil
+		}).Once()
+	s.manager.check()
+	s.EqualValues(1, s.manager.getJob(1).GetExecutingPlans())
+}
+
+func (s *MajorCompactionManagerSuite) TestGetClusteringField() {
+	paramtable.Get().Save(Params.DataCoordCfg.EnableLevelZeroSegment.Key, "true")
+	defer paramtable.Get().Reset(Params.DataCoordCfg.EnableLevelZeroSegment.Key)
+
+	coll := &collectionInfo{
+		ID: 1,
+		Schema: &schemapb.CollectionSchema{Fields: []*schemapb.FieldSchema{
+			{FieldID: 101, Name: "tenant_id", DataType: schemapb.DataType_Int64},
+		}},
+		Properties: map[string]string{common.CollectionClusteringFieldKey: "tenant_id"},
+	}
+	s.EqualValues(101, getClusteringField(coll).GetFieldID())
+
+	s.Nil(getClusteringField(&collectionInfo{ID: 1, Schema: coll.Schema}))
+	s.Nil(getClusteringField(&collectionInfo{ID: 1, Properties: coll.Properties}))
+
+	paramtable.Get().Save(Params.DataCoordCfg.ClusteringCompactionEnabled.Key, "false")
+	s.Nil(getClusteringField(coll))
+	paramtable.Get().Reset(Params.DataCoordCfg.ClusteringCompactionEnabled.Key)
+
+	// the deletes of the clustered segments are routed by the level zero segments only
+	paramtable.Get().Save(Params.DataCoordCfg.EnableLevelZeroSegment.Key, "false")
+	s.Nil(getClusteringField(coll))
+}
+
+func (s *MajorCompactionManagerSuite) TestServices() {
+	svr := &Server{
+		handler:                s.handler,
+		allocator:              newMockAllocator(),
+		majorCompactionManager: s.manager,
+	}
+	svr.stateCode.Store(commonpb.StateCode_Healthy)
+	s.handler.EXPECT().GetCollection(mock.Anything, int64(1)).Return(&collectionInfo{ID: 1, Partitions: []int64{10}}, nil)
+	s.handler.EXPECT().GetCollection(mock.Anything, int64(2)).Return(nil, nil)
+
+	cases := []struct {
+		tag string
+		req *datapb.MajorCompactionRequest
+	}{
+		{"negative_target_size", &datapb.MajorCompactionRequest{CollectionID: 1, PartitionID: 10, TargetSize: -1}},
+		{"collection_not_found", &datapb.MajorCompactionRequest{CollectionID: 2, PartitionID: 10}},
+		{"partition_not_found", &datapb.MajorCompactionRequest{CollectionID: 1, PartitionID: 11}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.tag, func() {
+			resp, err := svr.MajorCompaction(context.TODO(), tc.req)
+			s.NoError(err)
+			s.False(merr.Ok(resp.GetStatus()))
+		})
+	}
+
+	resp, err := svr.MajorCompaction(context.TODO(), &datapb.MajorCompactionRequest{CollectionID: 1, PartitionID: 10})
+	s.NoError(merr.CheckRPCCall(resp, err))
+	jobID := resp.GetJobID()
+
+	stateResp, err := svr.GetMajorCompactionState(context.TODO(), &datapb.GetMajorCompactionStateRequest{JobID: jobID})
+	s.NoError(merr.CheckRPCCall(stateResp, err))
+	s.Equal(datapb.MajorCompactionState_MajorCompactionRunning, stateResp.GetJob().GetState())
+	s.Equal(Params.DataCoordCfg.SegmentMaxSize.GetAsInt64()*1024*1024, stateResp.GetJob().GetTargetSize())
+
+	status, err := svr.AlterMajorCompaction(context.TODO(), &datapb.AlterMajorCompactionRequest{JobID: jobID, Command: datapb.MajorCompactionCommand_PauseMajorCompaction})
+	s.NoError(merr.CheckRPCCall(status, err))
+	stateResp, err = svr.GetMajorCompactionState(context.TODO(), &datapb.GetMajorCompactionStateRequest{JobID: jobID})
+	s.NoError(merr.CheckRPCCall(stateResp, err))
+	s.Equal(datapb.MajorCompactionState_MajorCompactionPaused, stateResp.GetJob().GetState())
+
+	status, err = svr.AlterMajorCompaction(context.TODO(), &datapb.AlterMajorCompactionRequest{JobID: jobID})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(status), merr.ErrParameterInvalid)
+
+	stateResp, err = svr.GetMajorCompactionState(context.TODO(), &datapb.GetMajorCompactionStateRequest{JobID: jobID + 1})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(stateResp.GetStatus()), merr.ErrParameterInvalid)
+
+	svr.stateCode.Store(commonpb.StateCode_Abnormal)
+	resp, err = svr.MajorCompaction(context.TODO(), &datapb.MajorCompactionRequest{CollectionID: 1, PartitionID: 10})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrServiceNotReady)
+	stateResp, err = svr.GetMajorCompactionState(context.TODO(), &datapb.GetMajorCompactionStateRequest{JobID: jobID})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(stateResp.GetStatus()), merr.ErrServiceNotReady)
+	status, err = svr.AlterMajorCompaction(context.TODO(), &datapb.AlterMajorCompactionRequest{JobID: jobID})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(status), merr.ErrServiceNotReady)
+}
+
+func TestMajorCompactionManager(t *testing.T) {
+	suite.Run(t, new(MajorCompactionManagerSuite))
+}
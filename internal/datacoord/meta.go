@@ -67,6 +67,9 @@ type meta struct {
 	// buildID2Meta records the meta information of the segment
 	// buildID -> segmentIndex
 	buildID2SegmentIndex map[UniqueID]*model.SegmentIndex
+
+	// eventLog records the lifecycle events of segments, nil means not recording
+	eventLog *segmentEventLog
 }
 
 // A local cache of segment metric update. Must call commit() to take effect.
@@ -186,6 +189,14 @@ func (m *meta) AddCollection(collection *collectionInfo) {
 	log.Info("meta update: add collection - complete", zap.Int64("collectionID", collection.ID))
 }
 
+// resetCollections drops the collection infos cached, they are fetched from RootCoord again when needed
+func (m *meta) resetCollections() {
+	m.Lock()
+	defer m.Unlock()
+	m.collections = make(map[UniqueID]*collectionInfo)
+	metrics.DataCoordNumCollections.WithLabelValues().Set(0)
+}
+
 // GetCollection returns collection info with provided collection id from local cache
 func (m *meta) GetCollection(collectionID UniqueID) *collectionInfo {
 	m.RLock()
@@ -233,6 +244,14 @@ func (m *meta) GetSegmentsChanPart(selector SegmentInfoSelector) []*chanPartSegm
 		cloned := segmentInfo.Clone()
 
 		dim := fmt.Sprintf("%d-%s", cloned.PartitionID, cloned.InsertChannel)
+		// the segments of different time buckets are never compacted together
+		if bucket := cloned.GetTimeBucket(); bucket != nil {
+			dim = fmt.Sprintf("%s-%d", dim, bucket.GetStart())
+		}
+		// neither the segments of different primary key buckets
+		if bucket := cloned.GetPkBucket(); bucket != nil {
+			dim = fmt.Sprintf("%s-pk%d", dim, bucket.GetIndex())
+		}
 		entry, ok := mDimEntry[dim]
 		if !ok {
 			entry = &chanPartSegments{
@@ -272,6 +291,7 @@ func (m *meta) GetCollectionBinlogSize() (int64, map[UniqueID]int64) {
 	defer m.RUnlock()
 	collectionBinlogSize := make(map[UniqueID]int64)
 	collectionRowsNum := make(map[UniqueID]map[commonpb.SegmentState]int64)
+	collectionDeleteStats := make(map[UniqueID]*datapb.SegmentDeleteStats)
 	segments := m.segments.GetSegments()
 	var total int64
 	for _, segment := range segments {
@@ -281,6 +301,20 @@ func (m *meta) GetCollectionBinlogSize() (int64, map[UniqueID]int64) {
 			collectionBinlogSize[segment.GetCollectionID()] += segmentSize
 			metrics.DataCoordStoredBinlogSize.WithLabelValues(
 				fmt.Sprint(segment.GetCollectionID()), fmt.Sprint(segment.GetID())).Set(float64(segmentSize))
+			deleteStats := segment.getDeleteStats()
+			metrics.DataCoordSegmentDeletedRowRatio.WithLabelValues(
+				fmt.Sprint(segment.GetCollectionID()), fmt.Sprint(segment.GetID())).Set(deleteStats.GetDeletedRowRatio())
+			metrics.DataCoordSegmentDeltalogSizeRatio.WithLabelValues(
+				fmt.Sprint(segment.GetCollectionID()), fmt.Sprint(segment.GetID())).Set(deleteStats.GetDeltalogSizeRatio())
+			collectionStats, ok := collectionDeleteStats[segment.GetCollectionID()]
+			if !ok {
+				collectionStats = &datapb.SegmentDeleteStats{}
+				collectionDeleteStats[segment.GetCollectionID()] = collectionStats
+			}
+			collectionStats.NumRows += deleteStats.GetNumRows()
+			collectionStats.DeletedRows += deleteStats.GetDeletedRows()
+			collectionStats.BinlogSize += deleteStats.GetBinlogSize()
+			collectionStats.DeltalogSize += deleteStats.GetDeltalogSize()
 			if _, ok := collectionRowsNum[segment.GetCollectionID()]; !ok {
 				collectionRowsNum[segment.GetCollectionID()] = make(map[commonpb.SegmentState]int64)
 			}
@@ -292,6 +326,11 @@ func (m *meta) GetCollectionBinlogSize() (int64, map[UniqueID]int64) {
 			metrics.DataCoordNumStoredRows.WithLabelValues(fmt.Sprint(collection), state.String()).Set(float64(rows))
 		}
 	}
+	for collection, stats := range collectionDeleteStats {
+		calcDeleteRatios(stats)
+		metrics.DataCoordCollectionDeletedRowRatio.WithLabelValues(fmt.Sprint(collection)).Set(stats.GetDeletedRowRatio())
+		metrics.DataCoordCollectionDeltalogSizeRatio.WithLabelValues(fmt.Sprint(collection)).Set(stats.GetDeltalogSizeRatio())
+	}
 	return total, collectionBinlogSize
 }
 
@@ -308,12 +347,44 @@ func (m *meta) AddSegment(ctx context.Context, segment *SegmentInfo) error {
 		return err
 	}
 	m.segments.SetSegment(segment.GetID(), segment)
+	m.eventLog.record(newSegmentStateEvent(segment, commonpb.SegmentState_SegmentStateNone, segmentEventActorDataCoord, "segment added"))
 
 	metrics.DataCoordNumSegments.WithLabelValues(segment.GetState().String(), segment.GetLevel().String()).Inc()
 	log.Info("meta update: adding segment - complete", zap.Int64("segmentID", segment.GetID()))
 	return nil
 }
 
+// AddSegments records the new segments along with their binlogs in one meta update,
+// the segments are visible all at once, or none of them if the update failed.
+func (m *meta) AddSegments(ctx context.Context, segments ...*SegmentInfo) error {
+	log := log.Ctx(ctx)
+	segmentIDs := lo.Map(segments, func(segment *SegmentInfo, _ int) int64 { return segment.GetID() })
+	log.Info("meta update: adding segments - Start", zap.Int64s("segmentIDs", segmentIDs))
+	m.Lock()
+	defer m.Unlock()
+	infos := make([]*datapb.SegmentInfo, 0, len(segments))
+	increments := make([]metastore.BinlogsIncrement, 0, len(segments))
+	for _, segment := range segments {
+		infos = append(infos, segment.SegmentInfo)
+		increments = append(increments, metastore.BinlogsIncrement{Segment: segment.SegmentInfo})
+	}
+	if err := m.catalog.AlterSegments(m.ctx, infos, increments...); err != nil {
+		log.Error("meta update: adding segments failed",
+			zap.Int64s("segmentIDs", segmentIDs),
+			zap.Error(err))
+		return err
+	}
+	events := make([]*datapb.SegmentEvent, 0, len(segments))
+	for _, segment := range segments {
+		m.segments.SetSegment(segment.GetID(), segment)
+		events = append(events, newSegmentStateEvent(segment, commonpb.SegmentState_SegmentStateNone, segmentEventActorDataCoord, "segment added"))
+		metrics.DataCoordNumSegments.WithLabelValues(segment.GetState().String(), segment.GetLevel().String()).Inc()
+	}
+	m.eventLog.record(events...)
+	log.Info("meta update: adding segments - complete", zap.Int64s("segmentIDs", segmentIDs))
+	return nil
+}
+
 // DropSegment remove segment with provided id, etcd persistence also removed
 func (m *meta) DropSegment(segmentID UniqueID) error {
 	log.Debug("meta update: dropping segment", zap.Int64("segmentID", segmentID))
@@ -333,6 +404,9 @@ func (m *meta) DropSegment(segmentID UniqueID) error {
 	}
 	metrics.DataCoordNumSegments.WithLabelValues(segment.GetState().String(), segment.GetLevel().String()).Dec()
 	m.segments.DropSegment(segmentID)
+	event := newSegmentStateEvent(segment, segment.GetState(), segmentEventActorGC, "dropped segment recycled")
+	event.Type = datapb.SegmentEventType_SegmentRecycled
+	m.eventLog.record(event)
 	log.Info("meta update: dropping segment - complete",
 		zap.Int64("segmentID", segmentID))
 	return nil
@@ -366,13 +440,32 @@ func (m *meta) GetAllSegmentsUnsafe() []*SegmentInfo {
 	return m.segments.GetSegments()
 }
 
-// SetState setting segment with provided ID state
-func (m *meta) SetState(segmentID UniqueID, targetState commonpb.SegmentState) error {
+// SetState setting segment with provided ID state, the reason is recorded in the segment event
+func (m *meta) SetState(segmentID UniqueID, targetState commonpb.SegmentState, reason string) error {
 	log.Debug("meta update: setting segment state",
 		zap.Int64("segmentID", segmentID),
 		zap.Any("target state", targetState))
 	m.Lock()
 	defer m.Unlock()
+	return m.setState(segmentID, targetState, reason)
+}
+
+// SetStateIf sets the state of the segment if @condition accepts it, the condition is checked under the meta lock,
+// so the flags set under the lock like isCompacting can't change before the state is set.
+// Returns whether the condition is accepted.
+func (m *meta) SetStateIf(segmentID UniqueID, targetState commonpb.SegmentState, reason string,
+	condition func(segment *SegmentInfo) bool,
+) (bool, error) {
+	m.Lock()
+	defer m.Unlock()
+	segment := m.segments.GetSegment(segmentID)
+	if segment == nil || !condition(segment) {
+		return false, nil
+	}
+	return true, m.setState(segmentID, targetState, reason)
+}
+
+func (m *meta) setState(segmentID UniqueID, targetState commonpb.SegmentState, reason string) error {
 	curSegInfo := m.segments.GetSegment(segmentID)
 	if curSegInfo == nil {
 		log.Warn("meta update: setting segment state - segment not found",
@@ -403,6 +496,9 @@ func (m *meta) SetState(segmentID UniqueID, targetState commonpb.SegmentState) e
 		metricMutation.commit()
 		// Update in-memory meta.
 		m.segments.SetState(segmentID, targetState)
+		if curSegInfo.GetState() != targetState {
+			m.eventLog.record(newSegmentStateEvent(clonedSegment, curSegInfo.GetState(), segmentEventActorDataCoord, reason))
+		}
 	}
 	log.Info("meta update: setting segment state - complete",
 		zap.Int64("segmentID", segmentID),
@@ -445,6 +541,9 @@ type updateSegmentPack struct {
 	increments map[int64]metastore.BinlogsIncrement
 	// for update segment metric after alter segments
 	metricMutation *segMetricMutation
+	// actor and reason of the segment events
+	eventActor  string
+	eventReason string
 }
 
 func (p *updateSegmentPack) Get(segmentID int64) *SegmentInfo {
@@ -467,6 +566,15 @@ func (p *updateSegmentPack) Get(segmentID int64) *SegmentInfo {
 
 type UpdateOperator func(*updateSegmentPack) bool
 
+// SegmentEventOperator sets the actor and reason of the events of the segments changing state.
+func SegmentEventOperator(actor, reason string) UpdateOperator {
+	return func(modPack *updateSegmentPack) bool {
+		modPack.eventActor = actor
+		modPack.eventReason = reason
+		return true
+	}
+}
+
 func CreateL0Operator(collectionID, partitionID, segmentID int64, channel string) UpdateOperator {
 	return func(modPack *updateSegmentPack) bool {
 		segment := modPack.meta.segments.GetSegment(segmentID)
@@ -560,6 +668,25 @@ func UpdateBinlogsOperator(segmentID int64, binlogs, statslogs, deltalogs []*dat
 	}
 }
 
+// UpdateStatslogsOperator replaces the statslogs of the segment rather than merges, for the statslogs
+// rewritten from the insert binlogs of the segment.
+func UpdateStatslogsOperator(segmentID int64, statslogs []*datapb.FieldBinlog) UpdateOperator {
+	return func(modPack *updateSegmentPack) bool {
+		segment := modPack.Get(segmentID)
+		if segment == nil {
+			log.Warn("meta update: update statslogs failed - segment not found",
+				zap.Int64("segmentID", segmentID))
+			return false
+		}
+
+		segment.Statslogs = statslogs
+		modPack.increments[segmentID] = metastore.BinlogsIncrement{
+			Segment: segment.SegmentInfo,
+		}
+		return true
+	}
+}
+
 // update startPosition
 func UpdateStartPosition(startPositions []*datapb.SegmentStartPosition) UpdateOperator {
 	return func(modPack *updateSegmentPack) bool {
@@ -634,6 +761,7 @@ func (m *meta) UpdateSegmentsInfo(operators ...UpdateOperator) error {
 		metricMutation: &segMetricMutation{
 			stateChange: make(map[string]map[string]int),
 		},
+		eventActor: segmentEventActorDataCoord,
 	}
 
 	for _, operator := range operators {
@@ -654,9 +782,15 @@ func (m *meta) UpdateSegmentsInfo(operators ...UpdateOperator) error {
 	// Apply metric mutation after a successful meta update.
 	updatePack.metricMutation.commit()
 	// update memory status
+	var events []*datapb.SegmentEvent
 	for id, s := range updatePack.segments {
+		fromState := getSegmentState(m.segments.GetSegment(id))
+		if fromState != s.GetState() {
+			events = append(events, newSegmentStateEvent(s, fromState, updatePack.eventActor, updatePack.eventReason))
+		}
 		m.segments.SetSegment(id, s)
 	}
+	m.eventLog.record(events...)
 	log.Info("meta update: update flush segments info - update flush segments info successfully")
 	return nil
 }
@@ -695,6 +829,13 @@ func (m *meta) UpdateDropChannelSegmentInfo(channel string, segments []*SegmentI
 			modSegments[seg.ID] = clonedSeg
 		}
 	}
+	events := make([]*datapb.SegmentEvent, 0, len(modSegments))
+	for id, segment := range modSegments {
+		fromState := getSegmentState(m.segments.GetSegment(id))
+		if fromState != segment.GetState() {
+			events = append(events, newSegmentStateEvent(segment, fromState, segmentEventActorDataCoord, fmt.Sprintf("channel %s dropped", channel)))
+		}
+	}
 	err := m.batchSaveDropSegments(channel, modSegments)
 	if err != nil {
 		log.Warn("meta update: update drop channel segment info failed",
@@ -705,6 +846,7 @@ func (m *meta) UpdateDropChannelSegmentInfo(channel string, segments []*SegmentI
 			zap.String("channel", channel))
 		// Apply segment metric mutation on successful meta update.
 		metricMutation.commit()
+		m.eventLog.record(events...)
 	}
 	return err
 }
@@ -990,11 +1132,20 @@ func (m *meta) CompleteCompactionMutation(plan *datapb.CompactionPlan,
 		return nil, nil, err
 	}
 
+	newSegIDs := lo.Map(segments, func(segment *SegmentInfo, _ int) int64 { return segment.GetID() })
+	events := lo.Map(modSegments, func(segment *SegmentInfo, _ int) *datapb.SegmentEvent {
+		return newSegmentStateEvent(segment, getSegmentState(m.segments.GetSegment(segment.GetID())), segmentEventActorCompaction,
+			fmt.Sprintf("compacted into segments %v by plan %d", newSegIDs, plan.GetPlanID()))
+	})
 	if err := m.alterMetaStoreAfterCompaction(segments, modSegments); err != nil {
-		newSegIDs := lo.Map(segments, func(segment *SegmentInfo, _ int) int64 { return segment.GetID() })
 		log.Warn("fail to alert meta store", zap.Error(err), zap.Int64s("segmentIDs", newSegIDs), zap.Int64("planID", plan.GetPlanID()))
 		return nil, nil, err
 	}
+	for _, segment := range segments {
+		events = append(events, newSegmentStateEvent(segment, commonpb.SegmentState_SegmentStateNone, segmentEventActorCompaction,
+			fmt.Sprintf("compacted from segments %v by plan %d", segment.GetCompactionFrom(), plan.GetPlanID())))
+	}
+	m.eventLog.record(events...)
 	return segments, metricMutation, err
 }
 
@@ -1054,55 +1205,63 @@ func (m *meta) prepareCompactionMutation(plan *datapb.CompactionPlan,
 		deletedDeltalogs = append(deletedDeltalogs, l.GetDeltalogs()...)
 	}
 
-	// MixCompaction / MergeCompaction will generates one and only one segment
-	compactToSegment := result.GetSegments()[0]
-
 	newAddedDeltalogs := updateDeltalogs(originDeltalogs, deletedDeltalogs)
-	copiedDeltalogs, err := m.copyDeltaFiles(newAddedDeltalogs, modSegments[0].CollectionID, modSegments[0].PartitionID, compactToSegment.GetSegmentID())
-	if err != nil {
-		return nil, nil, nil, err
-	}
-	deltalogs := append(compactToSegment.GetDeltalogs(), copiedDeltalogs...)
 
 	compactionFrom := make([]UniqueID, 0, len(modSegments))
 	for _, s := range modSegments {
 		compactionFrom = append(compactionFrom, s.GetID())
 	}
 
-	segmentInfo := &datapb.SegmentInfo{
-		ID:                  compactToSegment.GetSegmentID(),
-		CollectionID:        modSegments[0].CollectionID,
-		PartitionID:         modSegments[0].PartitionID,
-		InsertChannel:       modSegments[0].InsertChannel,
-		NumOfRows:           compactToSegment.NumOfRows,
-		State:               commonpb.SegmentState_Flushed,
-		MaxRowNum:           modSegments[0].MaxRowNum,
-		Binlogs:             compactToSegment.GetInsertLogs(),
-		Statslogs:           compactToSegment.GetField2StatslogPaths(),
-		Deltalogs:           deltalogs,
-		StartPosition:       startPosition,
-		DmlPosition:         dmlPosition,
-		CreatedByCompaction: true,
-		CompactionFrom:      compactionFrom,
-		LastExpireTime:      plan.GetStartTime(),
-		Level:               datapb.SegmentLevel_L1,
-	}
-	segment := NewSegmentInfo(segmentInfo)
+	// MixCompaction / MergeCompaction will generates one and only one segment,
+	// ClusteringCompaction generates one or more segments, the new added deltalogs are copied to each of them
+	segments := make([]*SegmentInfo, 0, len(result.GetSegments()))
+	for _, compactToSegment := range result.GetSegments() {
+		copiedDeltalogs, err := m.copyDeltaFiles(newAddedDeltalogs, modSegments[0].CollectionID, modSegments[0].PartitionID, compactToSegment.GetSegmentID())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		deltalogs := append(compactToSegment.GetDeltalogs(), copiedDeltalogs...)
+
+		segmentInfo := &datapb.SegmentInfo{
+			ID:                  compactToSegment.GetSegmentID(),
+			CollectionID:        modSegments[0].CollectionID,
+			PartitionID:         modSegments[0].PartitionID,
+			InsertChannel:       modSegments[0].InsertChannel,
+			NumOfRows:           compactToSegment.NumOfRows,
+			State:               commonpb.SegmentState_Flushed,
+			MaxRowNum:           modSegments[0].MaxRowNum,
+			Binlogs:             compactToSegment.GetInsertLogs(),
+			Statslogs:           compactToSegment.GetField2StatslogPaths(),
+			Deltalogs:           deltalogs,
+			StartPosition:       startPosition,
+			DmlPosition:         dmlPosition,
+			CreatedByCompaction: true,
+			CompactionFrom:      compactionFrom,
+			LastExpireTime:      plan.GetStartTime(),
+			Level:               datapb.SegmentLevel_L1,
+			// the segments of a plan are in the same time bucket and primary key bucket
+			TimeBucket: modSegments[0].GetTimeBucket(),
+			PkBucket:   modSegments[0].GetPkBucket(),
+			IsSorted:   compactToSegment.GetIsSorted(),
+		}
+		segment := NewSegmentInfo(segmentInfo)
 
-	// L1 segment with NumRows=0 will be discarded, so no need to change the metric
-	if segmentInfo.GetNumOfRows() > 0 {
-		metricMutation.addNewSeg(segment.GetState(), segment.GetLevel(), segment.GetNumOfRows())
-	}
+		// L1 segment with NumRows=0 will be discarded, so no need to change the metric
+		if segmentInfo.GetNumOfRows() > 0 {
+			metricMutation.addNewSeg(segment.GetState(), segment.GetLevel(), segment.GetNumOfRows())
+		}
 
-	log.Info("meta update: prepare for complete compaction mutation - complete",
-		zap.Int64("collectionID", segment.GetCollectionID()),
-		zap.Int64("partitionID", segment.GetPartitionID()),
-		zap.Int64("new segment ID", segment.GetID()),
-		zap.String("new segment level", segment.GetLevel().String()),
-		zap.Int64("new segment num of rows", segment.GetNumOfRows()),
-		zap.Any("compacted from", segment.GetCompactionFrom()))
+		log.Info("meta update: prepare for complete compaction mutation - complete",
+			zap.Int64("collectionID", segment.GetCollectionID()),
+			zap.Int64("partitionID", segment.GetPartitionID()),
+			zap.Int64("new segment ID", segment.GetID()),
+			zap.String("new segment level", segment.GetLevel().String()),
+			zap.Int64("new segment num of rows", segment.GetNumOfRows()),
+			zap.Any("compacted from", segment.GetCompactionFrom()))
+		segments = append(segments, segment)
+	}
 
-	return modSegments, []*SegmentInfo{segment}, metricMutation, nil
+	return modSegments, segments, metricMutation, nil
 }
 
 func (m *meta) copyDeltaFiles(binlogs []*datapb.FieldBinlog, collectionID, partitionID, targetSegmentID int64) ([]*datapb.FieldBinlog, error) {
@@ -1111,7 +1270,7 @@ func (m *meta) copyDeltaFiles(binlogs []*datapb.FieldBinlog, collectionID, parti
 		fieldBinlog = proto.Clone(fieldBinlog).(*datapb.FieldBinlog)
 		for _, binlog := range fieldBinlog.Binlogs {
 			blobKey := metautil.JoinIDPath(collectionID, partitionID, targetSegmentID, binlog.LogID)
-			blobPath := path.Join(m.chunkManager.RootPath(), common.SegmentDeltaLogPath, blobKey)
+			blobPath := path.Join(storage.LayoutRootPath(m.chunkManager.RootPath(), collectionID), common.SegmentDeltaLogPath, blobKey)
 			blob, err := m.chunkManager.Read(m.ctx, binlog.LogPath)
 			if err != nil {
 				return nil, err
@@ -1284,6 +1443,16 @@ func (m *meta) GetChannelCheckpoint(vChannel string) *msgpb.MsgPosition {
 	return proto.Clone(v).(*msgpb.MsgPosition)
 }
 
+// GetChannelCheckpoints returns a copy of the checkpoints of all channels.
+func (m *meta) GetChannelCheckpoints() map[string]*msgpb.MsgPosition {
+	checkpoints := make(map[string]*msgpb.MsgPosition)
+	m.channelCPs.Range(func(vChannel string, pos *msgpb.MsgPosition) bool {
+		checkpoints[vChannel] = proto.Clone(pos).(*msgpb.MsgPosition)
+		return true
+	})
+	return checkpoints
+}
+
 func (m *meta) DropChannelCheckpoint(vChannel string) error {
 	m.channelCPLocks.Lock(vChannel)
 	defer m.channelCPLocks.Unlock(vChannel)
@@ -253,6 +253,7 @@ func (suite *MetaBasicSuite) TestPrepareCompleteCompactionMutation() {
 		Field2StatslogPaths: []*datapb.FieldBinlog{getFieldBinlogIDs(1, 5)},
 		Deltalogs:           []*datapb.FieldBinlog{getFieldBinlogIDs(0, 5)},
 		NumOfRows:           2,
+		IsSorted:            true,
 	}
 	inCompactionResult := &datapb.CompactionPlanResult{
 		Segments: []*datapb.CompactionSegment{inSegment},
@@ -283,6 +284,7 @@ func (suite *MetaBasicSuite) TestPrepareCompleteCompactionMutation() {
 	suite.EqualValues(inSegment.GetDeltalogs(), newSegment[0].GetDeltalogs())
 	suite.NotZero(newSegment[0].lastFlushTime)
 	suite.Equal(uint64(15), newSegment[0].GetLastExpireTime())
+	suite.True(newSegment[0].GetIsSorted())
 
 	segmentsDone, metricMutationDone, err := m.CompleteCompactionMutation(plan, inCompactionResult)
 	suite.NoError(err)
@@ -290,6 +292,65 @@ func (suite *MetaBasicSuite) TestPrepareCompleteCompactionMutation() {
 	suite.NotNil(metricMutationDone)
 }
 
+func (suite *MetaBasicSuite) TestPrepareClusteringCompactionMutation() {
+	m := &meta{
+		catalog: &datacoord.Catalog{MetaKv: NewMetaMemoryKV()},
+		segments: &SegmentsInfo{
+			map[UniqueID]*SegmentInfo{
+				1: {SegmentInfo: &datapb.SegmentInfo{
+					ID:           1,
+					CollectionID: 100,
+					PartitionID:  10,
+					State:        commonpb.SegmentState_Flushed,
+					Binlogs:      []*datapb.FieldBinlog{getFieldBinlogIDs(1, 1, 2)},
+					NumOfRows:    2,
+				}},
+				2: {SegmentInfo: &datapb.SegmentInfo{
+					ID:           2,
+					CollectionID: 100,
+					PartitionID:  10,
+					State:        commonpb.SegmentState_Flushed,
+					Binlogs:      []*datapb.FieldBinlog{getFieldBinlogIDs(1, 3, 4)},
+					NumOfRows:    2,
+				}},
+			},
+		},
+	}
+
+	plan := &datapb.CompactionPlan{
+		SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{
+			{SegmentID: 1, FieldBinlogs: []*datapb.FieldBinlog{getFieldBinlogIDs(1, 1, 2)}},
+			{SegmentID: 2, FieldBinlogs: []*datapb.FieldBinlog{getFieldBinlogIDs(1, 3, 4)}},
+		},
+		Type:      datapb.CompactionType_ClusteringCompaction,
+		StartTime: 15,
+	}
+	result := &datapb.CompactionPlanResult{
+		Segments: []*datapb.CompactionSegment{
+			{SegmentID: 3, InsertLogs: []*datapb.FieldBinlog{getFieldBinlogIDs(1, 5)}, NumOfRows: 2},
+			{SegmentID: 4, InsertLogs: []*datapb.FieldBinlog{getFieldBinlogIDs(1, 6)}, NumOfRows: 1},
+			{SegmentID: 5, NumOfRows: 0},
+		},
+	}
+	newSegments, metricMutation, err := m.CompleteCompactionMutation(plan, result)
+	suite.NoError(err)
+	suite.Equal(int64(3), metricMutation.rowCountAccChange)
+
+	suite.Require().Len(newSegments, 3)
+	for i, segment := range newSegments {
+		suite.Equal(result.GetSegments()[i].GetSegmentID(), segment.GetID())
+		suite.Equal(result.GetSegments()[i].GetNumOfRows(), segment.GetNumOfRows())
+		suite.ElementsMatch([]int64{1, 2}, segment.GetCompactionFrom())
+		suite.EqualValues(result.GetSegments()[i].GetInsertLogs(), segment.GetBinlogs())
+	}
+	suite.Equal(commonpb.SegmentState_Flushed, m.GetSegment(3).GetState())
+	suite.Equal(commonpb.SegmentState_Flushed, m.GetSegment(4).GetState())
+	// the empty result segment is dropped
+	suite.Equal(commonpb.SegmentState_Dropped, m.GetSegment(5).GetState())
+	suite.Equal(commonpb.SegmentState_Dropped, m.GetSegment(1).GetState())
+	suite.Equal(commonpb.SegmentState_Dropped, m.GetSegment(2).GetState())
+}
+
 func TestMeta(t *testing.T) {
 	suite.Run(t, new(MetaBasicSuite))
 	suite.Run(t, new(MetaReloadSuite))
@@ -372,9 +433,9 @@ func TestMeta_Basic(t *testing.T) {
 		assert.EqualValues(t, 1, len(segIDs))
 		assert.Contains(t, segIDs, segID1_1)
 
-		err = meta.SetState(segID0_0, commonpb.SegmentState_Sealed)
+		err = meta.SetState(segID0_0, commonpb.SegmentState_Sealed, "")
 		assert.NoError(t, err)
-		err = meta.SetState(segID0_0, commonpb.SegmentState_Flushed)
+		err = meta.SetState(segID0_0, commonpb.SegmentState_Flushed, "")
 		assert.NoError(t, err)
 
 		info0_0 = meta.GetHealthySegment(segID0_0)
@@ -626,6 +687,63 @@ func TestUpdateSegmentsInfo(t *testing.T) {
 		assert.Equal(t, updated.NumOfRows, expected.NumOfRows)
 	})
 
+	t.Run("save binlogs again", func(t *testing.T) {
+		meta, err := newMemoryMeta()
+		assert.NoError(t, err)
+
+		segment1 := &SegmentInfo{SegmentInfo: &datapb.SegmentInfo{
+			ID: 1, State: commonpb.SegmentState_Growing,
+			Binlogs: []*datapb.FieldBinlog{getFieldBinlogIDs(1, 1)},
+		}}
+		err = meta.AddSegment(context.TODO(), segment1)
+		assert.NoError(t, err)
+
+		// the binlogs recorded already are not duplicated by the retried request
+		for i := 0; i < 2; i++ {
+			err = meta.UpdateSegmentsInfo(
+				UpdateBinlogsOperator(1,
+					[]*datapb.FieldBinlog{getFieldBinlogIDs(1, 2, 3)},
+					[]*datapb.FieldBinlog{getFieldBinlogIDs(1, 4)},
+					[]*datapb.FieldBinlog{getFieldBinlogIDs(0, 5)},
+				),
+			)
+			assert.NoError(t, err)
+		}
+
+		updated := meta.GetHealthySegment(1)
+		assert.Equal(t, getFieldBinlogIDs(1, 1, 2, 3).GetBinlogs(), updated.GetBinlogs()[0].GetBinlogs())
+		assert.Len(t, updated.GetStatslogs()[0].GetBinlogs(), 1)
+		assert.Len(t, updated.GetDeltalogs()[0].GetBinlogs(), 1)
+	})
+
+	t.Run("replace statslogs", func(t *testing.T) {
+		meta, err := newMemoryMeta()
+		assert.NoError(t, err)
+
+		segment1 := &SegmentInfo{SegmentInfo: &datapb.SegmentInfo{
+			ID: 1, State: commonpb.SegmentState_Flushed,
+			Binlogs:   []*datapb.FieldBinlog{getFieldBinlogIDs(1, 1)},
+			Statslogs: []*datapb.FieldBinlog{getFieldBinlogIDs(1, 2, 3)},
+		}}
+		err = meta.AddSegment(context.TODO(), segment1)
+		assert.NoError(t, err)
+
+		err = meta.UpdateSegmentsInfo(
+			UpdateStatslogsOperator(1, []*datapb.FieldBinlog{getFieldBinlogIDs(1, 4)}),
+		)
+		assert.NoError(t, err)
+
+		updated := meta.GetHealthySegment(1)
+		assert.Equal(t, getFieldBinlogIDs(1, 4).GetBinlogs(), updated.GetStatslogs()[0].GetBinlogs())
+		assert.Equal(t, getFieldBinlogIDs(1, 1).GetBinlogs(), updated.GetBinlogs()[0].GetBinlogs())
+
+		// segment not found
+		err = meta.UpdateSegmentsInfo(
+			UpdateStatslogsOperator(2, []*datapb.FieldBinlog{getFieldBinlogIDs(1, 4)}),
+		)
+		assert.NoError(t, err)
+	})
+
 	t.Run("update compacted segment", func(t *testing.T) {
 		meta, err := newMemoryMeta()
 		assert.NoError(t, err)
@@ -1037,6 +1155,53 @@ func TestChannelCP(t *testing.T) {
 	})
 }
 
+func TestMeta_AddSegments(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		meta, err := newMemoryMeta()
+		assert.NoError(t, err)
+		err = meta.AddSegments(context.TODO(),
+			NewSegmentInfo(&datapb.SegmentInfo{
+				ID: 1, CollectionID: 100, PartitionID: 10, NumOfRows: 1, State: commonpb.SegmentState_Flushed,
+				Binlogs: []*datapb.FieldBinlog{getFieldBinlogIDs(1, 11)},
+			}),
+			NewSegmentInfo(&datapb.SegmentInfo{ID: 2, CollectionID: 100, PartitionID: 10, State: commonpb.SegmentState_Flushed}),
+		)
+		assert.NoError(t, err)
+		assert.Len(t, meta.GetSegmentsOfCollection(100), 2)
+		assert.Len(t, meta.GetSegment(1).GetBinlogs(), 1)
+	})
+
+	t.Run("catalog_failed", func(t *testing.T) {
+		catalog := mocks.NewDataCoordCatalog(t)
+		catalog.EXPECT().AlterSegments(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("mock"))
+		meta := &meta{catalog: catalog, segments: NewSegmentsInfo()}
+		err := meta.AddSegments(context.TODO(),
+			NewSegmentInfo(&datapb.SegmentInfo{ID: 1, CollectionID: 100}),
+			NewSegmentInfo(&datapb.SegmentInfo{ID: 2, CollectionID: 100}),
+		)
+		assert.Error(t, err)
+		assert.Empty(t, meta.GetSegmentsOfCollection(100))
+	})
+}
+
+func TestMeta_GetSegmentsChanPartByBucket(t *testing.T) {
+	meta, err := newMemoryMeta()
+	assert.NoError(t, err)
+	for _, segment := range []*datapb.SegmentInfo{
+		{ID: 1, PartitionID: 10, InsertChannel: "c1", TimeBucket: &datapb.TimeBucket{Start: 0, End: 100}},
+		{ID: 2, PartitionID: 10, InsertChannel: "c1", TimeBucket: &datapb.TimeBucket{Start: 0, End: 100}},
+		{ID: 3, PartitionID: 10, InsertChannel: "c1", TimeBucket: &datapb.TimeBucket{Start: 100, End: 200}},
+		{ID: 4, PartitionID: 10, InsertChannel: "c1"},
+		{ID: 5, PartitionID: 10, InsertChannel: "c1", PkBucket: &datapb.PKBucket{Index: 0, Num: 2}},
+		{ID: 6, PartitionID: 10, InsertChannel: "c1", PkBucket: &datapb.PKBucket{Index: 1, Num: 2}},
+		{ID: 7, PartitionID: 10, InsertChannel: "c1", PkBucket: &datapb.PKBucket{Index: 1, Num: 2}},
+	} {
+		assert.NoError(t, meta.AddSegment(context.TODO(), NewSegmentInfo(segment)))
+	}
+	result := meta.GetSegmentsChanPart(func(*SegmentInfo) bool { return true })
+	assert.ElementsMatch(t, []int{2, 1, 1, 1, 2}, lo.Map(result, func(entry *chanPartSegments, _ int) int { return len(entry.segments) }))
+}
+
 func Test_meta_GcConfirm(t *testing.T) {
 	m := &meta{}
 	catalog := mocks.NewDataCoordCatalog(t)
@@ -285,6 +285,50 @@ func (_c *MockChannelManager_GetCollectionIDByChannel_Call) RunAndReturn(run fun
 	return _c
 }
 
+// GetNodeChannels provides a mock function with given fields: nodeID
+func (_m *MockChannelManager) GetNodeChannels(nodeID int64) []RWChannel {
+	ret := _m.Called(nodeID)
+
+	var r0 []RWChannel
+	if rf, ok := ret.Get(0).(func(int64) []RWChannel); ok {
+		r0 = rf(nodeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]RWChannel)
+		}
+	}
+
+	return r0
+}
+
+// MockChannelManager_GetNodeChannels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNodeChannels'
+type MockChannelManager_GetNodeChannels_Call struct {
+	*mock.Call
+}
+
+// GetNodeChannels is a helper method to define mock.On call
+//   - nodeID int64
+func (_e *MockChannelManager_Expecter) GetNodeChannels(nodeID interface{}) *MockChannelManager_GetNodeChannels_Call {
+	return &MockChannelManager_GetNodeChannels_Call{Call: _e.mock.On("GetNodeChannels", nodeID)}
+}
+
+func (_c *MockChannelManager_GetNodeChannels_Call) Run(run func(nodeID int64)) *MockChannelManager_GetNodeChannels_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *MockChannelManager_GetNodeChannels_Call) Return(_a0 []RWChannel) *MockChannelManager_GetNodeChannels_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockChannelManager_GetNodeChannels_Call) RunAndReturn(run func(int64) []RWChannel) *MockChannelManager_GetNodeChannels_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetNodeChannelsByCollectionID provides a mock function with given fields: collectionID
 func (_m *MockChannelManager) GetNodeChannelsByCollectionID(collectionID int64) map[int64][]string {
 	ret := _m.Called(collectionID)
@@ -192,6 +192,70 @@ func (_c *MockCompactionPlanContext_isFull_Call) RunAndReturn(run func() bool) *
 	return _c
 }
 
+// listTasks provides a mock function with given fields:
+func (_m *MockCompactionPlanContext) listTasks() ([]*compactionTask, []*compactionTask, []int64) {
+	ret := _m.Called()
+
+	var r0 []*compactionTask
+	var r1 []*compactionTask
+	var r2 []int64
+	if rf, ok := ret.Get(0).(func() ([]*compactionTask, []*compactionTask, []int64)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*compactionTask); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*compactionTask)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() []*compactionTask); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]*compactionTask)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func() []int64); ok {
+		r2 = rf()
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).([]int64)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// MockCompactionPlanContext_listTasks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'listTasks'
+type MockCompactionPlanContext_listTasks_Call struct {
+	*mock.Call
+}
+
+// listTasks is a helper method to define mock.On call
+func (_e *MockCompactionPlanContext_Expecter) listTasks() *MockCompactionPlanContext_listTasks_Call {
+	return &MockCompactionPlanContext_listTasks_Call{Call: _e.mock.On("listTasks")}
+}
+
+func (_c *MockCompactionPlanContext_listTasks_Call) Run(run func()) *MockCompactionPlanContext_listTasks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockCompactionPlanContext_listTasks_Call) Return(queuing []*compactionTask, executing []*compactionTask, pressuredNodes []int64) *MockCompactionPlanContext_listTasks_Call {
+	_c.Call.Return(queuing, executing, pressuredNodes)
+	return _c
+}
+
+func (_c *MockCompactionPlanContext_listTasks_Call) RunAndReturn(run func() ([]*compactionTask, []*compactionTask, []int64)) *MockCompactionPlanContext_listTasks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // removeTasksByChannel provides a mock function with given fields: channel
 func (_m *MockCompactionPlanContext) removeTasksByChannel(channel string) {
 	_m.Called(channel)
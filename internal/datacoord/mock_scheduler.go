@@ -54,6 +54,49 @@ func (_c *MockScheduler_Finish_Call) RunAndReturn(run func(int64, *datapb.Compac
 	return _c
 }
 
+// GetPressuredNodes provides a mock function with given fields:
+func (_m *MockScheduler) GetPressuredNodes() []int64 {
+	ret := _m.Called()
+
+	var r0 []int64
+	if rf, ok := ret.Get(0).(func() []int64); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	return r0
+}
+
+// MockScheduler_GetPressuredNodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPressuredNodes'
+type MockScheduler_GetPressuredNodes_Call struct {
+	*mock.Call
+}
+
+// GetPressuredNodes is a helper method to define mock.On call
+func (_e *MockScheduler_Expecter) GetPressuredNodes() *MockScheduler_GetPressuredNodes_Call {
+	return &MockScheduler_GetPressuredNodes_Call{Call: _e.mock.On("GetPressuredNodes")}
+}
+
+func (_c *MockScheduler_GetPressuredNodes_Call) Run(run func()) *MockScheduler_GetPressuredNodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockScheduler_GetPressuredNodes_Call) Return(_a0 []int64) *MockScheduler_GetPressuredNodes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockScheduler_GetPressuredNodes_Call) RunAndReturn(run func() []int64) *MockScheduler_GetPressuredNodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetTaskCount provides a mock function with given fields:
 func (_m *MockScheduler) GetTaskCount() int {
 	ret := _m.Called()
@@ -95,6 +138,61 @@ func (_c *MockScheduler_GetTaskCount_Call) RunAndReturn(run func() int) *MockSch
 	return _c
 }
 
+// ListTasks provides a mock function with given fields:
+func (_m *MockScheduler) ListTasks() ([]*compactionTask, []*compactionTask) {
+	ret := _m.Called()
+
+	var r0 []*compactionTask
+	var r1 []*compactionTask
+	if rf, ok := ret.Get(0).(func() ([]*compactionTask, []*compactionTask)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*compactionTask); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*compactionTask)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() []*compactionTask); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]*compactionTask)
+		}
+	}
+
+	return r0, r1
+}
+
+// MockScheduler_ListTasks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTasks'
+type MockScheduler_ListTasks_Call struct {
+	*mock.Call
+}
+
+// ListTasks is a helper method to define mock.On call
+func (_e *MockScheduler_Expecter) ListTasks() *MockScheduler_ListTasks_Call {
+	return &MockScheduler_ListTasks_Call{Call: _e.mock.On("ListTasks")}
+}
+
+func (_c *MockScheduler_ListTasks_Call) Run(run func()) *MockScheduler_ListTasks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockScheduler_ListTasks_Call) Return(queuing []*compactionTask, executing []*compactionTask) *MockScheduler_ListTasks_Call {
+	_c.Call.Return(queuing, executing)
+	return _c
+}
+
+func (_c *MockScheduler_ListTasks_Call) RunAndReturn(run func() ([]*compactionTask, []*compactionTask)) *MockScheduler_ListTasks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LogStatus provides a mock function with given fields:
 func (_m *MockScheduler) LogStatus() {
 	_m.Called()
@@ -127,6 +225,40 @@ func (_c *MockScheduler_LogStatus_Call) RunAndReturn(run func()) *MockScheduler_
 	return _c
 }
 
+// Requeue provides a mock function with given fields: nodeID, plan
+func (_m *MockScheduler) Requeue(nodeID int64, plan *datapb.CompactionPlan) {
+	_m.Called(nodeID, plan)
+}
+
+// MockScheduler_Requeue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Requeue'
+type MockScheduler_Requeue_Call struct {
+	*mock.Call
+}
+
+// Requeue is a helper method to define mock.On call
+//   - nodeID int64
+//   - plan *datapb.CompactionPlan
+func (_e *MockScheduler_Expecter) Requeue(nodeID interface{}, plan interface{}) *MockScheduler_Requeue_Call {
+	return &MockScheduler_Requeue_Call{Call: _e.mock.On("Requeue", nodeID, plan)}
+}
+
+func (_c *MockScheduler_Requeue_Call) Run(run func(nodeID int64, plan *datapb.CompactionPlan)) *MockScheduler_Requeue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(*datapb.CompactionPlan))
+	})
+	return _c
+}
+
+func (_c *MockScheduler_Requeue_Call) Return() *MockScheduler_Requeue_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockScheduler_Requeue_Call) RunAndReturn(run func(int64, *datapb.CompactionPlan)) *MockScheduler_Requeue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Schedule provides a mock function with given fields:
 func (_m *MockScheduler) Schedule() []*compactionTask {
 	ret := _m.Called()
@@ -170,6 +302,72 @@ func (_c *MockScheduler_Schedule_Call) RunAndReturn(run func() []*compactionTask
 	return _c
 }
 
+// SetPressuredNodes provides a mock function with given fields: nodes
+func (_m *MockScheduler) SetPressuredNodes(nodes []int64) {
+	_m.Called(nodes)
+}
+
+// MockScheduler_SetPressuredNodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPressuredNodes'
+type MockScheduler_SetPressuredNodes_Call struct {
+	*mock.Call
+}
+
+// SetPressuredNodes is a helper method to define mock.On call
+//   - nodes []int64
+func (_e *MockScheduler_Expecter) SetPressuredNodes(nodes interface{}) *MockScheduler_SetPressuredNodes_Call {
+	return &MockScheduler_SetPressuredNodes_Call{Call: _e.mock.On("SetPressuredNodes", nodes)}
+}
+
+func (_c *MockScheduler_SetPressuredNodes_Call) Run(run func(nodes []int64)) *MockScheduler_SetPressuredNodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]int64))
+	})
+	return _c
+}
+
+func (_c *MockScheduler_SetPressuredNodes_Call) Return() *MockScheduler_SetPressuredNodes_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockScheduler_SetPressuredNodes_Call) RunAndReturn(run func([]int64)) *MockScheduler_SetPressuredNodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetStealingNodes provides a mock function with given fields: nodes
+func (_m *MockScheduler) SetStealingNodes(nodes []int64) {
+	_m.Called(nodes)
+}
+
+// MockScheduler_SetStealingNodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetStealingNodes'
+type MockScheduler_SetStealingNodes_Call struct {
+	*mock.Call
+}
+
+// SetStealingNodes is a helper method to define mock.On call
+//   - nodes []int64
+func (_e *MockScheduler_Expecter) SetStealingNodes(nodes interface{}) *MockScheduler_SetStealingNodes_Call {
+	return &MockScheduler_SetStealingNodes_Call{Call: _e.mock.On("SetStealingNodes", nodes)}
+}
+
+func (_c *MockScheduler_SetStealingNodes_Call) Run(run func(nodes []int64)) *MockScheduler_SetStealingNodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]int64))
+	})
+	return _c
+}
+
+func (_c *MockScheduler_SetStealingNodes_Call) Return() *MockScheduler_SetStealingNodes_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockScheduler_SetStealingNodes_Call) RunAndReturn(run func([]int64)) *MockScheduler_SetStealingNodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Submit provides a mock function with given fields: t
 func (_m *MockScheduler) Submit(t ...*compactionTask) {
 	_va := make([]interface{}, len(t))
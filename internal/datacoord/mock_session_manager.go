@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	datapb "github.com/milvus-io/milvus/internal/proto/datapb"
+	sessionutil "github.com/milvus-io/milvus/internal/util/sessionutil"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -318,6 +319,50 @@ func (_c *MockSessionManager_DeleteSession_Call) RunAndReturn(run func(*NodeInfo
 	return _c
 }
 
+// DropCompactionPlan provides a mock function with given fields: ctx, nodeID, req
+func (_m *MockSessionManager) DropCompactionPlan(ctx context.Context, nodeID int64, req *datapb.DropCompactionPlanRequest) error {
+	ret := _m.Called(ctx, nodeID, req)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *datapb.DropCompactionPlanRequest) error); ok {
+		r0 = rf(ctx, nodeID, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSessionManager_DropCompactionPlan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DropCompactionPlan'
+type MockSessionManager_DropCompactionPlan_Call struct {
+	*mock.Call
+}
+
+// DropCompactionPlan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - nodeID int64
+//   - req *datapb.DropCompactionPlanRequest
+func (_e *MockSessionManager_Expecter) DropCompactionPlan(ctx interface{}, nodeID interface{}, req interface{}) *MockSessionManager_DropCompactionPlan_Call {
+	return &MockSessionManager_DropCompactionPlan_Call{Call: _e.mock.On("DropCompactionPlan", ctx, nodeID, req)}
+}
+
+func (_c *MockSessionManager_DropCompactionPlan_Call) Run(run func(ctx context.Context, nodeID int64, req *datapb.DropCompactionPlanRequest)) *MockSessionManager_DropCompactionPlan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(*datapb.DropCompactionPlanRequest))
+	})
+	return _c
+}
+
+func (_c *MockSessionManager_DropCompactionPlan_Call) Return(_a0 error) *MockSessionManager_DropCompactionPlan_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSessionManager_DropCompactionPlan_Call) RunAndReturn(run func(context.Context, int64, *datapb.DropCompactionPlanRequest) error) *MockSessionManager_DropCompactionPlan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Flush provides a mock function with given fields: ctx, nodeID, req
 func (_m *MockSessionManager) Flush(ctx context.Context, nodeID int64, req *datapb.FlushSegmentsRequest) {
 	_m.Called(ctx, nodeID, req)
@@ -605,6 +650,49 @@ func (_c *MockSessionManager_NotifyChannelOperation_Call) RunAndReturn(run func(
 	return _c
 }
 
+// SupportFeature provides a mock function with given fields: nodeID, feature
+func (_m *MockSessionManager) SupportFeature(nodeID int64, feature sessionutil.Feature) bool {
+	ret := _m.Called(nodeID, feature)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(int64, sessionutil.Feature) bool); ok {
+		r0 = rf(nodeID, feature)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockSessionManager_SupportFeature_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SupportFeature'
+type MockSessionManager_SupportFeature_Call struct {
+	*mock.Call
+}
+
+// SupportFeature is a helper method to define mock.On call
+//   - nodeID int64
+//   - feature sessionutil.Feature
+func (_e *MockSessionManager_Expecter) SupportFeature(nodeID interface{}, feature interface{}) *MockSessionManager_SupportFeature_Call {
+	return &MockSessionManager_SupportFeature_Call{Call: _e.mock.On("SupportFeature", nodeID, feature)}
+}
+
+func (_c *MockSessionManager_SupportFeature_Call) Run(run func(nodeID int64, feature sessionutil.Feature)) *MockSessionManager_SupportFeature_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(sessionutil.Feature))
+	})
+	return _c
+}
+
+func (_c *MockSessionManager_SupportFeature_Call) Return(_a0 bool) *MockSessionManager_SupportFeature_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSessionManager_SupportFeature_Call) RunAndReturn(run func(int64, sessionutil.Feature) bool) *MockSessionManager_SupportFeature_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SyncSegments provides a mock function with given fields: nodeID, req
 func (_m *MockSessionManager) SyncSegments(nodeID int64, req *datapb.SyncSegmentsRequest) error {
 	ret := _m.Called(nodeID, req)
@@ -315,6 +315,14 @@ func (c *mockDataNodeClient) DropImport(ctx context.Context, req *datapb.DropImp
 	return &commonpb.Status{ErrorCode: commonpb.ErrorCode_Success}, nil
 }
 
+func (c *mockDataNodeClient) VerifySegment(ctx context.Context, req *datapb.VerifySegmentRequest, opts ...grpc.CallOption) (*datapb.VerifySegmentResponse, error) {
+	return &datapb.VerifySegmentResponse{Status: merr.Success()}, nil
+}
+
+func (c *mockDataNodeClient) DropCompactionPlan(ctx context.Context, req *datapb.DropCompactionPlanRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	return merr.Success(), nil
+}
+
 func (c *mockDataNodeClient) Stop() error {
 	c.state = commonpb.StateCode_Abnormal
 	return nil
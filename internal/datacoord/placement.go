@@ -0,0 +1,228 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"fmt"
+	"plugin"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/placement"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// placementPolicy places the channels and the segments on the datanodes by the placement plugin,
+// the node info of the plugin is from the sessions of the datanodes.
+type placementPolicy struct {
+	plugin   placement.Policy
+	sessions SessionManager
+}
+
+// loadPlacementPolicy loads the placement plugin from dataCoord.placement.soPath,
+// it returns nil if no plugin is configured.
+func loadPlacementPolicy(sessions SessionManager) (*placementPolicy, error) {
+	path := Params.DataCoordCfg.PlacementSoPath.GetValue()
+	if path == "" {
+		return nil, nil
+	}
+
+	log.Info("start to load placement plugin", zap.String("path", path))
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to open the placement plugin, error: %s", err.Error())
+	}
+	symbol, err := p.Lookup(placement.PolicySymbol)
+	if err != nil {
+		return nil, fmt.Errorf("fail to find the '%s' object in the placement plugin, error: %s", placement.PolicySymbol, err.Error())
+	}
+	policy, ok := symbol.(placement.Policy)
+	if !ok {
+		return nil, fmt.Errorf("fail to convert the '%s' object to the placement policy", placement.PolicySymbol)
+	}
+	if err := policy.Init(Params.DataCoordCfg.PlacementParams.GetValue()); err != nil {
+		return nil, fmt.Errorf("fail to init the placement plugin, error: %s", err.Error())
+	}
+	log.Info("placement plugin loaded", zap.String("path", path))
+	return newPlacementPolicy(policy, sessions), nil
+}
+
+func newPlacementPolicy(policy placement.Policy, sessions SessionManager) *placementPolicy {
+	return &placementPolicy{
+		plugin:   policy,
+		sessions: sessions,
+	}
+}
+
+// getNodes returns the placement nodes of the node channel infos, with the address and the labels of the sessions.
+func (p *placementPolicy) getNodes(infos []*NodeChannelInfo) []placement.Node {
+	sessions := lo.SliceToMap(p.sessions.GetSessions(), func(session *Session) (int64, *NodeInfo) {
+		return session.info.NodeID, session.info
+	})
+	return lo.Map(infos, func(info *NodeChannelInfo, _ int) placement.Node {
+		node := placement.Node{
+			NodeID: info.NodeID,
+			Channels: lo.Map(info.Channels, func(ch RWChannel, _ int) string {
+				return ch.GetName()
+			}),
+		}
+		if session, ok := sessions[info.NodeID]; ok {
+			node.Address = session.Address
+			node.Labels = session.Labels
+		}
+		return node
+	})
+}
+
+// assignChannels places the channels on the candidate nodes by the plugin,
+// it returns the channels placed by node and the ones left to the default policy.
+func (p *placementPolicy) assignChannels(candidates []*NodeChannelInfo, channels []RWChannel) (map[int64][]RWChannel, []RWChannel) {
+	if len(candidates) == 0 || len(channels) == 0 {
+		return nil, channels
+	}
+
+	assignments := p.plugin.AssignChannels(p.getNodes(candidates), lo.Map(channels, func(ch RWChannel, _ int) placement.Channel {
+		return placement.Channel{Name: ch.GetName(), CollectionID: ch.GetCollectionID()}
+	}))
+
+	valid := typeutil.NewUniqueSet(lo.Map(candidates, func(info *NodeChannelInfo, _ int) int64 { return info.NodeID })...)
+	placed := make(map[int64][]RWChannel)
+	var rest []RWChannel
+	for _, ch := range channels {
+		nodeID, ok := assignments[ch.GetName()]
+		if !ok || !valid.Contain(nodeID) {
+			rest = append(rest, ch)
+			continue
+		}
+		placed[nodeID] = append(placed[nodeID], ch)
+	}
+	return placed, rest
+}
+
+// segmentPlacement returns the placement of the segments of the stolen compactions by the plugin,
+// the channels of the nodes are from the channel manager.
+func (p *placementPolicy) segmentPlacement(cm ChannelManager) segmentPlacement {
+	return func(nodeIDs []int64, plan *datapb.CompactionPlan) (int64, bool) {
+		candidates := lo.Map(nodeIDs, func(nodeID int64, _ int) *NodeChannelInfo {
+			return &NodeChannelInfo{NodeID: nodeID, Channels: cm.GetNodeChannels(nodeID)}
+		})
+		segments := lo.Map(plan.GetSegmentBinlogs(), func(seg *datapb.CompactionSegmentBinlogs, _ int) placement.Segment {
+			var size int64
+			for _, fieldBinlog := range seg.GetFieldBinlogs() {
+				for _, binlog := range fieldBinlog.GetBinlogs() {
+					size += binlog.GetLogSize()
+				}
+			}
+			return placement.Segment{
+				ID:           seg.GetSegmentID(),
+				CollectionID: seg.GetCollectionID(),
+				PartitionID:  seg.GetPartitionID(),
+				Channel:      plan.GetChannel(),
+				Size:         size,
+			}
+		})
+
+		nodeID, ok := p.plugin.AssignSegments(p.getNodes(candidates), segments)
+		return nodeID, ok && lo.Contains(nodeIDs, nodeID)
+	}
+}
+
+// placementPolicyFactory creates the channel policies placing the channels by the placement plugin first,
+// the channels not placed by the plugin are placed by the policies of the default factory.
+type placementPolicyFactory struct {
+	ChannelPolicyFactory
+	placement *placementPolicy
+}
+
+func newPlacementPolicyFactory(factory ChannelPolicyFactory, placement *placementPolicy) *placementPolicyFactory {
+	return &placementPolicyFactory{
+		ChannelPolicyFactory: factory,
+		placement:            placement,
+	}
+}
+
+// NewAssignPolicy implementing ChannelPolicyFactory places the new channels on the registered nodes.
+func (f *placementPolicyFactory) NewAssignPolicy() ChannelAssignPolicy {
+	fallback := f.ChannelPolicyFactory.NewAssignPolicy()
+	return func(store ROChannelStore, channels []RWChannel) *ChannelOpSet {
+		newChannels := filterChannels(store, channels)
+		placed, rest := f.placement.assignChannels(store.GetNodesChannels(), newChannels)
+		if len(placed) == 0 {
+			return fallback(store, channels)
+		}
+
+		opSet := NewChannelOpSet()
+		for nodeID, chs := range placed {
+			opSet.Add(nodeID, chs...)
+		}
+		if len(rest) > 0 {
+			opSet.Insert(fallback(store, rest).Collect()...)
+		}
+		return opSet
+	}
+}
+
+// NewDeregisterPolicy implementing ChannelPolicyFactory places the channels of the deregistered node on the others.
+func (f *placementPolicyFactory) NewDeregisterPolicy() DeregisterPolicy {
+	fallback := f.ChannelPolicyFactory.NewDeregisterPolicy()
+	reassign := f.NewReassignPolicy()
+	return func(store ROChannelStore, nodeID int64) *ChannelOpSet {
+		info := store.GetNode(nodeID)
+		others := lo.Filter(store.GetNodesChannels(), func(info *NodeChannelInfo, _ int) bool {
+			return info.NodeID != nodeID
+		})
+		// the channels are kept in buffer by the default policy if no node is left
+		if info == nil || len(info.Channels) == 0 || len(others) == 0 {
+			return fallback(store, nodeID)
+		}
+		return reassign(store, []*NodeChannelInfo{info})
+	}
+}
+
+// NewReassignPolicy implementing ChannelPolicyFactory places the channels released by the nodes on the others.
+func (f *placementPolicyFactory) NewReassignPolicy() ChannelReassignPolicy {
+	fallback := f.ChannelPolicyFactory.NewReassignPolicy()
+	return func(store ROChannelStore, reassigns []*NodeChannelInfo) *ChannelOpSet {
+		excluded := typeutil.NewUniqueSet(lo.Map(reassigns, func(info *NodeChannelInfo, _ int) int64 { return info.NodeID })...)
+		candidates := lo.Filter(store.GetNodesChannels(), func(info *NodeChannelInfo, _ int) bool {
+			return !excluded.Contain(info.NodeID)
+		})
+
+		opSet := NewChannelOpSet()
+		var rest []*NodeChannelInfo
+		for _, reassign := range reassigns {
+			placed, unplaced := f.placement.assignChannels(candidates, reassign.Channels)
+			for nodeID, chs := range placed {
+				opSet.Delete(reassign.NodeID, chs...)
+				opSet.Add(nodeID, chs...)
+			}
+			if len(unplaced) > 0 {
+				rest = append(rest, &NodeChannelInfo{NodeID: reassign.NodeID, Channels: unplaced})
+			}
+		}
+		if opSet.Len() == 0 {
+			return fallback(store, reassigns)
+		}
+		if len(rest) > 0 {
+			opSet.Insert(fallback(store, rest).Collect()...)
+		}
+		return opSet
+	}
+}
@@ -0,0 +1,192 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+
+	memkv "github.com/milvus-io/milvus/internal/kv/mem"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/placement"
+)
+
+type fakePlacementPolicy struct {
+	channels    map[string]int64
+	segmentNode int64
+	nodes       []placement.Node
+	segments    []placement.Segment
+}
+
+func (p *fakePlacementPolicy) Init(params map[string]string) error {
+	return nil
+}
+
+func (p *fakePlacementPolicy) AssignChannels(nodes []placement.Node, channels []placement.Channel) map[string]int64 {
+	p.nodes = nodes
+	return lo.PickBy(p.channels, func(name string, _ int64) bool {
+		return lo.ContainsBy(channels, func(ch placement.Channel) bool { return ch.Name == name })
+	})
+}
+
+func (p *fakePlacementPolicy) AssignSegments(nodes []placement.Node, segments []placement.Segment) (int64, bool) {
+	p.nodes = nodes
+	p.segments = segments
+	return p.segmentNode, p.segmentNode != 0
+}
+
+type PlacementSuite struct {
+	suite.Suite
+
+	plugin  *fakePlacementPolicy
+	factory *placementPolicyFactory
+	store   *ChannelStore
+}
+
+func TestPlacementSuite(t *testing.T) {
+	suite.Run(t, new(PlacementSuite))
+}
+
+func (s *PlacementSuite) SetupTest() {
+	sessions := NewSessionManagerImpl()
+	sessions.AddSession(&NodeInfo{NodeID: 2, Address: "addr-2"})
+	sessions.AddSession(&NodeInfo{NodeID: 3, Address: "addr-3", Labels: map[string]string{"zone": "az-1"}})
+
+	s.plugin = &fakePlacementPolicy{}
+	s.factory = newPlacementPolicyFactory(NewChannelPolicyFactoryV1(nil), newPlacementPolicy(s.plugin, sessions))
+	s.store = &ChannelStore{
+		store: memkv.NewMemoryKV(),
+		channelsInfo: map[int64]*NodeChannelInfo{
+			1: {1, []RWChannel{getChannel("ch-a", 1), getChannel("ch-b", 1)}},
+			2: {2, []RWChannel{}},
+			3: {3, []RWChannel{getChannel("ch-c", 1)}},
+		},
+	}
+}
+
+func (s *PlacementSuite) nodeIDs() []int64 {
+	return lo.Map(s.plugin.nodes, func(node placement.Node, _ int) int64 { return node.NodeID })
+}
+
+func (s *PlacementSuite) TestLoad() {
+	policy, err := loadPlacementPolicy(NewSessionManagerImpl())
+	s.NoError(err)
+	s.Nil(policy)
+
+	paramtable.Get().Save(Params.DataCoordCfg.PlacementSoPath.Key, "/not/exist/placement.so")
+	defer paramtable.Get().Reset(Params.DataCoordCfg.PlacementSoPath.Key)
+	_, err = loadPlacementPolicy(NewSessionManagerImpl())
+	s.Error(err)
+}
+
+func (s *PlacementSuite) TestAssign() {
+	s.plugin.channels = map[string]int64{"ch-d": 3, "ch-e": 9}
+	assign := s.factory.NewAssignPolicy()
+
+	// the channel placed on the unknown node is placed by the default policy
+	opSet := assign(s.store, []RWChannel{getChannel("ch-a", 1), getChannel("ch-d", 1), getChannel("ch-e", 1)})
+	s.ElementsMatch(NewChannelOpSet(
+		NewAddOp(3, getChannel("ch-d", 1)),
+		NewAddOp(2, getChannel("ch-e", 1)),
+	).Collect(), opSet.Collect())
+	s.ElementsMatch([]int64{1, 2, 3}, s.nodeIDs())
+
+	node, ok := lo.Find(s.plugin.nodes, func(node placement.Node) bool { return node.NodeID == 3 })
+	s.Require().True(ok)
+	s.Equal("addr-3", node.Address)
+	s.Equal(map[string]string{"zone": "az-1"}, node.Labels)
+	s.Equal([]string{"ch-c"}, node.Channels)
+
+	// nothing placed
+	s.plugin.channels = nil
+	channels := []RWChannel{getChannel("ch-d", 1)}
+	s.Equal(AverageAssignPolicy(s.store, channels).Collect(), assign(s.store, channels).Collect())
+}
+
+func (s *PlacementSuite) TestReassign() {
+	s.plugin.channels = map[string]int64{"ch-a": 3, "ch-b": 1}
+	reassign := s.factory.NewReassignPolicy()
+
+	// the channel placed on the released node is placed by the default policy
+	opSet := reassign(s.store, []*NodeChannelInfo{s.store.GetNode(1)})
+	s.ElementsMatch(NewChannelOpSet(
+		NewDeleteOp(1, getChannel("ch-a", 1)),
+		NewAddOp(3, getChannel("ch-a", 1)),
+		NewDeleteOp(1, getChannel("ch-b", 1)),
+		NewAddOp(2, getChannel("ch-b", 1)),
+	).Collect(), opSet.Collect())
+	s.ElementsMatch([]int64{2, 3}, s.nodeIDs())
+}
+
+func (s *PlacementSuite) TestDeregister() {
+	s.plugin.channels = map[string]int64{"ch-a": 3, "ch-b": 3}
+	deregister := s.factory.NewDeregisterPolicy()
+
+	opSet := deregister(s.store, 1)
+	s.ElementsMatch(NewChannelOpSet(
+		NewDeleteOp(1, getChannel("ch-a", 1), getChannel("ch-b", 1)),
+		NewAddOp(3, getChannel("ch-a", 1), getChannel("ch-b", 1)),
+	).Collect(), opSet.Collect())
+
+	// the channels are kept in buffer if no node is left
+	s.store.channelsInfo = map[int64]*NodeChannelInfo{1: {1, []RWChannel{getChannel("ch-a", 1)}}}
+	opSet = deregister(s.store, 1)
+	s.ElementsMatch(NewChannelOpSet(
+		NewDeleteOp(1, getChannel("ch-a", 1)),
+		NewAddOp(bufferID, getChannel("ch-a", 1)),
+	).Collect(), opSet.Collect())
+}
+
+func (s *PlacementSuite) TestSegmentPlacement() {
+	cm := NewMockChannelManager(s.T())
+	cm.EXPECT().GetNodeChannels(int64(2)).Return(nil)
+	cm.EXPECT().GetNodeChannels(int64(3)).Return([]RWChannel{getChannel("ch-c", 1)})
+	place := s.factory.placement.segmentPlacement(cm)
+
+	plan := &datapb.CompactionPlan{
+		Channel: "ch-a",
+		SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{
+			{
+				SegmentID:    100,
+				CollectionID: 1,
+				PartitionID:  10,
+				FieldBinlogs: []*datapb.FieldBinlog{
+					{Binlogs: []*datapb.Binlog{{LogSize: 100}, {LogSize: 200}}},
+					{Binlogs: []*datapb.Binlog{{LogSize: 300}}},
+				},
+			},
+		},
+	}
+	s.plugin.segmentNode = 3
+	nodeID, ok := place([]int64{2, 3}, plan)
+	s.True(ok)
+	s.EqualValues(3, nodeID)
+	s.Equal([]placement.Segment{{ID: 100, CollectionID: 1, PartitionID: 10, Channel: "ch-a", Size: 600}}, s.plugin.segments)
+	s.Equal([]string{"ch-c"}, s.plugin.nodes[1].Channels)
+
+	// the node not in the candidates
+	s.plugin.segmentNode = 1
+	_, ok = place([]int64{2, 3}, plan)
+	s.False(ok)
+
+	s.plugin.segmentNode = 0
+	_, ok = place([]int64{2, 3}, plan)
+	s.False(ok)
+}
@@ -0,0 +1,126 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// autoShardIndex is the shard index of the published segments assigned to the shard of the fewest rows.
+const autoShardIndex = -1
+
+// readPublishManifest reads the manifest written by an external writer.
+func readPublishManifest(ctx context.Context, cm storage.ChunkManager, manifestPath string) (*datapb.PublishManifest, error) {
+	data, err := cm.Read(ctx, manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &datapb.PublishManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal publish manifest %s", manifestPath)
+	}
+	return manifest, nil
+}
+
+// mapShardChannels returns the channel of every shard index.
+func mapShardChannels(channels []string) (map[int]string, error) {
+	shards := make(map[int]string, len(channels))
+	for _, channel := range channels {
+		shardIdx, err := parseShardIndex(channel)
+		if err != nil {
+			return nil, err
+		}
+		shards[shardIdx] = channel
+	}
+	return shards, nil
+}
+
+// checkPublishedSegment checks that the published segment has the insert binlogs of all the fields
+// including the RowID and Timestamp fields, and every field has the same number of rows as the segment.
+func checkPublishedSegment(segment *datapb.PublishedSegment, schema *schemapb.CollectionSchema) error {
+	if segment.GetNumOfRows() <= 0 {
+		return merr.WrapErrParameterInvalidMsg("invalid number of rows %d of published segment", segment.GetNumOfRows())
+	}
+	rows := make(map[int64]int64, len(segment.GetBinlogs()))
+	for _, fieldBinlog := range segment.GetBinlogs() {
+		for _, l := range fieldBinlog.GetBinlogs() {
+			rows[fieldBinlog.GetFieldID()] += l.GetEntriesNum()
+		}
+	}
+	fieldIDs := lo.Map(schema.GetFields(), func(field *schemapb.FieldSchema, _ int) int64 { return field.GetFieldID() })
+	fieldIDs = lo.Uniq(append(fieldIDs, common.RowIDField, common.TimeStampField))
+	for _, fieldID := range fieldIDs {
+		if rows[fieldID] != segment.GetNumOfRows() {
+			return merr.WrapErrParameterInvalidMsg("field %d of published segment has %d rows, expected %d",
+				fieldID, rows[fieldID], segment.GetNumOfRows())
+		}
+	}
+	if len(rows) != len(fieldIDs) {
+		return merr.WrapErrParameterInvalidMsg("published segment has binlogs of fields not in the schema")
+	}
+	return nil
+}
+
+// assignPublishedSegments returns the channel of every published segment. The segments of autoShardIndex
+// are assigned one by one to the channel of the fewest rows, counting the rows of the existing segments
+// and of the published segments assigned before, the ties are broken by the shard index.
+func assignPublishedSegments(shards map[int]string, existing []*SegmentInfo, segments []*datapb.PublishedSegment) ([]string, error) {
+	rows := make(map[string]int64, len(shards))
+	for _, channel := range shards {
+		rows[channel] = 0
+	}
+	for _, segment := range existing {
+		if _, ok := rows[segment.GetInsertChannel()]; ok {
+			rows[segment.GetInsertChannel()] += segment.GetNumOfRows()
+		}
+	}
+	for _, segment := range segments {
+		if segment.GetShardIndex() != autoShardIndex {
+			rows[shards[int(segment.GetShardIndex())]] += segment.GetNumOfRows()
+		}
+	}
+
+	shardIdxs := lo.Keys(shards)
+	sort.Ints(shardIdxs)
+	channels := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment.GetShardIndex() != autoShardIndex {
+			channels = append(channels, shards[int(segment.GetShardIndex())])
+			continue
+		}
+		if len(shardIdxs) == 0 {
+			return nil, merr.WrapErrParameterInvalidMsg("no channel watched to assign published segment")
+		}
+		channel := shards[lo.MinBy(shardIdxs, func(a, b int) bool {
+			return rows[shards[a]] < rows[shards[b]]
+		})]
+		rows[channel] += segment.GetNumOfRows()
+		channels = append(channels, channel)
+	}
+	return channels, nil
+}
@@ -0,0 +1,50 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestAssignPublishedSegments(t *testing.T) {
+	shards := map[int]string{0: "ch_v0", 1: "ch_v1", 2: "ch_v2"}
+	existing := []*SegmentInfo{
+		NewSegmentInfo(&datapb.SegmentInfo{ID: 1, InsertChannel: "ch_v0", NumOfRows: 30}),
+		NewSegmentInfo(&datapb.SegmentInfo{ID: 2, InsertChannel: "ch_v1", NumOfRows: 15}),
+		NewSegmentInfo(&datapb.SegmentInfo{ID: 3, InsertChannel: "ch_unwatched", NumOfRows: 100}),
+	}
+	segments := []*datapb.PublishedSegment{
+		{ShardIndex: autoShardIndex, NumOfRows: 10},
+		{ShardIndex: autoShardIndex, NumOfRows: 10},
+		{ShardIndex: 2, NumOfRows: 5},
+		{ShardIndex: autoShardIndex, NumOfRows: 10},
+	}
+
+	channels, err := assignPublishedSegments(shards, existing, segments)
+	assert.NoError(t, err)
+	// the rows of the segment of shard 2 are counted before any segment is assigned,
+	// and the ties are broken by the shard index
+	assert.Equal(t, []string{"ch_v2", "ch_v1", "ch_v2", "ch_v2"}, channels)
+
+	_, err = assignPublishedSegments(map[int]string{}, nil, segments[:1])
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
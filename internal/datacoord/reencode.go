@@ -0,0 +1,311 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/metastore/kv/binlog"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// outdatedStatslog is a segment whose primary key stats are not merged into a compound stats log,
+// the binlog encodings are listed in storage.InspectBinlogEncoding.
+const outdatedStatslog = "statslog"
+
+// reencodeManager rewrites the segments of outdated encodings into the current format in the background,
+// by mix compactions of a single segment. A segment is inspected once by reading its smallest insert binlog,
+// as the binlogs of a segment are never rewritten in place. Re-encoding is of low priority:
+//   - at most ReencodeInspectBatch segments are inspected in each interval
+//   - no re-encode compaction is submitted while any other compaction is running,
+//     and at most ReencodeMaxConcurrency re-encode compactions run at the same time
+//
+// The inspections are only kept in memory, the segments are inspected again if datacoord restarts.
+type reencodeManager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	meta              *meta
+	handler           Handler
+	allocator         allocator
+	compactionHandler compactionPlanContext
+	cli               storage.ChunkManager
+
+	mu         sync.Mutex
+	inspected  map[UniqueID][]string // outdated encodings of the inspected segments, empty if up to date
+	reencoding map[UniqueID]UniqueID // plan ID of the running re-encode compaction of each segment
+}
+
+func newReencodeManager(ctx context.Context, meta *meta, handler Handler, allocator allocator,
+	compactionHandler compactionPlanContext, cli storage.ChunkManager,
+) *reencodeManager {
+	ctx, cancel := context.WithCancel(ctx)
+	return &reencodeManager{
+		ctx:               ctx,
+		cancel:            cancel,
+		meta:              meta,
+		handler:           handler,
+		allocator:         allocator,
+		compactionHandler: compactionHandler,
+		cli:               cli,
+		inspected:         make(map[UniqueID][]string),
+		reencoding:        make(map[UniqueID]UniqueID),
+	}
+}
+
+func (m *reencodeManager) start() {
+	m.wg.Add(1)
+	go m.loop()
+}
+
+func (m *reencodeManager) close() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+func (m *reencodeManager) loop() {
+	defer m.wg.Done()
+	ticker := time.NewTicker(Params.DataCoordCfg.ReencodeCheckInterval.GetAsDuration(time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			log.Info("reencode loop quit")
+			return
+		case <-ticker.C:
+			if Params.DataCoordCfg.ReencodeEnabled.GetAsBool() {
+				m.check()
+			}
+		}
+	}
+}
+
+func (m *reencodeManager) check() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.refresh()
+	m.inspect()
+	m.updateMetrics()
+	m.submit()
+}
+
+// refresh forgets the segments no longer healthy, and the re-encode compactions no longer running.
+func (m *reencodeManager) refresh() {
+	for segmentID, planID := range m.reencoding {
+		task := m.compactionHandler.getCompaction(planID)
+		if task == nil || (task.state != executing && task.state != pipelining) {
+			delete(m.reencoding, segmentID)
+		}
+	}
+	for segmentID := range m.inspected {
+		if m.meta.GetHealthySegment(segmentID) == nil {
+			delete(m.inspected, segmentID)
+		}
+	}
+}
+
+// inspect inspects the encodings of a batch of the flushed segments not inspected yet, from the oldest one.
+func (m *reencodeManager) inspect() {
+	segments := m.meta.SelectSegments(func(segment *SegmentInfo) bool {
+		_, ok := m.inspected[segment.GetID()]
+		return !ok && isSegmentHealthy(segment) && isFlush(segment) &&
+			segment.GetLevel() != datapb.SegmentLevel_L0 && !segment.GetIsImporting()
+	})
+	sort.Slice(segments, func(i, j int) bool { return segments[i].GetID() < segments[j].GetID() })
+	batch := Params.DataCoordCfg.ReencodeInspectBatch.GetAsInt()
+	if len(segments) > batch {
+		segments = segments[:batch]
+	}
+
+	for _, segment := range segments {
+		outdated, err := m.inspectSegment(segment)
+		if err != nil {
+			// inspect it again in the next round
+			log.Warn("failed to inspect segment encodings", zap.Int64("segmentID", segment.GetID()), zap.Error(err))
+			continue
+		}
+		m.inspected[segment.GetID()] = outdated
+		if len(outdated) > 0 {
+			log.Info("found segment of outdated encodings",
+				zap.Int64("collectionID", segment.GetCollectionID()),
+				zap.Int64("segmentID", segment.GetID()),
+				zap.Strings("outdated", outdated))
+		}
+	}
+}
+
+// inspectSegment returns the outdated encodings of the segment, the smallest insert binlog is read
+// as the representative of the binlogs, which are written by the same node.
+func (m *reencodeManager) inspectSegment(segment *SegmentInfo) ([]string, error) {
+	var outdated []string
+	statslogs := lo.FlatMap(segment.GetStatslogs(), func(fieldBinlog *datapb.FieldBinlog, _ int) []*datapb.Binlog {
+		return fieldBinlog.GetBinlogs()
+	})
+	hasCompound := lo.ContainsBy(statslogs, func(l *datapb.Binlog) bool {
+		return l.GetLogID() == int64(storage.CompoundStatsType)
+	})
+	if len(statslogs) > 1 && !hasCompound {
+		outdated = append(outdated, outdatedStatslog)
+	}
+
+	cloned := segment.Clone()
+	if err := binlog.DecompressBinLogs(cloned.SegmentInfo); err != nil {
+		return nil, err
+	}
+	var (
+		smallest *datapb.Binlog
+		fieldID  int64
+	)
+	for _, fieldBinlog := range cloned.GetBinlogs() {
+		for _, l := range fieldBinlog.GetBinlogs() {
+			if smallest == nil || l.GetLogSize() < smallest.GetLogSize() {
+				smallest, fieldID = l, fieldBinlog.GetFieldID()
+			}
+		}
+	}
+	if smallest == nil {
+		return outdated, nil
+	}
+	data, err := m.cli.Read(m.ctx, smallest.GetLogPath())
+	if err != nil {
+		return nil, err
+	}
+	binlogOutdated, err := storage.InspectBinlogEncoding(data)
+	if err != nil {
+		return nil, err
+	}
+	if m.isUncompressedField(segment.GetCollectionID(), fieldID) {
+		// the field is set not to be compressed
+		binlogOutdated = lo.Without(binlogOutdated, storage.OutdatedUncompressed)
+	}
+	return append(outdated, binlogOutdated...), nil
+}
+
+// isUncompressedField returns whether the storage options of the field disable the compression.
+func (m *reencodeManager) isUncompressedField(collectionID, fieldID int64) bool {
+	coll := m.meta.GetCollection(collectionID)
+	if coll == nil {
+		return false
+	}
+	for _, field := range coll.Schema.GetFields() {
+		if field.GetFieldID() == fieldID {
+			compression, err := common.GetFieldCompression(field.GetTypeParams()...)
+			return err == nil && compression == common.FieldCompressionNone
+		}
+	}
+	return false
+}
+
+func (m *reencodeManager) updateMetrics() {
+	metrics.DataCoordOutdatedSegmentNum.Reset()
+	for _, outdated := range m.inspected {
+		for _, encoding := range outdated {
+			metrics.DataCoordOutdatedSegmentNum.WithLabelValues(encoding).Inc()
+		}
+	}
+}
+
+// submit submits the re-encode compactions of the outdated segments if no other compaction is running.
+func (m *reencodeManager) submit() {
+	planIDs := typeutil.NewSet(lo.Values(m.reencoding)...)
+	running := lo.Filter(m.compactionHandler.getCompactionTasksBySignalID(0), func(task *compactionTask, _ int) bool {
+		return (task.state == executing || task.state == pipelining) && !planIDs.Contain(task.plan.GetPlanID())
+	})
+	if len(running) > 0 {
+		return
+	}
+
+	candidates := lo.Filter(lo.Keys(m.inspected), func(segmentID UniqueID, _ int) bool {
+		_, ok := m.reencoding[segmentID]
+		return !ok && len(m.inspected[segmentID]) > 0
+	})
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+	for _, segmentID := range candidates {
+		if len(m.reencoding) >= Params.DataCoordCfg.ReencodeMaxConcurrency.GetAsInt() || m.compactionHandler.isFull() {
+			return
+		}
+		segment := m.meta.GetHealthySegment(segmentID)
+		if segment == nil || segment.isCompacting {
+			continue
+		}
+		planID, err := m.reencode(segment)
+		if err != nil {
+			log.Warn("failed to submit re-encode compaction", zap.Int64("segmentID", segmentID), zap.Error(err))
+			continue
+		}
+		if planID == 0 {
+			continue
+		}
+		m.reencoding[segmentID] = planID
+		log.Info("submitted re-encode compaction",
+			zap.Int64("collectionID", segment.GetCollectionID()),
+			zap.Int64("segmentID", segmentID),
+			zap.Int64("planID", planID),
+			zap.Strings("outdated", m.inspected[segmentID]))
+	}
+}
+
+// reencode submits a mix compaction of the segment only, returns 0 if the collection disabled auto compaction.
+func (m *reencodeManager) reencode(segment *SegmentInfo) (UniqueID, error) {
+	coll, err := m.handler.GetCollection(m.ctx, segment.GetCollectionID())
+	if err != nil {
+		return 0, err
+	}
+	if coll == nil {
+		return 0, nil
+	}
+	if enabled, err := getCollectionAutoCompactionEnabled(coll.Properties); err != nil || !enabled {
+		return 0, err
+	}
+	ttl, err := getPartitionTTL(coll.Properties, segment.GetPartitionID())
+	if err != nil {
+		return 0, err
+	}
+
+	plan := segmentsToPlan([]*SegmentInfo{segment}, &compactTime{collectionTTL: ttl})
+	if err := fillOriginPlan(m.allocator, plan); err != nil {
+		return 0, err
+	}
+	signalID, err := m.allocator.allocID(m.ctx)
+	if err != nil {
+		return 0, err
+	}
+	signal := &compactionSignal{
+		id:           signalID,
+		collectionID: segment.GetCollectionID(),
+		partitionID:  segment.GetPartitionID(),
+		segmentID:    segment.GetID(),
+		channel:      segment.GetInsertChannel(),
+	}
+	if err := m.compactionHandler.execCompactionPlan(signal, plan); err != nil {
+		return 0, err
+	}
+	return plan.GetPlanID(), nil
+}
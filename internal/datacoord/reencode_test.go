@@ -0,0 +1,197 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/etcdpb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type ReencodeManagerSuite struct {
+	suite.Suite
+
+	meta       *meta
+	schema     *schemapb.CollectionSchema
+	handler    *NMockHandler
+	compaction *MockCompactionPlanContext
+	cli        storage.ChunkManager
+	manager    *reencodeManager
+}
+
+func (s *ReencodeManagerSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (s *ReencodeManagerSuite) SetupTest() {
+	var err error
+	s.meta, err = newMemoryMeta()
+	s.Require().NoError(err)
+	s.schema = &schemapb.CollectionSchema{Fields: []*schemapb.FieldSchema{
+		{FieldID: common.RowIDField, Name: "row_id", DataType: schemapb.DataType_Int64},
+		{FieldID: common.TimeStampField, Name: "Timestamp", DataType: schemapb.DataType_Int64},
+		{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+	}}
+	s.meta.AddCollection(&collectionInfo{ID: 1, Schema: s.schema})
+	s.handler = NewNMockHandler(s.T())
+	s.compaction = NewMockCompactionPlanContext(s.T())
+	s.cli = storage.NewLocalChunkManager(storage.RootPath(s.T().TempDir()))
+	s.manager = newReencodeManager(context.TODO(), s.meta, s.handler, newMockAllocator(), s.compaction, s.cli)
+}
+
+// addSegment adds a flushed segment with an insert binlog written by the current codec and the statslogs.
+func (s *ReencodeManagerSuite) addSegment(segmentID int64, statslogIDs ...int64) {
+	codec := storage.NewInsertCodecWithSchema(&etcdpb.CollectionMeta{ID: 1, Schema: s.schema})
+	blobs, err := codec.Serialize(10, segmentID, &storage.InsertData{Data: map[storage.FieldID]storage.FieldData{
+		common.RowIDField:     &storage.Int64FieldData{Data: []int64{1, 2}},
+		common.TimeStampField: &storage.Int64FieldData{Data: []int64{10, 20}},
+		100:                   &storage.Int64FieldData{Data: []int64{1, 2}},
+	}})
+	s.Require().NoError(err)
+
+	segment := &datapb.SegmentInfo{
+		ID:            segmentID,
+		CollectionID:  1,
+		PartitionID:   10,
+		InsertChannel: "ch-1",
+		NumOfRows:     2,
+		State:         commonpb.SegmentState_Flushed,
+		Level:         datapb.SegmentLevel_L1,
+	}
+	for i, blob := range blobs {
+		fieldID := s.schema.GetFields()[i].GetFieldID()
+		logPath := metautil.BuildInsertLogPath(s.cli.RootPath(), 1, 10, segmentID, fieldID, int64(i+1))
+		s.Require().NoError(s.cli.Write(context.TODO(), logPath, blob.GetValue()))
+		segment.Binlogs = append(segment.Binlogs, &datapb.FieldBinlog{
+			FieldID: fieldID,
+			Binlogs: []*datapb.Binlog{{EntriesNum: 2, LogID: int64(i + 1), LogPath: logPath, LogSize: int64(len(blob.GetValue()))}},
+		})
+	}
+	if len(statslogIDs) > 0 {
+		statslogs := &datapb.FieldBinlog{FieldID: 100}
+		for _, logID := range statslogIDs {
+			statslogs.Binlogs = append(statslogs.Binlogs, &datapb.Binlog{
+				LogID:   logID,
+				LogPath: metautil.BuildStatsLogPath(s.cli.RootPath(), 1, 10, segmentID, 100, logID),
+			})
+		}
+		segment.Statslogs = []*datapb.FieldBinlog{statslogs}
+	}
+	s.Require().NoError(s.meta.AddSegment(context.TODO(), NewSegmentInfo(segment)))
+}
+
+func (s *ReencodeManagerSuite) TestInspect() {
+	s.addSegment(100, int64(storage.CompoundStatsType))
+	s.addSegment(101, 11, 12)
+
+	s.compaction.EXPECT().getCompactionTasksBySignalID(int64(0)).Return(nil).Once()
+	s.compaction.EXPECT().isFull().Return(false).Once()
+	s.handler.EXPECT().GetCollection(mock.Anything, int64(1)).Return(&collectionInfo{ID: 1}, nil).Once()
+	s.compaction.EXPECT().execCompactionPlan(mock.Anything, mock.Anything).
+		RunAndReturn(func(signal *compactionSignal, plan *datapb.CompactionPlan) error {
+			s.EqualValues(101, signal.segmentID)
+			s.Equal(datapb.CompactionType_MixCompaction, plan.GetType())
+			s.Len(plan.GetSegmentBinlogs(), 1)
+			s.EqualValues(101, plan.GetSegmentBinlogs()[0].GetSegmentID())
+			return nil
+		}).Once()
+	s.manager.check()
+	s.Empty(s.manager.inspected[100])
+	s.Equal([]string{outdatedStatslog}, s.manager.inspected[101])
+	s.Len(s.manager.reencoding, 1)
+	planID := s.manager.reencoding[101]
+
+	// the re-encode compaction is still running, no more compaction is submitted
+	s.compaction.EXPECT().getCompaction(planID).Return(&compactionTask{state: executing}).Once()
+	s.compaction.EXPECT().getCompactionTasksBySignalID(int64(0)).Return(nil).Once()
+	s.manager.check()
+	s.Len(s.manager.reencoding, 1)
+
+	// the compacted segment is dropped and forgotten
+	s.Require().NoError(s.meta.SetState(101, commonpb.SegmentState_Dropped, "test"))
+	s.compaction.EXPECT().getCompaction(planID).Return(&compactionTask{state: completed}).Once()
+	s.compaction.EXPECT().getCompactionTasksBySignalID(int64(0)).Return(nil).Once()
+	s.manager.check()
+	s.Empty(s.manager.reencoding)
+	s.NotContains(s.manager.inspected, int64(101))
+}
+
+func (s *ReencodeManagerSuite) TestUncompressedField() {
+	for _, field := range s.schema.GetFields() {
+		field.TypeParams = []*commonpb.KeyValuePair{{Key: common.FieldCompressionKey, Value: common.FieldCompressionNone}}
+	}
+	s.addSegment(100, int64(storage.CompoundStatsType))
+
+	// the binlogs are not compressed as the fields set
+	s.compaction.EXPECT().getCompactionTasksBySignalID(int64(0)).Return(nil).Once()
+	s.manager.check()
+	s.Contains(s.manager.inspected, int64(100))
+	s.Empty(s.manager.inspected[100])
+}
+
+func (s *ReencodeManagerSuite) TestLowPriority() {
+	s.addSegment(100, 11, 12)
+
+	// other compactions are running
+	s.compaction.EXPECT().getCompactionTasksBySignalID(int64(0)).Return([]*compactionTask{
+		{plan: &datapb.CompactionPlan{PlanID: 1}, state: executing},
+	}).Once()
+	s.manager.check()
+	s.Equal([]string{outdatedStatslog}, s.manager.inspected[100])
+	s.Empty(s.manager.reencoding)
+
+	// the collection disabled auto compaction
+	s.compaction.EXPECT().getCompactionTasksBySignalID(int64(0)).Return(nil).Once()
+	s.compaction.EXPECT().isFull().Return(false).Once()
+	s.handler.EXPECT().GetCollection(mock.Anything, int64(1)).Return(&collectionInfo{
+		ID:         1,
+		Properties: map[string]string{common.CollectionAutoCompactionKey: "false"},
+	}, nil).Once()
+	s.manager.check()
+	s.Empty(s.manager.reencoding)
+}
+
+func (s *ReencodeManagerSuite) TestInspectFailed() {
+	s.addSegment(100)
+	for _, fieldBinlog := range s.meta.GetHealthySegment(100).GetBinlogs() {
+		s.Require().NoError(s.cli.Write(context.TODO(), fieldBinlog.GetBinlogs()[0].GetLogPath(), []byte("invalid")))
+	}
+
+	s.compaction.EXPECT().getCompactionTasksBySignalID(int64(0)).Return(nil)
+	s.manager.check()
+	s.NotContains(s.manager.inspected, int64(100))
+
+	// inspected again in the next round
+	s.Require().NoError(s.cli.RemoveWithPrefix(context.TODO(), s.cli.RootPath()))
+	s.manager.check()
+	s.NotContains(s.manager.inspected, int64(100))
+}
+
+func TestReencodeManager(t *testing.T) {
+	suite.Run(t, new(ReencodeManagerSuite))
+}
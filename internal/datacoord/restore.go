@@ -0,0 +1,213 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+)
+
+// readBackupManifest reads the manifest written by Backup.
+func readBackupManifest(ctx context.Context, cm storage.ChunkManager, manifestPath string) (*datapb.BackupManifest, error) {
+	data, err := cm.Read(ctx, manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &datapb.BackupManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal backup manifest %s", manifestPath)
+	}
+	return manifest, nil
+}
+
+// parseShardIndex returns the shard index of a virtual channel, e.g. 1 for by-dev-rootcoord-dml_2_100v1.
+func parseShardIndex(vchannel string) (int, error) {
+	idx := strings.LastIndex(vchannel, "v")
+	if idx < 0 {
+		return 0, merr.WrapErrParameterInvalidMsg("invalid virtual channel %s", vchannel)
+	}
+	shardIdx, err := strconv.Atoi(vchannel[idx+1:])
+	if err != nil {
+		return 0, merr.WrapErrParameterInvalidMsg("invalid virtual channel %s", vchannel)
+	}
+	return shardIdx, nil
+}
+
+// mapRestoreChannels maps every backup channel to the target channel of the same shard index.
+func mapRestoreChannels(backupChannels []string, targetChannels []string) (map[string]string, error) {
+	shards := make(map[int]string, len(targetChannels))
+	for _, channel := range targetChannels {
+		shardIdx, err := parseShardIndex(channel)
+		if err != nil {
+			return nil, err
+		}
+		shards[shardIdx] = channel
+	}
+	mapping := make(map[string]string, len(backupChannels))
+	for _, channel := range backupChannels {
+		shardIdx, err := parseShardIndex(channel)
+		if err != nil {
+			return nil, err
+		}
+		target, ok := shards[shardIdx]
+		if !ok {
+			return nil, merr.WrapErrParameterInvalidMsg("no target channel for backup channel %s, shards num mismatch", channel)
+		}
+		mapping[channel] = target
+	}
+	return mapping, nil
+}
+
+// binlogCopier copies the binlogs of the source segments to the paths of the target segments,
+// the copies are tracked so that they are removed if the target segments are not added.
+type binlogCopier struct {
+	cm storage.ChunkManager
+	// the source files recorded in the manifest by the paths, verified if present
+	files  map[string]*datapb.BackupFile
+	copied []string
+}
+
+func newBinlogCopier(cm storage.ChunkManager, files []*datapb.BackupFile) *binlogCopier {
+	return &binlogCopier{
+		cm:    cm,
+		files: lo.SliceToMap(files, func(file *datapb.BackupFile) (string, *datapb.BackupFile) { return file.GetPath(), file }),
+	}
+}
+
+// restoreBinlogs copies the binlogs of a backup segment to the paths of the target segment with the same log ids.
+func (c *binlogCopier) restoreBinlogs(ctx context.Context, binlogType storage.BinlogType,
+	fieldBinlogs []*datapb.FieldBinlog, collectionID, partitionID, segmentID UniqueID,
+) ([]*datapb.FieldBinlog, error) {
+	return c.copyBinlogs(ctx, binlogType, fieldBinlogs, collectionID, partitionID, segmentID,
+		func(l *datapb.Binlog) (UniqueID, error) {
+			// the path is authoritative, the log id of legacy binlogs may be unset
+			logID, err := strconv.ParseInt(path.Base(l.GetLogPath()), 10, 64)
+			if err != nil {
+				return 0, errors.Wrapf(err, "failed to parse log id of %s", l.GetLogPath())
+			}
+			return logID, nil
+		})
+}
+
+// copyBinlogs copies the binlogs to the paths of the target segment with the log ids returned by logIDOf,
+// every file is verified against its etag or checksum in the manifest if present, and the CRC-32C checksum of the binlog if set.
+// The binlogs copied keep all the fields of the source ones but the paths and the log ids.
+func (c *binlogCopier) copyBinlogs(ctx context.Context, binlogType storage.BinlogType,
+	fieldBinlogs []*datapb.FieldBinlog, collectionID, partitionID, segmentID UniqueID,
+	logIDOf func(l *datapb.Binlog) (UniqueID, error),
+) ([]*datapb.FieldBinlog, error) {
+	restored := make([]*datapb.FieldBinlog, 0, len(fieldBinlogs))
+	for _, fieldBinlog := range fieldBinlogs {
+		binlogs := make([]*datapb.Binlog, 0, len(fieldBinlog.GetBinlogs()))
+		for _, l := range fieldBinlog.GetBinlogs() {
+			logID, err := logIDOf(l)
+			if err != nil {
+				return nil, err
+			}
+			var target string
+			rootPath := storage.LayoutRootPath(c.cm.RootPath(), collectionID)
+			switch binlogType {
+			case storage.InsertBinlog:
+				target = metautil.BuildInsertLogPath(rootPath, collectionID, partitionID, segmentID, fieldBinlog.GetFieldID(), logID)
+			case storage.StatsBinlog:
+				target = metautil.BuildStatsLogPath(rootPath, collectionID, partitionID, segmentID, fieldBinlog.GetFieldID(), logID)
+			case storage.DeleteBinlog:
+				target = metautil.BuildDeltaLogPath(rootPath, collectionID, partitionID, segmentID, logID)
+			default:
+				return nil, merr.WrapErrParameterInvalidMsg("unsupported binlog type %d", binlogType)
+			}
+
+			file := c.files[l.GetLogPath()]
+			if err := c.verifyEtag(ctx, file); err != nil {
+				return nil, err
+			}
+			data, err := c.cm.Read(ctx, l.GetLogPath())
+			if err != nil {
+				return nil, err
+			}
+			if file.GetChecksum() != "" {
+				actual := md5.Sum(data)
+				if hex.EncodeToString(actual[:]) != file.GetChecksum() {
+					return nil, merr.WrapErrIoFailedReason("checksum mismatch", l.GetLogPath())
+				}
+			}
+			if err := storage.VerifyBinlogChecksum(l.GetLogPath(), data, l.GetChecksum()); err != nil {
+				return nil, err
+			}
+			// tracked before written, a failed write may leave a partial object
+			c.copied = append(c.copied, target)
+			if err := c.cm.Write(ctx, target, data); err != nil {
+				return nil, err
+			}
+
+			binlog := proto.Clone(l).(*datapb.Binlog)
+			binlog.LogPath = target
+			binlog.LogID = logID
+			binlogs = append(binlogs, binlog)
+		}
+		restored = append(restored, &datapb.FieldBinlog{
+			FieldID: fieldBinlog.GetFieldID(),
+			Binlogs: binlogs,
+		})
+	}
+	return restored, nil
+}
+
+// verifyEtag checks the etag of the file against the one in the manifest, which changes once the file is rewritten.
+func (c *binlogCopier) verifyEtag(ctx context.Context, file *datapb.BackupFile) error {
+	etagCM, ok := c.cm.(storage.EtagChunkManager)
+	if file.GetEtag() == "" || !ok {
+		return nil
+	}
+	etag, err := etagCM.Etag(ctx, file.GetPath())
+	if err != nil {
+		return err
+	}
+	if etag != file.GetEtag() {
+		return merr.WrapErrIoFailedReason("etag mismatch", file.GetPath())
+	}
+	return nil
+}
+
+// rollback removes the binlogs copied, which are referenced by no segment as the target segments are not added.
+// The ones failed to remove are left to the garbage collector, which recycles them as orphans.
+func (c *binlogCopier) rollback(ctx context.Context) {
+	if len(c.copied) == 0 {
+		return
+	}
+	if err := c.cm.MultiRemove(ctx, c.copied); err != nil {
+		log.Ctx(ctx).Warn("failed to remove the copied binlogs, left to gc", zap.Int("num", len(c.copied)), zap.Error(err))
+		return
+	}
+	c.copied = nil
+}
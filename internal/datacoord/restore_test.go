@@ -0,0 +1,130 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+)
+
+func TestMapRestoreChannels(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		mapping, err := mapRestoreChannels(
+			[]string{"by-dev-rootcoord-dml_0_100v0", "by-dev-rootcoord-dml_1_100v1"},
+			[]string{"by-dev-rootcoord-dml_5_200v1", "by-dev-rootcoord-dml_4_200v0"},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"by-dev-rootcoord-dml_0_100v0": "by-dev-rootcoord-dml_4_200v0",
+			"by-dev-rootcoord-dml_1_100v1": "by-dev-rootcoord-dml_5_200v1",
+		}, mapping)
+	})
+
+	t.Run("shards num mismatch", func(t *testing.T) {
+		_, err := mapRestoreChannels(
+			[]string{"by-dev-rootcoord-dml_0_100v0", "by-dev-rootcoord-dml_1_100v1"},
+			[]string{"by-dev-rootcoord-dml_4_200v0"},
+		)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("invalid channel", func(t *testing.T) {
+		_, err := mapRestoreChannels([]string{"by-dev-rootcoord-dml_0_100"}, []string{"by-dev-rootcoord-dml_4_200v0"})
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+}
+
+func TestBinlogCopier(t *testing.T) {
+	ctx := context.Background()
+	cm := storage.NewLocalChunkManager(storage.RootPath(t.TempDir()))
+	source := metautil.BuildInsertLogPath(cm.RootPath(), 100, 10, 1, 1, 11)
+	require.NoError(t, cm.Write(ctx, source, []byte("insert")))
+	fieldBinlogs := []*datapb.FieldBinlog{{FieldID: 1, Binlogs: []*datapb.Binlog{{
+		EntriesNum: 10,
+		LogPath:    source,
+		LogID:      11,
+		Checksum:   storage.BinlogChecksum([]byte("insert")),
+		ZoneMap:    &datapb.ZoneMap{IntMin: 1, IntMax: 10},
+	}}}}
+
+	t.Run("restore", func(t *testing.T) {
+		copier := newBinlogCopier(cm, nil)
+		restored, err := copier.restoreBinlogs(ctx, storage.InsertBinlog, fieldBinlogs, 200, 20, 2)
+		require.NoError(t, err)
+		target := metautil.BuildInsertLogPath(cm.RootPath(), 200, 20, 2, 1, 11)
+		require.Len(t, restored, 1)
+		binlog := restored[0].GetBinlogs()[0]
+		assert.Equal(t, target, binlog.GetLogPath())
+		assert.EqualValues(t, 11, binlog.GetLogID())
+		// all the other fields are kept
+		assert.EqualValues(t, 10, binlog.GetEntriesNum())
+		assert.Equal(t, storage.BinlogChecksum([]byte("insert")), binlog.GetChecksum())
+		assert.EqualValues(t, 10, binlog.GetZoneMap().GetIntMax())
+		// the source is untouched
+		assert.Equal(t, source, fieldBinlogs[0].GetBinlogs()[0].GetLogPath())
+
+		data, err := cm.Read(ctx, target)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("insert"), data)
+
+		copier.rollback(ctx)
+		exist, err := cm.Exist(ctx, target)
+		require.NoError(t, err)
+		assert.False(t, exist)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		copier := newBinlogCopier(cm, nil)
+		_, err := copier.restoreBinlogs(ctx, storage.InsertBinlog, fieldBinlogs, 200, 20, 3)
+		require.NoError(t, err)
+		missing := []*datapb.FieldBinlog{{FieldID: 1, Binlogs: []*datapb.Binlog{{LogPath: metautil.BuildInsertLogPath(cm.RootPath(), 100, 10, 1, 1, 12)}}}}
+		_, err = copier.restoreBinlogs(ctx, storage.InsertBinlog, missing, 200, 20, 3)
+		require.Error(t, err)
+
+		// the copies of the failed restore are removed
+		copier.rollback(ctx)
+		keys, _, err := cm.ListWithPrefix(ctx, metautil.BuildInsertLogPath(cm.RootPath(), 200, 20, 3, 1, 11), false)
+		require.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		copier := newBinlogCopier(cm, []*datapb.BackupFile{{Path: source, Checksum: "mismatch"}})
+		_, err := copier.restoreBinlogs(ctx, storage.InsertBinlog, fieldBinlogs, 200, 20, 4)
+		assert.ErrorIs(t, err, merr.ErrIoFailed)
+	})
+	t.Run("etag", func(t *testing.T) {
+		etag, err := cm.Etag(ctx, source)
+		require.NoError(t, err)
+		copier := newBinlogCopier(cm, []*datapb.BackupFile{{Path: source, Etag: etag}})
+		_, err = copier.restoreBinlogs(ctx, storage.InsertBinlog, fieldBinlogs, 200, 20, 5)
+		assert.NoError(t, err)
+
+		// rewritten after the backup
+		copier = newBinlogCopier(cm, []*datapb.BackupFile{{Path: source, Etag: "stale"}})
+		_, err = copier.restoreBinlogs(ctx, storage.InsertBinlog, fieldBinlogs, 200, 20, 6)
+		assert.ErrorIs(t, err, merr.ErrIoFailed)
+	})
+}
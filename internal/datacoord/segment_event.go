@@ -0,0 +1,234 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/metastore"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/log"
+)
+
+const (
+	segmentEventActorDataCoord  = "datacoord"
+	segmentEventActorCompaction = "compaction"
+	segmentEventActorGC         = "garbage-collector"
+)
+
+func dataNodeActor(nodeID int64) string {
+	return fmt.Sprintf("datanode-%d", nodeID)
+}
+
+func indexNodeActor(nodeID int64) string {
+	return fmt.Sprintf("indexnode-%d", nodeID)
+}
+
+func saveBinlogPathsReason(req *datapb.SaveBinlogPathsRequest) string {
+	switch {
+	case req.GetSegLevel() == datapb.SegmentLevel_L0:
+		return "L0 segment saved"
+	case req.GetDropped():
+		return "segment dropped by datanode"
+	case req.GetFlushed():
+		return "segment flushed by datanode"
+	case req.GetImporting():
+		return "segment imported by datanode"
+	default:
+		return "binlogs saved by datanode"
+	}
+}
+
+const (
+	// segmentEventFlushInterval is the interval to persist the recorded segment events in batch
+	segmentEventFlushInterval = time.Second
+	// segmentEventMaxPending is the max number of the events pending to persist, the events beyond are dropped
+	segmentEventMaxPending = 10000
+	// segmentEventRecycleInterval is the interval to recycle the expired events, which lists all the events
+	segmentEventRecycleInterval = time.Hour
+)
+
+// segmentEventLog persists the lifecycle events of segments, so that the state transitions
+// could be queried after an incident instead of grepping the logs of all the nodes.
+// Recording is best-effort, the events are persisted in batch in background,
+// so that the meta updates triggering them never wait for or fail by the persistence.
+type segmentEventLog struct {
+	catalog metastore.DataCoordCatalog
+
+	mu      sync.Mutex
+	lastID  int64
+	pending []*datapb.SegmentEvent
+
+	lastRecycleTime time.Time
+
+	closeCh   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func newSegmentEventLog(catalog metastore.DataCoordCatalog) *segmentEventLog {
+	return &segmentEventLog{
+		catalog: catalog,
+		closeCh: make(chan struct{}),
+	}
+}
+
+// start starts the background persistence of the recorded events.
+func (l *segmentEventLog) start() {
+	if l == nil {
+		return
+	}
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		ticker := time.NewTicker(segmentEventFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				l.flush()
+			case <-l.closeCh:
+				l.flush()
+				return
+			}
+		}
+	}()
+}
+
+// close stops the background persistence after persisting the pending events.
+func (l *segmentEventLog) close() {
+	if l == nil {
+		return
+	}
+	l.closeOnce.Do(func() {
+		close(l.closeCh)
+		l.wg.Wait()
+	})
+}
+
+// getSegmentState returns the state of the segment, SegmentStateNone if the segment doesn't exist.
+func getSegmentState(segment *SegmentInfo) commonpb.SegmentState {
+	if segment == nil {
+		return commonpb.SegmentState_SegmentStateNone
+	}
+	return segment.GetState()
+}
+
+// newSegmentStateEvent creates the event of segment transiting from @from to its current state.
+func newSegmentStateEvent(segment *SegmentInfo, from commonpb.SegmentState, actor, reason string) *datapb.SegmentEvent {
+	return &datapb.SegmentEvent{
+		CollectionID: segment.GetCollectionID(),
+		PartitionID:  segment.GetPartitionID(),
+		SegmentID:    segment.GetID(),
+		Type:         datapb.SegmentEventType_SegmentStateChanged,
+		FromState:    from,
+		ToState:      segment.GetState(),
+		Actor:        actor,
+		Reason:       reason,
+	}
+}
+
+// record queues the events to persist, it's a no-op if the log is nil or disabled.
+func (l *segmentEventLog) record(events ...*datapb.SegmentEvent) {
+	if l == nil || len(events) == 0 || !Params.DataCoordCfg.SegmentEventEnabled.GetAsBool() {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.pending)+len(events) > segmentEventMaxPending {
+		log.RatedWarn(10, "too many pending segment events, drop the events", zap.Int("eventNum", len(events)))
+		return
+	}
+	for _, event := range events {
+		// event ID is the unix nanoseconds, and keeps increasing even if the clock goes back
+		id := time.Now().UnixNano()
+		if id <= l.lastID {
+			id = l.lastID + 1
+		}
+		l.lastID = id
+		event.EventID = id
+	}
+	l.pending = append(l.pending, events...)
+}
+
+// flush persists the pending events.
+func (l *segmentEventLog) flush() {
+	l.mu.Lock()
+	events := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+	if len(events) == 0 {
+		return
+	}
+	if err := l.catalog.SaveSegmentEvents(context.TODO(), events); err != nil {
+		log.Warn("failed to persist segment events", zap.Int("eventNum", len(events)), zap.Error(err))
+	}
+}
+
+// list returns the events matching the request ordered by time.
+func (l *segmentEventLog) list(ctx context.Context, req *datapb.GetSegmentEventsRequest) ([]*datapb.SegmentEvent, error) {
+	events, err := l.catalog.ListSegmentEvents(ctx, req.GetCollectionID())
+	if err != nil {
+		return nil, err
+	}
+	events = lo.Filter(events, func(event *datapb.SegmentEvent, _ int) bool {
+		eventTime := time.Unix(0, event.GetEventID()).UnixMilli()
+		return (req.GetSegmentID() == 0 || event.GetSegmentID() == req.GetSegmentID()) &&
+			(req.GetStartTime() == 0 || eventTime >= req.GetStartTime()) &&
+			(req.GetEndTime() == 0 || eventTime <= req.GetEndTime())
+	})
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].GetEventID() < events[j].GetEventID()
+	})
+	if req.GetLimit() > 0 && int64(len(events)) > req.GetLimit() {
+		events = events[int64(len(events))-req.GetLimit():]
+	}
+	return events, nil
+}
+
+// recycle removes the events older than the retention, at most once per segmentEventRecycleInterval.
+func (l *segmentEventLog) recycle(ctx context.Context) {
+	if l == nil || time.Since(l.lastRecycleTime) < segmentEventRecycleInterval {
+		return
+	}
+	l.lastRecycleTime = time.Now()
+	events, err := l.catalog.ListSegmentEvents(ctx, 0)
+	if err != nil {
+		log.Warn("failed to list segment events", zap.Error(err))
+		return
+	}
+	expireTime := time.Now().Add(-Params.DataCoordCfg.SegmentEventRetention.GetAsDuration(time.Second)).UnixNano()
+	expired := lo.Filter(events, func(event *datapb.SegmentEvent, _ int) bool {
+		return event.GetEventID() < expireTime
+	})
+	if len(expired) == 0 {
+		return
+	}
+	if err := l.catalog.DropSegmentEvents(ctx, expired); err != nil {
+		log.Warn("failed to recycle expired segment events", zap.Error(err))
+		return
+	}
+	log.Info("recycle expired segment events done", zap.Int("eventNum", len(expired)))
+}
@@ -0,0 +1,232 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/metastore/mocks"
+	"github.com/milvus-io/milvus/internal/metastore/model"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type SegmentEventLogSuite struct {
+	suite.Suite
+
+	meta *meta
+}
+
+func (s *SegmentEventLogSuite) SetupSuite() {
+	paramtable.Get().Save(Params.DataCoordCfg.SegmentEventEnabled.Key, "true")
+}
+
+func (s *SegmentEventLogSuite) TearDownSuite() {
+	paramtable.Get().Reset(Params.DataCoordCfg.SegmentEventEnabled.Key)
+}
+
+func (s *SegmentEventLogSuite) SetupTest() {
+	var err error
+	s.meta, err = newMemoryMeta()
+	s.Require().NoError(err)
+	s.meta.eventLog = newSegmentEventLog(s.meta.catalog)
+}
+
+func (s *SegmentEventLogSuite) listEvents(req *datapb.GetSegmentEventsRequest) []*datapb.SegmentEvent {
+	s.meta.eventLog.flush()
+	events, err := s.meta.eventLog.list(context.TODO(), req)
+	s.Require().NoError(err)
+	return events
+}
+
+func (s *SegmentEventLogSuite) TestLifecycle() {
+	segment := NewSegmentInfo(&datapb.SegmentInfo{
+		ID:           1,
+		CollectionID: 100,
+		PartitionID:  10,
+		State:        commonpb.SegmentState_Growing,
+	})
+	s.Require().NoError(s.meta.AddSegment(context.TODO(), segment))
+	s.Require().NoError(s.meta.SetState(1, commonpb.SegmentState_Sealed, "sealed by segment seal policy"))
+	// setting the same state records nothing
+	s.Require().NoError(s.meta.SetState(1, commonpb.SegmentState_Sealed, "sealed again"))
+	s.Require().NoError(s.meta.UpdateSegmentsInfo(
+		SegmentEventOperator(dataNodeActor(5), "segment flushed by datanode"),
+		UpdateStatusOperator(1, commonpb.SegmentState_Flushed),
+	))
+
+	s.meta.buildID2SegmentIndex[1000] = &model.SegmentIndex{
+		SegmentID:    1,
+		CollectionID: 100,
+		PartitionID:  10,
+		IndexID:      200,
+		BuildID:      1000,
+		NodeID:       6,
+		IndexState:   commonpb.IndexState_InProgress,
+	}
+	s.Require().NoError(s.meta.FinishTask(&indexpb.IndexTaskInfo{BuildID: 1000, State: commonpb.IndexState_Finished}))
+
+	s.Require().NoError(s.meta.SetState(1, commonpb.SegmentState_Dropped, "marked dropped by request"))
+	s.Require().NoError(s.meta.DropSegment(1))
+
+	events := s.listEvents(&datapb.GetSegmentEventsRequest{CollectionID: 100, SegmentID: 1})
+	s.Require().Len(events, 6)
+	for i := 1; i < len(events); i++ {
+		s.Less(events[i-1].GetEventID(), events[i].GetEventID())
+	}
+	expected := []struct {
+		eventType datapb.SegmentEventType
+		from, to  commonpb.SegmentState
+		actor     string
+	}{
+		{datapb.SegmentEventType_SegmentStateChanged, commonpb.SegmentState_SegmentStateNone, commonpb.SegmentState_Growing, segmentEventActorDataCoord},
+		{datapb.SegmentEventType_SegmentStateChanged, commonpb.SegmentState_Growing, commonpb.SegmentState_Sealed, segmentEventActorDataCoord},
+		{datapb.SegmentEventType_SegmentStateChanged, commonpb.SegmentState_Sealed, commonpb.SegmentState_Flushed, dataNodeActor(5)},
+		{datapb.SegmentEventType_SegmentIndexed, commonpb.SegmentState_Flushed, commonpb.SegmentState_Flushed, indexNodeActor(6)},
+		{datapb.SegmentEventType_SegmentStateChanged, commonpb.SegmentState_Flushed, commonpb.SegmentState_Dropped, segmentEventActorDataCoord},
+		{datapb.SegmentEventType_SegmentRecycled, commonpb.SegmentState_Dropped, commonpb.SegmentState_Dropped, segmentEventActorGC},
+	}
+	for i, e := range expected {
+		s.Equal(e.eventType, events[i].GetType(), "event %d", i)
+		s.Equal(e.from, events[i].GetFromState(), "event %d", i)
+		s.Equal(e.to, events[i].GetToState(), "event %d", i)
+		s.Equal(e.actor, events[i].GetActor(), "event %d", i)
+		s.EqualValues(10, events[i].GetPartitionID())
+	}
+	s.EqualValues(200, events[3].GetIndexID())
+	s.Equal("segment flushed by datanode", events[2].GetReason())
+
+	// latest N events
+	events = s.listEvents(&datapb.GetSegmentEventsRequest{SegmentID: 1, Limit: 2})
+	s.Require().Len(events, 2)
+	s.Equal(datapb.SegmentEventType_SegmentRecycled, events[1].GetType())
+
+	// filter by time
+	s.Empty(s.listEvents(&datapb.GetSegmentEventsRequest{EndTime: time.Now().Add(-time.Hour).UnixMilli()}))
+	s.Empty(s.listEvents(&datapb.GetSegmentEventsRequest{StartTime: time.Now().Add(time.Hour).UnixMilli()}))
+	s.Empty(s.listEvents(&datapb.GetSegmentEventsRequest{CollectionID: 101}))
+}
+
+func (s *SegmentEventLogSuite) TestDisabled() {
+	paramtable.Get().Save(Params.DataCoordCfg.SegmentEventEnabled.Key, "false")
+	defer paramtable.Get().Save(Params.DataCoordCfg.SegmentEventEnabled.Key, "true")
+
+	s.Require().NoError(s.meta.AddSegment(context.TODO(), NewSegmentInfo(&datapb.SegmentInfo{ID: 1, State: commonpb.SegmentState_Growing})))
+	s.Empty(s.listEvents(&datapb.GetSegmentEventsRequest{}))
+
+	// nil event log records nothing
+	s.meta.eventLog = nil
+	s.NotPanics(func() {
+		s.meta.eventLog.start()
+		s.meta.eventLog.record(&datapb.SegmentEvent{})
+		s.meta.eventLog.recycle(context.TODO())
+		s.meta.eventLog.close()
+	})
+}
+
+func (s *SegmentEventLogSuite) TestRecycle() {
+	s.meta.eventLog.record(&datapb.SegmentEvent{CollectionID: 100, SegmentID: 1})
+	s.meta.eventLog.flush()
+	s.meta.eventLog.recycle(context.TODO())
+	s.Len(s.listEvents(&datapb.GetSegmentEventsRequest{}), 1)
+
+	paramtable.Get().Save(Params.DataCoordCfg.SegmentEventRetention.Key, "0")
+	defer paramtable.Get().Reset(Params.DataCoordCfg.SegmentEventRetention.Key)
+	// recycled at most once per interval
+	s.meta.eventLog.recycle(context.TODO())
+	s.Len(s.listEvents(&datapb.GetSegmentEventsRequest{}), 1)
+
+	s.meta.eventLog.lastRecycleTime = time.Time{}
+	s.meta.eventLog.recycle(context.TODO())
+	s.Empty(s.listEvents(&datapb.GetSegmentEventsRequest{}))
+}
+
+func (s *SegmentEventLogSuite) TestBackground() {
+	s.meta.eventLog.start()
+	s.meta.eventLog.record(&datapb.SegmentEvent{CollectionID: 100, SegmentID: 1})
+	s.Eventually(func() bool {
+		events, err := s.meta.catalog.ListSegmentEvents(context.TODO(), 100)
+		return err == nil && len(events) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// the pending events are persisted on close
+	s.meta.eventLog.record(&datapb.SegmentEvent{CollectionID: 100, SegmentID: 2})
+	s.meta.eventLog.close()
+	events, err := s.meta.catalog.ListSegmentEvents(context.TODO(), 100)
+	s.NoError(err)
+	s.Len(events, 2)
+
+	// the events beyond the max pending are dropped
+	for i := 0; i < segmentEventMaxPending+1; i++ {
+		s.meta.eventLog.record(&datapb.SegmentEvent{CollectionID: 101, SegmentID: 1})
+	}
+	s.Len(s.meta.eventLog.pending, segmentEventMaxPending)
+}
+
+func (s *SegmentEventLogSuite) TestCatalogFailure() {
+	catalog := mocks.NewDataCoordCatalog(s.T())
+	catalog.EXPECT().SaveSegmentEvents(mock.Anything, mock.Anything).Return(errors.New("mock"))
+	catalog.EXPECT().ListSegmentEvents(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+	eventLog := newSegmentEventLog(catalog)
+
+	// recording failure is ignored
+	eventLog.record(&datapb.SegmentEvent{SegmentID: 1})
+	eventLog.flush()
+	eventLog.recycle(context.TODO())
+	_, err := eventLog.list(context.TODO(), &datapb.GetSegmentEventsRequest{})
+	s.Error(err)
+}
+
+func TestSegmentEventLog(t *testing.T) {
+	suite.Run(t, new(SegmentEventLogSuite))
+}
+
+func TestServer_GetSegmentEvents(t *testing.T) {
+	paramtable.Get().Save(Params.DataCoordCfg.SegmentEventEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.DataCoordCfg.SegmentEventEnabled.Key)
+	svr := newTestServer(t, nil)
+
+	err := svr.meta.AddSegment(context.TODO(), NewSegmentInfo(&datapb.SegmentInfo{ID: 1, CollectionID: 100, State: commonpb.SegmentState_Growing}))
+	assert.NoError(t, err)
+	svr.meta.eventLog.flush()
+	resp, err := svr.GetSegmentEvents(context.TODO(), &datapb.GetSegmentEventsRequest{CollectionID: 100})
+	assert.NoError(t, merr.CheckRPCCall(resp, err))
+	assert.Len(t, resp.GetEvents(), 1)
+	assert.Equal(t, commonpb.SegmentState_Growing, resp.GetEvents()[0].GetToState())
+
+	eventLog := svr.meta.eventLog
+	svr.meta.eventLog = nil
+	resp, err = svr.GetSegmentEvents(context.TODO(), &datapb.GetSegmentEventsRequest{})
+	assert.NoError(t, err)
+	assert.False(t, merr.Ok(resp.GetStatus()))
+	svr.meta.eventLog = eventLog
+
+	closeTestServer(t, svr)
+	resp, err = svr.GetSegmentEvents(context.TODO(), &datapb.GetSegmentEventsRequest{})
+	assert.NoError(t, err)
+	assert.False(t, merr.Ok(resp.GetStatus()))
+}
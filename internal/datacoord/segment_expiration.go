@@ -0,0 +1,167 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+// segmentExpirer drops the flushed segments whose rows are all expired by the ttl of their partitions,
+// i.e. the max timestamp of the rows is before the ttl. The dropped segments are excluded from the query targets
+// and recycled by the garbage collector, without waiting for the compactions to expire the rows one by one.
+// The segments frozen by the garbage collector for the backups are not dropped until they are unfrozen.
+type segmentExpirer struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	meta      *meta
+	handler   Handler
+	allocator allocator
+	gc        *garbageCollector
+}
+
+func newSegmentExpirer(ctx context.Context, meta *meta, handler Handler, allocator allocator, gc *garbageCollector) *segmentExpirer {
+	ctx, cancel := context.WithCancel(ctx)
+	return &segmentExpirer{
+		ctx:       ctx,
+		cancel:    cancel,
+		meta:      meta,
+		handler:   handler,
+		allocator: allocator,
+		gc:        gc,
+	}
+}
+
+func (e *segmentExpirer) start() {
+	e.wg.Add(1)
+	go e.loop()
+}
+
+func (e *segmentExpirer) close() {
+	e.cancel()
+	e.wg.Wait()
+}
+
+func (e *segmentExpirer) loop() {
+	defer e.wg.Done()
+	ticker := time.NewTicker(Params.DataCoordCfg.SegmentExpirationCheckInterval.GetAsDuration(time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.ctx.Done():
+			log.Info("segment expiration loop quit")
+			return
+		case <-ticker.C:
+			if Params.DataCoordCfg.SegmentExpirationEnabled.GetAsBool() {
+				e.expire()
+			}
+		}
+	}
+}
+
+// expire drops the segments expired, the ones compacting, importing or frozen are checked next time.
+func (e *segmentExpirer) expire() {
+	ctx, cancel := context.WithTimeout(e.ctx, 5*time.Second)
+	ts, err := e.allocator.allocTimestamp(ctx)
+	cancel()
+	if err != nil {
+		log.Warn("failed to alloc timestamp for segment expiration", zap.Error(err))
+		return
+	}
+	now, _ := tsoutil.ParseTS(ts)
+
+	segments := e.meta.SelectSegments(e.isExpirable)
+	collections := make(map[UniqueID]*collectionInfo)
+	for _, segment := range segments {
+		log := log.With(zap.Int64("collectionID", segment.GetCollectionID()),
+			zap.Int64("partitionID", segment.GetPartitionID()),
+			zap.Int64("segmentID", segment.GetID()))
+		coll, ok := collections[segment.GetCollectionID()]
+		if !ok {
+			coll, err = e.handler.GetCollection(e.ctx, segment.GetCollectionID())
+			if err != nil {
+				log.Warn("failed to get collection for segment expiration", zap.Error(err))
+				continue
+			}
+			collections[segment.GetCollectionID()] = coll
+		}
+		if coll == nil {
+			continue
+		}
+		ttl, err := getPartitionTTL(coll.Properties, segment.GetPartitionID())
+		if err != nil {
+			log.Warn("failed to get partition ttl", zap.Error(err))
+			continue
+		}
+		if ttl <= 0 {
+			continue
+		}
+
+		maxTs := getSegmentMaxTimestamp(segment)
+		if maxTs == 0 || maxTs >= tsoutil.ComposeTSByTime(now.Add(-ttl), 0) {
+			continue
+		}
+		// the segment may be picked by a compaction or frozen by a backup since selected,
+		// check it again under the meta lock
+		dropped, err := e.meta.SetStateIf(segment.GetID(), commonpb.SegmentState_Dropped, "expired by ttl", e.isExpirable)
+		if err != nil {
+			log.Warn("failed to drop expired segment", zap.Error(err))
+			continue
+		}
+		if !dropped {
+			log.Info("segment changed since selected, skip expiration")
+			continue
+		}
+		log.Info("segment expired by ttl, dropped", zap.Duration("ttl", ttl),
+			zap.Time("maxTime", tsoutil.PhysicalTime(maxTs)), zap.Int64("numRows", segment.GetNumOfRows()))
+	}
+}
+
+func (e *segmentExpirer) isExpirable(segment *SegmentInfo) bool {
+	return segment.GetState() == commonpb.SegmentState_Flushed &&
+		segment.GetLevel() != datapb.SegmentLevel_L0 &&
+		!segment.GetIsImporting() &&
+		!segment.isCompacting &&
+		!e.gc.isFrozen(segment.GetID())
+}
+
+// getSegmentMaxTimestamp returns the max timestamp of the rows of the segment by the insert binlogs,
+// or by the dml position if the binlogs have no timestamps.
+func getSegmentMaxTimestamp(segment *SegmentInfo) Timestamp {
+	var maxTs Timestamp
+	for _, fieldBinlog := range segment.GetBinlogs() {
+		for _, binlog := range fieldBinlog.GetBinlogs() {
+			if binlog.GetTimestampTo() > maxTs {
+				maxTs = binlog.GetTimestampTo()
+			}
+		}
+	}
+	if maxTs == 0 {
+		maxTs = segment.GetDmlPosition().GetTimestamp()
+	}
+	return maxTs
+}
@@ -0,0 +1,191 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+type SegmentExpirerSuite struct {
+	suite.Suite
+
+	now       time.Time
+	meta      *meta
+	handler   *NMockHandler
+	allocator *NMockAllocator
+	gc        *garbageCollector
+	expirer   *segmentExpirer
+}
+
+func (s *SegmentExpirerSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (s *SegmentExpirerSuite) SetupTest() {
+	var err error
+	s.now = time.Now()
+	s.meta, err = newMemoryMeta()
+	s.Require().NoError(err)
+	s.handler = NewNMockHandler(s.T())
+	s.allocator = NewNMockAllocator(s.T())
+	s.gc = newGarbageCollector(s.meta, s.handler, GcOption{})
+	s.expirer = newSegmentExpirer(context.TODO(), s.meta, s.handler, s.allocator, s.gc)
+}
+
+func (s *SegmentExpirerSuite) addSegment(segmentID, collectionID, partitionID int64, maxTime time.Time, opts ...func(segment *datapb.SegmentInfo)) {
+	segment := &datapb.SegmentInfo{
+		ID:           segmentID,
+		CollectionID: collectionID,
+		PartitionID:  partitionID,
+		NumOfRows:    100,
+		State:        commonpb.SegmentState_Flushed,
+		Level:        datapb.SegmentLevel_L1,
+		Binlogs: []*datapb.FieldBinlog{
+			{FieldID: 100, Binlogs: []*datapb.Binlog{
+				{EntriesNum: 50, LogID: segmentID, TimestampFrom: tsoutil.ComposeTSByTime(maxTime.Add(-time.Minute), 0), TimestampTo: tsoutil.ComposeTSByTime(maxTime.Add(-time.Second), 0)},
+				{EntriesNum: 50, LogID: segmentID + 1000, TimestampFrom: tsoutil.ComposeTSByTime(maxTime.Add(-time.Second), 0), TimestampTo: tsoutil.ComposeTSByTime(maxTime, 0)},
+			}},
+		},
+	}
+	for _, opt := range opts {
+		opt(segment)
+	}
+	s.Require().NoError(s.meta.AddSegment(context.TODO(), NewSegmentInfo(segment)))
+}
+
+func (s *SegmentExpirerSuite) TestExpire() {
+	s.allocator.EXPECT().allocTimestamp(mock.Anything).Return(tsoutil.ComposeTSByTime(s.now, 0), nil)
+	s.handler.EXPECT().GetCollection(mock.Anything, int64(1)).Return(&collectionInfo{
+		ID: 1,
+		Properties: map[string]string{
+			common.CollectionTTLConfigKey:             "3600",
+			common.PartitionTTLConfigKeyPrefix + "20": "86400",
+		},
+	}, nil).Once()
+	s.handler.EXPECT().GetCollection(mock.Anything, int64(2)).Return(&collectionInfo{ID: 2}, nil).Once()
+	s.handler.EXPECT().GetCollection(mock.Anything, int64(3)).Return(nil, errors.New("mock")).Once()
+
+	s.addSegment(1, 1, 10, s.now.Add(-2*time.Hour))
+	s.addSegment(2, 1, 10, s.now.Add(-10*time.Minute))
+	// the partition ttl overrides the collection ttl
+	s.addSegment(3, 1, 20, s.now.Add(-2*time.Hour))
+	s.addSegment(4, 1, 10, s.now.Add(-2*time.Hour))
+	s.meta.SetSegmentCompacting(4, true)
+	s.addSegment(5, 1, 10, s.now.Add(-2*time.Hour), func(segment *datapb.SegmentInfo) {
+		segment.Level = datapb.SegmentLevel_L0
+	})
+	s.addSegment(6, 1, 10, s.now.Add(-2*time.Hour), func(segment *datapb.SegmentInfo) {
+		segment.State = commonpb.SegmentState_Flushing
+	})
+	// expired by the dml position if the binlogs have no timestamps
+	s.addSegment(7, 1, 10, s.now.Add(-2*time.Hour), func(segment *datapb.SegmentInfo) {
+		segment.Binlogs = []*datapb.FieldBinlog{{FieldID: 100, Binlogs: []*datapb.Binlog{{EntriesNum: 100, LogID: 7}}}}
+		segment.DmlPosition = &msgpb.MsgPosition{Timestamp: tsoutil.ComposeTSByTime(s.now.Add(-2*time.Hour), 0)}
+	})
+	// no ttl
+	s.addSegment(8, 2, 30, s.now.Add(-2*time.Hour))
+	s.addSegment(9, 3, 40, s.now.Add(-2*time.Hour))
+	// frozen by a backup
+	s.addSegment(10, 1, 10, s.now.Add(-2*time.Hour))
+	s.gc.FreezeSegments(10)
+
+	s.expirer.expire()
+
+	for segmentID, state := range map[int64]commonpb.SegmentState{
+		1:  commonpb.SegmentState_Dropped,
+		2:  commonpb.SegmentState_Flushed,
+		3:  commonpb.SegmentState_Flushed,
+		4:  commonpb.SegmentState_Flushed,
+		5:  commonpb.SegmentState_Flushed,
+		6:  commonpb.SegmentState_Flushing,
+		7:  commonpb.SegmentState_Dropped,
+		8:  commonpb.SegmentState_Flushed,
+		9:  commonpb.SegmentState_Flushed,
+		10: commonpb.SegmentState_Flushed,
+	} {
+		s.Equal(state, s.meta.GetSegment(segmentID).GetState(), "segment %d", segmentID)
+	}
+}
+
+func (s *SegmentExpirerSuite) TestUnfrozen() {
+	s.allocator.EXPECT().allocTimestamp(mock.Anything).Return(tsoutil.ComposeTSByTime(s.now, 0), nil)
+	s.handler.EXPECT().GetCollection(mock.Anything, int64(1)).Return(&collectionInfo{
+		ID:         1,
+		Properties: map[string]string{common.CollectionTTLConfigKey: "3600"},
+	}, nil).Once()
+	s.addSegment(1, 1, 10, s.now.Add(-2*time.Hour))
+	s.gc.FreezeSegments(1)
+	s.gc.UnfreezeSegments(1)
+
+	s.expirer.expire()
+	s.Equal(commonpb.SegmentState_Dropped, s.meta.GetSegment(1).GetState())
+}
+
+func (s *SegmentExpirerSuite) TestChangedSinceSelected() {
+	s.allocator.EXPECT().allocTimestamp(mock.Anything).Return(tsoutil.ComposeTSByTime(s.now, 0), nil)
+	s.addSegment(1, 1, 10, s.now.Add(-2*time.Hour))
+	s.addSegment(2, 2, 20, s.now.Add(-2*time.Hour))
+	coll := func(collectionID int64) *collectionInfo {
+		return &collectionInfo{
+			ID:         collectionID,
+			Properties: map[string]string{common.CollectionTTLConfigKey: "3600"},
+		}
+	}
+	// picked by a compaction and frozen by a backup after the segments are selected
+	s.handler.EXPECT().GetCollection(mock.Anything, int64(1)).Run(func(_ context.Context, _ int64) {
+		s.meta.SetSegmentCompacting(1, true)
+	}).Return(coll(1), nil).Once()
+	s.handler.EXPECT().GetCollection(mock.Anything, int64(2)).Run(func(_ context.Context, _ int64) {
+		s.gc.FreezeSegments(2)
+	}).Return(coll(2), nil).Once()
+
+	s.expirer.expire()
+	s.Equal(commonpb.SegmentState_Flushed, s.meta.GetSegment(1).GetState())
+	s.Equal(commonpb.SegmentState_Flushed, s.meta.GetSegment(2).GetState())
+}
+
+func (s *SegmentExpirerSuite) TestAllocFailed() {
+	s.allocator.EXPECT().allocTimestamp(mock.Anything).Return(0, errors.New("mock"))
+	s.addSegment(1, 1, 10, s.now.Add(-2*time.Hour))
+
+	s.expirer.expire()
+	s.Equal(commonpb.SegmentState_Flushed, s.meta.GetSegment(1).GetState())
+}
+
+func (s *SegmentExpirerSuite) TestGetSegmentMaxTimestamp() {
+	s.addSegment(1, 1, 10, s.now)
+	s.Equal(tsoutil.ComposeTSByTime(s.now, 0), getSegmentMaxTimestamp(s.meta.GetSegment(1)))
+
+	s.EqualValues(0, getSegmentMaxTimestamp(NewSegmentInfo(&datapb.SegmentInfo{ID: 2})))
+}
+
+func TestSegmentExpirer(t *testing.T) {
+	suite.Run(t, new(SegmentExpirerSuite))
+}
@@ -365,5 +365,37 @@ func (s *SegmentInfo) getSegmentSize() int64 {
 	return s.size.Load()
 }
 
+// getDeleteStats returns the delete amplification of the segment,
+// the deleted rows are the delete entries, which may include the ones of non-existing primary keys.
+func (s *SegmentInfo) getDeleteStats() *datapb.SegmentDeleteStats {
+	stats := &datapb.SegmentDeleteStats{
+		SegmentID: s.GetID(),
+		NumRows:   s.GetNumOfRows(),
+	}
+	for _, binlogs := range s.GetBinlogs() {
+		for _, l := range binlogs.GetBinlogs() {
+			stats.BinlogSize += l.GetLogSize()
+		}
+	}
+	for _, deltaLogs := range s.GetDeltalogs() {
+		for _, l := range deltaLogs.GetBinlogs() {
+			stats.DeletedRows += l.GetEntriesNum()
+			stats.DeltalogSize += l.GetLogSize()
+		}
+	}
+	calcDeleteRatios(stats)
+	return stats
+}
+
+// calcDeleteRatios fills the ratios of the delete stats by the rows and sizes.
+func calcDeleteRatios(stats *datapb.SegmentDeleteStats) {
+	if stats.GetNumRows() > 0 {
+		stats.DeletedRowRatio = float64(stats.GetDeletedRows()) / float64(stats.GetNumRows())
+	}
+	if stats.GetBinlogSize() > 0 {
+		stats.DeltalogSizeRatio = float64(stats.GetDeltalogSize()) / float64(stats.GetBinlogSize())
+	}
+}
+
 // SegmentInfoSelector is the function type to select SegmentInfo from meta
 type SegmentInfoSelector func(*SegmentInfo) bool
@@ -65,12 +65,35 @@ func putAllocation(a *Allocation) {
 	allocPool.Put(a)
 }
 
+// AllocOption is the option of AllocSegment.
+type AllocOption func(opts *allocOptions)
+
+type allocOptions struct {
+	timeBucket *datapb.TimeBucket
+	pkBucket   *datapb.PKBucket
+}
+
+// WithTimeBucket allocates the rows in the segments of the time bucket.
+func WithTimeBucket(timeBucket *datapb.TimeBucket) AllocOption {
+	return func(opts *allocOptions) {
+		opts.timeBucket = timeBucket
+	}
+}
+
+// WithPKBucket allocates the rows in the segments of the primary key bucket.
+func WithPKBucket(pkBucket *datapb.PKBucket) AllocOption {
+	return func(opts *allocOptions) {
+		opts.pkBucket = pkBucket
+	}
+}
+
 // Manager manages segment related operations.
 type Manager interface {
 	// CreateSegment create new segment when segment not exist
 
-	// AllocSegment allocates rows and record the allocation.
-	AllocSegment(ctx context.Context, collectionID, partitionID UniqueID, channelName string, requestRows int64) ([]*Allocation, error)
+	// AllocSegment allocates rows and record the allocation, the rows are allocated in the segments of the
+	// time bucket and the primary key bucket if they are set by the options.
+	AllocSegment(ctx context.Context, collectionID, partitionID UniqueID, channelName string, requestRows int64, opts ...AllocOption) ([]*Allocation, error)
 	// allocSegmentForImport allocates one segment allocation for bulk insert.
 	// TODO: Remove this method and AllocSegment() above instead.
 	allocSegmentForImport(ctx context.Context, collectionID, partitionID UniqueID, channelName string, requestRows int64, taskID int64) (*Allocation, error)
@@ -262,8 +285,13 @@ func (s *SegmentManager) maybeResetLastExpireForSegments() error {
 
 // AllocSegment allocate segment per request collcation, partication, channel and rows
 func (s *SegmentManager) AllocSegment(ctx context.Context, collectionID UniqueID,
-	partitionID UniqueID, channelName string, requestRows int64,
+	partitionID UniqueID, channelName string, requestRows int64, opts ...AllocOption,
 ) ([]*Allocation, error) {
+	options := &allocOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	log := log.Ctx(ctx).
 		With(zap.Int64("collectionID", collectionID)).
 		With(zap.Int64("partitionID", partitionID)).
@@ -282,7 +310,8 @@ func (s *SegmentManager) AllocSegment(ctx context.Context, collectionID UniqueID
 			log.Warn("Failed to get segment info from meta", zap.Int64("id", segmentID))
 			continue
 		}
-		if !satisfy(segment, collectionID, partitionID, channelName) || !isGrowing(segment) || segment.GetLevel() == datapb.SegmentLevel_L0 {
+		if !satisfy(segment, collectionID, partitionID, channelName) || !isGrowing(segment) || segment.GetLevel() == datapb.SegmentLevel_L0 ||
+			!sameTimeBucket(segment.GetTimeBucket(), options.timeBucket) || !samePKBucket(segment.GetPkBucket(), options.pkBucket) {
 			continue
 		}
 		segments = append(segments, segment)
@@ -302,7 +331,7 @@ func (s *SegmentManager) AllocSegment(ctx context.Context, collectionID UniqueID
 		return nil, err
 	}
 	for _, allocation := range newSegmentAllocations {
-		segment, err := s.openNewSegment(ctx, collectionID, partitionID, channelName, commonpb.SegmentState_Growing, datapb.SegmentLevel_L1)
+		segment, err := s.openNewSegment(ctx, collectionID, partitionID, channelName, options.timeBucket, options.pkBucket, commonpb.SegmentState_Growing, datapb.SegmentLevel_L1)
 		if err != nil {
 			log.Error("Failed to open new segment for segment allocation")
 			return nil, err
@@ -344,7 +373,7 @@ func (s *SegmentManager) allocSegmentForImport(ctx context.Context, collectionID
 		return nil, err
 	}
 
-	segment, err := s.openNewSegment(ctx, collectionID, partitionID, channelName, commonpb.SegmentState_Importing, datapb.SegmentLevel_L1)
+	segment, err := s.openNewSegment(ctx, collectionID, partitionID, channelName, nil, nil, commonpb.SegmentState_Importing, datapb.SegmentLevel_L1)
 	if err != nil {
 		return nil, err
 	}
@@ -362,6 +391,22 @@ func satisfy(segment *SegmentInfo, collectionID, partitionID UniqueID, channel s
 		segment.GetInsertChannel() == channel
 }
 
+// samePKBucket returns whether the primary key buckets are the same, nil means not bucketed.
+func samePKBucket(a, b *datapb.PKBucket) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.GetIndex() == b.GetIndex() && a.GetNum() == b.GetNum()
+}
+
+// sameTimeBucket returns whether the time buckets are the same, nil means not bucketed.
+func sameTimeBucket(a, b *datapb.TimeBucket) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.GetFieldID() == b.GetFieldID() && a.GetStart() == b.GetStart() && a.GetEnd() == b.GetEnd()
+}
+
 func isGrowing(segment *SegmentInfo) bool {
 	return segment.GetState() == commonpb.SegmentState_Growing
 }
@@ -382,7 +427,7 @@ func (s *SegmentManager) genExpireTs(ctx context.Context, isImported bool) (Time
 }
 
 func (s *SegmentManager) openNewSegment(ctx context.Context, collectionID UniqueID, partitionID UniqueID,
-	channelName string, segmentState commonpb.SegmentState, level datapb.SegmentLevel,
+	channelName string, timeBucket *datapb.TimeBucket, pkBucket *datapb.PKBucket, segmentState commonpb.SegmentState, level datapb.SegmentLevel,
 ) (*SegmentInfo, error) {
 	log := log.Ctx(ctx)
 	ctx, sp := otel.Tracer(typeutil.DataCoordRole).Start(ctx, "open-Segment")
@@ -408,6 +453,8 @@ func (s *SegmentManager) openNewSegment(ctx context.Context, collectionID Unique
 		MaxRowNum:      int64(maxNumOfRows),
 		Level:          level,
 		LastExpireTime: 0,
+		TimeBucket:     timeBucket,
+		PkBucket:       pkBucket,
 	}
 	if segmentState == commonpb.SegmentState_Importing {
 		segmentInfo.IsImporting = true
@@ -483,7 +530,7 @@ func (s *SegmentManager) FlushImportSegments(ctx context.Context, collectionID U
 	// 'Sealed' because all data has been imported, and there is no data
 	// in the datanode flowgraph that needs to be synced.
 	for _, id := range candidates {
-		if err := s.meta.SetState(id, commonpb.SegmentState_Flushed); err != nil {
+		if err := s.meta.SetState(id, commonpb.SegmentState_Flushed, "import completed"); err != nil {
 			return err
 		}
 	}
@@ -520,7 +567,7 @@ func (s *SegmentManager) SealAllSegments(ctx context.Context, collectionID Uniqu
 		if info.State != commonpb.SegmentState_Growing {
 			continue
 		}
-		if err := s.meta.SetState(id, commonpb.SegmentState_Sealed); err != nil {
+		if err := s.meta.SetState(id, commonpb.SegmentState_Sealed, "sealed by flush"); err != nil {
 			return nil, err
 		}
 		ret = append(ret, id)
@@ -589,14 +636,14 @@ func (s *SegmentManager) cleanupSealedSegment(ts Timestamp, channel string) {
 
 		if isEmptySealedSegment(segment, ts) {
 			log.Info("remove empty sealed segment", zap.Int64("collection", segment.CollectionID), zap.Int64("segment", id))
-			s.meta.SetState(id, commonpb.SegmentState_Dropped)
+			s.meta.SetState(id, commonpb.SegmentState_Dropped, "empty sealed segment removed")
 			continue
 		}
 
 		// clean up importing segment since the task failed.
 		if segment.GetState() == commonpb.SegmentState_Importing && segment.GetLastExpireTime() < ts {
 			log.Info("cleanup staled importing segment", zap.Int64("collection", segment.CollectionID), zap.Int64("segment", id))
-			s.meta.SetState(id, commonpb.SegmentState_Dropped)
+			s.meta.SetState(id, commonpb.SegmentState_Dropped, "stale importing segment cleaned up")
 			continue
 		}
 
@@ -624,7 +671,7 @@ func (s *SegmentManager) tryToSealSegment(ts Timestamp, channel string) error {
 		// change shouldSeal to segment seal policy logic
 		for _, policy := range s.segmentSealPolicies {
 			if policy(info, ts) {
-				if err := s.meta.SetState(id, commonpb.SegmentState_Sealed); err != nil {
+				if err := s.meta.SetState(id, commonpb.SegmentState_Sealed, "sealed by segment seal policy"); err != nil {
 					return err
 				}
 				break
@@ -638,7 +685,7 @@ func (s *SegmentManager) tryToSealSegment(ts Timestamp, channel string) error {
 				if info.State != commonpb.SegmentState_Growing {
 					continue
 				}
-				if err := s.meta.SetState(info.GetID(), commonpb.SegmentState_Sealed); err != nil {
+				if err := s.meta.SetState(info.GetID(), commonpb.SegmentState_Sealed, "sealed by channel seal policy"); err != nil {
 					return err
 				}
 			}
@@ -121,6 +121,40 @@ func TestAllocSegment(t *testing.T) {
 		assert.NotEqualValues(t, 0, allocations[0].ExpireTime)
 	})
 
+	t.Run("time bucket allocation", func(t *testing.T) {
+		bucket1 := &datapb.TimeBucket{FieldID: 100, Start: 0, End: 3600000}
+		bucket2 := &datapb.TimeBucket{FieldID: 100, Start: 3600000, End: 7200000}
+		allocations1, err := segmentManager.AllocSegment(ctx, collID, 100, "c1", 100, WithTimeBucket(bucket1))
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, len(allocations1))
+		allocations2, err := segmentManager.AllocSegment(ctx, collID, 100, "c1", 100, WithTimeBucket(bucket2))
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, len(allocations2))
+		assert.NotEqual(t, allocations1[0].SegmentID, allocations2[0].SegmentID)
+
+		allocations, err := segmentManager.AllocSegment(ctx, collID, 100, "c1", 100, WithTimeBucket(&datapb.TimeBucket{FieldID: 100, Start: 0, End: 3600000}))
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, len(allocations))
+		assert.Equal(t, allocations1[0].SegmentID, allocations[0].SegmentID)
+		assert.EqualValues(t, 3600000, meta.GetSegment(allocations[0].SegmentID).GetTimeBucket().GetEnd())
+	})
+
+	t.Run("pk bucket allocation", func(t *testing.T) {
+		allocations1, err := segmentManager.AllocSegment(ctx, collID, 100, "c1", 100, WithPKBucket(&datapb.PKBucket{Index: 0, Num: 2}))
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, len(allocations1))
+		allocations2, err := segmentManager.AllocSegment(ctx, collID, 100, "c1", 100, WithPKBucket(&datapb.PKBucket{Index: 1, Num: 2}))
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, len(allocations2))
+		assert.NotEqual(t, allocations1[0].SegmentID, allocations2[0].SegmentID)
+
+		allocations, err := segmentManager.AllocSegment(ctx, collID, 100, "c1", 100, WithPKBucket(&datapb.PKBucket{Index: 1, Num: 2}))
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, len(allocations))
+		assert.Equal(t, allocations2[0].SegmentID, allocations[0].SegmentID)
+		assert.EqualValues(t, 1, meta.GetSegment(allocations[0].SegmentID).GetPkBucket().GetIndex())
+	})
+
 	t.Run("allocation fails 1", func(t *testing.T) {
 		failsAllocator := &FailsAllocator{
 			allocTsSucceed: true,
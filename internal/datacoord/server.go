@@ -38,6 +38,7 @@ import (
 	datanodeclient "github.com/milvus-io/milvus/internal/distributed/datanode/client"
 	indexnodeclient "github.com/milvus-io/milvus/internal/distributed/indexnode/client"
 	rootcoordclient "github.com/milvus-io/milvus/internal/distributed/rootcoord/client"
+	"github.com/milvus-io/milvus/internal/http/standby"
 	"github.com/milvus-io/milvus/internal/kv"
 	etcdkv "github.com/milvus-io/milvus/internal/kv/etcd"
 	"github.com/milvus-io/milvus/internal/kv/tikv"
@@ -76,6 +77,8 @@ var (
 	ttMaxInterval             = 2 * time.Minute
 	ttCheckerWarnMsg          = fmt.Sprintf("Datacoord haven't received tt for %f minutes", ttMaxInterval.Minutes())
 	segmentTimedFlushDuration = 10.0
+	// featureGateInterval is the interval to refresh the features supported by the whole cluster
+	featureGateInterval = time.Minute
 )
 
 type (
@@ -118,9 +121,11 @@ type Server struct {
 	cluster          Cluster
 	sessionManager   SessionManager
 	channelManager   ChannelManager
+	placement        *placementPolicy
 	rootCoordClient  types.RootCoordClient
 	garbageCollector *garbageCollector
 	gcOpt            GcOption
+	exportManager    *exportManager
 	handler          Handler
 
 	compactionTrigger     trigger
@@ -128,6 +133,11 @@ type Server struct {
 	compactionViewManager *CompactionViewManager
 
 	metricsCacheManager *metricsinfo.MetricsCacheManager
+	decommissionManager *decommissionManager
+	segmentExpirer      *segmentExpirer
+	reencodeManager     *reencodeManager
+
+	majorCompactionManager *majorCompactionManager
 
 	flushCh         chan UniqueID
 	buildIndexCh    chan UniqueID
@@ -140,9 +150,13 @@ type Server struct {
 	inEventCh <-chan *sessionutil.SessionEvent
 	// qcEventCh <-chan *sessionutil.SessionEvent
 	qnEventCh <-chan *sessionutil.SessionEvent
+	// featureGate holds back the binlog encodings until all the readers support them
+	featureGate *sessionutil.FeatureGate
 
 	enableActiveStandBy bool
 	activateFunc        func() error
+	// warmStandby keeps the meta up to date in standby mode, nil if not enabled
+	warmStandby *warmStandby
 
 	dataNodeCreator        dataNodeCreatorFunc
 	indexNodeCreator       indexNodeCreatorFunc
@@ -299,6 +313,8 @@ func (s *Server) initSession() error {
 	}
 	s.session.Init(typeutil.DataCoordRole, s.address, true, true)
 	s.session.SetEnableActiveStandBy(s.enableActiveStandBy)
+	s.featureGate = sessionutil.NewFeatureGate(s.session, featureGateInterval,
+		typeutil.QueryNodeRole, typeutil.DataNodeRole, typeutil.IndexNodeRole)
 	return nil
 }
 
@@ -312,6 +328,7 @@ func (s *Server) Init() error {
 	if s.enableActiveStandBy {
 		s.activateFunc = func() error {
 			log.Info("DataCoord switch from standby to active, activating")
+			s.activateWarmStandby()
 			if err := s.initDataCoord(); err != nil {
 				log.Error("DataCoord init failed", zap.Error(err))
 				return err
@@ -321,6 +338,12 @@ func (s *Server) Init() error {
 			return nil
 		}
 		s.stateCode.Store(commonpb.StateCode_StandBy)
+		if Params.DataCoordCfg.WarmStandbyEnabled.GetAsBool() {
+			if err := s.initWarmStandby(); err != nil {
+				log.Warn("DataCoord failed to warm up standby, the meta will be fully reloaded on activation", zap.Error(err))
+			}
+		}
+		standby.Register(s)
 		log.Info("DataCoord enter standby mode successfully")
 		return nil
 	}
@@ -328,6 +351,60 @@ func (s *Server) Init() error {
 	return s.initDataCoord()
 }
 
+// initWarmStandby loads the meta in standby mode, and keeps it up to date by watching the meta store.
+func (s *Server) initWarmStandby() error {
+	if Params.MetaStoreCfg.MetaStoreType.GetValue() != util.MetaStoreTypeEtcd {
+		return merr.WrapErrParameterInvalidMsg("warm standby only supports etcd meta store")
+	}
+	storageCli, err := s.newChunkManagerFactory()
+	if err != nil {
+		return err
+	}
+	// the revision is got before loading, the changes during loading are watched and reloaded again
+	metaRootPath := Params.EtcdCfg.MetaRootPath.GetValue()
+	revision, err := getMetaRevision(s.ctx, s.etcdCli, metaRootPath)
+	if err != nil {
+		return err
+	}
+	if err := s.initMeta(storageCli); err != nil {
+		s.meta = nil
+		return err
+	}
+	s.warmStandby = newWarmStandby(s.meta, s.etcdCli, metaRootPath)
+	s.warmStandby.start(s.ctx, revision)
+	return nil
+}
+
+// activateWarmStandby catches up the meta loaded in standby mode,
+// or drops it to be fully reloaded if failed.
+func (s *Server) activateWarmStandby() {
+	if s.warmStandby == nil {
+		return
+	}
+	if err := s.warmStandby.activate(s.ctx); err != nil {
+		log.Warn("DataCoord failed to catch up the warm standby meta, fallback to full reload", zap.Error(err))
+		s.meta = nil
+		return
+	}
+	log.Info("DataCoord activated with the warm standby meta", zap.Int64("revision", s.warmStandby.revision()))
+}
+
+// StandbyStatus returns the active/standby status of DataCoord.
+func (s *Server) StandbyStatus() *standby.Status {
+	status := standby.NewStatus(typeutil.DataCoordRole, s.session.GetServerID(), s.stateCode.Load().(commonpb.StateCode))
+	if s.warmStandby != nil {
+		status.Warm = status.State == standby.StateStandby && s.warmStandby.getErr() == nil
+		status.Revision = s.warmStandby.revision()
+		status.PendingChanges = s.warmStandby.pendingChanges()
+		lastSync := s.warmStandby.getLastSync()
+		status.LastSyncTime = &lastSync
+		if err := s.warmStandby.getErr(); err != nil {
+			status.Error = err.Error()
+		}
+	}
+	return status
+}
+
 func (s *Server) initDataCoord() error {
 	s.stateCode.Store(commonpb.StateCode_Initializing)
 	var err error
@@ -380,6 +457,13 @@ func (s *Server) initDataCoord() error {
 
 	s.initGarbageCollection(storageCli)
 	s.initIndexBuilder(storageCli)
+	s.initExportManager(storageCli)
+	s.initDecommissionManager()
+	s.initSegmentExpirer()
+	if Params.DataCoordCfg.EnableCompaction.GetAsBool() {
+		s.initReencodeManager(storageCli)
+		s.initMajorCompactionManager()
+	}
 
 	s.serverLoopCtx, s.serverLoopCancel = context.WithCancel(s.ctx)
 
@@ -408,8 +492,12 @@ func (s *Server) startDataCoord() {
 		s.compactionHandler.start()
 		s.compactionTrigger.start()
 		s.compactionViewManager.Start()
+		s.reencodeManager.start()
+		s.majorCompactionManager.start()
 	}
 	s.startServerLoop()
+	s.decommissionManager.start()
+	s.segmentExpirer.start()
 
 	// http.Register(&http.Handler{
 	// 	Path: "/datacoord/garbage_collection/pause",
@@ -460,13 +548,20 @@ func (s *Server) initCluster() error {
 		return nil
 	}
 
-	var err error
-	s.channelManager, err = NewChannelManager(s.watchClient, s.handler, withMsgstreamFactory(s.factory),
-		withStateChecker(), withBgChecker())
+	s.sessionManager = NewSessionManagerImpl(withSessionCreator(s.dataNodeCreator))
+	opts := []ChannelManagerOpt{withMsgstreamFactory(s.factory), withStateChecker(), withBgChecker(), withFeatureGate(s.featureGate)}
+	placement, err := loadPlacementPolicy(s.sessionManager)
+	if err != nil {
+		log.Warn("fail to load the placement plugin, use the default placement", zap.Error(err))
+	} else if placement != nil {
+		opts = append(opts, withFactory(newPlacementPolicyFactory(NewChannelPolicyFactoryV1(s.watchClient), placement)))
+		s.placement = placement
+	}
+
+	s.channelManager, err = NewChannelManager(s.watchClient, s.handler, opts...)
 	if err != nil {
 		return err
 	}
-	s.sessionManager = NewSessionManagerImpl(withSessionCreator(s.dataNodeCreator))
 	s.cluster = NewClusterImpl(s.sessionManager, s.channelManager)
 	return nil
 }
@@ -497,7 +592,12 @@ func (s *Server) SetIndexNodeCreator(f func(context.Context, string, int64) (typ
 }
 
 func (s *Server) createCompactionHandler() {
-	s.compactionHandler = newCompactionPlanHandler(s.sessionManager, s.channelManager, s.meta, s.allocator)
+	handler := newCompactionPlanHandler(s.sessionManager, s.channelManager, s.meta, s.allocator)
+	handler.featureGate = s.featureGate
+	if s.placement != nil {
+		handler.scheduler = NewCompactionScheduler(withSegmentPlacement(s.placement.segmentPlacement(s.channelManager)))
+	}
+	s.compactionHandler = handler
 	triggerv2 := NewCompactionTriggerManager(s.meta, s.allocator, s.compactionHandler)
 	s.compactionViewManager = NewCompactionViewManager(s.meta, triggerv2, s.allocator)
 }
@@ -535,6 +635,27 @@ func (s *Server) initGarbageCollection(cli storage.ChunkManager) {
 	})
 }
 
+func (s *Server) initExportManager(cli storage.ChunkManager) {
+	s.exportManager = newExportManager(s.ctx, s.meta, s.handler, cli, s.garbageCollector,
+		Params.DataCoordCfg.ExportConcurrency.GetAsInt())
+}
+
+func (s *Server) initDecommissionManager() {
+	s.decommissionManager = newDecommissionManager(s.ctx, s.meta, s.channelManager, s.compactionHandler, s.indexNodeManager)
+}
+
+func (s *Server) initReencodeManager(cli storage.ChunkManager) {
+	s.reencodeManager = newReencodeManager(s.ctx, s.meta, s.handler, s.allocator, s.compactionHandler, cli)
+}
+
+func (s *Server) initSegmentExpirer() {
+	s.segmentExpirer = newSegmentExpirer(s.ctx, s.meta, s.handler, s.allocator, s.garbageCollector)
+}
+
+func (s *Server) initMajorCompactionManager() {
+	s.majorCompactionManager = newMajorCompactionManager(s.ctx, s.meta, s.handler, s.allocator, s.compactionHandler)
+}
+
 func (s *Server) initServiceDiscovery() error {
 	r := semver.MustParseRange(">=2.2.3")
 	sessions, rev, err := s.session.GetSessionsWithVersionRange(typeutil.DataNodeRole, r)
@@ -547,8 +668,10 @@ func (s *Server) initServiceDiscovery() error {
 	datanodes := make([]*NodeInfo, 0, len(sessions))
 	for _, session := range sessions {
 		info := &NodeInfo{
-			NodeID:  session.ServerID,
-			Address: session.Address,
+			NodeID:          session.ServerID,
+			Address:         session.Address,
+			ProtocolVersion: session.ProtocolVersion,
+			Labels:          session.ServerLabels,
 		}
 		datanodes = append(datanodes, info)
 	}
@@ -637,6 +760,7 @@ func (s *Server) initMeta(chunkManager storage.ChunkManager) error {
 		if err != nil {
 			return err
 		}
+		s.meta.eventLog = newSegmentEventLog(catalog)
 		return nil
 	}
 	return retry.Do(s.ctx, reloadEtcdFn, retry.Attempts(connMetaMaxRetryTime))
@@ -664,6 +788,7 @@ func (s *Server) startServerLoop() {
 	s.startFlushLoop(s.serverLoopCtx)
 	s.startIndexService(s.serverLoopCtx)
 	s.garbageCollector.start()
+	s.meta.eventLog.start()
 }
 
 // startDataNodeTtLoop start a goroutine to recv data node tt msg from msgstream
@@ -925,8 +1050,10 @@ func (s *Server) handleSessionEvent(ctx context.Context, role string, event *ses
 			Channels: []*datapb.ChannelStatus{},
 		}
 		node := &NodeInfo{
-			NodeID:  event.Session.ServerID,
-			Address: event.Session.Address,
+			NodeID:          event.Session.ServerID,
+			Address:         event.Session.Address,
+			ProtocolVersion: event.Session.ProtocolVersion,
+			Labels:          event.Session.ServerLabels,
 		}
 		switch event.EventType {
 		case sessionutil.SessionAddEvent:
@@ -1040,7 +1167,7 @@ func (s *Server) postFlush(ctx context.Context, segmentID UniqueID) error {
 		return merr.WrapErrSegmentNotFound(segmentID, "segment not found, might be a faked segment, ignore post flush")
 	}
 	// set segment to SegmentState_Flushed
-	if err := s.meta.SetState(segmentID, commonpb.SegmentState_Flushed); err != nil {
+	if err := s.meta.SetState(segmentID, commonpb.SegmentState_Flushed, "flush completed"); err != nil {
 		log.Error("flush segment complete failed", zap.Error(err))
 		return err
 	}
@@ -1096,15 +1223,24 @@ func (s *Server) initRootCoordClient() error {
 //
 //	stop message stream client and stop server loops
 func (s *Server) Stop() error {
+	if s.warmStandby != nil {
+		s.warmStandby.close()
+	}
 	if !s.stateCode.CompareAndSwap(commonpb.StateCode_Healthy, commonpb.StateCode_Abnormal) {
 		return nil
 	}
 	logutil.Logger(s.ctx).Info("server shutdown")
 	s.cluster.Close()
+	s.exportManager.close()
+	s.decommissionManager.close()
+	s.segmentExpirer.close()
 	s.garbageCollector.close()
+	s.meta.eventLog.close()
 	s.stopServerLoop()
 
 	if Params.DataCoordCfg.EnableCompaction.GetAsBool() {
+		s.reencodeManager.close()
+		s.majorCompactionManager.close()
 		s.stopCompactionTrigger()
 		s.stopCompactionHandler()
 	}
@@ -42,6 +42,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/datacoord/broker"
+	"github.com/milvus-io/milvus/internal/http/standby"
 	"github.com/milvus-io/milvus/internal/metastore/model"
 	"github.com/milvus-io/milvus/internal/mocks"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
@@ -387,6 +388,55 @@ func TestGetSegmentInfo(t *testing.T) {
 		assert.NoError(t, err)
 		assert.EqualValues(t, commonpb.ErrorCode_Success, resp.GetStatus().GetErrorCode())
 	})
+	t.Run("with delete stats", func(t *testing.T) {
+		svr := newTestServer(t, nil)
+		defer closeTestServer(t, svr)
+
+		segInfo := &datapb.SegmentInfo{
+			ID:        0,
+			State:     commonpb.SegmentState_Flushed,
+			NumOfRows: 100,
+			Binlogs: []*datapb.FieldBinlog{
+				{
+					FieldID: 1,
+					Binlogs: []*datapb.Binlog{
+						{EntriesNum: 50, LogSize: 1000},
+						{EntriesNum: 50, LogSize: 1000},
+					},
+				},
+			},
+			Deltalogs: []*datapb.FieldBinlog{
+				{
+					Binlogs: []*datapb.Binlog{
+						{EntriesNum: 10, LogSize: 200},
+						{EntriesNum: 15, LogSize: 300},
+					},
+				},
+			},
+		}
+		err := svr.meta.AddSegment(context.TODO(), NewSegmentInfo(segInfo))
+		assert.NoError(t, err)
+		err = svr.meta.AddSegment(context.TODO(), NewSegmentInfo(&datapb.SegmentInfo{ID: 1, State: commonpb.SegmentState_Flushed}))
+		assert.NoError(t, err)
+
+		resp, err := svr.GetSegmentInfo(svr.ctx, &datapb.GetSegmentInfoRequest{SegmentIDs: []int64{0, 1}})
+		assert.NoError(t, merr.CheckRPCCall(resp, err))
+		assert.Len(t, resp.GetDeleteStats(), 2)
+		stats := resp.GetDeleteStats()[0]
+		assert.EqualValues(t, 0, stats.GetSegmentID())
+		assert.EqualValues(t, 100, stats.GetNumRows())
+		assert.EqualValues(t, 25, stats.GetDeletedRows())
+		assert.EqualValues(t, 2000, stats.GetBinlogSize())
+		assert.EqualValues(t, 500, stats.GetDeltalogSize())
+		assert.Equal(t, 0.25, stats.GetDeletedRowRatio())
+		assert.Equal(t, 0.25, stats.GetDeltalogSizeRatio())
+
+		// empty segment has no delete amplification
+		stats = resp.GetDeleteStats()[1]
+		assert.EqualValues(t, 1, stats.GetSegmentID())
+		assert.Zero(t, stats.GetDeletedRowRatio())
+		assert.Zero(t, stats.GetDeltalogSizeRatio())
+	})
 	t.Run("with wrong segmentID", func(t *testing.T) {
 		svr := newTestServer(t, nil)
 		defer closeTestServer(t, svr)
@@ -996,7 +1046,7 @@ type spySegmentManager struct {
 }
 
 // AllocSegment allocates rows and record the allocation.
-func (s *spySegmentManager) AllocSegment(ctx context.Context, collectionID UniqueID, partitionID UniqueID, channelName string, requestRows int64) ([]*Allocation, error) {
+func (s *spySegmentManager) AllocSegment(ctx context.Context, collectionID UniqueID, partitionID UniqueID, channelName string, requestRows int64, opts ...AllocOption) ([]*Allocation, error) {
 	panic("not implemented") // TODO: Implement
 }
 
@@ -2572,6 +2622,73 @@ func TestGetCompactionStateWithPlans(t *testing.T) {
 	})
 }
 
+func TestListCompactionTasks(t *testing.T) {
+	t.Run("test list compaction tasks successfully", func(t *testing.T) {
+		svr := &Server{}
+		svr.stateCode.Store(commonpb.StateCode_Healthy)
+
+		mockHandler := NewMockCompactionPlanContext(t)
+		mockHandler.EXPECT().listTasks().Return(
+			[]*compactionTask{
+				{
+					triggerInfo:    &compactionSignal{id: 1, collectionID: 100},
+					plan:           &datapb.CompactionPlan{PlanID: 1, Channel: "ch-1", Type: datapb.CompactionType_MixCompaction},
+					state:          pipelining,
+					dataNodeID:     1,
+					priority:       mergePriority,
+					preemptedTimes: 1,
+				},
+				{
+					triggerInfo: &compactionSignal{id: 2, collectionID: 101},
+					plan:        &datapb.CompactionPlan{PlanID: 2, Channel: "ch-2", Type: datapb.CompactionType_MixCompaction},
+					state:       pipelining,
+					priority:    manualPriority,
+				},
+			},
+			[]*compactionTask{
+				{
+					triggerInfo: &compactionSignal{id: 3, collectionID: 100},
+					plan:        &datapb.CompactionPlan{PlanID: 3, Channel: "ch-1", Type: datapb.CompactionType_Level0DeleteCompaction},
+					state:       executing,
+					dataNodeID:  1,
+					priority:    deltaPriority,
+				},
+			},
+			[]int64{1})
+		svr.compactionHandler = mockHandler
+
+		resp, err := svr.ListCompactionTasks(context.TODO(), &datapb.ListCompactionTasksRequest{})
+		assert.NoError(t, err)
+		assert.NoError(t, merr.Error(resp.GetStatus()))
+		assert.Len(t, resp.GetQueuing(), 2)
+		assert.Len(t, resp.GetExecuting(), 1)
+		assert.Equal(t, []int64{1}, resp.GetFlushPressuredNodes())
+
+		resp, err = svr.ListCompactionTasks(context.TODO(), &datapb.ListCompactionTasksRequest{CollectionID: 100})
+		assert.NoError(t, err)
+		assert.NoError(t, merr.Error(resp.GetStatus()))
+		assert.Len(t, resp.GetQueuing(), 1)
+		view := resp.GetQueuing()[0]
+		assert.EqualValues(t, 1, view.GetPlanID())
+		assert.EqualValues(t, 1, view.GetTriggerID())
+		assert.Equal(t, "ch-1", view.GetChannel())
+		assert.EqualValues(t, 1, view.GetNodeID())
+		assert.Equal(t, "merge", view.GetPriority())
+		assert.Equal(t, "pipelining", view.GetState())
+		assert.EqualValues(t, 1, view.GetPreemptedTimes())
+		assert.Len(t, resp.GetExecuting(), 1)
+		assert.Equal(t, "delta", resp.GetExecuting()[0].GetPriority())
+	})
+
+	t.Run("test list compaction tasks with closed server", func(t *testing.T) {
+		svr := &Server{}
+		svr.stateCode.Store(commonpb.StateCode_Abnormal)
+		resp, err := svr.ListCompactionTasks(context.TODO(), &datapb.ListCompactionTasksRequest{})
+		assert.NoError(t, err)
+		assert.ErrorIs(t, merr.Error(resp.GetStatus()), merr.ErrServiceNotReady)
+	})
+}
+
 func TestOptions(t *testing.T) {
 	kv := getWatchKV(t)
 	defer func() {
@@ -3718,6 +3835,21 @@ func TestDataCoord_EnableActiveStandby(t *testing.T) {
 	defer closeTestServer(t, svr)
 }
 
+func TestDataCoord_EnableWarmStandby(t *testing.T) {
+	paramtable.Get().Save(Params.DataCoordCfg.EnableActiveStandby.Key, "true")
+	defer paramtable.Get().Reset(Params.DataCoordCfg.EnableActiveStandby.Key)
+	paramtable.Get().Save(Params.DataCoordCfg.WarmStandbyEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.DataCoordCfg.WarmStandbyEnabled.Key)
+	svr := testDataCoordBase(t)
+	defer closeTestServer(t, svr)
+
+	assert.NotNil(t, svr.warmStandby)
+	status := svr.StandbyStatus()
+	assert.Equal(t, standby.StateActive, status.State)
+	assert.False(t, status.Warm)
+	assert.Empty(t, status.Error)
+}
+
 func TestDataNodeTtChannel(t *testing.T) {
 	paramtable.Get().Save(Params.DataNodeCfg.DataNodeTimeTickByRPC.Key, "false")
 	defer paramtable.Get().Reset(Params.DataNodeCfg.DataNodeTimeTickByRPC.Key)
@@ -19,11 +19,13 @@ package datacoord
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/rand"
 	"strconv"
 	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
 	"github.com/samber/lo"
 	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
@@ -32,6 +34,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
 	"github.com/milvus-io/milvus/internal/metastore/kv/binlog"
+	"github.com/milvus-io/milvus/internal/metastore/model"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/storage"
@@ -232,7 +235,8 @@ func (s *Server) AssignSegmentID(ctx context.Context, req *datapb.AssignSegmentI
 		} else {
 			// Have segment manager allocate and return the segment allocation info.
 			segAlloc, err := s.segmentManager.AllocSegment(ctx,
-				r.CollectionID, r.PartitionID, r.ChannelName, int64(r.Count))
+				r.CollectionID, r.PartitionID, r.ChannelName, int64(r.Count),
+				WithTimeBucket(r.GetTimeBucket()), WithPKBucket(r.GetPkBucket()))
 			if err != nil {
 				log.Warn("failed to alloc segment", zap.Any("request", r), zap.Error(err))
 				continue
@@ -251,6 +255,8 @@ func (s *Server) AssignSegmentID(ctx context.Context, req *datapb.AssignSegmentI
 				PartitionID:  r.PartitionID,
 				ExpireTime:   allocation.ExpireTime,
 				Status:       merr.Success(),
+				TimeBucket:   r.GetTimeBucket(),
+				PkBucket:     r.GetPkBucket(),
 			}
 			assigns = append(assigns, result)
 		}
@@ -402,6 +408,7 @@ func (s *Server) GetSegmentInfo(ctx context.Context, req *datapb.GetSegmentInfoR
 		}, nil
 	}
 	infos := make([]*datapb.SegmentInfo, 0, len(req.GetSegmentIDs()))
+	deleteStats := make([]*datapb.SegmentDeleteStats, 0, len(req.GetSegmentIDs()))
 	channelCPs := make(map[string]*msgpb.MsgPosition)
 	for _, id := range req.SegmentIDs {
 		var info *SegmentInfo
@@ -423,6 +430,7 @@ func (s *Server) GetSegmentInfo(ctx context.Context, req *datapb.GetSegmentInfoR
 			}
 			segmentutil.ReCalcRowCount(info.SegmentInfo, clonedInfo.SegmentInfo)
 			infos = append(infos, clonedInfo.SegmentInfo)
+			deleteStats = append(deleteStats, clonedInfo.getDeleteStats())
 		} else {
 			info = s.meta.GetHealthySegment(id)
 			if info == nil {
@@ -433,6 +441,7 @@ func (s *Server) GetSegmentInfo(ctx context.Context, req *datapb.GetSegmentInfoR
 			clonedInfo := info.Clone()
 			segmentutil.ReCalcRowCount(info.SegmentInfo, clonedInfo.SegmentInfo)
 			infos = append(infos, clonedInfo.SegmentInfo)
+			deleteStats = append(deleteStats, clonedInfo.getDeleteStats())
 		}
 		vchannel := info.InsertChannel
 		if _, ok := channelCPs[vchannel]; vchannel != "" && !ok {
@@ -440,6 +449,7 @@ func (s *Server) GetSegmentInfo(ctx context.Context, req *datapb.GetSegmentInfoR
 		}
 	}
 	resp.Infos = infos
+	resp.DeleteStats = deleteStats
 	resp.ChannelCheckpoint = channelCPs
 	return resp, nil
 }
@@ -527,6 +537,7 @@ func (s *Server) SaveBinlogPaths(ctx context.Context, req *datapb.SaveBinlogPath
 
 	// save binlogs, start positions and checkpoints
 	operators = append(operators,
+		SegmentEventOperator(dataNodeActor(nodeID), saveBinlogPathsReason(req)),
 		UpdateBinlogsOperator(req.GetSegmentID(), req.GetField2BinlogPaths(), req.GetField2StatslogPaths(), req.GetDeltalogs()),
 		UpdateStartPosition(req.GetStartPositions()),
 		UpdateCheckPointOperator(req.GetSegmentID(), req.GetImporting(), req.GetCheckPoints()),
@@ -628,6 +639,7 @@ func (s *Server) DropVirtualChannel(ctx context.Context, req *datapb.DropVirtual
 	s.compactionHandler.removeTasksByChannel(channel)
 
 	metrics.CleanupDataCoordNumStoredRows(collectionID)
+	metrics.CleanupDataCoordCollectionDeleteRatio(collectionID)
 	metrics.DataCoordCheckpointUnixSeconds.DeleteLabelValues(fmt.Sprint(paramtable.GetNodeID()), channel)
 
 	// no compaction triggered in Drop procedure
@@ -642,7 +654,7 @@ func (s *Server) SetSegmentState(ctx context.Context, req *datapb.SetSegmentStat
 			Status: merr.Status(err),
 		}, nil
 	}
-	err := s.meta.SetState(req.GetSegmentId(), req.GetNewState())
+	err := s.meta.SetState(req.GetSegmentId(), req.GetNewState(), "set by SetSegmentState request")
 	if err != nil {
 		log.Error("failed to updated segment state in dataCoord meta",
 			zap.Int64("segmentID", req.SegmentId),
@@ -1256,11 +1268,14 @@ func (s *Server) WatchChannels(ctx context.Context, req *datapb.WatchChannelsReq
 	}
 	for _, channelName := range req.GetChannelNames() {
 		ch := &channelMeta{
-			Name:            channelName,
-			CollectionID:    req.GetCollectionID(),
-			StartPositions:  req.GetStartPositions(),
-			Schema:          req.GetSchema(),
-			CreateTimestamp: req.GetCreateTimestamp(),
+			Name:              channelName,
+			CollectionID:      req.GetCollectionID(),
+			StartPositions:    req.GetStartPositions(),
+			Schema:            req.GetSchema(),
+			CreateTimestamp:   req.GetCreateTimestamp(),
+			BinlogFormat:      req.GetBinlogFormat(),
+			StorageTenant:     req.GetStorageTenant(),
+			BinlogCompression: req.GetBinlogCompression(),
 		}
 		err := s.channelManager.Watch(ctx, ch)
 		if err != nil {
@@ -1624,7 +1639,7 @@ func (s *Server) MarkSegmentsDropped(ctx context.Context, req *datapb.MarkSegmen
 	log.Info("marking segments dropped", zap.Int64s("segments", req.GetSegmentIds()))
 	var err error
 	for _, segID := range req.GetSegmentIds() {
-		if err = s.meta.SetState(segID, commonpb.SegmentState_Dropped); err != nil {
+		if err = s.meta.SetState(segID, commonpb.SegmentState_Dropped, "marked dropped by request"); err != nil {
 			// Fail-open.
 			log.Error("failed to set segment state as dropped", zap.Int64("segmentID", segID))
 			break
@@ -1735,3 +1750,1058 @@ func (s *Server) GcControl(ctx context.Context, request *datapb.GcControlRequest
 
 	return status, nil
 }
+
+// InspectMeta returns the decoded meta selected by the request target, so that operators
+// could debug datacoord without accessing the meta store directly. The meta is never modified.
+func (s *Server) InspectMeta(ctx context.Context, req *datapb.InspectMetaRequest) (*datapb.InspectMetaResponse, error) {
+	log := log.Ctx(ctx).With(
+		zap.String("target", req.GetTarget().String()),
+		zap.Int64("collectionID", req.GetCollectionID()),
+		zap.String("channel", req.GetChannel()),
+	)
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.InspectMetaResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	matchCollection := func(collectionID int64) bool {
+		return req.GetCollectionID() == 0 || req.GetCollectionID() == collectionID
+	}
+	matchChannel := func(channel string) bool {
+		return req.GetChannel() == "" || req.GetChannel() == channel
+	}
+
+	resp := &datapb.InspectMetaResponse{
+		Status: merr.Success(),
+	}
+	switch req.GetTarget() {
+	case datapb.MetaInspectTarget_InspectSegments:
+		states := typeutil.NewSet(req.GetStates()...)
+		segments := s.meta.SelectSegments(func(segment *SegmentInfo) bool {
+			return matchCollection(segment.GetCollectionID()) && matchChannel(segment.GetInsertChannel()) &&
+				(states.Len() == 0 || states.Contain(segment.GetState()))
+		})
+		resp.Segments = make([]*datapb.SegmentInfo, 0, len(segments))
+		for _, segment := range segments {
+			cloned := segment.Clone()
+			if err := binlog.DecompressBinLogs(cloned.SegmentInfo); err != nil {
+				log.Warn("failed to decompress segment binlogs", zap.Int64("segmentID", segment.GetID()), zap.Error(err))
+				return &datapb.InspectMetaResponse{
+					Status: merr.Status(err),
+				}, nil
+			}
+			resp.Segments = append(resp.Segments, cloned.SegmentInfo)
+		}
+	case datapb.MetaInspectTarget_InspectChannelCheckpoints:
+		// collectionID is not applied since checkpoints are keyed by channel only
+		resp.ChannelCheckpoints = lo.PickBy(s.meta.GetChannelCheckpoints(), func(channel string, _ *msgpb.MsgPosition) bool {
+			return matchChannel(channel)
+		})
+	case datapb.MetaInspectTarget_InspectIndexTasks:
+		for _, segIndex := range s.meta.GetAllSegIndexes() {
+			if !matchCollection(segIndex.CollectionID) {
+				continue
+			}
+			resp.IndexTasks = append(resp.IndexTasks, model.MarshalSegmentIndexModel(segIndex))
+		}
+	case datapb.MetaInspectTarget_InspectCompactionPlans:
+		for _, task := range s.compactionHandler.getCompactionTasksBySignalID(0) {
+			if task.plan == nil || !matchCollection(task.triggerInfo.collectionID) || !matchChannel(task.plan.GetChannel()) {
+				continue
+			}
+			resp.CompactionPlans = append(resp.CompactionPlans, &datapb.CompactionPlanView{
+				Plan:      task.plan,
+				State:     task.state.String(),
+				NodeID:    task.dataNodeID,
+				TriggerID: task.triggerInfo.id,
+			})
+		}
+	default:
+		err := merr.WrapErrParameterInvalidMsg("unknown inspect target %d", req.GetTarget())
+		return &datapb.InspectMetaResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	log.Info("inspect meta done", zap.Int("segments", len(resp.GetSegments())),
+		zap.Int("channelCheckpoints", len(resp.GetChannelCheckpoints())),
+		zap.Int("indexTasks", len(resp.GetIndexTasks())),
+		zap.Int("compactionPlans", len(resp.GetCompactionPlans())))
+	return resp, nil
+}
+
+// Backup flushes the collection and writes a manifest of its flushed segments to the object storage.
+// The manifest holds the segment meta, the segment index meta and the etags or checksums of all referenced files.
+// The garbage collection of the selected segments is frozen until the manifest is written, so that every
+// file listed in the manifest exists when it is returned.
+func (s *Server) Backup(ctx context.Context, req *datapb.BackupRequest) (*datapb.BackupResponse, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.GetCollectionID()),
+		zap.Int64s("partitionIDs", req.GetPartitionIDs()),
+		zap.String("name", req.GetName()),
+	)
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.BackupResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	if err := checkBackupName(req.GetName()); err != nil {
+		return &datapb.BackupResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	cm := s.meta.chunkManager
+	manifestPath := getBackupManifestPath(cm.RootPath(), req.GetName())
+	exist, err := cm.Exist(ctx, manifestPath)
+	if err != nil {
+		log.Warn("failed to check backup manifest", zap.Error(err))
+		return &datapb.BackupResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	if exist {
+		return &datapb.BackupResponse{
+			Status: merr.Status(merr.WrapErrParameterInvalidMsg("backup %s already exists", req.GetName())),
+		}, nil
+	}
+
+	log.Info("receive backup request")
+	flushResp, err := s.Flush(ctx, &datapb.FlushRequest{
+		DbID:         req.GetDbID(),
+		CollectionID: req.GetCollectionID(),
+	})
+	if err = merr.CheckRPCCall(flushResp, err); err != nil {
+		log.Warn("failed to flush collection for backup", zap.Error(err))
+		return &datapb.BackupResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	if err := s.waitForFlushed(ctx, req.GetCollectionID(), flushResp.GetSegmentIDs(), flushResp.GetFlushTs()); err != nil {
+		log.Warn("failed to wait for flush for backup", zap.Error(err))
+		return &datapb.BackupResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	segmentIDs := lo.Map(s.selectBackupSegments(req.GetCollectionID(), req.GetPartitionIDs()),
+		func(segment *SegmentInfo, _ int) int64 { return segment.GetID() })
+	s.garbageCollector.FreezeSegments(segmentIDs...)
+	defer s.garbageCollector.UnfreezeSegments(segmentIDs...)
+
+	// a segment may be recycled between selection and freeze, read them again once frozen
+	segments := make([]*SegmentInfo, 0, len(segmentIDs))
+	for _, segmentID := range segmentIDs {
+		segment := s.meta.GetSegment(segmentID)
+		if segment == nil {
+			err := merr.WrapErrSegmentNotFound(segmentID, "segment recycled during backup")
+			log.Warn("failed to collect segments for backup", zap.Error(err))
+			return &datapb.BackupResponse{
+				Status: merr.Status(err),
+			}, nil
+		}
+		segments = append(segments, segment)
+	}
+
+	manifest, err := buildBackupManifest(ctx, cm, s.meta, segments)
+	if err != nil {
+		log.Warn("failed to build backup manifest", zap.Error(err))
+		return &datapb.BackupResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	manifest.Name = req.GetName()
+	manifest.CollectionID = req.GetCollectionID()
+	manifest.PartitionIDs = req.GetPartitionIDs()
+	manifest.FlushTs = flushResp.GetFlushTs()
+	manifest.CreateTime = time.Now().Unix()
+	if err := writeBackupManifest(ctx, cm, manifestPath, manifest); err != nil {
+		log.Warn("failed to write backup manifest", zap.Error(err))
+		return &datapb.BackupResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	log.Info("backup manifest written", zap.String("manifestPath", manifestPath),
+		zap.Int("segments", len(manifest.GetSegments())), zap.Int("files", len(manifest.GetFiles())))
+	return &datapb.BackupResponse{
+		Status:       merr.Success(),
+		ManifestPath: manifestPath,
+		FlushTs:      flushResp.GetFlushTs(),
+		SegmentIDs:   segmentIDs,
+	}, nil
+}
+
+// Restore copies the segments of a backup manifest into the collection of the request, which is expected
+// to be created with the schema and shards num of the backup collection. The segments get new ids, and their
+// binlogs are copied to the paths of the new ids, so the backup is left untouched and may be restored again.
+// Index files are not restored, the indexes of the target collection are built on the restored segments.
+func (s *Server) Restore(ctx context.Context, req *datapb.RestoreRequest) (*datapb.RestoreResponse, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.GetCollectionID()),
+		zap.String("manifestPath", req.GetManifestPath()),
+	)
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.RestoreResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	log.Info("receive restore request")
+	cm := s.meta.chunkManager
+	manifest, err := readBackupManifest(ctx, cm, req.GetManifestPath())
+	if err != nil {
+		log.Warn("failed to read backup manifest", zap.Error(err))
+		return &datapb.RestoreResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	targetChannels := lo.Map(s.channelManager.GetChannelsByCollectionID(req.GetCollectionID()),
+		func(channel RWChannel, _ int) string { return channel.GetName() })
+	if len(targetChannels) == 0 {
+		err := merr.WrapErrCollectionNotFound(req.GetCollectionID(), "no channel watched")
+		log.Warn("failed to restore backup", zap.Error(err))
+		return &datapb.RestoreResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	backupChannels := lo.Uniq(lo.Map(manifest.GetSegments(),
+		func(segment *datapb.SegmentInfo, _ int) string { return segment.GetInsertChannel() }))
+	channelMapping, err := mapRestoreChannels(backupChannels, targetChannels)
+	if err != nil {
+		log.Warn("failed to map backup channels", zap.Error(err))
+		return &datapb.RestoreResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	// copy all the binlogs before adding the segments in one meta update,
+	// so that a failed restore leaves neither segment nor binlog copy behind
+	copier := newBinlogCopier(cm, manifest.GetFiles())
+	added := false
+	defer func() {
+		if !added {
+			copier.rollback(ctx)
+		}
+	}()
+	segments := make([]*SegmentInfo, 0, len(manifest.GetSegments()))
+	for _, backup := range manifest.GetSegments() {
+		partitionID, ok := req.GetPartitionMapping()[backup.GetPartitionID()]
+		if !ok {
+			err := merr.WrapErrParameterInvalidMsg("no target partition for backup partition %d", backup.GetPartitionID())
+			log.Warn("failed to restore backup", zap.Error(err))
+			return &datapb.RestoreResponse{
+				Status: merr.Status(err),
+			}, nil
+		}
+		channel := channelMapping[backup.GetInsertChannel()]
+		channelCP := s.meta.GetChannelCheckpoint(channel)
+		if channelCP == nil {
+			err := merr.WrapErrChannelNotFound(channel, "nil checkpoint")
+			log.Warn("failed to restore backup", zap.Error(err))
+			return &datapb.RestoreResponse{
+				Status: merr.Status(err),
+			}, nil
+		}
+		segmentID, err := s.allocator.allocID(ctx)
+		if err != nil {
+			log.Warn("failed to alloc segment id", zap.Error(err))
+			return &datapb.RestoreResponse{
+				Status: merr.Status(err),
+			}, nil
+		}
+
+		segment := &datapb.SegmentInfo{
+			ID:             segmentID,
+			CollectionID:   req.GetCollectionID(),
+			PartitionID:    partitionID,
+			InsertChannel:  channel,
+			NumOfRows:      backup.GetNumOfRows(),
+			State:          commonpb.SegmentState_Flushed,
+			MaxRowNum:      backup.GetMaxRowNum(),
+			LastExpireTime: backup.GetLastExpireTime(),
+			// the positions of the backup channel are meaningless for the target channel
+			StartPosition:  proto.Clone(channelCP).(*msgpb.MsgPosition),
+			DmlPosition:    proto.Clone(channelCP).(*msgpb.MsgPosition),
+			Level:          backup.GetLevel(),
+			StorageVersion: backup.GetStorageVersion(),
+		}
+		for _, restore := range []struct {
+			binlogType storage.BinlogType
+			source     []*datapb.FieldBinlog
+			target     *[]*datapb.FieldBinlog
+		}{
+			{storage.InsertBinlog, backup.GetBinlogs(), &segment.Binlogs},
+			{storage.StatsBinlog, backup.GetStatslogs(), &segment.Statslogs},
+			{storage.DeleteBinlog, backup.GetDeltalogs(), &segment.Deltalogs},
+		} {
+			*restore.target, err = copier.restoreBinlogs(ctx, restore.binlogType, restore.source,
+				segment.GetCollectionID(), segment.GetPartitionID(), segment.GetID())
+			if err != nil {
+				log.Warn("failed to copy backup binlogs", zap.Int64("backupSegmentID", backup.GetID()), zap.Error(err))
+				return &datapb.RestoreResponse{
+					Status: merr.Status(err),
+				}, nil
+			}
+		}
+		segments = append(segments, NewSegmentInfo(segment))
+	}
+
+	if err := s.meta.AddSegments(ctx, segments...); err != nil {
+		log.Warn("failed to add restored segments", zap.Error(err))
+		return &datapb.RestoreResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	added = true
+	segmentIDs := lo.Map(segments, func(segment *SegmentInfo, _ int) int64 { return segment.GetID() })
+
+	log.Info("backup restored", zap.String("backup", manifest.GetName()), zap.Int64s("segmentIDs", segmentIDs))
+	return &datapb.RestoreResponse{
+		Status:     merr.Success(),
+		SegmentIDs: segmentIDs,
+	}, nil
+}
+
+// PublishSegments registers the segments written by an external writer, e.g. a bulk writer of a Spark job,
+// so the rows are loaded without going through the message queue. The binlogs are copied to the paths of
+// the segments with new ids, and the segments are added as flushed in one meta update after all the binlogs
+// copied, so they are visible to queries all at once, or none of them if the publishing failed.
+func (s *Server) PublishSegments(ctx context.Context, req *datapb.PublishSegmentsRequest) (*datapb.PublishSegmentsResponse, error) {
+	log := log.Ctx(ctx).With(zap.String("manifestPath", req.GetManifestPath()))
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.PublishSegmentsResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	log.Info("receive publish segments request")
+	manifest, err := readPublishManifest(ctx, s.meta.chunkManager, req.GetManifestPath())
+	if err != nil {
+		log.Warn("failed to read publish manifest", zap.Error(err))
+		return &datapb.PublishSegmentsResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	segments, err := s.publishSegments(ctx, manifest.GetCollectionID(), manifest.GetPartitionID(), manifest.GetSegments(), manifest.GetFiles())
+	if err != nil {
+		return &datapb.PublishSegmentsResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	return &datapb.PublishSegmentsResponse{
+		Status:     merr.Success(),
+		SegmentIDs: lo.Map(segments, func(segment *SegmentInfo, _ int) int64 { return segment.GetID() }),
+	}, nil
+}
+
+// ImportSegmentBinlogs registers the binlog sets written by an external writer as sealed segments like PublishSegments,
+// with the binlogs in the request rather than in a manifest. The binlogs are verified against their checksums if set,
+// and the segments without a shard are assigned to the channels of the fewest rows.
+func (s *Server) ImportSegmentBinlogs(ctx context.Context, req *datapb.ImportSegmentBinlogsRequest) (*datapb.ImportSegmentBinlogsResponse, error) {
+	log := log.Ctx(ctx).With(zap.Int64("collectionID", req.GetCollectionID()), zap.Int64("partitionID", req.GetPartitionID()))
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.ImportSegmentBinlogsResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	log.Info("receive import segment binlogs request", zap.Int("segmentNum", len(req.GetSegments())))
+	segments, err := s.publishSegments(ctx, req.GetCollectionID(), req.GetPartitionID(), req.GetSegments(), nil)
+	if err != nil {
+		return &datapb.ImportSegmentBinlogsResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	return &datapb.ImportSegmentBinlogsResponse{
+		Status:     merr.Success(),
+		SegmentIDs: lo.Map(segments, func(segment *SegmentInfo, _ int) int64 { return segment.GetID() }),
+		Channels:   lo.Map(segments, func(segment *SegmentInfo, _ int) string { return segment.GetInsertChannel() }),
+	}, nil
+}
+
+// publishSegments validates the published segments against the schema, copies their binlogs to the paths of
+// the segments with new ids, and adds them as flushed segments of the partition in one meta update.
+func (s *Server) publishSegments(ctx context.Context, collectionID, partitionID int64,
+	publishedSegments []*datapb.PublishedSegment, files []*datapb.BackupFile,
+) ([]*SegmentInfo, error) {
+	log := log.Ctx(ctx).With(zap.Int64("collectionID", collectionID), zap.Int64("partitionID", partitionID))
+	collection, err := s.handler.GetCollection(ctx, collectionID)
+	if err == nil && collection == nil {
+		err = merr.WrapErrCollectionNotFound(collectionID)
+	}
+	if err != nil {
+		log.Warn("failed to get collection", zap.Error(err))
+		return nil, err
+	}
+	if !lo.Contains(collection.Partitions, partitionID) {
+		err := merr.WrapErrPartitionNotFound(partitionID)
+		log.Warn("failed to publish segments", zap.Error(err))
+		return nil, err
+	}
+	if len(publishedSegments) == 0 {
+		err := merr.WrapErrParameterInvalidMsg("no segment to publish")
+		log.Warn("failed to publish segments", zap.Error(err))
+		return nil, err
+	}
+	maxRowNum, err := calBySchemaPolicy(collection.Schema)
+	if err != nil {
+		log.Warn("failed to estimate max row num of segment", zap.Error(err))
+		return nil, err
+	}
+	shards, err := mapShardChannels(lo.Map(s.channelManager.GetChannelsByCollectionID(collectionID),
+		func(channel RWChannel, _ int) string { return channel.GetName() }))
+	if err != nil {
+		log.Warn("failed to map shard channels", zap.Error(err))
+		return nil, err
+	}
+	for _, published := range publishedSegments {
+		err := checkPublishedSegment(published, collection.Schema)
+		if err == nil && published.GetShardIndex() != autoShardIndex && shards[int(published.GetShardIndex())] == "" {
+			err = merr.WrapErrParameterInvalidMsg("no channel watched for shard %d", published.GetShardIndex())
+		}
+		if err != nil {
+			log.Warn("invalid published segment", zap.Error(err))
+			return nil, err
+		}
+	}
+	healthySegments := s.meta.SelectSegments(func(segment *SegmentInfo) bool {
+		return segment.GetCollectionID() == collectionID && isSegmentHealthy(segment)
+	})
+	channels, err := assignPublishedSegments(shards, healthySegments, publishedSegments)
+	if err != nil {
+		log.Warn("failed to assign published segments", zap.Error(err))
+		return nil, err
+	}
+
+	copier := newBinlogCopier(s.meta.chunkManager, files)
+	added := false
+	defer func() {
+		if !added {
+			copier.rollback(ctx)
+		}
+	}()
+	allocLogID := func(*datapb.Binlog) (UniqueID, error) {
+		return s.allocator.allocID(ctx)
+	}
+	segments := make([]*SegmentInfo, 0, len(publishedSegments))
+	for i, published := range publishedSegments {
+		channel := channels[i]
+		channelCP := s.meta.GetChannelCheckpoint(channel)
+		if channelCP == nil {
+			err := merr.WrapErrChannelNotFound(channel, "nil checkpoint")
+			log.Warn("failed to publish segments", zap.Error(err))
+			return nil, err
+		}
+		segmentID, err := s.allocator.allocID(ctx)
+		if err != nil {
+			log.Warn("failed to alloc segment id", zap.Error(err))
+			return nil, err
+		}
+
+		segment := &datapb.SegmentInfo{
+			ID:            segmentID,
+			CollectionID:  collectionID,
+			PartitionID:   partitionID,
+			InsertChannel: channel,
+			NumOfRows:     published.GetNumOfRows(),
+			State:         commonpb.SegmentState_Flushed,
+			MaxRowNum:     int64(maxRowNum),
+			StartPosition: proto.Clone(channelCP).(*msgpb.MsgPosition),
+			DmlPosition:   proto.Clone(channelCP).(*msgpb.MsgPosition),
+			Level:         datapb.SegmentLevel_L1,
+		}
+		for _, publish := range []struct {
+			binlogType storage.BinlogType
+			source     []*datapb.FieldBinlog
+			target     *[]*datapb.FieldBinlog
+		}{
+			{storage.InsertBinlog, published.GetBinlogs(), &segment.Binlogs},
+			{storage.StatsBinlog, published.GetStatslogs(), &segment.Statslogs},
+		} {
+			*publish.target, err = copier.copyBinlogs(ctx, publish.binlogType, publish.source,
+				segment.GetCollectionID(), segment.GetPartitionID(), segment.GetID(), allocLogID)
+			if err != nil {
+				log.Warn("failed to copy published binlogs", zap.Int64("segmentID", segmentID), zap.Error(err))
+				return nil, err
+			}
+		}
+		segments = append(segments, NewSegmentInfo(segment))
+	}
+
+	if err := s.meta.AddSegments(ctx, segments...); err != nil {
+		log.Warn("failed to add published segments", zap.Error(err))
+		return nil, err
+	}
+	added = true
+	log.Info("segments published", zap.Int64s("segmentIDs", lo.Map(segments, func(segment *SegmentInfo, _ int) int64 {
+		return segment.GetID()
+	})))
+	return segments, nil
+}
+
+// Export starts a background job writing the live rows of a partition as parquet files,
+// the progress can be checked by GetExportState.
+func (s *Server) Export(ctx context.Context, req *datapb.ExportRequest) (*datapb.ExportResponse, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.GetCollectionID()),
+		zap.Int64("partitionID", req.GetPartitionID()),
+		zap.String("targetPrefix", req.GetTargetPrefix()),
+	)
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.ExportResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	log.Info("receive export request")
+	if req.GetTargetPrefix() == "" {
+		return &datapb.ExportResponse{
+			Status: merr.Status(merr.WrapErrParameterInvalidMsg("export target prefix is empty")),
+		}, nil
+	}
+	coll, err := s.handler.GetCollection(ctx, req.GetCollectionID())
+	if err != nil {
+		log.Warn("failed to get collection", zap.Error(err))
+		return &datapb.ExportResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	if coll == nil {
+		return &datapb.ExportResponse{
+			Status: merr.Status(merr.WrapErrCollectionNotFound(req.GetCollectionID())),
+		}, nil
+	}
+	if !lo.Contains(coll.Partitions, req.GetPartitionID()) {
+		return &datapb.ExportResponse{
+			Status: merr.Status(merr.WrapErrPartitionNotFound(req.GetPartitionID())),
+		}, nil
+	}
+
+	jobID, err := s.allocator.allocID(ctx)
+	if err != nil {
+		log.Warn("failed to alloc export job id", zap.Error(err))
+		return &datapb.ExportResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	exportTs, err := s.allocator.allocTimestamp(ctx)
+	if err != nil {
+		log.Warn("failed to alloc export timestamp", zap.Error(err))
+		return &datapb.ExportResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	s.exportManager.submit(&datapb.ExportJob{
+		JobID:        jobID,
+		CollectionID: req.GetCollectionID(),
+		PartitionID:  req.GetPartitionID(),
+		TargetPrefix: req.GetTargetPrefix(),
+		ExportTs:     exportTs,
+	})
+
+	log.Info("export job submitted", zap.Int64("jobID", jobID), zap.Uint64("exportTs", exportTs))
+	return &datapb.ExportResponse{
+		Status: merr.Success(),
+		JobID:  jobID,
+	}, nil
+}
+
+// GetExportState returns the state and progress of an export job.
+func (s *Server) GetExportState(ctx context.Context, req *datapb.GetExportStateRequest) (*datapb.GetExportStateResponse, error) {
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.GetExportStateResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	job := s.exportManager.getJob(req.GetJobID())
+	if job == nil {
+		return &datapb.GetExportStateResponse{
+			Status: merr.Status(merr.WrapErrParameterInvalidMsg("export job %d not found", req.GetJobID())),
+		}, nil
+	}
+	return &datapb.GetExportStateResponse{
+		Status: merr.Success(),
+		Job:    job,
+	}, nil
+}
+
+// DecommissionNode starts to drain the DataNode or IndexNode in the background,
+// the progress can be checked by GetDecommissionState.
+func (s *Server) DecommissionNode(ctx context.Context, req *datapb.DecommissionNodeRequest) (*commonpb.Status, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("nodeID", req.GetNodeID()),
+		zap.String("role", req.GetRole()),
+	)
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return merr.Status(err), nil
+	}
+
+	log.Info("receive decommission node request")
+	if err := s.checkDecommissionNode(req.GetNodeID(), req.GetRole()); err != nil {
+		log.Warn("failed to decommission node", zap.Error(err))
+		return merr.Status(err), nil
+	}
+	if err := s.decommissionManager.decommission(req.GetNodeID(), req.GetRole()); err != nil {
+		log.Warn("failed to decommission node", zap.Error(err))
+		return merr.Status(err), nil
+	}
+	return merr.Success(), nil
+}
+
+// checkDecommissionNode checks the node of @role is online.
+func (s *Server) checkDecommissionNode(nodeID int64, role string) error {
+	switch role {
+	case typeutil.DataNodeRole:
+		if !lo.Contains(s.sessionManager.GetSessionIDs(), nodeID) {
+			return merr.WrapErrNodeNotFound(nodeID)
+		}
+	case typeutil.IndexNodeRole:
+		if _, ok := s.indexNodeManager.GetClientByID(nodeID); !ok {
+			return merr.WrapErrNodeNotFound(nodeID)
+		}
+	default:
+		return merr.WrapErrParameterInvalidMsg("datacoord could not decommission node of role %q", role)
+	}
+	return nil
+}
+
+// GetDecommissionState returns the decommission progress of a DataNode or IndexNode,
+// the node is safe to terminate once it's Decommissioned.
+func (s *Server) GetDecommissionState(ctx context.Context, req *datapb.GetDecommissionStateRequest) (*datapb.GetDecommissionStateResponse, error) {
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.GetDecommissionStateResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	progress := s.decommissionManager.getProgress(req.GetNodeID())
+	if progress == nil {
+		if err := s.checkDecommissionNode(req.GetNodeID(), req.GetRole()); err != nil {
+			return &datapb.GetDecommissionStateResponse{
+				Status: merr.Status(err),
+			}, nil
+		}
+		progress = &datapb.DecommissionProgress{
+			NodeID: req.GetNodeID(),
+			Role:   req.GetRole(),
+			State:  datapb.DecommissionState_DecommissionNone,
+		}
+	}
+	return &datapb.GetDecommissionStateResponse{
+		Status:   merr.Success(),
+		Progress: progress,
+	}, nil
+}
+
+// GetSegmentEvents returns the recorded lifecycle events of segments, ordered by time.
+func (s *Server) GetSegmentEvents(ctx context.Context, req *datapb.GetSegmentEventsRequest) (*datapb.GetSegmentEventsResponse, error) {
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.GetSegmentEventsResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	if s.meta.eventLog == nil {
+		return &datapb.GetSegmentEventsResponse{
+			Status: merr.Status(merr.WrapErrServiceUnavailable("segment event log not initialized")),
+		}, nil
+	}
+
+	events, err := s.meta.eventLog.list(ctx, req)
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to list segment events",
+			zap.Int64("collectionID", req.GetCollectionID()),
+			zap.Int64("segmentID", req.GetSegmentID()),
+			zap.Error(err))
+		return &datapb.GetSegmentEventsResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	return &datapb.GetSegmentEventsResponse{
+		Status: merr.Success(),
+		Events: events,
+	}, nil
+}
+
+// RefreshSegmentStats submits a plan per segment rewriting the statslogs of the sealed segment from its insert binlogs,
+// the segments are validated all before any plan is submitted.
+func (s *Server) RefreshSegmentStats(ctx context.Context, req *datapb.RefreshSegmentStatsRequest) (*datapb.RefreshSegmentStatsResponse, error) {
+	log := log.Ctx(ctx).With(zap.Int64s("segmentIDs", req.GetSegmentIDs()))
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.RefreshSegmentStatsResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	if !Params.DataCoordCfg.EnableCompaction.GetAsBool() {
+		return &datapb.RefreshSegmentStatsResponse{
+			Status: merr.Status(merr.WrapErrServiceUnavailable("compaction disabled")),
+		}, nil
+	}
+
+	log.Info("receive refresh segment stats request")
+	if len(req.GetSegmentIDs()) == 0 {
+		return &datapb.RefreshSegmentStatsResponse{
+			Status: merr.Status(merr.WrapErrParameterInvalidMsg("no segment to refresh")),
+		}, nil
+	}
+	segments := make([]*SegmentInfo, 0, len(req.GetSegmentIDs()))
+	for _, segmentID := range lo.Uniq(req.GetSegmentIDs()) {
+		segment := s.meta.GetHealthySegment(segmentID)
+		if segment == nil {
+			return &datapb.RefreshSegmentStatsResponse{
+				Status: merr.Status(merr.WrapErrSegmentNotFound(segmentID)),
+			}, nil
+		}
+		var err error
+		switch {
+		case segment.GetState() != commonpb.SegmentState_Flushed:
+			err = merr.WrapErrSegmentNotFound(segmentID, "segment is not flushed")
+		case segment.GetLevel() == datapb.SegmentLevel_L0:
+			err = merr.WrapErrParameterInvalidMsg("segment %d is of level zero without insert binlogs", segmentID)
+		case segment.GetIsImporting():
+			err = merr.WrapErrParameterInvalidMsg("segment %d is importing", segmentID)
+		case segment.isCompacting:
+			err = merr.WrapErrServiceUnavailable(fmt.Sprintf("segment %d is compacting", segmentID))
+		}
+		if err != nil {
+			log.Warn("failed to refresh segment stats", zap.Error(err))
+			return &datapb.RefreshSegmentStatsResponse{
+				Status: merr.Status(err),
+			}, nil
+		}
+		segments = append(segments, segment)
+	}
+
+	compactionID, err := s.allocator.allocID(ctx)
+	if err != nil {
+		log.Warn("failed to alloc compaction id", zap.Error(err))
+		return &datapb.RefreshSegmentStatsResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	planIDs := make([]int64, 0, len(segments))
+	for _, segment := range segments {
+		plan := &datapb.CompactionPlan{
+			Type:      datapb.CompactionType_StatsRefreshCompaction,
+			Channel:   segment.GetInsertChannel(),
+			TotalRows: segment.GetNumOfRows(),
+			SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{{
+				SegmentID:           segment.GetID(),
+				FieldBinlogs:        segment.GetBinlogs(),
+				Field2StatslogPaths: segment.GetStatslogs(),
+				Level:               segment.GetLevel(),
+				CollectionID:        segment.GetCollectionID(),
+				PartitionID:         segment.GetPartitionID(),
+			}},
+		}
+		err := fillOriginPlan(s.allocator, plan)
+		if err == nil {
+			err = s.compactionHandler.execCompactionPlan(&compactionSignal{
+				id:           compactionID,
+				isForce:      true,
+				collectionID: segment.GetCollectionID(),
+				partitionID:  segment.GetPartitionID(),
+				channel:      segment.GetInsertChannel(),
+				segmentID:    segment.GetID(),
+			}, plan)
+		}
+		if err != nil {
+			// the plans submitted are kept, which are tracked by the compaction id as well
+			log.Warn("failed to submit stats refresh plan", zap.Int64("segmentID", segment.GetID()), zap.Error(err))
+			return &datapb.RefreshSegmentStatsResponse{
+				Status:       merr.Status(err),
+				CompactionID: compactionID,
+				PlanIDs:      planIDs,
+			}, nil
+		}
+		planIDs = append(planIDs, plan.GetPlanID())
+	}
+
+	log.Info("stats refresh plans submitted", zap.Int64("compactionID", compactionID), zap.Int64s("planIDs", planIDs))
+	return &datapb.RefreshSegmentStatsResponse{
+		Status:       merr.Success(),
+		CompactionID: compactionID,
+		PlanIDs:      planIDs,
+	}, nil
+}
+
+// GetSegmentSnapshot returns the segments of the collection visible at the timestamp with their binlog paths,
+// the binlogs are kept from garbage collection for the lease if requested.
+func (s *Server) GetSegmentSnapshot(ctx context.Context, req *datapb.GetSegmentSnapshotRequest) (*datapb.GetSegmentSnapshotResponse, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.GetCollectionID()),
+		zap.Int64s("partitionIDs", req.GetPartitionIDs()),
+		zap.Uint64("ts", req.GetTimestamp()),
+	)
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.GetSegmentSnapshotResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	log.Info("receive get segment snapshot request")
+	channels := s.channelManager.GetChannelsByCollectionID(req.GetCollectionID())
+	if len(channels) == 0 {
+		err := merr.WrapErrCollectionNotFound(req.GetCollectionID(), "no channel watched")
+		log.Warn("failed to get segment snapshot", zap.Error(err))
+		return &datapb.GetSegmentSnapshotResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	// the rows before the minimal checkpoint of the channels are all flushed
+	checkpoint := uint64(math.MaxUint64)
+	for _, channel := range channels {
+		channelCP := s.meta.GetChannelCheckpoint(channel.GetName())
+		if channelCP == nil {
+			err := merr.WrapErrChannelNotFound(channel.GetName(), "nil checkpoint")
+			log.Warn("failed to get segment snapshot", zap.Error(err))
+			return &datapb.GetSegmentSnapshotResponse{
+				Status: merr.Status(err),
+			}, nil
+		}
+		if channelCP.GetTimestamp() < checkpoint {
+			checkpoint = channelCP.GetTimestamp()
+		}
+	}
+	ts := req.GetTimestamp()
+	if ts == 0 {
+		ts = checkpoint
+	}
+	if err := checkSnapshotTs(ts, checkpoint, Params.DataCoordCfg.GCDropTolerance.GetAsDuration(time.Second)); err != nil {
+		log.Warn("failed to get segment snapshot", zap.Error(err))
+		return &datapb.GetSegmentSnapshotResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	partitionSet := typeutil.NewUniqueSet(req.GetPartitionIDs()...)
+	segments, err := selectSnapshotSegments(s.meta.SelectSegments(func(segment *SegmentInfo) bool {
+		return segment.GetCollectionID() == req.GetCollectionID() &&
+			(partitionSet.Len() == 0 || partitionSet.Contain(segment.GetPartitionID()))
+	}), ts)
+	if err != nil {
+		log.Warn("failed to get segment snapshot", zap.Error(err))
+		return &datapb.GetSegmentSnapshotResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	segmentIDs := lo.Map(segments, func(segment *SegmentInfo, _ int) int64 { return segment.GetID() })
+	if req.GetLeaseSeconds() > 0 {
+		s.garbageCollector.FreezeSegments(segmentIDs...)
+		time.AfterFunc(time.Duration(req.GetLeaseSeconds())*time.Second, func() {
+			s.garbageCollector.UnfreezeSegments(segmentIDs...)
+		})
+		// a segment may be recycled between selection and freeze
+		for _, segmentID := range segmentIDs {
+			if s.meta.GetSegment(segmentID) == nil {
+				err := merr.WrapErrSegmentNotFound(segmentID, "segment recycled during snapshot")
+				log.Warn("failed to get segment snapshot", zap.Error(err))
+				return &datapb.GetSegmentSnapshotResponse{
+					Status: merr.Status(err),
+				}, nil
+			}
+		}
+	}
+
+	resp := &datapb.GetSegmentSnapshotResponse{
+		Status:    merr.Success(),
+		Timestamp: ts,
+		Segments:  make([]*datapb.SegmentInfo, 0, len(segments)),
+	}
+	for _, segment := range segments {
+		cloned := segment.Clone()
+		if err := binlog.DecompressBinLogs(cloned.SegmentInfo); err != nil {
+			log.Warn("failed to decompress binlogs", zap.Int64("segmentID", segment.GetID()), zap.Error(err))
+			return &datapb.GetSegmentSnapshotResponse{
+				Status: merr.Status(err),
+			}, nil
+		}
+		resp.Segments = append(resp.Segments, cloned.SegmentInfo)
+	}
+	log.Info("get segment snapshot done", zap.Uint64("snapshotTs", ts), zap.Int64s("segmentIDs", segmentIDs),
+		zap.Int64("leaseSeconds", req.GetLeaseSeconds()))
+	return resp, nil
+}
+
+// ListCompactionTasks returns the compaction tasks queuing and executing in the scheduler, in the order of the priorities.
+func (s *Server) ListCompactionTasks(ctx context.Context, req *datapb.ListCompactionTasksRequest) (*datapb.ListCompactionTasksResponse, error) {
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.ListCompactionTasksResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	if !Params.DataCoordCfg.EnableCompaction.GetAsBool() {
+		return &datapb.ListCompactionTasksResponse{
+			Status: merr.Status(merr.WrapErrServiceUnavailable("compaction disabled")),
+		}, nil
+	}
+
+	queuing, executing, pressuredNodes := s.compactionHandler.listTasks()
+	toViews := func(tasks []*compactionTask) []*datapb.CompactionTaskView {
+		views := make([]*datapb.CompactionTaskView, 0, len(tasks))
+		for _, task := range tasks {
+			if req.GetCollectionID() != 0 && task.triggerInfo.collectionID != req.GetCollectionID() {
+				continue
+			}
+			views = append(views, &datapb.CompactionTaskView{
+				PlanID:         task.plan.GetPlanID(),
+				TriggerID:      task.triggerInfo.id,
+				CollectionID:   task.triggerInfo.collectionID,
+				Channel:        task.plan.GetChannel(),
+				NodeID:         task.dataNodeID,
+				Type:           task.plan.GetType(),
+				Priority:       task.priority.String(),
+				State:          task.state.String(),
+				PreemptedTimes: task.preemptedTimes,
+			})
+		}
+		return views
+	}
+	return &datapb.ListCompactionTasksResponse{
+		Status:              merr.Success(),
+		Queuing:             toViews(queuing),
+		Executing:           toViews(executing),
+		FlushPressuredNodes: pressuredNodes,
+	}, nil
+}
+
+// MajorCompaction starts a job merging all the sealed segments of the partition into segments of the target size,
+// the progress can be checked by GetMajorCompactionState.
+func (s *Server) MajorCompaction(ctx context.Context, req *datapb.MajorCompactionRequest) (*datapb.MajorCompactionResponse, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.GetCollectionID()),
+		zap.Int64("partitionID", req.GetPartitionID()),
+		zap.Int64("targetSize", req.GetTargetSize()),
+	)
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.MajorCompactionResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	if !Params.DataCoordCfg.EnableCompaction.GetAsBool() {
+		return &datapb.MajorCompactionResponse{
+			Status: merr.Status(merr.WrapErrServiceUnavailable("compaction disabled")),
+		}, nil
+	}
+
+	log.Info("receive major compaction request")
+	if req.GetTargetSize() < 0 {
+		return &datapb.MajorCompactionResponse{
+			Status: merr.Status(merr.WrapErrParameterInvalidMsg("major compaction target size %d is negative", req.GetTargetSize())),
+		}, nil
+	}
+	coll, err := s.handler.GetCollection(ctx, req.GetCollectionID())
+	if err != nil {
+		log.Warn("failed to get collection", zap.Error(err))
+		return &datapb.MajorCompactionResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	if coll == nil {
+		return &datapb.MajorCompactionResponse{
+			Status: merr.Status(merr.WrapErrCollectionNotFound(req.GetCollectionID())),
+		}, nil
+	}
+	if !lo.Contains(coll.Partitions, req.GetPartitionID()) {
+		return &datapb.MajorCompactionResponse{
+			Status: merr.Status(merr.WrapErrPartitionNotFound(req.GetPartitionID())),
+		}, nil
+	}
+
+	jobID, err := s.allocator.allocID(ctx)
+	if err != nil {
+		log.Warn("failed to alloc major compaction job id", zap.Error(err))
+		return &datapb.MajorCompactionResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	err = s.majorCompactionManager.submit(&datapb.MajorCompactionJob{
+		JobID:        jobID,
+		CollectionID: req.GetCollectionID(),
+		PartitionID:  req.GetPartitionID(),
+		TargetSize:   req.GetTargetSize(),
+	})
+	if err != nil {
+		log.Warn("failed to submit major compaction job", zap.Error(err))
+		return &datapb.MajorCompactionResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	log.Info("major compaction job submitted", zap.Int64("jobID", jobID))
+	return &datapb.MajorCompactionResponse{
+		Status: merr.Success(),
+		JobID:  jobID,
+	}, nil
+}
+
+func (s *Server) GetMajorCompactionState(ctx context.Context, req *datapb.GetMajorCompactionStateRequest) (*datapb.GetMajorCompactionStateResponse, error) {
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.GetMajorCompactionStateResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	if !Params.DataCoordCfg.EnableCompaction.GetAsBool() {
+		return &datapb.GetMajorCompactionStateResponse{
+			Status: merr.Status(merr.WrapErrServiceUnavailable("compaction disabled")),
+		}, nil
+	}
+
+	job := s.majorCompactionManager.getJob(req.GetJobID())
+	if job == nil {
+		return &datapb.GetMajorCompactionStateResponse{
+			Status: merr.Status(merr.WrapErrParameterInvalidMsg("major compaction job %d not found", req.GetJobID())),
+		}, nil
+	}
+	return &datapb.GetMajorCompactionStateResponse{
+		Status: merr.Success(),
+		Job:    job,
+	}, nil
+}
+
+// AlterMajorCompaction pauses or resumes the major compaction job.
+func (s *Server) AlterMajorCompaction(ctx context.Context, req *datapb.AlterMajorCompactionRequest) (*commonpb.Status, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("jobID", req.GetJobID()),
+		zap.Stringer("command", req.GetCommand()),
+	)
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return merr.Status(err), nil
+	}
+	if !Params.DataCoordCfg.EnableCompaction.GetAsBool() {
+		return merr.Status(merr.WrapErrServiceUnavailable("compaction disabled")), nil
+	}
+
+	if err := s.majorCompactionManager.alter(req.GetJobID(), req.GetCommand()); err != nil {
+		log.Warn("failed to alter major compaction job", zap.Error(err))
+		return merr.Status(err), nil
+	}
+	return merr.Success(), nil
+}
+
+// GetGcReport returns the reconciliation report of the object storage against the meta,
+// the storage is reconciled right now if requested, which never removes the orphans.
+func (s *Server) GetGcReport(ctx context.Context, req *datapb.GetGcReportRequest) (*datapb.GetGcReportResponse, error) {
+	if err := merr.CheckHealthy(s.GetStateCode()); err != nil {
+		return &datapb.GetGcReportResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
+	report, err := s.garbageCollector.GetReport(ctx, req.GetRefresh())
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to get gc report", zap.Bool("refresh", req.GetRefresh()), zap.Error(err))
+		return &datapb.GetGcReportResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	return &datapb.GetGcReportResponse{
+		Status: merr.Success(),
+		Report: report,
+	}, nil
+}
@@ -2,6 +2,11 @@ package datacoord
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
 	"testing"
 	"time"
 
@@ -18,15 +23,19 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/metastore/model"
 	"github.com/milvus-io/milvus/internal/mocks"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/metautil"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
 )
 
 type ServerSuite struct {
@@ -1403,3 +1412,789 @@ func (s *GcControlServiceSuite) TestTimeoutCtx() {
 func TestGcControlService(t *testing.T) {
 	suite.Run(t, new(GcControlServiceSuite))
 }
+
+type InspectMetaServiceSuite struct {
+	suite.Suite
+
+	server *Server
+}
+
+func (s *InspectMetaServiceSuite) SetupTest() {
+	s.server = newTestServer(s.T(), nil)
+}
+
+func (s *InspectMetaServiceSuite) TearDownTest() {
+	if s.server != nil {
+		closeTestServer(s.T(), s.server)
+	}
+}
+
+func (s *InspectMetaServiceSuite) TestClosedServer() {
+	closeTestServer(s.T(), s.server)
+	resp, err := s.server.InspectMeta(context.TODO(), &datapb.InspectMetaRequest{})
+	s.NoError(err)
+	s.False(merr.Ok(resp.GetStatus()))
+	s.server = nil
+}
+
+func (s *InspectMetaServiceSuite) TestUnknownTarget() {
+	resp, err := s.server.InspectMeta(context.TODO(), &datapb.InspectMetaRequest{
+		Target: datapb.MetaInspectTarget(100),
+	})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrParameterInvalid)
+}
+
+func (s *InspectMetaServiceSuite) TestSegments() {
+	segments := []*datapb.SegmentInfo{
+		{ID: 1, CollectionID: 100, InsertChannel: "ch1", State: commonpb.SegmentState_Flushed},
+		{ID: 2, CollectionID: 100, InsertChannel: "ch2", State: commonpb.SegmentState_Growing},
+		{ID: 3, CollectionID: 101, InsertChannel: "ch3", State: commonpb.SegmentState_Flushed},
+	}
+	for _, segment := range segments {
+		s.Require().NoError(s.server.meta.AddSegment(context.TODO(), NewSegmentInfo(segment)))
+	}
+
+	resp, err := s.server.InspectMeta(context.TODO(), &datapb.InspectMetaRequest{
+		Target: datapb.MetaInspectTarget_InspectSegments,
+	})
+	s.NoError(err)
+	s.True(merr.Ok(resp.GetStatus()))
+	s.Len(resp.GetSegments(), 3)
+
+	resp, err = s.server.InspectMeta(context.TODO(), &datapb.InspectMetaRequest{
+		Target:       datapb.MetaInspectTarget_InspectSegments,
+		CollectionID: 100,
+		States:       []commonpb.SegmentState{commonpb.SegmentState_Flushed},
+	})
+	s.NoError(err)
+	s.True(merr.Ok(resp.GetStatus()))
+	s.Require().Len(resp.GetSegments(), 1)
+	s.EqualValues(1, resp.GetSegments()[0].GetID())
+
+	resp, err = s.server.InspectMeta(context.TODO(), &datapb.InspectMetaRequest{
+		Target:  datapb.MetaInspectTarget_InspectSegments,
+		Channel: "ch2",
+	})
+	s.NoError(err)
+	s.Require().Len(resp.GetSegments(), 1)
+	s.EqualValues(2, resp.GetSegments()[0].GetID())
+}
+
+func (s *InspectMetaServiceSuite) TestChannelCheckpoints() {
+	s.Require().NoError(s.server.meta.UpdateChannelCheckpoint("ch1", &msgpb.MsgPosition{ChannelName: "ch1", MsgID: []byte{1}, Timestamp: 100}))
+	s.Require().NoError(s.server.meta.UpdateChannelCheckpoint("ch2", &msgpb.MsgPosition{ChannelName: "ch2", MsgID: []byte{2}, Timestamp: 200}))
+
+	resp, err := s.server.InspectMeta(context.TODO(), &datapb.InspectMetaRequest{
+		Target: datapb.MetaInspectTarget_InspectChannelCheckpoints,
+	})
+	s.NoError(err)
+	s.True(merr.Ok(resp.GetStatus()))
+	s.Len(resp.GetChannelCheckpoints(), 2)
+
+	resp, err = s.server.InspectMeta(context.TODO(), &datapb.InspectMetaRequest{
+		Target:  datapb.MetaInspectTarget_InspectChannelCheckpoints,
+		Channel: "ch2",
+	})
+	s.NoError(err)
+	s.Require().Len(resp.GetChannelCheckpoints(), 1)
+	s.EqualValues(200, resp.GetChannelCheckpoints()["ch2"].GetTimestamp())
+}
+
+func (s *InspectMetaServiceSuite) TestIndexTasksAndCompactionPlans() {
+	resp, err := s.server.InspectMeta(context.TODO(), &datapb.InspectMetaRequest{
+		Target: datapb.MetaInspectTarget_InspectIndexTasks,
+	})
+	s.NoError(err)
+	s.True(merr.Ok(resp.GetStatus()))
+	s.Empty(resp.GetIndexTasks())
+
+	resp, err = s.server.InspectMeta(context.TODO(), &datapb.InspectMetaRequest{
+		Target: datapb.MetaInspectTarget_InspectCompactionPlans,
+	})
+	s.NoError(err)
+	s.True(merr.Ok(resp.GetStatus()))
+	s.Empty(resp.GetCompactionPlans())
+}
+
+func TestInspectMetaService(t *testing.T) {
+	suite.Run(t, new(InspectMetaServiceSuite))
+}
+
+type BackupServiceSuite struct {
+	suite.Suite
+
+	server *Server
+}
+
+func (s *BackupServiceSuite) SetupTest() {
+	s.server = newTestServer(s.T(), nil)
+}
+
+func (s *BackupServiceSuite) TearDownTest() {
+	if s.server != nil {
+		s.server.meta.chunkManager.RemoveWithPrefix(context.TODO(), s.server.meta.chunkManager.RootPath())
+		closeTestServer(s.T(), s.server)
+	}
+}
+
+// addSegment adds a segment with an insert binlog to the meta, and writes the binlog if @write is true.
+func (s *BackupServiceSuite) addSegment(segmentID int64, state commonpb.SegmentState, write bool) string {
+	cm := s.server.meta.chunkManager
+	logPath := metautil.BuildInsertLogPath(cm.RootPath(), 100, 10, segmentID, 1, segmentID*10)
+	segment := &datapb.SegmentInfo{
+		ID:            segmentID,
+		CollectionID:  100,
+		PartitionID:   10,
+		InsertChannel: "ch1",
+		NumOfRows:     10,
+		State:         state,
+		Binlogs: []*datapb.FieldBinlog{
+			{FieldID: 1, Binlogs: []*datapb.Binlog{{EntriesNum: 10, LogID: segmentID * 10, LogPath: logPath}}},
+		},
+	}
+	s.Require().NoError(s.server.meta.AddSegment(context.TODO(), NewSegmentInfo(segment)))
+	if write {
+		s.Require().NoError(cm.Write(context.TODO(), logPath, []byte("binlog")))
+	}
+	return logPath
+}
+
+func (s *BackupServiceSuite) TestClosedServer() {
+	closeTestServer(s.T(), s.server)
+	resp, err := s.server.Backup(context.TODO(), &datapb.BackupRequest{Name: "backup"})
+	s.NoError(err)
+	s.False(merr.Ok(resp.GetStatus()))
+	s.server = nil
+}
+
+func (s *BackupServiceSuite) TestInvalidName() {
+	for _, name := range []string{"", "a/b", ".."} {
+		resp, err := s.server.Backup(context.TODO(), &datapb.BackupRequest{CollectionID: 100, Name: name})
+		s.NoError(err)
+		s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrParameterInvalid)
+	}
+}
+
+func (s *BackupServiceSuite) TestBackup() {
+	cm := s.server.meta.chunkManager
+	logPath := s.addSegment(1, commonpb.SegmentState_Flushed, true)
+	s.addSegment(2, commonpb.SegmentState_Growing, false)
+
+	s.Require().NoError(s.server.meta.CreateIndex(&model.Index{CollectionID: 100, FieldID: 1, IndexID: 1000}))
+	s.Require().NoError(s.server.meta.AddSegmentIndex(&model.SegmentIndex{
+		SegmentID:    1,
+		CollectionID: 100,
+		PartitionID:  10,
+		IndexID:      1000,
+		BuildID:      2000,
+	}))
+	s.Require().NoError(s.server.meta.FinishTask(&indexpb.IndexTaskInfo{
+		BuildID:       2000,
+		State:         commonpb.IndexState_Finished,
+		IndexFileKeys: []string{"index"},
+	}))
+	indexPath := metautil.BuildSegmentIndexFilePath(cm.RootPath(), 2000, 0, 10, 1, "index")
+	s.Require().NoError(cm.Write(context.TODO(), indexPath, []byte("index")))
+
+	resp, err := s.server.Backup(context.TODO(), &datapb.BackupRequest{CollectionID: 100, Name: "backup"})
+	s.NoError(err)
+	s.Require().True(merr.Ok(resp.GetStatus()))
+	s.ElementsMatch([]int64{1}, resp.GetSegmentIDs())
+	s.False(s.server.garbageCollector.isFrozen(1))
+
+	data, err := cm.Read(context.TODO(), resp.GetManifestPath())
+	s.Require().NoError(err)
+	manifest := &datapb.BackupManifest{}
+	s.Require().NoError(json.Unmarshal(data, manifest))
+	s.Equal("backup", manifest.GetName())
+	s.EqualValues(100, manifest.GetCollectionID())
+	s.Require().Len(manifest.GetSegments(), 1)
+	s.EqualValues(1, manifest.GetSegments()[0].GetID())
+	s.Require().Len(manifest.GetSegmentIndexes(), 1)
+	s.EqualValues(2000, manifest.GetSegmentIndexes()[0].GetBuildID())
+	s.Require().Len(manifest.GetFiles(), 2)
+	s.Equal(logPath, manifest.GetFiles()[0].GetPath())
+	// the files are not read if the storage tells the etags
+	s.NotEmpty(manifest.GetFiles()[0].GetEtag())
+	s.Empty(manifest.GetFiles()[0].GetChecksum())
+	s.Equal(indexPath, manifest.GetFiles()[1].GetPath())
+	s.NotEmpty(manifest.GetFiles()[1].GetEtag())
+
+	// read for the checksums otherwise
+	manifest, err = buildBackupManifest(context.TODO(), struct{ storage.ChunkManager }{cm}, s.server.meta,
+		[]*SegmentInfo{s.server.meta.GetSegment(1)})
+	s.Require().NoError(err)
+	s.Require().Len(manifest.GetFiles(), 2)
+	s.EqualValues(len("binlog"), manifest.GetFiles()[0].GetSize())
+	checksum := md5.Sum([]byte("binlog"))
+	s.Equal(hex.EncodeToString(checksum[:]), manifest.GetFiles()[0].GetChecksum())
+	s.Empty(manifest.GetFiles()[0].GetEtag())
+
+	// backup names are unique
+	resp, err = s.server.Backup(context.TODO(), &datapb.BackupRequest{CollectionID: 100, Name: "backup"})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrParameterInvalid)
+}
+
+func (s *BackupServiceSuite) TestMissingFile() {
+	s.addSegment(1, commonpb.SegmentState_Flushed, false)
+
+	resp, err := s.server.Backup(context.TODO(), &datapb.BackupRequest{CollectionID: 100, Name: "backup"})
+	s.NoError(err)
+	s.False(merr.Ok(resp.GetStatus()))
+	s.False(s.server.garbageCollector.isFrozen(1))
+
+	exist, err := s.server.meta.chunkManager.Exist(context.TODO(),
+		getBackupManifestPath(s.server.meta.chunkManager.RootPath(), "backup"))
+	s.NoError(err)
+	s.False(exist)
+}
+
+func TestBackupService(t *testing.T) {
+	suite.Run(t, new(BackupServiceSuite))
+}
+
+type RestoreServiceSuite struct {
+	suite.Suite
+
+	server *Server
+}
+
+func (s *RestoreServiceSuite) SetupTest() {
+	s.server = newTestServer(s.T(), nil)
+}
+
+func (s *RestoreServiceSuite) TearDownTest() {
+	if s.server != nil {
+		s.server.meta.chunkManager.RemoveWithPrefix(context.TODO(), s.server.meta.chunkManager.RootPath())
+		closeTestServer(s.T(), s.server)
+	}
+}
+
+// backup writes a backup of a flushed segment of collection 100 and returns the manifest path.
+func (s *RestoreServiceSuite) backup() string {
+	cm := s.server.meta.chunkManager
+	s.Require().NoError(s.server.meta.AddSegment(context.TODO(), NewSegmentInfo(&datapb.SegmentInfo{
+		ID:            1,
+		CollectionID:  100,
+		PartitionID:   10,
+		InsertChannel: "ch_100v0",
+		NumOfRows:     10,
+		State:         commonpb.SegmentState_Flushed,
+		Binlogs: []*datapb.FieldBinlog{
+			{FieldID: 1, Binlogs: []*datapb.Binlog{{EntriesNum: 10, LogID: 11, LogPath: metautil.BuildInsertLogPath(cm.RootPath(), 100, 10, 1, 1, 11)}}},
+		},
+		Deltalogs: []*datapb.FieldBinlog{
+			{Binlogs: []*datapb.Binlog{{EntriesNum: 1, LogID: 12, LogPath: metautil.BuildDeltaLogPath(cm.RootPath(), 100, 10, 1, 12)}}},
+		},
+	})))
+	s.Require().NoError(cm.Write(context.TODO(), metautil.BuildInsertLogPath(cm.RootPath(), 100, 10, 1, 1, 11), []byte("insert")))
+	s.Require().NoError(cm.Write(context.TODO(), metautil.BuildDeltaLogPath(cm.RootPath(), 100, 10, 1, 12), []byte("delta")))
+
+	resp, err := s.server.Backup(context.TODO(), &datapb.BackupRequest{CollectionID: 100, Name: "backup"})
+	s.Require().NoError(merr.CheckRPCCall(resp, err))
+	return resp.GetManifestPath()
+}
+
+// watchTarget watches the channel of the target collection 200.
+func (s *RestoreServiceSuite) watchTarget() {
+	s.server.channelManager.AddNode(0)
+	s.Require().NoError(s.server.channelManager.Watch(context.TODO(), &channelMeta{Name: "ch_200v0", CollectionID: 200}))
+	s.Require().NoError(s.server.meta.UpdateChannelCheckpoint("ch_200v0", &msgpb.MsgPosition{ChannelName: "ch_200v0", MsgID: []byte{1}, Timestamp: 100}))
+}
+
+func (s *RestoreServiceSuite) TestClosedServer() {
+	closeTestServer(s.T(), s.server)
+	resp, err := s.server.Restore(context.TODO(), &datapb.RestoreRequest{})
+	s.NoError(err)
+	s.False(merr.Ok(resp.GetStatus()))
+	s.server = nil
+}
+
+func (s *RestoreServiceSuite) TestManifestNotFound() {
+	resp, err := s.server.Restore(context.TODO(), &datapb.RestoreRequest{ManifestPath: "not_exist", CollectionID: 200})
+	s.NoError(err)
+	s.False(merr.Ok(resp.GetStatus()))
+}
+
+func (s *RestoreServiceSuite) TestTargetNotWatched() {
+	manifestPath := s.backup()
+	resp, err := s.server.Restore(context.TODO(), &datapb.RestoreRequest{
+		ManifestPath:     manifestPath,
+		CollectionID:     200,
+		PartitionMapping: map[int64]int64{10: 20},
+	})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrCollectionNotFound)
+}
+
+func (s *RestoreServiceSuite) TestPartitionNotMapped() {
+	manifestPath := s.backup()
+	s.watchTarget()
+	resp, err := s.server.Restore(context.TODO(), &datapb.RestoreRequest{
+		ManifestPath: manifestPath,
+		CollectionID: 200,
+	})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrParameterInvalid)
+}
+
+func (s *RestoreServiceSuite) TestChecksumMismatch() {
+	manifestPath := s.backup()
+	s.watchTarget()
+	cm := s.server.meta.chunkManager
+	s.Require().NoError(cm.Write(context.TODO(), metautil.BuildInsertLogPath(cm.RootPath(), 100, 10, 1, 1, 11), []byte("corrupted")))
+
+	resp, err := s.server.Restore(context.TODO(), &datapb.RestoreRequest{
+		ManifestPath:     manifestPath,
+		CollectionID:     200,
+		PartitionMapping: map[int64]int64{10: 20},
+	})
+	s.NoError(err)
+	s.False(merr.Ok(resp.GetStatus()))
+	s.Empty(s.server.meta.GetSegmentsOfCollection(200))
+}
+
+func (s *RestoreServiceSuite) TestRestore() {
+	manifestPath := s.backup()
+	s.watchTarget()
+	cm := s.server.meta.chunkManager
+
+	resp, err := s.server.Restore(context.TODO(), &datapb.RestoreRequest{
+		ManifestPath:     manifestPath,
+		CollectionID:     200,
+		PartitionMapping: map[int64]int64{10: 20},
+	})
+	s.NoError(err)
+	s.Require().True(merr.Ok(resp.GetStatus()))
+	s.Require().Len(resp.GetSegmentIDs(), 1)
+
+	segment := s.server.meta.GetSegment(resp.GetSegmentIDs()[0])
+	s.Require().NotNil(segment)
+	s.NotEqualValues(1, segment.GetID())
+	s.EqualValues(200, segment.GetCollectionID())
+	s.EqualValues(20, segment.GetPartitionID())
+	s.Equal("ch_200v0", segment.GetInsertChannel())
+	s.Equal(commonpb.SegmentState_Flushed, segment.GetState())
+	s.EqualValues(10, segment.GetNumOfRows())
+	s.EqualValues(100, segment.GetDmlPosition().GetTimestamp())
+
+	insertPath := metautil.BuildInsertLogPath(cm.RootPath(), 200, 20, segment.GetID(), 1, 11)
+	deltaPath := metautil.BuildDeltaLogPath(cm.RootPath(), 200, 20, segment.GetID(), 12)
+	for p, expected := range map[string]string{insertPath: "insert", deltaPath: "delta"} {
+		data, err := cm.Read(context.TODO(), p)
+		s.Require().NoError(err)
+		s.Equal(expected, string(data))
+	}
+
+	// the backup is left untouched
+	exist, err := cm.Exist(context.TODO(), metautil.BuildInsertLogPath(cm.RootPath(), 100, 10, 1, 1, 11))
+	s.NoError(err)
+	s.True(exist)
+}
+
+func TestRestoreService(t *testing.T) {
+	suite.Run(t, new(RestoreServiceSuite))
+}
+
+type PublishServiceSuite struct {
+	suite.Suite
+
+	server *Server
+}
+
+func (s *PublishServiceSuite) SetupTest() {
+	paramtable.Get().Save(Params.LocalStorageCfg.Path.Key, s.T().TempDir())
+	s.server = newTestServer(s.T(), nil)
+	s.server.meta.AddCollection(&collectionInfo{
+		ID: 200,
+		Schema: &schemapb.CollectionSchema{
+			Fields: []*schemapb.FieldSchema{
+				{FieldID: common.RowIDField, Name: common.RowIDFieldName, DataType: schemapb.DataType_Int64},
+				{FieldID: common.TimeStampField, Name: common.TimeStampFieldName, DataType: schemapb.DataType_Int64},
+				{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+				{
+					FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector,
+					TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}},
+				},
+			},
+		},
+		Partitions: []int64{20},
+	})
+	s.server.channelManager.AddNode(0)
+	s.Require().NoError(s.server.channelManager.Watch(context.TODO(), &channelMeta{Name: "ch_200v0", CollectionID: 200}))
+	s.Require().NoError(s.server.meta.UpdateChannelCheckpoint("ch_200v0", &msgpb.MsgPosition{ChannelName: "ch_200v0", MsgID: []byte{1}, Timestamp: 100}))
+}
+
+func (s *PublishServiceSuite) TearDownTest() {
+	if s.server != nil {
+		s.server.meta.chunkManager.RemoveWithPrefix(context.TODO(), s.server.meta.chunkManager.RootPath())
+		closeTestServer(s.T(), s.server)
+	}
+	paramtable.Get().Reset(Params.LocalStorageCfg.Path.Key)
+}
+
+// bulkPath returns the path of the bulk directory under the storage root path.
+func (s *PublishServiceSuite) bulkPath(elems ...string) string {
+	return path.Join(append([]string{s.server.meta.chunkManager.RootPath(), "bulk"}, elems...)...)
+}
+
+// publishedSegment writes the binlogs of a segment with 10 rows under the bulk directory.
+func (s *PublishServiceSuite) publishedSegment(name string) *datapb.PublishedSegment {
+	cm := s.server.meta.chunkManager
+	segment := &datapb.PublishedSegment{NumOfRows: 10}
+	for _, fieldID := range []int64{common.RowIDField, common.TimeStampField, 100, 101} {
+		p := s.bulkPath(name, fmt.Sprint(fieldID), "insert.binlog")
+		s.Require().NoError(cm.Write(context.TODO(), p, []byte(p)))
+		segment.Binlogs = append(segment.Binlogs, &datapb.FieldBinlog{
+			FieldID: fieldID,
+			Binlogs: []*datapb.Binlog{{EntriesNum: 10, LogPath: p, LogSize: int64(len(p))}},
+		})
+	}
+	p := s.bulkPath(name, "100", "stats.binlog")
+	s.Require().NoError(cm.Write(context.TODO(), p, []byte(p)))
+	segment.Statslogs = []*datapb.FieldBinlog{{FieldID: 100, Binlogs: []*datapb.Binlog{{EntriesNum: 10, LogPath: p}}}}
+	return segment
+}
+
+func (s *PublishServiceSuite) publish(manifest *datapb.PublishManifest) *datapb.PublishSegmentsResponse {
+	data, err := json.Marshal(manifest)
+	s.Require().NoError(err)
+	manifestPath := s.bulkPath("manifest.json")
+	s.Require().NoError(s.server.meta.chunkManager.Write(context.TODO(), manifestPath, data))
+	resp, err := s.server.PublishSegments(context.TODO(), &datapb.PublishSegmentsRequest{ManifestPath: manifestPath})
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *PublishServiceSuite) TestClosedServer() {
+	closeTestServer(s.T(), s.server)
+	resp, err := s.server.PublishSegments(context.TODO(), &datapb.PublishSegmentsRequest{})
+	s.NoError(err)
+	s.False(merr.Ok(resp.GetStatus()))
+	s.server = nil
+}
+
+func (s *PublishServiceSuite) TestManifestNotFound() {
+	resp, err := s.server.PublishSegments(context.TODO(), &datapb.PublishSegmentsRequest{ManifestPath: "not_exist"})
+	s.NoError(err)
+	s.False(merr.Ok(resp.GetStatus()))
+}
+
+func (s *PublishServiceSuite) TestPartitionNotFound() {
+	resp := s.publish(&datapb.PublishManifest{
+		CollectionID: 200,
+		PartitionID:  21,
+		Segments:     []*datapb.PublishedSegment{s.publishedSegment("a")},
+	})
+	s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrPartitionNotFound)
+}
+
+func (s *PublishServiceSuite) TestInvalidSegment() {
+	missingField := s.publishedSegment("a")
+	missingField.Binlogs = missingField.Binlogs[1:]
+	rowsMismatch := s.publishedSegment("b")
+	rowsMismatch.NumOfRows = 11
+	unknownField := s.publishedSegment("c")
+	unknownField.Binlogs = append(unknownField.Binlogs, &datapb.FieldBinlog{
+		FieldID: 102,
+		Binlogs: []*datapb.Binlog{{EntriesNum: 10, LogPath: s.bulkPath("c", "102", "insert.binlog")}},
+	})
+	shardNotWatched := s.publishedSegment("d")
+	shardNotWatched.ShardIndex = 1
+
+	for _, segment := range []*datapb.PublishedSegment{missingField, rowsMismatch, unknownField, shardNotWatched} {
+		resp := s.publish(&datapb.PublishManifest{
+			CollectionID: 200,
+			PartitionID:  20,
+			Segments:     []*datapb.PublishedSegment{s.publishedSegment("e"), segment},
+		})
+		s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrParameterInvalid)
+	}
+	s.Empty(s.server.meta.GetSegmentsOfCollection(200))
+}
+
+func (s *PublishServiceSuite) TestChecksumMismatch() {
+	resp := s.publish(&datapb.PublishManifest{
+		CollectionID: 200,
+		PartitionID:  20,
+		Segments:     []*datapb.PublishedSegment{s.publishedSegment("a"), s.publishedSegment("b")},
+		Files:        []*datapb.BackupFile{{Path: s.bulkPath("b", "100", "insert.binlog"), Checksum: "mismatch"}},
+	})
+	s.False(merr.Ok(resp.GetStatus()))
+	s.Empty(s.server.meta.GetSegmentsOfCollection(200))
+}
+
+func (s *PublishServiceSuite) TestPublish() {
+	checksum := md5.Sum([]byte(s.bulkPath("a", "100", "insert.binlog")))
+	resp := s.publish(&datapb.PublishManifest{
+		CollectionID: 200,
+		PartitionID:  20,
+		Segments:     []*datapb.PublishedSegment{s.publishedSegment("a"), s.publishedSegment("b")},
+		Files:        []*datapb.BackupFile{{Path: s.bulkPath("a", "100", "insert.binlog"), Checksum: hex.EncodeToString(checksum[:])}},
+	})
+	s.Require().True(merr.Ok(resp.GetStatus()))
+	s.Require().Len(resp.GetSegmentIDs(), 2)
+
+	cm := s.server.meta.chunkManager
+	for _, segmentID := range resp.GetSegmentIDs() {
+		segment := s.server.meta.GetSegment(segmentID)
+		s.Require().NotNil(segment)
+		s.EqualValues(200, segment.GetCollectionID())
+		s.EqualValues(20, segment.GetPartitionID())
+		s.Equal("ch_200v0", segment.GetInsertChannel())
+		s.Equal(commonpb.SegmentState_Flushed, segment.GetState())
+		s.EqualValues(10, segment.GetNumOfRows())
+		s.Positive(segment.GetMaxRowNum())
+		s.EqualValues(100, segment.GetDmlPosition().GetTimestamp())
+		s.Len(segment.GetBinlogs(), 4)
+		s.Len(segment.GetStatslogs(), 1)
+
+		for _, fieldBinlog := range segment.GetBinlogs() {
+			l := fieldBinlog.GetBinlogs()[0]
+			s.Equal(metautil.BuildInsertLogPath(cm.RootPath(), 200, 20, segmentID, fieldBinlog.GetFieldID(), l.GetLogID()), l.GetLogPath())
+			data, err := cm.Read(context.TODO(), l.GetLogPath())
+			s.Require().NoError(err)
+			s.Contains(string(data), fmt.Sprintf("/%d/insert.binlog", fieldBinlog.GetFieldID()))
+		}
+	}
+}
+
+func (s *PublishServiceSuite) TestImportSegmentBinlogs() {
+	s.Require().NoError(s.server.channelManager.Watch(context.TODO(), &channelMeta{Name: "ch_200v1", CollectionID: 200}))
+	s.Require().NoError(s.server.meta.UpdateChannelCheckpoint("ch_200v1", &msgpb.MsgPosition{ChannelName: "ch_200v1", MsgID: []byte{1}, Timestamp: 100}))
+	s.Require().NoError(s.server.meta.AddSegment(context.TODO(), NewSegmentInfo(&datapb.SegmentInfo{
+		ID:            1,
+		CollectionID:  200,
+		PartitionID:   20,
+		InsertChannel: "ch_200v0",
+		NumOfRows:     15,
+		State:         commonpb.SegmentState_Flushed,
+	})))
+
+	segments := lo.Map([]string{"a", "b", "c"}, func(name string, _ int) *datapb.PublishedSegment {
+		segment := s.publishedSegment(name)
+		segment.ShardIndex = autoShardIndex
+		return segment
+	})
+	data := []byte(s.bulkPath("a", "100", "insert.binlog"))
+	segments[0].Binlogs[2].Binlogs[0].Checksum = storage.BinlogChecksum(data)
+	resp, err := s.server.ImportSegmentBinlogs(context.TODO(), &datapb.ImportSegmentBinlogsRequest{
+		CollectionID: 200,
+		PartitionID:  20,
+		Segments:     segments,
+	})
+	s.Require().NoError(err)
+	s.Require().True(merr.Ok(resp.GetStatus()))
+	s.Require().Len(resp.GetSegmentIDs(), 3)
+	// the segments are assigned to the channel of the fewest rows one by one
+	s.Equal([]string{"ch_200v1", "ch_200v1", "ch_200v0"}, resp.GetChannels())
+	for i, segmentID := range resp.GetSegmentIDs() {
+		segment := s.server.meta.GetSegment(segmentID)
+		s.Require().NotNil(segment)
+		s.Equal(resp.GetChannels()[i], segment.GetInsertChannel())
+		s.Equal(commonpb.SegmentState_Flushed, segment.GetState())
+	}
+	// the checksum is kept along with the copied binlog
+	s.Equal(storage.BinlogChecksum(data), s.server.meta.GetSegment(resp.GetSegmentIDs()[0]).GetBinlogs()[2].GetBinlogs()[0].GetChecksum())
+}
+
+func (s *PublishServiceSuite) TestImportSegmentBinlogsChecksumMismatch() {
+	segment := s.publishedSegment("a")
+	segment.Binlogs[2].Binlogs[0].Checksum = storage.BinlogChecksum([]byte("mismatch"))
+	resp, err := s.server.ImportSegmentBinlogs(context.TODO(), &datapb.ImportSegmentBinlogsRequest{
+		CollectionID: 200,
+		PartitionID:  20,
+		Segments:     []*datapb.PublishedSegment{s.publishedSegment("b"), segment},
+	})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrIoChecksumMismatch)
+	s.Empty(s.server.meta.GetSegmentsOfCollection(200))
+
+	resp, err = s.server.ImportSegmentBinlogs(context.TODO(), &datapb.ImportSegmentBinlogsRequest{
+		CollectionID: 200,
+		PartitionID:  20,
+	})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrParameterInvalid)
+}
+
+func TestPublishService(t *testing.T) {
+	suite.Run(t, new(PublishServiceSuite))
+}
+
+type RefreshStatsServiceSuite struct {
+	suite.Suite
+
+	server     *Server
+	compaction *MockCompactionPlanContext
+}
+
+func (s *RefreshStatsServiceSuite) SetupTest() {
+	s.server = newTestServer(s.T(), nil)
+	s.compaction = NewMockCompactionPlanContext(s.T())
+	s.server.compactionHandler = s.compaction
+
+	segments := []*datapb.SegmentInfo{
+		{ID: 1, CollectionID: 100, PartitionID: 10, InsertChannel: "ch1", State: commonpb.SegmentState_Flushed, NumOfRows: 10, Level: datapb.SegmentLevel_L1},
+		{ID: 2, CollectionID: 100, PartitionID: 10, InsertChannel: "ch2", State: commonpb.SegmentState_Flushed, NumOfRows: 20, Level: datapb.SegmentLevel_L1},
+		{ID: 3, CollectionID: 100, PartitionID: 10, InsertChannel: "ch1", State: commonpb.SegmentState_Growing},
+		{ID: 4, CollectionID: 100, PartitionID: 10, InsertChannel: "ch1", State: commonpb.SegmentState_Flushed, Level: datapb.SegmentLevel_L0},
+	}
+	for _, segment := range segments {
+		s.Require().NoError(s.server.meta.AddSegment(context.TODO(), NewSegmentInfo(segment)))
+	}
+}
+
+func (s *RefreshStatsServiceSuite) TearDownTest() {
+	if s.server != nil {
+		closeTestServer(s.T(), s.server)
+	}
+}
+
+func (s *RefreshStatsServiceSuite) TestClosedServer() {
+	closeTestServer(s.T(), s.server)
+	resp, err := s.server.RefreshSegmentStats(context.TODO(), &datapb.RefreshSegmentStatsRequest{SegmentIDs: []int64{1}})
+	s.NoError(err)
+	s.False(merr.Ok(resp.GetStatus()))
+	s.server = nil
+}
+
+func (s *RefreshStatsServiceSuite) TestRefresh() {
+	plans := make([]*datapb.CompactionPlan, 0)
+	s.compaction.EXPECT().execCompactionPlan(mock.Anything, mock.Anything).RunAndReturn(func(signal *compactionSignal, plan *datapb.CompactionPlan) error {
+		s.True(signal.isForce)
+		s.Equal(plan.GetSegmentBinlogs()[0].GetSegmentID(), signal.segmentID)
+		plans = append(plans, plan)
+		return nil
+	}).Twice()
+
+	resp, err := s.server.RefreshSegmentStats(context.TODO(), &datapb.RefreshSegmentStatsRequest{SegmentIDs: []int64{1, 2, 1}})
+	s.NoError(err)
+	s.True(merr.Ok(resp.GetStatus()))
+	s.NotZero(resp.GetCompactionID())
+	s.Require().Len(plans, 2)
+	s.Equal(lo.Map(plans, func(plan *datapb.CompactionPlan, _ int) int64 { return plan.GetPlanID() }), resp.GetPlanIDs())
+	for i, plan := range plans {
+		s.Equal(datapb.CompactionType_StatsRefreshCompaction, plan.GetType())
+		s.Equal(fmt.Sprintf("ch%d", i+1), plan.GetChannel())
+		s.EqualValues((i+1)*10, plan.GetTotalRows())
+	}
+}
+
+func (s *RefreshStatsServiceSuite) TestInvalidSegments() {
+	for _, segmentIDs := range [][]int64{nil, {1, 3}, {4}, {5}} {
+		resp, err := s.server.RefreshSegmentStats(context.TODO(), &datapb.RefreshSegmentStatsRequest{SegmentIDs: segmentIDs})
+		s.NoError(err)
+		s.False(merr.Ok(resp.GetStatus()))
+	}
+
+	s.server.meta.SetSegmentCompacting(1, true)
+	resp, err := s.server.RefreshSegmentStats(context.TODO(), &datapb.RefreshSegmentStatsRequest{SegmentIDs: []int64{1}})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrServiceUnavailable)
+}
+
+func (s *RefreshStatsServiceSuite) TestSubmitFailed() {
+	s.compaction.EXPECT().execCompactionPlan(mock.Anything, mock.Anything).Return(nil).Once()
+	s.compaction.EXPECT().execCompactionPlan(mock.Anything, mock.Anything).Return(errStatsRefreshNotSupported).Once()
+
+	resp, err := s.server.RefreshSegmentStats(context.TODO(), &datapb.RefreshSegmentStatsRequest{SegmentIDs: []int64{1, 2}})
+	s.NoError(err)
+	s.False(merr.Ok(resp.GetStatus()))
+	s.Len(resp.GetPlanIDs(), 1)
+}
+
+func TestRefreshStatsService(t *testing.T) {
+	suite.Run(t, new(RefreshStatsServiceSuite))
+}
+
+type SegmentSnapshotServiceSuite struct {
+	suite.Suite
+
+	server     *Server
+	checkpoint uint64
+}
+
+func (s *SegmentSnapshotServiceSuite) SetupTest() {
+	s.server = newTestServer(s.T(), nil)
+	s.server.channelManager.AddNode(0)
+	s.Require().NoError(s.server.channelManager.Watch(context.TODO(), &channelMeta{Name: "ch_100v0", CollectionID: 100}))
+	s.checkpoint = tsoutil.ComposeTSByTime(time.Now(), 0)
+	s.Require().NoError(s.server.meta.UpdateChannelCheckpoint("ch_100v0", &msgpb.MsgPosition{ChannelName: "ch_100v0", MsgID: []byte{1}, Timestamp: s.checkpoint}))
+
+	cm := s.server.meta.chunkManager
+	for _, segment := range []*datapb.SegmentInfo{
+		{ID: 1, PartitionID: 10, State: commonpb.SegmentState_Flushed},
+		{ID: 2, PartitionID: 20, State: commonpb.SegmentState_Flushed},
+		{ID: 3, PartitionID: 10, State: commonpb.SegmentState_Dropped, DroppedAt: uint64(time.Now().Add(-time.Second).UnixNano())},
+	} {
+		segment.CollectionID = 100
+		segment.InsertChannel = "ch_100v0"
+		segment.Binlogs = []*datapb.FieldBinlog{
+			{FieldID: 1, Binlogs: []*datapb.Binlog{{EntriesNum: 10, LogID: segment.ID * 10, LogPath: metautil.BuildInsertLogPath(cm.RootPath(), 100, segment.PartitionID, segment.ID, 1, segment.ID*10)}}},
+		}
+		s.Require().NoError(s.server.meta.AddSegment(context.TODO(), NewSegmentInfo(segment)))
+	}
+}
+
+func (s *SegmentSnapshotServiceSuite) TearDownTest() {
+	if s.server != nil {
+		closeTestServer(s.T(), s.server)
+	}
+}
+
+func (s *SegmentSnapshotServiceSuite) TestClosedServer() {
+	closeTestServer(s.T(), s.server)
+	resp, err := s.server.GetSegmentSnapshot(context.TODO(), &datapb.GetSegmentSnapshotRequest{CollectionID: 100})
+	s.NoError(err)
+	s.False(merr.Ok(resp.GetStatus()))
+	s.server = nil
+}
+
+func (s *SegmentSnapshotServiceSuite) TestSnapshot() {
+	resp, err := s.server.GetSegmentSnapshot(context.TODO(), &datapb.GetSegmentSnapshotRequest{CollectionID: 100})
+	s.NoError(merr.CheckRPCCall(resp, err))
+	s.Equal(s.checkpoint, resp.GetTimestamp())
+	s.ElementsMatch([]int64{1, 2}, lo.Map(resp.GetSegments(), func(segment *datapb.SegmentInfo, _ int) int64 { return segment.GetID() }))
+
+	// the segment dropped after the timestamp is visible
+	cm := s.server.meta.chunkManager
+	resp, err = s.server.GetSegmentSnapshot(context.TODO(), &datapb.GetSegmentSnapshotRequest{
+		CollectionID: 100,
+		PartitionIDs: []int64{10},
+		Timestamp:    tsoutil.ComposeTSByTime(time.Now().Add(-time.Minute), 0),
+	})
+	s.NoError(merr.CheckRPCCall(resp, err))
+	s.Require().Len(resp.GetSegments(), 2)
+	for _, segment := range resp.GetSegments() {
+		s.Equal(metautil.BuildInsertLogPath(cm.RootPath(), 100, 10, segment.GetID(), 1, segment.GetID()*10),
+			segment.GetBinlogs()[0].GetBinlogs()[0].GetLogPath())
+	}
+}
+
+func (s *SegmentSnapshotServiceSuite) TestLease() {
+	resp, err := s.server.GetSegmentSnapshot(context.TODO(), &datapb.GetSegmentSnapshotRequest{CollectionID: 100, LeaseSeconds: 1})
+	s.NoError(merr.CheckRPCCall(resp, err))
+	s.True(s.server.garbageCollector.isFrozen(1))
+	s.True(s.server.garbageCollector.isFrozen(2))
+	s.Eventually(func() bool {
+		return !s.server.garbageCollector.isFrozen(1) && !s.server.garbageCollector.isFrozen(2)
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
+func (s *SegmentSnapshotServiceSuite) TestInvalidRequest() {
+	resp, err := s.server.GetSegmentSnapshot(context.TODO(), &datapb.GetSegmentSnapshotRequest{CollectionID: 200})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrCollectionNotFound)
+
+	// not flushed yet
+	resp, err = s.server.GetSegmentSnapshot(context.TODO(), &datapb.GetSegmentSnapshotRequest{CollectionID: 100, Timestamp: s.checkpoint + 1})
+	s.NoError(err)
+	s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrParameterInvalid)
+}
+
+func TestSegmentSnapshotService(t *testing.T) {
+	suite.Run(t, new(SegmentSnapshotServiceSuite))
+}
@@ -32,6 +32,11 @@ var errDisposed = errors.New("client is disposed")
 type NodeInfo struct {
 	NodeID  int64
 	Address string
+	// ProtocolVersion is the protocol version registered in the session of the node,
+	// new RPC fields must be gated by the features it supports.
+	ProtocolVersion int32
+	// Labels are the server labels registered in the session of the node.
+	Labels map[string]string
 }
 
 // Session contains session info of a node
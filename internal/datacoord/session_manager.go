@@ -31,6 +31,7 @@ import (
 	"github.com/milvus-io/milvus/internal/metastore/kv/binlog"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/internal/util/sessionutil"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
@@ -52,10 +53,12 @@ type SessionManager interface {
 	DeleteSession(node *NodeInfo)
 	GetSessionIDs() []int64
 	GetSessions() []*Session
+	SupportFeature(nodeID int64, feature sessionutil.Feature) bool
 
 	Flush(ctx context.Context, nodeID int64, req *datapb.FlushSegmentsRequest)
 	FlushChannels(ctx context.Context, nodeID int64, req *datapb.FlushChannelsRequest) error
 	Compaction(ctx context.Context, nodeID int64, plan *datapb.CompactionPlan) error
+	DropCompactionPlan(ctx context.Context, nodeID int64, req *datapb.DropCompactionPlanRequest) error
 	SyncSegments(nodeID int64, req *datapb.SyncSegmentsRequest) error
 	Import(ctx context.Context, nodeID int64, itr *datapb.ImportTaskRequest)
 	GetCompactionPlansResults() map[int64]*datapb.CompactionPlanResult
@@ -151,6 +154,18 @@ func (c *SessionManagerImpl) GetSessions() []*Session {
 	return ret
 }
 
+// SupportFeature returns whether the DataNode supports @feature, false if the node is not found.
+func (c *SessionManagerImpl) SupportFeature(nodeID int64, feature sessionutil.Feature) bool {
+	c.sessions.RLock()
+	defer c.sessions.RUnlock()
+
+	session, ok := c.sessions.data[nodeID]
+	if !ok {
+		return false
+	}
+	return sessionutil.SupportFeature(session.info.ProtocolVersion, feature)
+}
+
 func (c *SessionManagerImpl) getClient(ctx context.Context, nodeID int64) (types.DataNodeClient, error) {
 	c.sessions.RLock()
 	session, ok := c.sessions.data[nodeID]
@@ -205,6 +220,26 @@ func (c *SessionManagerImpl) Compaction(ctx context.Context, nodeID int64, plan
 	return nil
 }
 
+// DropCompactionPlan is a grpc interface. It will stop the compaction plan in DataNode with provided `nodeID` synchronously.
+func (c *SessionManagerImpl) DropCompactionPlan(ctx context.Context, nodeID int64, req *datapb.DropCompactionPlanRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, Params.DataCoordCfg.CompactionRPCTimeout.GetAsDuration(time.Second))
+	defer cancel()
+	cli, err := c.getClient(ctx, nodeID)
+	if err != nil {
+		log.Warn("failed to get client", zap.Int64("nodeID", nodeID), zap.Error(err))
+		return err
+	}
+
+	resp, err := cli.DropCompactionPlan(ctx, req)
+	if err := VerifyResponse(resp, err); err != nil {
+		log.Warn("failed to drop compaction plan", zap.Int64("node", nodeID), zap.Error(err), zap.Int64("planID", req.GetPlanID()))
+		return err
+	}
+
+	log.Info("success to drop compaction plan", zap.Int64("node", nodeID), zap.Int64("planID", req.GetPlanID()))
+	return nil
+}
+
 // SyncSegments is a grpc interface. It will send request to DataNode with provided `nodeID` synchronously.
 func (c *SessionManagerImpl) SyncSegments(nodeID int64, req *datapb.SyncSegmentsRequest) error {
 	log := log.With(
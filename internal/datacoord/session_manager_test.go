@@ -11,6 +11,7 @@ import (
 	"github.com/milvus-io/milvus/internal/mocks"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/internal/util/sessionutil"
 	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/testutils"
@@ -35,10 +36,19 @@ func (s *SessionManagerSuite) SetupTest() {
 		return s.dn, nil
 	}))
 
-	s.m.AddSession(&NodeInfo{1000, "addr-1"})
+	s.m.AddSession(&NodeInfo{NodeID: 1000, Address: "addr-1", ProtocolVersion: sessionutil.CurrentProtocolVersion})
 	s.MetricsEqual(metrics.DataCoordNumDataNodes, 1)
 }
 
+func (s *SessionManagerSuite) TestSupportFeature() {
+	s.True(s.m.SupportFeature(1000, sessionutil.FeatureLevelZeroCompaction))
+	s.False(s.m.SupportFeature(1000, sessionutil.Feature("unknown")))
+	s.False(s.m.SupportFeature(100, sessionutil.FeatureLevelZeroCompaction))
+
+	s.m.AddSession(&NodeInfo{NodeID: 1001, Address: "addr-2"})
+	s.False(s.m.SupportFeature(1001, sessionutil.FeatureLevelZeroCompaction))
+}
+
 func (s *SessionManagerSuite) TestNotifyChannelOperation() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
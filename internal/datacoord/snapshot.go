@@ -0,0 +1,89 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+// checkSnapshotTs checks the segments visible at @ts are all in meta with their binlogs:
+// - the rows before @ts are flushed, i.e. @ts is not after the channel checkpoint @checkpoint;
+// - the segments dropped after @ts are not recycled, i.e. @ts is within the drop tolerance of GC.
+func checkSnapshotTs(ts Timestamp, checkpoint Timestamp, dropTolerance time.Duration) error {
+	if ts > checkpoint {
+		return merr.WrapErrParameterInvalidMsg("snapshot timestamp %d is after the channel checkpoint %d, flush the collection first", ts, checkpoint)
+	}
+	if time.Since(tsoutil.PhysicalTime(ts)) > dropTolerance {
+		return merr.WrapErrParameterInvalidMsg("snapshot timestamp %d is older than the drop tolerance %s of garbage collection", ts, dropTolerance)
+	}
+	return nil
+}
+
+// selectSnapshotSegments returns the segments visible at @ts from @segments, which are all the segments in meta
+// of a collection including the dropped ones.
+//
+// A segment is visible from its creation until it's dropped. The result segments of a compaction are created when
+// the compaction completes, at the time the source segments are dropped, so either the sources or the results of
+// a compaction are visible at any time, and the compactions in flight are invisible until they complete.
+// The completion time of a compaction is unknown once all of its sources are recycled, the results are visible then
+// if the plan started before @ts, as the deletes applied by the compaction are all before the start time.
+func selectSnapshotSegments(segments []*SegmentInfo, ts Timestamp) ([]*SegmentInfo, error) {
+	physical := uint64(tsoutil.PhysicalTime(ts).UnixNano())
+	segmentMap := make(map[UniqueID]*SegmentInfo, len(segments))
+	for _, segment := range segments {
+		segmentMap[segment.GetID()] = segment
+	}
+
+	visible := make([]*SegmentInfo, 0, len(segments))
+	for _, segment := range segments {
+		switch segment.GetState() {
+		case commonpb.SegmentState_SegmentStateNone, commonpb.SegmentState_NotExist:
+			continue
+		case commonpb.SegmentState_Dropped:
+			if segment.GetDroppedAt() <= physical {
+				continue
+			}
+		}
+		if segment.GetIsImporting() || segment.GetStartPosition().GetTimestamp() > ts ||
+			len(segment.GetBinlogs()) == 0 && len(segment.GetDeltalogs()) == 0 {
+			continue
+		}
+
+		if segment.GetCreatedByCompaction() {
+			var createdAt uint64
+			for _, from := range segment.GetCompactionFrom() {
+				if source, ok := segmentMap[from]; ok && source.GetDroppedAt() > 0 {
+					createdAt = source.GetDroppedAt()
+					break
+				}
+			}
+			if createdAt == 0 && segment.GetLastExpireTime() > ts {
+				return nil, merr.WrapErrParameterInvalidMsg("snapshot at %d is unavailable, the sources of segment %d compacted after it are recycled",
+					ts, segment.GetID())
+			}
+			if createdAt > physical {
+				continue
+			}
+		}
+		visible = append(visible, segment)
+	}
+	return visible, nil
+}
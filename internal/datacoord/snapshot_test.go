@@ -0,0 +1,81 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+func TestCheckSnapshotTs(t *testing.T) {
+	now := tsoutil.ComposeTSByTime(time.Now(), 0)
+	assert.NoError(t, checkSnapshotTs(now, now, time.Hour))
+	assert.ErrorIs(t, checkSnapshotTs(now+1, now, time.Hour), merr.ErrParameterInvalid)
+
+	old := tsoutil.ComposeTSByTime(time.Now().Add(-2*time.Hour), 0)
+	assert.ErrorIs(t, checkSnapshotTs(old, now, time.Hour), merr.ErrParameterInvalid)
+}
+
+func TestSelectSnapshotSegments(t *testing.T) {
+	// t0 < t1 < t2 < t3
+	t0 := time.Now().Add(-time.Hour)
+	t1, t2, t3 := t0.Add(time.Minute), t0.Add(2*time.Minute), t0.Add(3*time.Minute)
+	tsOf := func(t time.Time) Timestamp { return tsoutil.ComposeTSByTime(t, 0) }
+	binlogs := []*datapb.FieldBinlog{{FieldID: 1, Binlogs: []*datapb.Binlog{{LogID: 1}}}}
+	startAt := func(t time.Time) *msgpb.MsgPosition { return &msgpb.MsgPosition{Timestamp: tsOf(t)} }
+
+	segments := lo.Map([]*datapb.SegmentInfo{
+		{ID: 1, State: commonpb.SegmentState_Flushed, StartPosition: startAt(t0), Binlogs: binlogs},
+		// 2, 3 are compacted to 4 at t2, the plan of which started at t1
+		{ID: 2, State: commonpb.SegmentState_Dropped, StartPosition: startAt(t0), Binlogs: binlogs, Compacted: true, DroppedAt: uint64(t2.UnixNano())},
+		{ID: 3, State: commonpb.SegmentState_Dropped, StartPosition: startAt(t0), Binlogs: binlogs, Compacted: true, DroppedAt: uint64(t2.UnixNano())},
+		{ID: 4, State: commonpb.SegmentState_Flushed, StartPosition: startAt(t0), Binlogs: binlogs, CreatedByCompaction: true, CompactionFrom: []int64{2, 3}, LastExpireTime: tsOf(t1)},
+		{ID: 5, State: commonpb.SegmentState_Growing, StartPosition: startAt(t3), Binlogs: binlogs},
+		{ID: 6, State: commonpb.SegmentState_Growing, StartPosition: startAt(t0)},
+		{ID: 7, State: commonpb.SegmentState_Dropped, StartPosition: startAt(t0), Binlogs: binlogs, DroppedAt: uint64(t1.UnixNano())},
+		{ID: 8, State: commonpb.SegmentState_Flushed, StartPosition: startAt(t0), Binlogs: binlogs, IsImporting: true},
+	}, func(info *datapb.SegmentInfo, _ int) *SegmentInfo { return NewSegmentInfo(info) })
+
+	visibleAt := func(segments []*SegmentInfo, at time.Time) []int64 {
+		visible, err := selectSnapshotSegments(segments, tsOf(at))
+		assert.NoError(t, err)
+		return lo.Map(visible, func(segment *SegmentInfo, _ int) int64 { return segment.GetID() })
+	}
+	assert.ElementsMatch(t, []int64{1, 2, 3, 7}, visibleAt(segments, t0.Add(time.Second)))
+	// the compaction in flight is invisible
+	assert.ElementsMatch(t, []int64{1, 2, 3}, visibleAt(segments, t1.Add(time.Second)))
+	assert.ElementsMatch(t, []int64{1, 4}, visibleAt(segments, t2.Add(time.Second)))
+	assert.ElementsMatch(t, []int64{1, 4, 5}, visibleAt(segments, t3.Add(time.Second)))
+
+	// the sources of 9 are recycled, which is visible since the plan started
+	segments = append(segments, NewSegmentInfo(&datapb.SegmentInfo{
+		ID: 9, State: commonpb.SegmentState_Flushed, StartPosition: startAt(t0), Binlogs: binlogs,
+		CreatedByCompaction: true, CompactionFrom: []int64{100}, LastExpireTime: tsOf(t1),
+	}))
+	assert.ElementsMatch(t, []int64{1, 2, 3, 9}, visibleAt(segments, t1.Add(time.Second)))
+	_, err := selectSnapshotSegments(segments, tsOf(t0.Add(time.Second)))
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
@@ -134,6 +134,20 @@ func getCollectionTTL(properties map[string]string) (time.Duration, error) {
 	return Params.CommonCfg.EntityExpirationTTL.GetAsDuration(time.Second), nil
 }
 
+// getPartitionTTL returns ttl if partition's ttl is specified in the collection properties, or return collection's ttl
+func getPartitionTTL(properties map[string]string, partitionID UniqueID) (time.Duration, error) {
+	v, ok := properties[common.PartitionTTLConfigKeyPrefix+strconv.FormatInt(partitionID, 10)]
+	if ok {
+		ttl, err := strconv.Atoi(v)
+		if err != nil {
+			return -1, err
+		}
+		return time.Duration(ttl) * time.Second, nil
+	}
+
+	return getCollectionTTL(properties)
+}
+
 func UpdateCompactionSegmentSizeMetrics(segments []*datapb.CompactionSegment) {
 	var totalSize int64
 	for _, seg := range segments {
@@ -216,13 +230,25 @@ func getFieldBinlogs(id UniqueID, binlogs []*datapb.FieldBinlog) *datapb.FieldBi
 	return nil
 }
 
+// mergeFieldBinlogs appends the new binlogs to the current ones of the same fields. The binlogs whose log ids
+// are recorded already are skipped, so the binlogs saved again by a retried request are not duplicated.
 func mergeFieldBinlogs(currentBinlogs []*datapb.FieldBinlog, newBinlogs []*datapb.FieldBinlog) []*datapb.FieldBinlog {
 	for _, newBinlog := range newBinlogs {
 		fieldBinlogs := getFieldBinlogs(newBinlog.GetFieldID(), currentBinlogs)
 		if fieldBinlogs == nil {
 			currentBinlogs = append(currentBinlogs, newBinlog)
-		} else {
-			fieldBinlogs.Binlogs = append(fieldBinlogs.Binlogs, newBinlog.Binlogs...)
+			continue
+		}
+		recorded := typeutil.NewSet[int64]()
+		for _, binlog := range fieldBinlogs.GetBinlogs() {
+			recorded.Insert(binlog.GetLogID())
+		}
+		for _, binlog := range newBinlog.GetBinlogs() {
+			// the log id is unknown if not set
+			if binlog.GetLogID() != 0 && recorded.Contain(binlog.GetLogID()) {
+				continue
+			}
+			fieldBinlogs.Binlogs = append(fieldBinlogs.Binlogs, binlog)
 		}
 	}
 	return currentBinlogs
@@ -165,6 +165,27 @@ func (suite *UtilSuite) TestGetCollectionTTL() {
 	suite.Equal(ttl, Params.CommonCfg.EntityExpirationTTL.GetAsDuration(time.Second))
 }
 
+func (suite *UtilSuite) TestGetPartitionTTL() {
+	properties := map[string]string{
+		common.CollectionTTLConfigKey:              "3600",
+		common.PartitionTTLConfigKeyPrefix + "100": "60",
+		common.PartitionTTLConfigKeyPrefix + "200": "error value",
+	}
+
+	ttl, err := getPartitionTTL(properties, 100)
+	suite.NoError(err)
+	suite.Equal(time.Minute, ttl)
+
+	// fallback to the collection ttl
+	ttl, err = getPartitionTTL(properties, 101)
+	suite.NoError(err)
+	suite.Equal(time.Hour, ttl)
+
+	ttl, err = getPartitionTTL(properties, 200)
+	suite.Error(err)
+	suite.Equal(int(ttl), -1)
+}
+
 func (suite *UtilSuite) TestGetCollectionAutoCompactionEnabled() {
 	properties := map[string]string{
 		common.CollectionAutoCompactionKey: "true",
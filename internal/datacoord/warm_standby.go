@@ -0,0 +1,359 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus/internal/metastore/kv/datacoord"
+	"github.com/milvus-io/milvus/internal/metastore/model"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util"
+)
+
+// warmStandbyCatchUpTimeout is the max time to wait for the watches to catch up the meta store on activation.
+const warmStandbyCatchUpTimeout = 10 * time.Second
+
+// warmStandbyPrefixes are the meta prefixes written by the active DataCoord, relative to the meta root path.
+var warmStandbyPrefixes = []string{datacoord.MetaPrefix, util.FieldIndexPrefix, util.SegmentIndexPrefix}
+
+type segmentKey struct {
+	collectionID UniqueID
+	partitionID  UniqueID
+}
+
+// warmStandby keeps the meta of a standby DataCoord up to date by watching the meta store incrementally,
+// so the standby could be activated without reloading all the meta.
+// The changed keys are only marked as dirty by the watches, and reloaded from the catalog periodically,
+// which keeps the meta consistent with the catalog no matter how the active DataCoord writes the keys.
+//
+// Only DataCoord supports the warm standby, the other coordinators reload their meta on activation.
+// It covers the meta DataCoord loads from the meta store, i.e. the segments, the channel checkpoints and
+// the indexes. The collection infos are a cache of RootCoord and are dropped on activation to be fetched again,
+// the compaction and import tasks are not persisted and are created by the activation as without warm standby.
+type warmStandby struct {
+	meta     *meta
+	etcdCli  *clientv3.Client
+	rootPath string
+
+	mu              sync.Mutex
+	dirtySegments   map[UniqueID]segmentKey
+	channelCPsDirty bool
+	indexesDirty    bool
+	// err is set if the watch is broken, e.g. compacted, the meta needs a full reload then
+	err      error
+	lastSync time.Time
+
+	// revisions are the revisions each watch has received
+	revisions []*atomic.Int64
+	syncMu    sync.Mutex
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+func newWarmStandby(meta *meta, etcdCli *clientv3.Client, rootPath string) *warmStandby {
+	return &warmStandby{
+		meta:          meta,
+		etcdCli:       etcdCli,
+		rootPath:      rootPath,
+		dirtySegments: make(map[UniqueID]segmentKey),
+		lastSync:      time.Now(),
+	}
+}
+
+// getMetaRevision returns the current revision of the meta store.
+func getMetaRevision(ctx context.Context, etcdCli *clientv3.Client, rootPath string) (int64, error) {
+	resp, err := etcdCli.Get(ctx, rootPath, clientv3.WithCountOnly())
+	if err != nil {
+		return 0, err
+	}
+	return resp.Header.Revision, nil
+}
+
+// start watches the changes after the revision, which is the one the meta loaded at.
+func (w *warmStandby) start(ctx context.Context, revision int64) {
+	ctx, w.cancel = context.WithCancel(ctx)
+	for _, prefix := range warmStandbyPrefixes {
+		rev := atomic.NewInt64(revision)
+		w.revisions = append(w.revisions, rev)
+		watchCh := w.etcdCli.Watch(ctx, path.Join(w.rootPath, prefix)+"/", clientv3.WithPrefix(), clientv3.WithRev(revision+1))
+		w.wg.Add(1)
+		go w.watch(ctx, watchCh, rev)
+	}
+
+	w.wg.Add(1)
+	go w.syncLoop(ctx)
+	log.Info("DataCoord warm standby started", zap.Int64("revision", revision))
+}
+
+func (w *warmStandby) watch(ctx context.Context, watchCh clientv3.WatchChan, revision *atomic.Int64) {
+	defer w.wg.Done()
+	for resp := range watchCh {
+		if err := resp.Err(); err != nil {
+			w.setErr(err)
+			return
+		}
+		for _, event := range resp.Events {
+			w.markDirty(strings.TrimPrefix(string(event.Kv.Key), w.rootPath+"/"))
+		}
+		revision.Store(resp.Header.Revision)
+	}
+	if ctx.Err() == nil {
+		w.setErr(errors.New("warm standby watch channel closed"))
+	}
+}
+
+func (w *warmStandby) syncLoop(ctx context.Context) {
+	defer w.wg.Done()
+	ticker := time.NewTicker(Params.DataCoordCfg.WarmStandbySyncInterval.GetAsDuration(time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.sync(); err != nil {
+				log.Warn("DataCoord warm standby failed to sync meta", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (w *warmStandby) markDirty(key string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch {
+	case strings.HasPrefix(key, datacoord.ChannelCheckpointPrefix+"/"):
+		w.channelCPsDirty = true
+	case strings.HasPrefix(key, util.FieldIndexPrefix+"/"), strings.HasPrefix(key, util.SegmentIndexPrefix+"/"):
+		w.indexesDirty = true
+	default:
+		if collectionID, partitionID, segmentID, ok := datacoord.ParseSegmentKey(key); ok {
+			w.dirtySegments[segmentID] = segmentKey{collectionID: collectionID, partitionID: partitionID}
+		}
+	}
+}
+
+func (w *warmStandby) setErr(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil {
+		log.Warn("DataCoord warm standby is broken, the meta will be fully reloaded on activation", zap.Error(err))
+		w.err = err
+	}
+}
+
+func (w *warmStandby) getErr() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// sync reloads the dirty meta from the catalog.
+func (w *warmStandby) sync() error {
+	w.syncMu.Lock()
+	defer w.syncMu.Unlock()
+
+	w.mu.Lock()
+	if w.err != nil {
+		w.mu.Unlock()
+		return w.err
+	}
+	dirtySegments, channelCPsDirty, indexesDirty := w.dirtySegments, w.channelCPsDirty, w.indexesDirty
+	w.dirtySegments, w.channelCPsDirty, w.indexesDirty = make(map[UniqueID]segmentKey), false, false
+	w.mu.Unlock()
+
+	err := w.reload(dirtySegments, channelCPsDirty, indexesDirty)
+	if err != nil {
+		// mark them dirty again to retry in the next round
+		w.mu.Lock()
+		for segmentID, key := range dirtySegments {
+			w.dirtySegments[segmentID] = key
+		}
+		w.channelCPsDirty = w.channelCPsDirty || channelCPsDirty
+		w.indexesDirty = w.indexesDirty || indexesDirty
+		w.mu.Unlock()
+		return err
+	}
+
+	w.mu.Lock()
+	w.lastSync = time.Now()
+	w.mu.Unlock()
+	return nil
+}
+
+func (w *warmStandby) reload(dirtySegments map[UniqueID]segmentKey, channelCPsDirty, indexesDirty bool) error {
+	m := w.meta
+	ctx := m.ctx
+	for segmentID, key := range dirtySegments {
+		segment, err := m.catalog.LoadSegment(ctx, key.collectionID, key.partitionID, segmentID)
+		if err != nil {
+			return err
+		}
+		m.Lock()
+		if segment == nil {
+			m.segments.DropSegment(segmentID)
+		} else {
+			info := NewSegmentInfo(segment)
+			if old := m.segments.GetSegment(segmentID); old != nil {
+				for indexID, segIdx := range old.segmentIndexes {
+					info.segmentIndexes[indexID] = segIdx
+				}
+			}
+			m.segments.SetSegment(segmentID, info)
+		}
+		m.Unlock()
+	}
+
+	if channelCPsDirty {
+		channelCPs, err := m.catalog.ListChannelCheckpoint(ctx)
+		if err != nil {
+			return err
+		}
+		m.channelCPs.Range(func(vChannel string, _ *msgpb.MsgPosition) bool {
+			if _, ok := channelCPs[vChannel]; !ok {
+				m.channelCPs.Remove(vChannel)
+			}
+			return true
+		})
+		for vChannel, pos := range channelCPs {
+			pos.ChannelName = vChannel
+			m.channelCPs.Insert(vChannel, pos)
+		}
+	}
+
+	if indexesDirty {
+		fieldIndexes, err := m.catalog.ListIndexes(ctx)
+		if err != nil {
+			return err
+		}
+		segmentIndexes, err := m.catalog.ListSegmentIndexes(ctx)
+		if err != nil {
+			return err
+		}
+		m.Lock()
+		m.indexes = make(map[UniqueID]map[UniqueID]*model.Index)
+		for _, fieldIndex := range fieldIndexes {
+			m.updateCollectionIndex(fieldIndex)
+		}
+		for _, segment := range m.segments.GetSegments() {
+			segment.segmentIndexes = make(map[UniqueID]*model.SegmentIndex)
+		}
+		m.buildID2SegmentIndex = make(map[UniqueID]*model.SegmentIndex)
+		for _, segIdx := range segmentIndexes {
+			m.updateSegmentIndex(segIdx)
+		}
+		m.Unlock()
+	}
+	return nil
+}
+
+// catchUp waits until the watches have received all the changes before now.
+func (w *warmStandby) catchUp(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, warmStandbyCatchUpTimeout)
+	defer cancel()
+	target, err := getMetaRevision(ctx, w.etcdCli, w.rootPath)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if err := w.getErr(); err != nil {
+			return err
+		}
+		if w.revision() >= target {
+			return nil
+		}
+		// the watches only receive the revisions of their own prefixes unless notified the progress
+		if err := w.etcdCli.RequestProgress(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// revision returns the revision all the watches have received.
+func (w *warmStandby) revision() int64 {
+	var revision int64
+	for i, rev := range w.revisions {
+		if i == 0 || rev.Load() < revision {
+			revision = rev.Load()
+		}
+	}
+	return revision
+}
+
+// activate catches up the meta store and stops watching,
+// the meta is up to date if no error returned, otherwise it needs a full reload.
+func (w *warmStandby) activate(ctx context.Context) error {
+	err := w.catchUp(ctx)
+	w.close()
+	if err != nil {
+		return err
+	}
+	if err := w.sync(); err != nil {
+		return err
+	}
+	w.meta.resetCollections()
+	return nil
+}
+
+func (w *warmStandby) close() {
+	w.closeOnce.Do(func() {
+		if w.cancel != nil {
+			w.cancel()
+		}
+		w.wg.Wait()
+	})
+}
+
+// pendingChanges returns the number of dirty keys not synced yet.
+func (w *warmStandby) pendingChanges() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	pending := len(w.dirtySegments)
+	if w.channelCPsDirty {
+		pending++
+	}
+	if w.indexesDirty {
+		pending++
+	}
+	return pending
+}
+
+func (w *warmStandby) getLastSync() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastSync
+}
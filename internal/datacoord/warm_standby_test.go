@@ -0,0 +1,162 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/suite"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	etcdkv "github.com/milvus-io/milvus/internal/kv/etcd"
+	"github.com/milvus-io/milvus/internal/metastore"
+	"github.com/milvus-io/milvus/internal/metastore/kv/datacoord"
+	"github.com/milvus-io/milvus/internal/metastore/model"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/util/etcd"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type WarmStandbySuite struct {
+	suite.Suite
+
+	etcdCli  *clientv3.Client
+	rootPath string
+	// catalog is the one the active DataCoord writes by
+	catalog metastore.DataCoordCatalog
+	meta    *meta
+}
+
+func (s *WarmStandbySuite) SetupSuite() {
+	paramtable.Init()
+	var err error
+	s.etcdCli, err = etcd.GetEtcdClient(
+		Params.EtcdCfg.UseEmbedEtcd.GetAsBool(),
+		Params.EtcdCfg.EtcdUseSSL.GetAsBool(),
+		Params.EtcdCfg.Endpoints.GetAsStrings(),
+		Params.EtcdCfg.EtcdTLSCert.GetValue(),
+		Params.EtcdCfg.EtcdTLSKey.GetValue(),
+		Params.EtcdCfg.EtcdTLSCACert.GetValue(),
+		Params.EtcdCfg.EtcdTLSMinVersion.GetValue())
+	s.Require().NoError(err)
+}
+
+func (s *WarmStandbySuite) TearDownSuite() {
+	s.etcdCli.Close()
+}
+
+func (s *WarmStandbySuite) SetupTest() {
+	s.rootPath = fmt.Sprintf("/test/datacoord/warm-standby/%d", rand.Int())
+	s.catalog = datacoord.NewCatalog(etcdkv.NewEtcdKV(s.etcdCli, s.rootPath), "", "")
+
+	ctx := context.Background()
+	s.Require().NoError(s.catalog.AddSegment(ctx, &datapb.SegmentInfo{
+		ID: 1, CollectionID: 100, PartitionID: 10, InsertChannel: "ch1", State: commonpb.SegmentState_Flushed, NumOfRows: 10,
+	}))
+	s.Require().NoError(s.catalog.CreateIndex(ctx, &model.Index{CollectionID: 100, FieldID: 101, IndexID: 1000}))
+	s.Require().NoError(s.catalog.CreateSegmentIndex(ctx, &model.SegmentIndex{
+		SegmentID: 1, CollectionID: 100, PartitionID: 10, IndexID: 1000, BuildID: 10000,
+	}))
+
+	var err error
+	s.meta, err = newMeta(ctx, datacoord.NewCatalog(etcdkv.NewEtcdKV(s.etcdCli, s.rootPath), "", ""), nil)
+	s.Require().NoError(err)
+}
+
+func (s *WarmStandbySuite) TearDownTest() {
+	_, err := s.etcdCli.Delete(context.Background(), s.rootPath, clientv3.WithPrefix())
+	s.NoError(err)
+}
+
+func (s *WarmStandbySuite) startWarmStandby() *warmStandby {
+	revision, err := getMetaRevision(context.Background(), s.etcdCli, s.rootPath)
+	s.Require().NoError(err)
+	w := newWarmStandby(s.meta, s.etcdCli, s.rootPath)
+	w.start(context.Background(), revision)
+	return w
+}
+
+func (s *WarmStandbySuite) TestActivate() {
+	w := s.startWarmStandby()
+	defer w.close()
+
+	ctx := context.Background()
+	s.Require().NoError(s.catalog.AddSegment(ctx, &datapb.SegmentInfo{
+		ID: 2, CollectionID: 100, PartitionID: 10, InsertChannel: "ch1", State: commonpb.SegmentState_Growing,
+	}))
+	s.Require().NoError(s.catalog.AlterSegments(ctx, []*datapb.SegmentInfo{{
+		ID: 1, CollectionID: 100, PartitionID: 10, InsertChannel: "ch1", State: commonpb.SegmentState_Dropped, NumOfRows: 10,
+	}}))
+	s.Require().NoError(s.catalog.SaveChannelCheckpoint(ctx, "ch1", &msgpb.MsgPosition{Timestamp: 100}))
+
+	s.Eventually(func() bool {
+		return w.pendingChanges() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	// not applied until synced
+	s.Nil(s.meta.GetSegment(2))
+	s.meta.AddCollection(&collectionInfo{ID: 100})
+
+	s.Require().NoError(w.activate(ctx))
+	s.Equal(commonpb.SegmentState_Growing, s.meta.GetSegment(2).GetState())
+	s.Equal(commonpb.SegmentState_Dropped, s.meta.GetSegment(1).GetState())
+	// the segment index is kept after the segment reloaded
+	s.Len(s.meta.GetSegment(1).segmentIndexes, 1)
+	s.EqualValues(100, s.meta.GetChannelCheckpoint("ch1").GetTimestamp())
+	// the collection infos cached are fetched from RootCoord again
+	s.Nil(s.meta.GetCollection(100))
+	s.Zero(w.pendingChanges())
+	s.NoError(w.getErr())
+}
+
+func (s *WarmStandbySuite) TestSync() {
+	w := s.startWarmStandby()
+	defer w.close()
+
+	ctx := context.Background()
+	s.Require().NoError(s.catalog.CreateIndex(ctx, &model.Index{CollectionID: 100, FieldID: 102, IndexID: 1001}))
+	s.Require().NoError(s.catalog.CreateSegmentIndex(ctx, &model.SegmentIndex{
+		SegmentID: 1, CollectionID: 100, PartitionID: 10, IndexID: 1001, BuildID: 10001,
+	}))
+	s.Require().NoError(s.catalog.DropSegment(ctx, &datapb.SegmentInfo{ID: 1, CollectionID: 100, PartitionID: 10}))
+	s.Eventually(func() bool {
+		return w.pendingChanges() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	s.Require().NoError(w.sync())
+	s.Nil(s.meta.GetSegment(1))
+	s.Len(s.meta.indexes[100], 2)
+	s.Len(s.meta.buildID2SegmentIndex, 2)
+	s.Zero(w.pendingChanges())
+}
+
+func (s *WarmStandbySuite) TestBroken() {
+	w := s.startWarmStandby()
+	w.setErr(errors.New("mock"))
+	s.Error(w.sync())
+	s.Error(w.activate(context.Background()))
+}
+
+func TestWarmStandby(t *testing.T) {
+	suite.Run(t, new(WarmStandbySuite))
+}
@@ -18,9 +18,11 @@ package allocator
 
 import (
 	"context"
+	"time"
 
 	gAllocator "github.com/milvus-io/milvus/internal/allocator"
 	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
@@ -50,7 +52,11 @@ func New(ctx context.Context, rootCoord types.RootCoordClient, peerID UniqueID)
 	if err != nil {
 		return nil, err
 	}
-	return &Impl{idAlloc}, nil
+	return NewLeasedAllocator(&Impl{idAlloc}, func() int64 {
+		return paramtable.Get().DataNodeCfg.IDLeaseSize.GetAsInt64()
+	}, func() time.Duration {
+		return paramtable.Get().DataNodeCfg.IDLeaseTTL.GetAsDuration(time.Second)
+	}), nil
 }
 
 func (a *Impl) GetIDAlloactor() *gAllocator.IDAllocator {
@@ -63,6 +69,11 @@ func (a *Impl) GetGenerator(count int, done <-chan struct{}) (<-chan UniqueID, e
 		return nil, err
 	}
 
+	return newGenerator(idStart, count, done), nil
+}
+
+// newGenerator returns the channel of the count ids from the start, closed once they are all taken or done.
+func newGenerator(idStart UniqueID, count int, done <-chan struct{}) <-chan UniqueID {
 	rt := make(chan UniqueID)
 	go func(rt chan<- UniqueID) {
 		for i := 0; i < count; i++ {
@@ -75,6 +86,5 @@ func (a *Impl) GetGenerator(count int, done <-chan struct{}) (<-chan UniqueID, e
 		}
 		close(rt)
 	}(rt)
-
-	return rt, nil
+	return rt
 }
@@ -18,7 +18,11 @@ package allocator
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -27,9 +31,11 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/rootcoordpb"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
 )
 
 func TestGetGenerator(t *testing.T) {
+	paramtable.Init()
 	tests := []struct {
 		isvalid  bool
 		innumber int
@@ -90,3 +96,112 @@ func (m *RootCoordFactory) AllocID(ctx context.Context, in *rootcoordpb.AllocIDR
 	}
 	return resp, nil
 }
+
+// countingAllocator allocates the ids from 1 in order, and counts the allocations.
+type countingAllocator struct {
+	Allocator
+
+	mu     sync.Mutex
+	nextID UniqueID
+	calls  int
+	err    error
+}
+
+func (a *countingAllocator) Alloc(count uint32) (UniqueID, UniqueID, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.calls++
+	if a.err != nil {
+		return 0, 0, a.err
+	}
+	start := a.nextID + 1
+	a.nextID += int64(count)
+	return start, start + int64(count), nil
+}
+
+func (a *countingAllocator) Calls() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.calls
+}
+
+func (a *countingAllocator) Close() {}
+
+func TestLeasedAllocator(t *testing.T) {
+	leaseSize := func() int64 { return 100 }
+	noTTL := func() time.Duration { return 0 }
+
+	t.Run("leased", func(t *testing.T) {
+		inner := &countingAllocator{}
+		alloc := NewLeasedAllocator(inner, leaseSize, noTTL)
+
+		// handed out of the lease in order, the next lease is renewed once a quarter is left
+		seen := make(map[UniqueID]struct{})
+		for i := 0; i < 11; i++ {
+			start, end, err := alloc.Alloc(7)
+			require.NoError(t, err)
+			assert.EqualValues(t, 7, end-start)
+			for id := start; id < end; id++ {
+				_, ok := seen[id]
+				assert.False(t, ok)
+				seen[id] = struct{}{}
+			}
+		}
+		assert.Eventually(t, func() bool { return inner.Calls() == 2 }, time.Second, time.Millisecond)
+
+		// the rest of the lease short of the count is skipped for the next one
+		start, _, err := alloc.Alloc(40)
+		require.NoError(t, err)
+		assert.EqualValues(t, 101, start)
+		id, err := alloc.AllocOne()
+		require.NoError(t, err)
+		assert.EqualValues(t, 141, id)
+
+		gen, err := alloc.GetGenerator(3, make(chan struct{}))
+		require.NoError(t, err)
+		ids := make([]UniqueID, 0)
+		for id := range gen {
+			ids = append(ids, id)
+		}
+		assert.Equal(t, []UniqueID{142, 143, 144}, ids)
+	})
+
+	t.Run("large or disabled", func(t *testing.T) {
+		inner := &countingAllocator{}
+		alloc := NewLeasedAllocator(inner, leaseSize, noTTL)
+		start, end, err := alloc.Alloc(60)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, start)
+		assert.EqualValues(t, 61, end)
+		assert.Equal(t, 1, inner.Calls())
+
+		alloc = NewLeasedAllocator(inner, func() int64 { return 0 }, noTTL)
+		start, _, err = alloc.Alloc(1)
+		require.NoError(t, err)
+		assert.EqualValues(t, 61, start)
+		assert.Equal(t, 2, inner.Calls())
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		inner := &countingAllocator{}
+		alloc := NewLeasedAllocator(inner, leaseSize, func() time.Duration { return time.Millisecond })
+		start, _, err := alloc.Alloc(1)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, start)
+
+		time.Sleep(10 * time.Millisecond)
+		start, _, err = alloc.Alloc(1)
+		require.NoError(t, err)
+		assert.EqualValues(t, 101, start)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		inner := &countingAllocator{err: errors.New("mock")}
+		alloc := NewLeasedAllocator(inner, leaseSize, noTTL)
+		_, _, err := alloc.Alloc(1)
+		assert.Error(t, err)
+		_, err = alloc.GetGenerator(1, make(chan struct{}))
+		assert.Error(t, err)
+		alloc.Close()
+	})
+}
@@ -0,0 +1,169 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package allocator
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+)
+
+// idLease is a range [start, end) of the ids leased from the allocator.
+type idLease struct {
+	start    UniqueID
+	end      UniqueID
+	leasedAt time.Time
+}
+
+func (l *idLease) remaining() int64 {
+	return l.end - l.start
+}
+
+var _ Allocator = (*leasedAllocator)(nil)
+
+// leasedAllocator hands out the ids of the leases taken from the allocator, so the ids of the flushes
+// are allocated locally without a round trip to the allocator each. The next lease is renewed in the background
+// once the current one runs low, and a request larger than half of the lease goes to the allocator directly.
+//
+// The ids are only handed out within the bounds of the leases granted, and the leases are never persisted,
+// the rest of the leases is dropped on crash or expiration, so the ids are never handed out twice.
+type leasedAllocator struct {
+	Allocator
+
+	leaseSize func() int64
+	leaseTTL  func() time.Duration
+
+	mu      sync.Mutex
+	current idLease
+	next    *idLease
+	// renewing is closed once the renewal in the background is done, nil if no renewal is in flight
+	renewing chan struct{}
+}
+
+// NewLeasedAllocator returns the allocator handing out the ids of the leases of the size taken from the allocator,
+// a lease is used for the ttl at most. The size and ttl are read on each allocation so they could be refreshed,
+// a non-positive size means no lease, and a non-positive ttl means the leases never expire.
+func NewLeasedAllocator(alloc Allocator, leaseSize func() int64, leaseTTL func() time.Duration) Allocator {
+	return &leasedAllocator{
+		Allocator: alloc,
+		leaseSize: leaseSize,
+		leaseTTL:  leaseTTL,
+	}
+}
+
+func (a *leasedAllocator) AllocOne() (UniqueID, error) {
+	start, _, err := a.Alloc(1)
+	if err != nil {
+		return 0, err
+	}
+	return start, nil
+}
+
+func (a *leasedAllocator) Alloc(count uint32) (UniqueID, UniqueID, error) {
+	size := a.leaseSize()
+	if size <= 0 || int64(count) > size/2 {
+		return a.Allocator.Alloc(count)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for {
+		a.dropExpired()
+		if a.current.remaining() >= int64(count) {
+			start := a.current.start
+			a.current.start += int64(count)
+			if a.current.remaining() < size/4 {
+				a.renewAsync(size)
+			}
+			return start, start + int64(count), nil
+		}
+
+		// the rest of the current lease short of the count is skipped
+		if a.next != nil {
+			a.current, a.next = *a.next, nil
+			continue
+		}
+		if renewing := a.renewing; renewing != nil {
+			a.mu.Unlock()
+			<-renewing
+			a.mu.Lock()
+			if a.next != nil {
+				continue
+			}
+		}
+
+		start, end, err := a.Allocator.Alloc(uint32(size))
+		if err != nil {
+			return 0, 0, err
+		}
+		a.current = idLease{start: start, end: end, leasedAt: time.Now()}
+	}
+}
+
+func (a *leasedAllocator) GetGenerator(count int, done <-chan struct{}) (<-chan UniqueID, error) {
+	idStart, _, err := a.Alloc(uint32(count))
+	if err != nil {
+		return nil, err
+	}
+	return newGenerator(idStart, count, done), nil
+}
+
+func (a *leasedAllocator) Close() {
+	a.mu.Lock()
+	a.current, a.next = idLease{}, nil
+	a.mu.Unlock()
+	a.Allocator.Close()
+}
+
+// dropExpired drops the leases used beyond the ttl.
+func (a *leasedAllocator) dropExpired() {
+	ttl := a.leaseTTL()
+	if ttl <= 0 {
+		return
+	}
+	if a.current.remaining() > 0 && time.Since(a.current.leasedAt) > ttl {
+		a.current = idLease{}
+	}
+	if a.next != nil && time.Since(a.next.leasedAt) > ttl {
+		a.next = nil
+	}
+}
+
+// renewAsync takes the next lease in the background if there isn't one, must be called with the lock held.
+func (a *leasedAllocator) renewAsync(size int64) {
+	if a.next != nil || a.renewing != nil {
+		return
+	}
+	renewing := make(chan struct{})
+	a.renewing = renewing
+	go func() {
+		start, end, err := a.Allocator.Alloc(uint32(size))
+
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if err != nil {
+			log.Warn("failed to renew the id lease, leased on the next allocation", zap.Error(err))
+		} else {
+			a.next = &idLease{start: start, end: end, leasedAt: time.Now()}
+		}
+		a.renewing = nil
+		close(renewing)
+	}()
+}
@@ -21,15 +21,18 @@ import (
 	"strconv"
 
 	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
 	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/datanode/allocator"
 	"github.com/milvus-io/milvus/internal/datanode/io"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/metautil"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
@@ -60,32 +63,86 @@ func downloadBlobs(ctx context.Context, b io.BinlogIO, paths []string) ([]*Blob,
 	return resp, nil
 }
 
-// genDeltaBlobs returns key, value
-func genDeltaBlobs(b io.BinlogIO, allocator allocator.Allocator, data *DeleteData, collID, partID, segID UniqueID) (string, []byte, error) {
+// binlogChecksum returns the checksums of the insert and delta logs of the segments recorded in segment meta by the paths.
+func binlogChecksum(segments []*datapb.CompactionSegmentBinlogs) map[string]uint32 {
+	checksum := make(map[string]uint32)
+	for _, s := range segments {
+		for _, fieldBinlogs := range [][]*datapb.FieldBinlog{s.GetFieldBinlogs(), s.GetDeltalogs()} {
+			for _, f := range fieldBinlogs {
+				for _, b := range f.GetBinlogs() {
+					if b.GetChecksum() != 0 {
+						checksum[b.GetLogPath()] = b.GetChecksum()
+					}
+				}
+			}
+		}
+	}
+	return checksum
+}
+
+// verifyBinlogs verifies the downloaded binlogs against the checksums recorded in segment meta by the paths.
+func verifyBinlogs(paths []string, values [][]byte, checksum map[string]uint32) error {
+	for i, path := range paths {
+		expected, ok := checksum[path]
+		if !ok {
+			continue
+		}
+		if err := storage.VerifyBinlogChecksum(path, values[i], expected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyBlobs is verifyBinlogs of the downloaded blobs.
+func verifyBlobs(paths []string, blobs []*Blob, checksum map[string]uint32) error {
+	for i, path := range paths {
+		expected, ok := checksum[path]
+		if !ok {
+			continue
+		}
+		if err := storage.VerifyBinlogChecksum(path, blobs[i].GetValue(), expected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// genDeltaBlobs returns key, value and the number of the deletes serialized, only the latest delete of each pk
+// is serialized if dataNode.segment.deltalogDedup is enabled.
+func genDeltaBlobs(b io.BinlogIO, allocator allocator.Allocator, data *DeleteData, collID, partID, segID UniqueID) (string, []byte, int64, error) {
 	dCodec := storage.NewDeleteCodec()
 
+	if Params.DataNodeCfg.DeltalogDedupEnabled.GetAsBool() {
+		data = data.Dedup()
+	}
 	blob, err := dCodec.Serialize(collID, partID, segID, data)
 	if err != nil {
-		return "", nil, err
+		return "", nil, 0, err
 	}
 
 	idx, err := allocator.AllocOne()
 	if err != nil {
-		return "", nil, err
+		return "", nil, 0, err
 	}
 	k := metautil.JoinIDPath(collID, partID, segID, idx)
 	key := b.JoinFullPath(common.SegmentDeltaLogPath, k)
 
-	return key, blob.GetValue(), nil
+	return key, blob.GetValue(), int64(len(data.Pks)), nil
 }
 
 // genInsertBlobs returns insert-paths and save blob to kvs
 func genInsertBlobs(b io.BinlogIO, allocator allocator.Allocator, data *InsertData, collectionID, partID, segID UniqueID, iCodec *storage.InsertCodec, kvs map[string][]byte) (map[UniqueID]*datapb.FieldBinlog, error) {
-	inlogs, err := iCodec.Serialize(partID, segID, data)
+	record, err := storage.NewSortedInsertRecord(context.TODO(), iCodec.Schema.GetSchema(), data)
 	if err != nil {
 		return nil, err
 	}
+	defer record.Release()
 
+	inlogs, err := iCodec.SerializeRecord(partID, segID, record)
+	if err != nil {
+		return nil, err
+	}
 	inpaths := make(map[UniqueID]*datapb.FieldBinlog)
 	notifyGenIdx := make(chan struct{})
 	defer close(notifyGenIdx)
@@ -106,13 +163,54 @@ func genInsertBlobs(b io.BinlogIO, allocator allocator.Allocator, data *InsertDa
 		kvs[key] = value
 		inpaths[fID] = &datapb.FieldBinlog{
 			FieldID: fID,
-			Binlogs: []*datapb.Binlog{{LogSize: int64(fileLen), LogPath: key, EntriesNum: blob.RowNum}},
+			Binlogs: []*datapb.Binlog{{LogSize: int64(fileLen), LogPath: key, EntriesNum: blob.RowNum, Checksum: storage.BinlogChecksum(value), ZoneMap: blob.ZoneMap}},
 		}
 	}
 
 	return inpaths, nil
 }
 
+// verifyInsertData verifies the insert data against the schema on a dry-run upload, the data must be
+// of all the fields of the schema by the data types of the fields, with the same number of rows.
+func verifyInsertData(schema *schemapb.CollectionSchema, data *InsertData) error {
+	rowNum := data.GetRowNum()
+	fields := make(map[UniqueID]struct{}, len(schema.GetFields()))
+	for _, field := range schema.GetFields() {
+		fields[field.GetFieldID()] = struct{}{}
+		fieldData, ok := data.Data[field.GetFieldID()]
+		if !ok {
+			return merr.WrapErrParameterInvalidMsg("field %d of the schema not found in the insert data", field.GetFieldID())
+		}
+		// the string data built without the schema are of no data type, which are not verified
+		if dataType := fieldData.GetDataType(); dataType != schemapb.DataType_None && dataType != field.GetDataType() {
+			return merr.WrapErrParameterInvalidMsg("field %d is of %s in the insert data, expected %s",
+				field.GetFieldID(), fieldData.GetDataType(), field.GetDataType())
+		}
+		if fieldData.RowNum() != rowNum {
+			return merr.WrapErrParameterInvalidMsg("field %d has %d rows, expected %d", field.GetFieldID(), fieldData.RowNum(), rowNum)
+		}
+	}
+	for fieldID := range data.Data {
+		if _, ok := fields[fieldID]; !ok {
+			return merr.WrapErrParameterInvalidMsg("field %d of the insert data not found in the schema", fieldID)
+		}
+	}
+	return nil
+}
+
+// verifyInsertBinlogs verifies the insert binlogs serialized on a dry-run upload are all of the rows of the insert data.
+func verifyInsertBinlogs(inpaths map[UniqueID]*datapb.FieldBinlog, rowNum int64) error {
+	for fieldID, fieldBinlog := range inpaths {
+		for _, binlog := range fieldBinlog.GetBinlogs() {
+			if binlog.GetEntriesNum() != rowNum {
+				return merr.WrapErrParameterInvalidMsg("binlog %s of field %d has %d rows, expected %d",
+					binlog.GetLogPath(), fieldID, binlog.GetEntriesNum(), rowNum)
+			}
+		}
+	}
+	return nil
+}
+
 // genStatBlobs return stats log paths and save blob to kvs
 func genStatBlobs(b io.BinlogIO, allocator allocator.Allocator, stats *storage.PrimaryKeyStats, collectionID, partID, segID UniqueID, iCodec *storage.InsertCodec, kvs map[string][]byte, totRows int64) (map[UniqueID]*datapb.FieldBinlog, error) {
 	statBlob, err := iCodec.SerializePkStats(stats, totRows)
@@ -135,11 +233,54 @@ func genStatBlobs(b io.BinlogIO, allocator allocator.Allocator, stats *storage.P
 
 	statPaths[fID] = &datapb.FieldBinlog{
 		FieldID: fID,
-		Binlogs: []*datapb.Binlog{{LogSize: int64(fileLen), LogPath: key, EntriesNum: totRows}},
+		Binlogs: []*datapb.Binlog{{LogSize: int64(fileLen), LogPath: key, EntriesNum: totRows, Checksum: storage.BinlogChecksum(value)}},
 	}
 	return statPaths, nil
 }
 
+// genMergedStatBlobs merges the pk stats of the batches into the compound statslog and save blob to kvs
+func genMergedStatBlobs(b io.BinlogIO, stats []*storage.PrimaryKeyStats, collectionID, partID, segID UniqueID, iCodec *storage.InsertCodec, kvs map[string][]byte, totRows int64) (map[UniqueID]*datapb.FieldBinlog, error) {
+	statBlob, err := iCodec.SerializePkStatsList(stats, totRows)
+	if err != nil {
+		return nil, err
+	}
+
+	fID, _ := strconv.ParseInt(statBlob.GetKey(), 10, 64)
+	k := metautil.JoinIDPath(collectionID, partID, segID, fID, int64(storage.CompoundStatsType))
+	key := b.JoinFullPath(common.SegmentStatslogPath, k)
+	value := statBlob.GetValue()
+
+	kvs[key] = value
+
+	return map[UniqueID]*datapb.FieldBinlog{
+		fID: {
+			FieldID: fID,
+			Binlogs: []*datapb.Binlog{{LogSize: int64(len(value)), LogPath: key, EntriesNum: totRows, Checksum: storage.BinlogChecksum(value)}},
+		},
+	}, nil
+}
+
+// genInventoryBlob lists the binlogs of the log types in the inventory of the segment and saves it to kvs,
+// if dataNode.upload.inventoryEnabled. No inventory is written by a dry run.
+func genInventoryBlob(ctx context.Context, b io.BinlogIO, collectionID, partID, segID UniqueID, kvs map[string][]byte, logs map[string][]*datapb.FieldBinlog) error {
+	if !Params.DataNodeCfg.UploadInventory.GetAsBool() || io.DryRunOf(ctx) != nil {
+		return nil
+	}
+	inv := storage.NewSegmentInventory(collectionID, partID, segID)
+	for logType, fieldBinlogs := range logs {
+		inv.AddFieldBinlogs(logType, fieldBinlogs...)
+	}
+	if len(inv.Binlogs) == 0 {
+		return nil
+	}
+	value, err := inv.Marshal()
+	if err != nil {
+		return err
+	}
+	kvs[b.JoinFullPath(common.SegmentInventoryPath, metautil.JoinIDPath(collectionID, partID, segID), inv.Name())] = value
+	return nil
+}
+
 // update stats log
 // also update with insert data if not nil
 func uploadStatsLog(
@@ -161,6 +302,12 @@ func uploadStatsLog(
 	if err != nil {
 		return nil, err
 	}
+	err = genInventoryBlob(ctx, b, collectionID, partID, segID, kvs, map[string][]*datapb.FieldBinlog{
+		common.SegmentStatslogPath: lo.Values(statPaths),
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	err = b.Upload(ctx, kvs)
 	if err != nil {
@@ -170,6 +317,64 @@ func uploadStatsLog(
 	return statPaths, nil
 }
 
+// uploadMergedStatsLog uploads the compound statslog merged from the pk stats of the batches,
+// so the loaders read a single statslog rather than the ones of all the batches.
+func uploadMergedStatsLog(
+	ctx context.Context,
+	b io.BinlogIO,
+	collectionID UniqueID,
+	partID UniqueID,
+	segID UniqueID,
+	stats []*storage.PrimaryKeyStats,
+	totRows int64,
+	iCodec *storage.InsertCodec,
+) (map[UniqueID]*datapb.FieldBinlog, error) {
+	ctx, span := otel.Tracer(typeutil.DataNodeRole).Start(ctx, "UploadMergedStatslog")
+	defer span.End()
+	kvs := make(map[string][]byte)
+
+	statPaths, err := genMergedStatBlobs(b, stats, collectionID, partID, segID, iCodec, kvs, totRows)
+	if err != nil {
+		return nil, err
+	}
+	err = genInventoryBlob(ctx, b, collectionID, partID, segID, kvs, map[string][]*datapb.FieldBinlog{
+		common.SegmentStatslogPath: lo.Values(statPaths),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.Upload(ctx, kvs)
+	if err != nil {
+		return nil, err
+	}
+
+	return statPaths, nil
+}
+
+// removeBatchStatslogs removes the pk statslogs of the batches merged into the compound statslog, which are not
+// recorded in the meta, so the ones failed to remove are left to the garbage collection.
+func removeBatchStatslogs(ctx context.Context, b io.BinlogIO, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	if err := b.MultiRemove(ctx, paths); err != nil {
+		log.Ctx(ctx).Warn("failed to remove the statslogs of the batches, left to the garbage collection",
+			zap.Strings("paths", paths), zap.Error(err))
+	}
+}
+
+// logPaths returns the paths of the binlogs of the fields.
+func logPaths(fieldBinlogs map[UniqueID]*datapb.FieldBinlog) []string {
+	var paths []string
+	for _, fieldBinlog := range fieldBinlogs {
+		for _, binlog := range fieldBinlog.GetBinlogs() {
+			paths = append(paths, binlog.GetLogPath())
+		}
+	}
+	return paths
+}
+
 func uploadInsertLog(
 	ctx context.Context,
 	b io.BinlogIO,
@@ -192,10 +397,28 @@ func uploadInsertLog(
 		return nil, nil
 	}
 
+	dryRun := io.DryRunOf(ctx) != nil
+	if dryRun {
+		if err := verifyInsertData(iCodec.Schema.GetSchema(), iData); err != nil {
+			return nil, err
+		}
+	}
+
 	inpaths, err := genInsertBlobs(b, allocator, iData, collectionID, partID, segID, iCodec, kvs)
 	if err != nil {
 		return nil, err
 	}
+	if dryRun {
+		if err := verifyInsertBinlogs(inpaths, int64(iData.GetRowNum())); err != nil {
+			return nil, err
+		}
+	}
+	err = genInventoryBlob(ctx, b, collectionID, partID, segID, kvs, map[string][]*datapb.FieldBinlog{
+		common.SegmentInsertLogPath: lo.Values(inpaths),
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	err = b.Upload(ctx, kvs)
 	if err != nil {
@@ -205,6 +428,85 @@ func uploadInsertLog(
 	return inpaths, nil
 }
 
+// uploadInsertLogWithStats uploads the insert data along with the pk statslog of the batch,
+// the pk stats of the batch are returned to be merged into the compound statslog.
+func uploadInsertLogWithStats(
+	ctx context.Context,
+	b io.BinlogIO,
+	allocator allocator.Allocator,
+	collectionID UniqueID,
+	partID UniqueID,
+	segID UniqueID,
+	iData *InsertData,
+	iCodec *storage.InsertCodec,
+) (map[UniqueID]*datapb.FieldBinlog, map[UniqueID]*datapb.FieldBinlog, *storage.PrimaryKeyStats, error) {
+	ctx, span := otel.Tracer(typeutil.DataNodeRole).Start(ctx, "UploadInsertLogWithStats")
+	defer span.End()
+	kvs := make(map[string][]byte)
+
+	pkField, err := typeutil.GetPrimaryFieldSchema(iCodec.Schema.GetSchema())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	pkData, ok := iData.Data[pkField.GetFieldID()]
+	if !ok || pkData.RowNum() == 0 {
+		log.Warn("binlog io uploading insert data without pks",
+			zap.Int64("segmentID", segID),
+			zap.Int64("collectionID", iCodec.Schema.GetID()),
+		)
+		return nil, nil, nil, merr.WrapErrParameterInvalidMsg("no pk in the insert data of segment %d", segID)
+	}
+
+	dryRun := io.DryRunOf(ctx) != nil
+	if dryRun {
+		if err := verifyInsertData(iCodec.Schema.GetSchema(), iData); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	inpaths, err := genInsertBlobs(b, allocator, iData, collectionID, partID, segID, iCodec, kvs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	rowNum := int64(pkData.RowNum())
+	if dryRun {
+		if err := verifyInsertBinlogs(inpaths, rowNum); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	stats, err := storage.NewPrimaryKeyStats(pkField.GetFieldID(), int64(pkField.GetDataType()), rowNum)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	stats.UpdateByMsgs(pkData)
+	if Params.DataNodeCfg.FieldStatsEnabled.GetAsBool() {
+		fieldStats, err := storage.NewFieldStatsByData(iCodec.Schema.GetSchema(), iData, Params.DataNodeCfg.HLLPrecision.GetAsInt())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		stats.SetFieldStats(fieldStats)
+	}
+	statPaths, err := genStatBlobs(b, allocator, stats, collectionID, partID, segID, iCodec, kvs, rowNum)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = genInventoryBlob(ctx, b, collectionID, partID, segID, kvs, map[string][]*datapb.FieldBinlog{
+		common.SegmentInsertLogPath: lo.Values(inpaths),
+		common.SegmentStatslogPath:  lo.Values(statPaths),
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	err = b.Upload(ctx, kvs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return inpaths, statPaths, stats, nil
+}
+
 func uploadDeltaLog(
 	ctx context.Context,
 	b io.BinlogIO,
@@ -212,6 +514,7 @@ func uploadDeltaLog(
 	collectionID UniqueID,
 	partID UniqueID,
 	segID UniqueID,
+	pkFieldID UniqueID,
 	dData *DeleteData,
 ) ([]*datapb.FieldBinlog, error) {
 	ctx, span := otel.Tracer(typeutil.DataNodeRole).Start(ctx, "UploadDeltaLog")
@@ -222,7 +525,7 @@ func uploadDeltaLog(
 	)
 
 	if dData.RowCount > 0 {
-		k, v, err := genDeltaBlobs(b, allocator, dData, collectionID, partID, segID)
+		k, v, rowCount, err := genDeltaBlobs(b, allocator, dData, collectionID, partID, segID)
 		if err != nil {
 			log.Warn("generate delta blobs wrong",
 				zap.Int64("collectionID", collectionID),
@@ -233,18 +536,25 @@ func uploadDeltaLog(
 
 		kvs[k] = v
 		deltaInfo = append(deltaInfo, &datapb.FieldBinlog{
-			FieldID: 0, // TODO: Not useful on deltalogs, FieldID shall be ID of primary key field
+			FieldID: pkFieldID,
 			Binlogs: []*datapb.Binlog{{
-				EntriesNum: dData.RowCount,
+				EntriesNum: rowCount,
 				LogPath:    k,
 				LogSize:    int64(len(v)),
+				Checksum:   storage.BinlogChecksum(v),
 			}},
 		})
 	} else {
 		return nil, nil
 	}
 
-	err := b.Upload(ctx, kvs)
+	err := genInventoryBlob(ctx, b, collectionID, partID, segID, kvs, map[string][]*datapb.FieldBinlog{
+		common.SegmentDeltaLogPath: deltaInfo,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = b.Upload(ctx, kvs)
 	if err != nil {
 		return nil, err
 	}
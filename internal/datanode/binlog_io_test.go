@@ -24,17 +24,21 @@ import (
 	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/datanode/allocator"
 	"github.com/milvus-io/milvus/internal/datanode/io"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
 )
 
 var binlogTestDir = "/tmp/milvus_test/test_binlog_io"
@@ -159,6 +163,107 @@ func TestBinlogIOInterfaceMethods(t *testing.T) {
 	})
 }
 
+func TestUploadInsertLogWithStats(t *testing.T) {
+	ctx := context.Background()
+	cm := storage.NewLocalChunkManager(storage.RootPath(binlogTestDir))
+	defer cm.RemoveWithPrefix(ctx, cm.RootPath())
+
+	f := &MetaFactory{}
+	meta := f.GetCollectionMeta(UniqueID(10001), "test_upload_with_stats", schemapb.DataType_Int64)
+	iCodec := storage.NewInsertCodecWithSchema(meta)
+	binlogIO := io.NewBinlogIO(cm, getOrCreateIOPool())
+
+	alloc := allocator.NewMockAllocator(t)
+	alloc.EXPECT().GetGenerator(mock.Anything, mock.Anything).Call.Return(validGeneratorFn, nil)
+	alloc.EXPECT().AllocOne().Return(1000, nil)
+
+	var batchStats []*storage.PrimaryKeyStats
+	for i := 0; i < 2; i++ {
+		inPaths, statPaths, stats, err := uploadInsertLogWithStats(ctx, binlogIO, alloc, meta.GetID(), 10, 1, genInsertData(2), iCodec)
+		require.NoError(t, err)
+		assert.NotEmpty(t, inPaths)
+		assert.Equal(t, 1, len(statPaths))
+		assert.EqualValues(t, stats.FieldID, statPaths[stats.FieldID].GetFieldID())
+		assert.EqualValues(t, 2, statPaths[stats.FieldID].GetBinlogs()[0].GetEntriesNum())
+		batchStats = append(batchStats, stats)
+	}
+
+	statPaths, err := uploadMergedStatsLog(ctx, binlogIO, meta.GetID(), 10, 1, batchStats, 4, iCodec)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(statPaths))
+	for _, fieldBinlog := range statPaths {
+		require.Equal(t, 1, len(fieldBinlog.GetBinlogs()))
+		binlog := fieldBinlog.GetBinlogs()[0]
+		assert.Equal(t, storage.CompoundStatsType.LogIdx(), path.Base(binlog.GetLogPath()))
+
+		value, err := cm.Read(ctx, binlog.GetLogPath())
+		require.NoError(t, err)
+		merged, err := storage.DeserializeStatsList(&storage.Blob{Value: value})
+		require.NoError(t, err)
+		assert.Equal(t, 2, len(merged))
+	}
+
+	_, err = uploadMergedStatsLog(ctx, binlogIO, meta.GetID(), 10, 1, nil, 0, iCodec)
+	assert.Error(t, err)
+}
+
+func TestUploadSegmentInventory(t *testing.T) {
+	ctx := context.Background()
+	cm := storage.NewLocalChunkManager(storage.RootPath(binlogTestDir))
+	defer cm.RemoveWithPrefix(ctx, cm.RootPath())
+	paramtable.Get().Save(Params.DataNodeCfg.UploadInventory.Key, "true")
+	defer paramtable.Get().Reset(Params.DataNodeCfg.UploadInventory.Key)
+
+	f := &MetaFactory{}
+	meta := f.GetCollectionMeta(UniqueID(10001), "test_upload_inventory", schemapb.DataType_Int64)
+	iCodec := storage.NewInsertCodecWithSchema(meta)
+	binlogIO := io.NewBinlogIO(cm, getOrCreateIOPool())
+
+	alloc := allocator.NewMockAllocator(t)
+	alloc.EXPECT().GetGenerator(mock.Anything, mock.Anything).Call.Return(validGeneratorFn, nil)
+	// the log ids are unique, so is the inventory of each upload
+	var nextID atomic.Int64
+	nextID.Store(1000)
+	alloc.EXPECT().AllocOne().RunAndReturn(func() (UniqueID, error) {
+		return nextID.Inc(), nil
+	})
+
+	inPaths, statPaths, _, err := uploadInsertLogWithStats(ctx, binlogIO, alloc, meta.GetID(), 10, 1, genInsertData(2), iCodec)
+	require.NoError(t, err)
+	dData := storage.NewDeleteData([]storage.PrimaryKey{storage.NewInt64PrimaryKey(1)}, []uint64{1})
+	deltaPaths, err := uploadDeltaLog(ctx, binlogIO, alloc, meta.GetID(), 10, 1, 106, dData)
+	require.NoError(t, err)
+
+	expected := make(map[string]*datapb.Binlog)
+	for _, fieldBinlogs := range [][]*datapb.FieldBinlog{lo.Values(inPaths), lo.Values(statPaths), deltaPaths} {
+		for _, fieldBinlog := range fieldBinlogs {
+			for _, binlog := range fieldBinlog.GetBinlogs() {
+				expected[binlog.GetLogPath()] = binlog
+			}
+		}
+	}
+
+	inv, err := storage.ReadSegmentInventory(ctx, cm, cm.RootPath(), meta.GetID(), 10, 1)
+	require.NoError(t, err)
+	require.NotNil(t, inv)
+	assert.EqualValues(t, 1, inv.SegmentID)
+	assert.Equal(t, len(expected), len(inv.Binlogs))
+	for _, binlog := range inv.Binlogs {
+		e, ok := expected[binlog.Path]
+		require.True(t, ok, binlog.Path)
+		assert.Equal(t, e.GetLogSize(), binlog.Size)
+		assert.Equal(t, e.GetChecksum(), binlog.Checksum)
+		assert.Equal(t, e.GetEntriesNum(), binlog.RowCount)
+	}
+
+	// no inventory is written by a dry run
+	_, err = uploadInsertLog(io.WithDryRun(ctx, io.NewDryRunReport()), binlogIO, alloc, meta.GetID(), 10, 2, genInsertData(2), iCodec)
+	require.NoError(t, err)
+	inv, err = storage.ReadSegmentInventory(ctx, cm, cm.RootPath(), meta.GetID(), 10, 2)
+	require.NoError(t, err)
+	assert.Nil(t, inv)
+}
+
 func prepareBlob(cm storage.ChunkManager, key string) ([]byte, string, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -199,7 +304,7 @@ func TestBinlogIOInnerMethods(t *testing.T) {
 		for _, test := range tests {
 			t.Run(test.description, func(t *testing.T) {
 				if test.isvalid {
-					k, v, err := genDeltaBlobs(binlogIO, alloc, &DeleteData{
+					k, v, _, err := genDeltaBlobs(binlogIO, alloc, &DeleteData{
 						Pks: []storage.PrimaryKey{test.deletepk},
 						Tss: []uint64{test.ts},
 					}, meta.GetID(), 10, 1)
@@ -214,13 +319,35 @@ func TestBinlogIOInnerMethods(t *testing.T) {
 		}
 	})
 
+	t.Run("Test genDeltaBlobs dedup", func(t *testing.T) {
+		alloc := allocator.NewMockAllocator(t)
+		alloc.EXPECT().AllocOne().Call.Return(int64(11112), nil)
+		binlogIO := io.NewBinlogIO(cm, getOrCreateIOPool())
+
+		pks := []storage.PrimaryKey{storage.NewInt64PrimaryKey(1), storage.NewInt64PrimaryKey(2), storage.NewInt64PrimaryKey(1)}
+		dData := storage.NewDeleteData(pks, []uint64{100, 101, 102})
+		_, v, rowCount, err := genDeltaBlobs(binlogIO, alloc, dData, 1, 1, 1)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, rowCount)
+		_, _, deleted, err := storage.NewDeleteCodec().Deserialize([]*Blob{{Value: v}})
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, deleted.RowCount)
+		assert.ElementsMatch(t, []uint64{101, 102}, deleted.Tss)
+
+		paramtable.Get().Save(Params.DataNodeCfg.DeltalogDedupEnabled.Key, "false")
+		defer paramtable.Get().Reset(Params.DataNodeCfg.DeltalogDedupEnabled.Key)
+		_, _, rowCount, err = genDeltaBlobs(binlogIO, alloc, dData, 1, 1, 1)
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, rowCount)
+	})
+
 	t.Run("Test genDeltaBlobs error", func(t *testing.T) {
 		pk := storage.NewInt64PrimaryKey(1)
 
 		t.Run("Test serialize error", func(t *testing.T) {
 			alloc := allocator.NewMockAllocator(t)
 			binlogIO := io.NewBinlogIO(cm, getOrCreateIOPool())
-			k, v, err := genDeltaBlobs(binlogIO, alloc, &DeleteData{Pks: []storage.PrimaryKey{pk}, Tss: []uint64{}}, 1, 1, 1)
+			k, v, _, err := genDeltaBlobs(binlogIO, alloc, &DeleteData{Pks: []storage.PrimaryKey{pk}, Tss: []uint64{}}, 1, 1, 1)
 			assert.Error(t, err)
 			assert.Empty(t, k)
 			assert.Empty(t, v)
@@ -230,7 +357,7 @@ func TestBinlogIOInnerMethods(t *testing.T) {
 			alloc := allocator.NewMockAllocator(t)
 			alloc.EXPECT().AllocOne().Call.Return(int64(0), fmt.Errorf("mock AllocOne error"))
 			binlogIO := io.NewBinlogIO(cm, getOrCreateIOPool())
-			k, v, err := genDeltaBlobs(binlogIO, alloc, &DeleteData{Pks: []storage.PrimaryKey{pk}, Tss: []uint64{1}}, 1, 1, 1)
+			k, v, _, err := genDeltaBlobs(binlogIO, alloc, &DeleteData{Pks: []storage.PrimaryKey{pk}, Tss: []uint64{1}}, 1, 1, 1)
 			assert.Error(t, err)
 			assert.Empty(t, k)
 			assert.Empty(t, v)
@@ -0,0 +1,202 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/etcdpb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// clusteringRow is a row to cluster along with the value of its clustering field.
+type clusteringRow struct {
+	key   interface{}
+	value *storage.Value
+}
+
+// cluster merges the rows of the segments like merge, sorts them by the value of the clustering field,
+// and writes them into the result segments of at most MaxSegmentRows rows each, the first of which is @targetSegID.
+// The ranges of the clustering field of the result segments, recorded by the zone maps of their insert binlogs,
+// overlap by the boundary values at most, so the segments out of the range of a filter on the field are pruned.
+// All the rows are sorted in memory, the plan is limited by dataCoord.compaction.clustering.maxInputSize.
+func (t *compactionTask) cluster(
+	ctx context.Context,
+	unMergedInsertlogs [][][]string,
+	targetSegID UniqueID,
+	partID UniqueID,
+	meta *etcdpb.CollectionMeta,
+	delta *storage.SortedDeleteData,
+	deleted []*storage.DeleteBitmap,
+) ([]*datapb.CompactionSegment, error) {
+	ctx, span := otel.Tracer(typeutil.DataNodeRole).Start(ctx, fmt.Sprintf("CompactCluster-%d", t.getPlanID()))
+	defer span.End()
+	log := log.With(zap.Int64("planID", t.getPlanID()), zap.Int64("clusteringField", t.plan.GetClusteringFieldID()))
+	clusterStart := time.Now()
+
+	clusteringField, ok := lo.Find(meta.GetSchema().GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetFieldID() == t.plan.GetClusteringFieldID()
+	})
+	if !ok {
+		log.Warn("clustering field not found in schema")
+		return nil, merr.WrapErrFieldNotFound(t.plan.GetClusteringFieldID(), "clustering field not found in schema")
+	}
+	fieldID := clusteringField.GetFieldID()
+
+	rows := make([]clusteringRow, 0, t.plan.GetTotalRows())
+	oldRowNums, expired, downloadTimeCost, _, err := t.iterate(ctx, unMergedInsertlogs, meta, delta, deleted, false, func(v *storage.Value) error {
+		row, ok := v.Value.(map[UniqueID]interface{})
+		if !ok {
+			log.Warn("transfer interface to map wrong")
+			return errors.New("unexpected error")
+		}
+		rows = append(rows, clusteringRow{key: row[fieldID], value: v})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortStart := time.Now()
+	sort.SliceStable(rows, func(i, j int) bool {
+		return compareClusteringKey(rows[i].key, rows[j].key) < 0
+	})
+	sortTimeCost := time.Since(sortStart)
+
+	maxRows := int(t.plan.GetMaxSegmentRows())
+	if maxRows <= 0 {
+		maxRows = lo.Max([]int{len(rows), 1})
+	}
+
+	var (
+		segments       []*datapb.CompactionSegment
+		numBinlogs     int
+		uploadTimeCost time.Duration
+	)
+	// an empty segment is still returned if all the rows are deleted or expired, as the merge compaction does
+	for start := 0; start < len(rows) || len(segments) == 0; start += maxRows {
+		end := lo.Min([]int{start + maxRows, len(rows)})
+		segmentID := targetSegID
+		if len(segments) > 0 {
+			segmentID, err = t.AllocOne()
+			if err != nil {
+				log.Warn("failed to allocate segmentID", zap.Error(err))
+				return nil, err
+			}
+		}
+
+		writer, err := newSegmentWriter(t, segmentID, partID, meta)
+		if err != nil {
+			return nil, err
+		}
+		for i := start; i < end; i++ {
+			if err := writer.write(ctx, rows[i].value); err != nil {
+				return nil, err
+			}
+			// the rows are copied into the write buffer, release them as soon as possible
+			rows[i].value = nil
+		}
+		insertPaths, statPaths, err := writer.finish(ctx)
+		if err != nil {
+			return nil, err
+		}
+		numBinlogs += writer.numBinlogs
+		uploadTimeCost += writer.uploadTimeCost
+
+		segments = append(segments, &datapb.CompactionSegment{
+			SegmentID:           segmentID,
+			InsertLogs:          insertPaths,
+			Field2StatslogPaths: statPaths,
+			NumOfRows:           writer.numRows,
+			Channel:             t.plan.GetChannel(),
+		})
+		if end > start {
+			log.Info("clustered segment written", zap.Int64("segmentID", segmentID), zap.Int64("numRows", writer.numRows),
+				zap.Any("minKey", rows[start].key), zap.Any("maxKey", rows[end-1].key))
+		}
+	}
+
+	log.Info("compact cluster end",
+		zap.Int64("original numRows", oldRowNums),
+		zap.Int("remaining insert numRows", len(rows)),
+		zap.Int64("expired entities", expired),
+		zap.Int("result segment number", len(segments)),
+		zap.Int("binlog file number", numBinlogs),
+		zap.Duration("download insert log elapse", downloadTimeCost),
+		zap.Duration("sort elapse", sortTimeCost),
+		zap.Duration("upload insert log elapse", uploadTimeCost),
+		zap.Duration("cluster elapse", time.Since(clusterStart)))
+
+	return segments, nil
+}
+
+// compareClusteringKey compares the values of the clustering field, nil is less than any value,
+// and the values of the types not comparable are taken as equal.
+func compareClusteringKey(a, b interface{}) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+	switch x := a.(type) {
+	case int8:
+		return compareOrdered(x, b.(int8))
+	case int16:
+		return compareOrdered(x, b.(int16))
+	case int32:
+		return compareOrdered(x, b.(int32))
+	case int64:
+		return compareOrdered(x, b.(int64))
+	case float32:
+		return compareOrdered(x, b.(float32))
+	case float64:
+		return compareOrdered(x, b.(float64))
+	case string:
+		return compareOrdered(x, b.(string))
+	default:
+		return 0
+	}
+}
+
+func compareOrdered[T int8 | int16 | int32 | int64 | float32 | float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -121,6 +121,12 @@ func (c *compactionExecutor) stopTask(planID UniqueID) {
 	}
 }
 
+// dropTask stops the task and discards its result if completed, the plan could be executed again.
+func (c *compactionExecutor) dropTask(planID UniqueID) {
+	c.stopTask(planID)
+	c.injectDone(planID)
+}
+
 func (c *compactionExecutor) isValidChannel(channel string) bool {
 	// if vchannel marked dropped, compaction should not proceed
 	return !c.dropped.Contain(channel)
@@ -171,7 +177,9 @@ func (c *compactionExecutor) getAllCompactionResults() []*datapb.CompactionPlanR
 		completed = append(completed, planID)
 		results = append(results, result)
 
-		if result.GetType() == datapb.CompactionType_Level0DeleteCompaction {
+		// the results of stats refresh are not synced by SyncSegments either
+		if result.GetType() == datapb.CompactionType_Level0DeleteCompaction ||
+			result.GetType() == datapb.CompactionType_StatsRefreshCompaction {
 			completedLevelZero = append(completedLevelZero, planID)
 		}
 		return true
@@ -42,6 +42,31 @@ func TestCompactionExecutor(t *testing.T) {
 		ex.stopTask(UniqueID(1))
 	})
 
+	t.Run("Test dropTask", func(t *testing.T) {
+		ex := newCompactionExecutor()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go ex.start(ctx)
+		mc := newMockCompactor(true)
+		mc.alwaysWorking = true
+		ex.execute(mc)
+
+		// wait for task enqueued
+		found := false
+		for !found {
+			found = ex.executing.Contain(mc.getPlanID())
+		}
+		ex.dropTask(mc.getPlanID())
+		assert.False(t, ex.executing.Contain(mc.getPlanID()))
+
+		// the completed result is discarded
+		ex.completed.Insert(2, &datapb.CompactionPlanResult{PlanID: 2})
+		ex.completedCompactor.Insert(2, newMockCompactor(true))
+		ex.dropTask(2)
+		assert.False(t, ex.completed.Contain(2))
+		assert.False(t, ex.completedCompactor.Contain(2))
+	})
+
 	t.Run("Test start", func(t *testing.T) {
 		ex := newCompactionExecutor()
 		ctx, cancel := context.WithCancel(context.TODO())
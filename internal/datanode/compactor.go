@@ -19,6 +19,7 @@ package datanode
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/datanode/allocator"
 	"github.com/milvus-io/milvus/internal/datanode/io"
+	"github.com/milvus-io/milvus/internal/datanode/membudget"
 	"github.com/milvus-io/milvus/internal/datanode/metacache"
 	"github.com/milvus-io/milvus/internal/datanode/syncmgr"
 	"github.com/milvus-io/milvus/internal/metastore/kv/binlog"
@@ -69,7 +71,7 @@ type compactor interface {
 // make sure compactionTask implements compactor interface
 var _ compactor = (*compactionTask)(nil)
 
-// for MixCompaction only
+// for MixCompaction and ClusteringCompaction
 type compactionTask struct {
 	binlogIO io.BinlogIO
 	compactor
@@ -142,112 +144,271 @@ func (t *compactionTask) getNumRows() (int64, error) {
 	return numRows, nil
 }
 
-func (t *compactionTask) mergeDeltalogs(dBlobs map[UniqueID][]*Blob) (map[interface{}]Timestamp, error) {
+// mergeDeltalogs merges the deltalogs of the segments into the deletes sorted by the primary keys,
+// by which the latest delete of a primary key is looked up by binary search.
+func (t *compactionTask) mergeDeltalogs(dBlobs map[UniqueID][]*Blob) (*storage.SortedDeleteData, error) {
 	log := log.With(zap.Int64("planID", t.getPlanID()))
 	mergeStart := time.Now()
 	dCodec := storage.NewDeleteCodec()
 
-	pk2ts := make(map[interface{}]Timestamp)
-
+	merged := &storage.DeleteData{}
 	for _, blobs := range dBlobs {
 		_, _, dData, err := dCodec.Deserialize(blobs)
 		if err != nil {
 			log.Warn("merge deltalogs wrong", zap.Error(err))
 			return nil, err
 		}
-
-		for i := int64(0); i < dData.RowCount; i++ {
-			pk := dData.Pks[i]
-			ts := dData.Tss[i]
-			if lastTS, ok := pk2ts[pk.GetValue()]; ok && lastTS > ts {
-				ts = lastTS
-			}
-			pk2ts[pk.GetValue()] = ts
-		}
+		merged.AppendBatch(dData.Pks, dData.Tss)
 	}
+	delta := storage.NewSortedDeleteData(merged)
 
 	log.Info("mergeDeltalogs end",
-		zap.Int("number of deleted pks to compact in insert logs", len(pk2ts)),
+		zap.Int("number of deletes to compact in insert logs", delta.Len()),
 		zap.Duration("elapse", time.Since(mergeStart)))
 
-	return pk2ts, nil
+	return delta, nil
 }
 
+// uploadRemainLog uploads the remaining insert rows along with the pk statslog of the batch,
+// and the compound statslog merged from the pk stats of all the batches. The pk statslogs of the batches,
+// batchStatslogs and the one of the remaining rows, are removed once merged, so only the compound statslog
+// is returned as the statslog of the segment.
 func (t *compactionTask) uploadRemainLog(
 	ctxTimeout context.Context,
 	targetSegID UniqueID,
 	partID UniqueID,
 	meta *etcdpb.CollectionMeta,
-	stats *storage.PrimaryKeyStats,
+	batchStats []*storage.PrimaryKeyStats,
+	batchStatslogs []string,
 	totRows int64,
 	writeBuffer *storage.InsertData,
 ) (map[UniqueID]*datapb.FieldBinlog, map[UniqueID]*datapb.FieldBinlog, error) {
 	iCodec := storage.NewInsertCodecWithSchema(meta)
+	iCodec.BinlogFormat = t.metaCache.BinlogFormat()
 	inPaths := make(map[int64]*datapb.FieldBinlog, 0)
-	var err error
 	if !writeBuffer.IsEmpty() {
-		inPaths, err = uploadInsertLog(ctxTimeout, t.binlogIO, t.Allocator, meta.GetID(), partID, targetSegID, writeBuffer, iCodec)
+		var (
+			statPaths map[UniqueID]*datapb.FieldBinlog
+			stats     *storage.PrimaryKeyStats
+			err       error
+		)
+		inPaths, statPaths, stats, err = uploadInsertLogWithStats(ctxTimeout, t.binlogIO, t.Allocator, meta.GetID(), partID, targetSegID, writeBuffer, iCodec)
 		if err != nil {
 			return nil, nil, err
 		}
+		batchStats = append(batchStats, stats)
+		batchStatslogs = append(batchStatslogs, logPaths(statPaths)...)
 	}
 
-	statPaths, err := uploadStatsLog(ctxTimeout, t.binlogIO, t.Allocator, meta.GetID(), partID, targetSegID, stats, totRows, iCodec)
+	mergedPaths, err := uploadMergedStatsLog(ctxTimeout, t.binlogIO, meta.GetID(), partID, targetSegID, batchStats, totRows, iCodec)
 	if err != nil {
 		return nil, nil, err
 	}
+	removeBatchStatslogs(ctxTimeout, t.binlogIO, batchStatslogs)
 
-	return inPaths, statPaths, nil
+	return inPaths, mergedPaths, nil
 }
 
+// uploadSingleInsertLog uploads a batch of the insert rows along with the pk statslog of the batch,
+// the pk stats of the batch are returned to be merged into the compound statslog.
 func (t *compactionTask) uploadSingleInsertLog(
 	ctxTimeout context.Context,
 	targetSegID UniqueID,
 	partID UniqueID,
 	meta *etcdpb.CollectionMeta,
 	writeBuffer *storage.InsertData,
-) (map[UniqueID]*datapb.FieldBinlog, error) {
+) (map[UniqueID]*datapb.FieldBinlog, map[UniqueID]*datapb.FieldBinlog, *storage.PrimaryKeyStats, error) {
 	iCodec := storage.NewInsertCodecWithSchema(meta)
+	iCodec.BinlogFormat = t.metaCache.BinlogFormat()
+
+	return uploadInsertLogWithStats(ctxTimeout, t.binlogIO, t.Allocator, meta.GetID(), partID, targetSegID, writeBuffer, iCodec)
+}
+
+// segmentBinlogIterator iterates the rows of a segment batch by batch, the binlogs of the next batch
+// are opened when the rows of the current batch are all read, and the binlogs are streamed event by event,
+// so only an event of each binlog of the current batch is in memory.
+type segmentBinlogIterator struct {
+	ctx      context.Context
+	binlogIO io.BinlogIO
+	batches  [][]string // paths of the field binlogs of each batch
+	checksum map[string]uint32
+	pkID     int64
+	pkType   schemapb.DataType
+	deleted  *storage.DeleteBitmap // deletes recorded by the row offsets, nil if none
+	offset   int64
+
+	current          *storage.InsertEventIterator
+	prefetcher       *io.Prefetcher // nil if the batches are streamed without read-ahead
+	downloadTimeCost time.Duration
+	err              error
+}
+
+// newSegmentBinlogIterator returns the iterator of the batches of the segment, the batches are prefetched
+// up to dataNode.binlogIO.compaction.prefetchDepth ahead of the one read if it's positive.
+func newSegmentBinlogIterator(ctx context.Context, binlogIO io.BinlogIO, batches [][]string, checksum map[string]uint32, pkID int64, pkType schemapb.DataType, deleted *storage.DeleteBitmap) *segmentBinlogIterator {
+	itr := &segmentBinlogIterator{
+		ctx:      ctx,
+		binlogIO: binlogIO,
+		batches:  batches,
+		checksum: checksum,
+		pkID:     pkID,
+		pkType:   pkType,
+		deleted:  deleted,
+	}
+	if depth := Params.DataNodeCfg.CompactionPrefetchDepth.GetAsInt(); depth > 0 && len(batches) > 0 {
+		itr.prefetcher = io.NewPrefetcher(ctx, binlogIO, batches, checksum, depth)
+	}
+	return itr
+}
 
-	inPaths, err := uploadInsertLog(ctxTimeout, t.binlogIO, t.Allocator, meta.GetID(), partID, targetSegID, writeBuffer, iCodec)
+// HasNext returns true if the segment has unread rows, or the next batch fails to be read, which Next returns.
+func (itr *segmentBinlogIterator) HasNext() bool {
+	for itr.err == nil && (itr.current == nil || !itr.current.HasNext()) {
+		if itr.current != nil {
+			itr.current.Dispose()
+			itr.current = nil
+		}
+		if len(itr.batches) == 0 {
+			return false
+		}
+		itr.err = itr.nextBatch()
+	}
+	return true
+}
+
+func (itr *segmentBinlogIterator) Next() (interface{}, error) {
+	if !itr.HasNext() {
+		return nil, storage.ErrNoMoreRecord
+	}
+	if itr.err != nil {
+		return nil, itr.err
+	}
+	next, err := itr.current.Next()
 	if err != nil {
 		return nil, err
 	}
+	offset := itr.offset
+	itr.offset++
+	if itr.deleted != nil {
+		v := next.(*storage.Value)
+		if ts, ok := itr.deleted.DeletedAt(offset); ok && uint64(v.Timestamp) < ts {
+			v.IsDeleted = true
+		}
+	}
+	return next, nil
+}
 
-	return inPaths, nil
+func (itr *segmentBinlogIterator) Dispose() {
+	if itr.current != nil {
+		itr.current.Dispose()
+		itr.current = nil
+	}
+	if itr.prefetcher != nil {
+		itr.prefetcher.Close()
+	}
+	itr.batches = nil
 }
 
+func (itr *segmentBinlogIterator) nextBatch() error {
+	path := itr.batches[0]
+	itr.batches = itr.batches[1:]
+
+	downloadStart := time.Now()
+	var (
+		readers []*storage.StreamBinlogReader
+		err     error
+	)
+	if itr.prefetcher != nil {
+		// only the time waiting for the batch prefetched is counted
+		readers, err = itr.prefetcher.Next()
+	} else {
+		// the checksums are verified by the readers once the binlogs are all read
+		checksums := lo.Map(path, func(p string, _ int) uint32 {
+			return itr.checksum[p]
+		})
+		readers, err = itr.binlogIO.DownloadStream(itr.ctx, path, checksums)
+	}
+	if err != nil {
+		log.Warn("download insertlogs wrong", zap.Strings("path", path), zap.Error(err))
+		return err
+	}
+	itr.downloadTimeCost += time.Since(downloadStart)
+
+	// deserialize the binlogs event by event
+	itr.current = storage.NewStreamInsertEventIterator(readers, itr.pkID, itr.pkType)
+	return nil
+}
+
+// merge merges the rows of the segments by a k-way merge of the primary key and the timestamp, the deleted
+// and expired rows are filtered on the fly, and the merged rows are uploaded once the write buffer is full.
+// The segments sorted by the primary keys are streamed, only a batch of each of them is downloaded at a time,
+// while the ones not sorted, e.g. the flushed ones, are sorted in memory if they're no larger than
+// dataNode.segment.compactionSortMaxSize, otherwise the segments are merged one after another unsorted.
+// It returns whether the merged rows are sorted by the primary keys besides the paths and the number of rows.
+// unMergedInsertlogs is the binlog paths of each batch of each segment,
+// deleted is the deletes of each segment recorded by the row offsets, aligned with unMergedInsertlogs.
 func (t *compactionTask) merge(
 	ctx context.Context,
-	unMergedInsertlogs [][]string,
+	unMergedInsertlogs [][][]string,
 	targetSegID UniqueID,
 	partID UniqueID,
 	meta *etcdpb.CollectionMeta,
-	delta map[interface{}]Timestamp,
-) ([]*datapb.FieldBinlog, []*datapb.FieldBinlog, int64, error) {
+	delta *storage.SortedDeleteData,
+	deleted []*storage.DeleteBitmap,
+) ([]*datapb.FieldBinlog, []*datapb.FieldBinlog, int64, bool, error) {
 	ctx, span := otel.Tracer(typeutil.DataNodeRole).Start(ctx, fmt.Sprintf("CompactMerge-%d", t.getPlanID()))
 	defer span.End()
 	log := log.With(zap.Int64("planID", t.getPlanID()))
 	mergeStart := time.Now()
 
-	var (
-		numBinlogs int   // binlog number
-		numRows    int64 // the number of rows uploaded
-		expired    int64 // the number of expired entity
+	writer, err := newSegmentWriter(t, targetSegID, partID, meta)
+	if err != nil {
+		return nil, nil, -1, false, err
+	}
 
-		insertField2Path = make(map[UniqueID]*datapb.FieldBinlog)
-		insertPaths      = make([]*datapb.FieldBinlog, 0)
+	oldRowNums, expired, downloadTimeCost, sorted, err := t.iterate(ctx, unMergedInsertlogs, meta, delta, deleted, true, func(v *storage.Value) error {
+		return writer.write(ctx, v)
+	})
+	if err != nil {
+		return nil, nil, 0, false, err
+	}
 
-		statField2Path = make(map[UniqueID]*datapb.FieldBinlog)
-		statPaths      = make([]*datapb.FieldBinlog, 0)
-	)
-	writeBuffer, err := storage.NewInsertData(meta.GetSchema())
+	// upload stats log and remain insert rows
+	insertPaths, statPaths, err := writer.finish(ctx)
 	if err != nil {
-		return nil, nil, -1, err
+		return nil, nil, 0, false, err
 	}
 
+	log.Info("compact merge end",
+		zap.Bool("sorted", sorted),
+		zap.Int64("original numRows", oldRowNums),
+		zap.Int64("remaining insert numRows", writer.numRows),
+		zap.Int64("expired entities", expired),
+		zap.Int("binlog file number", writer.numBinlogs),
+		zap.Duration("download insert log elapse", downloadTimeCost),
+		zap.Duration("upload insert log elapse", writer.uploadTimeCost),
+		zap.Duration("merge elapse", time.Since(mergeStart)))
+
+	return insertPaths, statPaths, writer.numRows, sorted, nil
+}
+
+// iterate reads the rows of the segments, and calls @fn on each row neither deleted nor expired. The rows are
+// merged by the primary key and the timestamp if @byPK and the segments are sortable, see sortable, otherwise
+// they're read one segment after another. It returns the number of rows of the segments before compacted,
+// the number of the expired rows, the time cost of downloading the insert binlogs and whether they're merged.
+func (t *compactionTask) iterate(
+	ctx context.Context,
+	unMergedInsertlogs [][][]string,
+	meta *etcdpb.CollectionMeta,
+	delta *storage.SortedDeleteData,
+	deleted []*storage.DeleteBitmap,
+	byPK bool,
+	fn func(v *storage.Value) error,
+) (int64, int64, time.Duration, bool, error) {
+	log := log.With(zap.Int64("planID", t.getPlanID()))
+
 	isDeletedValue := func(v *storage.Value) bool {
-		ts, ok := delta[v.PK.GetValue()]
+		ts, ok := delta.LatestTs(v.PK)
 		// insert task and delete task has the same ts when upsert
 		// here should be < instead of <=
 		// to avoid the upsert data to be deleted after compact
@@ -257,34 +418,6 @@ func (t *compactionTask) merge(
 		return false
 	}
 
-	addInsertFieldPath := func(inPaths map[UniqueID]*datapb.FieldBinlog, timestampFrom, timestampTo int64) {
-		for fID, path := range inPaths {
-			for _, binlog := range path.GetBinlogs() {
-				binlog.TimestampTo = uint64(timestampTo)
-				binlog.TimestampFrom = uint64(timestampFrom)
-			}
-			tmpBinlog, ok := insertField2Path[fID]
-			if !ok {
-				tmpBinlog = path
-			} else {
-				tmpBinlog.Binlogs = append(tmpBinlog.Binlogs, path.GetBinlogs()...)
-			}
-			insertField2Path[fID] = tmpBinlog
-		}
-	}
-
-	addStatFieldPath := func(statPaths map[UniqueID]*datapb.FieldBinlog) {
-		for fID, path := range statPaths {
-			tmpBinlog, ok := statField2Path[fID]
-			if !ok {
-				tmpBinlog = path
-			} else {
-				tmpBinlog.Binlogs = append(tmpBinlog.Binlogs, path.GetBinlogs()...)
-			}
-			statField2Path[fID] = tmpBinlog
-		}
-	}
-
 	// get pkID, pkType, dim
 	var pkField *schemapb.FieldSchema
 	for _, fs := range meta.GetSchema().GetFields() {
@@ -295,59 +428,35 @@ func (t *compactionTask) merge(
 
 	if pkField == nil {
 		log.Warn("failed to get pk field from schema")
-		return nil, nil, 0, fmt.Errorf("no pk field in schema")
+		return 0, 0, 0, false, fmt.Errorf("no pk field in schema")
 	}
 
 	pkID := pkField.GetFieldID()
 	pkType := pkField.GetDataType()
 
-	expired = 0
-	numRows = 0
-	numBinlogs = 0
+	var expired int64
 	currentTs := t.GetCurrentTime()
-	currentRows := 0
 	downloadTimeCost := time.Duration(0)
-	uploadInsertTimeCost := time.Duration(0)
 
 	oldRowNums, err := t.getNumRows()
 	if err != nil {
-		return nil, nil, 0, err
-	}
-
-	stats, err := storage.NewPrimaryKeyStats(pkID, int64(pkType), oldRowNums)
-	if err != nil {
-		return nil, nil, 0, err
+		return 0, 0, 0, false, err
 	}
-	// initial timestampFrom, timestampTo = -1, -1 is an illegal value, only to mark initial state
-	var (
-		timestampTo   int64 = -1
-		timestampFrom int64 = -1
-	)
-
-	for _, path := range unMergedInsertlogs {
-		downloadStart := time.Now()
-		data, err := downloadBlobs(ctx, t.binlogIO, path)
-		if err != nil {
-			log.Warn("download insertlogs wrong", zap.Strings("path", path), zap.Error(err))
-			return nil, nil, 0, err
-		}
-		downloadTimeCost += time.Since(downloadStart)
-
-		iter, err := storage.NewInsertBinlogIterator(data, pkID, pkType)
-		if err != nil {
-			log.Warn("new insert binlogs Itr wrong", zap.Strings("path", path), zap.Error(err))
-			return nil, nil, 0, err
-		}
 
-		for iter.HasNext() {
-			vInter, _ := iter.Next()
+	iterateRows := func(itr iterator) error {
+		for itr.HasNext() {
+			vInter, err := itr.Next()
+			if err != nil {
+				log.Warn("failed to read insert binlogs", zap.Error(err))
+				return err
+			}
 			v, ok := vInter.(*storage.Value)
 			if !ok {
-				log.Warn("transfer interface to Value wrong", zap.Strings("path", path))
-				return nil, nil, 0, errors.New("unexpected error")
+				log.Warn("transfer interface to Value wrong")
+				return errors.New("unexpected error")
 			}
 
-			if isDeletedValue(v) {
+			if v.IsDeleted || isDeletedValue(v) {
 				continue
 			}
 
@@ -358,82 +467,286 @@ func (t *compactionTask) merge(
 				continue
 			}
 
-			// Update timestampFrom, timestampTo
-			if v.Timestamp < timestampFrom || timestampFrom == -1 {
-				timestampFrom = v.Timestamp
-			}
-			if v.Timestamp > timestampTo || timestampFrom == -1 {
-				timestampTo = v.Timestamp
+			if err := fn(v); err != nil {
+				return err
 			}
+		}
+		return nil
+	}
 
-			row, ok := v.Value.(map[UniqueID]interface{})
-			if !ok {
-				log.Warn("transfer interface to map wrong", zap.Strings("path", path))
-				return nil, nil, 0, errors.New("unexpected error")
-			}
+	checksum := binlogChecksum(t.plan.GetSegmentBinlogs())
+	newSegmentIterator := func(i int) *segmentBinlogIterator {
+		var bitmap *storage.DeleteBitmap
+		if i < len(deleted) {
+			bitmap = deleted[i]
+		}
+		return newSegmentBinlogIterator(ctx, t.binlogIO, unMergedInsertlogs[i], checksum, pkID, pkType, bitmap)
+	}
 
-			err = writeBuffer.Append(row)
+	if !byPK || !t.sortable(len(unMergedInsertlogs)) {
+		// the segments are read one by one, so only the batches of a segment are downloaded or prefetched at a time
+		for i := range unMergedInsertlogs {
+			segmentIterator := newSegmentIterator(i)
+			err := iterateRows(segmentIterator)
+			segmentIterator.Dispose()
+			downloadTimeCost += segmentIterator.downloadTimeCost
 			if err != nil {
-				return nil, nil, 0, err
+				return 0, 0, 0, false, err
 			}
+		}
+		return oldRowNums, expired, downloadTimeCost, false, nil
+	}
 
-			currentRows++
-			stats.Update(v.PK)
-
-			// check size every 100 rows in case of too many `GetMemorySize` call
-			if (currentRows+1)%100 == 0 && writeBuffer.GetMemorySize() > paramtable.Get().DataNodeCfg.BinLogMaxSize.GetAsInt() {
-				numRows += int64(writeBuffer.GetRowNum())
-				uploadInsertStart := time.Now()
-				inPaths, err := t.uploadSingleInsertLog(ctx, targetSegID, partID, meta, writeBuffer)
-				if err != nil {
-					log.Warn("failed to upload single insert log", zap.Error(err))
-					return nil, nil, 0, err
-				}
-				uploadInsertTimeCost += time.Since(uploadInsertStart)
-				addInsertFieldPath(inPaths, timestampFrom, timestampTo)
-				timestampFrom = -1
-				timestampTo = -1
-
-				writeBuffer, _ = storage.NewInsertData(meta.GetSchema())
-				currentRows = 0
-				numBinlogs++
+	segmentBinlogs := t.plan.GetSegmentBinlogs()
+	iterators := make([]iterator, 0, len(unMergedInsertlogs))
+	segmentIterators := make([]*segmentBinlogIterator, 0, len(unMergedInsertlogs))
+	defer func() {
+		for _, segmentIterator := range segmentIterators {
+			segmentIterator.Dispose()
+		}
+	}()
+	for i := range unMergedInsertlogs {
+		segmentIterator := newSegmentIterator(i)
+		segmentIterators = append(segmentIterators, segmentIterator)
+		if segmentBinlogs[i].GetIsSorted() {
+			iterators = append(iterators, segmentIterator)
+			continue
+		}
+		values, err := sortValues(segmentIterator)
+		if err != nil {
+			log.Warn("failed to sort insert binlogs", zap.Int64("segmentID", segmentBinlogs[i].GetSegmentID()), zap.Error(err))
+			return 0, 0, 0, false, err
+		}
+		iterators = append(iterators, values)
+	}
+
+	iter := storage.NewPKMergeIterator(iterators)
+	defer iter.Dispose()
+	if err := iterateRows(iter); err != nil {
+		return 0, 0, 0, false, err
+	}
+	for _, segmentIterator := range segmentIterators {
+		downloadTimeCost += segmentIterator.downloadTimeCost
+	}
+	return oldRowNums, expired, downloadTimeCost, true, nil
+}
+
+// sortable returns true if the segments of the plan are sorted by the primary keys, or the insert binlogs of
+// the ones not sorted are no larger than dataNode.segment.compactionSortMaxSize, which are sorted in memory.
+// numSegments is the number of the segments of the plan with insert binlogs.
+func (t *compactionTask) sortable(numSegments int) bool {
+	segmentBinlogs := t.plan.GetSegmentBinlogs()
+	if len(segmentBinlogs) != numSegments {
+		return false
+	}
+	var size int64
+	for _, s := range segmentBinlogs {
+		if s.GetIsSorted() {
+			continue
+		}
+		for _, fieldBinlog := range s.GetFieldBinlogs() {
+			for _, binlog := range fieldBinlog.GetBinlogs() {
+				size += binlog.GetLogSize()
 			}
 		}
 	}
+	return size <= Params.DataNodeCfg.CompactionSortMaxSize.GetAsInt64()
+}
 
-	// upload stats log and remain insert rows
-	if writeBuffer.GetRowNum() > 0 || numRows > 0 {
-		numRows += int64(writeBuffer.GetRowNum())
+// sortValues reads the rows of the segment not deleted by the row offsets, and sorts them by the primary key
+// and the timestamp.
+func sortValues(segmentIterator *segmentBinlogIterator) (*valuesIterator, error) {
+	var values []*storage.Value
+	for segmentIterator.HasNext() {
+		vInter, err := segmentIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		v, ok := vInter.(*storage.Value)
+		if !ok {
+			return nil, errTransferType
+		}
+		if !v.IsDeleted {
+			values = append(values, v)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].PK.EQ(values[j].PK) {
+			return values[i].Timestamp < values[j].Timestamp
+		}
+		return values[i].PK.LT(values[j].PK)
+	})
+	return &valuesIterator{values: values}, nil
+}
+
+// valuesIterator iterates the values held in memory.
+type valuesIterator struct {
+	values []*storage.Value
+}
+
+func (itr *valuesIterator) HasNext() bool {
+	return len(itr.values) > 0
+}
+
+func (itr *valuesIterator) Next() (interface{}, error) {
+	if len(itr.values) == 0 {
+		return nil, storage.ErrNoMoreRecord
+	}
+	v := itr.values[0]
+	itr.values[0] = nil
+	itr.values = itr.values[1:]
+	return v, nil
+}
+
+func (itr *valuesIterator) Dispose() {
+	itr.values = nil
+}
+
+// segmentWriter writes the rows of a result segment of the compaction, the rows are buffered and uploaded
+// as a batch of insert binlogs when the buffer is larger than dataNode.segment.binlog.maxsize,
+// and the remaining rows are uploaded along with the compound statslog when finished.
+type segmentWriter struct {
+	t         *compactionTask
+	segmentID UniqueID
+	partID    UniqueID
+	meta      *etcdpb.CollectionMeta
+
+	writeBuffer *storage.InsertData
+	currentRows int
+	numRows     int64 // the number of rows uploaded
+	numBinlogs  int   // binlog number
+	// the pk stats of the uploaded batches, merged into the compound statslog at last
+	batchStats []*storage.PrimaryKeyStats
+	// the paths of the pk statslogs of the uploaded batches, removed once merged
+	batchStatslogs []string
+	// initial timestampFrom, timestampTo = -1, -1 is an illegal value, only to mark initial state
+	timestampFrom int64
+	timestampTo   int64
+
+	insertField2Path map[UniqueID]*datapb.FieldBinlog
+	statField2Path   map[UniqueID]*datapb.FieldBinlog
+	uploadTimeCost   time.Duration
+}
+
+func newSegmentWriter(t *compactionTask, segmentID, partID UniqueID, meta *etcdpb.CollectionMeta) (*segmentWriter, error) {
+	writeBuffer, err := storage.NewInsertData(meta.GetSchema())
+	if err != nil {
+		return nil, err
+	}
+	return &segmentWriter{
+		t:                t,
+		segmentID:        segmentID,
+		partID:           partID,
+		meta:             meta,
+		writeBuffer:      writeBuffer,
+		batchStats:       make([]*storage.PrimaryKeyStats, 0),
+		timestampFrom:    -1,
+		timestampTo:      -1,
+		insertField2Path: make(map[UniqueID]*datapb.FieldBinlog),
+		statField2Path:   make(map[UniqueID]*datapb.FieldBinlog),
+	}, nil
+}
+
+func (w *segmentWriter) addInsertFieldPath(inPaths map[UniqueID]*datapb.FieldBinlog) {
+	for fID, path := range inPaths {
+		for _, binlog := range path.GetBinlogs() {
+			binlog.TimestampTo = uint64(w.timestampTo)
+			binlog.TimestampFrom = uint64(w.timestampFrom)
+		}
+		tmpBinlog, ok := w.insertField2Path[fID]
+		if !ok {
+			tmpBinlog = path
+		} else {
+			tmpBinlog.Binlogs = append(tmpBinlog.Binlogs, path.GetBinlogs()...)
+		}
+		w.insertField2Path[fID] = tmpBinlog
+	}
+}
+
+func (w *segmentWriter) addStatFieldPath(statPaths map[UniqueID]*datapb.FieldBinlog) {
+	for fID, path := range statPaths {
+		tmpBinlog, ok := w.statField2Path[fID]
+		if !ok {
+			tmpBinlog = path
+		} else {
+			tmpBinlog.Binlogs = append(tmpBinlog.Binlogs, path.GetBinlogs()...)
+		}
+		w.statField2Path[fID] = tmpBinlog
+	}
+}
+
+func (w *segmentWriter) write(ctx context.Context, v *storage.Value) error {
+	// Update timestampFrom, timestampTo
+	if v.Timestamp < w.timestampFrom || w.timestampFrom == -1 {
+		w.timestampFrom = v.Timestamp
+	}
+	if v.Timestamp > w.timestampTo || w.timestampTo == -1 {
+		w.timestampTo = v.Timestamp
+	}
+
+	row, ok := v.Value.(map[UniqueID]interface{})
+	if !ok {
+		log.Warn("transfer interface to map wrong", zap.Int64("planID", w.t.getPlanID()))
+		return errors.New("unexpected error")
+	}
+
+	err := w.writeBuffer.Append(row)
+	if err != nil {
+		return err
+	}
+
+	w.currentRows++
+
+	// check size every 100 rows in case of too many `GetMemorySize` call
+	if (w.currentRows+1)%100 == 0 && w.writeBuffer.GetMemorySize() > paramtable.Get().DataNodeCfg.BinLogMaxSize.GetAsInt() {
+		w.numRows += int64(w.writeBuffer.GetRowNum())
+		uploadInsertStart := time.Now()
+		inPaths, statsPaths, stats, err := w.t.uploadSingleInsertLog(ctx, w.segmentID, w.partID, w.meta, w.writeBuffer)
+		if err != nil {
+			log.Warn("failed to upload single insert log", zap.Int64("planID", w.t.getPlanID()), zap.Error(err))
+			return err
+		}
+		w.uploadTimeCost += time.Since(uploadInsertStart)
+		w.addInsertFieldPath(inPaths)
+		w.batchStats = append(w.batchStats, stats)
+		w.batchStatslogs = append(w.batchStatslogs, logPaths(statsPaths)...)
+		w.timestampFrom = -1
+		w.timestampTo = -1
+
+		w.writeBuffer, _ = storage.NewInsertData(w.meta.GetSchema())
+		w.currentRows = 0
+		w.numBinlogs++
+	}
+	return nil
+}
+
+// finish uploads the remaining rows and the compound statslog, and returns the insert binlogs and statslogs of the segment.
+func (w *segmentWriter) finish(ctx context.Context) ([]*datapb.FieldBinlog, []*datapb.FieldBinlog, error) {
+	if w.writeBuffer.GetRowNum() > 0 || w.numRows > 0 {
+		w.numRows += int64(w.writeBuffer.GetRowNum())
 		uploadStart := time.Now()
-		inPaths, statsPaths, err := t.uploadRemainLog(ctx, targetSegID, partID, meta,
-			stats, numRows+int64(currentRows), writeBuffer)
+		inPaths, statsPaths, err := w.t.uploadRemainLog(ctx, w.segmentID, w.partID, w.meta,
+			w.batchStats, w.batchStatslogs, w.numRows, w.writeBuffer)
 		if err != nil {
-			return nil, nil, 0, err
+			return nil, nil, err
 		}
 
-		uploadInsertTimeCost += time.Since(uploadStart)
-		addInsertFieldPath(inPaths, timestampFrom, timestampTo)
-		addStatFieldPath(statsPaths)
-		numBinlogs += len(inPaths)
+		w.uploadTimeCost += time.Since(uploadStart)
+		w.addInsertFieldPath(inPaths)
+		w.addStatFieldPath(statsPaths)
+		w.numBinlogs += len(inPaths)
 	}
 
-	for _, path := range insertField2Path {
+	insertPaths := make([]*datapb.FieldBinlog, 0, len(w.insertField2Path))
+	for _, path := range w.insertField2Path {
 		insertPaths = append(insertPaths, path)
 	}
 
-	for _, path := range statField2Path {
+	statPaths := make([]*datapb.FieldBinlog, 0, len(w.statField2Path))
+	for _, path := range w.statField2Path {
 		statPaths = append(statPaths, path)
 	}
-
-	log.Info("compact merge end",
-		zap.Int64("remaining insert numRows", numRows),
-		zap.Int64("expired entities", expired),
-		zap.Int("binlog file number", numBinlogs),
-		zap.Duration("download insert log elapse", downloadTimeCost),
-		zap.Duration("upload insert log elapse", uploadInsertTimeCost),
-		zap.Duration("merge elapse", time.Since(mergeStart)))
-
-	return insertPaths, statPaths, numRows, nil
+	return insertPaths, statPaths, nil
 }
 
 func (t *compactionTask) compact() (*datapb.CompactionPlanResult, error) {
@@ -467,6 +780,14 @@ func (t *compactionTask) compact() (*datapb.CompactionPlanResult, error) {
 		return binlogs.GetSegmentID()
 	})
 
+	// reserve the memory before blocking the flush of the segments, as flush releases memory of the buffers
+	workingSetSize := t.workingSetSize()
+	if err := membudget.GetBudget().Reserve(ctxTimeout, membudget.Compaction, workingSetSize); err != nil {
+		log.Warn("compact wrong, failed to reserve memory", zap.Int64("workingSetSize", workingSetSize), zap.Error(err))
+		return nil, err
+	}
+	defer membudget.GetBudget().Release(membudget.Compaction, workingSetSize)
+
 	// Inject to stop flush
 	// when compaction failed, these segments need to be Unblocked by injectDone in compaction_executor
 	// when compaction succeeded, these segments will be Unblocked by SyncSegments from DataCoord.
@@ -480,8 +801,16 @@ func (t *compactionTask) compact() (*datapb.CompactionPlanResult, error) {
 		return nil, err
 	}
 
+	pkField, err := typeutil.GetPrimaryFieldSchema(t.metaCache.Schema())
+	if err != nil {
+		log.Warn("compact wrong, failed to get pk field from schema", zap.Error(err))
+		return nil, err
+	}
+
 	dblobs := make(map[UniqueID][]*Blob)
-	allPath := make([][]string, 0)
+	allPath := make([][][]string, 0)
+	bitmaps := make([]*storage.DeleteBitmap, 0)
+	checksum := binlogChecksum(t.plan.GetSegmentBinlogs())
 	for _, s := range t.plan.GetSegmentBinlogs() {
 		// Get the number of field binlog files from non-empty segment
 		var binlogNum int
@@ -497,35 +826,50 @@ func (t *compactionTask) compact() (*datapb.CompactionPlanResult, error) {
 			return nil, errIllegalCompactionPlan
 		}
 
+		batches := make([][]string, 0, binlogNum)
 		for idx := 0; idx < binlogNum; idx++ {
 			var ps []string
 			for _, f := range s.GetFieldBinlogs() {
 				ps = append(ps, f.GetBinlogs()[idx].GetLogPath())
 			}
-			allPath = append(allPath, ps)
+			batches = append(batches, ps)
 		}
+		allPath = append(allPath, batches)
 
 		segID := s.GetSegmentID()
 		paths := make([]string, 0)
-		for _, d := range s.GetDeltalogs() {
+		for _, d := range binlog.FilterDeltalogs(s.GetDeltalogs(), pkField.GetFieldID()) {
 			for _, l := range d.GetBinlogs() {
 				path := l.GetLogPath()
 				paths = append(paths, path)
 			}
 		}
 
+		var bitmap *storage.DeleteBitmap
 		if len(paths) != 0 {
 			bs, err := downloadBlobs(ctxTimeout, t.binlogIO, paths)
 			if err != nil {
 				log.Warn("compact wrong, fail to download deltalogs", zap.Int64("segment", segID), zap.Strings("path", paths), zap.Error(err))
 				return nil, err
 			}
-			dblobs[segID] = append(dblobs[segID], bs...)
+			if err := verifyBlobs(paths, bs, checksum); err != nil {
+				log.Warn("compact wrong, deltalogs corrupted", zap.Int64("segment", segID), zap.Error(err))
+				return nil, err
+			}
+			bs, bitmap, err = storage.NewDeleteCodec().DeserializeBitmaps(bs)
+			if err != nil {
+				log.Warn("compact wrong, fail to read bitmap deltalogs", zap.Int64("segment", segID), zap.Error(err))
+				return nil, err
+			}
+			if len(bs) > 0 {
+				dblobs[segID] = append(dblobs[segID], bs...)
+			}
 		}
+		bitmaps = append(bitmaps, bitmap)
 	}
 	log.Info("compact download deltalogs done", zap.Duration("elapse", t.tr.RecordSpan()))
 
-	deltaPk2Ts, err := t.mergeDeltalogs(dblobs)
+	delta, err := t.mergeDeltalogs(dblobs)
 	if err != nil {
 		log.Warn("compact wrong, fail to merge deltalogs", zap.Error(err))
 		return nil, err
@@ -535,29 +879,40 @@ func (t *compactionTask) compact() (*datapb.CompactionPlanResult, error) {
 	partID := segmentBinlog.GetPartitionID()
 	meta := &etcdpb.CollectionMeta{ID: t.metaCache.Collection(), Schema: t.metaCache.Schema()}
 
-	inPaths, statsPaths, numRows, err := t.merge(ctxTimeout, allPath, targetSegID, partID, meta, deltaPk2Ts)
-	if err != nil {
-		log.Warn("compact wrong, fail to merge", zap.Error(err))
-		return nil, err
+	var segments []*datapb.CompactionSegment
+	if t.plan.GetType() == datapb.CompactionType_ClusteringCompaction {
+		segments, err = t.cluster(ctxTimeout, allPath, targetSegID, partID, meta, delta, bitmaps)
+		if err != nil {
+			log.Warn("compact wrong, fail to cluster", zap.Error(err))
+			return nil, err
+		}
+	} else {
+		inPaths, statsPaths, numRows, sorted, err := t.merge(ctxTimeout, allPath, targetSegID, partID, meta, delta, bitmaps)
+		if err != nil {
+			log.Warn("compact wrong, fail to merge", zap.Error(err))
+			return nil, err
+		}
+		segments = []*datapb.CompactionSegment{{
+			SegmentID:           targetSegID,
+			InsertLogs:          inPaths,
+			Field2StatslogPaths: statsPaths,
+			NumOfRows:           numRows,
+			Channel:             t.plan.GetChannel(),
+			IsSorted:            sorted,
+		}}
 	}
 
-	pack := &datapb.CompactionSegment{
-		SegmentID:           targetSegID,
-		InsertLogs:          inPaths,
-		Field2StatslogPaths: statsPaths,
-		NumOfRows:           numRows,
-		Channel:             t.plan.GetChannel(),
+	for _, pack := range segments {
+		log.Info("compact done",
+			zap.Int64("targetSegmentID", pack.GetSegmentID()),
+			zap.Int64s("compactedFrom", segIDs),
+			zap.Int("num of binlog paths", len(pack.GetInsertLogs())),
+			zap.Int("num of stats paths", len(pack.GetField2StatslogPaths())),
+			zap.Int("num of delta paths", len(pack.GetDeltalogs())),
+			zap.Duration("elapse", time.Since(compactStart)),
+		)
 	}
 
-	log.Info("compact done",
-		zap.Int64("targetSegmentID", targetSegID),
-		zap.Int64s("compactedFrom", segIDs),
-		zap.Int("num of binlog paths", len(inPaths)),
-		zap.Int("num of stats paths", len(statsPaths)),
-		zap.Int("num of delta paths", len(pack.GetDeltalogs())),
-		zap.Duration("elapse", time.Since(compactStart)),
-	)
-
 	metrics.DataNodeCompactionLatency.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), t.plan.GetType().String()).Observe(float64(t.tr.ElapseSpan().Milliseconds()))
 	metrics.DataNodeCompactionLatencyInQueue.WithLabelValues(fmt.Sprint(paramtable.GetNodeID())).Observe(float64(durInQueue.Milliseconds()))
 
@@ -565,13 +920,44 @@ func (t *compactionTask) compact() (*datapb.CompactionPlanResult, error) {
 		State:    commonpb.CompactionState_Completed,
 		PlanID:   t.getPlanID(),
 		Channel:  t.plan.GetChannel(),
-		Segments: []*datapb.CompactionSegment{pack},
+		Segments: segments,
 		Type:     t.plan.GetType(),
 	}
 
 	return planResult, nil
 }
 
+// workingSetSize estimates the memory held by the compaction: the deltalogs of all segments are
+// loaded at once, the insert binlogs are read a batch at a time, and the merged rows are buffered
+// up to dataNode.segment.binlog.maxsize before written. The clustering compaction holds all the rows
+// to sort them, estimated by the size of all the insert binlogs.
+func (t *compactionTask) workingSetSize() int64 {
+	var deltaSize, maxBatchSize, insertSize int64
+	for _, s := range t.plan.GetSegmentBinlogs() {
+		for _, d := range s.GetDeltalogs() {
+			for _, l := range d.GetBinlogs() {
+				deltaSize += l.GetLogSize()
+			}
+		}
+		batchSizes := make(map[int]int64)
+		for _, f := range s.GetFieldBinlogs() {
+			for idx, l := range f.GetBinlogs() {
+				batchSizes[idx] += l.GetLogSize()
+				insertSize += l.GetLogSize()
+			}
+		}
+		for _, size := range batchSizes {
+			if size > maxBatchSize {
+				maxBatchSize = size
+			}
+		}
+	}
+	if t.plan.GetType() == datapb.CompactionType_ClusteringCompaction {
+		maxBatchSize = insertSize
+	}
+	return deltaSize + maxBatchSize + paramtable.Get().DataNodeCfg.BinLogMaxSize.GetAsInt64()
+}
+
 func (t *compactionTask) injectDone() {
 	t.injectDoneOnce.Do(func() {
 		for _, binlog := range t.plan.SegmentBinlogs {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"path"
 	"testing"
 	"time"
 
@@ -42,8 +43,10 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/etcdpb"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/timerecord"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
 var compactTestDir = "/tmp/milvus_test/compact"
@@ -183,14 +186,18 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 					done: make(chan struct{}, 1),
 				}
 				t.Run(test.description, func(t *testing.T) {
-					pk2ts, err := task.mergeDeltalogs(test.dBlobs)
+					delta, err := task.mergeDeltalogs(test.dBlobs)
 					if test.isvalid {
 						assert.NoError(t, err)
-						assert.Equal(t, 5, len(pk2ts))
-						assert.EqualValues(t, 20001, pk2ts[UniqueID(2)])
+						assert.Equal(t, 7, delta.Len())
+						ts, ok := delta.LatestTs(storage.NewInt64PrimaryKey(2))
+						assert.True(t, ok)
+						assert.EqualValues(t, 20001, ts)
+						_, ok = delta.LatestTs(storage.NewInt64PrimaryKey(6))
+						assert.False(t, ok)
 					} else {
 						assert.Error(t, err)
-						assert.Nil(t, pk2ts)
+						assert.Nil(t, delta)
 					}
 				})
 			}
@@ -259,9 +266,9 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 					task := &compactionTask{
 						done: make(chan struct{}, 1),
 					}
-					pk2ts, err := task.mergeDeltalogs(dBlobs)
+					delta, err := task.mergeDeltalogs(dBlobs)
 					assert.NoError(t, err)
-					assert.Equal(t, test.expectedpk2ts, len(pk2ts))
+					assert.Equal(t, test.expectedpk2ts, delta.Len())
 				})
 			}
 		})
@@ -279,6 +286,10 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 
 		metaCache := metacache.NewMockMetaCache(t)
 		metaCache.EXPECT().Schema().Return(meta.GetSchema()).Maybe()
+		metaCache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+		metaCache.EXPECT().StorageTenant().Return("").Maybe()
+		metaCache.EXPECT().BinlogCompression().Return("").Maybe()
+		metaCache.EXPECT().DeltalogSorted().Return(false).Maybe()
 		metaCache.EXPECT().GetSegmentByID(mock.Anything).RunAndReturn(func(id int64, filters ...metacache.SegmentFilter) (*metacache.SegmentInfo, bool) {
 			segment := metacache.NewSegmentInfo(&datapb.SegmentInfo{
 				CollectionID: 1,
@@ -312,9 +323,7 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 				allPaths = append(allPaths, ps)
 			}
 
-			dm := map[interface{}]Timestamp{
-				1: 10000,
-			}
+			dm := int64Deltas(map[int64]Timestamp{1: 10000})
 
 			ct := &compactionTask{
 				metaCache: metaCache,
@@ -327,7 +336,7 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 					},
 				},
 			}
-			inPaths, statsPaths, numOfRow, err := ct.merge(context.Background(), allPaths, 2, 0, meta, dm)
+			inPaths, statsPaths, numOfRow, _, err := ct.merge(context.Background(), [][][]string{allPaths}, 2, 0, meta, dm, nil)
 			assert.NoError(t, err)
 			assert.Equal(t, int64(2), numOfRow)
 			assert.Equal(t, 1, len(inPaths[0].GetBinlogs()))
@@ -335,6 +344,186 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 			assert.NotEqual(t, -1, inPaths[0].GetBinlogs()[0].GetTimestampFrom())
 			assert.NotEqual(t, -1, inPaths[0].GetBinlogs()[0].GetTimestampTo())
 		})
+		t.Run("Cluster by the clustering field", func(t *testing.T) {
+			mockbIO := io.NewBinlogIO(cm, getOrCreateIOPool())
+			paramtable.Get().Save(Params.CommonCfg.EntityExpirationTTL.Key, "0")
+			iData := genInsertData(101)
+			iCodec := storage.NewInsertCodecWithSchema(meta)
+			inpath, err := uploadInsertLog(context.Background(), mockbIO, alloc, meta.GetID(), 0, 1, iData, iCodec)
+			assert.NoError(t, err)
+
+			var paths []string
+			for _, fieldBinlog := range inpath {
+				paths = append(paths, fieldBinlog.GetBinlogs()[0].GetLogPath())
+			}
+
+			ct := &compactionTask{
+				metaCache: metaCache,
+				binlogIO:  mockbIO,
+				Allocator: alloc,
+				done:      make(chan struct{}, 1),
+				plan: &datapb.CompactionPlan{
+					Type:              datapb.CompactionType_ClusteringCompaction,
+					ClusteringFieldID: 105,
+					MaxSegmentRows:    50,
+					SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{
+						{SegmentID: 1},
+					},
+				},
+			}
+			segments, err := ct.cluster(context.Background(), [][][]string{{paths}}, 2, 0, meta, int64Deltas(nil), nil)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, len(segments))
+			assert.Equal(t, int64(2), segments[0].GetSegmentID())
+			assert.Equal(t, []int64{50, 50, 1}, lo.Map(segments, func(segment *datapb.CompactionSegment, _ int) int64 {
+				return segment.GetNumOfRows()
+			}))
+
+			ct.plan.ClusteringFieldID = 999
+			_, err = ct.cluster(context.Background(), [][][]string{{paths}}, 2, 0, meta, int64Deltas(nil), nil)
+			assert.ErrorIs(t, err, merr.ErrFieldNotFound)
+		})
+		t.Run("Merge with corrupted binlog", func(t *testing.T) {
+			mockbIO := io.NewBinlogIO(cm, getOrCreateIOPool())
+			paramtable.Get().Save(Params.CommonCfg.EntityExpirationTTL.Key, "0")
+			iData := genInsertDataWithExpiredTS()
+			iCodec := storage.NewInsertCodecWithSchema(meta)
+			inpath, err := uploadInsertLog(context.Background(), mockbIO, alloc, meta.GetID(), 0, 1, iData, iCodec)
+			assert.NoError(t, err)
+
+			var paths []string
+			fieldBinlogs := make([]*datapb.FieldBinlog, 0, len(inpath))
+			for _, fieldBinlog := range inpath {
+				assert.NotZero(t, fieldBinlog.GetBinlogs()[0].GetChecksum())
+				paths = append(paths, fieldBinlog.GetBinlogs()[0].GetLogPath())
+				fieldBinlogs = append(fieldBinlogs, fieldBinlog)
+			}
+			// the checksum recorded in meta mismatches the binlog content
+			fieldBinlogs[0].GetBinlogs()[0].Checksum++
+
+			ct := &compactionTask{
+				metaCache: metaCache,
+				binlogIO:  mockbIO,
+				Allocator: alloc,
+				done:      make(chan struct{}, 1),
+				plan: &datapb.CompactionPlan{
+					SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{
+						{SegmentID: 1, FieldBinlogs: fieldBinlogs},
+					},
+				},
+			}
+			_, _, _, _, err = ct.merge(context.Background(), [][][]string{{paths}}, 2, 0, meta, int64Deltas(nil), nil)
+			assert.ErrorIs(t, err, merr.ErrIoChecksumMismatch)
+		})
+		t.Run("Merge with delete bitmap", func(t *testing.T) {
+			mockbIO := io.NewBinlogIO(cm, getOrCreateIOPool())
+			paramtable.Get().Save(Params.CommonCfg.EntityExpirationTTL.Key, "0")
+			iData := genInsertData(3)
+			iCodec := storage.NewInsertCodecWithSchema(meta)
+			inpath, err := uploadInsertLog(context.Background(), mockbIO, alloc, meta.GetID(), 0, 1, iData, iCodec)
+			assert.NoError(t, err)
+			var ps []string
+			for _, path := range inpath {
+				ps = append(ps, path.GetBinlogs()[0].GetLogPath())
+			}
+
+			// the row at offset 1 is deleted, the delete at offset 2 is earlier than the insert
+			bitmap := storage.NewDeleteBitmap(3)
+			bitmap.Add(1, 10)
+			bitmap.Add(2, 1)
+
+			ct := &compactionTask{
+				metaCache: metaCache,
+				binlogIO:  mockbIO,
+				Allocator: alloc,
+				done:      make(chan struct{}, 1),
+				plan: &datapb.CompactionPlan{
+					SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{
+						{SegmentID: 1},
+					},
+				},
+			}
+			_, _, numOfRow, _, err := ct.merge(context.Background(), [][][]string{{ps}}, 2, 0, meta, int64Deltas(nil), []*storage.DeleteBitmap{bitmap})
+			assert.NoError(t, err)
+			assert.EqualValues(t, 2, numOfRow)
+		})
+		t.Run("Merge segments by pk", func(t *testing.T) {
+			mockbIO := io.NewBinlogIO(cm, getOrCreateIOPool())
+			paramtable.Get().Save(Params.CommonCfg.EntityExpirationTTL.Key, "0")
+			iCodec := storage.NewInsertCodecWithSchema(meta)
+
+			// two batches of each segment, the first one is sorted by the pks while the second one is not
+			var allPaths [][][]string
+			for i, pks := range [][][2]int64{
+				{{1, 4}, {5, 8}},
+				{{3, 2}, {7, 6}},
+			} {
+				segmentID := int64(i + 1)
+				var batches [][]string
+				for idx, batch := range pks {
+					iData := genInsertDataWithPKs([2]storage.PrimaryKey{
+						storage.NewInt64PrimaryKey(batch[0]), storage.NewInt64PrimaryKey(batch[1]),
+					}, schemapb.DataType_Int64)
+					// the log ids are the same for each upload, use the partition id to make the paths different
+					inpath, err := uploadInsertLog(context.Background(), mockbIO, alloc, meta.GetID(), int64(idx), segmentID, iData, iCodec)
+					assert.NoError(t, err)
+					var ps []string
+					for _, path := range inpath {
+						ps = append(ps, path.GetBinlogs()[0].GetLogPath())
+					}
+					batches = append(batches, ps)
+				}
+				allPaths = append(allPaths, batches)
+			}
+
+			ct := &compactionTask{
+				metaCache: metaCache,
+				binlogIO:  mockbIO,
+				Allocator: alloc,
+				done:      make(chan struct{}, 1),
+				plan: &datapb.CompactionPlan{
+					SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{
+						{SegmentID: 1, IsSorted: true}, {SegmentID: 2},
+					},
+				},
+			}
+			for _, c := range []struct {
+				sortMaxSize string
+				sorted      bool
+				pks         []int64
+			}{
+				// the second segment is sorted in memory, and merged with the first one streamed
+				{"268435456", true, []int64{1, 2, 3, 4, 5, 6, 7, 8}},
+				// too large to sort, the rows are merged segment by segment, batch by batch
+				{"-1", false, []int64{1, 4, 5, 8, 3, 2, 7, 6}},
+			} {
+				// the batches are streamed, or prefetched ahead of the merge
+				for _, depth := range []string{"0", "1", "2"} {
+					paramtable.Get().Save(Params.DataNodeCfg.CompactionSortMaxSize.Key, c.sortMaxSize)
+					paramtable.Get().Save(Params.DataNodeCfg.CompactionPrefetchDepth.Key, depth)
+					inPaths, _, numOfRow, sorted, err := ct.merge(context.Background(), allPaths, 3, 0, meta, int64Deltas(nil), nil)
+					paramtable.Get().Reset(Params.DataNodeCfg.CompactionPrefetchDepth.Key)
+					paramtable.Get().Reset(Params.DataNodeCfg.CompactionSortMaxSize.Key)
+					assert.NoError(t, err)
+					assert.Equal(t, int64(8), numOfRow)
+					assert.Equal(t, c.sorted, sorted)
+
+					pkBinlog, ok := lo.Find(inPaths, func(fieldBinlog *datapb.FieldBinlog) bool { return fieldBinlog.GetFieldID() == 106 })
+					require.True(t, ok)
+					require.Equal(t, 1, len(pkBinlog.GetBinlogs()))
+					blobs, err := downloadBlobs(context.Background(), mockbIO, []string{pkBinlog.GetBinlogs()[0].GetLogPath()})
+					require.NoError(t, err)
+					reader, err := storage.NewBinlogReader(blobs[0].GetValue())
+					require.NoError(t, err)
+					eventReader, err := reader.NextEventReader()
+					require.NoError(t, err)
+					pks, err := eventReader.GetInt64FromPayload()
+					require.NoError(t, err)
+					reader.Close()
+					assert.Equal(t, c.pks, pks)
+				}
+			}
+		})
 		t.Run("Merge without expiration2", func(t *testing.T) {
 			mockbIO := io.NewBinlogIO(cm, getOrCreateIOPool())
 			iCodec := storage.NewInsertCodecWithSchema(meta)
@@ -362,7 +551,7 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 				allPaths = append(allPaths, ps)
 			}
 
-			dm := map[interface{}]Timestamp{}
+			dm := int64Deltas(nil)
 
 			ct := &compactionTask{
 				metaCache: metaCache,
@@ -375,12 +564,14 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 					},
 				},
 			}
-			inPaths, statsPaths, numOfRow, err := ct.merge(context.Background(), allPaths, 2, 0, meta, dm)
+			inPaths, statsPaths, numOfRow, _, err := ct.merge(context.Background(), [][][]string{allPaths}, 2, 0, meta, dm, nil)
 			assert.NoError(t, err)
 			assert.Equal(t, int64(2), numOfRow)
 			assert.Equal(t, 1, len(inPaths[0].GetBinlogs()))
 			assert.Equal(t, 1, len(statsPaths))
+			// only the compound statslog, the statslog of the batch is removed once merged
 			assert.Equal(t, 1, len(statsPaths[0].GetBinlogs()))
+			assert.Equal(t, storage.CompoundStatsType.LogIdx(), path.Base(statsPaths[0].GetBinlogs()[0].GetLogPath()))
 			assert.NotEqual(t, -1, inPaths[0].GetBinlogs()[0].GetTimestampFrom())
 			assert.NotEqual(t, -1, inPaths[0].GetBinlogs()[0].GetTimestampTo())
 		})
@@ -411,9 +602,7 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 				allPaths = append(allPaths, ps)
 			}
 
-			dm := map[interface{}]Timestamp{
-				1: 10000,
-			}
+			dm := int64Deltas(nil)
 
 			ct := &compactionTask{
 				metaCache: metaCache,
@@ -426,7 +615,7 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 					},
 				},
 			}
-			inPaths, statsPaths, numOfRow, err := ct.merge(context.Background(), allPaths, 2, 0, meta, dm)
+			inPaths, statsPaths, numOfRow, _, err := ct.merge(context.Background(), [][][]string{allPaths}, 2, 0, meta, dm, nil)
 			assert.NoError(t, err)
 			assert.Equal(t, int64(101), numOfRow)
 			assert.Equal(t, 2, len(inPaths[0].GetBinlogs()))
@@ -458,9 +647,7 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 				allPaths = append(allPaths, ps)
 			}
 
-			dm := map[interface{}]Timestamp{
-				1: 10000,
-			}
+			dm := int64Deltas(map[int64]Timestamp{1: 10000})
 
 			// 10 days in seconds
 			ct := &compactionTask{
@@ -475,7 +662,7 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 				},
 				done: make(chan struct{}, 1),
 			}
-			inPaths, statsPaths, numOfRow, err := ct.merge(context.Background(), allPaths, 2, 0, meta, dm)
+			inPaths, statsPaths, numOfRow, _, err := ct.merge(context.Background(), [][][]string{allPaths}, 2, 0, meta, dm, nil)
 			assert.NoError(t, err)
 			assert.Equal(t, int64(0), numOfRow)
 			assert.Equal(t, 0, len(inPaths))
@@ -489,6 +676,10 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 			meta := NewMetaFactory().GetCollectionMeta(1, "test", schemapb.DataType_Int64)
 			metaCache := metacache.NewMockMetaCache(t)
 			metaCache.EXPECT().Schema().Return(meta.GetSchema()).Maybe()
+			metaCache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+			metaCache.EXPECT().StorageTenant().Return("").Maybe()
+			metaCache.EXPECT().BinlogCompression().Return("").Maybe()
+			metaCache.EXPECT().DeltalogSorted().Return(false).Maybe()
 			metaCache.EXPECT().GetSegmentByID(mock.Anything).RunAndReturn(func(id int64, filters ...metacache.SegmentFilter) (*metacache.SegmentInfo, bool) {
 				segment := metacache.NewSegmentInfo(&datapb.SegmentInfo{
 					CollectionID: 1,
@@ -514,9 +705,7 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 				allPaths = append(allPaths, ps)
 			}
 
-			dm := map[interface{}]Timestamp{
-				1: 10000,
-			}
+			dm := int64Deltas(map[int64]Timestamp{1: 10000})
 
 			ct := &compactionTask{
 				metaCache: metaCache,
@@ -529,11 +718,12 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 					},
 				},
 			}
-			_, _, _, err = ct.merge(context.Background(), allPaths, 2, 0, &etcdpb.CollectionMeta{
+			// the pk stats are sized by the rows of the batches rather than the rows of the segments in meta
+			_, statsPaths, _, _, err := ct.merge(context.Background(), [][][]string{allPaths}, 2, 0, &etcdpb.CollectionMeta{
 				Schema: meta.GetSchema(),
-			}, dm)
-			assert.Error(t, err)
-			t.Log(err)
+			}, dm, nil)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, statsPaths)
 		})
 
 		t.Run("Merge with meta error", func(t *testing.T) {
@@ -558,9 +748,7 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 				allPaths = append(allPaths, ps)
 			}
 
-			dm := map[interface{}]Timestamp{
-				1: 10000,
-			}
+			dm := int64Deltas(map[int64]Timestamp{1: 10000})
 
 			ct := &compactionTask{
 				metaCache: metaCache,
@@ -573,13 +761,13 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 					},
 				},
 			}
-			_, _, _, err = ct.merge(context.Background(), allPaths, 2, 0, &etcdpb.CollectionMeta{
+			_, _, _, _, err = ct.merge(context.Background(), [][][]string{allPaths}, 2, 0, &etcdpb.CollectionMeta{
 				Schema: &schemapb.CollectionSchema{Fields: []*schemapb.FieldSchema{
 					{DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{
 						{Key: common.DimKey, Value: "64"},
 					}},
 				}},
-			}, dm)
+			}, dm, nil)
 			assert.Error(t, err)
 		})
 
@@ -605,9 +793,7 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 				allPaths = append(allPaths, ps)
 			}
 
-			dm := map[interface{}]Timestamp{
-				1: 10000,
-			}
+			dm := int64Deltas(map[int64]Timestamp{1: 10000})
 
 			ct := &compactionTask{
 				metaCache: metaCache,
@@ -616,13 +802,13 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 				done:      make(chan struct{}, 1),
 			}
 
-			_, _, _, err = ct.merge(context.Background(), allPaths, 2, 0, &etcdpb.CollectionMeta{
+			_, _, _, _, err = ct.merge(context.Background(), [][][]string{allPaths}, 2, 0, &etcdpb.CollectionMeta{
 				Schema: &schemapb.CollectionSchema{Fields: []*schemapb.FieldSchema{
 					{DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{
 						{Key: common.DimKey, Value: "bad_dim"},
 					}},
 				}},
-			}, dm)
+			}, dm, nil)
 			assert.Error(t, err)
 		})
 	})
@@ -724,21 +910,27 @@ func TestCompactionTaskInnerMethods(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 			defer cancel()
 
+			// the compound statslog is uploaded without allocating the log id
 			alloc := allocator.NewMockAllocator(t)
-			alloc.EXPECT().AllocOne().Call.Return(int64(11111), nil)
 
 			meta := f.GetCollectionMeta(UniqueID(10001), "test_upload_remain_log", schemapb.DataType_Int64)
 			stats, err := storage.NewPrimaryKeyStats(106, int64(schemapb.DataType_Int64), 10)
 
 			require.NoError(t, err)
 
+			metaCache := metacache.NewMockMetaCache(t)
+			metaCache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative)
+			metaCache.EXPECT().StorageTenant().Return("").Maybe()
+			metaCache.EXPECT().BinlogCompression().Return("").Maybe()
+			metaCache.EXPECT().DeltalogSorted().Return(false).Maybe()
 			ct := &compactionTask{
+				metaCache: metaCache,
 				binlogIO:  io.NewBinlogIO(&mockCm{errSave: true}, getOrCreateIOPool()),
 				Allocator: alloc,
 				done:      make(chan struct{}, 1),
 			}
 
-			_, _, err = ct.uploadRemainLog(ctx, 1, 2, meta, stats, 10, nil)
+			_, _, err = ct.uploadRemainLog(ctx, 1, 2, meta, []*storage.PrimaryKeyStats{stats}, nil, 10, nil)
 			assert.Error(t, err)
 		})
 	})
@@ -760,6 +952,24 @@ func getInt64DeltaBlobs(segID UniqueID, pks []UniqueID, tss []Timestamp) ([]*Blo
 	return []*Blob{blob}, err
 }
 
+func TestCompareClusteringKey(t *testing.T) {
+	assert.Equal(t, 0, compareClusteringKey(nil, nil))
+	assert.Equal(t, -1, compareClusteringKey(nil, int64(1)))
+	assert.Equal(t, 1, compareClusteringKey(int64(1), nil))
+	assert.Equal(t, -1, compareClusteringKey(int32(1), int32(2)))
+	assert.Equal(t, 1, compareClusteringKey(2.5, 1.5))
+	assert.Equal(t, 0, compareClusteringKey("a", "a"))
+	assert.Equal(t, -1, compareClusteringKey("a", "b"))
+}
+
+func int64Deltas(pk2ts map[int64]Timestamp) *storage.SortedDeleteData {
+	deltaData := &DeleteData{}
+	for pk, ts := range pk2ts {
+		deltaData.Append(storage.NewInt64PrimaryKey(pk), ts)
+	}
+	return storage.NewSortedDeleteData(deltaData)
+}
+
 func TestCompactorInterfaceMethods(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -861,6 +1071,8 @@ func TestCompactorInterfaceMethods(t *testing.T) {
 		for _, c := range cases {
 			collName := "test_compact_coll_name"
 			meta := NewMetaFactory().GetCollectionMeta(c.colID, collName, c.pkType)
+			pkField, err := typeutil.GetPrimaryFieldSchema(meta.GetSchema())
+			require.NoError(t, err)
 
 			mockbIO := io.NewBinlogIO(cm, getOrCreateIOPool())
 			iCodec := storage.NewInsertCodecWithSchema(meta)
@@ -868,6 +1080,10 @@ func TestCompactorInterfaceMethods(t *testing.T) {
 			metaCache := metacache.NewMockMetaCache(t)
 			metaCache.EXPECT().Collection().Return(c.colID)
 			metaCache.EXPECT().Schema().Return(meta.GetSchema())
+			metaCache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+			metaCache.EXPECT().StorageTenant().Return("").Maybe()
+			metaCache.EXPECT().BinlogCompression().Return("").Maybe()
+			metaCache.EXPECT().DeltalogSorted().Return(false).Maybe()
 			syncMgr := syncmgr.NewMockSyncManager(t)
 			syncMgr.EXPECT().Block(mock.Anything).Return()
 
@@ -918,7 +1134,7 @@ func TestCompactorInterfaceMethods(t *testing.T) {
 			require.NoError(t, err)
 			sPaths1, err := uploadStatsLog(context.Background(), mockbIO, alloc, meta.GetID(), c.parID, c.segID1, stats1, 2, iCodec)
 			require.NoError(t, err)
-			dPaths1, err := uploadDeltaLog(context.TODO(), mockbIO, alloc, meta.GetID(), c.parID, c.segID1, dData1)
+			dPaths1, err := uploadDeltaLog(context.TODO(), mockbIO, alloc, meta.GetID(), c.parID, c.segID1, pkField.GetFieldID(), dData1)
 			require.NoError(t, err)
 			require.Equal(t, 12, len(iPaths1))
 
@@ -928,7 +1144,7 @@ func TestCompactorInterfaceMethods(t *testing.T) {
 			require.NoError(t, err)
 			sPaths2, err := uploadStatsLog(context.Background(), mockbIO, alloc, meta.GetID(), c.parID, c.segID2, stats2, 2, iCodec)
 			require.NoError(t, err)
-			dPaths2, err := uploadDeltaLog(context.TODO(), mockbIO, alloc, meta.GetID(), c.parID, c.segID2, dData2)
+			dPaths2, err := uploadDeltaLog(context.TODO(), mockbIO, alloc, meta.GetID(), c.parID, c.segID2, pkField.GetFieldID(), dData2)
 			require.NoError(t, err)
 			require.Equal(t, 12, len(iPaths2))
 
@@ -999,6 +1215,8 @@ func TestCompactorInterfaceMethods(t *testing.T) {
 		alloc.EXPECT().GetGenerator(mock.Anything, mock.Anything).Call.Return(validGeneratorFn, nil)
 
 		meta := NewMetaFactory().GetCollectionMeta(collID, "test_compact_coll_name", schemapb.DataType_Int64)
+		pkField, err := typeutil.GetPrimaryFieldSchema(meta.GetSchema())
+		require.NoError(t, err)
 
 		mockbIO := io.NewBinlogIO(cm, getOrCreateIOPool())
 		iCodec := storage.NewInsertCodecWithSchema(meta)
@@ -1006,6 +1224,10 @@ func TestCompactorInterfaceMethods(t *testing.T) {
 		metaCache := metacache.NewMockMetaCache(t)
 		metaCache.EXPECT().Collection().Return(collID)
 		metaCache.EXPECT().Schema().Return(meta.GetSchema())
+		metaCache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+		metaCache.EXPECT().StorageTenant().Return("").Maybe()
+		metaCache.EXPECT().BinlogCompression().Return("").Maybe()
+		metaCache.EXPECT().DeltalogSorted().Return(false).Maybe()
 		syncMgr := syncmgr.NewMockSyncManager(t)
 		syncMgr.EXPECT().Block(mock.Anything).Return()
 
@@ -1061,7 +1283,7 @@ func TestCompactorInterfaceMethods(t *testing.T) {
 		require.NoError(t, err)
 		sPaths1, err := uploadStatsLog(context.Background(), mockbIO, alloc, meta.GetID(), partID, segID1, stats1, 1, iCodec)
 		require.NoError(t, err)
-		dPaths1, err := uploadDeltaLog(context.TODO(), mockbIO, alloc, meta.GetID(), partID, segID1, dData1)
+		dPaths1, err := uploadDeltaLog(context.TODO(), mockbIO, alloc, meta.GetID(), partID, segID1, pkField.GetFieldID(), dData1)
 		require.NoError(t, err)
 		require.Equal(t, 12, len(iPaths1))
 
@@ -1071,7 +1293,7 @@ func TestCompactorInterfaceMethods(t *testing.T) {
 		require.NoError(t, err)
 		sPaths2, err := uploadStatsLog(context.Background(), mockbIO, alloc, meta.GetID(), partID, segID2, stats2, 1, iCodec)
 		require.NoError(t, err)
-		dPaths2, err := uploadDeltaLog(context.TODO(), mockbIO, alloc, meta.GetID(), partID, segID2, dData2)
+		dPaths2, err := uploadDeltaLog(context.TODO(), mockbIO, alloc, meta.GetID(), partID, segID2, pkField.GetFieldID(), dData2)
 		require.NoError(t, err)
 		require.Equal(t, 12, len(iPaths2))
 
@@ -45,6 +45,7 @@ import (
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/internal/util/dependency"
+	"github.com/milvus-io/milvus/internal/util/diskwatchdog"
 	"github.com/milvus-io/milvus/internal/util/sessionutil"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/metrics"
@@ -392,6 +393,8 @@ func (node *DataNode) Start() error {
 
 		go node.importManager.Start()
 
+		diskwatchdog.Start(typeutil.DataNodeRole)
+
 		if Params.DataNodeCfg.DataNodeTimeTickByRPC.GetAsBool() {
 			node.timeTickSender = newTimeTickSender(node.broker, node.session.ServerID,
 				retry.Attempts(20), retry.Sleep(time.Millisecond*100))
@@ -469,6 +472,7 @@ func (node *DataNode) Stop() error {
 		if node.importManager != nil {
 			node.importManager.Close()
 		}
+		diskwatchdog.Stop()
 
 		node.stopWaiter.Wait()
 	})
@@ -28,6 +28,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/datanode/allocator"
 	"github.com/milvus-io/milvus/internal/datanode/broker"
+	"github.com/milvus-io/milvus/internal/datanode/io"
 	"github.com/milvus-io/milvus/internal/datanode/metacache"
 	"github.com/milvus-io/milvus/internal/datanode/syncmgr"
 	"github.com/milvus-io/milvus/internal/datanode/writebuffer"
@@ -40,6 +41,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/mq/msgdispatcher"
 	"github.com/milvus-io/milvus/pkg/mq/msgstream"
 	"github.com/milvus-io/milvus/pkg/util/conc"
+	"github.com/milvus-io/milvus/pkg/util/retry"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
@@ -126,22 +128,35 @@ func (dsService *dataSyncService) close() {
 
 func getMetaCacheWithTickler(initCtx context.Context, node *DataNode, info *datapb.ChannelWatchInfo, tickler *tickler, unflushed, flushed []*datapb.SegmentInfo, storageV2Cache *metacache.StorageV2Cache) (metacache.MetaCache, error) {
 	tickler.setTotal(int32(len(unflushed) + len(flushed)))
-	return initMetaCache(initCtx, storageV2Cache, node.chunkManager, info, tickler, unflushed, flushed)
+	return initMetaCache(initCtx, node.ctx, storageV2Cache, node.chunkManager, info, tickler, unflushed, flushed)
 }
 
 func getMetaCacheWithEtcdTickler(initCtx context.Context, node *DataNode, info *datapb.ChannelWatchInfo, tickler *etcdTickler, unflushed, flushed []*datapb.SegmentInfo, storageV2Cache *metacache.StorageV2Cache) (metacache.MetaCache, error) {
 	tickler.watch()
 	defer tickler.stop()
 
-	return initMetaCache(initCtx, storageV2Cache, node.chunkManager, info, tickler, unflushed, flushed)
+	return initMetaCache(initCtx, node.ctx, storageV2Cache, node.chunkManager, info, tickler, unflushed, flushed)
 }
 
-func initMetaCache(initCtx context.Context, storageV2Cache *metacache.StorageV2Cache, chunkManager storage.ChunkManager, info *datapb.ChannelWatchInfo, tickler interface{ inc() }, unflushed, flushed []*datapb.SegmentInfo) (metacache.MetaCache, error) {
+// initMetaCache recovers the meta cache of the channel with the segments and their pk stats.
+// If lazy loading enabled, the pk stats of flushed segments are loaded in background within nodeCtx,
+// the lookups of them wait until loaded.
+func initMetaCache(initCtx context.Context, nodeCtx context.Context, storageV2Cache *metacache.StorageV2Cache, chunkManager storage.ChunkManager, info *datapb.ChannelWatchInfo, tickler interface{ inc() }, unflushed, flushed []*datapb.SegmentInfo) (metacache.MetaCache, error) {
 	// tickler will update addSegment progress to watchInfo
 	futures := make([]*conc.Future[any], 0, len(unflushed)+len(flushed))
 	segmentPks := typeutil.NewConcurrentMap[int64, []*storage.PkStatistics]()
+	// the stats binlogs downloaded are kept if the load fails, so the retries of lazy loading
+	// download the failed ones only
+	statsIO := io.NewCachedBinlogIO(io.NewBinlogIO(chunkManager, getOrCreateIOPool()))
 
-	loadSegmentStats := func(segType string, segments []*datapb.SegmentInfo) {
+	loadSegmentStats := func(ctx context.Context, segment *datapb.SegmentInfo) ([]*storage.PkStatistics, error) {
+		if params.Params.CommonCfg.EnableStorageV2.GetAsBool() {
+			return loadStatsV2(storageV2Cache, segment, info.GetSchema())
+		}
+		return loadStats(ctx, statsIO, info.GetSchema(), segment.GetID(), segment.GetStatslogs())
+	}
+
+	recoverSegments := func(segType string, segments []*datapb.SegmentInfo) {
 		for _, item := range segments {
 			log.Info("recover segments from checkpoints",
 				zap.String("vChannelName", item.GetInsertChannel()),
@@ -151,14 +166,9 @@ func initMetaCache(initCtx context.Context, storageV2Cache *metacache.StorageV2C
 			)
 			segment := item
 
-			future := getOrCreateIOPool().Submit(func() (any, error) {
-				var stats []*storage.PkStatistics
-				var err error
-				if params.Params.CommonCfg.EnableStorageV2.GetAsBool() {
-					stats, err = loadStatsV2(storageV2Cache, segment, info.GetSchema())
-				} else {
-					stats, err = loadStats(initCtx, chunkManager, info.GetSchema(), segment.GetID(), segment.GetStatslogs())
-				}
+			// the stats binlogs are downloaded by the io pool, so the segments are loaded by the stats pool
+			future := getOrCreateStatsPool().Submit(func() (any, error) {
+				stats, err := loadSegmentStats(initCtx, segment)
 				if err != nil {
 					return nil, err
 				}
@@ -172,8 +182,16 @@ func initMetaCache(initCtx context.Context, storageV2Cache *metacache.StorageV2C
 		}
 	}
 
-	loadSegmentStats("growing", unflushed)
-	loadSegmentStats("sealed", flushed)
+	lazySets := make(map[int64]*metacache.BloomFilterSet)
+	recoverSegments("growing", unflushed)
+	if params.Params.DataNodeCfg.ChannelLazyLoadStatsEnabled.GetAsBool() {
+		for _, segment := range flushed {
+			lazySets[segment.GetID()] = metacache.NewLazyBloomFilterSet()
+			tickler.inc()
+		}
+	} else {
+		recoverSegments("sealed", flushed)
+	}
 
 	// use fetched segment info
 	info.Vchan.FlushedSegments = flushed
@@ -185,13 +203,50 @@ func initMetaCache(initCtx context.Context, storageV2Cache *metacache.StorageV2C
 
 	// return channel, nil
 	metacache := metacache.NewMetaCache(info, func(segment *datapb.SegmentInfo) *metacache.BloomFilterSet {
+		if bfs, ok := lazySets[segment.GetID()]; ok {
+			return bfs
+		}
 		entries, _ := segmentPks.Get(segment.GetID())
 		return metacache.NewBloomFilterSet(entries...)
 	})
 
+	if len(lazySets) > 0 {
+		go lazyLoadSegmentStats(nodeCtx, flushed, lazySets, loadSegmentStats)
+	}
+
 	return metacache, nil
 }
 
+// lazyLoadSegmentStats loads the pk stats of the segments into their lazy loaded bloom filter sets.
+func lazyLoadSegmentStats(ctx context.Context, segments []*datapb.SegmentInfo, lazySets map[int64]*metacache.BloomFilterSet,
+	loadSegmentStats func(context.Context, *datapb.SegmentInfo) ([]*storage.PkStatistics, error),
+) {
+	log.Info("lazy load segment stats in background", zap.Int("segmentNum", len(segments)))
+	futures := make([]*conc.Future[any], 0, len(segments))
+	for _, item := range segments {
+		segment := item
+		future := getOrCreateStatsPool().Submit(func() (any, error) {
+			err := lazySets[segment.GetID()].Load(func() ([]*storage.PkStatistics, error) {
+				var stats []*storage.PkStatistics
+				err := retry.Do(ctx, func() error {
+					var err error
+					stats, err = loadSegmentStats(ctx, segment)
+					return err
+				})
+				return stats, err
+			})
+			if err != nil {
+				log.Warn("failed to lazy load segment stats, all pks may exist in the segment",
+					zap.Int64("segmentID", segment.GetID()), zap.Error(err))
+			}
+			return struct{}{}, nil
+		})
+		futures = append(futures, future)
+	}
+	_ = conc.AwaitAll(futures...)
+	log.Info("lazy load segment stats done", zap.Int("segmentNum", len(segments)))
+}
+
 func loadStatsV2(storageCache *metacache.StorageV2Cache, segment *datapb.SegmentInfo, schema *schemapb.CollectionSchema) ([]*storage.PkStatistics, error) {
 	space, err := storageCache.GetOrCreateSpace(segment.ID, syncmgr.SpaceCreatorFunc(segment.ID, schema, storageCache.ArrowSchema()))
 	if err != nil {
@@ -243,7 +298,7 @@ func loadStatsV2(storageCache *metacache.StorageV2Cache, segment *datapb.Segment
 	return getResult(stats), nil
 }
 
-func loadStats(ctx context.Context, chunkManager storage.ChunkManager, schema *schemapb.CollectionSchema, segmentID int64, statsBinlogs []*datapb.FieldBinlog) ([]*storage.PkStatistics, error) {
+func loadStats(ctx context.Context, binlogIO io.BinlogIO, schema *schemapb.CollectionSchema, segmentID int64, statsBinlogs []*datapb.FieldBinlog) ([]*storage.PkStatistics, error) {
 	startTs := time.Now()
 	log := log.With(zap.Int64("segmentID", segmentID))
 	log.Info("begin to init pk bloom filter", zap.Int("statsBinLogsLen", len(statsBinlogs)))
@@ -259,6 +314,7 @@ func loadStats(ctx context.Context, chunkManager storage.ChunkManager, schema *s
 
 	// filter stats binlog files which is pk field stats log
 	bloomFilterFiles := []string{}
+	checksum := make(map[string]uint32)
 	logType := storage.DefaultStatsType
 
 	for _, binlog := range statsBinlogs {
@@ -268,6 +324,7 @@ func loadStats(ctx context.Context, chunkManager storage.ChunkManager, schema *s
 	Loop:
 		for _, log := range binlog.GetBinlogs() {
 			_, logidx := path.Split(log.GetLogPath())
+			checksum[log.GetLogPath()] = log.GetChecksum()
 			// if special status log exist
 			// only load one file
 			switch logidx {
@@ -288,11 +345,15 @@ func loadStats(ctx context.Context, chunkManager storage.ChunkManager, schema *s
 	}
 
 	// read historical PK filter
-	values, err := chunkManager.MultiRead(ctx, bloomFilterFiles)
+	values, err := binlogIO.Download(ctx, bloomFilterFiles)
 	if err != nil {
 		log.Warn("failed to load bloom filter files", zap.Error(err))
 		return nil, err
 	}
+	if err := verifyBinlogs(bloomFilterFiles, values, checksum); err != nil {
+		log.Warn("bloom filter files corrupted", zap.Error(err))
+		return nil, err
+	}
 	blobs := make([]*Blob, 0)
 	for i := 0; i < len(values); i++ {
 		blobs = append(blobs, &Blob{Value: values[i]})
@@ -23,11 +23,14 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"path"
 	"testing"
+	"time"
 
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
 
@@ -36,6 +39,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/datanode/allocator"
 	"github.com/milvus-io/milvus/internal/datanode/broker"
+	"github.com/milvus-io/milvus/internal/datanode/io"
 	"github.com/milvus-io/milvus/internal/datanode/metacache"
 	"github.com/milvus-io/milvus/internal/datanode/writebuffer"
 	"github.com/milvus-io/milvus/internal/mocks"
@@ -47,6 +51,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/mq/msgdispatcher"
 	"github.com/milvus-io/milvus/pkg/mq/msgstream"
+	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/tsoutil"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
@@ -302,6 +307,86 @@ func TestGetChannelWithTickler(t *testing.T) {
 	assert.Equal(t, 2, len(metaCache.GetSegmentsBy(metacache.WithSegmentIDs(200, 201), metacache.WithSegmentState(commonpb.SegmentState_Flushed))))
 }
 
+func TestGetChannelWithLazyLoadStats(t *testing.T) {
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.ChannelLazyLoadStatsEnabled.Key, "true")
+	defer paramtable.Get().Reset(paramtable.Get().DataNodeCfg.ChannelLazyLoadStatsEnabled.Key)
+
+	channelName := "by-dev-rootcoord-dml-0"
+	info := getWatchInfoByOpID(100, channelName, datapb.ChannelWatchState_ToWatch)
+	node := newIDLEDataNodeMock(context.Background(), schemapb.DataType_Int64)
+	node.chunkManager = storage.NewLocalChunkManager(storage.RootPath(dataSyncServiceTestDir))
+	defer node.chunkManager.RemoveWithPrefix(context.Background(), node.chunkManager.RootPath())
+
+	meta := NewMetaFactory().GetCollectionMeta(1, "test_collection", schemapb.DataType_Int64)
+	info.Schema = meta.GetSchema()
+	pkField, err := typeutil.GetPrimaryFieldSchema(meta.GetSchema())
+	require.NoError(t, err)
+
+	// the stats log of the flushed segment
+	stats, err := storage.NewPrimaryKeyStats(pkField.GetFieldID(), int64(schemapb.DataType_Int64), 1)
+	require.NoError(t, err)
+	stats.Update(storage.NewInt64PrimaryKey(1))
+	blob, err := storage.NewInsertCodecWithSchema(meta).SerializePkStats(stats, 1)
+	require.NoError(t, err)
+	statsPath := path.Join(node.chunkManager.RootPath(), "stats_log", "200")
+	require.NoError(t, node.chunkManager.Write(context.Background(), statsPath, blob.GetValue()))
+
+	flushed := []*datapb.SegmentInfo{
+		{
+			ID:           200,
+			CollectionID: 1,
+			PartitionID:  10,
+			NumOfRows:    1,
+			State:        commonpb.SegmentState_Flushed,
+			Statslogs: []*datapb.FieldBinlog{{
+				FieldID: pkField.GetFieldID(),
+				Binlogs: []*datapb.Binlog{{LogPath: statsPath}},
+			}},
+		},
+	}
+
+	metaCache, err := getMetaCacheWithTickler(context.TODO(), node, info, newTickler(), nil, flushed, nil)
+	assert.NoError(t, err)
+	segment, ok := metaCache.GetSegmentByID(200)
+	require.True(t, ok)
+
+	// loaded in background
+	assert.Eventually(t, func() bool {
+		return segment.GetBloomFilterSet().IsLoaded()
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.True(t, segment.GetBloomFilterSet().PkExists(storage.NewInt64PrimaryKey(1)))
+	assert.False(t, segment.GetBloomFilterSet().PkExists(storage.NewInt64PrimaryKey(2)))
+}
+
+func TestLoadStatsChecksum(t *testing.T) {
+	cm := storage.NewLocalChunkManager(storage.RootPath(dataSyncServiceTestDir))
+	defer cm.RemoveWithPrefix(context.Background(), cm.RootPath())
+
+	meta := NewMetaFactory().GetCollectionMeta(1, "test_collection", schemapb.DataType_Int64)
+	pkField, err := typeutil.GetPrimaryFieldSchema(meta.GetSchema())
+	require.NoError(t, err)
+
+	stats, err := storage.NewPrimaryKeyStats(pkField.GetFieldID(), int64(schemapb.DataType_Int64), 1)
+	require.NoError(t, err)
+	stats.Update(storage.NewInt64PrimaryKey(1))
+	blob, err := storage.NewInsertCodecWithSchema(meta).SerializePkStats(stats, 1)
+	require.NoError(t, err)
+	statsPath := path.Join(cm.RootPath(), "stats_log", "200")
+	require.NoError(t, cm.Write(context.Background(), statsPath, blob.GetValue()))
+
+	statsBinlogs := []*datapb.FieldBinlog{{
+		FieldID: pkField.GetFieldID(),
+		Binlogs: []*datapb.Binlog{{LogPath: statsPath, Checksum: storage.BinlogChecksum(blob.GetValue())}},
+	}}
+	pkStats, err := loadStats(context.Background(), io.NewBinlogIO(cm, getOrCreateIOPool()), meta.GetSchema(), 200, statsBinlogs)
+	assert.NoError(t, err)
+	assert.Len(t, pkStats, 1)
+
+	statsBinlogs[0].Binlogs[0].Checksum++
+	_, err = loadStats(context.Background(), io.NewBinlogIO(cm, getOrCreateIOPool()), meta.GetSchema(), 200, statsBinlogs)
+	assert.ErrorIs(t, err, merr.ErrIoChecksumMismatch)
+}
+
 type DataSyncServiceSuite struct {
 	suite.Suite
 	MockDataSuiteBase
@@ -189,6 +189,18 @@ func (ddn *ddNode) Operate(in []Msg) []Msg {
 				continue
 			}
 
+			// the corrupted message is still consumed, as it would be consumed again once the channel is recovered,
+			// the mismatch is reported with the proxy producing the message
+			if _, err := msgstream.VerifyInsertChecksum(imsg); err != nil {
+				log.Error("insert message corrupted in the message stream",
+					zap.Int64("msgID", imsg.ID()),
+					zap.Int64("sourceProxy", imsg.SourceID()),
+					zap.Int64("segmentID", imsg.GetSegmentID()),
+					zap.String("vChannelName", ddn.vChannelName),
+					zap.Error(err))
+				metrics.DataNodeInsertChecksumMismatchCount.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), fmt.Sprint(ddn.collectionID)).Inc()
+			}
+
 			rateCol.Add(metricsinfo.InsertConsumeThroughput, float64(proto.Size(&imsg.InsertRequest)))
 
 			metrics.DataNodeConsumeBytesCount.
@@ -233,6 +233,31 @@ func TestFlowGraph_DDNode_Operate(t *testing.T) {
 		assert.Equal(t, 1, len(rt[0].(*flowGraphMsg).insertMessages))
 	})
 
+	t.Run("Test DDNode Operate insert msg with checksum", func(t *testing.T) {
+		ddn := ddNode{
+			ctx:          context.Background(),
+			collectionID: 1,
+		}
+
+		verified := getInsertMsg(100, 10000)
+		verified.RowIDs = []int64{1}
+		verified.Timestamps = []uint64{10000}
+		require.NoError(t, msgstream.SetInsertChecksum(verified))
+
+		corrupted := getInsertMsg(200, 20000)
+		corrupted.RowIDs = []int64{2}
+		corrupted.Timestamps = []uint64{20000}
+		require.NoError(t, msgstream.SetInsertChecksum(corrupted))
+		corrupted.RowIDs[0] = 3
+
+		tsMessages := []msgstream.TsMsg{verified, corrupted}
+		var msgStreamMsg Msg = flowgraph.GenerateMsgStreamMsg(tsMessages, 0, 0, nil, nil)
+
+		// the corrupted message is reported but still consumed
+		rt := ddn.Operate([]Msg{msgStreamMsg})
+		assert.Equal(t, 2, len(rt[0].(*flowGraphMsg).insertMessages))
+	})
+
 	t.Run("Test DDNode Operate Delete Msg", func(t *testing.T) {
 		tests := []struct {
 			ddnCollID   UniqueID
@@ -30,6 +30,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/diskwatchdog"
 	"github.com/milvus-io/milvus/internal/util/importutilv2"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util/conc"
@@ -83,6 +84,11 @@ func (e *executor) Start() {
 			log.Info("import executor exited")
 			return
 		case <-exeTicker.C:
+			if diskwatchdog.IsDegraded() {
+				// pending tasks stay pending until the local disk recovers
+				log.RatedWarn(60, "import is paused, local disk is in degraded mode")
+				continue
+			}
 			tasks := e.manager.GetBy(WithStates(internalpb.ImportState_Pending))
 			wg := &sync.WaitGroup{}
 			for _, task := range tasks {
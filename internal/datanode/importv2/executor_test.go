@@ -41,6 +41,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/diskwatchdog"
 	"github.com/milvus-io/milvus/internal/util/importutilv2"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/util/conc"
@@ -282,6 +283,34 @@ func (s *ExecutorSuite) TestExecutor_Start_Preimport() {
 	}, 10*time.Second, 100*time.Millisecond)
 }
 
+func (s *ExecutorSuite) TestExecutor_Start_DiskDegraded() {
+	// degrade at any disk usage
+	paramtable.Get().Save(paramtable.Get().LocalStorageCfg.Path.Key, s.T().TempDir())
+	defer paramtable.Get().Reset(paramtable.Get().LocalStorageCfg.Path.Key)
+	paramtable.Get().Save(paramtable.Get().LocalStorageCfg.DiskWatchdogHighWatermark.Key, "0")
+	defer paramtable.Get().Reset(paramtable.Get().LocalStorageCfg.DiskWatchdogHighWatermark.Key)
+	diskwatchdog.Start(typeutil.DataNodeRole)
+	defer diskwatchdog.Stop()
+
+	preimportReq := &datapb.PreImportRequest{
+		JobID:        1,
+		TaskID:       2,
+		CollectionID: 3,
+		PartitionIDs: []int64{4},
+		Vchannels:    []string{"ch-0"},
+		Schema:       s.schema,
+		ImportFiles:  []*internalpb.ImportFile{{Paths: []string{"dummy.json"}}},
+	}
+	preimportTask := NewPreImportTask(preimportReq)
+	s.manager.Add(preimportTask)
+
+	go s.executor.Start()
+	defer s.executor.Close()
+	s.Never(func() bool {
+		return s.manager.Get(preimportTask.GetTaskID()).GetState() != internalpb.ImportState_Pending
+	}, 2*time.Second, 100*time.Millisecond)
+}
+
 func (s *ExecutorSuite) TestExecutor_Start_Preimport_Failed() {
 	content := &sampleContent{
 		Rows: make([]sampleRow, 0),
@@ -0,0 +1,121 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/config"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/ratelimitutil"
+)
+
+const bandwidthWaitInterval = 10 * time.Millisecond
+
+var (
+	globalBandwidthLimiter   *bandwidthLimiter
+	bandwidthLimiterInitOnce sync.Once
+)
+
+// bandwidthLimiter throttles the bytes read and written by the binlogIO with separate token buckets,
+// so the flush and compaction of a datanode couldn't saturate the object storage bandwidth.
+// The budgets are shared by all the binlogIO of the datanode, and refreshed on config changes.
+type bandwidthLimiter struct {
+	read  *ratelimitutil.Limiter
+	write *ratelimitutil.Limiter
+}
+
+func getBandwidthLimiter() *bandwidthLimiter {
+	bandwidthLimiterInitOnce.Do(func() {
+		params := paramtable.Get()
+		globalBandwidthLimiter = newBandwidthLimiter(params.DataNodeCfg.ReadBandwidthLimitMB.GetAsFloat(),
+			params.DataNodeCfg.WriteBandwidthLimitMB.GetAsFloat())
+		params.Watch(params.DataNodeCfg.ReadBandwidthLimitMB.Key,
+			config.NewHandler("datanode.io.readBandwidth", updateBandwidthHandler(globalBandwidthLimiter.read)))
+		params.Watch(params.DataNodeCfg.WriteBandwidthLimitMB.Key,
+			config.NewHandler("datanode.io.writeBandwidth", updateBandwidthHandler(globalBandwidthLimiter.write)))
+	})
+	return globalBandwidthLimiter
+}
+
+func newBandwidthLimiter(readLimitMB, writeLimitMB float64) *bandwidthLimiter {
+	l := &bandwidthLimiter{
+		read:  ratelimitutil.NewLimiter(ratelimitutil.Inf, 0),
+		write: ratelimitutil.NewLimiter(ratelimitutil.Inf, 0),
+	}
+	l.read.SetLimit(bandwidthLimit(readLimitMB))
+	l.write.SetLimit(bandwidthLimit(writeLimitMB))
+	return l
+}
+
+// bandwidthLimit returns the limit in bytes per second, a non-positive budget means unlimited.
+func bandwidthLimit(limitMB float64) ratelimitutil.Limit {
+	if limitMB <= 0 {
+		return ratelimitutil.Inf
+	}
+	return ratelimitutil.Limit(limitMB * 1024 * 1024)
+}
+
+// updateBandwidthHandler returns the handler updating the limit of the limiter on config changes.
+func updateBandwidthHandler(limiter *ratelimitutil.Limiter) func(evt *config.Event) {
+	return func(evt *config.Event) {
+		if !evt.HasUpdated {
+			return
+		}
+		log := log.Ctx(context.Background()).With(
+			zap.String("key", evt.Key),
+			zap.String("value", evt.Value),
+		)
+		limitMB, err := strconv.ParseFloat(evt.Value, 64)
+		if err != nil {
+			log.Warn("failed to parse datanode io bandwidth limit", zap.Error(err))
+			return
+		}
+		limiter.SetLimit(bandwidthLimit(limitMB))
+		log.Info("datanode io bandwidth limit updated", zap.Float64("limitMB", limitMB))
+	}
+}
+
+// waitRead charges the bytes read, the size of a read is unknown until it's done,
+// so the reads are throttled by the debt of the former ones.
+func (l *bandwidthLimiter) waitRead(ctx context.Context, size int) error {
+	return wait(ctx, l.read, size)
+}
+
+// waitWrite waits for the budget of the bytes to write.
+func (l *bandwidthLimiter) waitWrite(ctx context.Context, size int) error {
+	return wait(ctx, l.write, size)
+}
+
+// wait blocks until the limiter allows the bytes, the limiter lets the bytes go once it's not in debt,
+// so a request larger than the budget of a second is not blocked forever but punishes the following ones.
+func wait(ctx context.Context, limiter *ratelimitutil.Limiter, size int) error {
+	for !limiter.AllowN(time.Now(), size) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(bandwidthWaitInterval):
+		}
+	}
+	return nil
+}
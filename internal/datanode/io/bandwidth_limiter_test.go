@@ -0,0 +1,69 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/config"
+	"github.com/milvus-io/milvus/pkg/util/ratelimitutil"
+)
+
+func TestBandwidthLimiter(t *testing.T) {
+	ctx := context.Background()
+	l := newBandwidthLimiter(0, 10)
+	assert.Equal(t, ratelimitutil.Inf, l.read.Limit())
+	assert.Equal(t, ratelimitutil.Limit(10*1024*1024), l.write.Limit())
+
+	t.Run("unlimited read", func(t *testing.T) {
+		start := time.Now()
+		for i := 0; i < 10; i++ {
+			assert.NoError(t, l.waitRead(ctx, 100*1024*1024))
+		}
+		assert.Less(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("limited write", func(t *testing.T) {
+		start := time.Now()
+		// the first write goes on debt, the following one waits for it paid off
+		assert.NoError(t, l.waitWrite(ctx, 1024*1024))
+		assert.NoError(t, l.waitWrite(ctx, 1))
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+		assert.NoError(t, l.waitWrite(ctx, 10*1024*1024))
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, l.waitWrite(ctx, 1), context.DeadlineExceeded)
+	})
+
+	t.Run("update limit", func(t *testing.T) {
+		handler := updateBandwidthHandler(l.write)
+		handler(&config.Event{Key: "dataNode.bandwidth.writeLimitMB", Value: "abc", HasUpdated: true})
+		assert.Equal(t, ratelimitutil.Limit(10*1024*1024), l.write.Limit())
+
+		handler(&config.Event{Key: "dataNode.bandwidth.writeLimitMB", Value: "20", HasUpdated: true})
+		assert.Equal(t, ratelimitutil.Limit(20*1024*1024), l.write.Limit())
+
+		handler(&config.Event{Key: "dataNode.bandwidth.writeLimitMB", Value: "0", HasUpdated: true})
+		assert.Equal(t, ratelimitutil.Inf, l.write.Limit())
+		assert.NoError(t, l.waitWrite(ctx, 100*1024*1024))
+	})
+}
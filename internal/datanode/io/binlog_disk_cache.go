@@ -0,0 +1,227 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// binlogDiskCacheSubdir is the subdirectory of the configured directory holding the binlogs cached,
+// which is owned by the cache and cleared on start.
+const binlogDiskCacheSubdir = "binlogs"
+
+var (
+	globalBinlogDiskCache   *binlogDiskCache
+	binlogDiskCacheInitOnce sync.Once
+)
+
+// binlogDiskCache caches the binlogs downloaded on the local disk, so the binlogs read again by the following
+// compactions and the retries are not downloaded again.
+// The binlogs are keyed by the path, as the path of a binlog is never reused by another binlog in the object
+// storage, and the binlog uploaded by the datanode itself is removed from the cache.
+// The least recently used binlogs are evicted once the size cached exceeds the capacity,
+// and the binlogs not accessed within the ttl are expired.
+type binlogDiskCache struct {
+	dir      string
+	capacity int64
+	// ttl is the duration a binlog is kept since last accessed, no expiration if 0
+	ttl time.Duration
+
+	mu   sync.Mutex
+	size int64
+	// lru is the list of the *cachedBinlog, the most recently accessed at the front
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type cachedBinlog struct {
+	path       string
+	file       string
+	size       int64
+	accessedAt time.Time
+}
+
+// getBinlogDiskCache returns the binlog disk cache shared by the binlogIO of the datanode, nil if disabled.
+func getBinlogDiskCache() *binlogDiskCache {
+	binlogDiskCacheInitOnce.Do(func() {
+		params := paramtable.Get()
+		if !params.DataNodeCfg.BinlogCacheEnabled.GetAsBool() {
+			return
+		}
+		dir := params.DataNodeCfg.BinlogCachePath.GetValue()
+		if dir == "" {
+			dir = path.Join(params.LocalStorageCfg.Path.GetValue(), "binlog_cache")
+		}
+		cache, err := newBinlogDiskCache(dir, params.DataNodeCfg.BinlogCacheCapacityMB.GetAsInt64()*1024*1024,
+			params.DataNodeCfg.BinlogCacheTTL.GetAsDuration(time.Second))
+		if err != nil {
+			log.Warn("failed to init binlog disk cache, binlogs are not cached", zap.String("dir", dir), zap.Error(err))
+			return
+		}
+		globalBinlogDiskCache = cache
+	})
+	return globalBinlogDiskCache
+}
+
+// newBinlogDiskCache returns the cache in the binlogs subdirectory of the dir, the binlogs cached before are
+// removed as they're not tracked. Nothing else in the dir is touched.
+func newBinlogDiskCache(dir string, capacity int64, ttl time.Duration) (*binlogDiskCache, error) {
+	dir = path.Join(dir, binlogDiskCacheSubdir)
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	return &binlogDiskCache{
+		dir:      dir,
+		capacity: capacity,
+		ttl:      ttl,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+	}, nil
+}
+
+// fileOf returns the file caching the binlog of the path.
+func (c *binlogDiskCache) fileOf(binlogPath string) string {
+	sum := sha256.Sum256([]byte(binlogPath))
+	return path.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// get returns the binlog of the path cached, false if the cache is nil or the binlog is not cached.
+func (c *binlogDiskCache) get(binlogPath string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	now := time.Now()
+	c.expire(now)
+	elem, ok := c.entries[binlogPath]
+	if !ok {
+		c.mu.Unlock()
+		return nil, false
+	}
+	binlog := elem.Value.(*cachedBinlog)
+	binlog.accessedAt = now
+	c.lru.MoveToFront(elem)
+	c.mu.Unlock()
+
+	// the file may be evicted while reading, which is taken as a miss
+	data, err := os.ReadFile(binlog.file)
+	if err != nil {
+		log.Debug("failed to read cached binlog", zap.String("path", binlogPath), zap.Error(err))
+		return nil, false
+	}
+	return data, true
+}
+
+// put caches the binlog of the path, the least recently used binlogs are evicted beyond the capacity.
+// Nothing is cached if the cache is nil.
+func (c *binlogDiskCache) put(binlogPath string, data []byte) {
+	if c == nil || int64(len(data)) > c.capacity {
+		return
+	}
+	file := c.fileOf(binlogPath)
+	if err := c.writeFile(file, data); err != nil {
+		log.Warn("failed to cache binlog", zap.String("path", binlogPath), zap.Error(err))
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if elem, ok := c.entries[binlogPath]; ok {
+		// the file is rewritten by the same binlog
+		binlog := elem.Value.(*cachedBinlog)
+		binlog.accessedAt = now
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[binlogPath] = c.lru.PushFront(&cachedBinlog{
+		path:       binlogPath,
+		file:       file,
+		size:       int64(len(data)),
+		accessedAt: now,
+	})
+	c.size += int64(len(data))
+	for c.size > c.capacity {
+		c.remove(c.lru.Back())
+	}
+	c.expire(now)
+}
+
+// invalidate removes the binlog of the path from the cache, which is rewritten.
+func (c *binlogDiskCache) invalidate(binlogPath string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[binlogPath]; ok {
+		c.remove(elem)
+	}
+}
+
+// writeFile writes the file by renaming a temp file, so a file partially written is never read.
+func (c *binlogDiskCache) writeFile(file string, data []byte) error {
+	tmp, err := os.CreateTemp(c.dir, "tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), file)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// expire removes the binlogs not accessed within the ttl, which are at the back of the lru list.
+func (c *binlogDiskCache) expire(now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	for elem := c.lru.Back(); elem != nil && now.Sub(elem.Value.(*cachedBinlog).accessedAt) > c.ttl; elem = c.lru.Back() {
+		c.remove(elem)
+	}
+}
+
+func (c *binlogDiskCache) remove(elem *list.Element) {
+	binlog := c.lru.Remove(elem).(*cachedBinlog)
+	delete(c.entries, binlog.path)
+	c.size -= binlog.size
+	if err := os.Remove(binlog.file); err != nil && !os.IsNotExist(err) {
+		log.Warn("failed to remove cached binlog", zap.String("path", binlog.path), zap.Error(err))
+	}
+}
@@ -0,0 +1,154 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/util/conc"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestBinlogDiskCache(t *testing.T) {
+	t.Run("get and put", func(t *testing.T) {
+		c, err := newBinlogDiskCache(t.TempDir(), 1024, 0)
+		require.NoError(t, err)
+
+		_, ok := c.get("a")
+		assert.False(t, ok)
+		c.put("a", []byte{1, 2, 3})
+		data, ok := c.get("a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte{1, 2, 3}, data)
+
+		// the binlog invalidated is removed
+		c.invalidate("a")
+		_, ok = c.get("a")
+		assert.False(t, ok)
+		assert.Empty(t, c.entries)
+		assert.Equal(t, int64(0), c.size)
+	})
+
+	t.Run("evict least recently used", func(t *testing.T) {
+		c, err := newBinlogDiskCache(t.TempDir(), 4, 0)
+		require.NoError(t, err)
+
+		c.put("a", []byte{1, 2})
+		c.put("b", []byte{1})
+		_, ok := c.get("a")
+		assert.True(t, ok)
+		c.put("c", []byte{1, 2})
+		_, ok = c.get("b")
+		assert.False(t, ok)
+		_, ok = c.get("a")
+		assert.True(t, ok)
+		_, ok = c.get("c")
+		assert.True(t, ok)
+		assert.Equal(t, int64(4), c.size)
+
+		// the binlog larger than the capacity is not cached
+		c.put("d", []byte{1, 2, 3, 4, 5})
+		_, ok = c.get("d")
+		assert.False(t, ok)
+
+		files, err := os.ReadDir(c.dir)
+		require.NoError(t, err)
+		assert.Len(t, files, 2)
+	})
+
+	t.Run("expire", func(t *testing.T) {
+		c, err := newBinlogDiskCache(t.TempDir(), 1024, 50*time.Millisecond)
+		require.NoError(t, err)
+
+		c.put("a", []byte{1})
+		_, ok := c.get("a")
+		assert.True(t, ok)
+		time.Sleep(100 * time.Millisecond)
+		_, ok = c.get("a")
+		assert.False(t, ok)
+		assert.Empty(t, c.entries)
+	})
+
+	t.Run("remove previous binlogs", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(path.Join(dir, binlogDiskCacheSubdir), 0o700))
+		require.NoError(t, os.WriteFile(path.Join(dir, binlogDiskCacheSubdir, "a"), []byte{1}, 0o600))
+		// the files not owned by the cache are kept
+		require.NoError(t, os.WriteFile(path.Join(dir, "b"), []byte{1}, 0o600))
+		_, err := newBinlogDiskCache(dir, 1024, 0)
+		require.NoError(t, err)
+		files, err := os.ReadDir(path.Join(dir, binlogDiskCacheSubdir))
+		require.NoError(t, err)
+		assert.Empty(t, files)
+		_, err = os.Stat(path.Join(dir, "b"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("nil cache", func(t *testing.T) {
+		var c *binlogDiskCache
+		c.put("a", []byte{1})
+		_, ok := c.get("a")
+		assert.False(t, ok)
+		c.invalidate("a")
+	})
+}
+
+// countingChunkManager counts the reads of the LocalChunkManager.
+type countingChunkManager struct {
+	*storage.LocalChunkManager
+	reads atomic.Int32
+}
+
+func (cm *countingChunkManager) Read(ctx context.Context, filePath string) ([]byte, error) {
+	cm.reads.Inc()
+	return cm.LocalChunkManager.Read(ctx, filePath)
+}
+
+func TestDownloadDiskCached(t *testing.T) {
+	paramtable.Init()
+	cm := &countingChunkManager{LocalChunkManager: storage.NewLocalChunkManager(storage.RootPath(t.TempDir()))}
+	cache, err := newBinlogDiskCache(t.TempDir(), 1024, 0)
+	require.NoError(t, err)
+	b := &BinlogIoImpl{ChunkManager: cm, pool: conc.NewDefaultPool[any](), diskCache: cache}
+
+	ctx := context.Background()
+	binlogPath := b.JoinFullPath("a/b/c")
+	require.NoError(t, b.Upload(ctx, map[string][]byte{binlogPath: {1, 2, 3}}))
+
+	for i := 0; i < 2; i++ {
+		vs, err := b.Download(ctx, []string{binlogPath})
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{{1, 2, 3}}, vs)
+	}
+	assert.Equal(t, int32(1), cm.reads.Load())
+
+	// the binlog rewritten by the datanode is downloaded again
+	require.NoError(t, b.Upload(ctx, map[string][]byte{binlogPath: {4, 5, 6, 7}}))
+	vs, err := b.Download(ctx, []string{binlogPath})
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{{4, 5, 6, 7}}, vs)
+	assert.Equal(t, int32(2), cm.reads.Load())
+}
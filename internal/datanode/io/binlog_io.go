@@ -19,85 +19,520 @@ package io
 import (
 	"context"
 	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/samber/lo"
-	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/util/conc"
-	"github.com/milvus-io/milvus/pkg/util/retry"
-	"github.com/milvus-io/milvus/pkg/util/typeutil"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
 )
 
+// errCorruptedBinlog marks the binlogs downloaded but failed to decompress.
+var errCorruptedBinlog = errors.New("corrupted binlog")
+
 type BinlogIO interface {
+	// Download downloads the paths in parallel, each path is retried on its own. If any path fails,
+	// the error is returned with the binlogs of the paths downloaded, nil for the failed ones.
 	Download(ctx context.Context, paths []string) ([][]byte, error)
+	// DownloadStream returns the readers of the binlogs reading the binlogs event by event by ranged reads,
+	// the checksums are the ones recorded in segment meta, aligned with the paths, and verified once
+	// the binlogs are all read.
+	DownloadStream(ctx context.Context, paths []string, checksums []uint32) ([]*storage.StreamBinlogReader, error)
+	// Upload uploads the kvs once admitted by the upload scheduler of the datanode,
+	// the upload is queued by the priority of the context, see WithUploadPriority,
+	// and only validated without written if the context is of a dry run, see WithDryRun.
 	Upload(ctx context.Context, kvs map[string][]byte) error
-	// JoinFullPath returns the full path by join the paths with the chunkmanager's rootpath
+	// MultiRemove removes the binlogs of the paths, e.g. the ones superseded by the binlogs uploaded later,
+	// nothing is removed if the context is of a dry run.
+	MultiRemove(ctx context.Context, paths []string) error
+	// JoinFullPath returns the full path by join the paths with the chunkmanager's rootpath,
+	// and the storage tenant if any
 	JoinFullPath(paths ...string) string
 }
 
 type BinlogIoImpl struct {
 	storage.ChunkManager
 	pool *conc.Pool[any]
+	// tenant is the tenant component of the binlog paths, no tenant component if empty
+	tenant string
+	// compression is the codec compressing the insert and delta binlogs on upload, uncompressed if empty
+	compression string
+	// diskCache caches the binlogs downloaded on the local disk, nil if disabled
+	diskCache *binlogDiskCache
 }
 
 func NewBinlogIO(cm storage.ChunkManager, ioPool *conc.Pool[any]) BinlogIO {
-	return &BinlogIoImpl{cm, ioPool}
+	return &BinlogIoImpl{ChunkManager: cm, pool: ioPool, diskCache: getBinlogDiskCache()}
 }
 
-func (b *BinlogIoImpl) Download(ctx context.Context, paths []string) ([][]byte, error) {
-	ctx, span := otel.Tracer(typeutil.DataNodeRole).Start(ctx, "Download")
-	defer span.End()
+// NewTenantBinlogIO returns the BinlogIO which joins the paths of the storage tenant.
+func NewTenantBinlogIO(cm storage.ChunkManager, ioPool *conc.Pool[any], tenant string) BinlogIO {
+	return &BinlogIoImpl{ChunkManager: cm, pool: ioPool, tenant: tenant, diskCache: getBinlogDiskCache()}
+}
+
+// NewCollectionBinlogIO returns the BinlogIO which joins the paths of the storage tenant,
+// and compresses the insert and delta binlogs by the compression of the collection.
+func NewCollectionBinlogIO(cm storage.ChunkManager, ioPool *conc.Pool[any], tenant, compression string) BinlogIO {
+	return &BinlogIoImpl{ChunkManager: cm, pool: ioPool, tenant: tenant, compression: compression, diskCache: getBinlogDiskCache()}
+}
+
+// Download returns the binlogs decompressed, the binlogs uncompressed are returned as is.
+func (b *BinlogIoImpl) Download(ctx context.Context, paths []string) (values [][]byte, err error) {
+	ctx, span := StartIOSpan(ctx, "Download", paths, 0)
+	defer func() {
+		SetIOBytes(span, lo.SumBy(values, func(value []byte) int64 { return int64(len(value)) }))
+		EndIOSpan(span, err)
+	}()
 
 	futures := make([]*conc.Future[any], 0, len(paths))
 	for _, path := range paths {
 		path := path
 		future := b.pool.Submit(func() (any, error) {
-			var val []byte
-			var err error
-
-			log.Debug("BinlogIO download", zap.String("path", path))
-			err = retry.Do(ctx, func() error {
-				val, err = b.Read(ctx, path)
-				if err != nil {
-					log.Warn("BinlogIO fail to download", zap.String("path", path), zap.Error(err))
-				}
+			return b.read(ctx, path)
+		})
+		futures = append(futures, future)
+	}
+
+	// wait for all paths rather than the first failure, so the paths downloaded are returned
+	// and needn't be downloaded again if the download is retried
+	var firstErr error
+	values = make([][]byte, len(futures))
+	for i, future := range futures {
+		value, err := future.Await()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		values[i] = value.([]byte)
+	}
+	return values, firstErr
+}
+
+// read downloads and decompresses a binlog, the latency, retries and the failure of the download are observed.
+// The binlog cached on the local disk is read rather than downloaded.
+func (b *BinlogIoImpl) read(ctx context.Context, path string) (val []byte, err error) {
+	var (
+		labels   = labelsOf(path)
+		start    = time.Now()
+		attempts int
+		cached   bool
+	)
+	ctx, span := StartIOSpan(ctx, "Read", []string{path}, 0)
+	defer func() {
+		observeRequest(metrics.DownloadLabel, labels, start, attempts, err)
+		span.SetAttributes(attribute.Int("attempts", attempts), attribute.Bool("cached", cached))
+		EndIOSpan(span, err)
+	}()
+
+	val, cached = b.diskCache.get(path)
+	if !cached {
+		log.Debug("BinlogIO download", zap.String("path", path))
+		err = Retry(ctx, func(ctx context.Context) error {
+			attempts++
+			val, err = b.Read(ctx, path)
+			if err != nil {
+				log.Warn("BinlogIO fail to download", zap.String("path", path), zap.Error(err))
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		observeBytes(metrics.DownloadLabel, labels, len(val))
+		if err := getBandwidthLimiter().waitRead(ctx, len(val)); err != nil {
+			return nil, err
+		}
+		b.diskCache.put(path, val)
+	}
+	SetIOBytes(span, int64(len(val)))
+
+	val, err = storage.DecompressBinlog(val)
+	if err != nil {
+		log.Warn("BinlogIO fail to decompress", zap.String("path", path), zap.Error(err))
+		return nil, errors.Mark(err, errCorruptedBinlog)
+	}
+	return val, nil
+}
+
+func (b *BinlogIoImpl) DownloadStream(ctx context.Context, paths []string, checksums []uint32) ([]*storage.StreamBinlogReader, error) {
+	ctx, span := StartIOSpan(ctx, "DownloadStream", paths, 0)
+	defer span.End()
+
+	futures := make([]*conc.Future[any], 0, len(paths))
+	for i, path := range paths {
+		path := path
+		var checksum uint32
+		if i < len(checksums) {
+			checksum = checksums[i]
+		}
+		future := b.pool.Submit(func() (any, error) {
+			if mapped, ok := storage.AsMappedReader(b.ChunkManager); ok {
+				return b.mappedStream(ctx, mapped, path, checksum)
+			}
+			var size int64
+			err := Retry(ctx, func(ctx context.Context) error {
+				var err error
+				size, err = b.Size(ctx, path)
 				return err
 			})
-
-			return val, err
+			if err != nil {
+				log.Warn("BinlogIO fail to get binlog size", zap.String("path", path), zap.Error(err))
+				return nil, err
+			}
+			return storage.NewStreamBinlogReader(path, size, checksum, b.rangeReader(ctx, path))
 		})
 		futures = append(futures, future)
 	}
 
 	err := conc.AwaitAll(futures...)
+	readers := lo.FilterMap(futures, func(future *conc.Future[any], _ int) (*storage.StreamBinlogReader, bool) {
+		reader, ok := future.Value().(*storage.StreamBinlogReader)
+		return reader, ok && reader != nil
+	})
 	if err != nil {
+		for _, reader := range readers {
+			reader.Close()
+		}
 		return nil, err
 	}
+	return readers, nil
+}
 
-	return lo.Map(futures, func(future *conc.Future[any], _ int) []byte {
-		return future.Value().([]byte)
-	}), nil
+// mappedStream returns the StreamBinlogReader of the local binlog mapped with the sequential advice, whose ranges
+// are copied from the page cache read ahead, the binlog is released once the reader closed.
+func (b *BinlogIoImpl) mappedStream(ctx context.Context, mapped storage.MappedReader, path string, checksum uint32) (*storage.StreamBinlogReader, error) {
+	file, err := mapped.ReadMapped(ctx, path, storage.MmapAdviceSequential)
+	if err != nil {
+		log.Warn("BinlogIO fail to map binlog", zap.String("path", path), zap.Error(err))
+		return nil, err
+	}
+	labels := labelsOf(path)
+	reader, err := storage.NewStreamBinlogReader(path, int64(file.Len()), checksum, func(off, length int64) ([]byte, error) {
+		val, err := file.ReadAt(off, length)
+		if err == nil {
+			observeBytes(metrics.DownloadLabel, labels, len(val))
+		}
+		return val, err
+	})
+	if err != nil {
+		file.Release()
+		return nil, err
+	}
+	reader.OnClose(func() {
+		if err := file.Release(); err != nil {
+			log.Warn("BinlogIO fail to release mapped binlog", zap.String("path", path), zap.Error(err))
+		}
+	})
+	return reader, nil
 }
 
-func (b *BinlogIoImpl) Upload(ctx context.Context, kvs map[string][]byte) error {
-	ctx, span := otel.Tracer(typeutil.DataNodeRole).Start(ctx, "Upload")
-	defer span.End()
-	future := b.pool.Submit(func() (any, error) {
-		log.Debug("BinlogIO uplaod", zap.Strings("paths", lo.Keys(kvs)))
-		err := retry.Do(ctx, func() error {
-			return b.MultiWrite(ctx, kvs)
+// rangeReader returns the ranged reader of the binlog, each range is read with retries within the read bandwidth budget.
+func (b *BinlogIoImpl) rangeReader(ctx context.Context, path string) storage.RangeReader {
+	labels := labelsOf(path)
+	return func(off, length int64) (val []byte, err error) {
+		var (
+			start    = time.Now()
+			attempts int
+		)
+		ctx, span := StartIOSpan(ctx, "ReadRange", []string{path}, length)
+		defer func() {
+			observeRequest(metrics.DownloadLabel, labels, start, attempts, err)
+			span.SetAttributes(attribute.Int64("offset", off), attribute.Int("attempts", attempts))
+			EndIOSpan(span, err)
+		}()
+
+		err = Retry(ctx, func(ctx context.Context) error {
+			attempts++
+			val, err = b.ReadAt(ctx, path, off, length)
+			if err != nil {
+				log.Warn("BinlogIO fail to read range", zap.String("path", path), zap.Int64("offset", off), zap.Int64("length", length), zap.Error(err))
+			}
+			return err
 		})
+		if err != nil {
+			return nil, err
+		}
+		observeBytes(metrics.DownloadLabel, labels, len(val))
+		if err := getBandwidthLimiter().waitRead(ctx, len(val)); err != nil {
+			return nil, err
+		}
+		return val, nil
+	}
+}
 
-		return nil, err
+// Upload writes the kvs in a single MultiWrite if they are within dataNode.upload.partSize,
+// otherwise the kvs are split into parts of the size and the parts are uploaded concurrently.
+// The insert and delta binlogs are compressed before upload if the compression is set.
+func (b *BinlogIoImpl) Upload(ctx context.Context, kvs map[string][]byte) (err error) {
+	ctx, span := StartIOSpan(ctx, "Upload", lo.Keys(kvs), sizeOf(kvs))
+	defer func() {
+		EndIOSpan(span, err)
+	}()
+
+	report := DryRunOf(ctx)
+	if report != nil {
+		if err = b.validateUpload(kvs); err != nil {
+			return err
+		}
+	}
+
+	kvs, err = b.compress(kvs)
+	if err != nil {
+		return err
+	}
+	if report != nil {
+		report.add(kvs)
+		return nil
+	}
+
+	// the binlogs rewritten are downloaded again
+	for key := range kvs {
+		b.diskCache.invalidate(key)
+	}
+	manifestKey, err := b.beginUpload(ctx, kvs)
+	if err != nil {
+		return err
+	}
+	err = b.upload(ctx, kvs)
+	b.finishUpload(manifestKey, lo.Keys(kvs), err)
+	return err
+}
+
+func (b *BinlogIoImpl) MultiRemove(ctx context.Context, paths []string) error {
+	if DryRunOf(ctx) != nil {
+		return nil
+	}
+	log.Debug("BinlogIO remove", zap.Strings("paths", paths))
+	return Retry(ctx, func(ctx context.Context) error {
+		return b.ChunkManager.MultiRemove(ctx, paths)
 	})
+}
+
+func (b *BinlogIoImpl) upload(ctx context.Context, kvs map[string][]byte) error {
+	partSize := paramtable.Get().DataNodeCfg.UploadPartSize.GetAsInt64()
+	if partSize > 0 {
+		if parts := splitParts(kvs, partSize); len(parts) > 1 {
+			return b.uploadParts(ctx, parts)
+		}
+	}
+
+	log.Debug("BinlogIO uplaod", zap.Strings("paths", lo.Keys(kvs)))
+	return b.scheduleWrite(ctx, kvs)
+}
+
+// beginUpload writes the manifest of the kvs if dataNode.upload.manifestEnabled, the key of the manifest is returned,
+// empty if no manifest is written. A single binlog is written atomically, so it needs no manifest.
+func (b *BinlogIoImpl) beginUpload(ctx context.Context, kvs map[string][]byte) (string, error) {
+	if len(kvs) <= 1 || !paramtable.Get().DataNodeCfg.UploadManifest.GetAsBool() {
+		return "", nil
+	}
+	manifest := &storage.UploadManifest{Keys: lo.Keys(kvs)}
+	sort.Strings(manifest.Keys)
+	key := storage.NewUploadManifestKey(metautil.TenantRootPath(b.ChunkManager.RootPath(), b.tenant), paramtable.GetNodeID())
+	if err := b.writeManifest(ctx, key, manifest); err != nil {
+		log.Warn("BinlogIO fail to write upload manifest", zap.String("manifest", key), zap.Error(err))
+		return "", err
+	}
+	return key, nil
+}
+
+// finishUpload removes the manifest once the kvs are all uploaded, or marks it aborted if the upload failed,
+// so the binlogs uploaded are recycled by the garbage collection at once. The manifest is updated without the context
+// of the upload, as the upload may fail for the context canceled.
+func (b *BinlogIoImpl) finishUpload(manifestKey string, keys []string, uploadErr error) {
+	if manifestKey == "" {
+		return
+	}
+	ctx := context.Background()
+	if uploadErr == nil {
+		if err := b.Remove(ctx, manifestKey); err != nil {
+			log.Warn("BinlogIO fail to remove upload manifest", zap.String("manifest", manifestKey), zap.Error(err))
+		}
+		return
+	}
+	sort.Strings(keys)
+	if err := b.writeManifest(ctx, manifestKey, &storage.UploadManifest{Keys: keys, Aborted: true}); err != nil {
+		log.Warn("BinlogIO fail to abort upload manifest", zap.String("manifest", manifestKey), zap.Error(err))
+	}
+}
 
-	_, err := future.Await()
+func (b *BinlogIoImpl) writeManifest(ctx context.Context, key string, manifest *storage.UploadManifest) error {
+	data, err := manifest.Marshal()
+	if err != nil {
+		return err
+	}
+	return Retry(ctx, func(ctx context.Context) error {
+		return b.Write(ctx, key, data)
+	})
+}
+
+// scheduleWrite writes the kvs in the io pool once the upload is admitted by the upload scheduler,
+// the upload is queued by the priority of the context.
+func (b *BinlogIoImpl) scheduleWrite(ctx context.Context, kvs map[string][]byte) error {
+	release, err := GetUploadScheduler().Acquire(ctx, uploadPriorityOf(ctx), sizeOf(kvs))
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	future := b.pool.Submit(func() (any, error) {
+		return nil, b.write(ctx, kvs)
+	})
+	_, err = future.Await()
 	return err
 }
 
+// write writes the kvs with retries, the latency, retries and the failure of the upload are observed.
+func (b *BinlogIoImpl) write(ctx context.Context, kvs map[string][]byte) (err error) {
+	var (
+		labels   = labelsOfBatch(kvs)
+		start    = time.Now()
+		attempts int
+	)
+	ctx, span := StartIOSpan(ctx, "MultiWrite", lo.Keys(kvs), sizeOf(kvs))
+	defer func() {
+		observeRequest(metrics.UploadLabel, labels, start, attempts, err)
+		span.SetAttributes(attribute.Int("attempts", attempts))
+		EndIOSpan(span, err)
+	}()
+
+	err = Retry(ctx, func(ctx context.Context) error {
+		attempts++
+		return b.multiWrite(ctx, kvs)
+	})
+	if err != nil {
+		return err
+	}
+	for key, value := range kvs {
+		observeBytes(metrics.UploadLabel, labelsOf(key), len(value))
+	}
+	return nil
+}
+
+// multiWrite writes the kvs within the write bandwidth budget, each attempt of a retry is charged as it's sent again.
+func (b *BinlogIoImpl) multiWrite(ctx context.Context, kvs map[string][]byte) error {
+	if err := getBandwidthLimiter().waitWrite(ctx, int(sizeOf(kvs))); err != nil {
+		return err
+	}
+	return b.MultiWrite(ctx, kvs)
+}
+
+// compress returns the kvs with the insert and delta binlogs compressed, the stats logs are left uncompressed.
+func (b *BinlogIoImpl) compress(kvs map[string][]byte) (map[string][]byte, error) {
+	if b.compression == "" || b.compression == common.BinlogCompressionNone {
+		return kvs, nil
+	}
+
+	compressed := make(map[string][]byte, len(kvs))
+	for key, value := range kvs {
+		info, ok := metautil.ParseLogPath(key)
+		if !ok || (info.LogType != common.SegmentInsertLogPath && info.LogType != common.SegmentDeltaLogPath) {
+			compressed[key] = value
+			continue
+		}
+		value, err := storage.CompressBinlog(b.compression, value)
+		if err != nil {
+			return nil, err
+		}
+		compressed[key] = value
+	}
+	return compressed, nil
+}
+
+// uploadParts uploads the parts by at most dataNode.upload.concurrency workers, each part is retried on its own,
+// so a failed part doesn't upload the succeeded ones again.
+func (b *BinlogIoImpl) uploadParts(ctx context.Context, parts []map[string][]byte) error {
+	var totalSize int64
+	for _, part := range parts {
+		totalSize += sizeOf(part)
+	}
+	log := log.Ctx(ctx).With(zap.Int("parts", len(parts)), zap.Int64("totalSize", totalSize))
+	log.Info("BinlogIO upload in parts")
+
+	var (
+		uploadedParts = atomic.NewInt32(0)
+		uploadedSize  = atomic.NewInt64(0)
+	)
+	g, ctx := errgroup.WithContext(ctx)
+	if concurrency := paramtable.Get().DataNodeCfg.UploadConcurrency.GetAsInt(); concurrency > 0 {
+		g.SetLimit(concurrency)
+	}
+	for _, part := range parts {
+		part := part
+		g.Go(func() error {
+			if err := b.scheduleWrite(ctx, part); err != nil {
+				log.Warn("BinlogIO fail to upload part", zap.Strings("paths", lo.Keys(part)), zap.Error(err))
+				return err
+			}
+			log.Debug("BinlogIO uploaded part",
+				zap.Int32("uploadedParts", uploadedParts.Inc()),
+				zap.Int64("uploadedSize", uploadedSize.Add(sizeOf(part))))
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	log.Info("BinlogIO upload in parts done")
+	return nil
+}
+
+// splitParts splits the kvs into parts whose size is no more than maxSize,
+// a value larger than maxSize is a part by itself.
+func splitParts(kvs map[string][]byte, maxSize int64) []map[string][]byte {
+	keys := lo.Keys(kvs)
+	sort.Strings(keys)
+
+	parts := make([]map[string][]byte, 0)
+	var (
+		current     map[string][]byte
+		currentSize int64
+	)
+	for _, key := range keys {
+		size := int64(len(kvs[key]))
+		if current == nil || currentSize+size > maxSize {
+			current = make(map[string][]byte)
+			currentSize = 0
+			parts = append(parts, current)
+		}
+		current[key] = kvs[key]
+		currentSize += size
+	}
+	return parts
+}
+
+func sizeOf(part map[string][]byte) int64 {
+	var size int64
+	for _, value := range part {
+		size += int64(len(value))
+	}
+	return size
+}
+
+// JoinFullPath joins the paths under the root path of the storage tenant,
+// and under the layout dir of the collection if the paths are of a binlog, i.e. "{log type}/{collection id}/...".
 func (b *BinlogIoImpl) JoinFullPath(paths ...string) string {
-	return path.Join(b.ChunkManager.RootPath(), path.Join(paths...))
+	p := path.Join(paths...)
+	rootPath := metautil.TenantRootPath(b.ChunkManager.RootPath(), b.tenant)
+	if parts := strings.SplitN(p, "/", 3); len(parts) >= 2 {
+		if collectionID, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+			rootPath = storage.LayoutRootPath(rootPath, collectionID)
+		}
+	}
+	return path.Join(rootPath, p)
 }
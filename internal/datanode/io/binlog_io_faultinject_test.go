@@ -0,0 +1,122 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build faultinject
+
+package io
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/util/conc"
+	"github.com/milvus-io/milvus/pkg/util/faultinject"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// BinlogIOFaultInjectSuite checks BinlogIO recovers from the storage faults by retrying.
+type BinlogIOFaultInjectSuite struct {
+	suite.Suite
+
+	rootPath string
+	cm       storage.ChunkManager
+	b        BinlogIO
+}
+
+func (s *BinlogIOFaultInjectSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (s *BinlogIOFaultInjectSuite) SetupTest() {
+	s.rootPath = s.T().TempDir()
+	s.cm = storage.NewFaultInjectChunkManager(storage.NewLocalChunkManager(storage.RootPath(s.rootPath)))
+	s.b = NewBinlogIO(s.cm, conc.NewDefaultPool[any]())
+}
+
+func (s *BinlogIOFaultInjectSuite) TearDownTest() {
+	faultinject.Reset()
+}
+
+func (s *BinlogIOFaultInjectSuite) TestUploadPartialFailure() {
+	kvs := map[string][]byte{
+		path.Join(s.rootPath, "a/b/c"): {1, 255, 255},
+		path.Join(s.rootPath, "a/b/d"): {1, 255, 255},
+		path.Join(s.rootPath, "a/b/e"): {1, 255, 255},
+	}
+	faultinject.Enable(faultinject.ChunkManagerMultiWrite, faultinject.Fault{Err: errors.New("mock error"), Skip: 1, Times: 2})
+
+	ctx := context.Background()
+	s.NoError(s.b.Upload(ctx, kvs))
+	s.Equal(2, faultinject.Triggered(faultinject.ChunkManagerMultiWrite))
+
+	vs, err := s.b.Download(ctx, lo.Keys(kvs))
+	s.NoError(err)
+	s.ElementsMatch(lo.Values(kvs), vs)
+}
+
+func (s *BinlogIOFaultInjectSuite) TestUploadPartFailure() {
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.UploadPartSize.Key, "3")
+	defer paramtable.Get().Reset(paramtable.Get().DataNodeCfg.UploadPartSize.Key)
+
+	kvs := map[string][]byte{
+		path.Join(s.rootPath, "a/b/c"): {1, 255, 255},
+		path.Join(s.rootPath, "a/b/d"): {1, 255, 255},
+		path.Join(s.rootPath, "a/b/e"): {1, 255, 255},
+	}
+	// only the failed part is uploaded again
+	faultinject.Enable(faultinject.ChunkManagerMultiWrite, faultinject.Fault{Err: errors.New("mock error"), Times: 1})
+
+	ctx := context.Background()
+	s.NoError(s.b.Upload(ctx, kvs))
+	s.Equal(1, faultinject.Triggered(faultinject.ChunkManagerMultiWrite))
+
+	vs, err := s.b.Download(ctx, lo.Keys(kvs))
+	s.NoError(err)
+	s.ElementsMatch(lo.Values(kvs), vs)
+}
+
+func (s *BinlogIOFaultInjectSuite) TestDownloadFailure() {
+	kvs := map[string][]byte{
+		path.Join(s.rootPath, "a/b/c"): {1, 255, 255},
+		path.Join(s.rootPath, "a/b/d"): {1, 255, 255},
+	}
+	ctx := context.Background()
+	s.Require().NoError(s.b.Upload(ctx, kvs))
+
+	faultinject.Enable(faultinject.ChunkManagerRead, faultinject.Fault{Err: errors.New("mock error"), Times: 3})
+	vs, err := s.b.Download(ctx, lo.Keys(kvs))
+	s.NoError(err)
+	s.ElementsMatch(lo.Values(kvs), vs)
+	s.Equal(3, faultinject.Triggered(faultinject.ChunkManagerRead))
+
+	// unrecoverable after the retries run out
+	faultinject.Enable(faultinject.ChunkManagerRead, faultinject.Fault{Err: errors.New("mock error")})
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	_, err = s.b.Download(ctx, lo.Keys(kvs))
+	s.Error(err)
+}
+
+func TestBinlogIOFaultInject(t *testing.T) {
+	suite.Run(t, new(BinlogIOFaultInjectSuite))
+}
@@ -0,0 +1,114 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// binlogLabels are the collection and the binlog type labels of the binlog io metrics.
+type binlogLabels struct {
+	collectionID string
+	binlogType   string
+}
+
+var binlogTypeLabels = map[string]string{
+	common.SegmentInsertLogPath: metrics.InsertBinlogLabel,
+	common.SegmentDeltaLogPath:  metrics.DeltaBinlogLabel,
+	common.SegmentStatslogPath:  metrics.StatsBinlogLabel,
+}
+
+// labelsOf parses the labels from the binlog path, i.e. {root}/[tenant=xxx/]{binlog type}/{collection id}/...
+func labelsOf(path string) binlogLabels {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if binlogType, ok := binlogTypeLabels[part]; ok && i+1 < len(parts) {
+			return binlogLabels{collectionID: parts[i+1], binlogType: binlogType}
+		}
+	}
+	return binlogLabels{binlogType: metrics.UnknownBinlogLabel}
+}
+
+// labelsOfBatch returns the labels shared by the binlogs written in a request,
+// the label is metrics.AllLabel if the binlogs differ in it.
+func labelsOfBatch(kvs map[string][]byte) binlogLabels {
+	var labels *binlogLabels
+	for key := range kvs {
+		l := labelsOf(key)
+		if labels == nil {
+			labels = &l
+			continue
+		}
+		if labels.collectionID != l.collectionID {
+			labels.collectionID = metrics.AllLabel
+		}
+		if labels.binlogType != l.binlogType {
+			labels.binlogType = metrics.AllLabel
+		}
+	}
+	if labels == nil {
+		return binlogLabels{binlogType: metrics.UnknownBinlogLabel}
+	}
+	return *labels
+}
+
+// failureCauseOf classifies the error of a binlog io request.
+func failureCauseOf(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return metrics.CanceledCauseLabel
+	case errors.Is(err, context.DeadlineExceeded):
+		return metrics.TimeoutCauseLabel
+	case errors.Is(err, merr.ErrIoKeyNotFound):
+		return metrics.NotFoundCauseLabel
+	case errors.Is(err, merr.ErrIoChecksumMismatch), errors.Is(err, errCorruptedBinlog):
+		return metrics.CorruptedCauseLabel
+	default:
+		return metrics.StorageCauseLabel
+	}
+}
+
+// observeBytes counts the bytes transferred of a binlog.
+func observeBytes(op string, labels binlogLabels, size int) {
+	metrics.DataNodeBinlogIOBytes.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), op, labels.collectionID, labels.binlogType).
+		Add(float64(size))
+}
+
+// observeRequest observes the latency and the retries of a binlog io request, and the failure cause if it failed.
+func observeRequest(op string, labels binlogLabels, start time.Time, attempts int, err error) {
+	nodeID := fmt.Sprint(paramtable.GetNodeID())
+	metrics.DataNodeBinlogIOLatency.WithLabelValues(nodeID, op, labels.collectionID, labels.binlogType).
+		Observe(float64(time.Since(start).Milliseconds()))
+	if attempts > 1 {
+		metrics.DataNodeBinlogIORetryCount.WithLabelValues(nodeID, op, labels.collectionID, labels.binlogType).
+			Add(float64(attempts - 1))
+	}
+	if err != nil {
+		metrics.DataNodeBinlogIOFailureCount.WithLabelValues(nodeID, op, labels.collectionID, labels.binlogType, failureCauseOf(err)).
+			Inc()
+	}
+}
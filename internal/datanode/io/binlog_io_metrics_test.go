@@ -0,0 +1,100 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/conc"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestBinlogLabels(t *testing.T) {
+	assert.Equal(t, binlogLabels{collectionID: "1", binlogType: metrics.InsertBinlogLabel},
+		labelsOf("files/insert_log/1/10/100/101/1000"))
+	assert.Equal(t, binlogLabels{collectionID: "1", binlogType: metrics.DeltaBinlogLabel},
+		labelsOf("files/tenant=tenant1/delta_log/1/10/100/1000"))
+	assert.Equal(t, binlogLabels{collectionID: "1", binlogType: metrics.StatsBinlogLabel},
+		labelsOf("files/stats_log/1/10/100/101/1000"))
+	assert.Equal(t, binlogLabels{binlogType: metrics.UnknownBinlogLabel}, labelsOf("files/a/b"))
+
+	assert.Equal(t, binlogLabels{collectionID: "1", binlogType: metrics.InsertBinlogLabel}, labelsOfBatch(map[string][]byte{
+		"files/insert_log/1/10/100/101/1000": nil,
+		"files/insert_log/1/10/100/102/1001": nil,
+	}))
+	assert.Equal(t, binlogLabels{collectionID: "1", binlogType: metrics.AllLabel}, labelsOfBatch(map[string][]byte{
+		"files/insert_log/1/10/100/101/1000": nil,
+		"files/stats_log/1/10/100/101/1001":  nil,
+	}))
+	assert.Equal(t, binlogLabels{collectionID: metrics.AllLabel, binlogType: metrics.DeltaBinlogLabel}, labelsOfBatch(map[string][]byte{
+		"files/delta_log/1/10/100/1000": nil,
+		"files/delta_log/2/20/200/2000": nil,
+	}))
+	assert.Equal(t, binlogLabels{binlogType: metrics.UnknownBinlogLabel}, labelsOfBatch(nil))
+}
+
+func TestFailureCause(t *testing.T) {
+	assert.Equal(t, metrics.CanceledCauseLabel, failureCauseOf(errors.Wrap(context.Canceled, "download")))
+	assert.Equal(t, metrics.TimeoutCauseLabel, failureCauseOf(context.DeadlineExceeded))
+	assert.Equal(t, metrics.NotFoundCauseLabel, failureCauseOf(merr.WrapErrIoKeyNotFound("a/b")))
+	assert.Equal(t, metrics.CorruptedCauseLabel, failureCauseOf(errors.Mark(errors.New("bad header"), errCorruptedBinlog)))
+	assert.Equal(t, metrics.StorageCauseLabel, failureCauseOf(merr.WrapErrIoFailed("a/b", errors.New("mock"))))
+}
+
+func TestBinlogIOMetrics(t *testing.T) {
+	paramtable.Init()
+	cm := storage.NewLocalChunkManager(storage.RootPath(t.TempDir()))
+	b := NewBinlogIO(cm, conc.NewDefaultPool[any]())
+	nodeID := fmt.Sprint(paramtable.GetNodeID())
+	ctx := context.Background()
+
+	deltaPath := b.JoinFullPath(common.SegmentDeltaLogPath, "9527/10/100/1000")
+	labels := []string{nodeID, metrics.UploadLabel, "9527", metrics.DeltaBinlogLabel}
+	uploaded := testutil.ToFloat64(metrics.DataNodeBinlogIOBytes.WithLabelValues(labels...))
+	assert.NoError(t, b.Upload(ctx, map[string][]byte{deltaPath: {1, 2, 3}}))
+	assert.Equal(t, uploaded+3, testutil.ToFloat64(metrics.DataNodeBinlogIOBytes.WithLabelValues(labels...)))
+
+	labels = []string{nodeID, metrics.DownloadLabel, "9527", metrics.DeltaBinlogLabel}
+	downloaded := testutil.ToFloat64(metrics.DataNodeBinlogIOBytes.WithLabelValues(labels...))
+	_, err := b.Download(ctx, []string{deltaPath})
+	assert.NoError(t, err)
+	assert.Equal(t, downloaded+3, testutil.ToFloat64(metrics.DataNodeBinlogIOBytes.WithLabelValues(labels...)))
+
+	// the missing binlog is retried until the deadline is too close for another attempt
+	missingPath := path.Join(cm.RootPath(), common.SegmentDeltaLogPath, "9527/10/100/1001")
+	notFound := metrics.DataNodeBinlogIOFailureCount.WithLabelValues(append(labels, metrics.NotFoundCauseLabel)...)
+	failed := testutil.ToFloat64(notFound)
+	retried := testutil.ToFloat64(metrics.DataNodeBinlogIORetryCount.WithLabelValues(labels...))
+	ctx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	_, err = b.Download(ctx, []string{missingPath})
+	assert.ErrorIs(t, err, merr.ErrIoKeyNotFound)
+	assert.Equal(t, failed+1, testutil.ToFloat64(notFound))
+	assert.Greater(t, testutil.ToFloat64(metrics.DataNodeBinlogIORetryCount.WithLabelValues(labels...)), retried)
+}
@@ -1,15 +1,21 @@
 package io
 
 import (
+	"fmt"
 	"path"
 	"testing"
+	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/suite"
 	"golang.org/x/net/context"
 
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/util/conc"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
 )
 
 const binlogIOTestDir = "/tmp/milvus_test/binlog_io"
@@ -25,6 +31,10 @@ type BinlogIOSuite struct {
 	b  BinlogIO
 }
 
+func (s *BinlogIOSuite) SetupSuite() {
+	paramtable.Init()
+}
+
 func (s *BinlogIOSuite) SetupTest() {
 	pool := conc.NewDefaultPool[any]()
 
@@ -53,6 +63,330 @@ func (s *BinlogIOSuite) TestUploadDownload() {
 	s.ElementsMatch(lo.Values(kvs), vs)
 }
 
+func (s *BinlogIOSuite) TestUploadInParts() {
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.UploadPartSize.Key, "4")
+	defer paramtable.Get().Reset(paramtable.Get().DataNodeCfg.UploadPartSize.Key)
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.UploadConcurrency.Key, "2")
+	defer paramtable.Get().Reset(paramtable.Get().DataNodeCfg.UploadConcurrency.Key)
+
+	kvs := map[string][]byte{
+		path.Join(binlogIOTestDir, "a/b/c"): {1, 255},
+		path.Join(binlogIOTestDir, "a/b/d"): {1, 255},
+		path.Join(binlogIOTestDir, "a/b/e"): {1, 255, 255, 255, 255},
+		path.Join(binlogIOTestDir, "a/b/f"): {1},
+	}
+
+	ctx := context.Background()
+	s.NoError(s.b.Upload(ctx, kvs))
+
+	vs, err := s.b.Download(ctx, lo.Keys(kvs))
+	s.NoError(err)
+	s.ElementsMatch(lo.Values(kvs), vs)
+}
+
+func (s *BinlogIOSuite) TestUploadManifest() {
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.UploadManifest.Key, "true")
+	defer paramtable.Get().Reset(paramtable.Get().DataNodeCfg.UploadManifest.Key)
+	ctx := context.Background()
+	manifestPrefix := storage.UploadManifestPrefix(s.cm.RootPath())
+	s.Require().NoError(s.cm.RemoveWithPrefix(ctx, manifestPrefix))
+	defer s.cm.RemoveWithPrefix(ctx, manifestPrefix)
+
+	// the manifest is removed once the binlogs are all uploaded
+	kvs := map[string][]byte{
+		path.Join(binlogIOTestDir, "a/b/c"): {1, 255, 255},
+		path.Join(binlogIOTestDir, "a/b/d"): {1, 255, 255},
+	}
+	s.NoError(s.b.Upload(ctx, kvs))
+	manifests, _, err := s.cm.ListWithPrefix(ctx, manifestPrefix, true)
+	s.NoError(err)
+	s.Empty(manifests)
+
+	// the manifest is marked aborted if the upload failed, a/b/c is a file so a/b/c/e fails
+	kvs = map[string][]byte{
+		path.Join(binlogIOTestDir, "a/b/e"):   {1, 255, 255},
+		path.Join(binlogIOTestDir, "a/b/c/e"): {1, 255, 255},
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	s.Error(s.b.Upload(timeoutCtx, kvs))
+	manifests, _, err = s.cm.ListWithPrefix(ctx, manifestPrefix, true)
+	s.NoError(err)
+	s.Require().Len(manifests, 1)
+	data, err := s.cm.Read(ctx, manifests[0])
+	s.NoError(err)
+	manifest, err := storage.UnmarshalUploadManifest(data)
+	s.NoError(err)
+	s.True(manifest.Aborted)
+	s.ElementsMatch(lo.Keys(kvs), manifest.Keys)
+}
+
+func (s *BinlogIOSuite) TestUploadDownloadCompressed() {
+	b := NewCollectionBinlogIO(s.cm, conc.NewDefaultPool[any](), "", common.BinlogCompressionZstd)
+
+	dData := &storage.DeleteData{}
+	for i := int64(0); i < 100; i++ {
+		dData.Append(storage.NewInt64PrimaryKey(i), uint64(i+1))
+	}
+	blob, err := storage.NewDeleteCodec().Serialize(1, 10, 100, dData)
+	s.Require().NoError(err)
+
+	deltaPath := b.JoinFullPath(common.SegmentDeltaLogPath, "1/10/100/1")
+	statsPath := b.JoinFullPath(common.SegmentStatslogPath, "1/10/100/101/1")
+	kvs := map[string][]byte{
+		deltaPath: blob.GetValue(),
+		statsPath: {1, 255, 255},
+	}
+
+	ctx := context.Background()
+	s.NoError(b.Upload(ctx, kvs))
+
+	// the delta binlog is compressed in storage, the stats log is not
+	raw, err := s.cm.Read(ctx, deltaPath)
+	s.NoError(err)
+	s.True(storage.IsCompressedBinlog(raw))
+	s.Less(len(raw), len(blob.GetValue()))
+	raw, err = s.cm.Read(ctx, statsPath)
+	s.NoError(err)
+	s.Equal(kvs[statsPath], raw)
+
+	vs, err := b.Download(ctx, []string{deltaPath, statsPath})
+	s.NoError(err)
+	s.Equal([][]byte{blob.GetValue(), kvs[statsPath]}, vs)
+
+	// the uncompressed binlogs are still downloaded by the BinlogIO compressing
+	s.NoError(s.b.Upload(ctx, kvs))
+	vs, err = b.Download(ctx, []string{deltaPath})
+	s.NoError(err)
+	s.Equal([][]byte{blob.GetValue()}, vs)
+
+	b = NewCollectionBinlogIO(s.cm, conc.NewDefaultPool[any](), "", "gzip")
+	s.Error(b.Upload(ctx, kvs))
+}
+
+func (s *BinlogIOSuite) TestUploadDryRun() {
+	b := NewCollectionBinlogIO(s.cm, conc.NewDefaultPool[any](), "", common.BinlogCompressionZstd)
+
+	dData := &storage.DeleteData{}
+	for i := int64(0); i < 100; i++ {
+		dData.Append(storage.NewInt64PrimaryKey(i), uint64(i+1))
+	}
+	blob, err := storage.NewDeleteCodec().Serialize(1, 10, 100, dData)
+	s.Require().NoError(err)
+
+	deltaPath := b.JoinFullPath(common.SegmentDeltaLogPath, "1/10/100/2")
+	statsPath := b.JoinFullPath(common.SegmentStatslogPath, "1/10/100/101/2")
+	kvs := map[string][]byte{
+		deltaPath: blob.GetValue(),
+		statsPath: {1, 255, 255},
+	}
+
+	report := NewDryRunReport()
+	ctx := WithDryRun(context.Background(), report)
+	s.NoError(b.Upload(ctx, kvs))
+
+	// the binlogs are sized as written, the delta binlog compressed, but nothing is written
+	binlogs := report.Binlogs()
+	s.ElementsMatch([]string{deltaPath, statsPath}, lo.Keys(binlogs))
+	s.Less(binlogs[deltaPath], int64(len(blob.GetValue())))
+	s.EqualValues(3, binlogs[statsPath])
+	s.Equal(binlogs[deltaPath]+3, report.Size())
+	for _, key := range []string{deltaPath, statsPath} {
+		exist, err := s.cm.Exist(context.Background(), key)
+		s.NoError(err)
+		s.False(exist)
+	}
+
+	// the paths not joined by JoinFullPath
+	s.Error(b.Upload(ctx, map[string][]byte{path.Join(binlogIOTestDir, "a/b/c"): {1}}))
+	s.Error(b.Upload(ctx, map[string][]byte{path.Join(binlogIOTestDir, "other", common.SegmentStatslogPath, "1/10/100/101/2"): {1}}))
+	// the binlog of another segment
+	s.Error(b.Upload(ctx, map[string][]byte{b.JoinFullPath(common.SegmentDeltaLogPath, "1/10/200/2"): blob.GetValue()}))
+	// the unreadable binlog
+	s.Error(b.Upload(ctx, map[string][]byte{deltaPath: {1, 255, 255}}))
+	s.Equal(2, len(report.Binlogs()))
+}
+
+func (s *BinlogIOSuite) TestDownloadStream() {
+	// the local binlogs are mapped unless mmap reads disabled
+	for _, mmapRead := range []string{"true", "false"} {
+		s.Run("mmapRead="+mmapRead, func() {
+			params := paramtable.Get()
+			params.Save(params.LocalStorageCfg.MmapReadEnabled.Key, mmapRead)
+			defer params.Reset(params.LocalStorageCfg.MmapReadEnabled.Key)
+			s.testDownloadStream()
+		})
+	}
+}
+
+func (s *BinlogIOSuite) testDownloadStream() {
+	iData := &storage.InsertData{Data: map[int64]storage.FieldData{
+		0:   &storage.Int64FieldData{Data: []int64{1, 2, 3}},
+		1:   &storage.Int64FieldData{Data: []int64{1, 2, 3}},
+		100: &storage.Int64FieldData{Data: []int64{4, 5, 6}},
+	}}
+	kvs := make(map[string][]byte)
+	paths := make([]string, 0)
+	checksums := make([]uint32, 0)
+	for _, fieldID := range []int64{0, 1, 100} {
+		writer := storage.NewInsertBinlogWriter(schemapb.DataType_Int64, 1, 10, 100, fieldID)
+		eventWriter, err := writer.NextInsertEventWriter()
+		s.Require().NoError(err)
+		s.Require().NoError(eventWriter.AddInt64ToPayload(iData.Data[fieldID].(*storage.Int64FieldData).Data))
+		eventWriter.SetEventTimestamp(1, 1)
+		writer.SetEventTimeStamp(1, 1)
+		writer.AddExtra("original_size", "24")
+		s.Require().NoError(writer.Finish())
+		value, err := writer.GetBuffer()
+		s.Require().NoError(err)
+		writer.Close()
+
+		path := s.b.JoinFullPath(common.SegmentInsertLogPath, fmt.Sprintf("1/10/100/%d/1", fieldID))
+		kvs[path] = value
+		paths = append(paths, path)
+		checksums = append(checksums, storage.BinlogChecksum(value))
+	}
+
+	ctx := context.Background()
+	s.Require().NoError(s.b.Upload(ctx, kvs))
+
+	readers, err := s.b.DownloadStream(ctx, paths, checksums)
+	s.Require().NoError(err)
+	itr := storage.NewStreamInsertEventIterator(readers, 100, schemapb.DataType_Int64)
+	defer itr.Dispose()
+	pks := make([]int64, 0)
+	for itr.HasNext() {
+		v, err := itr.Next()
+		s.Require().NoError(err)
+		pks = append(pks, v.(*storage.Value).PK.GetValue().(int64))
+	}
+	s.Equal([]int64{4, 5, 6}, pks)
+
+	ctx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	_, err = s.b.DownloadStream(ctx, []string{paths[0], s.b.JoinFullPath("not_exist")}, nil)
+	s.Error(err)
+}
+
+func (s *BinlogIOSuite) TestPrefetch() {
+	ctx := context.Background()
+	kvs := make(map[string][]byte)
+	batches := make([][]string, 0)
+	checksums := make(map[string]uint32)
+	for logID := int64(1); logID <= 3; logID++ {
+		paths := make([]string, 0)
+		for _, fieldID := range []int64{0, 1, 100} {
+			writer := storage.NewInsertBinlogWriter(schemapb.DataType_Int64, 1, 10, 100, fieldID)
+			eventWriter, err := writer.NextInsertEventWriter()
+			s.Require().NoError(err)
+			s.Require().NoError(eventWriter.AddInt64ToPayload([]int64{logID * 10, logID*10 + 1}))
+			eventWriter.SetEventTimestamp(1, 1)
+			writer.SetEventTimeStamp(1, 1)
+			writer.AddExtra("original_size", "16")
+			s.Require().NoError(writer.Finish())
+			value, err := writer.GetBuffer()
+			s.Require().NoError(err)
+			writer.Close()
+
+			path := s.b.JoinFullPath(common.SegmentInsertLogPath, fmt.Sprintf("1/10/100/%d/%d", fieldID, logID))
+			kvs[path] = value
+			paths = append(paths, path)
+			checksums[path] = storage.BinlogChecksum(value)
+		}
+		batches = append(batches, paths)
+	}
+	s.Require().NoError(s.b.Upload(ctx, kvs))
+	defer s.cm.RemoveWithPrefix(ctx, s.cm.RootPath())
+
+	prefetcher := NewPrefetcher(ctx, s.b, batches, checksums, 1)
+	pks := make([]int64, 0)
+	for {
+		readers, err := prefetcher.Next()
+		if errors.Is(err, storage.ErrNoMoreRecord) {
+			break
+		}
+		s.Require().NoError(err)
+		itr := storage.NewStreamInsertEventIterator(readers, 100, schemapb.DataType_Int64)
+		for itr.HasNext() {
+			v, err := itr.Next()
+			s.Require().NoError(err)
+			pks = append(pks, v.(*storage.Value).PK.GetValue().(int64))
+		}
+		itr.Dispose()
+	}
+	prefetcher.Close()
+	s.Equal([]int64{10, 11, 20, 21, 30, 31}, pks)
+
+	// closed without consumed
+	prefetcher = NewPrefetcher(ctx, s.b, batches, checksums, 2)
+	prefetcher.Close()
+
+	// the batches after the failed one aren't downloaded
+	failed := [][]string{{s.b.JoinFullPath("not_exist")}, batches[0]}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	prefetcher = NewPrefetcher(timeoutCtx, s.b, failed, checksums, 2)
+	defer prefetcher.Close()
+	_, err := prefetcher.Next()
+	s.Error(err)
+	_, err = prefetcher.Next()
+	s.Error(err)
+}
+
+func (s *BinlogIOSuite) TestDownloadPartially() {
+	existPath := path.Join(binlogIOTestDir, "partial/a")
+	missingPath := path.Join(binlogIOTestDir, "partial/b")
+	ctx := context.Background()
+	s.Require().NoError(s.cm.Remove(ctx, missingPath))
+	s.Require().NoError(s.b.Upload(ctx, map[string][]byte{existPath: {1, 255, 255}}))
+
+	ctx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	vs, err := s.b.Download(ctx, []string{existPath, missingPath})
+	s.Error(err)
+	s.Equal([][]byte{{1, 255, 255}, nil}, vs)
+}
+
+func (s *BinlogIOSuite) TestCachedDownload() {
+	cachedPath := path.Join(binlogIOTestDir, "cached/a")
+	missingPath := path.Join(binlogIOTestDir, "cached/b")
+	ctx := context.Background()
+	s.Require().NoError(s.cm.Remove(ctx, missingPath))
+	s.Require().NoError(s.b.Upload(ctx, map[string][]byte{cachedPath: {1, 255, 255}}))
+
+	b := NewCachedBinlogIO(s.b)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	_, err := b.Download(timeoutCtx, []string{cachedPath, missingPath})
+	s.Error(err)
+
+	// the binlog downloaded is served by the cache rather than downloaded again
+	s.Require().NoError(s.cm.Remove(ctx, cachedPath))
+	s.Require().NoError(s.b.Upload(ctx, map[string][]byte{missingPath: {2}}))
+	vs, err := b.Download(ctx, []string{cachedPath, missingPath})
+	s.NoError(err)
+	s.Equal([][]byte{{1, 255, 255}, {2}}, vs)
+	s.Empty(b.(*cachedBinlogIO).cache)
+}
+
+func (s *BinlogIOSuite) TestSplitParts() {
+	kvs := map[string][]byte{
+		"a": {1, 2},
+		"b": {1, 2},
+		"c": {1, 2, 3, 4, 5},
+		"d": {1},
+	}
+	parts := splitParts(kvs, 4)
+	s.Equal([]map[string][]byte{
+		{"a": {1, 2}, "b": {1, 2}},
+		{"c": {1, 2, 3, 4, 5}},
+		{"d": {1}},
+	}, parts)
+
+	s.Len(splitParts(kvs, 100), 1)
+	s.Empty(splitParts(nil, 4))
+}
+
 func (s *BinlogIOSuite) TestJoinFullPath() {
 	tests := []struct {
 		description string
@@ -70,4 +404,7 @@ func (s *BinlogIOSuite) TestJoinFullPath() {
 			s.Equal(test.outPath, out)
 		})
 	}
+
+	b := NewTenantBinlogIO(s.cm, conc.NewDefaultPool[any](), "tenant1")
+	s.Equal(path.Join(binlogIOTestDir, "tenant=tenant1", "a/b"), b.JoinFullPath("a", "b"))
 }
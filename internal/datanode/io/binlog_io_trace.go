@@ -0,0 +1,65 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// StartIOSpan starts the span of a request to the object storage on the binlogs of the paths,
+// the span is attributed with the segments of the binlogs, the number of the paths and the bytes of the request.
+// The bytes read are unknown ahead, which are attributed by SetIOBytes once read.
+func StartIOSpan(ctx context.Context, name string, paths []string, size int64) (context.Context, trace.Span) {
+	return otel.Tracer(typeutil.DataNodeRole).Start(ctx, name, trace.WithAttributes(
+		attribute.Int64Slice("segmentIDs", segmentIDsOf(paths)),
+		attribute.Int("pathCount", len(paths)),
+		attribute.Int64("bytes", size),
+	))
+}
+
+// SetIOBytes attributes the span with the bytes of the request.
+func SetIOBytes(span trace.Span, size int64) {
+	span.SetAttributes(attribute.Int64("bytes", size))
+}
+
+// EndIOSpan ends the span of the request, the error of the request is recorded if any.
+func EndIOSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// segmentIDsOf returns the distinct ids of the segments of the binlog paths, the paths not of binlogs are skipped.
+func segmentIDsOf(paths []string) []int64 {
+	ids := typeutil.NewSet[int64]()
+	for _, path := range paths {
+		if info, ok := metautil.ParseLogPath(path); ok {
+			ids.Insert(info.SegmentID)
+		}
+	}
+	return ids.Collect()
+}
@@ -0,0 +1,104 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/util/conc"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	return lo.SliceToMap(span.Attributes(), func(kv attribute.KeyValue) (attribute.Key, attribute.Value) {
+		return kv.Key, kv.Value
+	})
+}
+
+func TestBinlogIOTrace(t *testing.T) {
+	paramtable.Init()
+	recorder := tracetest.NewSpanRecorder()
+	provider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(provider)
+
+	ctx := context.Background()
+	cm := storage.NewLocalChunkManager(storage.RootPath(path.Join(binlogIOTestDir, "trace")))
+	defer cm.RemoveWithPrefix(ctx, cm.RootPath())
+	b := NewBinlogIO(cm, conc.NewDefaultPool[any]())
+
+	kvs := map[string][]byte{
+		metautil.BuildInsertLogPath(cm.RootPath(), 1, 2, 3, 100, 1000): {1, 2, 3},
+		metautil.BuildInsertLogPath(cm.RootPath(), 1, 2, 4, 100, 1001): {4, 5},
+	}
+	ctx, root := otel.Tracer("test").Start(ctx, "Flush")
+	require.NoError(t, b.Upload(ctx, kvs))
+	_, err := b.Download(ctx, lo.Keys(kvs))
+	require.NoError(t, err)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = b.Download(timeoutCtx, []string{path.Join(cm.RootPath(), "not_found")})
+	require.Error(t, err)
+	root.End()
+
+	spans := lo.GroupBy(recorder.Ended(), func(span sdktrace.ReadOnlySpan) string { return span.Name() })
+	for _, span := range recorder.Ended() {
+		// traced end-to-end in the trace of the caller
+		assert.Equal(t, root.SpanContext().TraceID(), span.SpanContext().TraceID())
+	}
+
+	require.Len(t, spans["Upload"], 1)
+	upload := spans["Upload"][0]
+	attrs := spanAttributes(upload)
+	assert.ElementsMatch(t, []int64{3, 4}, attrs["segmentIDs"].AsInt64Slice())
+	assert.EqualValues(t, 2, attrs["pathCount"].AsInt64())
+	assert.EqualValues(t, 5, attrs["bytes"].AsInt64())
+
+	require.Len(t, spans["MultiWrite"], 1)
+	write := spans["MultiWrite"][0]
+	assert.Equal(t, upload.SpanContext().SpanID(), write.Parent().SpanID())
+	assert.EqualValues(t, 5, spanAttributes(write)["bytes"].AsInt64())
+	assert.EqualValues(t, 1, spanAttributes(write)["attempts"].AsInt64())
+
+	require.Len(t, spans["Download"], 2)
+	require.Len(t, spans["Read"], 3)
+	download := spans["Download"][0]
+	assert.EqualValues(t, 5, spanAttributes(download)["bytes"].AsInt64())
+	assert.Equal(t, codes.Unset, download.Status().Code)
+	assert.Equal(t, codes.Error, spans["Download"][1].Status().Code)
+	reads := lo.Filter(spans["Read"], func(span sdktrace.ReadOnlySpan, _ int) bool {
+		return span.Parent().SpanID() == download.SpanContext().SpanID()
+	})
+	assert.Len(t, reads, 2)
+	assert.ElementsMatch(t, []int64{3, 2}, lo.Map(reads, func(span sdktrace.ReadOnlySpan, _ int) int64 {
+		return spanAttributes(span)["bytes"].AsInt64()
+	}))
+}
@@ -0,0 +1,80 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"context"
+	"sync"
+)
+
+// cachedBinlogIO keeps the binlogs downloaded by the failed downloads, so a download retried
+// after some of the paths failed only downloads the failed paths again rather than all of them.
+// The binlogs are evicted once they are returned by a successful download.
+type cachedBinlogIO struct {
+	BinlogIO
+
+	mu    sync.Mutex
+	cache map[string][]byte
+}
+
+// NewCachedBinlogIO returns the BinlogIO caching the partial results of the failed downloads of b.
+func NewCachedBinlogIO(b BinlogIO) BinlogIO {
+	return &cachedBinlogIO{
+		BinlogIO: b,
+		cache:    make(map[string][]byte),
+	}
+}
+
+func (b *cachedBinlogIO) Download(ctx context.Context, paths []string) ([][]byte, error) {
+	values := make([][]byte, len(paths))
+	missing := make([]string, 0, len(paths))
+	missingIdx := make([]int, 0, len(paths))
+	b.mu.Lock()
+	for i, path := range paths {
+		if value, ok := b.cache[path]; ok {
+			values[i] = value
+			continue
+		}
+		missing = append(missing, path)
+		missingIdx = append(missingIdx, i)
+	}
+	b.mu.Unlock()
+
+	if len(missing) > 0 {
+		downloaded, err := b.BinlogIO.Download(ctx, missing)
+		if err != nil {
+			b.mu.Lock()
+			for i, value := range downloaded {
+				if value != nil {
+					b.cache[missing[i]] = value
+				}
+			}
+			b.mu.Unlock()
+			return nil, err
+		}
+		for i, value := range downloaded {
+			values[missingIdx[i]] = value
+		}
+	}
+
+	b.mu.Lock()
+	for _, path := range paths {
+		delete(b.cache, path)
+	}
+	b.mu.Unlock()
+	return values, nil
+}
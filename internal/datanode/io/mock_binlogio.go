@@ -6,6 +6,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/milvus-io/milvus/internal/storage"
 )
 
 // MockBinlogIO is an autogenerated mock type for the BinlogIO type
@@ -76,6 +78,62 @@ func (_c *MockBinlogIO_Download_Call) RunAndReturn(run func(context.Context, []s
 	return _c
 }
 
+// DownloadStream provides a mock function with given fields: ctx, paths, checksums
+func (_m *MockBinlogIO) DownloadStream(ctx context.Context, paths []string, checksums []uint32) ([]*storage.StreamBinlogReader, error) {
+	ret := _m.Called(ctx, paths, checksums)
+
+	var r0 []*storage.StreamBinlogReader
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, []uint32) ([]*storage.StreamBinlogReader, error)); ok {
+		return rf(ctx, paths, checksums)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, []uint32) []*storage.StreamBinlogReader); ok {
+		r0 = rf(ctx, paths, checksums)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.StreamBinlogReader)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, []uint32) error); ok {
+		r1 = rf(ctx, paths, checksums)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockBinlogIO_DownloadStream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DownloadStream'
+type MockBinlogIO_DownloadStream_Call struct {
+	*mock.Call
+}
+
+// DownloadStream is a helper method to define mock.On call
+//   - ctx context.Context
+//   - paths []string
+//   - checksums []uint32
+func (_e *MockBinlogIO_Expecter) DownloadStream(ctx interface{}, paths interface{}, checksums interface{}) *MockBinlogIO_DownloadStream_Call {
+	return &MockBinlogIO_DownloadStream_Call{Call: _e.mock.On("DownloadStream", ctx, paths, checksums)}
+}
+
+func (_c *MockBinlogIO_DownloadStream_Call) Run(run func(ctx context.Context, paths []string, checksums []uint32)) *MockBinlogIO_DownloadStream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string), args[2].([]uint32))
+	})
+	return _c
+}
+
+func (_c *MockBinlogIO_DownloadStream_Call) Return(_a0 []*storage.StreamBinlogReader, _a1 error) *MockBinlogIO_DownloadStream_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockBinlogIO_DownloadStream_Call) RunAndReturn(run func(context.Context, []string, []uint32) ([]*storage.StreamBinlogReader, error)) *MockBinlogIO_DownloadStream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // JoinFullPath provides a mock function with given fields: paths
 func (_m *MockBinlogIO) JoinFullPath(paths ...string) string {
 	_va := make([]interface{}, len(paths))
@@ -131,6 +189,49 @@ func (_c *MockBinlogIO_JoinFullPath_Call) RunAndReturn(run func(...string) strin
 	return _c
 }
 
+// MultiRemove provides a mock function with given fields: ctx, paths
+func (_m *MockBinlogIO) MultiRemove(ctx context.Context, paths []string) error {
+	ret := _m.Called(ctx, paths)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(ctx, paths)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockBinlogIO_MultiRemove_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MultiRemove'
+type MockBinlogIO_MultiRemove_Call struct {
+	*mock.Call
+}
+
+// MultiRemove is a helper method to define mock.On call
+//   - ctx context.Context
+//   - paths []string
+func (_e *MockBinlogIO_Expecter) MultiRemove(ctx interface{}, paths interface{}) *MockBinlogIO_MultiRemove_Call {
+	return &MockBinlogIO_MultiRemove_Call{Call: _e.mock.On("MultiRemove", ctx, paths)}
+}
+
+func (_c *MockBinlogIO_MultiRemove_Call) Run(run func(ctx context.Context, paths []string)) *MockBinlogIO_MultiRemove_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *MockBinlogIO_MultiRemove_Call) Return(_a0 error) *MockBinlogIO_MultiRemove_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockBinlogIO_MultiRemove_Call) RunAndReturn(run func(context.Context, []string) error) *MockBinlogIO_MultiRemove_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Upload provides a mock function with given fields: ctx, kvs
 func (_m *MockBinlogIO) Upload(ctx context.Context, kvs map[string][]byte) error {
 	ret := _m.Called(ctx, kvs)
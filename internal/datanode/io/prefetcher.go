@@ -0,0 +1,142 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus/internal/storage"
+)
+
+type prefetchResult struct {
+	readers []*storage.StreamBinlogReader
+	err     error
+}
+
+// Prefetcher downloads the batches of binlogs consumed in order by a compaction, at most depth batches
+// are downloaded ahead of the one consumed, so the downloads of the next batches overlap the merge of the current one.
+// The batches prefetched are downloaded as a whole and read from the memory by the readers.
+type Prefetcher struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	results []chan prefetchResult
+	tokens  chan struct{}
+	next    int
+	// err is the failure of the batch consumed, which fails the batches after it as well
+	err error
+}
+
+// NewPrefetcher starts prefetching the batches of the binlog paths, checksums are the ones recorded in the segment meta
+// by the paths, and verified once the binlogs are all read. At least a batch is prefetched even if depth is non-positive.
+// The prefetcher must be closed once it's no longer used.
+func NewPrefetcher(ctx context.Context, b BinlogIO, batches [][]string, checksums map[string]uint32, depth int) *Prefetcher {
+	if depth < 1 {
+		depth = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	p := &Prefetcher{
+		ctx:     ctx,
+		cancel:  cancel,
+		results: make([]chan prefetchResult, len(batches)),
+		tokens:  make(chan struct{}, depth),
+	}
+	for i := range p.results {
+		p.results[i] = make(chan prefetchResult, 1)
+	}
+	go p.prefetch(b, batches, checksums)
+	return p
+}
+
+func (p *Prefetcher) prefetch(b BinlogIO, batches [][]string, checksums map[string]uint32) {
+	for i, paths := range batches {
+		select {
+		case p.tokens <- struct{}{}:
+		case <-p.ctx.Done():
+			return
+		}
+		readers, err := p.download(b, paths, checksums)
+		p.results[i] <- prefetchResult{readers: readers, err: err}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (p *Prefetcher) download(b BinlogIO, paths []string, checksums map[string]uint32) ([]*storage.StreamBinlogReader, error) {
+	values, err := b.Download(p.ctx, paths)
+	if err != nil {
+		return nil, err
+	}
+	readers := make([]*storage.StreamBinlogReader, 0, len(paths))
+	for i, path := range paths {
+		reader, err := storage.NewStreamBinlogReader(path, int64(len(values[i])), checksums[path], memoryRangeReader(values[i]))
+		if err != nil {
+			for _, reader := range readers {
+				reader.Close()
+			}
+			return nil, err
+		}
+		readers = append(readers, reader)
+	}
+	return readers, nil
+}
+
+// Next returns the readers of the next batch, blocked until the batch is downloaded.
+func (p *Prefetcher) Next() ([]*storage.StreamBinlogReader, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.next >= len(p.results) {
+		return nil, storage.ErrNoMoreRecord
+	}
+	var result prefetchResult
+	select {
+	case result = <-p.results[p.next]:
+	case <-p.ctx.Done():
+		return nil, p.ctx.Err()
+	}
+	p.next++
+	<-p.tokens
+	p.err = result.err
+	return result.readers, result.err
+}
+
+// Close stops prefetching, the readers of the batches prefetched but not consumed are closed.
+func (p *Prefetcher) Close() {
+	p.cancel()
+	for _, ch := range p.results {
+		select {
+		case result := <-ch:
+			for _, reader := range result.readers {
+				reader.Close()
+			}
+		default:
+		}
+	}
+}
+
+// memoryRangeReader reads the ranges of the binlog downloaded as a whole.
+func memoryRangeReader(value []byte) storage.RangeReader {
+	return func(off, length int64) ([]byte, error) {
+		if off < 0 || length < 0 || off+length > int64(len(value)) {
+			return nil, errors.Newf("range [%d, %d) out of the binlog of %d bytes", off, off+length, len(value))
+		}
+		return value[off : off+length], nil
+	}
+}
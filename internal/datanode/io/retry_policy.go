@@ -0,0 +1,79 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/retry"
+)
+
+// ErrRetryExceeded marks the binlog requests failed for retried beyond the max duration of the operation class.
+var ErrRetryExceeded = errors.New("binlog io retry exceeded")
+
+// retryPolicy bounds the retries of the binlog requests of an operation class, so a stuck object storage
+// fails the flush or compaction to be recovered or rescheduled, rather than hanging it until the context is done.
+type retryPolicy struct {
+	// maxDuration is the max duration of all the attempts of a request, no bound if non-positive
+	maxDuration time.Duration
+	// maxCount is the max attempts of a request, the default attempts of retry.Do if zero
+	maxCount uint
+}
+
+// retryPolicyOf returns the retry policy of the operation class of the context,
+// the class is the upload priority of the context, see WithUploadPriority.
+func retryPolicyOf(ctx context.Context) retryPolicy {
+	params := &paramtable.Get().DataNodeCfg
+	if uploadPriorityOf(ctx) == UploadPriorityFlush {
+		return retryPolicy{
+			maxDuration: params.FlushMaxRetryDuration.GetAsDuration(time.Second),
+			maxCount:    uint(params.FlushMaxRetryCount.GetAsInt()),
+		}
+	}
+	return retryPolicy{
+		maxDuration: params.CompactionMaxRetryDuration.GetAsDuration(time.Second),
+		maxCount:    uint(params.CompactionMaxRetryCount.GetAsInt()),
+	}
+}
+
+// Retry retries the request within the retry policy of the operation class of the context, the context passed
+// to the request is done once the max duration is exceeded, so an attempt stuck is canceled as well.
+// The opts are applied after the policy, which override the attempts of the policy if any.
+func Retry(ctx context.Context, fn func(ctx context.Context) error, opts ...retry.Option) error {
+	policy := retryPolicyOf(ctx)
+	retryCtx := ctx
+	if policy.maxDuration > 0 {
+		var cancel context.CancelFunc
+		retryCtx, cancel = context.WithTimeout(ctx, policy.maxDuration)
+		defer cancel()
+	}
+	if policy.maxCount > 0 {
+		opts = append([]retry.Option{retry.Attempts(policy.maxCount)}, opts...)
+	}
+
+	err := retry.Do(retryCtx, func() error {
+		return fn(retryCtx)
+	}, opts...)
+	if err != nil && ctx.Err() == nil && retryCtx.Err() != nil {
+		return errors.Mark(errors.Wrapf(err, "retried beyond %s", policy.maxDuration), ErrRetryExceeded)
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/retry"
+)
+
+func TestRetry(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	errMock := errors.New("mock")
+
+	t.Run("max count by the class", func(t *testing.T) {
+		params.Save(params.DataNodeCfg.FlushMaxRetryCount.Key, "3")
+		defer params.Reset(params.DataNodeCfg.FlushMaxRetryCount.Key)
+		params.Save(params.DataNodeCfg.CompactionMaxRetryCount.Key, "2")
+		defer params.Reset(params.DataNodeCfg.CompactionMaxRetryCount.Key)
+
+		for priority, expected := range map[UploadPriority]int{UploadPriorityFlush: 3, UploadPriorityCompaction: 2} {
+			attempts := 0
+			err := Retry(WithUploadPriority(context.Background(), priority), func(ctx context.Context) error {
+				attempts++
+				return errMock
+			}, retry.Sleep(time.Millisecond))
+			assert.ErrorIs(t, err, errMock)
+			assert.False(t, errors.Is(err, ErrRetryExceeded))
+			assert.Equal(t, expected, attempts)
+		}
+
+		// the options override the attempts of the policy
+		attempts := 0
+		err := Retry(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return errMock
+		}, retry.Attempts(1))
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("max duration", func(t *testing.T) {
+		params.Save(params.DataNodeCfg.CompactionMaxRetryDuration.Key, "1")
+		defer params.Reset(params.DataNodeCfg.CompactionMaxRetryDuration.Key)
+		params.Save(params.DataNodeCfg.CompactionMaxRetryCount.Key, "0")
+		defer params.Reset(params.DataNodeCfg.CompactionMaxRetryCount.Key)
+
+		// the attempt stuck is canceled once the duration is exceeded
+		start := time.Now()
+		err := Retry(context.Background(), func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.True(t, errors.Is(err, ErrRetryExceeded))
+		assert.Less(t, time.Since(start), 3*time.Second)
+
+		// the context canceled by the caller isn't of the retry exceeded
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err = Retry(ctx, func(ctx context.Context) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, errors.Is(err, ErrRetryExceeded))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		attempts := 0
+		err := Retry(WithUploadPriority(context.Background(), UploadPriorityFlush), func(ctx context.Context) error {
+			attempts++
+			if attempts < 2 {
+				return errMock
+			}
+			return nil
+		}, retry.Sleep(time.Millisecond))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+	})
+}
@@ -0,0 +1,117 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"context"
+	"sync"
+
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+)
+
+// DryRunReport collects the binlogs of the dry-run uploads, which are validated and encoded as uploaded but not written.
+type DryRunReport struct {
+	mu sync.Mutex
+	// binlogs are the sizes of the binlogs encoded by the paths
+	binlogs map[string]int64
+}
+
+func NewDryRunReport() *DryRunReport {
+	return &DryRunReport{binlogs: make(map[string]int64)}
+}
+
+// Binlogs returns the sizes of the binlogs of the dry-run uploads by the paths,
+// the sizes are the ones to be written to the object storage, i.e. compressed if enabled.
+func (r *DryRunReport) Binlogs() map[string]int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	binlogs := make(map[string]int64, len(r.binlogs))
+	for path, size := range r.binlogs {
+		binlogs[path] = size
+	}
+	return binlogs
+}
+
+// Size returns the total size of the binlogs of the dry-run uploads.
+func (r *DryRunReport) Size() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var size int64
+	for _, s := range r.binlogs {
+		size += s
+	}
+	return size
+}
+
+func (r *DryRunReport) add(kvs map[string][]byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, value := range kvs {
+		r.binlogs[key] = int64(len(value))
+	}
+}
+
+type dryRunKey struct{}
+
+// WithDryRun returns the context whose binlogIO uploads are validated and recorded into the report
+// rather than written to the object storage.
+func WithDryRun(ctx context.Context, report *DryRunReport) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, report)
+}
+
+// DryRunOf returns the report of the dry-run uploads of the context, nil if the uploads are written.
+func DryRunOf(ctx context.Context) *DryRunReport {
+	report, _ := ctx.Value(dryRunKey{}).(*DryRunReport)
+	return report
+}
+
+// validateUpload validates the kvs to upload, the keys must be the binlog paths joined by JoinFullPath,
+// and the insert and delta binlogs must be readable and of the collection, partition, segment and field of the keys.
+func (b *BinlogIoImpl) validateUpload(kvs map[string][]byte) error {
+	for key, value := range kvs {
+		info, ok := metautil.ParseLogPath(key)
+		if !ok {
+			return merr.WrapErrParameterInvalidMsg("invalid binlog path %s", key)
+		}
+		ids := []int64{info.CollectionID, info.PartitionID, info.SegmentID, info.FieldID, info.LogID}
+		if info.LogType == common.SegmentDeltaLogPath {
+			ids = []int64{info.CollectionID, info.PartitionID, info.SegmentID, info.LogID}
+		}
+		if expected := b.JoinFullPath(info.LogType, metautil.JoinIDPath(ids...)); key != expected {
+			return merr.WrapErrParameterInvalidMsg("binlog path %s not under the root of the binlogs, expected %s", key, expected)
+		}
+		if info.LogType != common.SegmentInsertLogPath && info.LogType != common.SegmentDeltaLogPath {
+			continue
+		}
+
+		reader, err := storage.NewBinlogReader(value)
+		if err != nil {
+			return merr.WrapErrParameterInvalidMsg("unreadable binlog %s: %s", key, err.Error())
+		}
+		fixPart := reader.DescriptorEventDataFixPart
+		reader.Close()
+		if fixPart.CollectionID != info.CollectionID || fixPart.PartitionID != info.PartitionID || fixPart.SegmentID != info.SegmentID ||
+			(info.LogType == common.SegmentInsertLogPath && fixPart.FieldID != info.FieldID) {
+			return merr.WrapErrParameterInvalidMsg("binlog %s of collection %d partition %d segment %d field %d mismatches its path",
+				key, fixPart.CollectionID, fixPart.PartitionID, fixPart.SegmentID, fixPart.FieldID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// UploadPriority is the priority of the uploads queued by the upload scheduler, the higher goes first.
+type UploadPriority int
+
+const (
+	// UploadPriorityCompaction is the priority of the compaction uploads, the default of the binlogIO uploads
+	UploadPriorityCompaction UploadPriority = iota
+	// UploadPriorityFlush is the priority of the flush uploads, which block the checkpoints of the channels
+	UploadPriorityFlush
+
+	numUploadPriorities
+)
+
+type uploadPriorityKey struct{}
+
+// WithUploadPriority returns the context whose binlogIO uploads are queued by the priority.
+func WithUploadPriority(ctx context.Context, priority UploadPriority) context.Context {
+	return context.WithValue(ctx, uploadPriorityKey{}, priority)
+}
+
+func uploadPriorityOf(ctx context.Context) UploadPriority {
+	if priority, ok := ctx.Value(uploadPriorityKey{}).(UploadPriority); ok {
+		return priority
+	}
+	return UploadPriorityCompaction
+}
+
+var (
+	globalUploadScheduler   *UploadScheduler
+	uploadSchedulerInitOnce sync.Once
+)
+
+// GetUploadScheduler returns the upload scheduler shared by the flush and compaction of the datanode.
+func GetUploadScheduler() *UploadScheduler {
+	uploadSchedulerInitOnce.Do(func() {
+		globalUploadScheduler = NewUploadScheduler(func() int64 {
+			return paramtable.Get().DataNodeCfg.UploadMaxInflight.GetAsInt64()
+		})
+	})
+	return globalUploadScheduler
+}
+
+// UploadScheduler bounds the bytes of the uploads in flight, the uploads beyond the bound are queued
+// by the priority and admitted in order as the former ones are done, so the compactions couldn't
+// take the bandwidth from the flushes. The uploads of the same priority are admitted first in first out,
+// and an upload larger than the bound is admitted once nothing is in flight, so it isn't queued forever.
+type UploadScheduler struct {
+	mu          sync.Mutex
+	maxInflight func() int64
+	inflight    int64
+	// waiters are the queues of the uploads waiting for admission by the priority
+	waiters [numUploadPriorities]*list.List
+}
+
+type uploadWaiter struct {
+	size     int64
+	admitted chan struct{}
+}
+
+// NewUploadScheduler returns the scheduler bounded by the max inflight bytes, which is read on each admission
+// so the bound could be refreshed, a non-positive bound means unlimited.
+func NewUploadScheduler(maxInflight func() int64) *UploadScheduler {
+	s := &UploadScheduler{maxInflight: maxInflight}
+	for i := range s.waiters {
+		s.waiters[i] = list.New()
+	}
+	return s
+}
+
+// Acquire blocks until the upload of the size is admitted, the release func must be called once the upload is done.
+// The upload is dequeued and the error of the context is returned if the context is done before admission.
+func (s *UploadScheduler) Acquire(ctx context.Context, priority UploadPriority, size int64) (release func(), err error) {
+	if priority < 0 || priority >= numUploadPriorities {
+		priority = UploadPriorityCompaction
+	}
+	release = func() { s.release(size) }
+
+	s.mu.Lock()
+	if s.queuedAhead(priority) == 0 && s.admissible(size) {
+		s.inflight += size
+		s.mu.Unlock()
+		return release, nil
+	}
+	waiter := &uploadWaiter{size: size, admitted: make(chan struct{})}
+	elem := s.waiters[priority].PushBack(waiter)
+	s.mu.Unlock()
+
+	select {
+	case <-waiter.admitted:
+		return release, nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-waiter.admitted:
+		// admitted while canceled, give the bytes back
+		s.inflight -= size
+	default:
+		s.waiters[priority].Remove(elem)
+	}
+	s.dispatch()
+	return nil, ctx.Err()
+}
+
+// Inflight returns the bytes of the uploads admitted and not released.
+func (s *UploadScheduler) Inflight() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inflight
+}
+
+// Queued returns the number of the uploads waiting for admission.
+func (s *UploadScheduler) Queued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queuedAhead(UploadPriorityCompaction)
+}
+
+func (s *UploadScheduler) release(size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inflight -= size
+	s.dispatch()
+}
+
+// queuedAhead returns the number of the uploads waiting of the priority or higher.
+func (s *UploadScheduler) queuedAhead(priority UploadPriority) int {
+	var n int
+	for p := priority; p < numUploadPriorities; p++ {
+		n += s.waiters[p].Len()
+	}
+	return n
+}
+
+func (s *UploadScheduler) admissible(size int64) bool {
+	maxInflight := s.maxInflight()
+	return maxInflight <= 0 || s.inflight == 0 || s.inflight+size <= maxInflight
+}
+
+// dispatch admits the waiters from the highest priority in order, and stops at the first one not admissible,
+// so a large upload isn't starved by the smaller ones behind it.
+func (s *UploadScheduler) dispatch() {
+	for p := numUploadPriorities - 1; p >= 0; p-- {
+		queue := s.waiters[p]
+		for queue.Len() > 0 {
+			elem := queue.Front()
+			waiter := elem.Value.(*uploadWaiter)
+			if !s.admissible(waiter.size) {
+				return
+			}
+			queue.Remove(elem)
+			s.inflight += waiter.size
+			close(waiter.admitted)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func acquireAsync(s *UploadScheduler, ctx context.Context, priority UploadPriority, size int64, admitted chan<- int64) <-chan func() {
+	releases := make(chan func(), 1)
+	go func() {
+		release, err := s.Acquire(ctx, priority, size)
+		if err != nil {
+			close(releases)
+			return
+		}
+		admitted <- size
+		releases <- release
+	}()
+	return releases
+}
+
+func TestUploadScheduler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unlimited", func(t *testing.T) {
+		s := NewUploadScheduler(func() int64 { return 0 })
+		r1, err := s.Acquire(ctx, UploadPriorityCompaction, 100)
+		require.NoError(t, err)
+		r2, err := s.Acquire(ctx, UploadPriorityFlush, 100)
+		require.NoError(t, err)
+		assert.EqualValues(t, 200, s.Inflight())
+		r1()
+		r2()
+		assert.EqualValues(t, 0, s.Inflight())
+	})
+
+	t.Run("priority", func(t *testing.T) {
+		s := NewUploadScheduler(func() int64 { return 100 })
+		release, err := s.Acquire(ctx, UploadPriorityCompaction, 80)
+		require.NoError(t, err)
+
+		admitted := make(chan int64, 2)
+		compaction := acquireAsync(s, ctx, UploadPriorityCompaction, 50, admitted)
+		assert.Eventually(t, func() bool { return s.Queued() == 1 }, time.Second, time.Millisecond)
+		flush := acquireAsync(s, ctx, UploadPriorityFlush, 60, admitted)
+		assert.Eventually(t, func() bool { return s.Queued() == 2 }, time.Second, time.Millisecond)
+
+		// the flush upload goes first, and the compaction one waits for the bytes
+		release()
+		assert.EqualValues(t, 60, <-admitted)
+		assert.Equal(t, 1, s.Queued())
+		(<-flush)()
+		assert.EqualValues(t, 50, <-admitted)
+		(<-compaction)()
+		assert.EqualValues(t, 0, s.Inflight())
+	})
+
+	t.Run("larger than bound", func(t *testing.T) {
+		s := NewUploadScheduler(func() int64 { return 100 })
+		release, err := s.Acquire(ctx, UploadPriorityFlush, 10)
+		require.NoError(t, err)
+
+		admitted := make(chan int64, 1)
+		large := acquireAsync(s, ctx, UploadPriorityFlush, 200, admitted)
+		assert.Eventually(t, func() bool { return s.Queued() == 1 }, time.Second, time.Millisecond)
+
+		// admitted once nothing is in flight
+		release()
+		assert.EqualValues(t, 200, <-admitted)
+		(<-large)()
+		assert.EqualValues(t, 0, s.Inflight())
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		s := NewUploadScheduler(func() int64 { return 100 })
+		release, err := s.Acquire(ctx, UploadPriorityCompaction, 100)
+		require.NoError(t, err)
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() {
+			_, err := s.Acquire(cancelCtx, UploadPriorityFlush, 50)
+			done <- err
+		}()
+		assert.Eventually(t, func() bool { return s.Queued() == 1 }, time.Second, time.Millisecond)
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+		assert.Equal(t, 0, s.Queued())
+
+		release()
+		assert.EqualValues(t, 0, s.Inflight())
+	})
+}
+
+func TestUploadPriorityOf(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, UploadPriorityCompaction, uploadPriorityOf(ctx))
+	assert.Equal(t, UploadPriorityFlush, uploadPriorityOf(WithUploadPriority(ctx, UploadPriorityFlush)))
+}
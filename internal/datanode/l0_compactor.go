@@ -19,6 +19,7 @@ package datanode
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/samber/lo"
@@ -29,6 +30,7 @@ import (
 	"github.com/milvus-io/milvus/internal/datanode/allocator"
 	"github.com/milvus-io/milvus/internal/datanode/io"
 	iter "github.com/milvus-io/milvus/internal/datanode/iterators"
+	"github.com/milvus-io/milvus/internal/datanode/membudget"
 	"github.com/milvus-io/milvus/internal/datanode/metacache"
 	"github.com/milvus-io/milvus/internal/datanode/syncmgr"
 	"github.com/milvus-io/milvus/internal/metastore/kv/binlog"
@@ -138,35 +140,57 @@ func (t *levelZeroCompactionTask) compact() (*datapb.CompactionPlanResult, error
 		log.Warn("compact wrong, not target sealed segments")
 		return nil, errIllegalCompactionPlan
 	}
-	err := binlog.DecompressCompactionBinlogs(l0Segments)
+	err := binlog.DecompressCompactionBinlogs(t.plan.GetSegmentBinlogs())
 	if err != nil {
 		log.Warn("DecompressCompactionBinlogs failed", zap.Error(err))
 		return nil, err
 	}
 
+	pkField, err := typeutil.GetPrimaryFieldSchema(t.metacache.Schema())
+	if err != nil {
+		log.Warn("failed to get pk field from schema", zap.Error(err))
+		return nil, err
+	}
+
 	var (
 		totalSize      int64
+		maxSegmentSize int64
 		totalDeltalogs = make(map[UniqueID][]string)
 	)
 	for _, s := range l0Segments {
 		paths := []string{}
-		for _, d := range s.GetDeltalogs() {
+		var segmentSize int64
+		for _, d := range binlog.FilterDeltalogs(s.GetDeltalogs(), pkField.GetFieldID()) {
 			for _, l := range d.GetBinlogs() {
 				paths = append(paths, l.GetLogPath())
-				totalSize += l.GetLogSize()
+				segmentSize += l.GetLogSize()
 			}
 		}
 		if len(paths) > 0 {
 			totalDeltalogs[s.GetSegmentID()] = paths
 		}
+		totalSize += segmentSize
+		if segmentSize > maxSegmentSize {
+			maxSegmentSize = segmentSize
+		}
 	}
 
 	var resultSegments []*datapb.CompactionSegment
 
-	if float64(hardware.GetFreeMemoryCount())*paramtable.Get().DataNodeCfg.L0BatchMemoryRatio.GetAsFloat() < float64(totalSize) {
-		resultSegments, err = t.linearProcess(ctxTimeout, targetSegIDs, totalDeltalogs)
-	} else {
+	// the deltalogs of all L0 segments are processed in a batch only if they fit both the free memory and the memory budget,
+	// otherwise the deltalogs of each L0 segment are processed one by one
+	budget := membudget.GetBudget()
+	if float64(hardware.GetFreeMemoryCount())*paramtable.Get().DataNodeCfg.L0BatchMemoryRatio.GetAsFloat() >= float64(totalSize) &&
+		budget.TryReserve(membudget.Compaction, totalSize) {
+		defer budget.Release(membudget.Compaction, totalSize)
 		resultSegments, err = t.batchProcess(ctxTimeout, targetSegIDs, lo.Values(totalDeltalogs)...)
+	} else {
+		if err = budget.Reserve(ctxTimeout, membudget.Compaction, maxSegmentSize); err != nil {
+			log.Warn("compact wrong, failed to reserve memory", zap.Error(err))
+			return nil, err
+		}
+		defer budget.Release(membudget.Compaction, maxSegmentSize)
+		resultSegments, err = t.linearProcess(ctxTimeout, targetSegIDs, totalDeltalogs)
 	}
 	if err != nil {
 		return nil, err
@@ -255,11 +279,16 @@ func (t *levelZeroCompactionTask) batchProcess(ctx context.Context, targetSegmen
 func (t *levelZeroCompactionTask) loadDelta(ctx context.Context, deltaLogs ...[]string) ([]*iter.DeltalogIterator, error) {
 	allIters := make([]*iter.DeltalogIterator, 0)
 
+	checksum := binlogChecksum(t.plan.GetSegmentBinlogs())
 	for _, paths := range deltaLogs {
 		blobs, err := t.Download(ctx, paths)
 		if err != nil {
 			return nil, err
 		}
+		if err := verifyBinlogs(paths, blobs, checksum); err != nil {
+			log.Ctx(ctx).Warn("deltalogs corrupted", zap.Error(err))
+			return nil, err
+		}
 
 		allIters = append(allIters, iter.NewDeltalogIterator(blobs, nil))
 	}
@@ -304,7 +333,9 @@ func (t *levelZeroCompactionTask) splitDelta(
 	}
 }
 
-func (t *levelZeroCompactionTask) composeDeltalog(segmentID int64, dData *storage.DeleteData) (map[string][]byte, *datapb.Binlog, error) {
+// composeDeltalog serializes the deletes of the target segment, the deletes are recorded by the offsets of
+// the deleted rows if deltalog bitmap is enabled, nil binlog is returned if none of the rows are deleted.
+func (t *levelZeroCompactionTask) composeDeltalog(ctx context.Context, segmentID int64, dData *storage.DeleteData) (map[string][]byte, *datapb.Binlog, error) {
 	var (
 		collID   = t.metacache.Collection()
 		uploadKv = make(map[string][]byte)
@@ -314,7 +345,29 @@ func (t *levelZeroCompactionTask) composeDeltalog(segmentID int64, dData *storag
 	if !ok {
 		return nil, nil, merr.WrapErrSegmentLack(segmentID)
 	}
-	blob, err := storage.NewDeleteCodec().Serialize(collID, seg.PartitionID(), segmentID, dData)
+
+	var bitmap *storage.DeleteBitmap
+	if paramtable.Get().DataNodeCfg.DeltalogBitmapEnabled.GetAsBool() {
+		var err error
+		bitmap, err = t.resolveDeleteBitmap(ctx, segmentID, dData)
+		if err != nil {
+			return nil, nil, err
+		}
+		if bitmap != nil && bitmap.Len() == 0 {
+			return nil, nil, nil
+		}
+	}
+
+	var (
+		blob *storage.Blob
+		err  error
+	)
+	if bitmap != nil {
+		blob, err = storage.NewDeleteCodec().SerializeBitmap(collID, seg.PartitionID(), segmentID, bitmap)
+	} else {
+		delCodec := &storage.DeleteCodec{SortedByPK: t.metacache.DeltalogSorted()}
+		blob, err = delCodec.Serialize(collID, seg.PartitionID(), segmentID, dData)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
@@ -331,22 +384,126 @@ func (t *levelZeroCompactionTask) composeDeltalog(segmentID int64, dData *storag
 
 	// TODO Timestamp?
 	deltalog := &datapb.Binlog{
-		LogSize: int64(len(blob.GetValue())),
-		LogPath: blobPath,
-		LogID:   logID,
+		LogSize:  int64(len(blob.GetValue())),
+		LogPath:  blobPath,
+		LogID:    logID,
+		Checksum: storage.BinlogChecksum(blob.GetValue()),
 	}
 
 	return uploadKv, deltalog, nil
 }
 
+// resolveDeleteBitmap resolves the deletes to the offsets of the rows in the target segment by its insert binlogs,
+// a row is deleted by the earliest delete of its primary key after it's inserted. Deletes of the primary keys
+// not in the segment are dropped. It returns nil if the insert binlogs are not in the plan.
+func (t *levelZeroCompactionTask) resolveDeleteBitmap(ctx context.Context, segmentID int64, dData *storage.DeleteData) (*storage.DeleteBitmap, error) {
+	pkField, err := typeutil.GetPrimaryFieldSchema(t.metacache.Schema())
+	if err != nil {
+		return nil, err
+	}
+	segment, ok := lo.Find(t.plan.GetSegmentBinlogs(), func(s *datapb.CompactionSegmentBinlogs) bool {
+		return s.GetSegmentID() == segmentID
+	})
+	if !ok {
+		return nil, nil
+	}
+	fieldBinlogs := make([][]*datapb.Binlog, 0, 3)
+	for _, fieldID := range []int64{common.RowIDField, common.TimeStampField, pkField.GetFieldID()} {
+		fieldBinlog, ok := lo.Find(segment.GetFieldBinlogs(), func(fb *datapb.FieldBinlog) bool {
+			return fb.GetFieldID() == fieldID
+		})
+		if !ok || len(fieldBinlog.GetBinlogs()) == 0 {
+			return nil, nil
+		}
+		if len(fieldBinlogs) > 0 && len(fieldBinlog.GetBinlogs()) != len(fieldBinlogs[0]) {
+			return nil, fmt.Errorf("binlogs of field %d mismatch with the other fields of segment %d", fieldID, segmentID)
+		}
+		fieldBinlogs = append(fieldBinlogs, fieldBinlog.GetBinlogs())
+	}
+
+	deletes := make(map[any][]Timestamp)
+	for i, pk := range dData.Pks {
+		deletes[pk.GetValue()] = append(deletes[pk.GetValue()], dData.Tss[i])
+	}
+	for _, tss := range deletes {
+		sort.Slice(tss, func(i, j int) bool { return tss[i] < tss[j] })
+	}
+
+	var (
+		offset  int64
+		offsets []int64
+		tss     []Timestamp
+	)
+	for i := range fieldBinlogs[0] {
+		paths := lo.Map(fieldBinlogs, func(binlogs []*datapb.Binlog, _ int) string {
+			return binlogs[i].GetLogPath()
+		})
+		values, err := t.Download(ctx, paths)
+		if err != nil {
+			return nil, err
+		}
+		for j, binlogs := range fieldBinlogs {
+			if err := storage.VerifyBinlogChecksum(paths[j], values[j], binlogs[i].GetChecksum()); err != nil {
+				return nil, err
+			}
+		}
+		blobs := lo.Map(values, func(v []byte, j int) *storage.Blob {
+			return &storage.Blob{Key: paths[j], Value: v}
+		})
+		insertIter, err := storage.NewInsertEventIterator(blobs, pkField.GetFieldID(), pkField.GetDataType())
+		if err != nil {
+			return nil, err
+		}
+		for insertIter.HasNext() {
+			next, err := insertIter.Next()
+			if err != nil {
+				insertIter.Dispose()
+				return nil, err
+			}
+			v := next.(*storage.Value)
+			if deleteTss, ok := deletes[v.PK.GetValue()]; ok {
+				idx := sort.Search(len(deleteTss), func(i int) bool {
+					return deleteTss[i] > Timestamp(v.Timestamp)
+				})
+				if idx < len(deleteTss) {
+					offsets = append(offsets, offset)
+					tss = append(tss, deleteTss[idx])
+				}
+			}
+			offset++
+		}
+		insertIter.Dispose()
+	}
+
+	bitmap := storage.NewDeleteBitmap(offset)
+	for i := range offsets {
+		bitmap.Add(offsets[i], tss[i])
+	}
+	log.Ctx(ctx).Info("L0 compaction resolved deletes to row offsets",
+		zap.Int64("segmentID", segmentID),
+		zap.Int64("deleteRows", dData.RowCount),
+		zap.Int("deletedRows", bitmap.Len()),
+		zap.Int64("numRows", offset))
+	return bitmap, nil
+}
+
 func (t *levelZeroCompactionTask) uploadByCheck(ctx context.Context, requireCheck bool, alteredSegments map[int64]*storage.DeleteData, resultSegments map[int64]*datapb.CompactionSegment) error {
+	pkField, err := typeutil.GetPrimaryFieldSchema(t.metacache.Schema())
+	if err != nil {
+		return err
+	}
 	for segID, dData := range alteredSegments {
 		if !requireCheck || (dData.Size() >= paramtable.Get().DataNodeCfg.FlushDeleteBufferBytes.GetAsInt64()) {
-			blobs, binlog, err := t.composeDeltalog(segID, dData)
+			blobs, binlog, err := t.composeDeltalog(ctx, segID, dData)
 			if err != nil {
 				log.Warn("L0 compaction composeDelta fail", zap.Int64("segmentID", segID), zap.Error(err))
 				return err
 			}
+			if binlog == nil {
+				// none of the rows of the segment are deleted
+				delete(alteredSegments, segID)
+				continue
+			}
 			err = t.Upload(ctx, blobs)
 			if err != nil {
 				log.Warn("L0 compaction upload blobs fail", zap.Int64("segmentID", segID), zap.Any("binlog", binlog), zap.Error(err))
@@ -356,7 +513,7 @@ func (t *levelZeroCompactionTask) uploadByCheck(ctx context.Context, requireChec
 			if _, ok := resultSegments[segID]; !ok {
 				resultSegments[segID] = &datapb.CompactionSegment{
 					SegmentID: segID,
-					Deltalogs: []*datapb.FieldBinlog{{Binlogs: []*datapb.Binlog{binlog}}},
+					Deltalogs: []*datapb.FieldBinlog{{FieldID: pkField.GetFieldID(), Binlogs: []*datapb.Binlog{binlog}}},
 					Channel:   t.plan.GetChannel(),
 				}
 			} else {
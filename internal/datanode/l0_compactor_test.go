@@ -19,6 +19,7 @@ package datanode
 import (
 	"context"
 	"path"
+	"strconv"
 	"testing"
 
 	"github.com/cockroachdb/errors"
@@ -27,14 +28,17 @@ import (
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
 
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/datanode/allocator"
 	"github.com/milvus-io/milvus/internal/datanode/io"
 	iter "github.com/milvus-io/milvus/internal/datanode/iterators"
 	"github.com/milvus-io/milvus/internal/datanode/metacache"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/etcdpb"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/metautil"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/timerecord"
@@ -52,6 +56,8 @@ type LevelZeroCompactionTaskSuite struct {
 	mockMeta     *metacache.MockMetaCache
 	task         *levelZeroCompactionTask
 
+	schema *schemapb.CollectionSchema
+
 	dData *storage.DeleteData
 	dBlob []byte
 }
@@ -60,8 +66,14 @@ func (s *LevelZeroCompactionTaskSuite) SetupTest() {
 	s.mockAlloc = allocator.NewMockAllocator(s.T())
 	s.mockBinlogIO = io.NewMockBinlogIO(s.T())
 	s.mockMeta = metacache.NewMockMetaCache(s.T())
+	s.mockMeta.EXPECT().DeltalogSorted().Return(false).Maybe()
 	// plan of the task is unset
 	s.task = newLevelZeroCompactionTask(context.Background(), s.mockBinlogIO, s.mockAlloc, s.mockMeta, nil, nil)
+	s.schema = &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+		},
+	}
 
 	pk2ts := map[int64]uint64{
 		1: 20000,
@@ -214,6 +226,7 @@ func (s *LevelZeroCompactionTaskSuite) TestCompactLinear() {
 		RunAndReturn(func(id int64, filters ...metacache.SegmentFilter) (*metacache.SegmentInfo, bool) {
 			return metacache.NewSegmentInfo(&datapb.SegmentInfo{ID: id, PartitionID: 10}, nil), true
 		})
+	s.mockMeta.EXPECT().Schema().Return(s.schema)
 
 	s.mockAlloc.EXPECT().AllocOne().Return(19530, nil).Times(2)
 	s.mockBinlogIO.EXPECT().JoinFullPath(mock.Anything, mock.Anything).
@@ -316,6 +329,7 @@ func (s *LevelZeroCompactionTaskSuite) TestCompactBatch() {
 		RunAndReturn(func(id int64, filters ...metacache.SegmentFilter) (*metacache.SegmentInfo, bool) {
 			return metacache.NewSegmentInfo(&datapb.SegmentInfo{ID: id, PartitionID: 10}, nil), true
 		})
+	s.mockMeta.EXPECT().Schema().Return(s.schema)
 
 	s.mockAlloc.EXPECT().AllocOne().Return(19530, nil).Times(2)
 	s.mockBinlogIO.EXPECT().JoinFullPath(mock.Anything, mock.Anything).
@@ -364,6 +378,7 @@ func (s *LevelZeroCompactionTaskSuite) TestUploadByCheck() {
 	s.Run("uploadByCheck directly composeDeltalog failed", func() {
 		s.SetupTest()
 		s.mockMeta.EXPECT().Collection().Return(1)
+		s.mockMeta.EXPECT().Schema().Return(s.schema)
 		s.mockMeta.EXPECT().GetSegmentByID(mock.Anything).Return(nil, false).Once()
 
 		segments := map[int64]*storage.DeleteData{100: s.dData}
@@ -377,6 +392,7 @@ func (s *LevelZeroCompactionTaskSuite) TestUploadByCheck() {
 		s.SetupTest()
 		s.mockBinlogIO.EXPECT().Upload(mock.Anything, mock.Anything).Return(errors.New("mock upload failed"))
 		s.mockMeta.EXPECT().Collection().Return(1)
+		s.mockMeta.EXPECT().Schema().Return(s.schema)
 		s.mockMeta.EXPECT().GetSegmentByID(
 			mock.MatchedBy(func(ID int64) bool {
 				return ID == 100
@@ -399,6 +415,7 @@ func (s *LevelZeroCompactionTaskSuite) TestUploadByCheck() {
 		s.SetupTest()
 		s.mockBinlogIO.EXPECT().Upload(mock.Anything, mock.Anything).Return(nil)
 		s.mockMeta.EXPECT().Collection().Return(1)
+		s.mockMeta.EXPECT().Schema().Return(s.schema)
 		s.mockMeta.EXPECT().GetSegmentByID(
 			mock.MatchedBy(func(ID int64) bool {
 				return ID == 100
@@ -419,11 +436,13 @@ func (s *LevelZeroCompactionTaskSuite) TestUploadByCheck() {
 		s.True(ok)
 		s.EqualValues(100, seg1.GetSegmentID())
 		s.Equal(1, len(seg1.GetDeltalogs()))
+		s.EqualValues(100, seg1.GetDeltalogs()[0].GetFieldID())
 		s.Equal(1, len(seg1.GetDeltalogs()[0].GetBinlogs()))
 	})
 
 	s.Run("check without upload", func() {
 		s.SetupTest()
+		s.mockMeta.EXPECT().Schema().Return(s.schema)
 		segments := map[int64]*storage.DeleteData{100: s.dData}
 		results := make(map[int64]*datapb.CompactionSegment)
 		s.Require().Empty(results)
@@ -491,7 +510,7 @@ func (s *LevelZeroCompactionTaskSuite) TestComposeDeltalog() {
 	blobPath := path.Join(common.SegmentDeltaLogPath, blobKey)
 	s.mockBinlogIO.EXPECT().JoinFullPath(mock.Anything, mock.Anything).Return(blobPath)
 
-	kvs, binlog, err := s.task.composeDeltalog(100, s.dData)
+	kvs, binlog, err := s.task.composeDeltalog(context.Background(), 100, s.dData)
 	s.NoError(err)
 	s.Equal(1, len(kvs))
 	v, ok := kvs[blobPath]
@@ -499,10 +518,74 @@ func (s *LevelZeroCompactionTaskSuite) TestComposeDeltalog() {
 	s.NotNil(v)
 	s.Equal(blobPath, binlog.LogPath)
 
-	_, _, err = s.task.composeDeltalog(101, s.dData)
+	_, _, err = s.task.composeDeltalog(context.Background(), 101, s.dData)
 	s.Error(err)
 }
 
+func (s *LevelZeroCompactionTaskSuite) TestComposeDeltalogBitmap() {
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.DeltalogBitmapEnabled.Key, "true")
+	defer paramtable.Get().Reset(paramtable.Get().DataNodeCfg.DeltalogBitmapEnabled.Key)
+
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: common.RowIDField, Name: common.RowIDFieldName, DataType: schemapb.DataType_Int64},
+			{FieldID: common.TimeStampField, Name: common.TimeStampFieldName, DataType: schemapb.DataType_Int64},
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+		},
+	}
+	// pk 1 is inserted again after it's deleted
+	iData := &storage.InsertData{Data: map[int64]storage.FieldData{
+		common.RowIDField:     &storage.Int64FieldData{Data: []int64{1, 2, 3, 4}},
+		common.TimeStampField: &storage.Int64FieldData{Data: []int64{100, 100, 100, 30000}},
+		100:                   &storage.Int64FieldData{Data: []int64{1, 2, 4, 1}},
+	}}
+	blobs, err := storage.NewInsertCodecWithSchema(&etcdpb.CollectionMeta{ID: 1, Schema: schema}).Serialize(10, 100, iData)
+	s.Require().NoError(err)
+	insertKvs := make(map[string][]byte)
+	fieldBinlogs := lo.Map(blobs, func(blob *storage.Blob, _ int) *datapb.FieldBinlog {
+		fieldID, err := strconv.ParseInt(blob.GetKey(), 10, 64)
+		s.Require().NoError(err)
+		logPath := path.Join("insert_log", blob.GetKey())
+		insertKvs[logPath] = blob.GetValue()
+		return &datapb.FieldBinlog{FieldID: fieldID, Binlogs: []*datapb.Binlog{{LogPath: logPath}}}
+	})
+	s.task.plan = &datapb.CompactionPlan{
+		SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{
+			{SegmentID: 100, Level: datapb.SegmentLevel_L1, FieldBinlogs: fieldBinlogs},
+		},
+	}
+
+	s.mockMeta.EXPECT().Collection().Return(1)
+	s.mockMeta.EXPECT().Schema().Return(schema)
+	s.mockMeta.EXPECT().GetSegmentByID(int64(100), mock.Anything).
+		Return(metacache.NewSegmentInfo(&datapb.SegmentInfo{ID: 100, PartitionID: 10}, nil), true)
+	s.mockBinlogIO.EXPECT().Download(mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, paths []string) ([][]byte, error) {
+			return lo.Map(paths, func(p string, _ int) []byte { return insertKvs[p] }), nil
+		})
+	s.mockAlloc.EXPECT().AllocOne().Return(19530, nil).Once()
+	blobPath := path.Join(common.SegmentDeltaLogPath, metautil.JoinIDPath(1, 10, 100, 19530))
+	s.mockBinlogIO.EXPECT().JoinFullPath(mock.Anything, mock.Anything).Return(blobPath).Once()
+
+	kvs, binlog, err := s.task.composeDeltalog(context.Background(), 100, s.dData)
+	s.Require().NoError(err)
+	s.Equal(blobPath, binlog.GetLogPath())
+	deltalogs, bitmap, err := storage.NewDeleteCodec().DeserializeBitmaps([]*storage.Blob{{Value: kvs[blobPath]}})
+	s.Require().NoError(err)
+	s.Empty(deltalogs)
+	s.EqualValues(4, bitmap.NumRows())
+	offsets, tss := bitmap.Records()
+	s.Equal([]int64{0, 1}, offsets)
+	s.Equal([]Timestamp{20000, 20001}, tss)
+
+	// none of the rows are deleted
+	dData := storage.NewDeleteData([]storage.PrimaryKey{storage.NewInt64PrimaryKey(3)}, []Timestamp{20002})
+	kvs, binlog, err = s.task.composeDeltalog(context.Background(), 100, dData)
+	s.NoError(err)
+	s.Nil(kvs)
+	s.Nil(binlog)
+}
+
 func (s *LevelZeroCompactionTaskSuite) TestSplitDelta() {
 	bfs1 := metacache.NewBloomFilterSetWithBatchSize(100)
 	bfs1.UpdatePKRange(&storage.Int64FieldData{Data: []int64{1, 3}})
@@ -601,3 +684,19 @@ func (s *LevelZeroCompactionTaskSuite) TestLoadDelta() {
 		}
 	}
 }
+
+func (s *LevelZeroCompactionTaskSuite) TestLoadDeltaCorrupted() {
+	s.task.plan = &datapb.CompactionPlan{
+		SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{{
+			SegmentID: 100,
+			Level:     datapb.SegmentLevel_L0,
+			Deltalogs: []*datapb.FieldBinlog{{Binlogs: []*datapb.Binlog{
+				{LogPath: "corrupted", Checksum: storage.BinlogChecksum(s.dBlob) + 1},
+			}}},
+		}},
+	}
+	s.mockBinlogIO.EXPECT().Download(mock.Anything, []string{"corrupted"}).Return([][]byte{s.dBlob}, nil).Once()
+
+	_, err := s.task.loadDelta(context.TODO(), []string{"corrupted"})
+	s.ErrorIs(err, merr.ErrIoChecksumMismatch)
+}
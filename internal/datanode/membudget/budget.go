@@ -0,0 +1,205 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package membudget provides the memory budget shared by the datanode components,
+// so that flush and compaction running at the same time cannot jointly OOM the node.
+package membudget
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/hardware"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// Category is the kind of memory accounted in the budget.
+type Category string
+
+const (
+	// InsertBuffer is the memory of the insert data buffered in write buffers.
+	InsertBuffer Category = "insert_buffer"
+	// DeleteBuffer is the memory of the delete data buffered in write buffers.
+	DeleteBuffer Category = "delete_buffer"
+	// Compaction is the working set of the compaction tasks.
+	Compaction Category = "compaction"
+	// Serialization is the scratch space of encoding buffers into binlogs.
+	Serialization Category = "serialization"
+)
+
+var (
+	budget         *Budget
+	budgetInitOnce sync.Once
+)
+
+// GetBudget returns the memory budget of the datanode,
+// whose capacity is dataNode.memory.budgetRatio of the system memory.
+func GetBudget() *Budget {
+	budgetInitOnce.Do(func() {
+		budget = NewBudget(func() int64 {
+			return int64(float64(hardware.GetMemoryCount()) * paramtable.Get().DataNodeCfg.MemoryBudgetRatio.GetAsFloat())
+		})
+	})
+	return budget
+}
+
+// Budget is a memory quota shared by several categories of memory usage.
+//
+// Buffered data has been consumed already and cannot be denied, it is tracked
+// by Track & Untrack, and the owners shall sync it when the budget is exceeded.
+// The memory of compaction and serialization is reserved before allocated,
+// a reservation is denied if it would exceed the budget, unless there is no other
+// reservation of the same category, so that every category could always make progress.
+type Budget struct {
+	mut      sync.Mutex
+	capacity func() int64 // capacity <= 0 means unlimited
+	used     map[Category]int64
+	reserved map[Category]int // number of outstanding reservations
+	released chan struct{}    // closed and renewed once any memory is released
+}
+
+// NewBudget creates a budget with the capacity provider.
+func NewBudget(capacity func() int64) *Budget {
+	return &Budget{
+		capacity: capacity,
+		used:     make(map[Category]int64),
+		reserved: make(map[Category]int),
+		released: make(chan struct{}),
+	}
+}
+
+// Capacity returns the capacity of the budget in bytes, 0 means unlimited.
+func (b *Budget) Capacity() int64 {
+	capacity := b.capacity()
+	if capacity < 0 {
+		return 0
+	}
+	return capacity
+}
+
+// Used returns the memory in bytes used by all categories.
+func (b *Budget) Used() int64 {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return b.total()
+}
+
+// UsedBy returns the memory in bytes used by the category.
+func (b *Budget) UsedBy(category Category) int64 {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return b.used[category]
+}
+
+// Exceeded returns whether the memory used is beyond the capacity.
+func (b *Budget) Exceeded() bool {
+	capacity := b.Capacity()
+	return capacity > 0 && b.Used() > capacity
+}
+
+// Track accounts the memory which has been allocated and cannot be denied.
+func (b *Budget) Track(category Category, size int64) {
+	if size <= 0 {
+		return
+	}
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	b.add(category, size)
+}
+
+// Untrack stops accounting the memory tracked before.
+func (b *Budget) Untrack(category Category, size int64) {
+	if size <= 0 {
+		return
+	}
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	b.add(category, -size)
+	b.notify()
+}
+
+// TryReserve reserves the memory for the category, returns false if the reservation is denied.
+func (b *Budget) TryReserve(category Category, size int64) bool {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	ok, _ := b.tryReserve(category, size)
+	return ok
+}
+
+// Reserve reserves the memory for the category, waits until the memory is released if denied.
+// It returns error if the context is done before the reservation is granted.
+func (b *Budget) Reserve(ctx context.Context, category Category, size int64) error {
+	for {
+		b.mut.Lock()
+		ok, released := b.tryReserve(category, size)
+		b.mut.Unlock()
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to reserve %d bytes of memory for %s, used %d of %d: %w",
+				size, category, b.Used(), b.Capacity(), ctx.Err())
+		case <-released:
+		}
+	}
+}
+
+// Release returns the memory reserved by TryReserve or Reserve.
+func (b *Budget) Release(category Category, size int64) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	if b.reserved[category] > 0 {
+		b.reserved[category]--
+	}
+	b.add(category, -size)
+	b.notify()
+}
+
+// tryReserve reserves the memory if possible, or returns the channel notified on the next release.
+func (b *Budget) tryReserve(category Category, size int64) (bool, <-chan struct{}) {
+	capacity := b.Capacity()
+	if capacity > 0 && b.reserved[category] > 0 && b.total()+size > capacity {
+		return false, b.released
+	}
+	b.reserved[category]++
+	b.add(category, size)
+	return true, nil
+}
+
+func (b *Budget) add(category Category, size int64) {
+	b.used[category] += size
+	if b.used[category] < 0 {
+		b.used[category] = 0
+	}
+	metrics.DataNodeMemoryBudgetUsage.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), string(category)).Set(float64(b.used[category]))
+}
+
+func (b *Budget) notify() {
+	close(b.released)
+	b.released = make(chan struct{})
+}
+
+func (b *Budget) total() int64 {
+	var total int64
+	for _, size := range b.used {
+		total += size
+	}
+	return total
+}
@@ -0,0 +1,120 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package membudget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type BudgetSuite struct {
+	suite.Suite
+
+	capacity int64
+	budget   *Budget
+}
+
+func (s *BudgetSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (s *BudgetSuite) SetupTest() {
+	s.capacity = 100
+	s.budget = NewBudget(func() int64 { return s.capacity })
+}
+
+func (s *BudgetSuite) TestTrack() {
+	s.budget.Track(InsertBuffer, 60)
+	s.budget.Track(DeleteBuffer, 30)
+	s.EqualValues(90, s.budget.Used())
+	s.False(s.budget.Exceeded())
+
+	// buffered data is never denied
+	s.budget.Track(InsertBuffer, 20)
+	s.EqualValues(80, s.budget.UsedBy(InsertBuffer))
+	s.True(s.budget.Exceeded())
+
+	s.budget.Untrack(InsertBuffer, 80)
+	s.budget.Untrack(DeleteBuffer, 40)
+	s.EqualValues(0, s.budget.Used())
+	s.False(s.budget.Exceeded())
+}
+
+func (s *BudgetSuite) TestTryReserve() {
+	s.budget.Track(InsertBuffer, 80)
+
+	// the first reservation of a category is always granted
+	s.True(s.budget.TryReserve(Compaction, 50))
+	s.True(s.budget.TryReserve(Serialization, 10))
+	s.False(s.budget.TryReserve(Compaction, 10))
+	s.EqualValues(50, s.budget.UsedBy(Compaction))
+
+	s.budget.Untrack(InsertBuffer, 80)
+	s.True(s.budget.TryReserve(Compaction, 40))
+	s.EqualValues(100, s.budget.Used())
+
+	s.budget.Release(Compaction, 50)
+	s.budget.Release(Compaction, 40)
+	s.budget.Release(Serialization, 10)
+	s.EqualValues(0, s.budget.Used())
+
+	// unlimited
+	s.capacity = 0
+	s.True(s.budget.TryReserve(Compaction, 200))
+	s.True(s.budget.TryReserve(Compaction, 200))
+	s.False(s.budget.Exceeded())
+}
+
+func (s *BudgetSuite) TestReserve() {
+	s.Require().True(s.budget.TryReserve(Compaction, 80))
+
+	reserved := make(chan error, 1)
+	go func() {
+		reserved <- s.budget.Reserve(context.Background(), Compaction, 50)
+	}()
+	select {
+	case <-reserved:
+		s.FailNow("reservation shall wait for the memory released")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	s.budget.Release(Compaction, 80)
+	s.NoError(<-reserved)
+	s.EqualValues(50, s.budget.UsedBy(Compaction))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.Error(s.budget.Reserve(ctx, Compaction, 60))
+	s.EqualValues(50, s.budget.UsedBy(Compaction))
+}
+
+func (s *BudgetSuite) TestGetBudget() {
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.MemoryBudgetRatio.Key, "0")
+	defer paramtable.Get().Reset(paramtable.Get().DataNodeCfg.MemoryBudgetRatio.Key)
+
+	s.Same(GetBudget(), GetBudget())
+	s.EqualValues(0, GetBudget().Capacity())
+}
+
+func TestBudget(t *testing.T) {
+	suite.Run(t, new(BudgetSuite))
+}
@@ -34,6 +34,11 @@ type BloomFilterSet struct {
 	batchSize uint
 	current   *storage.PkStatistics
 	history   []*storage.PkStatistics
+
+	// loaded is closed once the history entries of a lazy loaded BloomFilterSet are loaded, nil if not lazy loaded.
+	loaded chan struct{}
+	// unknown is set if failed to load the history entries, then every pk may exist.
+	unknown bool
 }
 
 // NewBloomFilterSet returns a BloomFilterSet with provided historyEntries.
@@ -55,9 +60,58 @@ func NewBloomFilterSetWithBatchSize(batchSize uint, historyEntries ...*storage.P
 	}
 }
 
+// NewLazyBloomFilterSet returns a BloomFilterSet whose history entries are loaded later by `Load`,
+// the lookups wait until they are loaded.
+// Shall serve Flushed segments only.
+func NewLazyBloomFilterSet() *BloomFilterSet {
+	return &BloomFilterSet{
+		batchSize: paramtable.Get().CommonCfg.BloomFilterSize.GetAsUint(),
+		loaded:    make(chan struct{}),
+	}
+}
+
+// Load loads the history entries of a lazy loaded BloomFilterSet with the loader.
+// If the loader fails, every pk is regarded as may exist.
+func (bfs *BloomFilterSet) Load(loader func() ([]*storage.PkStatistics, error)) error {
+	entries, err := loader()
+
+	bfs.mut.Lock()
+	defer bfs.mut.Unlock()
+	if err != nil {
+		bfs.unknown = true
+	} else {
+		bfs.history = append(entries, bfs.history...)
+	}
+	close(bfs.loaded)
+	return err
+}
+
+// IsLoaded returns whether the history entries are loaded.
+func (bfs *BloomFilterSet) IsLoaded() bool {
+	if bfs.loaded == nil {
+		return true
+	}
+	select {
+	case <-bfs.loaded:
+		return true
+	default:
+		return false
+	}
+}
+
+func (bfs *BloomFilterSet) waitLoaded() {
+	if bfs.loaded != nil {
+		<-bfs.loaded
+	}
+}
+
 func (bfs *BloomFilterSet) PkExists(pk storage.PrimaryKey) bool {
+	bfs.waitLoaded()
 	bfs.mut.RLock()
 	defer bfs.mut.RUnlock()
+	if bfs.unknown {
+		return true
+	}
 	if bfs.current != nil && bfs.current.PkExist(pk) {
 		return true
 	}
@@ -101,6 +155,7 @@ func (bfs *BloomFilterSet) Roll(newStats ...*storage.PrimaryKeyStats) {
 }
 
 func (bfs *BloomFilterSet) GetHistory() []*storage.PkStatistics {
+	bfs.waitLoaded()
 	bfs.mut.RLock()
 	defer bfs.mut.RUnlock()
 
@@ -18,7 +18,9 @@ package metacache
 
 import (
 	"testing"
+	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
@@ -91,6 +93,41 @@ func (s *BloomFilterSetSuite) TestRoll() {
 	s.Equal(1, len(history), "history shall have one entry after empty roll")
 }
 
+func (s *BloomFilterSetSuite) TestLazyLoad() {
+	loaded := NewBloomFilterSet()
+	s.Require().NoError(loaded.UpdatePKRange(s.GetFieldData([]int64{1, 2, 3})))
+
+	bfs := NewLazyBloomFilterSet()
+	s.False(bfs.IsLoaded())
+
+	// lookups wait until the history entries loaded
+	exists := make(chan bool, 1)
+	go func() {
+		exists <- bfs.PkExists(storage.NewInt64PrimaryKey(1))
+	}()
+	select {
+	case <-exists:
+		s.FailNow("lookup shall wait until loaded")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	s.NoError(bfs.Load(func() ([]*storage.PkStatistics, error) {
+		return []*storage.PkStatistics{loaded.current}, nil
+	}))
+	s.True(bfs.IsLoaded())
+	s.True(<-exists)
+	s.False(bfs.PkExists(storage.NewInt64PrimaryKey(4)))
+	s.Len(bfs.GetHistory(), 1)
+
+	// every pk may exist if failed to load
+	bfs = NewLazyBloomFilterSet()
+	s.Error(bfs.Load(func() ([]*storage.PkStatistics, error) {
+		return nil, errors.New("mocked")
+	}))
+	s.True(bfs.IsLoaded())
+	s.True(bfs.PkExists(storage.NewInt64PrimaryKey(4)))
+}
+
 func TestBloomFilterSet(t *testing.T) {
 	suite.Run(t, new(BloomFilterSetSuite))
 }
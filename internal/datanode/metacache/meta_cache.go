@@ -35,6 +35,14 @@ type MetaCache interface {
 	Collection() int64
 	// Schema returns collection schema.
 	Schema() *schemapb.CollectionSchema
+	// BinlogFormat returns the format of the insert binlogs of the collection.
+	BinlogFormat() string
+	// BinlogCompression returns the codec compressing the insert and delta binlogs of the collection.
+	BinlogCompression() string
+	// DeltalogSorted returns whether the deltalogs of the collection are sorted by the primary keys.
+	DeltalogSorted() bool
+	// StorageTenant returns the tenant component of the binlog paths of the collection.
+	StorageTenant() string
 	// AddSegment adds a segment from segment info.
 	AddSegment(segInfo *datapb.SegmentInfo, factory PkStatsFactory, actions ...SegmentAction)
 	// UpdateSegments applies action to segment(s) satisfy the provided filters.
@@ -58,20 +66,28 @@ var _ MetaCache = (*metaCacheImpl)(nil)
 type PkStatsFactory func(vchannel *datapb.SegmentInfo) *BloomFilterSet
 
 type metaCacheImpl struct {
-	collectionID int64
-	vChannelName string
-	segmentInfos map[int64]*SegmentInfo
-	schema       *schemapb.CollectionSchema
-	mu           sync.RWMutex
+	collectionID      int64
+	vChannelName      string
+	segmentInfos      map[int64]*SegmentInfo
+	schema            *schemapb.CollectionSchema
+	binlogFormat      string
+	storageTenant     string
+	binlogCompression string
+	deltalogSorted    bool
+	mu                sync.RWMutex
 }
 
 func NewMetaCache(info *datapb.ChannelWatchInfo, factory PkStatsFactory) MetaCache {
 	vchannel := info.GetVchan()
 	cache := &metaCacheImpl{
-		collectionID: vchannel.GetCollectionID(),
-		vChannelName: vchannel.GetChannelName(),
-		segmentInfos: make(map[int64]*SegmentInfo),
-		schema:       info.GetSchema(),
+		collectionID:      vchannel.GetCollectionID(),
+		vChannelName:      vchannel.GetChannelName(),
+		segmentInfos:      make(map[int64]*SegmentInfo),
+		schema:            info.GetSchema(),
+		binlogFormat:      info.GetBinlogFormat(),
+		storageTenant:     info.GetStorageTenant(),
+		binlogCompression: info.GetBinlogCompression(),
+		deltalogSorted:    info.GetDeltalogSorted(),
 	}
 
 	cache.init(vchannel, factory)
@@ -100,6 +116,26 @@ func (c *metaCacheImpl) Schema() *schemapb.CollectionSchema {
 	return c.schema
 }
 
+// BinlogFormat returns the format of the insert binlogs of the collection.
+func (c *metaCacheImpl) BinlogFormat() string {
+	return c.binlogFormat
+}
+
+// BinlogCompression returns the codec compressing the insert and delta binlogs of the collection.
+func (c *metaCacheImpl) BinlogCompression() string {
+	return c.binlogCompression
+}
+
+// DeltalogSorted returns whether the deltalogs of the collection are sorted by the primary keys.
+func (c *metaCacheImpl) DeltalogSorted() bool {
+	return c.deltalogSorted
+}
+
+// StorageTenant returns the tenant component of the binlog paths of the collection.
+func (c *metaCacheImpl) StorageTenant() string {
+	return c.storageTenant
+}
+
 // AddSegment adds a segment from segment info.
 func (c *metaCacheImpl) AddSegment(segInfo *datapb.SegmentInfo, factory PkStatsFactory, actions ...SegmentAction) {
 	segment := NewSegmentInfo(segInfo, factory(segInfo))
@@ -88,7 +88,11 @@ func (s *MetaCacheSuite) SetupTest() {
 	})
 
 	s.cache = NewMetaCache(&datapb.ChannelWatchInfo{
-		Schema: s.collSchema,
+		Schema:            s.collSchema,
+		BinlogFormat:      common.BinlogFormatParquet,
+		StorageTenant:     "tenant1",
+		BinlogCompression: common.BinlogCompressionZstd,
+		DeltalogSorted:    true,
 		Vchan: &datapb.VchannelInfo{
 			CollectionID:      s.collectionID,
 			ChannelName:       s.vchannel,
@@ -101,6 +105,10 @@ func (s *MetaCacheSuite) SetupTest() {
 func (s *MetaCacheSuite) TestMetaInfo() {
 	s.Equal(s.collectionID, s.cache.Collection())
 	s.Equal(s.collSchema, s.cache.Schema())
+	s.Equal(common.BinlogFormatParquet, s.cache.BinlogFormat())
+	s.Equal("tenant1", s.cache.StorageTenant())
+	s.Equal(common.BinlogCompressionZstd, s.cache.BinlogCompression())
+	s.True(s.cache.DeltalogSorted())
 }
 
 func (s *MetaCacheSuite) TestCompactSegments() {
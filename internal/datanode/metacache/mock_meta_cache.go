@@ -73,6 +73,88 @@ func (_c *MockMetaCache_AddSegment_Call) RunAndReturn(run func(*datapb.SegmentIn
 	return _c
 }
 
+// BinlogCompression provides a mock function with given fields:
+func (_m *MockMetaCache) BinlogCompression() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// MockMetaCache_BinlogCompression_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BinlogCompression'
+type MockMetaCache_BinlogCompression_Call struct {
+	*mock.Call
+}
+
+// BinlogCompression is a helper method to define mock.On call
+func (_e *MockMetaCache_Expecter) BinlogCompression() *MockMetaCache_BinlogCompression_Call {
+	return &MockMetaCache_BinlogCompression_Call{Call: _e.mock.On("BinlogCompression")}
+}
+
+func (_c *MockMetaCache_BinlogCompression_Call) Run(run func()) *MockMetaCache_BinlogCompression_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMetaCache_BinlogCompression_Call) Return(_a0 string) *MockMetaCache_BinlogCompression_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMetaCache_BinlogCompression_Call) RunAndReturn(run func() string) *MockMetaCache_BinlogCompression_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// BinlogFormat provides a mock function with given fields:
+func (_m *MockMetaCache) BinlogFormat() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// MockMetaCache_BinlogFormat_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BinlogFormat'
+type MockMetaCache_BinlogFormat_Call struct {
+	*mock.Call
+}
+
+// BinlogFormat is a helper method to define mock.On call
+func (_e *MockMetaCache_Expecter) BinlogFormat() *MockMetaCache_BinlogFormat_Call {
+	return &MockMetaCache_BinlogFormat_Call{Call: _e.mock.On("BinlogFormat")}
+}
+
+func (_c *MockMetaCache_BinlogFormat_Call) Run(run func()) *MockMetaCache_BinlogFormat_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMetaCache_BinlogFormat_Call) Return(_a0 string) *MockMetaCache_BinlogFormat_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMetaCache_BinlogFormat_Call) RunAndReturn(run func() string) *MockMetaCache_BinlogFormat_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Collection provides a mock function with given fields:
 func (_m *MockMetaCache) Collection() int64 {
 	ret := _m.Called()
@@ -165,6 +247,47 @@ func (_c *MockMetaCache_CompactSegments_Call) RunAndReturn(run func(int64, int64
 	return _c
 }
 
+// DeltalogSorted provides a mock function with given fields:
+func (_m *MockMetaCache) DeltalogSorted() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockMetaCache_DeltalogSorted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeltalogSorted'
+type MockMetaCache_DeltalogSorted_Call struct {
+	*mock.Call
+}
+
+// DeltalogSorted is a helper method to define mock.On call
+func (_e *MockMetaCache_Expecter) DeltalogSorted() *MockMetaCache_DeltalogSorted_Call {
+	return &MockMetaCache_DeltalogSorted_Call{Call: _e.mock.On("DeltalogSorted")}
+}
+
+func (_c *MockMetaCache_DeltalogSorted_Call) Run(run func()) *MockMetaCache_DeltalogSorted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMetaCache_DeltalogSorted_Call) Return(_a0 bool) *MockMetaCache_DeltalogSorted_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMetaCache_DeltalogSorted_Call) RunAndReturn(run func() bool) *MockMetaCache_DeltalogSorted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetSegmentByID provides a mock function with given fields: id, filters
 func (_m *MockMetaCache) GetSegmentByID(id int64, filters ...SegmentFilter) (*SegmentInfo, bool) {
 	_va := make([]interface{}, len(filters))
@@ -517,6 +640,47 @@ func (_c *MockMetaCache_Schema_Call) RunAndReturn(run func() *schemapb.Collectio
 	return _c
 }
 
+// StorageTenant provides a mock function with given fields:
+func (_m *MockMetaCache) StorageTenant() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// MockMetaCache_StorageTenant_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StorageTenant'
+type MockMetaCache_StorageTenant_Call struct {
+	*mock.Call
+}
+
+// StorageTenant is a helper method to define mock.On call
+func (_e *MockMetaCache_Expecter) StorageTenant() *MockMetaCache_StorageTenant_Call {
+	return &MockMetaCache_StorageTenant_Call{Call: _e.mock.On("StorageTenant")}
+}
+
+func (_c *MockMetaCache_StorageTenant_Call) Run(run func()) *MockMetaCache_StorageTenant_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMetaCache_StorageTenant_Call) Return(_a0 string) *MockMetaCache_StorageTenant_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMetaCache_StorageTenant_Call) RunAndReturn(run func() string) *MockMetaCache_StorageTenant_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateSegments provides a mock function with given fields: action, filters
 func (_m *MockMetaCache) UpdateSegments(action SegmentAction, filters ...SegmentFilter) {
 	_va := make([]interface{}, len(filters))
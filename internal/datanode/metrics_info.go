@@ -50,6 +50,20 @@ func (node *DataNode) getQuotaMetrics() (*metricsinfo.DataNodeQuotaMetrics, erro
 	}
 
 	minFGChannel, minFGTt := rateCol.getMinFlowGraphTt()
+
+	// the channels without flowgraph are being released, no need to press back the writing to them
+	channels := make([]metricsinfo.ChannelPressure, 0)
+	if node.writeBufferManager != nil {
+		for _, pressure := range node.writeBufferManager.GetChannelPressures() {
+			ds, ok := node.flowgraphManager.GetFlowgraphService(pressure.Channel)
+			if !ok {
+				continue
+			}
+			pressure.CollectionID = ds.collectionID
+			channels = append(channels, pressure)
+		}
+	}
+
 	return &metricsinfo.DataNodeQuotaMetrics{
 		Hms: metricsinfo.HardwareMetrics{},
 		Rms: rms,
@@ -62,6 +76,7 @@ func (node *DataNode) getQuotaMetrics() (*metricsinfo.DataNodeQuotaMetrics, erro
 			NodeID:        node.GetSession().ServerID,
 			CollectionIDs: node.flowgraphManager.GetCollectionIDs(),
 		},
+		Channels: channels,
 	}, nil
 }
 
@@ -242,8 +242,17 @@ func (node *DataNode) Compaction(ctx context.Context, req *datapb.CompactionPlan
 		return merr.Status(err), nil
 	}
 
-	ds, ok := node.flowgraphManager.GetFlowgraphService(req.GetChannel())
-	if !ok {
+	var meta metacache.MetaCache
+	if ds, ok := node.flowgraphManager.GetFlowgraphService(req.GetChannel()); ok {
+		meta = ds.metacache
+	} else if isStealable(req) {
+		// the plan is stolen from the datanode watching the channel, the segments of it are read and written
+		// through the object storage with the channel info carried by the plan
+		log.Info("compaction plan of the channel not in this DataNode", zap.String("channelName", req.GetChannel()))
+		meta = metacache.NewMetaCache(req.GetChannelInfo(), func(_ *datapb.SegmentInfo) *metacache.BloomFilterSet {
+			return metacache.NewBloomFilterSet()
+		})
+	} else {
 		log.Warn("illegel compaction plan, channel not in this DataNode", zap.String("channelName", req.GetChannel()))
 		return merr.Status(merr.WrapErrChannelNotFound(req.GetChannel(), "illegel compaction plan")), nil
 	}
@@ -253,7 +262,6 @@ func (node *DataNode) Compaction(ctx context.Context, req *datapb.CompactionPlan
 		return merr.Status(merr.WrapErrChannelNotFound(req.GetChannel(), "channel is dropping")), nil
 	}
 
-	meta := ds.metacache
 	for _, segment := range req.GetSegmentBinlogs() {
 		if segment.GetLevel() == datapb.SegmentLevel_L0 {
 			continue
@@ -276,21 +284,30 @@ func (node *DataNode) Compaction(ctx context.Context, req *datapb.CompactionPlan
 	var task compactor
 	switch req.GetType() {
 	case datapb.CompactionType_Level0DeleteCompaction:
-		binlogIO := io.NewBinlogIO(node.chunkManager, getOrCreateIOPool())
+		binlogIO := io.NewCollectionBinlogIO(node.chunkManager, getOrCreateIOPool(), meta.StorageTenant(), meta.BinlogCompression())
 		task = newLevelZeroCompactionTask(
 			taskCtx,
 			binlogIO,
 			node.allocator,
-			ds.metacache,
+			meta,
 			node.syncMgr,
 			req,
 		)
-	case datapb.CompactionType_MixCompaction:
-		binlogIO := io.NewBinlogIO(node.chunkManager, getOrCreateIOPool())
+	case datapb.CompactionType_StatsRefreshCompaction:
+		binlogIO := io.NewCollectionBinlogIO(node.chunkManager, getOrCreateIOPool(), meta.StorageTenant(), meta.BinlogCompression())
+		task = newStatsRefreshTask(
+			taskCtx,
+			binlogIO,
+			node.allocator,
+			meta,
+			req,
+		)
+	case datapb.CompactionType_MixCompaction, datapb.CompactionType_ClusteringCompaction:
+		binlogIO := io.NewCollectionBinlogIO(node.chunkManager, getOrCreateIOPool(), meta.StorageTenant(), meta.BinlogCompression())
 		task = newCompactionTask(
 			taskCtx,
 			binlogIO,
-			ds.metacache,
+			meta,
 			node.syncMgr,
 			node.allocator,
 			req,
@@ -304,6 +321,16 @@ func (node *DataNode) Compaction(ctx context.Context, req *datapb.CompactionPlan
 	return merr.Success(), nil
 }
 
+// isStealable returns whether the plan could be executed without the flowgraph of the channel,
+// only the merge compactions of the sealed segments carrying the channel info are.
+func isStealable(plan *datapb.CompactionPlan) bool {
+	if plan.GetChannelInfo() == nil {
+		return false
+	}
+	return plan.GetType() == datapb.CompactionType_MixCompaction ||
+		plan.GetType() == datapb.CompactionType_ClusteringCompaction
+}
+
 // GetCompactionState called by DataCoord
 // return status of all compaction plans
 func (node *DataNode) GetCompactionState(ctx context.Context, req *datapb.CompactionStateRequest) (*datapb.CompactionStateResponse, error) {
@@ -351,7 +378,7 @@ func (node *DataNode) SyncSegments(ctx context.Context, req *datapb.SyncSegments
 		log.Warn("failed to DecompressBinLog", zap.Error(err))
 		return merr.Status(err), nil
 	}
-	pks, err := loadStats(ctx, node.chunkManager, ds.metacache.Schema(), req.GetCompactedTo(), req.GetStatsLogs())
+	pks, err := loadStats(ctx, io.NewBinlogIO(node.chunkManager, getOrCreateIOPool()), ds.metacache.Schema(), req.GetCompactedTo(), req.GetStatsLogs())
 	if err != nil {
 		log.Warn("failed to load segment statslog", zap.Error(err))
 		return merr.Status(err), nil
@@ -579,7 +606,7 @@ func (node *DataNode) AddImportSegment(ctx context.Context, req *datapb.AddImpor
 				Status: merr.Status(err),
 			}, nil
 		}
-		pks, err := loadStats(ctx, node.chunkManager, ds.metacache.Schema(), req.GetSegmentId(), req.GetStatsLog())
+		pks, err := loadStats(ctx, io.NewBinlogIO(node.chunkManager, getOrCreateIOPool()), ds.metacache.Schema(), req.GetSegmentId(), req.GetStatsLog())
 		if err != nil {
 			log.Warn("failed to get segment pk stats", zap.Error(err))
 			return &datapb.AddImportSegmentResponse{
@@ -874,7 +901,7 @@ func createBinLogs(rowNum int, schema *schemapb.CollectionSchema, ts Timestamp,
 
 		k := metautil.JoinIDPath(colID, partID, segmentID, fieldID, logidx)
 
-		key := path.Join(node.chunkManager.RootPath(), common.SegmentInsertLogPath, k)
+		key := path.Join(storage.LayoutRootPath(node.chunkManager.RootPath(), colID), common.SegmentInsertLogPath, k)
 		kvs[key] = blob.Value[:]
 		field2Insert[fieldID] = &datapb.Binlog{
 			EntriesNum:    int64(rowNum),
@@ -882,6 +909,7 @@ func createBinLogs(rowNum int, schema *schemapb.CollectionSchema, ts Timestamp,
 			TimestampTo:   ts,
 			LogPath:       key,
 			LogSize:       int64(len(blob.Value)),
+			ZoneMap:       blob.ZoneMap,
 		}
 		field2Logidx[fieldID] = logidx
 	}
@@ -904,7 +932,7 @@ func createBinLogs(rowNum int, schema *schemapb.CollectionSchema, ts Timestamp,
 	// no error raise if alloc=false
 	k := metautil.JoinIDPath(colID, partID, segmentID, fieldID, logidx)
 
-	key := path.Join(node.chunkManager.RootPath(), common.SegmentStatslogPath, k)
+	key := path.Join(storage.LayoutRootPath(node.chunkManager.RootPath(), colID), common.SegmentStatslogPath, k)
 	kvs[key] = statsBinLog.Value
 	field2Stats[fieldID] = &datapb.Binlog{
 		EntriesNum:    int64(rowNum),
@@ -1062,3 +1090,50 @@ func (node *DataNode) DropImport(ctx context.Context, req *datapb.DropImportRequ
 
 	return merr.Success(), nil
 }
+
+// VerifySegment serializes the binlogs of a flushed segment of the channel again by a dry-run upload,
+// the binlogs are validated and sized as uploaded by the compactions, but never written.
+func (node *DataNode) VerifySegment(ctx context.Context, req *datapb.VerifySegmentRequest) (*datapb.VerifySegmentResponse, error) {
+	log := log.Ctx(ctx).With(zap.String("channel", req.GetChannel()),
+		zap.Int64("segmentID", req.GetSegment().GetSegmentID()))
+
+	if err := merr.CheckHealthy(node.GetStateCode()); err != nil {
+		return &datapb.VerifySegmentResponse{Status: merr.Status(err)}, nil
+	}
+
+	ds, ok := node.flowgraphManager.GetFlowgraphService(req.GetChannel())
+	if !ok {
+		log.Warn("failed to verify segment, channel not in this DataNode")
+		return &datapb.VerifySegmentResponse{Status: merr.Status(merr.WrapErrChannelNotFound(req.GetChannel()))}, nil
+	}
+	segmentID := req.GetSegment().GetSegmentID()
+	if _, ok := ds.metacache.GetSegmentByID(segmentID, metacache.WithSegmentState(commonpb.SegmentState_Flushed)); !ok {
+		log.Warn("failed to verify segment, segment with flushed state not found")
+		return &datapb.VerifySegmentResponse{Status: merr.Status(merr.WrapErrSegmentNotFound(segmentID, "segment with flushed state not found"))}, nil
+	}
+
+	binlogIO := io.NewCollectionBinlogIO(node.chunkManager, getOrCreateIOPool(), ds.metacache.StorageTenant(), ds.metacache.BinlogCompression())
+	meta := &etcdpb.CollectionMeta{ID: ds.metacache.Collection(), Schema: ds.metacache.Schema()}
+	resp, err := verifySegment(ctx, binlogIO, node.allocator, meta, ds.metacache.BinlogFormat(), req.GetSegment())
+	if err != nil {
+		log.Warn("failed to verify segment", zap.Error(err))
+		return &datapb.VerifySegmentResponse{Status: merr.Status(err)}, nil
+	}
+
+	log.Info("datanode verify segment done", zap.Int64("numRows", resp.GetNumOfRows()), zap.Int64("size", resp.GetSize()))
+	return resp, nil
+}
+
+// DropCompactionPlan stops the compaction plan and discards its result, the plan is preempted by DataCoord
+// and could be executed again later.
+func (node *DataNode) DropCompactionPlan(ctx context.Context, req *datapb.DropCompactionPlanRequest) (*commonpb.Status, error) {
+	log := log.Ctx(ctx).With(zap.Int64("planID", req.GetPlanID()), zap.String("channel", req.GetChannel()))
+	if err := merr.CheckHealthy(node.GetStateCode()); err != nil {
+		log.Warn("DataNode.DropCompactionPlan failed", zap.Int64("nodeId", node.GetNodeID()), zap.Error(err))
+		return merr.Status(err), nil
+	}
+
+	node.compactionExecutor.dropTask(req.GetPlanID())
+	log.Info("DataNode drop compaction plan done")
+	return merr.Success(), nil
+}
@@ -212,6 +212,27 @@ func (s *DataNodeServicesSuite) TestGetCompactionState() {
 	})
 }
 
+func (s *DataNodeServicesSuite) TestDropCompactionPlan() {
+	s.Run("success", func() {
+		s.node.compactionExecutor.completed.Insert(int64(1), &datapb.CompactionPlanResult{
+			PlanID: 1,
+			State:  commonpb.CompactionState_Completed,
+		})
+		s.node.compactionExecutor.completedCompactor.Insert(int64(1), newMockCompactor(true))
+
+		status, err := s.node.DropCompactionPlan(s.ctx, &datapb.DropCompactionPlanRequest{PlanID: 1})
+		s.NoError(merr.CheckRPCCall(status, err))
+		s.False(s.node.compactionExecutor.completed.Contain(1))
+	})
+
+	s.Run("unhealthy", func() {
+		node := &DataNode{}
+		node.UpdateStateCode(commonpb.StateCode_Abnormal)
+		status, _ := node.DropCompactionPlan(s.ctx, &datapb.DropCompactionPlanRequest{PlanID: 1})
+		s.Assert().Equal(merr.Code(merr.ErrServiceNotReady), status.GetCode())
+	})
+}
+
 func (s *DataNodeServicesSuite) TestCompaction() {
 	dmChannelName := "by-dev-rootcoord-dml_0_100v0"
 	schema := &schemapb.CollectionSchema{
@@ -244,6 +265,10 @@ func (s *DataNodeServicesSuite) TestCompaction() {
 	metaCache := metacache.NewMockMetaCache(s.T())
 	metaCache.EXPECT().Collection().Return(1).Maybe()
 	metaCache.EXPECT().Schema().Return(schema).Maybe()
+	metaCache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+	metaCache.EXPECT().StorageTenant().Return("").Maybe()
+	metaCache.EXPECT().BinlogCompression().Return("").Maybe()
+	metaCache.EXPECT().DeltalogSorted().Return(false).Maybe()
 	s.node.writeBufferManager.Register(dmChannelName, metaCache, nil)
 
 	fgservice.metacache.AddSegment(&datapb.SegmentInfo{
@@ -324,6 +349,116 @@ func (s *DataNodeServicesSuite) TestCompaction() {
 		s.NoError(err)
 		s.False(merr.Ok(resp))
 	})
+
+	s.Run("stolen_plan", func() {
+		node := s.node
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		otherChannel := dmChannelName + "other"
+		channelInfo := &datapb.ChannelWatchInfo{
+			Vchan: &datapb.VchannelInfo{
+				CollectionID: 1,
+				ChannelName:  otherChannel,
+				FlushedSegments: []*datapb.SegmentInfo{
+					{ID: 200, CollectionID: 1, PartitionID: 2, State: commonpb.SegmentState_Flushed, NumOfRows: 10},
+				},
+			},
+			Schema: schema,
+		}
+
+		// the level zero compactions are never stolen
+		resp, err := node.Compaction(ctx, &datapb.CompactionPlan{
+			PlanID:      1001,
+			Channel:     otherChannel,
+			Type:        datapb.CompactionType_Level0DeleteCompaction,
+			ChannelInfo: channelInfo,
+		})
+		s.NoError(err)
+		s.ErrorIs(merr.Error(resp), merr.ErrChannelNotFound)
+
+		resp, err = node.Compaction(ctx, &datapb.CompactionPlan{
+			PlanID:      1002,
+			Channel:     otherChannel,
+			Type:        datapb.CompactionType_MixCompaction,
+			ChannelInfo: channelInfo,
+			SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{
+				{SegmentID: 201, Level: datapb.SegmentLevel_L1},
+			},
+		})
+		s.NoError(err)
+		s.ErrorIs(merr.Error(resp), merr.ErrSegmentNotFound)
+
+		resp, err = node.Compaction(ctx, &datapb.CompactionPlan{
+			PlanID:      1003,
+			Channel:     otherChannel,
+			Type:        datapb.CompactionType_MixCompaction,
+			ChannelInfo: channelInfo,
+			SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{
+				{SegmentID: 200, Level: datapb.SegmentLevel_L1},
+			},
+		})
+		s.NoError(err)
+		s.True(merr.Ok(resp))
+	})
+}
+
+func (s *DataNodeServicesSuite) TestVerifySegment() {
+	dmChannelName := "by-dev-rootcoord-dml_0_101v0"
+	schema := &schemapb.CollectionSchema{
+		Name: "test_collection",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: common.RowIDField, Name: common.RowIDFieldName, DataType: schemapb.DataType_Int64},
+			{FieldID: common.TimeStampField, Name: common.TimeStampFieldName, DataType: schemapb.DataType_Int64},
+			{FieldID: common.StartOfUserFieldID, DataType: schemapb.DataType_Int64, IsPrimaryKey: true, Name: "pk"},
+		},
+	}
+	growingSegmentID := int64(101)
+
+	vchan := &datapb.VchannelInfo{
+		CollectionID:        1,
+		ChannelName:         dmChannelName,
+		UnflushedSegmentIds: []int64{},
+		FlushedSegmentIds:   []int64{},
+	}
+	err := s.node.flowgraphManager.AddandStartWithEtcdTickler(s.node, vchan, schema, genTestTickler())
+	s.Require().NoError(err)
+	defer s.node.flowgraphManager.RemoveFlowgraph(dmChannelName)
+
+	fgservice, ok := s.node.flowgraphManager.GetFlowgraphService(dmChannelName)
+	s.Require().True(ok)
+	fgservice.metacache.AddSegment(&datapb.SegmentInfo{
+		ID:            growingSegmentID,
+		CollectionID:  1,
+		PartitionID:   2,
+		StartPosition: &msgpb.MsgPosition{},
+	}, func(_ *datapb.SegmentInfo) *metacache.BloomFilterSet { return metacache.NewBloomFilterSet() })
+
+	s.Run("service_not_ready", func() {
+		node := &DataNode{}
+		node.UpdateStateCode(commonpb.StateCode_Abnormal)
+		resp, err := node.VerifySegment(s.ctx, &datapb.VerifySegmentRequest{Channel: dmChannelName})
+		s.NoError(err)
+		s.False(merr.Ok(resp.GetStatus()))
+	})
+
+	s.Run("channel_not_match", func() {
+		resp, err := s.node.VerifySegment(s.ctx, &datapb.VerifySegmentRequest{
+			Channel: dmChannelName + "other",
+			Segment: &datapb.CompactionSegmentBinlogs{SegmentID: growingSegmentID},
+		})
+		s.NoError(err)
+		s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrChannelNotFound)
+	})
+
+	s.Run("segment_not_flushed", func() {
+		resp, err := s.node.VerifySegment(s.ctx, &datapb.VerifySegmentRequest{
+			Channel: dmChannelName,
+			Segment: &datapb.CompactionSegmentBinlogs{SegmentID: growingSegmentID},
+		})
+		s.NoError(err)
+		s.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrSegmentNotFound)
+	})
 }
 
 func (s *DataNodeServicesSuite) TestFlushSegments() {
@@ -357,6 +492,10 @@ func (s *DataNodeServicesSuite) TestFlushSegments() {
 	metaCache := metacache.NewMockMetaCache(s.T())
 	metaCache.EXPECT().Collection().Return(1).Maybe()
 	metaCache.EXPECT().Schema().Return(schema).Maybe()
+	metaCache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+	metaCache.EXPECT().StorageTenant().Return("").Maybe()
+	metaCache.EXPECT().BinlogCompression().Return("").Maybe()
+	metaCache.EXPECT().DeltalogSorted().Return(false).Maybe()
 	s.node.writeBufferManager.Register(dmChannelName, metaCache, nil)
 
 	fgservice.metacache.AddSegment(&datapb.SegmentInfo{
@@ -0,0 +1,260 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/datanode/allocator"
+	"github.com/milvus-io/milvus/internal/datanode/io"
+	"github.com/milvus-io/milvus/internal/datanode/metacache"
+	"github.com/milvus-io/milvus/internal/metastore/kv/binlog"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/etcdpb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/timerecord"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// make sure statsRefreshTask implements compactor interface
+var _ compactor = (*statsRefreshTask)(nil)
+
+// statsRefreshTask rewrites the statslog of the sealed segments from their insert binlogs, the pk stats
+// cover all the rows in the binlogs, deleted or not, as the ones written by sync.
+// The binlogs of the segments are read only, so the flush of the segments is not blocked.
+type statsRefreshTask struct {
+	compactor
+	binlogIO  io.BinlogIO
+	allocator allocator.Allocator
+	metacache metacache.MetaCache
+
+	plan *datapb.CompactionPlan
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	done chan struct{}
+	tr   *timerecord.TimeRecorder
+}
+
+func newStatsRefreshTask(
+	ctx context.Context,
+	binlogIO io.BinlogIO,
+	alloc allocator.Allocator,
+	metaCache metacache.MetaCache,
+	plan *datapb.CompactionPlan,
+) *statsRefreshTask {
+	ctx, cancel := context.WithCancel(ctx)
+	return &statsRefreshTask{
+		ctx:       ctx,
+		cancel:    cancel,
+		binlogIO:  binlogIO,
+		allocator: alloc,
+		metacache: metaCache,
+		plan:      plan,
+		tr:        timerecord.NewTimeRecorder("stats refresh"),
+		done:      make(chan struct{}, 1),
+	}
+}
+
+func (t *statsRefreshTask) complete() {
+	t.done <- struct{}{}
+}
+
+func (t *statsRefreshTask) stop() {
+	t.cancel()
+	<-t.done
+}
+
+func (t *statsRefreshTask) getPlanID() UniqueID {
+	return t.plan.GetPlanID()
+}
+
+func (t *statsRefreshTask) getChannelName() string {
+	return t.plan.GetChannel()
+}
+
+func (t *statsRefreshTask) getCollection() int64 {
+	return t.metacache.Collection()
+}
+
+// Do nothing for stats refresh, no segment is blocked
+func (t *statsRefreshTask) injectDone() {}
+
+func (t *statsRefreshTask) compact() (*datapb.CompactionPlanResult, error) {
+	ctx, span := otel.Tracer(typeutil.DataNodeRole).Start(t.ctx, "StatsRefresh")
+	defer span.End()
+	log := log.Ctx(t.ctx).With(zap.Int64("planID", t.plan.GetPlanID()), zap.String("type", t.plan.GetType().String()))
+	log.Info("stats refresh", zap.Duration("wait in queue elapse", t.tr.RecordSpan()))
+
+	if !funcutil.CheckCtxValid(ctx) {
+		log.Warn("refresh wrong, task context done or timeout")
+		return nil, errContext
+	}
+
+	ctxTimeout, cancelAll := context.WithTimeout(ctx, time.Duration(t.plan.GetTimeoutInSeconds())*time.Second)
+	defer cancelAll()
+
+	if len(t.plan.GetSegmentBinlogs()) == 0 {
+		log.Warn("refresh wrong, there's no segments in segment binlogs")
+		return nil, errIllegalCompactionPlan
+	}
+	if err := binlog.DecompressCompactionBinlogs(t.plan.GetSegmentBinlogs()); err != nil {
+		log.Warn("refresh wrong, fail to decompress binlogs", zap.Error(err))
+		return nil, err
+	}
+
+	segments := make([]*datapb.CompactionSegment, 0, len(t.plan.GetSegmentBinlogs()))
+	for _, s := range t.plan.GetSegmentBinlogs() {
+		segment, err := t.refresh(ctxTimeout, s)
+		if err != nil {
+			log.Warn("refresh wrong, fail to refresh the stats of segment", zap.Int64("segmentID", s.GetSegmentID()), zap.Error(err))
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+
+	metrics.DataNodeCompactionLatency.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), t.plan.GetType().String()).
+		Observe(float64(t.tr.ElapseSpan().Milliseconds()))
+	log.Info("stats refresh finished", zap.Int("segments", len(segments)), zap.Duration("elapse", t.tr.ElapseSpan()))
+
+	return &datapb.CompactionPlanResult{
+		PlanID:   t.plan.GetPlanID(),
+		State:    commonpb.CompactionState_Completed,
+		Segments: segments,
+		Channel:  t.plan.GetChannel(),
+		Type:     t.plan.GetType(),
+	}, nil
+}
+
+// refresh reads the insert binlogs of the segment batch by batch and uploads the statslog of the stats
+// collected over all the batches, the field stats are collected if dataNode.segment.fieldStatsEnabled.
+func (t *statsRefreshTask) refresh(ctx context.Context, s *datapb.CompactionSegmentBinlogs) (*datapb.CompactionSegment, error) {
+	schema := t.metacache.Schema()
+	pkField, err := typeutil.GetPrimaryFieldSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		binlogNum int
+		numRows   int64
+	)
+	for _, f := range s.GetFieldBinlogs() {
+		if f.GetFieldID() == pkField.GetFieldID() {
+			binlogNum = len(f.GetBinlogs())
+			numRows = lo.SumBy(f.GetBinlogs(), func(b *datapb.Binlog) int64 { return b.GetEntriesNum() })
+		}
+	}
+	if binlogNum == 0 {
+		return nil, errIllegalCompactionPlan
+	}
+
+	stats, err := storage.NewPrimaryKeyStats(pkField.GetFieldID(), int64(pkField.GetDataType()), numRows)
+	if err != nil {
+		return nil, err
+	}
+	withFieldStats := Params.DataNodeCfg.FieldStatsEnabled.GetAsBool()
+	fieldStats := make(map[int64]*storage.FieldStats)
+
+	meta := &etcdpb.CollectionMeta{ID: t.metacache.Collection(), Schema: schema}
+	iCodec := storage.NewInsertCodecWithSchema(meta)
+	checksum := binlogChecksum([]*datapb.CompactionSegmentBinlogs{s})
+
+	var rowNum int64
+	for idx := 0; idx < binlogNum; idx++ {
+		var paths []string
+		for _, f := range s.GetFieldBinlogs() {
+			// only the pk binlogs are read if no field stats are collected
+			if !withFieldStats && f.GetFieldID() != pkField.GetFieldID() {
+				continue
+			}
+			if idx >= len(f.GetBinlogs()) {
+				return nil, fmt.Errorf("binlogs of field %d not aligned, %d binlogs, expected %d", f.GetFieldID(), len(f.GetBinlogs()), binlogNum)
+			}
+			paths = append(paths, f.GetBinlogs()[idx].GetLogPath())
+		}
+
+		blobs, err := downloadBlobs(ctx, t.binlogIO, paths)
+		if err != nil {
+			return nil, err
+		}
+		if err := verifyBlobs(paths, blobs, checksum); err != nil {
+			return nil, err
+		}
+		_, _, data, err := iCodec.Deserialize(blobs)
+		if err != nil {
+			return nil, err
+		}
+
+		pkData, ok := data.Data[pkField.GetFieldID()]
+		if !ok {
+			return nil, fmt.Errorf("no pk data of field %d in the binlogs of batch %d", pkField.GetFieldID(), idx)
+		}
+		stats.UpdateByMsgs(pkData)
+		rowNum += int64(pkData.RowNum())
+
+		if withFieldStats {
+			batchStats, err := storage.NewFieldStatsByData(schema, data, Params.DataNodeCfg.HLLPrecision.GetAsInt())
+			if err != nil {
+				return nil, err
+			}
+			for _, fs := range batchStats {
+				merged, ok := fieldStats[fs.FieldID]
+				if !ok {
+					fieldStats[fs.FieldID] = fs
+					continue
+				}
+				if err := merged.Merge(fs); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if withFieldStats {
+		// in the order of the fields in schema
+		stats.SetFieldStats(lo.FilterMap(schema.GetFields(), func(field *schemapb.FieldSchema, _ int) (*storage.FieldStats, bool) {
+			fs, ok := fieldStats[field.GetFieldID()]
+			return fs, ok
+		}))
+	}
+
+	statPaths, err := uploadStatsLog(ctx, t.binlogIO, t.allocator, t.metacache.Collection(), s.GetPartitionID(), s.GetSegmentID(), stats, rowNum, iCodec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &datapb.CompactionSegment{
+		SegmentID:           s.GetSegmentID(),
+		Field2StatslogPaths: lo.Values(statPaths),
+		NumOfRows:           rowNum,
+		Channel:             t.plan.GetChannel(),
+	}, nil
+}
@@ -0,0 +1,189 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/datanode/allocator"
+	"github.com/milvus-io/milvus/internal/datanode/io"
+	"github.com/milvus-io/milvus/internal/datanode/metacache"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/etcdpb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestStatsRefreshTaskSuite(t *testing.T) {
+	suite.Run(t, new(StatsRefreshTaskSuite))
+}
+
+type StatsRefreshTaskSuite struct {
+	suite.Suite
+
+	cm        storage.ChunkManager
+	binlogIO  io.BinlogIO
+	alloc     *allocator.MockAllocator
+	metaCache *metacache.MockMetaCache
+	meta      *etcdpb.CollectionMeta
+}
+
+func (s *StatsRefreshTaskSuite) SetupSuite() {
+	paramtable.Get().Init(paramtable.NewBaseTable())
+}
+
+func (s *StatsRefreshTaskSuite) SetupTest() {
+	s.cm = storage.NewLocalChunkManager(storage.RootPath(compactTestDir))
+	s.binlogIO = io.NewBinlogIO(s.cm, getOrCreateIOPool())
+	s.meta = NewMetaFactory().GetCollectionMeta(1, "test", schemapb.DataType_Int64)
+
+	s.alloc = allocator.NewMockAllocator(s.T())
+	s.alloc.EXPECT().GetGenerator(mock.Anything, mock.Anything).Call.Return(validGeneratorFn, nil).Maybe()
+	s.alloc.EXPECT().AllocOne().Return(100, nil).Maybe()
+
+	s.metaCache = metacache.NewMockMetaCache(s.T())
+	s.metaCache.EXPECT().Schema().Return(s.meta.GetSchema()).Maybe()
+	s.metaCache.EXPECT().Collection().Return(s.meta.GetID()).Maybe()
+}
+
+func (s *StatsRefreshTaskSuite) TearDownTest() {
+	s.cm.RemoveWithPrefix(context.Background(), s.cm.RootPath())
+	paramtable.Get().Reset(Params.DataNodeCfg.FieldStatsEnabled.Key)
+}
+
+// uploadBatches uploads the insert data as the batches of the segment, and returns the field binlogs of them.
+func (s *StatsRefreshTaskSuite) uploadBatches(batches ...*InsertData) []*datapb.FieldBinlog {
+	iCodec := storage.NewInsertCodecWithSchema(s.meta)
+	fieldBinlogs := make(map[int64]*datapb.FieldBinlog)
+	for i, data := range batches {
+		// uploaded to the paths of different partitions, so the binlogs of the batches are not overwritten
+		inPaths, err := uploadInsertLog(context.Background(), s.binlogIO, s.alloc, s.meta.GetID(), int64(i), 1, data, iCodec)
+		s.Require().NoError(err)
+		for fieldID, fieldBinlog := range inPaths {
+			if _, ok := fieldBinlogs[fieldID]; !ok {
+				fieldBinlogs[fieldID] = &datapb.FieldBinlog{FieldID: fieldID}
+			}
+			fieldBinlogs[fieldID].Binlogs = append(fieldBinlogs[fieldID].Binlogs, fieldBinlog.GetBinlogs()...)
+		}
+	}
+	result := make([]*datapb.FieldBinlog, 0, len(fieldBinlogs))
+	for _, fieldBinlog := range fieldBinlogs {
+		result = append(result, fieldBinlog)
+	}
+	return result
+}
+
+func (s *StatsRefreshTaskSuite) newTask(fieldBinlogs []*datapb.FieldBinlog) *statsRefreshTask {
+	return newStatsRefreshTask(context.Background(), s.binlogIO, s.alloc, s.metaCache, &datapb.CompactionPlan{
+		PlanID:           19530,
+		Type:             datapb.CompactionType_StatsRefreshCompaction,
+		Channel:          "ch-1",
+		TimeoutInSeconds: 60,
+		SegmentBinlogs: []*datapb.CompactionSegmentBinlogs{
+			{SegmentID: 1, PartitionID: 10, Level: datapb.SegmentLevel_L1, FieldBinlogs: fieldBinlogs},
+		},
+	})
+}
+
+func (s *StatsRefreshTaskSuite) readStats(result *datapb.CompactionPlanResult) *storage.PrimaryKeyStats {
+	s.Require().Equal(1, len(result.GetSegments()))
+	statslogs := result.GetSegments()[0].GetField2StatslogPaths()
+	s.Require().Equal(1, len(statslogs))
+	s.Require().Equal(1, len(statslogs[0].GetBinlogs()))
+
+	values, err := s.binlogIO.Download(context.Background(), []string{statslogs[0].GetBinlogs()[0].GetLogPath()})
+	s.Require().NoError(err)
+	stats, err := storage.DeserializeStats([]*storage.Blob{{Value: values[0]}})
+	s.Require().NoError(err)
+	s.Require().Equal(1, len(stats))
+	return stats[0]
+}
+
+func (s *StatsRefreshTaskSuite) TestRefresh() {
+	paramtable.Get().Save(Params.DataNodeCfg.FieldStatsEnabled.Key, "true")
+	fieldBinlogs := s.uploadBatches(genInsertData(10), genInsertData(20))
+
+	task := s.newTask(fieldBinlogs)
+	result, err := task.compact()
+	s.Require().NoError(err)
+	s.Equal(commonpb.CompactionState_Completed, result.GetState())
+	s.Equal(datapb.CompactionType_StatsRefreshCompaction, result.GetType())
+
+	segment := result.GetSegments()[0]
+	s.EqualValues(1, segment.GetSegmentID())
+	s.EqualValues(30, segment.GetNumOfRows())
+	s.Empty(segment.GetInsertLogs())
+
+	stats := s.readStats(result)
+	s.EqualValues(106, stats.FieldID)
+	s.Equal(storage.NewInt64PrimaryKey(0), stats.MinPk)
+	s.Equal(storage.NewInt64PrimaryKey(19), stats.MaxPk)
+	pkStats := &storage.PkStatistics{PkFilter: stats.BF, MinPK: stats.MinPk, MaxPK: stats.MaxPk}
+	s.True(pkStats.PkExist(storage.NewInt64PrimaryKey(15)))
+
+	// the stats of the fields are merged over the batches
+	fieldStats, err := storage.MergeFieldStats([]*storage.PrimaryKeyStats{stats})
+	s.Require().NoError(err)
+	s.Contains(fieldStats, int64(105))
+	s.EqualValues(0, fieldStats[105].Min)
+	s.EqualValues(19, fieldStats[105].Max)
+}
+
+func (s *StatsRefreshTaskSuite) TestRefreshPkOnly() {
+	paramtable.Get().Save(Params.DataNodeCfg.FieldStatsEnabled.Key, "false")
+	fieldBinlogs := s.uploadBatches(genInsertData(10))
+
+	result, err := s.newTask(fieldBinlogs).compact()
+	s.Require().NoError(err)
+	s.EqualValues(10, result.GetSegments()[0].GetNumOfRows())
+
+	stats := s.readStats(result)
+	s.Equal(storage.NewInt64PrimaryKey(9), stats.MaxPk)
+	s.Empty(stats.FieldStats)
+}
+
+func (s *StatsRefreshTaskSuite) TestRefreshFailed() {
+	s.Run("corrupted binlog", func() {
+		fieldBinlogs := s.uploadBatches(genInsertData(10))
+		for _, fieldBinlog := range fieldBinlogs {
+			if fieldBinlog.GetFieldID() == 106 {
+				fieldBinlog.GetBinlogs()[0].Checksum++
+			}
+		}
+		_, err := s.newTask(fieldBinlogs).compact()
+		s.ErrorIs(err, merr.ErrIoChecksumMismatch)
+	})
+
+	s.Run("no pk binlogs", func() {
+		_, err := s.newTask(nil).compact()
+		s.ErrorIs(err, errIllegalCompactionPlan)
+	})
+
+	s.Run("task stopped", func() {
+		task := s.newTask(s.uploadBatches(genInsertData(10)))
+		task.cancel()
+		_, err := task.compact()
+		s.ErrorIs(err, errContext)
+	})
+}
@@ -0,0 +1,117 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncmgr
+
+import (
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const (
+	// weight of the latest sample in the moving average
+	storageStatsAlpha = 0.2
+
+	// bounds of the ratio to scale the configured sync size by
+	minSyncScale = 0.25
+	maxSyncScale = 2.0
+)
+
+// storageStats is the exponentially weighted moving average of the latency and throughput of
+// the object storage writes of the sync tasks.
+type storageStats struct {
+	mut        sync.RWMutex
+	samples    int
+	latency    float64 // milliseconds
+	throughput float64 // bytes per second
+}
+
+// globalStorageStats is shared by all sync tasks of the datanode, which write to the same object storage.
+var globalStorageStats = &storageStats{}
+
+// Observe records a write of size bytes which took elapse.
+func (s *storageStats) Observe(size int64, elapse time.Duration) {
+	if elapse <= 0 {
+		return
+	}
+	latency := float64(elapse) / float64(time.Millisecond)
+	throughput := float64(size) / elapse.Seconds()
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if s.samples == 0 {
+		s.latency, s.throughput = latency, throughput
+	} else {
+		s.latency = storageStatsAlpha*latency + (1-storageStatsAlpha)*s.latency
+		s.throughput = storageStatsAlpha*throughput + (1-storageStatsAlpha)*s.throughput
+	}
+	s.samples++
+}
+
+// Get returns the average latency in milliseconds and throughput in bytes per second,
+// ok is false if nothing observed.
+func (s *storageStats) Get() (latency float64, throughput float64, ok bool) {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	return s.latency, s.throughput, s.samples > 0
+}
+
+// syncScale returns the ratio to scale the configured sync size by, which is the target latency
+// over the measured one, so the syncs are smaller when the storage is slow and larger when it is fast.
+// It's 1 if adaptive sync is disabled or nothing observed.
+func (s *storageStats) syncScale() float64 {
+	params := paramtable.Get()
+	if !params.DataNodeCfg.AdaptiveSyncEnabled.GetAsBool() {
+		return 1
+	}
+	latency, _, ok := s.Get()
+	target := params.DataNodeCfg.AdaptiveSyncTargetLatency.GetAsFloat()
+	if !ok || latency <= 0 || target <= 0 {
+		return 1
+	}
+	scale := target / latency
+	if scale < minSyncScale {
+		return minSyncScale
+	}
+	if scale > maxSyncScale {
+		return maxSyncScale
+	}
+	return scale
+}
+
+// syncBufferSize returns the buffer size to sync a segment.
+func (s *storageStats) syncBufferSize() int64 {
+	size := paramtable.Get().DataNodeCfg.FlushInsertBufferSize.GetAsInt64()
+	return int64(float64(size) * s.syncScale())
+}
+
+// parallelSyncTasks returns the max parallel sync tasks, which is raised when the syncs are scaled down
+// for the slow storage, to keep the throughput.
+func (s *storageStats) parallelSyncTasks() int {
+	parallel := paramtable.Get().DataNodeCfg.MaxParallelSyncMgrTasks.GetAsInt()
+	if scale := s.syncScale(); scale < 1 {
+		return int(float64(parallel) / scale)
+	}
+	return parallel
+}
+
+// SyncBufferSize returns the buffer size to sync a segment, which is dataNode.segment.insertBufSize
+// adapted to the measured latency of the object storage if adaptive sync is enabled.
+func SyncBufferSize() int64 {
+	return globalStorageStats.syncBufferSize()
+}
@@ -0,0 +1,104 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncmgr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type StorageStatsSuite struct {
+	suite.Suite
+}
+
+func (s *StorageStatsSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (s *StorageStatsSuite) SetupTest() {
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.AdaptiveSyncEnabled.Key, "true")
+}
+
+func (s *StorageStatsSuite) TearDownTest() {
+	paramtable.Get().Reset(paramtable.Get().DataNodeCfg.AdaptiveSyncEnabled.Key)
+}
+
+func (s *StorageStatsSuite) TestObserve() {
+	stats := &storageStats{}
+	_, _, ok := stats.Get()
+	s.False(ok)
+
+	stats.Observe(1000, time.Second)
+	latency, throughput, ok := stats.Get()
+	s.True(ok)
+	s.InDelta(1000, latency, 1e-6)
+	s.InDelta(1000, throughput, 1e-6)
+
+	stats.Observe(2000, 2*time.Second)
+	latency, throughput, _ = stats.Get()
+	s.InDelta(1200, latency, 1e-6)
+	s.InDelta(1000, throughput, 1e-6)
+
+	// ignored
+	stats.Observe(1000, 0)
+	latency, _, _ = stats.Get()
+	s.InDelta(1200, latency, 1e-6)
+}
+
+func (s *StorageStatsSuite) TestAdapt() {
+	params := paramtable.Get()
+	bufferSize := params.DataNodeCfg.FlushInsertBufferSize.GetAsInt64()
+	parallel := params.DataNodeCfg.MaxParallelSyncMgrTasks.GetAsInt()
+
+	// nothing observed
+	stats := &storageStats{}
+	s.EqualValues(1, stats.syncScale())
+	s.Equal(bufferSize, stats.syncBufferSize())
+	s.Equal(parallel, stats.parallelSyncTasks())
+
+	// slow storage, smaller and more parallel syncs
+	stats.Observe(1000, 2*time.Second)
+	s.InDelta(0.5, stats.syncScale(), 1e-6)
+	s.Equal(bufferSize/2, stats.syncBufferSize())
+	s.Equal(parallel*2, stats.parallelSyncTasks())
+
+	// too slow
+	stats = &storageStats{}
+	stats.Observe(1000, time.Minute)
+	s.InDelta(minSyncScale, stats.syncScale(), 1e-6)
+	s.Equal(parallel*4, stats.parallelSyncTasks())
+
+	// fast storage, larger syncs
+	stats = &storageStats{}
+	stats.Observe(1000, 100*time.Millisecond)
+	s.InDelta(maxSyncScale, stats.syncScale(), 1e-6)
+	s.Equal(bufferSize*2, stats.syncBufferSize())
+	s.Equal(parallel, stats.parallelSyncTasks())
+
+	// disabled
+	params.Save(params.DataNodeCfg.AdaptiveSyncEnabled.Key, "false")
+	s.EqualValues(1, stats.syncScale())
+	s.Equal(bufferSize, stats.syncBufferSize())
+}
+
+func TestStorageStats(t *testing.T) {
+	suite.Run(t, new(StorageStatsSuite))
+}
@@ -10,10 +10,12 @@ import (
 	"github.com/milvus-io/milvus/internal/datanode/broker"
 	"github.com/milvus-io/milvus/internal/datanode/metacache"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/retry"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
 // MetaWriter is the interface for SyncManager to write segment sync meta.
@@ -125,10 +127,19 @@ func (b *brokerMetaWriter) UpdateSync(pack *SyncTask) error {
 		return nil
 	}, b.opts...)
 	if err != nil {
-		log.Warn("failed to SaveBinlogPaths",
+		// the binlog paths may be saved even though the rpc failed, e.g. the response lost,
+		// the sync is done if datacoord recorded the binlogs of it
+		recorded, reconcileErr := b.reconcileSync(pack)
+		if !recorded {
+			log.Warn("failed to SaveBinlogPaths",
+				zap.Int64("segmentID", pack.segmentID),
+				zap.Error(err),
+				zap.NamedError("reconcileErr", reconcileErr))
+			return err
+		}
+		log.Info("binlog paths recorded by datacoord already, ignore the failure of SaveBinlogPaths",
 			zap.Int64("segmentID", pack.segmentID),
 			zap.Error(err))
-		return err
 	}
 
 	pack.metacache.UpdateSegments(metacache.SetStartPosRecorded(true), metacache.WithSegmentIDs(lo.Map(startPos, func(pos *datapb.SegmentStartPosition, _ int) int64 { return pos.GetSegmentID() })...))
@@ -136,6 +147,39 @@ func (b *brokerMetaWriter) UpdateSync(pack *SyncTask) error {
 	return nil
 }
 
+// reconcileSync returns whether the binlogs of the sync task are all recorded in the segment meta of datacoord.
+// The compound stats log is not checked, as its log id is shared by the flushes of all segments.
+func (b *brokerMetaWriter) reconcileSync(pack *SyncTask) (bool, error) {
+	collectLogIDs := func(logIDs typeutil.Set[int64], fieldBinlogs ...*datapb.FieldBinlog) {
+		for _, fieldBinlog := range fieldBinlogs {
+			for _, binlog := range fieldBinlog.GetBinlogs() {
+				if binlog.GetLogID() != int64(storage.CompoundStatsType) {
+					logIDs.Insert(binlog.GetLogID())
+				}
+			}
+		}
+	}
+	synced := typeutil.NewSet[int64]()
+	collectLogIDs(synced, lo.Values(pack.insertBinlogs)...)
+	collectLogIDs(synced, lo.Values(pack.statsBinlogs)...)
+	collectLogIDs(synced, pack.deltaBinlog)
+	if synced.Len() == 0 {
+		return false, nil
+	}
+
+	infos, err := b.broker.GetSegmentInfo(context.Background(), []int64{pack.segmentID})
+	if err != nil {
+		return false, err
+	}
+	recorded := typeutil.NewSet[int64]()
+	for _, info := range infos {
+		collectLogIDs(recorded, info.GetBinlogs()...)
+		collectLogIDs(recorded, info.GetStatslogs()...)
+		collectLogIDs(recorded, info.GetDeltalogs()...)
+	}
+	return recorded.Contain(synced.Collect()...), nil
+}
+
 func (b *brokerMetaWriter) UpdateSyncV2(pack *SyncTaskV2) error {
 	checkPoints := []*datapb.CheckPoint{}
 
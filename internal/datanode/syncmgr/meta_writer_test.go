@@ -62,6 +62,43 @@ func (s *MetaWriterSuite) TestReturnError() {
 	s.Error(err)
 }
 
+func (s *MetaWriterSuite) TestReconcileSync() {
+	s.broker.EXPECT().SaveBinlogPaths(mock.Anything, mock.Anything).Return(errors.New("mocked"))
+
+	bfs := metacache.NewBloomFilterSet()
+	seg := metacache.NewSegmentInfo(&datapb.SegmentInfo{}, bfs)
+	metacache.UpdateNumOfRows(1000)(seg)
+	s.metacache.EXPECT().GetSegmentByID(mock.Anything).Return(seg, true)
+	s.metacache.EXPECT().GetSegmentsBy(mock.Anything).Return([]*metacache.SegmentInfo{seg})
+	task := NewSyncTask().WithSegmentID(100)
+	task.WithMetaCache(s.metacache)
+	task.appendBinlog(101, &datapb.Binlog{LogID: 1001})
+	task.appendDeltalog(&datapb.Binlog{LogID: 1002})
+
+	s.Run("recorded", func() {
+		s.broker.EXPECT().GetSegmentInfo(mock.Anything, []int64{100}).Return([]*datapb.SegmentInfo{{
+			ID:        100,
+			Binlogs:   []*datapb.FieldBinlog{{FieldID: 101, Binlogs: []*datapb.Binlog{{LogID: 1000}, {LogID: 1001}}}},
+			Deltalogs: []*datapb.FieldBinlog{{Binlogs: []*datapb.Binlog{{LogID: 1002}}}},
+		}}, nil).Once()
+		s.metacache.EXPECT().UpdateSegments(mock.Anything, mock.Anything).Return().Once()
+		s.NoError(s.writer.UpdateSync(task))
+	})
+
+	s.Run("not_recorded", func() {
+		s.broker.EXPECT().GetSegmentInfo(mock.Anything, []int64{100}).Return([]*datapb.SegmentInfo{{
+			ID:      100,
+			Binlogs: []*datapb.FieldBinlog{{FieldID: 101, Binlogs: []*datapb.Binlog{{LogID: 1000}}}},
+		}}, nil).Once()
+		s.Error(s.writer.UpdateSync(task))
+	})
+
+	s.Run("get_segment_failed", func() {
+		s.broker.EXPECT().GetSegmentInfo(mock.Anything, []int64{100}).Return(nil, errors.New("mocked")).Once()
+		s.Error(s.writer.UpdateSync(task))
+	})
+}
+
 func (s *MetaWriterSuite) TestNormalSaveV2() {
 	s.broker.EXPECT().SaveBinlogPaths(mock.Anything, mock.Anything).Return(nil)
 
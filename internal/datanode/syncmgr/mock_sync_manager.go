@@ -112,6 +112,48 @@ func (_c *MockSyncManager_GetEarliestPosition_Call) RunAndReturn(run func(string
 	return _c
 }
 
+// GetPendingTaskNum provides a mock function with given fields: channel
+func (_m *MockSyncManager) GetPendingTaskNum(channel string) int {
+	ret := _m.Called(channel)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(channel)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// MockSyncManager_GetPendingTaskNum_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingTaskNum'
+type MockSyncManager_GetPendingTaskNum_Call struct {
+	*mock.Call
+}
+
+// GetPendingTaskNum is a helper method to define mock.On call
+//   - channel string
+func (_e *MockSyncManager_Expecter) GetPendingTaskNum(channel interface{}) *MockSyncManager_GetPendingTaskNum_Call {
+	return &MockSyncManager_GetPendingTaskNum_Call{Call: _e.mock.On("GetPendingTaskNum", channel)}
+}
+
+func (_c *MockSyncManager_GetPendingTaskNum_Call) Run(run func(channel string)) *MockSyncManager_GetPendingTaskNum_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockSyncManager_GetPendingTaskNum_Call) Return(_a0 int) *MockSyncManager_GetPendingTaskNum_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSyncManager_GetPendingTaskNum_Call) RunAndReturn(run func(string) int) *MockSyncManager_GetPendingTaskNum_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SyncData provides a mock function with given fields: ctx, task
 func (_m *MockSyncManager) SyncData(ctx context.Context, task Task) *conc.Future[error] {
 	ret := _m.Called(ctx, task)
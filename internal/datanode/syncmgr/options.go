@@ -1,7 +1,10 @@
 package syncmgr
 
 import (
+	"context"
+
 	"github.com/samber/lo"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
@@ -116,3 +119,19 @@ func (t *SyncTask) WithLevel(level datapb.SegmentLevel) *SyncTask {
 	t.level = level
 	return t
 }
+
+func (t *SyncTask) WithStorageTenant(tenant string) *SyncTask {
+	t.storageTenant = tenant
+	return t
+}
+
+func (t *SyncTask) WithBinlogCompression(compression string) *SyncTask {
+	t.binlogCompression = compression
+	return t
+}
+
+// WithTraceContext traces the task in the trace of the context, the task isn't canceled along with the context.
+func (t *SyncTask) WithTraceContext(ctx context.Context) *SyncTask {
+	t.traceCtx = trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+	return t
+}
@@ -55,6 +55,19 @@ type SyncPack struct {
 	level        datapb.SegmentLevel
 }
 
+// scratchSize returns the estimated scratch memory to serialize the pack,
+// which is about the size of the buffered data.
+func (p *SyncPack) scratchSize() int64 {
+	var size int64
+	if p.insertData != nil {
+		size += int64(p.insertData.GetMemorySize())
+	}
+	if p.deltaData != nil {
+		size += p.deltaData.Size()
+	}
+	return size
+}
+
 func (p *SyncPack) WithInsertData(insertData *storage.InsertData) *SyncPack {
 	p.insertData = insertData
 	return p
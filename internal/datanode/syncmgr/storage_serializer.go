@@ -25,6 +25,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/datanode/membudget"
 	"github.com/milvus-io/milvus/internal/datanode/metacache"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/etcdpb"
@@ -41,8 +42,7 @@ type storageV1Serializer struct {
 	schema       *schemapb.CollectionSchema
 	pkField      *schemapb.FieldSchema
 
-	inCodec  *storage.InsertCodec
-	delCodec *storage.DeleteCodec
+	inCodec *storage.InsertCodec
 
 	metacache  metacache.MetaCache
 	metaWriter MetaWriter
@@ -60,13 +60,13 @@ func NewStorageSerializer(metacache metacache.MetaCache, metaWriter MetaWriter)
 		ID:     collectionID,
 	}
 	inCodec := storage.NewInsertCodecWithSchema(meta)
+	inCodec.BinlogFormat = metacache.BinlogFormat()
 	return &storageV1Serializer{
 		collectionID: collectionID,
 		schema:       schema,
 		pkField:      pkField,
 
 		inCodec:    inCodec,
-		delCodec:   storage.NewDeleteCodec(),
 		metacache:  metacache,
 		metaWriter: metaWriter,
 	}, nil
@@ -82,6 +82,13 @@ func (s *storageV1Serializer) EncodeBuffer(ctx context.Context, pack *SyncPack)
 		zap.String("channel", pack.channelName),
 	)
 
+	scratchSize := pack.scratchSize()
+	if err := membudget.GetBudget().Reserve(ctx, membudget.Serialization, scratchSize); err != nil {
+		log.Warn("failed to reserve memory for serialization", zap.Error(err))
+		return nil, err
+	}
+	defer membudget.GetBudget().Release(membudget.Serialization, scratchSize)
+
 	if pack.insertData != nil {
 		memSize := make(map[int64]int64)
 		for fieldID, fieldData := range pack.insertData.Data {
@@ -120,13 +127,12 @@ func (s *storageV1Serializer) EncodeBuffer(ctx context.Context, pack *SyncPack)
 	}
 
 	if pack.deltaData != nil {
-		deltaBlob, err := s.serializeDeltalog(pack)
+		deltaBlobs, err := s.serializeDeltalog(pack)
 		if err != nil {
 			log.Warn("failed to serialize delta log", zap.Error(err))
 			return nil, err
 		}
-		task.deltaBlob = deltaBlob
-		task.deltaRowCount = pack.deltaData.RowCount
+		task.deltaBlobs = deltaBlobs
 	}
 	if pack.isDrop {
 		task.WithDrop()
@@ -148,6 +154,8 @@ func (s *storageV1Serializer) setTaskMeta(task *SyncTask, pack *SyncPack) {
 		WithStartPosition(pack.startPosition).
 		WithCheckpoint(pack.checkpoint).
 		WithLevel(pack.level).
+		WithStorageTenant(s.metacache.StorageTenant()).
+		WithBinlogCompression(s.metacache.BinlogCompression()).
 		WithTimeRange(pack.tsFrom, pack.tsTo).
 		WithMetaCache(s.metacache).
 		WithMetaWriter(s.metaWriter).
@@ -157,12 +165,22 @@ func (s *storageV1Serializer) setTaskMeta(task *SyncTask, pack *SyncPack) {
 		})
 }
 
+// serializeBinlog returns the binlogs keyed by field id.
 func (s *storageV1Serializer) serializeBinlog(ctx context.Context, pack *SyncPack) (map[int64]*storage.Blob, error) {
-	blobs, err := s.inCodec.Serialize(pack.partitionID, pack.segmentID, pack.insertData)
+	record, err := storage.NewSortedInsertRecord(ctx, s.schema, pack.insertData)
 	if err != nil {
 		return nil, err
 	}
+	defer record.Release()
 
+	blobs, err := s.inCodec.SerializeRecord(pack.partitionID, pack.segmentID, record)
+	if err != nil {
+		return nil, err
+	}
+	return blobsByFieldID(blobs)
+}
+
+func blobsByFieldID(blobs []*storage.Blob) (map[int64]*storage.Blob, error) {
 	result := make(map[int64]*storage.Blob)
 	for _, blob := range blobs {
 		fieldID, err := strconv.ParseInt(blob.GetKey(), 10, 64)
@@ -185,6 +203,13 @@ func (s *storageV1Serializer) serializeStatslog(pack *SyncPack) (*storage.Primar
 		return nil, nil, err
 	}
 	stats.UpdateByMsgs(pkFieldData)
+	if params := paramtable.Get(); params.DataNodeCfg.FieldStatsEnabled.GetAsBool() {
+		fieldStats, err := storage.NewFieldStatsByData(s.schema, pack.insertData, params.DataNodeCfg.HLLPrecision.GetAsInt())
+		if err != nil {
+			return nil, nil, err
+		}
+		stats.SetFieldStats(fieldStats)
+	}
 
 	blob, err := s.inCodec.SerializePkStats(stats, pack.batchSize)
 	if err != nil {
@@ -210,6 +235,10 @@ func (s *storageV1Serializer) serializeMergedPkStats(pack *SyncPack) (*storage.B
 	}), segment.NumOfRows())
 }
 
-func (s *storageV1Serializer) serializeDeltalog(pack *SyncPack) (*storage.Blob, error) {
-	return s.delCodec.Serialize(pack.collectionID, pack.partitionID, pack.segmentID, pack.deltaData)
+// serializeDeltalog serializes the delete data into the deltalogs of dataNode.segment.deltalogChunkSize at most,
+// which are sorted by the primary keys if the collection writes sorted deltalogs, see metacache.DeltalogSorted.
+func (s *storageV1Serializer) serializeDeltalog(pack *SyncPack) ([]*storage.Blob, error) {
+	chunkSize := paramtable.Get().DataNodeCfg.DeltalogChunkSize.GetAsInt64()
+	delCodec := &storage.DeleteCodec{SortedByPK: s.metacache.DeltalogSorted()}
+	return delCodec.SerializeChunks(pack.collectionID, pack.partitionID, pack.segmentID, pack.deltaData, chunkSize)
 }
@@ -34,6 +34,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/tsoutil"
 )
 
@@ -54,6 +55,8 @@ type StorageV1SerializerSuite struct {
 }
 
 func (s *StorageV1SerializerSuite) SetupSuite() {
+	paramtable.Init()
+
 	s.collectionID = rand.Int63n(100) + 1000
 	s.partitionID = rand.Int63n(100) + 2000
 	s.segmentID = rand.Int63n(1000) + 10000
@@ -87,6 +90,10 @@ func (s *StorageV1SerializerSuite) SetupSuite() {
 func (s *StorageV1SerializerSuite) SetupTest() {
 	s.mockCache.EXPECT().Collection().Return(s.collectionID)
 	s.mockCache.EXPECT().Schema().Return(s.schema)
+	s.mockCache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+	s.mockCache.EXPECT().StorageTenant().Return("").Maybe()
+	s.mockCache.EXPECT().BinlogCompression().Return("").Maybe()
+	s.mockCache.EXPECT().DeltalogSorted().Return(false).Maybe()
 
 	var err error
 	s.serializer, err = NewStorageSerializer(s.mockCache, s.mockMetaWriter)
@@ -229,6 +236,14 @@ func (s *StorageV1SerializerSuite) TestSerializeInsert() {
 		s.EqualValues(100, taskV1.tsTo)
 		s.Len(taskV1.binlogBlobs, 4)
 		s.NotNil(taskV1.batchStatsBlob)
+
+		// the statslog carries the stats of the scalar fields along with the pk bloom filter
+		stats, err := storage.DeserializeStats([]*storage.Blob{taskV1.batchStatsBlob})
+		s.Require().NoError(err)
+		s.Equal(storage.StatsFormatV2, stats[0].Version)
+		s.Require().Len(stats[0].FieldStats, 1)
+		s.EqualValues(100, stats[0].FieldStats[0].FieldID)
+		s.EqualValues(10, stats[0].FieldStats[0].Cardinality())
 	})
 
 	s.Run("with_flush_segment_not_found", func() {
@@ -307,7 +322,7 @@ func (s *StorageV1SerializerSuite) TestSerializeDelete() {
 		}, taskV1.checkpoint)
 		s.EqualValues(50, taskV1.tsFrom)
 		s.EqualValues(100, taskV1.tsTo)
-		s.NotNil(taskV1.deltaBlob)
+		s.Len(taskV1.deltaBlobs, 1)
 	})
 }
 
@@ -315,6 +330,10 @@ func (s *StorageV1SerializerSuite) TestBadSchema() {
 	mockCache := metacache.NewMockMetaCache(s.T())
 	mockCache.EXPECT().Collection().Return(s.collectionID).Once()
 	mockCache.EXPECT().Schema().Return(&schemapb.CollectionSchema{}).Once()
+	mockCache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+	mockCache.EXPECT().StorageTenant().Return("").Maybe()
+	mockCache.EXPECT().BinlogCompression().Return("").Maybe()
+	mockCache.EXPECT().DeltalogSorted().Return(false).Maybe()
 	_, err := NewStorageSerializer(mockCache, s.mockMetaWriter)
 	s.Error(err)
 }
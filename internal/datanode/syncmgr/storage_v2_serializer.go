@@ -29,6 +29,7 @@ import (
 	milvus_storage "github.com/milvus-io/milvus-storage/go/storage"
 	"github.com/milvus-io/milvus-storage/go/storage/options"
 	"github.com/milvus-io/milvus-storage/go/storage/schema"
+	"github.com/milvus-io/milvus/internal/datanode/membudget"
 	"github.com/milvus-io/milvus/internal/datanode/metacache"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/querycoordv2/params"
@@ -75,6 +76,13 @@ func (s *storageV2Serializer) EncodeBuffer(ctx context.Context, pack *SyncPack)
 	tr := timerecord.NewTimeRecorder("storage_serializer_v2")
 	metricSegLevel := pack.level.String()
 
+	scratchSize := pack.scratchSize()
+	if err := membudget.GetBudget().Reserve(ctx, membudget.Serialization, scratchSize); err != nil {
+		log.Warn("failed to reserve memory for serialization", zap.Error(err))
+		return nil, err
+	}
+	defer membudget.GetBudget().Release(membudget.Serialization, scratchSize)
+
 	space, err := s.storageV2Cache.GetOrCreateSpace(pack.segmentID, SpaceCreatorFunc(pack.segmentID, s.schema, s.arrowSchema))
 	if err != nil {
 		log.Warn("failed to get or create space", zap.Error(err))
@@ -97,6 +97,10 @@ func (s *StorageV2SerializerSuite) SetupTest() {
 
 	s.mockCache.EXPECT().Collection().Return(s.collectionID)
 	s.mockCache.EXPECT().Schema().Return(s.schema)
+	s.mockCache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+	s.mockCache.EXPECT().StorageTenant().Return("").Maybe()
+	s.mockCache.EXPECT().BinlogCompression().Return("").Maybe()
+	s.mockCache.EXPECT().DeltalogSorted().Return(false).Maybe()
 
 	s.serializer, err = NewStorageV2Serializer(storageCache, s.mockCache, s.mockMetaWriter)
 	s.Require().NoError(err)
@@ -355,6 +359,10 @@ func (s *StorageV2SerializerSuite) TestBadSchema() {
 	mockCache := metacache.NewMockMetaCache(s.T())
 	mockCache.EXPECT().Collection().Return(s.collectionID).Once()
 	mockCache.EXPECT().Schema().Return(&schemapb.CollectionSchema{}).Once()
+	mockCache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+	mockCache.EXPECT().StorageTenant().Return("").Maybe()
+	mockCache.EXPECT().BinlogCompression().Return("").Maybe()
+	mockCache.EXPECT().DeltalogSorted().Return(false).Maybe()
 	_, err := NewStorageV2Serializer(s.storageCache, mockCache, s.mockMetaWriter)
 	s.Error(err)
 }
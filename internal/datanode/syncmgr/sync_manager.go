@@ -47,6 +47,8 @@ type SyncManager interface {
 	SyncData(ctx context.Context, task Task) *conc.Future[error]
 	// GetEarliestPosition returns the earliest position (normally start position) of the processing sync task of provided channel.
 	GetEarliestPosition(channel string) (int64, *msgpb.MsgPosition)
+	// GetPendingTaskNum returns the number of the submitted but unfinished sync tasks of provided channel.
+	GetPendingTaskNum(channel string) int
 	// Block allows caller to block tasks of provided segment id.
 	// normally used by compaction task.
 	// if levelzero delta policy is enabled, this shall be an empty operation.
@@ -104,10 +106,32 @@ func (mgr *syncManager) resizeHandler(evt *config.Event) {
 	}
 }
 
+// adaptParallelism resizes the worker pool to the parallelism adapted to the storage latency,
+// it's a no-op unless adaptive sync is enabled.
+func (mgr *syncManager) adaptParallelism() {
+	if !paramtable.Get().DataNodeCfg.AdaptiveSyncEnabled.GetAsBool() {
+		return
+	}
+	size := globalStorageStats.parallelSyncTasks()
+	if size == mgr.workerPool.Cap() {
+		return
+	}
+	if err := mgr.workerPool.Resize(size); err != nil {
+		log.Warn("failed to adapt datanode syncmgr pool size", zap.Int("size", size), zap.Error(err))
+		return
+	}
+	latency, throughput, _ := globalStorageStats.Get()
+	log.Info("sync mgr pool size adapted to storage latency",
+		zap.Int("newSize", size),
+		zap.Float64("latency(ms)", latency),
+		zap.Float64("throughput(bytes/s)", throughput),
+		zap.Int64("syncBufferSize", globalStorageStats.syncBufferSize()))
+}
+
 func (mgr *syncManager) SyncData(ctx context.Context, task Task) *conc.Future[error] {
 	switch t := task.(type) {
 	case *SyncTask:
-		t.WithAllocator(mgr.allocator).WithChunkManager(mgr.chunkManager)
+		t.WithAllocator(mgr.allocator).WithChunkManager(mgr.chunkManager).WithTraceContext(ctx)
 	case *SyncTaskV2:
 		t.WithAllocator(mgr.allocator)
 	}
@@ -138,6 +162,7 @@ func (mgr *syncManager) safeSubmitTask(task Task) *conc.Future[error] {
 			// if previous sync task is not finished, block here
 			f := mgr.Submit(targetID, task)
 			err, _ = f.Await()
+			mgr.adaptParallelism()
 			if errors.Is(err, errTargetSegmentNotMatch) {
 				log.Info("target updated during submitting", zap.Error(err))
 				continue
@@ -165,6 +190,17 @@ func (mgr *syncManager) GetEarliestPosition(channel string) (int64, *msgpb.MsgPo
 	return segmentID, cp
 }
 
+func (mgr *syncManager) GetPendingTaskNum(channel string) int {
+	var num int
+	mgr.tasks.Range(func(_ string, task Task) bool {
+		if task.ChannelName() == channel {
+			num++
+		}
+		return true
+	})
+	return num
+}
+
 func (mgr *syncManager) Block(segmentID int64) {
 	mgr.keyLock.Lock(segmentID)
 }
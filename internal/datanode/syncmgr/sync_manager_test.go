@@ -291,6 +291,28 @@ func (s *SyncManagerSuite) TestResizePool() {
 	s.Equal(cap*2, syncMgr.keyLockDispatcher.workerPool.Cap())
 }
 
+func (s *SyncManagerSuite) TestAdaptParallelism() {
+	manager, err := NewSyncManager(s.chunkManager, s.allocator)
+	s.NoError(err)
+	syncMgr, ok := manager.(*syncManager)
+	s.Require().True(ok)
+	cap := syncMgr.keyLockDispatcher.workerPool.Cap()
+
+	defer func(stats *storageStats) { globalStorageStats = stats }(globalStorageStats)
+	globalStorageStats = &storageStats{}
+	globalStorageStats.Observe(1000, 2*time.Second)
+
+	// disabled
+	syncMgr.adaptParallelism()
+	s.Equal(cap, syncMgr.keyLockDispatcher.workerPool.Cap())
+
+	params := paramtable.Get()
+	params.Save(params.DataNodeCfg.AdaptiveSyncEnabled.Key, "true")
+	defer params.Reset(params.DataNodeCfg.AdaptiveSyncEnabled.Key)
+	syncMgr.adaptParallelism()
+	s.Equal(cap*2, syncMgr.keyLockDispatcher.workerPool.Cap())
+}
+
 func (s *SyncManagerSuite) TestNewSyncManager() {
 	manager, err := NewSyncManager(s.chunkManager, s.allocator)
 	s.NoError(err)
@@ -325,6 +347,31 @@ func (s *SyncManagerSuite) TestTargetUpdated() {
 	s.NoError(err)
 }
 
+func (s *SyncManagerSuite) TestGetPendingTaskNum() {
+	manager, err := NewSyncManager(s.chunkManager, s.allocator)
+	s.NoError(err)
+
+	sig := make(chan struct{})
+	task := NewMockTask(s.T())
+	task.EXPECT().SegmentID().Return(1000)
+	task.EXPECT().ChannelName().Return(s.channelName)
+	task.EXPECT().Checkpoint().Return(&msgpb.MsgPosition{})
+	task.EXPECT().CalcTargetSegment().Return(1000, nil)
+	task.EXPECT().Run().RunAndReturn(func() error {
+		<-sig
+		return nil
+	})
+
+	f := manager.SyncData(context.Background(), task)
+	s.Equal(1, manager.GetPendingTaskNum(s.channelName))
+	s.Equal(0, manager.GetPendingTaskNum("other-channel"))
+
+	close(sig)
+	err, _ = f.Await()
+	s.NoError(err)
+	s.Equal(0, manager.GetPendingTaskNum(s.channelName))
+}
+
 func TestSyncManager(t *testing.T) {
 	suite.Run(t, new(SyncManagerSuite))
 }
@@ -20,9 +20,13 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/samber/lo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
@@ -30,6 +34,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/allocator"
+	"github.com/milvus-io/milvus/internal/datanode/io"
 	"github.com/milvus-io/milvus/internal/datanode/metacache"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/storage"
@@ -63,6 +68,10 @@ type SyncTask struct {
 	// not the total num of rows of segemnt
 	batchSize int64
 	level     datapb.SegmentLevel
+	// storageTenant is the tenant component of the binlog paths, no tenant component if empty
+	storageTenant string
+	// binlogCompression is the codec compressing the insert and delta binlogs, uncompressed if empty
+	binlogCompression string
 
 	// targetSegmentID stores the "current" segmentID task shall be handling
 	targetSegmentID atomic.Int64
@@ -84,11 +93,13 @@ type SyncTask struct {
 	binlogMemsize   map[int64]int64         // memory size
 	batchStatsBlob  *storage.Blob
 	mergedStatsBlob *storage.Blob
-	deltaBlob       *storage.Blob
-	deltaRowCount   int64
+	deltaBlobs      []*storage.Blob
 
 	// prefetched log ids
 	ids []int64
+	// serialized is whether the binlogs are serialized with the log ids reserved for serializedSegmentID
+	serialized          bool
+	serializedSegmentID int64
 
 	segmentData map[string][]byte
 
@@ -96,6 +107,9 @@ type SyncTask struct {
 
 	failureCallback func(err error)
 
+	// traceCtx carries the trace of the sync, without the cancellation of the caller
+	traceCtx context.Context
+
 	tr *timerecord.TimeRecorder
 }
 
@@ -126,11 +140,21 @@ func (t *SyncTask) handleError(err error) {
 func (t *SyncTask) Run() (err error) {
 	t.tr = timerecord.NewTimeRecorder("syncTask")
 
+	ctx := t.traceCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := otel.Tracer(typeutil.DataNodeRole).Start(ctx, "SyncTask", trace.WithAttributes(
+		attribute.Int64("segmentID", t.segmentID),
+		attribute.String("channel", t.channelName),
+	))
+
 	log := t.getLogger()
 	defer func() {
 		if err != nil {
 			t.handleError(err)
 		}
+		io.EndIOSpan(span, err)
 	}()
 
 	var has bool
@@ -162,17 +186,11 @@ func (t *SyncTask) Run() (err error) {
 		t.segmentID = t.segment.CompactTo()
 	}
 
-	err = t.prefetchIDs()
-	if err != nil {
-		log.Warn("failed allocate ids for sync task", zap.Error(err))
+	if err = t.serializeBinlogs(); err != nil {
 		return err
 	}
 
-	t.processInsertBlobs()
-	t.processStatsBlob()
-	t.processDeltaBlob()
-
-	err = t.writeLogs()
+	err = t.writeLogs(ctx)
 	if err != nil {
 		log.Warn("failed to save serialized data into storage", zap.Error(err))
 		t.handleError(err)
@@ -183,9 +201,9 @@ func (t *SyncTask) Run() (err error) {
 	totalSize += lo.SumBy(lo.Values(t.binlogMemsize), func(fieldSize int64) float64 {
 		return float64(fieldSize)
 	})
-	if t.deltaBlob != nil {
-		totalSize += float64(len(t.deltaBlob.Value))
-	}
+	totalSize += lo.SumBy(t.deltaBlobs, func(blob *storage.Blob) float64 {
+		return float64(len(blob.Value))
+	})
 
 	metrics.DataNodeFlushedSize.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), metrics.AllLabel, t.level.String()).Add(totalSize)
 
@@ -211,6 +229,7 @@ func (t *SyncTask) Run() (err error) {
 	t.metacache.UpdateSegments(metacache.MergeSegmentAction(actions...), metacache.WithSegmentIDs(t.segment.SegmentID()))
 
 	log.Info("task done", zap.Float64("flushedSize", totalSize))
+	t.releaseBlobs()
 
 	if !t.isFlush {
 		metrics.DataNodeAutoFlushBufferCount.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), metrics.SuccessLabel, t.level.String()).Inc()
@@ -219,15 +238,55 @@ func (t *SyncTask) Run() (err error) {
 	return nil
 }
 
+// serializeBinlogs reserves the log ids and serializes the binlogs of the segment once, the binlogs are kept
+// for the retries of the task, so a retried task overwrites the binlogs uploaded before rather than leaking them
+// under new log ids. The binlogs are serialized again only if the segment is compacted to another one since.
+func (t *SyncTask) serializeBinlogs() error {
+	if t.serialized && t.serializedSegmentID == t.segmentID {
+		return nil
+	}
+	log := t.getLogger()
+	t.insertBinlogs = make(map[int64]*datapb.FieldBinlog)
+	t.statsBinlogs = make(map[int64]*datapb.FieldBinlog)
+	t.deltaBinlog = &datapb.FieldBinlog{FieldID: t.pkField.GetFieldID()}
+	t.segmentData = make(map[string][]byte)
+
+	if err := t.prefetchIDs(); err != nil {
+		log.Warn("failed allocate ids for sync task", zap.Error(err))
+		return err
+	}
+	if err := t.processInsertBlobs(); err != nil {
+		log.Warn("failed to compress insert binlogs", zap.Error(err))
+		return err
+	}
+	t.processStatsBlob()
+	if err := t.processDeltaBlob(); err != nil {
+		log.Warn("failed to compress delta binlogs", zap.Error(err))
+		return err
+	}
+	if err := t.processInventory(); err != nil {
+		log.Warn("failed to serialize segment inventory", zap.Error(err))
+		return err
+	}
+	t.serialized = true
+	t.serializedSegmentID = t.segmentID
+	return nil
+}
+
+// releaseBlobs returns the pooled buffers of the binlogs to the serialization once the task is done,
+// the blobs are kept for the retries till then.
+func (t *SyncTask) releaseBlobs() {
+	storage.ReleaseBlobs(lo.Values(t.binlogBlobs)...)
+	storage.ReleaseBlobs(t.deltaBlobs...)
+}
+
 // prefetchIDs pre-allcates ids depending on the number of blobs current task contains.
 func (t *SyncTask) prefetchIDs() error {
 	totalIDCount := len(t.binlogBlobs)
 	if t.batchStatsBlob != nil {
 		totalIDCount++
 	}
-	if t.deltaBlob != nil {
-		totalIDCount++
-	}
+	totalIDCount += len(t.deltaBlobs)
 	start, _, err := t.allocator.Alloc(uint32(totalIDCount))
 	if err != nil {
 		return err
@@ -245,19 +304,35 @@ func (t *SyncTask) nextID() int64 {
 	return r
 }
 
-func (t *SyncTask) processInsertBlobs() {
+// rootPath returns the root path of the binlogs, with the tenant component and the layout dir if any.
+func (t *SyncTask) rootPath() string {
+	return storage.LayoutRootPath(metautil.TenantRootPath(t.chunkManager.RootPath(), t.storageTenant), t.collectionID)
+}
+
+// processInsertBlobs compresses the insert binlogs if the binlog compression is set,
+// the checksums are computed on the uncompressed binlogs as the readers verify them after decompression.
+func (t *SyncTask) processInsertBlobs() error {
 	for fieldID, blob := range t.binlogBlobs {
-		k := metautil.JoinIDPath(t.collectionID, t.partitionID, t.segmentID, fieldID, t.nextID())
-		key := path.Join(t.chunkManager.RootPath(), common.SegmentInsertLogPath, k)
-		t.segmentData[key] = blob.GetValue()
+		logID := t.nextID()
+		k := metautil.JoinIDPath(t.collectionID, t.partitionID, t.segmentID, fieldID, logID)
+		key := path.Join(t.rootPath(), common.SegmentInsertLogPath, k)
+		value, err := storage.CompressBinlog(t.binlogCompression, blob.GetValue())
+		if err != nil {
+			return err
+		}
+		t.segmentData[key] = value
 		t.appendBinlog(fieldID, &datapb.Binlog{
 			EntriesNum:    blob.RowNum,
 			TimestampFrom: t.tsFrom,
 			TimestampTo:   t.tsTo,
 			LogPath:       key,
+			LogID:         logID,
 			LogSize:       t.binlogMemsize[fieldID],
+			Checksum:      storage.BinlogChecksum(blob.GetValue()),
+			ZoneMap:       blob.ZoneMap,
 		})
 	}
+	return nil
 }
 
 func (t *SyncTask) processStatsBlob() {
@@ -270,27 +345,56 @@ func (t *SyncTask) processStatsBlob() {
 	}
 }
 
-func (t *SyncTask) processDeltaBlob() {
-	if t.deltaBlob != nil {
-		value := t.deltaBlob.GetValue()
+func (t *SyncTask) processDeltaBlob() error {
+	for _, blob := range t.deltaBlobs {
+		value := blob.GetValue()
 		data := &datapb.Binlog{}
 
-		blobKey := metautil.JoinIDPath(t.collectionID, t.partitionID, t.segmentID, t.nextID())
-		blobPath := path.Join(t.chunkManager.RootPath(), common.SegmentDeltaLogPath, blobKey)
+		logID := t.nextID()
+		blobKey := metautil.JoinIDPath(t.collectionID, t.partitionID, t.segmentID, logID)
+		blobPath := path.Join(t.rootPath(), common.SegmentDeltaLogPath, blobKey)
 
-		t.segmentData[blobPath] = value
-		data.LogSize = int64(len(t.deltaBlob.Value))
+		compressed, err := storage.CompressBinlog(t.binlogCompression, value)
+		if err != nil {
+			return err
+		}
+		t.segmentData[blobPath] = compressed
+		data.LogSize = int64(len(blob.Value))
 		data.LogPath = blobPath
+		data.LogID = logID
 		data.TimestampFrom = t.tsFrom
 		data.TimestampTo = t.tsTo
-		data.EntriesNum = t.deltaRowCount
+		data.EntriesNum = blob.RowNum
+		data.Checksum = storage.BinlogChecksum(value)
 		t.appendDeltalog(data)
 	}
+	return nil
+}
+
+// processInventory lists the binlogs of the task in the inventory of the segment if dataNode.upload.inventoryEnabled,
+// the inventory is written along with the binlogs.
+func (t *SyncTask) processInventory() error {
+	if !paramtable.Get().DataNodeCfg.UploadInventory.GetAsBool() {
+		return nil
+	}
+	inv := storage.NewSegmentInventory(t.collectionID, t.partitionID, t.segmentID)
+	inv.AddFieldBinlogs(common.SegmentInsertLogPath, lo.Values(t.insertBinlogs)...)
+	inv.AddFieldBinlogs(common.SegmentStatslogPath, lo.Values(t.statsBinlogs)...)
+	inv.AddFieldBinlogs(common.SegmentDeltaLogPath, t.deltaBinlog)
+	if len(inv.Binlogs) == 0 {
+		return nil
+	}
+	value, err := inv.Marshal()
+	if err != nil {
+		return err
+	}
+	t.segmentData[inv.Key(t.rootPath())] = value
+	return nil
 }
 
 func (t *SyncTask) convertBlob2StatsBinlog(blob *storage.Blob, fieldID, logID int64, rowNum int64) {
 	key := metautil.JoinIDPath(t.collectionID, t.partitionID, t.segmentID, fieldID, logID)
-	key = path.Join(t.chunkManager.RootPath(), common.SegmentStatslogPath, key)
+	key = path.Join(t.rootPath(), common.SegmentStatslogPath, key)
 
 	value := blob.GetValue()
 	t.segmentData[key] = value
@@ -299,7 +403,9 @@ func (t *SyncTask) convertBlob2StatsBinlog(blob *storage.Blob, fieldID, logID in
 		TimestampFrom: t.tsFrom,
 		TimestampTo:   t.tsTo,
 		LogPath:       key,
+		LogID:         logID,
 		LogSize:       int64(len(value)),
+		Checksum:      storage.BinlogChecksum(value),
 	})
 }
 
@@ -331,9 +437,31 @@ func (t *SyncTask) appendDeltalog(deltalog *datapb.Binlog) {
 }
 
 // writeLogs writes log files (binlog/deltalog/statslog) into storage via chunkManger.
-func (t *SyncTask) writeLogs() error {
-	return retry.Do(context.Background(), func() error {
-		return t.chunkManager.MultiWrite(context.Background(), t.segmentData)
+// The latency of the successful writes is observed to adapt the sync size and concurrency.
+// The writes are admitted by the upload scheduler of the datanode ahead of the compaction uploads,
+// and retried within the retry policy of the flushes.
+func (t *SyncTask) writeLogs(ctx context.Context) (err error) {
+	var size int64
+	for _, data := range t.segmentData {
+		size += int64(len(data))
+	}
+	ctx, span := io.StartIOSpan(ctx, "MultiWrite", lo.Keys(t.segmentData), size)
+	defer func() {
+		io.EndIOSpan(span, err)
+	}()
+
+	release, err := io.GetUploadScheduler().Acquire(ctx, io.UploadPriorityFlush, size)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return io.Retry(io.WithUploadPriority(ctx, io.UploadPriorityFlush), func(ctx context.Context) error {
+		start := time.Now()
+		err := t.chunkManager.MultiWrite(ctx, t.segmentData)
+		if err == nil {
+			globalStorageStats.Observe(size, time.Since(start))
+		}
+		return err
 	}, t.writeRetryOpts...)
 }
 
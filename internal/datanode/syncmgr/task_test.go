@@ -17,8 +17,10 @@
 package syncmgr
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
+	"strings"
 	"testing"
 	"time"
 
@@ -37,6 +39,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/retry"
 	"github.com/milvus-io/milvus/pkg/util/tsoutil"
@@ -220,6 +223,145 @@ func (s *SyncTaskSuite) TestRunNormal() {
 		s.NoError(err)
 	})
 
+	s.Run("with_storage_tenant", func() {
+		task := s.getSuiteSyncTask()
+		task.WithTimeRange(50, 100)
+		task.WithMetaWriter(BrokerMetaWriter(s.broker, 1))
+		task.WithCheckpoint(&msgpb.MsgPosition{
+			ChannelName: s.channelName,
+			MsgID:       []byte{1, 2, 3, 4},
+			Timestamp:   100,
+		})
+		task.WithStorageTenant("tenant1")
+		task.binlogBlobs[100] = &storage.Blob{
+			Key:   "100",
+			Value: []byte("test_data"),
+		}
+		task.deltaBlobs = []*storage.Blob{{
+			Key:   "100",
+			Value: []byte("test_data"),
+		}}
+
+		err := task.Run()
+		s.Require().NoError(err)
+		insertLogPath := task.insertBinlogs[100].GetBinlogs()[0].GetLogPath()
+		s.True(strings.HasPrefix(insertLogPath, "files/tenant=tenant1/insert_log/"))
+		s.Equal("tenant1", metautil.GetTenantFromLogPath(insertLogPath))
+		deltaLogPath := task.deltaBinlog.GetBinlogs()[0].GetLogPath()
+		s.True(strings.HasPrefix(deltaLogPath, "files/tenant=tenant1/delta_log/"))
+	})
+
+	s.Run("with_inventory", func() {
+		paramtable.Get().Save(paramtable.Get().DataNodeCfg.UploadInventory.Key, "true")
+		defer paramtable.Get().Reset(paramtable.Get().DataNodeCfg.UploadInventory.Key)
+		task := s.getSuiteSyncTask()
+		task.WithTimeRange(50, 100)
+		task.WithMetaWriter(BrokerMetaWriter(s.broker, 1))
+		task.WithCheckpoint(&msgpb.MsgPosition{
+			ChannelName: s.channelName,
+			MsgID:       []byte{1, 2, 3, 4},
+			Timestamp:   100,
+		})
+		task.binlogBlobs[100] = &storage.Blob{
+			Key:   "100",
+			Value: []byte("test_data"),
+		}
+		task.deltaBlobs = []*storage.Blob{{
+			Key:   "100",
+			Value: []byte("test_delta"),
+		}}
+
+		err := task.Run()
+		s.Require().NoError(err)
+		prefix := storage.SegmentInventoryPrefix("files", task.collectionID, task.partitionID, task.segmentID)
+		var inv *storage.SegmentInventory
+		for key, value := range task.segmentData {
+			if strings.HasPrefix(key, prefix) {
+				inv, err = storage.UnmarshalSegmentInventory(value)
+				s.Require().NoError(err)
+			}
+		}
+		s.Require().NotNil(inv)
+		s.Len(inv.Binlogs, len(task.segmentData)-1)
+		for _, binlog := range inv.Binlogs {
+			s.Contains(task.segmentData, binlog.Path)
+		}
+	})
+
+	s.Run("with_checksum", func() {
+		task := s.getSuiteSyncTask()
+		task.WithTimeRange(50, 100)
+		task.WithMetaWriter(BrokerMetaWriter(s.broker, 1))
+		task.WithCheckpoint(&msgpb.MsgPosition{
+			ChannelName: s.channelName,
+			MsgID:       []byte{1, 2, 3, 4},
+			Timestamp:   100,
+		})
+		task.binlogBlobs[100] = &storage.Blob{
+			Key:   "100",
+			Value: []byte("test_data"),
+		}
+		task.deltaBlobs = []*storage.Blob{{
+			Key:   "100",
+			Value: []byte("test_delta"),
+		}}
+
+		err := task.Run()
+		s.Require().NoError(err)
+		s.Equal(storage.BinlogChecksum([]byte("test_data")), task.insertBinlogs[100].GetBinlogs()[0].GetChecksum())
+		s.Equal(storage.BinlogChecksum([]byte("test_delta")), task.deltaBinlog.GetBinlogs()[0].GetChecksum())
+	})
+
+	s.Run("with_compression", func() {
+		task := s.getSuiteSyncTask()
+		task.WithTimeRange(50, 100)
+		task.WithMetaWriter(BrokerMetaWriter(s.broker, 1))
+		task.WithBinlogCompression(common.BinlogCompressionSnappy)
+		task.WithCheckpoint(&msgpb.MsgPosition{
+			ChannelName: s.channelName,
+			MsgID:       []byte{1, 2, 3, 4},
+			Timestamp:   100,
+		})
+		task.binlogBlobs[100] = &storage.Blob{
+			Key:   "100",
+			Value: []byte("test_data"),
+		}
+		task.deltaBlobs = []*storage.Blob{{
+			Key:   "100",
+			Value: []byte("test_delta"),
+		}}
+
+		err := task.Run()
+		s.Require().NoError(err)
+		s.Len(task.segmentData, 2)
+		for _, value := range task.segmentData {
+			s.True(storage.IsCompressedBinlog(value))
+		}
+		insertLog := task.insertBinlogs[100].GetBinlogs()[0]
+		value, err := storage.DecompressBinlog(task.segmentData[insertLog.GetLogPath()])
+		s.NoError(err)
+		s.Equal([]byte("test_data"), value)
+		s.Equal(storage.BinlogChecksum([]byte("test_data")), insertLog.GetChecksum())
+	})
+
+	s.Run("with_unknown_compression", func() {
+		task := s.getSuiteSyncTask()
+		task.WithTimeRange(50, 100)
+		task.WithBinlogCompression("gzip")
+		task.WithCheckpoint(&msgpb.MsgPosition{
+			ChannelName: s.channelName,
+			MsgID:       []byte{1, 2, 3, 4},
+			Timestamp:   100,
+		})
+		task.binlogBlobs[100] = &storage.Blob{
+			Key:   "100",
+			Value: []byte("test_data"),
+		}
+
+		err := task.Run()
+		s.Error(err)
+	})
+
 	s.Run("with_statslog", func() {
 		task := s.getSuiteSyncTask()
 		task.WithTimeRange(50, 100)
@@ -253,10 +395,10 @@ func (s *SyncTaskSuite) TestRunNormal() {
 			Timestamp:   100,
 		})
 		task.WithDrop()
-		task.deltaBlob = &storage.Blob{
+		task.deltaBlobs = []*storage.Blob{{
 			Key:   "100",
 			Value: []byte("test_data"),
-		}
+		}}
 
 		err := task.Run()
 		s.NoError(err)
@@ -273,10 +415,10 @@ func (s *SyncTaskSuite) TestRunL0Segment() {
 
 	s.Run("pure_delete_l0_flush", func() {
 		task := s.getSuiteSyncTask()
-		task.deltaBlob = &storage.Blob{
+		task.deltaBlobs = []*storage.Blob{{
 			Key:   "100",
 			Value: []byte("test_data"),
-		}
+		}}
 		task.WithTimeRange(50, 100)
 		task.WithMetaWriter(BrokerMetaWriter(s.broker, 1))
 		task.WithCheckpoint(&msgpb.MsgPosition{
@@ -412,6 +554,43 @@ func (s *SyncTaskSuite) TestRunError() {
 	})
 }
 
+func (s *SyncTaskSuite) TestRetryRun() {
+	seg := metacache.NewSegmentInfo(&datapb.SegmentInfo{}, metacache.NewBloomFilterSet())
+	s.metacache.EXPECT().GetSegmentByID(s.segmentID).Return(seg, true)
+	s.metacache.EXPECT().UpdateSegments(mock.Anything, mock.Anything).Return()
+
+	allocated := 0
+	s.allocator.AllocF = func(count uint32) (int64, int64, error) {
+		allocated++
+		return int64(allocated * 1000), int64(allocated*1000) + int64(count), nil
+	}
+	written := make([][]string, 0)
+	s.chunkManager.ExpectedCalls = nil
+	s.chunkManager.EXPECT().RootPath().Return("files")
+	s.chunkManager.EXPECT().MultiWrite(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, kvs map[string][]byte) error {
+		written = append(written, lo.Keys(kvs))
+		return nil
+	})
+	metaWriter := NewMockMetaWriter(s.T())
+	metaWriter.EXPECT().UpdateSync(mock.Anything).Return(errors.New("mocked")).Once()
+	metaWriter.EXPECT().UpdateSync(mock.Anything).Return(nil).Once()
+
+	task := s.getSuiteSyncTask().WithMetaWriter(metaWriter)
+	task.binlogBlobs[100] = &storage.Blob{Key: "100", Value: []byte("test_data")}
+	task.deltaBlobs = []*storage.Blob{{Key: "delta", Value: []byte("test_delta")}}
+
+	s.Error(task.Run())
+	s.NoError(task.Run())
+
+	// the binlogs retried overwrite the ones written before under the log ids reserved
+	s.Equal(1, allocated)
+	s.Require().Len(written, 2)
+	s.ElementsMatch(written[0], written[1])
+	s.Len(task.insertBinlogs[100].GetBinlogs(), 1)
+	s.Len(task.deltaBinlog.GetBinlogs(), 1)
+	s.EqualValues(100, task.deltaBinlog.GetFieldID())
+}
+
 func (s *SyncTaskSuite) TestNextID() {
 	task := s.getSuiteSyncTask()
 
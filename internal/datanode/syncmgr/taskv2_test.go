@@ -184,6 +184,10 @@ func (s *SyncTaskSuiteV2) getSuiteSyncTask() *SyncTaskV2 {
 
 	s.metacache.EXPECT().Collection().Return(s.collectionID)
 	s.metacache.EXPECT().Schema().Return(s.schema)
+	s.metacache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+	s.metacache.EXPECT().StorageTenant().Return("").Maybe()
+	s.metacache.EXPECT().BinlogCompression().Return("").Maybe()
+	s.metacache.EXPECT().DeltalogSorted().Return(false).Maybe()
 	serializer, err := NewStorageV2Serializer(storageCache, s.metacache, nil)
 	s.Require().NoError(err)
 	task, err := serializer.EncodeBuffer(context.Background(), pack)
@@ -0,0 +1,185 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"context"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus/internal/datanode/allocator"
+	"github.com/milvus-io/milvus/internal/datanode/io"
+	"github.com/milvus-io/milvus/internal/metastore/kv/binlog"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/etcdpb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// verifySegment downloads the binlogs of the segment batch by batch, and uploads them again by a dry run,
+// so the insert data are verified against the schema and the binlogs are serialized and validated as uploaded
+// by the compactions, with nothing written. The binlogs of the dry run are returned with their sizes.
+func verifySegment(
+	ctx context.Context,
+	b io.BinlogIO,
+	alloc allocator.Allocator,
+	meta *etcdpb.CollectionMeta,
+	binlogFormat string,
+	segment *datapb.CompactionSegmentBinlogs,
+) (*datapb.VerifySegmentResponse, error) {
+	if err := binlog.DecompressCompactionBinlogs([]*datapb.CompactionSegmentBinlogs{segment}); err != nil {
+		return nil, err
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(meta.GetSchema())
+	if err != nil {
+		return nil, err
+	}
+
+	report := io.NewDryRunReport()
+	ctx = io.WithDryRun(ctx, report)
+
+	iCodec := storage.NewInsertCodecWithSchema(meta)
+	iCodec.BinlogFormat = binlogFormat
+	collectionID, partID, segID := meta.GetID(), segment.GetPartitionID(), segment.GetSegmentID()
+	checksum := binlogChecksum([]*datapb.CompactionSegmentBinlogs{segment})
+
+	var (
+		numRows           int64
+		batchStats        []*storage.PrimaryKeyStats
+		batchStatslogSize int64
+		inPaths           = make(map[UniqueID]*datapb.FieldBinlog)
+		statPaths         = make(map[UniqueID]*datapb.FieldBinlog)
+	)
+	batches, err := insertBatchPaths(segment)
+	if err != nil {
+		return nil, err
+	}
+	for _, paths := range batches {
+		blobs, err := downloadBlobs(ctx, b, paths)
+		if err != nil {
+			return nil, err
+		}
+		if err := verifyBlobs(paths, blobs, checksum); err != nil {
+			return nil, err
+		}
+		_, _, data, err := iCodec.Deserialize(blobs)
+		if err != nil {
+			return nil, err
+		}
+
+		// the statslogs of the batches are removed once merged into the compound one
+		batchInPaths, batchStatPaths, stats, err := uploadInsertLogWithStats(ctx, b, alloc, collectionID, partID, segID, data, iCodec)
+		if err != nil {
+			return nil, err
+		}
+		mergeFieldBinlogs(inPaths, batchInPaths)
+		for _, fieldBinlog := range batchStatPaths {
+			for _, l := range fieldBinlog.GetBinlogs() {
+				batchStatslogSize += l.GetLogSize()
+			}
+		}
+		batchStats = append(batchStats, stats)
+		numRows += int64(data.GetRowNum())
+	}
+	if len(batchStats) > 0 {
+		mergedPaths, err := uploadMergedStatsLog(ctx, b, collectionID, partID, segID, batchStats, numRows, iCodec)
+		if err != nil {
+			return nil, err
+		}
+		mergeFieldBinlogs(statPaths, mergedPaths)
+	}
+
+	var deltaPaths []*datapb.FieldBinlog
+	paths := make([]string, 0)
+	for _, d := range binlog.FilterDeltalogs(segment.GetDeltalogs(), pkField.GetFieldID()) {
+		for _, l := range d.GetBinlogs() {
+			paths = append(paths, l.GetLogPath())
+		}
+	}
+	if len(paths) > 0 {
+		blobs, err := downloadBlobs(ctx, b, paths)
+		if err != nil {
+			return nil, err
+		}
+		if err := verifyBlobs(paths, blobs, checksum); err != nil {
+			return nil, err
+		}
+		// the deletes recorded by the row offsets are verified on read, and kept as is by the compactions
+		blobs, _, err = storage.NewDeleteCodec().DeserializeBitmaps(blobs)
+		if err != nil {
+			return nil, err
+		}
+		if len(blobs) > 0 {
+			_, _, dData, err := storage.NewDeleteCodec().Deserialize(blobs)
+			if err != nil {
+				return nil, err
+			}
+			deltaPaths, err = uploadDeltaLog(ctx, b, alloc, collectionID, partID, segID, pkField.GetFieldID(), dData)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &datapb.VerifySegmentResponse{
+		Status:              merr.Success(),
+		NumOfRows:           numRows,
+		InsertLogs:          lo.Values(inPaths),
+		Field2StatslogPaths: lo.Values(statPaths),
+		Deltalogs:           deltaPaths,
+		Size:                report.Size() - batchStatslogSize,
+	}, nil
+}
+
+// insertBatchPaths returns the paths of the insert binlogs of the segment by the batches,
+// the i-th binlogs of all the fields are of the i-th batch.
+func insertBatchPaths(segment *datapb.CompactionSegmentBinlogs) ([][]string, error) {
+	var binlogNum int
+	for _, f := range segment.GetFieldBinlogs() {
+		if f != nil {
+			binlogNum = len(f.GetBinlogs())
+			break
+		}
+	}
+	for _, f := range segment.GetFieldBinlogs() {
+		if len(f.GetBinlogs()) != binlogNum {
+			return nil, merr.WrapErrParameterInvalidMsg("field %d of segment %d has %d binlogs, expected %d",
+				f.GetFieldID(), segment.GetSegmentID(), len(f.GetBinlogs()), binlogNum)
+		}
+	}
+	batches := make([][]string, 0, binlogNum)
+	for idx := 0; idx < binlogNum; idx++ {
+		var ps []string
+		for _, f := range segment.GetFieldBinlogs() {
+			ps = append(ps, f.GetBinlogs()[idx].GetLogPath())
+		}
+		batches = append(batches, ps)
+	}
+	return batches, nil
+}
+
+// mergeFieldBinlogs appends the binlogs of the fields into the binlogs of the same fields.
+func mergeFieldBinlogs(target, fieldBinlogs map[UniqueID]*datapb.FieldBinlog) {
+	for fID, fieldBinlog := range fieldBinlogs {
+		if existing, ok := target[fID]; ok {
+			existing.Binlogs = append(existing.Binlogs, fieldBinlog.GetBinlogs()...)
+		} else {
+			target[fID] = fieldBinlog
+		}
+	}
+}
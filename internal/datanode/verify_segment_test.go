@@ -0,0 +1,181 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/datanode/allocator"
+	"github.com/milvus-io/milvus/internal/datanode/io"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/storage"
+)
+
+func TestVerifySegment(t *testing.T) {
+	ctx := context.Background()
+	cm := storage.NewLocalChunkManager(storage.RootPath(binlogTestDir))
+	defer cm.RemoveWithPrefix(ctx, cm.RootPath())
+
+	f := &MetaFactory{}
+	meta := f.GetCollectionMeta(UniqueID(10001), "test_verify_segment", schemapb.DataType_Int64)
+	iCodec := storage.NewInsertCodecWithSchema(meta)
+	binlogIO := io.NewBinlogIO(cm, getOrCreateIOPool())
+
+	alloc := allocator.NewMockAllocator(t)
+	alloc.EXPECT().GetGenerator(mock.Anything, mock.Anything).Call.Return(validGeneratorFn, nil)
+	alloc.EXPECT().AllocOne().Return(1000, nil)
+
+	inPaths := make(map[UniqueID]*datapb.FieldBinlog)
+	statPaths := make(map[UniqueID]*datapb.FieldBinlog)
+	for i := 0; i < 2; i++ {
+		// the ids of the generator are the same for both batches, so the partitions tell the binlogs apart
+		batchInPaths, batchStatPaths, _, err := uploadInsertLogWithStats(ctx, binlogIO, alloc, meta.GetID(), int64(10+i), 1, genInsertData(2), iCodec)
+		require.NoError(t, err)
+		mergeFieldBinlogs(inPaths, batchInPaths)
+		mergeFieldBinlogs(statPaths, batchStatPaths)
+	}
+	dData := storage.NewDeleteData([]storage.PrimaryKey{storage.NewInt64PrimaryKey(1)}, []uint64{1})
+	deltaPaths, err := uploadDeltaLog(ctx, binlogIO, alloc, meta.GetID(), 10, 1, 106, dData)
+	require.NoError(t, err)
+
+	segment := &datapb.CompactionSegmentBinlogs{
+		SegmentID:           1,
+		CollectionID:        meta.GetID(),
+		PartitionID:         10,
+		FieldBinlogs:        lo.Values(inPaths),
+		Field2StatslogPaths: lo.Values(statPaths),
+		Deltalogs:           deltaPaths,
+	}
+	written, _, err := cm.ListWithPrefix(ctx, cm.RootPath(), true)
+	require.NoError(t, err)
+
+	var nextID atomic.Int64
+	nextID.Store(2000)
+	verifyAlloc := allocator.NewMockAllocator(t)
+	verifyAlloc.EXPECT().GetGenerator(mock.Anything, mock.Anything).RunAndReturn(func(count int, done <-chan struct{}) (<-chan UniqueID, error) {
+		ret := make(chan UniqueID, count)
+		for i := 0; i < count; i++ {
+			ret <- nextID.Inc()
+		}
+		return ret, nil
+	})
+	verifyAlloc.EXPECT().AllocOne().RunAndReturn(func() (UniqueID, error) {
+		return nextID.Inc(), nil
+	})
+
+	resp, err := verifySegment(ctx, binlogIO, verifyAlloc, meta, "", segment)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, resp.GetNumOfRows())
+	assert.Equal(t, len(inPaths), len(resp.GetInsertLogs()))
+	for _, fieldBinlog := range resp.GetInsertLogs() {
+		assert.Equal(t, 2, len(fieldBinlog.GetBinlogs()))
+	}
+	assert.Equal(t, 1, len(resp.GetDeltalogs()))
+	assert.Positive(t, resp.GetSize())
+
+	var size int64
+	for _, fieldBinlogs := range [][]*datapb.FieldBinlog{resp.GetInsertLogs(), resp.GetField2StatslogPaths(), resp.GetDeltalogs()} {
+		for _, fieldBinlog := range fieldBinlogs {
+			for _, binlog := range fieldBinlog.GetBinlogs() {
+				size += binlog.GetLogSize()
+			}
+		}
+	}
+	assert.Equal(t, size, resp.GetSize())
+
+	// nothing is written by the dry run
+	files, _, err := cm.ListWithPrefix(ctx, cm.RootPath(), true)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, written, files)
+
+	t.Run("binlogs not of the batches", func(t *testing.T) {
+		broken := &datapb.CompactionSegmentBinlogs{
+			SegmentID:    1,
+			CollectionID: meta.GetID(),
+			PartitionID:  10,
+			FieldBinlogs: lo.Values(inPaths),
+		}
+		broken.FieldBinlogs[0] = &datapb.FieldBinlog{FieldID: broken.FieldBinlogs[0].GetFieldID()}
+		_, err := verifySegment(ctx, binlogIO, verifyAlloc, meta, "", broken)
+		assert.Error(t, err)
+	})
+}
+
+func TestUploadInsertLogDryRun(t *testing.T) {
+	ctx := context.Background()
+	cm := storage.NewLocalChunkManager(storage.RootPath(binlogTestDir))
+	defer cm.RemoveWithPrefix(ctx, cm.RootPath())
+
+	f := &MetaFactory{}
+	meta := f.GetCollectionMeta(UniqueID(10001), "test_upload_dry_run", schemapb.DataType_Int64)
+	iCodec := storage.NewInsertCodecWithSchema(meta)
+	binlogIO := io.NewBinlogIO(cm, getOrCreateIOPool())
+
+	alloc := allocator.NewMockAllocator(t)
+	alloc.EXPECT().GetGenerator(mock.Anything, mock.Anything).Call.Return(validGeneratorFn, nil).Maybe()
+
+	report := io.NewDryRunReport()
+	dryRunCtx := io.WithDryRun(ctx, report)
+	inPaths, err := uploadInsertLog(dryRunCtx, binlogIO, alloc, meta.GetID(), 10, 1, genInsertData(2), iCodec)
+	require.NoError(t, err)
+	assert.Equal(t, len(meta.GetSchema().GetFields()), len(inPaths))
+	assert.Equal(t, len(inPaths), len(report.Binlogs()))
+	for _, fieldBinlog := range inPaths {
+		binlog := fieldBinlog.GetBinlogs()[0]
+		assert.EqualValues(t, 2, binlog.GetEntriesNum())
+		assert.Equal(t, binlog.GetLogSize(), report.Binlogs()[binlog.GetLogPath()])
+		exist, err := cm.Exist(ctx, binlog.GetLogPath())
+		require.NoError(t, err)
+		assert.False(t, exist)
+	}
+
+	t.Run("field missing", func(t *testing.T) {
+		data := genInsertData(2)
+		delete(data.Data, 105)
+		_, err := uploadInsertLog(dryRunCtx, binlogIO, alloc, meta.GetID(), 10, 1, data, iCodec)
+		assert.Error(t, err)
+	})
+
+	t.Run("rows mismatched", func(t *testing.T) {
+		data := genInsertData(2)
+		data.Data[105] = genInsertData(3).Data[105]
+		_, err := uploadInsertLog(dryRunCtx, binlogIO, alloc, meta.GetID(), 10, 1, data, iCodec)
+		assert.Error(t, err)
+	})
+
+	t.Run("field not in schema", func(t *testing.T) {
+		data := genInsertData(2)
+		data.Data[999] = data.Data[105]
+		_, err := uploadInsertLog(dryRunCtx, binlogIO, alloc, meta.GetID(), 10, 1, data, iCodec)
+		assert.Error(t, err)
+	})
+
+	t.Run("data type mismatched", func(t *testing.T) {
+		data := genInsertData(2)
+		data.Data[106], data.Data[107] = data.Data[107], data.Data[106]
+		_, err := uploadInsertLog(dryRunCtx, binlogIO, alloc, meta.GetID(), 10, 1, data, iCodec)
+		assert.Error(t, err)
+	})
+}
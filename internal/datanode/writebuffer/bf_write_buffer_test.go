@@ -195,9 +195,17 @@ func (s *BFWriteBufferSuite) SetupTest() {
 	s.syncMgr = syncmgr.NewMockSyncManager(s.T())
 	s.metacacheInt64 = metacache.NewMockMetaCache(s.T())
 	s.metacacheInt64.EXPECT().Schema().Return(s.collInt64Schema).Maybe()
+	s.metacacheInt64.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+	s.metacacheInt64.EXPECT().StorageTenant().Return("").Maybe()
+	s.metacacheInt64.EXPECT().BinlogCompression().Return("").Maybe()
+	s.metacacheInt64.EXPECT().DeltalogSorted().Return(false).Maybe()
 	s.metacacheInt64.EXPECT().Collection().Return(s.collID).Maybe()
 	s.metacacheVarchar = metacache.NewMockMetaCache(s.T())
 	s.metacacheVarchar.EXPECT().Schema().Return(s.collVarcharSchema).Maybe()
+	s.metacacheVarchar.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+	s.metacacheVarchar.EXPECT().StorageTenant().Return("").Maybe()
+	s.metacacheVarchar.EXPECT().BinlogCompression().Return("").Maybe()
+	s.metacacheVarchar.EXPECT().DeltalogSorted().Return(false).Maybe()
 	s.metacacheVarchar.EXPECT().Collection().Return(s.collID).Maybe()
 
 	s.broker = broker.NewMockBroker(s.T())
@@ -433,6 +441,10 @@ func (s *BFWriteBufferSuite) TestCreateFailure() {
 	metacache := metacache.NewMockMetaCache(s.T())
 	metacache.EXPECT().Collection().Return(s.collID)
 	metacache.EXPECT().Schema().Return(&schemapb.CollectionSchema{})
+	metacache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+	metacache.EXPECT().StorageTenant().Return("").Maybe()
+	metacache.EXPECT().BinlogCompression().Return("").Maybe()
+	metacache.EXPECT().DeltalogSorted().Return(false).Maybe()
 	_, err := NewBFWriteBuffer(s.channelName, metacache, s.storageV2Cache, s.syncMgr, &writeBufferOption{})
 	s.Error(err)
 }
@@ -166,6 +166,10 @@ func (s *L0WriteBufferSuite) SetupTest() {
 	s.syncMgr = syncmgr.NewMockSyncManager(s.T())
 	s.metacache = metacache.NewMockMetaCache(s.T())
 	s.metacache.EXPECT().Schema().Return(s.collSchema).Maybe()
+	s.metacache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+	s.metacache.EXPECT().StorageTenant().Return("").Maybe()
+	s.metacache.EXPECT().BinlogCompression().Return("").Maybe()
+	s.metacache.EXPECT().DeltalogSorted().Return(false).Maybe()
 	s.metacache.EXPECT().Collection().Return(s.collID).Maybe()
 	s.allocator = allocator.NewMockGIDAllocator()
 	s.allocator.AllocOneF = func() (int64, error) { return int64(tsoutil.ComposeTSByTime(time.Now(), 0)), nil }
@@ -227,6 +231,10 @@ func (s *L0WriteBufferSuite) TestCreateFailure() {
 	metacache := metacache.NewMockMetaCache(s.T())
 	metacache.EXPECT().Collection().Return(s.collID)
 	metacache.EXPECT().Schema().Return(&schemapb.CollectionSchema{})
+	metacache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+	metacache.EXPECT().StorageTenant().Return("").Maybe()
+	metacache.EXPECT().BinlogCompression().Return("").Maybe()
+	metacache.EXPECT().DeltalogSorted().Return(false).Maybe()
 	_, err := NewL0WriteBuffer(s.channelName, metacache, s.storageCache, s.syncMgr, &writeBufferOption{
 		idAllocator: s.allocator,
 	})
@@ -2,12 +2,14 @@ package writebuffer
 
 import (
 	"context"
+	"math"
 	"sync"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus/internal/datanode/membudget"
 	"github.com/milvus-io/milvus/internal/datanode/metacache"
 	"github.com/milvus-io/milvus/internal/datanode/syncmgr"
 	"github.com/milvus-io/milvus/pkg/log"
@@ -15,6 +17,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/util/hardware"
 	"github.com/milvus-io/milvus/pkg/util/lifetime"
 	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metricsinfo"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 )
 
@@ -37,6 +40,8 @@ type BufferManager interface {
 	GetCheckpoint(channel string) (*msgpb.MsgPosition, bool, error)
 	// NotifyCheckpointUpdated notify write buffer checkpoint updated to reset flushTs.
 	NotifyCheckpointUpdated(channel string, ts uint64)
+	// GetChannelPressures returns the write pressure of the channels, the collection ids are not filled.
+	GetChannelPressures() []metricsinfo.ChannelPressure
 
 	// Start makes the background check start to work.
 	Start()
@@ -115,10 +120,14 @@ func (m *bufferManager) memoryCheck() {
 
 	totalMemory := hardware.GetMemoryCount()
 	memoryWatermark := float64(totalMemory) * paramtable.Get().DataNodeCfg.MemoryWatermark.GetAsFloat()
-	if float64(total) < memoryWatermark {
+	// buffers shall be synced as well if the memory budget shared with compaction is exceeded
+	budget := membudget.GetBudget()
+	if float64(total) < memoryWatermark && !budget.Exceeded() {
 		log.RatedDebug(20, "skip force sync because memory level is not high enough",
 			zap.Float64("current_total_memory_usage", toMB(float64(total))),
-			zap.Float64("current_memory_watermark", toMB(memoryWatermark)))
+			zap.Float64("current_memory_watermark", toMB(memoryWatermark)),
+			zap.Float64("current_memory_budget_usage", toMB(float64(budget.Used()))),
+			zap.Float64("memory_budget", toMB(float64(budget.Capacity()))))
 		return
 	}
 
@@ -129,6 +138,42 @@ func (m *bufferManager) memoryCheck() {
 	}
 }
 
+// GetChannelPressures returns the write pressure of the channels, which is the usage of the buffer of the channel
+// against the channel memory watermark, or the node one if unlimited, or the usage of the sync queue of the channel,
+// whichever is higher.
+func (m *bufferManager) GetChannelPressures() []metricsinfo.ChannelPressure {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	params := paramtable.Get()
+	bufferCapacity := params.DataNodeCfg.ChannelMemoryWatermark.GetAsFloat()
+	if bufferCapacity <= 0 {
+		bufferCapacity = float64(hardware.GetMemoryCount()) * params.DataNodeCfg.MemoryWatermark.GetAsFloat()
+	}
+	maxPendingSyncs := params.DataNodeCfg.MaxPendingSyncTasksPerChannel.GetAsInt()
+
+	pressures := make([]metricsinfo.ChannelPressure, 0, len(m.buffers))
+	for channel, buf := range m.buffers {
+		size := buf.MemorySize()
+		pendingSyncs := m.syncMgr.GetPendingTaskNum(channel)
+
+		var pressure float64
+		if bufferCapacity > 0 {
+			pressure = float64(size) / bufferCapacity
+		}
+		if maxPendingSyncs > 0 {
+			pressure = math.Max(pressure, float64(pendingSyncs)/float64(maxPendingSyncs))
+		}
+		pressures = append(pressures, metricsinfo.ChannelPressure{
+			Channel:      channel,
+			BufferSize:   size,
+			PendingSyncs: pendingSyncs,
+			Pressure:     math.Min(pressure, 1),
+		})
+	}
+	return pressures
+}
+
 func (m *bufferManager) Stop() {
 	m.ch.Close()
 	m.wg.Wait()
@@ -12,6 +12,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/datanode/allocator"
+	"github.com/milvus-io/milvus/internal/datanode/membudget"
 	"github.com/milvus-io/milvus/internal/datanode/metacache"
 	"github.com/milvus-io/milvus/internal/datanode/syncmgr"
 	"github.com/milvus-io/milvus/pkg/common"
@@ -61,6 +62,10 @@ func (s *ManagerSuite) SetupTest() {
 	s.metacache = metacache.NewMockMetaCache(s.T())
 	s.metacache.EXPECT().Collection().Return(s.collID).Maybe()
 	s.metacache.EXPECT().Schema().Return(s.collSchema).Maybe()
+	s.metacache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+	s.metacache.EXPECT().StorageTenant().Return("").Maybe()
+	s.metacache.EXPECT().BinlogCompression().Return("").Maybe()
+	s.metacache.EXPECT().DeltalogSorted().Return(false).Maybe()
 	s.allocator = allocator.NewMockAllocator(s.T())
 
 	mgr := NewManager(s.syncMgr)
@@ -238,6 +243,77 @@ func (s *ManagerSuite) TestMemoryCheck() {
 	wb.AssertExpectations(s.T())
 }
 
+func (s *ManagerSuite) TestMemoryBudgetExceeded() {
+	manager := s.manager
+	param := paramtable.Get()
+	param.Save(param.DataNodeCfg.MemoryBudgetRatio.Key, "0.01")
+	defer param.Reset(param.DataNodeCfg.MemoryBudgetRatio.Key)
+
+	wb := NewMockWriteBuffer(s.T())
+	wb.EXPECT().MemorySize().Return(1024)
+	manager.mut.Lock()
+	manager.buffers[s.channelName] = wb
+	manager.mut.Unlock()
+
+	// buffers are far below the watermark
+	manager.memoryCheck()
+
+	// the memory budget is exceeded by compaction
+	budget := membudget.GetBudget()
+	size := budget.Capacity() + 1
+	s.Require().True(budget.TryReserve(membudget.Compaction, size))
+	defer budget.Release(membudget.Compaction, size)
+
+	wb.EXPECT().EvictBuffer(mock.Anything).Return().Once()
+	manager.memoryCheck()
+}
+
+func (s *ManagerSuite) TestGetChannelPressures() {
+	manager := s.manager
+	param := paramtable.Get()
+	param.Save(param.DataNodeCfg.ChannelMemoryWatermark.Key, "1000")
+	param.Save(param.DataNodeCfg.MaxPendingSyncTasksPerChannel.Key, "10")
+	defer func() {
+		param.Reset(param.DataNodeCfg.ChannelMemoryWatermark.Key)
+		param.Reset(param.DataNodeCfg.MaxPendingSyncTasksPerChannel.Key)
+	}()
+
+	wb := NewMockWriteBuffer(s.T())
+	manager.mut.Lock()
+	manager.buffers[s.channelName] = wb
+	manager.mut.Unlock()
+
+	s.Run("buffer_pressure", func() {
+		wb.EXPECT().MemorySize().Return(500).Once()
+		s.syncMgr.EXPECT().GetPendingTaskNum(s.channelName).Return(2).Once()
+
+		pressures := manager.GetChannelPressures()
+		s.Require().Len(pressures, 1)
+		s.Equal(s.channelName, pressures[0].Channel)
+		s.EqualValues(500, pressures[0].BufferSize)
+		s.Equal(2, pressures[0].PendingSyncs)
+		s.InDelta(0.5, pressures[0].Pressure, 1e-6)
+	})
+
+	s.Run("sync_pressure", func() {
+		wb.EXPECT().MemorySize().Return(100).Once()
+		s.syncMgr.EXPECT().GetPendingTaskNum(s.channelName).Return(8).Once()
+
+		pressures := manager.GetChannelPressures()
+		s.Require().Len(pressures, 1)
+		s.InDelta(0.8, pressures[0].Pressure, 1e-6)
+	})
+
+	s.Run("saturated", func() {
+		wb.EXPECT().MemorySize().Return(2000).Once()
+		s.syncMgr.EXPECT().GetPendingTaskNum(s.channelName).Return(0).Once()
+
+		pressures := manager.GetChannelPressures()
+		s.Require().Len(pressures, 1)
+		s.EqualValues(1, pressures[0].Pressure)
+	})
+}
+
 func TestManager(t *testing.T) {
 	suite.Run(t, new(ManagerSuite))
 }
@@ -6,6 +6,8 @@ import (
 	context "context"
 
 	metacache "github.com/milvus-io/milvus/internal/datanode/metacache"
+	metricsinfo "github.com/milvus-io/milvus/pkg/util/metricsinfo"
+
 	mock "github.com/stretchr/testify/mock"
 
 	msgpb "github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
@@ -149,6 +151,49 @@ func (_c *MockBufferManager_FlushChannel_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// GetChannelPressures provides a mock function with given fields:
+func (_m *MockBufferManager) GetChannelPressures() []metricsinfo.ChannelPressure {
+	ret := _m.Called()
+
+	var r0 []metricsinfo.ChannelPressure
+	if rf, ok := ret.Get(0).(func() []metricsinfo.ChannelPressure); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]metricsinfo.ChannelPressure)
+		}
+	}
+
+	return r0
+}
+
+// MockBufferManager_GetChannelPressures_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetChannelPressures'
+type MockBufferManager_GetChannelPressures_Call struct {
+	*mock.Call
+}
+
+// GetChannelPressures is a helper method to define mock.On call
+func (_e *MockBufferManager_Expecter) GetChannelPressures() *MockBufferManager_GetChannelPressures_Call {
+	return &MockBufferManager_GetChannelPressures_Call{Call: _e.mock.On("GetChannelPressures")}
+}
+
+func (_c *MockBufferManager_GetChannelPressures_Call) Run(run func()) *MockBufferManager_GetChannelPressures_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockBufferManager_GetChannelPressures_Call) Return(_a0 []metricsinfo.ChannelPressure) *MockBufferManager_GetChannelPressures_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockBufferManager_GetChannelPressures_Call) RunAndReturn(run func() []metricsinfo.ChannelPressure) *MockBufferManager_GetChannelPressures_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetCheckpoint provides a mock function with given fields: channel
 func (_m *MockBufferManager) GetCheckpoint(channel string) (*msgpb.MsgPosition, bool, error) {
 	ret := _m.Called(channel)
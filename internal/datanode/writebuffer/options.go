@@ -1,8 +1,6 @@
 package writebuffer
 
 import (
-	"time"
-
 	"github.com/milvus-io/milvus/internal/allocator"
 	"github.com/milvus-io/milvus/internal/datanode/metacache"
 	"github.com/milvus-io/milvus/internal/datanode/syncmgr"
@@ -39,7 +37,9 @@ func defaultWBOption(metacache metacache.MetaCache) *writeBufferOption {
 		deletePolicy: deletePolicy,
 		syncPolicies: []SyncPolicy{
 			GetFullBufferPolicy(),
-			GetSyncStaleBufferPolicy(paramtable.Get().DataNodeCfg.SyncPeriod.GetAsDuration(time.Second)),
+			GetSyncPeriodPolicy(),
+			GetChannelMemoryPolicy(),
+			GetCheckpointLagPolicy(),
 			GetCompactedSegmentsPolicy(metacache),
 			GetSealedSegmentsPolicy(metacache),
 		},
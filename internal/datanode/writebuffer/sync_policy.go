@@ -3,6 +3,7 @@ package writebuffer
 import (
 	"container/heap"
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/samber/lo"
@@ -11,6 +12,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus/internal/datanode/metacache"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/tsoutil"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
@@ -58,14 +60,73 @@ func GetCompactedSegmentsPolicy(meta metacache.MetaCache) SyncPolicy {
 
 func GetSyncStaleBufferPolicy(staleDuration time.Duration) SyncPolicy {
 	return wrapSelectSegmentFuncPolicy(func(buffers []*segmentBuffer, ts typeutil.Timestamp) []int64 {
-		current := tsoutil.PhysicalTime(ts)
+		return selectStaleBuffers(buffers, ts, staleDuration)
+	}, "buffer stale")
+}
+
+// GetSyncPeriodPolicy returns the stale buffer policy of dataNode.segment.syncPeriod,
+// the period is read at each check so that it's refreshable.
+func GetSyncPeriodPolicy() SyncPolicy {
+	return wrapSelectSegmentFuncPolicy(func(buffers []*segmentBuffer, ts typeutil.Timestamp) []int64 {
+		return selectStaleBuffers(buffers, ts, paramtable.Get().DataNodeCfg.SyncPeriod.GetAsDuration(time.Second))
+	}, "buffer stale")
+}
+
+func selectStaleBuffers(buffers []*segmentBuffer, ts typeutil.Timestamp, staleDuration time.Duration) []int64 {
+	current := tsoutil.PhysicalTime(ts)
+	return lo.FilterMap(buffers, func(buf *segmentBuffer, _ int) (int64, bool) {
+		minTs := buf.MinTimestamp()
+		start := tsoutil.PhysicalTime(minTs)
+		jitter := time.Duration(rand.Float64() * 0.1 * float64(staleDuration))
+		return buf.segmentID, current.Sub(start) > staleDuration+jitter
+	})
+}
+
+// GetChannelMemoryPolicy selects the largest buffers of the channel until the rest of them fit in
+// dataNode.segment.channelMemoryWatermark, nothing is selected if the watermark is not positive.
+func GetChannelMemoryPolicy() SyncPolicy {
+	return wrapSelectSegmentFuncPolicy(func(buffers []*segmentBuffer, _ typeutil.Timestamp) []int64 {
+		watermark := paramtable.Get().DataNodeCfg.ChannelMemoryWatermark.GetAsInt64()
+		if watermark <= 0 {
+			return nil
+		}
+		total := lo.SumBy(buffers, func(buf *segmentBuffer) int64 { return buf.MemorySize() })
+		if total <= watermark {
+			return nil
+		}
+
+		sorted := make([]*segmentBuffer, len(buffers))
+		copy(sorted, buffers)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].MemorySize() > sorted[j].MemorySize()
+		})
+		var ids []int64
+		for _, buf := range sorted {
+			if total <= watermark {
+				break
+			}
+			ids = append(ids, buf.segmentID)
+			total -= buf.MemorySize()
+		}
+		return ids
+	}, "channel memory watermark")
+}
+
+// GetCheckpointLagPolicy selects the buffers whose earliest positions lag behind the current time
+// by more than dataNode.segment.maxCheckpointLag, which hold the channel checkpoint back,
+// nothing is selected if the lag is not positive.
+func GetCheckpointLagPolicy() SyncPolicy {
+	return wrapSelectSegmentFuncPolicy(func(buffers []*segmentBuffer, _ typeutil.Timestamp) []int64 {
+		maxLag := paramtable.Get().DataNodeCfg.MaxCheckpointLag.GetAsDuration(time.Second)
+		if maxLag <= 0 {
+			return nil
+		}
+		now := time.Now()
 		return lo.FilterMap(buffers, func(buf *segmentBuffer, _ int) (int64, bool) {
-			minTs := buf.MinTimestamp()
-			start := tsoutil.PhysicalTime(minTs)
-			jitter := time.Duration(rand.Float64() * 0.1 * float64(staleDuration))
-			return buf.segmentID, current.Sub(start) > staleDuration+jitter
+			pos := buf.EarliestPosition()
+			return buf.segmentID, pos != nil && now.Sub(tsoutil.PhysicalTime(pos.GetTimestamp())) > maxLag
 		})
-	}, "buffer stale")
+	}, "checkpoint lag")
 }
 
 func GetSealedSegmentsPolicy(meta metacache.MetaCache) SyncPolicy {
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
@@ -73,6 +74,77 @@ func (s *SyncPolicySuite) TestSyncStalePolicy() {
 	s.Equal(0, len(ids), "")
 }
 
+func (s *SyncPolicySuite) TestSyncPeriodPolicy() {
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.SyncPeriod.Key, "120")
+	defer paramtable.Get().Reset(paramtable.Get().DataNodeCfg.SyncPeriod.Key)
+	policy := GetSyncPeriodPolicy()
+
+	buffer, err := newSegmentBuffer(100, s.collSchema)
+	s.Require().NoError(err)
+	buffer.insertBuffer.startPos = &msgpb.MsgPosition{
+		Timestamp: tsoutil.ComposeTSByTime(time.Now().Add(-time.Minute*3), 0),
+	}
+
+	ids := policy.SelectSegments([]*segmentBuffer{buffer}, tsoutil.ComposeTSByTime(time.Now(), 0))
+	s.ElementsMatch([]int64{100}, ids)
+
+	// the period is refreshed without recreating the policy
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.SyncPeriod.Key, "600")
+	ids = policy.SelectSegments([]*segmentBuffer{buffer}, tsoutil.ComposeTSByTime(time.Now(), 0))
+	s.Equal(0, len(ids))
+}
+
+func (s *SyncPolicySuite) TestChannelMemoryPolicy() {
+	defer paramtable.Get().Reset(paramtable.Get().DataNodeCfg.ChannelMemoryWatermark.Key)
+	policy := GetChannelMemoryPolicy()
+
+	buffers := lo.Map([]int64{100, 300, 200}, func(size int64, _ int) *segmentBuffer {
+		return &segmentBuffer{
+			segmentID:    size,
+			insertBuffer: &InsertBuffer{BufferBase: BufferBase{size: size}},
+			deltaBuffer:  &DeltaBuffer{BufferBase: BufferBase{}},
+		}
+	})
+
+	ids := policy.SelectSegments(buffers, 0)
+	s.Equal(0, len(ids), "unlimited watermark shall not select any buffer")
+
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.ChannelMemoryWatermark.Key, "600")
+	ids = policy.SelectSegments(buffers, 0)
+	s.Equal(0, len(ids), "buffers under the watermark shall not be synced")
+
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.ChannelMemoryWatermark.Key, "250")
+	ids = policy.SelectSegments(buffers, 0)
+	s.ElementsMatch([]int64{300, 200}, ids)
+}
+
+func (s *SyncPolicySuite) TestCheckpointLagPolicy() {
+	defer paramtable.Get().Reset(paramtable.Get().DataNodeCfg.MaxCheckpointLag.Key)
+	policy := GetCheckpointLagPolicy()
+
+	lagged, err := newSegmentBuffer(100, s.collSchema)
+	s.Require().NoError(err)
+	lagged.deltaBuffer.startPos = &msgpb.MsgPosition{
+		Timestamp: tsoutil.ComposeTSByTime(time.Now().Add(-time.Minute*3), 0),
+	}
+	recent, err := newSegmentBuffer(200, s.collSchema)
+	s.Require().NoError(err)
+	recent.insertBuffer.startPos = &msgpb.MsgPosition{
+		Timestamp: tsoutil.ComposeTSByTime(time.Now(), 0),
+	}
+	empty, err := newSegmentBuffer(300, s.collSchema)
+	s.Require().NoError(err)
+	buffers := []*segmentBuffer{lagged, recent, empty}
+
+	// the lag is measured against the current time rather than the consumed timestamp
+	ids := policy.SelectSegments(buffers, lagged.deltaBuffer.startPos.GetTimestamp())
+	s.Equal(0, len(ids), "unlimited lag shall not select any buffer")
+
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.MaxCheckpointLag.Key, "60")
+	ids = policy.SelectSegments(buffers, lagged.deltaBuffer.startPos.GetTimestamp())
+	s.ElementsMatch([]int64{100}, ids)
+}
+
 func (s *SyncPolicySuite) TestSealedSegmentsPolicy() {
 	metacache := metacache.NewMockMetaCache(s.T())
 	policy := GetSealedSegmentsPolicy(metacache)
@@ -15,6 +15,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/datanode/broker"
+	"github.com/milvus-io/milvus/internal/datanode/membudget"
 	"github.com/milvus-io/milvus/internal/datanode/metacache"
 	"github.com/milvus-io/milvus/internal/datanode/syncmgr"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
@@ -353,6 +354,7 @@ func (wb *writeBufferBase) yieldBuffer(segmentID int64) (*storage.InsertData, *s
 
 	// remove buffer and move it to sync manager
 	delete(wb.buffers, segmentID)
+	untrackBuffer(buffer)
 	start := buffer.EarliestPosition()
 	timeRange := buffer.GetTimeRange()
 	insert, delta := buffer.Yield()
@@ -483,6 +485,7 @@ func (wb *writeBufferBase) bufferInsert(inData *inData, startPos, endPos *msgpb.
 		metacache.WithSegmentIDs(inData.segmentID))
 
 	metrics.DataNodeFlowGraphBufferDataSize.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), fmt.Sprint(wb.collectionID)).Add(float64(totalMemSize))
+	membudget.GetBudget().Track(membudget.InsertBuffer, totalMemSize)
 
 	return nil
 }
@@ -492,6 +495,13 @@ func (wb *writeBufferBase) bufferDelete(segmentID int64, pks []storage.PrimaryKe
 	segBuf := wb.getOrCreateBuffer(segmentID)
 	bufSize := segBuf.deltaBuffer.Buffer(pks, tss, startPos, endPos)
 	metrics.DataNodeFlowGraphBufferDataSize.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), fmt.Sprint(wb.collectionID)).Add(float64(bufSize))
+	membudget.GetBudget().Track(membudget.DeleteBuffer, bufSize)
+}
+
+// untrackBuffer releases the memory of the segment buffer from the memory budget.
+func untrackBuffer(buffer *segmentBuffer) {
+	membudget.GetBudget().Untrack(membudget.InsertBuffer, buffer.insertBuffer.size)
+	membudget.GetBudget().Untrack(membudget.DeleteBuffer, buffer.deltaBuffer.size)
 }
 
 func (wb *writeBufferBase) getSyncTask(ctx context.Context, segmentID int64) (syncmgr.Task, error) {
@@ -547,9 +557,9 @@ func (wb *writeBufferBase) getSyncTask(ctx context.Context, segmentID int64) (sy
 	return wb.serializer.EncodeBuffer(ctx, pack)
 }
 
-// getEstBatchSize returns the batch size based on estimated size per record and FlushBufferSize configuration value.
+// getEstBatchSize returns the batch size based on estimated size per record and the sync buffer size.
 func (wb *writeBufferBase) getEstBatchSize() uint {
-	sizeLimit := paramtable.Get().DataNodeCfg.FlushInsertBufferSize.GetAsInt64()
+	sizeLimit := syncmgr.SyncBufferSize()
 	return uint(sizeLimit / int64(wb.estSizePerRecord))
 }
 
@@ -558,6 +568,9 @@ func (wb *writeBufferBase) Close(drop bool) {
 	wb.mut.Lock()
 	defer wb.mut.Unlock()
 	if !drop {
+		for _, buffer := range wb.buffers {
+			untrackBuffer(buffer)
+		}
 		return
 	}
 
@@ -52,6 +52,10 @@ func (s *WriteBufferSuite) SetupTest() {
 	s.syncMgr = syncmgr.NewMockSyncManager(s.T())
 	s.metacache = metacache.NewMockMetaCache(s.T())
 	s.metacache.EXPECT().Schema().Return(s.collSchema).Maybe()
+	s.metacache.EXPECT().BinlogFormat().Return(common.BinlogFormatNative).Maybe()
+	s.metacache.EXPECT().StorageTenant().Return("").Maybe()
+	s.metacache.EXPECT().BinlogCompression().Return("").Maybe()
+	s.metacache.EXPECT().DeltalogSorted().Return(false).Maybe()
 	s.metacache.EXPECT().Collection().Return(s.collID).Maybe()
 	s.wb, err = newWriteBufferBase(s.channelName, s.metacache, storageCache, s.syncMgr, &writeBufferOption{
 		pkStatsFactory: func(vchannel *datapb.SegmentInfo) *metacache.BloomFilterSet {
@@ -631,3 +631,105 @@ func (c *Client) GcControl(ctx context.Context, req *datapb.GcControlRequest, op
 		return client.GcControl(ctx, req)
 	})
 }
+
+func (c *Client) InspectMeta(ctx context.Context, req *datapb.InspectMetaRequest, opts ...grpc.CallOption) (*datapb.InspectMetaResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.InspectMetaResponse, error) {
+		return client.InspectMeta(ctx, req)
+	})
+}
+
+func (c *Client) Backup(ctx context.Context, req *datapb.BackupRequest, opts ...grpc.CallOption) (*datapb.BackupResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.BackupResponse, error) {
+		return client.Backup(ctx, req)
+	})
+}
+
+func (c *Client) Restore(ctx context.Context, req *datapb.RestoreRequest, opts ...grpc.CallOption) (*datapb.RestoreResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.RestoreResponse, error) {
+		return client.Restore(ctx, req)
+	})
+}
+
+func (c *Client) PublishSegments(ctx context.Context, req *datapb.PublishSegmentsRequest, opts ...grpc.CallOption) (*datapb.PublishSegmentsResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.PublishSegmentsResponse, error) {
+		return client.PublishSegments(ctx, req)
+	})
+}
+
+func (c *Client) Export(ctx context.Context, req *datapb.ExportRequest, opts ...grpc.CallOption) (*datapb.ExportResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.ExportResponse, error) {
+		return client.Export(ctx, req)
+	})
+}
+
+func (c *Client) GetExportState(ctx context.Context, req *datapb.GetExportStateRequest, opts ...grpc.CallOption) (*datapb.GetExportStateResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.GetExportStateResponse, error) {
+		return client.GetExportState(ctx, req)
+	})
+}
+
+func (c *Client) DecommissionNode(ctx context.Context, req *datapb.DecommissionNodeRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*commonpb.Status, error) {
+		return client.DecommissionNode(ctx, req)
+	})
+}
+
+func (c *Client) GetDecommissionState(ctx context.Context, req *datapb.GetDecommissionStateRequest, opts ...grpc.CallOption) (*datapb.GetDecommissionStateResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.GetDecommissionStateResponse, error) {
+		return client.GetDecommissionState(ctx, req)
+	})
+}
+
+func (c *Client) GetSegmentEvents(ctx context.Context, req *datapb.GetSegmentEventsRequest, opts ...grpc.CallOption) (*datapb.GetSegmentEventsResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.GetSegmentEventsResponse, error) {
+		return client.GetSegmentEvents(ctx, req)
+	})
+}
+
+func (c *Client) ListCompactionTasks(ctx context.Context, req *datapb.ListCompactionTasksRequest, opts ...grpc.CallOption) (*datapb.ListCompactionTasksResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.ListCompactionTasksResponse, error) {
+		return client.ListCompactionTasks(ctx, req)
+	})
+}
+
+func (c *Client) MajorCompaction(ctx context.Context, req *datapb.MajorCompactionRequest, opts ...grpc.CallOption) (*datapb.MajorCompactionResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.MajorCompactionResponse, error) {
+		return client.MajorCompaction(ctx, req)
+	})
+}
+
+func (c *Client) GetMajorCompactionState(ctx context.Context, req *datapb.GetMajorCompactionStateRequest, opts ...grpc.CallOption) (*datapb.GetMajorCompactionStateResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.GetMajorCompactionStateResponse, error) {
+		return client.GetMajorCompactionState(ctx, req)
+	})
+}
+
+func (c *Client) AlterMajorCompaction(ctx context.Context, req *datapb.AlterMajorCompactionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*commonpb.Status, error) {
+		return client.AlterMajorCompaction(ctx, req)
+	})
+}
+
+func (c *Client) GetGcReport(ctx context.Context, req *datapb.GetGcReportRequest, opts ...grpc.CallOption) (*datapb.GetGcReportResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.GetGcReportResponse, error) {
+		return client.GetGcReport(ctx, req)
+	})
+}
+
+func (c *Client) ImportSegmentBinlogs(ctx context.Context, req *datapb.ImportSegmentBinlogsRequest, opts ...grpc.CallOption) (*datapb.ImportSegmentBinlogsResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.ImportSegmentBinlogsResponse, error) {
+		return client.ImportSegmentBinlogs(ctx, req)
+	})
+}
+
+func (c *Client) RefreshSegmentStats(ctx context.Context, req *datapb.RefreshSegmentStatsRequest, opts ...grpc.CallOption) (*datapb.RefreshSegmentStatsResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.RefreshSegmentStatsResponse, error) {
+		return client.RefreshSegmentStats(ctx, req)
+	})
+}
+
+func (c *Client) GetSegmentSnapshot(ctx context.Context, req *datapb.GetSegmentSnapshotRequest, opts ...grpc.CallOption) (*datapb.GetSegmentSnapshotResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataCoordClient) (*datapb.GetSegmentSnapshotResponse, error) {
+		return client.GetSegmentSnapshot(ctx, req)
+	})
+}
@@ -485,3 +485,71 @@ func (s *Server) ReportDataNodeTtMsgs(ctx context.Context, req *datapb.ReportDat
 func (s *Server) GcControl(ctx context.Context, req *datapb.GcControlRequest) (*commonpb.Status, error) {
 	return s.dataCoord.GcControl(ctx, req)
 }
+
+func (s *Server) InspectMeta(ctx context.Context, req *datapb.InspectMetaRequest) (*datapb.InspectMetaResponse, error) {
+	return s.dataCoord.InspectMeta(ctx, req)
+}
+
+func (s *Server) Backup(ctx context.Context, req *datapb.BackupRequest) (*datapb.BackupResponse, error) {
+	return s.dataCoord.Backup(ctx, req)
+}
+
+func (s *Server) Restore(ctx context.Context, req *datapb.RestoreRequest) (*datapb.RestoreResponse, error) {
+	return s.dataCoord.Restore(ctx, req)
+}
+
+func (s *Server) PublishSegments(ctx context.Context, req *datapb.PublishSegmentsRequest) (*datapb.PublishSegmentsResponse, error) {
+	return s.dataCoord.PublishSegments(ctx, req)
+}
+
+func (s *Server) Export(ctx context.Context, req *datapb.ExportRequest) (*datapb.ExportResponse, error) {
+	return s.dataCoord.Export(ctx, req)
+}
+
+func (s *Server) GetExportState(ctx context.Context, req *datapb.GetExportStateRequest) (*datapb.GetExportStateResponse, error) {
+	return s.dataCoord.GetExportState(ctx, req)
+}
+
+func (s *Server) DecommissionNode(ctx context.Context, req *datapb.DecommissionNodeRequest) (*commonpb.Status, error) {
+	return s.dataCoord.DecommissionNode(ctx, req)
+}
+
+func (s *Server) GetDecommissionState(ctx context.Context, req *datapb.GetDecommissionStateRequest) (*datapb.GetDecommissionStateResponse, error) {
+	return s.dataCoord.GetDecommissionState(ctx, req)
+}
+
+func (s *Server) GetSegmentEvents(ctx context.Context, req *datapb.GetSegmentEventsRequest) (*datapb.GetSegmentEventsResponse, error) {
+	return s.dataCoord.GetSegmentEvents(ctx, req)
+}
+
+func (s *Server) ListCompactionTasks(ctx context.Context, req *datapb.ListCompactionTasksRequest) (*datapb.ListCompactionTasksResponse, error) {
+	return s.dataCoord.ListCompactionTasks(ctx, req)
+}
+
+func (s *Server) MajorCompaction(ctx context.Context, req *datapb.MajorCompactionRequest) (*datapb.MajorCompactionResponse, error) {
+	return s.dataCoord.MajorCompaction(ctx, req)
+}
+
+func (s *Server) GetMajorCompactionState(ctx context.Context, req *datapb.GetMajorCompactionStateRequest) (*datapb.GetMajorCompactionStateResponse, error) {
+	return s.dataCoord.GetMajorCompactionState(ctx, req)
+}
+
+func (s *Server) AlterMajorCompaction(ctx context.Context, req *datapb.AlterMajorCompactionRequest) (*commonpb.Status, error) {
+	return s.dataCoord.AlterMajorCompaction(ctx, req)
+}
+
+func (s *Server) GetGcReport(ctx context.Context, req *datapb.GetGcReportRequest) (*datapb.GetGcReportResponse, error) {
+	return s.dataCoord.GetGcReport(ctx, req)
+}
+
+func (s *Server) ImportSegmentBinlogs(ctx context.Context, req *datapb.ImportSegmentBinlogsRequest) (*datapb.ImportSegmentBinlogsResponse, error) {
+	return s.dataCoord.ImportSegmentBinlogs(ctx, req)
+}
+
+func (s *Server) RefreshSegmentStats(ctx context.Context, req *datapb.RefreshSegmentStatsRequest) (*datapb.RefreshSegmentStatsResponse, error) {
+	return s.dataCoord.RefreshSegmentStats(ctx, req)
+}
+
+func (s *Server) GetSegmentSnapshot(ctx context.Context, req *datapb.GetSegmentSnapshotRequest) (*datapb.GetSegmentSnapshotResponse, error) {
+	return s.dataCoord.GetSegmentSnapshot(ctx, req)
+}
@@ -276,3 +276,15 @@ func (c *Client) DropImport(ctx context.Context, req *datapb.DropImportRequest,
 		return client.DropImport(ctx, req)
 	})
 }
+
+func (c *Client) VerifySegment(ctx context.Context, req *datapb.VerifySegmentRequest, opts ...grpc.CallOption) (*datapb.VerifySegmentResponse, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataNodeClient) (*datapb.VerifySegmentResponse, error) {
+		return client.VerifySegment(ctx, req)
+	})
+}
+
+func (c *Client) DropCompactionPlan(ctx context.Context, req *datapb.DropCompactionPlanRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	return wrapGrpcCall(ctx, c, func(client datapb.DataNodeClient) (*commonpb.Status, error) {
+		return client.DropCompactionPlan(ctx, req)
+	})
+}
@@ -403,3 +403,11 @@ func (s *Server) QueryImport(ctx context.Context, req *datapb.QueryImportRequest
 func (s *Server) DropImport(ctx context.Context, req *datapb.DropImportRequest) (*commonpb.Status, error) {
 	return s.datanode.DropImport(ctx, req)
 }
+
+func (s *Server) VerifySegment(ctx context.Context, req *datapb.VerifySegmentRequest) (*datapb.VerifySegmentResponse, error) {
+	return s.datanode.VerifySegment(ctx, req)
+}
+
+func (s *Server) DropCompactionPlan(ctx context.Context, req *datapb.DropCompactionPlanRequest) (*commonpb.Status, error) {
+	return s.datanode.DropCompactionPlan(ctx, req)
+}
@@ -186,6 +186,14 @@ func (m *MockDataNode) DropImport(ctx context.Context, req *datapb.DropImportReq
 	return m.status, m.err
 }
 
+func (m *MockDataNode) VerifySegment(ctx context.Context, req *datapb.VerifySegmentRequest) (*datapb.VerifySegmentResponse, error) {
+	return &datapb.VerifySegmentResponse{Status: m.status}, m.err
+}
+
+func (m *MockDataNode) DropCompactionPlan(ctx context.Context, req *datapb.DropCompactionPlanRequest) (*commonpb.Status, error) {
+	return m.status, m.err
+}
+
 // /////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 func Test_NewServer(t *testing.T) {
 	paramtable.Init()
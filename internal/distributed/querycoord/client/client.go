@@ -25,6 +25,7 @@ import (
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proto/querypb"
 	"github.com/milvus-io/milvus/internal/util/grpcclient"
@@ -401,3 +402,25 @@ func (c *Client) DeactivateChecker(ctx context.Context, req *querypb.DeactivateC
 		return client.DeactivateChecker(ctx, req)
 	})
 }
+
+func (c *Client) DecommissionNode(ctx context.Context, req *datapb.DecommissionNodeRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	req = typeutil.Clone(req)
+	commonpbutil.UpdateMsgBase(
+		req.GetBase(),
+		commonpbutil.FillMsgBaseFromClient(paramtable.GetNodeID(), commonpbutil.WithTargetID(c.grpcClient.GetNodeID())),
+	)
+	return wrapGrpcCall(ctx, c, func(client querypb.QueryCoordClient) (*commonpb.Status, error) {
+		return client.DecommissionNode(ctx, req)
+	})
+}
+
+func (c *Client) GetDecommissionState(ctx context.Context, req *datapb.GetDecommissionStateRequest, opts ...grpc.CallOption) (*datapb.GetDecommissionStateResponse, error) {
+	req = typeutil.Clone(req)
+	commonpbutil.UpdateMsgBase(
+		req.GetBase(),
+		commonpbutil.FillMsgBaseFromClient(paramtable.GetNodeID(), commonpbutil.WithTargetID(c.grpcClient.GetNodeID())),
+	)
+	return wrapGrpcCall(ctx, c, func(client querypb.QueryCoordClient) (*datapb.GetDecommissionStateResponse, error) {
+		return client.GetDecommissionState(ctx, req)
+	})
+}
@@ -37,6 +37,7 @@ import (
 	dcc "github.com/milvus-io/milvus/internal/distributed/datacoord/client"
 	rcc "github.com/milvus-io/milvus/internal/distributed/rootcoord/client"
 	"github.com/milvus-io/milvus/internal/distributed/utils"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proto/querypb"
 	qc "github.com/milvus-io/milvus/internal/querycoordv2"
@@ -432,3 +433,11 @@ func (s *Server) DeactivateChecker(ctx context.Context, req *querypb.DeactivateC
 func (s *Server) ListCheckers(ctx context.Context, req *querypb.ListCheckersRequest) (*querypb.ListCheckersResponse, error) {
 	return s.queryCoord.ListCheckers(ctx, req)
 }
+
+func (s *Server) DecommissionNode(ctx context.Context, req *datapb.DecommissionNodeRequest) (*commonpb.Status, error) {
+	return s.queryCoord.DecommissionNode(ctx, req)
+}
+
+func (s *Server) GetDecommissionState(ctx context.Context, req *datapb.GetDecommissionStateRequest) (*datapb.GetDecommissionStateResponse, error) {
+	return s.queryCoord.GetDecommissionState(ctx, req)
+}
@@ -25,5 +25,8 @@ const LogLevelRouterPath = "/log/level"
 // EventLogRouterPath is path for eventlog control.
 const EventLogRouterPath = "/eventlog"
 
+// StandbyRouterPath is path for the active/standby status of the coordinators.
+const StandbyRouterPath = "/standby"
+
 // ExprPath is path for expression.
 const ExprPath = "/expr"
@@ -26,6 +26,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus/internal/http/healthz"
+	"github.com/milvus-io/milvus/internal/http/standby"
 	"github.com/milvus-io/milvus/pkg/eventlog"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util/expr"
@@ -71,6 +72,10 @@ func registerDefaults() {
 		Path:    EventLogRouterPath,
 		Handler: eventlog.Handler(),
 	})
+	Register(&Handler{
+		Path:    StandbyRouterPath,
+		Handler: standby.DefaultHandler(),
+	})
 	Register(&Handler{
 		Path: ExprPath,
 		Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -34,6 +34,7 @@ import (
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus/internal/http/healthz"
+	"github.com/milvus-io/milvus/internal/http/standby"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util/expr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
@@ -128,6 +129,29 @@ func (suite *HTTPServerTestSuite) TestHealthzHandler() {
 	suite.Equal("{\"state\":\"component m2 state is Abnormal\",\"detail\":[{\"name\":\"m1\",\"code\":1},{\"name\":\"m2\",\"code\":2}]}", string(body))
 }
 
+type mockStandbyIndicator struct {
+	status *standby.Status
+}
+
+func (m *mockStandbyIndicator) StandbyStatus() *standby.Status {
+	return m.status
+}
+
+func (suite *HTTPServerTestSuite) TestStandbyHandler() {
+	standby.Register(&mockStandbyIndicator{standby.NewStatus("datacoord", 1, commonpb.StateCode_StandBy)})
+	standby.Register(&mockStandbyIndicator{standby.NewStatus("rootcoord", 2, commonpb.StateCode_Healthy)})
+
+	url := "http://localhost:" + DefaultListenPort + StandbyRouterPath
+	client := http.Client{}
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	resp, err := client.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	suite.Equal("{\"coordinators\":[{\"role\":\"datacoord\",\"server_id\":1,\"state\":\"standby\",\"warm\":false},{\"role\":\"rootcoord\",\"server_id\":2,\"state\":\"active\",\"warm\":false}]}", string(body))
+}
+
 func (suite *HTTPServerTestSuite) TestEventlogHandler() {
 	url := "http://localhost:" + DefaultListenPort + EventLogRouterPath
 	client := http.Client{}
@@ -0,0 +1,124 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package standby exposes the active/standby status of the coordinators in the process.
+package standby
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/log"
+)
+
+const (
+	// StateActive means the coordinator is serving.
+	StateActive = "active"
+	// StateStandby means the coordinator is waiting to be activated.
+	StateStandby = "standby"
+)
+
+// Status is the active/standby status of a coordinator.
+type Status struct {
+	Role     string `json:"role"`
+	ServerID int64  `json:"server_id"`
+	State    string `json:"state"`
+	// Warm is true if the standby keeps its meta up to date, so it could be activated without a full reload.
+	Warm bool `json:"warm"`
+	// Revision is the meta store revision the warm standby has caught up.
+	Revision int64 `json:"revision,omitempty"`
+	// PendingChanges is the number of changed meta not applied by the warm standby yet.
+	PendingChanges int        `json:"pending_changes,omitempty"`
+	LastSyncTime   *time.Time `json:"last_sync_time,omitempty"`
+	// Error is the reason why the warm standby falls back to a full reload on activation.
+	Error string `json:"error,omitempty"`
+}
+
+// NewStatus returns the status of a coordinator without warm standby.
+func NewStatus(role string, serverID int64, code commonpb.StateCode) *Status {
+	state := strings.ToLower(code.String())
+	switch code {
+	case commonpb.StateCode_Healthy:
+		state = StateActive
+	case commonpb.StateCode_StandBy:
+		state = StateStandby
+	}
+	return &Status{
+		Role:     role,
+		ServerID: serverID,
+		State:    state,
+	}
+}
+
+// Indicator reports the status of a coordinator.
+type Indicator interface {
+	StandbyStatus() *Status
+}
+
+// Response is the response of the standby status API.
+type Response struct {
+	Coordinators []*Status `json:"coordinators"`
+}
+
+type Handler struct {
+	mu         sync.RWMutex
+	indicators []Indicator
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+var defaultHandler = &Handler{}
+
+// Register registers the coordinator to the default handler.
+func Register(indicator Indicator) {
+	defaultHandler.Register(indicator)
+}
+
+// DefaultHandler returns the handler of the coordinators registered by Register.
+func DefaultHandler() *Handler {
+	return defaultHandler
+}
+
+func (handler *Handler) Register(indicator Indicator) {
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	handler.indicators = append(handler.indicators, indicator)
+}
+
+func (handler *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler.mu.RLock()
+	resp := &Response{Coordinators: make([]*Status, 0, len(handler.indicators))}
+	for _, indicator := range handler.indicators {
+		resp.Coordinators = append(resp.Coordinators, indicator.StandbyStatus())
+	}
+	handler.mu.RUnlock()
+
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("failed to marshal standby status", zap.Error(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/milvus-io/milvus/internal/proto/indexpb"
 	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
@@ -24,6 +25,15 @@ func NewChunkMgrFactory() *chunkMgrFactory {
 }
 
 func (m *chunkMgrFactory) NewChunkManager(ctx context.Context, config *indexpb.StorageConfig) (storage.ChunkManager, error) {
+	// upload tuning is local to the node, so it is not carried in the storage config.
+	minioCfg := &paramtable.Get().MinioCfg
+	if config.GetStorageType() == storage.StorageTypeHDFS {
+		// the kerberos token and the block layout of hdfs are local to the node as well
+		chunkManagerFactory := storage.NewChunkManagerFactory(storage.StorageTypeHDFS, append(storage.HDFSOptions(paramtable.Get()),
+			storage.RootPath(config.GetRootPath()),
+			storage.CreateBucket(true))...)
+		return chunkManagerFactory.NewPersistentStorageChunkManager(ctx)
+	}
 	chunkManagerFactory := storage.NewChunkManagerFactory(config.GetStorageType(),
 		storage.RootPath(config.GetRootPath()),
 		storage.Address(config.GetAddress()),
@@ -37,6 +47,11 @@ func (m *chunkMgrFactory) NewChunkManager(ctx context.Context, config *indexpb.S
 		storage.UseVirtualHost(config.GetUseVirtualHost()),
 		storage.RequestTimeout(config.GetRequestTimeoutMs()),
 		storage.Region(config.GetRegion()),
+		storage.UploadPartSize(minioCfg.UploadPartSize.GetAsInt64()),
+		storage.UploadConcurrency(minioCfg.UploadConcurrency.GetAsInt()),
+		storage.UploadThreshold(minioCfg.UploadThreshold.GetAsInt64()),
+		storage.UploadBandwidthLimit(minioCfg.UploadBandwidthLimitMB.GetAsFloat()),
+		storage.ObjectPolicyOptions(paramtable.Get()),
 		storage.CreateBucket(true),
 	)
 	return chunkManagerFactory.NewPersistentStorageChunkManager(ctx)
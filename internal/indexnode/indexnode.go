@@ -46,8 +46,10 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/internal/util/dependency"
+	"github.com/milvus-io/milvus/internal/util/diskwatchdog"
 	"github.com/milvus-io/milvus/internal/util/initcore"
 	"github.com/milvus-io/milvus/internal/util/sessionutil"
 	"github.com/milvus-io/milvus/pkg/common"
@@ -223,6 +225,17 @@ func (i *IndexNode) Init() error {
 		}
 		log.Info("IndexNode init session successful", zap.Int64("serverID", i.session.ServerID))
 
+		if err := storage.CheckSegcoreStorageType(paramtable.Get().CommonCfg.StorageType.GetValue()); err != nil {
+			log.Error("IndexNode init failed", zap.Error(err))
+			initErr = err
+			return
+		}
+		// the segcore puts the index files built with the object policy
+		if _, err := storage.SegcoreIndexFilesPolicy(paramtable.Get()); err != nil {
+			log.Error("IndexNode init failed", zap.Error(err))
+			initErr = err
+			return
+		}
 		i.initSegcore()
 	})
 
@@ -235,6 +248,7 @@ func (i *IndexNode) Start() error {
 	var startErr error
 	i.once.Do(func() {
 		startErr = i.sched.Start()
+		diskwatchdog.Start(typeutil.IndexNodeRole)
 
 		i.UpdateStateCode(commonpb.StateCode_Healthy)
 		log.Info("IndexNode", zap.String("State", i.lifetime.GetState().String()))
@@ -274,6 +288,7 @@ func (i *IndexNode) Stop() error {
 		if i.session != nil {
 			i.session.Stop()
 		}
+		diskwatchdog.Stop()
 
 		i.CloseSegcore()
 		log.Info("Index node stopped.")
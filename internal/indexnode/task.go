@@ -32,6 +32,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proto/indexpb"
 	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/diskwatchdog"
 	"github.com/milvus-io/milvus/internal/util/indexcgowrapper"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
@@ -109,6 +110,10 @@ func (it *indexBuildTaskV2) BuildIndex(ctx context.Context) error {
 				zap.Bool("enable disk", Params.IndexNodeCfg.EnableDisk.GetAsBool()))
 			return merr.WrapErrIndexNotSupported("disk index")
 		}
+		if err := diskwatchdog.CheckAvailable(); err != nil {
+			log.Ctx(ctx).Warn("IndexNode pauses building disk index, local disk is in degraded mode", zap.Error(err))
+			return err
+		}
 
 		// check load size and size of field data
 		localUsedSize, err := indexcgowrapper.GetLocalUsedSize(paramtable.Get().LocalStorageCfg.Path.GetValue())
@@ -138,8 +143,13 @@ func (it *indexBuildTaskV2) BuildIndex(ctx context.Context) error {
 		}
 	}
 
+	indexFilesPolicy, err := storage.SegcoreIndexFilesPolicy(paramtable.Get())
+	if err != nil {
+		log.Ctx(ctx).Warn("invalid object policy of the index files", zap.Error(err))
+		return err
+	}
 	var buildIndexInfo *indexcgowrapper.BuildIndexInfo
-	buildIndexInfo, err = indexcgowrapper.NewBuildIndexInfo(it.req.GetStorageConfig())
+	buildIndexInfo, err = indexcgowrapper.NewBuildIndexInfo(it.req.GetStorageConfig(), indexFilesPolicy)
 	defer indexcgowrapper.DeleteBuildIndexInfo(buildIndexInfo)
 	if err != nil {
 		log.Ctx(ctx).Warn("create build index info failed", zap.Error(err))
@@ -453,6 +463,10 @@ func (it *indexBuildTask) BuildIndex(ctx context.Context) error {
 				zap.Bool("enable disk", Params.IndexNodeCfg.EnableDisk.GetAsBool()))
 			return errors.New("index node don't support build disk index")
 		}
+		if err := diskwatchdog.CheckAvailable(); err != nil {
+			log.Ctx(ctx).Warn("IndexNode pauses building disk index, local disk is in degraded mode", zap.Error(err))
+			return err
+		}
 
 		// check load size and size of field data
 		localUsedSize, err := indexcgowrapper.GetLocalUsedSize(paramtable.Get().LocalStorageCfg.Path.GetValue())
@@ -482,8 +496,13 @@ func (it *indexBuildTask) BuildIndex(ctx context.Context) error {
 		}
 	}
 
+	indexFilesPolicy, err := storage.SegcoreIndexFilesPolicy(paramtable.Get())
+	if err != nil {
+		log.Ctx(ctx).Warn("invalid object policy of the index files", zap.Error(err))
+		return err
+	}
 	var buildIndexInfo *indexcgowrapper.BuildIndexInfo
-	buildIndexInfo, err = indexcgowrapper.NewBuildIndexInfo(it.req.GetStorageConfig())
+	buildIndexInfo, err = indexcgowrapper.NewBuildIndexInfo(it.req.GetStorageConfig(), indexFilesPolicy)
 	defer indexcgowrapper.DeleteBuildIndexInfo(buildIndexInfo)
 	if err != nil {
 		log.Ctx(ctx).Warn("create build index info failed", zap.Error(err))
@@ -109,6 +109,8 @@ type BinlogsIncrement struct {
 //go:generate mockery --name=DataCoordCatalog --with-expecter
 type DataCoordCatalog interface {
 	ListSegments(ctx context.Context) ([]*datapb.SegmentInfo, error)
+	// LoadSegment loads the segment with its binlogs, returns nil if the segment doesn't exist.
+	LoadSegment(ctx context.Context, collectionID, partitionID, segmentID typeutil.UniqueID) (*datapb.SegmentInfo, error)
 	AddSegment(ctx context.Context, segment *datapb.SegmentInfo) error
 	// TODO Remove this later, we should update flush segments info for each segment separately, so far we still need transaction
 	AlterSegments(ctx context.Context, newSegments []*datapb.SegmentInfo, binlogs ...BinlogsIncrement) error
@@ -125,6 +127,10 @@ type DataCoordCatalog interface {
 	SaveChannelCheckpoint(ctx context.Context, vChannel string, pos *msgpb.MsgPosition) error
 	DropChannelCheckpoint(ctx context.Context, vChannel string) error
 
+	SaveSegmentEvents(ctx context.Context, events []*datapb.SegmentEvent) error
+	ListSegmentEvents(ctx context.Context, collectionID typeutil.UniqueID) ([]*datapb.SegmentEvent, error)
+	DropSegmentEvents(ctx context.Context, events []*datapb.SegmentEvent) error
+
 	CreateIndex(ctx context.Context, index *model.Index) error
 	ListIndexes(ctx context.Context) ([]*model.Index, error)
 	AlterIndexes(ctx context.Context, newIndexes []*model.Index) error
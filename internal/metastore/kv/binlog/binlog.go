@@ -96,7 +96,11 @@ func CompressFieldBinlogs(fieldBinlogs []*datapb.FieldBinlog) error {
 					return err
 				}
 				binlog.LogID = logID
-				binlog.LogPath = ""
+				// the tenant of the collection and the layout dir are unknown when the path is rebuilt,
+				// so the paths of the tenant or of the path layout are kept as they are
+				if metautil.GetTenantFromLogPath(logPath) == "" && !metautil.HasLayoutDir(chunkManagerRootPath(), logPath) {
+					binlog.LogPath = ""
+				}
 			}
 		}
 	}
@@ -167,20 +171,36 @@ func DecompressBinLog(binlogType storage.BinlogType, collectionID, partitionID,
 	return nil
 }
 
-// build a binlog path on the storage by metadata
-func buildLogPath(binlogType storage.BinlogType, collectionID, partitionID, segmentID, fieldID, logID typeutil.UniqueID) (string, error) {
-	chunkManagerRootPath := paramtable.Get().MinioCfg.RootPath.GetValue()
+func chunkManagerRootPath() string {
 	if paramtable.Get().CommonCfg.StorageType.GetValue() == "local" {
-		chunkManagerRootPath = paramtable.Get().LocalStorageCfg.Path.GetValue()
+		return paramtable.Get().LocalStorageCfg.Path.GetValue()
 	}
+	return paramtable.Get().MinioCfg.RootPath.GetValue()
+}
+
+// build a binlog path on the storage by metadata
+func buildLogPath(binlogType storage.BinlogType, collectionID, partitionID, segmentID, fieldID, logID typeutil.UniqueID) (string, error) {
+	rootPath := chunkManagerRootPath()
 	switch binlogType {
 	case storage.InsertBinlog:
-		return metautil.BuildInsertLogPath(chunkManagerRootPath, collectionID, partitionID, segmentID, fieldID, logID), nil
+		return metautil.BuildInsertLogPath(rootPath, collectionID, partitionID, segmentID, fieldID, logID), nil
 	case storage.DeleteBinlog:
-		return metautil.BuildDeltaLogPath(chunkManagerRootPath, collectionID, partitionID, segmentID, logID), nil
+		return metautil.BuildDeltaLogPath(rootPath, collectionID, partitionID, segmentID, logID), nil
 	case storage.StatsBinlog:
-		return metautil.BuildStatsLogPath(chunkManagerRootPath, collectionID, partitionID, segmentID, fieldID, logID), nil
+		return metautil.BuildStatsLogPath(rootPath, collectionID, partitionID, segmentID, fieldID, logID), nil
 	}
 	// should not happen
 	return "", merr.WrapErrParameterInvalidMsg("invalid binlog type")
 }
+
+// FilterDeltalogs returns the deltalogs of the primary key field.
+// The deltalogs written before the field id is recorded have FieldID 0, which are taken as of the primary key field.
+func FilterDeltalogs(fieldBinlogs []*datapb.FieldBinlog, pkFieldID typeutil.UniqueID) []*datapb.FieldBinlog {
+	ret := make([]*datapb.FieldBinlog, 0, len(fieldBinlogs))
+	for _, fieldBinlog := range fieldBinlogs {
+		if fieldBinlog.GetFieldID() == 0 || fieldBinlog.GetFieldID() == pkFieldID {
+			ret = append(ret, fieldBinlog)
+		}
+	}
+	return ret
+}
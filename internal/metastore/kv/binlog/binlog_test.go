@@ -18,6 +18,7 @@ package binlog
 
 import (
 	"math/rand"
+	"path"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
@@ -288,3 +289,57 @@ func TestBinlog_Compress(t *testing.T) {
 	err = DecompressBinLog(invaildType, 1, 1, 1, segmentInfo.Binlogs)
 	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
 }
+
+func TestBinlog_CompressTenant(t *testing.T) {
+	paramtable.Init()
+	rootPath := metautil.TenantRootPath(paramtable.Get().MinioCfg.RootPath.GetValue(), "tenant1")
+	segmentInfo := getSegment(rootPath, 0, 1, 2, 3, 10)
+	compressedSegmentInfo := proto.Clone(segmentInfo).(*datapb.SegmentInfo)
+	err := CompressBinLogs(compressedSegmentInfo)
+	assert.NoError(t, err)
+
+	// the paths of the tenant are kept
+	for i := 0; i < 10; i++ {
+		binlog := compressedSegmentInfo.GetBinlogs()[0].GetBinlogs()[i]
+		assert.EqualValues(t, i, binlog.GetLogID())
+		assert.Equal(t, segmentInfo.GetBinlogs()[0].GetBinlogs()[i].GetLogPath(), binlog.GetLogPath())
+		assert.Equal(t, "tenant1", metautil.GetTenantFromLogPath(binlog.GetLogPath()))
+	}
+	err = DecompressBinLogs(compressedSegmentInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, segmentInfo.GetDeltalogs()[0].GetBinlogs()[0].GetLogPath(), compressedSegmentInfo.GetDeltalogs()[0].GetBinlogs()[0].GetLogPath())
+	assert.Equal(t, segmentInfo.GetStatslogs()[0].GetBinlogs()[0].GetLogPath(), compressedSegmentInfo.GetStatslogs()[0].GetBinlogs()[0].GetLogPath())
+}
+
+func TestBinlog_CompressLayout(t *testing.T) {
+	paramtable.Init()
+	rootPath := path.Join(chunkManagerRootPath(), "by-dev", "2024-03")
+	segmentInfo := getSegment(rootPath, 0, 1, 2, 3, 10)
+	compressedSegmentInfo := proto.Clone(segmentInfo).(*datapb.SegmentInfo)
+	err := CompressBinLogs(compressedSegmentInfo)
+	assert.NoError(t, err)
+
+	// the paths of the path layout are kept
+	for i := 0; i < 10; i++ {
+		binlog := compressedSegmentInfo.GetBinlogs()[0].GetBinlogs()[i]
+		assert.EqualValues(t, i, binlog.GetLogID())
+		assert.Equal(t, segmentInfo.GetBinlogs()[0].GetBinlogs()[i].GetLogPath(), binlog.GetLogPath())
+	}
+	err = DecompressBinLogs(compressedSegmentInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, segmentInfo.GetDeltalogs()[0].GetBinlogs()[0].GetLogPath(), compressedSegmentInfo.GetDeltalogs()[0].GetBinlogs()[0].GetLogPath())
+	assert.Equal(t, segmentInfo.GetStatslogs()[0].GetBinlogs()[0].GetLogPath(), compressedSegmentInfo.GetStatslogs()[0].GetBinlogs()[0].GetLogPath())
+}
+
+func TestBinlog_FilterDeltalogs(t *testing.T) {
+	deltalogs := []*datapb.FieldBinlog{
+		{FieldID: 0, Binlogs: []*datapb.Binlog{{LogID: 1}}},
+		{FieldID: 100, Binlogs: []*datapb.Binlog{{LogID: 2}}},
+		{FieldID: 101, Binlogs: []*datapb.Binlog{{LogID: 3}}},
+	}
+	filtered := FilterDeltalogs(deltalogs, 100)
+	assert.Len(t, filtered, 2)
+	assert.EqualValues(t, 1, filtered[0].GetBinlogs()[0].GetLogID())
+	assert.EqualValues(t, 2, filtered[1].GetBinlogs()[0].GetLogID())
+	assert.Empty(t, FilterDeltalogs(nil, 100))
+}
@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/cockroachdb/errors"
 	"github.com/golang/protobuf/proto"
 	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
@@ -42,6 +43,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/util"
 	"github.com/milvus-io/milvus/pkg/util/etcd"
+	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
@@ -248,6 +250,43 @@ func (kc *Catalog) AddSegment(ctx context.Context, segment *datapb.SegmentInfo)
 	return kc.MetaKv.MultiSave(kvs)
 }
 
+// LoadSegment loads the segment with its binlogs, it returns nil if the segment doesn't exist.
+func (kc *Catalog) LoadSegment(ctx context.Context, collectionID, partitionID, segmentID typeutil.UniqueID) (*datapb.SegmentInfo, error) {
+	value, err := kc.MetaKv.Load(buildSegmentPath(collectionID, partitionID, segmentID))
+	if errors.Is(err, merr.ErrIoKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	segment := &datapb.SegmentInfo{}
+	if err := proto.Unmarshal([]byte(value), segment); err != nil {
+		return nil, err
+	}
+
+	logs := make(map[storage.BinlogType]map[typeutil.UniqueID][]*datapb.FieldBinlog)
+	for _, binlogType := range []storage.BinlogType{storage.InsertBinlog, storage.DeleteBinlog, storage.StatsBinlog} {
+		_, values, err := kc.getBinlogsWithPrefix(binlogType, collectionID, partitionID, segmentID)
+		if err != nil {
+			return nil, err
+		}
+		logs[binlogType] = make(map[typeutil.UniqueID][]*datapb.FieldBinlog)
+		for _, value := range values {
+			fieldBinlog := &datapb.FieldBinlog{}
+			if err := proto.Unmarshal([]byte(value), fieldBinlog); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal datapb.FieldBinlog: %d, err:%w", fieldBinlog.FieldID, err)
+			}
+			logs[binlogType][segmentID] = append(logs[binlogType][segmentID], fieldBinlog)
+		}
+	}
+
+	err = kc.applyBinlogInfo([]*datapb.SegmentInfo{segment}, logs[storage.InsertBinlog], logs[storage.DeleteBinlog], logs[storage.StatsBinlog])
+	if err != nil {
+		return nil, err
+	}
+	return segment, nil
+}
+
 // LoadFromSegmentPath loads segment info from persistent storage by given segment path.
 // # TESTING ONLY #
 func (kc *Catalog) LoadFromSegmentPath(colID, partID, segID typeutil.UniqueID) (*datapb.SegmentInfo, error) {
@@ -530,6 +569,44 @@ func (kc *Catalog) DropChannelCheckpoint(ctx context.Context, vChannel string) e
 	return kc.MetaKv.Remove(k)
 }
 
+func (kc *Catalog) SaveSegmentEvents(ctx context.Context, events []*datapb.SegmentEvent) error {
+	kvs := make(map[string]string, len(events))
+	for _, event := range events {
+		v, err := proto.Marshal(event)
+		if err != nil {
+			return err
+		}
+		kvs[buildSegmentEventKey(event)] = string(v)
+	}
+	return etcd.SaveByBatchWithLimit(kvs, maxEtcdTxnNum, kc.MetaKv.MultiSave)
+}
+
+// ListSegmentEvents lists the segment events of the collection, or all the events if collectionID is 0.
+func (kc *Catalog) ListSegmentEvents(ctx context.Context, collectionID typeutil.UniqueID) ([]*datapb.SegmentEvent, error) {
+	_, values, err := kc.MetaKv.LoadWithPrefix(buildSegmentEventPrefix(collectionID))
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*datapb.SegmentEvent, 0, len(values))
+	for _, value := range values {
+		event := &datapb.SegmentEvent{}
+		if err := proto.Unmarshal([]byte(value), event); err != nil {
+			log.Error("unmarshal segment event failed", zap.Error(err))
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (kc *Catalog) DropSegmentEvents(ctx context.Context, events []*datapb.SegmentEvent) error {
+	keys := make([]string, 0, len(events))
+	for _, event := range events {
+		keys = append(keys, buildSegmentEventKey(event))
+	}
+	return etcd.RemoveByBatchWithLimit(keys, maxEtcdTxnNum, kc.MetaKv.MultiRemove)
+}
+
 func (kc *Catalog) getBinlogsWithPrefix(binlogType storage.BinlogType, collectionID, partitionID,
 	segmentID typeutil.UniqueID,
 ) ([]string, []string, error) {
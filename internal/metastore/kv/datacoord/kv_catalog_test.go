@@ -42,6 +42,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/indexpb"
 	"github.com/milvus-io/milvus/pkg/util/etcd"
+	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/metautil"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 )
@@ -265,6 +266,84 @@ func Test_ListSegments(t *testing.T) {
 	})
 }
 
+func Test_LoadSegment(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		metakv := mocks.NewMetaKv(t)
+		metakv.EXPECT().Load(mock.Anything).Return("", merr.WrapErrIoKeyNotFound(k5))
+
+		catalog := NewCatalog(metakv, rootPath, "")
+		ret, err := catalog.LoadSegment(context.TODO(), collectionID, partitionID, segmentID)
+		assert.Nil(t, ret)
+		assert.NoError(t, err)
+	})
+
+	t.Run("load failed", func(t *testing.T) {
+		metakv := mocks.NewMetaKv(t)
+		metakv.EXPECT().Load(mock.Anything).Return("", errors.New("error"))
+
+		catalog := NewCatalog(metakv, rootPath, "")
+		ret, err := catalog.LoadSegment(context.TODO(), collectionID, partitionID, segmentID)
+		assert.Nil(t, ret)
+		assert.Error(t, err)
+	})
+
+	t.Run("load successfully", func(t *testing.T) {
+		var savedKvs map[string]string
+
+		metakv := mocks.NewMetaKv(t)
+		metakv.EXPECT().MultiSave(mock.Anything).RunAndReturn(func(m map[string]string) error {
+			savedKvs = m
+			return nil
+		})
+
+		catalog := NewCatalog(metakv, rootPath, "")
+		err := catalog.AddSegment(context.TODO(), segment1)
+		assert.NoError(t, err)
+
+		metakv.EXPECT().Load(mock.Anything).RunAndReturn(func(key string) (string, error) {
+			return savedKvs[key], nil
+		})
+		metakv.EXPECT().LoadWithPrefix(mock.Anything).RunAndReturn(func(prefix string) ([]string, []string, error) {
+			keys, values := make([]string, 0), make([]string, 0)
+			for key, value := range savedKvs {
+				if strings.HasPrefix(key, prefix) {
+					keys = append(keys, key)
+					values = append(values, value)
+				}
+			}
+			return keys, values, nil
+		})
+
+		ret, err := catalog.LoadSegment(context.TODO(), collectionID, partitionID, segmentID)
+		assert.NoError(t, err)
+		assert.Equal(t, segmentID, ret.GetID())
+		assert.Equal(t, 1, len(ret.GetBinlogs()))
+		assert.Equal(t, 1, len(ret.GetDeltalogs()))
+		assert.Equal(t, 1, len(ret.GetStatslogs()))
+		assert.Equal(t, logID, ret.GetBinlogs()[0].GetBinlogs()[0].GetLogID())
+	})
+}
+
+func Test_ParseSegmentKey(t *testing.T) {
+	for _, key := range []string{k1, k2, k3, k5} {
+		collID, partID, segID, ok := ParseSegmentKey(key)
+		assert.True(t, ok, key)
+		assert.Equal(t, collectionID, collID)
+		assert.Equal(t, partitionID, partID)
+		assert.Equal(t, segmentID, segID)
+	}
+
+	for _, key := range []string{
+		buildChannelCPKey("ch1"),
+		SegmentPrefix + "/1/2",
+		SegmentPrefix + "/1/err/3",
+		SegmentPrefix + "s/1/2/3",
+	} {
+		_, _, _, ok := ParseSegmentKey(key)
+		assert.False(t, ok, key)
+	}
+}
+
 func Test_AddSegments(t *testing.T) {
 	t.Run("generate binlog kvs failed", func(t *testing.T) {
 		metakv := mocks.NewMetaKv(t)
@@ -608,6 +687,59 @@ func TestChannelCP(t *testing.T) {
 	})
 }
 
+func TestSegmentEvents(t *testing.T) {
+	event := &datapb.SegmentEvent{
+		EventID:      1000,
+		CollectionID: 1,
+		SegmentID:    10,
+		Type:         datapb.SegmentEventType_SegmentStateChanged,
+		FromState:    commonpb.SegmentState_Growing,
+		ToState:      commonpb.SegmentState_Sealed,
+	}
+	k := buildSegmentEventKey(event)
+	assert.Equal(t, SegmentEventPrefix+"/1/10/1000", k)
+	v, err := proto.Marshal(event)
+	assert.NoError(t, err)
+
+	t.Run("SaveSegmentEvents", func(t *testing.T) {
+		txn := mocks.NewMetaKv(t)
+		txn.EXPECT().MultiSave(map[string]string{k: string(v)}).Return(nil)
+		catalog := NewCatalog(txn, rootPath, "")
+		err := catalog.SaveSegmentEvents(context.TODO(), []*datapb.SegmentEvent{event})
+		assert.NoError(t, err)
+	})
+
+	t.Run("ListSegmentEvents", func(t *testing.T) {
+		txn := mocks.NewMetaKv(t)
+		txn.EXPECT().LoadWithPrefix(SegmentEventPrefix+"/1/").Return([]string{k}, []string{string(v)}, nil)
+		txn.EXPECT().LoadWithPrefix(SegmentEventPrefix+"/").Return([]string{k}, []string{"invalid"}, nil)
+		catalog := NewCatalog(txn, rootPath, "")
+		res, err := catalog.ListSegmentEvents(context.TODO(), 1)
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		assert.True(t, proto.Equal(event, res[0]))
+
+		_, err = catalog.ListSegmentEvents(context.TODO(), 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("ListSegmentEvents failed", func(t *testing.T) {
+		txn := mocks.NewMetaKv(t)
+		txn.EXPECT().LoadWithPrefix(mock.Anything).Return(nil, nil, errors.New("mock error"))
+		catalog := NewCatalog(txn, rootPath, "")
+		_, err := catalog.ListSegmentEvents(context.TODO(), 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("DropSegmentEvents", func(t *testing.T) {
+		txn := mocks.NewMetaKv(t)
+		txn.EXPECT().MultiRemove([]string{k}).Return(nil)
+		catalog := NewCatalog(txn, rootPath, "")
+		err := catalog.DropSegmentEvents(context.TODO(), []*datapb.SegmentEvent{event})
+		assert.NoError(t, err)
+	})
+}
+
 func Test_MarkChannelDeleted_SaveError(t *testing.T) {
 	txn := mocks.NewMetaKv(t)
 	txn.EXPECT().
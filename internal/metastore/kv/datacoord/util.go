@@ -18,6 +18,8 @@ package datacoord
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	"go.uber.org/zap"
@@ -232,6 +234,31 @@ func buildSegmentPath(collectionID typeutil.UniqueID, partitionID typeutil.Uniqu
 	return fmt.Sprintf("%s/%d/%d/%d", SegmentPrefix, collectionID, partitionID, segmentID)
 }
 
+// ParseSegmentKey parses the ids from the key of the segment or its binlogs, which is relative to the meta root path.
+func ParseSegmentKey(key string) (collectionID, partitionID, segmentID typeutil.UniqueID, ok bool) {
+	for _, prefix := range []string{SegmentPrefix, SegmentBinlogPathPrefix, SegmentDeltalogPathPrefix, SegmentStatslogPathPrefix} {
+		if !strings.HasPrefix(key, prefix+"/") {
+			continue
+		}
+		ids := strings.Split(key[len(prefix)+1:], "/")
+		if len(ids) < 3 {
+			return 0, 0, 0, false
+		}
+		var err error
+		if collectionID, err = strconv.ParseInt(ids[0], 10, 64); err != nil {
+			return 0, 0, 0, false
+		}
+		if partitionID, err = strconv.ParseInt(ids[1], 10, 64); err != nil {
+			return 0, 0, 0, false
+		}
+		if segmentID, err = strconv.ParseInt(ids[2], 10, 64); err != nil {
+			return 0, 0, 0, false
+		}
+		return collectionID, partitionID, segmentID, true
+	}
+	return 0, 0, 0, false
+}
+
 func buildFieldBinlogPath(collectionID typeutil.UniqueID, partitionID typeutil.UniqueID, segmentID typeutil.UniqueID, fieldID typeutil.UniqueID) string {
 	return fmt.Sprintf("%s/%d/%d/%d/%d", SegmentBinlogPathPrefix, collectionID, partitionID, segmentID, fieldID)
 }
@@ -267,6 +294,17 @@ func buildChannelCPKey(vChannel string) string {
 	return fmt.Sprintf("%s/%s", ChannelCheckpointPrefix, vChannel)
 }
 
+func buildSegmentEventKey(event *datapb.SegmentEvent) string {
+	return fmt.Sprintf("%s/%d/%d/%d", SegmentEventPrefix, event.GetCollectionID(), event.GetSegmentID(), event.GetEventID())
+}
+
+func buildSegmentEventPrefix(collectionID typeutil.UniqueID) string {
+	if collectionID == 0 {
+		return SegmentEventPrefix + "/"
+	}
+	return fmt.Sprintf("%s/%d/", SegmentEventPrefix, collectionID)
+}
+
 func BuildIndexKey(collectionID, indexID int64) string {
 	return fmt.Sprintf("%s/%d/%d", util.FieldIndexPrefix, collectionID, indexID)
 }
@@ -517,6 +517,49 @@ func (_c *DataCoordCatalog_DropSegment_Call) RunAndReturn(run func(context.Conte
 	return _c
 }
 
+// DropSegmentEvents provides a mock function with given fields: ctx, events
+func (_m *DataCoordCatalog) DropSegmentEvents(ctx context.Context, events []*datapb.SegmentEvent) error {
+	ret := _m.Called(ctx, events)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*datapb.SegmentEvent) error); ok {
+		r0 = rf(ctx, events)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DataCoordCatalog_DropSegmentEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DropSegmentEvents'
+type DataCoordCatalog_DropSegmentEvents_Call struct {
+	*mock.Call
+}
+
+// DropSegmentEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - events []*datapb.SegmentEvent
+func (_e *DataCoordCatalog_Expecter) DropSegmentEvents(ctx interface{}, events interface{}) *DataCoordCatalog_DropSegmentEvents_Call {
+	return &DataCoordCatalog_DropSegmentEvents_Call{Call: _e.mock.On("DropSegmentEvents", ctx, events)}
+}
+
+func (_c *DataCoordCatalog_DropSegmentEvents_Call) Run(run func(ctx context.Context, events []*datapb.SegmentEvent)) *DataCoordCatalog_DropSegmentEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*datapb.SegmentEvent))
+	})
+	return _c
+}
+
+func (_c *DataCoordCatalog_DropSegmentEvents_Call) Return(_a0 error) *DataCoordCatalog_DropSegmentEvents_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DataCoordCatalog_DropSegmentEvents_Call) RunAndReturn(run func(context.Context, []*datapb.SegmentEvent) error) *DataCoordCatalog_DropSegmentEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DropSegmentIndex provides a mock function with given fields: ctx, collID, partID, segID, buildID
 func (_m *DataCoordCatalog) DropSegmentIndex(ctx context.Context, collID int64, partID int64, segID int64, buildID int64) error {
 	ret := _m.Called(ctx, collID, partID, segID, buildID)
@@ -715,6 +758,61 @@ func (_c *DataCoordCatalog_ListIndexes_Call) RunAndReturn(run func(context.Conte
 	return _c
 }
 
+// ListSegmentEvents provides a mock function with given fields: ctx, collectionID
+func (_m *DataCoordCatalog) ListSegmentEvents(ctx context.Context, collectionID int64) ([]*datapb.SegmentEvent, error) {
+	ret := _m.Called(ctx, collectionID)
+
+	var r0 []*datapb.SegmentEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*datapb.SegmentEvent, error)); ok {
+		return rf(ctx, collectionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*datapb.SegmentEvent); ok {
+		r0 = rf(ctx, collectionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*datapb.SegmentEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, collectionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DataCoordCatalog_ListSegmentEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSegmentEvents'
+type DataCoordCatalog_ListSegmentEvents_Call struct {
+	*mock.Call
+}
+
+// ListSegmentEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - collectionID int64
+func (_e *DataCoordCatalog_Expecter) ListSegmentEvents(ctx interface{}, collectionID interface{}) *DataCoordCatalog_ListSegmentEvents_Call {
+	return &DataCoordCatalog_ListSegmentEvents_Call{Call: _e.mock.On("ListSegmentEvents", ctx, collectionID)}
+}
+
+func (_c *DataCoordCatalog_ListSegmentEvents_Call) Run(run func(ctx context.Context, collectionID int64)) *DataCoordCatalog_ListSegmentEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *DataCoordCatalog_ListSegmentEvents_Call) Return(_a0 []*datapb.SegmentEvent, _a1 error) *DataCoordCatalog_ListSegmentEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DataCoordCatalog_ListSegmentEvents_Call) RunAndReturn(run func(context.Context, int64) ([]*datapb.SegmentEvent, error)) *DataCoordCatalog_ListSegmentEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListSegmentIndexes provides a mock function with given fields: ctx
 func (_m *DataCoordCatalog) ListSegmentIndexes(ctx context.Context) ([]*model.SegmentIndex, error) {
 	ret := _m.Called(ctx)
@@ -823,6 +921,63 @@ func (_c *DataCoordCatalog_ListSegments_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
+// LoadSegment provides a mock function with given fields: ctx, collectionID, partitionID, segmentID
+func (_m *DataCoordCatalog) LoadSegment(ctx context.Context, collectionID int64, partitionID int64, segmentID int64) (*datapb.SegmentInfo, error) {
+	ret := _m.Called(ctx, collectionID, partitionID, segmentID)
+
+	var r0 *datapb.SegmentInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) (*datapb.SegmentInfo, error)); ok {
+		return rf(ctx, collectionID, partitionID, segmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) *datapb.SegmentInfo); ok {
+		r0 = rf(ctx, collectionID, partitionID, segmentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.SegmentInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int64) error); ok {
+		r1 = rf(ctx, collectionID, partitionID, segmentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DataCoordCatalog_LoadSegment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LoadSegment'
+type DataCoordCatalog_LoadSegment_Call struct {
+	*mock.Call
+}
+
+// LoadSegment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - collectionID int64
+//   - partitionID int64
+//   - segmentID int64
+func (_e *DataCoordCatalog_Expecter) LoadSegment(ctx interface{}, collectionID interface{}, partitionID interface{}, segmentID interface{}) *DataCoordCatalog_LoadSegment_Call {
+	return &DataCoordCatalog_LoadSegment_Call{Call: _e.mock.On("LoadSegment", ctx, collectionID, partitionID, segmentID)}
+}
+
+func (_c *DataCoordCatalog_LoadSegment_Call) Run(run func(ctx context.Context, collectionID int64, partitionID int64, segmentID int64)) *DataCoordCatalog_LoadSegment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(int64))
+	})
+	return _c
+}
+
+func (_c *DataCoordCatalog_LoadSegment_Call) Return(_a0 *datapb.SegmentInfo, _a1 error) *DataCoordCatalog_LoadSegment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DataCoordCatalog_LoadSegment_Call) RunAndReturn(run func(context.Context, int64, int64, int64) (*datapb.SegmentInfo, error)) *DataCoordCatalog_LoadSegment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // MarkChannelAdded provides a mock function with given fields: ctx, channel
 func (_m *DataCoordCatalog) MarkChannelAdded(ctx context.Context, channel string) error {
 	ret := _m.Called(ctx, channel)
@@ -996,6 +1151,49 @@ func (_c *DataCoordCatalog_SaveDroppedSegmentsInBatch_Call) RunAndReturn(run fun
 	return _c
 }
 
+// SaveSegmentEvents provides a mock function with given fields: ctx, events
+func (_m *DataCoordCatalog) SaveSegmentEvents(ctx context.Context, events []*datapb.SegmentEvent) error {
+	ret := _m.Called(ctx, events)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*datapb.SegmentEvent) error); ok {
+		r0 = rf(ctx, events)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DataCoordCatalog_SaveSegmentEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveSegmentEvents'
+type DataCoordCatalog_SaveSegmentEvents_Call struct {
+	*mock.Call
+}
+
+// SaveSegmentEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - events []*datapb.SegmentEvent
+func (_e *DataCoordCatalog_Expecter) SaveSegmentEvents(ctx interface{}, events interface{}) *DataCoordCatalog_SaveSegmentEvents_Call {
+	return &DataCoordCatalog_SaveSegmentEvents_Call{Call: _e.mock.On("SaveSegmentEvents", ctx, events)}
+}
+
+func (_c *DataCoordCatalog_SaveSegmentEvents_Call) Run(run func(ctx context.Context, events []*datapb.SegmentEvent)) *DataCoordCatalog_SaveSegmentEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*datapb.SegmentEvent))
+	})
+	return _c
+}
+
+func (_c *DataCoordCatalog_SaveSegmentEvents_Call) Return(_a0 error) *DataCoordCatalog_SaveSegmentEvents_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DataCoordCatalog_SaveSegmentEvents_Call) RunAndReturn(run func(context.Context, []*datapb.SegmentEvent) error) *DataCoordCatalog_SaveSegmentEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ShouldDropChannel provides a mock function with given fields: ctx, channel
 func (_m *DataCoordCatalog) ShouldDropChannel(ctx context.Context, channel string) bool {
 	ret := _m.Called(ctx, channel)
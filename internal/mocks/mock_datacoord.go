@@ -91,6 +91,61 @@ func (_c *MockDataCoord_AlterIndex_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// AlterMajorCompaction provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) AlterMajorCompaction(_a0 context.Context, _a1 *datapb.AlterMajorCompactionRequest) (*commonpb.Status, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *commonpb.Status
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.AlterMajorCompactionRequest) (*commonpb.Status, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.AlterMajorCompactionRequest) *commonpb.Status); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*commonpb.Status)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.AlterMajorCompactionRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_AlterMajorCompaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AlterMajorCompaction'
+type MockDataCoord_AlterMajorCompaction_Call struct {
+	*mock.Call
+}
+
+// AlterMajorCompaction is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.AlterMajorCompactionRequest
+func (_e *MockDataCoord_Expecter) AlterMajorCompaction(_a0 interface{}, _a1 interface{}) *MockDataCoord_AlterMajorCompaction_Call {
+	return &MockDataCoord_AlterMajorCompaction_Call{Call: _e.mock.On("AlterMajorCompaction", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_AlterMajorCompaction_Call) Run(run func(_a0 context.Context, _a1 *datapb.AlterMajorCompactionRequest)) *MockDataCoord_AlterMajorCompaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.AlterMajorCompactionRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_AlterMajorCompaction_Call) Return(_a0 *commonpb.Status, _a1 error) *MockDataCoord_AlterMajorCompaction_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_AlterMajorCompaction_Call) RunAndReturn(run func(context.Context, *datapb.AlterMajorCompactionRequest) (*commonpb.Status, error)) *MockDataCoord_AlterMajorCompaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // AssignSegmentID provides a mock function with given fields: _a0, _a1
 func (_m *MockDataCoord) AssignSegmentID(_a0 context.Context, _a1 *datapb.AssignSegmentIDRequest) (*datapb.AssignSegmentIDResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -146,6 +201,61 @@ func (_c *MockDataCoord_AssignSegmentID_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
+// Backup provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) Backup(_a0 context.Context, _a1 *datapb.BackupRequest) (*datapb.BackupResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.BackupResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.BackupRequest) (*datapb.BackupResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.BackupRequest) *datapb.BackupResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.BackupResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.BackupRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_Backup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Backup'
+type MockDataCoord_Backup_Call struct {
+	*mock.Call
+}
+
+// Backup is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.BackupRequest
+func (_e *MockDataCoord_Expecter) Backup(_a0 interface{}, _a1 interface{}) *MockDataCoord_Backup_Call {
+	return &MockDataCoord_Backup_Call{Call: _e.mock.On("Backup", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_Backup_Call) Run(run func(_a0 context.Context, _a1 *datapb.BackupRequest)) *MockDataCoord_Backup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.BackupRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_Backup_Call) Return(_a0 *datapb.BackupResponse, _a1 error) *MockDataCoord_Backup_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_Backup_Call) RunAndReturn(run func(context.Context, *datapb.BackupRequest) (*datapb.BackupResponse, error)) *MockDataCoord_Backup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // BroadcastAlteredCollection provides a mock function with given fields: _a0, _a1
 func (_m *MockDataCoord) BroadcastAlteredCollection(_a0 context.Context, _a1 *datapb.AlterCollectionRequest) (*commonpb.Status, error) {
 	ret := _m.Called(_a0, _a1)
@@ -311,6 +421,61 @@ func (_c *MockDataCoord_CreateIndex_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// DecommissionNode provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) DecommissionNode(_a0 context.Context, _a1 *datapb.DecommissionNodeRequest) (*commonpb.Status, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *commonpb.Status
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.DecommissionNodeRequest) (*commonpb.Status, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.DecommissionNodeRequest) *commonpb.Status); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*commonpb.Status)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.DecommissionNodeRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_DecommissionNode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DecommissionNode'
+type MockDataCoord_DecommissionNode_Call struct {
+	*mock.Call
+}
+
+// DecommissionNode is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.DecommissionNodeRequest
+func (_e *MockDataCoord_Expecter) DecommissionNode(_a0 interface{}, _a1 interface{}) *MockDataCoord_DecommissionNode_Call {
+	return &MockDataCoord_DecommissionNode_Call{Call: _e.mock.On("DecommissionNode", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_DecommissionNode_Call) Run(run func(_a0 context.Context, _a1 *datapb.DecommissionNodeRequest)) *MockDataCoord_DecommissionNode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.DecommissionNodeRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_DecommissionNode_Call) Return(_a0 *commonpb.Status, _a1 error) *MockDataCoord_DecommissionNode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_DecommissionNode_Call) RunAndReturn(run func(context.Context, *datapb.DecommissionNodeRequest) (*commonpb.Status, error)) *MockDataCoord_DecommissionNode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DescribeIndex provides a mock function with given fields: _a0, _a1
 func (_m *MockDataCoord) DescribeIndex(_a0 context.Context, _a1 *indexpb.DescribeIndexRequest) (*indexpb.DescribeIndexResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -476,6 +641,61 @@ func (_c *MockDataCoord_DropVirtualChannel_Call) RunAndReturn(run func(context.C
 	return _c
 }
 
+// Export provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) Export(_a0 context.Context, _a1 *datapb.ExportRequest) (*datapb.ExportResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.ExportResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.ExportRequest) (*datapb.ExportResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.ExportRequest) *datapb.ExportResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.ExportResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.ExportRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_Export_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Export'
+type MockDataCoord_Export_Call struct {
+	*mock.Call
+}
+
+// Export is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.ExportRequest
+func (_e *MockDataCoord_Expecter) Export(_a0 interface{}, _a1 interface{}) *MockDataCoord_Export_Call {
+	return &MockDataCoord_Export_Call{Call: _e.mock.On("Export", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_Export_Call) Run(run func(_a0 context.Context, _a1 *datapb.ExportRequest)) *MockDataCoord_Export_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.ExportRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_Export_Call) Return(_a0 *datapb.ExportResponse, _a1 error) *MockDataCoord_Export_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_Export_Call) RunAndReturn(run func(context.Context, *datapb.ExportRequest) (*datapb.ExportResponse, error)) *MockDataCoord_Export_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Flush provides a mock function with given fields: _a0, _a1
 func (_m *MockDataCoord) Flush(_a0 context.Context, _a1 *datapb.FlushRequest) (*datapb.FlushResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -861,6 +1081,116 @@ func (_c *MockDataCoord_GetComponentStates_Call) RunAndReturn(run func(context.C
 	return _c
 }
 
+// GetDecommissionState provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) GetDecommissionState(_a0 context.Context, _a1 *datapb.GetDecommissionStateRequest) (*datapb.GetDecommissionStateResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.GetDecommissionStateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetDecommissionStateRequest) (*datapb.GetDecommissionStateResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetDecommissionStateRequest) *datapb.GetDecommissionStateResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.GetDecommissionStateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetDecommissionStateRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_GetDecommissionState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDecommissionState'
+type MockDataCoord_GetDecommissionState_Call struct {
+	*mock.Call
+}
+
+// GetDecommissionState is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.GetDecommissionStateRequest
+func (_e *MockDataCoord_Expecter) GetDecommissionState(_a0 interface{}, _a1 interface{}) *MockDataCoord_GetDecommissionState_Call {
+	return &MockDataCoord_GetDecommissionState_Call{Call: _e.mock.On("GetDecommissionState", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_GetDecommissionState_Call) Run(run func(_a0 context.Context, _a1 *datapb.GetDecommissionStateRequest)) *MockDataCoord_GetDecommissionState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.GetDecommissionStateRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_GetDecommissionState_Call) Return(_a0 *datapb.GetDecommissionStateResponse, _a1 error) *MockDataCoord_GetDecommissionState_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_GetDecommissionState_Call) RunAndReturn(run func(context.Context, *datapb.GetDecommissionStateRequest) (*datapb.GetDecommissionStateResponse, error)) *MockDataCoord_GetDecommissionState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetExportState provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) GetExportState(_a0 context.Context, _a1 *datapb.GetExportStateRequest) (*datapb.GetExportStateResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.GetExportStateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetExportStateRequest) (*datapb.GetExportStateResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetExportStateRequest) *datapb.GetExportStateResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.GetExportStateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetExportStateRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_GetExportState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetExportState'
+type MockDataCoord_GetExportState_Call struct {
+	*mock.Call
+}
+
+// GetExportState is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.GetExportStateRequest
+func (_e *MockDataCoord_Expecter) GetExportState(_a0 interface{}, _a1 interface{}) *MockDataCoord_GetExportState_Call {
+	return &MockDataCoord_GetExportState_Call{Call: _e.mock.On("GetExportState", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_GetExportState_Call) Run(run func(_a0 context.Context, _a1 *datapb.GetExportStateRequest)) *MockDataCoord_GetExportState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.GetExportStateRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_GetExportState_Call) Return(_a0 *datapb.GetExportStateResponse, _a1 error) *MockDataCoord_GetExportState_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_GetExportState_Call) RunAndReturn(run func(context.Context, *datapb.GetExportStateRequest) (*datapb.GetExportStateResponse, error)) *MockDataCoord_GetExportState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetFlushAllState provides a mock function with given fields: _a0, _a1
 func (_m *MockDataCoord) GetFlushAllState(_a0 context.Context, _a1 *milvuspb.GetFlushAllStateRequest) (*milvuspb.GetFlushAllStateResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -1026,24 +1356,24 @@ func (_c *MockDataCoord_GetFlushedSegments_Call) RunAndReturn(run func(context.C
 	return _c
 }
 
-// GetIndexBuildProgress provides a mock function with given fields: _a0, _a1
-func (_m *MockDataCoord) GetIndexBuildProgress(_a0 context.Context, _a1 *indexpb.GetIndexBuildProgressRequest) (*indexpb.GetIndexBuildProgressResponse, error) {
+// GetGcReport provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) GetGcReport(_a0 context.Context, _a1 *datapb.GetGcReportRequest) (*datapb.GetGcReportResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *indexpb.GetIndexBuildProgressResponse
+	var r0 *datapb.GetGcReportResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *indexpb.GetIndexBuildProgressRequest) (*indexpb.GetIndexBuildProgressResponse, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetGcReportRequest) (*datapb.GetGcReportResponse, error)); ok {
 		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *indexpb.GetIndexBuildProgressRequest) *indexpb.GetIndexBuildProgressResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetGcReportRequest) *datapb.GetGcReportResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*indexpb.GetIndexBuildProgressResponse)
+			r0 = ret.Get(0).(*datapb.GetGcReportResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *indexpb.GetIndexBuildProgressRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetGcReportRequest) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -1052,42 +1382,97 @@ func (_m *MockDataCoord) GetIndexBuildProgress(_a0 context.Context, _a1 *indexpb
 	return r0, r1
 }
 
-// MockDataCoord_GetIndexBuildProgress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIndexBuildProgress'
-type MockDataCoord_GetIndexBuildProgress_Call struct {
+// MockDataCoord_GetGcReport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetGcReport'
+type MockDataCoord_GetGcReport_Call struct {
 	*mock.Call
 }
 
-// GetIndexBuildProgress is a helper method to define mock.On call
+// GetGcReport is a helper method to define mock.On call
 //   - _a0 context.Context
-//   - _a1 *indexpb.GetIndexBuildProgressRequest
-func (_e *MockDataCoord_Expecter) GetIndexBuildProgress(_a0 interface{}, _a1 interface{}) *MockDataCoord_GetIndexBuildProgress_Call {
-	return &MockDataCoord_GetIndexBuildProgress_Call{Call: _e.mock.On("GetIndexBuildProgress", _a0, _a1)}
+//   - _a1 *datapb.GetGcReportRequest
+func (_e *MockDataCoord_Expecter) GetGcReport(_a0 interface{}, _a1 interface{}) *MockDataCoord_GetGcReport_Call {
+	return &MockDataCoord_GetGcReport_Call{Call: _e.mock.On("GetGcReport", _a0, _a1)}
 }
 
-func (_c *MockDataCoord_GetIndexBuildProgress_Call) Run(run func(_a0 context.Context, _a1 *indexpb.GetIndexBuildProgressRequest)) *MockDataCoord_GetIndexBuildProgress_Call {
+func (_c *MockDataCoord_GetGcReport_Call) Run(run func(_a0 context.Context, _a1 *datapb.GetGcReportRequest)) *MockDataCoord_GetGcReport_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*indexpb.GetIndexBuildProgressRequest))
+		run(args[0].(context.Context), args[1].(*datapb.GetGcReportRequest))
 	})
 	return _c
 }
 
-func (_c *MockDataCoord_GetIndexBuildProgress_Call) Return(_a0 *indexpb.GetIndexBuildProgressResponse, _a1 error) *MockDataCoord_GetIndexBuildProgress_Call {
+func (_c *MockDataCoord_GetGcReport_Call) Return(_a0 *datapb.GetGcReportResponse, _a1 error) *MockDataCoord_GetGcReport_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockDataCoord_GetIndexBuildProgress_Call) RunAndReturn(run func(context.Context, *indexpb.GetIndexBuildProgressRequest) (*indexpb.GetIndexBuildProgressResponse, error)) *MockDataCoord_GetIndexBuildProgress_Call {
+func (_c *MockDataCoord_GetGcReport_Call) RunAndReturn(run func(context.Context, *datapb.GetGcReportRequest) (*datapb.GetGcReportResponse, error)) *MockDataCoord_GetGcReport_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetIndexInfos provides a mock function with given fields: _a0, _a1
-func (_m *MockDataCoord) GetIndexInfos(_a0 context.Context, _a1 *indexpb.GetIndexInfoRequest) (*indexpb.GetIndexInfoResponse, error) {
+// GetIndexBuildProgress provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) GetIndexBuildProgress(_a0 context.Context, _a1 *indexpb.GetIndexBuildProgressRequest) (*indexpb.GetIndexBuildProgressResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *indexpb.GetIndexInfoResponse
+	var r0 *indexpb.GetIndexBuildProgressResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *indexpb.GetIndexInfoRequest) (*indexpb.GetIndexInfoResponse, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *indexpb.GetIndexBuildProgressRequest) (*indexpb.GetIndexBuildProgressResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *indexpb.GetIndexBuildProgressRequest) *indexpb.GetIndexBuildProgressResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*indexpb.GetIndexBuildProgressResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *indexpb.GetIndexBuildProgressRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_GetIndexBuildProgress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIndexBuildProgress'
+type MockDataCoord_GetIndexBuildProgress_Call struct {
+	*mock.Call
+}
+
+// GetIndexBuildProgress is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *indexpb.GetIndexBuildProgressRequest
+func (_e *MockDataCoord_Expecter) GetIndexBuildProgress(_a0 interface{}, _a1 interface{}) *MockDataCoord_GetIndexBuildProgress_Call {
+	return &MockDataCoord_GetIndexBuildProgress_Call{Call: _e.mock.On("GetIndexBuildProgress", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_GetIndexBuildProgress_Call) Run(run func(_a0 context.Context, _a1 *indexpb.GetIndexBuildProgressRequest)) *MockDataCoord_GetIndexBuildProgress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*indexpb.GetIndexBuildProgressRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_GetIndexBuildProgress_Call) Return(_a0 *indexpb.GetIndexBuildProgressResponse, _a1 error) *MockDataCoord_GetIndexBuildProgress_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_GetIndexBuildProgress_Call) RunAndReturn(run func(context.Context, *indexpb.GetIndexBuildProgressRequest) (*indexpb.GetIndexBuildProgressResponse, error)) *MockDataCoord_GetIndexBuildProgress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetIndexInfos provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) GetIndexInfos(_a0 context.Context, _a1 *indexpb.GetIndexInfoRequest) (*indexpb.GetIndexInfoResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *indexpb.GetIndexInfoResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *indexpb.GetIndexInfoRequest) (*indexpb.GetIndexInfoResponse, error)); ok {
 		return rf(_a0, _a1)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, *indexpb.GetIndexInfoRequest) *indexpb.GetIndexInfoResponse); ok {
@@ -1301,6 +1686,61 @@ func (_c *MockDataCoord_GetInsertBinlogPaths_Call) RunAndReturn(run func(context
 	return _c
 }
 
+// GetMajorCompactionState provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) GetMajorCompactionState(_a0 context.Context, _a1 *datapb.GetMajorCompactionStateRequest) (*datapb.GetMajorCompactionStateResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.GetMajorCompactionStateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetMajorCompactionStateRequest) (*datapb.GetMajorCompactionStateResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetMajorCompactionStateRequest) *datapb.GetMajorCompactionStateResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.GetMajorCompactionStateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetMajorCompactionStateRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_GetMajorCompactionState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMajorCompactionState'
+type MockDataCoord_GetMajorCompactionState_Call struct {
+	*mock.Call
+}
+
+// GetMajorCompactionState is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.GetMajorCompactionStateRequest
+func (_e *MockDataCoord_Expecter) GetMajorCompactionState(_a0 interface{}, _a1 interface{}) *MockDataCoord_GetMajorCompactionState_Call {
+	return &MockDataCoord_GetMajorCompactionState_Call{Call: _e.mock.On("GetMajorCompactionState", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_GetMajorCompactionState_Call) Run(run func(_a0 context.Context, _a1 *datapb.GetMajorCompactionStateRequest)) *MockDataCoord_GetMajorCompactionState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.GetMajorCompactionStateRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_GetMajorCompactionState_Call) Return(_a0 *datapb.GetMajorCompactionStateResponse, _a1 error) *MockDataCoord_GetMajorCompactionState_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_GetMajorCompactionState_Call) RunAndReturn(run func(context.Context, *datapb.GetMajorCompactionStateRequest) (*datapb.GetMajorCompactionStateResponse, error)) *MockDataCoord_GetMajorCompactionState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetMetrics provides a mock function with given fields: _a0, _a1
 func (_m *MockDataCoord) GetMetrics(_a0 context.Context, _a1 *milvuspb.GetMetricsRequest) (*milvuspb.GetMetricsResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -1521,6 +1961,61 @@ func (_c *MockDataCoord_GetRecoveryInfoV2_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// GetSegmentEvents provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) GetSegmentEvents(_a0 context.Context, _a1 *datapb.GetSegmentEventsRequest) (*datapb.GetSegmentEventsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.GetSegmentEventsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetSegmentEventsRequest) (*datapb.GetSegmentEventsResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetSegmentEventsRequest) *datapb.GetSegmentEventsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.GetSegmentEventsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetSegmentEventsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_GetSegmentEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSegmentEvents'
+type MockDataCoord_GetSegmentEvents_Call struct {
+	*mock.Call
+}
+
+// GetSegmentEvents is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.GetSegmentEventsRequest
+func (_e *MockDataCoord_Expecter) GetSegmentEvents(_a0 interface{}, _a1 interface{}) *MockDataCoord_GetSegmentEvents_Call {
+	return &MockDataCoord_GetSegmentEvents_Call{Call: _e.mock.On("GetSegmentEvents", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_GetSegmentEvents_Call) Run(run func(_a0 context.Context, _a1 *datapb.GetSegmentEventsRequest)) *MockDataCoord_GetSegmentEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.GetSegmentEventsRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_GetSegmentEvents_Call) Return(_a0 *datapb.GetSegmentEventsResponse, _a1 error) *MockDataCoord_GetSegmentEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_GetSegmentEvents_Call) RunAndReturn(run func(context.Context, *datapb.GetSegmentEventsRequest) (*datapb.GetSegmentEventsResponse, error)) *MockDataCoord_GetSegmentEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetSegmentIndexState provides a mock function with given fields: _a0, _a1
 func (_m *MockDataCoord) GetSegmentIndexState(_a0 context.Context, _a1 *indexpb.GetSegmentIndexStateRequest) (*indexpb.GetSegmentIndexStateResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -1686,6 +2181,61 @@ func (_c *MockDataCoord_GetSegmentInfoChannel_Call) RunAndReturn(run func(contex
 	return _c
 }
 
+// GetSegmentSnapshot provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) GetSegmentSnapshot(_a0 context.Context, _a1 *datapb.GetSegmentSnapshotRequest) (*datapb.GetSegmentSnapshotResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.GetSegmentSnapshotResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetSegmentSnapshotRequest) (*datapb.GetSegmentSnapshotResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetSegmentSnapshotRequest) *datapb.GetSegmentSnapshotResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.GetSegmentSnapshotResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetSegmentSnapshotRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_GetSegmentSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSegmentSnapshot'
+type MockDataCoord_GetSegmentSnapshot_Call struct {
+	*mock.Call
+}
+
+// GetSegmentSnapshot is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.GetSegmentSnapshotRequest
+func (_e *MockDataCoord_Expecter) GetSegmentSnapshot(_a0 interface{}, _a1 interface{}) *MockDataCoord_GetSegmentSnapshot_Call {
+	return &MockDataCoord_GetSegmentSnapshot_Call{Call: _e.mock.On("GetSegmentSnapshot", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_GetSegmentSnapshot_Call) Run(run func(_a0 context.Context, _a1 *datapb.GetSegmentSnapshotRequest)) *MockDataCoord_GetSegmentSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.GetSegmentSnapshotRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_GetSegmentSnapshot_Call) Return(_a0 *datapb.GetSegmentSnapshotResponse, _a1 error) *MockDataCoord_GetSegmentSnapshot_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_GetSegmentSnapshot_Call) RunAndReturn(run func(context.Context, *datapb.GetSegmentSnapshotRequest) (*datapb.GetSegmentSnapshotResponse, error)) *MockDataCoord_GetSegmentSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetSegmentStates provides a mock function with given fields: _a0, _a1
 func (_m *MockDataCoord) GetSegmentStates(_a0 context.Context, _a1 *datapb.GetSegmentStatesRequest) (*datapb.GetSegmentStatesResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -1961,6 +2511,61 @@ func (_c *MockDataCoord_Import_Call) RunAndReturn(run func(context.Context, *dat
 	return _c
 }
 
+// ImportSegmentBinlogs provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) ImportSegmentBinlogs(_a0 context.Context, _a1 *datapb.ImportSegmentBinlogsRequest) (*datapb.ImportSegmentBinlogsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.ImportSegmentBinlogsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.ImportSegmentBinlogsRequest) (*datapb.ImportSegmentBinlogsResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.ImportSegmentBinlogsRequest) *datapb.ImportSegmentBinlogsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.ImportSegmentBinlogsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.ImportSegmentBinlogsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_ImportSegmentBinlogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ImportSegmentBinlogs'
+type MockDataCoord_ImportSegmentBinlogs_Call struct {
+	*mock.Call
+}
+
+// ImportSegmentBinlogs is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.ImportSegmentBinlogsRequest
+func (_e *MockDataCoord_Expecter) ImportSegmentBinlogs(_a0 interface{}, _a1 interface{}) *MockDataCoord_ImportSegmentBinlogs_Call {
+	return &MockDataCoord_ImportSegmentBinlogs_Call{Call: _e.mock.On("ImportSegmentBinlogs", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_ImportSegmentBinlogs_Call) Run(run func(_a0 context.Context, _a1 *datapb.ImportSegmentBinlogsRequest)) *MockDataCoord_ImportSegmentBinlogs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.ImportSegmentBinlogsRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_ImportSegmentBinlogs_Call) Return(_a0 *datapb.ImportSegmentBinlogsResponse, _a1 error) *MockDataCoord_ImportSegmentBinlogs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_ImportSegmentBinlogs_Call) RunAndReturn(run func(context.Context, *datapb.ImportSegmentBinlogsRequest) (*datapb.ImportSegmentBinlogsResponse, error)) *MockDataCoord_ImportSegmentBinlogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Init provides a mock function with given fields:
 func (_m *MockDataCoord) Init() error {
 	ret := _m.Called()
@@ -2002,6 +2607,171 @@ func (_c *MockDataCoord_Init_Call) RunAndReturn(run func() error) *MockDataCoord
 	return _c
 }
 
+// InspectMeta provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) InspectMeta(_a0 context.Context, _a1 *datapb.InspectMetaRequest) (*datapb.InspectMetaResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.InspectMetaResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.InspectMetaRequest) (*datapb.InspectMetaResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.InspectMetaRequest) *datapb.InspectMetaResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.InspectMetaResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.InspectMetaRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_InspectMeta_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InspectMeta'
+type MockDataCoord_InspectMeta_Call struct {
+	*mock.Call
+}
+
+// InspectMeta is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.InspectMetaRequest
+func (_e *MockDataCoord_Expecter) InspectMeta(_a0 interface{}, _a1 interface{}) *MockDataCoord_InspectMeta_Call {
+	return &MockDataCoord_InspectMeta_Call{Call: _e.mock.On("InspectMeta", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_InspectMeta_Call) Run(run func(_a0 context.Context, _a1 *datapb.InspectMetaRequest)) *MockDataCoord_InspectMeta_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.InspectMetaRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_InspectMeta_Call) Return(_a0 *datapb.InspectMetaResponse, _a1 error) *MockDataCoord_InspectMeta_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_InspectMeta_Call) RunAndReturn(run func(context.Context, *datapb.InspectMetaRequest) (*datapb.InspectMetaResponse, error)) *MockDataCoord_InspectMeta_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListCompactionTasks provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) ListCompactionTasks(_a0 context.Context, _a1 *datapb.ListCompactionTasksRequest) (*datapb.ListCompactionTasksResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.ListCompactionTasksResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.ListCompactionTasksRequest) (*datapb.ListCompactionTasksResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.ListCompactionTasksRequest) *datapb.ListCompactionTasksResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.ListCompactionTasksResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.ListCompactionTasksRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_ListCompactionTasks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListCompactionTasks'
+type MockDataCoord_ListCompactionTasks_Call struct {
+	*mock.Call
+}
+
+// ListCompactionTasks is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.ListCompactionTasksRequest
+func (_e *MockDataCoord_Expecter) ListCompactionTasks(_a0 interface{}, _a1 interface{}) *MockDataCoord_ListCompactionTasks_Call {
+	return &MockDataCoord_ListCompactionTasks_Call{Call: _e.mock.On("ListCompactionTasks", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_ListCompactionTasks_Call) Run(run func(_a0 context.Context, _a1 *datapb.ListCompactionTasksRequest)) *MockDataCoord_ListCompactionTasks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.ListCompactionTasksRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_ListCompactionTasks_Call) Return(_a0 *datapb.ListCompactionTasksResponse, _a1 error) *MockDataCoord_ListCompactionTasks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_ListCompactionTasks_Call) RunAndReturn(run func(context.Context, *datapb.ListCompactionTasksRequest) (*datapb.ListCompactionTasksResponse, error)) *MockDataCoord_ListCompactionTasks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MajorCompaction provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) MajorCompaction(_a0 context.Context, _a1 *datapb.MajorCompactionRequest) (*datapb.MajorCompactionResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.MajorCompactionResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.MajorCompactionRequest) (*datapb.MajorCompactionResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.MajorCompactionRequest) *datapb.MajorCompactionResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.MajorCompactionResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.MajorCompactionRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_MajorCompaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MajorCompaction'
+type MockDataCoord_MajorCompaction_Call struct {
+	*mock.Call
+}
+
+// MajorCompaction is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.MajorCompactionRequest
+func (_e *MockDataCoord_Expecter) MajorCompaction(_a0 interface{}, _a1 interface{}) *MockDataCoord_MajorCompaction_Call {
+	return &MockDataCoord_MajorCompaction_Call{Call: _e.mock.On("MajorCompaction", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_MajorCompaction_Call) Run(run func(_a0 context.Context, _a1 *datapb.MajorCompactionRequest)) *MockDataCoord_MajorCompaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.MajorCompactionRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_MajorCompaction_Call) Return(_a0 *datapb.MajorCompactionResponse, _a1 error) *MockDataCoord_MajorCompaction_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_MajorCompaction_Call) RunAndReturn(run func(context.Context, *datapb.MajorCompactionRequest) (*datapb.MajorCompactionResponse, error)) *MockDataCoord_MajorCompaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ManualCompaction provides a mock function with given fields: _a0, _a1
 func (_m *MockDataCoord) ManualCompaction(_a0 context.Context, _a1 *milvuspb.ManualCompactionRequest) (*milvuspb.ManualCompactionResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -2112,6 +2882,116 @@ func (_c *MockDataCoord_MarkSegmentsDropped_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// PublishSegments provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) PublishSegments(_a0 context.Context, _a1 *datapb.PublishSegmentsRequest) (*datapb.PublishSegmentsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.PublishSegmentsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.PublishSegmentsRequest) (*datapb.PublishSegmentsResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.PublishSegmentsRequest) *datapb.PublishSegmentsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.PublishSegmentsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.PublishSegmentsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_PublishSegments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublishSegments'
+type MockDataCoord_PublishSegments_Call struct {
+	*mock.Call
+}
+
+// PublishSegments is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.PublishSegmentsRequest
+func (_e *MockDataCoord_Expecter) PublishSegments(_a0 interface{}, _a1 interface{}) *MockDataCoord_PublishSegments_Call {
+	return &MockDataCoord_PublishSegments_Call{Call: _e.mock.On("PublishSegments", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_PublishSegments_Call) Run(run func(_a0 context.Context, _a1 *datapb.PublishSegmentsRequest)) *MockDataCoord_PublishSegments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.PublishSegmentsRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_PublishSegments_Call) Return(_a0 *datapb.PublishSegmentsResponse, _a1 error) *MockDataCoord_PublishSegments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_PublishSegments_Call) RunAndReturn(run func(context.Context, *datapb.PublishSegmentsRequest) (*datapb.PublishSegmentsResponse, error)) *MockDataCoord_PublishSegments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RefreshSegmentStats provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) RefreshSegmentStats(_a0 context.Context, _a1 *datapb.RefreshSegmentStatsRequest) (*datapb.RefreshSegmentStatsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.RefreshSegmentStatsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.RefreshSegmentStatsRequest) (*datapb.RefreshSegmentStatsResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.RefreshSegmentStatsRequest) *datapb.RefreshSegmentStatsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.RefreshSegmentStatsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.RefreshSegmentStatsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_RefreshSegmentStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RefreshSegmentStats'
+type MockDataCoord_RefreshSegmentStats_Call struct {
+	*mock.Call
+}
+
+// RefreshSegmentStats is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.RefreshSegmentStatsRequest
+func (_e *MockDataCoord_Expecter) RefreshSegmentStats(_a0 interface{}, _a1 interface{}) *MockDataCoord_RefreshSegmentStats_Call {
+	return &MockDataCoord_RefreshSegmentStats_Call{Call: _e.mock.On("RefreshSegmentStats", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_RefreshSegmentStats_Call) Run(run func(_a0 context.Context, _a1 *datapb.RefreshSegmentStatsRequest)) *MockDataCoord_RefreshSegmentStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.RefreshSegmentStatsRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_RefreshSegmentStats_Call) Return(_a0 *datapb.RefreshSegmentStatsResponse, _a1 error) *MockDataCoord_RefreshSegmentStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_RefreshSegmentStats_Call) RunAndReturn(run func(context.Context, *datapb.RefreshSegmentStatsRequest) (*datapb.RefreshSegmentStatsResponse, error)) *MockDataCoord_RefreshSegmentStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Register provides a mock function with given fields:
 func (_m *MockDataCoord) Register() error {
 	ret := _m.Called()
@@ -2208,6 +3088,61 @@ func (_c *MockDataCoord_ReportDataNodeTtMsgs_Call) RunAndReturn(run func(context
 	return _c
 }
 
+// Restore provides a mock function with given fields: _a0, _a1
+func (_m *MockDataCoord) Restore(_a0 context.Context, _a1 *datapb.RestoreRequest) (*datapb.RestoreResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.RestoreResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.RestoreRequest) (*datapb.RestoreResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.RestoreRequest) *datapb.RestoreResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.RestoreResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.RestoreRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoord_Restore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restore'
+type MockDataCoord_Restore_Call struct {
+	*mock.Call
+}
+
+// Restore is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.RestoreRequest
+func (_e *MockDataCoord_Expecter) Restore(_a0 interface{}, _a1 interface{}) *MockDataCoord_Restore_Call {
+	return &MockDataCoord_Restore_Call{Call: _e.mock.On("Restore", _a0, _a1)}
+}
+
+func (_c *MockDataCoord_Restore_Call) Run(run func(_a0 context.Context, _a1 *datapb.RestoreRequest)) *MockDataCoord_Restore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.RestoreRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataCoord_Restore_Call) Return(_a0 *datapb.RestoreResponse, _a1 error) *MockDataCoord_Restore_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoord_Restore_Call) RunAndReturn(run func(context.Context, *datapb.RestoreRequest) (*datapb.RestoreResponse, error)) *MockDataCoord_Restore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveBinlogPaths provides a mock function with given fields: _a0, _a1
 func (_m *MockDataCoord) SaveBinlogPaths(_a0 context.Context, _a1 *datapb.SaveBinlogPathsRequest) (*commonpb.Status, error) {
 	ret := _m.Called(_a0, _a1)
@@ -103,6 +103,76 @@ func (_c *MockDataCoordClient_AlterIndex_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// AlterMajorCompaction provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) AlterMajorCompaction(ctx context.Context, in *datapb.AlterMajorCompactionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *commonpb.Status
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.AlterMajorCompactionRequest, ...grpc.CallOption) (*commonpb.Status, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.AlterMajorCompactionRequest, ...grpc.CallOption) *commonpb.Status); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*commonpb.Status)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.AlterMajorCompactionRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_AlterMajorCompaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AlterMajorCompaction'
+type MockDataCoordClient_AlterMajorCompaction_Call struct {
+	*mock.Call
+}
+
+// AlterMajorCompaction is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.AlterMajorCompactionRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) AlterMajorCompaction(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_AlterMajorCompaction_Call {
+	return &MockDataCoordClient_AlterMajorCompaction_Call{Call: _e.mock.On("AlterMajorCompaction",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_AlterMajorCompaction_Call) Run(run func(ctx context.Context, in *datapb.AlterMajorCompactionRequest, opts ...grpc.CallOption)) *MockDataCoordClient_AlterMajorCompaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.AlterMajorCompactionRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_AlterMajorCompaction_Call) Return(_a0 *commonpb.Status, _a1 error) *MockDataCoordClient_AlterMajorCompaction_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_AlterMajorCompaction_Call) RunAndReturn(run func(context.Context, *datapb.AlterMajorCompactionRequest, ...grpc.CallOption) (*commonpb.Status, error)) *MockDataCoordClient_AlterMajorCompaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // AssignSegmentID provides a mock function with given fields: ctx, in, opts
 func (_m *MockDataCoordClient) AssignSegmentID(ctx context.Context, in *datapb.AssignSegmentIDRequest, opts ...grpc.CallOption) (*datapb.AssignSegmentIDResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -173,6 +243,76 @@ func (_c *MockDataCoordClient_AssignSegmentID_Call) RunAndReturn(run func(contex
 	return _c
 }
 
+// Backup provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) Backup(ctx context.Context, in *datapb.BackupRequest, opts ...grpc.CallOption) (*datapb.BackupResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.BackupResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.BackupRequest, ...grpc.CallOption) (*datapb.BackupResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.BackupRequest, ...grpc.CallOption) *datapb.BackupResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.BackupResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.BackupRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_Backup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Backup'
+type MockDataCoordClient_Backup_Call struct {
+	*mock.Call
+}
+
+// Backup is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.BackupRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) Backup(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_Backup_Call {
+	return &MockDataCoordClient_Backup_Call{Call: _e.mock.On("Backup",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_Backup_Call) Run(run func(ctx context.Context, in *datapb.BackupRequest, opts ...grpc.CallOption)) *MockDataCoordClient_Backup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.BackupRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_Backup_Call) Return(_a0 *datapb.BackupResponse, _a1 error) *MockDataCoordClient_Backup_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_Backup_Call) RunAndReturn(run func(context.Context, *datapb.BackupRequest, ...grpc.CallOption) (*datapb.BackupResponse, error)) *MockDataCoordClient_Backup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // BroadcastAlteredCollection provides a mock function with given fields: ctx, in, opts
 func (_m *MockDataCoordClient) BroadcastAlteredCollection(ctx context.Context, in *datapb.AlterCollectionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
 	_va := make([]interface{}, len(opts))
@@ -424,6 +564,76 @@ func (_c *MockDataCoordClient_CreateIndex_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// DecommissionNode provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) DecommissionNode(ctx context.Context, in *datapb.DecommissionNodeRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *commonpb.Status
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.DecommissionNodeRequest, ...grpc.CallOption) (*commonpb.Status, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.DecommissionNodeRequest, ...grpc.CallOption) *commonpb.Status); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*commonpb.Status)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.DecommissionNodeRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_DecommissionNode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DecommissionNode'
+type MockDataCoordClient_DecommissionNode_Call struct {
+	*mock.Call
+}
+
+// DecommissionNode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.DecommissionNodeRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) DecommissionNode(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_DecommissionNode_Call {
+	return &MockDataCoordClient_DecommissionNode_Call{Call: _e.mock.On("DecommissionNode",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_DecommissionNode_Call) Run(run func(ctx context.Context, in *datapb.DecommissionNodeRequest, opts ...grpc.CallOption)) *MockDataCoordClient_DecommissionNode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.DecommissionNodeRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_DecommissionNode_Call) Return(_a0 *commonpb.Status, _a1 error) *MockDataCoordClient_DecommissionNode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_DecommissionNode_Call) RunAndReturn(run func(context.Context, *datapb.DecommissionNodeRequest, ...grpc.CallOption) (*commonpb.Status, error)) *MockDataCoordClient_DecommissionNode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DescribeIndex provides a mock function with given fields: ctx, in, opts
 func (_m *MockDataCoordClient) DescribeIndex(ctx context.Context, in *indexpb.DescribeIndexRequest, opts ...grpc.CallOption) (*indexpb.DescribeIndexResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -634,6 +844,76 @@ func (_c *MockDataCoordClient_DropVirtualChannel_Call) RunAndReturn(run func(con
 	return _c
 }
 
+// Export provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) Export(ctx context.Context, in *datapb.ExportRequest, opts ...grpc.CallOption) (*datapb.ExportResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.ExportResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.ExportRequest, ...grpc.CallOption) (*datapb.ExportResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.ExportRequest, ...grpc.CallOption) *datapb.ExportResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.ExportResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.ExportRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_Export_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Export'
+type MockDataCoordClient_Export_Call struct {
+	*mock.Call
+}
+
+// Export is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.ExportRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) Export(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_Export_Call {
+	return &MockDataCoordClient_Export_Call{Call: _e.mock.On("Export",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_Export_Call) Run(run func(ctx context.Context, in *datapb.ExportRequest, opts ...grpc.CallOption)) *MockDataCoordClient_Export_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.ExportRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_Export_Call) Return(_a0 *datapb.ExportResponse, _a1 error) *MockDataCoordClient_Export_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_Export_Call) RunAndReturn(run func(context.Context, *datapb.ExportRequest, ...grpc.CallOption) (*datapb.ExportResponse, error)) *MockDataCoordClient_Export_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Flush provides a mock function with given fields: ctx, in, opts
 func (_m *MockDataCoordClient) Flush(ctx context.Context, in *datapb.FlushRequest, opts ...grpc.CallOption) (*datapb.FlushResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -1124,8 +1404,8 @@ func (_c *MockDataCoordClient_GetComponentStates_Call) RunAndReturn(run func(con
 	return _c
 }
 
-// GetFlushAllState provides a mock function with given fields: ctx, in, opts
-func (_m *MockDataCoordClient) GetFlushAllState(ctx context.Context, in *milvuspb.GetFlushAllStateRequest, opts ...grpc.CallOption) (*milvuspb.GetFlushAllStateResponse, error) {
+// GetDecommissionState provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) GetDecommissionState(ctx context.Context, in *datapb.GetDecommissionStateRequest, opts ...grpc.CallOption) (*datapb.GetDecommissionStateResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -1135,20 +1415,20 @@ func (_m *MockDataCoordClient) GetFlushAllState(ctx context.Context, in *milvusp
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *milvuspb.GetFlushAllStateResponse
+	var r0 *datapb.GetDecommissionStateResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *milvuspb.GetFlushAllStateRequest, ...grpc.CallOption) (*milvuspb.GetFlushAllStateResponse, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetDecommissionStateRequest, ...grpc.CallOption) (*datapb.GetDecommissionStateResponse, error)); ok {
 		return rf(ctx, in, opts...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *milvuspb.GetFlushAllStateRequest, ...grpc.CallOption) *milvuspb.GetFlushAllStateResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetDecommissionStateRequest, ...grpc.CallOption) *datapb.GetDecommissionStateResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*milvuspb.GetFlushAllStateResponse)
+			r0 = ret.Get(0).(*datapb.GetDecommissionStateResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *milvuspb.GetFlushAllStateRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetDecommissionStateRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -1157,21 +1437,21 @@ func (_m *MockDataCoordClient) GetFlushAllState(ctx context.Context, in *milvusp
 	return r0, r1
 }
 
-// MockDataCoordClient_GetFlushAllState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFlushAllState'
-type MockDataCoordClient_GetFlushAllState_Call struct {
+// MockDataCoordClient_GetDecommissionState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDecommissionState'
+type MockDataCoordClient_GetDecommissionState_Call struct {
 	*mock.Call
 }
 
-// GetFlushAllState is a helper method to define mock.On call
+// GetDecommissionState is a helper method to define mock.On call
 //   - ctx context.Context
-//   - in *milvuspb.GetFlushAllStateRequest
+//   - in *datapb.GetDecommissionStateRequest
 //   - opts ...grpc.CallOption
-func (_e *MockDataCoordClient_Expecter) GetFlushAllState(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_GetFlushAllState_Call {
-	return &MockDataCoordClient_GetFlushAllState_Call{Call: _e.mock.On("GetFlushAllState",
+func (_e *MockDataCoordClient_Expecter) GetDecommissionState(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_GetDecommissionState_Call {
+	return &MockDataCoordClient_GetDecommissionState_Call{Call: _e.mock.On("GetDecommissionState",
 		append([]interface{}{ctx, in}, opts...)...)}
 }
 
-func (_c *MockDataCoordClient_GetFlushAllState_Call) Run(run func(ctx context.Context, in *milvuspb.GetFlushAllStateRequest, opts ...grpc.CallOption)) *MockDataCoordClient_GetFlushAllState_Call {
+func (_c *MockDataCoordClient_GetDecommissionState_Call) Run(run func(ctx context.Context, in *datapb.GetDecommissionStateRequest, opts ...grpc.CallOption)) *MockDataCoordClient_GetDecommissionState_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		variadicArgs := make([]grpc.CallOption, len(args)-2)
 		for i, a := range args[2:] {
@@ -1179,23 +1459,23 @@ func (_c *MockDataCoordClient_GetFlushAllState_Call) Run(run func(ctx context.Co
 				variadicArgs[i] = a.(grpc.CallOption)
 			}
 		}
-		run(args[0].(context.Context), args[1].(*milvuspb.GetFlushAllStateRequest), variadicArgs...)
+		run(args[0].(context.Context), args[1].(*datapb.GetDecommissionStateRequest), variadicArgs...)
 	})
 	return _c
 }
 
-func (_c *MockDataCoordClient_GetFlushAllState_Call) Return(_a0 *milvuspb.GetFlushAllStateResponse, _a1 error) *MockDataCoordClient_GetFlushAllState_Call {
+func (_c *MockDataCoordClient_GetDecommissionState_Call) Return(_a0 *datapb.GetDecommissionStateResponse, _a1 error) *MockDataCoordClient_GetDecommissionState_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockDataCoordClient_GetFlushAllState_Call) RunAndReturn(run func(context.Context, *milvuspb.GetFlushAllStateRequest, ...grpc.CallOption) (*milvuspb.GetFlushAllStateResponse, error)) *MockDataCoordClient_GetFlushAllState_Call {
+func (_c *MockDataCoordClient_GetDecommissionState_Call) RunAndReturn(run func(context.Context, *datapb.GetDecommissionStateRequest, ...grpc.CallOption) (*datapb.GetDecommissionStateResponse, error)) *MockDataCoordClient_GetDecommissionState_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetFlushState provides a mock function with given fields: ctx, in, opts
-func (_m *MockDataCoordClient) GetFlushState(ctx context.Context, in *datapb.GetFlushStateRequest, opts ...grpc.CallOption) (*milvuspb.GetFlushStateResponse, error) {
+// GetExportState provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) GetExportState(ctx context.Context, in *datapb.GetExportStateRequest, opts ...grpc.CallOption) (*datapb.GetExportStateResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -1205,20 +1485,20 @@ func (_m *MockDataCoordClient) GetFlushState(ctx context.Context, in *datapb.Get
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *milvuspb.GetFlushStateResponse
+	var r0 *datapb.GetExportStateResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetFlushStateRequest, ...grpc.CallOption) (*milvuspb.GetFlushStateResponse, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetExportStateRequest, ...grpc.CallOption) (*datapb.GetExportStateResponse, error)); ok {
 		return rf(ctx, in, opts...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetFlushStateRequest, ...grpc.CallOption) *milvuspb.GetFlushStateResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetExportStateRequest, ...grpc.CallOption) *datapb.GetExportStateResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*milvuspb.GetFlushStateResponse)
+			r0 = ret.Get(0).(*datapb.GetExportStateResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetFlushStateRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetExportStateRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -1227,21 +1507,21 @@ func (_m *MockDataCoordClient) GetFlushState(ctx context.Context, in *datapb.Get
 	return r0, r1
 }
 
-// MockDataCoordClient_GetFlushState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFlushState'
-type MockDataCoordClient_GetFlushState_Call struct {
+// MockDataCoordClient_GetExportState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetExportState'
+type MockDataCoordClient_GetExportState_Call struct {
 	*mock.Call
 }
 
-// GetFlushState is a helper method to define mock.On call
+// GetExportState is a helper method to define mock.On call
 //   - ctx context.Context
-//   - in *datapb.GetFlushStateRequest
+//   - in *datapb.GetExportStateRequest
 //   - opts ...grpc.CallOption
-func (_e *MockDataCoordClient_Expecter) GetFlushState(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_GetFlushState_Call {
-	return &MockDataCoordClient_GetFlushState_Call{Call: _e.mock.On("GetFlushState",
+func (_e *MockDataCoordClient_Expecter) GetExportState(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_GetExportState_Call {
+	return &MockDataCoordClient_GetExportState_Call{Call: _e.mock.On("GetExportState",
 		append([]interface{}{ctx, in}, opts...)...)}
 }
 
-func (_c *MockDataCoordClient_GetFlushState_Call) Run(run func(ctx context.Context, in *datapb.GetFlushStateRequest, opts ...grpc.CallOption)) *MockDataCoordClient_GetFlushState_Call {
+func (_c *MockDataCoordClient_GetExportState_Call) Run(run func(ctx context.Context, in *datapb.GetExportStateRequest, opts ...grpc.CallOption)) *MockDataCoordClient_GetExportState_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		variadicArgs := make([]grpc.CallOption, len(args)-2)
 		for i, a := range args[2:] {
@@ -1249,23 +1529,23 @@ func (_c *MockDataCoordClient_GetFlushState_Call) Run(run func(ctx context.Conte
 				variadicArgs[i] = a.(grpc.CallOption)
 			}
 		}
-		run(args[0].(context.Context), args[1].(*datapb.GetFlushStateRequest), variadicArgs...)
+		run(args[0].(context.Context), args[1].(*datapb.GetExportStateRequest), variadicArgs...)
 	})
 	return _c
 }
 
-func (_c *MockDataCoordClient_GetFlushState_Call) Return(_a0 *milvuspb.GetFlushStateResponse, _a1 error) *MockDataCoordClient_GetFlushState_Call {
+func (_c *MockDataCoordClient_GetExportState_Call) Return(_a0 *datapb.GetExportStateResponse, _a1 error) *MockDataCoordClient_GetExportState_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockDataCoordClient_GetFlushState_Call) RunAndReturn(run func(context.Context, *datapb.GetFlushStateRequest, ...grpc.CallOption) (*milvuspb.GetFlushStateResponse, error)) *MockDataCoordClient_GetFlushState_Call {
+func (_c *MockDataCoordClient_GetExportState_Call) RunAndReturn(run func(context.Context, *datapb.GetExportStateRequest, ...grpc.CallOption) (*datapb.GetExportStateResponse, error)) *MockDataCoordClient_GetExportState_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetFlushedSegments provides a mock function with given fields: ctx, in, opts
-func (_m *MockDataCoordClient) GetFlushedSegments(ctx context.Context, in *datapb.GetFlushedSegmentsRequest, opts ...grpc.CallOption) (*datapb.GetFlushedSegmentsResponse, error) {
+// GetFlushAllState provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) GetFlushAllState(ctx context.Context, in *milvuspb.GetFlushAllStateRequest, opts ...grpc.CallOption) (*milvuspb.GetFlushAllStateResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -1275,7 +1555,147 @@ func (_m *MockDataCoordClient) GetFlushedSegments(ctx context.Context, in *datap
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *datapb.GetFlushedSegmentsResponse
+	var r0 *milvuspb.GetFlushAllStateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *milvuspb.GetFlushAllStateRequest, ...grpc.CallOption) (*milvuspb.GetFlushAllStateResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *milvuspb.GetFlushAllStateRequest, ...grpc.CallOption) *milvuspb.GetFlushAllStateResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*milvuspb.GetFlushAllStateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *milvuspb.GetFlushAllStateRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_GetFlushAllState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFlushAllState'
+type MockDataCoordClient_GetFlushAllState_Call struct {
+	*mock.Call
+}
+
+// GetFlushAllState is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *milvuspb.GetFlushAllStateRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) GetFlushAllState(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_GetFlushAllState_Call {
+	return &MockDataCoordClient_GetFlushAllState_Call{Call: _e.mock.On("GetFlushAllState",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_GetFlushAllState_Call) Run(run func(ctx context.Context, in *milvuspb.GetFlushAllStateRequest, opts ...grpc.CallOption)) *MockDataCoordClient_GetFlushAllState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*milvuspb.GetFlushAllStateRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_GetFlushAllState_Call) Return(_a0 *milvuspb.GetFlushAllStateResponse, _a1 error) *MockDataCoordClient_GetFlushAllState_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_GetFlushAllState_Call) RunAndReturn(run func(context.Context, *milvuspb.GetFlushAllStateRequest, ...grpc.CallOption) (*milvuspb.GetFlushAllStateResponse, error)) *MockDataCoordClient_GetFlushAllState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFlushState provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) GetFlushState(ctx context.Context, in *datapb.GetFlushStateRequest, opts ...grpc.CallOption) (*milvuspb.GetFlushStateResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *milvuspb.GetFlushStateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetFlushStateRequest, ...grpc.CallOption) (*milvuspb.GetFlushStateResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetFlushStateRequest, ...grpc.CallOption) *milvuspb.GetFlushStateResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*milvuspb.GetFlushStateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetFlushStateRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_GetFlushState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFlushState'
+type MockDataCoordClient_GetFlushState_Call struct {
+	*mock.Call
+}
+
+// GetFlushState is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.GetFlushStateRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) GetFlushState(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_GetFlushState_Call {
+	return &MockDataCoordClient_GetFlushState_Call{Call: _e.mock.On("GetFlushState",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_GetFlushState_Call) Run(run func(ctx context.Context, in *datapb.GetFlushStateRequest, opts ...grpc.CallOption)) *MockDataCoordClient_GetFlushState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.GetFlushStateRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_GetFlushState_Call) Return(_a0 *milvuspb.GetFlushStateResponse, _a1 error) *MockDataCoordClient_GetFlushState_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_GetFlushState_Call) RunAndReturn(run func(context.Context, *datapb.GetFlushStateRequest, ...grpc.CallOption) (*milvuspb.GetFlushStateResponse, error)) *MockDataCoordClient_GetFlushState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFlushedSegments provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) GetFlushedSegments(ctx context.Context, in *datapb.GetFlushedSegmentsRequest, opts ...grpc.CallOption) (*datapb.GetFlushedSegmentsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.GetFlushedSegmentsResponse
 	var r1 error
 	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetFlushedSegmentsRequest, ...grpc.CallOption) (*datapb.GetFlushedSegmentsResponse, error)); ok {
 		return rf(ctx, in, opts...)
@@ -1334,6 +1754,76 @@ func (_c *MockDataCoordClient_GetFlushedSegments_Call) RunAndReturn(run func(con
 	return _c
 }
 
+// GetGcReport provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) GetGcReport(ctx context.Context, in *datapb.GetGcReportRequest, opts ...grpc.CallOption) (*datapb.GetGcReportResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.GetGcReportResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetGcReportRequest, ...grpc.CallOption) (*datapb.GetGcReportResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetGcReportRequest, ...grpc.CallOption) *datapb.GetGcReportResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.GetGcReportResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetGcReportRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_GetGcReport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetGcReport'
+type MockDataCoordClient_GetGcReport_Call struct {
+	*mock.Call
+}
+
+// GetGcReport is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.GetGcReportRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) GetGcReport(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_GetGcReport_Call {
+	return &MockDataCoordClient_GetGcReport_Call{Call: _e.mock.On("GetGcReport",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_GetGcReport_Call) Run(run func(ctx context.Context, in *datapb.GetGcReportRequest, opts ...grpc.CallOption)) *MockDataCoordClient_GetGcReport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.GetGcReportRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_GetGcReport_Call) Return(_a0 *datapb.GetGcReportResponse, _a1 error) *MockDataCoordClient_GetGcReport_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_GetGcReport_Call) RunAndReturn(run func(context.Context, *datapb.GetGcReportRequest, ...grpc.CallOption) (*datapb.GetGcReportResponse, error)) *MockDataCoordClient_GetGcReport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetIndexBuildProgress provides a mock function with given fields: ctx, in, opts
 func (_m *MockDataCoordClient) GetIndexBuildProgress(ctx context.Context, in *indexpb.GetIndexBuildProgressRequest, opts ...grpc.CallOption) (*indexpb.GetIndexBuildProgressResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -1684,6 +2174,76 @@ func (_c *MockDataCoordClient_GetInsertBinlogPaths_Call) RunAndReturn(run func(c
 	return _c
 }
 
+// GetMajorCompactionState provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) GetMajorCompactionState(ctx context.Context, in *datapb.GetMajorCompactionStateRequest, opts ...grpc.CallOption) (*datapb.GetMajorCompactionStateResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.GetMajorCompactionStateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetMajorCompactionStateRequest, ...grpc.CallOption) (*datapb.GetMajorCompactionStateResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetMajorCompactionStateRequest, ...grpc.CallOption) *datapb.GetMajorCompactionStateResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.GetMajorCompactionStateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetMajorCompactionStateRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_GetMajorCompactionState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMajorCompactionState'
+type MockDataCoordClient_GetMajorCompactionState_Call struct {
+	*mock.Call
+}
+
+// GetMajorCompactionState is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.GetMajorCompactionStateRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) GetMajorCompactionState(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_GetMajorCompactionState_Call {
+	return &MockDataCoordClient_GetMajorCompactionState_Call{Call: _e.mock.On("GetMajorCompactionState",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_GetMajorCompactionState_Call) Run(run func(ctx context.Context, in *datapb.GetMajorCompactionStateRequest, opts ...grpc.CallOption)) *MockDataCoordClient_GetMajorCompactionState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.GetMajorCompactionStateRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_GetMajorCompactionState_Call) Return(_a0 *datapb.GetMajorCompactionStateResponse, _a1 error) *MockDataCoordClient_GetMajorCompactionState_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_GetMajorCompactionState_Call) RunAndReturn(run func(context.Context, *datapb.GetMajorCompactionStateRequest, ...grpc.CallOption) (*datapb.GetMajorCompactionStateResponse, error)) *MockDataCoordClient_GetMajorCompactionState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetMetrics provides a mock function with given fields: ctx, in, opts
 func (_m *MockDataCoordClient) GetMetrics(ctx context.Context, in *milvuspb.GetMetricsRequest, opts ...grpc.CallOption) (*milvuspb.GetMetricsResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -1964,8 +2524,8 @@ func (_c *MockDataCoordClient_GetRecoveryInfoV2_Call) RunAndReturn(run func(cont
 	return _c
 }
 
-// GetSegmentIndexState provides a mock function with given fields: ctx, in, opts
-func (_m *MockDataCoordClient) GetSegmentIndexState(ctx context.Context, in *indexpb.GetSegmentIndexStateRequest, opts ...grpc.CallOption) (*indexpb.GetSegmentIndexStateResponse, error) {
+// GetSegmentEvents provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) GetSegmentEvents(ctx context.Context, in *datapb.GetSegmentEventsRequest, opts ...grpc.CallOption) (*datapb.GetSegmentEventsResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -1975,20 +2535,20 @@ func (_m *MockDataCoordClient) GetSegmentIndexState(ctx context.Context, in *ind
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *indexpb.GetSegmentIndexStateResponse
+	var r0 *datapb.GetSegmentEventsResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *indexpb.GetSegmentIndexStateRequest, ...grpc.CallOption) (*indexpb.GetSegmentIndexStateResponse, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetSegmentEventsRequest, ...grpc.CallOption) (*datapb.GetSegmentEventsResponse, error)); ok {
 		return rf(ctx, in, opts...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *indexpb.GetSegmentIndexStateRequest, ...grpc.CallOption) *indexpb.GetSegmentIndexStateResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetSegmentEventsRequest, ...grpc.CallOption) *datapb.GetSegmentEventsResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*indexpb.GetSegmentIndexStateResponse)
+			r0 = ret.Get(0).(*datapb.GetSegmentEventsResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *indexpb.GetSegmentIndexStateRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetSegmentEventsRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -1997,12 +2557,82 @@ func (_m *MockDataCoordClient) GetSegmentIndexState(ctx context.Context, in *ind
 	return r0, r1
 }
 
-// MockDataCoordClient_GetSegmentIndexState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSegmentIndexState'
-type MockDataCoordClient_GetSegmentIndexState_Call struct {
+// MockDataCoordClient_GetSegmentEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSegmentEvents'
+type MockDataCoordClient_GetSegmentEvents_Call struct {
 	*mock.Call
 }
 
-// GetSegmentIndexState is a helper method to define mock.On call
+// GetSegmentEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.GetSegmentEventsRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) GetSegmentEvents(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_GetSegmentEvents_Call {
+	return &MockDataCoordClient_GetSegmentEvents_Call{Call: _e.mock.On("GetSegmentEvents",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_GetSegmentEvents_Call) Run(run func(ctx context.Context, in *datapb.GetSegmentEventsRequest, opts ...grpc.CallOption)) *MockDataCoordClient_GetSegmentEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.GetSegmentEventsRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_GetSegmentEvents_Call) Return(_a0 *datapb.GetSegmentEventsResponse, _a1 error) *MockDataCoordClient_GetSegmentEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_GetSegmentEvents_Call) RunAndReturn(run func(context.Context, *datapb.GetSegmentEventsRequest, ...grpc.CallOption) (*datapb.GetSegmentEventsResponse, error)) *MockDataCoordClient_GetSegmentEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSegmentIndexState provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) GetSegmentIndexState(ctx context.Context, in *indexpb.GetSegmentIndexStateRequest, opts ...grpc.CallOption) (*indexpb.GetSegmentIndexStateResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *indexpb.GetSegmentIndexStateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *indexpb.GetSegmentIndexStateRequest, ...grpc.CallOption) (*indexpb.GetSegmentIndexStateResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *indexpb.GetSegmentIndexStateRequest, ...grpc.CallOption) *indexpb.GetSegmentIndexStateResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*indexpb.GetSegmentIndexStateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *indexpb.GetSegmentIndexStateRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_GetSegmentIndexState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSegmentIndexState'
+type MockDataCoordClient_GetSegmentIndexState_Call struct {
+	*mock.Call
+}
+
+// GetSegmentIndexState is a helper method to define mock.On call
 //   - ctx context.Context
 //   - in *indexpb.GetSegmentIndexStateRequest
 //   - opts ...grpc.CallOption
@@ -2174,6 +2804,76 @@ func (_c *MockDataCoordClient_GetSegmentInfoChannel_Call) RunAndReturn(run func(
 	return _c
 }
 
+// GetSegmentSnapshot provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) GetSegmentSnapshot(ctx context.Context, in *datapb.GetSegmentSnapshotRequest, opts ...grpc.CallOption) (*datapb.GetSegmentSnapshotResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.GetSegmentSnapshotResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetSegmentSnapshotRequest, ...grpc.CallOption) (*datapb.GetSegmentSnapshotResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetSegmentSnapshotRequest, ...grpc.CallOption) *datapb.GetSegmentSnapshotResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.GetSegmentSnapshotResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetSegmentSnapshotRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_GetSegmentSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSegmentSnapshot'
+type MockDataCoordClient_GetSegmentSnapshot_Call struct {
+	*mock.Call
+}
+
+// GetSegmentSnapshot is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.GetSegmentSnapshotRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) GetSegmentSnapshot(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_GetSegmentSnapshot_Call {
+	return &MockDataCoordClient_GetSegmentSnapshot_Call{Call: _e.mock.On("GetSegmentSnapshot",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_GetSegmentSnapshot_Call) Run(run func(ctx context.Context, in *datapb.GetSegmentSnapshotRequest, opts ...grpc.CallOption)) *MockDataCoordClient_GetSegmentSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.GetSegmentSnapshotRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_GetSegmentSnapshot_Call) Return(_a0 *datapb.GetSegmentSnapshotResponse, _a1 error) *MockDataCoordClient_GetSegmentSnapshot_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_GetSegmentSnapshot_Call) RunAndReturn(run func(context.Context, *datapb.GetSegmentSnapshotRequest, ...grpc.CallOption) (*datapb.GetSegmentSnapshotResponse, error)) *MockDataCoordClient_GetSegmentSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetSegmentStates provides a mock function with given fields: ctx, in, opts
 func (_m *MockDataCoordClient) GetSegmentStates(ctx context.Context, in *datapb.GetSegmentStatesRequest, opts ...grpc.CallOption) (*datapb.GetSegmentStatesResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -2524,6 +3224,286 @@ func (_c *MockDataCoordClient_Import_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// ImportSegmentBinlogs provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) ImportSegmentBinlogs(ctx context.Context, in *datapb.ImportSegmentBinlogsRequest, opts ...grpc.CallOption) (*datapb.ImportSegmentBinlogsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.ImportSegmentBinlogsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.ImportSegmentBinlogsRequest, ...grpc.CallOption) (*datapb.ImportSegmentBinlogsResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.ImportSegmentBinlogsRequest, ...grpc.CallOption) *datapb.ImportSegmentBinlogsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.ImportSegmentBinlogsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.ImportSegmentBinlogsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_ImportSegmentBinlogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ImportSegmentBinlogs'
+type MockDataCoordClient_ImportSegmentBinlogs_Call struct {
+	*mock.Call
+}
+
+// ImportSegmentBinlogs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.ImportSegmentBinlogsRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) ImportSegmentBinlogs(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_ImportSegmentBinlogs_Call {
+	return &MockDataCoordClient_ImportSegmentBinlogs_Call{Call: _e.mock.On("ImportSegmentBinlogs",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_ImportSegmentBinlogs_Call) Run(run func(ctx context.Context, in *datapb.ImportSegmentBinlogsRequest, opts ...grpc.CallOption)) *MockDataCoordClient_ImportSegmentBinlogs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.ImportSegmentBinlogsRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_ImportSegmentBinlogs_Call) Return(_a0 *datapb.ImportSegmentBinlogsResponse, _a1 error) *MockDataCoordClient_ImportSegmentBinlogs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_ImportSegmentBinlogs_Call) RunAndReturn(run func(context.Context, *datapb.ImportSegmentBinlogsRequest, ...grpc.CallOption) (*datapb.ImportSegmentBinlogsResponse, error)) *MockDataCoordClient_ImportSegmentBinlogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InspectMeta provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) InspectMeta(ctx context.Context, in *datapb.InspectMetaRequest, opts ...grpc.CallOption) (*datapb.InspectMetaResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.InspectMetaResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.InspectMetaRequest, ...grpc.CallOption) (*datapb.InspectMetaResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.InspectMetaRequest, ...grpc.CallOption) *datapb.InspectMetaResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.InspectMetaResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.InspectMetaRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_InspectMeta_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InspectMeta'
+type MockDataCoordClient_InspectMeta_Call struct {
+	*mock.Call
+}
+
+// InspectMeta is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.InspectMetaRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) InspectMeta(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_InspectMeta_Call {
+	return &MockDataCoordClient_InspectMeta_Call{Call: _e.mock.On("InspectMeta",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_InspectMeta_Call) Run(run func(ctx context.Context, in *datapb.InspectMetaRequest, opts ...grpc.CallOption)) *MockDataCoordClient_InspectMeta_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.InspectMetaRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_InspectMeta_Call) Return(_a0 *datapb.InspectMetaResponse, _a1 error) *MockDataCoordClient_InspectMeta_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_InspectMeta_Call) RunAndReturn(run func(context.Context, *datapb.InspectMetaRequest, ...grpc.CallOption) (*datapb.InspectMetaResponse, error)) *MockDataCoordClient_InspectMeta_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListCompactionTasks provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) ListCompactionTasks(ctx context.Context, in *datapb.ListCompactionTasksRequest, opts ...grpc.CallOption) (*datapb.ListCompactionTasksResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.ListCompactionTasksResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.ListCompactionTasksRequest, ...grpc.CallOption) (*datapb.ListCompactionTasksResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.ListCompactionTasksRequest, ...grpc.CallOption) *datapb.ListCompactionTasksResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.ListCompactionTasksResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.ListCompactionTasksRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_ListCompactionTasks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListCompactionTasks'
+type MockDataCoordClient_ListCompactionTasks_Call struct {
+	*mock.Call
+}
+
+// ListCompactionTasks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.ListCompactionTasksRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) ListCompactionTasks(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_ListCompactionTasks_Call {
+	return &MockDataCoordClient_ListCompactionTasks_Call{Call: _e.mock.On("ListCompactionTasks",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_ListCompactionTasks_Call) Run(run func(ctx context.Context, in *datapb.ListCompactionTasksRequest, opts ...grpc.CallOption)) *MockDataCoordClient_ListCompactionTasks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.ListCompactionTasksRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_ListCompactionTasks_Call) Return(_a0 *datapb.ListCompactionTasksResponse, _a1 error) *MockDataCoordClient_ListCompactionTasks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_ListCompactionTasks_Call) RunAndReturn(run func(context.Context, *datapb.ListCompactionTasksRequest, ...grpc.CallOption) (*datapb.ListCompactionTasksResponse, error)) *MockDataCoordClient_ListCompactionTasks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MajorCompaction provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) MajorCompaction(ctx context.Context, in *datapb.MajorCompactionRequest, opts ...grpc.CallOption) (*datapb.MajorCompactionResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.MajorCompactionResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.MajorCompactionRequest, ...grpc.CallOption) (*datapb.MajorCompactionResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.MajorCompactionRequest, ...grpc.CallOption) *datapb.MajorCompactionResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.MajorCompactionResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.MajorCompactionRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_MajorCompaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MajorCompaction'
+type MockDataCoordClient_MajorCompaction_Call struct {
+	*mock.Call
+}
+
+// MajorCompaction is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.MajorCompactionRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) MajorCompaction(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_MajorCompaction_Call {
+	return &MockDataCoordClient_MajorCompaction_Call{Call: _e.mock.On("MajorCompaction",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_MajorCompaction_Call) Run(run func(ctx context.Context, in *datapb.MajorCompactionRequest, opts ...grpc.CallOption)) *MockDataCoordClient_MajorCompaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.MajorCompactionRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_MajorCompaction_Call) Return(_a0 *datapb.MajorCompactionResponse, _a1 error) *MockDataCoordClient_MajorCompaction_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_MajorCompaction_Call) RunAndReturn(run func(context.Context, *datapb.MajorCompactionRequest, ...grpc.CallOption) (*datapb.MajorCompactionResponse, error)) *MockDataCoordClient_MajorCompaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ManualCompaction provides a mock function with given fields: ctx, in, opts
 func (_m *MockDataCoordClient) ManualCompaction(ctx context.Context, in *milvuspb.ManualCompactionRequest, opts ...grpc.CallOption) (*milvuspb.ManualCompactionResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -2664,6 +3644,146 @@ func (_c *MockDataCoordClient_MarkSegmentsDropped_Call) RunAndReturn(run func(co
 	return _c
 }
 
+// PublishSegments provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) PublishSegments(ctx context.Context, in *datapb.PublishSegmentsRequest, opts ...grpc.CallOption) (*datapb.PublishSegmentsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.PublishSegmentsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.PublishSegmentsRequest, ...grpc.CallOption) (*datapb.PublishSegmentsResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.PublishSegmentsRequest, ...grpc.CallOption) *datapb.PublishSegmentsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.PublishSegmentsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.PublishSegmentsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_PublishSegments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublishSegments'
+type MockDataCoordClient_PublishSegments_Call struct {
+	*mock.Call
+}
+
+// PublishSegments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.PublishSegmentsRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) PublishSegments(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_PublishSegments_Call {
+	return &MockDataCoordClient_PublishSegments_Call{Call: _e.mock.On("PublishSegments",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_PublishSegments_Call) Run(run func(ctx context.Context, in *datapb.PublishSegmentsRequest, opts ...grpc.CallOption)) *MockDataCoordClient_PublishSegments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.PublishSegmentsRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_PublishSegments_Call) Return(_a0 *datapb.PublishSegmentsResponse, _a1 error) *MockDataCoordClient_PublishSegments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_PublishSegments_Call) RunAndReturn(run func(context.Context, *datapb.PublishSegmentsRequest, ...grpc.CallOption) (*datapb.PublishSegmentsResponse, error)) *MockDataCoordClient_PublishSegments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RefreshSegmentStats provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) RefreshSegmentStats(ctx context.Context, in *datapb.RefreshSegmentStatsRequest, opts ...grpc.CallOption) (*datapb.RefreshSegmentStatsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.RefreshSegmentStatsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.RefreshSegmentStatsRequest, ...grpc.CallOption) (*datapb.RefreshSegmentStatsResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.RefreshSegmentStatsRequest, ...grpc.CallOption) *datapb.RefreshSegmentStatsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.RefreshSegmentStatsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.RefreshSegmentStatsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_RefreshSegmentStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RefreshSegmentStats'
+type MockDataCoordClient_RefreshSegmentStats_Call struct {
+	*mock.Call
+}
+
+// RefreshSegmentStats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.RefreshSegmentStatsRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) RefreshSegmentStats(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_RefreshSegmentStats_Call {
+	return &MockDataCoordClient_RefreshSegmentStats_Call{Call: _e.mock.On("RefreshSegmentStats",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_RefreshSegmentStats_Call) Run(run func(ctx context.Context, in *datapb.RefreshSegmentStatsRequest, opts ...grpc.CallOption)) *MockDataCoordClient_RefreshSegmentStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.RefreshSegmentStatsRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_RefreshSegmentStats_Call) Return(_a0 *datapb.RefreshSegmentStatsResponse, _a1 error) *MockDataCoordClient_RefreshSegmentStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_RefreshSegmentStats_Call) RunAndReturn(run func(context.Context, *datapb.RefreshSegmentStatsRequest, ...grpc.CallOption) (*datapb.RefreshSegmentStatsResponse, error)) *MockDataCoordClient_RefreshSegmentStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ReportDataNodeTtMsgs provides a mock function with given fields: ctx, in, opts
 func (_m *MockDataCoordClient) ReportDataNodeTtMsgs(ctx context.Context, in *datapb.ReportDataNodeTtMsgsRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
 	_va := make([]interface{}, len(opts))
@@ -2734,6 +3854,76 @@ func (_c *MockDataCoordClient_ReportDataNodeTtMsgs_Call) RunAndReturn(run func(c
 	return _c
 }
 
+// Restore provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataCoordClient) Restore(ctx context.Context, in *datapb.RestoreRequest, opts ...grpc.CallOption) (*datapb.RestoreResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.RestoreResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.RestoreRequest, ...grpc.CallOption) (*datapb.RestoreResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.RestoreRequest, ...grpc.CallOption) *datapb.RestoreResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.RestoreResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.RestoreRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataCoordClient_Restore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restore'
+type MockDataCoordClient_Restore_Call struct {
+	*mock.Call
+}
+
+// Restore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.RestoreRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataCoordClient_Expecter) Restore(ctx interface{}, in interface{}, opts ...interface{}) *MockDataCoordClient_Restore_Call {
+	return &MockDataCoordClient_Restore_Call{Call: _e.mock.On("Restore",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataCoordClient_Restore_Call) Run(run func(ctx context.Context, in *datapb.RestoreRequest, opts ...grpc.CallOption)) *MockDataCoordClient_Restore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.RestoreRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataCoordClient_Restore_Call) Return(_a0 *datapb.RestoreResponse, _a1 error) *MockDataCoordClient_Restore_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataCoordClient_Restore_Call) RunAndReturn(run func(context.Context, *datapb.RestoreRequest, ...grpc.CallOption) (*datapb.RestoreResponse, error)) *MockDataCoordClient_Restore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveBinlogPaths provides a mock function with given fields: ctx, in, opts
 func (_m *MockDataCoordClient) SaveBinlogPaths(ctx context.Context, in *datapb.SaveBinlogPathsRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
 	_va := make([]interface{}, len(opts))
@@ -197,6 +197,61 @@ func (_c *MockDataNode_Compaction_Call) RunAndReturn(run func(context.Context, *
 	return _c
 }
 
+// DropCompactionPlan provides a mock function with given fields: _a0, _a1
+func (_m *MockDataNode) DropCompactionPlan(_a0 context.Context, _a1 *datapb.DropCompactionPlanRequest) (*commonpb.Status, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *commonpb.Status
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.DropCompactionPlanRequest) (*commonpb.Status, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.DropCompactionPlanRequest) *commonpb.Status); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*commonpb.Status)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.DropCompactionPlanRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataNode_DropCompactionPlan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DropCompactionPlan'
+type MockDataNode_DropCompactionPlan_Call struct {
+	*mock.Call
+}
+
+// DropCompactionPlan is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.DropCompactionPlanRequest
+func (_e *MockDataNode_Expecter) DropCompactionPlan(_a0 interface{}, _a1 interface{}) *MockDataNode_DropCompactionPlan_Call {
+	return &MockDataNode_DropCompactionPlan_Call{Call: _e.mock.On("DropCompactionPlan", _a0, _a1)}
+}
+
+func (_c *MockDataNode_DropCompactionPlan_Call) Run(run func(_a0 context.Context, _a1 *datapb.DropCompactionPlanRequest)) *MockDataNode_DropCompactionPlan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.DropCompactionPlanRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataNode_DropCompactionPlan_Call) Return(_a0 *commonpb.Status, _a1 error) *MockDataNode_DropCompactionPlan_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataNode_DropCompactionPlan_Call) RunAndReturn(run func(context.Context, *datapb.DropCompactionPlanRequest) (*commonpb.Status, error)) *MockDataNode_DropCompactionPlan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DropImport provides a mock function with given fields: _a0, _a1
 func (_m *MockDataNode) DropImport(_a0 context.Context, _a1 *datapb.DropImportRequest) (*commonpb.Status, error) {
 	ret := _m.Called(_a0, _a1)
@@ -1547,6 +1602,61 @@ func (_c *MockDataNode_UpdateStateCode_Call) RunAndReturn(run func(commonpb.Stat
 	return _c
 }
 
+// VerifySegment provides a mock function with given fields: _a0, _a1
+func (_m *MockDataNode) VerifySegment(_a0 context.Context, _a1 *datapb.VerifySegmentRequest) (*datapb.VerifySegmentResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.VerifySegmentResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.VerifySegmentRequest) (*datapb.VerifySegmentResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.VerifySegmentRequest) *datapb.VerifySegmentResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.VerifySegmentResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.VerifySegmentRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataNode_VerifySegment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifySegment'
+type MockDataNode_VerifySegment_Call struct {
+	*mock.Call
+}
+
+// VerifySegment is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.VerifySegmentRequest
+func (_e *MockDataNode_Expecter) VerifySegment(_a0 interface{}, _a1 interface{}) *MockDataNode_VerifySegment_Call {
+	return &MockDataNode_VerifySegment_Call{Call: _e.mock.On("VerifySegment", _a0, _a1)}
+}
+
+func (_c *MockDataNode_VerifySegment_Call) Run(run func(_a0 context.Context, _a1 *datapb.VerifySegmentRequest)) *MockDataNode_VerifySegment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.VerifySegmentRequest))
+	})
+	return _c
+}
+
+func (_c *MockDataNode_VerifySegment_Call) Return(_a0 *datapb.VerifySegmentResponse, _a1 error) *MockDataNode_VerifySegment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataNode_VerifySegment_Call) RunAndReturn(run func(context.Context, *datapb.VerifySegmentRequest) (*datapb.VerifySegmentResponse, error)) *MockDataNode_VerifySegment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // WatchDmChannels provides a mock function with given fields: _a0, _a1
 func (_m *MockDataNode) WatchDmChannels(_a0 context.Context, _a1 *datapb.WatchDmChannelsRequest) (*commonpb.Status, error) {
 	ret := _m.Called(_a0, _a1)
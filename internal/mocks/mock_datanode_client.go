@@ -282,6 +282,76 @@ func (_c *MockDataNodeClient_Compaction_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
+// DropCompactionPlan provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataNodeClient) DropCompactionPlan(ctx context.Context, in *datapb.DropCompactionPlanRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *commonpb.Status
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.DropCompactionPlanRequest, ...grpc.CallOption) (*commonpb.Status, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.DropCompactionPlanRequest, ...grpc.CallOption) *commonpb.Status); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*commonpb.Status)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.DropCompactionPlanRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataNodeClient_DropCompactionPlan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DropCompactionPlan'
+type MockDataNodeClient_DropCompactionPlan_Call struct {
+	*mock.Call
+}
+
+// DropCompactionPlan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.DropCompactionPlanRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataNodeClient_Expecter) DropCompactionPlan(ctx interface{}, in interface{}, opts ...interface{}) *MockDataNodeClient_DropCompactionPlan_Call {
+	return &MockDataNodeClient_DropCompactionPlan_Call{Call: _e.mock.On("DropCompactionPlan",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataNodeClient_DropCompactionPlan_Call) Run(run func(ctx context.Context, in *datapb.DropCompactionPlanRequest, opts ...grpc.CallOption)) *MockDataNodeClient_DropCompactionPlan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.DropCompactionPlanRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataNodeClient_DropCompactionPlan_Call) Return(_a0 *commonpb.Status, _a1 error) *MockDataNodeClient_DropCompactionPlan_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataNodeClient_DropCompactionPlan_Call) RunAndReturn(run func(context.Context, *datapb.DropCompactionPlanRequest, ...grpc.CallOption) (*commonpb.Status, error)) *MockDataNodeClient_DropCompactionPlan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DropImport provides a mock function with given fields: ctx, in, opts
 func (_m *MockDataNodeClient) DropImport(ctx context.Context, in *datapb.DropImportRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
 	_va := make([]interface{}, len(opts))
@@ -1402,6 +1472,76 @@ func (_c *MockDataNodeClient_SyncSegments_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// VerifySegment provides a mock function with given fields: ctx, in, opts
+func (_m *MockDataNodeClient) VerifySegment(ctx context.Context, in *datapb.VerifySegmentRequest, opts ...grpc.CallOption) (*datapb.VerifySegmentResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.VerifySegmentResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.VerifySegmentRequest, ...grpc.CallOption) (*datapb.VerifySegmentResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.VerifySegmentRequest, ...grpc.CallOption) *datapb.VerifySegmentResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.VerifySegmentResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.VerifySegmentRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDataNodeClient_VerifySegment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifySegment'
+type MockDataNodeClient_VerifySegment_Call struct {
+	*mock.Call
+}
+
+// VerifySegment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.VerifySegmentRequest
+//   - opts ...grpc.CallOption
+func (_e *MockDataNodeClient_Expecter) VerifySegment(ctx interface{}, in interface{}, opts ...interface{}) *MockDataNodeClient_VerifySegment_Call {
+	return &MockDataNodeClient_VerifySegment_Call{Call: _e.mock.On("VerifySegment",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockDataNodeClient_VerifySegment_Call) Run(run func(ctx context.Context, in *datapb.VerifySegmentRequest, opts ...grpc.CallOption)) *MockDataNodeClient_VerifySegment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.VerifySegmentRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDataNodeClient_VerifySegment_Call) Return(_a0 *datapb.VerifySegmentResponse, _a1 error) *MockDataNodeClient_VerifySegment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDataNodeClient_VerifySegment_Call) RunAndReturn(run func(context.Context, *datapb.VerifySegmentRequest, ...grpc.CallOption) (*datapb.VerifySegmentResponse, error)) *MockDataNodeClient_VerifySegment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // WatchDmChannels provides a mock function with given fields: ctx, in, opts
 func (_m *MockDataNodeClient) WatchDmChannels(ctx context.Context, in *datapb.WatchDmChannelsRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
 	_va := make([]interface{}, len(opts))
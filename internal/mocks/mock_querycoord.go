@@ -8,6 +8,8 @@ import (
 	commonpb "github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 
+	datapb "github.com/milvus-io/milvus/internal/proto/datapb"
+
 	internalpb "github.com/milvus-io/milvus/internal/proto/internalpb"
 
 	milvuspb "github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
@@ -254,6 +256,61 @@ func (_c *MockQueryCoord_DeactivateChecker_Call) RunAndReturn(run func(context.C
 	return _c
 }
 
+// DecommissionNode provides a mock function with given fields: _a0, _a1
+func (_m *MockQueryCoord) DecommissionNode(_a0 context.Context, _a1 *datapb.DecommissionNodeRequest) (*commonpb.Status, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *commonpb.Status
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.DecommissionNodeRequest) (*commonpb.Status, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.DecommissionNodeRequest) *commonpb.Status); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*commonpb.Status)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.DecommissionNodeRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQueryCoord_DecommissionNode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DecommissionNode'
+type MockQueryCoord_DecommissionNode_Call struct {
+	*mock.Call
+}
+
+// DecommissionNode is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.DecommissionNodeRequest
+func (_e *MockQueryCoord_Expecter) DecommissionNode(_a0 interface{}, _a1 interface{}) *MockQueryCoord_DecommissionNode_Call {
+	return &MockQueryCoord_DecommissionNode_Call{Call: _e.mock.On("DecommissionNode", _a0, _a1)}
+}
+
+func (_c *MockQueryCoord_DecommissionNode_Call) Run(run func(_a0 context.Context, _a1 *datapb.DecommissionNodeRequest)) *MockQueryCoord_DecommissionNode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.DecommissionNodeRequest))
+	})
+	return _c
+}
+
+func (_c *MockQueryCoord_DecommissionNode_Call) Return(_a0 *commonpb.Status, _a1 error) *MockQueryCoord_DecommissionNode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQueryCoord_DecommissionNode_Call) RunAndReturn(run func(context.Context, *datapb.DecommissionNodeRequest) (*commonpb.Status, error)) *MockQueryCoord_DecommissionNode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DescribeResourceGroup provides a mock function with given fields: _a0, _a1
 func (_m *MockQueryCoord) DescribeResourceGroup(_a0 context.Context, _a1 *querypb.DescribeResourceGroupRequest) (*querypb.DescribeResourceGroupResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -419,6 +476,61 @@ func (_c *MockQueryCoord_GetComponentStates_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// GetDecommissionState provides a mock function with given fields: _a0, _a1
+func (_m *MockQueryCoord) GetDecommissionState(_a0 context.Context, _a1 *datapb.GetDecommissionStateRequest) (*datapb.GetDecommissionStateResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datapb.GetDecommissionStateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetDecommissionStateRequest) (*datapb.GetDecommissionStateResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetDecommissionStateRequest) *datapb.GetDecommissionStateResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.GetDecommissionStateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetDecommissionStateRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQueryCoord_GetDecommissionState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDecommissionState'
+type MockQueryCoord_GetDecommissionState_Call struct {
+	*mock.Call
+}
+
+// GetDecommissionState is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *datapb.GetDecommissionStateRequest
+func (_e *MockQueryCoord_Expecter) GetDecommissionState(_a0 interface{}, _a1 interface{}) *MockQueryCoord_GetDecommissionState_Call {
+	return &MockQueryCoord_GetDecommissionState_Call{Call: _e.mock.On("GetDecommissionState", _a0, _a1)}
+}
+
+func (_c *MockQueryCoord_GetDecommissionState_Call) Run(run func(_a0 context.Context, _a1 *datapb.GetDecommissionStateRequest)) *MockQueryCoord_GetDecommissionState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*datapb.GetDecommissionStateRequest))
+	})
+	return _c
+}
+
+func (_c *MockQueryCoord_GetDecommissionState_Call) Return(_a0 *datapb.GetDecommissionStateResponse, _a1 error) *MockQueryCoord_GetDecommissionState_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQueryCoord_GetDecommissionState_Call) RunAndReturn(run func(context.Context, *datapb.GetDecommissionStateRequest) (*datapb.GetDecommissionStateResponse, error)) *MockQueryCoord_GetDecommissionState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetMetrics provides a mock function with given fields: _a0, _a1
 func (_m *MockQueryCoord) GetMetrics(_a0 context.Context, _a1 *milvuspb.GetMetricsRequest) (*milvuspb.GetMetricsResponse, error) {
 	ret := _m.Called(_a0, _a1)
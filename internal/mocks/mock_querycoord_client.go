@@ -9,6 +9,8 @@ import (
 
 	grpc "google.golang.org/grpc"
 
+	datapb "github.com/milvus-io/milvus/internal/proto/datapb"
+
 	internalpb "github.com/milvus-io/milvus/internal/proto/internalpb"
 
 	milvuspb "github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
@@ -352,6 +354,76 @@ func (_c *MockQueryCoordClient_DeactivateChecker_Call) RunAndReturn(run func(con
 	return _c
 }
 
+// DecommissionNode provides a mock function with given fields: ctx, in, opts
+func (_m *MockQueryCoordClient) DecommissionNode(ctx context.Context, in *datapb.DecommissionNodeRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *commonpb.Status
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.DecommissionNodeRequest, ...grpc.CallOption) (*commonpb.Status, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.DecommissionNodeRequest, ...grpc.CallOption) *commonpb.Status); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*commonpb.Status)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.DecommissionNodeRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQueryCoordClient_DecommissionNode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DecommissionNode'
+type MockQueryCoordClient_DecommissionNode_Call struct {
+	*mock.Call
+}
+
+// DecommissionNode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.DecommissionNodeRequest
+//   - opts ...grpc.CallOption
+func (_e *MockQueryCoordClient_Expecter) DecommissionNode(ctx interface{}, in interface{}, opts ...interface{}) *MockQueryCoordClient_DecommissionNode_Call {
+	return &MockQueryCoordClient_DecommissionNode_Call{Call: _e.mock.On("DecommissionNode",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockQueryCoordClient_DecommissionNode_Call) Run(run func(ctx context.Context, in *datapb.DecommissionNodeRequest, opts ...grpc.CallOption)) *MockQueryCoordClient_DecommissionNode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.DecommissionNodeRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockQueryCoordClient_DecommissionNode_Call) Return(_a0 *commonpb.Status, _a1 error) *MockQueryCoordClient_DecommissionNode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQueryCoordClient_DecommissionNode_Call) RunAndReturn(run func(context.Context, *datapb.DecommissionNodeRequest, ...grpc.CallOption) (*commonpb.Status, error)) *MockQueryCoordClient_DecommissionNode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DescribeResourceGroup provides a mock function with given fields: ctx, in, opts
 func (_m *MockQueryCoordClient) DescribeResourceGroup(ctx context.Context, in *querypb.DescribeResourceGroupRequest, opts ...grpc.CallOption) (*querypb.DescribeResourceGroupResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -562,6 +634,76 @@ func (_c *MockQueryCoordClient_GetComponentStates_Call) RunAndReturn(run func(co
 	return _c
 }
 
+// GetDecommissionState provides a mock function with given fields: ctx, in, opts
+func (_m *MockQueryCoordClient) GetDecommissionState(ctx context.Context, in *datapb.GetDecommissionStateRequest, opts ...grpc.CallOption) (*datapb.GetDecommissionStateResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *datapb.GetDecommissionStateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetDecommissionStateRequest, ...grpc.CallOption) (*datapb.GetDecommissionStateResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *datapb.GetDecommissionStateRequest, ...grpc.CallOption) *datapb.GetDecommissionStateResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datapb.GetDecommissionStateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *datapb.GetDecommissionStateRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQueryCoordClient_GetDecommissionState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDecommissionState'
+type MockQueryCoordClient_GetDecommissionState_Call struct {
+	*mock.Call
+}
+
+// GetDecommissionState is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *datapb.GetDecommissionStateRequest
+//   - opts ...grpc.CallOption
+func (_e *MockQueryCoordClient_Expecter) GetDecommissionState(ctx interface{}, in interface{}, opts ...interface{}) *MockQueryCoordClient_GetDecommissionState_Call {
+	return &MockQueryCoordClient_GetDecommissionState_Call{Call: _e.mock.On("GetDecommissionState",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockQueryCoordClient_GetDecommissionState_Call) Run(run func(ctx context.Context, in *datapb.GetDecommissionStateRequest, opts ...grpc.CallOption)) *MockQueryCoordClient_GetDecommissionState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*datapb.GetDecommissionStateRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockQueryCoordClient_GetDecommissionState_Call) Return(_a0 *datapb.GetDecommissionStateResponse, _a1 error) *MockQueryCoordClient_GetDecommissionState_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQueryCoordClient_GetDecommissionState_Call) RunAndReturn(run func(context.Context, *datapb.GetDecommissionStateRequest, ...grpc.CallOption) (*datapb.GetDecommissionStateResponse, error)) *MockQueryCoordClient_GetDecommissionState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetMetrics provides a mock function with given fields: ctx, in, opts
 func (_m *MockQueryCoordClient) GetMetrics(ctx context.Context, in *milvuspb.GetMetricsRequest, opts ...grpc.CallOption) (*milvuspb.GetMetricsResponse, error) {
 	_va := make([]interface{}, len(opts))
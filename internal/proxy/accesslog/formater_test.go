@@ -18,10 +18,12 @@ package accesslog
 
 import (
 	"context"
+	"encoding/base64"
 	"net"
 	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/suite"
 	"go.opentelemetry.io/otel"
 	"google.golang.org/grpc"
@@ -30,6 +32,7 @@ import (
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util"
@@ -181,6 +184,93 @@ func (s *LogFormatterSuite) TestFormatMethodResult() {
 	}
 }
 
+func (s *LogFormatterSuite) TestFormatRequest() {
+	formatter := NewFormatter("$method_request")
+	decode := func(fs string, message proto.Message) {
+		bytes, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(fs, "\n"))
+		s.Require().NoError(err)
+		s.Require().NoError(proto.Unmarshal(bytes, message))
+	}
+
+	query := &milvuspb.QueryRequest{
+		DbName:         "test-db",
+		CollectionName: "test-collection",
+		Expr:           "pk in [1, 2]",
+	}
+	decoded := &milvuspb.QueryRequest{}
+	decode(formatter.Format(NewGrpcAccessInfo(s.ctx, s.serverinfo, query)), decoded)
+	s.True(proto.Equal(query, decoded))
+
+	// the searched vectors are redacted
+	placeholderGroup, err := proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{{
+			Tag:    "$0",
+			Type:   commonpb.PlaceholderType_FloatVector,
+			Values: [][]byte{{1, 2, 3, 4}, {5, 6, 7, 8}},
+		}},
+	})
+	s.Require().NoError(err)
+	search := &milvuspb.SearchRequest{
+		CollectionName:   "test-collection",
+		Dsl:              "pk > 0",
+		PlaceholderGroup: placeholderGroup,
+	}
+	expected, err := proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{{
+			Tag:    "$0",
+			Type:   commonpb.PlaceholderType_FloatVector,
+			Values: [][]byte{make([]byte, 4), make([]byte, 4)},
+		}},
+	})
+	s.Require().NoError(err)
+
+	decodedSearch := &milvuspb.SearchRequest{}
+	decode(formatter.Format(NewGrpcAccessInfo(s.ctx, s.serverinfo, search)), decodedSearch)
+	s.Equal("pk > 0", decodedSearch.GetDsl())
+	s.Equal(expected, decodedSearch.GetPlaceholderGroup())
+	// the request itself is untouched
+	s.Equal(placeholderGroup, search.GetPlaceholderGroup())
+
+	hybridSearch := &milvuspb.HybridSearchRequest{
+		CollectionName: "test-collection",
+		Requests:       []*milvuspb.SearchRequest{search, {PlaceholderGroup: []byte("malformed")}},
+	}
+	decodedHybridSearch := &milvuspb.HybridSearchRequest{}
+	decode(formatter.Format(NewGrpcAccessInfo(s.ctx, s.serverinfo, hybridSearch)), decodedHybridSearch)
+	s.Require().Len(decodedHybridSearch.GetRequests(), 2)
+	s.Equal(expected, decodedHybridSearch.GetRequests()[0].GetPlaceholderGroup())
+	s.Empty(decodedHybridSearch.GetRequests()[1].GetPlaceholderGroup())
+
+	// the requests of the other methods are not logged
+	info := NewGrpcAccessInfo(s.ctx, s.serverinfo, &milvuspb.InsertRequest{CollectionName: "test-collection"})
+	s.Equal(unknownString+"\n", formatter.Format(info))
+	info = NewGrpcAccessInfo(s.ctx, s.serverinfo, nil)
+	s.Equal(unknownString+"\n", formatter.Format(info))
+}
+
+func (s *LogFormatterSuite) TestParse() {
+	formatter := NewFormatter("[$time_start] $method_name [db: $database_name] [expr: $method_expr]")
+	info := NewGrpcAccessInfo(s.ctx, &grpc.UnaryServerInfo{FullMethod: "/milvus.proto.milvus.MilvusService/Query"}, &milvuspb.QueryRequest{
+		DbName: "test-db",
+		Expr:   "a in [1, 2] and b == \"]\"",
+	})
+	values, err := formatter.Parse(formatter.Format(info))
+	s.Require().NoError(err)
+	s.Equal("Query", values["$method_name"])
+	s.Equal("test-db", values["$database_name"])
+	s.Equal("a in [1, 2] and b == \"]\"", values["$method_expr"])
+	s.Equal(getTimeStart(info), values["$time_start"])
+
+	_, err = formatter.Parse("Query [db: test-db]")
+	s.Error(err)
+	_, err = formatter.Parse("[now] Query [db: test-db] [expr: ")
+	s.Error(err)
+
+	// adjacent metrics could not be parsed
+	_, err = NewFormatter("$method_name$database_name").Parse("Querytest-db")
+	s.Error(err)
+}
+
 func (s *LogFormatterSuite) TestParseConfigKeyFailed() {
 	configKey := ".testf.invalidSub"
 	_, _, err := parseConfigKey(configKey)
@@ -17,6 +17,7 @@
 package accesslog
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/milvus-io/milvus/pkg/util/merr"
@@ -48,6 +49,7 @@ var metricFuncMap = map[string]getMetricFunc{
 	"$time_start":      getTimeStart,
 	"$time_end":        getTimeEnd,
 	"$method_expr":     getExpr,
+	"$method_request":  getRequest,
 	"$sdk_version":     getSdkVersion,
 	"$cluster_prefix":  getClusterPrefix,
 }
@@ -147,6 +149,39 @@ func (f *Formatter) Format(info AccessInfo) string {
 	return result
 }
 
+// Parse extracts the metric values from a line written by the formatter, it's the inverse of Format.
+// A metric value is assumed not to contain the text following the metric in the format.
+func (f *Formatter) Parse(line string) (map[string]string, error) {
+	line = strings.TrimSuffix(line, "\n")
+	values := make(map[string]string, len(f.fields))
+	rest := line
+	for id, prefix := range f.prefixs {
+		if !strings.HasPrefix(rest, prefix) {
+			return nil, merr.WrapErrParameterInvalid(f.fmt, line, "line does not match the access log format")
+		}
+		rest = rest[len(prefix):]
+		if id >= len(f.fields) {
+			break
+		}
+
+		next := f.prefixs[id+1]
+		end := -1
+		if id+1 == len(f.prefixs)-1 {
+			if strings.HasSuffix(rest, next) {
+				end = len(rest) - len(next)
+			}
+		} else if next != "" {
+			end = strings.Index(rest, next)
+		}
+		if end < 0 {
+			return nil, merr.WrapErrParameterInvalid(f.fmt, line, fmt.Sprintf("failed to parse the value of %s", f.fields[id]))
+		}
+		values[f.fields[id]] = rest[:end]
+		rest = rest[end:]
+	}
+	return values, nil
+}
+
 func parseConfigKey(k string) (string, string, error) {
 	fields := strings.Split(k, ".")
 	if len(fields) != 2 || (fields[1] != fomaterkey && fields[1] != methodKey) {
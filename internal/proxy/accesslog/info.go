@@ -18,10 +18,12 @@ package accesslog
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"path"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -120,21 +122,21 @@ func getTimeCost(i *GrpcAccessInfo) string {
 }
 
 func getTimeNow(i *GrpcAccessInfo) string {
-	return time.Now().Format(timePrintFormat)
+	return time.Now().Format(TimePrintFormat)
 }
 
 func getTimeStart(i *GrpcAccessInfo) string {
 	if i.start.IsZero() {
 		return unknownString
 	}
-	return i.start.Format(timePrintFormat)
+	return i.start.Format(TimePrintFormat)
 }
 
 func getTimeEnd(i *GrpcAccessInfo) string {
 	if i.end.IsZero() {
 		return unknownString
 	}
-	return i.end.Format(timePrintFormat)
+	return i.end.Format(TimePrintFormat)
 }
 
 func getMethodName(i *GrpcAccessInfo) string {
@@ -267,6 +269,52 @@ func getExpr(i *GrpcAccessInfo) string {
 	return unknownString
 }
 
+// getRequest returns the base64 encoded Search, HybridSearch or Query request, which could be replayed by the replay tool.
+// The vectors and texts searched are redacted, see redactPlaceholderGroup.
+func getRequest(i *GrpcAccessInfo) string {
+	var message proto.Message
+	switch req := i.req.(type) {
+	case *milvuspb.SearchRequest:
+		redacted := proto.Clone(req).(*milvuspb.SearchRequest)
+		redacted.PlaceholderGroup = redactPlaceholderGroup(redacted.GetPlaceholderGroup())
+		message = redacted
+	case *milvuspb.HybridSearchRequest:
+		redacted := proto.Clone(req).(*milvuspb.HybridSearchRequest)
+		for _, sub := range redacted.GetRequests() {
+			sub.PlaceholderGroup = redactPlaceholderGroup(sub.GetPlaceholderGroup())
+		}
+		message = redacted
+	case *milvuspb.QueryRequest:
+		message = req
+	default:
+		return unknownString
+	}
+	bytes, err := proto.Marshal(message)
+	if err != nil {
+		return unknownString
+	}
+	return base64.StdEncoding.EncodeToString(bytes)
+}
+
+// redactPlaceholderGroup zeroes the values of the placeholders, which keeps the type,
+// the dim and the number of the searched vectors only, nil if the group is malformed.
+func redactPlaceholderGroup(group []byte) []byte {
+	placeholderGroup := &commonpb.PlaceholderGroup{}
+	if err := proto.Unmarshal(group, placeholderGroup); err != nil {
+		return nil
+	}
+	for _, placeholder := range placeholderGroup.GetPlaceholders() {
+		for i, value := range placeholder.GetValues() {
+			placeholder.Values[i] = make([]byte, len(value))
+		}
+	}
+	redacted, err := proto.Marshal(placeholderGroup)
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
 func getSdkVersion(i *GrpcAccessInfo) string {
 	clientInfo := connection.GetManager().Get(i.ctx)
 	if clientInfo != nil {
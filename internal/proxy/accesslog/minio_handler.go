@@ -28,6 +28,7 @@ import (
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"go.uber.org/zap"
 
+	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/retry"
@@ -60,6 +61,7 @@ type minioHandler struct {
 
 	retentionPolicy RetentionFunc
 	client          *minio.Client
+	putOptions      func(objectName string) minio.PutObjectOptions
 
 	taskCh    chan task
 	closeCh   chan struct{}
@@ -83,6 +85,10 @@ func NewMinioHandler(ctx context.Context, cfg *paramtable.MinioConfig, rootPath
 		iamEndpoint:       cfg.IAMEndpoint.GetValue(),
 	}
 
+	putOptions, err := storage.ObjectPutOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
 	client, err := newMinioClient(ctx, handlerCfg)
 	if err != nil {
 		return nil, err
@@ -92,6 +98,7 @@ func NewMinioHandler(ctx context.Context, cfg *paramtable.MinioConfig, rootPath
 		bucketName: handlerCfg.bucketName,
 		rootPath:   rootPath,
 		client:     client,
+		putOptions: putOptions,
 	}
 	handler.start(queueLen)
 	return handler, nil
@@ -182,7 +189,7 @@ func (c *minioHandler) Update(objectName string, filePath string) {
 // update log file to minio
 func (c *minioHandler) update(objectName string, filePath string) error {
 	path := join(c.rootPath, filePath)
-	_, err := c.client.FPutObject(context.Background(), c.bucketName, path, objectName, minio.PutObjectOptions{})
+	_, err := c.client.FPutObject(context.Background(), c.bucketName, path, objectName, c.putOptions(path))
 	return err
 }
 
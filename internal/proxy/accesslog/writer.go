@@ -37,7 +37,7 @@ const megabyte = 1024 * 1024
 var (
 	CheckBucketRetryAttempts uint = 20
 	timeNameFormat                = ".2006-01-02T15-04-05.000"
-	timePrintFormat               = "2006/01/02 15:04:05.000 -07:00"
+	TimePrintFormat               = "2006/01/02 15:04:05.000 -07:00"
 )
 
 type CacheLogger struct {
@@ -0,0 +1,100 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connector
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/util"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const (
+	typeKey       = "type"
+	addressKey    = "address"
+	topicKey      = "topic"
+	databaseKey   = "database"
+	collectionKey = "collection"
+)
+
+// Config is the config of a connector, which inserts the records of a source into a collection.
+type Config struct {
+	Name           string
+	Type           string
+	Address        string
+	Topic          string
+	DBName         string
+	CollectionName string
+	// Options keeps all the options of the source, for the sources registered by RegisterSource.
+	Options map[string]string
+
+	BatchSize     int
+	FlushInterval time.Duration
+}
+
+// parseConfigs parses the sources defined by <name>.<option> of the connector config.
+func parseConfigs(params *paramtable.ConnectorConfig) ([]*Config, error) {
+	configs := make(map[string]*Config)
+	for key, value := range params.Sources.GetValue() {
+		fields := strings.SplitN(key, ".", 2)
+		if len(fields) != 2 || fields[0] == "" || fields[1] == "" {
+			return nil, merr.WrapErrParameterInvalid("<SourceName>.<option>", key, "parse connector source config key failed")
+		}
+		cfg, ok := configs[fields[0]]
+		if !ok {
+			cfg = &Config{
+				Name:          fields[0],
+				DBName:        util.DefaultDBName,
+				Options:       make(map[string]string),
+				BatchSize:     params.BatchSize.GetAsInt(),
+				FlushInterval: params.FlushInterval.GetAsDuration(time.Millisecond),
+			}
+			configs[fields[0]] = cfg
+		}
+		cfg.Options[fields[1]] = value
+		switch fields[1] {
+		case typeKey:
+			cfg.Type = value
+		case addressKey:
+			cfg.Address = value
+		case topicKey:
+			cfg.Topic = value
+		case databaseKey:
+			cfg.DBName = value
+		case collectionKey:
+			cfg.CollectionName = value
+		}
+	}
+
+	result := make([]*Config, 0, len(configs))
+	for _, cfg := range configs {
+		if cfg.Type == "" || cfg.CollectionName == "" {
+			return nil, merr.WrapErrParameterInvalidMsg("type and collection are required by connector %s", cfg.Name)
+		}
+		if cfg.BatchSize <= 0 || cfg.FlushInterval <= 0 {
+			return nil, merr.WrapErrParameterInvalidMsg("batch size and flush interval of connectors should be positive")
+		}
+		result = append(result, cfg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
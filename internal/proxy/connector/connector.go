@@ -0,0 +1,173 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connector
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+const (
+	checkpointPrefix = "connector/checkpoint"
+	retryInterval    = time.Second
+)
+
+// Writer is the insert path of the connectors, implemented by proxy.
+type Writer interface {
+	DescribeCollection(ctx context.Context, request *milvuspb.DescribeCollectionRequest) (*milvuspb.DescribeCollectionResponse, error)
+	Insert(ctx context.Context, request *milvuspb.InsertRequest) (*milvuspb.MutationResult, error)
+}
+
+// Connector inserts the records of a source into a collection in batches, and
+// checkpoints the position of the source after each batch inserted, so the
+// records are inserted at least once.
+type Connector struct {
+	cfg         *Config
+	writer      Writer
+	checkpoints kv.BaseKV
+	newSource   func(cfg *Config) (Source, error)
+	mapper      *mapper
+}
+
+func newConnector(cfg *Config, writer Writer, checkpoints kv.BaseKV) *Connector {
+	return &Connector{
+		cfg:         cfg,
+		writer:      writer,
+		checkpoints: checkpoints,
+		newSource:   newSource,
+	}
+}
+
+func (c *Connector) checkpointKey() string {
+	return path.Join(checkpointPrefix, c.cfg.Name)
+}
+
+// Run consumes the source from the checkpoint until the context is done or the source is broken.
+func (c *Connector) Run(ctx context.Context) error {
+	log := log.Ctx(ctx).With(zap.String("connector", c.cfg.Name))
+
+	var position []byte
+	value, err := c.checkpoints.Load(c.checkpointKey())
+	if err == nil {
+		position = []byte(value)
+	} else if !errors.Is(err, merr.ErrIoKeyNotFound) {
+		return err
+	}
+	if err := c.refreshMapper(ctx); err != nil {
+		return err
+	}
+
+	source, err := c.newSource(c.cfg)
+	if err != nil {
+		return err
+	}
+	if err := source.Open(ctx, position); err != nil {
+		return err
+	}
+	defer source.Close()
+	log.Info("connector started", zap.String("collection", c.cfg.CollectionName), zap.Bool("fromCheckpoint", position != nil))
+
+	batch := make([]*Record, 0, c.cfg.BatchSize)
+	ticker := time.NewTicker(c.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case record, ok := <-source.Chan():
+			if !ok {
+				return fmt.Errorf("source of connector %s is closed", c.cfg.Name)
+			}
+			batch = append(batch, record)
+			if len(batch) < c.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		if err := c.flush(ctx, batch); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		ticker.Reset(c.cfg.FlushInterval)
+	}
+}
+
+func (c *Connector) refreshMapper(ctx context.Context) error {
+	resp, err := c.writer.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         c.cfg.DBName,
+		CollectionName: c.cfg.CollectionName,
+	})
+	if err = merr.CheckRPCCall(resp, err); err != nil {
+		return err
+	}
+	m, err := newMapper(resp.GetSchema())
+	if err != nil {
+		return err
+	}
+	c.mapper = m
+	return nil
+}
+
+// flush inserts the batch, retries until it's inserted or the context is done,
+// then saves the position of the last record as the checkpoint.
+func (c *Connector) flush(ctx context.Context, batch []*Record) error {
+	log := log.Ctx(ctx).With(zap.String("connector", c.cfg.Name))
+	for {
+		err := c.insert(ctx, batch)
+		if err == nil {
+			break
+		}
+		log.Warn("failed to insert records of connector, retry later", zap.Int("records", len(batch)), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryInterval):
+		}
+		// the schema may be changed
+		if err := c.refreshMapper(ctx); err != nil {
+			log.Warn("failed to refresh the schema of connector", zap.Error(err))
+		}
+	}
+	return c.checkpoints.Save(c.checkpointKey(), string(batch[len(batch)-1].Position))
+}
+
+func (c *Connector) insert(ctx context.Context, batch []*Record) error {
+	fieldsData, numRows, err := c.mapper.Map(batch)
+	if err != nil || numRows == 0 {
+		return err
+	}
+	resp, err := c.writer.Insert(ctx, &milvuspb.InsertRequest{
+		DbName:         c.cfg.DBName,
+		CollectionName: c.cfg.CollectionName,
+		FieldsData:     fieldsData,
+		NumRows:        numRows,
+	})
+	return merr.CheckRPCCall(resp, err)
+}
@@ -0,0 +1,232 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	memkv "github.com/milvus-io/milvus/internal/kv/mem"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type fakeSource struct {
+	position []byte
+	ch       chan *Record
+}
+
+func (s *fakeSource) Open(ctx context.Context, position []byte) error {
+	s.position = position
+	return nil
+}
+
+func (s *fakeSource) Chan() <-chan *Record {
+	return s.ch
+}
+
+func (s *fakeSource) Close() {}
+
+func testSchema() *schemapb.CollectionSchema {
+	return &schemapb.CollectionSchema{
+		Name:               "test",
+		EnableDynamicField: true,
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "id", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{
+				FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector,
+				TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}},
+			},
+			{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+		},
+	}
+}
+
+func testRecord(id int) *Record {
+	return &Record{
+		Value:    []byte(fmt.Sprintf(`{"id": %d, "vec": [0.1, 0.2], "tag": "t%d"}`, id, id)),
+		Position: []byte(fmt.Sprint(id)),
+	}
+}
+
+type ConnectorSuite struct {
+	suite.Suite
+
+	writer      *mocks.MockProxy
+	checkpoints *memkv.MemoryKV
+	source      *fakeSource
+	connector   *Connector
+}
+
+func (s *ConnectorSuite) SetupTest() {
+	s.writer = mocks.NewMockProxy(s.T())
+	s.writer.EXPECT().DescribeCollection(mock.Anything, mock.Anything).Return(&milvuspb.DescribeCollectionResponse{
+		Status: merr.Success(),
+		Schema: testSchema(),
+	}, nil).Maybe()
+	s.checkpoints = memkv.NewMemoryKV()
+	s.source = &fakeSource{ch: make(chan *Record, 16)}
+	s.connector = newConnector(&Config{
+		Name:           "test",
+		Type:           "fake",
+		DBName:         "default",
+		CollectionName: "test",
+		BatchSize:      2,
+		FlushInterval:  50 * time.Millisecond,
+	}, s.writer, s.checkpoints)
+	s.connector.newSource = func(cfg *Config) (Source, error) {
+		return s.source, nil
+	}
+}
+
+func (s *ConnectorSuite) run(ctx context.Context) chan error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.connector.Run(ctx)
+	}()
+	return errCh
+}
+
+func (s *ConnectorSuite) checkpoint() string {
+	value, _ := s.checkpoints.Load(s.connector.checkpointKey())
+	return value
+}
+
+func (s *ConnectorSuite) TestRun() {
+	rows := atomic.NewInt64(0)
+	s.writer.EXPECT().Insert(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error) {
+			s.Equal("test", req.GetCollectionName())
+			s.Len(req.GetFieldsData(), 3)
+			for _, fieldData := range req.GetFieldsData() {
+				s.Equal(fieldData.GetFieldName() == "$meta", fieldData.GetIsDynamic())
+			}
+			rows.Add(int64(req.GetNumRows()))
+			return &milvuspb.MutationResult{Status: merr.Success()}, nil
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := s.run(ctx)
+	s.source.ch <- testRecord(1)
+	s.source.ch <- &Record{Value: []byte(`{"id": "invalid"}`), Position: []byte("2")}
+	s.source.ch <- testRecord(3)
+	s.Eventually(func() bool {
+		return rows.Load() == 2 && s.checkpoint() == "3"
+	}, 5*time.Second, 10*time.Millisecond)
+	s.Nil(s.source.position)
+
+	// the batch not full is flushed by interval
+	s.source.ch <- testRecord(4)
+	s.Eventually(func() bool {
+		return rows.Load() == 3 && s.checkpoint() == "4"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+}
+
+func (s *ConnectorSuite) TestResume() {
+	s.NoError(s.checkpoints.Save(s.connector.checkpointKey(), "10"))
+	close(s.source.ch)
+	s.Error(s.connector.Run(context.Background()))
+	s.Equal([]byte("10"), s.source.position)
+}
+
+func (s *ConnectorSuite) TestRetry() {
+	s.writer.EXPECT().Insert(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+	s.writer.EXPECT().Insert(mock.Anything, mock.Anything).Return(
+		&milvuspb.MutationResult{Status: merr.Status(merr.ErrServiceNotReady)}, nil).Once()
+	s.writer.EXPECT().Insert(mock.Anything, mock.Anything).Return(
+		&milvuspb.MutationResult{Status: merr.Success()}, nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := s.run(ctx)
+	s.source.ch <- testRecord(1)
+	s.source.ch <- testRecord(2)
+	s.Eventually(func() bool {
+		return s.checkpoint() == "2"
+	}, 10*time.Second, 10*time.Millisecond)
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+}
+
+func (s *ConnectorSuite) TestDescribeFailed() {
+	s.writer = mocks.NewMockProxy(s.T())
+	s.writer.EXPECT().DescribeCollection(mock.Anything, mock.Anything).Return(&milvuspb.DescribeCollectionResponse{
+		Status: merr.Status(merr.WrapErrCollectionNotFound("test")),
+	}, nil)
+	s.connector.writer = s.writer
+	s.ErrorIs(s.connector.Run(context.Background()), merr.ErrCollectionNotFound)
+}
+
+func TestConnector(t *testing.T) {
+	suite.Run(t, new(ConnectorSuite))
+}
+
+func TestParseConfigs(t *testing.T) {
+	var params paramtable.ComponentParam
+	params.Init(paramtable.NewBaseTable(paramtable.SkipRemote(true)))
+	prefix := params.ProxyCfg.Connector.Sources.KeyPrefix
+	params.SaveGroup(map[string]string{
+		prefix + "orders.type":       "kafka",
+		prefix + "orders.address":    "localhost:9092",
+		prefix + "orders.topic":      "orders",
+		prefix + "orders.collection": "orders",
+		prefix + "logs.type":         "custom",
+		prefix + "logs.database":     "db1",
+		prefix + "logs.collection":   "logs",
+		prefix + "logs.region":       "us-west-2",
+	})
+
+	configs, err := parseConfigs(&params.ProxyCfg.Connector)
+	assert.NoError(t, err)
+	assert.Len(t, configs, 2)
+	assert.Equal(t, "logs", configs[0].Name)
+	assert.Equal(t, "db1", configs[0].DBName)
+	assert.Equal(t, "us-west-2", configs[0].Options["region"])
+	assert.Equal(t, "orders", configs[1].Name)
+	assert.Equal(t, "default", configs[1].DBName)
+	assert.Equal(t, "localhost:9092", configs[1].Address)
+	assert.Equal(t, "orders", configs[1].Topic)
+	assert.Equal(t, 1000, configs[1].BatchSize)
+	assert.Equal(t, time.Second, configs[1].FlushInterval)
+
+	_, err = newSource(configs[0])
+	assert.Error(t, err)
+	RegisterSource("custom", func(cfg *Config) (Source, error) {
+		return &fakeSource{}, nil
+	})
+	_, err = newSource(configs[0])
+	assert.NoError(t, err)
+
+	params.SaveGroup(map[string]string{prefix + "invalid.type": "kafka"})
+	_, err = parseConfigs(&params.ProxyCfg.Connector)
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connector
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	lockPrefix  = "connector/lock"
+	lockTimeout = 5 * time.Second
+)
+
+// Locker elects the only proxy running a connector.
+type Locker interface {
+	// TryLock returns whether the connector is owned by the caller.
+	TryLock(name string) (bool, error)
+}
+
+// etcdLocker locks the connectors with the lease of the proxy session, so a
+// connector is taken over by another proxy after its owner is down.
+type etcdLocker struct {
+	cli      *clientv3.Client
+	rootPath string
+	leaseID  clientv3.LeaseID
+	owner    string
+}
+
+// NewEtcdLocker creates a locker owning the connectors with the lease.
+func NewEtcdLocker(cli *clientv3.Client, rootPath string, leaseID clientv3.LeaseID, nodeID int64) Locker {
+	return &etcdLocker{
+		cli:      cli,
+		rootPath: rootPath,
+		leaseID:  leaseID,
+		owner:    strconv.FormatInt(nodeID, 10),
+	}
+}
+
+func (l *etcdLocker) TryLock(name string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	key := path.Join(l.rootPath, lockPrefix, name)
+	resp, err := l.cli.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, l.owner, clientv3.WithLease(l.leaseID))).
+		Else(clientv3.OpGet(key)).
+		Commit()
+	if err != nil {
+		return false, err
+	}
+	if resp.Succeeded {
+		return true, nil
+	}
+	kvs := resp.Responses[0].GetResponseRange().GetKvs()
+	return len(kvs) > 0 && string(kvs[0].Value) == l.owner && clientv3.LeaseID(kvs[0].Lease) == l.leaseID, nil
+}
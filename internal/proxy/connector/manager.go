@@ -0,0 +1,117 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const checkInterval = 10 * time.Second
+
+// Manager runs the connectors owned by the proxy, the connectors not owned are
+// checked periodically to take over them from the proxies down.
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	connectors []*Connector
+	locker     Locker
+	running    *typeutil.ConcurrentSet[string]
+}
+
+// NewManager creates the manager of the connectors defined by the config.
+func NewManager(params *paramtable.ConnectorConfig, writer Writer, checkpoints kv.BaseKV, locker Locker) (*Manager, error) {
+	configs, err := parseConfigs(params)
+	if err != nil {
+		return nil, err
+	}
+	connectors := make([]*Connector, 0, len(configs))
+	for _, cfg := range configs {
+		connectors = append(connectors, newConnector(cfg, writer, checkpoints))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		ctx:        ctx,
+		cancel:     cancel,
+		connectors: connectors,
+		locker:     locker,
+		running:    typeutil.NewConcurrentSet[string](),
+	}, nil
+}
+
+func (m *Manager) Start() {
+	if len(m.connectors) == 0 {
+		return
+	}
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			m.check()
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// check starts the connectors owned but not running.
+func (m *Manager) check() {
+	for _, c := range m.connectors {
+		name := c.cfg.Name
+		if m.running.Contain(name) {
+			continue
+		}
+		owned, err := m.locker.TryLock(name)
+		if err != nil {
+			log.Warn("failed to lock connector", zap.String("connector", name), zap.Error(err))
+			continue
+		}
+		if !owned {
+			continue
+		}
+		m.running.Insert(name)
+		m.wg.Add(1)
+		go func(c *Connector) {
+			defer m.wg.Done()
+			defer m.running.Remove(c.cfg.Name)
+			err := c.Run(m.ctx)
+			if m.ctx.Err() == nil {
+				log.Warn("connector stopped, restart it later", zap.String("connector", c.cfg.Name), zap.Error(err))
+			}
+		}(c)
+	}
+}
+
+func (m *Manager) Stop() {
+	m.cancel()
+	m.wg.Wait()
+}
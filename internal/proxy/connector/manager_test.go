@@ -0,0 +1,124 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	memkv "github.com/milvus-io/milvus/internal/kv/mem"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/pkg/util/etcd"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type lockerFunc func(name string) (bool, error)
+
+func (f lockerFunc) TryLock(name string) (bool, error) {
+	return f(name)
+}
+
+func TestManager(t *testing.T) {
+	var params paramtable.ComponentParam
+	params.Init(paramtable.NewBaseTable(paramtable.SkipRemote(true)))
+	prefix := params.ProxyCfg.Connector.Sources.KeyPrefix
+	params.SaveGroup(map[string]string{
+		prefix + "owned.type":         "manager_test",
+		prefix + "owned.collection":   "test",
+		prefix + "unowned.type":       "manager_test",
+		prefix + "unowned.collection": "test",
+	})
+
+	opened := atomic.NewInt32(0)
+	RegisterSource("manager_test", func(cfg *Config) (Source, error) {
+		assert.Equal(t, "owned", cfg.Name)
+		opened.Inc()
+		return &fakeSource{ch: make(chan *Record)}, nil
+	})
+	writer := mocks.NewMockProxy(t)
+	writer.EXPECT().DescribeCollection(mock.Anything, mock.Anything).Return(&milvuspb.DescribeCollectionResponse{
+		Status: merr.Success(),
+		Schema: testSchema(),
+	}, nil)
+	locker := lockerFunc(func(name string) (bool, error) {
+		return name == "owned", nil
+	})
+
+	manager, err := NewManager(&params.ProxyCfg.Connector, writer, memkv.NewMemoryKV(), locker)
+	assert.NoError(t, err)
+	manager.Start()
+	assert.Eventually(t, func() bool {
+		return manager.running.Contain("owned")
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, manager.running.Contain("unowned"))
+	manager.Stop()
+	assert.EqualValues(t, 1, opened.Load())
+}
+
+func TestEtcdLocker(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	cli, err := etcd.GetEtcdClient(
+		params.EtcdCfg.UseEmbedEtcd.GetAsBool(),
+		params.EtcdCfg.EtcdUseSSL.GetAsBool(),
+		params.EtcdCfg.Endpoints.GetAsStrings(),
+		params.EtcdCfg.EtcdTLSCert.GetValue(),
+		params.EtcdCfg.EtcdTLSKey.GetValue(),
+		params.EtcdCfg.EtcdTLSCACert.GetValue(),
+		params.EtcdCfg.EtcdTLSMinVersion.GetValue())
+	assert.NoError(t, err)
+	defer cli.Close()
+
+	ctx := context.Background()
+	rootPath := "/test/connector/" + time.Now().Format(time.RFC3339Nano)
+	defer cli.Delete(ctx, rootPath, clientv3.WithPrefix())
+
+	lease1, err := cli.Grant(ctx, 60)
+	assert.NoError(t, err)
+	lease2, err := cli.Grant(ctx, 60)
+	assert.NoError(t, err)
+	locker1 := NewEtcdLocker(cli, rootPath, lease1.ID, 1)
+	locker2 := NewEtcdLocker(cli, rootPath, lease2.ID, 2)
+
+	owned, err := locker1.TryLock("test")
+	assert.NoError(t, err)
+	assert.True(t, owned)
+	owned, err = locker1.TryLock("test")
+	assert.NoError(t, err)
+	assert.True(t, owned)
+	owned, err = locker2.TryLock("test")
+	assert.NoError(t, err)
+	assert.False(t, owned)
+
+	// taken over after the owner's lease revoked
+	_, err = cli.Revoke(ctx, lease1.ID)
+	assert.NoError(t, err)
+	owned, err = locker2.TryLock("test")
+	assert.NoError(t, err)
+	assert.True(t, owned)
+	owned, err = locker1.TryLock("test")
+	assert.NoError(t, err)
+	assert.False(t, owned)
+}
@@ -0,0 +1,103 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connector
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	jsonrow "github.com/milvus-io/milvus/internal/util/importutilv2/json"
+	"github.com/milvus-io/milvus/pkg/log"
+)
+
+// mapper maps json records to the rows of a collection.
+type mapper struct {
+	schema *schemapb.CollectionSchema
+	parser jsonrow.RowParser
+}
+
+func newMapper(schema *schemapb.CollectionSchema) (*mapper, error) {
+	parser, err := jsonrow.NewRowParser(schema)
+	if err != nil {
+		return nil, err
+	}
+	return &mapper{
+		schema: schema,
+		parser: parser,
+	}, nil
+}
+
+// Map maps the records into the fields data of an insert request, the records
+// not matching the schema are skipped.
+func (m *mapper) Map(records []*Record) ([]*schemapb.FieldData, uint32, error) {
+	rows := make([]jsonrow.Row, 0, len(records))
+	for _, record := range records {
+		row, err := m.parse(record.Value)
+		if err != nil {
+			log.Warn("skip the record not matching the schema", zap.String("collection", m.schema.GetName()),
+				zap.ByteString("record", record.Value), zap.Error(err))
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, 0, nil
+	}
+
+	data, err := storage.NewInsertData(m.schema)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, row := range rows {
+		if err := data.Append(row); err != nil {
+			return nil, 0, err
+		}
+	}
+	record, err := storage.TransferInsertDataToInsertRecord(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	fields := lo.KeyBy(m.schema.GetFields(), func(field *schemapb.FieldSchema) int64 {
+		return field.GetFieldID()
+	})
+	fieldsData := lo.Filter(record.GetFieldsData(), func(fieldData *schemapb.FieldData, _ int) bool {
+		// the auto id primary key has no data
+		return data.Data[fieldData.GetFieldId()].RowNum() == len(rows)
+	})
+	for _, fieldData := range fieldsData {
+		field := fields[fieldData.GetFieldId()]
+		fieldData.FieldName = field.GetName()
+		fieldData.IsDynamic = field.GetIsDynamic()
+	}
+	return fieldsData, uint32(len(rows)), nil
+}
+
+func (m *mapper) parse(value []byte) (jsonrow.Row, error) {
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	var raw any
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return m.parser.Parse(raw)
+}
@@ -0,0 +1,119 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper/kafka"
+)
+
+const mqSourceBufSize = 1024
+
+// mqSource consumes a topic with the message queue client of milvus, the
+// position of a record is its serialized message id.
+type mqSource struct {
+	cfg      *Config
+	client   mqwrapper.Client
+	consumer mqwrapper.Consumer
+	ch       chan *Record
+	closeCh  chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newKafkaSource(cfg *Config) (Source, error) {
+	if cfg.Address == "" || cfg.Topic == "" {
+		return nil, fmt.Errorf("address and topic are required by kafka source of connector %s", cfg.Name)
+	}
+	return newMQSource(cfg, kafka.NewKafkaClientInstance(cfg.Address)), nil
+}
+
+func newMQSource(cfg *Config, client mqwrapper.Client) *mqSource {
+	return &mqSource{
+		cfg:     cfg,
+		client:  client,
+		ch:      make(chan *Record, mqSourceBufSize),
+		closeCh: make(chan struct{}),
+	}
+}
+
+func (s *mqSource) Open(ctx context.Context, position []byte) error {
+	initialPosition := mqwrapper.SubscriptionPositionEarliest
+	if position != nil {
+		initialPosition = mqwrapper.SubscriptionPositionUnknown
+	}
+	consumer, err := s.client.Subscribe(mqwrapper.ConsumerOptions{
+		Topic:                       s.cfg.Topic,
+		SubscriptionName:            "milvus-connector-" + s.cfg.Name,
+		SubscriptionInitialPosition: initialPosition,
+		BufSize:                     mqSourceBufSize,
+	})
+	if err != nil {
+		return err
+	}
+	if position != nil {
+		msgID, err := s.client.BytesToMsgID(position)
+		if err == nil {
+			err = consumer.Seek(msgID, false)
+		}
+		if err != nil {
+			consumer.Close()
+			return err
+		}
+	}
+	s.consumer = consumer
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(s.ch)
+		for {
+			select {
+			case <-s.closeCh:
+				return
+			case msg, ok := <-consumer.Chan():
+				if !ok {
+					return
+				}
+				record := &Record{Value: msg.Payload(), Position: msg.ID().Serialize()}
+				select {
+				case <-s.closeCh:
+					return
+				case s.ch <- record:
+				}
+				consumer.Ack(msg)
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *mqSource) Chan() <-chan *Record {
+	return s.ch
+}
+
+func (s *mqSource) Close() {
+	close(s.closeCh)
+	s.wg.Wait()
+	if s.consumer != nil {
+		s.consumer.Close()
+	}
+	s.client.Close()
+}
@@ -0,0 +1,69 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Record is a record consumed from an external stream.
+type Record struct {
+	// Value is a json row of the collection.
+	Value []byte
+	// Position is where to resume consuming after the record.
+	Position []byte
+}
+
+// Source consumes the records of an external stream.
+type Source interface {
+	// Open starts consuming after the position, from the earliest record if the position is nil.
+	Open(ctx context.Context, position []byte) error
+	// Chan returns the consumed records, it's closed when the source is closed or broken.
+	Chan() <-chan *Record
+	Close()
+}
+
+// SourceFactory creates the source of a connector.
+type SourceFactory func(cfg *Config) (Source, error)
+
+var (
+	sourceMu        sync.RWMutex
+	sourceFactories = map[string]SourceFactory{
+		"kafka": newKafkaSource,
+	}
+)
+
+// RegisterSource registers the factory of a source type, so that the sources of
+// other streaming services can be plugged in.
+func RegisterSource(sourceType string, factory SourceFactory) {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	sourceFactories[strings.ToLower(sourceType)] = factory
+}
+
+func newSource(cfg *Config) (Source, error) {
+	sourceMu.RLock()
+	factory, ok := sourceFactories[strings.ToLower(cfg.Type)]
+	sourceMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown source type %s of connector %s", cfg.Type, cfg.Name)
+	}
+	return factory(cfg)
+}
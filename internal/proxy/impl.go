@@ -2340,6 +2340,7 @@ func (node *Proxy) Insert(ctx context.Context, request *milvuspb.InsertRequest)
 		segIDAssigner: node.segAssigner,
 		chMgr:         node.chMgr,
 		chTicker:      node.chTicker,
+		limiter:       node.multiRateLimiter,
 	}
 
 	constructFailedResponse := func(err error) *milvuspb.MutationResult {
@@ -2439,6 +2440,7 @@ func (node *Proxy) Delete(ctx context.Context, request *milvuspb.DeleteRequest)
 		chTicker:        node.chTicker,
 		queue:           node.sched.dmQueue,
 		lb:              node.lbPolicy,
+		limiter:         node.multiRateLimiter,
 	}
 
 	log.Debug("init delete runner in Proxy")
@@ -2524,6 +2526,7 @@ func (node *Proxy) Upsert(ctx context.Context, request *milvuspb.UpsertRequest)
 		segIDAssigner: node.segAssigner,
 		chMgr:         node.chMgr,
 		chTicker:      node.chTicker,
+		limiter:       node.multiRateLimiter,
 	}
 
 	log.Debug("Enqueue upsert request in Proxy",
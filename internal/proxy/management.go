@@ -17,14 +17,21 @@
 package proxy
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 
+	"google.golang.org/grpc"
+
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	management "github.com/milvus-io/milvus/internal/http"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
 // this file contains proxy management restful API handler
@@ -32,8 +39,34 @@ import (
 const (
 	mgrRouteGcPause  = `/management/datacoord/garbage_collection/pause`
 	mgrRouteGcResume = `/management/datacoord/garbage_collection/resume`
+	mgrRouteGcReport = `/management/datacoord/garbage_collection/report`
+
+	mgrRouteInspectMeta   = `/management/datacoord/meta/inspect`
+	mgrRouteBackup        = `/management/datacoord/backup`
+	mgrRouteRestore       = `/management/datacoord/restore`
+	mgrRoutePublish       = `/management/datacoord/segments/publish`
+	mgrRouteRefreshStats  = `/management/datacoord/segments/stats/refresh`
+	mgrRouteExport        = `/management/datacoord/export`
+	mgrRouteExportState   = `/management/datacoord/export/state`
+	mgrRouteSegmentEvents = `/management/datacoord/segment/events`
+	mgrRouteCompactions   = `/management/datacoord/compaction/tasks`
+
+	mgrRouteMajorCompaction       = `/management/datacoord/compaction/major`
+	mgrRouteMajorCompactionState  = `/management/datacoord/compaction/major/state`
+	mgrRouteMajorCompactionPause  = `/management/datacoord/compaction/major/pause`
+	mgrRouteMajorCompactionResume = `/management/datacoord/compaction/major/resume`
+
+	mgrRouteDecommissionNode  = `/management/node/decommission`
+	mgrRouteDecommissionState = `/management/node/decommission/state`
 )
 
+var mgrInspectTargets = map[string]datapb.MetaInspectTarget{
+	"segments":            datapb.MetaInspectTarget_InspectSegments,
+	"channel_checkpoints": datapb.MetaInspectTarget_InspectChannelCheckpoints,
+	"index_tasks":         datapb.MetaInspectTarget_InspectIndexTasks,
+	"compaction_plans":    datapb.MetaInspectTarget_InspectCompactionPlans,
+}
+
 var mgrRouteRegisterOnce sync.Once
 
 func RegisterMgrRoute(proxy *Proxy) {
@@ -46,6 +79,70 @@ func RegisterMgrRoute(proxy *Proxy) {
 			Path:        mgrRouteGcResume,
 			HandlerFunc: proxy.ResumeDatacoordGC,
 		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteGcReport,
+			HandlerFunc: proxy.GetDatacoordGcReport,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteInspectMeta,
+			HandlerFunc: proxy.InspectDatacoordMeta,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteBackup,
+			HandlerFunc: proxy.BackupCollection,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteRestore,
+			HandlerFunc: proxy.RestoreCollection,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRoutePublish,
+			HandlerFunc: proxy.PublishSegments,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteRefreshStats,
+			HandlerFunc: proxy.RefreshSegmentStats,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteExport,
+			HandlerFunc: proxy.ExportPartition,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteExportState,
+			HandlerFunc: proxy.GetExportState,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteSegmentEvents,
+			HandlerFunc: proxy.GetSegmentEvents,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteCompactions,
+			HandlerFunc: proxy.ListCompactionTasks,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteMajorCompaction,
+			HandlerFunc: proxy.MajorCompaction,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteMajorCompactionState,
+			HandlerFunc: proxy.GetMajorCompactionState,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteMajorCompactionPause,
+			HandlerFunc: proxy.PauseMajorCompaction,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteMajorCompactionResume,
+			HandlerFunc: proxy.ResumeMajorCompaction,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteDecommissionNode,
+			HandlerFunc: proxy.DecommissionNode,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRouteDecommissionState,
+			HandlerFunc: proxy.GetDecommissionState,
+		})
 	})
 }
 
@@ -91,3 +188,624 @@ func (node *Proxy) ResumeDatacoordGC(w http.ResponseWriter, req *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(`{"msg": "OK"}`))
 }
+
+// GetDatacoordGcReport returns the reconciliation report of the object storage against the datacoord meta in json,
+// the storage is reconciled right now if the query param refresh is true.
+func (node *Proxy) GetDatacoordGcReport(w http.ResponseWriter, req *http.Request) {
+	request := &datapb.GetGcReportRequest{
+		Base: commonpbutil.NewMsgBase(),
+	}
+	if refresh := req.URL.Query().Get("refresh"); refresh != "" {
+		v, err := strconv.ParseBool(refresh)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf(`{"msg": "invalid refresh, %s"}`, err.Error())))
+			return
+		}
+		request.Refresh = v
+	}
+
+	resp, err := node.dataCoord.GetGcReport(req.Context(), request)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get garbage collection report, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get garbage collection report, %s"}`, resp.GetStatus().GetReason())))
+		return
+	}
+	bs, err := json.Marshal(resp.GetReport())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to marshal garbage collection report, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs)
+}
+
+// InspectDatacoordMeta returns the decoded datacoord meta in json, the query params are:
+// target (segments, channel_checkpoints, index_tasks or compaction_plans), collection_id,
+// channel and states (comma separated segment states, e.g. Flushed,Growing).
+func (node *Proxy) InspectDatacoordMeta(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	target, ok := mgrInspectTargets[query.Get("target")]
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "invalid inspect target %q"}`, query.Get("target"))))
+		return
+	}
+	request := &datapb.InspectMetaRequest{
+		Base:    commonpbutil.NewMsgBase(),
+		Target:  target,
+		Channel: query.Get("channel"),
+	}
+	if collectionID := query.Get("collection_id"); collectionID != "" {
+		id, err := strconv.ParseInt(collectionID, 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf(`{"msg": "invalid collection id, %s"}`, err.Error())))
+			return
+		}
+		request.CollectionID = id
+	}
+	if states := query.Get("states"); states != "" {
+		for _, state := range strings.Split(states, ",") {
+			value, ok := commonpb.SegmentState_value[state]
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(fmt.Sprintf(`{"msg": "invalid segment state %q"}`, state)))
+				return
+			}
+			request.States = append(request.States, commonpb.SegmentState(value))
+		}
+	}
+
+	resp, err := node.dataCoord.InspectMeta(req.Context(), request)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to inspect datacoord meta, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to inspect datacoord meta, %s"}`, resp.GetStatus().GetReason())))
+		return
+	}
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to marshal datacoord meta, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs)
+}
+
+// BackupCollection flushes a collection and writes its backup manifest, the query params are:
+// name, collection_id and partition_ids (comma separated, empty means all partitions).
+func (node *Proxy) BackupCollection(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	collectionID, err := strconv.ParseInt(query.Get("collection_id"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "invalid collection id, %s"}`, err.Error())))
+		return
+	}
+	request := &datapb.BackupRequest{
+		Base:         commonpbutil.NewMsgBase(),
+		CollectionID: collectionID,
+		Name:         query.Get("name"),
+	}
+	if partitionIDs := query.Get("partition_ids"); partitionIDs != "" {
+		for _, partitionID := range strings.Split(partitionIDs, ",") {
+			id, err := strconv.ParseInt(partitionID, 10, 64)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(fmt.Sprintf(`{"msg": "invalid partition id, %s"}`, err.Error())))
+				return
+			}
+			request.PartitionIDs = append(request.PartitionIDs, id)
+		}
+	}
+
+	resp, err := node.dataCoord.Backup(req.Context(), request)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to backup collection, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to backup collection, %s"}`, resp.GetStatus().GetReason())))
+		return
+	}
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to marshal backup response, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs)
+}
+
+// RestoreCollection restores a backup manifest into an existing collection, the query params are:
+// manifest_path, collection_id and partition_mapping (comma separated backup_partition_id:partition_id pairs).
+func (node *Proxy) RestoreCollection(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	collectionID, err := strconv.ParseInt(query.Get("collection_id"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "invalid collection id, %s"}`, err.Error())))
+		return
+	}
+	request := &datapb.RestoreRequest{
+		Base:             commonpbutil.NewMsgBase(),
+		ManifestPath:     query.Get("manifest_path"),
+		CollectionID:     collectionID,
+		PartitionMapping: make(map[int64]int64),
+	}
+	if mapping := query.Get("partition_mapping"); mapping != "" {
+		for _, pair := range strings.Split(mapping, ",") {
+			from, to, ok := strings.Cut(pair, ":")
+			fromID, fromErr := strconv.ParseInt(from, 10, 64)
+			toID, toErr := strconv.ParseInt(to, 10, 64)
+			if !ok || fromErr != nil || toErr != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(fmt.Sprintf(`{"msg": "invalid partition mapping %q"}`, pair)))
+				return
+			}
+			request.PartitionMapping[fromID] = toID
+		}
+	}
+
+	resp, err := node.dataCoord.Restore(req.Context(), request)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to restore collection, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to restore collection, %s"}`, resp.GetStatus().GetReason())))
+		return
+	}
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to marshal restore response, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs)
+}
+
+// PublishSegments registers the segments of a publish manifest written by an external writer,
+// the query param is manifest_path.
+func (node *Proxy) PublishSegments(w http.ResponseWriter, req *http.Request) {
+	resp, err := node.dataCoord.PublishSegments(req.Context(), &datapb.PublishSegmentsRequest{
+		Base:         commonpbutil.NewMsgBase(),
+		ManifestPath: req.URL.Query().Get("manifest_path"),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to publish segments, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to publish segments, %s"}`, resp.GetStatus().GetReason())))
+		return
+	}
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to marshal publish response, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs)
+}
+
+// RefreshSegmentStats recomputes the statslogs of the sealed segments of segment_ids (comma separated)
+// from their insert binlogs, the progress can be checked by the compaction id returned.
+func (node *Proxy) RefreshSegmentStats(w http.ResponseWriter, req *http.Request) {
+	request := &datapb.RefreshSegmentStatsRequest{
+		Base: commonpbutil.NewMsgBase(),
+	}
+	for _, segmentID := range strings.Split(req.URL.Query().Get("segment_ids"), ",") {
+		id, err := strconv.ParseInt(segmentID, 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf(`{"msg": "invalid segment id, %s"}`, err.Error())))
+			return
+		}
+		request.SegmentIDs = append(request.SegmentIDs, id)
+	}
+
+	resp, err := node.dataCoord.RefreshSegmentStats(req.Context(), request)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to refresh segment stats, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to refresh segment stats, %s"}`, resp.GetStatus().GetReason())))
+		return
+	}
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to marshal refresh segment stats response, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs)
+}
+
+func (node *Proxy) ExportPartition(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	collectionID, err := strconv.ParseInt(query.Get("collection_id"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "invalid collection id, %s"}`, err.Error())))
+		return
+	}
+	partitionID, err := strconv.ParseInt(query.Get("partition_id"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "invalid partition id, %s"}`, err.Error())))
+		return
+	}
+
+	resp, err := node.dataCoord.Export(req.Context(), &datapb.ExportRequest{
+		Base:         commonpbutil.NewMsgBase(),
+		CollectionID: collectionID,
+		PartitionID:  partitionID,
+		TargetPrefix: query.Get("target_prefix"),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to export partition, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to export partition, %s"}`, resp.GetStatus().GetReason())))
+		return
+	}
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to marshal export response, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs)
+}
+
+func (node *Proxy) GetExportState(w http.ResponseWriter, req *http.Request) {
+	jobID, err := strconv.ParseInt(req.URL.Query().Get("job_id"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "invalid job id, %s"}`, err.Error())))
+		return
+	}
+
+	resp, err := node.dataCoord.GetExportState(req.Context(), &datapb.GetExportStateRequest{
+		Base:  commonpbutil.NewMsgBase(),
+		JobID: jobID,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get export state, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get export state, %s"}`, resp.GetStatus().GetReason())))
+		return
+	}
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to marshal export state response, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs)
+}
+
+// GetSegmentEvents returns the lifecycle events of segments, the optional query params are collection_id,
+// segment_id, start_time and end_time (unix milliseconds), and limit to return the latest events only.
+func (node *Proxy) GetSegmentEvents(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	request := &datapb.GetSegmentEventsRequest{
+		Base: commonpbutil.NewMsgBase(),
+	}
+	for _, param := range []struct {
+		name  string
+		value *int64
+	}{
+		{"collection_id", &request.CollectionID},
+		{"segment_id", &request.SegmentID},
+		{"start_time", &request.StartTime},
+		{"end_time", &request.EndTime},
+		{"limit", &request.Limit},
+	} {
+		if value := query.Get(param.name); value != "" {
+			v, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(fmt.Sprintf(`{"msg": "invalid %s, %s"}`, param.name, err.Error())))
+				return
+			}
+			*param.value = v
+		}
+	}
+
+	resp, err := node.dataCoord.GetSegmentEvents(req.Context(), request)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get segment events, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get segment events, %s"}`, resp.GetStatus().GetReason())))
+		return
+	}
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to marshal segment events, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs)
+}
+
+func (node *Proxy) ListCompactionTasks(w http.ResponseWriter, req *http.Request) {
+	request := &datapb.ListCompactionTasksRequest{
+		Base: commonpbutil.NewMsgBase(),
+	}
+	if value := req.URL.Query().Get("collection_id"); value != "" {
+		collectionID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf(`{"msg": "invalid collection_id, %s"}`, err.Error())))
+			return
+		}
+		request.CollectionID = collectionID
+	}
+
+	resp, err := node.dataCoord.ListCompactionTasks(req.Context(), request)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list compaction tasks, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list compaction tasks, %s"}`, resp.GetStatus().GetReason())))
+		return
+	}
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to marshal compaction tasks, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs)
+}
+
+// nodeDecommissioner is the coordinator draining the nodes of a role.
+type nodeDecommissioner interface {
+	DecommissionNode(ctx context.Context, req *datapb.DecommissionNodeRequest, opts ...grpc.CallOption) (*commonpb.Status, error)
+	GetDecommissionState(ctx context.Context, req *datapb.GetDecommissionStateRequest, opts ...grpc.CallOption) (*datapb.GetDecommissionStateResponse, error)
+}
+
+// parseDecommissionParams returns the coordinator and the node id of the role and node_id query params,
+// querynodes are decommissioned by querycoord, datanodes and indexnodes by datacoord.
+func (node *Proxy) parseDecommissionParams(req *http.Request) (nodeDecommissioner, string, int64, error) {
+	query := req.URL.Query()
+	nodeID, err := strconv.ParseInt(query.Get("node_id"), 10, 64)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("invalid node id, %s", err.Error())
+	}
+	role := query.Get("role")
+	switch role {
+	case typeutil.QueryNodeRole:
+		return node.queryCoord, role, nodeID, nil
+	case typeutil.DataNodeRole, typeutil.IndexNodeRole:
+		return node.dataCoord, role, nodeID, nil
+	default:
+		return nil, "", 0, fmt.Errorf("invalid node role %q", role)
+	}
+}
+
+// MajorCompaction starts to merge all the sealed segments of a partition, the query params are collection_id,
+// partition_id and the optional target_size in bytes.
+func (node *Proxy) MajorCompaction(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	collectionID, err := strconv.ParseInt(query.Get("collection_id"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "invalid collection id, %s"}`, err.Error())))
+		return
+	}
+	partitionID, err := strconv.ParseInt(query.Get("partition_id"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "invalid partition id, %s"}`, err.Error())))
+		return
+	}
+	var targetSize int64
+	if value := query.Get("target_size"); value != "" {
+		targetSize, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf(`{"msg": "invalid target size, %s"}`, err.Error())))
+			return
+		}
+	}
+
+	resp, err := node.dataCoord.MajorCompaction(req.Context(), &datapb.MajorCompactionRequest{
+		Base:         commonpbutil.NewMsgBase(),
+		CollectionID: collectionID,
+		PartitionID:  partitionID,
+		TargetSize:   targetSize,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to start major compaction, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to start major compaction, %s"}`, resp.GetStatus().GetReason())))
+		return
+	}
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to marshal major compaction response, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs)
+}
+
+func (node *Proxy) GetMajorCompactionState(w http.ResponseWriter, req *http.Request) {
+	jobID, err := strconv.ParseInt(req.URL.Query().Get("job_id"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "invalid job id, %s"}`, err.Error())))
+		return
+	}
+
+	resp, err := node.dataCoord.GetMajorCompactionState(req.Context(), &datapb.GetMajorCompactionStateRequest{
+		Base:  commonpbutil.NewMsgBase(),
+		JobID: jobID,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get major compaction state, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get major compaction state, %s"}`, resp.GetStatus().GetReason())))
+		return
+	}
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to marshal major compaction state response, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs)
+}
+
+func (node *Proxy) PauseMajorCompaction(w http.ResponseWriter, req *http.Request) {
+	node.alterMajorCompaction(w, req, datapb.MajorCompactionCommand_PauseMajorCompaction)
+}
+
+func (node *Proxy) ResumeMajorCompaction(w http.ResponseWriter, req *http.Request) {
+	node.alterMajorCompaction(w, req, datapb.MajorCompactionCommand_ResumeMajorCompaction)
+}
+
+func (node *Proxy) alterMajorCompaction(w http.ResponseWriter, req *http.Request, command datapb.MajorCompactionCommand) {
+	jobID, err := strconv.ParseInt(req.URL.Query().Get("job_id"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "invalid job id, %s"}`, err.Error())))
+		return
+	}
+
+	resp, err := node.dataCoord.AlterMajorCompaction(req.Context(), &datapb.AlterMajorCompactionRequest{
+		Base:    commonpbutil.NewMsgBase(),
+		JobID:   jobID,
+		Command: command,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to alter major compaction, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to alter major compaction, %s"}`, resp.GetReason())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"msg": "OK"}`))
+}
+
+// DecommissionNode starts to drain the node, the query params are role (querynode, datanode or indexnode)
+// and node_id. The node is safe to terminate once GetDecommissionState reports it Decommissioned.
+func (node *Proxy) DecommissionNode(w http.ResponseWriter, req *http.Request) {
+	coord, role, nodeID, err := node.parseDecommissionParams(req)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "%s"}`, err.Error())))
+		return
+	}
+
+	resp, err := coord.DecommissionNode(req.Context(), &datapb.DecommissionNodeRequest{
+		Base:   commonpbutil.NewMsgBase(),
+		NodeID: nodeID,
+		Role:   role,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to decommission node, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to decommission node, %s"}`, resp.GetReason())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"msg": "OK"}`))
+}
+
+func (node *Proxy) GetDecommissionState(w http.ResponseWriter, req *http.Request) {
+	coord, role, nodeID, err := node.parseDecommissionParams(req)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "%s"}`, err.Error())))
+		return
+	}
+
+	resp, err := coord.GetDecommissionState(req.Context(), &datapb.GetDecommissionStateRequest{
+		Base:   commonpbutil.NewMsgBase(),
+		NodeID: nodeID,
+		Role:   role,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get decommission state, %s"}`, err.Error())))
+		return
+	}
+	if resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get decommission state, %s"}`, resp.GetStatus().GetReason())))
+		return
+	}
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to marshal decommission state response, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs)
+}
@@ -30,24 +30,29 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus/internal/mocks"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
 type ProxyManagementSuite struct {
 	suite.Suite
 
-	datacoord *mocks.MockDataCoordClient
-	proxy     *Proxy
+	datacoord  *mocks.MockDataCoordClient
+	querycoord *mocks.MockQueryCoordClient
+	proxy      *Proxy
 }
 
 func (s *ProxyManagementSuite) SetupTest() {
 	s.datacoord = mocks.NewMockDataCoordClient(s.T())
+	s.querycoord = mocks.NewMockQueryCoordClient(s.T())
 	s.proxy = &Proxy{
-		dataCoord: s.datacoord,
+		dataCoord:  s.datacoord,
+		queryCoord: s.querycoord,
 	}
 }
 
 func (s *ProxyManagementSuite) TearDownTest() {
 	s.datacoord.AssertExpectations(s.T())
+	s.querycoord.AssertExpectations(s.T())
 }
 
 func (s *ProxyManagementSuite) TestPauseDataCoordGC() {
@@ -158,6 +163,1109 @@ func (s *ProxyManagementSuite) TestResumeDatacoordGC() {
 	})
 }
 
+func (s *ProxyManagementSuite) TestGetDatacoordGcReport() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().GetGcReport(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.GetGcReportRequest, options ...grpc.CallOption) (*datapb.GetGcReportResponse, error) {
+			s.True(req.GetRefresh())
+			return &datapb.GetGcReportResponse{
+				Status: &commonpb.Status{},
+				Report: &datapb.GcReport{
+					TotalObjects:  10,
+					OrphanObjects: 1,
+					OrphanSize:    100,
+					Orphans:       []*datapb.GcReportObject{{Path: "files/insert_log/1/2/3/4/5", SegmentID: 3, Size: 100}},
+				},
+			}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteGcReport+"?refresh=true", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetDatacoordGcReport(recorder, req)
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Contains(recorder.Body.String(), `"orphan_objects":1`)
+		s.Contains(recorder.Body.String(), `"path":"files/insert_log/1/2/3/4/5"`)
+	})
+
+	s.Run("invalid_params", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteGcReport+"?refresh=abc", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetDatacoordGcReport(recorder, req)
+
+		s.Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().GetGcReport(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteGcReport, nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetDatacoordGcReport(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().GetGcReport(mock.Anything, mock.Anything).Return(&datapb.GetGcReportResponse{
+			Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Reason:    "mocked",
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteGcReport, nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetDatacoordGcReport(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestInspectDatacoordMeta() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().InspectMeta(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.InspectMetaRequest, options ...grpc.CallOption) (*datapb.InspectMetaResponse, error) {
+			s.Equal(datapb.MetaInspectTarget_InspectSegments, req.GetTarget())
+			s.EqualValues(100, req.GetCollectionID())
+			s.Equal([]commonpb.SegmentState{commonpb.SegmentState_Flushed, commonpb.SegmentState_Growing}, req.GetStates())
+			return &datapb.InspectMetaResponse{
+				Status:   &commonpb.Status{},
+				Segments: []*datapb.SegmentInfo{{ID: 1, CollectionID: 100}},
+			}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteInspectMeta+"?target=segments&collection_id=100&states=Flushed,Growing", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.InspectDatacoordMeta(recorder, req)
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Contains(recorder.Body.String(), `"collectionID":100`)
+	})
+
+	s.Run("invalid_params", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		for _, query := range []string{
+			"?target=unknown",
+			"?target=segments&collection_id=abc",
+			"?target=segments&states=Unknown",
+		} {
+			req, err := http.NewRequest(http.MethodGet, mgrRouteInspectMeta+query, nil)
+			s.Require().NoError(err)
+
+			recorder := httptest.NewRecorder()
+			s.proxy.InspectDatacoordMeta(recorder, req)
+
+			s.Equal(http.StatusBadRequest, recorder.Code, query)
+		}
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().InspectMeta(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteInspectMeta+"?target=compaction_plans", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.InspectDatacoordMeta(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().InspectMeta(mock.Anything, mock.Anything).Return(&datapb.InspectMetaResponse{
+			Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Reason:    "mocked",
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteInspectMeta+"?target=index_tasks", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.InspectDatacoordMeta(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestBackupCollection() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().Backup(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.BackupRequest, options ...grpc.CallOption) (*datapb.BackupResponse, error) {
+			s.Equal("daily", req.GetName())
+			s.EqualValues(100, req.GetCollectionID())
+			s.Equal([]int64{10, 11}, req.GetPartitionIDs())
+			return &datapb.BackupResponse{
+				Status:       &commonpb.Status{},
+				ManifestPath: "files/backup/daily/manifest.json",
+			}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteBackup+"?name=daily&collection_id=100&partition_ids=10,11", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.BackupCollection(recorder, req)
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Contains(recorder.Body.String(), `"manifest_path":"files/backup/daily/manifest.json"`)
+	})
+
+	s.Run("invalid_params", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		for _, query := range []string{
+			"?name=daily",
+			"?name=daily&collection_id=100&partition_ids=abc",
+		} {
+			req, err := http.NewRequest(http.MethodGet, mgrRouteBackup+query, nil)
+			s.Require().NoError(err)
+
+			recorder := httptest.NewRecorder()
+			s.proxy.BackupCollection(recorder, req)
+
+			s.Equal(http.StatusBadRequest, recorder.Code, query)
+		}
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().Backup(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteBackup+"?name=daily&collection_id=100", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.BackupCollection(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().Backup(mock.Anything, mock.Anything).Return(&datapb.BackupResponse{
+			Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Reason:    "mocked",
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteBackup+"?name=daily&collection_id=100", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.BackupCollection(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestRestoreCollection() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().Restore(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.RestoreRequest, options ...grpc.CallOption) (*datapb.RestoreResponse, error) {
+			s.Equal("files/backup/daily/manifest.json", req.GetManifestPath())
+			s.EqualValues(200, req.GetCollectionID())
+			s.Equal(map[int64]int64{10: 20, 11: 21}, req.GetPartitionMapping())
+			return &datapb.RestoreResponse{
+				Status:     &commonpb.Status{},
+				SegmentIDs: []int64{1000},
+			}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteRestore+"?manifest_path=files/backup/daily/manifest.json&collection_id=200&partition_mapping=10:20,11:21", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.RestoreCollection(recorder, req)
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Contains(recorder.Body.String(), `"segmentIDs":[1000]`)
+	})
+
+	s.Run("invalid_params", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		for _, query := range []string{
+			"?manifest_path=manifest.json",
+			"?manifest_path=manifest.json&collection_id=200&partition_mapping=10",
+			"?manifest_path=manifest.json&collection_id=200&partition_mapping=10:a",
+		} {
+			req, err := http.NewRequest(http.MethodGet, mgrRouteRestore+query, nil)
+			s.Require().NoError(err)
+
+			recorder := httptest.NewRecorder()
+			s.proxy.RestoreCollection(recorder, req)
+
+			s.Equal(http.StatusBadRequest, recorder.Code, query)
+		}
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().Restore(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteRestore+"?manifest_path=manifest.json&collection_id=200", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.RestoreCollection(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().Restore(mock.Anything, mock.Anything).Return(&datapb.RestoreResponse{
+			Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Reason:    "mocked",
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteRestore+"?manifest_path=manifest.json&collection_id=200", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.RestoreCollection(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestPublishSegments() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().PublishSegments(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.PublishSegmentsRequest, options ...grpc.CallOption) (*datapb.PublishSegmentsResponse, error) {
+			s.Equal("files/bulk/manifest.json", req.GetManifestPath())
+			return &datapb.PublishSegmentsResponse{
+				Status:     &commonpb.Status{},
+				SegmentIDs: []int64{1000, 1001},
+			}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, mgrRoutePublish+"?manifest_path=files/bulk/manifest.json", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.PublishSegments(recorder, req)
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Contains(recorder.Body.String(), `"segmentIDs":[1000,1001]`)
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().PublishSegments(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRoutePublish+"?manifest_path=manifest.json", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.PublishSegments(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().PublishSegments(mock.Anything, mock.Anything).Return(&datapb.PublishSegmentsResponse{
+			Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Reason:    "mocked",
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRoutePublish+"?manifest_path=manifest.json", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.PublishSegments(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestExportPartition() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().Export(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.ExportRequest, options ...grpc.CallOption) (*datapb.ExportResponse, error) {
+			s.EqualValues(100, req.GetCollectionID())
+			s.EqualValues(10, req.GetPartitionID())
+			s.Equal("export/p10", req.GetTargetPrefix())
+			return &datapb.ExportResponse{
+				Status: &commonpb.Status{},
+				JobID:  1000,
+			}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteExport+"?collection_id=100&partition_id=10&target_prefix=export/p10", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.ExportPartition(recorder, req)
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Contains(recorder.Body.String(), `"jobID":1000`)
+	})
+
+	s.Run("invalid_params", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		for _, query := range []string{
+			"?partition_id=10&target_prefix=export",
+			"?collection_id=100&target_prefix=export",
+		} {
+			req, err := http.NewRequest(http.MethodGet, mgrRouteExport+query, nil)
+			s.Require().NoError(err)
+
+			recorder := httptest.NewRecorder()
+			s.proxy.ExportPartition(recorder, req)
+
+			s.Equal(http.StatusBadRequest, recorder.Code, query)
+		}
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().Export(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteExport+"?collection_id=100&partition_id=10&target_prefix=export", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.ExportPartition(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().Export(mock.Anything, mock.Anything).Return(&datapb.ExportResponse{
+			Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Reason:    "mocked",
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteExport+"?collection_id=100&partition_id=10&target_prefix=export", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.ExportPartition(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestGetExportState() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().GetExportState(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.GetExportStateRequest, options ...grpc.CallOption) (*datapb.GetExportStateResponse, error) {
+			s.EqualValues(1000, req.GetJobID())
+			return &datapb.GetExportStateResponse{
+				Status: &commonpb.Status{},
+				Job: &datapb.ExportJob{
+					JobID: 1000,
+					State: datapb.ExportState_ExportCompleted,
+					Files: []string{"export/p10/1.parquet"},
+				},
+			}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteExportState+"?job_id=1000", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetExportState(recorder, req)
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Contains(recorder.Body.String(), `"files":["export/p10/1.parquet"]`)
+	})
+
+	s.Run("invalid_params", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteExportState+"?job_id=abc", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetExportState(recorder, req)
+
+		s.Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().GetExportState(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteExportState+"?job_id=1000", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetExportState(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().GetExportState(mock.Anything, mock.Anything).Return(&datapb.GetExportStateResponse{
+			Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Reason:    "mocked",
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteExportState+"?job_id=1000", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetExportState(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestGetSegmentEvents() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().GetSegmentEvents(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.GetSegmentEventsRequest, options ...grpc.CallOption) (*datapb.GetSegmentEventsResponse, error) {
+			s.EqualValues(100, req.GetCollectionID())
+			s.EqualValues(1000, req.GetSegmentID())
+			s.EqualValues(1, req.GetStartTime())
+			s.EqualValues(0, req.GetEndTime())
+			s.EqualValues(10, req.GetLimit())
+			return &datapb.GetSegmentEventsResponse{
+				Status: &commonpb.Status{},
+				Events: []*datapb.SegmentEvent{
+					{
+						CollectionID: 100,
+						SegmentID:    1000,
+						FromState:    commonpb.SegmentState_Growing,
+						ToState:      commonpb.SegmentState_Sealed,
+						Actor:        "datacoord",
+					},
+				},
+			}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteSegmentEvents+"?collection_id=100&segment_id=1000&start_time=1&limit=10", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetSegmentEvents(recorder, req)
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Contains(recorder.Body.String(), `"actor":"datacoord"`)
+	})
+
+	s.Run("invalid_params", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteSegmentEvents+"?segment_id=abc", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetSegmentEvents(recorder, req)
+
+		s.Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().GetSegmentEvents(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteSegmentEvents, nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetSegmentEvents(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().GetSegmentEvents(mock.Anything, mock.Anything).Return(&datapb.GetSegmentEventsResponse{
+			Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Reason:    "mocked",
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteSegmentEvents, nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetSegmentEvents(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestListCompactionTasks() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().ListCompactionTasks(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.ListCompactionTasksRequest, options ...grpc.CallOption) (*datapb.ListCompactionTasksResponse, error) {
+			s.EqualValues(100, req.GetCollectionID())
+			return &datapb.ListCompactionTasksResponse{
+				Status: &commonpb.Status{},
+				Queuing: []*datapb.CompactionTaskView{
+					{PlanID: 1, CollectionID: 100, Priority: "merge", State: "pipelining", PreemptedTimes: 1},
+				},
+				FlushPressuredNodes: []int64{1},
+			}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteCompactions+"?collection_id=100", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.ListCompactionTasks(recorder, req)
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Contains(recorder.Body.String(), `"priority":"merge"`)
+		s.Contains(recorder.Body.String(), `"flush_pressured_nodes":[1]`)
+	})
+
+	s.Run("invalid_params", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteCompactions+"?collection_id=abc", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.ListCompactionTasks(recorder, req)
+
+		s.Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().ListCompactionTasks(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteCompactions, nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.ListCompactionTasks(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().ListCompactionTasks(mock.Anything, mock.Anything).Return(&datapb.ListCompactionTasksResponse{
+			Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Reason:    "mocked",
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteCompactions, nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.ListCompactionTasks(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestRefreshSegmentStats() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().RefreshSegmentStats(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.RefreshSegmentStatsRequest, options ...grpc.CallOption) (*datapb.RefreshSegmentStatsResponse, error) {
+			s.Equal([]int64{1, 2}, req.GetSegmentIDs())
+			return &datapb.RefreshSegmentStatsResponse{
+				Status:       &commonpb.Status{},
+				CompactionID: 100,
+				PlanIDs:      []int64{101, 102},
+			}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteRefreshStats+"?segment_ids=1,2", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.RefreshSegmentStats(recorder, req)
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Contains(recorder.Body.String(), `"compactionID":100`)
+	})
+
+	s.Run("invalid_params", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		for _, query := range []string{"", "?segment_ids=1,abc"} {
+			req, err := http.NewRequest(http.MethodGet, mgrRouteRefreshStats+query, nil)
+			s.Require().NoError(err)
+
+			recorder := httptest.NewRecorder()
+			s.proxy.RefreshSegmentStats(recorder, req)
+
+			s.Equal(http.StatusBadRequest, recorder.Code)
+		}
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().RefreshSegmentStats(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteRefreshStats+"?segment_ids=1", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.RefreshSegmentStats(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().RefreshSegmentStats(mock.Anything, mock.Anything).Return(&datapb.RefreshSegmentStatsResponse{
+			Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Reason:    "mocked",
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteRefreshStats+"?segment_ids=1", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.RefreshSegmentStats(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestMajorCompaction() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().MajorCompaction(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.MajorCompactionRequest, options ...grpc.CallOption) (*datapb.MajorCompactionResponse, error) {
+			s.EqualValues(100, req.GetCollectionID())
+			s.EqualValues(10, req.GetPartitionID())
+			s.EqualValues(1024, req.GetTargetSize())
+			return &datapb.MajorCompactionResponse{
+				Status: &commonpb.Status{},
+				JobID:  1,
+			}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteMajorCompaction+"?collection_id=100&partition_id=10&target_size=1024", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.MajorCompaction(recorder, req)
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Contains(recorder.Body.String(), `"jobID":1`)
+	})
+
+	s.Run("invalid_params", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		for _, query := range []string{"?partition_id=10", "?collection_id=100", "?collection_id=100&partition_id=10&target_size=abc"} {
+			req, err := http.NewRequest(http.MethodGet, mgrRouteMajorCompaction+query, nil)
+			s.Require().NoError(err)
+
+			recorder := httptest.NewRecorder()
+			s.proxy.MajorCompaction(recorder, req)
+
+			s.Equal(http.StatusBadRequest, recorder.Code)
+		}
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().MajorCompaction(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteMajorCompaction+"?collection_id=100&partition_id=10", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.MajorCompaction(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().MajorCompaction(mock.Anything, mock.Anything).Return(&datapb.MajorCompactionResponse{
+			Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Reason:    "mocked",
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteMajorCompaction+"?collection_id=100&partition_id=10", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.MajorCompaction(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestGetMajorCompactionState() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().GetMajorCompactionState(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.GetMajorCompactionStateRequest, options ...grpc.CallOption) (*datapb.GetMajorCompactionStateResponse, error) {
+			s.EqualValues(1, req.GetJobID())
+			return &datapb.GetMajorCompactionStateResponse{
+				Status: &commonpb.Status{},
+				Job: &datapb.MajorCompactionJob{
+					JobID:             1,
+					State:             datapb.MajorCompactionState_MajorCompactionRunning,
+					TotalSegments:     10,
+					CompactedSegments: 4,
+				},
+			}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteMajorCompactionState+"?job_id=1", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetMajorCompactionState(recorder, req)
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Contains(recorder.Body.String(), `"compacted_segments":4`)
+	})
+
+	s.Run("invalid_params", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteMajorCompactionState, nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetMajorCompactionState(recorder, req)
+
+		s.Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().GetMajorCompactionState(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteMajorCompactionState+"?job_id=1", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetMajorCompactionState(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().GetMajorCompactionState(mock.Anything, mock.Anything).Return(&datapb.GetMajorCompactionStateResponse{
+			Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Reason:    "mocked",
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteMajorCompactionState+"?job_id=1", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetMajorCompactionState(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestAlterMajorCompaction() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().AlterMajorCompaction(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.AlterMajorCompactionRequest, options ...grpc.CallOption) (*commonpb.Status, error) {
+			s.EqualValues(1, req.GetJobID())
+			s.Equal(datapb.MajorCompactionCommand_PauseMajorCompaction, req.GetCommand())
+			return &commonpb.Status{}, nil
+		}).Once()
+		s.datacoord.EXPECT().AlterMajorCompaction(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.AlterMajorCompactionRequest, options ...grpc.CallOption) (*commonpb.Status, error) {
+			s.Equal(datapb.MajorCompactionCommand_ResumeMajorCompaction, req.GetCommand())
+			return &commonpb.Status{}, nil
+		}).Once()
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteMajorCompactionPause+"?job_id=1", nil)
+		s.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+		s.proxy.PauseMajorCompaction(recorder, req)
+		s.Equal(http.StatusOK, recorder.Code)
+
+		req, err = http.NewRequest(http.MethodGet, mgrRouteMajorCompactionResume+"?job_id=1", nil)
+		s.Require().NoError(err)
+		recorder = httptest.NewRecorder()
+		s.proxy.ResumeMajorCompaction(recorder, req)
+		s.Equal(http.StatusOK, recorder.Code)
+	})
+
+	s.Run("invalid_params", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteMajorCompactionPause+"?job_id=abc", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.PauseMajorCompaction(recorder, req)
+
+		s.Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().AlterMajorCompaction(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteMajorCompactionPause+"?job_id=1", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.PauseMajorCompaction(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().AlterMajorCompaction(mock.Anything, mock.Anything).Return(&commonpb.Status{
+			ErrorCode: commonpb.ErrorCode_UnexpectedError,
+			Reason:    "mocked",
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteMajorCompactionResume+"?job_id=1", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.ResumeMajorCompaction(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestDecommissionNode() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().DecommissionNode(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.DecommissionNodeRequest, options ...grpc.CallOption) (*commonpb.Status, error) {
+			s.EqualValues(1, req.GetNodeID())
+			s.Equal(typeutil.DataNodeRole, req.GetRole())
+			return &commonpb.Status{}, nil
+		})
+		s.querycoord.EXPECT().DecommissionNode(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.DecommissionNodeRequest, options ...grpc.CallOption) (*commonpb.Status, error) {
+			s.EqualValues(2, req.GetNodeID())
+			s.Equal(typeutil.QueryNodeRole, req.GetRole())
+			return &commonpb.Status{}, nil
+		})
+
+		for _, params := range []string{"?role=datanode&node_id=1", "?role=querynode&node_id=2"} {
+			req, err := http.NewRequest(http.MethodGet, mgrRouteDecommissionNode+params, nil)
+			s.Require().NoError(err)
+
+			recorder := httptest.NewRecorder()
+			s.proxy.DecommissionNode(recorder, req)
+
+			s.Equal(http.StatusOK, recorder.Code)
+			s.Equal(`{"msg": "OK"}`, recorder.Body.String())
+		}
+	})
+
+	s.Run("invalid_params", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		for _, params := range []string{"?role=datanode&node_id=abc", "?role=proxy&node_id=1"} {
+			req, err := http.NewRequest(http.MethodGet, mgrRouteDecommissionNode+params, nil)
+			s.Require().NoError(err)
+
+			recorder := httptest.NewRecorder()
+			s.proxy.DecommissionNode(recorder, req)
+
+			s.Equal(http.StatusBadRequest, recorder.Code)
+		}
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.querycoord.EXPECT().DecommissionNode(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteDecommissionNode+"?role=querynode&node_id=2", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.DecommissionNode(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().DecommissionNode(mock.Anything, mock.Anything).Return(&commonpb.Status{
+			ErrorCode: commonpb.ErrorCode_UnexpectedError,
+			Reason:    "mocked",
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteDecommissionNode+"?role=indexnode&node_id=3", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.DecommissionNode(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestGetDecommissionState() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.querycoord.EXPECT().GetDecommissionState(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *datapb.GetDecommissionStateRequest, options ...grpc.CallOption) (*datapb.GetDecommissionStateResponse, error) {
+			s.EqualValues(2, req.GetNodeID())
+			return &datapb.GetDecommissionStateResponse{
+				Status: &commonpb.Status{},
+				Progress: &datapb.DecommissionProgress{
+					NodeID:            2,
+					Role:              typeutil.QueryNodeRole,
+					State:             datapb.DecommissionState_Decommissioning,
+					RemainingSegments: 10,
+				},
+			}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteDecommissionState+"?role=querynode&node_id=2", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetDecommissionState(recorder, req)
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Contains(recorder.Body.String(), `"remaining_segments":10`)
+	})
+
+	s.Run("invalid_params", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteDecommissionState+"?role=querynode", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetDecommissionState(recorder, req)
+
+		s.Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	s.Run("return_error", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().GetDecommissionState(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteDecommissionState+"?role=datanode&node_id=1", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetDecommissionState(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	s.Run("return_failure", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		s.datacoord.EXPECT().GetDecommissionState(mock.Anything, mock.Anything).Return(&datapb.GetDecommissionStateResponse{
+			Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Reason:    "mocked",
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, mgrRouteDecommissionState+"?role=datanode&node_id=1", nil)
+		s.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		s.proxy.GetDecommissionState(recorder, req)
+
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
 func TestProxyManagement(t *testing.T) {
 	suite.Run(t, new(ProxyManagementSuite))
 }
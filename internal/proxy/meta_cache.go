@@ -32,6 +32,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proto/querypb"
 	"github.com/milvus-io/milvus/internal/proto/rootcoordpb"
@@ -114,6 +115,13 @@ type schemaInfo struct {
 	fieldMap             *typeutil.ConcurrentMap[string, int64] // field name to id mapping
 	hasPartitionKeyField bool
 	pkField              *schemapb.FieldSchema
+	// readOnly is set for the external collections, whose rows are the parquet files under the external path
+	readOnly bool
+	// timeBucketField and timeBucketWidth (ms) are set for the collections bucketing segments by time
+	timeBucketField int64
+	timeBucketWidth int64
+	// pkBucketNum is set for the collections bucketing segments by primary key hash
+	pkBucketNum int64
 }
 
 func newSchemaInfo(schema *schemapb.CollectionSchema) *schemaInfo {
@@ -141,6 +149,39 @@ func (s *schemaInfo) MapFieldID(name string) (int64, bool) {
 	return s.fieldMap.Get(name)
 }
 
+func (s *schemaInfo) IsReadOnly() bool {
+	return s.readOnly
+}
+
+// GetTimeBucketField returns the time field of the collection bucketing segments by time.
+func (s *schemaInfo) GetTimeBucketField() (int64, bool) {
+	return s.timeBucketField, s.timeBucketWidth > 0
+}
+
+// GetTimeBucket returns the time bucket of the rows with the time value, nil if not bucketed by time.
+func (s *schemaInfo) GetTimeBucket(value int64) *datapb.TimeBucket {
+	if s.timeBucketWidth <= 0 {
+		return nil
+	}
+	start := value / s.timeBucketWidth * s.timeBucketWidth
+	if start > value {
+		start -= s.timeBucketWidth
+	}
+	return &datapb.TimeBucket{
+		FieldID: s.timeBucketField,
+		Start:   start,
+		End:     start + s.timeBucketWidth,
+	}
+}
+
+// GetPKBucket returns the primary key bucket of the index, nil if not bucketed by primary key.
+func (s *schemaInfo) GetPKBucket(index int64) *datapb.PKBucket {
+	if s.pkBucketNum <= 0 {
+		return nil
+	}
+	return &datapb.PKBucket{Index: index, Num: s.pkBucketNum}
+}
+
 func (s *schemaInfo) IsPartitionKeyCollection() bool {
 	return s.hasPartitionKeyField
 }
@@ -366,9 +407,19 @@ func (m *MetaCache) update(ctx context.Context, database, collectionName string,
 		m.collInfo[database] = make(map[string]*collectionInfo)
 	}
 
+	schema := newSchemaInfo(collection.Schema)
+	externalPath, _ := common.GetExternalPath(collection.GetProperties()...)
+	schema.readOnly = externalPath != ""
+	if fieldName, width, err := common.GetTimeBucket(collection.GetProperties()...); err == nil && fieldName != "" {
+		if fieldID, ok := schema.MapFieldID(fieldName); ok {
+			schema.timeBucketField = fieldID
+			schema.timeBucketWidth = width
+		}
+	}
+	schema.pkBucketNum, _ = common.GetPKBucketNum(collection.GetProperties()...)
 	m.collInfo[database][collectionName] = &collectionInfo{
 		collID:              collection.CollectionID,
-		schema:              newSchemaInfo(collection.Schema),
+		schema:              schema,
 		partInfo:            parsePartitionsInfo(infos),
 		createdTimestamp:    collection.CreatedTimestamp,
 		createdUtcTimestamp: collection.CreatedUtcTimestamp,
@@ -610,6 +661,7 @@ func (m *MetaCache) describeCollection(ctx context.Context, database, collection
 		CreatedUtcTimestamp:  coll.CreatedUtcTimestamp,
 		ConsistencyLevel:     coll.ConsistencyLevel,
 		DbName:               coll.GetDbName(),
+		Properties:           coll.GetProperties(),
 	}
 	for _, field := range coll.Schema.Fields {
 		if field.FieldID >= common.StartOfUserFieldID {
@@ -18,6 +18,7 @@ package proxy
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -29,10 +30,12 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/allocator"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/mq/msgstream"
 	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/retry"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
@@ -101,6 +104,15 @@ func genInsertMsgsByPartition(ctx context.Context,
 	}
 	repackedMsgs = append(repackedMsgs, msg)
 
+	// the checksum of each message is verified by the datanode consuming it
+	if Params.ProxyCfg.InsertChecksumEnabled.GetAsBool() {
+		for _, msg := range repackedMsgs {
+			if err := msgstream.SetInsertChecksum(msg.(*msgstream.InsertMsg)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return repackedMsgs, nil
 }
 
@@ -125,36 +137,119 @@ func repackInsertDataByPartition(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
-	beforeAssign := time.Now()
-	assignedSegmentInfos, err := segIDAssigner.GetSegmentID(insertMsg.CollectionID, partitionID, channelName, uint32(len(rowOffsets)), maxTs)
-	metrics.ProxyAssignSegmentIDLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10)).Observe(float64(time.Since(beforeAssign).Milliseconds()))
+	schema, err := globalMetaCache.GetCollectionSchema(ctx, insertMsg.GetDbName(), insertMsg.CollectionName)
 	if err != nil {
-		log.Error("allocate segmentID for insert data failed",
-			zap.String("collectionName", insertMsg.CollectionName),
-			zap.String("channelName", channelName),
-			zap.Int("allocate count", len(rowOffsets)),
-			zap.Error(err))
 		return nil, err
 	}
 
-	startPos := 0
-	for segmentID, count := range assignedSegmentInfos {
-		subRowOffsets := rowOffsets[startPos : startPos+int(count)]
-		msgs, err := genInsertMsgsByPartition(ctx, segmentID, partitionID, partitionName, subRowOffsets, channelName, insertMsg)
+	buckets, err := groupRowsByBucket(schema, rowOffsets, insertMsg)
+	if err != nil {
+		return nil, err
+	}
+	for _, bucket := range buckets {
+		bucketRowOffsets := bucket.rowOffsets
+		beforeAssign := time.Now()
+		assignedSegmentInfos, err := segIDAssigner.GetSegmentID(insertMsg.CollectionID, partitionID, channelName,
+			uint32(len(bucketRowOffsets)), maxTs, withTimeBucket(bucket.timeBucket), withPKBucket(bucket.pkBucket))
+		metrics.ProxyAssignSegmentIDLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10)).Observe(float64(time.Since(beforeAssign).Milliseconds()))
 		if err != nil {
-			log.Warn("repack insert data to insert msgs failed",
+			log.Error("allocate segmentID for insert data failed",
 				zap.String("collectionName", insertMsg.CollectionName),
-				zap.Int64("partitionID", partitionID),
+				zap.String("channelName", channelName),
+				zap.Int("allocate count", len(bucketRowOffsets)),
 				zap.Error(err))
 			return nil, err
 		}
-		res = append(res, msgs...)
-		startPos += int(count)
+
+		startPos := 0
+		for segmentID, count := range assignedSegmentInfos {
+			subRowOffsets := bucketRowOffsets[startPos : startPos+int(count)]
+			msgs, err := genInsertMsgsByPartition(ctx, segmentID, partitionID, partitionName, subRowOffsets, channelName, insertMsg)
+			if err != nil {
+				log.Warn("repack insert data to insert msgs failed",
+					zap.String("collectionName", insertMsg.CollectionName),
+					zap.Int64("partitionID", partitionID),
+					zap.Error(err))
+				return nil, err
+			}
+			res = append(res, msgs...)
+			startPos += int(count)
+		}
 	}
 
 	return res, nil
 }
 
+// rowBucket is the rows in the same time bucket and primary key bucket, which are assigned to
+// the segments of the buckets.
+type rowBucket struct {
+	timeBucket *datapb.TimeBucket
+	pkBucket   *datapb.PKBucket
+	rowOffsets []int
+}
+
+// groupRowsByBucket groups the rows by the time bucket of the time field and the bucket of the
+// primary key hash, so the rows of different buckets are assigned to different segments. All rows
+// are in the nil buckets if the collection is not bucketed.
+func groupRowsByBucket(schema *schemaInfo, rowOffsets []int, insertMsg *msgstream.InsertMsg) ([]*rowBucket, error) {
+	timeBucketOf := func(offset int) *datapb.TimeBucket { return nil }
+	if fieldID, ok := schema.GetTimeBucketField(); ok {
+		var values []int64
+		for _, fieldData := range insertMsg.GetFieldsData() {
+			if fieldData.GetFieldId() == fieldID {
+				values = fieldData.GetScalars().GetLongData().GetData()
+				break
+			}
+		}
+		if len(values) != int(insertMsg.NRows()) {
+			return nil, merr.WrapErrParameterInvalidMsg("the time bucket field %d of %d rows has %d values",
+				fieldID, insertMsg.NRows(), len(values))
+		}
+		timeBucketOf = func(offset int) *datapb.TimeBucket { return schema.GetTimeBucket(values[offset]) }
+	}
+
+	pkBucketOf := func(offset int) *datapb.PKBucket { return nil }
+	if schema.pkBucketNum > 0 {
+		var pkData *schemapb.FieldData
+		for _, fieldData := range insertMsg.GetFieldsData() {
+			if fieldData.GetFieldId() == schema.pkField.GetFieldID() {
+				pkData = fieldData
+				break
+			}
+		}
+		int64PKs := pkData.GetScalars().GetLongData().GetData()
+		varCharPKs := pkData.GetScalars().GetStringData().GetData()
+		switch {
+		case len(int64PKs) == int(insertMsg.NRows()):
+			pkBucketOf = func(offset int) *datapb.PKBucket {
+				return schema.GetPKBucket(typeutil.HashInt64PK2Bucket(int64PKs[offset], schema.pkBucketNum))
+			}
+		case len(varCharPKs) == int(insertMsg.NRows()):
+			pkBucketOf = func(offset int) *datapb.PKBucket {
+				return schema.GetPKBucket(typeutil.HashVarCharPK2Bucket(varCharPKs[offset], schema.pkBucketNum))
+			}
+		default:
+			return nil, merr.WrapErrParameterInvalidMsg("the primary key of %d rows has %d values",
+				insertMsg.NRows(), len(int64PKs)+len(varCharPKs))
+		}
+	}
+
+	buckets := make([]*rowBucket, 0)
+	key2Bucket := make(map[string]*rowBucket)
+	for _, offset := range rowOffsets {
+		timeBucket, pkBucket := timeBucketOf(offset), pkBucketOf(offset)
+		key := fmt.Sprintf("%d-%d", timeBucket.GetStart(), pkBucket.GetIndex())
+		bucket, ok := key2Bucket[key]
+		if !ok {
+			bucket = &rowBucket{timeBucket: timeBucket, pkBucket: pkBucket}
+			key2Bucket[key] = bucket
+			buckets = append(buckets, bucket)
+		}
+		bucket.rowOffsets = append(bucket.rowOffsets, offset)
+	}
+	return buckets, nil
+}
+
 func setMsgID(ctx context.Context,
 	msgs []msgstream.TsMsg,
 	idAllocator *allocator.IDAllocator,
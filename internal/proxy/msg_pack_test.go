@@ -32,6 +32,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/mq/msgstream"
 	"github.com/milvus-io/milvus/pkg/util/funcutil"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
 func TestRepackInsertData(t *testing.T) {
@@ -54,6 +55,8 @@ func TestRepackInsertData(t *testing.T) {
 		mock.AnythingOfType("string"),
 		mock.AnythingOfType("string"),
 	).Return(int64(1), nil)
+	cache.EXPECT().GetCollectionSchema(mock.Anything, mock.Anything, mock.Anything).
+		Return(newSchemaInfo(&schemapb.CollectionSchema{}), nil)
 	globalMetaCache = cache
 
 	idAllocator, err := allocator.NewIDAllocator(ctx, rc, paramtable.GetNodeID())
@@ -139,6 +142,20 @@ func TestRepackInsertData(t *testing.T) {
 		_, err = repackInsertData(ctx, []string{"test_dml_channel"}, insertMsg, result, idAllocator, segAllocator)
 		assert.NoError(t, err)
 	})
+
+	t.Run("repack insert data with checksum", func(t *testing.T) {
+		paramtable.Get().Save(paramtable.Get().ProxyCfg.InsertChecksumEnabled.Key, "true")
+		defer paramtable.Get().Reset(paramtable.Get().ProxyCfg.InsertChecksumEnabled.Key)
+
+		msgPack, err := repackInsertData(ctx, []string{"test_dml_channel"}, insertMsg, result, idAllocator, segAllocator)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, msgPack.Msgs)
+		for _, msg := range msgPack.Msgs {
+			carried, err := msgstream.VerifyInsertChecksum(msg.(*msgstream.InsertMsg))
+			assert.True(t, carried)
+			assert.NoError(t, err)
+		}
+	})
 }
 
 func TestRepackInsertDataWithPartitionKey(t *testing.T) {
@@ -235,3 +252,81 @@ func TestRepackInsertDataWithPartitionKey(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestGroupRowsByBucket(t *testing.T) {
+	schema := newSchemaInfo(&schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "ts", DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+		},
+	})
+	longField := func(fieldID int64, data []int64) *schemapb.FieldData {
+		return &schemapb.FieldData{
+			FieldId: fieldID,
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: data}},
+			}},
+		}
+	}
+	pks := []int64{1, 2, 3, 4, 5}
+	insertMsg := &msgstream.InsertMsg{
+		InsertRequest: msgpb.InsertRequest{
+			NumRows: 5,
+			FieldsData: []*schemapb.FieldData{
+				longField(100, []int64{5, 1500, -1, 999, 1000}),
+				longField(101, pks),
+			},
+			Version: msgpb.InsertDataVersion_ColumnBased,
+		},
+	}
+	rowOffsets := []int{0, 1, 2, 3, 4}
+
+	buckets, err := groupRowsByBucket(schema, rowOffsets, insertMsg)
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 1)
+	assert.Nil(t, buckets[0].timeBucket)
+	assert.Nil(t, buckets[0].pkBucket)
+	assert.Equal(t, rowOffsets, buckets[0].rowOffsets)
+
+	t.Run("time bucket", func(t *testing.T) {
+		schema.timeBucketField = 100
+		schema.timeBucketWidth = 1000
+		defer func() { schema.timeBucketWidth = 0 }()
+		buckets, err := groupRowsByBucket(schema, rowOffsets, insertMsg)
+		assert.NoError(t, err)
+		assert.Len(t, buckets, 3)
+		assert.EqualValues(t, 0, buckets[0].timeBucket.GetStart())
+		assert.EqualValues(t, 1000, buckets[0].timeBucket.GetEnd())
+		assert.EqualValues(t, 1000, buckets[1].timeBucket.GetStart())
+		assert.EqualValues(t, -1000, buckets[2].timeBucket.GetStart())
+		assert.EqualValues(t, 100, buckets[2].timeBucket.GetFieldID())
+		assert.Equal(t, []int{0, 3}, buckets[0].rowOffsets)
+		assert.Equal(t, []int{1, 4}, buckets[1].rowOffsets)
+		assert.Equal(t, []int{2}, buckets[2].rowOffsets)
+
+		msg := &msgstream.InsertMsg{InsertRequest: msgpb.InsertRequest{NumRows: 5, Version: msgpb.InsertDataVersion_ColumnBased}}
+		_, err = groupRowsByBucket(schema, rowOffsets, msg)
+		assert.Error(t, err)
+	})
+
+	t.Run("pk bucket", func(t *testing.T) {
+		schema.pkBucketNum = 2
+		defer func() { schema.pkBucketNum = 0 }()
+		buckets, err := groupRowsByBucket(schema, rowOffsets, insertMsg)
+		assert.NoError(t, err)
+		rows := 0
+		for _, bucket := range buckets {
+			assert.Nil(t, bucket.timeBucket)
+			assert.EqualValues(t, 2, bucket.pkBucket.GetNum())
+			for _, offset := range bucket.rowOffsets {
+				assert.Equal(t, typeutil.HashInt64PK2Bucket(pks[offset], 2), bucket.pkBucket.GetIndex())
+			}
+			rows += len(bucket.rowOffsets)
+		}
+		assert.Equal(t, 5, rows)
+
+		msg := &msgstream.InsertMsg{InsertRequest: msgpb.InsertRequest{NumRows: 5, Version: msgpb.InsertDataVersion_ColumnBased}}
+		_, err = groupRowsByBucket(schema, rowOffsets, msg)
+		assert.Error(t, err)
+	})
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proto/proxypb"
 	"github.com/milvus-io/milvus/pkg/config"
@@ -116,6 +117,40 @@ func (m *MultiRateLimiter) Check(collectionIDs []int64, rt internalpb.RateType,
 	return ret
 }
 
+// CheckChannels checks if the writing to the channels of collection is denied by the back pressure of DataNodes.
+func (m *MultiRateLimiter) CheckChannels(collectionID int64, channels []string) error {
+	if !Params.QuotaConfig.QuotaAndLimitsEnabled.GetAsBool() {
+		return nil
+	}
+
+	m.quotaStatesMu.RLock()
+	defer m.quotaStatesMu.RUnlock()
+
+	limiter, ok := m.collectionLimiters[collectionID]
+	if !ok {
+		return nil
+	}
+	for _, channel := range channels {
+		if limiter.deniedChannels.Contain(channel) {
+			return merr.WrapErrServiceRateLimit(0, fmt.Sprintf("the writing to channel %s is pressed back by the datanode, please retry later", channel))
+		}
+	}
+	return nil
+}
+
+// checkChannelBackPressure checks if any of the primary keys is hashed to the channel denied by the back pressure,
+// it's checked before the rows are repacked, so that no segment rows or message ids are allocated for the rejected ones.
+func checkChannelBackPressure(limiter *MultiRateLimiter, collectionID int64, pks *schemapb.IDs, channelNames []string) error {
+	if limiter == nil || !Params.QuotaConfig.QuotaAndLimitsEnabled.GetAsBool() {
+		return nil
+	}
+	channels := typeutil.NewSet[string]()
+	for _, channelIdx := range typeutil.HashPK2Channels(pks, channelNames) {
+		channels.Insert(channelNames[channelIdx])
+	}
+	return limiter.CheckChannels(collectionID, channels.Collect())
+}
+
 func isNotCollectionLevelLimitRequest(rt internalpb.RateType) bool {
 	// Most ddl is global level, only DDLFlush will be applied at collection
 	switch rt {
@@ -185,15 +220,17 @@ func (m *MultiRateLimiter) SetRates(rates []*proxypb.CollectionRate) error {
 
 // rateLimiter implements Limiter.
 type rateLimiter struct {
-	limiters    *typeutil.ConcurrentMap[internalpb.RateType, *ratelimitutil.Limiter]
-	quotaStates *typeutil.ConcurrentMap[milvuspb.QuotaState, commonpb.ErrorCode]
+	limiters       *typeutil.ConcurrentMap[internalpb.RateType, *ratelimitutil.Limiter]
+	quotaStates    *typeutil.ConcurrentMap[milvuspb.QuotaState, commonpb.ErrorCode]
+	deniedChannels typeutil.Set[string]
 }
 
 // newRateLimiter returns a new RateLimiter.
 func newRateLimiter(globalLevel bool) *rateLimiter {
 	rl := &rateLimiter{
-		limiters:    typeutil.NewConcurrentMap[internalpb.RateType, *ratelimitutil.Limiter](),
-		quotaStates: typeutil.NewConcurrentMap[milvuspb.QuotaState, commonpb.ErrorCode](),
+		limiters:       typeutil.NewConcurrentMap[internalpb.RateType, *ratelimitutil.Limiter](),
+		quotaStates:    typeutil.NewConcurrentMap[milvuspb.QuotaState, commonpb.ErrorCode](),
+		deniedChannels: typeutil.NewSet[string](),
 	}
 	rl.registerLimiters(globalLevel)
 	return rl
@@ -245,6 +282,13 @@ func (rl *rateLimiter) setRates(collectionRate *proxypb.CollectionRate) error {
 		)
 	}
 
+	rl.deniedChannels = typeutil.NewSet(collectionRate.GetDeniedChannels()...)
+	if len(collectionRate.GetDeniedChannels()) > 0 {
+		log.RatedWarn(30, "Proxy set collection denied channels",
+			zap.Strings("channels", collectionRate.GetDeniedChannels()),
+		)
+	}
+
 	return nil
 }
 
@@ -24,16 +24,20 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proto/proxypb"
 	"github.com/milvus-io/milvus/pkg/util/etcd"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/ratelimitutil"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
 func TestMultiRateLimiter(t *testing.T) {
@@ -207,6 +211,51 @@ func TestMultiRateLimiter(t *testing.T) {
 		assert.Contains(t, codes, GetQuotaErrorString(commonpb.ErrorCode_DiskQuotaExhausted))
 		assert.Contains(t, codes, GetQuotaErrorString(commonpb.ErrorCode_ForceDeny))
 	})
+
+	t.Run("test denied channels", func(t *testing.T) {
+		bak := Params.QuotaConfig.QuotaAndLimitsEnabled.GetValue()
+		paramtable.Get().Save(Params.QuotaConfig.QuotaAndLimitsEnabled.Key, "true")
+		defer paramtable.Get().Save(Params.QuotaConfig.QuotaAndLimitsEnabled.Key, bak)
+
+		multiLimiter := NewMultiRateLimiter()
+		err := multiLimiter.SetRates([]*proxypb.CollectionRate{
+			{
+				Collection:     1,
+				DeniedChannels: []string{"ch-1"},
+			},
+		})
+		assert.NoError(t, err)
+
+		err = multiLimiter.CheckChannels(1, []string{"ch-0", "ch-1"})
+		assert.True(t, errors.Is(err, merr.ErrServiceRateLimit))
+		assert.True(t, merr.IsRetryableErr(err))
+		assert.NoError(t, multiLimiter.CheckChannels(1, []string{"ch-0"}))
+		assert.NoError(t, multiLimiter.CheckChannels(2, []string{"ch-1"}))
+
+		// the primary keys are checked by the channels hashed to
+		channelNames := []string{"ch-0", "ch-1"}
+		pks := &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{}}}
+		for pk := int64(1); pk < 20; pk++ {
+			pks.GetIntId().Data = append(pks.GetIntId().Data, pk)
+		}
+		toCh0 := &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{}}}
+		for i, channelIdx := range typeutil.HashPK2Channels(pks, channelNames) {
+			if channelIdx == 0 {
+				typeutil.AppendIDs(toCh0, pks, i)
+			}
+		}
+		require.NotEmpty(t, toCh0.GetIntId().GetData())
+		require.Less(t, len(toCh0.GetIntId().GetData()), 19)
+		assert.NoError(t, checkChannelBackPressure(multiLimiter, 1, toCh0, channelNames))
+		err = checkChannelBackPressure(multiLimiter, 1, pks, channelNames)
+		assert.True(t, errors.Is(err, merr.ErrServiceRateLimit))
+		assert.NoError(t, checkChannelBackPressure(nil, 1, pks, channelNames))
+
+		// the channels are not denied once the back pressure is gone
+		err = multiLimiter.SetRates([]*proxypb.CollectionRate{{Collection: 1}})
+		assert.NoError(t, err)
+		assert.NoError(t, multiLimiter.CheckChannels(1, []string{"ch-1"}))
+	})
 }
 
 func TestRateLimiter(t *testing.T) {
@@ -33,9 +33,11 @@ import (
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus/internal/allocator"
+	etcdkv "github.com/milvus-io/milvus/internal/kv/etcd"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proxy/accesslog"
 	"github.com/milvus-io/milvus/internal/proxy/connection"
+	"github.com/milvus-io/milvus/internal/proxy/connector"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/internal/util/dependency"
 	"github.com/milvus-io/milvus/internal/util/sessionutil"
@@ -121,6 +123,9 @@ type Proxy struct {
 	// resource manager
 	resourceManager        resource.Manager
 	replicateStreamManager *ReplicateStreamManager
+
+	// runs the connectors inserting the records of external streams
+	connectorManager *connector.Manager
 }
 
 // NewProxy returns a Proxy struct.
@@ -164,6 +169,10 @@ func (node *Proxy) Register() error {
 	node.session.Register()
 	metrics.NumNodes.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), typeutil.ProxyRole).Inc()
 	log.Info("Proxy Register Finished")
+	if err := node.startConnectors(); err != nil {
+		log.Warn("failed to start connectors", zap.Error(err))
+		return err
+	}
 	node.session.LivenessCheck(node.ctx, func() {
 		log.Error("Proxy disconnected from etcd, process will exit", zap.Int64("Server Id", node.session.ServerID))
 		if err := node.Stop(); err != nil {
@@ -181,6 +190,23 @@ func (node *Proxy) Register() error {
 	return nil
 }
 
+// startConnectors starts the connectors if enabled, it requires the lease of the registered session.
+func (node *Proxy) startConnectors() error {
+	if !Params.ProxyCfg.Connector.Enable.GetAsBool() {
+		return nil
+	}
+	rootPath := Params.EtcdCfg.MetaRootPath.GetValue()
+	manager, err := connector.NewManager(&Params.ProxyCfg.Connector, node,
+		etcdkv.NewEtcdKV(node.etcdCli, rootPath),
+		connector.NewEtcdLocker(node.etcdCli, rootPath, *node.session.LeaseID, node.session.ServerID))
+	if err != nil {
+		return err
+	}
+	node.connectorManager = manager
+	node.connectorManager.Start()
+	return nil
+}
+
 // initSession initialize the session of Proxy.
 func (node *Proxy) initSession() error {
 	node.session = sessionutil.NewSession(node.ctx)
@@ -429,6 +455,11 @@ func (node *Proxy) Start() error {
 func (node *Proxy) Stop() error {
 	node.cancel()
 
+	if node.connectorManager != nil {
+		node.connectorManager.Stop()
+		log.Info("close connectors", zap.String("role", typeutil.ProxyRole))
+	}
+
 	if node.rowIDAllocator != nil {
 		node.rowIDAllocator.Close()
 		log.Info("close id allocator", zap.String("role", typeutil.ProxyRole))
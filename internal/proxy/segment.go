@@ -52,6 +52,8 @@ type segRequest struct {
 	partitionID UniqueID
 	segInfo     map[UniqueID]uint32
 	channelName string
+	timeBucket  *datapb.TimeBucket
+	pkBucket    *datapb.PKBucket
 	timestamp   Timestamp
 }
 
@@ -65,6 +67,8 @@ type assignInfo struct {
 	collID         UniqueID
 	partitionID    UniqueID
 	channelName    string
+	timeBucket     *datapb.TimeBucket
+	pkBucket       *datapb.PKBucket
 	segInfos       *list.List
 	lastInsertTime time.Time
 }
@@ -202,6 +206,13 @@ func (sa *segIDAssigner) pickCanDoFunc() {
 		collID := segRequest.collID
 		partitionID := segRequest.partitionID
 		channelName := segRequest.channelName
+		recordKey := channelName
+		if segRequest.timeBucket != nil {
+			recordKey = fmt.Sprintf("%s-%d", recordKey, segRequest.timeBucket.GetStart())
+		}
+		if segRequest.pkBucket != nil {
+			recordKey = fmt.Sprintf("%s-pk%d", recordKey, segRequest.pkBucket.GetIndex())
+		}
 
 		if _, ok := records[collID]; !ok {
 			records[collID] = make(map[UniqueID]map[string]uint32)
@@ -210,18 +221,20 @@ func (sa *segIDAssigner) pickCanDoFunc() {
 			records[collID][partitionID] = make(map[string]uint32)
 		}
 
-		if _, ok := records[collID][partitionID][channelName]; !ok {
-			records[collID][partitionID][channelName] = 0
+		if _, ok := records[collID][partitionID][recordKey]; !ok {
+			records[collID][partitionID][recordKey] = 0
 		}
 
-		records[collID][partitionID][channelName] += segRequest.count
-		assign, err := sa.getAssign(segRequest.collID, segRequest.partitionID, segRequest.channelName)
-		if err != nil || assign.Capacity(segRequest.timestamp) < records[collID][partitionID][channelName] {
+		records[collID][partitionID][recordKey] += segRequest.count
+		assign, err := sa.getAssign(segRequest.collID, segRequest.partitionID, segRequest.channelName, segRequest.timeBucket, segRequest.pkBucket)
+		if err != nil || assign.Capacity(segRequest.timestamp) < records[collID][partitionID][recordKey] {
 			sa.segReqs = append(sa.segReqs, &datapb.SegmentIDRequest{
 				ChannelName:  channelName,
 				Count:        segRequest.count,
 				CollectionID: collID,
 				PartitionID:  partitionID,
+				TimeBucket:   segRequest.timeBucket,
+				PkBucket:     segRequest.pkBucket,
 			})
 			newTodoReqs = append(newTodoReqs, req)
 		} else {
@@ -234,7 +247,9 @@ func (sa *segIDAssigner) pickCanDoFunc() {
 	sa.ToDoReqs = newTodoReqs
 }
 
-func (sa *segIDAssigner) getAssign(collID UniqueID, partitionID UniqueID, channelName string) (*assignInfo, error) {
+func (sa *segIDAssigner) getAssign(collID UniqueID, partitionID UniqueID, channelName string,
+	timeBucket *datapb.TimeBucket, pkBucket *datapb.PKBucket,
+) (*assignInfo, error) {
 	assignInfos, ok := sa.assignInfos[collID]
 	if !ok {
 		return nil, fmt.Errorf("can not find collection %d", collID)
@@ -242,7 +257,8 @@ func (sa *segIDAssigner) getAssign(collID UniqueID, partitionID UniqueID, channe
 
 	for e := assignInfos.Front(); e != nil; e = e.Next() {
 		info := e.Value.(*assignInfo)
-		if info.partitionID != partitionID || info.channelName != channelName {
+		if info.partitionID != partitionID || info.channelName != channelName ||
+			!sameTimeBucket(info.timeBucket, timeBucket) || !samePKBucket(info.pkBucket, pkBucket) {
 			continue
 		}
 		return info, nil
@@ -264,7 +280,24 @@ func (sa *segIDAssigner) checkSegReqEqual(req1, req2 *datapb.SegmentIDRequest) b
 	if req1 == req2 {
 		return true
 	}
-	return req1.CollectionID == req2.CollectionID && req1.PartitionID == req2.PartitionID && req1.ChannelName == req2.ChannelName
+	return req1.CollectionID == req2.CollectionID && req1.PartitionID == req2.PartitionID && req1.ChannelName == req2.ChannelName &&
+		sameTimeBucket(req1.GetTimeBucket(), req2.GetTimeBucket()) && samePKBucket(req1.GetPkBucket(), req2.GetPkBucket())
+}
+
+// samePKBucket checks whether the primary key buckets are the same, nil for the collections not bucketed.
+func samePKBucket(a, b *datapb.PKBucket) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.GetIndex() == b.GetIndex() && a.GetNum() == b.GetNum()
+}
+
+// sameTimeBucket checks whether the time buckets are the same, nil for the collections not bucketed.
+func sameTimeBucket(a, b *datapb.TimeBucket) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.GetFieldID() == b.GetFieldID() && a.GetStart() == b.GetStart() && a.GetEnd() == b.GetEnd()
 }
 
 func (sa *segIDAssigner) reduceSegReqs() {
@@ -339,7 +372,7 @@ func (sa *segIDAssigner) syncSegments() (bool, error) {
 			success = false
 			continue
 		}
-		assign, err := sa.getAssign(segAssign.CollectionID, segAssign.PartitionID, segAssign.ChannelName)
+		assign, err := sa.getAssign(segAssign.CollectionID, segAssign.PartitionID, segAssign.ChannelName, segAssign.GetTimeBucket(), segAssign.GetPkBucket())
 		segInfo2 := &segInfo{
 			segID:      segAssign.SegID,
 			count:      segAssign.Count,
@@ -357,6 +390,8 @@ func (sa *segIDAssigner) syncSegments() (bool, error) {
 				collID:      segAssign.CollectionID,
 				partitionID: segAssign.PartitionID,
 				channelName: segAssign.ChannelName,
+				timeBucket:  segAssign.GetTimeBucket(),
+				pkBucket:    segAssign.GetPkBucket(),
 				segInfos:    segInfos,
 			}
 			colInfos.PushBack(assign)
@@ -374,7 +409,7 @@ func (sa *segIDAssigner) syncSegments() (bool, error) {
 
 func (sa *segIDAssigner) processFunc(req allocator.Request) error {
 	segRequest := req.(*segRequest)
-	assign, err := sa.getAssign(segRequest.collID, segRequest.partitionID, segRequest.channelName)
+	assign, err := sa.getAssign(segRequest.collID, segRequest.partitionID, segRequest.channelName, segRequest.timeBucket, segRequest.pkBucket)
 	if err != nil {
 		return err
 	}
@@ -383,7 +418,28 @@ func (sa *segIDAssigner) processFunc(req allocator.Request) error {
 	return err2
 }
 
-func (sa *segIDAssigner) GetSegmentID(collID UniqueID, partitionID UniqueID, channelName string, count uint32, ts Timestamp) (map[UniqueID]uint32, error) {
+// segIDOption sets the bucket of the rows to assign, the rows of the collections not bucketed
+// are assigned without options.
+type segIDOption func(req *segRequest)
+
+// withTimeBucket assigns the rows in the segments of the time bucket.
+func withTimeBucket(timeBucket *datapb.TimeBucket) segIDOption {
+	return func(req *segRequest) {
+		req.timeBucket = timeBucket
+	}
+}
+
+// withPKBucket assigns the rows in the segments of the primary key bucket.
+func withPKBucket(pkBucket *datapb.PKBucket) segIDOption {
+	return func(req *segRequest) {
+		req.pkBucket = pkBucket
+	}
+}
+
+// GetSegmentID assigns the segments for the rows, the buckets of the rows are set by the options.
+func (sa *segIDAssigner) GetSegmentID(collID UniqueID, partitionID UniqueID, channelName string,
+	count uint32, ts Timestamp, opts ...segIDOption,
+) (map[UniqueID]uint32, error) {
 	req := &segRequest{
 		BaseRequest: allocator.BaseRequest{Done: make(chan error), Valid: false},
 		collID:      collID,
@@ -392,6 +448,9 @@ func (sa *segIDAssigner) GetSegmentID(collID UniqueID, partitionID UniqueID, cha
 		count:       count,
 		timestamp:   ts,
 	}
+	for _, opt := range opts {
+		opt(req)
+	}
 	sa.Reqs <- req
 	if err := req.Wait(); err != nil {
 		return nil, fmt.Errorf("getSegmentID failed: %s", err)
@@ -54,6 +54,7 @@ func (mockD *mockDataCoord) AssignSegmentID(ctx context.Context, req *datapb.Ass
 				CollectionID: r.CollectionID,
 				PartitionID:  r.PartitionID,
 				ExpireTime:   mockD.expireTime,
+				TimeBucket:   r.GetTimeBucket(),
 
 				Status: merr.Success(),
 			}
@@ -312,3 +313,40 @@ func TestSegmentAllocator6(t *testing.T) {
 	wg.Wait()
 	assert.True(t, success)
 }
+
+type mockBucketDataCoord struct {
+	mockDataCoord
+	buckets []*datapb.TimeBucket
+}
+
+func (mockD *mockBucketDataCoord) AssignSegmentID(ctx context.Context, req *datapb.AssignSegmentIDRequest, opts ...grpc.CallOption) (*datapb.AssignSegmentIDResponse, error) {
+	for _, r := range req.GetSegmentIDRequests() {
+		mockD.buckets = append(mockD.buckets, r.GetTimeBucket())
+	}
+	return mockD.mockDataCoord.AssignSegmentID(ctx, req, opts...)
+}
+
+func TestSegmentAllocatorTimeBucket(t *testing.T) {
+	ctx := context.Background()
+	dataCoord := &mockBucketDataCoord{mockDataCoord: mockDataCoord{expireTime: Timestamp(1000)}}
+	segAllocator, err := newSegIDAssigner(ctx, dataCoord, getLastTick1)
+	assert.NoError(t, err)
+	segAllocator.Start()
+
+	bucket1 := &datapb.TimeBucket{FieldID: 100, Start: 0, End: 1000}
+	bucket2 := &datapb.TimeBucket{FieldID: 100, Start: 1000, End: 2000}
+	ret, err := segAllocator.GetSegmentID(1, 1, "abc", 10, 1, withTimeBucket(bucket1))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(10), ret[1])
+	ret, err = segAllocator.GetSegmentID(1, 1, "abc", 10, 1, withTimeBucket(bucket2))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(10), ret[1])
+	segAllocator.Close()
+
+	// the rows of different buckets are never assigned from the same request
+	assert.Len(t, dataCoord.buckets, 2)
+	assert.True(t, sameTimeBucket(bucket1, dataCoord.buckets[0]))
+	assert.True(t, sameTimeBucket(bucket2, dataCoord.buckets[1]))
+	assert.False(t, sameTimeBucket(bucket1, nil))
+	assert.True(t, sameTimeBucket(nil, nil))
+}
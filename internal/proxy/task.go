@@ -344,6 +344,13 @@ func (t *createCollectionTask) PreExecute(ctx context.Context) error {
 				return err
 			}
 		}
+		// validate storage options of the field
+		if err = validateFieldStorageOptions(field); err != nil {
+			return err
+		}
+		if err = validateFieldNullable(field); err != nil {
+			return err
+		}
 	}
 
 	if err := validateMultipleVectorFields(t.schema); err != nil {
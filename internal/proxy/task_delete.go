@@ -55,6 +55,8 @@ type deleteTask struct {
 	partitionID      UniqueID
 	partitionKeyMode bool
 
+	limiter *MultiRateLimiter
+
 	// set by scheduler
 	ts    Timestamp
 	msgID UniqueID
@@ -135,6 +137,11 @@ func (dt *deleteTask) Execute(ctx context.Context) (err error) {
 		return err
 	}
 
+	err = checkChannelBackPressure(dt.limiter, dt.collectionID, dt.primaryKeys, dt.vChannels)
+	if err != nil {
+		return err
+	}
+
 	hashValues := typeutil.HashPK2Channels(dt.primaryKeys, dt.vChannels)
 	// repack delete msg by dmChannel
 	result := make(map[uint32]msgstream.TsMsg)
@@ -245,6 +252,8 @@ type deleteRunner struct {
 
 	// task queue
 	queue *dmTaskQueue
+
+	limiter *MultiRateLimiter
 }
 
 func (dr *deleteRunner) Init(ctx context.Context) error {
@@ -264,6 +273,9 @@ func (dr *deleteRunner) Init(ctx context.Context) error {
 	if err != nil {
 		return ErrWithLog(log, "Failed to get collection schema", err)
 	}
+	if dr.schema.IsReadOnly() {
+		return merr.WrapErrCollectionReadOnly(collName, "could not delete from external collection")
+	}
 
 	dr.partitionKeyMode = dr.schema.IsPartitionKeyCollection()
 	// get partitionIDs of delete
@@ -338,6 +350,7 @@ func (dr *deleteRunner) produce(ctx context.Context, primaryKeys *schemapb.IDs)
 		partitionKeyMode: dr.partitionKeyMode,
 		vChannels:        dr.vChannels,
 		primaryKeys:      primaryKeys,
+		limiter:          dr.limiter,
 	}
 
 	if err := dr.queue.Enqueue(task); err != nil {
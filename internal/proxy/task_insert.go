@@ -37,6 +37,7 @@ type insertTask struct {
 	pChannels     []pChan
 	schema        *schemapb.CollectionSchema
 	partitionKeys *schemapb.FieldData
+	limiter       *MultiRateLimiter
 }
 
 // TraceCtx returns insertTask context
@@ -117,6 +118,9 @@ func (it *insertTask) PreExecute(ctx context.Context) error {
 		log.Warn("get collection schema from global meta cache failed", zap.String("collectionName", collectionName), zap.Error(err))
 		return err
 	}
+	if schema.IsReadOnly() {
+		return merr.WrapErrCollectionReadOnly(collectionName, "could not insert into external collection")
+	}
 	it.schema = schema.CollectionSchema
 
 	rowNums := uint32(it.insertMsg.NRows())
@@ -246,6 +250,13 @@ func (it *insertTask) Execute(ctx context.Context) error {
 		zap.Duration("get cache duration", getCacheDur),
 		zap.Duration("get msgStream duration", getMsgStreamDur))
 
+	err = checkChannelBackPressure(it.limiter, collID, it.result.GetIDs(), channelNames)
+	if err != nil {
+		log.Warn("insert to channels under back pressure", zap.Error(err))
+		it.result.Status = merr.Status(err)
+		return err
+	}
+
 	// assign segmentID for insert data and repack data by segmentID
 	var msgPack *msgstream.MsgPack
 	if it.partitionKeys == nil {
@@ -11,6 +11,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/pkg/mq/msgstream"
+	"github.com/milvus-io/milvus/pkg/util/merr"
 )
 
 func TestInsertTask_CheckAligned(t *testing.T) {
@@ -284,4 +285,22 @@ func TestInsertTask(t *testing.T) {
 		assert.ElementsMatch(t, channels, resChannels)
 		assert.ElementsMatch(t, channels, it.pChannels)
 	})
+	t.Run("external collection", func(t *testing.T) {
+		collectionName := "col_external"
+		schema := newSchemaInfo(&schemapb.CollectionSchema{Name: collectionName})
+		schema.readOnly = true
+		cache := NewMockCache(t)
+		cache.EXPECT().GetCollectionSchema(mock.Anything, mock.Anything, collectionName).Return(schema, nil)
+		globalMetaCache = cache
+		it := insertTask{
+			ctx: context.Background(),
+			insertMsg: &msgstream.InsertMsg{
+				InsertRequest: msgpb.InsertRequest{
+					CollectionName: collectionName,
+				},
+			},
+		}
+		err := it.PreExecute(context.Background())
+		assert.ErrorIs(t, err, merr.ErrCollectionReadOnly)
+	})
 }
@@ -63,6 +63,7 @@ type upsertTask struct {
 	schema           *schemaInfo
 	partitionKeyMode bool
 	partitionKeys    *schemapb.FieldData
+	limiter          *MultiRateLimiter
 }
 
 // TraceCtx returns upsertTask context
@@ -291,6 +292,9 @@ func (it *upsertTask) PreExecute(ctx context.Context) error {
 			zap.Error(err))
 		return err
 	}
+	if schema.IsReadOnly() {
+		return merr.WrapErrCollectionReadOnly(collectionName, "could not upsert into external collection")
+	}
 	it.schema = schema
 
 	it.partitionKeyMode, err = isPartitionKeyMode(ctx, it.req.GetDbName(), collectionName)
@@ -518,6 +522,20 @@ func (it *upsertTask) Execute(ctx context.Context) (err error) {
 	if err != nil {
 		return err
 	}
+	// the rows are inserted to and deleted from the channels hashed by the same primary keys
+	channelNames, err := it.chMgr.getVChannels(it.collectionID)
+	if err != nil {
+		log.Warn("get vChannels failed", zap.Error(err))
+		it.result.Status = merr.Status(err)
+		return err
+	}
+	err = checkChannelBackPressure(it.limiter, it.collectionID, it.result.GetIDs(), channelNames)
+	if err != nil {
+		log.Warn("upsert to channels under back pressure", zap.Error(err))
+		it.result.Status = merr.Status(err)
+		return err
+	}
+
 	msgPack := &msgstream.MsgPack{
 		BeginTs: it.BeginTs(),
 		EndTs:   it.EndTs(),
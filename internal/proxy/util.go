@@ -368,6 +368,41 @@ func validateMaxCapacityPerRow(collectionName string, field *schemapb.FieldSchem
 	return nil
 }
 
+// validateFieldStorageOptions validates the storage options in the type params of the field,
+// the compression codec and the encoding in binlogs and the mmap preference on load.
+func validateFieldStorageOptions(field *schemapb.FieldSchema) error {
+	if _, err := common.GetFieldCompression(field.GetTypeParams()...); err != nil {
+		return merr.WrapErrParameterInvalidMsg(err.Error())
+	}
+	encoding, err := common.GetFieldEncoding(field.GetTypeParams()...)
+	if err != nil {
+		return merr.WrapErrParameterInvalidMsg(err.Error())
+	}
+	if encoding == common.FieldEncodingDelta && !common.IsDeltaEncodingSupported(field.GetDataType()) {
+		return merr.WrapErrParameterInvalidMsg("%s %s is not supported for %s field %s",
+			common.FieldEncodingKey, encoding, field.GetDataType(), field.GetName())
+	}
+	for _, param := range field.GetTypeParams() {
+		if param.GetKey() == common.MmapEnabledKey && param.GetValue() != "true" && param.GetValue() != "false" {
+			return merr.WrapErrParameterInvalidMsg("the value of %s must be true or false", common.MmapEnabledKey)
+		}
+	}
+	return nil
+}
+
+// validateFieldNullable validates the nullable type param of the field. No field could be nullable yet,
+// only the binlogs keep the validity of the fields, the inserts and the segcore don't carry it.
+func validateFieldNullable(field *schemapb.FieldSchema) error {
+	nullable, err := common.IsFieldNullable(field.GetTypeParams()...)
+	if err != nil {
+		return merr.WrapErrParameterInvalidMsg(err.Error())
+	}
+	if nullable {
+		return merr.WrapErrParameterInvalidMsg("field %s couldn't be nullable, which is not supported yet", field.GetName())
+	}
+	return nil
+}
+
 func validateVectorFieldMetricType(field *schemapb.FieldSchema) error {
 	if !isVectorType(field.DataType) {
 		return nil
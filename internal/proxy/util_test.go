@@ -2089,3 +2089,45 @@ func TestSendReplicateMessagePack(t *testing.T) {
 		SendReplicateMessagePack(ctx, mockStream, &milvuspb.ReleasePartitionsRequest{})
 	})
 }
+
+func Test_validateFieldStorageOptions(t *testing.T) {
+	field := func(dataType schemapb.DataType, kvs ...string) *schemapb.FieldSchema {
+		f := &schemapb.FieldSchema{Name: "field", DataType: dataType}
+		for i := 0; i+1 < len(kvs); i += 2 {
+			f.TypeParams = append(f.TypeParams, &commonpb.KeyValuePair{Key: kvs[i], Value: kvs[i+1]})
+		}
+		return f
+	}
+
+	assert.NoError(t, validateFieldStorageOptions(field(schemapb.DataType_FloatVector)))
+	assert.NoError(t, validateFieldStorageOptions(field(schemapb.DataType_FloatVector,
+		common.FieldCompressionKey, common.FieldCompressionNone,
+		common.FieldEncodingKey, common.FieldEncodingPlain,
+		common.MmapEnabledKey, "true")))
+	assert.NoError(t, validateFieldStorageOptions(field(schemapb.DataType_Int64,
+		common.FieldCompressionKey, common.FieldCompressionSnappy,
+		common.FieldEncodingKey, common.FieldEncodingDelta)))
+
+	assert.ErrorIs(t, validateFieldStorageOptions(field(schemapb.DataType_Int64, common.FieldCompressionKey, "lzo")), merr.ErrParameterInvalid)
+	assert.ErrorIs(t, validateFieldStorageOptions(field(schemapb.DataType_Int64, common.FieldEncodingKey, "rle")), merr.ErrParameterInvalid)
+	assert.ErrorIs(t, validateFieldStorageOptions(field(schemapb.DataType_VarChar, common.FieldEncodingKey, common.FieldEncodingDelta)), merr.ErrParameterInvalid)
+	assert.ErrorIs(t, validateFieldStorageOptions(field(schemapb.DataType_Int64, common.MmapEnabledKey, "yes")), merr.ErrParameterInvalid)
+}
+
+func Test_validateFieldNullable(t *testing.T) {
+	field := func(dataType schemapb.DataType, nullable string) *schemapb.FieldSchema {
+		return &schemapb.FieldSchema{
+			Name:       "field",
+			DataType:   dataType,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.FieldNullableKey, Value: nullable}},
+		}
+	}
+
+	assert.NoError(t, validateFieldNullable(&schemapb.FieldSchema{Name: "field", DataType: schemapb.DataType_Int64}))
+	assert.NoError(t, validateFieldNullable(field(schemapb.DataType_FloatVector, "false")))
+
+	assert.ErrorIs(t, validateFieldNullable(field(schemapb.DataType_Int64, "yes")), merr.ErrParameterInvalid)
+	// not supported yet
+	assert.ErrorIs(t, validateFieldNullable(field(schemapb.DataType_VarChar, "true")), merr.ErrParameterInvalid)
+	assert.ErrorIs(t, validateFieldNullable(field(schemapb.DataType_FloatVector, "true")), merr.ErrParameterInvalid)
+}
@@ -22,6 +22,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/querypb"
 	"github.com/milvus-io/milvus/internal/querycoordv2/utils"
 	"github.com/milvus-io/milvus/pkg/log"
@@ -93,3 +94,69 @@ func (s *Server) DeactivateChecker(ctx context.Context, req *querypb.DeactivateC
 	}
 	return merr.Success(), nil
 }
+
+// DecommissionNode marks the QueryNode stopping, then the balance checker moves all its segments
+// and channels to other QueryNodes, the progress can be checked by GetDecommissionState.
+func (s *Server) DecommissionNode(ctx context.Context, req *datapb.DecommissionNodeRequest) (*commonpb.Status, error) {
+	log := log.Ctx(ctx).With(zap.Int64("nodeID", req.GetNodeID()))
+	log.Info("decommission node request received")
+	if err := merr.CheckHealthy(s.State()); err != nil {
+		log.Warn("failed to decommission node", zap.Error(err))
+		return merr.Status(err), nil
+	}
+	if err := s.checkDecommissionNode(req.GetNodeID(), req.GetRole()); err != nil {
+		log.Warn("failed to decommission node", zap.Error(err))
+		return merr.Status(err), nil
+	}
+
+	s.nodeMgr.Stopping(req.GetNodeID())
+	s.checkerController.Check()
+	return merr.Success(), nil
+}
+
+func (s *Server) checkDecommissionNode(nodeID int64, role string) error {
+	if role != typeutil.QueryNodeRole {
+		return merr.WrapErrParameterInvalidMsg("querycoord could not decommission node of role %q", role)
+	}
+	if s.nodeMgr.Get(nodeID) == nil {
+		return merr.WrapErrNodeNotFound(nodeID)
+	}
+	return nil
+}
+
+// GetDecommissionState returns the segments and channels left on the QueryNode,
+// the node is safe to terminate once it's Decommissioned.
+func (s *Server) GetDecommissionState(ctx context.Context, req *datapb.GetDecommissionStateRequest) (*datapb.GetDecommissionStateResponse, error) {
+	log := log.Ctx(ctx).With(zap.Int64("nodeID", req.GetNodeID()))
+	if err := merr.CheckHealthy(s.State()); err != nil {
+		log.Warn("failed to get decommission state", zap.Error(err))
+		return &datapb.GetDecommissionStateResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	if err := s.checkDecommissionNode(req.GetNodeID(), req.GetRole()); err != nil {
+		log.Warn("failed to get decommission state", zap.Error(err))
+		return &datapb.GetDecommissionStateResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+	node := s.nodeMgr.Get(req.GetNodeID())
+
+	progress := &datapb.DecommissionProgress{
+		NodeID: req.GetNodeID(),
+		Role:   typeutil.QueryNodeRole,
+		State:  datapb.DecommissionState_DecommissionNone,
+	}
+	if node != nil && node.IsStoppingState() {
+		progress.RemainingSegments = int64(len(s.dist.SegmentDistManager.GetByNode(req.GetNodeID())))
+		progress.RemainingChannels = int64(len(s.dist.ChannelDistManager.GetByNode(req.GetNodeID())))
+		progress.State = datapb.DecommissionState_Decommissioning
+		if progress.GetRemainingSegments() == 0 && progress.GetRemainingChannels() == 0 {
+			progress.State = datapb.DecommissionState_Decommissioned
+		}
+	}
+	return &datapb.GetDecommissionStateResponse{
+		Status:   merr.Success(),
+		Progress: progress,
+	}, nil
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus/internal/allocator"
+	"github.com/milvus-io/milvus/internal/http/standby"
 	"github.com/milvus-io/milvus/internal/kv"
 	etcdkv "github.com/milvus-io/milvus/internal/kv/etcd"
 	"github.com/milvus-io/milvus/internal/kv/tikv"
@@ -200,6 +201,7 @@ func (s *Server) Init() error {
 			return nil
 		}
 		s.UpdateStateCode(commonpb.StateCode_StandBy)
+		standby.Register(s)
 		log.Info("QueryCoord enter standby mode successfully")
 		return nil
 	}
@@ -405,6 +407,9 @@ func (s *Server) startQueryCoord() error {
 	if err != nil {
 		return err
 	}
+	sessions = lo.PickBy(sessions, func(_ string, node *sessionutil.Session) bool {
+		return s.checkProtocolCompatible(node)
+	})
 	for _, node := range sessions {
 		s.nodeMgr.Add(session.NewNodeInfo(node.ServerID, node.Address))
 		s.taskScheduler.AddExecutor(node.ServerID)
@@ -524,6 +529,11 @@ func (s *Server) State() commonpb.StateCode {
 	return commonpb.StateCode(s.status.Load())
 }
 
+// StandbyStatus returns the active/standby status of QueryCoord.
+func (s *Server) StandbyStatus() *standby.Status {
+	return standby.NewStatus(typeutil.QueryCoordRole, s.session.GetServerID(), s.State())
+}
+
 func (s *Server) GetComponentStates(ctx context.Context, req *milvuspb.GetComponentStatesRequest) (*milvuspb.ComponentStates, error) {
 	log.Debug("QueryCoord current state", zap.String("StateCode", s.State().String()))
 	nodeID := common.NotRegisteredID
@@ -615,6 +625,9 @@ func (s *Server) watchNodes(revision int64) {
 				}
 				return
 			}
+			if !s.checkProtocolCompatible(event.Session) {
+				continue
+			}
 
 			switch event.EventType {
 			case sessionutil.SessionAddEvent:
@@ -652,6 +665,20 @@ func (s *Server) watchNodes(revision int64) {
 	}
 }
 
+// checkProtocolCompatible returns whether the QueryNode of @node could join the cluster,
+// QueryNodes of a protocol version too old are ignored until they are upgraded.
+func (s *Server) checkProtocolCompatible(node *sessionutil.Session) bool {
+	if !sessionutil.IsProtocolCompatible(node.ProtocolVersion) {
+		log.Warn("ignore QueryNode with incompatible protocol version",
+			zap.Int64("nodeID", node.ServerID),
+			zap.String("address", node.Address),
+			zap.Int32("protocolVersion", node.ProtocolVersion),
+			zap.Int32("minCompatibleVersion", sessionutil.MinCompatibleProtocolVersion))
+		return false
+	}
+	return true
+}
+
 func (s *Server) handleNodeUpLoop() {
 	defer s.wg.Done()
 	ticker := time.NewTicker(Params.QueryCoordCfg.CheckHealthInterval.GetAsDuration(time.Millisecond))
@@ -263,6 +263,19 @@ func (suite *ServerSuite) TestNodeUpdate() {
 	}, 5*time.Second, time.Second)
 }
 
+func (suite *ServerSuite) TestCheckProtocolCompatible() {
+	session := &sessionutil.Session{}
+	session.ServerID = 1000
+	session.ProtocolVersion = sessionutil.CurrentProtocolVersion
+	suite.True(suite.server.checkProtocolCompatible(session))
+
+	session.ProtocolVersion = sessionutil.MinCompatibleProtocolVersion
+	suite.True(suite.server.checkProtocolCompatible(session))
+
+	session.ProtocolVersion = sessionutil.MinCompatibleProtocolVersion - 1
+	suite.False(suite.server.checkProtocolCompatible(session))
+}
+
 func (suite *ServerSuite) TestNodeDown() {
 	downNode := suite.nodes[0]
 	downNode.Stop()
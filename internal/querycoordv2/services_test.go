@@ -1465,6 +1465,52 @@ func (suite *ServiceSuite) TestCheckHealth() {
 	suite.Empty(resp.Reasons)
 }
 
+func (suite *ServiceSuite) TestDecommissionNode() {
+	ctx := context.Background()
+	server := suite.server
+	server.checkerController = &checkers.CheckerController{}
+	node := suite.nodes[0]
+
+	// Test for invalid role
+	status, err := server.DecommissionNode(ctx, &datapb.DecommissionNodeRequest{NodeID: node, Role: typeutil.DataNodeRole})
+	suite.NoError(err)
+	suite.ErrorIs(merr.Error(status), merr.ErrParameterInvalid)
+
+	// Test for node not found
+	status, err = server.DecommissionNode(ctx, &datapb.DecommissionNodeRequest{NodeID: 999, Role: typeutil.QueryNodeRole})
+	suite.NoError(err)
+	suite.ErrorIs(merr.Error(status), merr.ErrNodeNotFound)
+
+	resp, err := server.GetDecommissionState(ctx, &datapb.GetDecommissionStateRequest{NodeID: node, Role: typeutil.QueryNodeRole})
+	suite.NoError(merr.CheckRPCCall(resp, err))
+	suite.Equal(datapb.DecommissionState_DecommissionNone, resp.GetProgress().GetState())
+
+	// Test for node still serving segments
+	suite.updateSegmentDist(suite.collections[0], node)
+	status, err = server.DecommissionNode(ctx, &datapb.DecommissionNodeRequest{NodeID: node, Role: typeutil.QueryNodeRole})
+	suite.NoError(merr.CheckRPCCall(status, err))
+	suite.True(suite.nodeMgr.Get(node).IsStoppingState())
+	resp, err = server.GetDecommissionState(ctx, &datapb.GetDecommissionStateRequest{NodeID: node, Role: typeutil.QueryNodeRole})
+	suite.NoError(merr.CheckRPCCall(resp, err))
+	suite.Equal(datapb.DecommissionState_Decommissioning, resp.GetProgress().GetState())
+	suite.NotZero(resp.GetProgress().GetRemainingSegments())
+
+	// Test for node drained
+	suite.dist.SegmentDistManager.Update(node)
+	resp, err = server.GetDecommissionState(ctx, &datapb.GetDecommissionStateRequest{NodeID: node, Role: typeutil.QueryNodeRole})
+	suite.NoError(merr.CheckRPCCall(resp, err))
+	suite.Equal(datapb.DecommissionState_Decommissioned, resp.GetProgress().GetState())
+
+	// Test for server is not healthy
+	server.UpdateStateCode(commonpb.StateCode_Initializing)
+	status, err = server.DecommissionNode(ctx, &datapb.DecommissionNodeRequest{NodeID: node, Role: typeutil.QueryNodeRole})
+	suite.NoError(err)
+	suite.ErrorIs(merr.Error(status), merr.ErrServiceNotReady)
+	resp, err = server.GetDecommissionState(ctx, &datapb.GetDecommissionStateRequest{NodeID: node, Role: typeutil.QueryNodeRole})
+	suite.NoError(err)
+	suite.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrServiceNotReady)
+}
+
 func (suite *ServiceSuite) TestGetShardLeaders() {
 	suite.loadAll()
 	ctx := context.Background()
@@ -335,6 +335,11 @@ func (ex *Executor) subscribeChannel(task *ChannelTask, step int) error {
 			zap.Error(err))
 		return err
 	}
+	err = fillExternalTable(req, action.ChannelName(), collectionInfo)
+	if err != nil {
+		log.Warn("failed to subscribe channel, invalid external path", zap.Error(err))
+		return err
+	}
 
 	ts := dmChannel.GetSeekPosition().GetTimestamp()
 	log.Info("subscribe channel...",
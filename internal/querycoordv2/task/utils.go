@@ -21,7 +21,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/samber/lo"
+
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
@@ -113,10 +116,13 @@ func packLoadSegmentRequest(
 	if task.Source() == utils.LeaderChecker {
 		loadScope = querypb.LoadScope_Delta
 	}
-	// field mmap enabled if collection-level mmap enabled or the field mmap enabled
+	// field mmap enabled if the field mmap enabled, or collection-level mmap enabled and the field has no mmap preference
 	collectionMmapEnabled := common.IsMmapEnabled(collectionProperties...)
 	for _, field := range schema.GetFields() {
-		if collectionMmapEnabled {
+		hasPreference := lo.ContainsBy(field.GetTypeParams(), func(kv *commonpb.KeyValuePair) bool {
+			return kv.GetKey() == common.MmapEnabledKey
+		})
+		if collectionMmapEnabled && !hasPreference {
 			field.TypeParams = append(field.TypeParams, &commonpb.KeyValuePair{
 				Key:   common.MmapEnabledKey,
 				Value: "true",
@@ -218,6 +224,29 @@ func fillSubChannelRequest(
 	return nil
 }
 
+// fillExternalTable assigns the channel its share of the files of an external collection.
+func fillExternalTable(
+	req *querypb.WatchDmChannelsRequest,
+	channel string,
+	collection *milvuspb.DescribeCollectionResponse,
+) error {
+	path, err := common.GetExternalPath(collection.GetProperties()...)
+	if err != nil || path == "" {
+		return err
+	}
+	channels := collection.GetVirtualChannelNames()
+	index := lo.IndexOf(channels, channel)
+	if index < 0 {
+		return fmt.Errorf("channel %s not found in collection %d", channel, collection.GetCollectionID())
+	}
+	req.ExternalTable = &querypb.ExternalTableInfo{
+		Path:       path,
+		ShardIndex: int32(index),
+		ShardNum:   int32(len(channels)),
+	}
+	return nil
+}
+
 func packUnsubDmChannelRequest(task *ChannelTask, action Action) *querypb.UnsubDmChannelRequest {
 	return &querypb.UnsubDmChannelRequest{
 		Base: commonpbutil.NewMsgBase(
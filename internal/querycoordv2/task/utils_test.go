@@ -109,6 +109,11 @@ func (s *UtilsSuite) TestPackLoadSegmentRequestMmap() {
 					DataType:     schemapb.DataType_Int64,
 					IsPrimaryKey: true,
 				},
+				{
+					FieldID:    101,
+					DataType:   schemapb.DataType_Int64,
+					TypeParams: []*commonpb.KeyValuePair{{Key: common.MmapEnabledKey, Value: "false"}},
+				},
 			},
 		},
 		Properties: []*commonpb.KeyValuePair{
@@ -135,9 +140,33 @@ func (s *UtilsSuite) TestPackLoadSegmentRequestMmap() {
 	s.Equal(task.CollectionID(), req.CollectionID)
 	s.Equal(task.ReplicaID(), req.ReplicaID)
 	s.Equal(action.Node(), req.GetDstNodeID())
-	for _, field := range req.GetSchema().GetFields() {
-		s.True(common.IsMmapEnabled(field.GetTypeParams()...))
+	// the field mmap preference takes precedence over the collection's
+	s.True(common.IsFieldMmapEnabled(req.GetSchema(), 100))
+	s.False(common.IsFieldMmapEnabled(req.GetSchema(), 101))
+}
+
+func (s *UtilsSuite) TestFillExternalTable() {
+	collectionInfoResp := &milvuspb.DescribeCollectionResponse{
+		CollectionID:        1,
+		VirtualChannelNames: []string{"ch-0", "ch-1", "ch-2"},
 	}
+
+	req := &querypb.WatchDmChannelsRequest{}
+	s.NoError(fillExternalTable(req, "ch-1", collectionInfoResp))
+	s.Nil(req.GetExternalTable())
+
+	collectionInfoResp.Properties = []*commonpb.KeyValuePair{
+		{
+			Key:   common.CollectionExternalPathKey,
+			Value: "/external/table/",
+		},
+	}
+	s.NoError(fillExternalTable(req, "ch-1", collectionInfoResp))
+	s.Equal("external/table", req.GetExternalTable().GetPath())
+	s.EqualValues(1, req.GetExternalTable().GetShardIndex())
+	s.EqualValues(3, req.GetExternalTable().GetShardNum())
+
+	s.Error(fillExternalTable(&querypb.WatchDmChannelsRequest{}, "ch-3", collectionInfoResp))
 }
 
 func TestUtils(t *testing.T) {
@@ -90,6 +90,8 @@ func PackSegmentLoadInfo(segment *datapb.SegmentInfo, channelCheckpoint *msgpb.M
 		DeltaPosition:  checkpoint,
 		Level:          segment.GetLevel(),
 		StorageVersion: segment.GetStorageVersion(),
+		TimeBucket:     segment.GetTimeBucket(),
+		PkBucket:       segment.GetPkBucket(),
 	}
 	loadInfo.SegmentSize = calculateSegmentSize(loadInfo)
 	return loadInfo
@@ -78,6 +78,7 @@ type ShardDelegator interface {
 
 	// control
 	Serviceable() bool
+	Stopped() bool
 	Start()
 	Close()
 }
@@ -103,7 +104,7 @@ type shardDelegator struct {
 	tsafeManager    tsafe.Manager
 	pkOracle        pkoracle.PkOracle
 	level0Mut       sync.RWMutex
-	level0Deletions map[int64]*storage.DeleteData // partitionID -> deletions
+	level0Deletions map[int64]*storage.SortedDeleteData // partitionID -> deletions sorted by the primary keys
 	// stream delete buffer
 	deleteMut    sync.RWMutex
 	deleteBuffer deletebuffer.DeleteBuffer[*deletebuffer.Item]
@@ -191,6 +192,7 @@ func (sd *shardDelegator) search(ctx context.Context, req *querypb.SearchRequest
 	if req.Req.IgnoreGrowing {
 		growing = []SegmentEntry{}
 	}
+	sealed = pruneSealedSegments(req.GetReq().GetSerializedExprPlan(), sealed)
 
 	sealedNum := lo.SumBy(sealed, func(item SnapshotItem) int { return len(item.Segments) })
 	log.Debug("search segments...",
@@ -422,6 +424,7 @@ func (sd *shardDelegator) QueryStream(ctx context.Context, req *querypb.QueryReq
 	if req.Req.IgnoreGrowing {
 		growing = []SegmentEntry{}
 	}
+	sealed = pruneSealedSegments(req.GetReq().GetSerializedExprPlan(), sealed)
 
 	log.Info("query stream segments...",
 		zap.Int("sealedNum", len(sealed)),
@@ -493,6 +496,7 @@ func (sd *shardDelegator) Query(ctx context.Context, req *querypb.QueryRequest)
 	if req.Req.IgnoreGrowing {
 		growing = []SegmentEntry{}
 	}
+	sealed = pruneSealedSegments(req.GetReq().GetSerializedExprPlan(), sealed)
 
 	sealedNum := lo.SumBy(sealed, func(item SnapshotItem) int { return len(item.Segments) })
 	log.Debug("query segments...",
@@ -805,7 +809,7 @@ func NewShardDelegator(ctx context.Context, collectionID UniqueID, replicaID Uni
 		workerManager:   workerManager,
 		lifetime:        lifetime.NewLifetime(lifetime.Initializing),
 		distribution:    NewDistribution(),
-		level0Deletions: make(map[int64]*storage.DeleteData),
+		level0Deletions: make(map[int64]*storage.SortedDeleteData),
 		deleteBuffer:    deletebuffer.NewListDeleteBuffer[*deletebuffer.Item](startTs, sizePerBlock),
 		pkOracle:        pkoracle.NewPkOracle(),
 		tsafeManager:    tsafeManager,
@@ -189,7 +189,7 @@ func (sd *shardDelegator) ProcessDelete(deleteData []*DeleteData, ts uint64) {
 	delRecords := make(map[int64]DeleteData)
 	for _, data := range deleteData {
 		for i, pk := range data.PrimaryKeys {
-			segmentIDs, err := sd.pkOracle.Get(pk, pkoracle.WithPartitionID(data.PartitionID))
+			segmentIDs, err := sd.pkOracle.Get(pk, pkoracle.WithPartitionID(data.PartitionID), pkoracle.WithPKBucketOf(pk))
 			if err != nil {
 				log.Warn("failed to get delete candidates for pk", zap.Any("pk", pk.GetValue()))
 				continue
@@ -336,7 +336,7 @@ func (sd *shardDelegator) LoadGrowing(ctx context.Context, infos []*querypb.Segm
 		log := log.With(
 			zap.Int64("segmentID", segment.ID()),
 		)
-		deletedPks, deletedTss := sd.GetLevel0Deletions(segment.Partition())
+		deletedPks, deletedTss := sd.GetLevel0Deletions(segment.Partition(), nil)
 		if len(deletedPks) == 0 {
 			continue
 		}
@@ -456,6 +456,9 @@ func (sd *shardDelegator) LoadSegments(ctx context.Context, req *querypb.LoadSeg
 			PartitionID: info.GetPartitionID(),
 			NodeID:      req.GetDstNodeID(),
 			Version:     req.GetVersion(),
+			TimeBucket:  info.GetTimeBucket(),
+			PKBucket:    info.GetPkBucket(),
+			ZoneMaps:    segmentZoneMaps(info),
 		}
 	})
 	if req.GetInfos()[0].GetLevel() == datapb.SegmentLevel_L0 {
@@ -475,48 +478,57 @@ func (sd *shardDelegator) LoadSegments(ctx context.Context, req *querypb.LoadSeg
 	return nil
 }
 
-func (sd *shardDelegator) GetLevel0Deletions(partitionID int64) ([]storage.PrimaryKey, []storage.Timestamp) {
+// GetLevel0Deletions returns the deletions of the L0 segments of the partition and of all the partitions
+// in the order of the timestamps. Only the ones which may exist in the candidate are returned if it's not nil,
+// which are looked up within the primary key range of the candidate by the binary search.
+func (sd *shardDelegator) GetLevel0Deletions(partitionID int64, candidate *pkoracle.BloomFilterSet) ([]storage.PrimaryKey, []storage.Timestamp) {
 	sd.level0Mut.RLock()
-	deleteData, ok1 := sd.level0Deletions[partitionID]
-	allPartitionsDeleteData, ok2 := sd.level0Deletions[common.InvalidPartitionID]
-	sd.level0Mut.RUnlock()
-	// we may need to merge the specified partition deletions and the all partitions deletions,
-	// so release the mutex as early as possible.
-
-	if ok1 && ok2 {
-		pks := make([]storage.PrimaryKey, 0, deleteData.RowCount+allPartitionsDeleteData.RowCount)
-		tss := make([]storage.Timestamp, 0, deleteData.RowCount+allPartitionsDeleteData.RowCount)
-
-		i := 0
-		j := 0
-		for i < int(deleteData.RowCount) || j < int(allPartitionsDeleteData.RowCount) {
-			if i == int(deleteData.RowCount) {
-				pks = append(pks, allPartitionsDeleteData.Pks[j])
-				tss = append(tss, allPartitionsDeleteData.Tss[j])
-				j++
-			} else if j == int(allPartitionsDeleteData.RowCount) {
-				pks = append(pks, deleteData.Pks[i])
-				tss = append(tss, deleteData.Tss[i])
-				i++
-			} else if deleteData.Tss[i] < allPartitionsDeleteData.Tss[j] {
-				pks = append(pks, deleteData.Pks[i])
-				tss = append(tss, deleteData.Tss[i])
-				i++
-			} else {
-				pks = append(pks, allPartitionsDeleteData.Pks[j])
-				tss = append(tss, allPartitionsDeleteData.Tss[j])
-				j++
-			}
+	deletions := make([]*storage.SortedDeleteData, 0, 2)
+	for _, id := range []int64{partitionID, common.InvalidPartitionID} {
+		if deleteData, ok := sd.level0Deletions[id]; ok {
+			deletions = append(deletions, deleteData)
 		}
+	}
+	sd.level0Mut.RUnlock()
+	// the deletions are immutable once generated, so release the mutex as early as possible.
 
-		return pks, tss
-	} else if ok1 {
-		return deleteData.Pks, deleteData.Tss
-	} else if ok2 {
-		return allPartitionsDeleteData.Pks, allPartitionsDeleteData.Tss
+	var minPK, maxPK storage.PrimaryKey
+	if candidate != nil {
+		var ok bool
+		if minPK, maxPK, ok = candidate.PkRange(); !ok {
+			return nil, nil
+		}
 	}
 
-	return nil, nil
+	type DeletePair struct {
+		Pk storage.PrimaryKey
+		Ts storage.Timestamp
+	}
+	pairs := make([]DeletePair, 0)
+	for _, deleteData := range deletions {
+		start, end := 0, deleteData.Len()
+		if candidate != nil {
+			start, end = deleteData.Range(minPK, maxPK)
+		}
+		for i := start; i < end; i++ {
+			if candidate == nil || candidate.MayPkExist(deleteData.Pks[i]) {
+				pairs = append(pairs, DeletePair{deleteData.Pks[i], deleteData.Tss[i]})
+			}
+		}
+	}
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Ts < pairs[j].Ts
+	})
+	pks := make([]storage.PrimaryKey, 0, len(pairs))
+	tss := make([]storage.Timestamp, 0, len(pairs))
+	for _, pair := range pairs {
+		pks = append(pks, pair.Pk)
+		tss = append(tss, pair.Ts)
+	}
+	return pks, tss
 }
 
 func (sd *shardDelegator) GenerateLevel0DeletionCache() {
@@ -534,35 +546,22 @@ func (sd *shardDelegator) GenerateLevel0DeletionCache() {
 		deletions[segment.Partition()] = deleteData
 	}
 
-	type DeletePair struct {
-		Pk storage.PrimaryKey
-		Ts storage.Timestamp
-	}
-	for _, deleteData := range deletions {
-		pairs := make([]DeletePair, deleteData.RowCount)
-		for i := range deleteData.Pks {
-			pairs[i] = DeletePair{deleteData.Pks[i], deleteData.Tss[i]}
-		}
-		sort.Slice(pairs, func(i, j int) bool {
-			return pairs[i].Ts < pairs[j].Ts
-		})
-		for i := range pairs {
-			deleteData.Pks[i], deleteData.Tss[i] = pairs[i].Pk, pairs[i].Ts
-		}
+	// sorted by the primary keys, so the deletions of a segment are looked up by its primary key range
+	sorted := make(map[int64]*storage.SortedDeleteData, len(deletions))
+	totalSize := int64(0)
+	for partitionID, deleteData := range deletions {
+		sorted[partitionID] = storage.NewSortedDeleteData(deleteData)
+		totalSize += deleteData.Size()
 	}
 
 	sd.level0Mut.Lock()
 	defer sd.level0Mut.Unlock()
-	totalSize := int64(0)
-	for _, delete := range deletions {
-		totalSize += delete.Size()
-	}
 	metrics.QueryNodeLevelZeroSize.WithLabelValues(
 		fmt.Sprint(paramtable.GetNodeID()),
 		fmt.Sprint(sd.collectionID),
 		sd.vchannelName,
 	).Set(float64(totalSize))
-	sd.level0Deletions = deletions
+	sd.level0Deletions = sorted
 }
 
 func (sd *shardDelegator) loadStreamDelete(ctx context.Context,
@@ -598,13 +597,7 @@ func (sd *shardDelegator) loadStreamDelete(ctx context.Context,
 			position = deltaPositions[0]
 		}
 
-		deletedPks, deletedTss := sd.GetLevel0Deletions(candidate.Partition())
-		deleteData := &storage.DeleteData{}
-		for i, pk := range deletedPks {
-			if candidate.MayPkExist(pk) {
-				deleteData.Append(pk, deletedTss[i])
-			}
-		}
+		deleteData := storage.NewDeleteData(sd.GetLevel0Deletions(candidate.Partition(), candidate))
 
 		if deleteData.RowCount > 0 {
 			log.Info("forward L0 delete to worker...",
@@ -927,44 +927,60 @@ func (s *DelegatorDataSuite) TestLevel0Deletions() {
 	partitionID := int64(10)
 	partitionDeleteData := storage.NewDeleteData([]storage.PrimaryKey{storage.NewInt64PrimaryKey(1)}, []storage.Timestamp{100})
 	allPartitionDeleteData := storage.NewDeleteData([]storage.PrimaryKey{storage.NewInt64PrimaryKey(2)}, []storage.Timestamp{101})
-	delegator.level0Deletions[partitionID] = partitionDeleteData
+	delegator.level0Deletions[partitionID] = storage.NewSortedDeleteData(partitionDeleteData)
 
-	pks, _ := delegator.GetLevel0Deletions(partitionID)
+	pks, _ := delegator.GetLevel0Deletions(partitionID, nil)
 	s.True(pks[0].EQ(partitionDeleteData.Pks[0]))
 
-	pks, _ = delegator.GetLevel0Deletions(partitionID + 1)
+	pks, _ = delegator.GetLevel0Deletions(partitionID+1, nil)
 	s.Empty(pks)
 
-	delegator.level0Deletions[common.InvalidPartitionID] = allPartitionDeleteData
-	pks, _ = delegator.GetLevel0Deletions(partitionID)
+	delegator.level0Deletions[common.InvalidPartitionID] = storage.NewSortedDeleteData(allPartitionDeleteData)
+	pks, _ = delegator.GetLevel0Deletions(partitionID, nil)
 	s.Len(pks, 2)
 	s.True(pks[0].EQ(partitionDeleteData.Pks[0]))
 	s.True(pks[1].EQ(allPartitionDeleteData.Pks[0]))
 
 	delete(delegator.level0Deletions, partitionID)
-	pks, _ = delegator.GetLevel0Deletions(partitionID)
+	pks, _ = delegator.GetLevel0Deletions(partitionID, nil)
 	s.True(pks[0].EQ(allPartitionDeleteData.Pks[0]))
 
 	// exchange the order
-	delegator.level0Deletions = make(map[int64]*storage.DeleteData)
+	delegator.level0Deletions = make(map[int64]*storage.SortedDeleteData)
 	partitionDeleteData, allPartitionDeleteData = allPartitionDeleteData, partitionDeleteData
-	delegator.level0Deletions[partitionID] = partitionDeleteData
+	delegator.level0Deletions[partitionID] = storage.NewSortedDeleteData(partitionDeleteData)
 
-	pks, _ = delegator.GetLevel0Deletions(partitionID)
+	pks, _ = delegator.GetLevel0Deletions(partitionID, nil)
 	s.True(pks[0].EQ(partitionDeleteData.Pks[0]))
 
-	pks, _ = delegator.GetLevel0Deletions(partitionID + 1)
+	pks, _ = delegator.GetLevel0Deletions(partitionID+1, nil)
 	s.Empty(pks)
 
-	delegator.level0Deletions[common.InvalidPartitionID] = allPartitionDeleteData
-	pks, _ = delegator.GetLevel0Deletions(partitionID)
+	delegator.level0Deletions[common.InvalidPartitionID] = storage.NewSortedDeleteData(allPartitionDeleteData)
+	pks, _ = delegator.GetLevel0Deletions(partitionID, nil)
 	s.Len(pks, 2)
 	s.True(pks[0].EQ(allPartitionDeleteData.Pks[0]))
 	s.True(pks[1].EQ(partitionDeleteData.Pks[0]))
 
 	delete(delegator.level0Deletions, partitionID)
-	pks, _ = delegator.GetLevel0Deletions(partitionID)
+	pks, _ = delegator.GetLevel0Deletions(partitionID, nil)
 	s.True(pks[0].EQ(allPartitionDeleteData.Pks[0]))
+
+	// only the deletions within the pk range of the candidate in the order of the timestamps
+	deleteData := storage.NewDeleteData(nil, nil)
+	for i := int64(0); i < 3000; i++ {
+		deleteData.Append(storage.NewInt64PrimaryKey(i), storage.Timestamp(3000-i))
+	}
+	delegator.level0Deletions = map[int64]*storage.SortedDeleteData{partitionID: storage.NewSortedDeleteData(deleteData)}
+	candidate := pkoracle.NewBloomFilterSet(1, partitionID, commonpb.SegmentState_Sealed)
+	pks, _ = delegator.GetLevel0Deletions(partitionID, candidate)
+	s.Empty(pks)
+	candidate.UpdateBloomFilter([]storage.PrimaryKey{storage.NewInt64PrimaryKey(1500), storage.NewInt64PrimaryKey(2500)})
+	pks, tss := delegator.GetLevel0Deletions(partitionID, candidate)
+	s.GreaterOrEqual(len(pks), 2)
+	s.True(pks[0].EQ(storage.NewInt64PrimaryKey(2500)))
+	s.EqualValues(500, tss[0])
+	s.True(pks[len(pks)-1].EQ(storage.NewInt64PrimaryKey(1500)))
 }
 
 func TestDelegatorDataSuite(t *testing.T) {
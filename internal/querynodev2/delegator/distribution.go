@@ -23,6 +23,7 @@ import (
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
+	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
@@ -84,6 +85,12 @@ type SegmentEntry struct {
 	PartitionID   UniqueID
 	Version       int64
 	TargetVersion int64
+	// TimeBucket is the time range of the rows in the segment, nil if not bucketed by time
+	TimeBucket *datapb.TimeBucket
+	// PKBucket is the primary key bucket of the rows in the segment, nil if not bucketed by primary key
+	PKBucket *datapb.PKBucket
+	// ZoneMaps is the range of the values of the scalar fields in the segment, merged from the zone maps of the binlogs
+	ZoneMaps map[int64]*datapb.ZoneMap
 }
 
 // NewDistribution creates a new distribution instance with all field initialized.
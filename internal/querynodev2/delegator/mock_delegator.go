@@ -733,6 +733,47 @@ func (_c *MockShardDelegator_Start_Call) RunAndReturn(run func()) *MockShardDele
 	return _c
 }
 
+// Stopped provides a mock function with given fields:
+func (_m *MockShardDelegator) Stopped() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockShardDelegator_Stopped_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stopped'
+type MockShardDelegator_Stopped_Call struct {
+	*mock.Call
+}
+
+// Stopped is a helper method to define mock.On call
+func (_e *MockShardDelegator_Expecter) Stopped() *MockShardDelegator_Stopped_Call {
+	return &MockShardDelegator_Stopped_Call{Call: _e.mock.On("Stopped")}
+}
+
+func (_c *MockShardDelegator_Stopped_Call) Run(run func()) *MockShardDelegator_Stopped_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockShardDelegator_Stopped_Call) Return(_a0 bool) *MockShardDelegator_Stopped_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockShardDelegator_Stopped_Call) RunAndReturn(run func() bool) *MockShardDelegator_Stopped_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SyncDistribution provides a mock function with given fields: ctx, entries
 func (_m *MockShardDelegator) SyncDistribution(ctx context.Context, entries ...SegmentEntry) {
 	_va := make([]interface{}, len(entries))
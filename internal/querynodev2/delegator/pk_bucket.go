@@ -0,0 +1,81 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delegator
+
+import (
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// parsePKBuckets returns the primary key buckets which may match the expr,
+// nil if the expr doesn't limit the primary key.
+func parsePKBuckets(expr *planpb.Expr, numBuckets int64) typeutil.Set[int64] {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_BinaryExpr:
+		left := parsePKBuckets(e.BinaryExpr.GetLeft(), numBuckets)
+		right := parsePKBuckets(e.BinaryExpr.GetRight(), numBuckets)
+		switch e.BinaryExpr.GetOp() {
+		case planpb.BinaryExpr_LogicalAnd:
+			if left == nil {
+				return right
+			}
+			if right == nil {
+				return left
+			}
+			return left.Intersection(right)
+		case planpb.BinaryExpr_LogicalOr:
+			if left == nil || right == nil {
+				return nil
+			}
+			return left.Union(right)
+		}
+	case *planpb.Expr_UnaryRangeExpr:
+		if !e.UnaryRangeExpr.GetColumnInfo().GetIsPrimaryKey() || e.UnaryRangeExpr.GetOp() != planpb.OpType_Equal {
+			return nil
+		}
+		bucket, ok := pkBucketOfValue(e.UnaryRangeExpr.GetValue(), numBuckets)
+		if !ok {
+			return nil
+		}
+		return typeutil.NewSet(bucket)
+	case *planpb.Expr_TermExpr:
+		if !e.TermExpr.GetColumnInfo().GetIsPrimaryKey() || e.TermExpr.GetIsInField() {
+			return nil
+		}
+		buckets := typeutil.NewSet[int64]()
+		for _, value := range e.TermExpr.GetValues() {
+			bucket, ok := pkBucketOfValue(value, numBuckets)
+			if !ok {
+				return nil
+			}
+			buckets.Insert(bucket)
+		}
+		return buckets
+	}
+	return nil
+}
+
+func pkBucketOfValue(value *planpb.GenericValue, numBuckets int64) (int64, bool) {
+	switch v := value.GetVal().(type) {
+	case *planpb.GenericValue_Int64Val:
+		return typeutil.HashInt64PK2Bucket(v.Int64Val, numBuckets), true
+	case *planpb.GenericValue_StringVal:
+		return typeutil.HashVarCharPK2Bucket(v.StringVal, numBuckets), true
+	default:
+		return 0, false
+	}
+}
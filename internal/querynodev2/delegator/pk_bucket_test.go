@@ -0,0 +1,66 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delegator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+func pkTerm(values ...int64) *planpb.Expr {
+	genericValues := make([]*planpb.GenericValue, 0, len(values))
+	for _, v := range values {
+		genericValues = append(genericValues, int64Val(v))
+	}
+	return &planpb.Expr{Expr: &planpb.Expr_TermExpr{TermExpr: &planpb.TermExpr{
+		ColumnInfo: &planpb.ColumnInfo{FieldId: 100, IsPrimaryKey: true},
+		Values:     genericValues,
+	}}}
+}
+
+func TestParsePKBuckets(t *testing.T) {
+	const numBuckets = 16
+	bucketOf := func(pk int64) int64 { return typeutil.HashInt64PK2Bucket(pk, numBuckets) }
+
+	assert.Nil(t, parsePKBuckets(nil, numBuckets))
+	assert.Nil(t, parsePKBuckets(unaryRange(101, planpb.OpType_Equal, 1), numBuckets))
+
+	buckets := parsePKBuckets(pkTerm(1, 2), numBuckets)
+	assert.ElementsMatch(t, typeutil.NewSet(bucketOf(1), bucketOf(2)).Collect(), buckets.Collect())
+
+	equal := &planpb.Expr{Expr: &planpb.Expr_UnaryRangeExpr{UnaryRangeExpr: &planpb.UnaryRangeExpr{
+		ColumnInfo: &planpb.ColumnInfo{FieldId: 100, IsPrimaryKey: true},
+		Op:         planpb.OpType_Equal,
+		Value:      &planpb.GenericValue{Val: &planpb.GenericValue_StringVal{StringVal: "pk"}},
+	}}}
+	buckets = parsePKBuckets(equal, numBuckets)
+	assert.ElementsMatch(t, []int64{typeutil.HashVarCharPK2Bucket("pk", numBuckets)}, buckets.Collect())
+
+	// the other conditions don't limit the primary key
+	buckets = parsePKBuckets(logical(planpb.BinaryExpr_LogicalAnd, pkTerm(1), unaryRange(101, planpb.OpType_Equal, 1)), numBuckets)
+	assert.ElementsMatch(t, []int64{bucketOf(1)}, buckets.Collect())
+	assert.Nil(t, parsePKBuckets(logical(planpb.BinaryExpr_LogicalOr, pkTerm(1), unaryRange(101, planpb.OpType_Equal, 1)), numBuckets))
+
+	buckets = parsePKBuckets(logical(planpb.BinaryExpr_LogicalOr, pkTerm(1), pkTerm(2)), numBuckets)
+	assert.ElementsMatch(t, typeutil.NewSet(bucketOf(1), bucketOf(2)).Collect(), buckets.Collect())
+	buckets = parsePKBuckets(logical(planpb.BinaryExpr_LogicalAnd, pkTerm(1), pkTerm(1, 2)), numBuckets)
+	assert.ElementsMatch(t, []int64{bucketOf(1)}, buckets.Collect())
+}
@@ -0,0 +1,87 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delegator
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// pruneSealedSegments skips the sealed segments whose time bucket, primary key bucket or zone maps can't match
+// the filter of the plan, the pinned snapshot items are not modified.
+func pruneSealedSegments(serializedPlan []byte, sealed []SnapshotItem) []SnapshotItem {
+	bucketed := lo.ContainsBy(sealed, func(item SnapshotItem) bool {
+		return lo.ContainsBy(item.Segments, func(segment SegmentEntry) bool {
+			return segment.TimeBucket != nil || segment.PKBucket != nil || segment.ZoneMaps != nil
+		})
+	})
+	if !bucketed || len(serializedPlan) == 0 {
+		return sealed
+	}
+	plan := &planpb.PlanNode{}
+	if err := proto.Unmarshal(serializedPlan, plan); err != nil {
+		return sealed
+	}
+	var expr *planpb.Expr
+	switch node := plan.GetNode().(type) {
+	case *planpb.PlanNode_VectorAnns:
+		expr = node.VectorAnns.GetPredicates()
+	case *planpb.PlanNode_Query:
+		expr = node.Query.GetPredicates()
+	default:
+		return sealed
+	}
+
+	timeRanges := make(map[int64]timeRange)
+	pkBuckets := make(map[int64]typeutil.Set[int64])
+	matchTimeBucket := func(segment SegmentEntry) bool {
+		bucket := segment.TimeBucket
+		if bucket == nil {
+			return true
+		}
+		r, ok := timeRanges[bucket.GetFieldID()]
+		if !ok {
+			r = parseTimeRange(expr, bucket.GetFieldID())
+			timeRanges[bucket.GetFieldID()] = r
+		}
+		return !r.intersect(timeRange{lower: bucket.GetStart(), upper: bucket.GetEnd() - 1}).isEmpty()
+	}
+	matchPKBucket := func(segment SegmentEntry) bool {
+		bucket := segment.PKBucket
+		if bucket == nil {
+			return true
+		}
+		buckets, ok := pkBuckets[bucket.GetNum()]
+		if !ok {
+			buckets = parsePKBuckets(expr, bucket.GetNum())
+			pkBuckets[bucket.GetNum()] = buckets
+		}
+		return buckets == nil || buckets.Contain(bucket.GetIndex())
+	}
+
+	result := make([]SnapshotItem, 0, len(sealed))
+	for _, item := range sealed {
+		segments := lo.Filter(item.Segments, func(segment SegmentEntry, _ int) bool {
+			return matchTimeBucket(segment) && matchPKBucket(segment) && zoneMapsMayMatch(expr, segment.ZoneMaps)
+		})
+		result = append(result, SnapshotItem{NodeID: item.NodeID, Segments: segments})
+	}
+	return result
+}
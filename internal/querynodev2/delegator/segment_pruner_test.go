@@ -0,0 +1,127 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delegator
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+type SegmentPrunerSuite struct {
+	suite.Suite
+}
+
+func (s *SegmentPrunerSuite) segmentIDs(items []SnapshotItem) []int64 {
+	return lo.FlatMap(items, func(item SnapshotItem, _ int) []int64 {
+		return lo.Map(item.Segments, func(segment SegmentEntry, _ int) int64 { return segment.SegmentID })
+	})
+}
+
+func (s *SegmentPrunerSuite) serialize(expr *planpb.Expr) []byte {
+	bs, err := proto.Marshal(&planpb.PlanNode{Node: &planpb.PlanNode_Query{Query: &planpb.QueryPlanNode{Predicates: expr}}})
+	s.Require().NoError(err)
+	return bs
+}
+
+func (s *SegmentPrunerSuite) TestPruneTimeBuckets() {
+	bucket := func(start int64) *datapb.TimeBucket {
+		return &datapb.TimeBucket{FieldID: testTimeField, Start: start, End: start + 100}
+	}
+	sealed := []SnapshotItem{
+		{
+			NodeID: 1,
+			Segments: []SegmentEntry{
+				{SegmentID: 1, TimeBucket: bucket(0)},
+				{SegmentID: 2, TimeBucket: bucket(100)},
+				{SegmentID: 3},
+			},
+		},
+		{
+			NodeID:   2,
+			Segments: []SegmentEntry{{SegmentID: 4, TimeBucket: bucket(200)}},
+		},
+	}
+
+	result := pruneSealedSegments(s.serialize(unaryRange(testTimeField, planpb.OpType_GreaterEqual, 100)), sealed)
+	s.Equal([]int64{2, 3, 4}, s.segmentIDs(result))
+	s.Len(result, 2)
+	s.Len(sealed[0].Segments, 3)
+
+	// the end of a bucket is exclusive
+	result = pruneSealedSegments(s.serialize(unaryRange(testTimeField, planpb.OpType_LessEqual, 100)), sealed)
+	s.Equal([]int64{1, 2, 3}, s.segmentIDs(result))
+
+	result = pruneSealedSegments(s.serialize(unaryRange(101, planpb.OpType_LessEqual, 100)), sealed)
+	s.Equal([]int64{1, 2, 3, 4}, s.segmentIDs(result))
+
+	result = pruneSealedSegments(nil, sealed)
+	s.Equal([]int64{1, 2, 3, 4}, s.segmentIDs(result))
+	result = pruneSealedSegments([]byte("invalid"), sealed)
+	s.Equal([]int64{1, 2, 3, 4}, s.segmentIDs(result))
+}
+
+func (s *SegmentPrunerSuite) TestPrunePKBuckets() {
+	const numBuckets = 4
+	sealed := []SnapshotItem{
+		{
+			NodeID: 1,
+			Segments: lo.Map([]int64{0, 1, 2, 3}, func(index int64, _ int) SegmentEntry {
+				return SegmentEntry{SegmentID: index, PKBucket: &datapb.PKBucket{Index: index, Num: numBuckets}}
+			}),
+		},
+		{
+			NodeID:   2,
+			Segments: []SegmentEntry{{SegmentID: 10}},
+		},
+	}
+
+	result := pruneSealedSegments(s.serialize(pkTerm(100)), sealed)
+	s.Equal([]int64{typeutil.HashInt64PK2Bucket(100, numBuckets), 10}, s.segmentIDs(result))
+
+	result = pruneSealedSegments(s.serialize(unaryRange(101, planpb.OpType_GreaterEqual, 100)), sealed)
+	s.Equal([]int64{0, 1, 2, 3, 10}, s.segmentIDs(result))
+}
+
+func (s *SegmentPrunerSuite) TestPruneZoneMaps() {
+	sealed := []SnapshotItem{
+		{
+			NodeID: 1,
+			Segments: []SegmentEntry{
+				{SegmentID: 1, ZoneMaps: map[int64]*datapb.ZoneMap{101: {IntMin: 0, IntMax: 99}}},
+				{SegmentID: 2, ZoneMaps: map[int64]*datapb.ZoneMap{101: {IntMin: 100, IntMax: 199}}},
+				{SegmentID: 3},
+			},
+		},
+	}
+
+	result := pruneSealedSegments(s.serialize(unaryRange(101, planpb.OpType_GreaterEqual, 100)), sealed)
+	s.Equal([]int64{2, 3}, s.segmentIDs(result))
+
+	result = pruneSealedSegments(s.serialize(unaryRange(102, planpb.OpType_GreaterEqual, 100)), sealed)
+	s.Equal([]int64{1, 2, 3}, s.segmentIDs(result))
+}
+
+func TestSegmentPruner(t *testing.T) {
+	suite.Run(t, new(SegmentPrunerSuite))
+}
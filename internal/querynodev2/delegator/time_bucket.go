@@ -0,0 +1,136 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delegator
+
+import (
+	"math"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+)
+
+// timeRange is the closed range of the time field values matched by a filter.
+type timeRange struct {
+	lower int64
+	upper int64
+}
+
+var fullTimeRange = timeRange{lower: math.MinInt64, upper: math.MaxInt64}
+
+func (r timeRange) isEmpty() bool {
+	return r.lower > r.upper
+}
+
+func (r timeRange) intersect(o timeRange) timeRange {
+	return timeRange{lower: lo.Max([]int64{r.lower, o.lower}), upper: lo.Min([]int64{r.upper, o.upper})}
+}
+
+func (r timeRange) union(o timeRange) timeRange {
+	if r.isEmpty() {
+		return o
+	}
+	if o.isEmpty() {
+		return r
+	}
+	return timeRange{lower: lo.Min([]int64{r.lower, o.lower}), upper: lo.Max([]int64{r.upper, o.upper})}
+}
+
+// parseTimeRange returns the range of the field values which may match the expr, the full range
+// if the expr doesn't limit the field.
+func parseTimeRange(expr *planpb.Expr, fieldID int64) timeRange {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_BinaryExpr:
+		left := parseTimeRange(e.BinaryExpr.GetLeft(), fieldID)
+		right := parseTimeRange(e.BinaryExpr.GetRight(), fieldID)
+		switch e.BinaryExpr.GetOp() {
+		case planpb.BinaryExpr_LogicalAnd:
+			return left.intersect(right)
+		case planpb.BinaryExpr_LogicalOr:
+			return left.union(right)
+		}
+	case *planpb.Expr_UnaryRangeExpr:
+		value, ok := int64Value(e.UnaryRangeExpr.GetValue())
+		if !matchTimeColumn(e.UnaryRangeExpr.GetColumnInfo(), fieldID) || !ok {
+			return fullTimeRange
+		}
+		switch e.UnaryRangeExpr.GetOp() {
+		case planpb.OpType_GreaterThan:
+			if value == math.MaxInt64 {
+				return timeRange{lower: math.MaxInt64, upper: math.MinInt64}
+			}
+			return timeRange{lower: value + 1, upper: math.MaxInt64}
+		case planpb.OpType_GreaterEqual:
+			return timeRange{lower: value, upper: math.MaxInt64}
+		case planpb.OpType_LessThan:
+			if value == math.MinInt64 {
+				return timeRange{lower: math.MaxInt64, upper: math.MinInt64}
+			}
+			return timeRange{lower: math.MinInt64, upper: value - 1}
+		case planpb.OpType_LessEqual:
+			return timeRange{lower: math.MinInt64, upper: value}
+		case planpb.OpType_Equal:
+			return timeRange{lower: value, upper: value}
+		}
+	case *planpb.Expr_BinaryRangeExpr:
+		lower, lowerOk := int64Value(e.BinaryRangeExpr.GetLowerValue())
+		upper, upperOk := int64Value(e.BinaryRangeExpr.GetUpperValue())
+		if !matchTimeColumn(e.BinaryRangeExpr.GetColumnInfo(), fieldID) || !lowerOk || !upperOk {
+			return fullTimeRange
+		}
+		r := timeRange{lower: lower, upper: upper}
+		if !e.BinaryRangeExpr.GetLowerInclusive() {
+			if lower == math.MaxInt64 {
+				return timeRange{lower: math.MaxInt64, upper: math.MinInt64}
+			}
+			r.lower++
+		}
+		if !e.BinaryRangeExpr.GetUpperInclusive() {
+			if upper == math.MinInt64 {
+				return timeRange{lower: math.MaxInt64, upper: math.MinInt64}
+			}
+			r.upper--
+		}
+		return r
+	case *planpb.Expr_TermExpr:
+		if !matchTimeColumn(e.TermExpr.GetColumnInfo(), fieldID) || e.TermExpr.GetIsInField() {
+			return fullTimeRange
+		}
+		r := timeRange{lower: math.MaxInt64, upper: math.MinInt64}
+		for _, v := range e.TermExpr.GetValues() {
+			value, ok := int64Value(v)
+			if !ok {
+				return fullTimeRange
+			}
+			r = r.union(timeRange{lower: value, upper: value})
+		}
+		return r
+	}
+	return fullTimeRange
+}
+
+func matchTimeColumn(info *planpb.ColumnInfo, fieldID int64) bool {
+	return info.GetFieldId() == fieldID && len(info.GetNestedPath()) == 0
+}
+
+func int64Value(value *planpb.GenericValue) (int64, bool) {
+	v, ok := value.GetVal().(*planpb.GenericValue_Int64Val)
+	if !ok {
+		return 0, false
+	}
+	return v.Int64Val, true
+}
@@ -0,0 +1,93 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delegator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+)
+
+const testTimeField = int64(100)
+
+func timeColumn(fieldID int64) *planpb.ColumnInfo {
+	return &planpb.ColumnInfo{FieldId: fieldID}
+}
+
+func int64Val(v int64) *planpb.GenericValue {
+	return &planpb.GenericValue{Val: &planpb.GenericValue_Int64Val{Int64Val: v}}
+}
+
+func unaryRange(fieldID int64, op planpb.OpType, v int64) *planpb.Expr {
+	return &planpb.Expr{Expr: &planpb.Expr_UnaryRangeExpr{UnaryRangeExpr: &planpb.UnaryRangeExpr{
+		ColumnInfo: timeColumn(fieldID),
+		Op:         op,
+		Value:      int64Val(v),
+	}}}
+}
+
+func logical(op planpb.BinaryExpr_BinaryOp, left, right *planpb.Expr) *planpb.Expr {
+	return &planpb.Expr{Expr: &planpb.Expr_BinaryExpr{BinaryExpr: &planpb.BinaryExpr{Op: op, Left: left, Right: right}}}
+}
+
+type TimeBucketSuite struct {
+	suite.Suite
+}
+
+func (s *TimeBucketSuite) TestParseTimeRange() {
+	s.Equal(fullTimeRange, parseTimeRange(nil, testTimeField))
+	s.Equal(fullTimeRange, parseTimeRange(unaryRange(101, planpb.OpType_GreaterThan, 10), testTimeField))
+
+	s.Equal(timeRange{lower: 11, upper: math.MaxInt64}, parseTimeRange(unaryRange(testTimeField, planpb.OpType_GreaterThan, 10), testTimeField))
+	s.Equal(timeRange{lower: math.MinInt64, upper: 10}, parseTimeRange(unaryRange(testTimeField, planpb.OpType_LessEqual, 10), testTimeField))
+	s.Equal(fullTimeRange, parseTimeRange(unaryRange(testTimeField, planpb.OpType_NotEqual, 10), testTimeField))
+	s.True(parseTimeRange(unaryRange(testTimeField, planpb.OpType_GreaterThan, math.MaxInt64), testTimeField).isEmpty())
+
+	and := logical(planpb.BinaryExpr_LogicalAnd,
+		unaryRange(testTimeField, planpb.OpType_GreaterEqual, 10),
+		unaryRange(testTimeField, planpb.OpType_LessThan, 20))
+	s.Equal(timeRange{lower: 10, upper: 19}, parseTimeRange(and, testTimeField))
+	or := logical(planpb.BinaryExpr_LogicalOr, and, unaryRange(testTimeField, planpb.OpType_Equal, 50))
+	s.Equal(timeRange{lower: 10, upper: 50}, parseTimeRange(or, testTimeField))
+	s.Equal(fullTimeRange, parseTimeRange(logical(planpb.BinaryExpr_LogicalOr, and, unaryRange(101, planpb.OpType_Equal, 1)), testTimeField))
+
+	binaryRange := &planpb.Expr{Expr: &planpb.Expr_BinaryRangeExpr{BinaryRangeExpr: &planpb.BinaryRangeExpr{
+		ColumnInfo:     timeColumn(testTimeField),
+		LowerInclusive: false,
+		UpperInclusive: true,
+		LowerValue:     int64Val(10),
+		UpperValue:     int64Val(20),
+	}}}
+	s.Equal(timeRange{lower: 11, upper: 20}, parseTimeRange(binaryRange, testTimeField))
+
+	term := &planpb.Expr{Expr: &planpb.Expr_TermExpr{TermExpr: &planpb.TermExpr{
+		ColumnInfo: timeColumn(testTimeField),
+		Values:     []*planpb.GenericValue{int64Val(30), int64Val(5)},
+	}}}
+	s.Equal(timeRange{lower: 5, upper: 30}, parseTimeRange(term, testTimeField))
+
+	nested := unaryRange(testTimeField, planpb.OpType_Equal, 10)
+	nested.GetUnaryRangeExpr().ColumnInfo.NestedPath = []string{"key"}
+	s.Equal(fullTimeRange, parseTimeRange(nested, testTimeField))
+}
+
+func TestTimeBucket(t *testing.T) {
+	suite.Run(t, new(TimeBucketSuite))
+}
@@ -0,0 +1,167 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delegator
+
+import (
+	"golang.org/x/exp/constraints"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/storage"
+)
+
+// segmentZoneMaps merges the zone maps of the binlogs of each scalar field of the segment,
+// the fields with any binlog not recording the zone map are left out, nil if none is left.
+func segmentZoneMaps(info *querypb.SegmentLoadInfo) map[int64]*datapb.ZoneMap {
+	var zoneMaps map[int64]*datapb.ZoneMap
+	for _, fieldBinlog := range info.GetBinlogPaths() {
+		if len(fieldBinlog.GetBinlogs()) == 0 {
+			continue
+		}
+		binlogZoneMaps := make([]*datapb.ZoneMap, 0, len(fieldBinlog.GetBinlogs()))
+		for _, binlog := range fieldBinlog.GetBinlogs() {
+			binlogZoneMaps = append(binlogZoneMaps, binlog.GetZoneMap())
+		}
+		if zoneMap := storage.MergeZoneMaps(binlogZoneMaps...); zoneMap != nil {
+			if zoneMaps == nil {
+				zoneMaps = make(map[int64]*datapb.ZoneMap)
+			}
+			zoneMaps[fieldBinlog.GetFieldID()] = zoneMap
+		}
+	}
+	return zoneMaps
+}
+
+// zoneMapsMayMatch returns whether any row in the range of the zone maps may match the expr,
+// true if the expr is not limited by the zone maps.
+func zoneMapsMayMatch(expr *planpb.Expr, zoneMaps map[int64]*datapb.ZoneMap) bool {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_BinaryExpr:
+		switch e.BinaryExpr.GetOp() {
+		case planpb.BinaryExpr_LogicalAnd:
+			return zoneMapsMayMatch(e.BinaryExpr.GetLeft(), zoneMaps) && zoneMapsMayMatch(e.BinaryExpr.GetRight(), zoneMaps)
+		case planpb.BinaryExpr_LogicalOr:
+			return zoneMapsMayMatch(e.BinaryExpr.GetLeft(), zoneMaps) || zoneMapsMayMatch(e.BinaryExpr.GetRight(), zoneMaps)
+		}
+	case *planpb.Expr_UnaryRangeExpr:
+		zoneMap := zoneMapOf(e.UnaryRangeExpr.GetColumnInfo(), zoneMaps)
+		if zoneMap == nil {
+			return true
+		}
+		toMin, toMax, ok := compareZoneMap(e.UnaryRangeExpr.GetColumnInfo().GetDataType(), zoneMap, e.UnaryRangeExpr.GetValue())
+		if !ok {
+			return true
+		}
+		switch e.UnaryRangeExpr.GetOp() {
+		case planpb.OpType_GreaterThan:
+			return toMax < 0
+		case planpb.OpType_GreaterEqual:
+			return toMax <= 0
+		case planpb.OpType_LessThan:
+			return toMin > 0
+		case planpb.OpType_LessEqual:
+			return toMin >= 0
+		case planpb.OpType_Equal:
+			return toMin >= 0 && toMax <= 0
+		case planpb.OpType_NotEqual:
+			return toMin != 0 || toMax != 0
+		}
+	case *planpb.Expr_BinaryRangeExpr:
+		zoneMap := zoneMapOf(e.BinaryRangeExpr.GetColumnInfo(), zoneMaps)
+		if zoneMap == nil {
+			return true
+		}
+		dataType := e.BinaryRangeExpr.GetColumnInfo().GetDataType()
+		_, lowerToMax, lowerOk := compareZoneMap(dataType, zoneMap, e.BinaryRangeExpr.GetLowerValue())
+		upperToMin, _, upperOk := compareZoneMap(dataType, zoneMap, e.BinaryRangeExpr.GetUpperValue())
+		if !lowerOk || !upperOk {
+			return true
+		}
+		if lowerToMax > 0 || (lowerToMax == 0 && !e.BinaryRangeExpr.GetLowerInclusive()) {
+			return false
+		}
+		return upperToMin > 0 || (upperToMin == 0 && e.BinaryRangeExpr.GetUpperInclusive())
+	case *planpb.Expr_TermExpr:
+		zoneMap := zoneMapOf(e.TermExpr.GetColumnInfo(), zoneMaps)
+		if zoneMap == nil || e.TermExpr.GetIsInField() {
+			return true
+		}
+		for _, value := range e.TermExpr.GetValues() {
+			toMin, toMax, ok := compareZoneMap(e.TermExpr.GetColumnInfo().GetDataType(), zoneMap, value)
+			if !ok || (toMin >= 0 && toMax <= 0) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func zoneMapOf(info *planpb.ColumnInfo, zoneMaps map[int64]*datapb.ZoneMap) *datapb.ZoneMap {
+	if len(info.GetNestedPath()) > 0 {
+		return nil
+	}
+	return zoneMaps[info.GetFieldId()]
+}
+
+// compareZoneMap compares the value to the min and the max of the zone map of the data type,
+// false if the value can't be compared.
+func compareZoneMap(dataType schemapb.DataType, zoneMap *datapb.ZoneMap, value *planpb.GenericValue) (int, int, bool) {
+	switch dataType {
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32, schemapb.DataType_Int64:
+		v, ok := value.GetVal().(*planpb.GenericValue_Int64Val)
+		if !ok {
+			return 0, 0, false
+		}
+		return compare(v.Int64Val, zoneMap.GetIntMin()), compare(v.Int64Val, zoneMap.GetIntMax()), true
+	case schemapb.DataType_Float, schemapb.DataType_Double:
+		var f float64
+		switch v := value.GetVal().(type) {
+		case *planpb.GenericValue_FloatVal:
+			f = v.FloatVal
+		case *planpb.GenericValue_Int64Val:
+			f = float64(v.Int64Val)
+		default:
+			return 0, 0, false
+		}
+		// the float values are compared in float32 by segcore
+		if dataType == schemapb.DataType_Float {
+			f = float64(float32(f))
+		}
+		return compare(f, zoneMap.GetFloatMin()), compare(f, zoneMap.GetFloatMax()), true
+	case schemapb.DataType_VarChar, schemapb.DataType_String:
+		v, ok := value.GetVal().(*planpb.GenericValue_StringVal)
+		if !ok {
+			return 0, 0, false
+		}
+		return compare(v.StringVal, zoneMap.GetStringMin()), compare(v.StringVal, zoneMap.GetStringMax()), true
+	}
+	return 0, 0, false
+}
+
+func compare[T constraints.Ordered](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,154 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delegator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+)
+
+const (
+	testIntField    = int64(101)
+	testFloatField  = int64(102)
+	testStringField = int64(103)
+)
+
+func typedColumn(fieldID int64, dataType schemapb.DataType) *planpb.ColumnInfo {
+	return &planpb.ColumnInfo{FieldId: fieldID, DataType: dataType}
+}
+
+func typedUnaryRange(info *planpb.ColumnInfo, op planpb.OpType, value *planpb.GenericValue) *planpb.Expr {
+	return &planpb.Expr{Expr: &planpb.Expr_UnaryRangeExpr{UnaryRangeExpr: &planpb.UnaryRangeExpr{
+		ColumnInfo: info,
+		Op:         op,
+		Value:      value,
+	}}}
+}
+
+type ZoneMapSuite struct {
+	suite.Suite
+
+	zoneMaps map[int64]*datapb.ZoneMap
+}
+
+func (s *ZoneMapSuite) SetupTest() {
+	s.zoneMaps = map[int64]*datapb.ZoneMap{
+		testIntField:    {IntMin: 10, IntMax: 20},
+		testFloatField:  {FloatMin: float64(float32(0.1)), FloatMax: 1.5},
+		testStringField: {StringMin: "b", StringMax: "d"},
+	}
+}
+
+func (s *ZoneMapSuite) TestUnaryRange() {
+	intColumn := typedColumn(testIntField, schemapb.DataType_Int64)
+	cases := []struct {
+		op    planpb.OpType
+		value int64
+		match bool
+	}{
+		{planpb.OpType_GreaterThan, 20, false},
+		{planpb.OpType_GreaterThan, 19, true},
+		{planpb.OpType_GreaterEqual, 20, true},
+		{planpb.OpType_LessThan, 10, false},
+		{planpb.OpType_LessEqual, 10, true},
+		{planpb.OpType_Equal, 9, false},
+		{planpb.OpType_Equal, 15, true},
+		{planpb.OpType_NotEqual, 15, true},
+		{planpb.OpType_PrefixMatch, 0, true},
+	}
+	for _, c := range cases {
+		s.Equal(c.match, zoneMapsMayMatch(typedUnaryRange(intColumn, c.op, int64Val(c.value)), s.zoneMaps), "%s %d", c.op, c.value)
+	}
+
+	s.True(zoneMapsMayMatch(typedUnaryRange(&planpb.ColumnInfo{FieldId: testIntField, DataType: schemapb.DataType_Int64, NestedPath: []string{"a"}},
+		planpb.OpType_Equal, int64Val(0)), s.zoneMaps))
+	s.True(zoneMapsMayMatch(typedUnaryRange(typedColumn(104, schemapb.DataType_Int64), planpb.OpType_Equal, int64Val(0)), s.zoneMaps))
+
+	s.False(zoneMapsMayMatch(typedUnaryRange(typedColumn(testIntField, schemapb.DataType_Int64), planpb.OpType_NotEqual, int64Val(10)),
+		map[int64]*datapb.ZoneMap{testIntField: {IntMin: 10, IntMax: 10}}))
+
+	// the float literal is compared in float32 as the stored values
+	floatColumn := typedColumn(testFloatField, schemapb.DataType_Float)
+	s.True(zoneMapsMayMatch(typedUnaryRange(floatColumn, planpb.OpType_Equal, &planpb.GenericValue{Val: &planpb.GenericValue_FloatVal{FloatVal: 0.1}}), s.zoneMaps))
+	s.False(zoneMapsMayMatch(typedUnaryRange(floatColumn, planpb.OpType_GreaterThan, int64Val(2)), s.zoneMaps))
+
+	stringColumn := typedColumn(testStringField, schemapb.DataType_VarChar)
+	s.False(zoneMapsMayMatch(typedUnaryRange(stringColumn, planpb.OpType_Equal, &planpb.GenericValue{Val: &planpb.GenericValue_StringVal{StringVal: "a"}}), s.zoneMaps))
+	s.True(zoneMapsMayMatch(typedUnaryRange(stringColumn, planpb.OpType_Equal, &planpb.GenericValue{Val: &planpb.GenericValue_StringVal{StringVal: "c"}}), s.zoneMaps))
+	s.True(zoneMapsMayMatch(typedUnaryRange(stringColumn, planpb.OpType_Equal, int64Val(0)), s.zoneMaps))
+}
+
+func (s *ZoneMapSuite) TestBinaryRangeAndTerm() {
+	intColumn := typedColumn(testIntField, schemapb.DataType_Int64)
+	binaryRange := func(lower, upper int64, lowerInclusive, upperInclusive bool) *planpb.Expr {
+		return &planpb.Expr{Expr: &planpb.Expr_BinaryRangeExpr{BinaryRangeExpr: &planpb.BinaryRangeExpr{
+			ColumnInfo:     intColumn,
+			LowerValue:     int64Val(lower),
+			UpperValue:     int64Val(upper),
+			LowerInclusive: lowerInclusive,
+			UpperInclusive: upperInclusive,
+		}}}
+	}
+	s.True(zoneMapsMayMatch(binaryRange(0, 10, true, true), s.zoneMaps))
+	s.False(zoneMapsMayMatch(binaryRange(0, 10, true, false), s.zoneMaps))
+	s.True(zoneMapsMayMatch(binaryRange(20, 30, true, true), s.zoneMaps))
+	s.False(zoneMapsMayMatch(binaryRange(20, 30, false, true), s.zoneMaps))
+	s.False(zoneMapsMayMatch(binaryRange(21, 30, true, true), s.zoneMaps))
+
+	term := func(values ...int64) *planpb.Expr {
+		genericValues := make([]*planpb.GenericValue, 0, len(values))
+		for _, v := range values {
+			genericValues = append(genericValues, int64Val(v))
+		}
+		return &planpb.Expr{Expr: &planpb.Expr_TermExpr{TermExpr: &planpb.TermExpr{ColumnInfo: intColumn, Values: genericValues}}}
+	}
+	s.False(zoneMapsMayMatch(term(1, 2, 30), s.zoneMaps))
+	s.True(zoneMapsMayMatch(term(1, 12), s.zoneMaps))
+
+	s.True(zoneMapsMayMatch(logical(planpb.BinaryExpr_LogicalOr, term(1), term(12)), s.zoneMaps))
+	s.False(zoneMapsMayMatch(logical(planpb.BinaryExpr_LogicalAnd, term(1), term(12)), s.zoneMaps))
+	s.True(zoneMapsMayMatch(nil, s.zoneMaps))
+}
+
+func (s *ZoneMapSuite) TestSegmentZoneMaps() {
+	info := &querypb.SegmentLoadInfo{BinlogPaths: []*datapb.FieldBinlog{
+		{FieldID: testIntField, Binlogs: []*datapb.Binlog{
+			{ZoneMap: &datapb.ZoneMap{IntMin: 10, IntMax: 20}},
+			{ZoneMap: &datapb.ZoneMap{IntMin: 0, IntMax: 15}},
+		}},
+		{FieldID: testFloatField, Binlogs: []*datapb.Binlog{
+			{ZoneMap: &datapb.ZoneMap{FloatMin: 1, FloatMax: 2}},
+			{},
+		}},
+	}}
+	zoneMaps := segmentZoneMaps(info)
+	s.Len(zoneMaps, 1)
+	s.EqualValues(0, zoneMaps[testIntField].GetIntMin())
+	s.EqualValues(20, zoneMaps[testIntField].GetIntMax())
+
+	s.Nil(segmentZoneMaps(&querypb.SegmentLoadInfo{}))
+}
+
+func TestZoneMap(t *testing.T) {
+	suite.Run(t, new(ZoneMapSuite))
+}
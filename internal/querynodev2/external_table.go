@@ -0,0 +1,268 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/importutilv2/parquet"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
+	"github.com/milvus-io/milvus/pkg/util/hardware"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	externalTableFileSuffix     = ".parquet"
+	externalTableReadBufferSize = 16 << 20
+	// rows of an external table are visible to all queries
+	externalTableTimestamp = 1
+	// the memory of the growing segments of a parquet file predicted by the file size, as the parquet files are
+	// encoded and compressed
+	externalTableExpansionRate = 4
+)
+
+var errDelegatorStopped = errors.New("delegator stopped")
+
+// externalTable is the share of the parquet files of an external collection assigned to a channel.
+type externalTable struct {
+	collectionID int64
+	partitionID  int64
+	channel      string
+	position     *msgpb.MsgPosition
+	path         string
+	schema       *schemapb.CollectionSchema
+	pkFieldID    int64
+	files        []string
+	size         int64 // the total size of the files
+}
+
+// newExternalTable lists the share of the parquet files of the external collection assigned to the channel,
+// returns nil if the collection of the request is not external.
+func newExternalTable(ctx context.Context, cm storage.ChunkManager, req *querypb.WatchDmChannelsRequest) (*externalTable, error) {
+	table := req.GetExternalTable()
+	if table == nil {
+		return nil, nil
+	}
+	if table.GetShardNum() <= 0 || table.GetShardIndex() < 0 || table.GetShardIndex() >= table.GetShardNum() {
+		return nil, fmt.Errorf("invalid external table shard %d of %d", table.GetShardIndex(), table.GetShardNum())
+	}
+	partitionIDs := req.GetLoadMeta().GetPartitionIDs()
+	if len(partitionIDs) == 0 {
+		partitionIDs = req.GetPartitionIDs()
+	}
+	if len(partitionIDs) == 0 {
+		return nil, fmt.Errorf("no partition to load external table %s", table.GetPath())
+	}
+
+	files, _, err := cm.ListWithPrefix(ctx, table.GetPath()+"/", true)
+	if err != nil {
+		return nil, err
+	}
+	files = lo.Filter(files, func(file string, _ int) bool {
+		return strings.HasSuffix(file, externalTableFileSuffix) &&
+			typeutil.HashString2Uint32(path.Base(file))%uint32(table.GetShardNum()) == uint32(table.GetShardIndex())
+	})
+	var size int64
+	for _, file := range files {
+		fileSize, err := cm.Size(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		size += fileSize
+	}
+
+	schema := typeutil.Clone(req.GetSchema())
+	schema.Fields = lo.Filter(schema.GetFields(), func(field *schemapb.FieldSchema, _ int) bool {
+		return field.GetFieldID() >= common.StartOfUserFieldID
+	})
+	pkField, err := typeutil.GetPrimaryFieldSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	channel := req.GetInfos()[0]
+	return &externalTable{
+		collectionID: req.GetCollectionID(),
+		partitionID:  partitionIDs[0],
+		channel:      channel.GetChannelName(),
+		position: &msgpb.MsgPosition{
+			ChannelName: channel.GetChannelName(),
+			Timestamp:   channel.GetSeekPosition().GetTimestamp(),
+		},
+		path:      table.GetPath(),
+		schema:    schema,
+		pkFieldID: pkField.GetFieldID(),
+		files:     files,
+		size:      size,
+	}, nil
+}
+
+// checkMemory checks whether the memory is sufficient to insert the files as growing segments.
+func (t *externalTable) checkMemory() error {
+	usedMem := hardware.GetUsedMemoryCount()
+	totalMem := hardware.GetMemoryCount()
+	if usedMem == 0 || totalMem == 0 {
+		return errors.New("get memory failed when checking external table")
+	}
+	predict := usedMem + uint64(t.size)*externalTableExpansionRate
+	limit := uint64(float64(totalMem) * paramtable.Get().QueryNodeCfg.OverloadedMemoryThresholdPercentage.GetAsFloat())
+	if predict > limit {
+		return merr.WrapErrServiceMemoryLimitExceeded(float32(predict), float32(limit),
+			fmt.Sprintf("external table %s of %d bytes", t.path, t.size))
+	}
+	return nil
+}
+
+// load inserts the files into the growing segments of the delegator one after another, one segment per file.
+// It returns errDelegatorStopped once the delegator is stopped, e.g. the channel is unsubscribed.
+func (t *externalTable) load(ctx context.Context, cm storage.ChunkManager, sd delegator.ShardDelegator) error {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", t.collectionID),
+		zap.String("channel", t.channel),
+		zap.String("path", t.path),
+	)
+	for _, file := range t.files {
+		segmentID := int64(typeutil.HashString2Uint32(t.channel + "/" + file))
+		rows, err := loadExternalFile(ctx, cm, sd, t.schema, t.pkFieldID, file, &delegator.InsertData{
+			PartitionID:   t.partitionID,
+			StartPosition: t.position,
+		}, segmentID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load external file %s", file)
+		}
+		log.Info("external file loaded", zap.String("file", file),
+			zap.Int64("segmentID", segmentID), zap.Int64("rows", rows))
+	}
+	if sd.Stopped() {
+		return errDelegatorStopped
+	}
+	return nil
+}
+
+// loadExternalTable loads the external table of the channel in background, the delegator is started once
+// the table is loaded, or the channel is unsubscribed if failed, which is watched again by the querycoord.
+func (node *QueryNode) loadExternalTable(table *externalTable, sd delegator.ShardDelegator) {
+	log := log.Ctx(node.ctx).With(
+		zap.Int64("collectionID", table.collectionID),
+		zap.String("channel", table.channel),
+		zap.String("path", table.path),
+		zap.Int("files", len(table.files)),
+		zap.Int64("size", table.size),
+	)
+
+	start := time.Now()
+	err := table.load(node.ctx, node.chunkManager, sd)
+	if errors.Is(err, errDelegatorStopped) {
+		log.Info("channel unsubscribed while loading external table")
+		return
+	}
+	if err != nil {
+		log.Warn("failed to load external table, unsubscribe the channel", zap.Error(err))
+		if current, ok := node.delegators.Get(table.channel); ok && current == sd {
+			node.UnsubDmChannel(node.ctx, &querypb.UnsubDmChannelRequest{
+				Base:         commonpbutil.NewMsgBase(commonpbutil.WithTargetID(node.GetNodeID())),
+				CollectionID: table.collectionID,
+				ChannelName:  table.channel,
+			})
+		}
+		return
+	}
+	sd.Start()
+	log.Info("external table loaded", zap.Duration("elapse", time.Since(start)))
+}
+
+// loadExternalFile inserts a parquet file into a growing segment batch by batch,
+// returns the number of rows.
+func loadExternalFile(ctx context.Context, cm storage.ChunkManager, sd delegator.ShardDelegator,
+	schema *schemapb.CollectionSchema, pkFieldID int64, file string, template *delegator.InsertData, segmentID int64,
+) (int64, error) {
+	fileReader, err := cm.Reader(ctx, file)
+	if err != nil {
+		return 0, err
+	}
+	reader, err := parquet.NewReader(ctx, schema, fileReader, externalTableReadBufferSize)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	var rows int64
+	for {
+		if sd.Stopped() {
+			return rows, errDelegatorStopped
+		}
+		data, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return rows, err
+		}
+		num := data.Data[pkFieldID].RowNum()
+		fillExternalDynamicData(data, schema, num)
+		record, err := storage.TransferInsertDataToInsertRecord(data)
+		if err != nil {
+			return rows, err
+		}
+		pkData := lo.FindOrElse(record.GetFieldsData(), nil, func(fd *schemapb.FieldData) bool {
+			return fd.GetFieldId() == pkFieldID
+		})
+		pks, err := storage.ParseFieldData2PrimaryKeys(pkData)
+		if err != nil {
+			return rows, err
+		}
+		insertData := *template
+		insertData.InsertRecord = record
+		insertData.PrimaryKeys = pks
+		insertData.RowIDs = lo.RangeFrom(rows, num)
+		insertData.Timestamps = lo.Times(num, func(int) uint64 { return externalTableTimestamp })
+		sd.ProcessInsert(map[int64]*delegator.InsertData{segmentID: &insertData})
+		rows += int64(num)
+	}
+}
+
+// fillExternalDynamicData fills empty dynamic values for files without the dynamic field.
+func fillExternalDynamicData(data *storage.InsertData, schema *schemapb.CollectionSchema, rowNum int) {
+	dynamicField := typeutil.GetDynamicField(schema)
+	if !schema.GetEnableDynamicField() || dynamicField == nil {
+		return
+	}
+	jsonData, ok := data.Data[dynamicField.GetFieldID()].(*storage.JSONFieldData)
+	if !ok {
+		return
+	}
+	for jsonData.RowNum() < rowNum {
+		jsonData.Data = append(jsonData.Data, []byte("{}"))
+	}
+}
@@ -0,0 +1,197 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/parquet"
+	"github.com/apache/arrow/go/v12/parquet/pqarrow"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type ExternalTableSuite struct {
+	suite.Suite
+
+	root   string
+	cm     storage.ChunkManager
+	schema *schemapb.CollectionSchema
+}
+
+func (s *ExternalTableSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (s *ExternalTableSuite) SetupTest() {
+	s.root = s.T().TempDir()
+	s.cm = storage.NewLocalChunkManager(storage.RootPath(s.root))
+	s.schema = &schemapb.CollectionSchema{
+		Name:               "external",
+		EnableDynamicField: true,
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: common.RowIDField, Name: common.RowIDFieldName, DataType: schemapb.DataType_Int64},
+			{FieldID: common.TimeStampField, Name: common.TimeStampFieldName, DataType: schemapb.DataType_Int64},
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "age", DataType: schemapb.DataType_Int32},
+			{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+		},
+	}
+	for i := 0; i < 4; i++ {
+		s.writeParquet(fmt.Sprintf("table/part-%d.parquet", i), int64(i*10), 10)
+	}
+	s.Require().NoError(os.WriteFile(path.Join(s.root, "table", "_SUCCESS"), nil, 0o600))
+}
+
+func (s *ExternalTableSuite) writeParquet(file string, start int64, rows int) {
+	pkBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	ageBuilder := array.NewInt32Builder(memory.DefaultAllocator)
+	for i := 0; i < rows; i++ {
+		pkBuilder.Append(start + int64(i))
+		ageBuilder.Append(int32(i))
+	}
+	arrowSchema := arrow.NewSchema([]arrow.Field{
+		{Name: "pk", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "age", Type: arrow.PrimitiveTypes.Int32},
+	}, nil)
+	record := array.NewRecord(arrowSchema, []arrow.Array{pkBuilder.NewArray(), ageBuilder.NewArray()}, int64(rows))
+
+	s.Require().NoError(os.MkdirAll(path.Dir(path.Join(s.root, file)), 0o700))
+	f, err := os.Create(path.Join(s.root, file))
+	s.Require().NoError(err)
+	defer f.Close()
+	w, err := pqarrow.NewFileWriter(arrowSchema, f, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps())
+	s.Require().NoError(err)
+	s.Require().NoError(w.Write(record))
+	s.Require().NoError(w.Close())
+}
+
+func (s *ExternalTableSuite) request(shardIndex, shardNum int32) *querypb.WatchDmChannelsRequest {
+	return &querypb.WatchDmChannelsRequest{
+		CollectionID: 1,
+		Schema:       s.schema,
+		Infos:        []*datapb.VchannelInfo{{ChannelName: fmt.Sprintf("ch-%d", shardIndex)}},
+		LoadMeta:     &querypb.LoadMetaInfo{PartitionIDs: []int64{10}},
+		ExternalTable: &querypb.ExternalTableInfo{
+			Path:       path.Join(s.root, "table"),
+			ShardIndex: shardIndex,
+			ShardNum:   shardNum,
+		},
+	}
+}
+
+// load loads the external table of the shards, returns the primary keys of each segment.
+func (s *ExternalTableSuite) load(shardNum int32) map[int64][]int64 {
+	segments := make(map[int64][]int64)
+	for i := int32(0); i < shardNum; i++ {
+		sd := delegator.NewMockShardDelegator(s.T())
+		sd.EXPECT().ProcessInsert(mock.Anything).Run(func(insertRecords map[int64]*delegator.InsertData) {
+			for segmentID, insertData := range insertRecords {
+				s.EqualValues(10, insertData.PartitionID)
+				s.EqualValues(10, insertData.InsertRecord.GetNumRows())
+				s.Len(insertData.RowIDs, 10)
+				s.Len(insertData.Timestamps, 10)
+				s.Len(insertData.InsertRecord.GetFieldsData(), 3)
+				for _, pk := range insertData.PrimaryKeys {
+					segments[segmentID] = append(segments[segmentID], pk.GetValue().(int64))
+				}
+			}
+		}).Maybe()
+		sd.EXPECT().Stopped().Return(false)
+		table, err := newExternalTable(context.Background(), s.cm, s.request(i, shardNum))
+		s.Require().NoError(err)
+		s.Require().NoError(table.checkMemory())
+		s.Require().NoError(table.load(context.Background(), s.cm, sd))
+	}
+	return segments
+}
+
+func (s *ExternalTableSuite) TestLoad() {
+	for _, shardNum := range []int32{1, 2, 3} {
+		segments := s.load(shardNum)
+		s.Len(segments, 4)
+		var pks []int64
+		for _, segmentPks := range segments {
+			pks = append(pks, segmentPks...)
+		}
+		s.Len(pks, 40)
+		s.ElementsMatch(pks, lo.RangeFrom[int64](0, 40))
+	}
+}
+
+func (s *ExternalTableSuite) TestInvalid() {
+	table, err := newExternalTable(context.Background(), s.cm, &querypb.WatchDmChannelsRequest{})
+	s.NoError(err)
+	s.Nil(table)
+	_, err = newExternalTable(context.Background(), s.cm, s.request(2, 2))
+	s.Error(err)
+
+	req := s.request(0, 1)
+	req.LoadMeta = nil
+	_, err = newExternalTable(context.Background(), s.cm, req)
+	s.Error(err)
+
+	// the columns must be in the schema
+	s.schema.Fields = s.schema.Fields[:3]
+	s.schema.EnableDynamicField = false
+	table, err = newExternalTable(context.Background(), s.cm, s.request(0, 1))
+	s.Require().NoError(err)
+	sd := delegator.NewMockShardDelegator(s.T())
+	sd.EXPECT().Stopped().Return(false).Maybe()
+	s.Error(table.load(context.Background(), s.cm, sd))
+}
+
+func (s *ExternalTableSuite) TestStopped() {
+	table, err := newExternalTable(context.Background(), s.cm, s.request(0, 1))
+	s.Require().NoError(err)
+	s.Len(table.files, 4)
+	s.Positive(table.size)
+
+	sd := delegator.NewMockShardDelegator(s.T())
+	sd.EXPECT().Stopped().Return(true)
+	s.ErrorIs(table.load(context.Background(), s.cm, sd), errDelegatorStopped)
+}
+
+func (s *ExternalTableSuite) TestCheckMemory() {
+	table, err := newExternalTable(context.Background(), s.cm, s.request(0, 1))
+	s.Require().NoError(err)
+
+	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.OverloadedMemoryThresholdPercentage.Key, "0")
+	defer paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.OverloadedMemoryThresholdPercentage.Key)
+	s.ErrorIs(table.checkMemory(), merr.ErrServiceMemoryLimitExceeded)
+}
+
+func TestExternalTable(t *testing.T) {
+	suite.Run(t, new(ExternalTableSuite))
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
@@ -38,6 +39,7 @@ type BloomFilterSet struct {
 	segmentID    int64
 	paritionID   int64
 	segType      commonpb.SegmentState
+	pkBucket     *datapb.PKBucket
 	currentStat  *storage.PkStatistics
 	historyStats []*storage.PkStatistics
 }
@@ -74,6 +76,16 @@ func (s *BloomFilterSet) Type() commonpb.SegmentState {
 	return s.segType
 }
 
+// PKBucket returns the primary key bucket of the segment, nil if not bucketed.
+func (s *BloomFilterSet) PKBucket() *datapb.PKBucket {
+	return s.pkBucket
+}
+
+// SetPKBucket sets the primary key bucket of the segment.
+func (s *BloomFilterSet) SetPKBucket(bucket *datapb.PKBucket) {
+	s.pkBucket = bucket
+}
+
 // UpdateBloomFilter updates currentStats with provided pks.
 func (s *BloomFilterSet) UpdateBloomFilter(pks []storage.PrimaryKey) {
 	s.statsMutex.Lock()
@@ -104,6 +116,28 @@ func (s *BloomFilterSet) UpdateBloomFilter(pks []storage.PrimaryKey) {
 	}
 }
 
+// PkRange returns the range of the primary keys of the stats, false if there are no primary keys in the range,
+// i.e. MayPkExist is false for any primary key.
+func (s *BloomFilterSet) PkRange() (storage.PrimaryKey, storage.PrimaryKey, bool) {
+	s.statsMutex.RLock()
+	defer s.statsMutex.RUnlock()
+
+	var minPK, maxPK storage.PrimaryKey
+	for _, stat := range append([]*storage.PkStatistics{s.currentStat}, s.historyStats...) {
+		// the stats without the range return false on PkExist
+		if stat == nil || stat.MinPK == nil || stat.MaxPK == nil || stat.PkFilter == nil {
+			continue
+		}
+		if minPK == nil || stat.MinPK.LT(minPK) {
+			minPK = stat.MinPK
+		}
+		if maxPK == nil || stat.MaxPK.GT(maxPK) {
+			maxPK = stat.MaxPK
+		}
+	}
+	return minPK, maxPK, minPK != nil
+}
+
 // AddHistoricalStats add loaded historical stats.
 func (s *BloomFilterSet) AddHistoricalStats(stats *storage.PkStatistics) {
 	s.statsMutex.Lock()
@@ -18,6 +18,7 @@ package pkoracle
 
 import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
@@ -70,3 +71,27 @@ func WithPartitionID(partitionID int64) CandidateFilter {
 		return candidate.Partition() == partitionID || partitionID == common.InvalidPartitionID
 	}
 }
+
+// WithPKBucketOf returns CandidateFilter skipping the candidates of the other primary key buckets than the pk.
+func WithPKBucketOf(pk storage.PrimaryKey) CandidateFilter {
+	return func(candidate candidateWithWorker) bool {
+		bucketed, ok := candidate.Candidate.(interface{ PKBucket() *datapb.PKBucket })
+		if !ok || bucketed.PKBucket() == nil {
+			return true
+		}
+		bucket := bucketed.PKBucket()
+		return PKBucketOf(pk, bucket.GetNum()) == bucket.GetIndex()
+	}
+}
+
+// PKBucketOf returns the bucket of the primary key in the buckets of the number.
+func PKBucketOf(pk storage.PrimaryKey, numBuckets int64) int64 {
+	switch pk := pk.(type) {
+	case *storage.Int64PrimaryKey:
+		return typeutil.HashInt64PK2Bucket(pk.Value, numBuckets)
+	case *storage.VarCharPrimaryKey:
+		return typeutil.HashVarCharPK2Bucket(pk.Value, numBuckets)
+	default:
+		return -1
+	}
+}
@@ -539,6 +539,49 @@ func (_c *MockSegment_Level_Call) RunAndReturn(run func() datapb.SegmentLevel) *
 	return _c
 }
 
+// LoadDeltaBitmap provides a mock function with given fields: ctx, bitmap
+func (_m *MockSegment) LoadDeltaBitmap(ctx context.Context, bitmap *storage.DeleteBitmap) error {
+	ret := _m.Called(ctx, bitmap)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *storage.DeleteBitmap) error); ok {
+		r0 = rf(ctx, bitmap)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSegment_LoadDeltaBitmap_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LoadDeltaBitmap'
+type MockSegment_LoadDeltaBitmap_Call struct {
+	*mock.Call
+}
+
+// LoadDeltaBitmap is a helper method to define mock.On call
+//   - ctx context.Context
+//   - bitmap *storage.DeleteBitmap
+func (_e *MockSegment_Expecter) LoadDeltaBitmap(ctx interface{}, bitmap interface{}) *MockSegment_LoadDeltaBitmap_Call {
+	return &MockSegment_LoadDeltaBitmap_Call{Call: _e.mock.On("LoadDeltaBitmap", ctx, bitmap)}
+}
+
+func (_c *MockSegment_LoadDeltaBitmap_Call) Run(run func(ctx context.Context, bitmap *storage.DeleteBitmap)) *MockSegment_LoadDeltaBitmap_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*storage.DeleteBitmap))
+	})
+	return _c
+}
+
+func (_c *MockSegment_LoadDeltaBitmap_Call) Return(_a0 error) *MockSegment_LoadDeltaBitmap_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSegment_LoadDeltaBitmap_Call) RunAndReturn(run func(context.Context, *storage.DeleteBitmap) error) *MockSegment_LoadDeltaBitmap_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LoadDeltaData provides a mock function with given fields: ctx, deltaData
 func (_m *MockSegment) LoadDeltaData(ctx context.Context, deltaData *storage.DeleteData) error {
 	ret := _m.Called(ctx, deltaData)
@@ -52,6 +52,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/segcorepb"
 	"github.com/milvus-io/milvus/internal/querynodev2/pkoracle"
 	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/diskwatchdog"
 	typeutil_internal "github.com/milvus-io/milvus/internal/util/typeutil"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
@@ -1069,6 +1070,53 @@ func (s *LocalSegment) LoadDeltaData(ctx context.Context, deltaData *storage.Del
 	return nil
 }
 
+// LoadDeltaBitmap loads the deletes recorded by the offsets of the deleted rows,
+// which are applied without looking up the primary keys.
+func (s *LocalSegment) LoadDeltaBitmap(ctx context.Context, bitmap *storage.DeleteBitmap) error {
+	s.ptrLock.RLock()
+	defer s.ptrLock.RUnlock()
+
+	if s.ptr == nil {
+		return merr.WrapErrSegmentNotLoaded(s.segmentID, "segment released")
+	}
+	if s.Type() != SegmentTypeSealed {
+		return merr.WrapErrServiceInternal("delete bitmap is only applicable to sealed segments")
+	}
+	if bitmap.NumRows() != s.insertCount.Load() {
+		return merr.WrapErrServiceInternal(fmt.Sprintf("delete bitmap of %d rows mismatches with segment %d of %d rows",
+			bitmap.NumRows(), s.ID(), s.insertCount.Load()))
+	}
+
+	offsets, tss := bitmap.Records()
+	var status C.CStatus
+	GetDynamicPool().Submit(func() (any, error) {
+		status = C.LoadDeletedOffsets(s.ptr,
+			(*C.int64_t)(unsafe.Pointer(&offsets[0])),
+			(*C.uint64_t)(unsafe.Pointer(&tss[0])),
+			C.int64_t(len(offsets)))
+		return nil, nil
+	}).Await()
+
+	if err := HandleCStatus(ctx, &status, "LoadDeletedOffsets failed",
+		zap.Int64("collectionID", s.Collection()),
+		zap.Int64("partitionID", s.Partition()),
+		zap.Int64("segmentID", s.ID())); err != nil {
+		return err
+	}
+
+	s.rowNum.Store(-1)
+	for _, ts := range tss {
+		if ts > s.lastDeltaTimestamp.Load() {
+			s.lastDeltaTimestamp.Store(ts)
+		}
+	}
+
+	log.Ctx(ctx).Info("load deleted offsets done",
+		zap.Int64("segmentID", s.ID()),
+		zap.Int("deleteCount", len(offsets)))
+	return nil
+}
+
 func (s *LocalSegment) LoadIndex(ctx context.Context, indexInfo *querypb.FieldIndexInfo, fieldType schemapb.DataType) error {
 	ctx, sp := otel.Tracer(typeutil.QueryNodeRole).Start(ctx, fmt.Sprintf("LoadIndex-%d-%d", s.segmentID, indexInfo.GetFieldID()))
 	defer sp.End()
@@ -1183,6 +1231,11 @@ func (s *LocalSegment) WarmupChunkCache(ctx context.Context, fieldID int64) {
 	var status C.CStatus
 
 	warmingUp := strings.ToLower(paramtable.Get().QueryNodeCfg.ChunkCacheWarmingUp.GetValue())
+	if (warmingUp == "sync" || warmingUp == "async") && diskwatchdog.IsDegraded() {
+		// warming up fills the chunk cache on local disk
+		log.Warn("skip warming up chunk cache, local disk is in degraded mode")
+		return
+	}
 	switch warmingUp {
 	case "sync":
 		GetLoadPool().Submit(func() (any, error) {
@@ -57,6 +57,7 @@ type Segment interface {
 	Insert(ctx context.Context, rowIDs []int64, timestamps []typeutil.Timestamp, record *segcorepb.InsertRecord) error
 	Delete(ctx context.Context, primaryKeys []storage.PrimaryKey, timestamps []typeutil.Timestamp) error
 	LoadDeltaData(ctx context.Context, deltaData *storage.DeleteData) error
+	LoadDeltaBitmap(ctx context.Context, bitmap *storage.DeleteBitmap) error
 	LastDeltaTimestamp() uint64
 	Release()
 
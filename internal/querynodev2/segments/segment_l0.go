@@ -148,6 +148,10 @@ func (s *L0Segment) LoadDeltaData(ctx context.Context, deltaData *storage.Delete
 	return nil
 }
 
+func (s *L0Segment) LoadDeltaBitmap(ctx context.Context, bitmap *storage.DeleteBitmap) error {
+	return merr.WrapErrIoFailedReason("delete bitmap not supported for L0 segment")
+}
+
 func (s *L0Segment) DeleteRecords() ([]storage.PrimaryKey, []uint64) {
 	s.dataGuard.RLock()
 	defer s.dataGuard.RUnlock()
@@ -49,6 +49,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/querypb"
 	"github.com/milvus-io/milvus/internal/querynodev2/pkoracle"
 	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/diskwatchdog"
 	typeutil_internal "github.com/milvus-io/milvus/internal/util/typeutil"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
@@ -279,6 +280,7 @@ func (loader *segmentLoaderV2) LoadBloomFilterSet(ctx context.Context, collectio
 		partitionID := loadInfo.PartitionID
 		segmentID := loadInfo.SegmentID
 		bfs := pkoracle.NewBloomFilterSet(segmentID, partitionID, commonpb.SegmentState_Sealed)
+		bfs.SetPKBucket(loadInfo.GetPkBucket())
 
 		log.Info("loading bloom filter for remote...")
 		err := loader.loadBloomFilter(ctx, segmentID, bfs, loadInfo.StorageVersion)
@@ -883,6 +885,7 @@ func (loader *segmentLoader) LoadBloomFilterSet(ctx context.Context, collectionI
 		partitionID := loadInfo.PartitionID
 		segmentID := loadInfo.SegmentID
 		bfs := pkoracle.NewBloomFilterSet(segmentID, partitionID, commonpb.SegmentState_Sealed)
+		bfs.SetPKBucket(loadInfo.GetPkBucket())
 
 		log.Info("loading bloom filter for remote...")
 		pkStatsBinlogs, logType := loader.filterPKStatsBinlogs(loadInfo.Statslogs, pkField.GetFieldID())
@@ -1153,10 +1156,12 @@ func (loader *segmentLoader) loadBloomFilter(ctx context.Context, segmentID int6
 	}
 
 	startTs := time.Now()
-	values, err := loader.cm.MultiRead(ctx, binlogPaths)
+	// the stats are copied on deserialized, so the mapped stats logs are released once deserialized
+	values, release, err := storage.MultiReadMapped(ctx, loader.cm, binlogPaths, storage.MmapAdviceWillNeed)
 	if err != nil {
 		return err
 	}
+	defer release()
 	blobs := []*storage.Blob{}
 	for i := 0; i < len(values); i++ {
 		blobs = append(blobs, &storage.Blob{Value: values[i]})
@@ -1233,6 +1238,19 @@ func (loader *segmentLoader) LoadDeltaLogs(ctx context.Context, segment Segment,
 		log.Info("there are no delta logs saved with segment, skip loading delete record")
 		return nil
 	}
+	blobs, bitmap, err := dCodec.DeserializeBitmaps(blobs)
+	if err != nil {
+		return err
+	}
+	if bitmap != nil {
+		if err := segment.LoadDeltaBitmap(ctx, bitmap); err != nil {
+			return err
+		}
+		log.Info("load delta bitmaps done", zap.Int("deleteCount", bitmap.Len()))
+	}
+	if len(blobs) == 0 {
+		return nil
+	}
 	_, _, deltaData, err := dCodec.Deserialize(blobs)
 	if err != nil {
 		return err
@@ -1479,6 +1497,16 @@ func (loader *segmentLoader) checkSegmentSize(ctx context.Context, segmentLoadIn
 			paramtable.Get().QueryNodeCfg.MaxDiskUsagePercentage.GetAsFloat())
 	}
 
+	// segments loaded to disk (disk index and mmap) are not allowed while the local disk is almost full
+	if predictDiskUsage > diskUsage {
+		if err := diskwatchdog.CheckAvailable(); err != nil {
+			log.Warn("load segment failed, local disk is in degraded mode",
+				zap.Float64("predictDiskUsage(MB)", toMB(predictDiskUsage)),
+				zap.Error(err))
+			return 0, 0, err
+		}
+	}
+
 	return predictMemUsage - memUsage, predictDiskUsage - diskUsage, nil
 }
 
@@ -58,6 +58,7 @@ import (
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/internal/util/dependency"
+	"github.com/milvus-io/milvus/internal/util/diskwatchdog"
 	"github.com/milvus-io/milvus/internal/util/initcore"
 	"github.com/milvus-io/milvus/internal/util/sessionutil"
 	"github.com/milvus-io/milvus/pkg/config"
@@ -235,7 +236,11 @@ func (node *QueryNode) InitSegcore() error {
 	localDataRootPath := filepath.Join(paramtable.Get().LocalStorageCfg.Path.GetValue(), typeutil.QueryNodeRole)
 	initcore.InitLocalChunkManager(localDataRootPath)
 
-	err := initcore.InitRemoteChunkManager(paramtable.Get())
+	err := storage.CheckSegcoreStorageType(paramtable.Get().CommonCfg.StorageType.GetValue())
+	if err != nil {
+		return err
+	}
+	err = initcore.InitRemoteChunkManager(paramtable.Get())
 	if err != nil {
 		return err
 	}
@@ -391,6 +396,7 @@ func (node *QueryNode) Init() error {
 func (node *QueryNode) Start() error {
 	node.startOnce.Do(func() {
 		node.scheduler.Start()
+		diskwatchdog.Start(typeutil.QueryNodeRole)
 
 		paramtable.SetCreateTime(time.Now())
 		paramtable.SetUpdateTime(time.Now())
@@ -479,6 +485,7 @@ func (node *QueryNode) Stop() error {
 		if node.manager != nil {
 			node.manager.Segment.Clear()
 		}
+		diskwatchdog.Stop()
 
 		node.CloseSegcore()
 	})
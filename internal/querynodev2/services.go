@@ -244,6 +244,16 @@ func (node *QueryNode) WatchDmChannels(ctx context.Context, req *querypb.WatchDm
 		return merr.Success(), nil
 	}
 
+	// the external table is loaded in background, so the resource is checked ahead
+	externalTable, err := newExternalTable(ctx, node.chunkManager, req)
+	if err == nil && externalTable != nil {
+		err = externalTable.checkMemory()
+	}
+	if err != nil {
+		log.Warn("failed to prepare external table", zap.Error(err))
+		return merr.Status(err), nil
+	}
+
 	node.manager.Collection.PutOrRef(req.GetCollectionID(), req.GetSchema(),
 		node.composeIndexMeta(req.GetIndexInfoList(), req.Schema), req.GetLoadMeta())
 	defer func() {
@@ -345,8 +355,13 @@ func (node *QueryNode) WatchDmChannels(ctx context.Context, req *querypb.WatchDm
 
 	// start pipeline
 	pipeline.Start()
-	// delegator after all steps done
-	delegator.Start()
+	if externalTable != nil {
+		// delegator after the external table loaded
+		go node.loadExternalTable(externalTable, delegator)
+	} else {
+		// delegator after all steps done
+		delegator.Start()
+	}
 	log.Info("watch dml channel success")
 	return merr.Success(), nil
 }
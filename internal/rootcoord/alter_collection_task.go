@@ -26,6 +26,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus/internal/metastore/model"
+	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
 )
 
@@ -39,6 +40,18 @@ func (a *alterCollectionTask) Prepare(ctx context.Context) error {
 		return fmt.Errorf("alter collection failed, collection name does not exists")
 	}
 
+	for _, kv := range a.Req.GetProperties() {
+		// the binlogs written already could not be converted or moved,
+		// and the datanodes learn the binlog compression only when the channels watched
+		if kv.GetKey() == common.CollectionBinlogFormatKey || kv.GetKey() == common.CollectionStorageTenantKey ||
+			kv.GetKey() == common.CollectionBinlogCompressionKey ||
+			kv.GetKey() == common.CollectionExternalPathKey || kv.GetKey() == common.CollectionTimeBucketFieldKey ||
+			kv.GetKey() == common.CollectionTimeBucketSecondsKey || kv.GetKey() == common.CollectionPKBucketNumKey ||
+			kv.GetKey() == common.CollectionClusteringFieldKey {
+			return fmt.Errorf("alter collection failed, %s could not be altered", kv.GetKey())
+		}
+	}
+
 	return nil
 }
 
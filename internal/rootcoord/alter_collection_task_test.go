@@ -48,6 +48,78 @@ func Test_alterCollectionTask_Prepare(t *testing.T) {
 		err := task.Prepare(context.Background())
 		assert.NoError(t, err)
 	})
+
+	t.Run("alter binlog format", func(t *testing.T) {
+		task := &alterCollectionTask{
+			Req: &milvuspb.AlterCollectionRequest{
+				Base:           &commonpb.MsgBase{MsgType: commonpb.MsgType_AlterCollection},
+				CollectionName: "cn",
+				Properties: []*commonpb.KeyValuePair{
+					{Key: common.CollectionBinlogFormatKey, Value: common.BinlogFormatParquet},
+				},
+			},
+		}
+		err := task.Prepare(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("alter binlog compression", func(t *testing.T) {
+		task := &alterCollectionTask{
+			Req: &milvuspb.AlterCollectionRequest{
+				Base:           &commonpb.MsgBase{MsgType: commonpb.MsgType_AlterCollection},
+				CollectionName: "cn",
+				Properties: []*commonpb.KeyValuePair{
+					{Key: common.CollectionBinlogCompressionKey, Value: common.BinlogCompressionZstd},
+				},
+			},
+		}
+		err := task.Prepare(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("alter storage tenant", func(t *testing.T) {
+		task := &alterCollectionTask{
+			Req: &milvuspb.AlterCollectionRequest{
+				Base:           &commonpb.MsgBase{MsgType: commonpb.MsgType_AlterCollection},
+				CollectionName: "cn",
+				Properties: []*commonpb.KeyValuePair{
+					{Key: common.CollectionStorageTenantKey, Value: "tenant1"},
+				},
+			},
+		}
+		err := task.Prepare(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("alter external path", func(t *testing.T) {
+		task := &alterCollectionTask{
+			Req: &milvuspb.AlterCollectionRequest{
+				Base:           &commonpb.MsgBase{MsgType: commonpb.MsgType_AlterCollection},
+				CollectionName: "cn",
+				Properties: []*commonpb.KeyValuePair{
+					{Key: common.CollectionExternalPathKey, Value: "lake/table"},
+				},
+			},
+		}
+		err := task.Prepare(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("alter time bucket", func(t *testing.T) {
+		for _, key := range []string{common.CollectionTimeBucketFieldKey, common.CollectionTimeBucketSecondsKey, common.CollectionPKBucketNumKey, common.CollectionClusteringFieldKey} {
+			task := &alterCollectionTask{
+				Req: &milvuspb.AlterCollectionRequest{
+					Base:           &commonpb.MsgBase{MsgType: commonpb.MsgType_AlterCollection},
+					CollectionName: "cn",
+					Properties: []*commonpb.KeyValuePair{
+						{Key: key, Value: "1"},
+					},
+				},
+			}
+			err := task.Prepare(context.Background())
+			assert.Error(t, err, key)
+		}
+	})
 }
 
 func Test_alterCollectionTask_Execute(t *testing.T) {
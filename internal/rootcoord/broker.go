@@ -37,12 +37,15 @@ import (
 )
 
 type watchInfo struct {
-	ts             Timestamp
-	collectionID   UniqueID
-	partitionID    UniqueID
-	vChannels      []string
-	startPositions []*commonpb.KeyDataPair
-	schema         *schemapb.CollectionSchema
+	ts                Timestamp
+	collectionID      UniqueID
+	partitionID       UniqueID
+	vChannels         []string
+	startPositions    []*commonpb.KeyDataPair
+	schema            *schemapb.CollectionSchema
+	binlogFormat      string
+	binlogCompression string
+	storageTenant     string
 }
 
 // Broker communicates with other components.
@@ -165,11 +168,14 @@ func (b *ServerBroker) WatchChannels(ctx context.Context, info *watchInfo) error
 	log.Ctx(ctx).Info("watching channels", zap.Uint64("ts", info.ts), zap.Int64("collection", info.collectionID), zap.Strings("vChannels", info.vChannels))
 
 	resp, err := b.s.dataCoord.WatchChannels(ctx, &datapb.WatchChannelsRequest{
-		CollectionID:    info.collectionID,
-		ChannelNames:    info.vChannels,
-		StartPositions:  info.startPositions,
-		Schema:          info.schema,
-		CreateTimestamp: info.ts,
+		CollectionID:      info.collectionID,
+		ChannelNames:      info.vChannels,
+		StartPositions:    info.startPositions,
+		Schema:            info.schema,
+		CreateTimestamp:   info.ts,
+		BinlogFormat:      info.binlogFormat,
+		BinlogCompression: info.binlogCompression,
+		StorageTenant:     info.storageTenant,
 	})
 	if err != nil {
 		return err
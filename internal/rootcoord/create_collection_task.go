@@ -23,6 +23,7 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
@@ -79,6 +80,30 @@ func (t *createCollectionTask) validate() error {
 		return fmt.Errorf("shard num (%d) exceeds system limit (%d)", shardsNum, cfgShardLimit)
 	}
 
+	if _, err := common.GetBinlogFormat(t.Req.GetProperties()...); err != nil {
+		return merr.WrapErrParameterInvalidMsg("%s", err.Error())
+	}
+
+	if _, err := common.GetBinlogCompression(t.Req.GetProperties()...); err != nil {
+		return merr.WrapErrParameterInvalidMsg("%s", err.Error())
+	}
+
+	if _, err := common.GetStorageTenant(t.Req.GetProperties()...); err != nil {
+		return merr.WrapErrParameterInvalidMsg("%s", err.Error())
+	}
+
+	if _, err := common.GetExternalPath(t.Req.GetProperties()...); err != nil {
+		return merr.WrapErrParameterInvalidMsg("%s", err.Error())
+	}
+
+	if _, _, err := common.GetTimeBucket(t.Req.GetProperties()...); err != nil {
+		return merr.WrapErrParameterInvalidMsg("%s", err.Error())
+	}
+
+	if _, err := common.GetPKBucketNum(t.Req.GetProperties()...); err != nil {
+		return merr.WrapErrParameterInvalidMsg("%s", err.Error())
+	}
+
 	// 2. check db-collection capacity
 	db2CollIDs := t.core.meta.ListAllAvailCollections(t.ctx)
 
@@ -204,9 +229,44 @@ func (t *createCollectionTask) validateSchema(schema *schemapb.CollectionSchema)
 		msg := fmt.Sprintf("schema contains system field: %s, %s, %s", RowIDFieldName, TimeStampFieldName, MetaFieldName)
 		return merr.WrapErrParameterInvalid("schema don't contains system field", "contains", msg)
 	}
+
+	// the rows of an external collection are read from the parquet files as they are
+	if externalPath, _ := common.GetExternalPath(t.Req.GetProperties()...); externalPath != "" {
+		for _, field := range schema.GetFields() {
+			if field.GetIsPrimaryKey() && field.GetAutoID() || field.GetIsPartitionKey() {
+				return merr.WrapErrParameterInvalidMsg("external collection supports neither auto id nor partition key, field: %s", field.GetName())
+			}
+		}
+	}
+
+	// the time bucket of a row is the range of its time field value
+	if timeField, _, _ := common.GetTimeBucket(t.Req.GetProperties()...); timeField != "" {
+		field, ok := lo.Find(schema.GetFields(), func(field *schemapb.FieldSchema) bool { return field.GetName() == timeField })
+		if !ok || field.GetDataType() != schemapb.DataType_Int64 {
+			return merr.WrapErrParameterInvalidMsg("time bucket field %s should be an Int64 field of the schema", timeField)
+		}
+	}
+
+	// the segments are pruned by the zone maps of the clustering field, which are kept for the numeric and string fields
+	if clusteringField := common.GetClusteringField(t.Req.GetProperties()...); clusteringField != "" {
+		field, ok := lo.Find(schema.GetFields(), func(field *schemapb.FieldSchema) bool { return field.GetName() == clusteringField })
+		if !ok || field.GetIsPrimaryKey() || !isClusteringFieldType(field.GetDataType()) {
+			return merr.WrapErrParameterInvalidMsg("clustering field %s should be a numeric or varchar field of the schema other than the primary key", clusteringField)
+		}
+	}
 	return nil
 }
 
+func isClusteringFieldType(dataType schemapb.DataType) bool {
+	switch dataType {
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32, schemapb.DataType_Int64,
+		schemapb.DataType_Float, schemapb.DataType_Double, schemapb.DataType_VarChar:
+		return true
+	default:
+		return false
+	}
+}
+
 func (t *createCollectionTask) assignFieldID(schema *schemapb.CollectionSchema) {
 	for idx := range schema.GetFields() {
 		schema.Fields[idx].FieldID = int64(idx + StartOfUserFieldID)
@@ -419,6 +479,19 @@ func (t *createCollectionTask) Execute(ctx context.Context) error {
 	vchanNames := t.channels.virtualChannels
 	chanNames := t.channels.physicalChannels
 
+	binlogFormat, err := common.GetBinlogFormat(t.Req.GetProperties()...)
+	if err != nil {
+		return err
+	}
+	binlogCompression, err := common.GetBinlogCompression(t.Req.GetProperties()...)
+	if err != nil {
+		return err
+	}
+	storageTenant, err := common.GetStorageTenant(t.Req.GetProperties()...)
+	if err != nil {
+		return err
+	}
+
 	startPositions, err := t.addChannelsAndGetStartPositions(ctx, ts)
 	if err != nil {
 		// ugly here, since we must get start positions first.
@@ -504,10 +577,13 @@ func (t *createCollectionTask) Execute(ctx context.Context) error {
 	undoTask.AddStep(&watchChannelsStep{
 		baseStep: baseStep{core: t.core},
 		info: &watchInfo{
-			ts:             ts,
-			collectionID:   collID,
-			vChannels:      t.channels.virtualChannels,
-			startPositions: toKeyDataPairs(startPositions),
+			ts:                ts,
+			collectionID:      collID,
+			vChannels:         t.channels.virtualChannels,
+			startPositions:    toKeyDataPairs(startPositions),
+			binlogFormat:      binlogFormat,
+			binlogCompression: binlogCompression,
+			storageTenant:     storageTenant,
 			schema: &schemapb.CollectionSchema{
 				Name:        collInfo.Name,
 				Description: collInfo.Description,
@@ -121,6 +121,90 @@ func Test_createCollectionTask_validate(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("invalid binlog format", func(t *testing.T) {
+		task := createCollectionTask{
+			Req: &milvuspb.CreateCollectionRequest{
+				Base:      &commonpb.MsgBase{MsgType: commonpb.MsgType_CreateCollection},
+				ShardsNum: 1,
+				Properties: []*commonpb.KeyValuePair{
+					{Key: common.CollectionBinlogFormatKey, Value: "orc"},
+				},
+			},
+		}
+		err := task.validate()
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("invalid binlog compression", func(t *testing.T) {
+		task := createCollectionTask{
+			Req: &milvuspb.CreateCollectionRequest{
+				Base:      &commonpb.MsgBase{MsgType: commonpb.MsgType_CreateCollection},
+				ShardsNum: 1,
+				Properties: []*commonpb.KeyValuePair{
+					{Key: common.CollectionBinlogCompressionKey, Value: "gzip"},
+				},
+			},
+		}
+		err := task.validate()
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("invalid storage tenant", func(t *testing.T) {
+		task := createCollectionTask{
+			Req: &milvuspb.CreateCollectionRequest{
+				Base:      &commonpb.MsgBase{MsgType: commonpb.MsgType_CreateCollection},
+				ShardsNum: 1,
+				Properties: []*commonpb.KeyValuePair{
+					{Key: common.CollectionStorageTenantKey, Value: "../other"},
+				},
+			},
+		}
+		err := task.validate()
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("invalid external path", func(t *testing.T) {
+		task := createCollectionTask{
+			Req: &milvuspb.CreateCollectionRequest{
+				Base:      &commonpb.MsgBase{MsgType: commonpb.MsgType_CreateCollection},
+				ShardsNum: 1,
+				Properties: []*commonpb.KeyValuePair{
+					{Key: common.CollectionExternalPathKey, Value: "lake/../other"},
+				},
+			},
+		}
+		err := task.validate()
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("invalid time bucket", func(t *testing.T) {
+		task := createCollectionTask{
+			Req: &milvuspb.CreateCollectionRequest{
+				Base:      &commonpb.MsgBase{MsgType: commonpb.MsgType_CreateCollection},
+				ShardsNum: 1,
+				Properties: []*commonpb.KeyValuePair{
+					{Key: common.CollectionTimeBucketFieldKey, Value: "event_time"},
+				},
+			},
+		}
+		err := task.validate()
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("invalid pk bucket num", func(t *testing.T) {
+		task := createCollectionTask{
+			Req: &milvuspb.CreateCollectionRequest{
+				Base:      &commonpb.MsgBase{MsgType: commonpb.MsgType_CreateCollection},
+				ShardsNum: 1,
+				Properties: []*commonpb.KeyValuePair{
+					{Key: common.CollectionPKBucketNumKey, Value: "0"},
+				},
+			},
+		}
+		err := task.validate()
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
 	t.Run("total collection num exceeds limit", func(t *testing.T) {
 		paramtable.Get().Save(Params.QuotaConfig.MaxCollectionNum.Key, strconv.Itoa(2))
 		defer paramtable.Get().Reset(Params.QuotaConfig.MaxCollectionNum.Key)
@@ -258,6 +342,98 @@ func Test_createCollectionTask_validate(t *testing.T) {
 }
 
 func Test_createCollectionTask_validateSchema(t *testing.T) {
+	t.Run("external collection", func(t *testing.T) {
+		collectionName := funcutil.GenRandomStr()
+		task := createCollectionTask{
+			Req: &milvuspb.CreateCollectionRequest{
+				Base:           &commonpb.MsgBase{MsgType: commonpb.MsgType_CreateCollection},
+				CollectionName: collectionName,
+				Properties: []*commonpb.KeyValuePair{
+					{Key: common.CollectionExternalPathKey, Value: "lake/table"},
+				},
+			},
+		}
+		schema := &schemapb.CollectionSchema{
+			Name: collectionName,
+			Fields: []*schemapb.FieldSchema{
+				{Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+				{Name: "key", IsPartitionKey: true, DataType: schemapb.DataType_Int64},
+			},
+		}
+		err := task.validateSchema(schema)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+		schema.Fields[1].IsPartitionKey = false
+		assert.NoError(t, task.validateSchema(schema))
+
+		schema.Fields[0].AutoID = true
+		err = task.validateSchema(schema)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("time bucket field", func(t *testing.T) {
+		collectionName := funcutil.GenRandomStr()
+		task := createCollectionTask{
+			Req: &milvuspb.CreateCollectionRequest{
+				Base:           &commonpb.MsgBase{MsgType: commonpb.MsgType_CreateCollection},
+				CollectionName: collectionName,
+				Properties: []*commonpb.KeyValuePair{
+					{Key: common.CollectionTimeBucketFieldKey, Value: "event_time"},
+					{Key: common.CollectionTimeBucketSecondsKey, Value: "3600"},
+				},
+			},
+		}
+		schema := &schemapb.CollectionSchema{
+			Name: collectionName,
+			Fields: []*schemapb.FieldSchema{
+				{Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+				{Name: "event_time", DataType: schemapb.DataType_Double},
+			},
+		}
+		err := task.validateSchema(schema)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+		schema.Fields[1].DataType = schemapb.DataType_Int64
+		assert.NoError(t, task.validateSchema(schema))
+
+		schema.Fields[1].Name = "other"
+		err = task.validateSchema(schema)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("clustering field", func(t *testing.T) {
+		collectionName := funcutil.GenRandomStr()
+		task := createCollectionTask{
+			Req: &milvuspb.CreateCollectionRequest{
+				Base:           &commonpb.MsgBase{MsgType: commonpb.MsgType_CreateCollection},
+				CollectionName: collectionName,
+				Properties: []*commonpb.KeyValuePair{
+					{Key: common.CollectionClusteringFieldKey, Value: "tenant_id"},
+				},
+			},
+		}
+		schema := &schemapb.CollectionSchema{
+			Name: collectionName,
+			Fields: []*schemapb.FieldSchema{
+				{Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+				{Name: "tenant_id", DataType: schemapb.DataType_JSON},
+			},
+		}
+		err := task.validateSchema(schema)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+		schema.Fields[1].DataType = schemapb.DataType_VarChar
+		assert.NoError(t, task.validateSchema(schema))
+
+		task.Req.Properties[0].Value = "pk"
+		err = task.validateSchema(schema)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+		task.Req.Properties[0].Value = "other"
+		err = task.validateSchema(schema)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
 	t.Run("name mismatch", func(t *testing.T) {
 		collectionName := funcutil.GenRandomStr()
 		otherName := collectionName + "_other"
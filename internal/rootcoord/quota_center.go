@@ -83,6 +83,7 @@ type collectionStates = map[milvuspb.QuotaState]commonpb.ErrorCode
 //  5. DQL queue latency protection ->  dqlRate = curDQLRate * CoolOffSpeed
 //  6. Search result protection ->	 	searchRate = curSearchRate * CoolOffSpeed
 //  7. GrowingSegsSize protection ->    dmlRate = maxDMLRate * (high - cur) / (high - low)
+//  8. Channel back pressure ->         dmlRate = maxDMLRate * (high - cur) / (high - low), deny writing to channel if exceeded
 //
 // If necessary, user can also manually force to deny RW requests.
 type QuotaCenter struct {
@@ -103,9 +104,10 @@ type QuotaCenter struct {
 	readableCollections []int64
 	writableCollections []int64
 
-	currentRates map[int64]collectionRates
-	quotaStates  map[int64]collectionStates
-	tsoAllocator tso.Allocator
+	currentRates   map[int64]collectionRates
+	quotaStates    map[int64]collectionStates
+	deniedChannels map[int64][]string
+	tsoAllocator   tso.Allocator
 
 	rateAllocateStrategy RateAllocateStrategy
 
@@ -121,6 +123,7 @@ func NewQuotaCenter(proxies proxyutil.ProxyClientManagerInterface, queryCoord ty
 		dataCoord:           dataCoord,
 		currentRates:        make(map[int64]map[internalpb.RateType]Limit),
 		quotaStates:         make(map[int64]map[milvuspb.QuotaState]commonpb.ErrorCode),
+		deniedChannels:      make(map[int64][]string),
 		tsoAllocator:        tsoAllocator,
 		meta:                meta,
 		readableCollections: make([]int64, 0),
@@ -453,6 +456,8 @@ func (q *QuotaCenter) calculateWriteRates() error {
 	updateCollectionFactor(memFactors)
 	growingSegFactors := q.getGrowingSegmentsSizeFactor()
 	updateCollectionFactor(growingSegFactors)
+	channelFactors := q.getChannelPressureFactor()
+	updateCollectionFactor(channelFactors)
 
 	for collection, factor := range collectionFactors {
 		metrics.RootCoordRateLimitRatio.WithLabelValues(fmt.Sprint(collection)).Set(1 - factor)
@@ -679,6 +684,54 @@ func (q *QuotaCenter) getGrowingSegmentsSizeFactor() map[int64]float64 {
 	return collectionFactor
 }
 
+// getChannelPressureFactor returns the write factors of the collections by the pressure of their channels in DataNodes,
+// the writing to the channels with pressure above the high watermark is denied instead of the whole collection.
+func (q *QuotaCenter) getChannelPressureFactor() map[int64]float64 {
+	log := log.Ctx(context.Background()).WithRateGroup("rootcoord.QuotaCenter", 1.0, 60.0)
+	if !Params.QuotaConfig.ChannelBackPressureEnabled.GetAsBool() {
+		return make(map[int64]float64)
+	}
+
+	low := Params.QuotaConfig.ChannelPressureLowWaterLevel.GetAsFloat()
+	high := Params.QuotaConfig.ChannelPressureHighWaterLevel.GetAsFloat()
+
+	collectionFactor := make(map[int64]float64)
+	for nodeID, metric := range q.dataNodeMetrics {
+		for _, channel := range metric.Channels {
+			if channel.Pressure <= low {
+				continue
+			}
+			if channel.Pressure >= high {
+				q.deniedChannels[channel.CollectionID] = append(q.deniedChannels[channel.CollectionID], channel.Channel)
+				log.RatedWarn(10, "QuotaCenter: DataNode channel pressure to high water level, deny writing to channel",
+					zap.String("Node", fmt.Sprintf("%s-%d", typeutil.DataNodeRole, nodeID)),
+					zap.Int64("collection", channel.CollectionID),
+					zap.String("channel", channel.Channel),
+					zap.Int64("bufferSize", channel.BufferSize),
+					zap.Int("pendingSyncs", channel.PendingSyncs),
+					zap.Float64("curWatermark", channel.Pressure),
+					zap.Float64("highWatermark", high))
+				continue
+			}
+			factor := (high - channel.Pressure) / (high - low)
+			if cur, ok := collectionFactor[channel.CollectionID]; !ok || cur > factor {
+				collectionFactor[channel.CollectionID] = factor
+			}
+			log.RatedWarn(10, "QuotaCenter: DataNode channel pressure to low water level, limit writing rate",
+				zap.String("Node", fmt.Sprintf("%s-%d", typeutil.DataNodeRole, nodeID)),
+				zap.Int64("collection", channel.CollectionID),
+				zap.String("channel", channel.Channel),
+				zap.Int64("bufferSize", channel.BufferSize),
+				zap.Int("pendingSyncs", channel.PendingSyncs),
+				zap.Float64("curWatermark", channel.Pressure),
+				zap.Float64("lowWatermark", low),
+				zap.Float64("highWatermark", high),
+				zap.Float64("factor", factor))
+		}
+	}
+	return collectionFactor
+}
+
 // calculateRates calculates target rates by different strategies.
 func (q *QuotaCenter) calculateRates() error {
 	q.resetAllCurrentRates()
@@ -695,6 +748,7 @@ func (q *QuotaCenter) calculateRates() error {
 
 func (q *QuotaCenter) resetAllCurrentRates() {
 	q.quotaStates = make(map[int64]map[milvuspb.QuotaState]commonpb.ErrorCode)
+	q.deniedChannels = make(map[int64][]string)
 	q.currentRates = map[int64]map[internalpb.RateType]ratelimitutil.Limit{}
 	for _, collection := range q.writableCollections {
 		q.resetCurrentRate(internalpb.RateType_DMLInsert, collection)
@@ -821,10 +875,11 @@ func (q *QuotaCenter) setRates() error {
 		}
 
 		return &proxypb.CollectionRate{
-			Collection: collection,
-			Rates:      rates,
-			States:     lo.Keys(q.quotaStates[collection]),
-			Codes:      lo.Values(q.quotaStates[collection]),
+			Collection:     collection,
+			Rates:          rates,
+			States:         lo.Keys(q.quotaStates[collection]),
+			Codes:          lo.Values(q.quotaStates[collection]),
+			DeniedChannels: q.deniedChannels[collection],
 		}
 	}
 
@@ -32,6 +32,7 @@ import (
 	"github.com/milvus-io/milvus/internal/metastore/model"
 	"github.com/milvus-io/milvus/internal/mocks"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/proxypb"
 	mockrootcoord "github.com/milvus-io/milvus/internal/rootcoord/mocks"
 	"github.com/milvus-io/milvus/internal/util/proxyutil"
 	"github.com/milvus-io/milvus/pkg/common"
@@ -485,6 +486,54 @@ func TestQuotaCenter(t *testing.T) {
 		paramtable.Get().Reset(Params.QuotaConfig.GrowingSegmentsSizeHighWaterLevel.Key)
 	})
 
+	t.Run("test channel pressure factors", func(t *testing.T) {
+		qc := mocks.NewMockQueryCoordClient(t)
+		meta := mockrootcoord.NewIMetaTable(t)
+		meta.EXPECT().GetCollectionByID(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, merr.ErrCollectionNotFound).Maybe()
+		quotaCenter := NewQuotaCenter(pcm, qc, dc, core.tsoAllocator, meta)
+		paramtable.Get().Save(Params.QuotaConfig.ChannelPressureLowWaterLevel.Key, "0.6")
+		paramtable.Get().Save(Params.QuotaConfig.ChannelPressureHighWaterLevel.Key, "0.9")
+		defer func() {
+			paramtable.Get().Reset(Params.QuotaConfig.ChannelBackPressureEnabled.Key)
+			paramtable.Get().Reset(Params.QuotaConfig.ChannelPressureLowWaterLevel.Key)
+			paramtable.Get().Reset(Params.QuotaConfig.ChannelPressureHighWaterLevel.Key)
+		}()
+
+		quotaCenter.writableCollections = []int64{1, 2, 3}
+		quotaCenter.dataNodeMetrics = map[UniqueID]*metricsinfo.DataNodeQuotaMetrics{
+			1: {
+				Effect: metricsinfo.NodeEffect{NodeID: 1, CollectionIDs: []int64{1, 2, 3}},
+				Channels: []metricsinfo.ChannelPressure{
+					{Channel: "ch-1", CollectionID: 1, Pressure: 0.3},
+					{Channel: "ch-2", CollectionID: 2, Pressure: 0.75},
+					{Channel: "ch-3", CollectionID: 2, Pressure: 0.69},
+					{Channel: "ch-4", CollectionID: 3, Pressure: 0.95},
+				},
+			},
+		}
+
+		quotaCenter.resetAllCurrentRates()
+		factors := quotaCenter.getChannelPressureFactor()
+		assert.Len(t, factors, 1)
+		assert.InDelta(t, 0.5, factors[2], 0.01)
+		assert.Empty(t, quotaCenter.deniedChannels[1])
+		assert.Empty(t, quotaCenter.deniedChannels[2])
+		assert.ElementsMatch(t, []string{"ch-4"}, quotaCenter.deniedChannels[3])
+
+		// the writing to the collection is not denied, but to its channel
+		quotaCenter.resetAllCurrentRates()
+		err = quotaCenter.calculateWriteRates()
+		assert.NoError(t, err)
+		assert.NotContains(t, quotaCenter.quotaStates[3], milvuspb.QuotaState_DenyToWrite)
+		assert.ElementsMatch(t, []string{"ch-4"}, quotaCenter.deniedChannels[3])
+
+		paramtable.Get().Save(Params.QuotaConfig.ChannelBackPressureEnabled.Key, "false")
+		quotaCenter.resetAllCurrentRates()
+		factors = quotaCenter.getChannelPressureFactor()
+		assert.Empty(t, factors)
+		assert.Empty(t, quotaCenter.deniedChannels)
+	})
+
 	t.Run("test checkDiskQuota", func(t *testing.T) {
 		qc := mocks.NewMockQueryCoordClient(t)
 		meta := mockrootcoord.NewIMetaTable(t)
@@ -533,7 +582,11 @@ func TestQuotaCenter(t *testing.T) {
 	t.Run("test setRates", func(t *testing.T) {
 		qc := mocks.NewMockQueryCoordClient(t)
 		pcm.EXPECT().GetProxyCount().Return(1)
-		pcm.EXPECT().SetRates(mock.Anything, mock.Anything).Return(nil)
+		pcm.EXPECT().SetRates(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *proxypb.SetRatesRequest) error {
+			assert.Equal(t, 1, len(req.GetRates()))
+			assert.ElementsMatch(t, []string{"ch-1"}, req.GetRates()[0].GetDeniedChannels())
+			return nil
+		})
 		meta := mockrootcoord.NewIMetaTable(t)
 		meta.EXPECT().GetCollectionByID(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, merr.ErrCollectionNotFound).Maybe()
 		quotaCenter := NewQuotaCenter(pcm, qc, dc, core.tsoAllocator, meta)
@@ -544,6 +597,7 @@ func TestQuotaCenter(t *testing.T) {
 		quotaCenter.currentRates[collectionID][internalpb.RateType_DMLInsert] = 100
 		quotaCenter.quotaStates[collectionID][milvuspb.QuotaState_DenyToWrite] = commonpb.ErrorCode_MemoryQuotaExhausted
 		quotaCenter.quotaStates[collectionID][milvuspb.QuotaState_DenyToRead] = commonpb.ErrorCode_ForceDeny
+		quotaCenter.deniedChannels[collectionID] = []string{"ch-1"}
 		err = quotaCenter.setRates()
 		assert.NoError(t, err)
 	})
@@ -37,6 +37,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/allocator"
+	"github.com/milvus-io/milvus/internal/http/standby"
 	"github.com/milvus-io/milvus/internal/kv"
 	etcdkv "github.com/milvus-io/milvus/internal/kv/etcd"
 	"github.com/milvus-io/milvus/internal/kv/tikv"
@@ -162,6 +163,11 @@ func (c *Core) GetStateCode() commonpb.StateCode {
 	return commonpb.StateCode(c.stateCode.Load())
 }
 
+// StandbyStatus returns the active/standby status of RootCoord.
+func (c *Core) StandbyStatus() *standby.Status {
+	return standby.NewStatus(typeutil.RootCoordRole, c.session.GetServerID(), c.GetStateCode())
+}
+
 func (c *Core) sendTimeTick(t Timestamp, reason string) error {
 	pc := c.chanTimeTick.listDmlChannels()
 	pt := make([]uint64, len(pc))
@@ -545,6 +551,7 @@ func (c *Core) Init() error {
 			return err
 		}
 		c.UpdateStateCode(commonpb.StateCode_StandBy)
+		standby.Register(c)
 		log.Info("RootCoord enter standby mode successfully")
 	} else {
 		c.initOnce.Do(func() {
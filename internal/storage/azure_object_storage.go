@@ -20,6 +20,7 @@ import (
 	"context"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -37,29 +38,13 @@ import (
 
 type AzureObjectStorage struct {
 	*service.Client
+	// blockSize and concurrency of the block blob uploads, the defaults of the sdk if non-positive
+	blockSize   int64
+	concurrency int
 }
 
 func newAzureObjectStorageWithConfig(ctx context.Context, c *config) (*AzureObjectStorage, error) {
-	var client *service.Client
-	var err error
-	if c.useIAM {
-		cred, credErr := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
-			ClientID:      os.Getenv("AZURE_CLIENT_ID"),
-			TenantID:      os.Getenv("AZURE_TENANT_ID"),
-			TokenFilePath: os.Getenv("AZURE_FEDERATED_TOKEN_FILE"),
-		})
-		if credErr != nil {
-			return nil, credErr
-		}
-		client, err = service.NewClient("https://"+c.accessKeyID+".blob."+c.address+"/", cred, &service.ClientOptions{})
-	} else {
-		connectionString := os.Getenv("AZURE_STORAGE_CONNECTION_STRING")
-		if connectionString == "" {
-			connectionString = "DefaultEndpointsProtocol=https;AccountName=" + c.accessKeyID +
-				";AccountKey=" + c.secretAccessKeyID + ";EndpointSuffix=" + c.address
-		}
-		client, err = service.NewClientFromConnectionString(connectionString, &service.ClientOptions{})
-	}
+	client, err := newAzureServiceClient(c)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +72,50 @@ func newAzureObjectStorageWithConfig(ctx context.Context, c *config) (*AzureObje
 	if err != nil {
 		return nil, err
 	}
-	return &AzureObjectStorage{Client: client}, nil
+	return &AzureObjectStorage{Client: client, blockSize: c.uploadPartSize, concurrency: c.uploadConcurrency}, nil
+}
+
+// newAzureServiceClient returns the client of the storage account authenticated by the config:
+//   - the workload identity if useIAM and AZURE_FEDERATED_TOKEN_FILE is set, otherwise the managed identity of the host,
+//     the identity of AZURE_CLIENT_ID if set;
+//   - the SAS token of AZURE_STORAGE_SAS_TOKEN if set;
+//   - the connection string of AZURE_STORAGE_CONNECTION_STRING if set, otherwise the account key.
+//
+// The account name is the accessKeyID, and the address is the endpoint suffix, i.e. core.windows.net.
+func newAzureServiceClient(c *config) (*service.Client, error) {
+	serviceURL := "https://" + c.accessKeyID + ".blob." + c.address + "/"
+	if c.useIAM {
+		var (
+			cred azcore.TokenCredential
+			err  error
+		)
+		if tokenFile := os.Getenv("AZURE_FEDERATED_TOKEN_FILE"); tokenFile != "" {
+			cred, err = azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+				ClientID:      os.Getenv("AZURE_CLIENT_ID"),
+				TenantID:      os.Getenv("AZURE_TENANT_ID"),
+				TokenFilePath: tokenFile,
+			})
+		} else {
+			opts := &azidentity.ManagedIdentityCredentialOptions{}
+			if clientID := os.Getenv("AZURE_CLIENT_ID"); clientID != "" {
+				opts.ID = azidentity.ClientID(clientID)
+			}
+			cred, err = azidentity.NewManagedIdentityCredential(opts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return service.NewClient(serviceURL, cred, &service.ClientOptions{})
+	}
+	if sasToken := os.Getenv("AZURE_STORAGE_SAS_TOKEN"); sasToken != "" {
+		return service.NewClientWithNoCredential(serviceURL+"?"+strings.TrimPrefix(sasToken, "?"), &service.ClientOptions{})
+	}
+	connectionString := os.Getenv("AZURE_STORAGE_CONNECTION_STRING")
+	if connectionString == "" {
+		connectionString = "DefaultEndpointsProtocol=https;AccountName=" + c.accessKeyID +
+			";AccountKey=" + c.secretAccessKeyID + ";EndpointSuffix=" + c.address
+	}
+	return service.NewClientFromConnectionString(connectionString, &service.ClientOptions{})
 }
 
 // BlobReader is implemented because Azure's stream body does not have ReadAt and Seek interfaces.
@@ -185,7 +213,15 @@ func (AzureObjectStorage *AzureObjectStorage) GetObject(ctx context.Context, buc
 }
 
 func (AzureObjectStorage *AzureObjectStorage) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64) error {
-	_, err := AzureObjectStorage.Client.NewContainerClient(bucketName).NewBlockBlobClient(objectName).UploadStream(ctx, reader, &azblob.UploadStreamOptions{})
+	// the objects larger than a block are uploaded by the blocks staged concurrently and then committed
+	opts := &azblob.UploadStreamOptions{}
+	if AzureObjectStorage.blockSize > 0 {
+		opts.BlockSize = AzureObjectStorage.blockSize
+	}
+	if AzureObjectStorage.concurrency > 0 {
+		opts.Concurrency = AzureObjectStorage.concurrency
+	}
+	_, err := AzureObjectStorage.Client.NewContainerClient(bucketName).NewBlockBlobClient(objectName).UploadStream(ctx, reader, opts)
 	return checkObjectStorageError(objectName, err)
 }
 
@@ -197,6 +233,14 @@ func (AzureObjectStorage *AzureObjectStorage) StatObject(ctx context.Context, bu
 	return *info.ContentLength, nil
 }
 
+func (AzureObjectStorage *AzureObjectStorage) EtagObject(ctx context.Context, bucketName, objectName string) (string, error) {
+	info, err := AzureObjectStorage.Client.NewContainerClient(bucketName).NewBlockBlobClient(objectName).GetProperties(ctx, &blob.GetPropertiesOptions{})
+	if err != nil {
+		return "", checkObjectStorageError(objectName, err)
+	}
+	return string(*info.ETag), nil
+}
+
 func (AzureObjectStorage *AzureObjectStorage) ListObjects(ctx context.Context, bucketName string, prefix string, recursive bool) ([]string, []time.Time, error) {
 	var objectsKeys []string
 	var modTimes []time.Time
@@ -204,8 +248,8 @@ func (AzureObjectStorage *AzureObjectStorage) ListObjects(ctx context.Context, b
 		pager := AzureObjectStorage.Client.NewContainerClient(bucketName).NewListBlobsFlatPager(&azblob.ListBlobsFlatOptions{
 			Prefix: &prefix,
 		})
-		if pager.More() {
-			pageResp, err := pager.NextPage(context.Background())
+		for pager.More() {
+			pageResp, err := pager.NextPage(ctx)
 			if err != nil {
 				return []string{}, []time.Time{}, checkObjectStorageError(prefix, err)
 			}
@@ -218,8 +262,8 @@ func (AzureObjectStorage *AzureObjectStorage) ListObjects(ctx context.Context, b
 		pager := AzureObjectStorage.Client.NewContainerClient(bucketName).NewListBlobsHierarchyPager("/", &container.ListBlobsHierarchyOptions{
 			Prefix: &prefix,
 		})
-		if pager.More() {
-			pageResp, err := pager.NextPage(context.Background())
+		for pager.More() {
+			pageResp, err := pager.NextPage(ctx)
 			if err != nil {
 				return []string{}, []time.Time{}, checkObjectStorageError(prefix, err)
 			}
@@ -403,3 +403,52 @@ func TestReadFile(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestAzureServiceClient(t *testing.T) {
+	c := &config{
+		accessKeyID:       "account",
+		secretAccessKeyID: "a2V5",
+		address:           "core.windows.net",
+		cloudProvider:     CloudProviderAzure,
+	}
+
+	t.Run("sas token", func(t *testing.T) {
+		t.Setenv("AZURE_STORAGE_CONNECTION_STRING", "")
+		t.Setenv("AZURE_STORAGE_SAS_TOKEN", "?sv=2022-11-02&sig=mock")
+		client, err := newAzureServiceClient(c)
+		require.NoError(t, err)
+		assert.Equal(t, "https://account.blob.core.windows.net/?sv=2022-11-02&sig=mock", client.URL())
+	})
+
+	t.Run("account key", func(t *testing.T) {
+		t.Setenv("AZURE_STORAGE_CONNECTION_STRING", "")
+		t.Setenv("AZURE_STORAGE_SAS_TOKEN", "")
+		client, err := newAzureServiceClient(c)
+		require.NoError(t, err)
+		assert.Equal(t, "https://account.blob.core.windows.net/", client.URL())
+	})
+
+	t.Run("managed identity", func(t *testing.T) {
+		t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+		t.Setenv("AZURE_CLIENT_ID", "client")
+		iam := *c
+		iam.useIAM = true
+		client, err := newAzureServiceClient(&iam)
+		require.NoError(t, err)
+		assert.Equal(t, "https://account.blob.core.windows.net/", client.URL())
+	})
+}
+
+func TestRemoteStorageOf(t *testing.T) {
+	storageType, cloudProvider := RemoteStorageOf(StorageTypeAzure, CloudProviderAWS)
+	assert.Equal(t, "remote", storageType)
+	assert.Equal(t, CloudProviderAzure, cloudProvider)
+
+	storageType, cloudProvider = RemoteStorageOf("remote", CloudProviderAWS)
+	assert.Equal(t, "remote", storageType)
+	assert.Equal(t, CloudProviderAWS, cloudProvider)
+
+	storageType, cloudProvider = RemoteStorageOf("remote", CloudProviderGCPNative)
+	assert.Equal(t, "remote", storageType)
+	assert.Equal(t, CloudProviderGCP, cloudProvider)
+}
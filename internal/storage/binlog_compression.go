@@ -0,0 +1,129 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/pierrec/lz4/v4"
+
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/compressor"
+)
+
+// The compressed binlog wraps the whole binlog compressed by a codec:
+//
+//	magic (4 bytes) | codec (1 byte) | uncompressed size (uint64) | compressed binlog
+//
+// Readers detect it by the magic, so the binlogs of any codec and the uncompressed ones could be read alike.
+// The uncompressed size is recorded for the codecs decompressing into a preallocated buffer, e.g. in segcore.
+var compressedBinlogMagic = []byte("MVCB")
+
+const compressedBinlogHeaderSize = 4 + 1 + 8
+
+// codec ids in the compressed binlog header, keep consistent with internal/core/src/common/Consts.h
+const (
+	compressedBinlogCodecZstd   byte = 1
+	compressedBinlogCodecLz4    byte = 2
+	compressedBinlogCodecSnappy byte = 3
+)
+
+// maxCompressionRatios are the upper bounds of the compression ratios of the codecs by their formats,
+// the uncompressed size beyond the bound of the payload is corrupted, which is rejected rather than allocated.
+var maxCompressionRatios = map[byte]uint64{
+	compressedBinlogCodecZstd:   1 << 15, // a raw block of 128KB in a RLE block of 4 bytes
+	compressedBinlogCodecLz4:    256,     // a match extended by a length byte of 255 per byte
+	compressedBinlogCodecSnappy: 22,      // a copy of 64 bytes in 3 bytes
+}
+
+// IsCompressedBinlog returns whether the binlog is wrapped by CompressBinlog.
+func IsCompressedBinlog(data []byte) bool {
+	return len(data) >= compressedBinlogHeaderSize && bytes.HasPrefix(data, compressedBinlogMagic)
+}
+
+// CompressBinlog compresses the binlog by the codec, one of the common.BinlogCompression* values.
+// The binlog is returned as is if the codec is none or empty, and the parquet binlogs are not compressed
+// again as their columns are compressed already and they are read by the external engines directly.
+func CompressBinlog(compression string, data []byte) ([]byte, error) {
+	if compression == "" || compression == common.BinlogCompressionNone || IsParquetBinlog(data) {
+		return data, nil
+	}
+
+	header := make([]byte, compressedBinlogHeaderSize, compressedBinlogHeaderSize+len(data)/2)
+	copy(header, compressedBinlogMagic)
+	common.Endian.PutUint64(header[5:], uint64(len(data)))
+	switch compression {
+	case common.BinlogCompressionZstd:
+		header[4] = compressedBinlogCodecZstd
+		return compressor.ZstdCompressBytes(data, header), nil
+	case common.BinlogCompressionLz4:
+		header[4] = compressedBinlogCodecLz4
+		buf := bytes.NewBuffer(header)
+		writer := lz4.NewWriter(buf)
+		if _, err := writer.Write(data); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case common.BinlogCompressionSnappy:
+		header[4] = compressedBinlogCodecSnappy
+		return append(header, snappy.Encode(nil, data)...), nil
+	default:
+		return nil, fmt.Errorf("unknown binlog compression %s", compression)
+	}
+}
+
+// DecompressBinlog returns the binlog decompressed if it's compressed by CompressBinlog, or the binlog as is.
+func DecompressBinlog(data []byte) ([]byte, error) {
+	if !IsCompressedBinlog(data) {
+		return data, nil
+	}
+
+	size := common.Endian.Uint64(data[5:compressedBinlogHeaderSize])
+	payload := data[compressedBinlogHeaderSize:]
+	if ratio, ok := maxCompressionRatios[data[4]]; ok && size > uint64(len(payload))*ratio {
+		return nil, fmt.Errorf("uncompressed size %d of the compressed binlog exceeds the ratio %d of the %d bytes",
+			size, ratio, len(payload))
+	}
+	var (
+		decompressed []byte
+		err          error
+	)
+	switch data[4] {
+	case compressedBinlogCodecZstd:
+		decompressed, err = compressor.ZstdDecompressBytes(payload, make([]byte, 0, size))
+	case compressedBinlogCodecLz4:
+		decompressed = make([]byte, size)
+		_, err = io.ReadFull(lz4.NewReader(bytes.NewReader(payload)), decompressed)
+	case compressedBinlogCodecSnappy:
+		decompressed, err = snappy.Decode(make([]byte, size), payload)
+	default:
+		return nil, fmt.Errorf("unknown codec %d of the compressed binlog", data[4])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress binlog: %w", err)
+	}
+	if uint64(len(decompressed)) != size {
+		return nil, fmt.Errorf("decompressed binlog of %d bytes, expected %d", len(decompressed), size)
+	}
+	return decompressed, nil
+}
@@ -0,0 +1,88 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+func TestCompressBinlog(t *testing.T) {
+	dData := &DeleteData{}
+	for i := int64(0); i < 100; i++ {
+		dData.Append(NewInt64PrimaryKey(i), uint64(i+1))
+	}
+	blob, err := NewDeleteCodec().Serialize(1, 10, 100, dData)
+	require.NoError(t, err)
+
+	for _, compression := range []string{common.BinlogCompressionZstd, common.BinlogCompressionLz4, common.BinlogCompressionSnappy} {
+		t.Run(compression, func(t *testing.T) {
+			compressed, err := CompressBinlog(compression, blob.GetValue())
+			require.NoError(t, err)
+			assert.True(t, IsCompressedBinlog(compressed))
+			assert.Less(t, len(compressed), len(blob.GetValue()))
+
+			decompressed, err := DecompressBinlog(compressed)
+			require.NoError(t, err)
+			assert.Equal(t, blob.GetValue(), decompressed)
+
+			// read by the codecs directly
+			_, _, _, err = NewDeleteCodec().Deserialize([]*Blob{{Value: compressed}})
+			assert.NoError(t, err)
+		})
+	}
+
+	// uncompressed binlogs are read as is
+	uncompressed, err := CompressBinlog(common.BinlogCompressionNone, blob.GetValue())
+	require.NoError(t, err)
+	assert.False(t, IsCompressedBinlog(uncompressed))
+	decompressed, err := DecompressBinlog(uncompressed)
+	require.NoError(t, err)
+	assert.Equal(t, blob.GetValue(), decompressed)
+
+	_, err = CompressBinlog("gzip", blob.GetValue())
+	assert.Error(t, err)
+
+	// corrupted
+	compressed, err := CompressBinlog(common.BinlogCompressionZstd, blob.GetValue())
+	require.NoError(t, err)
+	_, err = DecompressBinlog(compressed[:len(compressed)-8])
+	assert.Error(t, err)
+	compressed[4] = 0
+	_, err = DecompressBinlog(compressed)
+	assert.Error(t, err)
+
+	for _, compression := range []string{common.BinlogCompressionZstd, common.BinlogCompressionLz4, common.BinlogCompressionSnappy} {
+		// the highly compressed binlogs are within the ratio of the codec
+		zeros := make([]byte, 16<<20)
+		compressed, err := CompressBinlog(compression, zeros)
+		require.NoError(t, err)
+		decompressed, err := DecompressBinlog(compressed)
+		require.NoError(t, err, compression)
+		assert.Equal(t, zeros, decompressed)
+
+		// the uncompressed size is rejected before allocated if it's beyond the ratio
+		common.Endian.PutUint64(compressed[5:], math.MaxUint64)
+		_, err = DecompressBinlog(compressed)
+		assert.ErrorContains(t, err, "exceeds the ratio", compression)
+	}
+}
@@ -0,0 +1,93 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+
+	"github.com/apache/arrow/go/v12/parquet/compress"
+	"github.com/apache/arrow/go/v12/parquet/file"
+)
+
+// Binlog encodings older than the ones written by this node, returned by InspectBinlogEncoding.
+const (
+	// OutdatedLegacyLayout is a binlog written in the layout of early 2.0 releases.
+	OutdatedLegacyLayout = "legacy_layout"
+	// OutdatedFormatVersion is a binlog of a descriptor format version older than BinlogFormatVersion.
+	OutdatedFormatVersion = "format_version"
+	// OutdatedUncompressed is a binlog whose payloads are not compressed.
+	OutdatedUncompressed = "uncompressed"
+)
+
+// InspectBinlogEncoding returns the encodings of the binlog which are older than the ones
+// this node writes, empty if the binlog is encoded as it would be written now.
+// Only the first event is inspected for the payload encoding, as all events of a binlog are written alike.
+func InspectBinlogEncoding(data []byte) ([]string, error) {
+	data, err := DecompressBinlog(data)
+	if err != nil {
+		return nil, err
+	}
+	if IsParquetBinlog(data) {
+		reader, err := file.NewParquetReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		if !isParquetCompressed(reader) {
+			return []string{OutdatedUncompressed}, nil
+		}
+		return nil, nil
+	}
+
+	var outdated []string
+	if IsLegacyBinlog(data) {
+		outdated = append(outdated, OutdatedLegacyLayout)
+	}
+	reader, err := NewBinlogReader(data)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	if reader.FormatVersion() < BinlogFormatVersion {
+		outdated = append(outdated, OutdatedFormatVersion)
+	}
+	eventReader, err := reader.NextEventReader()
+	if err != nil {
+		return nil, err
+	}
+	if eventReader != nil {
+		payloadReader, ok := eventReader.PayloadReaderInterface.(*PayloadReader)
+		if ok && !isParquetCompressed(payloadReader.reader) {
+			outdated = append(outdated, OutdatedUncompressed)
+		}
+	}
+	return outdated, nil
+}
+
+// isParquetCompressed returns whether all column chunks of the parquet file are compressed.
+func isParquetCompressed(reader *file.Reader) bool {
+	for i := 0; i < reader.NumRowGroups(); i++ {
+		rowGroup := reader.MetaData().RowGroup(i)
+		for j := 0; j < rowGroup.NumColumns(); j++ {
+			column, err := rowGroup.ColumnChunk(j)
+			if err != nil || column.Compression() == compress.Codecs.Uncompressed {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/parquet/file"
+	"github.com/apache/arrow/go/v12/parquet/pqarrow"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/etcdpb"
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+func TestInspectBinlogEncoding(t *testing.T) {
+	s := &LegacyBinlogSuite{}
+	s.SetT(t)
+	data := s.writeBinlog()
+
+	outdated, err := InspectBinlogEncoding(data)
+	assert.NoError(t, err)
+	assert.Empty(t, outdated)
+
+	outdated, err = InspectBinlogEncoding(s.toLegacy(data, true))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{OutdatedLegacyLayout, OutdatedFormatVersion}, outdated)
+
+	schema := &schemapb.CollectionSchema{Fields: []*schemapb.FieldSchema{
+		{FieldID: 0, Name: "row_id", DataType: schemapb.DataType_Int64},
+		{FieldID: 1, Name: "Timestamp", DataType: schemapb.DataType_Int64},
+		{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+	}}
+	codec := NewInsertCodecWithSchema(&etcdpb.CollectionMeta{Schema: schema})
+	codec.BinlogFormat = common.BinlogFormatParquet
+	blobs, err := codec.Serialize(1, 1, &InsertData{Data: map[FieldID]FieldData{
+		0:   &Int64FieldData{Data: []int64{1, 2}},
+		1:   &Int64FieldData{Data: []int64{10, 20}},
+		100: &Int64FieldData{Data: []int64{3, 4}},
+	}})
+	assert.NoError(t, err)
+	for _, blob := range blobs {
+		outdated, err = InspectBinlogEncoding(blob.GetValue())
+		assert.NoError(t, err)
+		assert.Empty(t, outdated)
+	}
+
+	_, err = InspectBinlogEncoding([]byte("invalid"))
+	assert.Error(t, err)
+}
+
+func TestIsParquetCompressed(t *testing.T) {
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	builder.AppendValues([]int64{1, 2, 3}, nil)
+	column := builder.NewArray()
+	defer column.Release()
+	arrowSchema := arrow.NewSchema([]arrow.Field{{Name: "val", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	record := array.NewRecord(arrowSchema, []arrow.Array{column}, 3)
+	defer record.Release()
+	table := array.NewTableFromRecords(arrowSchema, []arrow.Record{record})
+	defer table.Release()
+
+	// parquet files are written without compression by default
+	buf := &bytes.Buffer{}
+	assert.NoError(t, pqarrow.WriteTable(table, buf, 1024, nil, pqarrow.DefaultWriterProps()))
+	reader, err := file.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.False(t, isParquetCompressed(reader))
+	reader.Close()
+
+	w, err := NewPayloadWriter(schemapb.DataType_Int64)
+	assert.NoError(t, err)
+	assert.NoError(t, w.AddInt64ToPayload([]int64{1, 2, 3}))
+	assert.NoError(t, w.FinishPayloadWriter())
+	payload, err := w.GetPayloadBufferFromWriter()
+	assert.NoError(t, err)
+	reader, err = file.NewParquetReader(bytes.NewReader(payload))
+	assert.NoError(t, err)
+	assert.True(t, isParquetCompressed(reader))
+	reader.Close()
+}
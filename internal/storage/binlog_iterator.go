@@ -17,6 +17,8 @@
 package storage
 
 import (
+	"container/heap"
+	"fmt"
 	"sync/atomic"
 
 	"github.com/cockroachdb/errors"
@@ -88,24 +90,32 @@ func (itr *InsertBinlogIterator) Next() (interface{}, error) {
 		return nil, ErrNoMoreRecord
 	}
 
+	v, err := rowValue(itr.data, itr.pos, itr.PKfieldID, itr.PkType)
+	if err != nil {
+		return nil, err
+	}
+	itr.pos++
+	return v, nil
+}
+
+// rowValue returns the row at pos of data as the Value.
+func rowValue(data *InsertData, pos int, pkFieldID UniqueID, pkType schemapb.DataType) (*Value, error) {
 	m := make(map[FieldID]interface{})
-	for fieldID, fieldData := range itr.data.Data {
-		m[fieldID] = fieldData.GetRow(itr.pos)
+	for fieldID, fieldData := range data.Data {
+		m[fieldID] = fieldData.GetRow(pos)
 	}
-	pk, err := GenPrimaryKeyByRawData(itr.data.Data[itr.PKfieldID].GetRow(itr.pos), itr.PkType)
+	pk, err := GenPrimaryKeyByRawData(data.Data[pkFieldID].GetRow(pos), pkType)
 	if err != nil {
 		return nil, err
 	}
 
-	v := &Value{
-		ID:        itr.data.Data[common.RowIDField].GetRow(itr.pos).(int64),
-		Timestamp: itr.data.Data[common.TimeStampField].GetRow(itr.pos).(int64),
+	return &Value{
+		ID:        data.Data[common.RowIDField].GetRow(pos).(int64),
+		Timestamp: data.Data[common.TimeStampField].GetRow(pos).(int64),
 		PK:        pk,
 		IsDeleted: false,
 		Value:     m,
-	}
-	itr.pos++
-	return v, nil
+	}, nil
 }
 
 // Dispose disposes the iterator
@@ -125,6 +135,151 @@ func (itr *InsertBinlogIterator) isDisposed() bool {
 	return atomic.LoadInt32(&itr.dispose) == 1
 }
 
+// eventBinlogReader reads the events of a binlog one by one, i.e. BinlogReader and StreamBinlogReader.
+type eventBinlogReader interface {
+	NextEventReader() (*EventReader, error)
+	descriptor() *descriptorEvent
+	Close()
+}
+
+// InsertEventIterator is the iterator of the insert binlogs which deserializes the binlogs event by event,
+// only the events of the rows being iterated are kept deserialized, so the memory used is bounded by
+// the event size instead of the binlog size. The events of the field binlogs shall be aligned,
+// which is true for the binlogs written by InsertCodec.
+type InsertEventIterator struct {
+	dispose   int32 // 0: false, 1: true
+	readers   []eventBinlogReader
+	data      *InsertData // rows of the current events
+	PKfieldID int64
+	PkType    schemapb.DataType
+	pos       int
+	err       error
+}
+
+// NewInsertEventIterator creates a new iterator
+func NewInsertEventIterator(blobs []*Blob, PKfieldID UniqueID, pkType schemapb.DataType) (*InsertEventIterator, error) {
+	itr := &InsertEventIterator{PKfieldID: PKfieldID, PkType: pkType}
+	for _, blob := range blobs {
+		reader, err := NewBinlogReader(blob.GetValue())
+		if err != nil {
+			itr.closeReaders()
+			return nil, err
+		}
+		itr.readers = append(itr.readers, reader)
+	}
+	return itr, nil
+}
+
+// NewStreamInsertEventIterator creates a new iterator of the field binlogs read by the stream readers,
+// the readers are closed with the iterator.
+func NewStreamInsertEventIterator(readers []*StreamBinlogReader, PKfieldID UniqueID, pkType schemapb.DataType) *InsertEventIterator {
+	itr := &InsertEventIterator{PKfieldID: PKfieldID, PkType: pkType}
+	for _, reader := range readers {
+		itr.readers = append(itr.readers, reader)
+	}
+	return itr
+}
+
+// HasNext returns true if the iterator have unread record,
+// it returns true if the next events fail to be read as well, and Next returns the error.
+func (itr *InsertEventIterator) HasNext() bool {
+	if itr.isDisposed() {
+		return false
+	}
+	for !itr.hasNext() && itr.err == nil && itr.readers != nil {
+		itr.err = itr.nextEvents()
+	}
+	return itr.hasNext() || itr.err != nil
+}
+
+// Next returns the next record
+func (itr *InsertEventIterator) Next() (interface{}, error) {
+	if itr.isDisposed() {
+		return nil, ErrDisposed
+	}
+
+	if !itr.HasNext() {
+		return nil, ErrNoMoreRecord
+	}
+	if itr.err != nil {
+		return nil, itr.err
+	}
+
+	v, err := rowValue(itr.data, itr.pos, itr.PKfieldID, itr.PkType)
+	if err != nil {
+		return nil, err
+	}
+	itr.pos++
+	return v, nil
+}
+
+// Dispose disposes the iterator
+func (itr *InsertEventIterator) Dispose() {
+	if atomic.CompareAndSwapInt32(&itr.dispose, 0, 1) {
+		itr.closeReaders()
+		itr.data = nil
+	}
+}
+
+// nextEvents deserializes the next event of every field binlog, the readers are closed if all events are read.
+func (itr *InsertEventIterator) nextEvents() error {
+	data := &InsertData{Data: make(map[FieldID]FieldData)}
+	rowNum := -1
+	for _, reader := range itr.readers {
+		eventReader, err := reader.NextEventReader()
+		if err != nil {
+			return err
+		}
+		descriptor := reader.descriptor()
+		if eventReader == nil {
+			if rowNum > 0 {
+				return fmt.Errorf("events of field %d are less than the other fields", descriptor.FieldID)
+			}
+			rowNum = 0
+			continue
+		}
+		length, err := appendEventPayload(eventReader, descriptor.PayloadDataType, descriptor.FieldID, 0, data)
+		eventReader.Close()
+		if err != nil {
+			return err
+		}
+		if rowNum >= 0 && rowNum != length {
+			return fmt.Errorf("row num of the event of field %d is %d, mismatches with the other fields %d", descriptor.FieldID, length, rowNum)
+		}
+		rowNum = length
+	}
+
+	if rowNum <= 0 {
+		itr.closeReaders()
+		return nil
+	}
+	itr.data = data
+	itr.pos = 0
+	return nil
+}
+
+func (itr *InsertEventIterator) closeReaders() {
+	for _, reader := range itr.readers {
+		reader.Close()
+	}
+	itr.readers = nil
+}
+
+func (itr *InsertEventIterator) hasNext() bool {
+	if itr.data == nil {
+		return false
+	}
+	_, ok := itr.data.Data[common.RowIDField]
+	if !ok {
+		return false
+	}
+	return itr.pos < itr.data.Data[common.RowIDField].RowNum()
+}
+
+func (itr *InsertEventIterator) isDisposed() bool {
+	return atomic.LoadInt32(&itr.dispose) == 1
+}
+
 /*
 type DeltalogIterator struct {
 	dispose int32
@@ -277,6 +432,132 @@ func (itr *MergeIterator) hasNext() bool {
 	return true
 }
 
+// PKMergeIterator is the k-way merge of the iterators of Value ordered by the primary key and the timestamp,
+// only the head record of each iterator is kept, so the output is sorted if every iterator is sorted.
+type PKMergeIterator struct {
+	disposed  int32
+	iterators []Iterator
+	heads     valueHeap
+	inited    bool
+	err       error
+}
+
+// NewPKMergeIterator return a new PKMergeIterator.
+func NewPKMergeIterator(iterators []Iterator) *PKMergeIterator {
+	return &PKMergeIterator{
+		iterators: iterators,
+		heads:     make(valueHeap, 0, len(iterators)),
+	}
+}
+
+// HasNext returns true if the iterator have unread record,
+// it returns true if any iterator fails as well, and Next returns the error.
+func (itr *PKMergeIterator) HasNext() bool {
+	if itr.isDisposed() {
+		return false
+	}
+	itr.init()
+	return itr.err != nil || itr.heads.Len() > 0
+}
+
+// Next returns the next record
+func (itr *PKMergeIterator) Next() (interface{}, error) {
+	if itr.isDisposed() {
+		return nil, ErrDisposed
+	}
+	if !itr.HasNext() {
+		return nil, ErrNoMoreRecord
+	}
+	if itr.err != nil {
+		return nil, itr.err
+	}
+
+	head := heap.Pop(&itr.heads).(*valueHeapItem)
+	if err := itr.push(head.source); err != nil {
+		itr.err = err
+	}
+	return head.value, nil
+}
+
+// Dispose disposes the iterator
+func (itr *PKMergeIterator) Dispose() {
+	if !atomic.CompareAndSwapInt32(&itr.disposed, 0, 1) {
+		return
+	}
+	for _, tmpItr := range itr.iterators {
+		if tmpItr != nil {
+			tmpItr.Dispose()
+		}
+	}
+}
+
+func (itr *PKMergeIterator) init() {
+	if itr.inited {
+		return
+	}
+	itr.inited = true
+	for i := range itr.iterators {
+		if err := itr.push(i); err != nil {
+			itr.err = err
+			return
+		}
+	}
+}
+
+// push pushes the next record of the i-th iterator into the heap if any.
+func (itr *PKMergeIterator) push(i int) error {
+	tmpItr := itr.iterators[i]
+	if tmpItr == nil || !tmpItr.HasNext() {
+		return nil
+	}
+	next, err := tmpItr.Next()
+	if err != nil {
+		return err
+	}
+	value, ok := next.(*Value)
+	if !ok {
+		return fmt.Errorf("unexpected record type %T", next)
+	}
+	heap.Push(&itr.heads, &valueHeapItem{value: value, source: i})
+	return nil
+}
+
+func (itr *PKMergeIterator) isDisposed() bool {
+	return atomic.LoadInt32(&itr.disposed) == 1
+}
+
+type valueHeapItem struct {
+	value  *Value
+	source int // index of the iterator which the value is from
+}
+
+// valueHeap is the min heap of Value by the primary key and then the timestamp.
+type valueHeap []*valueHeapItem
+
+func (h valueHeap) Len() int { return len(h) }
+
+func (h valueHeap) Less(i, j int) bool {
+	if h[i].value.PK.EQ(h[j].value.PK) {
+		return h[i].value.Timestamp < h[j].value.Timestamp
+	}
+	return h[i].value.PK.LT(h[j].value.PK)
+}
+
+func (h valueHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *valueHeap) Push(x any) {
+	*h = append(*h, x.(*valueHeapItem))
+}
+
+func (h *valueHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
 /*
 func NewInsertlogMergeIterator(blobs [][]*Blob) (*MergeIterator, error) {
 	iterators := make([]Iterator, 0, len(blobs))
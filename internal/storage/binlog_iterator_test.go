@@ -19,6 +19,7 @@ package storage
 import (
 	"testing"
 
+	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
@@ -139,6 +140,189 @@ func TestInsertlogIterator(t *testing.T) {
 	})
 }
 
+// generateMultiEventTestData returns the binlogs of the int64 fields, the rows of each batch are in an event.
+func generateMultiEventTestData(t *testing.T, fieldIDs []FieldID, batches ...[]int64) []*Blob {
+	blobs := make([]*Blob, 0, len(fieldIDs))
+	for _, fieldID := range fieldIDs {
+		writer := NewInsertBinlogWriter(schemapb.DataType_Int64, 1, 1, 1, fieldID)
+		for _, batch := range batches {
+			eventWriter, err := writer.NextInsertEventWriter()
+			assert.NoError(t, err)
+			eventWriter.SetEventTimestamp(1, 1)
+			assert.NoError(t, eventWriter.AddInt64ToPayload(batch))
+		}
+		writer.SetEventTimeStamp(1, 1)
+		writer.AddExtra(originalSizeKey, "0")
+		assert.NoError(t, writer.Finish())
+		buffer, err := writer.GetBuffer()
+		assert.NoError(t, err)
+		writer.Close()
+		blobs = append(blobs, &Blob{Value: buffer})
+	}
+	return blobs
+}
+
+func TestInsertEventIterator(t *testing.T) {
+	t.Run("test dispose", func(t *testing.T) {
+		blobs := generateTestData(t, 1)
+		itr, err := NewInsertEventIterator(blobs, common.RowIDField, schemapb.DataType_Int64)
+		assert.NoError(t, err)
+
+		itr.Dispose()
+		assert.False(t, itr.HasNext())
+		_, err = itr.Next()
+		assert.Equal(t, ErrDisposed, err)
+	})
+
+	t.Run("same as insert binlog iterator", func(t *testing.T) {
+		blobs := generateTestData(t, 3)
+		itr, err := NewInsertEventIterator(blobs, common.RowIDField, schemapb.DataType_Int64)
+		assert.NoError(t, err)
+		expectedItr, err := NewInsertBinlogIterator(blobs, common.RowIDField, schemapb.DataType_Int64)
+		assert.NoError(t, err)
+
+		for expectedItr.HasNext() {
+			assert.True(t, itr.HasNext())
+			v, err := itr.Next()
+			assert.NoError(t, err)
+			expected, err := expectedItr.Next()
+			assert.NoError(t, err)
+			assert.EqualValues(t, expected, v)
+		}
+
+		assert.False(t, itr.HasNext())
+		_, err = itr.Next()
+		assert.Equal(t, ErrNoMoreRecord, err)
+		itr.Dispose()
+	})
+
+	t.Run("multiple events", func(t *testing.T) {
+		fieldIDs := []FieldID{common.RowIDField, common.TimeStampField, 100}
+		blobs := generateMultiEventTestData(t, fieldIDs, []int64{1, 2}, []int64{3}, []int64{4, 5, 6})
+		itr, err := NewInsertEventIterator(blobs, 100, schemapb.DataType_Int64)
+		assert.NoError(t, err)
+		defer itr.Dispose()
+
+		ids := make([]int64, 0)
+		for itr.HasNext() {
+			v, err := itr.Next()
+			assert.NoError(t, err)
+			value := v.(*Value)
+			assert.Equal(t, value.ID, value.PK.GetValue())
+			// only the current event is deserialized
+			assert.LessOrEqual(t, itr.data.Data[100].RowNum(), 3)
+			ids = append(ids, value.ID)
+		}
+		assert.Equal(t, []int64{1, 2, 3, 4, 5, 6}, ids)
+	})
+
+	t.Run("unaligned events", func(t *testing.T) {
+		blobs := generateMultiEventTestData(t, []FieldID{common.RowIDField, common.TimeStampField}, []int64{1, 2}, []int64{3})
+		blobs = append(blobs, generateMultiEventTestData(t, []FieldID{100}, []int64{1}, []int64{2, 3})...)
+		itr, err := NewInsertEventIterator(blobs, 100, schemapb.DataType_Int64)
+		assert.NoError(t, err)
+		defer itr.Dispose()
+
+		assert.True(t, itr.HasNext())
+		_, err = itr.Next()
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid binlog", func(t *testing.T) {
+		_, err := NewInsertEventIterator([]*Blob{{Value: []byte("invalid")}}, 100, schemapb.DataType_Int64)
+		assert.Error(t, err)
+	})
+}
+
+// valueIterator iterates the values, or fails with err after the values.
+type valueIterator struct {
+	values []*Value
+	err    error
+}
+
+func (itr *valueIterator) HasNext() bool {
+	return len(itr.values) > 0 || itr.err != nil
+}
+
+func (itr *valueIterator) Next() (interface{}, error) {
+	if len(itr.values) == 0 {
+		return nil, itr.err
+	}
+	v := itr.values[0]
+	itr.values = itr.values[1:]
+	return v, nil
+}
+
+func (itr *valueIterator) Dispose() {}
+
+func newValues(pkTs ...int64) []*Value {
+	values := make([]*Value, 0, len(pkTs)/2)
+	for i := 0; i < len(pkTs); i += 2 {
+		values = append(values, &Value{PK: NewInt64PrimaryKey(pkTs[i]), Timestamp: pkTs[i+1]})
+	}
+	return values
+}
+
+func TestPKMergeIterator(t *testing.T) {
+	t.Run("merge by pk and ts", func(t *testing.T) {
+		itr := NewPKMergeIterator([]Iterator{
+			&valueIterator{values: newValues(1, 10, 4, 10, 7, 10)},
+			&valueIterator{},
+			&valueIterator{values: newValues(2, 10, 4, 5, 8, 10)},
+			nil,
+			&valueIterator{values: newValues(3, 10)},
+		})
+		defer itr.Dispose()
+
+		var pkTs []int64
+		for itr.HasNext() {
+			v, err := itr.Next()
+			assert.NoError(t, err)
+			value := v.(*Value)
+			pkTs = append(pkTs, value.PK.GetValue().(int64), value.Timestamp)
+		}
+		assert.Equal(t, []int64{1, 10, 2, 10, 3, 10, 4, 5, 4, 10, 7, 10, 8, 10}, pkTs)
+		_, err := itr.Next()
+		assert.Equal(t, ErrNoMoreRecord, err)
+	})
+
+	t.Run("varchar pk", func(t *testing.T) {
+		itr := NewPKMergeIterator([]Iterator{
+			&valueIterator{values: []*Value{{PK: NewVarCharPrimaryKey("b")}, {PK: NewVarCharPrimaryKey("c")}}},
+			&valueIterator{values: []*Value{{PK: NewVarCharPrimaryKey("a")}}},
+		})
+		var pks []string
+		for itr.HasNext() {
+			v, err := itr.Next()
+			assert.NoError(t, err)
+			pks = append(pks, v.(*Value).PK.GetValue().(string))
+		}
+		assert.Equal(t, []string{"a", "b", "c"}, pks)
+	})
+
+	t.Run("iterator failed", func(t *testing.T) {
+		itr := NewPKMergeIterator([]Iterator{
+			&valueIterator{values: newValues(1, 10), err: errors.New("mock error")},
+			&valueIterator{values: newValues(2, 10)},
+		})
+		assert.True(t, itr.HasNext())
+		v, err := itr.Next()
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, v.(*Value).PK.GetValue())
+		assert.True(t, itr.HasNext())
+		_, err = itr.Next()
+		assert.Error(t, err)
+	})
+
+	t.Run("test dispose", func(t *testing.T) {
+		itr := NewPKMergeIterator([]Iterator{&valueIterator{values: newValues(1, 10)}})
+		itr.Dispose()
+		assert.False(t, itr.HasNext())
+		_, err := itr.Next()
+		assert.Equal(t, ErrDisposed, err)
+	})
+}
+
 func TestMergeIterator(t *testing.T) {
 	t.Run("empty iterators", func(t *testing.T) {
 		iterators := make([]Iterator, 0)
@@ -0,0 +1,275 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+)
+
+// MigrateBinlog rewrites the binlog or deltalog of the legacy layout or of a format version older than
+// BinlogFormatVersion in the current ones, the payloads are kept as they are. It returns false if the binlog
+// is current already, the compressed and parquet binlogs are written by the versioned releases only.
+func MigrateBinlog(data []byte) ([]byte, bool, error) {
+	if IsCompressedBinlog(data) || IsParquetBinlog(data) {
+		return data, false, nil
+	}
+	legacy := IsLegacyBinlog(data)
+	if legacy {
+		var err error
+		if data, err = MigrateLegacyBinlog(data); err != nil {
+			return nil, false, err
+		}
+	}
+
+	var magicNumber int32
+	reader := bytes.NewReader(data)
+	if err := binary.Read(reader, common.Endian, &magicNumber); err != nil {
+		return nil, false, err
+	}
+	if magicNumber != MagicNumber {
+		return nil, false, fmt.Errorf("parse magic number failed, expected: %d, actual: %d", MagicNumber, magicNumber)
+	}
+	header, err := readDescriptorEventHeader(reader)
+	if err != nil {
+		return nil, false, err
+	}
+	descriptor, err := readDescriptorEventData(reader)
+	if err != nil {
+		return nil, false, err
+	}
+	if descriptor.FormatVersion() >= BinlogFormatVersion {
+		return data, legacy, nil
+	}
+
+	descriptor.version = BinlogFormatVersion
+	descriptor.Extras[binlogVersionKey] = strconv.Itoa(BinlogFormatVersion)
+	if descriptor.ExtraBytes, err = json.Marshal(descriptor.Extras); err != nil {
+		return nil, false, err
+	}
+	descriptor.ExtraLength = int32(len(descriptor.ExtraBytes))
+	oldNextPosition := header.NextPosition
+	header.EventLength = header.GetMemoryUsageInBytes() + descriptor.GetMemoryUsageInBytes()
+	header.NextPosition = int32(magicNumberSize) + header.EventLength
+	// the positions of the events following the descriptor event are shifted by the length of the new extras
+	shift := header.NextPosition - oldNextPosition
+
+	buffer := new(bytes.Buffer)
+	if err := binary.Write(buffer, common.Endian, MagicNumber); err != nil {
+		return nil, false, err
+	}
+	if err := header.Write(buffer); err != nil {
+		return nil, false, err
+	}
+	if err := descriptor.Write(buffer); err != nil {
+		return nil, false, err
+	}
+	for pos := int(oldNextPosition); pos < len(data); {
+		event, err := readEventHeader(bytes.NewReader(data[pos:]))
+		if err != nil {
+			return nil, false, err
+		}
+		if int(event.EventLength) < currentEventHeaderSize || pos+int(event.EventLength) > len(data) {
+			return nil, false, fmt.Errorf("invalid event at position %d", pos)
+		}
+		body := data[pos+currentEventHeaderSize : pos+int(event.EventLength)]
+		event.NextPosition += shift
+		if err := event.Write(buffer); err != nil {
+			return nil, false, err
+		}
+		buffer.Write(body)
+		pos += int(event.EventLength)
+	}
+	return buffer.Bytes(), true, nil
+}
+
+// MigrateStatslog rewrites the pk stats, or the list of them of a compound statslog, written before the format
+// versions were recorded with StatsFormatV1. It returns false if the stats are versioned already.
+func MigrateStatslog(data []byte) ([]byte, bool, error) {
+	trimmed := bytes.TrimSpace(data)
+	isList := len(trimmed) > 0 && trimmed[0] == '['
+	var stats []*PrimaryKeyStats
+	sr := &StatsReader{}
+	sr.SetBuffer(data)
+	if isList {
+		var err error
+		if stats, err = sr.GetPrimaryKeyStatsList(); err != nil {
+			return nil, false, err
+		}
+	} else {
+		single, err := sr.GetPrimaryKeyStats()
+		if err != nil {
+			return nil, false, err
+		}
+		stats = []*PrimaryKeyStats{single}
+	}
+
+	outdated := false
+	for _, s := range stats {
+		outdated = outdated || s.Version == 0
+	}
+	if !outdated {
+		return data, false, nil
+	}
+	sw := &StatsWriter{}
+	var err error
+	if isList {
+		err = sw.GenerateList(stats)
+	} else {
+		err = sw.Generate(stats[0])
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return sw.GetBuffer(), true, nil
+}
+
+// BinlogMigrationCheckpoint persists the last object migrated, by which an interrupted migration is resumed.
+type BinlogMigrationCheckpoint interface {
+	// Load returns the last object migrated, empty if none.
+	Load(ctx context.Context) (string, error)
+	Save(ctx context.Context, key string) error
+}
+
+// BinlogMigrationResult is the result of a binlog migration.
+type BinlogMigrationResult struct {
+	Scanned  int
+	Migrated int
+	Copied   int
+}
+
+// BinlogMigrator rewrites the binlogs, deltalogs and statslogs of the outdated formats in the current ones,
+// in place or to the objects of the same relative paths under the target root path. The objects are migrated
+// in the order of their paths, and the last one migrated is checkpointed every checkpointInterval objects.
+type BinlogMigrator struct {
+	cli        ChunkManager
+	targetRoot string
+	checkpoint BinlogMigrationCheckpoint
+	// checkpointInterval is the number of the objects migrated between two checkpoints
+	checkpointInterval int
+}
+
+// NewBinlogMigrator returns the migrator of the binlogs of the chunk manager, the objects are rewritten in place
+// if the target root path is empty or the root path of the chunk manager.
+func NewBinlogMigrator(cli ChunkManager, targetRoot string, checkpoint BinlogMigrationCheckpoint, checkpointInterval int) *BinlogMigrator {
+	if targetRoot == cli.RootPath() {
+		targetRoot = ""
+	}
+	if checkpointInterval <= 0 {
+		checkpointInterval = 1
+	}
+	return &BinlogMigrator{
+		cli:                cli,
+		targetRoot:         targetRoot,
+		checkpoint:         checkpoint,
+		checkpointInterval: checkpointInterval,
+	}
+}
+
+// Migrate migrates the binlogs, deltalogs and statslogs under the root path of the chunk manager, of the default
+// layout, the tenants and the path layouts alike, from the one next to the last checkpoint.
+func (m *BinlogMigrator) Migrate(ctx context.Context) (*BinlogMigrationResult, error) {
+	rootPath := m.cli.RootPath()
+	if m.targetRoot != "" && strings.HasPrefix(m.targetRoot+"/", rootPath+"/") {
+		return nil, fmt.Errorf("target root path %s is under the root path %s", m.targetRoot, rootPath)
+	}
+	last, err := m.checkpoint.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if last != "" {
+		log.Info("resume the binlog migration", zap.String("checkpoint", last))
+	}
+
+	keys, _, err := m.cli.ListWithPrefix(ctx, rootPath+"/", true)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	result := &BinlogMigrationResult{}
+	pending := ""
+	for _, key := range keys {
+		if key <= last {
+			continue
+		}
+		info, ok := metautil.ParseLogPath(key)
+		if !ok || info.LogType == common.SegmentQuantizedLogPath {
+			continue
+		}
+		result.Scanned++
+		if err := m.migrate(ctx, info.LogType, key, result); err != nil {
+			return result, fmt.Errorf("failed to migrate %s: %w", key, err)
+		}
+		pending = key
+		if result.Scanned%m.checkpointInterval == 0 {
+			if err := m.checkpoint.Save(ctx, pending); err != nil {
+				return result, err
+			}
+			pending = ""
+		}
+	}
+	if pending != "" {
+		if err := m.checkpoint.Save(ctx, pending); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func (m *BinlogMigrator) migrate(ctx context.Context, logType string, key string, result *BinlogMigrationResult) error {
+	data, err := m.cli.Read(ctx, key)
+	if err != nil {
+		return err
+	}
+	var migrated bool
+	if logType == common.SegmentStatslogPath {
+		data, migrated, err = MigrateStatslog(data)
+	} else {
+		data, migrated, err = MigrateBinlog(data)
+	}
+	if err != nil {
+		return err
+	}
+
+	if m.targetRoot == "" {
+		if !migrated {
+			return nil
+		}
+		result.Migrated++
+		return m.cli.Write(ctx, key, data)
+	}
+	// the current ones are copied as well, so the objects under the target root path are complete
+	if migrated {
+		result.Migrated++
+	} else {
+		result.Copied++
+	}
+	return m.cli.Write(ctx, path.Join(m.targetRoot, strings.TrimPrefix(key, m.cli.RootPath())), data)
+}
@@ -0,0 +1,223 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+)
+
+func TestMigrateBinlog(t *testing.T) {
+	s := &LegacyBinlogSuite{}
+	s.SetT(t)
+	current := s.writeBinlog()
+
+	migrated, ok, err := MigrateBinlog(current)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, current, migrated)
+
+	legacy := s.toLegacy(current, true)
+	// the current layout of format version 0
+	unversioned, err := MigrateLegacyBinlog(legacy)
+	require.NoError(t, err)
+	for _, data := range [][]byte{legacy, unversioned} {
+		migrated, ok, err = MigrateBinlog(data)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.False(t, IsLegacyBinlog(migrated))
+		reader, values := s.readAll(migrated)
+		assert.Equal(t, BinlogFormatVersion, reader.FormatVersion())
+		assert.Equal(t, []int64{1, 2, 3, 4, 5}, values)
+		assert.Equal(t, "40", reader.Extras[originalSizeKey])
+		reader.Close()
+
+		outdated, err := InspectBinlogEncoding(migrated)
+		assert.NoError(t, err)
+		assert.NotContains(t, outdated, OutdatedFormatVersion)
+	}
+
+	_, _, err = MigrateBinlog([]byte("invalid binlog"))
+	assert.Error(t, err)
+	_, _, err = MigrateBinlog(unversioned[:len(unversioned)-1])
+	assert.Error(t, err)
+}
+
+func TestMigrateStatslog(t *testing.T) {
+	stats, err := NewPrimaryKeyStats(1, int64(schemapb.DataType_Int64), 10)
+	require.NoError(t, err)
+	stats.Update(NewInt64PrimaryKey(5))
+	// the stats written before the versions were recorded
+	unversioned, err := json.Marshal(stats)
+	require.NoError(t, err)
+	unversionedList, err := json.Marshal([]*PrimaryKeyStats{stats, stats})
+	require.NoError(t, err)
+
+	migrated, ok, err := MigrateStatslog(unversioned)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	got, err := DeserializeStats([]*Blob{{Value: migrated}})
+	require.NoError(t, err)
+	assert.Equal(t, StatsFormatV1, got[0].Version)
+	assert.EqualValues(t, 5, got[0].MaxPk.GetValue())
+	assert.Equal(t, 0, stats.Version)
+
+	migrated, ok, err = MigrateStatslog(unversionedList)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	sr := &StatsReader{}
+	sr.SetBuffer(migrated)
+	list, err := sr.GetPrimaryKeyStatsList()
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, StatsFormatV1, list[1].Version)
+
+	// written by the StatsWriter of now
+	sw := &StatsWriter{}
+	require.NoError(t, sw.Generate(stats))
+	_, ok, err = MigrateStatslog(sw.GetBuffer())
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = MigrateStatslog([]byte("invalid"))
+	assert.Error(t, err)
+}
+
+type memCheckpoint struct {
+	key   string
+	saves int
+}
+
+func (c *memCheckpoint) Load(ctx context.Context) (string, error) {
+	return c.key, nil
+}
+
+func (c *memCheckpoint) Save(ctx context.Context, key string) error {
+	c.key = key
+	c.saves++
+	return nil
+}
+
+// failingChunkManager fails the writes after n of them.
+type failingChunkManager struct {
+	ChunkManager
+	n int
+}
+
+func (cm *failingChunkManager) Write(ctx context.Context, filePath string, content []byte) error {
+	if cm.n == 0 {
+		return errors.New("mock")
+	}
+	cm.n--
+	return cm.ChunkManager.Write(ctx, filePath, content)
+}
+
+func TestBinlogMigrator(t *testing.T) {
+	ctx := context.Background()
+	s := &LegacyBinlogSuite{}
+	s.SetT(t)
+	current := s.writeBinlog()
+	legacy := s.toLegacy(current, true)
+	stats, err := NewPrimaryKeyStats(1, int64(schemapb.DataType_Int64), 10)
+	require.NoError(t, err)
+	unversionedStats, err := json.Marshal(stats)
+	require.NoError(t, err)
+
+	setup := func(t *testing.T) (ChunkManager, string) {
+		rootPath := path.Join(t.TempDir(), "files")
+		cm := NewLocalChunkManager(RootPath(rootPath))
+		objects := map[string][]byte{
+			metautil.BuildInsertLogPath(rootPath, 1, 2, 3, 100, 1):                                legacy,
+			metautil.BuildInsertLogPath(rootPath, 1, 2, 3, 100, 2):                                current,
+			metautil.BuildInsertLogPath(metautil.TenantRootPath(rootPath, "t1"), 1, 2, 3, 100, 3): legacy,
+			metautil.BuildDeltaLogPath(rootPath, 1, 2, 3, 4):                                      legacy,
+			metautil.BuildStatsLogPath(rootPath, 1, 2, 3, 100, 5):                                 unversionedStats,
+			path.Join(rootPath, "upload_manifest", "1"):                                           []byte("not a binlog"),
+		}
+		require.NoError(t, cm.MultiWrite(ctx, objects))
+		return cm, rootPath
+	}
+
+	t.Run("in place", func(t *testing.T) {
+		cm, rootPath := setup(t)
+		checkpoint := &memCheckpoint{}
+		result, err := NewBinlogMigrator(cm, rootPath, checkpoint, 2).Migrate(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &BinlogMigrationResult{Scanned: 5, Migrated: 4}, result)
+		assert.Equal(t, metautil.BuildInsertLogPath(metautil.TenantRootPath(rootPath, "t1"), 1, 2, 3, 100, 3), checkpoint.key)
+		assert.Equal(t, 3, checkpoint.saves)
+
+		data, err := cm.Read(ctx, metautil.BuildInsertLogPath(metautil.TenantRootPath(rootPath, "t1"), 1, 2, 3, 100, 3))
+		require.NoError(t, err)
+		outdated, err := InspectBinlogEncoding(data)
+		require.NoError(t, err)
+		assert.Empty(t, outdated)
+		data, err = cm.Read(ctx, metautil.BuildStatsLogPath(rootPath, 1, 2, 3, 100, 5))
+		require.NoError(t, err)
+		_, ok, err := MigrateStatslog(data)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		// resumed from the checkpoint, nothing left
+		result, err = NewBinlogMigrator(cm, "", checkpoint, 2).Migrate(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &BinlogMigrationResult{}, result)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		cm, rootPath := setup(t)
+		checkpoint := &memCheckpoint{}
+		_, err := NewBinlogMigrator(&failingChunkManager{ChunkManager: cm, n: 2}, "", checkpoint, 1).Migrate(ctx)
+		assert.Error(t, err)
+		assert.Equal(t, metautil.BuildInsertLogPath(rootPath, 1, 2, 3, 100, 2), checkpoint.key)
+
+		result, err := NewBinlogMigrator(cm, "", checkpoint, 1).Migrate(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &BinlogMigrationResult{Scanned: 2, Migrated: 2}, result)
+	})
+
+	t.Run("to new prefix", func(t *testing.T) {
+		cm, rootPath := setup(t)
+		targetRoot := rootPath + "-v2"
+		result, err := NewBinlogMigrator(cm, targetRoot, &memCheckpoint{}, 10).Migrate(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &BinlogMigrationResult{Scanned: 5, Migrated: 4, Copied: 1}, result)
+
+		// the source objects are kept
+		data, err := cm.Read(ctx, metautil.BuildDeltaLogPath(rootPath, 1, 2, 3, 4))
+		require.NoError(t, err)
+		assert.Equal(t, legacy, data)
+		data, err = cm.Read(ctx, metautil.BuildDeltaLogPath(targetRoot, 1, 2, 3, 4))
+		require.NoError(t, err)
+		assert.False(t, IsLegacyBinlog(data))
+		data, err = cm.Read(ctx, metautil.BuildInsertLogPath(targetRoot, 1, 2, 3, 100, 2))
+		require.NoError(t, err)
+		assert.Equal(t, current, data)
+
+		_, err = NewBinlogMigrator(cm, path.Join(rootPath, "v2"), &memCheckpoint{}, 10).Migrate(ctx)
+		assert.Error(t, err)
+	})
+}
@@ -35,6 +35,8 @@ type BinlogReader struct {
 	buffer      *bytes.Buffer
 	eventReader *EventReader
 	isClose     bool
+	// parquetPayload is the parquet binlog not read yet, see newParquetBinlogReader.
+	parquetPayload []byte
 }
 
 // NextEventReader iters all events reader to read the binlog file.
@@ -42,6 +44,12 @@ func (reader *BinlogReader) NextEventReader() (*EventReader, error) {
 	if reader.isClose {
 		return nil, errors.New("bin log reader is closed")
 	}
+	if reader.parquetPayload != nil {
+		return reader.nextParquetEventReader()
+	}
+	if err := reader.skipUnknownEvents(); err != nil {
+		return nil, err
+	}
 	if reader.buffer.Len() <= 0 {
 		return nil, nil
 	}
@@ -57,6 +65,31 @@ func (reader *BinlogReader) NextEventReader() (*EventReader, error) {
 	return reader.eventReader, nil
 }
 
+// skipUnknownEvents skips the events of types unknown to this node, which are
+// optional sections written by newer nodes. A section new readers must not
+// skip is declared as a required feature instead.
+func (reader *BinlogReader) skipUnknownEvents() error {
+	headerSize := binary.Size(baseEventHeader{})
+	for reader.buffer.Len() >= headerSize {
+		header, err := readEventHeader(bytes.NewReader(reader.buffer.Bytes()[:headerSize]))
+		if err != nil {
+			return err
+		}
+		if header.TypeCode >= DescriptorEventType && header.TypeCode < EventTypeEnd {
+			return nil
+		}
+		if header.EventLength < int32(headerSize) || int(header.EventLength) > reader.buffer.Len() {
+			return fmt.Errorf("invalid length %d of unknown event type %d", header.EventLength, header.TypeCode)
+		}
+		reader.buffer.Next(int(header.EventLength))
+	}
+	return nil
+}
+
+func (reader *BinlogReader) descriptor() *descriptorEvent {
+	return &reader.descriptorEvent
+}
+
 func (reader *BinlogReader) readMagicNumber() (int32, error) {
 	var err error
 	reader.magicNumber, err = readMagicNumber(reader.buffer)
@@ -114,6 +147,21 @@ func (reader *BinlogReader) Close() {
 
 // NewBinlogReader creates binlogReader to read binlog file.
 func NewBinlogReader(data []byte) (*BinlogReader, error) {
+	if IsCompressedBinlog(data) {
+		var err error
+		if data, err = DecompressBinlog(data); err != nil {
+			return nil, err
+		}
+	}
+	if IsParquetBinlog(data) {
+		return newParquetBinlogReader(data)
+	}
+	if IsLegacyBinlog(data) {
+		var err error
+		if data, err = MigrateLegacyBinlog(data); err != nil {
+			return nil, err
+		}
+	}
 	reader := &BinlogReader{
 		buffer:  bytes.NewBuffer(data),
 		isClose: false,
@@ -0,0 +1,245 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"hash/crc32"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// RangeReader reads length bytes of a binlog from the offset, e.g. by ChunkManager.ReadAt.
+type RangeReader func(off, length int64) ([]byte, error)
+
+// StreamBinlogReader reads a binlog event by event with a ranged read for each event, so only the event
+// being read is in memory rather than the whole binlog.
+// The binlogs can't be read by ranges, i.e. the compressed, parquet and legacy ones, are read as a whole.
+// The checksum of the binlog is verified once the events are all read, if it's recorded.
+type StreamBinlogReader struct {
+	descriptorEvent
+	path     string
+	size     int64
+	offset   int64
+	read     RangeReader
+	checksum uint32
+	hash     hash.Hash32
+
+	eventReader *EventReader
+	// whole is the reader of the binlog read as a whole, nil if the binlog is streamed
+	whole *BinlogReader
+	// onClose are called once the reader closed, i.e. to release the mapped binlog read by ranges
+	onClose []func()
+	isClose bool
+}
+
+// NewStreamBinlogReader returns the StreamBinlogReader of the binlog of the size, checksum is the one recorded
+// in the segment meta, 0 if none.
+func NewStreamBinlogReader(path string, size int64, checksum uint32, read RangeReader) (*StreamBinlogReader, error) {
+	reader := &StreamBinlogReader{
+		path:     path,
+		size:     size,
+		read:     read,
+		checksum: checksum,
+		hash:     crc32.New(castagnoliTable),
+	}
+	streamable, err := reader.streamable()
+	if err != nil {
+		return nil, err
+	}
+	if !streamable {
+		err = reader.readWhole()
+	} else {
+		err = reader.readDescriptorEvent()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+// streamable returns whether the binlog is in the current layout, whose events could be read by ranges.
+func (reader *StreamBinlogReader) streamable() (bool, error) {
+	if reader.size < int64(magicNumberSize+currentEventHeaderSize) {
+		return false, nil
+	}
+	head, err := reader.read(0, int64(magicNumberSize+currentEventHeaderSize))
+	if err != nil {
+		return false, err
+	}
+	if _, err := readMagicNumber(bytes.NewReader(head)); err != nil {
+		return false, nil
+	}
+	header, err := readDescriptorEventHeader(bytes.NewReader(head[magicNumberSize:]))
+	if err != nil {
+		return false, nil
+	}
+	return header.TypeCode == DescriptorEventType && int(header.NextPosition) == magicNumberSize+int(header.EventLength), nil
+}
+
+func (reader *StreamBinlogReader) readWhole() error {
+	data, err := reader.read(0, reader.size)
+	if err != nil {
+		return err
+	}
+	if data, err = DecompressBinlog(data); err != nil {
+		return err
+	}
+	reader.hash.Write(data)
+	if err := reader.verify(); err != nil {
+		return err
+	}
+	reader.whole, err = NewBinlogReader(data)
+	if err != nil {
+		return err
+	}
+	reader.descriptorEvent = reader.whole.descriptorEvent
+	return nil
+}
+
+func (reader *StreamBinlogReader) readDescriptorEvent() error {
+	if _, err := reader.readRange(int64(magicNumberSize)); err != nil {
+		return err
+	}
+	_, data, err := reader.readEvent()
+	if err != nil {
+		return err
+	}
+	event, err := ReadDescriptorEvent(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	reader.descriptorEvent = *event
+	return nil
+}
+
+// readEvent reads the event at the offset by a ranged read of the header and one of the whole event.
+func (reader *StreamBinlogReader) readEvent() (*eventHeader, []byte, error) {
+	offset := reader.offset
+	headerData, err := reader.readRange(int64(currentEventHeaderSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	header, err := readEventHeader(bytes.NewReader(headerData))
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.EventLength < int32(currentEventHeaderSize) || offset+int64(header.EventLength) > reader.size {
+		return nil, nil, fmt.Errorf("invalid length %d of the event at %d of binlog %s", header.EventLength, offset, reader.path)
+	}
+	// read the header again with the body rather than copying the body after the header
+	data, err := reader.read(offset, int64(header.EventLength))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data) != int(header.EventLength) || !bytes.Equal(data[:currentEventHeaderSize], headerData) {
+		return nil, nil, fmt.Errorf("event at %d of binlog %s changed while reading", offset, reader.path)
+	}
+	reader.hash.Write(data[currentEventHeaderSize:])
+	reader.offset = offset + int64(header.EventLength)
+	return header, data, nil
+}
+
+// readRange reads the range following the bytes read, which are hashed in order for the checksum.
+func (reader *StreamBinlogReader) readRange(length int64) ([]byte, error) {
+	if reader.offset+length > reader.size {
+		return nil, fmt.Errorf("binlog %s truncated at %d, size %d", reader.path, reader.offset, reader.size)
+	}
+	data, err := reader.read(reader.offset, length)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) != length {
+		return nil, fmt.Errorf("read %d bytes at %d of binlog %s, expected %d", len(data), reader.offset, reader.path, length)
+	}
+	reader.hash.Write(data)
+	reader.offset += length
+	return data, nil
+}
+
+// NextEventReader returns the reader of the next event, nil if the events are all read.
+// The event reader returned is closed on the next call.
+func (reader *StreamBinlogReader) NextEventReader() (*EventReader, error) {
+	if reader.isClose {
+		return nil, errors.New("stream binlog reader is closed")
+	}
+	if reader.whole != nil {
+		return reader.whole.NextEventReader()
+	}
+	if reader.eventReader != nil {
+		reader.eventReader.Close()
+		reader.eventReader = nil
+	}
+
+	for reader.offset < reader.size {
+		header, data, err := reader.readEvent()
+		if err != nil {
+			return nil, err
+		}
+		// skip the events of types unknown to this node as BinlogReader does
+		if header.TypeCode < DescriptorEventType || header.TypeCode >= EventTypeEnd {
+			continue
+		}
+		reader.eventReader, err = newEventReader(reader.PayloadDataType, bytes.NewBuffer(data))
+		if err != nil {
+			return nil, err
+		}
+		return reader.eventReader, nil
+	}
+	return nil, reader.verify()
+}
+
+func (reader *StreamBinlogReader) descriptor() *descriptorEvent {
+	return &reader.descriptorEvent
+}
+
+// verify checks the bytes read against the checksum recorded, the checksum is skipped if not recorded.
+func (reader *StreamBinlogReader) verify() error {
+	if reader.checksum == 0 {
+		return nil
+	}
+	if actual := reader.hash.Sum32(); actual != reader.checksum {
+		return merr.WrapErrIoChecksumMismatch(reader.path, reader.checksum, actual)
+	}
+	return nil
+}
+
+// Close closes the reader and the event reader returned.
+func (reader *StreamBinlogReader) Close() {
+	if reader.isClose {
+		return
+	}
+	if reader.eventReader != nil {
+		reader.eventReader.Close()
+	}
+	if reader.whole != nil {
+		reader.whole.Close()
+	}
+	for _, fn := range reader.onClose {
+		fn()
+	}
+	reader.isClose = true
+}
+
+// OnClose registers the function called once the reader closed.
+func (reader *StreamBinlogReader) OnClose(fn func()) {
+	reader.onClose = append(reader.onClose, fn)
+}
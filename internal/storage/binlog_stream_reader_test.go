@@ -0,0 +1,128 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// rangeReaderOf returns the RangeReader of the data, which records the length of the ranges read.
+func rangeReaderOf(data []byte, lengths *[]int64) RangeReader {
+	return func(off, length int64) ([]byte, error) {
+		*lengths = append(*lengths, length)
+		return data[off : off+length], nil
+	}
+}
+
+func newTestStreamReaders(t *testing.T, blobs []*Blob, checksum func(*Blob) uint32) ([]*StreamBinlogReader, int64) {
+	readers := make([]*StreamBinlogReader, 0, len(blobs))
+	var maxRange int64
+	for _, blob := range blobs {
+		var lengths []int64
+		reader, err := NewStreamBinlogReader(blob.GetKey(), int64(len(blob.GetValue())), checksum(blob), rangeReaderOf(blob.GetValue(), &lengths))
+		require.NoError(t, err)
+		readers = append(readers, reader)
+		for _, length := range lengths {
+			if length > maxRange {
+				maxRange = length
+			}
+		}
+	}
+	return readers, maxRange
+}
+
+func TestStreamBinlogReader(t *testing.T) {
+	fieldIDs := []FieldID{common.RowIDField, common.TimeStampField, 100}
+	blobs := generateMultiEventTestData(t, fieldIDs, []int64{1, 2}, []int64{3}, []int64{4, 5, 6})
+	withChecksum := func(blob *Blob) uint32 { return BinlogChecksum(blob.GetValue()) }
+
+	t.Run("stream events", func(t *testing.T) {
+		readers, maxRange := newTestStreamReaders(t, blobs, withChecksum)
+		// the binlog is not read as a whole
+		for _, blob := range blobs {
+			assert.Less(t, maxRange, int64(len(blob.GetValue())))
+		}
+
+		itr := NewStreamInsertEventIterator(readers, 100, schemapb.DataType_Int64)
+		defer itr.Dispose()
+		ids := make([]int64, 0)
+		for itr.HasNext() {
+			v, err := itr.Next()
+			require.NoError(t, err)
+			ids = append(ids, v.(*Value).ID)
+		}
+		assert.Equal(t, []int64{1, 2, 3, 4, 5, 6}, ids)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		readers, _ := newTestStreamReaders(t, blobs, func(blob *Blob) uint32 { return BinlogChecksum(blob.GetValue()) + 1 })
+		itr := NewStreamInsertEventIterator(readers, 100, schemapb.DataType_Int64)
+		defer itr.Dispose()
+		var err error
+		for itr.HasNext() && err == nil {
+			_, err = itr.Next()
+		}
+		assert.ErrorIs(t, err, merr.ErrIoChecksumMismatch)
+	})
+
+	t.Run("read as a whole", func(t *testing.T) {
+		compressed := make([]*Blob, 0, len(blobs))
+		for _, blob := range blobs {
+			value, err := CompressBinlog(common.BinlogCompressionZstd, blob.GetValue())
+			require.NoError(t, err)
+			compressed = append(compressed, &Blob{Key: blob.GetKey(), Value: value})
+		}
+		readers, _ := newTestStreamReaders(t, compressed, func(blob *Blob) uint32 {
+			value, err := DecompressBinlog(blob.GetValue())
+			require.NoError(t, err)
+			return BinlogChecksum(value)
+		})
+
+		itr := NewStreamInsertEventIterator(readers, 100, schemapb.DataType_Int64)
+		defer itr.Dispose()
+		count := 0
+		for itr.HasNext() {
+			_, err := itr.Next()
+			require.NoError(t, err)
+			count++
+		}
+		assert.Equal(t, 6, count)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		value := blobs[0].GetValue()
+		var lengths []int64
+		reader, err := NewStreamBinlogReader("truncated", int64(len(value)-1), 0, rangeReaderOf(value, &lengths))
+		require.NoError(t, err)
+		defer reader.Close()
+		for err == nil {
+			var eventReader *EventReader
+			eventReader, err = reader.NextEventReader()
+			if eventReader == nil && err == nil {
+				break
+			}
+		}
+		assert.Error(t, err)
+	})
+}
@@ -1347,6 +1347,94 @@ func TestNewBinlogReaderError(t *testing.T) {
 	reader.Close()
 }
 
+func TestBinlogFormatNegotiation(t *testing.T) {
+	writeBinlog := func(features map[string]bool) []byte {
+		w := NewInsertBinlogWriter(schemapb.DataType_Int64, 10, 20, 30, 40)
+		defer w.Close()
+		w.SetEventTimeStamp(1000, 2000)
+		e, err := w.NextInsertEventWriter()
+		assert.NoError(t, err)
+		err = e.AddDataToPayload([]int64{1, 2, 3})
+		assert.NoError(t, err)
+		e.SetEventTimestamp(100, 200)
+		w.AddExtra(originalSizeKey, "24")
+		for feature, required := range features {
+			w.AddFeature(feature, required)
+		}
+		err = w.Finish()
+		assert.NoError(t, err)
+		buf, err := w.GetBuffer()
+		assert.NoError(t, err)
+		return buf
+	}
+
+	t.Run("supported features", func(t *testing.T) {
+		reader, err := NewBinlogReader(writeBinlog(map[string]bool{BinlogFeatureDeltalogBitmap: true, "unknown": false}))
+		assert.NoError(t, err)
+		defer reader.Close()
+		assert.Equal(t, BinlogFormatVersion, reader.FormatVersion())
+		assert.True(t, reader.HasFeature(BinlogFeatureDeltalogBitmap))
+		assert.True(t, reader.HasFeature("unknown"))
+		assert.False(t, reader.HasFeature(BinlogFeatureDeltalogChunk))
+	})
+
+	t.Run("unsupported required feature", func(t *testing.T) {
+		_, err := NewBinlogReader(writeBinlog(map[string]bool{"unknown": true}))
+		assert.ErrorContains(t, err, "unsupported feature unknown")
+	})
+
+	t.Run("legacy descriptor", func(t *testing.T) {
+		data := newDescriptorEventData()
+		data.ExtraBytes = []byte(`{"original_size":"24"}`)
+		data.ExtraLength = int32(len(data.ExtraBytes))
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, data.Write(buffer))
+
+		read, err := readDescriptorEventData(buffer)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, read.FormatVersion())
+	})
+
+	t.Run("skip unknown events", func(t *testing.T) {
+		buf := writeBinlog(nil)
+		descriptor, err := ReadDescriptorEvent(bytes.NewReader(buf[binary.Size(MagicNumber):]))
+		assert.NoError(t, err)
+
+		unknown := &baseEventHeader{TypeCode: EventTypeEnd + 1}
+		unknown.EventLength = unknown.GetMemoryUsageInBytes() + 8
+		buffer := bytes.NewBuffer(append([]byte{}, buf[:descriptor.NextPosition]...))
+		assert.NoError(t, unknown.Write(buffer))
+		assert.NoError(t, binary.Write(buffer, common.Endian, int64(0)))
+		buffer.Write(buf[descriptor.NextPosition:])
+		assert.NoError(t, unknown.Write(buffer))
+		assert.NoError(t, binary.Write(buffer, common.Endian, int64(0)))
+
+		reader, err := NewBinlogReader(buffer.Bytes())
+		assert.NoError(t, err)
+		defer reader.Close()
+		event, err := reader.NextEventReader()
+		assert.NoError(t, err)
+		values, err := event.GetInt64FromPayload()
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3}, values)
+		event, err = reader.NextEventReader()
+		assert.NoError(t, err)
+		assert.Nil(t, event)
+
+		// an unknown event overflowing the binlog is corrupted
+		unknown.EventLength = 1024
+		buffer = bytes.NewBuffer(append([]byte{}, buf...))
+		assert.NoError(t, unknown.Write(buffer))
+		reader, err = NewBinlogReader(buffer.Bytes())
+		assert.NoError(t, err)
+		defer reader.Close()
+		_, err = reader.NextEventReader()
+		assert.NoError(t, err)
+		_, err = reader.NextEventReader()
+		assert.Error(t, err)
+	})
+}
+
 func TestNewBinlogWriterTsError(t *testing.T) {
 	w := NewInsertBinlogWriter(schemapb.DataType_Int64, 10, 20, 30, 40)
 
@@ -2,12 +2,16 @@ package storage
 
 import (
 	"fmt"
+	"hash/crc32"
 	"strconv"
 	"strings"
 
 	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
 )
 
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
 // ParseSegmentIDByBinlog parse segment id from binlog paths
 // if path format is not expected, returns error
 func ParseSegmentIDByBinlog(rootPath, path string) (UniqueID, error) {
@@ -39,3 +43,21 @@ func ParseSegmentIDByBinlog(rootPath, path string) (UniqueID, error) {
 	}
 	return 0, fmt.Errorf("%s is not a valid binlog path", path)
 }
+
+// BinlogChecksum returns the CRC-32C checksum of the binlog content recorded in segment meta,
+// which is computed by the CRC32 instructions of the CPU by hash/crc32 if available.
+func BinlogChecksum(value []byte) uint32 {
+	return crc32.Checksum(value, castagnoliTable)
+}
+
+// VerifyBinlogChecksum checks the binlog content against the checksum recorded in segment meta,
+// binlogs written without checksum (0) are not verified.
+func VerifyBinlogChecksum(path string, value []byte, checksum uint32) error {
+	if checksum == 0 {
+		return nil
+	}
+	if actual := BinlogChecksum(value); actual != checksum {
+		return merr.WrapErrIoChecksumMismatch(path, checksum, actual)
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
 )
 
 func TestParseSegmentIDByBinlog(t *testing.T) {
@@ -82,3 +84,15 @@ func TestParseSegmentIDByBinlog(t *testing.T) {
 		})
 	}
 }
+
+func TestBinlogChecksum(t *testing.T) {
+	value := []byte("binlog")
+	checksum := BinlogChecksum(value)
+	assert.NotZero(t, checksum)
+	assert.NoError(t, VerifyBinlogChecksum("files/insertLog/1", value, checksum))
+	// not recorded
+	assert.NoError(t, VerifyBinlogChecksum("files/insertLog/1", value, 0))
+
+	err := VerifyBinlogChecksum("files/insertLog/1", []byte("binlob"), checksum)
+	assert.ErrorIs(t, err, merr.ErrIoChecksumMismatch)
+}
@@ -53,6 +53,7 @@ type baseBinlogWriter struct {
 	binlogType   BinlogType
 	eventWriters []EventWriter
 	buffer       *bytes.Buffer
+	released     bool
 	length       int32
 }
 
@@ -92,10 +93,23 @@ func (writer *baseBinlogWriter) GetBuffer() ([]byte, error) {
 	if writer.buffer == nil {
 		return nil, fmt.Errorf("please close binlog before get buffer")
 	}
+	if writer.released {
+		return nil, fmt.Errorf("binlog buffer has been released")
+	}
 	return writer.buffer.Bytes(), nil
 }
 
-// Finish allocates buffer and releases resource
+// releaseBuffer returns the binlog buffer to the pool, the bytes got by GetBuffer must not be used since.
+func (writer *baseBinlogWriter) releaseBuffer() {
+	if writer.buffer != nil && !writer.released {
+		writer.released = true
+		putBinlogBuffer(writer.buffer)
+	}
+}
+
+// Finish allocates buffer and releases resource.
+// The events are finished ahead to sum up the size of the binlog, so the binlog is written
+// into a pooled buffer of the exact size, without growing and copying the buffer on the way.
 func (writer *baseBinlogWriter) Finish() error {
 	if writer.buffer != nil {
 		return nil
@@ -104,36 +118,50 @@ func (writer *baseBinlogWriter) Finish() error {
 		return fmt.Errorf("invalid start/end timestamp")
 	}
 
-	var offset int32
-	writer.buffer = new(bytes.Buffer)
-	if err := binary.Write(writer.buffer, common.Endian, MagicNumber); err != nil {
-		return err
-	}
-	offset += int32(binary.Size(MagicNumber))
-	if err := writer.descriptorEvent.Write(writer.buffer); err != nil {
+	if err := writer.descriptorEvent.FinishExtra(); err != nil {
 		return err
 	}
-	offset += writer.descriptorEvent.GetMemoryUsageInBytes()
+	offset := int32(binary.Size(MagicNumber)) + writer.descriptorEvent.GetMemoryUsageInBytes()
 
-	writer.length = 0
+	var length int32
 	for _, w := range writer.eventWriters {
 		w.SetOffset(offset)
 		if err := w.Finish(); err != nil {
 			return err
 		}
-		if err := w.Write(writer.buffer); err != nil {
-			return err
-		}
-		length, err := w.GetMemoryUsageInBytes()
+		size, err := w.GetMemoryUsageInBytes()
 		if err != nil {
 			return err
 		}
-		offset += length
+		offset += size
 		rows, err := w.GetPayloadLengthFromWriter()
 		if err != nil {
 			return err
 		}
-		writer.length += int32(rows)
+		length += int32(rows)
+	}
+
+	buffer := getBinlogBuffer(int(offset))
+	if err := writer.writeTo(buffer); err != nil {
+		putBinlogBuffer(buffer)
+		return err
+	}
+	writer.buffer = buffer
+	writer.length = length
+	return nil
+}
+
+func (writer *baseBinlogWriter) writeTo(buffer *bytes.Buffer) error {
+	if err := binary.Write(buffer, common.Endian, MagicNumber); err != nil {
+		return err
+	}
+	if err := writer.descriptorEvent.Write(buffer); err != nil {
+		return err
+	}
+	for _, w := range writer.eventWriters {
+		if err := w.Write(buffer); err != nil {
+			return err
+		}
 	}
 	return nil
 }
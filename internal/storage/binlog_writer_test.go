@@ -77,3 +77,63 @@ func TestBinlogWriterReader(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Nil(t, reader)
 }
+
+func TestBinlogWriterPooledBuffer(t *testing.T) {
+	binlogWriter := NewInsertBinlogWriter(schemapb.DataType_Int64, 10, 20, 30, 40)
+	defer binlogWriter.Close()
+	binlogWriter.SetEventTimeStamp(1000, 2000)
+	for i := 0; i < 3; i++ {
+		eventWriter, err := binlogWriter.NextInsertEventWriter()
+		assert.NoError(t, err)
+		assert.NoError(t, eventWriter.AddInt64ToPayload([]int64{1, 2, 3}))
+		eventWriter.SetEventTimestamp(1000, 2000)
+	}
+	binlogWriter.AddExtra(originalSizeKey, "24")
+	assert.NoError(t, binlogWriter.Finish())
+
+	// the buffer is sized by the events ahead
+	buffer, err := binlogWriter.GetBuffer()
+	assert.NoError(t, err)
+	last := binlogWriter.eventWriters[len(binlogWriter.eventWriters)-1].(*insertEventWriter)
+	assert.EqualValues(t, last.NextPosition, len(buffer))
+
+	binlogReader, err := NewBinlogReader(buffer)
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		eventReader, err := binlogReader.NextEventReader()
+		assert.NoError(t, err)
+		payload, err := eventReader.GetInt64FromPayload()
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3}, payload)
+	}
+	binlogReader.Close()
+
+	binlogWriter.releaseBuffer()
+	_, err = binlogWriter.GetBuffer()
+	assert.Error(t, err)
+	// released once
+	binlogWriter.releaseBuffer()
+}
+
+func TestBlobRelease(t *testing.T) {
+	dData := &DeleteData{}
+	for i := int64(0); i < 100; i++ {
+		dData.Append(NewInt64PrimaryKey(i), uint64(i+1))
+	}
+	codec := NewDeleteCodec()
+	blob, err := codec.Serialize(1, 10, 100, dData)
+	assert.NoError(t, err)
+	expected := append([]byte{}, blob.GetValue()...)
+	ReleaseBlobs(blob, nil)
+	assert.Nil(t, blob.GetValue())
+	blob.Release()
+
+	// the buffer released is reused by the next serialization
+	blob, err = codec.Serialize(1, 10, 100, dData)
+	assert.NoError(t, err)
+	assert.Equal(t, len(expected), len(blob.GetValue()))
+	_, _, result, err := codec.Deserialize([]*Blob{blob})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 100, result.RowCount)
+	blob.Release()
+}
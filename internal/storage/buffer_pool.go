@@ -0,0 +1,48 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBufferSize is the capacity beyond which the buffers are left to the gc rather than pooled,
+// so a few huge binlogs don't pin the memory of the pool.
+const maxPooledBufferSize = 64 << 20
+
+var binlogBufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBinlogBuffer returns an empty buffer of the capacity at least the size from the pool.
+func getBinlogBuffer(size int) *bytes.Buffer {
+	buffer := binlogBufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	buffer.Grow(size)
+	return buffer
+}
+
+// putBinlogBuffer returns the buffer to the pool, the bytes of the buffer must not be used since.
+func putBinlogBuffer(buffer *bytes.Buffer) {
+	if buffer == nil || buffer.Cap() > maxPooledBufferSize {
+		return
+	}
+	binlogBufferPool.Put(buffer)
+}
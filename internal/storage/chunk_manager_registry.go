@@ -0,0 +1,164 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+)
+
+// ChunkManagerConfig is the config of the storage passed to the factories registered, which is taken from
+// the minio section of the config if the storage type is not a builtin one.
+type ChunkManagerConfig struct {
+	Address          string
+	BucketName       string
+	AccessKeyID      string
+	SecretAccessKey  string
+	UseSSL           bool
+	CreateBucket     bool
+	RootPath         string
+	UseIAM           bool
+	CloudProvider    string
+	IAMEndpoint      string
+	UseVirtualHost   bool
+	Region           string
+	RequestTimeoutMs int64
+}
+
+// NewChunkManagerFunc creates the ChunkManager of a storage type by the config of the storage.
+type NewChunkManagerFunc func(ctx context.Context, cfg ChunkManagerConfig) (ChunkManager, error)
+
+type newChunkManagerFunc func(ctx context.Context, c *config) (ChunkManager, error)
+
+// chunkManagerFactories are the factories of the ChunkManagers by the storage types.
+var chunkManagerFactories = struct {
+	sync.RWMutex
+	factories map[string]newChunkManagerFunc
+	// the storage types registered by RegisterChunkManagerFactory
+	plugins map[string]struct{}
+}{factories: make(map[string]newChunkManagerFunc), plugins: make(map[string]struct{})}
+
+func init() {
+	registerChunkManagerFactory("local", func(ctx context.Context, c *config) (ChunkManager, error) {
+		return NewLocalChunkManager(RootPath(c.rootPath)), nil
+	})
+	newMinioChunkManager := func(ctx context.Context, c *config) (ChunkManager, error) {
+		return newMinioChunkManagerWithConfig(ctx, c)
+	}
+	registerChunkManagerFactory("minio", newMinioChunkManager)
+	registerChunkManagerFactory("opendal", newMinioChunkManager)
+	registerChunkManagerFactory("remote", func(ctx context.Context, c *config) (ChunkManager, error) {
+		return NewRemoteChunkManager(ctx, c)
+	})
+	registerChunkManagerFactory(StorageTypeAzure, func(ctx context.Context, c *config) (ChunkManager, error) {
+		azure := *c
+		azure.cloudProvider = CloudProviderAzure
+		return NewRemoteChunkManager(ctx, &azure)
+	})
+	registerChunkManagerFactory(StorageTypeHDFS, func(ctx context.Context, c *config) (ChunkManager, error) {
+		hdfs := *c
+		hdfs.cloudProvider = CloudProviderHDFS
+		return NewRemoteChunkManager(ctx, &hdfs)
+	})
+}
+
+// RegisterChunkManagerFactory registers the factory of the ChunkManagers of the storage type, by which the backends
+// out of tree are compiled in, it's supposed to be called in the init of the package of the backend, and panics
+// if the storage type is registered already. The storage type is set by common.storageType.
+// The backends registered are accessed by the Go ChunkManagers only, which the segcore of the querynode
+// and the indexnode can't access, so they are for the clusters of which the querynodes and the indexnodes
+// access the same storage by a builtin storage type.
+func RegisterChunkManagerFactory(storageType string, factory NewChunkManagerFunc) {
+	registerChunkManagerFactory(storageType, func(ctx context.Context, c *config) (ChunkManager, error) {
+		if c.hasObjectPolicies() {
+			return nil, errObjectPoliciesNotSupported(storageType)
+		}
+		return factory(ctx, c.chunkManagerConfig())
+	})
+	chunkManagerFactories.Lock()
+	defer chunkManagerFactories.Unlock()
+	chunkManagerFactories.plugins[storageType] = struct{}{}
+}
+
+// IsPluginStorageType returns whether the storage type is of a backend registered by RegisterChunkManagerFactory.
+func IsPluginStorageType(storageType string) bool {
+	chunkManagerFactories.RLock()
+	defer chunkManagerFactories.RUnlock()
+	_, ok := chunkManagerFactories.plugins[storageType]
+	return ok
+}
+
+// CheckSegcoreStorageType returns an error if the storage type is of a plugin, which the segcore can't access.
+func CheckSegcoreStorageType(storageType string) error {
+	if IsPluginStorageType(storageType) {
+		return errors.Newf("common.storageType %s is the storage type of a plugin, which the segcore of the querynode and the indexnode can't access", storageType)
+	}
+	return nil
+}
+
+func registerChunkManagerFactory(storageType string, factory newChunkManagerFunc) {
+	chunkManagerFactories.Lock()
+	defer chunkManagerFactories.Unlock()
+	if factory == nil {
+		panic("storage: nil chunk manager factory of " + storageType)
+	}
+	if _, ok := chunkManagerFactories.factories[storageType]; ok {
+		panic("storage: chunk manager factory of " + storageType + " registered twice")
+	}
+	chunkManagerFactories.factories[storageType] = factory
+}
+
+// RegisteredStorageTypes returns the storage types of the ChunkManager factories registered, sorted.
+func RegisteredStorageTypes() []string {
+	chunkManagerFactories.RLock()
+	defer chunkManagerFactories.RUnlock()
+	storageTypes := lo.Keys(chunkManagerFactories.factories)
+	sort.Strings(storageTypes)
+	return storageTypes
+}
+
+func chunkManagerFactoryOf(storageType string) (newChunkManagerFunc, error) {
+	chunkManagerFactories.RLock()
+	defer chunkManagerFactories.RUnlock()
+	factory, ok := chunkManagerFactories.factories[storageType]
+	if !ok {
+		return nil, errors.New("no chunk manager implemented with engine: " + storageType)
+	}
+	return factory, nil
+}
+
+func (c *config) chunkManagerConfig() ChunkManagerConfig {
+	return ChunkManagerConfig{
+		Address:          c.address,
+		BucketName:       c.bucketName,
+		AccessKeyID:      c.accessKeyID,
+		SecretAccessKey:  c.secretAccessKeyID,
+		UseSSL:           c.useSSL,
+		CreateBucket:     c.createBucket,
+		RootPath:         c.rootPath,
+		UseIAM:           c.useIAM,
+		CloudProvider:    c.cloudProvider,
+		IAMEndpoint:      c.iamEndpoint,
+		UseVirtualHost:   c.useVirtualHost,
+		Region:           c.region,
+		RequestTimeoutMs: c.requestTimeoutMs,
+	}
+}
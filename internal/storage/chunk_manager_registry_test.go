@@ -0,0 +1,63 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterChunkManagerFactory(t *testing.T) {
+	ctx := context.Background()
+	rootPath := t.TempDir()
+
+	var got ChunkManagerConfig
+	RegisterChunkManagerFactory("test-plugin", func(ctx context.Context, cfg ChunkManagerConfig) (ChunkManager, error) {
+		got = cfg
+		return NewLocalChunkManager(RootPath(cfg.RootPath)), nil
+	})
+	assert.Subset(t, RegisteredStorageTypes(), []string{"local", "minio", "opendal", "remote", StorageTypeAzure, StorageTypeHDFS, "test-plugin"})
+
+	cm, err := NewChunkManagerFactory("test-plugin", RootPath(rootPath), Address("localhost:1234"), BucketName("bucket"), UseSSL(true)).
+		NewPersistentStorageChunkManager(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, rootPath, cm.RootPath())
+	assert.Equal(t, ChunkManagerConfig{Address: "localhost:1234", BucketName: "bucket", UseSSL: true, RootPath: rootPath}, got)
+
+	assert.Panics(t, func() {
+		RegisterChunkManagerFactory("test-plugin", func(ctx context.Context, cfg ChunkManagerConfig) (ChunkManager, error) {
+			return nil, nil
+		})
+	})
+	assert.Panics(t, func() {
+		RegisterChunkManagerFactory("local", func(ctx context.Context, cfg ChunkManagerConfig) (ChunkManager, error) {
+			return nil, nil
+		})
+	})
+
+	_, err = NewChunkManagerFactory("unknown").NewPersistentStorageChunkManager(ctx)
+	assert.Error(t, err)
+
+	// the segcore can't access the plugins
+	assert.True(t, IsPluginStorageType("test-plugin"))
+	assert.False(t, IsPluginStorageType("local"))
+	assert.Error(t, CheckSegcoreStorageType("test-plugin"))
+	assert.NoError(t, CheckSegcoreStorageType("remote"))
+}
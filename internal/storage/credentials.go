@@ -0,0 +1,103 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"sync"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+)
+
+// RotatingCredentials are the access keys of the object storage rotated at runtime. The clients built with them
+// sign the requests issued after a rotation by the new keys, or fetch a new token of the IAM role, the clients are
+// kept, so the requests in flight, e.g. the parts of a multipart upload, are not interrupted. Only the Go clients
+// are rotated, the remote chunk manager of segcore keeps the access keys it's initialized with until a restart.
+type RotatingCredentials struct {
+	mu              sync.RWMutex
+	accessKeyID     string
+	secretAccessKey string
+	version         int64
+}
+
+func NewRotatingCredentials(accessKeyID, secretAccessKey string) *RotatingCredentials {
+	return &RotatingCredentials{accessKeyID: accessKeyID, secretAccessKey: secretAccessKey}
+}
+
+// Rotate replaces the access keys, the credentials of the IAM role are fetched again even if they are the same.
+func (c *RotatingCredentials) Rotate(accessKeyID, secretAccessKey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accessKeyID = accessKeyID
+	c.secretAccessKey = secretAccessKey
+	c.version++
+	log.Info("object storage credentials rotated", zap.Int64("version", c.version))
+}
+
+func (c *RotatingCredentials) get() (string, string, int64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.accessKeyID, c.secretAccessKey, c.version
+}
+
+// rotatingProvider is the credentials provider of a client, the credentials of which are rebuilt once rotated.
+type rotatingProvider struct {
+	creds    *RotatingCredentials
+	newCreds func(accessKeyID, secretAccessKey string) *credentials.Credentials
+
+	mu      sync.Mutex
+	version int64
+	current *credentials.Credentials
+}
+
+func (p *rotatingProvider) Retrieve() (credentials.Value, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	accessKeyID, secretAccessKey, version := p.creds.get()
+	if p.current == nil || version != p.version {
+		p.current = p.newCreds(accessKeyID, secretAccessKey)
+		p.version = version
+	}
+	return p.current.Get()
+}
+
+// IsExpired always returns true, the credentials are cached by the current ones until they expire or are rotated.
+func (p *rotatingProvider) IsExpired() bool {
+	return true
+}
+
+// newCredentials returns the credentials built by newCreds, rebuilt once the credentials of the config are rotated.
+func (c *config) newCredentials(newCreds func(accessKeyID, secretAccessKey string) *credentials.Credentials) *credentials.Credentials {
+	if c.credentials == nil {
+		return newCreds(c.accessKeyID, c.secretAccessKeyID)
+	}
+	return credentials.New(&rotatingProvider{creds: c.credentials, newCreds: newCreds})
+}
+
+func newStaticV2(accessKeyID, secretAccessKey string) *credentials.Credentials {
+	return credentials.NewStaticV2(accessKeyID, secretAccessKey, "")
+}
+
+func newStaticV4(accessKeyID, secretAccessKey string) *credentials.Credentials {
+	return credentials.NewStaticV4(accessKeyID, secretAccessKey, "")
+}
+
+func newIAM(string, string) *credentials.Credentials {
+	return credentials.NewIAM("")
+}
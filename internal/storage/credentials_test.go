@@ -0,0 +1,70 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgconfig "github.com/milvus-io/milvus/pkg/config"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestRotatingCredentials(t *testing.T) {
+	c := &config{accessKeyID: "ak", secretAccessKeyID: "sk"}
+	value, err := c.newCredentials(newStaticV4).Get()
+	require.NoError(t, err)
+	assert.Equal(t, "ak", value.AccessKeyID)
+
+	rotating := NewRotatingCredentials("ak1", "sk1")
+	Credentials(rotating)(c)
+	creds := c.newCredentials(newStaticV2)
+	value, err = creds.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "ak1", value.AccessKeyID)
+	assert.Equal(t, "sk1", value.SecretAccessKey)
+	assert.Equal(t, credentials.SignatureV2, value.SignerType)
+
+	// the clients built take the rotated ones
+	rotating.Rotate("ak2", "sk2")
+	value, err = creds.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "ak2", value.AccessKeyID)
+	assert.Equal(t, "sk2", value.SecretAccessKey)
+}
+
+func TestWatchCredentials(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	defer params.Reset(params.MinioCfg.AccessKeyID.Key)
+	defer params.Reset(params.MinioCfg.SecretAccessKey.Key)
+
+	creds := watchCredentials(params)
+	accessKeyID, _, version := creds.get()
+	assert.Equal(t, params.MinioCfg.AccessKeyID.GetValue(), accessKeyID)
+
+	params.Save(params.MinioCfg.AccessKeyID.Key, "rotated-ak")
+	params.Save(params.MinioCfg.SecretAccessKey.Key, "rotated-sk")
+	rotateCredentials(params, creds)(&pkgconfig.Event{Key: params.MinioCfg.SecretAccessKey.Key, Value: "rotated-sk", HasUpdated: true})
+	accessKeyID, secretAccessKey, newVersion := creds.get()
+	assert.Equal(t, "rotated-ak", accessKeyID)
+	assert.Equal(t, "rotated-sk", secretAccessKey)
+	assert.Greater(t, newVersion, version)
+}
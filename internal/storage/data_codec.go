@@ -25,12 +25,16 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
 	"github.com/samber/lo"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/etcdpb"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
@@ -71,6 +75,30 @@ type Blob struct {
 	Value  []byte
 	Size   int64
 	RowNum int64
+	// ZoneMap is the range of the values of the scalar field of the insert binlog, nil if not computed
+	ZoneMap *datapb.ZoneMap
+
+	// release returns the buffer of the value to the pool, nil if the value isn't pooled
+	release func()
+}
+
+// Release returns the buffer of the blob value to the pool once the value is uploaded,
+// the value must not be used since. The value not pooled is left to the gc.
+func (b *Blob) Release() {
+	if b.release != nil {
+		b.release()
+		b.release = nil
+	}
+	b.Value = nil
+}
+
+// ReleaseBlobs releases the values of the blobs, see Blob.Release.
+func ReleaseBlobs(blobs ...*Blob) {
+	for _, blob := range blobs {
+		if blob != nil {
+			blob.Release()
+		}
+	}
 }
 
 // BlobList implements sort.Interface for a list of Blob
@@ -110,6 +138,8 @@ func (b Blob) GetValue() []byte {
 // ${tenant}/insert_log/${collection_id}/${partition_id}/${segment_id}/${field_id}/${log_idx}
 type InsertCodec struct {
 	Schema *etcdpb.CollectionMeta
+	// BinlogFormat is the format of the binlogs serialized, common.BinlogFormatNative if empty.
+	BinlogFormat string
 }
 
 // NewInsertCodec creates an InsertCodec
@@ -203,8 +233,6 @@ func (insertCodec *InsertCodec) SerializePkStatsByData(data *InsertData) (*Blob,
 // For each field, it will create a binlog writer, and write an event to the binlog.
 // It returns binlog buffer in the end.
 func (insertCodec *InsertCodec) Serialize(partitionID UniqueID, segmentID UniqueID, data *InsertData) ([]*Blob, error) {
-	blobs := make([]*Blob, 0)
-	var writer *InsertBinlogWriter
 	if insertCodec.Schema == nil {
 		return nil, fmt.Errorf("schema is not set")
 	}
@@ -215,20 +243,6 @@ func (insertCodec *InsertCodec) Serialize(partitionID UniqueID, segmentID Unique
 	if timeFieldData.RowNum() <= 0 {
 		return nil, fmt.Errorf("there's no data in InsertData")
 	}
-	rowNum := int64(timeFieldData.RowNum())
-
-	ts := timeFieldData.(*Int64FieldData).Data
-	var startTs, endTs Timestamp
-	startTs, endTs = math.MaxUint64, 0
-	for _, t := range ts {
-		if uint64(t) > endTs {
-			endTs = uint64(t)
-		}
-
-		if uint64(t) < startTs {
-			startTs = uint64(t)
-		}
-	}
 
 	// sort insert data by rowID
 	dataSorter := &DataSorter{
@@ -237,186 +251,184 @@ func (insertCodec *InsertCodec) Serialize(partitionID UniqueID, segmentID Unique
 	}
 	sort.Sort(dataSorter)
 
-	for _, field := range insertCodec.Schema.Schema.Fields {
-		singleData := data.Data[field.FieldID]
+	record, err := InsertDataToRecord(insertCodec.Schema.Schema, data)
+	if err != nil {
+		return nil, err
+	}
+	defer record.Release()
+	return insertCodec.SerializeRecord(partitionID, segmentID, record)
+}
 
-		// encode fields
-		writer = NewInsertBinlogWriter(field.DataType, insertCodec.Schema.ID, partitionID, segmentID, field.FieldID)
-		var eventWriter *insertEventWriter
-		var err error
-		if typeutil.IsVectorType(field.DataType) {
-			switch field.DataType {
-			case schemapb.DataType_FloatVector:
-				eventWriter, err = writer.NextInsertEventWriter(singleData.(*FloatVectorFieldData).Dim)
-			case schemapb.DataType_BinaryVector:
-				eventWriter, err = writer.NextInsertEventWriter(singleData.(*BinaryVectorFieldData).Dim)
-			case schemapb.DataType_Float16Vector:
-				eventWriter, err = writer.NextInsertEventWriter(singleData.(*Float16VectorFieldData).Dim)
-			case schemapb.DataType_BFloat16Vector:
-				eventWriter, err = writer.NextInsertEventWriter(singleData.(*BFloat16VectorFieldData).Dim)
-			default:
-				return nil, fmt.Errorf("undefined data type %d", field.DataType)
-			}
-		} else {
-			eventWriter, err = writer.NextInsertEventWriter()
-		}
+// SerializeRecord transfer insert record to blob, the rows are written in the order of the record,
+// so the caller should sort the record by row id first, see InsertRecord.SortByRowID.
+// The columns are handed off to the binlog writers without copying.
+func (insertCodec *InsertCodec) SerializeRecord(partitionID UniqueID, segmentID UniqueID, record *InsertRecord) ([]*Blob, error) {
+	if insertCodec.Schema == nil {
+		return nil, fmt.Errorf("schema is not set")
+	}
+	rowNum := int64(record.NumRows())
+	if rowNum <= 0 {
+		return nil, fmt.Errorf("there's no data in InsertData")
+	}
+	startTs, endTs, err := record.TimestampRange()
+	if err != nil {
+		return nil, fmt.Errorf("data doesn't contains timestamp field")
+	}
+
+	blobs := make([]*Blob, 0, len(insertCodec.Schema.Schema.Fields))
+	serialize := insertCodec.serializeColumn
+	if insertCodec.BinlogFormat == common.BinlogFormatParquet {
+		serialize = insertCodec.serializeParquetColumn
+	}
+	for _, field := range insertCodec.Schema.Schema.Fields {
+		blob, err := serialize(partitionID, segmentID, field, record, startTs, endTs)
 		if err != nil {
-			writer.Close()
 			return nil, err
 		}
+		blobs = append(blobs, blob)
+	}
+
+	return blobs, nil
+}
+
+// isDeltaEncodedField returns whether the values of the field are near-monotonic, which are delta encoded in binlogs.
+func isDeltaEncodedField(field *schemapb.FieldSchema) bool {
+	if field.GetDataType() != schemapb.DataType_Int64 {
+		return false
+	}
+	return field.GetFieldID() == common.RowIDField || field.GetFieldID() == common.TimeStampField ||
+		(field.GetIsPrimaryKey() && field.GetAutoID())
+}
+
+// isLowCardinality returns whether the string column has no more distinct values than the threshold.
+func isLowCardinality(column arrow.Array, threshold int) bool {
+	values, ok := column.(*array.String)
+	if !ok {
+		return false
+	}
+	distinct := make(map[string]struct{}, threshold+1)
+	for i := 0; i < values.Len(); i++ {
+		if values.IsNull(i) {
+			continue
+		}
+		distinct[values.Value(i)] = struct{}{}
+		if len(distinct) > threshold {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldStorageOptions returns the compression and the encoding of the field in binlogs set by its type params,
+// the near-monotonic fields are delta encoded if the encoding is not set, and the string fields are dictionary
+// encoded if there are no more distinct values in the column than dataNode.segment.binlog.dictionaryCardinality,
+// otherwise plain encoded.
+func fieldStorageOptions(field *schemapb.FieldSchema, column arrow.Array) (string, string, error) {
+	compression, err := common.GetFieldCompression(field.GetTypeParams()...)
+	if err != nil {
+		return "", "", err
+	}
+	encoding, err := common.GetFieldEncoding(field.GetTypeParams()...)
+	if err != nil {
+		return "", "", err
+	}
+	if encoding == "" && isDeltaEncodedField(field) {
+		encoding = common.FieldEncodingDelta
+	}
+	if threshold := paramtable.Get().DataNodeCfg.DictionaryCardinality.GetAsInt(); encoding == "" && threshold > 0 &&
+		typeutil.IsStringType(field.GetDataType()) {
+		if isLowCardinality(column, threshold) {
+			encoding = common.FieldEncodingDictionary
+		} else {
+			encoding = common.FieldEncodingPlain
+		}
+	}
+	return compression, encoding, nil
+}
+
+func (insertCodec *InsertCodec) serializeColumn(partitionID UniqueID, segmentID UniqueID, field *schemapb.FieldSchema,
+	record *InsertRecord, startTs, endTs Timestamp,
+) (*Blob, error) {
+	column := record.Column(field.FieldID)
+	if column == nil {
+		return nil, fmt.Errorf("data of field %d not found", field.FieldID)
+	}
 
-		eventWriter.SetEventTimestamp(startTs, endTs)
+	// encode fields
+	writer := NewInsertBinlogWriter(field.DataType, insertCodec.Schema.ID, partitionID, segmentID, field.FieldID)
+	defer writer.Close()
+	var eventWriter *insertEventWriter
+	var err error
+	if typeutil.IsVectorType(field.DataType) {
+		fixedSizeType, ok := column.DataType().(*arrow.FixedSizeBinaryType)
+		if !ok {
+			return nil, fmt.Errorf("undefined data type %d", field.DataType)
+		}
 		switch field.DataType {
-		case schemapb.DataType_Bool:
-			err = eventWriter.AddBoolToPayload(singleData.(*BoolFieldData).Data)
-			if err != nil {
-				eventWriter.Close()
-				writer.Close()
-				return nil, err
-			}
-			writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", singleData.(*BoolFieldData).GetMemorySize()))
-		case schemapb.DataType_Int8:
-			err = eventWriter.AddInt8ToPayload(singleData.(*Int8FieldData).Data)
-			if err != nil {
-				eventWriter.Close()
-				writer.Close()
-				return nil, err
-			}
-			writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", singleData.(*Int8FieldData).GetMemorySize()))
-		case schemapb.DataType_Int16:
-			err = eventWriter.AddInt16ToPayload(singleData.(*Int16FieldData).Data)
-			if err != nil {
-				eventWriter.Close()
-				writer.Close()
-				return nil, err
-			}
-			writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", singleData.(*Int16FieldData).GetMemorySize()))
-		case schemapb.DataType_Int32:
-			err = eventWriter.AddInt32ToPayload(singleData.(*Int32FieldData).Data)
-			if err != nil {
-				eventWriter.Close()
-				writer.Close()
-				return nil, err
-			}
-			writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", singleData.(*Int32FieldData).GetMemorySize()))
-		case schemapb.DataType_Int64:
-			err = eventWriter.AddInt64ToPayload(singleData.(*Int64FieldData).Data)
-			if err != nil {
-				eventWriter.Close()
-				writer.Close()
-				return nil, err
-			}
-			writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", singleData.(*Int64FieldData).GetMemorySize()))
-		case schemapb.DataType_Float:
-			err = eventWriter.AddFloatToPayload(singleData.(*FloatFieldData).Data)
-			if err != nil {
-				eventWriter.Close()
-				writer.Close()
-				return nil, err
-			}
-			writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", singleData.(*FloatFieldData).GetMemorySize()))
-		case schemapb.DataType_Double:
-			err = eventWriter.AddDoubleToPayload(singleData.(*DoubleFieldData).Data)
-			if err != nil {
-				eventWriter.Close()
-				writer.Close()
-				return nil, err
-			}
-			writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", singleData.(*DoubleFieldData).GetMemorySize()))
-		case schemapb.DataType_String, schemapb.DataType_VarChar:
-			for _, singleString := range singleData.(*StringFieldData).Data {
-				err = eventWriter.AddOneStringToPayload(singleString)
-				if err != nil {
-					eventWriter.Close()
-					writer.Close()
-					return nil, err
-				}
-			}
-			writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", singleData.(*StringFieldData).GetMemorySize()))
-		case schemapb.DataType_Array:
-			for _, singleArray := range singleData.(*ArrayFieldData).Data {
-				err = eventWriter.AddOneArrayToPayload(singleArray)
-				if err != nil {
-					eventWriter.Close()
-					writer.Close()
-					return nil, err
-				}
-			}
-			writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", singleData.(*ArrayFieldData).GetMemorySize()))
-		case schemapb.DataType_JSON:
-			for _, singleJSON := range singleData.(*JSONFieldData).Data {
-				err = eventWriter.AddOneJSONToPayload(singleJSON)
-				if err != nil {
-					eventWriter.Close()
-					writer.Close()
-					return nil, err
-				}
-			}
-			writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", singleData.(*JSONFieldData).GetMemorySize()))
-		case schemapb.DataType_BinaryVector:
-			err = eventWriter.AddBinaryVectorToPayload(singleData.(*BinaryVectorFieldData).Data, singleData.(*BinaryVectorFieldData).Dim)
-			if err != nil {
-				eventWriter.Close()
-				writer.Close()
-				return nil, err
-			}
-			writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", singleData.(*BinaryVectorFieldData).GetMemorySize()))
 		case schemapb.DataType_FloatVector:
-			err = eventWriter.AddFloatVectorToPayload(singleData.(*FloatVectorFieldData).Data, singleData.(*FloatVectorFieldData).Dim)
-			if err != nil {
-				eventWriter.Close()
-				writer.Close()
-				return nil, err
-			}
-			writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", singleData.(*FloatVectorFieldData).GetMemorySize()))
-		case schemapb.DataType_Float16Vector:
-			err = eventWriter.AddFloat16VectorToPayload(singleData.(*Float16VectorFieldData).Data, singleData.(*Float16VectorFieldData).Dim)
-			if err != nil {
-				eventWriter.Close()
-				writer.Close()
-				return nil, err
-			}
-			writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", singleData.(*Float16VectorFieldData).GetMemorySize()))
-		case schemapb.DataType_BFloat16Vector:
-			err = eventWriter.AddBFloat16VectorToPayload(singleData.(*BFloat16VectorFieldData).Data, singleData.(*BFloat16VectorFieldData).Dim)
-			if err != nil {
-				eventWriter.Close()
-				writer.Close()
-				return nil, err
-			}
-			writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", singleData.(*BFloat16VectorFieldData).GetMemorySize()))
+			eventWriter, err = writer.NextInsertEventWriter(fixedSizeType.ByteWidth / 4)
+		case schemapb.DataType_BinaryVector:
+			eventWriter, err = writer.NextInsertEventWriter(fixedSizeType.ByteWidth * 8)
+		case schemapb.DataType_Float16Vector, schemapb.DataType_BFloat16Vector:
+			eventWriter, err = writer.NextInsertEventWriter(fixedSizeType.ByteWidth / 2)
 		default:
 			return nil, fmt.Errorf("undefined data type %d", field.DataType)
 		}
-		if err != nil {
+	} else {
+		eventWriter, err = writer.NextInsertEventWriter()
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer eventWriter.Close()
+	compression, encoding, err := fieldStorageOptions(field, column)
+	if err != nil {
+		return nil, err
+	}
+	if err = eventWriter.SetCompression(compression); err != nil {
+		return nil, err
+	}
+	if isNullableField(field) {
+		if err = eventWriter.SetNullable(true); err != nil {
 			return nil, err
 		}
-		writer.SetEventTimeStamp(startTs, endTs)
-
-		err = writer.Finish()
-		if err != nil {
-			eventWriter.Close()
-			writer.Close()
+	}
+	if encoding != "" {
+		if err = eventWriter.SetEncoding(encoding); err != nil {
 			return nil, err
 		}
+	}
 
-		buffer, err := writer.GetBuffer()
+	eventWriter.SetEventTimestamp(startTs, endTs)
+	if err = eventWriter.AddArrowArrayToPayload(column); err != nil {
+		return nil, err
+	}
+	writer.AddExtra(originalSizeKey, fmt.Sprintf("%v", record.FieldMemorySize(field.FieldID)))
+	zoneMap := ComputeZoneMap(field.DataType, column)
+	if zoneMap != nil {
+		value, err := marshalZoneMap(zoneMap)
 		if err != nil {
-			eventWriter.Close()
-			writer.Close()
 			return nil, err
 		}
-		blobKey := fmt.Sprintf("%d", field.FieldID)
-		blobs = append(blobs, &Blob{
-			Key:    blobKey,
-			Value:  buffer,
-			RowNum: rowNum,
-		})
-		eventWriter.Close()
-		writer.Close()
+		writer.AddExtra(zoneMapKey, value)
 	}
+	writer.SetEventTimeStamp(startTs, endTs)
 
-	return blobs, nil
+	if err = writer.Finish(); err != nil {
+		return nil, err
+	}
+
+	buffer, err := writer.GetBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return &Blob{
+		Key:     fmt.Sprintf("%d", field.FieldID),
+		Value:   buffer,
+		RowNum:  int64(column.Len()),
+		ZoneMap: zoneMap,
+		release: writer.releaseBuffer,
+	}, nil
 }
 
 func (insertCodec *InsertCodec) DeserializeAll(blobs []*Blob) (
@@ -439,6 +451,9 @@ func (insertCodec *InsertCodec) DeserializeAll(blobs []*Blob) (
 	if collectionID, partitionID, segmentID, err = insertCodec.DeserializeInto(blobs, 0, data); err != nil {
 		return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, nil, err
 	}
+	if err = insertCodec.fillMissingFields(data); err != nil {
+		return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, nil, err
+	}
 
 	return
 }
@@ -461,7 +476,6 @@ func (insertCodec *InsertCodec) DeserializeInto(fieldBinlogs []*Blob, rowNum int
 		dataType := binlogReader.PayloadDataType
 		fieldID := binlogReader.FieldID
 		totalLength := 0
-		dim := 0
 
 		for {
 			eventReader, err := binlogReader.NextEventReader()
@@ -471,328 +485,422 @@ func (insertCodec *InsertCodec) DeserializeInto(fieldBinlogs []*Blob, rowNum int
 			if eventReader == nil {
 				break
 			}
-			switch dataType {
-			case schemapb.DataType_Bool:
-				singleData, err := eventReader.GetBoolFromPayload()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
+			length, err := appendEventPayload(eventReader, dataType, fieldID, rowNum, insertData)
+			eventReader.Close()
+			if err != nil {
+				binlogReader.Close()
+				return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
+			}
+			totalLength += length
+		}
 
-				if insertData.Data[fieldID] == nil {
-					insertData.Data[fieldID] = &BoolFieldData{
-						Data: make([]bool, 0, rowNum),
-					}
-				}
-				boolFieldData := insertData.Data[fieldID].(*BoolFieldData)
+		if rowNum <= 0 {
+			rowNum = totalLength
+		}
 
-				boolFieldData.Data = append(boolFieldData.Data, singleData...)
-				totalLength += len(singleData)
-				insertData.Data[fieldID] = boolFieldData
+		if fieldID == common.TimeStampField {
+			blobInfo := BlobInfo{
+				Length: totalLength,
+			}
+			insertData.Infos = append(insertData.Infos, blobInfo)
+		}
+		binlogReader.Close()
+	}
 
-			case schemapb.DataType_Int8:
-				singleData, err := eventReader.GetInt8FromPayload()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
+	return collectionID, partitionID, segmentID, nil
+}
 
-				if insertData.Data[fieldID] == nil {
-					insertData.Data[fieldID] = &Int8FieldData{
-						Data: make([]int8, 0, rowNum),
-					}
-				}
-				int8FieldData := insertData.Data[fieldID].(*Int8FieldData)
+// fillMissingFields fills the fields of the schema absent in the binlogs, e.g. the ones added after the segment
+// flushed, by their default values, or by nulls if they're nullable without default values.
+func (insertCodec *InsertCodec) fillMissingFields(data *InsertData) error {
+	rowNum := data.GetRowNum()
+	for _, field := range insertCodec.Schema.GetSchema().GetFields() {
+		if _, ok := data.Data[field.GetFieldID()]; ok {
+			continue
+		}
+		if field.GetDefaultValue() == nil && !isNullableField(field) {
+			continue
+		}
+		fieldData, err := NewFieldData(field.GetDataType(), field)
+		if err != nil {
+			return err
+		}
+		row := defaultValueRow(field)
+		for i := 0; i < rowNum; i++ {
+			if err := fieldData.AppendRow(row); err != nil {
+				return fmt.Errorf("failed to fill the default value of field %d: %w", field.GetFieldID(), err)
+			}
+		}
+		data.Data[field.GetFieldID()] = fieldData
+	}
+	return nil
+}
 
-				int8FieldData.Data = append(int8FieldData.Data, singleData...)
-				totalLength += len(singleData)
-				insertData.Data[fieldID] = int8FieldData
+// defaultValueRow returns the default value of the field as the row of its field data, nil if not set.
+func defaultValueRow(field *schemapb.FieldSchema) any {
+	defaultValue := field.GetDefaultValue()
+	if defaultValue == nil {
+		return nil
+	}
+	switch field.GetDataType() {
+	case schemapb.DataType_Bool:
+		return defaultValue.GetBoolData()
+	case schemapb.DataType_Int8:
+		return int8(defaultValue.GetIntData())
+	case schemapb.DataType_Int16:
+		return int16(defaultValue.GetIntData())
+	case schemapb.DataType_Int32:
+		return defaultValue.GetIntData()
+	case schemapb.DataType_Int64:
+		return defaultValue.GetLongData()
+	case schemapb.DataType_Float:
+		return defaultValue.GetFloatData()
+	case schemapb.DataType_Double:
+		return defaultValue.GetDoubleData()
+	case schemapb.DataType_String, schemapb.DataType_VarChar:
+		return defaultValue.GetStringData()
+	default:
+		return nil
+	}
+}
 
-			case schemapb.DataType_Int16:
-				singleData, err := eventReader.GetInt16FromPayload()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
+// appendPayloadValidData appends the validity of the payload rows to the validity of the field data,
+// which has rows before appended. The field data becomes nullable once any payload is nullable.
+func appendPayloadValidData(eventReader *EventReader, validData []bool, nullable bool, rows int, payloadRows int) ([]bool, bool, error) {
+	payloadValidData, err := eventReader.GetValidDataFromPayload()
+	if err != nil {
+		return nil, false, err
+	}
+	if payloadValidData == nil && !nullable {
+		return validData, nullable, nil
+	}
+	if !nullable {
+		validData = appendValidRows(make([]bool, 0, rows+payloadRows), true, rows)
+	}
+	if payloadValidData == nil {
+		return appendValidRows(validData, true, payloadRows), true, nil
+	}
+	return append(validData, payloadValidData...), true, nil
+}
 
-				if insertData.Data[fieldID] == nil {
-					insertData.Data[fieldID] = &Int16FieldData{
-						Data: make([]int16, 0, rowNum),
-					}
-				}
-				int16FieldData := insertData.Data[fieldID].(*Int16FieldData)
+// appendEventPayload appends the payload of the insert event to the field data of insertData,
+// rowNum is the capacity hint of the field data, it returns the row num of the payload.
+func appendEventPayload(eventReader *EventReader, dataType schemapb.DataType, fieldID FieldID, rowNum int, insertData *InsertData) (int, error) {
+	totalLength := 0
+	switch dataType {
+	case schemapb.DataType_Bool:
+		singleData, err := eventReader.GetBoolFromPayload()
+		if err != nil {
+			return 0, err
+		}
 
-				int16FieldData.Data = append(int16FieldData.Data, singleData...)
-				totalLength += len(singleData)
-				insertData.Data[fieldID] = int16FieldData
+		if insertData.Data[fieldID] == nil {
+			insertData.Data[fieldID] = &BoolFieldData{
+				Data: make([]bool, 0, rowNum),
+			}
+		}
+		boolFieldData := insertData.Data[fieldID].(*BoolFieldData)
 
-			case schemapb.DataType_Int32:
-				singleData, err := eventReader.GetInt32FromPayload()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
+		boolFieldData.ValidData, boolFieldData.Nullable, err = appendPayloadValidData(eventReader, boolFieldData.ValidData, boolFieldData.Nullable,
+			len(boolFieldData.Data), len(singleData))
+		if err != nil {
+			return 0, err
+		}
+		boolFieldData.Data = append(boolFieldData.Data, singleData...)
+		totalLength += len(singleData)
+		insertData.Data[fieldID] = boolFieldData
 
-				if insertData.Data[fieldID] == nil {
-					insertData.Data[fieldID] = &Int32FieldData{
-						Data: make([]int32, 0, rowNum),
-					}
-				}
-				int32FieldData := insertData.Data[fieldID].(*Int32FieldData)
+	case schemapb.DataType_Int8:
+		singleData, err := eventReader.GetInt8FromPayload()
+		if err != nil {
+			return 0, err
+		}
 
-				int32FieldData.Data = append(int32FieldData.Data, singleData...)
-				totalLength += len(singleData)
-				insertData.Data[fieldID] = int32FieldData
+		if insertData.Data[fieldID] == nil {
+			insertData.Data[fieldID] = &Int8FieldData{
+				Data: make([]int8, 0, rowNum),
+			}
+		}
+		int8FieldData := insertData.Data[fieldID].(*Int8FieldData)
 
-			case schemapb.DataType_Int64:
-				singleData, err := eventReader.GetInt64FromPayload()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
+		int8FieldData.ValidData, int8FieldData.Nullable, err = appendPayloadValidData(eventReader, int8FieldData.ValidData, int8FieldData.Nullable,
+			len(int8FieldData.Data), len(singleData))
+		if err != nil {
+			return 0, err
+		}
+		int8FieldData.Data = append(int8FieldData.Data, singleData...)
+		totalLength += len(singleData)
+		insertData.Data[fieldID] = int8FieldData
 
-				if insertData.Data[fieldID] == nil {
-					insertData.Data[fieldID] = &Int64FieldData{
-						Data: make([]int64, 0, rowNum),
-					}
-				}
-				int64FieldData := insertData.Data[fieldID].(*Int64FieldData)
+	case schemapb.DataType_Int16:
+		singleData, err := eventReader.GetInt16FromPayload()
+		if err != nil {
+			return 0, err
+		}
 
-				int64FieldData.Data = append(int64FieldData.Data, singleData...)
-				totalLength += len(singleData)
-				insertData.Data[fieldID] = int64FieldData
+		if insertData.Data[fieldID] == nil {
+			insertData.Data[fieldID] = &Int16FieldData{
+				Data: make([]int16, 0, rowNum),
+			}
+		}
+		int16FieldData := insertData.Data[fieldID].(*Int16FieldData)
 
-			case schemapb.DataType_Float:
-				singleData, err := eventReader.GetFloatFromPayload()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
+		int16FieldData.ValidData, int16FieldData.Nullable, err = appendPayloadValidData(eventReader, int16FieldData.ValidData, int16FieldData.Nullable,
+			len(int16FieldData.Data), len(singleData))
+		if err != nil {
+			return 0, err
+		}
+		int16FieldData.Data = append(int16FieldData.Data, singleData...)
+		totalLength += len(singleData)
+		insertData.Data[fieldID] = int16FieldData
 
-				if insertData.Data[fieldID] == nil {
-					insertData.Data[fieldID] = &FloatFieldData{
-						Data: make([]float32, 0, rowNum),
-					}
-				}
-				floatFieldData := insertData.Data[fieldID].(*FloatFieldData)
+	case schemapb.DataType_Int32:
+		singleData, err := eventReader.GetInt32FromPayload()
+		if err != nil {
+			return 0, err
+		}
 
-				floatFieldData.Data = append(floatFieldData.Data, singleData...)
-				totalLength += len(singleData)
-				insertData.Data[fieldID] = floatFieldData
+		if insertData.Data[fieldID] == nil {
+			insertData.Data[fieldID] = &Int32FieldData{
+				Data: make([]int32, 0, rowNum),
+			}
+		}
+		int32FieldData := insertData.Data[fieldID].(*Int32FieldData)
 
-			case schemapb.DataType_Double:
-				singleData, err := eventReader.GetDoubleFromPayload()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
+		int32FieldData.ValidData, int32FieldData.Nullable, err = appendPayloadValidData(eventReader, int32FieldData.ValidData, int32FieldData.Nullable,
+			len(int32FieldData.Data), len(singleData))
+		if err != nil {
+			return 0, err
+		}
+		int32FieldData.Data = append(int32FieldData.Data, singleData...)
+		totalLength += len(singleData)
+		insertData.Data[fieldID] = int32FieldData
 
-				if insertData.Data[fieldID] == nil {
-					insertData.Data[fieldID] = &DoubleFieldData{
-						Data: make([]float64, 0, rowNum),
-					}
-				}
-				doubleFieldData := insertData.Data[fieldID].(*DoubleFieldData)
+	case schemapb.DataType_Int64:
+		singleData, err := eventReader.GetInt64FromPayload()
+		if err != nil {
+			return 0, err
+		}
 
-				doubleFieldData.Data = append(doubleFieldData.Data, singleData...)
-				totalLength += len(singleData)
-				insertData.Data[fieldID] = doubleFieldData
+		if insertData.Data[fieldID] == nil {
+			insertData.Data[fieldID] = &Int64FieldData{
+				Data: make([]int64, 0, rowNum),
+			}
+		}
+		int64FieldData := insertData.Data[fieldID].(*Int64FieldData)
 
-			case schemapb.DataType_String, schemapb.DataType_VarChar:
-				stringPayload, err := eventReader.GetStringFromPayload()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
+		int64FieldData.ValidData, int64FieldData.Nullable, err = appendPayloadValidData(eventReader, int64FieldData.ValidData, int64FieldData.Nullable,
+			len(int64FieldData.Data), len(singleData))
+		if err != nil {
+			return 0, err
+		}
+		int64FieldData.Data = append(int64FieldData.Data, singleData...)
+		totalLength += len(singleData)
+		insertData.Data[fieldID] = int64FieldData
 
-				if insertData.Data[fieldID] == nil {
-					insertData.Data[fieldID] = &StringFieldData{
-						Data: make([]string, 0, rowNum),
-					}
-				}
-				stringFieldData := insertData.Data[fieldID].(*StringFieldData)
+	case schemapb.DataType_Float:
+		singleData, err := eventReader.GetFloatFromPayload()
+		if err != nil {
+			return 0, err
+		}
 
-				stringFieldData.Data = append(stringFieldData.Data, stringPayload...)
-				stringFieldData.DataType = dataType
-				totalLength += len(stringPayload)
-				insertData.Data[fieldID] = stringFieldData
+		if insertData.Data[fieldID] == nil {
+			insertData.Data[fieldID] = &FloatFieldData{
+				Data: make([]float32, 0, rowNum),
+			}
+		}
+		floatFieldData := insertData.Data[fieldID].(*FloatFieldData)
 
-			case schemapb.DataType_Array:
-				arrayPayload, err := eventReader.GetArrayFromPayload()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
+		floatFieldData.ValidData, floatFieldData.Nullable, err = appendPayloadValidData(eventReader, floatFieldData.ValidData, floatFieldData.Nullable,
+			len(floatFieldData.Data), len(singleData))
+		if err != nil {
+			return 0, err
+		}
+		floatFieldData.Data = append(floatFieldData.Data, singleData...)
+		totalLength += len(singleData)
+		insertData.Data[fieldID] = floatFieldData
 
-				if insertData.Data[fieldID] == nil {
-					insertData.Data[fieldID] = &ArrayFieldData{
-						Data: make([]*schemapb.ScalarField, 0, rowNum),
-					}
-				}
-				arrayFieldData := insertData.Data[fieldID].(*ArrayFieldData)
+	case schemapb.DataType_Double:
+		singleData, err := eventReader.GetDoubleFromPayload()
+		if err != nil {
+			return 0, err
+		}
 
-				arrayFieldData.Data = append(arrayFieldData.Data, arrayPayload...)
-				totalLength += len(arrayPayload)
-				insertData.Data[fieldID] = arrayFieldData
+		if insertData.Data[fieldID] == nil {
+			insertData.Data[fieldID] = &DoubleFieldData{
+				Data: make([]float64, 0, rowNum),
+			}
+		}
+		doubleFieldData := insertData.Data[fieldID].(*DoubleFieldData)
 
-			case schemapb.DataType_JSON:
-				jsonPayload, err := eventReader.GetJSONFromPayload()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
+		doubleFieldData.ValidData, doubleFieldData.Nullable, err = appendPayloadValidData(eventReader, doubleFieldData.ValidData, doubleFieldData.Nullable,
+			len(doubleFieldData.Data), len(singleData))
+		if err != nil {
+			return 0, err
+		}
+		doubleFieldData.Data = append(doubleFieldData.Data, singleData...)
+		totalLength += len(singleData)
+		insertData.Data[fieldID] = doubleFieldData
 
-				if insertData.Data[fieldID] == nil {
-					insertData.Data[fieldID] = &JSONFieldData{
-						Data: make([][]byte, 0, rowNum),
-					}
-				}
-				jsonFieldData := insertData.Data[fieldID].(*JSONFieldData)
+	case schemapb.DataType_String, schemapb.DataType_VarChar:
+		stringPayload, err := eventReader.GetStringFromPayload()
+		if err != nil {
+			return 0, err
+		}
 
-				jsonFieldData.Data = append(jsonFieldData.Data, jsonPayload...)
-				totalLength += len(jsonPayload)
-				insertData.Data[fieldID] = jsonFieldData
+		if insertData.Data[fieldID] == nil {
+			insertData.Data[fieldID] = &StringFieldData{
+				Data: make([]string, 0, rowNum),
+			}
+		}
+		stringFieldData := insertData.Data[fieldID].(*StringFieldData)
 
-			case schemapb.DataType_BinaryVector:
-				var singleData []byte
-				singleData, dim, err = eventReader.GetBinaryVectorFromPayload()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
+		stringFieldData.ValidData, stringFieldData.Nullable, err = appendPayloadValidData(eventReader, stringFieldData.ValidData, stringFieldData.Nullable,
+			len(stringFieldData.Data), len(stringPayload))
+		if err != nil {
+			return 0, err
+		}
+		stringFieldData.Data = append(stringFieldData.Data, stringPayload...)
+		stringFieldData.DataType = dataType
+		totalLength += len(stringPayload)
+		insertData.Data[fieldID] = stringFieldData
 
-				if insertData.Data[fieldID] == nil {
-					insertData.Data[fieldID] = &BinaryVectorFieldData{
-						Data: make([]byte, 0, rowNum*dim),
-					}
-				}
-				binaryVectorFieldData := insertData.Data[fieldID].(*BinaryVectorFieldData)
+	case schemapb.DataType_Array:
+		arrayPayload, err := eventReader.GetArrayFromPayload()
+		if err != nil {
+			return 0, err
+		}
 
-				binaryVectorFieldData.Data = append(binaryVectorFieldData.Data, singleData...)
-				length, err := eventReader.GetPayloadLengthFromReader()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
-				totalLength += length
-				binaryVectorFieldData.Dim = dim
-				insertData.Data[fieldID] = binaryVectorFieldData
+		if insertData.Data[fieldID] == nil {
+			insertData.Data[fieldID] = &ArrayFieldData{
+				Data: make([]*schemapb.ScalarField, 0, rowNum),
+			}
+		}
+		arrayFieldData := insertData.Data[fieldID].(*ArrayFieldData)
 
-			case schemapb.DataType_Float16Vector:
-				var singleData []byte
-				singleData, dim, err = eventReader.GetFloat16VectorFromPayload()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
+		arrayFieldData.ValidData, arrayFieldData.Nullable, err = appendPayloadValidData(eventReader, arrayFieldData.ValidData, arrayFieldData.Nullable,
+			len(arrayFieldData.Data), len(arrayPayload))
+		if err != nil {
+			return 0, err
+		}
+		arrayFieldData.Data = append(arrayFieldData.Data, arrayPayload...)
+		totalLength += len(arrayPayload)
+		insertData.Data[fieldID] = arrayFieldData
 
-				if insertData.Data[fieldID] == nil {
-					insertData.Data[fieldID] = &Float16VectorFieldData{
-						Data: make([]byte, 0, rowNum*dim),
-					}
-				}
-				float16VectorFieldData := insertData.Data[fieldID].(*Float16VectorFieldData)
+	case schemapb.DataType_JSON:
+		jsonPayload, err := eventReader.GetJSONFromPayload()
+		if err != nil {
+			return 0, err
+		}
 
-				float16VectorFieldData.Data = append(float16VectorFieldData.Data, singleData...)
-				length, err := eventReader.GetPayloadLengthFromReader()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
-				totalLength += length
-				float16VectorFieldData.Dim = dim
-				insertData.Data[fieldID] = float16VectorFieldData
+		if insertData.Data[fieldID] == nil {
+			insertData.Data[fieldID] = &JSONFieldData{
+				Data: make([][]byte, 0, rowNum),
+			}
+		}
+		jsonFieldData := insertData.Data[fieldID].(*JSONFieldData)
 
-			case schemapb.DataType_BFloat16Vector:
-				var singleData []byte
-				singleData, dim, err = eventReader.GetBFloat16VectorFromPayload()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
+		jsonFieldData.ValidData, jsonFieldData.Nullable, err = appendPayloadValidData(eventReader, jsonFieldData.ValidData, jsonFieldData.Nullable,
+			len(jsonFieldData.Data), len(jsonPayload))
+		if err != nil {
+			return 0, err
+		}
+		jsonFieldData.Data = append(jsonFieldData.Data, jsonPayload...)
+		totalLength += len(jsonPayload)
+		insertData.Data[fieldID] = jsonFieldData
 
-				if insertData.Data[fieldID] == nil {
-					insertData.Data[fieldID] = &BFloat16VectorFieldData{
-						Data: make([]byte, 0, rowNum*dim),
-					}
-				}
-				bfloat16VectorFieldData := insertData.Data[fieldID].(*BFloat16VectorFieldData)
+	case schemapb.DataType_BinaryVector:
+		singleData, dim, err := eventReader.GetBinaryVectorFromPayload()
+		if err != nil {
+			return 0, err
+		}
 
-				bfloat16VectorFieldData.Data = append(bfloat16VectorFieldData.Data, singleData...)
-				length, err := eventReader.GetPayloadLengthFromReader()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
-				totalLength += length
-				bfloat16VectorFieldData.Dim = dim
-				insertData.Data[fieldID] = bfloat16VectorFieldData
+		if insertData.Data[fieldID] == nil {
+			insertData.Data[fieldID] = &BinaryVectorFieldData{
+				Data: make([]byte, 0, rowNum*dim),
+			}
+		}
+		binaryVectorFieldData := insertData.Data[fieldID].(*BinaryVectorFieldData)
 
-			case schemapb.DataType_FloatVector:
-				var singleData []float32
-				singleData, dim, err = eventReader.GetFloatVectorFromPayload()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
+		binaryVectorFieldData.Data = append(binaryVectorFieldData.Data, singleData...)
+		length, err := eventReader.GetPayloadLengthFromReader()
+		if err != nil {
+			return 0, err
+		}
+		totalLength += length
+		binaryVectorFieldData.Dim = dim
+		insertData.Data[fieldID] = binaryVectorFieldData
 
-				if insertData.Data[fieldID] == nil {
-					insertData.Data[fieldID] = &FloatVectorFieldData{
-						Data: make([]float32, 0, rowNum*dim),
-					}
-				}
-				floatVectorFieldData := insertData.Data[fieldID].(*FloatVectorFieldData)
+	case schemapb.DataType_Float16Vector:
+		singleData, dim, err := eventReader.GetFloat16VectorFromPayload()
+		if err != nil {
+			return 0, err
+		}
 
-				floatVectorFieldData.Data = append(floatVectorFieldData.Data, singleData...)
-				length, err := eventReader.GetPayloadLengthFromReader()
-				if err != nil {
-					eventReader.Close()
-					binlogReader.Close()
-					return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, err
-				}
-				totalLength += length
-				floatVectorFieldData.Dim = dim
-				insertData.Data[fieldID] = floatVectorFieldData
+		if insertData.Data[fieldID] == nil {
+			insertData.Data[fieldID] = &Float16VectorFieldData{
+				Data: make([]byte, 0, rowNum*dim),
+			}
+		}
+		float16VectorFieldData := insertData.Data[fieldID].(*Float16VectorFieldData)
 
-			default:
-				eventReader.Close()
-				binlogReader.Close()
-				return InvalidUniqueID, InvalidUniqueID, InvalidUniqueID, fmt.Errorf("undefined data type %d", dataType)
+		float16VectorFieldData.Data = append(float16VectorFieldData.Data, singleData...)
+		length, err := eventReader.GetPayloadLengthFromReader()
+		if err != nil {
+			return 0, err
+		}
+		totalLength += length
+		float16VectorFieldData.Dim = dim
+		insertData.Data[fieldID] = float16VectorFieldData
+
+	case schemapb.DataType_BFloat16Vector:
+		singleData, dim, err := eventReader.GetBFloat16VectorFromPayload()
+		if err != nil {
+			return 0, err
+		}
+
+		if insertData.Data[fieldID] == nil {
+			insertData.Data[fieldID] = &BFloat16VectorFieldData{
+				Data: make([]byte, 0, rowNum*dim),
 			}
-			eventReader.Close()
 		}
+		bfloat16VectorFieldData := insertData.Data[fieldID].(*BFloat16VectorFieldData)
 
-		if rowNum <= 0 {
-			rowNum = totalLength
+		bfloat16VectorFieldData.Data = append(bfloat16VectorFieldData.Data, singleData...)
+		length, err := eventReader.GetPayloadLengthFromReader()
+		if err != nil {
+			return 0, err
 		}
+		totalLength += length
+		bfloat16VectorFieldData.Dim = dim
+		insertData.Data[fieldID] = bfloat16VectorFieldData
 
-		if fieldID == common.TimeStampField {
-			blobInfo := BlobInfo{
-				Length: totalLength,
+	case schemapb.DataType_FloatVector:
+		singleData, dim, err := eventReader.GetFloatVectorFromPayload()
+		if err != nil {
+			return 0, err
+		}
+
+		if insertData.Data[fieldID] == nil {
+			insertData.Data[fieldID] = &FloatVectorFieldData{
+				Data: make([]float32, 0, rowNum*dim),
 			}
-			insertData.Infos = append(insertData.Infos, blobInfo)
 		}
-		binlogReader.Close()
-	}
+		floatVectorFieldData := insertData.Data[fieldID].(*FloatVectorFieldData)
 
-	return collectionID, partitionID, segmentID, nil
+		floatVectorFieldData.Data = append(floatVectorFieldData.Data, singleData...)
+		length, err := eventReader.GetPayloadLengthFromReader()
+		if err != nil {
+			return 0, err
+		}
+		totalLength += length
+		floatVectorFieldData.Dim = dim
+		insertData.Data[fieldID] = floatVectorFieldData
+
+	default:
+		return 0, fmt.Errorf("undefined data type %d", dataType)
+	}
+	return totalLength, nil
 }
 
 // func deserializeEntity[T any, U any](
@@ -927,8 +1035,83 @@ func (data *DeleteData) Size() int64 {
 	return data.memSize
 }
 
+// Dedup returns the delete data keeping the latest delete of each primary key only, in the order of the deletes,
+// the delete data itself is returned if there are no duplicated primary keys.
+func (data *DeleteData) Dedup() *DeleteData {
+	if len(data.Pks) != len(data.Tss) {
+		return data
+	}
+	latest := make(map[interface{}]int, len(data.Pks))
+	for i, pk := range data.Pks {
+		if j, ok := latest[pk.GetValue()]; !ok || data.Tss[i] >= data.Tss[j] {
+			latest[pk.GetValue()] = i
+		}
+	}
+	if len(latest) == len(data.Pks) {
+		return data
+	}
+
+	deduped := &DeleteData{
+		Pks: make([]PrimaryKey, 0, len(latest)),
+		Tss: make([]Timestamp, 0, len(latest)),
+	}
+	for i, pk := range data.Pks {
+		if latest[pk.GetValue()] == i {
+			deduped.Append(pk, data.Tss[i])
+		}
+	}
+	return deduped
+}
+
+const (
+	// deltalogChunkIndexKey and deltalogChunkNumKey are the keys of the descriptor extras
+	// which mark a deltalog as the chunk of a delete data split by DeleteCodec.SerializeChunks.
+	deltalogChunkIndexKey = "chunk_index"
+	deltalogChunkNumKey   = "chunk_num"
+)
+
+// deleteChunk is the ordering metadata of a chunk of the delete data.
+type deleteChunk struct {
+	index   int
+	num     int
+	startTs Timestamp
+	endTs   Timestamp
+}
+
+// deleteChunkOf returns the ordering metadata of the deltalog, nil if it's not a chunk.
+func deleteChunkOf(reader *BinlogReader) (*deleteChunk, error) {
+	indexStr, ok := reader.Extras[deltalogChunkIndexKey].(string)
+	if !ok {
+		return nil, nil
+	}
+	numStr, ok := reader.Extras[deltalogChunkNumKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%v not in extra of the deltalog chunk", deltalogChunkNumKey)
+	}
+	index, err := strconv.Atoi(indexStr)
+	if err != nil {
+		return nil, err
+	}
+	num, err := strconv.Atoi(numStr)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= num {
+		return nil, fmt.Errorf("invalid deltalog chunk %d of %d", index, num)
+	}
+	return &deleteChunk{
+		index:   index,
+		num:     num,
+		startTs: reader.StartTimestamp,
+		endTs:   reader.EndTimestamp,
+	}, nil
+}
+
 // DeleteCodec serializes and deserializes the delete data
-type DeleteCodec struct{}
+type DeleteCodec struct {
+	// SortedByPK writes the sorted deltalogs, see SortedDeleteData, the deltalogs of both layouts are read
+	SortedByPK bool
+}
 
 // NewDeleteCodec returns a DeleteCodec
 func NewDeleteCodec() *DeleteCodec {
@@ -938,6 +1121,93 @@ func NewDeleteCodec() *DeleteCodec {
 // Serialize transfer delete data to blob. .
 // For each delete message, it will save "pk,ts" string to binlog.
 func (deleteCodec *DeleteCodec) Serialize(collectionID UniqueID, partitionID UniqueID, segmentID UniqueID, data *DeleteData) (*Blob, error) {
+	length := len(data.Pks)
+	if length != len(data.Tss) {
+		return nil, fmt.Errorf("the length of pks, and TimeStamps is not equal")
+	}
+	if deleteCodec.SortedByPK {
+		return deleteCodec.serializeSorted(collectionID, partitionID, segmentID, NewSortedDeleteData(data), nil)
+	}
+	return deleteCodec.serialize(collectionID, partitionID, segmentID, data.Pks, data.Tss, nil)
+}
+
+// SerializeChunks transfers delete data to blobs, each of which holds the delete messages of chunkSize bytes at most,
+// so that a huge delete doesn't produce a deltalog exceeding the limits of the message or the object storage.
+// The chunks are marked with the ordering metadata, and Deserialize puts them back in order,
+// no matter in which order the blobs are passed.
+// It's the same as Serialize if the delete data fits in one chunk.
+func (deleteCodec *DeleteCodec) SerializeChunks(collectionID UniqueID, partitionID UniqueID, segmentID UniqueID, data *DeleteData, chunkSize int64) ([]*Blob, error) {
+	length := len(data.Pks)
+	if length != len(data.Tss) {
+		return nil, fmt.Errorf("the length of pks, and TimeStamps is not equal")
+	}
+	if chunkSize <= 0 {
+		return nil, merr.WrapErrParameterInvalidMsg("chunk size must be positive, but got %d", chunkSize)
+	}
+	// the sorted deltalogs are chunked by the ranges of the primary keys
+	var sorted *SortedDeleteData
+	if deleteCodec.SortedByPK {
+		sorted = NewSortedDeleteData(data)
+		data = sorted.DeleteData()
+	}
+
+	// the row offsets where the chunks start
+	starts := []int{0}
+	var size int64
+	for i := 0; i < length; i++ {
+		rowSize := data.Pks[i].Size() + int64(8)
+		if size > 0 && size+rowSize > chunkSize {
+			starts = append(starts, i)
+			size = 0
+		}
+		size += rowSize
+	}
+	if len(starts) == 1 {
+		if sorted != nil {
+			blob, err := deleteCodec.serializeSorted(collectionID, partitionID, segmentID, sorted, nil)
+			if err != nil {
+				return nil, err
+			}
+			return []*Blob{blob}, nil
+		}
+		blob, err := deleteCodec.serialize(collectionID, partitionID, segmentID, data.Pks, data.Tss, nil)
+		if err != nil {
+			return nil, err
+		}
+		return []*Blob{blob}, nil
+	}
+
+	startTs, endTs := lo.Min(data.Tss), lo.Max(data.Tss)
+	blobs := make([]*Blob, 0, len(starts))
+	for idx, start := range starts {
+		end := length
+		if idx+1 < len(starts) {
+			end = starts[idx+1]
+		}
+		chunk := &deleteChunk{
+			index:   idx,
+			num:     len(starts),
+			startTs: startTs,
+			endTs:   endTs,
+		}
+		var blob *Blob
+		var err error
+		if sorted != nil {
+			part := &SortedDeleteData{Pks: sorted.Pks[start:end], Tss: sorted.Tss[start:end]}
+			part.buildIndex()
+			blob, err = deleteCodec.serializeSorted(collectionID, partitionID, segmentID, part, chunk)
+		} else {
+			blob, err = deleteCodec.serialize(collectionID, partitionID, segmentID, data.Pks[start:end], data.Tss[start:end], chunk)
+		}
+		if err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, blob)
+	}
+	return blobs, nil
+}
+
+func (deleteCodec *DeleteCodec) serialize(collectionID UniqueID, partitionID UniqueID, segmentID UniqueID, pks []PrimaryKey, tss []Timestamp, chunk *deleteChunk) (*Blob, error) {
 	binlogWriter := NewDeleteBinlogWriter(schemapb.DataType_String, collectionID, partitionID, segmentID)
 	eventWriter, err := binlogWriter.NextDeleteEventWriter()
 	if err != nil {
@@ -946,16 +1216,12 @@ func (deleteCodec *DeleteCodec) Serialize(collectionID UniqueID, partitionID Uni
 	}
 	defer binlogWriter.Close()
 	defer eventWriter.Close()
-	length := len(data.Pks)
-	if length != len(data.Tss) {
-		return nil, fmt.Errorf("the length of pks, and TimeStamps is not equal")
-	}
 
 	sizeTotal := 0
 	var startTs, endTs Timestamp
 	startTs, endTs = math.MaxUint64, 0
-	for i := 0; i < length; i++ {
-		ts := data.Tss[i]
+	for i := 0; i < len(pks); i++ {
+		ts := tss[i]
 		if ts < startTs {
 			startTs = ts
 		}
@@ -963,7 +1229,7 @@ func (deleteCodec *DeleteCodec) Serialize(collectionID UniqueID, partitionID Uni
 			endTs = ts
 		}
 
-		deleteLog := NewDeleteLog(data.Pks[i], ts)
+		deleteLog := NewDeleteLog(pks[i], ts)
 		serializedPayload, err := json.Marshal(deleteLog)
 		if err != nil {
 			return nil, err
@@ -975,7 +1241,16 @@ func (deleteCodec *DeleteCodec) Serialize(collectionID UniqueID, partitionID Uni
 		sizeTotal += binary.Size(serializedPayload)
 	}
 	eventWriter.SetEventTimestamp(startTs, endTs)
-	binlogWriter.SetEventTimeStamp(startTs, endTs)
+	if chunk != nil {
+		// the chunks of the same delete data share the time range of the whole delete data,
+		// by which the chunks are grouped on read
+		binlogWriter.SetEventTimeStamp(chunk.startTs, chunk.endTs)
+		binlogWriter.AddExtra(deltalogChunkIndexKey, strconv.Itoa(chunk.index))
+		binlogWriter.AddExtra(deltalogChunkNumKey, strconv.Itoa(chunk.num))
+		binlogWriter.AddFeature(BinlogFeatureDeltalogChunk, false)
+	} else {
+		binlogWriter.SetEventTimeStamp(startTs, endTs)
+	}
 
 	// https://github.com/milvus-io/milvus/issues/9620
 	// It's a little complicated to count the memory size of a map.
@@ -992,19 +1267,32 @@ func (deleteCodec *DeleteCodec) Serialize(collectionID UniqueID, partitionID Uni
 		return nil, err
 	}
 	blob := &Blob{
-		Value: buffer,
+		Value:   buffer,
+		RowNum:  int64(len(pks)),
+		release: binlogWriter.releaseBuffer,
 	}
 	return blob, nil
 }
 
-// Deserialize deserializes the deltalog blobs into DeleteData
+// Deserialize deserializes the deltalog blobs into DeleteData.
+// The chunks written by SerializeChunks are put back in order at the position of the first chunk read,
+// and it fails if any chunk is missing.
 func (deleteCodec *DeleteCodec) Deserialize(blobs []*Blob) (partitionID UniqueID, segmentID UniqueID, data *DeleteData, err error) {
 	if len(blobs) == 0 {
 		return InvalidUniqueID, InvalidUniqueID, nil, fmt.Errorf("blobs is empty")
 	}
 
 	var pid, sid UniqueID
-	result := &DeleteData{}
+
+	// the chunks of the same delete data share the time range of the whole delete data
+	type chunkGroup struct {
+		startTs Timestamp
+		endTs   Timestamp
+		num     int
+	}
+	// parts are the delete data of the blobs in order, the chunks of a group take one part
+	parts := make([][]*DeleteData, 0, len(blobs))
+	groups := make(map[chunkGroup]int)
 
 	deserializeBlob := func(blob *Blob) error {
 		binlogReader, err := NewBinlogReader(blob.Value)
@@ -1012,8 +1300,40 @@ func (deleteCodec *DeleteCodec) Deserialize(blobs []*Blob) (partitionID UniqueID
 			return err
 		}
 		defer binlogReader.Close()
+		if binlogReader.HasFeature(BinlogFeatureDeltalogBitmap) {
+			return fmt.Errorf("deltalog of segment %d records row offsets, read it by DeserializeBitmaps", binlogReader.SegmentID)
+		}
 
 		pid, sid = binlogReader.PartitionID, binlogReader.SegmentID
+		chunk, err := deleteChunkOf(binlogReader)
+		if err != nil {
+			return err
+		}
+		result := &DeleteData{}
+		if chunk == nil {
+			parts = append(parts, []*DeleteData{result})
+		} else {
+			group := chunkGroup{startTs: chunk.startTs, endTs: chunk.endTs, num: chunk.num}
+			idx, ok := groups[group]
+			if !ok {
+				idx = len(parts)
+				groups[group] = idx
+				parts = append(parts, make([]*DeleteData, chunk.num))
+			}
+			if parts[idx][chunk.index] != nil {
+				return fmt.Errorf("duplicated deltalog chunk %d of %d", chunk.index, chunk.num)
+			}
+			parts[idx][chunk.index] = result
+		}
+		if binlogReader.HasFeature(BinlogFeatureDeltalogSorted) {
+			sorted, err := deserializeSorted(binlogReader)
+			if err != nil {
+				return err
+			}
+			result.AppendBatch(sorted.Pks, sorted.Tss)
+			return nil
+		}
+
 		eventReader, err := binlogReader.NextEventReader()
 		if err != nil {
 			return err
@@ -1067,9 +1387,44 @@ func (deleteCodec *DeleteCodec) Deserialize(blobs []*Blob) (partitionID UniqueID
 		}
 	}
 
+	result := &DeleteData{}
+	for _, part := range parts {
+		for idx, chunk := range part {
+			if chunk == nil {
+				return InvalidUniqueID, InvalidUniqueID, nil, fmt.Errorf("deltalog chunk %d of %d is missing", idx, len(part))
+			}
+			result.AppendBatch(chunk.Pks, chunk.Tss)
+		}
+	}
 	return pid, sid, result, nil
 }
 
+// DeserializeSorted deserializes the deltalog blobs into SortedDeleteData, a single sorted deltalog
+// is taken with its index as is, the others are sorted after read.
+func (deleteCodec *DeleteCodec) DeserializeSorted(blobs []*Blob) (partitionID UniqueID, segmentID UniqueID, data *SortedDeleteData, err error) {
+	if len(blobs) == 1 {
+		binlogReader, err := NewBinlogReader(blobs[0].Value)
+		if err != nil {
+			return InvalidUniqueID, InvalidUniqueID, nil, err
+		}
+		defer binlogReader.Close()
+		if binlogReader.HasFeature(BinlogFeatureDeltalogSorted) {
+			if _, ok := binlogReader.Extras[deltalogChunkIndexKey]; !ok {
+				data, err := deserializeSorted(binlogReader)
+				if err != nil {
+					return InvalidUniqueID, InvalidUniqueID, nil, err
+				}
+				return binlogReader.PartitionID, binlogReader.SegmentID, data, nil
+			}
+		}
+	}
+	pid, sid, deleteData, err := deleteCodec.Deserialize(blobs)
+	if err != nil {
+		return InvalidUniqueID, InvalidUniqueID, nil, err
+	}
+	return pid, sid, NewSortedDeleteData(deleteData), nil
+}
+
 // DataDefinitionCodec serializes and deserializes the data definition
 // Blob key example:
 // ${tenant}/data_definition_log/${collection_id}/ts/${log_idx}
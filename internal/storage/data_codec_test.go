@@ -17,10 +17,17 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"testing"
 
+	"github.com/apache/arrow/go/v12/parquet"
+	"github.com/apache/arrow/go/v12/parquet/compress"
+	"github.com/apache/arrow/go/v12/parquet/file"
+	"github.com/apache/arrow/go/v12/parquet/metadata"
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -345,22 +352,22 @@ func TestInsertCodec(t *testing.T) {
 
 	insertDataEmpty := &InsertData{
 		Data: map[int64]FieldData{
-			RowIDField:          &Int64FieldData{[]int64{}},
-			TimestampField:      &Int64FieldData{[]int64{}},
-			BoolField:           &BoolFieldData{[]bool{}},
-			Int8Field:           &Int8FieldData{[]int8{}},
-			Int16Field:          &Int16FieldData{[]int16{}},
-			Int32Field:          &Int32FieldData{[]int32{}},
-			Int64Field:          &Int64FieldData{[]int64{}},
-			FloatField:          &FloatFieldData{[]float32{}},
-			DoubleField:         &DoubleFieldData{[]float64{}},
-			StringField:         &StringFieldData{[]string{}, schemapb.DataType_VarChar},
+			RowIDField:          &Int64FieldData{Data: []int64{}},
+			TimestampField:      &Int64FieldData{Data: []int64{}},
+			BoolField:           &BoolFieldData{Data: []bool{}},
+			Int8Field:           &Int8FieldData{Data: []int8{}},
+			Int16Field:          &Int16FieldData{Data: []int16{}},
+			Int32Field:          &Int32FieldData{Data: []int32{}},
+			Int64Field:          &Int64FieldData{Data: []int64{}},
+			FloatField:          &FloatFieldData{Data: []float32{}},
+			DoubleField:         &DoubleFieldData{Data: []float64{}},
+			StringField:         &StringFieldData{Data: []string{}, DataType: schemapb.DataType_VarChar},
 			BinaryVectorField:   &BinaryVectorFieldData{[]byte{}, 8},
 			FloatVectorField:    &FloatVectorFieldData{[]float32{}, 4},
 			Float16VectorField:  &Float16VectorFieldData{[]byte{}, 4},
 			BFloat16VectorField: &BFloat16VectorFieldData{[]byte{}, 4},
-			ArrayField:          &ArrayFieldData{schemapb.DataType_Int32, []*schemapb.ScalarField{}},
-			JSONField:           &JSONFieldData{[][]byte{}},
+			ArrayField:          &ArrayFieldData{ElementType: schemapb.DataType_Int32, Data: []*schemapb.ScalarField{}},
+			JSONField:           &JSONFieldData{Data: [][]byte{}},
 		},
 	}
 	b, err := insertCodec.Serialize(PartitionID, SegmentID, insertDataEmpty)
@@ -453,6 +460,203 @@ func TestInsertCodec(t *testing.T) {
 	assert.Error(t, err, "SerializePkStatsList zero length pkstats list shall return error")
 }
 
+func TestInsertCodecDeltaEncoding(t *testing.T) {
+	meta := genTestCollectionMeta()
+	for _, field := range meta.GetSchema().GetFields() {
+		if field.GetIsPrimaryKey() {
+			field.AutoID = true
+		}
+	}
+	data, err := genSequentialInsertData(meta.GetSchema(), 1, 2, 3)
+	require.NoError(t, err)
+	blobs, err := NewInsertCodecWithSchema(meta).Serialize(PartitionID, SegmentID, data)
+	require.NoError(t, err)
+
+	encodings := make(map[string][]parquet.Encoding)
+	for _, blob := range blobs {
+		binlogReader, err := NewBinlogReader(blob.Value)
+		require.NoError(t, err)
+		eventReader, err := binlogReader.NextEventReader()
+		require.NoError(t, err)
+		chunk, err := eventReader.PayloadReaderInterface.(*PayloadReader).reader.MetaData().RowGroup(0).ColumnChunk(0)
+		require.NoError(t, err)
+		encodings[blob.Key] = chunk.Encodings()
+		binlogReader.Close()
+	}
+	for _, key := range []int64{RowIDField, TimestampField, Int64Field} {
+		assert.Contains(t, encodings[fmt.Sprint(key)], parquet.Encodings.DeltaBinaryPacked, key)
+	}
+	for _, key := range []int64{Int32Field, FloatField} {
+		assert.NotContains(t, encodings[fmt.Sprint(key)], parquet.Encodings.DeltaBinaryPacked, key)
+	}
+}
+
+func TestInsertCodecFieldStorageOptions(t *testing.T) {
+	meta := genTestCollectionMeta()
+	for _, field := range meta.GetSchema().GetFields() {
+		switch field.GetFieldID() {
+		case RowIDField:
+			field.TypeParams = append(field.TypeParams, &commonpb.KeyValuePair{Key: common.FieldEncodingKey, Value: common.FieldEncodingDictionary})
+		case Int32Field:
+			field.TypeParams = append(field.TypeParams, &commonpb.KeyValuePair{Key: common.FieldEncodingKey, Value: common.FieldEncodingDelta})
+		case FloatField:
+			field.TypeParams = append(field.TypeParams, &commonpb.KeyValuePair{Key: common.FieldCompressionKey, Value: common.FieldCompressionSnappy})
+		case FloatVectorField:
+			field.TypeParams = append(field.TypeParams,
+				&commonpb.KeyValuePair{Key: common.FieldCompressionKey, Value: common.FieldCompressionNone},
+				&commonpb.KeyValuePair{Key: common.FieldEncodingKey, Value: common.FieldEncodingPlain})
+		}
+	}
+
+	for _, format := range []string{common.BinlogFormatNative, common.BinlogFormatParquet} {
+		data, err := genSequentialInsertData(meta.GetSchema(), 1, 2, 3)
+		require.NoError(t, err)
+		codec := NewInsertCodecWithSchema(meta)
+		codec.BinlogFormat = format
+		blobs, err := codec.Serialize(PartitionID, SegmentID, data)
+		require.NoError(t, err)
+
+		chunks := make(map[string]*metadata.ColumnChunkMetaData)
+		for _, blob := range blobs {
+			var reader *file.Reader
+			if format == common.BinlogFormatParquet {
+				reader, err = file.NewParquetReader(bytes.NewReader(blob.Value))
+				require.NoError(t, err)
+			} else {
+				binlogReader, err := NewBinlogReader(blob.Value)
+				require.NoError(t, err)
+				eventReader, err := binlogReader.NextEventReader()
+				require.NoError(t, err)
+				reader = eventReader.PayloadReaderInterface.(*PayloadReader).reader
+				defer binlogReader.Close()
+			}
+			chunks[blob.Key], err = reader.MetaData().RowGroup(0).ColumnChunk(0)
+			require.NoError(t, err)
+		}
+
+		assert.NotContains(t, chunks[fmt.Sprint(RowIDField)].Encodings(), parquet.Encodings.DeltaBinaryPacked, format)
+		assert.Contains(t, chunks[fmt.Sprint(Int32Field)].Encodings(), parquet.Encodings.DeltaBinaryPacked, format)
+		assert.Contains(t, chunks[fmt.Sprint(TimestampField)].Encodings(), parquet.Encodings.DeltaBinaryPacked, format)
+		assert.Equal(t, compress.Codecs.Snappy, chunks[fmt.Sprint(FloatField)].Compression(), format)
+		assert.Equal(t, compress.Codecs.Zstd, chunks[fmt.Sprint(Int32Field)].Compression(), format)
+		assert.Equal(t, compress.Codecs.Uncompressed, chunks[fmt.Sprint(FloatVectorField)].Compression(), format)
+		assert.NotContains(t, chunks[fmt.Sprint(FloatVectorField)].Encodings(), parquet.Encodings.RLEDict, format)
+
+		_, _, _, result, err := codec.DeserializeAll(blobs)
+		require.NoError(t, err)
+		assert.Equal(t, data.Data[Int32Field], result.Data[Int32Field], format)
+		assert.Equal(t, data.Data[FloatVectorField], result.Data[FloatVectorField], format)
+	}
+
+	// delta encoding of float field
+	for _, field := range meta.GetSchema().GetFields() {
+		if field.GetFieldID() == FloatField {
+			field.TypeParams = append(field.TypeParams, &commonpb.KeyValuePair{Key: common.FieldEncodingKey, Value: common.FieldEncodingDelta})
+		}
+	}
+	for _, format := range []string{common.BinlogFormatNative, common.BinlogFormatParquet} {
+		data, err := genSequentialInsertData(meta.GetSchema(), 1, 2, 3)
+		require.NoError(t, err)
+		codec := NewInsertCodecWithSchema(meta)
+		codec.BinlogFormat = format
+		_, err = codec.Serialize(PartitionID, SegmentID, data)
+		assert.Error(t, err, format)
+	}
+}
+
+func TestInsertCodecNullable(t *testing.T) {
+	nullableFields := []int64{BoolField, Int8Field, Int16Field, Int32Field, FloatField, DoubleField, StringField, ArrayField, JSONField}
+	meta := genTestCollectionMeta()
+	for _, field := range meta.GetSchema().GetFields() {
+		if lo.Contains(nullableFields, field.GetFieldID()) {
+			field.TypeParams = append(field.TypeParams, &commonpb.KeyValuePair{Key: common.FieldNullableKey, Value: "true"})
+		}
+	}
+	data, err := genSequentialInsertData(meta.GetSchema(), 1, 2, 3)
+	require.NoError(t, err)
+	row := data.GetRow(0)
+	row[RowIDField], row[TimestampField], row[Int64Field] = int64(4), int64(104), int64(4)
+	for _, fieldID := range nullableFields {
+		row[fieldID] = nil
+	}
+	require.NoError(t, data.Append(row))
+	assert.Nil(t, data.Data[JSONField].GetRow(3))
+	assert.Equal(t, []bool{true, true, true, false}, data.Data[Int32Field].(*Int32FieldData).ValidData)
+	// the non-nullable fields reject the null rows
+	assert.Error(t, data.Data[Int64Field].AppendRow(nil))
+
+	for _, format := range []string{common.BinlogFormatNative, common.BinlogFormatParquet} {
+		codec := NewInsertCodecWithSchema(meta)
+		codec.BinlogFormat = format
+		blobs, err := codec.Serialize(PartitionID, SegmentID, data)
+		require.NoError(t, err)
+		_, _, _, result, err := codec.DeserializeAll(blobs)
+		require.NoError(t, err)
+		for _, fieldID := range nullableFields {
+			if fieldID == ArrayField {
+				continue
+			}
+			assert.Equal(t, data.Data[fieldID], result.Data[fieldID], format, fieldID)
+		}
+		assert.Equal(t, data.Data[Int64Field], result.Data[Int64Field], format)
+		arrayData := result.Data[ArrayField].(*ArrayFieldData)
+		assert.Equal(t, []bool{true, true, true, false}, arrayData.ValidData, format)
+		assert.True(t, proto.Equal(data.Data[ArrayField].GetRow(0).(*schemapb.ScalarField), arrayData.Data[0]), format)
+		assert.Nil(t, arrayData.Data[3], format)
+	}
+
+	record, err := InsertDataToRecord(meta.GetSchema(), data)
+	require.NoError(t, err)
+	defer record.Release()
+	assert.Equal(t, 1, record.Column(StringField).NullN())
+	assert.Equal(t, data.Data[DoubleField].GetMemorySize(), record.FieldMemorySize(DoubleField))
+	result, err := RecordToInsertData(record)
+	require.NoError(t, err)
+	for _, fieldID := range nullableFields {
+		if fieldID == ArrayField {
+			continue
+		}
+		assert.Equal(t, data.Data[fieldID], result.Data[fieldID], fieldID)
+	}
+	assert.Nil(t, result.Data[ArrayField].GetRow(3))
+}
+
+func TestInsertCodecFillMissingFields(t *testing.T) {
+	meta := genTestCollectionMeta()
+	data, err := genSequentialInsertData(meta.GetSchema(), 1, 2, 3)
+	require.NoError(t, err)
+	blobs, err := NewInsertCodecWithSchema(meta).Serialize(PartitionID, SegmentID, data)
+	require.NoError(t, err)
+
+	// the fields added after the binlogs flushed
+	meta.Schema.Fields = append(meta.Schema.Fields,
+		&schemapb.FieldSchema{
+			FieldID:      200,
+			Name:         "field_default",
+			DataType:     schemapb.DataType_Int64,
+			DefaultValue: &schemapb.ValueField{Data: &schemapb.ValueField_LongData{LongData: 7}},
+		},
+		&schemapb.FieldSchema{
+			FieldID:    201,
+			Name:       "field_nullable",
+			DataType:   schemapb.DataType_VarChar,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.FieldNullableKey, Value: "true"}},
+		},
+		&schemapb.FieldSchema{
+			FieldID:  202,
+			Name:     "field_required",
+			DataType: schemapb.DataType_Int64,
+		},
+	)
+	_, _, _, result, err := NewInsertCodecWithSchema(meta).DeserializeAll(blobs)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{7, 7, 7}, result.Data[200].(*Int64FieldData).Data)
+	assert.Nil(t, result.Data[200].(*Int64FieldData).ValidData)
+	assert.Equal(t, []bool{false, false, false}, result.Data[201].(*StringFieldData).ValidData)
+	assert.Nil(t, result.Data[201].GetRow(0))
+	assert.NotContains(t, result.Data, int64(202))
+}
+
 func TestDeleteCodec(t *testing.T) {
 	t.Run("int64 pk", func(t *testing.T) {
 		deleteCodec := NewDeleteCodec()
@@ -491,6 +695,53 @@ func TestDeleteCodec(t *testing.T) {
 		assert.Equal(t, sid, int64(1))
 		assert.Equal(t, data, deleteData)
 	})
+
+	t.Run("chunks", func(t *testing.T) {
+		deleteCodec := NewDeleteCodec()
+		deleteData := NewDeleteData(nil, nil)
+		for i := 0; i < 10; i++ {
+			deleteData.Append(NewInt64PrimaryKey(int64(i)), uint64(100+i))
+		}
+		other := NewDeleteData([]PrimaryKey{NewInt64PrimaryKey(100)}, []uint64{1000})
+
+		// 24 bytes per int64 pk row
+		blobs, err := deleteCodec.SerializeChunks(CollectionID, 1, 1, deleteData, 72)
+		assert.NoError(t, err)
+		assert.Len(t, blobs, 4)
+		assert.Equal(t, []int64{3, 3, 3, 1}, lo.Map(blobs, func(blob *Blob, _ int) int64 { return blob.RowNum }))
+		otherBlobs, err := deleteCodec.SerializeChunks(CollectionID, 1, 1, other, 72)
+		assert.NoError(t, err)
+		assert.Len(t, otherBlobs, 1)
+
+		// chunks out of order and interleaved with another deltalog
+		_, _, data, err := deleteCodec.Deserialize([]*Blob{blobs[2], otherBlobs[0], blobs[0], blobs[3], blobs[1]})
+		assert.NoError(t, err)
+		expected := NewDeleteData(nil, nil)
+		expected.AppendBatch(deleteData.Pks, deleteData.Tss)
+		expected.AppendBatch(other.Pks, other.Tss)
+		assert.Equal(t, expected, data)
+
+		// fits in one chunk
+		blobs, err = deleteCodec.SerializeChunks(CollectionID, 1, 1, deleteData, 1024)
+		assert.NoError(t, err)
+		assert.Len(t, blobs, 1)
+		_, _, data, err = deleteCodec.Deserialize(blobs)
+		assert.NoError(t, err)
+		assert.Equal(t, deleteData, data)
+
+		// missing chunk
+		blobs, err = deleteCodec.SerializeChunks(CollectionID, 1, 1, deleteData, 72)
+		assert.NoError(t, err)
+		_, _, _, err = deleteCodec.Deserialize(blobs[1:])
+		assert.Error(t, err)
+
+		// duplicated chunk
+		_, _, _, err = deleteCodec.Deserialize(append(blobs, blobs[0]))
+		assert.Error(t, err)
+
+		_, err = deleteCodec.SerializeChunks(CollectionID, 1, 1, deleteData, 0)
+		assert.Error(t, err)
+	})
 }
 
 func TestUpgradeDeleteLog(t *testing.T) {
@@ -797,16 +1048,16 @@ func TestMemorySize(t *testing.T) {
 
 	insertDataEmpty := &InsertData{
 		Data: map[int64]FieldData{
-			RowIDField:        &Int64FieldData{[]int64{}},
-			TimestampField:    &Int64FieldData{[]int64{}},
-			BoolField:         &BoolFieldData{[]bool{}},
-			Int8Field:         &Int8FieldData{[]int8{}},
-			Int16Field:        &Int16FieldData{[]int16{}},
-			Int32Field:        &Int32FieldData{[]int32{}},
-			Int64Field:        &Int64FieldData{[]int64{}},
-			FloatField:        &FloatFieldData{[]float32{}},
-			DoubleField:       &DoubleFieldData{[]float64{}},
-			StringField:       &StringFieldData{[]string{}, schemapb.DataType_VarChar},
+			RowIDField:        &Int64FieldData{Data: []int64{}},
+			TimestampField:    &Int64FieldData{Data: []int64{}},
+			BoolField:         &BoolFieldData{Data: []bool{}},
+			Int8Field:         &Int8FieldData{Data: []int8{}},
+			Int16Field:        &Int16FieldData{Data: []int16{}},
+			Int32Field:        &Int32FieldData{Data: []int32{}},
+			Int64Field:        &Int64FieldData{Data: []int64{}},
+			FloatField:        &FloatFieldData{Data: []float32{}},
+			DoubleField:       &DoubleFieldData{Data: []float64{}},
+			StringField:       &StringFieldData{Data: []string{}, DataType: schemapb.DataType_VarChar},
 			BinaryVectorField: &BinaryVectorFieldData{[]byte{}, 8},
 			FloatVectorField:  &FloatVectorFieldData{[]float32{}, 4},
 		},
@@ -864,4 +1115,21 @@ func TestDeleteData(t *testing.T) {
 		assert.EqualValues(t, dData.RowCount, 3)
 		assert.EqualValues(t, dData.Size(), 72)
 	})
+
+	t.Run("dedup", func(t *testing.T) {
+		dData := NewDeleteData(pks, []Timestamp{100, 101, 102})
+		assert.Same(t, dData, dData.Dedup())
+
+		dData.AppendBatch([]PrimaryKey{pks[2], pks[0], pks[0]}, []Timestamp{99, 105, 103})
+		deduped := dData.Dedup()
+		assert.Equal(t, []PrimaryKey{pks[1], pks[2], pks[0]}, deduped.Pks)
+		assert.Equal(t, []Timestamp{101, 102, 105}, deduped.Tss)
+		assert.EqualValues(t, 3, deduped.RowCount)
+		assert.EqualValues(t, 72, deduped.Size())
+
+		varcharData := NewDeleteData(nil, nil)
+		varcharData.Append(NewVarCharPrimaryKey("a"), 100)
+		varcharData.Append(NewVarCharPrimaryKey("a"), 100)
+		assert.EqualValues(t, 1, varcharData.Dedup().RowCount)
+	})
 }
@@ -0,0 +1,235 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// deltalogBitmapRowsKey is the key of the descriptor extras which stores the number of rows
+// of the segment, which is the length of the bitmap.
+const deltalogBitmapRowsKey = "bitmap_rows"
+
+// DeleteBitmap is the deletes of a sealed segment recorded by the offsets of the deleted rows.
+// The offsets are resolved from the primary keys when the deletes are written,
+// so that they are applied on load without looking up the primary keys.
+type DeleteBitmap struct {
+	numRows int64
+	tss     map[int64]Timestamp
+}
+
+// NewDeleteBitmap returns an empty DeleteBitmap of a segment with numRows rows.
+func NewDeleteBitmap(numRows int64) *DeleteBitmap {
+	return &DeleteBitmap{
+		numRows: numRows,
+		tss:     make(map[int64]Timestamp),
+	}
+}
+
+// Add records the row at the offset deleted at ts, the earliest delete of a row takes effect.
+func (b *DeleteBitmap) Add(offset int64, ts Timestamp) {
+	if old, ok := b.tss[offset]; ok && old <= ts {
+		return
+	}
+	b.tss[offset] = ts
+}
+
+// Merge merges the deletes of the same segment into b.
+func (b *DeleteBitmap) Merge(other *DeleteBitmap) error {
+	if other.numRows != b.numRows {
+		return fmt.Errorf("merge delete bitmaps of %d rows and %d rows", b.numRows, other.numRows)
+	}
+	for offset, ts := range other.tss {
+		b.Add(offset, ts)
+	}
+	return nil
+}
+
+// NumRows returns the number of rows of the segment.
+func (b *DeleteBitmap) NumRows() int64 {
+	return b.numRows
+}
+
+// Len returns the number of deleted rows.
+func (b *DeleteBitmap) Len() int {
+	return len(b.tss)
+}
+
+// DeletedAt returns the timestamp when the row at the offset is deleted, false if it's not deleted.
+func (b *DeleteBitmap) DeletedAt(offset int64) (Timestamp, bool) {
+	ts, ok := b.tss[offset]
+	return ts, ok
+}
+
+// Records returns the deleted offsets in ascending order, and the timestamps they are deleted at.
+func (b *DeleteBitmap) Records() ([]int64, []Timestamp) {
+	offsets := lo.Keys(b.tss)
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	tss := lo.Map(offsets, func(offset int64, _ int) Timestamp { return b.tss[offset] })
+	return offsets, tss
+}
+
+// SerializeBitmap serializes the delete bitmap of a sealed segment into a deltalog,
+// whose payload is the words of the bitmap followed by the delete timestamps of the set bits in order.
+func (deleteCodec *DeleteCodec) SerializeBitmap(collectionID UniqueID, partitionID UniqueID, segmentID UniqueID, bitmap *DeleteBitmap) (*Blob, error) {
+	if bitmap.Len() == 0 {
+		return nil, merr.WrapErrParameterInvalidMsg("the delete bitmap of segment %d is empty", segmentID)
+	}
+	offsets, tss := bitmap.Records()
+	if offsets[0] < 0 || offsets[len(offsets)-1] >= bitmap.NumRows() {
+		return nil, fmt.Errorf("deleted offsets out of the %d rows of segment %d", bitmap.NumRows(), segmentID)
+	}
+
+	words := make([]int64, (bitmap.NumRows()+63)/64)
+	for _, offset := range offsets {
+		words[offset/64] |= int64(1) << (offset % 64)
+	}
+	payload := make([]int64, 0, len(words)+len(tss))
+	payload = append(payload, words...)
+	var startTs, endTs Timestamp = math.MaxUint64, 0
+	for _, ts := range tss {
+		startTs = lo.Min([]Timestamp{startTs, ts})
+		endTs = lo.Max([]Timestamp{endTs, ts})
+		payload = append(payload, int64(ts))
+	}
+
+	binlogWriter := NewDeleteBinlogWriter(schemapb.DataType_Int64, collectionID, partitionID, segmentID)
+	defer binlogWriter.Close()
+	eventWriter, err := binlogWriter.NextDeleteEventWriter()
+	if err != nil {
+		return nil, err
+	}
+	defer eventWriter.Close()
+
+	if err = eventWriter.AddInt64ToPayload(payload); err != nil {
+		return nil, err
+	}
+	eventWriter.SetEventTimestamp(startTs, endTs)
+	binlogWriter.SetEventTimeStamp(startTs, endTs)
+	binlogWriter.AddExtra(deltalogBitmapRowsKey, strconv.FormatInt(bitmap.NumRows(), 10))
+	binlogWriter.AddFeature(BinlogFeatureDeltalogBitmap, true)
+	binlogWriter.AddExtra(originalSizeKey, fmt.Sprintf("%v", len(payload)*8))
+
+	if err = binlogWriter.Finish(); err != nil {
+		return nil, err
+	}
+	buffer, err := binlogWriter.GetBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return &Blob{
+		Value:  buffer,
+		RowNum: int64(len(offsets)),
+	}, nil
+}
+
+// DeserializeBitmaps reads the bitmap deltalogs of a sealed segment merged into a delete bitmap,
+// nil if there is none, and returns the other deltalogs left to Deserialize.
+func (deleteCodec *DeleteCodec) DeserializeBitmaps(blobs []*Blob) ([]*Blob, *DeleteBitmap, error) {
+	var (
+		deltalogs []*Blob
+		result    *DeleteBitmap
+	)
+	for _, blob := range blobs {
+		bitmap, err := deserializeBitmap(blob)
+		if err != nil {
+			return nil, nil, err
+		}
+		if bitmap == nil {
+			deltalogs = append(deltalogs, blob)
+			continue
+		}
+		if result == nil {
+			result = bitmap
+			continue
+		}
+		if err := result.Merge(bitmap); err != nil {
+			return nil, nil, err
+		}
+	}
+	return deltalogs, result, nil
+}
+
+// deserializeBitmap deserializes a bitmap deltalog, nil if the deltalog records primary keys.
+func deserializeBitmap(blob *Blob) (*DeleteBitmap, error) {
+	binlogReader, err := NewBinlogReader(blob.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	defer binlogReader.Close()
+	if !binlogReader.HasFeature(BinlogFeatureDeltalogBitmap) {
+		return nil, nil
+	}
+
+	rowsStr, ok := binlogReader.Extras[deltalogBitmapRowsKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%v not in extra of the bitmap deltalog", deltalogBitmapRowsKey)
+	}
+	numRows, err := strconv.ParseInt(rowsStr, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	var payload []int64
+	for {
+		eventReader, err := binlogReader.NextEventReader()
+		if err != nil {
+			return nil, err
+		}
+		if eventReader == nil {
+			break
+		}
+		values, err := eventReader.GetInt64FromPayload()
+		eventReader.Close()
+		if err != nil {
+			return nil, err
+		}
+		payload = append(payload, values...)
+	}
+
+	numWords := (numRows + 63) / 64
+	if int64(len(payload)) < numWords {
+		return nil, fmt.Errorf("bitmap deltalog of %d rows has only %d words", numRows, len(payload))
+	}
+	words, tss := payload[:numWords], payload[numWords:]
+	count := lo.SumBy(words, func(word int64) int { return bits.OnesCount64(uint64(word)) })
+	if count != len(tss) {
+		return nil, fmt.Errorf("bitmap deltalog has %d deleted rows but %d timestamps", count, len(tss))
+	}
+
+	bitmap := NewDeleteBitmap(numRows)
+	idx := 0
+	for i, word := range words {
+		for w := uint64(word); w != 0; w &= w - 1 {
+			offset := int64(i)*64 + int64(bits.TrailingZeros64(w))
+			if offset >= numRows {
+				return nil, fmt.Errorf("deleted offset %d out of %d rows", offset, numRows)
+			}
+			bitmap.Add(offset, Timestamp(tss[idx]))
+			idx++
+		}
+	}
+	return bitmap, nil
+}
@@ -0,0 +1,98 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteBitmap(t *testing.T) {
+	bitmap := NewDeleteBitmap(200)
+	bitmap.Add(130, 20)
+	bitmap.Add(0, 10)
+	bitmap.Add(130, 30)
+	bitmap.Add(199, 5)
+	assert.Equal(t, 3, bitmap.Len())
+
+	// the earliest delete takes effect
+	ts, ok := bitmap.DeletedAt(130)
+	assert.True(t, ok)
+	assert.EqualValues(t, 20, ts)
+	_, ok = bitmap.DeletedAt(1)
+	assert.False(t, ok)
+
+	offsets, tss := bitmap.Records()
+	assert.Equal(t, []int64{0, 130, 199}, offsets)
+	assert.Equal(t, []Timestamp{10, 20, 5}, tss)
+
+	other := NewDeleteBitmap(200)
+	other.Add(130, 15)
+	other.Add(1, 40)
+	assert.NoError(t, bitmap.Merge(other))
+	offsets, tss = bitmap.Records()
+	assert.Equal(t, []int64{0, 1, 130, 199}, offsets)
+	assert.Equal(t, []Timestamp{10, 40, 15, 5}, tss)
+
+	assert.Error(t, bitmap.Merge(NewDeleteBitmap(100)))
+}
+
+func TestDeleteCodecBitmap(t *testing.T) {
+	codec := NewDeleteCodec()
+	bitmap := NewDeleteBitmap(130)
+	bitmap.Add(3, 100)
+	bitmap.Add(64, 101)
+	bitmap.Add(129, 102)
+	other := NewDeleteBitmap(130)
+	other.Add(5, 200)
+
+	blob, err := codec.SerializeBitmap(CollectionID, 1, 1, bitmap)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, blob.RowNum)
+	otherBlob, err := codec.SerializeBitmap(CollectionID, 1, 1, other)
+	assert.NoError(t, err)
+	deltalog, err := codec.Serialize(CollectionID, 1, 1, NewDeleteData([]PrimaryKey{NewInt64PrimaryKey(1)}, []uint64{300}))
+	assert.NoError(t, err)
+
+	deltalogs, merged, err := codec.DeserializeBitmaps([]*Blob{blob, deltalog, otherBlob})
+	assert.NoError(t, err)
+	assert.Equal(t, []*Blob{deltalog}, deltalogs)
+	assert.EqualValues(t, 130, merged.NumRows())
+	offsets, tss := merged.Records()
+	assert.Equal(t, []int64{3, 5, 64, 129}, offsets)
+	assert.Equal(t, []Timestamp{100, 200, 101, 102}, tss)
+
+	deltalogs, merged, err = codec.DeserializeBitmaps([]*Blob{deltalog})
+	assert.NoError(t, err)
+	assert.Len(t, deltalogs, 1)
+	assert.Nil(t, merged)
+
+	// bitmap deltalogs are not readable as primary keys
+	_, _, _, err = codec.Deserialize([]*Blob{blob})
+	assert.Error(t, err)
+
+	_, err = codec.SerializeBitmap(CollectionID, 1, 1, NewDeleteBitmap(10))
+	assert.Error(t, err)
+	outOfRange := NewDeleteBitmap(10)
+	outOfRange.Add(10, 100)
+	_, err = codec.SerializeBitmap(CollectionID, 1, 1, outOfRange)
+	assert.Error(t, err)
+
+	_, _, err = codec.DeserializeBitmaps([]*Blob{blob, {Value: []byte("invalid")}})
+	assert.Error(t, err)
+}
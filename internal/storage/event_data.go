@@ -30,7 +30,36 @@ import (
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
-const originalSizeKey = "original_size"
+const (
+	originalSizeKey = "original_size"
+
+	// binlogVersionKey and binlogFeaturesKey are kept in extras, so that
+	// readers unaware of them still read the descriptor event.
+	binlogVersionKey  = "binlog_version"
+	binlogFeaturesKey = "binlog_features"
+
+	// BinlogFormatVersion is the descriptor format version of binlogs written by this node.
+	// Binlogs written before the version was introduced have version 0.
+	BinlogFormatVersion = 1
+
+	requiredFeature = "required"
+	optionalFeature = "optional"
+)
+
+// Binlog features declared in the descriptor event. A reader fails on a binlog
+// using an unknown required feature, and ignores unknown optional features,
+// whose events it skips.
+const (
+	// BinlogFeatureDeltalogChunk marks a deltalog written as one chunk of the delete data.
+	BinlogFeatureDeltalogChunk = "deltalog_chunk"
+	// BinlogFeatureDeltalogBitmap marks a deltalog recording the deleted row offsets of a sealed segment.
+	BinlogFeatureDeltalogBitmap = "deltalog_bitmap"
+	// BinlogFeatureDeltalogSorted marks a deltalog of the deletes sorted by the primary keys, see SortedDeleteData.
+	BinlogFeatureDeltalogSorted = "deltalog_sorted"
+)
+
+// supportedBinlogFeatures are the binlog features this node is able to read.
+var supportedBinlogFeatures = typeutil.NewSet(BinlogFeatureDeltalogChunk, BinlogFeatureDeltalogBitmap, BinlogFeatureDeltalogSorted)
 
 type descriptorEventData struct {
 	DescriptorEventDataFixPart
@@ -38,6 +67,11 @@ type descriptorEventData struct {
 	ExtraBytes        []byte
 	Extras            map[string]interface{}
 	PostHeaderLengths []uint8
+
+	// version and features are the binlog format version and the features
+	// with whether they are required, kept in extras when written.
+	version  int
+	features map[string]bool
 }
 
 // DescriptorEventDataFixPart is a memory struct saves events' DescriptorEventData.
@@ -72,6 +106,26 @@ func (data *descriptorEventData) AddExtra(k string, v interface{}) {
 	data.Extras[k] = v
 }
 
+// AddFeature declares a feature used by the binlog, readers not supporting
+// a required feature refuse to read the binlog.
+func (data *descriptorEventData) AddFeature(feature string, required bool) {
+	if data.features == nil {
+		data.features = make(map[string]bool)
+	}
+	data.features[feature] = required
+}
+
+// FormatVersion returns the descriptor format version of the binlog.
+func (data *descriptorEventData) FormatVersion() int {
+	return data.version
+}
+
+// HasFeature returns whether the binlog declares the feature.
+func (data *descriptorEventData) HasFeature(feature string) bool {
+	_, ok := data.features[feature]
+	return ok
+}
+
 // FinishExtra marshal extras to json format.
 // Call before GetMemoryUsageInBytes to get an accurate length of description event.
 func (data *descriptorEventData) FinishExtra() error {
@@ -93,6 +147,19 @@ func (data *descriptorEventData) FinishExtra() error {
 		return fmt.Errorf("value of %v must be able to be converted into int format", originalSizeKey)
 	}
 
+	data.version = BinlogFormatVersion
+	data.Extras[binlogVersionKey] = strconv.Itoa(data.version)
+	if len(data.features) > 0 {
+		features := make(map[string]string, len(data.features))
+		for feature, required := range data.features {
+			features[feature] = optionalFeature
+			if required {
+				features[feature] = requiredFeature
+			}
+		}
+		data.Extras[binlogFeaturesKey] = features
+	}
+
 	data.ExtraBytes, err = json.Marshal(data.Extras)
 	if err != nil {
 		return err
@@ -138,10 +205,38 @@ func readDescriptorEventData(buffer io.Reader) (*descriptorEventData, error) {
 	if err := json.Unmarshal(event.ExtraBytes, &event.Extras); err != nil {
 		return nil, err
 	}
+	if err := event.negotiate(); err != nil {
+		return nil, err
+	}
 
 	return event, nil
 }
 
+// negotiate parses the format version and features of a read descriptor event,
+// and checks all required features are supported.
+func (data *descriptorEventData) negotiate() error {
+	if versionStr, ok := data.Extras[binlogVersionKey].(string); ok {
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return fmt.Errorf("invalid binlog format version: %s", versionStr)
+		}
+		data.version = version
+	}
+	features, ok := data.Extras[binlogFeaturesKey].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	data.features = make(map[string]bool, len(features))
+	for feature, requirement := range features {
+		required := requirement == requiredFeature
+		if required && !supportedBinlogFeatures.Contain(feature) {
+			return fmt.Errorf("binlog of format version %d requires unsupported feature %s", data.version, feature)
+		}
+		data.features[feature] = required
+	}
+	return nil
+}
+
 type eventData interface {
 	GetEventDataFixPartSize() int32
 	WriteEventData(buffer io.Writer) error
@@ -25,7 +25,6 @@ import (
 	"github.com/cockroachdb/errors"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
-	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
@@ -134,7 +133,8 @@ func (writer *baseEventWriter) Write(buffer *bytes.Buffer) error {
 	if err != nil {
 		return err
 	}
-	if err := binary.Write(buffer, common.Endian, data); err != nil {
+	// the payload is written as is, binary.Write copies the bytes once more
+	if _, err := buffer.Write(data); err != nil {
 		return err
 	}
 	return nil
@@ -2,9 +2,14 @@ package storage
 
 import (
 	"context"
+	"path"
+	"sync"
+	"time"
 
-	"github.com/cockroachdb/errors"
+	"github.com/minio/minio-go/v7"
 
+	pkgconfig "github.com/milvus-io/milvus/pkg/config"
+	"github.com/milvus-io/milvus/pkg/util/faultinject"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 )
 
@@ -17,11 +22,20 @@ func NewChunkManagerFactoryWithParam(params *paramtable.ComponentParam) *ChunkMa
 	if params.CommonCfg.StorageType.GetValue() == "local" {
 		return NewChunkManagerFactory("local", RootPath(params.LocalStorageCfg.Path.GetValue()))
 	}
-	return NewChunkManagerFactory(params.CommonCfg.StorageType.GetValue(),
+	if params.CommonCfg.StorageType.GetValue() == StorageTypeHDFS {
+		opts := append(HDFSOptions(params),
+			RootPath(params.MinioCfg.RootPath.GetValue()),
+			CreateBucket(true))
+		opts = append(opts, hedgedReadOptions(&params.HDFSCfg.HedgedReadEnabled,
+			&params.HDFSCfg.HedgedReadPercentile, &params.HDFSCfg.HedgedReadMinDelayMs)...)
+		return NewChunkManagerFactory(StorageTypeHDFS, append(opts, TieredOptions(params)...)...)
+	}
+	opts := []Option{
 		RootPath(params.MinioCfg.RootPath.GetValue()),
 		Address(params.MinioCfg.Address.GetValue()),
 		AccessKeyID(params.MinioCfg.AccessKeyID.GetValue()),
 		SecretAccessKeyID(params.MinioCfg.SecretAccessKey.GetValue()),
+		Credentials(watchCredentials(params)),
 		UseSSL(params.MinioCfg.UseSSL.GetAsBool()),
 		BucketName(params.MinioCfg.BucketName.GetValue()),
 		UseIAM(params.MinioCfg.UseIAM.GetAsBool()),
@@ -30,7 +44,111 @@ func NewChunkManagerFactoryWithParam(params *paramtable.ComponentParam) *ChunkMa
 		UseVirtualHost(params.MinioCfg.UseVirtualHost.GetAsBool()),
 		Region(params.MinioCfg.Region.GetValue()),
 		RequestTimeout(params.MinioCfg.RequestTimeoutMs.GetAsInt64()),
-		CreateBucket(true))
+		GcpCredentialJSON(params.MinioCfg.GcpCredentialJSON.GetValue()),
+		UploadPartSize(params.MinioCfg.UploadPartSize.GetAsInt64()),
+		UploadConcurrency(params.MinioCfg.UploadConcurrency.GetAsInt()),
+		UploadThreshold(params.MinioCfg.UploadThreshold.GetAsInt64()),
+		UploadBandwidthLimit(params.MinioCfg.UploadBandwidthLimitMB.GetAsFloat()),
+		ObjectPolicyOptions(params),
+		CreateBucket(true),
+	}
+	opts = append(opts, hedgedReadOptions(&params.MinioCfg.HedgedReadEnabled,
+		&params.MinioCfg.HedgedReadPercentile, &params.MinioCfg.HedgedReadMinDelayMs)...)
+	return NewChunkManagerFactory(params.CommonCfg.StorageType.GetValue(), append(opts, TieredOptions(params)...)...)
+}
+
+// hedgedReadOptions returns the options of the hedged reads of the storage backend if they are enabled.
+func hedgedReadOptions(enabled, percentile, minDelayMs *paramtable.ParamItem) []Option {
+	if !enabled.GetAsBool() {
+		return nil
+	}
+	return []Option{HedgedRead(percentile.GetAsFloat(), time.Duration(minDelayMs.GetAsInt64())*time.Millisecond)}
+}
+
+// watchCredentials returns the credentials rotated once the access keys of the config are updated.
+func watchCredentials(params *paramtable.ComponentParam) *RotatingCredentials {
+	creds := NewRotatingCredentials(params.MinioCfg.AccessKeyID.GetValue(), params.MinioCfg.SecretAccessKey.GetValue())
+	params.Watch(params.MinioCfg.AccessKeyID.Key, pkgconfig.NewHandler("storage.credentials.accessKeyID", rotateCredentials(params, creds)))
+	params.Watch(params.MinioCfg.SecretAccessKey.Key, pkgconfig.NewHandler("storage.credentials.secretAccessKey", rotateCredentials(params, creds)))
+	return creds
+}
+
+// rotateCredentials returns the handler rotating the credentials by the access keys of the config once they are updated,
+// either of them updated alone is taken as well, as the updates of them are not dispatched at once.
+func rotateCredentials(params *paramtable.ComponentParam, creds *RotatingCredentials) func(evt *pkgconfig.Event) {
+	return func(evt *pkgconfig.Event) {
+		if evt.HasUpdated {
+			creds.Rotate(params.MinioCfg.AccessKeyID.GetValue(), params.MinioCfg.SecretAccessKey.GetValue())
+		}
+	}
+}
+
+// ObjectPolicyOptions returns the option of the encryption, the storage class and the tags of the objects written from the minio config.
+func ObjectPolicyOptions(params *paramtable.ComponentParam) Option {
+	return ObjectPolicies(defaultObjectPolicy(&params.MinioCfg), params.MinioCfg.ObjectPolicies.GetValue())
+}
+
+func defaultObjectPolicy(cfg *paramtable.MinioConfig) ObjectPolicy {
+	return ObjectPolicy{
+		SSE:          cfg.SSEType.GetValue(),
+		KMSKeyID:     cfg.SSEKMSKeyID.GetValue(),
+		StorageClass: cfg.StorageClass.GetValue(),
+		Tags:         cfg.ObjectTags.GetAsJSONMap(),
+	}
+}
+
+// ObjectPutOptions returns the put options of the objects by the encryption, the storage class and the tags of the minio config,
+// for the minio clients writing to the bucket out of the ChunkManagers, e.g. the one of the access logs.
+func ObjectPutOptions(cfg *paramtable.MinioConfig) (func(objectName string) minio.PutObjectOptions, error) {
+	policies, err := newObjectPolicies(defaultObjectPolicy(cfg), cfg.ObjectPolicies.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	return func(objectName string) minio.PutObjectOptions {
+		opts := minio.PutObjectOptions{}
+		policies.apply(objectName, &opts)
+		return opts
+	}, nil
+}
+
+// SegcoreIndexFilesPolicy returns the ObjectPolicy of the index files put by the segcore from the minio config,
+// an error if it's invalid or the chunk manager of the segcore can't apply it, which is of the S3 compatible storages only.
+func SegcoreIndexFilesPolicy(params *paramtable.ComponentParam) (ObjectPolicy, error) {
+	policy, err := IndexFilesPolicy(defaultObjectPolicy(&params.MinioCfg), params.MinioCfg.ObjectPolicies.GetValue())
+	if err != nil {
+		return ObjectPolicy{}, err
+	}
+	storageType, cloudProvider := RemoteStorageOf(params.CommonCfg.StorageType.GetValue(), params.MinioCfg.CloudProvider.GetValue())
+	if !policy.IsEmpty() && (storageType == "opendal" || cloudProvider == CloudProviderAzure) {
+		return ObjectPolicy{}, errObjectPoliciesNotSupported("the segcore of " + storageType + " " + cloudProvider)
+	}
+	return policy, nil
+}
+
+// TieredOptions returns the options of the hot tier of the local disk if the tiered storage enabled.
+func TieredOptions(params *paramtable.ComponentParam) []Option {
+	if !params.LocalStorageCfg.TieredEnabled.GetAsBool() {
+		return nil
+	}
+	hotRoot := params.LocalStorageCfg.TieredPath.GetValue()
+	if hotRoot == "" {
+		hotRoot = path.Join(params.LocalStorageCfg.Path.GetValue(), "tiered")
+	}
+	return []Option{TieredStorage(hotRoot, params.LocalStorageCfg.TieredEvictInterval.GetAsDuration(time.Second))}
+}
+
+// HDFSOptions returns the options of the HDFS storage from the hdfs config, the base dir is the bucket.
+func HDFSOptions(params *paramtable.ComponentParam) []Option {
+	return []Option{
+		Address(params.HDFSCfg.Address.GetValue()),
+		UseSSL(params.HDFSCfg.UseSSL.GetAsBool()),
+		BucketName(params.HDFSCfg.BaseDir.GetValue()),
+		RequestTimeout(params.HDFSCfg.RequestTimeoutMs.GetAsInt64()),
+		HdfsUser(params.HDFSCfg.User.GetValue()),
+		HdfsDelegationToken(params.HDFSCfg.DelegationToken.GetValue()),
+		HdfsBlockSize(params.HDFSCfg.BlockSize.GetAsInt64()),
+		HdfsReplication(params.HDFSCfg.Replication.GetAsInt()),
+	}
 }
 
 func NewChunkManagerFactory(persistentStorage string, opts ...Option) *ChunkManagerFactory {
@@ -45,20 +163,50 @@ func NewChunkManagerFactory(persistentStorage string, opts ...Option) *ChunkMana
 }
 
 func (f *ChunkManagerFactory) newChunkManager(ctx context.Context, engine string) (ChunkManager, error) {
-	switch engine {
-	case "local":
-		return NewLocalChunkManager(RootPath(f.config.rootPath)), nil
-	case "minio", "opendal":
-		return newMinioChunkManagerWithConfig(ctx, f.config)
-	case "remote":
-		return NewRemoteChunkManager(ctx, f.config)
-	default:
-		return nil, errors.New("no chunk manager implemented with engine: " + engine)
+	newChunkManager, err := chunkManagerFactoryOf(engine)
+	if err != nil {
+		return nil, err
 	}
+	return newChunkManager(ctx, f.config)
+}
+
+// tieredChunkManagers are the TieredChunkManagers of the hot roots, the hot tier of a root is owned by
+// a single manager in the process, otherwise the evictions of the managers would race on its files.
+var tieredChunkManagers = struct {
+	sync.Mutex
+	managers map[string]*TieredChunkManager
+}{managers: make(map[string]*TieredChunkManager)}
+
+func (f *ChunkManagerFactory) newTieredChunkManager(cold ChunkManager) (ChunkManager, error) {
+	tieredChunkManagers.Lock()
+	defer tieredChunkManagers.Unlock()
+	if cm, ok := tieredChunkManagers.managers[f.config.tieredPath]; ok {
+		return cm, nil
+	}
+	cm, err := NewTieredChunkManager(f.config.tieredPath, cold)
+	if err != nil {
+		return nil, err
+	}
+	cm.Start(f.config.tieredEvictInterval)
+	tieredChunkManagers.managers[f.config.tieredPath] = cm
+	return cm, nil
 }
 
 func (f *ChunkManagerFactory) NewPersistentStorageChunkManager(ctx context.Context) (ChunkManager, error) {
-	return f.newChunkManager(ctx, f.persistentStorage)
+	cm, err := f.newChunkManager(ctx, f.persistentStorage)
+	if err != nil {
+		return nil, err
+	}
+	if f.config.tieredPath != "" && f.persistentStorage != "local" {
+		cm, err = f.newTieredChunkManager(cm)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if faultinject.Enabled {
+		return NewFaultInjectChunkManager(cm), nil
+	}
+	return cm, nil
 }
 
 type Factory interface {
@@ -0,0 +1,95 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus/pkg/util/faultinject"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// FaultInjectChunkManager evaluates the faultinject points before delegating to the wrapped ChunkManager,
+// the ChunkManagerFactory wraps the chunk managers with it if built with the faultinject tag.
+type FaultInjectChunkManager struct {
+	ChunkManager
+}
+
+var _ ChunkManager = (*FaultInjectChunkManager)(nil)
+
+func NewFaultInjectChunkManager(cm ChunkManager) *FaultInjectChunkManager {
+	return &FaultInjectChunkManager{ChunkManager: cm}
+}
+
+func (cm *FaultInjectChunkManager) Read(ctx context.Context, filePath string) ([]byte, error) {
+	if err := faultinject.Inject(ctx, faultinject.ChunkManagerRead); err != nil {
+		return nil, err
+	}
+	return cm.ChunkManager.Read(ctx, filePath)
+}
+
+func (cm *FaultInjectChunkManager) Write(ctx context.Context, filePath string, content []byte) error {
+	if err := faultinject.Inject(ctx, faultinject.ChunkManagerWrite); err != nil {
+		return err
+	}
+	return cm.ChunkManager.Write(ctx, filePath, content)
+}
+
+// MultiRead reads the files not hit by the fault, the contents of the failed ones are nil.
+func (cm *FaultInjectChunkManager) MultiRead(ctx context.Context, filePaths []string) ([][]byte, error) {
+	var el error
+	healthyIdx := make([]int, 0, len(filePaths))
+	healthyPaths := make([]string, 0, len(filePaths))
+	for i, filePath := range filePaths {
+		if err := faultinject.Inject(ctx, faultinject.ChunkManagerMultiRead); err != nil {
+			el = merr.Combine(el, errors.Wrapf(err, "failed to read %s", filePath))
+			continue
+		}
+		healthyIdx = append(healthyIdx, i)
+		healthyPaths = append(healthyPaths, filePath)
+	}
+
+	contents := make([][]byte, len(filePaths))
+	values, err := cm.ChunkManager.MultiRead(ctx, healthyPaths)
+	if err != nil {
+		el = merr.Combine(el, err)
+	}
+	for i, value := range values {
+		contents[healthyIdx[i]] = value
+	}
+	return contents, el
+}
+
+// MultiWrite writes the files not hit by the fault.
+func (cm *FaultInjectChunkManager) MultiWrite(ctx context.Context, contents map[string][]byte) error {
+	var el error
+	healthy := make(map[string][]byte, len(contents))
+	for filePath, content := range contents {
+		if err := faultinject.Inject(ctx, faultinject.ChunkManagerMultiWrite); err != nil {
+			el = merr.Combine(el, errors.Wrapf(err, "failed to write %s", filePath))
+			continue
+		}
+		healthy[filePath] = content
+	}
+
+	if err := cm.ChunkManager.MultiWrite(ctx, healthy); err != nil {
+		el = merr.Combine(el, err)
+	}
+	return el
+}
@@ -0,0 +1,102 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build faultinject
+
+package storage
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/util/faultinject"
+)
+
+type FaultInjectChunkManagerSuite struct {
+	suite.Suite
+
+	rootPath string
+	cm       ChunkManager
+}
+
+func (s *FaultInjectChunkManagerSuite) SetupTest() {
+	s.rootPath = s.T().TempDir()
+	cm, err := NewChunkManagerFactory("local", RootPath(s.rootPath)).NewPersistentStorageChunkManager(context.TODO())
+	s.Require().NoError(err)
+	s.IsType(&FaultInjectChunkManager{}, cm)
+	s.cm = cm
+}
+
+func (s *FaultInjectChunkManagerSuite) TearDownTest() {
+	faultinject.Reset()
+}
+
+func (s *FaultInjectChunkManagerSuite) TestReadWrite() {
+	ctx := context.TODO()
+	mockErr := errors.New("mock error")
+	filePath := path.Join(s.rootPath, "a")
+
+	faultinject.Enable(faultinject.ChunkManagerWrite, faultinject.Fault{Err: mockErr, Times: 1})
+	s.ErrorIs(s.cm.Write(ctx, filePath, []byte{1}), mockErr)
+	s.NoError(s.cm.Write(ctx, filePath, []byte{1}))
+
+	faultinject.Enable(faultinject.ChunkManagerRead, faultinject.Fault{Err: mockErr, Times: 1})
+	_, err := s.cm.Read(ctx, filePath)
+	s.ErrorIs(err, mockErr)
+	content, err := s.cm.Read(ctx, filePath)
+	s.NoError(err)
+	s.Equal([]byte{1}, content)
+}
+
+func (s *FaultInjectChunkManagerSuite) TestPartialFailure() {
+	ctx := context.TODO()
+	mockErr := errors.New("mock error")
+	paths := []string{path.Join(s.rootPath, "a"), path.Join(s.rootPath, "b"), path.Join(s.rootPath, "c")}
+	contents := map[string][]byte{paths[0]: {1}, paths[1]: {2}, paths[2]: {3}}
+
+	// one of the files fails to write
+	faultinject.Enable(faultinject.ChunkManagerMultiWrite, faultinject.Fault{Err: mockErr, Skip: 1, Times: 1})
+	s.ErrorIs(s.cm.MultiWrite(ctx, contents), mockErr)
+	written := 0
+	for _, p := range paths {
+		exist, err := s.cm.Exist(ctx, p)
+		s.Require().NoError(err)
+		if exist {
+			written++
+		}
+	}
+	s.Equal(2, written)
+
+	// retry writes all of them
+	s.NoError(s.cm.MultiWrite(ctx, contents))
+
+	faultinject.Enable(faultinject.ChunkManagerMultiRead, faultinject.Fault{Err: mockErr, Skip: 1, Times: 1})
+	values, err := s.cm.MultiRead(ctx, paths)
+	s.ErrorIs(err, mockErr)
+	s.Equal([][]byte{{1}, nil, {3}}, values)
+
+	values, err = s.cm.MultiRead(ctx, paths)
+	s.NoError(err)
+	s.Equal([][]byte{{1}, {2}, {3}}, values)
+}
+
+func TestFaultInjectChunkManager(t *testing.T) {
+	suite.Run(t, new(FaultInjectChunkManagerSuite))
+}
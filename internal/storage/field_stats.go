@@ -0,0 +1,440 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"strconv"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+// The format versions of the pk stats. The pk stats written before the versions were recorded have version 0,
+// which are read as v1. The readers of v1 ignore the field stats, so the stats of v2 are read by them as is.
+const (
+	// StatsFormatV1 is the version of the pk stats of the primary keys only.
+	StatsFormatV1 = 1
+	// StatsFormatV2 is the version of the pk stats carrying the stats of the scalar fields.
+	StatsFormatV2 = 2
+)
+
+const (
+	minHLLPrecision = 4
+	maxHLLPrecision = 16
+)
+
+// HyperLogLog estimates the number of the distinct values added, by the 2^precision registers of a byte each,
+// the standard error of the estimation is about 1.04/sqrt(2^precision).
+type HyperLogLog struct {
+	precision uint8
+	registers []uint8
+}
+
+// NewHyperLogLog returns the sketch of the precision, which is clamped into [4, 16].
+func NewHyperLogLog(precision int) *HyperLogLog {
+	if precision < minHLLPrecision {
+		precision = minHLLPrecision
+	}
+	if precision > maxHLLPrecision {
+		precision = maxHLLPrecision
+	}
+	return &HyperLogLog{
+		precision: uint8(precision),
+		registers: make([]uint8, 1<<precision),
+	}
+}
+
+// Add adds the value to the sketch.
+func (h *HyperLogLog) Add(value []byte) {
+	hasher := fnv.New64a()
+	hasher.Write(value)
+	h.addHash(mix64(hasher.Sum64()))
+}
+
+// AddString adds the string to the sketch.
+func (h *HyperLogLog) AddString(value string) {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(value))
+	h.addHash(mix64(hasher.Sum64()))
+}
+
+// AddUint64 adds the 64 bits value to the sketch.
+func (h *HyperLogLog) AddUint64(value uint64) {
+	h.addHash(mix64(value))
+}
+
+func (h *HyperLogLog) addHash(hash uint64) {
+	index := hash >> (64 - h.precision)
+	// the sentinel bit bounds the rank by 64 - precision + 1
+	rank := uint8(bits.LeadingZeros64(hash<<h.precision|1<<(h.precision-1)) + 1)
+	if rank > h.registers[index] {
+		h.registers[index] = rank
+	}
+}
+
+// Merge merges the other sketch of the same precision into the sketch.
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if other == nil {
+		return nil
+	}
+	if h.precision != other.precision {
+		return fmt.Errorf("merge hyperloglog of precision %d into %d", other.precision, h.precision)
+	}
+	for i, rank := range other.registers {
+		if rank > h.registers[i] {
+			h.registers[i] = rank
+		}
+	}
+	return nil
+}
+
+// Estimate returns the estimated number of the distinct values added.
+func (h *HyperLogLog) Estimate() uint64 {
+	m := float64(len(h.registers))
+	var sum float64
+	var zeros int
+	for _, rank := range h.registers {
+		sum += math.Ldexp(1, -int(rank))
+		if rank == 0 {
+			zeros++
+		}
+	}
+	estimate := hllAlpha(len(h.registers)) * m * m / sum
+	// linear counting for the small cardinalities
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+type hyperLogLogJSON struct {
+	Precision uint8  `json:"precision"`
+	Registers []byte `json:"registers"`
+}
+
+// MarshalJSON encodes the registers in base64.
+func (h *HyperLogLog) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&hyperLogLogJSON{Precision: h.precision, Registers: h.registers})
+}
+
+// UnmarshalJSON decodes the sketch encoded by MarshalJSON.
+func (h *HyperLogLog) UnmarshalJSON(data []byte) error {
+	decoded := &hyperLogLogJSON{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		return err
+	}
+	if decoded.Precision < minHLLPrecision || decoded.Precision > maxHLLPrecision ||
+		len(decoded.Registers) != 1<<decoded.Precision {
+		return fmt.Errorf("invalid hyperloglog of precision %d and %d registers", decoded.Precision, len(decoded.Registers))
+	}
+	h.precision = decoded.Precision
+	h.registers = decoded.Registers
+	return nil
+}
+
+func hllAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
+
+// mix64 is the finalizer of splitmix64, which spreads the bits of the hash over all the 64 bits.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// FieldStats contains the min/max and the cardinality sketch of a scalar field in a batch of rows,
+// both are merged idempotently, so the stats of a batch read more than once don't skew the merged ones.
+// The min/max are int64 for the integer fields, float64 for the float fields, string for the string fields
+// and bool for the bool fields.
+type FieldStats struct {
+	FieldID int64             `json:"fieldID"`
+	Type    schemapb.DataType `json:"type"`
+	Min     interface{}       `json:"min"`
+	Max     interface{}       `json:"max"`
+	HLL     *HyperLogLog      `json:"hll"`
+}
+
+// IsFieldStatsSupported returns whether the stats of the field are collected, only the scalar fields are.
+func IsFieldStatsSupported(field *schemapb.FieldSchema) bool {
+	if common.IsSystemField(field.GetFieldID()) {
+		return false
+	}
+	switch field.GetDataType() {
+	case schemapb.DataType_Bool, schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32,
+		schemapb.DataType_Int64, schemapb.DataType_Float, schemapb.DataType_Double,
+		schemapb.DataType_String, schemapb.DataType_VarChar:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewFieldStatsByData returns the stats of the scalar fields of the schema in the insert data,
+// the sketches are of the hll precision.
+func NewFieldStatsByData(schema *schemapb.CollectionSchema, data *InsertData, hllPrecision int) ([]*FieldStats, error) {
+	result := make([]*FieldStats, 0)
+	for _, field := range schema.GetFields() {
+		if !IsFieldStatsSupported(field) {
+			continue
+		}
+		fieldData, ok := data.Data[field.GetFieldID()]
+		if !ok || fieldData.RowNum() == 0 {
+			continue
+		}
+		stats := &FieldStats{
+			FieldID: field.GetFieldID(),
+			Type:    field.GetDataType(),
+			HLL:     NewHyperLogLog(hllPrecision),
+		}
+		if err := stats.UpdateByData(fieldData); err != nil {
+			return nil, err
+		}
+		result = append(result, stats)
+	}
+	return result, nil
+}
+
+// UpdateByData updates the stats by the values of the field data.
+func (stats *FieldStats) UpdateByData(data FieldData) error {
+	switch data := data.(type) {
+	case *BoolFieldData:
+		for _, v := range data.Data {
+			stats.updateMinMax(v)
+			if v {
+				stats.HLL.AddUint64(1)
+			} else {
+				stats.HLL.AddUint64(0)
+			}
+		}
+	case *Int8FieldData:
+		for _, v := range data.Data {
+			stats.updateInt(int64(v))
+		}
+	case *Int16FieldData:
+		for _, v := range data.Data {
+			stats.updateInt(int64(v))
+		}
+	case *Int32FieldData:
+		for _, v := range data.Data {
+			stats.updateInt(int64(v))
+		}
+	case *Int64FieldData:
+		for _, v := range data.Data {
+			stats.updateInt(v)
+		}
+	case *FloatFieldData:
+		for _, v := range data.Data {
+			stats.updateFloat(float64(v))
+		}
+	case *DoubleFieldData:
+		for _, v := range data.Data {
+			stats.updateFloat(v)
+		}
+	case *StringFieldData:
+		for _, v := range data.Data {
+			stats.updateMinMax(v)
+			stats.HLL.AddString(v)
+		}
+	default:
+		return fmt.Errorf("field stats of data type %s not supported", data.GetDataType().String())
+	}
+	return nil
+}
+
+func (stats *FieldStats) updateInt(v int64) {
+	stats.updateMinMax(v)
+	stats.HLL.AddUint64(uint64(v))
+}
+
+func (stats *FieldStats) updateFloat(v float64) {
+	stats.updateMinMax(v)
+	stats.HLL.AddUint64(math.Float64bits(v))
+}
+
+func (stats *FieldStats) updateMinMax(v interface{}) {
+	if stats.Min == nil || compareScalar(v, stats.Min) < 0 {
+		stats.Min = v
+	}
+	if stats.Max == nil || compareScalar(v, stats.Max) > 0 {
+		stats.Max = v
+	}
+}
+
+// compareScalar compares the values of the same type, which is one of int64, float64, string and bool.
+func compareScalar(a, b interface{}) int {
+	switch a := a.(type) {
+	case int64:
+		b := b.(int64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	case float64:
+		b := b.(float64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	case string:
+		b := b.(string)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	case bool:
+		b := b.(bool)
+		switch {
+		case !a && b:
+			return -1
+		case a && !b:
+			return 1
+		}
+	}
+	return 0
+}
+
+// Cardinality returns the estimated number of the distinct values of the field.
+func (stats *FieldStats) Cardinality() uint64 {
+	if stats.HLL == nil {
+		return 0
+	}
+	return stats.HLL.Estimate()
+}
+
+// Merge merges the stats of the same field of another batch into the stats.
+func (stats *FieldStats) Merge(other *FieldStats) error {
+	if stats.FieldID != other.FieldID || stats.Type != other.Type {
+		return fmt.Errorf("merge stats of field %d(%s) into field %d(%s)", other.FieldID, other.Type, stats.FieldID, stats.Type)
+	}
+	if stats.HLL == nil {
+		stats.HLL = other.HLL
+	} else if err := stats.HLL.Merge(other.HLL); err != nil {
+		return err
+	}
+	if other.Min != nil {
+		stats.updateMinMax(other.Min)
+	}
+	if other.Max != nil {
+		stats.updateMinMax(other.Max)
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes the min/max by the data type of the field.
+func (stats *FieldStats) UnmarshalJSON(data []byte) error {
+	type fieldStatsJSON FieldStats
+	decoded := struct {
+		*fieldStatsJSON
+		Min json.RawMessage `json:"min"`
+		Max json.RawMessage `json:"max"`
+	}{fieldStatsJSON: (*fieldStatsJSON)(stats)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	var err error
+	if stats.Min, err = decodeScalar(stats.Type, decoded.Min); err != nil {
+		return err
+	}
+	stats.Max, err = decodeScalar(stats.Type, decoded.Max)
+	return err
+}
+
+func decodeScalar(dataType schemapb.DataType, data json.RawMessage) (interface{}, error) {
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	switch dataType {
+	case schemapb.DataType_Bool:
+		var v bool
+		err := json.Unmarshal(data, &v)
+		return v, err
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32, schemapb.DataType_Int64:
+		// decoded from the literal, as the float64 of json loses the precision of the large int64
+		return strconv.ParseInt(string(data), 10, 64)
+	case schemapb.DataType_Float, schemapb.DataType_Double:
+		var v float64
+		err := json.Unmarshal(data, &v)
+		return v, err
+	case schemapb.DataType_String, schemapb.DataType_VarChar:
+		var v string
+		err := json.Unmarshal(data, &v)
+		return v, err
+	default:
+		return nil, fmt.Errorf("field stats of data type %s not supported", dataType.String())
+	}
+}
+
+// MergeFieldStats merges the field stats of the pk stats of the batches by the field ids,
+// the pk stats of v1 carry no field stats and are skipped.
+func MergeFieldStats(stats []*PrimaryKeyStats) (map[int64]*FieldStats, error) {
+	result := make(map[int64]*FieldStats)
+	for _, pkStats := range stats {
+		for _, fieldStats := range pkStats.FieldStats {
+			merged, ok := result[fieldStats.FieldID]
+			if !ok {
+				// merged into a copy, so the stats of the batches are kept as is
+				merged = &FieldStats{FieldID: fieldStats.FieldID, Type: fieldStats.Type}
+				if fieldStats.HLL != nil {
+					merged.HLL = &HyperLogLog{precision: fieldStats.HLL.precision, registers: make([]uint8, len(fieldStats.HLL.registers))}
+				}
+				result[fieldStats.FieldID] = merged
+			}
+			if err := merged.Merge(fieldStats); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// DownloadSegmentFieldStats reads the stats logs in @paths and returns the stats of the scalar fields
+// merged over the batches, for the pruning and balance by the cardinality. The compound stats logs could be read
+// along with the ones of the batches. The stats logs of v1 carry no field stats, so the fields not found are
+// not collected, or written by the old versions.
+func DownloadSegmentFieldStats(ctx context.Context, cm ChunkManager, paths []string) (map[int64]*FieldStats, error) {
+	stats, err := DownloadSegmentStats(ctx, cm, paths)
+	if err != nil {
+		return nil, err
+	}
+	return MergeFieldStats(stats)
+}
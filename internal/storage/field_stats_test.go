@@ -0,0 +1,210 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	assertEstimate := func(expected int, estimate uint64) {
+		assert.InDelta(t, float64(expected), float64(estimate), float64(expected)*0.05+1)
+	}
+
+	h := NewHyperLogLog(12)
+	for i := 0; i < 100000; i++ {
+		h.AddUint64(uint64(i))
+		// the duplicated values are counted once
+		h.AddUint64(uint64(i))
+	}
+	assertEstimate(100000, h.Estimate())
+
+	small := NewHyperLogLog(12)
+	for i := 0; i < 100; i++ {
+		small.AddString(fmt.Sprintf("value-%d", i))
+	}
+	assertEstimate(100, small.Estimate())
+	assert.EqualValues(t, 0, NewHyperLogLog(12).Estimate())
+
+	// merged as the union
+	other := NewHyperLogLog(12)
+	for i := 50000; i < 150000; i++ {
+		other.Add([]byte(fmt.Sprint(i)))
+	}
+	merged := NewHyperLogLog(12)
+	for i := 0; i < 100000; i++ {
+		merged.Add([]byte(fmt.Sprint(i)))
+	}
+	require.NoError(t, merged.Merge(other))
+	assertEstimate(150000, merged.Estimate())
+	assert.Error(t, merged.Merge(NewHyperLogLog(10)))
+
+	data, err := json.Marshal(merged)
+	require.NoError(t, err)
+	decoded := &HyperLogLog{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, merged.Estimate(), decoded.Estimate())
+	assert.Error(t, json.Unmarshal([]byte(`{"precision":12,"registers":"AAAA"}`), decoded))
+
+	assert.Len(t, NewHyperLogLog(1).registers, 1<<minHLLPrecision)
+	assert.Len(t, NewHyperLogLog(20).registers, 1<<maxHLLPrecision)
+}
+
+func genFieldStatsTestSchema() *schemapb.CollectionSchema {
+	return &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: RowIDField, DataType: schemapb.DataType_Int64},
+			{FieldID: TimestampField, DataType: schemapb.DataType_Int64},
+			{FieldID: BoolField, DataType: schemapb.DataType_Bool},
+			{FieldID: Int32Field, DataType: schemapb.DataType_Int32},
+			{FieldID: Int64Field, DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: DoubleField, DataType: schemapb.DataType_Double},
+			{FieldID: StringField, DataType: schemapb.DataType_VarChar},
+			{FieldID: FloatVectorField, DataType: schemapb.DataType_FloatVector},
+		},
+	}
+}
+
+func TestFieldStats(t *testing.T) {
+	paramtable.Init()
+	schema := genFieldStatsTestSchema()
+	genData := func(offset int64) *InsertData {
+		data := &InsertData{Data: map[int64]FieldData{
+			RowIDField:       &Int64FieldData{},
+			TimestampField:   &Int64FieldData{},
+			BoolField:        &BoolFieldData{},
+			Int32Field:       &Int32FieldData{},
+			Int64Field:       &Int64FieldData{},
+			DoubleField:      &DoubleFieldData{},
+			StringField:      &StringFieldData{DataType: schemapb.DataType_VarChar},
+			FloatVectorField: &FloatVectorFieldData{Dim: 1},
+		}}
+		for i := offset; i < offset+1000; i++ {
+			data.Data[RowIDField].(*Int64FieldData).Data = append(data.Data[RowIDField].(*Int64FieldData).Data, i)
+			data.Data[TimestampField].(*Int64FieldData).Data = append(data.Data[TimestampField].(*Int64FieldData).Data, i)
+			data.Data[BoolField].(*BoolFieldData).Data = append(data.Data[BoolField].(*BoolFieldData).Data, i%2 == 0)
+			data.Data[Int32Field].(*Int32FieldData).Data = append(data.Data[Int32Field].(*Int32FieldData).Data, int32(i%10))
+			data.Data[Int64Field].(*Int64FieldData).Data = append(data.Data[Int64Field].(*Int64FieldData).Data, math.MaxInt64-i)
+			data.Data[DoubleField].(*DoubleFieldData).Data = append(data.Data[DoubleField].(*DoubleFieldData).Data, float64(i)/2)
+			data.Data[StringField].(*StringFieldData).Data = append(data.Data[StringField].(*StringFieldData).Data, fmt.Sprintf("str-%05d", i))
+			data.Data[FloatVectorField].(*FloatVectorFieldData).Data = append(data.Data[FloatVectorField].(*FloatVectorFieldData).Data, float32(i))
+		}
+		return data
+	}
+
+	genStats := func(offset int64) *PrimaryKeyStats {
+		data := genData(offset)
+		stats, err := NewPrimaryKeyStats(Int64Field, int64(schemapb.DataType_Int64), 1000)
+		require.NoError(t, err)
+		stats.UpdateByMsgs(data.Data[Int64Field])
+		fieldStats, err := NewFieldStatsByData(schema, data, 12)
+		require.NoError(t, err)
+		// the system and vector fields are skipped
+		assert.Len(t, fieldStats, 5)
+		stats.SetFieldStats(fieldStats)
+		return stats
+	}
+
+	ctx := context.Background()
+	cm := NewLocalChunkManager(RootPath(path.Join(localPath, "field_stats")))
+	defer cm.RemoveWithPrefix(ctx, cm.RootPath())
+
+	codec := NewInsertCodecWithSchema(genDumpTestCollectionMeta())
+	batches := []*PrimaryKeyStats{genStats(0), genStats(1000)}
+	paths := make([]string, 0)
+	for i, stats := range batches {
+		blob, err := codec.SerializePkStats(stats, 1000)
+		require.NoError(t, err)
+		key := metautil.BuildStatsLogPath(cm.RootPath(), CollectionID, PartitionID, SegmentID, Int64Field, int64(i+1))
+		require.NoError(t, cm.Write(ctx, key, blob.GetValue()))
+		paths = append(paths, key)
+	}
+	// the compound statslog carries the field stats of the batches as well
+	blob, err := codec.SerializePkStatsList(batches, 2000)
+	require.NoError(t, err)
+	key := metautil.BuildStatsLogPath(cm.RootPath(), CollectionID, PartitionID, SegmentID, Int64Field, int64(CompoundStatsType))
+	require.NoError(t, cm.Write(ctx, key, blob.GetValue()))
+	paths = append(paths, key)
+
+	pkStats, err := DownloadSegmentStats(ctx, cm, paths[:1])
+	require.NoError(t, err)
+	assert.Equal(t, StatsFormatV2, pkStats[0].Version)
+	pk := make([]byte, 8)
+	common.Endian.PutUint64(pk, uint64(math.MaxInt64))
+	assert.True(t, pkStats[0].BF.Test(pk))
+
+	fieldStats, err := DownloadSegmentFieldStats(ctx, cm, paths)
+	require.NoError(t, err)
+	assert.Len(t, fieldStats, 5)
+
+	assert.Equal(t, false, fieldStats[BoolField].Min)
+	assert.Equal(t, true, fieldStats[BoolField].Max)
+	assert.EqualValues(t, 2, fieldStats[BoolField].Cardinality())
+	assert.Equal(t, int64(0), fieldStats[Int32Field].Min)
+	assert.Equal(t, int64(9), fieldStats[Int32Field].Max)
+	assert.EqualValues(t, 10, fieldStats[Int32Field].Cardinality())
+	// the large int64 keeps the precision through json
+	assert.Equal(t, int64(math.MaxInt64-1999), fieldStats[Int64Field].Min)
+	assert.Equal(t, int64(math.MaxInt64), fieldStats[Int64Field].Max)
+	assert.InDelta(t, 2000, float64(fieldStats[Int64Field].Cardinality()), 100)
+	assert.Equal(t, 0.0, fieldStats[DoubleField].Min)
+	assert.Equal(t, 999.5, fieldStats[DoubleField].Max)
+	assert.Equal(t, "str-00000", fieldStats[StringField].Min)
+	assert.Equal(t, "str-01999", fieldStats[StringField].Max)
+	assert.InDelta(t, 2000, float64(fieldStats[StringField].Cardinality()), 100)
+
+	// the stats of the batches are kept as is
+	assert.Equal(t, "str-00999", batches[0].FieldStats[4].Max)
+
+	t.Run("stats v1", func(t *testing.T) {
+		stats, err := NewPrimaryKeyStats(Int64Field, int64(schemapb.DataType_Int64), 10)
+		require.NoError(t, err)
+		stats.Update(NewInt64PrimaryKey(1))
+		stats.SetFieldStats(nil)
+		data, err := json.Marshal(stats)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "fieldStats")
+		assert.NotContains(t, string(data), "version")
+
+		decoded, err := DeserializeStats([]*Blob{{Value: data}})
+		require.NoError(t, err)
+		assert.Equal(t, 0, decoded[0].Version)
+		merged, err := MergeFieldStats(decoded)
+		require.NoError(t, err)
+		assert.Empty(t, merged)
+	})
+
+	t.Run("merge mismatched", func(t *testing.T) {
+		a := &FieldStats{FieldID: Int32Field, Type: schemapb.DataType_Int32, HLL: NewHyperLogLog(12)}
+		assert.Error(t, a.Merge(&FieldStats{FieldID: Int64Field, Type: schemapb.DataType_Int64}))
+		assert.Error(t, a.Merge(&FieldStats{FieldID: Int32Field, Type: schemapb.DataType_Int32, HLL: NewHyperLogLog(10)}))
+		assert.Error(t, a.UpdateByData(&FloatVectorFieldData{}))
+	})
+}
@@ -0,0 +1,587 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/milvus-io/milvus/internal/storage/gcp"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/retry"
+)
+
+const (
+	gcsScope = "https://www.googleapis.com/auth/devstorage.read_write"
+	// gcsChunkAlignment is the granularity of the chunks of a resumable upload except the last one
+	gcsChunkAlignment = 256 * 1024
+	// gcsDefaultChunkSize is the chunk size of the resumable uploads if the upload part size isn't set
+	gcsDefaultChunkSize = 16 * 1024 * 1024
+)
+
+// gcsError is the error response of the GCS JSON API.
+type gcsError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *gcsError) Error() string {
+	return fmt.Sprintf("gcs request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// GcpNativeObjectStorage accesses the Google Cloud Storage by the JSON API, the objects are written by
+// the resumable uploads chunk by chunk if larger than a chunk, and read by the ranged media downloads.
+type GcpNativeObjectStorage struct {
+	client *http.Client
+	// endpoint is the scheme and host of the API, i.e. https://storage.googleapis.com
+	endpoint  string
+	chunkSize int64
+}
+
+func newGcpNativeObjectStorageWithConfig(ctx context.Context, c *config) (*GcpNativeObjectStorage, error) {
+	if c.bucketName == "" {
+		return nil, merr.WrapErrParameterInvalidMsg("invalid empty bucket name")
+	}
+	endpoint := gcsEndpoint(c)
+	client := &http.Client{Timeout: time.Duration(c.requestTimeoutMs) * time.Millisecond}
+	var projectID string
+	if os.Getenv("STORAGE_EMULATOR_HOST") == "" {
+		creds, err := gcsCredentials(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		projectID = creds.ProjectID
+		client.Transport = &oauth2.Transport{Source: creds.TokenSource, Base: http.DefaultTransport}
+	}
+	storage := newGcpNativeObjectStorage(client, endpoint, c.uploadPartSize)
+
+	// check valid in first query
+	checkBucketFn := func() error {
+		err := storage.checkBucket(ctx, c.bucketName, c.createBucket, projectID)
+		if err != nil {
+			var gcsErr *gcsError
+			if errors.As(err, &gcsErr) && gcsErr.StatusCode < http.StatusInternalServerError && gcsErr.StatusCode != http.StatusTooManyRequests {
+				return retry.Unrecoverable(err)
+			}
+		}
+		return err
+	}
+	if err := retry.Do(ctx, checkBucketFn, retry.Attempts(CheckBucketRetryAttempts)); err != nil {
+		return nil, err
+	}
+	return storage, nil
+}
+
+func newGcpNativeObjectStorage(client *http.Client, endpoint string, chunkSize int64) *GcpNativeObjectStorage {
+	if chunkSize <= 0 {
+		chunkSize = gcsDefaultChunkSize
+	}
+	// the chunks except the last one must be multiples of 256 KiB
+	chunkSize = (chunkSize + gcsChunkAlignment - 1) / gcsChunkAlignment * gcsChunkAlignment
+	return &GcpNativeObjectStorage{
+		client:    client,
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		chunkSize: chunkSize,
+	}
+}
+
+// gcsEndpoint returns the endpoint of the API, the emulator of STORAGE_EMULATOR_HOST if set.
+func gcsEndpoint(c *config) string {
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		if strings.Contains(host, "://") {
+			return host
+		}
+		return "http://" + host
+	}
+	address := c.address
+	if address == "" || strings.Contains(address, gcp.GcsDefaultAddress) {
+		return "https://" + gcp.GcsDefaultAddress
+	}
+	if c.useSSL {
+		return "https://" + address
+	}
+	return "http://" + address
+}
+
+// gcsCredentials returns the credentials of the storage: the workload identity of the metadata server if useIAM,
+// otherwise the service account key of gcpCredentialJSON, or the application default credentials if not set.
+func gcsCredentials(ctx context.Context, c *config) (*google.Credentials, error) {
+	if c.useIAM {
+		return &google.Credentials{TokenSource: google.ComputeTokenSource("", gcsScope)}, nil
+	}
+	if c.gcpCredentialJSON != "" {
+		return google.CredentialsFromJSON(ctx, []byte(c.gcpCredentialJSON), gcsScope)
+	}
+	return google.FindDefaultCredentials(ctx, gcsScope)
+}
+
+func (gcs *GcpNativeObjectStorage) objectURL(bucketName, objectName string) string {
+	return fmt.Sprintf("%s/storage/v1/b/%s/o/%s", gcs.endpoint, url.PathEscape(bucketName), url.PathEscape(objectName))
+}
+
+func (gcs *GcpNativeObjectStorage) uploadURL(bucketName, objectName, uploadType string) string {
+	return fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=%s&name=%s",
+		gcs.endpoint, url.PathEscape(bucketName), uploadType, url.QueryEscape(objectName))
+}
+
+// do sends the request and returns the response of the expected status, the others are returned as gcsError.
+func (gcs *GcpNativeObjectStorage) do(req *http.Request, expected ...int) (*http.Response, error) {
+	resp, err := gcs.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return resp, nil
+		}
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return nil, &gcsError{StatusCode: resp.StatusCode, Message: string(body)}
+}
+
+func (gcs *GcpNativeObjectStorage) checkBucket(ctx context.Context, bucketName string, createBucket bool, projectID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcs.bucketURL(bucketName), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := gcs.do(req, http.StatusOK)
+	if err == nil {
+		resp.Body.Close()
+		return nil
+	}
+	var gcsErr *gcsError
+	if !createBucket || !errors.As(err, &gcsErr) || gcsErr.StatusCode != http.StatusNotFound {
+		return err
+	}
+	if projectID == "" {
+		return merr.WrapErrParameterInvalidMsg("bucket %s not found and can't be created without the project of the credentials", bucketName)
+	}
+	body, err := json.Marshal(map[string]string{"name": bucketName})
+	if err != nil {
+		return err
+	}
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/storage/v1/b?project=%s", gcs.endpoint, url.QueryEscape(projectID)), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = gcs.do(req, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// gcsObject is the metadata of an object of the JSON API.
+type gcsObject struct {
+	Name    string    `json:"name"`
+	Size    string    `json:"size"`
+	Etag    string    `json:"etag"`
+	Updated time.Time `json:"updated"`
+}
+
+func (gcs *GcpNativeObjectStorage) stat(ctx context.Context, bucketName, objectName string) (*gcsObject, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcs.objectURL(bucketName, objectName), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := gcs.do(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	object := &gcsObject{}
+	if err := json.NewDecoder(resp.Body).Decode(object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+func (gcs *GcpNativeObjectStorage) GetObject(ctx context.Context, bucketName, objectName string, offset int64, size int64) (FileReader, error) {
+	return &gcsObjectReader{ctx: ctx, gcs: gcs, bucketName: bucketName, objectName: objectName, position: offset, needResetStream: true}, nil
+}
+
+func (gcs *GcpNativeObjectStorage) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64) error {
+	var err error
+	if objectSize >= 0 && objectSize <= gcs.chunkSize {
+		err = gcs.simpleUpload(ctx, bucketName, objectName, reader, objectSize)
+	} else {
+		err = gcs.resumableUpload(ctx, bucketName, objectName, reader, objectSize)
+	}
+	return checkObjectStorageError(objectName, err)
+}
+
+func (gcs *GcpNativeObjectStorage) simpleUpload(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gcs.uploadURL(bucketName, objectName, "media"), reader)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = objectSize
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := gcs.do(req, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// resumableUpload uploads the object chunk by chunk in a resumable upload session,
+// the bytes of a chunk not persisted by the storage are sent again.
+func (gcs *GcpNativeObjectStorage) resumableUpload(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gcs.uploadURL(bucketName, objectName, "resumable"), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Upload-Content-Type", "application/octet-stream")
+	if objectSize >= 0 {
+		req.Header.Set("X-Upload-Content-Length", strconv.FormatInt(objectSize, 10))
+	}
+	resp, err := gcs.do(req, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return errors.Newf("no resumable upload session of object %s", objectName)
+	}
+
+	buf := make([]byte, gcs.chunkSize)
+	var offset int64
+	for {
+		n, readErr := io.ReadFull(reader, buf)
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			return readErr
+		}
+		last := readErr != nil
+		chunk := buf[:n]
+		for {
+			total := "*"
+			if last {
+				total = strconv.FormatInt(offset+int64(len(chunk)), 10)
+			}
+			persisted, done, err := gcs.uploadChunk(ctx, session, chunk, offset, total)
+			if err != nil {
+				return err
+			}
+			if done {
+				return nil
+			}
+			chunk = chunk[persisted-offset:]
+			offset = persisted
+			if len(chunk) == 0 && !last {
+				break
+			}
+		}
+	}
+}
+
+// uploadChunk puts the chunk at the offset of the upload session, returns the bytes persisted so far,
+// and whether the upload is done.
+func (gcs *GcpNativeObjectStorage) uploadChunk(ctx context.Context, session string, chunk []byte, offset int64, total string) (int64, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, session, bytes.NewReader(chunk))
+	if err != nil {
+		return 0, false, err
+	}
+	req.ContentLength = int64(len(chunk))
+	if len(chunk) == 0 {
+		req.Header.Set("Content-Range", "bytes */"+total)
+	} else {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%s", offset, offset+int64(len(chunk))-1, total))
+	}
+	// 308 Resume Incomplete of the chunks persisted but the upload isn't done
+	resp, err := gcs.do(req, http.StatusOK, http.StatusCreated, http.StatusPermanentRedirect)
+	if err != nil {
+		return 0, false, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusPermanentRedirect {
+		return offset + int64(len(chunk)), true, nil
+	}
+	// Range: bytes=0-{last byte persisted}, absent if nothing persisted
+	var persisted int64
+	if r := resp.Header.Get("Range"); r != "" {
+		end, err := strconv.ParseInt(r[strings.LastIndex(r, "-")+1:], 10, 64)
+		if err != nil {
+			return 0, false, errors.Wrapf(err, "invalid range %s of the resumable upload", r)
+		}
+		persisted = end + 1
+	}
+	if persisted < offset || persisted > offset+int64(len(chunk)) {
+		return 0, false, errors.Newf("resumable upload persisted %d bytes out of the chunk [%d, %d)", persisted, offset, offset+int64(len(chunk)))
+	}
+	return persisted, false, nil
+}
+
+func (gcs *GcpNativeObjectStorage) StatObject(ctx context.Context, bucketName, objectName string) (int64, error) {
+	object, err := gcs.stat(ctx, bucketName, objectName)
+	if err != nil {
+		return 0, checkObjectStorageError(objectName, err)
+	}
+	size, err := strconv.ParseInt(object.Size, 10, 64)
+	if err != nil {
+		return 0, checkObjectStorageError(objectName, err)
+	}
+	return size, nil
+}
+
+func (gcs *GcpNativeObjectStorage) EtagObject(ctx context.Context, bucketName, objectName string) (string, error) {
+	object, err := gcs.stat(ctx, bucketName, objectName)
+	if err != nil {
+		return "", checkObjectStorageError(objectName, err)
+	}
+	return object.Etag, nil
+}
+
+// ListObjects lists the objects of the prefix page by page, the prefixes of the next level are listed
+// as the objects if not recursive.
+func (gcs *GcpNativeObjectStorage) ListObjects(ctx context.Context, bucketName string, prefix string, recursive bool) ([]string, []time.Time, error) {
+	var (
+		objectsKeys []string
+		modTimes    []time.Time
+		pageToken   string
+	)
+	for {
+		query := url.Values{}
+		query.Set("prefix", prefix)
+		query.Set("fields", "items(name,updated),prefixes,nextPageToken")
+		if !recursive {
+			query.Set("delimiter", "/")
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+			fmt.Sprintf("%s/storage/v1/b/%s/o?%s", gcs.endpoint, url.PathEscape(bucketName), query.Encode()), nil)
+		if err != nil {
+			return []string{}, []time.Time{}, err
+		}
+		resp, err := gcs.do(req, http.StatusOK)
+		if err != nil {
+			return []string{}, []time.Time{}, checkObjectStorageError(prefix, err)
+		}
+		page := struct {
+			Items         []gcsObject `json:"items"`
+			Prefixes      []string    `json:"prefixes"`
+			NextPageToken string      `json:"nextPageToken"`
+		}{}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return []string{}, []time.Time{}, checkObjectStorageError(prefix, err)
+		}
+		for _, object := range page.Items {
+			objectsKeys = append(objectsKeys, object.Name)
+			modTimes = append(modTimes, object.Updated)
+		}
+		for _, p := range page.Prefixes {
+			objectsKeys = append(objectsKeys, p)
+			modTimes = append(modTimes, time.Now())
+		}
+		if page.NextPageToken == "" {
+			return objectsKeys, modTimes, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// RemoveObject removes the object, the object not found is removed as the S3 compatible storages do.
+func (gcs *GcpNativeObjectStorage) RemoveObject(ctx context.Context, bucketName, objectName string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, gcs.objectURL(bucketName, objectName), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := gcs.do(req, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return checkObjectStorageError(objectName, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// gcsObjectReader reads the object by the ranged media downloads from the position,
+// the download is issued again once sought. gcsObjectReader is not concurrency safe.
+type gcsObjectReader struct {
+	ctx        context.Context
+	gcs        *GcpNativeObjectStorage
+	bucketName string
+	objectName string
+
+	position        int64
+	body            io.ReadCloser
+	needResetStream bool
+}
+
+func (r *gcsObjectReader) download(off, length int64) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.gcs.objectURL(r.bucketName, r.objectName)+"?alt=media", nil)
+	if err != nil {
+		return nil, err
+	}
+	if length > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+length-1))
+	} else if off > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", off))
+	}
+	resp, err := r.gcs.do(req, http.StatusOK, http.StatusPartialContent)
+	if err != nil {
+		var gcsErr *gcsError
+		if errors.As(err, &gcsErr) && gcsErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (r *gcsObjectReader) Read(p []byte) (int, error) {
+	if r.needResetStream {
+		if r.body != nil {
+			r.body.Close()
+		}
+		body, err := r.download(r.position, 0)
+		if err != nil {
+			return 0, err
+		}
+		r.body = body
+		r.needResetStream = false
+	}
+	n, err := r.body.Read(p)
+	r.position += int64(n)
+	return n, err
+}
+
+func (r *gcsObjectReader) ReadAt(p []byte, off int64) (int, error) {
+	body, err := r.download(off, int64(len(p)))
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	return io.ReadFull(body, p)
+}
+
+func (r *gcsObjectReader) Seek(offset int64, whence int) (int64, error) {
+	var newOffset int64
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = r.position + offset
+	case io.SeekEnd:
+		size, err := r.gcs.StatObject(r.ctx, r.bucketName, r.objectName)
+		if err != nil {
+			return 0, err
+		}
+		newOffset = size + offset
+	default:
+		return 0, merr.WrapErrIoFailedReason("invalid whence")
+	}
+	r.position = newOffset
+	r.needResetStream = true
+	return newOffset, nil
+}
+
+func (r *gcsObjectReader) Close() error {
+	if r.body != nil {
+		return r.body.Close()
+	}
+	return nil
+}
+
+func (gcs *GcpNativeObjectStorage) bucketURL(bucketName string) string {
+	return fmt.Sprintf("%s/storage/v1/b/%s", gcs.endpoint, url.PathEscape(bucketName))
+}
+
+// bucketLifecycle returns the lifecycle of the bucket, the rules are kept raw.
+func (gcs *GcpNativeObjectStorage) bucketLifecycle(ctx context.Context, bucketName string) (*gcsLifecycle, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcs.bucketURL(bucketName)+"?fields=lifecycle", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := gcs.do(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	lifecycle := &gcsLifecycle{}
+	if err := json.NewDecoder(resp.Body).Decode(lifecycle); err != nil {
+		return nil, err
+	}
+	return lifecycle, nil
+}
+
+func (gcs *GcpNativeObjectStorage) GetLifecycleRules(ctx context.Context, bucketName string) ([]LifecycleRule, error) {
+	lifecycle, err := gcs.bucketLifecycle(ctx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	var rules []LifecycleRule
+	for _, gcsRule := range lifecycle.Lifecycle.Rule {
+		if rule, ok := lifecycleRuleOfGCS(gcsRule); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (gcs *GcpNativeObjectStorage) SetLifecycleRules(ctx context.Context, bucketName string, rules []LifecycleRule) error {
+	lifecycle, err := gcs.bucketLifecycle(ctx, bucketName)
+	if err != nil {
+		return err
+	}
+	gcsRules := make([]map[string]any, 0, len(lifecycle.Lifecycle.Rule)+len(rules))
+	for _, gcsRule := range lifecycle.Lifecycle.Rule {
+		if _, ok := lifecycleRuleOfGCS(gcsRule); !ok {
+			gcsRules = append(gcsRules, gcsRule)
+		}
+	}
+	for _, rule := range rules {
+		gcsRules = append(gcsRules, gcsRuleOf(rule))
+	}
+	lifecycle.Lifecycle.Rule = gcsRules
+	body, err := json.Marshal(lifecycle)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, gcs.bucketURL(bucketName), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := gcs.do(req, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,302 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// fakeGcsServer serves the subset of the GCS JSON API used by GcpNativeObjectStorage for a single bucket,
+// the resumable uploads persist at most persistLimit bytes of each chunk if set.
+type fakeGcsServer struct {
+	mu           sync.Mutex
+	bucket       string
+	objects      map[string][]byte
+	sessions     map[string][]byte
+	persistLimit int
+	chunkPuts    int
+	// lifecycle is the lifecycle of the bucket patched
+	lifecycle []byte
+}
+
+func newFakeGcsServer(bucket string) (*fakeGcsServer, *httptest.Server) {
+	fake := &fakeGcsServer{bucket: bucket, objects: map[string][]byte{}, sessions: map[string][]byte{}}
+	return fake, httptest.NewServer(fake)
+}
+
+func (f *fakeGcsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := r.URL.EscapedPath()
+	bucketPath := "/storage/v1/b/" + f.bucket
+	switch {
+	case strings.HasPrefix(path, "/session/"):
+		f.serveChunk(w, r, strings.TrimPrefix(path, "/session/"))
+	case path == "/upload"+bucketPath+"/o":
+		f.serveUpload(w, r)
+	case path == bucketPath:
+		f.serveBucket(w, r)
+	case path == bucketPath+"/o":
+		f.serveList(w, r)
+	case strings.HasPrefix(path, bucketPath+"/o/"):
+		name, _ := url.PathUnescape(strings.TrimPrefix(path, bucketPath+"/o/"))
+		f.serveObject(w, r, name)
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func (f *fakeGcsServer) serveBucket(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPatch {
+		f.lifecycle, _ = io.ReadAll(r.Body)
+	}
+	w.WriteHeader(http.StatusOK)
+	if len(f.lifecycle) > 0 {
+		w.Write(f.lifecycle)
+	} else {
+		w.Write([]byte("{}"))
+	}
+}
+
+func (f *fakeGcsServer) serveUpload(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if r.URL.Query().Get("uploadType") == "resumable" {
+		id := strconv.Itoa(len(f.sessions))
+		f.sessions[id] = []byte{}
+		w.Header().Set("Location", fmt.Sprintf("http://%s/session/%s?name=%s", r.Host, id, url.QueryEscape(name)))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	data, _ := io.ReadAll(r.Body)
+	f.objects[name] = data
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *fakeGcsServer) serveChunk(w http.ResponseWriter, r *http.Request, id string) {
+	f.chunkPuts++
+	data, _ := io.ReadAll(r.Body)
+	var start int
+	var total string
+	if len(data) > 0 {
+		fmt.Sscanf(r.Header.Get("Content-Range"), "bytes %d-", &start)
+	}
+	total = r.Header.Get("Content-Range")[strings.LastIndex(r.Header.Get("Content-Range"), "/")+1:]
+	if start != len(f.sessions[id]) {
+		http.Error(w, "unexpected chunk offset", http.StatusBadRequest)
+		return
+	}
+	if total == "*" && f.persistLimit > 0 && len(data) > f.persistLimit {
+		data = data[:f.persistLimit]
+	}
+	f.sessions[id] = append(f.sessions[id], data...)
+	if total != "*" && strconv.Itoa(len(f.sessions[id])) == total {
+		f.objects[r.URL.Query().Get("name")] = f.sessions[id]
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if len(f.sessions[id]) > 0 {
+		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(f.sessions[id])-1))
+	}
+	w.WriteHeader(http.StatusPermanentRedirect)
+}
+
+func (f *fakeGcsServer) serveObject(w http.ResponseWriter, r *http.Request, name string) {
+	data, ok := f.objects[name]
+	if !ok {
+		http.Error(w, "no such object", http.StatusNotFound)
+		return
+	}
+	switch {
+	case r.Method == http.MethodDelete:
+		delete(f.objects, name)
+		w.WriteHeader(http.StatusNoContent)
+	case r.URL.Query().Get("alt") == "media":
+		start, end := 0, len(data)-1
+		if rng := r.Header.Get("Range"); rng != "" {
+			bounds := strings.SplitN(strings.TrimPrefix(rng, "bytes="), "-", 2)
+			start, _ = strconv.Atoi(bounds[0])
+			if bounds[1] != "" {
+				end, _ = strconv.Atoi(bounds[1])
+			}
+			if start >= len(data) {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			if end >= len(data) {
+				end = len(data) - 1
+			}
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		w.Write(data[start : end+1])
+	default:
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":    name,
+			"size":    strconv.Itoa(len(data)),
+			"etag":    fmt.Sprintf("etag-%d", len(data)),
+			"updated": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+// serveList lists a page of at most two entries, the page token is the index of the next entry.
+func (f *fakeGcsServer) serveList(w http.ResponseWriter, r *http.Request) {
+	prefix, delimiter := r.URL.Query().Get("prefix"), r.URL.Query().Get("delimiter")
+	seen := map[string]bool{}
+	var entries []string
+	for name := range f.objects {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if delimiter != "" {
+			if i := strings.Index(name[len(prefix):], delimiter); i >= 0 {
+				name = name[:len(prefix)+i+1]
+			}
+		}
+		if !seen[name] {
+			seen[name] = true
+			entries = append(entries, name)
+		}
+	}
+	sort.Strings(entries)
+	start, _ := strconv.Atoi(r.URL.Query().Get("pageToken"))
+	end := start + 2
+	page := map[string]interface{}{}
+	if end < len(entries) {
+		page["nextPageToken"] = strconv.Itoa(end)
+	} else {
+		end = len(entries)
+	}
+	var items []map[string]string
+	var prefixes []string
+	for _, entry := range entries[start:end] {
+		if strings.HasSuffix(entry, "/") {
+			prefixes = append(prefixes, entry)
+		} else {
+			items = append(items, map[string]string{"name": entry, "updated": time.Now().Format(time.RFC3339)})
+		}
+	}
+	page["items"] = items
+	page["prefixes"] = prefixes
+	json.NewEncoder(w).Encode(page)
+}
+
+func TestGcpNativeObjectStorage(t *testing.T) {
+	ctx := context.Background()
+	bucketName := "gcs-bucket"
+	fake, server := newFakeGcsServer(bucketName)
+	defer server.Close()
+	gcs := newGcpNativeObjectStorage(server.Client(), server.URL, 1)
+	require.NoError(t, gcs.checkBucket(ctx, bucketName, false, ""))
+	assert.Error(t, gcs.checkBucket(ctx, "no-bucket", false, ""))
+	assert.Error(t, gcs.checkBucket(ctx, "no-bucket", true, ""))
+
+	t.Run("test write and read", func(t *testing.T) {
+		small := []byte("small object")
+		require.NoError(t, gcs.PutObject(ctx, bucketName, "a/b/small", bytes.NewReader(small), int64(len(small))))
+		assert.Equal(t, small, fake.objects["a/b/small"])
+
+		// larger than a chunk, uploaded in a resumable session of 256 KiB chunks
+		large := bytes.Repeat([]byte("0123456789"), gcsChunkAlignment/10*3)
+		require.NoError(t, gcs.PutObject(ctx, bucketName, "a/b/large", bytes.NewReader(large), int64(len(large))))
+		assert.Equal(t, large, fake.objects["a/b/large"])
+
+		size, err := gcs.StatObject(ctx, bucketName, "a/b/large")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(large), size)
+		etag, err := gcs.EtagObject(ctx, bucketName, "a/b/small")
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("etag-%d", len(small)), etag)
+
+		reader, err := gcs.GetObject(ctx, bucketName, "a/b/large", 0, 0)
+		require.NoError(t, err)
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, large, data)
+
+		buf := make([]byte, 5)
+		n, err := reader.ReadAt(buf, 12)
+		require.NoError(t, err)
+		assert.Equal(t, 5, n)
+		assert.Equal(t, []byte("23456"), buf)
+
+		offset, err := reader.Seek(-3, io.SeekEnd)
+		require.NoError(t, err)
+		assert.EqualValues(t, len(large)-3, offset)
+		data, err = io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("789"), data)
+		require.NoError(t, reader.Close())
+
+		_, err = gcs.StatObject(ctx, bucketName, "a/b/none")
+		assert.True(t, errors.Is(err, merr.ErrIoKeyNotFound))
+		reader, err = gcs.GetObject(ctx, bucketName, "a/b/none", 0, 0)
+		require.NoError(t, err)
+		_, err = io.ReadAll(reader)
+		assert.Error(t, err)
+	})
+
+	t.Run("test resumable upload of unknown size and partial chunks", func(t *testing.T) {
+		fake.persistLimit = gcsChunkAlignment / 2
+		defer func() { fake.persistLimit = 0 }()
+		puts := fake.chunkPuts
+		data := bytes.Repeat([]byte("x"), gcsChunkAlignment*2+7)
+		require.NoError(t, gcs.PutObject(ctx, bucketName, "c/partial", bytes.NewReader(data), -1))
+		assert.Equal(t, data, fake.objects["c/partial"])
+		// the unpersisted halves of the chunks are sent again
+		assert.Greater(t, fake.chunkPuts-puts, 3)
+	})
+
+	t.Run("test list and remove", func(t *testing.T) {
+		for _, name := range []string{"list/a", "list/b", "list/c/d", "list/c/e", "list/f/g"} {
+			require.NoError(t, gcs.PutObject(ctx, bucketName, name, bytes.NewReader([]byte(name)), int64(len(name))))
+		}
+		keys, modTimes, err := gcs.ListObjects(ctx, bucketName, "list/", true)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"list/a", "list/b", "list/c/d", "list/c/e", "list/f/g"}, keys)
+		assert.Len(t, modTimes, len(keys))
+
+		keys, _, err = gcs.ListObjects(ctx, bucketName, "list/", false)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"list/a", "list/b", "list/c/", "list/f/"}, keys)
+
+		require.NoError(t, gcs.RemoveObject(ctx, bucketName, "list/a"))
+		require.NoError(t, gcs.RemoveObject(ctx, bucketName, "list/a"))
+		keys, _, err = gcs.ListObjects(ctx, bucketName, "list/", false)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"list/b", "list/c/", "list/f/"}, keys)
+	})
+}
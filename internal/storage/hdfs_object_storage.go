@@ -0,0 +1,482 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/retry"
+)
+
+// hdfsBlockAlignment is the granularity of the block size of the files written,
+// i.e. the minimum block size of the namenode by default
+const hdfsBlockAlignment = 1024 * 1024
+
+// hdfsError is the error response of the WebHDFS, i.e. the RemoteException of the namenode or datanode.
+type hdfsError struct {
+	StatusCode int
+	Exception  string
+	Message    string
+}
+
+func (e *hdfsError) Error() string {
+	return fmt.Sprintf("webhdfs request failed with status %d: %s %s", e.StatusCode, e.Exception, e.Message)
+}
+
+// HdfsObjectStorage accesses the HDFS by the WebHDFS REST API, the bucket is the directory of the objects,
+// and the object is the file of the path relative to the bucket.
+// The requests are sent to the active one of the namenodes and redirected to the datanodes to transfer the data.
+// It authenticates by the simple authentication or by a delegation token fetched outside, the kerberos login,
+// i.e. SPNEGO by a keytab, is not supported, so the kerberized clusters need the token renewed outside as well.
+type HdfsObjectStorage struct {
+	client *http.Client
+	// namenodes are the scheme and host of the WebHDFS of the namenodes, i.e. http://namenode:9870
+	namenodes []string
+	active    atomic.Int32
+
+	user            string
+	delegationToken string
+	blockSize       int64
+	replication     int
+}
+
+func newHdfsObjectStorageWithConfig(ctx context.Context, c *config) (*HdfsObjectStorage, error) {
+	if c.bucketName == "" {
+		return nil, merr.WrapErrParameterInvalidMsg("invalid empty hdfs base dir")
+	}
+	scheme := "http://"
+	if c.useSSL {
+		scheme = "https://"
+	}
+	var namenodes []string
+	for _, address := range strings.Split(c.address, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			namenodes = append(namenodes, scheme+address)
+		}
+	}
+	if len(namenodes) == 0 {
+		return nil, merr.WrapErrParameterInvalidMsg("invalid empty hdfs address")
+	}
+	client := &http.Client{Timeout: time.Duration(c.requestTimeoutMs) * time.Millisecond}
+	storage := newHdfsObjectStorage(client, namenodes, c.hdfsUser, c.hdfsDelegationToken, c.hdfsBlockSize, c.hdfsReplication)
+
+	// check valid in first query
+	checkBaseDirFn := func() error {
+		err := storage.checkBaseDir(ctx, c.bucketName, c.createBucket)
+		if err != nil {
+			var hdfsErr *hdfsError
+			if errors.Is(err, merr.ErrParameterInvalid) || (errors.As(err, &hdfsErr) && hdfsErr.StatusCode < http.StatusInternalServerError) {
+				return retry.Unrecoverable(err)
+			}
+		}
+		return err
+	}
+	if err := retry.Do(ctx, checkBaseDirFn, retry.Attempts(CheckBucketRetryAttempts)); err != nil {
+		return nil, err
+	}
+	return storage, nil
+}
+
+func newHdfsObjectStorage(client *http.Client, namenodes []string, user, delegationToken string, blockSize int64, replication int) *HdfsObjectStorage {
+	// the redirects to the datanodes are followed explicitly to send the data again
+	redirectFree := *client
+	redirectFree.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	if blockSize > 0 {
+		blockSize = (blockSize + hdfsBlockAlignment - 1) / hdfsBlockAlignment * hdfsBlockAlignment
+	}
+	return &HdfsObjectStorage{
+		client:          &redirectFree,
+		namenodes:       namenodes,
+		user:            user,
+		delegationToken: delegationToken,
+		blockSize:       blockSize,
+		replication:     replication,
+	}
+}
+
+func hdfsPath(bucketName, objectName string) string {
+	return path.Join("/", bucketName, objectName)
+}
+
+// namenode sends the request of the operation on the path to the active namenode, the standby namenodes
+// and the unreachable ones are skipped, the responses of the other status than expected are returned as hdfsError.
+func (h *HdfsObjectStorage) namenode(ctx context.Context, method, p, op string, query url.Values, expected ...int) (*http.Response, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("op", op)
+	if h.delegationToken != "" {
+		query.Set("delegation", h.delegationToken)
+	} else if h.user != "" {
+		query.Set("user.name", h.user)
+	}
+	escaped := (&url.URL{Path: p}).EscapedPath()
+
+	var lastErr error
+	active := int(h.active.Load())
+	for i := range h.namenodes {
+		idx := (active + i) % len(h.namenodes)
+		req, err := http.NewRequestWithContext(ctx, method, h.namenodes[idx]+"/webhdfs/v1"+escaped+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := h.do(req, expected...)
+		var hdfsErr *hdfsError
+		if err != nil && (!errors.As(err, &hdfsErr) || hdfsErr.Exception == "StandbyException") {
+			log.Debug("skip the namenode of hdfs", zap.String("namenode", h.namenodes[idx]), zap.Error(err))
+			lastErr = err
+			continue
+		}
+		if idx != active {
+			h.active.Store(int32(idx))
+		}
+		return resp, err
+	}
+	return nil, lastErr
+}
+
+// do sends the request and returns the response of the expected status, the others are returned as hdfsError.
+func (h *HdfsObjectStorage) do(req *http.Request, expected ...int) (*http.Response, error) {
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return resp, nil
+		}
+	}
+	defer resp.Body.Close()
+	remote := struct {
+		RemoteException struct {
+			Exception string `json:"exception"`
+			Message   string `json:"message"`
+		} `json:"RemoteException"`
+	}{}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if json.Unmarshal(body, &remote) != nil {
+		remote.RemoteException.Message = string(body)
+	}
+	return nil, &hdfsError{StatusCode: resp.StatusCode, Exception: remote.RemoteException.Exception, Message: remote.RemoteException.Message}
+}
+
+// redirected returns the location of the datanode the namenode redirects the operation to.
+func (h *HdfsObjectStorage) redirected(ctx context.Context, method, p, op string, query url.Values) (string, error) {
+	resp, err := h.namenode(ctx, method, p, op, query, http.StatusTemporaryRedirect)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return "", errors.Newf("no datanode redirected to for %s of %s", op, p)
+	}
+	return location, nil
+}
+
+func (h *HdfsObjectStorage) checkBaseDir(ctx context.Context, baseDir string, create bool) error {
+	status, err := h.fileStatus(ctx, hdfsPath(baseDir, ""))
+	if err == nil {
+		if status.Type != "DIRECTORY" {
+			return merr.WrapErrParameterInvalidMsg("hdfs base dir %s is not a directory", baseDir)
+		}
+		return nil
+	}
+	var hdfsErr *hdfsError
+	if !create || !errors.As(err, &hdfsErr) || hdfsErr.StatusCode != http.StatusNotFound {
+		return err
+	}
+	resp, err := h.namenode(ctx, http.MethodPut, hdfsPath(baseDir, ""), "MKDIRS", nil, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// hdfsFileStatus is the FileStatus of the WebHDFS.
+type hdfsFileStatus struct {
+	PathSuffix       string `json:"pathSuffix"`
+	Type             string `json:"type"`
+	Length           int64  `json:"length"`
+	ModificationTime int64  `json:"modificationTime"`
+}
+
+func (h *HdfsObjectStorage) fileStatus(ctx context.Context, p string) (*hdfsFileStatus, error) {
+	resp, err := h.namenode(ctx, http.MethodGet, p, "GETFILESTATUS", nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	status := struct {
+		FileStatus hdfsFileStatus `json:"FileStatus"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, err
+	}
+	return &status.FileStatus, nil
+}
+
+// objectStatus returns the status of the file of the object, the directories are not objects.
+func (h *HdfsObjectStorage) objectStatus(ctx context.Context, bucketName, objectName string) (*hdfsFileStatus, error) {
+	status, err := h.fileStatus(ctx, hdfsPath(bucketName, objectName))
+	if err != nil {
+		return nil, err
+	}
+	if status.Type != "FILE" {
+		return nil, &hdfsError{StatusCode: http.StatusNotFound, Exception: "FileNotFoundException", Message: "not a file"}
+	}
+	return status, nil
+}
+
+func (h *HdfsObjectStorage) GetObject(ctx context.Context, bucketName, objectName string, offset int64, size int64) (FileReader, error) {
+	return &hdfsFileReader{ctx: ctx, h: h, path: hdfsPath(bucketName, objectName), position: offset, needResetStream: true}, nil
+}
+
+// PutObject creates the file of the object in the block size and replication of the storage,
+// the file of the same path is overwritten.
+func (h *HdfsObjectStorage) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64) error {
+	query := url.Values{}
+	query.Set("overwrite", "true")
+	if h.blockSize > 0 {
+		query.Set("blocksize", strconv.FormatInt(h.blockSize, 10))
+	}
+	if h.replication > 0 {
+		query.Set("replication", strconv.Itoa(h.replication))
+	}
+	location, err := h.redirected(ctx, http.MethodPut, hdfsPath(bucketName, objectName), "CREATE", query)
+	if err != nil {
+		return checkObjectStorageError(objectName, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, location, reader)
+	if err != nil {
+		return checkObjectStorageError(objectName, err)
+	}
+	req.ContentLength = objectSize
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := h.do(req, http.StatusCreated, http.StatusOK)
+	if err != nil {
+		return checkObjectStorageError(objectName, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (h *HdfsObjectStorage) StatObject(ctx context.Context, bucketName, objectName string) (int64, error) {
+	status, err := h.objectStatus(ctx, bucketName, objectName)
+	if err != nil {
+		return 0, checkObjectStorageError(objectName, err)
+	}
+	return status.Length, nil
+}
+
+// EtagObject returns the length and the modification time of the file as the etag, which changes once overwritten.
+func (h *HdfsObjectStorage) EtagObject(ctx context.Context, bucketName, objectName string) (string, error) {
+	status, err := h.objectStatus(ctx, bucketName, objectName)
+	if err != nil {
+		return "", checkObjectStorageError(objectName, err)
+	}
+	return fmt.Sprintf("%d-%d", status.Length, status.ModificationTime), nil
+}
+
+func (h *HdfsObjectStorage) listStatus(ctx context.Context, p string) ([]hdfsFileStatus, error) {
+	resp, err := h.namenode(ctx, http.MethodGet, p, "LISTSTATUS", nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	statuses := struct {
+		FileStatuses struct {
+			FileStatus []hdfsFileStatus `json:"FileStatus"`
+		} `json:"FileStatuses"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return nil, err
+	}
+	return statuses.FileStatuses.FileStatus, nil
+}
+
+// ListObjects lists the files of the directory of the prefix whose names start with the rest of the prefix,
+// the directories are walked if recursive, or listed as the objects ending with "/" otherwise.
+func (h *HdfsObjectStorage) ListObjects(ctx context.Context, bucketName string, prefix string, recursive bool) ([]string, []time.Time, error) {
+	objectsKeys := make([]string, 0)
+	modTimes := make([]time.Time, 0)
+	dir, namePrefix := "", prefix
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		dir, namePrefix = prefix[:i+1], prefix[i+1:]
+	}
+
+	var walk func(dir, namePrefix string) error
+	walk = func(dir, namePrefix string) error {
+		statuses, err := h.listStatus(ctx, hdfsPath(bucketName, dir))
+		if err != nil {
+			var hdfsErr *hdfsError
+			if errors.As(err, &hdfsErr) && hdfsErr.StatusCode == http.StatusNotFound {
+				return nil
+			}
+			return err
+		}
+		for _, status := range statuses {
+			if !strings.HasPrefix(status.PathSuffix, namePrefix) {
+				continue
+			}
+			key := dir + status.PathSuffix
+			if status.Type == "DIRECTORY" {
+				if recursive {
+					if err := walk(key+"/", ""); err != nil {
+						return err
+					}
+					continue
+				}
+				key += "/"
+			}
+			objectsKeys = append(objectsKeys, key)
+			modTimes = append(modTimes, time.UnixMilli(status.ModificationTime))
+		}
+		return nil
+	}
+	if err := walk(dir, namePrefix); err != nil {
+		return []string{}, []time.Time{}, checkObjectStorageError(prefix, err)
+	}
+	return objectsKeys, modTimes, nil
+}
+
+// RemoveObject deletes the file of the object and then the parent directories left empty,
+// the object not found is removed as the S3 compatible storages do.
+func (h *HdfsObjectStorage) RemoveObject(ctx context.Context, bucketName, objectName string) error {
+	query := url.Values{}
+	query.Set("recursive", "false")
+	resp, err := h.namenode(ctx, http.MethodDelete, hdfsPath(bucketName, objectName), "DELETE", query, http.StatusOK)
+	if err != nil {
+		return checkObjectStorageError(objectName, err)
+	}
+	resp.Body.Close()
+
+	// the non empty directories fail to be deleted without recursive
+	for dir := path.Dir(path.Clean("/" + objectName)); dir != "/"; dir = path.Dir(dir) {
+		resp, err := h.namenode(ctx, http.MethodDelete, hdfsPath(bucketName, dir), "DELETE", query, http.StatusOK)
+		if err != nil {
+			break
+		}
+		resp.Body.Close()
+	}
+	return nil
+}
+
+// hdfsFileReader reads the file by the ranged OPEN of the datanodes from the position,
+// the file is opened again once sought. hdfsFileReader is not concurrency safe.
+type hdfsFileReader struct {
+	ctx  context.Context
+	h    *HdfsObjectStorage
+	path string
+
+	position        int64
+	body            io.ReadCloser
+	needResetStream bool
+}
+
+func (r *hdfsFileReader) open(off, length int64) (io.ReadCloser, error) {
+	query := url.Values{}
+	query.Set("offset", strconv.FormatInt(off, 10))
+	if length > 0 {
+		query.Set("length", strconv.FormatInt(length, 10))
+	}
+	location, err := r.h.redirected(r.ctx, http.MethodGet, r.path, "OPEN", query)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.h.do(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (r *hdfsFileReader) Read(p []byte) (int, error) {
+	if r.needResetStream {
+		if r.body != nil {
+			r.body.Close()
+		}
+		body, err := r.open(r.position, 0)
+		if err != nil {
+			return 0, err
+		}
+		r.body = body
+		r.needResetStream = false
+	}
+	n, err := r.body.Read(p)
+	r.position += int64(n)
+	return n, err
+}
+
+func (r *hdfsFileReader) ReadAt(p []byte, off int64) (int, error) {
+	body, err := r.open(off, int64(len(p)))
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	return io.ReadFull(body, p)
+}
+
+func (r *hdfsFileReader) Seek(offset int64, whence int) (int64, error) {
+	var newOffset int64
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = r.position + offset
+	case io.SeekEnd:
+		status, err := r.h.fileStatus(r.ctx, r.path)
+		if err != nil {
+			return 0, err
+		}
+		newOffset = status.Length + offset
+	default:
+		return 0, merr.WrapErrIoFailedReason("invalid whence")
+	}
+	r.position = newOffset
+	r.needResetStream = true
+	return newOffset, nil
+}
+
+func (r *hdfsFileReader) Close() error {
+	if r.body != nil {
+		return r.body.Close()
+	}
+	return nil
+}
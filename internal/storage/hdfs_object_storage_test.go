@@ -0,0 +1,260 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// fakeWebHdfsServer serves the subset of the WebHDFS used by HdfsObjectStorage, the namenode
+// redirects the data transfers to the datanode of the same server.
+type fakeWebHdfsServer struct {
+	mu     sync.Mutex
+	files  map[string][]byte
+	dirs   map[string]bool
+	params map[string]string
+}
+
+func newFakeWebHdfsServer() (*fakeWebHdfsServer, *httptest.Server) {
+	fake := &fakeWebHdfsServer{files: map[string][]byte{}, dirs: map[string]bool{"/": true}, params: map[string]string{}}
+	return fake, httptest.NewServer(fake)
+}
+
+func (f *fakeWebHdfsServer) remoteException(w http.ResponseWriter, status int, exception string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"RemoteException": map[string]string{"exception": exception, "message": exception}})
+}
+
+func (f *fakeWebHdfsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	query := r.URL.Query()
+	if strings.HasPrefix(r.URL.Path, "/datanode/") {
+		p := strings.TrimPrefix(r.URL.Path, "/datanode")
+		switch query.Get("op") {
+		case "CREATE":
+			data, _ := io.ReadAll(r.Body)
+			f.files[p] = data
+			for dir := path.Dir(p); ; dir = path.Dir(dir) {
+				f.dirs[dir] = true
+				if dir == "/" {
+					break
+				}
+			}
+			w.WriteHeader(http.StatusCreated)
+		case "OPEN":
+			data := f.files[p]
+			offset, _ := strconv.Atoi(query.Get("offset"))
+			end := len(data)
+			if length, err := strconv.Atoi(query.Get("length")); err == nil && offset+length < end {
+				end = offset + length
+			}
+			w.Write(data[offset:end])
+		}
+		return
+	}
+
+	p := strings.TrimPrefix(r.URL.Path, "/webhdfs/v1")
+	switch op := query.Get("op"); op {
+	case "CREATE", "OPEN":
+		for _, key := range []string{"user.name", "delegation", "blocksize", "replication"} {
+			f.params[key] = query.Get(key)
+		}
+		if _, ok := f.files[p]; !ok && op == "OPEN" {
+			f.remoteException(w, http.StatusNotFound, "FileNotFoundException")
+			return
+		}
+		w.Header().Set("Location", fmt.Sprintf("http://%s/datanode%s?%s", r.Host, r.URL.EscapedPath()[len("/webhdfs/v1"):], query.Encode()))
+		w.WriteHeader(http.StatusTemporaryRedirect)
+	case "MKDIRS":
+		f.dirs[p] = true
+		w.Write([]byte(`{"boolean":true}`))
+	case "GETFILESTATUS":
+		status, ok := f.status(p)
+		if !ok {
+			f.remoteException(w, http.StatusNotFound, "FileNotFoundException")
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"FileStatus": status})
+	case "LISTSTATUS":
+		if !f.dirs[p] {
+			f.remoteException(w, http.StatusNotFound, "FileNotFoundException")
+			return
+		}
+		statuses := make([]hdfsFileStatus, 0)
+		for child := range f.children(p) {
+			status, _ := f.status(path.Join(p, child))
+			status.PathSuffix = child
+			statuses = append(statuses, status)
+		}
+		sort.Slice(statuses, func(i, j int) bool { return statuses[i].PathSuffix < statuses[j].PathSuffix })
+		json.NewEncoder(w).Encode(map[string]any{"FileStatuses": map[string]any{"FileStatus": statuses}})
+	case "DELETE":
+		if _, ok := f.files[p]; ok {
+			delete(f.files, p)
+		} else if f.dirs[p] {
+			if len(f.children(p)) > 0 {
+				f.remoteException(w, http.StatusForbidden, "PathIsNotEmptyDirectoryException")
+				return
+			}
+			delete(f.dirs, p)
+		}
+		w.Write([]byte(`{"boolean":true}`))
+	default:
+		f.remoteException(w, http.StatusBadRequest, "IllegalArgumentException")
+	}
+}
+
+func (f *fakeWebHdfsServer) status(p string) (hdfsFileStatus, bool) {
+	if data, ok := f.files[p]; ok {
+		return hdfsFileStatus{Type: "FILE", Length: int64(len(data)), ModificationTime: int64(len(data))}, true
+	}
+	if f.dirs[p] {
+		return hdfsFileStatus{Type: "DIRECTORY"}, true
+	}
+	return hdfsFileStatus{}, false
+}
+
+func (f *fakeWebHdfsServer) children(dir string) map[string]bool {
+	children := map[string]bool{}
+	for p := range f.dirs {
+		if p != dir && path.Dir(p) == dir {
+			children[path.Base(p)] = true
+		}
+	}
+	for p := range f.files {
+		if path.Dir(p) == dir {
+			children[path.Base(p)] = true
+		}
+	}
+	return children
+}
+
+func newTestHdfsObjectStorage(t *testing.T, address string, opts ...Option) *HdfsObjectStorage {
+	c := newDefaultConfig()
+	for _, opt := range append([]Option{Address(address), BucketName("/milvus"), CreateBucket(true)}, opts...) {
+		opt(c)
+	}
+	h, err := newHdfsObjectStorageWithConfig(context.Background(), c)
+	require.NoError(t, err)
+	return h
+}
+
+func TestHdfsObjectStorage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("put get stat list and remove", func(t *testing.T) {
+		fake, server := newFakeWebHdfsServer()
+		defer server.Close()
+		h := newTestHdfsObjectStorage(t, strings.TrimPrefix(server.URL, "http://"), HdfsUser("milvus"), HdfsBlockSize(1000), HdfsReplication(2))
+		assert.True(t, fake.dirs["/milvus"])
+
+		data := []byte("0123456789")
+		require.NoError(t, h.PutObject(ctx, "milvus", "a/b/c", bytes.NewReader(data), int64(len(data))))
+		require.NoError(t, h.PutObject(ctx, "milvus", "a/d", bytes.NewReader(data[:3]), 3))
+		assert.Equal(t, "milvus", fake.params["user.name"])
+		assert.Equal(t, strconv.Itoa(hdfsBlockAlignment), fake.params["blocksize"])
+		assert.Equal(t, "2", fake.params["replication"])
+
+		size, err := h.StatObject(ctx, "milvus", "a/b/c")
+		assert.NoError(t, err)
+		assert.EqualValues(t, 10, size)
+		_, err = h.StatObject(ctx, "milvus", "a/b")
+		assert.ErrorIs(t, err, merr.ErrIoKeyNotFound)
+
+		reader, err := h.GetObject(ctx, "milvus", "a/b/c", 0, -1)
+		require.NoError(t, err)
+		got, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, data, got)
+		buf := make([]byte, 4)
+		n, err := reader.ReadAt(buf, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, "3456", string(buf[:n]))
+		_, err = reader.Seek(-2, io.SeekEnd)
+		assert.NoError(t, err)
+		got, err = io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, "89", string(got))
+		assert.NoError(t, reader.Close())
+
+		keys, _, err := h.ListObjects(ctx, "milvus", "a/", false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a/b/", "a/d"}, keys)
+		keys, _, err = h.ListObjects(ctx, "milvus", "a/", true)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a/b/c", "a/d"}, keys)
+		keys, _, err = h.ListObjects(ctx, "milvus", "x/", true)
+		assert.NoError(t, err)
+		assert.Empty(t, keys)
+
+		require.NoError(t, h.RemoveObject(ctx, "milvus", "a/b/c"))
+		assert.False(t, fake.dirs["/milvus/a/b"])
+		assert.True(t, fake.dirs["/milvus/a"])
+		assert.NoError(t, h.RemoveObject(ctx, "milvus", "a/b/c"))
+	})
+
+	t.Run("delegation token over user", func(t *testing.T) {
+		fake, server := newFakeWebHdfsServer()
+		defer server.Close()
+		h := newTestHdfsObjectStorage(t, strings.TrimPrefix(server.URL, "http://"), HdfsUser("milvus"), HdfsDelegationToken("token"))
+		require.NoError(t, h.PutObject(ctx, "milvus", "a", bytes.NewReader([]byte("a")), 1))
+		assert.Equal(t, "token", fake.params["delegation"])
+		assert.Equal(t, "", fake.params["user.name"])
+	})
+
+	t.Run("skip standby namenode", func(t *testing.T) {
+		standby := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			(&fakeWebHdfsServer{}).remoteException(w, http.StatusForbidden, "StandbyException")
+		}))
+		defer standby.Close()
+		_, server := newFakeWebHdfsServer()
+		defer server.Close()
+		h := newTestHdfsObjectStorage(t, strings.TrimPrefix(standby.URL, "http://")+","+strings.TrimPrefix(server.URL, "http://"))
+		assert.EqualValues(t, 1, h.active.Load())
+		require.NoError(t, h.PutObject(ctx, "milvus", "a", bytes.NewReader([]byte("a")), 1))
+	})
+
+	t.Run("base dir not a directory", func(t *testing.T) {
+		fake, server := newFakeWebHdfsServer()
+		defer server.Close()
+		fake.files["/milvus"] = []byte{}
+		c := newDefaultConfig()
+		Address(strings.TrimPrefix(server.URL, "http://"))(c)
+		BucketName("/milvus")(c)
+		_, err := newHdfsObjectStorageWithConfig(ctx, c)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+}
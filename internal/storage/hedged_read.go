@@ -0,0 +1,165 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus/pkg/metrics"
+)
+
+const (
+	// hedgeLatencyWindow is the number of the latest read latencies of a size class the hedge delay is derived from.
+	hedgeLatencyWindow = 1024
+	// hedgeMinSamples is the number of the read latencies of a size class observed before the reads are hedged.
+	hedgeMinSamples = 100
+	// hedgeRefreshInterval is the number of the read latencies observed between two refreshes of the hedge delay.
+	hedgeRefreshInterval = 64
+)
+
+// hedgeSizeClasses are the upper bounds of the object sizes of the size classes but the last one,
+// the latencies of the larger objects are longer by the transfer, so they are kept apart.
+var hedgeSizeClasses = []int64{64 << 10, 1 << 20, 16 << 20}
+
+func hedgeSizeClassOf(size int64) int {
+	return sort.Search(len(hedgeSizeClasses), func(i int) bool {
+		return size <= hedgeSizeClasses[i]
+	})
+}
+
+// latencyWindow keeps the latest read latencies of a size class and the hedge delay derived from them.
+type latencyWindow struct {
+	latencies []time.Duration
+	next      int
+	observed  int
+	delay     time.Duration
+}
+
+// readHedger issues a duplicate of the read not responded within the percentile of the latest read latencies
+// of the objects of the similar sizes, the first response of the two is taken and the other one is cancelled,
+// which smooths the tail latencies of the object storages.
+type readHedger struct {
+	percentile float64
+	minDelay   time.Duration
+
+	mu sync.Mutex
+	// the windows by the size classes
+	windows []*latencyWindow
+}
+
+func newReadHedger(percentile float64, minDelay time.Duration) *readHedger {
+	windows := make([]*latencyWindow, len(hedgeSizeClasses)+1)
+	for i := range windows {
+		windows[i] = &latencyWindow{latencies: make([]time.Duration, 0, hedgeLatencyWindow)}
+	}
+	return &readHedger{
+		percentile: percentile,
+		minDelay:   minDelay,
+		windows:    windows,
+	}
+}
+
+// observe records the latency of a successful read of the object of the size.
+func (h *readHedger) observe(size int64, latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	w := h.windows[hedgeSizeClassOf(size)]
+	if len(w.latencies) < hedgeLatencyWindow {
+		w.latencies = append(w.latencies, latency)
+	} else {
+		w.latencies[w.next] = latency
+	}
+	w.next = (w.next + 1) % hedgeLatencyWindow
+	w.observed++
+	if w.observed >= hedgeMinSamples && (w.delay == 0 || w.observed%hedgeRefreshInterval == 0) {
+		sorted := append([]time.Duration{}, w.latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		idx := lo.Clamp(int(float64(len(sorted))*h.percentile), 0, len(sorted)-1)
+		w.delay = lo.Max([]time.Duration{sorted[idx], h.minDelay, time.Nanosecond})
+	}
+}
+
+// hedgeDelay returns the delay after which the read of the object of the size is hedged,
+// 0 if not enough reads of the size class are observed yet.
+func (h *readHedger) hedgeDelay(size int64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.windows[hedgeSizeClassOf(size)].delay
+}
+
+type hedgedResult struct {
+	data   []byte
+	err    error
+	hedged bool
+}
+
+// read reads the object of the size by the read function, hedged by a duplicate of it if it's not responded
+// within the hedge delay. An error of either is returned only if the other one fails as well.
+func (h *readHedger) read(ctx context.Context, size int64, read func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	delay := h.hedgeDelay(size)
+	if delay == 0 {
+		return h.timedRead(ctx, size, read)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	// cancels the one not taken
+	defer cancel()
+	results := make(chan hedgedResult, 2)
+	issue := func(hedged bool) {
+		data, err := h.timedRead(ctx, size, read)
+		results <- hedgedResult{data: data, err: err, hedged: hedged}
+	}
+	go issue(false)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	inflight := 1
+	for {
+		select {
+		case <-timer.C:
+			metrics.PersistentDataHedgedReadCounter.WithLabelValues(metrics.HedgeIssuedLabel).Inc()
+			inflight++
+			go issue(true)
+		case result := <-results:
+			inflight--
+			if result.err == nil {
+				if result.hedged {
+					metrics.PersistentDataHedgedReadCounter.WithLabelValues(metrics.HedgeWonLabel).Inc()
+				}
+				return result.data, nil
+			}
+			// the read failed before the hedge delay, or both of them failed
+			if inflight == 0 {
+				return nil, result.err
+			}
+		}
+	}
+}
+
+func (h *readHedger) timedRead(ctx context.Context, size int64, read func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	start := time.Now()
+	data, err := read(ctx)
+	if err == nil {
+		h.observe(size, time.Since(start))
+	}
+	return data, err
+}
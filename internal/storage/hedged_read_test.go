@@ -0,0 +1,152 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+)
+
+func TestReadHedger(t *testing.T) {
+	ctx := context.Background()
+	size := int64(1024)
+
+	newHedger := func() *readHedger {
+		h := newReadHedger(0.99, 10*time.Millisecond)
+		for i := 0; i < hedgeMinSamples; i++ {
+			h.observe(size, time.Millisecond)
+		}
+		return h
+	}
+
+	t.Run("delay", func(t *testing.T) {
+		h := newReadHedger(0.9, time.Millisecond)
+		for i := 1; i < hedgeMinSamples; i++ {
+			h.observe(size, time.Duration(i)*time.Millisecond)
+		}
+		assert.Zero(t, h.hedgeDelay(size))
+		h.observe(size, 100*time.Millisecond)
+		assert.Equal(t, 91*time.Millisecond, h.hedgeDelay(size))
+
+		// not below the min delay
+		assert.Equal(t, 10*time.Millisecond, newHedger().hedgeDelay(size))
+
+		// the latest latencies only
+		for i := 0; i < hedgeLatencyWindow; i++ {
+			h.observe(size, 2*time.Millisecond)
+		}
+		assert.Equal(t, 2*time.Millisecond, h.hedgeDelay(size))
+	})
+
+	t.Run("size classes", func(t *testing.T) {
+		h := newReadHedger(0.9, time.Millisecond)
+		for i := 0; i < hedgeMinSamples; i++ {
+			h.observe(size, 2*time.Millisecond)
+			h.observe(32<<20, 200*time.Millisecond)
+		}
+		assert.Equal(t, 2*time.Millisecond, h.hedgeDelay(64<<10))
+		assert.Equal(t, 200*time.Millisecond, h.hedgeDelay(1<<30))
+		// not observed yet
+		assert.Zero(t, h.hedgeDelay(1<<20))
+	})
+
+	t.Run("not hedged before observed", func(t *testing.T) {
+		h := newReadHedger(0.99, time.Millisecond)
+		calls := atomic.NewInt32(0)
+		data, err := h.read(ctx, size, func(ctx context.Context) ([]byte, error) {
+			calls.Inc()
+			time.Sleep(5 * time.Millisecond)
+			return []byte("a"), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("a"), data)
+		assert.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("hedge wins", func(t *testing.T) {
+		h := newHedger()
+		calls := atomic.NewInt32(0)
+		cancelled := make(chan struct{})
+		data, err := h.read(ctx, size, func(ctx context.Context) ([]byte, error) {
+			if calls.Inc() == 1 {
+				<-ctx.Done()
+				close(cancelled)
+				return nil, ctx.Err()
+			}
+			return []byte("hedge"), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("hedge"), data)
+		assert.EqualValues(t, 2, calls.Load())
+		// the slow one is cancelled
+		<-cancelled
+	})
+
+	t.Run("fast read not hedged", func(t *testing.T) {
+		h := newHedger()
+		calls := atomic.NewInt32(0)
+		data, err := h.read(ctx, size, func(ctx context.Context) ([]byte, error) {
+			calls.Inc()
+			return []byte("a"), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("a"), data)
+		time.Sleep(20 * time.Millisecond)
+		assert.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("failed before hedged", func(t *testing.T) {
+		h := newHedger()
+		calls := atomic.NewInt32(0)
+		_, err := h.read(ctx, size, func(ctx context.Context) ([]byte, error) {
+			calls.Inc()
+			return nil, errors.New("mock")
+		})
+		assert.Error(t, err)
+		time.Sleep(20 * time.Millisecond)
+		assert.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("failed after hedged", func(t *testing.T) {
+		h := newHedger()
+		calls := atomic.NewInt32(0)
+		hedged := make(chan struct{})
+		data, err := h.read(ctx, size, func(ctx context.Context) ([]byte, error) {
+			if calls.Inc() == 1 {
+				<-hedged
+				return nil, errors.New("mock")
+			}
+			close(hedged)
+			time.Sleep(5 * time.Millisecond)
+			return []byte("hedge"), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("hedge"), data)
+
+		// both failed
+		_, err = h.read(ctx, size, func(ctx context.Context) ([]byte, error) {
+			time.Sleep(15 * time.Millisecond)
+			return nil, errors.New("mock")
+		})
+		assert.Error(t, err)
+	})
+}
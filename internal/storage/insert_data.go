@@ -135,6 +135,13 @@ type FieldData interface {
 
 func NewFieldData(dataType schemapb.DataType, fieldSchema *schemapb.FieldSchema) (FieldData, error) {
 	typeParams := fieldSchema.GetTypeParams()
+	nullable, err := common.IsFieldNullable(typeParams...)
+	if err != nil {
+		return nil, err
+	}
+	if nullable && !common.IsNullableSupported(dataType) {
+		return nil, fmt.Errorf("%s field %d couldn't be nullable", dataType, fieldSchema.GetFieldID())
+	}
 	switch dataType {
 	case schemapb.DataType_Float16Vector:
 		dim, err := GetDimFromParams(typeParams)
@@ -175,87 +182,119 @@ func NewFieldData(dataType schemapb.DataType, fieldSchema *schemapb.FieldSchema)
 
 	case schemapb.DataType_Bool:
 		return &BoolFieldData{
-			Data: make([]bool, 0),
+			Data:     make([]bool, 0),
+			Nullable: nullable,
 		}, nil
 
 	case schemapb.DataType_Int8:
 		return &Int8FieldData{
-			Data: make([]int8, 0),
+			Data:     make([]int8, 0),
+			Nullable: nullable,
 		}, nil
 
 	case schemapb.DataType_Int16:
 		return &Int16FieldData{
-			Data: make([]int16, 0),
+			Data:     make([]int16, 0),
+			Nullable: nullable,
 		}, nil
 
 	case schemapb.DataType_Int32:
 		return &Int32FieldData{
-			Data: make([]int32, 0),
+			Data:     make([]int32, 0),
+			Nullable: nullable,
 		}, nil
 
 	case schemapb.DataType_Int64:
 		return &Int64FieldData{
-			Data: make([]int64, 0),
+			Data:     make([]int64, 0),
+			Nullable: nullable,
 		}, nil
 	case schemapb.DataType_Float:
 		return &FloatFieldData{
-			Data: make([]float32, 0),
+			Data:     make([]float32, 0),
+			Nullable: nullable,
 		}, nil
 
 	case schemapb.DataType_Double:
 		return &DoubleFieldData{
-			Data: make([]float64, 0),
+			Data:     make([]float64, 0),
+			Nullable: nullable,
 		}, nil
 	case schemapb.DataType_JSON:
 		return &JSONFieldData{
-			Data: make([][]byte, 0),
+			Data:     make([][]byte, 0),
+			Nullable: nullable,
 		}, nil
 	case schemapb.DataType_Array:
 		return &ArrayFieldData{
 			Data:        make([]*schemapb.ScalarField, 0),
 			ElementType: fieldSchema.GetElementType(),
+			Nullable:    nullable,
 		}, nil
 	case schemapb.DataType_String, schemapb.DataType_VarChar:
 		return &StringFieldData{
 			Data:     make([]string, 0),
 			DataType: dataType,
+			Nullable: nullable,
 		}, nil
 	default:
 		return nil, fmt.Errorf("Unexpected schema data type: %d", dataType)
 	}
 }
 
+// The scalar field data of the nullable fields keep the validity of the rows in ValidData, in which false marks
+// the null rows, whose values in Data are zero values as placeholders. ValidData is nil if all the rows are valid.
 type BoolFieldData struct {
-	Data []bool
+	Data      []bool
+	ValidData []bool
+	Nullable  bool
 }
 type Int8FieldData struct {
-	Data []int8
+	Data      []int8
+	ValidData []bool
+	Nullable  bool
 }
 type Int16FieldData struct {
-	Data []int16
+	Data      []int16
+	ValidData []bool
+	Nullable  bool
 }
 type Int32FieldData struct {
-	Data []int32
+	Data      []int32
+	ValidData []bool
+	Nullable  bool
 }
 type Int64FieldData struct {
-	Data []int64
+	Data      []int64
+	ValidData []bool
+	Nullable  bool
 }
 type FloatFieldData struct {
-	Data []float32
+	Data      []float32
+	ValidData []bool
+	Nullable  bool
 }
 type DoubleFieldData struct {
-	Data []float64
+	Data      []float64
+	ValidData []bool
+	Nullable  bool
 }
 type StringFieldData struct {
-	Data     []string
-	DataType schemapb.DataType
+	Data      []string
+	DataType  schemapb.DataType
+	ValidData []bool
+	Nullable  bool
 }
 type ArrayFieldData struct {
 	ElementType schemapb.DataType
 	Data        []*schemapb.ScalarField
+	ValidData   []bool
+	Nullable    bool
 }
 type JSONFieldData struct {
-	Data [][]byte
+	Data      [][]byte
+	ValidData []bool
+	Nullable  bool
 }
 type BinaryVectorFieldData struct {
 	Data []byte
@@ -293,16 +332,16 @@ func (data *BFloat16VectorFieldData) RowNum() int {
 }
 
 // GetRow implements FieldData.GetRow
-func (data *BoolFieldData) GetRow(i int) any   { return data.Data[i] }
-func (data *Int8FieldData) GetRow(i int) any   { return data.Data[i] }
-func (data *Int16FieldData) GetRow(i int) any  { return data.Data[i] }
-func (data *Int32FieldData) GetRow(i int) any  { return data.Data[i] }
-func (data *Int64FieldData) GetRow(i int) any  { return data.Data[i] }
-func (data *FloatFieldData) GetRow(i int) any  { return data.Data[i] }
-func (data *DoubleFieldData) GetRow(i int) any { return data.Data[i] }
-func (data *StringFieldData) GetRow(i int) any { return data.Data[i] }
-func (data *ArrayFieldData) GetRow(i int) any  { return data.Data[i] }
-func (data *JSONFieldData) GetRow(i int) any   { return data.Data[i] }
+func (data *BoolFieldData) GetRow(i int) any   { return getNullableRow(data.Data, data.ValidData, i) }
+func (data *Int8FieldData) GetRow(i int) any   { return getNullableRow(data.Data, data.ValidData, i) }
+func (data *Int16FieldData) GetRow(i int) any  { return getNullableRow(data.Data, data.ValidData, i) }
+func (data *Int32FieldData) GetRow(i int) any  { return getNullableRow(data.Data, data.ValidData, i) }
+func (data *Int64FieldData) GetRow(i int) any  { return getNullableRow(data.Data, data.ValidData, i) }
+func (data *FloatFieldData) GetRow(i int) any  { return getNullableRow(data.Data, data.ValidData, i) }
+func (data *DoubleFieldData) GetRow(i int) any { return getNullableRow(data.Data, data.ValidData, i) }
+func (data *StringFieldData) GetRow(i int) any { return getNullableRow(data.Data, data.ValidData, i) }
+func (data *ArrayFieldData) GetRow(i int) any  { return getNullableRow(data.Data, data.ValidData, i) }
+func (data *JSONFieldData) GetRow(i int) any   { return getNullableRow(data.Data, data.ValidData, i) }
 func (data *BinaryVectorFieldData) GetRow(i int) interface{} {
 	return data.Data[i*data.Dim/8 : (i+1)*data.Dim/8]
 }
@@ -336,93 +375,63 @@ func (data *BFloat16VectorFieldData) GetRows() any { return data.Data }
 
 // AppendRow implements FieldData.AppendRow
 func (data *BoolFieldData) AppendRow(row interface{}) error {
-	v, ok := row.(bool)
-	if !ok {
-		return merr.WrapErrParameterInvalid("bool", row, "Wrong row type")
-	}
-	data.Data = append(data.Data, v)
-	return nil
+	var err error
+	data.Data, data.ValidData, err = appendNullableRow(data.Data, data.ValidData, data.Nullable, row, "bool")
+	return err
 }
 
 func (data *Int8FieldData) AppendRow(row interface{}) error {
-	v, ok := row.(int8)
-	if !ok {
-		return merr.WrapErrParameterInvalid("int8", row, "Wrong row type")
-	}
-	data.Data = append(data.Data, v)
-	return nil
+	var err error
+	data.Data, data.ValidData, err = appendNullableRow(data.Data, data.ValidData, data.Nullable, row, "int8")
+	return err
 }
 
 func (data *Int16FieldData) AppendRow(row interface{}) error {
-	v, ok := row.(int16)
-	if !ok {
-		return merr.WrapErrParameterInvalid("int16", row, "Wrong row type")
-	}
-	data.Data = append(data.Data, v)
-	return nil
+	var err error
+	data.Data, data.ValidData, err = appendNullableRow(data.Data, data.ValidData, data.Nullable, row, "int16")
+	return err
 }
 
 func (data *Int32FieldData) AppendRow(row interface{}) error {
-	v, ok := row.(int32)
-	if !ok {
-		return merr.WrapErrParameterInvalid("int32", row, "Wrong row type")
-	}
-	data.Data = append(data.Data, v)
-	return nil
+	var err error
+	data.Data, data.ValidData, err = appendNullableRow(data.Data, data.ValidData, data.Nullable, row, "int32")
+	return err
 }
 
 func (data *Int64FieldData) AppendRow(row interface{}) error {
-	v, ok := row.(int64)
-	if !ok {
-		return merr.WrapErrParameterInvalid("int64", row, "Wrong row type")
-	}
-	data.Data = append(data.Data, v)
-	return nil
+	var err error
+	data.Data, data.ValidData, err = appendNullableRow(data.Data, data.ValidData, data.Nullable, row, "int64")
+	return err
 }
 
 func (data *FloatFieldData) AppendRow(row interface{}) error {
-	v, ok := row.(float32)
-	if !ok {
-		return merr.WrapErrParameterInvalid("float32", row, "Wrong row type")
-	}
-	data.Data = append(data.Data, v)
-	return nil
+	var err error
+	data.Data, data.ValidData, err = appendNullableRow(data.Data, data.ValidData, data.Nullable, row, "float32")
+	return err
 }
 
 func (data *DoubleFieldData) AppendRow(row interface{}) error {
-	v, ok := row.(float64)
-	if !ok {
-		return merr.WrapErrParameterInvalid("float64", row, "Wrong row type")
-	}
-	data.Data = append(data.Data, v)
-	return nil
+	var err error
+	data.Data, data.ValidData, err = appendNullableRow(data.Data, data.ValidData, data.Nullable, row, "float64")
+	return err
 }
 
 func (data *StringFieldData) AppendRow(row interface{}) error {
-	v, ok := row.(string)
-	if !ok {
-		return merr.WrapErrParameterInvalid("string", row, "Wrong row type")
-	}
-	data.Data = append(data.Data, v)
-	return nil
+	var err error
+	data.Data, data.ValidData, err = appendNullableRow(data.Data, data.ValidData, data.Nullable, row, "string")
+	return err
 }
 
 func (data *ArrayFieldData) AppendRow(row interface{}) error {
-	v, ok := row.(*schemapb.ScalarField)
-	if !ok {
-		return merr.WrapErrParameterInvalid("*schemapb.ScalarField", row, "Wrong row type")
-	}
-	data.Data = append(data.Data, v)
-	return nil
+	var err error
+	data.Data, data.ValidData, err = appendNullableRow(data.Data, data.ValidData, data.Nullable, row, "*schemapb.ScalarField")
+	return err
 }
 
 func (data *JSONFieldData) AppendRow(row interface{}) error {
-	v, ok := row.([]byte)
-	if !ok {
-		return merr.WrapErrParameterInvalid("[]byte", row, "Wrong row type")
-	}
-	data.Data = append(data.Data, v)
-	return nil
+	var err error
+	data.Data, data.ValidData, err = appendNullableRow(data.Data, data.ValidData, data.Nullable, row, "[]byte")
+	return err
 }
 
 func (data *BinaryVectorFieldData) AppendRow(row interface{}) error {
@@ -467,6 +476,7 @@ func (data *BoolFieldData) AppendRows(rows interface{}) error {
 		return merr.WrapErrParameterInvalid("[]bool", rows, "Wrong rows type")
 	}
 	data.Data = append(data.Data, v...)
+	data.ValidData = appendValidRows(data.ValidData, data.Nullable, len(v))
 	return nil
 }
 
@@ -476,6 +486,7 @@ func (data *Int8FieldData) AppendRows(rows interface{}) error {
 		return merr.WrapErrParameterInvalid("[]int8", rows, "Wrong rows type")
 	}
 	data.Data = append(data.Data, v...)
+	data.ValidData = appendValidRows(data.ValidData, data.Nullable, len(v))
 	return nil
 }
 
@@ -485,6 +496,7 @@ func (data *Int16FieldData) AppendRows(rows interface{}) error {
 		return merr.WrapErrParameterInvalid("[]int16", rows, "Wrong rows type")
 	}
 	data.Data = append(data.Data, v...)
+	data.ValidData = appendValidRows(data.ValidData, data.Nullable, len(v))
 	return nil
 }
 
@@ -494,6 +506,7 @@ func (data *Int32FieldData) AppendRows(rows interface{}) error {
 		return merr.WrapErrParameterInvalid("[]int32", rows, "Wrong rows type")
 	}
 	data.Data = append(data.Data, v...)
+	data.ValidData = appendValidRows(data.ValidData, data.Nullable, len(v))
 	return nil
 }
 
@@ -503,6 +516,7 @@ func (data *Int64FieldData) AppendRows(rows interface{}) error {
 		return merr.WrapErrParameterInvalid("[]int64", rows, "Wrong rows type")
 	}
 	data.Data = append(data.Data, v...)
+	data.ValidData = appendValidRows(data.ValidData, data.Nullable, len(v))
 	return nil
 }
 
@@ -512,6 +526,7 @@ func (data *FloatFieldData) AppendRows(rows interface{}) error {
 		return merr.WrapErrParameterInvalid("[]float32", rows, "Wrong rows type")
 	}
 	data.Data = append(data.Data, v...)
+	data.ValidData = appendValidRows(data.ValidData, data.Nullable, len(v))
 	return nil
 }
 
@@ -521,6 +536,7 @@ func (data *DoubleFieldData) AppendRows(rows interface{}) error {
 		return merr.WrapErrParameterInvalid("[]float64", rows, "Wrong rows type")
 	}
 	data.Data = append(data.Data, v...)
+	data.ValidData = appendValidRows(data.ValidData, data.Nullable, len(v))
 	return nil
 }
 
@@ -530,6 +546,7 @@ func (data *StringFieldData) AppendRows(rows interface{}) error {
 		return merr.WrapErrParameterInvalid("[]string", rows, "Wrong rows type")
 	}
 	data.Data = append(data.Data, v...)
+	data.ValidData = appendValidRows(data.ValidData, data.Nullable, len(v))
 	return nil
 }
 
@@ -539,6 +556,7 @@ func (data *ArrayFieldData) AppendRows(rows interface{}) error {
 		return merr.WrapErrParameterInvalid("[]*schemapb.ScalarField", rows, "Wrong rows type")
 	}
 	data.Data = append(data.Data, v...)
+	data.ValidData = appendValidRows(data.ValidData, data.Nullable, len(v))
 	return nil
 }
 
@@ -548,6 +566,7 @@ func (data *JSONFieldData) AppendRows(rows interface{}) error {
 		return merr.WrapErrParameterInvalid("[][]byte", rows, "Wrong rows type")
 	}
 	data.Data = append(data.Data, v...)
+	data.ValidData = appendValidRows(data.ValidData, data.Nullable, len(v))
 	return nil
 }
 
@@ -604,13 +623,27 @@ func (data *BFloat16VectorFieldData) AppendRows(rows interface{}) error {
 }
 
 // GetMemorySize implements FieldData.GetMemorySize
-func (data *BoolFieldData) GetMemorySize() int          { return binary.Size(data.Data) }
-func (data *Int8FieldData) GetMemorySize() int          { return binary.Size(data.Data) }
-func (data *Int16FieldData) GetMemorySize() int         { return binary.Size(data.Data) }
-func (data *Int32FieldData) GetMemorySize() int         { return binary.Size(data.Data) }
-func (data *Int64FieldData) GetMemorySize() int         { return binary.Size(data.Data) }
-func (data *FloatFieldData) GetMemorySize() int         { return binary.Size(data.Data) }
-func (data *DoubleFieldData) GetMemorySize() int        { return binary.Size(data.Data) }
+func (data *BoolFieldData) GetMemorySize() int {
+	return binary.Size(data.Data) + binary.Size(data.ValidData)
+}
+func (data *Int8FieldData) GetMemorySize() int {
+	return binary.Size(data.Data) + binary.Size(data.ValidData)
+}
+func (data *Int16FieldData) GetMemorySize() int {
+	return binary.Size(data.Data) + binary.Size(data.ValidData)
+}
+func (data *Int32FieldData) GetMemorySize() int {
+	return binary.Size(data.Data) + binary.Size(data.ValidData)
+}
+func (data *Int64FieldData) GetMemorySize() int {
+	return binary.Size(data.Data) + binary.Size(data.ValidData)
+}
+func (data *FloatFieldData) GetMemorySize() int {
+	return binary.Size(data.Data) + binary.Size(data.ValidData)
+}
+func (data *DoubleFieldData) GetMemorySize() int {
+	return binary.Size(data.Data) + binary.Size(data.ValidData)
+}
 func (data *BinaryVectorFieldData) GetMemorySize() int  { return binary.Size(data.Data) + 4 }
 func (data *FloatVectorFieldData) GetMemorySize() int   { return binary.Size(data.Data) + 4 }
 func (data *Float16VectorFieldData) GetMemorySize() int { return binary.Size(data.Data) + 4 }
@@ -654,7 +687,7 @@ func (data *StringFieldData) GetMemorySize() int {
 	for _, val := range data.Data {
 		size += len(val) + 16
 	}
-	return size
+	return size + binary.Size(data.ValidData)
 }
 
 func (data *ArrayFieldData) GetMemorySize() int {
@@ -679,7 +712,7 @@ func (data *ArrayFieldData) GetMemorySize() int {
 			size += (&StringFieldData{Data: val.GetStringData().GetData()}).GetMemorySize()
 		}
 	}
-	return size
+	return size + binary.Size(data.ValidData)
 }
 
 func (data *JSONFieldData) GetMemorySize() int {
@@ -687,5 +720,43 @@ func (data *JSONFieldData) GetMemorySize() int {
 	for _, val := range data.Data {
 		size += len(val) + 16
 	}
-	return size
+	return size + binary.Size(data.ValidData)
+}
+
+// isNullRow returns whether the i-th row is null by the validity of the rows.
+func isNullRow(validData []bool, i int) bool {
+	return len(validData) > i && !validData[i]
+}
+
+// getNullableRow returns the i-th value, or nil if the row is null.
+func getNullableRow[T any](data []T, validData []bool, i int) any {
+	if isNullRow(validData, i) {
+		return nil
+	}
+	return data[i]
+}
+
+// appendNullableRow appends the row to the values and the validity of the field, a nil row is appended as
+// the zero value marked null if the field is nullable.
+func appendNullableRow[T any](data []T, validData []bool, nullable bool, row any, typeName string) ([]T, []bool, error) {
+	if row == nil && nullable {
+		var zero T
+		return append(data, zero), append(validData, false), nil
+	}
+	v, ok := row.(T)
+	if !ok {
+		return data, validData, merr.WrapErrParameterInvalid[any](typeName, row, "Wrong row type")
+	}
+	return append(data, v), appendValidRows(validData, nullable, 1), nil
+}
+
+// appendValidRows appends n valid rows to the validity of the field if it's nullable.
+func appendValidRows(validData []bool, nullable bool, n int) []bool {
+	if !nullable {
+		return validData
+	}
+	for i := 0; i < n; i++ {
+		validData = append(validData, true)
+	}
+	return validData
 }
@@ -6,7 +6,9 @@ import (
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
 
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 )
@@ -151,6 +153,30 @@ func (s *InsertDataSuite) TestMemorySize() {
 	s.Equal(s.iDataTwoRows.Data[BFloat16VectorField].GetMemorySize(), 20)
 }
 
+func (s *InsertDataSuite) TestNullable() {
+	nullable := []*commonpb.KeyValuePair{{Key: common.FieldNullableKey, Value: "true"}}
+	fieldData, err := NewFieldData(schemapb.DataType_Int32, &schemapb.FieldSchema{DataType: schemapb.DataType_Int32, TypeParams: nullable})
+	s.Require().NoError(err)
+	s.NoError(fieldData.AppendRow(int32(1)))
+	s.NoError(fieldData.AppendRow(nil))
+	s.NoError(fieldData.AppendRows([]int32{3, 4}))
+	s.ErrorIs(fieldData.AppendRow("5"), merr.ErrParameterInvalid)
+	s.Equal(4, fieldData.RowNum())
+	s.Equal(int32(1), fieldData.GetRow(0))
+	s.Nil(fieldData.GetRow(1))
+	s.Equal([]int32{1, 0, 3, 4}, fieldData.GetRows())
+	s.Equal([]bool{true, false, true, true}, fieldData.(*Int32FieldData).ValidData)
+	s.Equal(4*4+4, fieldData.GetMemorySize())
+
+	_, err = NewFieldData(schemapb.DataType_FloatVector, &schemapb.FieldSchema{DataType: schemapb.DataType_FloatVector, TypeParams: nullable})
+	s.Error(err)
+	_, err = NewFieldData(schemapb.DataType_Int32, &schemapb.FieldSchema{
+		DataType:   schemapb.DataType_Int32,
+		TypeParams: []*commonpb.KeyValuePair{{Key: common.FieldNullableKey, Value: "maybe"}},
+	})
+	s.Error(err)
+}
+
 func (s *InsertDataSuite) TestGetDataType() {
 	for _, field := range s.schema.GetFields() {
 		fieldData, ok := s.iDataOneRow.Data[field.GetFieldID()]
@@ -0,0 +1,520 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/bitutil"
+	"github.com/apache/arrow/go/v12/arrow/compute"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// fieldIDMetaKey is the key of the arrow field metadata which stores the milvus field id,
+// it's the same as the key parquet uses, so the field id is kept in the parquet binlog as well.
+const fieldIDMetaKey = "PARQUET:field_id"
+
+// InsertRecord is the columnar representation of InsertData backed by an arrow record, one column per field.
+// The columns of fixed width types share the memory with the InsertData converted from,
+// so the record could be handed off to the serializers and processed vectorized without copying.
+type InsertRecord struct {
+	schema    *schemapb.CollectionSchema
+	record    arrow.Record
+	field2Col map[FieldID]int
+}
+
+// InsertDataToRecord converts the InsertData to InsertRecord, all the fields in the schema must be present.
+// The caller should release the record after used.
+func InsertDataToRecord(schema *schemapb.CollectionSchema, data *InsertData) (*InsertRecord, error) {
+	if schema == nil {
+		return nil, errors.New("schema is not set")
+	}
+	if data == nil {
+		return nil, errors.New("insert data is nil")
+	}
+
+	fields := make([]arrow.Field, 0, len(schema.GetFields()))
+	columns := make([]arrow.Array, 0, len(schema.GetFields()))
+	defer func() {
+		for _, column := range columns {
+			column.Release()
+		}
+	}()
+
+	field2Col := make(map[FieldID]int, len(schema.GetFields()))
+	rowNum := -1
+	for _, field := range schema.GetFields() {
+		fieldData, ok := data.Data[field.GetFieldID()]
+		if !ok {
+			return nil, fmt.Errorf("data of field %d not found", field.GetFieldID())
+		}
+		if rowNum >= 0 && fieldData.RowNum() != rowNum {
+			return nil, fmt.Errorf("row num of field %d mismatch, expected %d, actual %d", field.GetFieldID(), rowNum, fieldData.RowNum())
+		}
+		rowNum = fieldData.RowNum()
+
+		column, err := fieldDataToArrowArray(field, fieldData)
+		if err != nil {
+			return nil, err
+		}
+		field2Col[field.GetFieldID()] = len(columns)
+		columns = append(columns, column)
+		fields = append(fields, arrow.Field{
+			Name:     field.GetName(),
+			Type:     column.DataType(),
+			Nullable: isNullableField(field),
+			Metadata: arrow.NewMetadata([]string{fieldIDMetaKey}, []string{strconv.FormatInt(field.GetFieldID(), 10)}),
+		})
+	}
+	if rowNum < 0 {
+		rowNum = 0
+	}
+
+	return &InsertRecord{
+		schema:    schema,
+		record:    array.NewRecord(arrow.NewSchema(fields, nil), columns, int64(rowNum)),
+		field2Col: field2Col,
+	}, nil
+}
+
+// NewSortedInsertRecord converts the InsertData to InsertRecord sorted by row id.
+// Unlike DataSorter, the InsertData is left unchanged, and nothing is copied if the rows are sorted already,
+// which is the common case of the write path.
+func NewSortedInsertRecord(ctx context.Context, schema *schemapb.CollectionSchema, data *InsertData) (*InsertRecord, error) {
+	record, err := InsertDataToRecord(schema, data)
+	if err != nil {
+		return nil, err
+	}
+	defer record.Release()
+	return record.SortByRowID(ctx)
+}
+
+// RecordToInsertData converts the InsertRecord back to InsertData for the codecs not supporting arrow yet.
+// The fixed width fields share the memory with the record.
+func RecordToInsertData(r *InsertRecord) (*InsertData, error) {
+	data := &InsertData{Data: make(map[FieldID]FieldData, len(r.field2Col))}
+	for _, field := range r.schema.GetFields() {
+		fieldData, err := arrowArrayToFieldData(field, r.Column(field.GetFieldID()))
+		if err != nil {
+			return nil, err
+		}
+		data.Data[field.GetFieldID()] = fieldData
+	}
+	return data, nil
+}
+
+// Schema returns the collection schema of the record.
+func (r *InsertRecord) Schema() *schemapb.CollectionSchema {
+	return r.schema
+}
+
+// Record returns the underlying arrow record.
+func (r *InsertRecord) Record() arrow.Record {
+	return r.record
+}
+
+// Column returns the column of the field, it returns nil if the field not found.
+func (r *InsertRecord) Column(fieldID FieldID) arrow.Array {
+	col, ok := r.field2Col[fieldID]
+	if !ok {
+		return nil
+	}
+	return r.record.Column(col)
+}
+
+// NumRows returns the row num of the record.
+func (r *InsertRecord) NumRows() int {
+	return int(r.record.NumRows())
+}
+
+// Timestamps returns the timestamp column without copying.
+func (r *InsertRecord) Timestamps() ([]int64, error) {
+	return r.int64Values(common.TimeStampField)
+}
+
+// RowIDs returns the row id column without copying.
+func (r *InsertRecord) RowIDs() ([]int64, error) {
+	return r.int64Values(common.RowIDField)
+}
+
+func (r *InsertRecord) int64Values(fieldID FieldID) ([]int64, error) {
+	column, ok := r.Column(fieldID).(*array.Int64)
+	if !ok {
+		return nil, fmt.Errorf("int64 field %d not found", fieldID)
+	}
+	return column.Int64Values(), nil
+}
+
+// TimestampRange returns the min and max timestamp of the rows.
+func (r *InsertRecord) TimestampRange() (Timestamp, Timestamp, error) {
+	ts, err := r.Timestamps()
+	if err != nil {
+		return 0, 0, err
+	}
+	var startTs, endTs Timestamp = math.MaxUint64, 0
+	for _, t := range ts {
+		if uint64(t) > endTs {
+			endTs = uint64(t)
+		}
+		if uint64(t) < startTs {
+			startTs = uint64(t)
+		}
+	}
+	return startTs, endTs, nil
+}
+
+// GetMemorySize returns the memory size of the rows, in the same way as InsertData.GetMemorySize.
+func (r *InsertRecord) GetMemorySize() int {
+	var size int
+	for _, field := range r.schema.GetFields() {
+		size += r.FieldMemorySize(field.GetFieldID())
+	}
+	return size
+}
+
+// FieldMemorySize returns the memory size of the field, in the same way as FieldData.GetMemorySize.
+func (r *InsertRecord) FieldMemorySize(fieldID FieldID) int {
+	column := r.Column(fieldID)
+	if column == nil {
+		return 0
+	}
+	size := r.valuesMemorySize(fieldID, column)
+	// a byte per row for the validity of the nullable fields
+	if r.record.Schema().Field(r.field2Col[fieldID]).Nullable {
+		size += column.Len()
+	}
+	return size
+}
+
+func (r *InsertRecord) valuesMemorySize(fieldID FieldID, column arrow.Array) int {
+	switch column := column.(type) {
+	case *array.String:
+		var size int
+		for i := 0; i < column.Len(); i++ {
+			size += len(column.Value(i)) + 16
+		}
+		return size
+	case *array.Binary:
+		field := typeutil.GetField(r.schema, fieldID)
+		if field.GetDataType() == schemapb.DataType_Array {
+			// the element size is only known after unmarshalled
+			fieldData, err := arrowArrayToFieldData(field, column)
+			if err != nil {
+				return 0
+			}
+			return fieldData.GetMemorySize()
+		}
+		var size int
+		for i := 0; i < column.Len(); i++ {
+			size += column.ValueLen(i) + 16
+		}
+		return size
+	case *array.FixedSizeBinary:
+		// the vector field data counts the dim in
+		return column.Len()*column.DataType().(*arrow.FixedSizeBinaryType).ByteWidth + 4
+	case *array.Boolean:
+		return column.Len()
+	default:
+		return column.Len() * column.DataType().(arrow.FixedWidthDataType).BitWidth() / 8
+	}
+}
+
+// Slice returns the rows in [i, j) without copying, the caller should release the returned record.
+func (r *InsertRecord) Slice(i, j int) *InsertRecord {
+	return &InsertRecord{
+		schema:    r.schema,
+		record:    r.record.NewSlice(int64(i), int64(j)),
+		field2Col: r.field2Col,
+	}
+}
+
+// Take returns the rows at the indices in order, the caller should release the returned record.
+func (r *InsertRecord) Take(ctx context.Context, indices []int64) (*InsertRecord, error) {
+	idx := newZeroCopyArray(arrow.PrimitiveTypes.Int64, len(indices), arrow.Int64Traits.CastToBytes(indices), nil)
+	defer idx.Release()
+
+	columns := make([]arrow.Array, 0, r.record.NumCols())
+	defer func() {
+		for _, column := range columns {
+			column.Release()
+		}
+	}()
+	for _, column := range r.record.Columns() {
+		taken, err := compute.TakeArray(ctx, column, idx)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, taken)
+	}
+	return &InsertRecord{
+		schema:    r.schema,
+		record:    array.NewRecord(r.record.Schema(), columns, int64(len(indices))),
+		field2Col: r.field2Col,
+	}, nil
+}
+
+// SortByRowID returns the rows sorted by row id, the same order as DataSorter does.
+// The caller should release the returned record.
+func (r *InsertRecord) SortByRowID(ctx context.Context) (*InsertRecord, error) {
+	rowIDs, err := r.RowIDs()
+	// keep the order if there is no row id, as DataSorter does
+	if err != nil || sort.SliceIsSorted(rowIDs, func(i, j int) bool { return rowIDs[i] < rowIDs[j] }) {
+		r.Retain()
+		return r, nil
+	}
+
+	indices := make([]int64, len(rowIDs))
+	for i := range indices {
+		indices[i] = int64(i)
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return rowIDs[indices[i]] < rowIDs[indices[j]]
+	})
+	return r.Take(ctx, indices)
+}
+
+// Retain increases the reference count of the record.
+func (r *InsertRecord) Retain() {
+	r.record.Retain()
+}
+
+// Release decreases the reference count of the record, the memory is released when it becomes 0.
+func (r *InsertRecord) Release() {
+	r.record.Release()
+}
+
+// newZeroCopyArray returns the fixed width array whose values buffer is the bytes,
+// the rows are null where validData is false, all valid if validData is nil.
+func newZeroCopyArray(dataType arrow.DataType, length int, bytes []byte, validData []bool) arrow.Array {
+	bitmap, nulls := validityBitmap(validData)
+	data := array.NewData(dataType, length, []*memory.Buffer{bitmap, memory.NewBufferBytes(bytes)}, nil, nulls, 0)
+	defer data.Release()
+	return array.MakeFromData(data)
+}
+
+// validityBitmap returns the arrow validity bitmap and the null count of validData, nil if validData is nil.
+func validityBitmap(validData []bool) (*memory.Buffer, int) {
+	if validData == nil {
+		return nil, 0
+	}
+	bitmap := make([]byte, bitutil.BytesForBits(int64(len(validData))))
+	nulls := 0
+	for i, valid := range validData {
+		if valid {
+			bitutil.SetBit(bitmap, i)
+		} else {
+			nulls++
+		}
+	}
+	return memory.NewBufferBytes(bitmap), nulls
+}
+
+// arrowValidData returns the validity of the rows of the array.
+func arrowValidData(arr arrow.Array) []bool {
+	validData := make([]bool, arr.Len())
+	for i := range validData {
+		validData[i] = arr.IsValid(i)
+	}
+	return validData
+}
+
+// isNullableField returns whether the field is nullable, the invalid nullable type param is taken as false,
+// which is rejected once the collection created.
+func isNullableField(field *schemapb.FieldSchema) bool {
+	nullable, err := common.IsFieldNullable(field.GetTypeParams()...)
+	return err == nil && nullable && common.IsNullableSupported(field.GetDataType())
+}
+
+// fixedSizeBinaryBytes returns the values of the array without copying.
+func fixedSizeBinaryBytes(arr *array.FixedSizeBinary) []byte {
+	width := arr.DataType().(*arrow.FixedSizeBinaryType).ByteWidth
+	values := arr.Data().Buffers()[1]
+	if values == nil {
+		return nil
+	}
+	offset := arr.Data().Offset()
+	return values.Bytes()[offset*width : (offset+arr.Len())*width]
+}
+
+func fieldDataToArrowArray(field *schemapb.FieldSchema, fieldData FieldData) (arrow.Array, error) {
+	switch field.GetDataType() {
+	case schemapb.DataType_Bool:
+		data := fieldData.(*BoolFieldData)
+		builder := array.NewBooleanBuilder(memory.DefaultAllocator)
+		defer builder.Release()
+		builder.AppendValues(data.Data, data.ValidData)
+		return builder.NewArray(), nil
+	case schemapb.DataType_Int8:
+		data := fieldData.(*Int8FieldData)
+		return newZeroCopyArray(arrow.PrimitiveTypes.Int8, len(data.Data), arrow.Int8Traits.CastToBytes(data.Data), data.ValidData), nil
+	case schemapb.DataType_Int16:
+		data := fieldData.(*Int16FieldData)
+		return newZeroCopyArray(arrow.PrimitiveTypes.Int16, len(data.Data), arrow.Int16Traits.CastToBytes(data.Data), data.ValidData), nil
+	case schemapb.DataType_Int32:
+		data := fieldData.(*Int32FieldData)
+		return newZeroCopyArray(arrow.PrimitiveTypes.Int32, len(data.Data), arrow.Int32Traits.CastToBytes(data.Data), data.ValidData), nil
+	case schemapb.DataType_Int64:
+		data := fieldData.(*Int64FieldData)
+		return newZeroCopyArray(arrow.PrimitiveTypes.Int64, len(data.Data), arrow.Int64Traits.CastToBytes(data.Data), data.ValidData), nil
+	case schemapb.DataType_Float:
+		data := fieldData.(*FloatFieldData)
+		return newZeroCopyArray(arrow.PrimitiveTypes.Float32, len(data.Data), arrow.Float32Traits.CastToBytes(data.Data), data.ValidData), nil
+	case schemapb.DataType_Double:
+		data := fieldData.(*DoubleFieldData)
+		return newZeroCopyArray(arrow.PrimitiveTypes.Float64, len(data.Data), arrow.Float64Traits.CastToBytes(data.Data), data.ValidData), nil
+	case schemapb.DataType_String, schemapb.DataType_VarChar:
+		data := fieldData.(*StringFieldData)
+		builder := array.NewStringBuilder(memory.DefaultAllocator)
+		defer builder.Release()
+		builder.AppendValues(data.Data, data.ValidData)
+		return builder.NewArray(), nil
+	case schemapb.DataType_Array:
+		data := fieldData.(*ArrayFieldData)
+		builder := array.NewBinaryBuilder(memory.DefaultAllocator, arrow.BinaryTypes.Binary)
+		defer builder.Release()
+		for i, value := range data.Data {
+			if isNullRow(data.ValidData, i) {
+				builder.AppendNull()
+				continue
+			}
+			bytes, err := proto.Marshal(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to marshal array of field %d", field.GetFieldID())
+			}
+			builder.Append(bytes)
+		}
+		return builder.NewArray(), nil
+	case schemapb.DataType_JSON:
+		data := fieldData.(*JSONFieldData)
+		builder := array.NewBinaryBuilder(memory.DefaultAllocator, arrow.BinaryTypes.Binary)
+		defer builder.Release()
+		builder.AppendValues(data.Data, data.ValidData)
+		return builder.NewArray(), nil
+	case schemapb.DataType_FloatVector:
+		data := fieldData.(*FloatVectorFieldData)
+		dataType := milvusDataTypeToArrowType(field.GetDataType(), data.Dim)
+		return newZeroCopyArray(dataType, data.RowNum(), arrow.Float32Traits.CastToBytes(data.Data), nil), nil
+	case schemapb.DataType_BinaryVector:
+		data := fieldData.(*BinaryVectorFieldData)
+		dataType := milvusDataTypeToArrowType(field.GetDataType(), data.Dim)
+		return newZeroCopyArray(dataType, data.RowNum(), data.Data, nil), nil
+	case schemapb.DataType_Float16Vector:
+		data := fieldData.(*Float16VectorFieldData)
+		dataType := milvusDataTypeToArrowType(field.GetDataType(), data.Dim)
+		return newZeroCopyArray(dataType, data.RowNum(), data.Data, nil), nil
+	case schemapb.DataType_BFloat16Vector:
+		data := fieldData.(*BFloat16VectorFieldData)
+		dataType := milvusDataTypeToArrowType(field.GetDataType(), data.Dim)
+		return newZeroCopyArray(dataType, data.RowNum(), data.Data, nil), nil
+	default:
+		return nil, fmt.Errorf("undefined data type %d", field.GetDataType())
+	}
+}
+
+func arrowArrayToFieldData(field *schemapb.FieldSchema, arr arrow.Array) (FieldData, error) {
+	if arr == nil {
+		return nil, fmt.Errorf("column of field %d not found", field.GetFieldID())
+	}
+	nullable := isNullableField(field)
+	var validData []bool
+	if nullable {
+		validData = arrowValidData(arr)
+	}
+	switch field.GetDataType() {
+	case schemapb.DataType_Bool:
+		column := arr.(*array.Boolean)
+		data := make([]bool, column.Len())
+		for i := range data {
+			data[i] = column.IsValid(i) && column.Value(i)
+		}
+		return &BoolFieldData{Data: data, ValidData: validData, Nullable: nullable}, nil
+	case schemapb.DataType_Int8:
+		return &Int8FieldData{Data: arr.(*array.Int8).Int8Values(), ValidData: validData, Nullable: nullable}, nil
+	case schemapb.DataType_Int16:
+		return &Int16FieldData{Data: arr.(*array.Int16).Int16Values(), ValidData: validData, Nullable: nullable}, nil
+	case schemapb.DataType_Int32:
+		return &Int32FieldData{Data: arr.(*array.Int32).Int32Values(), ValidData: validData, Nullable: nullable}, nil
+	case schemapb.DataType_Int64:
+		return &Int64FieldData{Data: arr.(*array.Int64).Int64Values(), ValidData: validData, Nullable: nullable}, nil
+	case schemapb.DataType_Float:
+		return &FloatFieldData{Data: arr.(*array.Float32).Float32Values(), ValidData: validData, Nullable: nullable}, nil
+	case schemapb.DataType_Double:
+		return &DoubleFieldData{Data: arr.(*array.Float64).Float64Values(), ValidData: validData, Nullable: nullable}, nil
+	case schemapb.DataType_String, schemapb.DataType_VarChar:
+		column := arr.(*array.String)
+		data := make([]string, column.Len())
+		for i := range data {
+			data[i] = column.Value(i)
+		}
+		return &StringFieldData{Data: data, DataType: field.GetDataType(), ValidData: validData, Nullable: nullable}, nil
+	case schemapb.DataType_Array:
+		column := arr.(*array.Binary)
+		data := make([]*schemapb.ScalarField, column.Len())
+		for i := range data {
+			if column.IsNull(i) {
+				continue
+			}
+			data[i] = &schemapb.ScalarField{}
+			if err := proto.Unmarshal(column.Value(i), data[i]); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal array of field %d", field.GetFieldID())
+			}
+		}
+		return &ArrayFieldData{ElementType: field.GetElementType(), Data: data, ValidData: validData, Nullable: nullable}, nil
+	case schemapb.DataType_JSON:
+		column := arr.(*array.Binary)
+		data := make([][]byte, column.Len())
+		for i := range data {
+			if column.IsValid(i) {
+				data[i] = column.Value(i)
+			}
+		}
+		return &JSONFieldData{Data: data, ValidData: validData, Nullable: nullable}, nil
+	case schemapb.DataType_FloatVector:
+		column := arr.(*array.FixedSizeBinary)
+		width := column.DataType().(*arrow.FixedSizeBinaryType).ByteWidth
+		return &FloatVectorFieldData{
+			Data: arrow.Float32Traits.CastFromBytes(fixedSizeBinaryBytes(column)),
+			Dim:  width / 4,
+		}, nil
+	case schemapb.DataType_BinaryVector:
+		column := arr.(*array.FixedSizeBinary)
+		width := column.DataType().(*arrow.FixedSizeBinaryType).ByteWidth
+		return &BinaryVectorFieldData{Data: fixedSizeBinaryBytes(column), Dim: width * 8}, nil
+	case schemapb.DataType_Float16Vector:
+		column := arr.(*array.FixedSizeBinary)
+		width := column.DataType().(*arrow.FixedSizeBinaryType).ByteWidth
+		return &Float16VectorFieldData{Data: fixedSizeBinaryBytes(column), Dim: width / 2}, nil
+	case schemapb.DataType_BFloat16Vector:
+		column := arr.(*array.FixedSizeBinary)
+		width := column.DataType().(*arrow.FixedSizeBinaryType).ByteWidth
+		return &BFloat16VectorFieldData{Data: fixedSizeBinaryBytes(column), Dim: width / 2}, nil
+	default:
+		return nil, fmt.Errorf("undefined data type %d", field.GetDataType())
+	}
+}
@@ -0,0 +1,203 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/etcdpb"
+)
+
+type InsertRecordSuite struct {
+	suite.Suite
+	meta *etcdpb.CollectionMeta
+}
+
+func (s *InsertRecordSuite) SetupSuite() {
+	s.meta = genTestCollectionMeta()
+}
+
+func (s *InsertRecordSuite) genInsertData(rowIDs ...int64) *InsertData {
+	data, err := genSequentialInsertData(s.meta.GetSchema(), rowIDs...)
+	s.Require().NoError(err)
+	return data
+}
+
+func (s *InsertRecordSuite) TestRoundTrip() {
+	data := s.genInsertData(1, 2, 3)
+	record, err := InsertDataToRecord(s.meta.GetSchema(), data)
+	s.Require().NoError(err)
+	defer record.Release()
+
+	s.Equal(3, record.NumRows())
+	s.EqualValues(len(s.meta.GetSchema().GetFields()), record.Record().NumCols())
+	s.Equal(data.GetMemorySize(), record.GetMemorySize())
+	s.Nil(record.Column(999))
+
+	startTs, endTs, err := record.TimestampRange()
+	s.NoError(err)
+	s.EqualValues(101, startTs)
+	s.EqualValues(103, endTs)
+
+	converted, err := RecordToInsertData(record)
+	s.Require().NoError(err)
+	for fieldID, fieldData := range data.Data {
+		if fieldID == ArrayField {
+			// the size cache of the proto message differs
+			expected := fieldData.(*ArrayFieldData)
+			actual := converted.Data[fieldID].(*ArrayFieldData)
+			s.Equal(expected.ElementType, actual.ElementType)
+			s.Require().Equal(expected.RowNum(), actual.RowNum())
+			for i := range expected.Data {
+				s.True(proto.Equal(expected.Data[i], actual.Data[i]))
+			}
+			continue
+		}
+		s.Equal(fieldData, converted.Data[fieldID], fieldID)
+	}
+}
+
+func (s *InsertRecordSuite) TestZeroCopy() {
+	data := s.genInsertData(1, 2)
+	record, err := InsertDataToRecord(s.meta.GetSchema(), data)
+	s.Require().NoError(err)
+	defer record.Release()
+
+	data.Data[Int64Field].(*Int64FieldData).Data[0] = 1000
+	data.Data[FloatVectorField].(*FloatVectorFieldData).Data[0] = 1000
+	s.EqualValues(1000, record.Column(Int64Field).(*array.Int64).Value(0))
+
+	converted, err := RecordToInsertData(record)
+	s.Require().NoError(err)
+	s.EqualValues(1000, converted.Data[FloatVectorField].(*FloatVectorFieldData).Data[0])
+}
+
+func (s *InsertRecordSuite) TestSortByRowID() {
+	ctx := context.Background()
+	record, err := NewSortedInsertRecord(ctx, s.meta.GetSchema(), s.genInsertData(3, 1, 2))
+	s.Require().NoError(err)
+	defer record.Release()
+
+	expected, err := InsertDataToRecord(s.meta.GetSchema(), s.genInsertData(1, 2, 3))
+	s.Require().NoError(err)
+	defer expected.Release()
+	s.True(array.RecordEqual(expected.Record(), record.Record()))
+
+	// nothing is copied if sorted already
+	sorted, err := expected.SortByRowID(ctx)
+	s.Require().NoError(err)
+	defer sorted.Release()
+	s.Same(expected, sorted)
+
+	slice := record.Slice(1, 3)
+	defer slice.Release()
+	rowIDs, err := slice.RowIDs()
+	s.NoError(err)
+	s.Equal([]int64{2, 3}, rowIDs)
+}
+
+func (s *InsertRecordSuite) TestSerializeRecord() {
+	ctx := context.Background()
+	codec := NewInsertCodecWithSchema(s.meta)
+	record, err := NewSortedInsertRecord(ctx, s.meta.GetSchema(), s.genInsertData(3, 1, 2))
+	s.Require().NoError(err)
+	defer record.Release()
+
+	blobs, err := codec.SerializeRecord(PartitionID, SegmentID, record)
+	s.Require().NoError(err)
+	s.Len(blobs, len(s.meta.GetSchema().GetFields()))
+
+	// the same as the InsertData serialized
+	expectedBlobs, err := codec.Serialize(PartitionID, SegmentID, s.genInsertData(3, 1, 2))
+	s.Require().NoError(err)
+	_, _, expected, err := codec.Deserialize(expectedBlobs)
+	s.Require().NoError(err)
+	partitionID, segmentID, actual, err := codec.Deserialize(blobs)
+	s.Require().NoError(err)
+	s.EqualValues(PartitionID, partitionID)
+	s.EqualValues(SegmentID, segmentID)
+	s.Equal(expected.Data, actual.Data)
+	s.Equal([]int64{1, 2, 3}, actual.Data[RowIDField].(*Int64FieldData).Data)
+
+	empty := record.Slice(0, 0)
+	defer empty.Release()
+	_, err = codec.SerializeRecord(PartitionID, SegmentID, empty)
+	s.Error(err)
+	_, err = NewInsertCodec().SerializeRecord(PartitionID, SegmentID, record)
+	s.Error(err)
+}
+
+func (s *InsertRecordSuite) TestInvalidData() {
+	_, err := InsertDataToRecord(nil, s.genInsertData(1))
+	s.Error(err)
+
+	_, err = InsertDataToRecord(s.meta.GetSchema(), nil)
+	s.Error(err)
+
+	data := s.genInsertData(1)
+	delete(data.Data, StringField)
+	_, err = InsertDataToRecord(s.meta.GetSchema(), data)
+	s.Error(err)
+
+	data = s.genInsertData(1)
+	data.Data[StringField] = &StringFieldData{Data: []string{"a", "b"}}
+	_, err = InsertDataToRecord(s.meta.GetSchema(), data)
+	s.Error(err)
+}
+
+// genSequentialInsertData returns the rows with the row ids given, the values of each row are derived from its row id.
+func genSequentialInsertData(schema *schemapb.CollectionSchema, rowIDs ...int64) (*InsertData, error) {
+	data, err := NewInsertData(schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range rowIDs {
+		b := byte(id)
+		err = data.Append(map[FieldID]interface{}{
+			RowIDField:          id,
+			TimestampField:      id + 100,
+			BoolField:           id%2 == 0,
+			Int8Field:           int8(id),
+			Int16Field:          int16(id),
+			Int32Field:          int32(id),
+			Int64Field:          id,
+			FloatField:          float32(id),
+			DoubleField:         float64(id),
+			StringField:         string(rune('a' + id)),
+			BinaryVectorField:   []byte{b},
+			FloatVectorField:    []float32{float32(id), float32(id), float32(id), float32(id)},
+			ArrayField:          &schemapb.ScalarField{Data: &schemapb.ScalarField_IntData{IntData: &schemapb.IntArray{Data: []int32{int32(id), 1}}}},
+			JSONField:           []byte(`{"id":` + string(rune('0'+id)) + `}`),
+			Float16VectorField:  []byte{b, b, b, b, b, b, b, b},
+			BFloat16VectorField: []byte{b, b, b, b, b, b, b, b},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func TestInsertRecord(t *testing.T) {
+	suite.Run(t, new(InsertRecordSuite))
+}
@@ -0,0 +1,196 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// Binlogs written by the early 2.0 releases have a server id in every event header,
+// and the binlog version, server version, commit id and header length in the fixed
+// part of the descriptor event, which may also have no extras. The payloads and the
+// fixed parts of the other events are unchanged, so a legacy binlog is read by
+// migrating its layout to the current one.
+
+type legacyEventHeader struct {
+	Timestamp    typeutil.Timestamp
+	TypeCode     EventTypeCode
+	ServerID     int32
+	EventLength  int32
+	NextPosition int32
+}
+
+type legacyDescriptorEventDataFixPart struct {
+	BinlogVersion   int16
+	ServerVersion   int64
+	CommitID        int64
+	HeaderLength    int8
+	CollectionID    int64
+	PartitionID     int64
+	SegmentID       int64
+	FieldID         int64
+	StartTimestamp  typeutil.Timestamp
+	EndTimestamp    typeutil.Timestamp
+	PayloadDataType schemapb.DataType
+}
+
+var (
+	legacyEventHeaderSize        = binary.Size(legacyEventHeader{})
+	legacyDescriptorFixPartSize  = binary.Size(legacyDescriptorEventDataFixPart{})
+	currentEventHeaderSize       = binary.Size(baseEventHeader{})
+	magicNumberSize              = binary.Size(MagicNumber)
+	legacyDescriptorMinimumBytes = magicNumberSize + legacyEventHeaderSize + legacyDescriptorFixPartSize
+)
+
+// readLegacyEventHeader reads the legacy event header at pos, returns false if it's not a valid one.
+func readLegacyEventHeader(data []byte, pos int) (*legacyEventHeader, bool) {
+	if pos+legacyEventHeaderSize > len(data) {
+		return nil, false
+	}
+	header := &legacyEventHeader{}
+	if err := binary.Read(bytes.NewReader(data[pos:]), common.Endian, header); err != nil {
+		return nil, false
+	}
+	if int(header.EventLength) < legacyEventHeaderSize || pos+int(header.EventLength) > len(data) ||
+		int(header.NextPosition) != pos+int(header.EventLength) {
+		return nil, false
+	}
+	return header, true
+}
+
+// IsLegacyBinlog returns whether the binlog is in the layout of early 2.0 releases.
+func IsLegacyBinlog(data []byte) bool {
+	if len(data) < legacyDescriptorMinimumBytes {
+		return false
+	}
+	var magicNumber int32
+	if err := binary.Read(bytes.NewReader(data), common.Endian, &magicNumber); err != nil || magicNumber != MagicNumber {
+		return false
+	}
+	// a current binlog has a consistent current descriptor header
+	current := &baseEventHeader{}
+	if err := binary.Read(bytes.NewReader(data[magicNumberSize:]), common.Endian, current); err == nil &&
+		current.TypeCode == DescriptorEventType && int(current.NextPosition) == magicNumberSize+int(current.EventLength) {
+		return false
+	}
+	header, ok := readLegacyEventHeader(data, magicNumberSize)
+	return ok && header.TypeCode == DescriptorEventType &&
+		int(header.EventLength) >= legacyEventHeaderSize+legacyDescriptorFixPartSize
+}
+
+// MigrateLegacyBinlog rewrites a binlog of early 2.0 releases in the current layout.
+func MigrateLegacyBinlog(data []byte) ([]byte, error) {
+	if !IsLegacyBinlog(data) {
+		return nil, fmt.Errorf("not a legacy binlog")
+	}
+	buffer := new(bytes.Buffer)
+	if err := binary.Write(buffer, common.Endian, MagicNumber); err != nil {
+		return nil, err
+	}
+
+	header, _ := readLegacyEventHeader(data, magicNumberSize)
+	descriptor, err := migrateLegacyDescriptorEvent(header, data[magicNumberSize+legacyEventHeaderSize:magicNumberSize+int(header.EventLength)])
+	if err != nil {
+		return nil, err
+	}
+	// the extras are kept as they are, instead of finished by descriptorEvent.Write
+	if err := descriptor.descriptorEventHeader.Write(buffer); err != nil {
+		return nil, err
+	}
+	if err := descriptor.descriptorEventData.Write(buffer); err != nil {
+		return nil, err
+	}
+
+	for pos := int(header.NextPosition); pos < len(data); pos = int(header.NextPosition) {
+		var ok bool
+		if header, ok = readLegacyEventHeader(data, pos); !ok {
+			return nil, fmt.Errorf("invalid legacy event at position %d", pos)
+		}
+		body := data[pos+legacyEventHeaderSize : pos+int(header.EventLength)]
+		eventLength := int32(currentEventHeaderSize + len(body))
+		current := &baseEventHeader{
+			Timestamp:    header.Timestamp,
+			TypeCode:     header.TypeCode,
+			EventLength:  eventLength,
+			NextPosition: int32(buffer.Len()) + eventLength,
+		}
+		if err := current.Write(buffer); err != nil {
+			return nil, err
+		}
+		buffer.Write(body)
+	}
+	return buffer.Bytes(), nil
+}
+
+// migrateLegacyDescriptorEvent builds the current descriptor event of the legacy descriptor event body.
+func migrateLegacyDescriptorEvent(header *legacyEventHeader, body []byte) (*descriptorEvent, error) {
+	fixPart := &legacyDescriptorEventDataFixPart{}
+	if err := binary.Read(bytes.NewReader(body), common.Endian, fixPart); err != nil {
+		return nil, err
+	}
+	extraBytes, err := legacyDescriptorExtras(body[legacyDescriptorFixPartSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	event := newDescriptorEvent()
+	event.descriptorEventHeader.Timestamp = header.Timestamp
+	event.DescriptorEventDataFixPart = DescriptorEventDataFixPart{
+		CollectionID:    fixPart.CollectionID,
+		PartitionID:     fixPart.PartitionID,
+		SegmentID:       fixPart.SegmentID,
+		FieldID:         fixPart.FieldID,
+		StartTimestamp:  fixPart.StartTimestamp,
+		EndTimestamp:    fixPart.EndTimestamp,
+		PayloadDataType: fixPart.PayloadDataType,
+	}
+	event.ExtraBytes = extraBytes
+	event.ExtraLength = int32(len(extraBytes))
+	event.EventLength = event.descriptorEventHeader.GetMemoryUsageInBytes() + event.descriptorEventData.GetMemoryUsageInBytes()
+	event.NextPosition = int32(magicNumberSize) + event.EventLength
+	return event, nil
+}
+
+// legacyDescriptorExtras returns the extras following the post header lengths of a legacy
+// descriptor event. The number of post header lengths depends on the release, so the
+// extras are located by the extra length matching the rest of the event.
+func legacyDescriptorExtras(variablePart []byte) ([]byte, error) {
+	lengthSize := binary.Size(int32(0))
+	for postHeaderLengths := 1; postHeaderLengths+lengthSize <= len(variablePart); postHeaderLengths++ {
+		var extraLength int32
+		if err := binary.Read(bytes.NewReader(variablePart[postHeaderLengths:]), common.Endian, &extraLength); err != nil {
+			return nil, err
+		}
+		if extraLength > 0 && postHeaderLengths+lengthSize+int(extraLength) == len(variablePart) {
+			return variablePart[postHeaderLengths+lengthSize:], nil
+		}
+		if extraLength == 0 && postHeaderLengths+lengthSize == len(variablePart) {
+			return []byte("{}"), nil
+		}
+	}
+	if len(variablePart) > int(EventTypeEnd) {
+		return nil, fmt.Errorf("invalid legacy descriptor event of %d variable bytes", len(variablePart))
+	}
+	// the releases before extras only have the post header lengths
+	return []byte("{}"), nil
+}
@@ -0,0 +1,171 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+type LegacyBinlogSuite struct {
+	suite.Suite
+}
+
+func TestLegacyBinlog(t *testing.T) {
+	suite.Run(t, new(LegacyBinlogSuite))
+}
+
+func (s *LegacyBinlogSuite) writeBinlog() []byte {
+	w := NewInsertBinlogWriter(schemapb.DataType_Int64, 10, 20, 30, 40)
+	defer w.Close()
+	w.SetEventTimeStamp(1000, 2000)
+	for _, values := range [][]int64{{1, 2, 3}, {4, 5}} {
+		e, err := w.NextInsertEventWriter()
+		s.Require().NoError(err)
+		s.Require().NoError(e.AddDataToPayload(values))
+		e.SetEventTimestamp(100, 200)
+	}
+	w.AddExtra(originalSizeKey, "40")
+	s.Require().NoError(w.Finish())
+	buf, err := w.GetBuffer()
+	s.Require().NoError(err)
+	return buf
+}
+
+// toLegacy rewrites a current binlog in the layout of early 2.0 releases.
+func (s *LegacyBinlogSuite) toLegacy(data []byte, withExtras bool) []byte {
+	descriptor, err := ReadDescriptorEvent(bytes.NewReader(data[magicNumberSize:]))
+	s.Require().NoError(err)
+
+	body := new(bytes.Buffer)
+	fixPart := descriptor.DescriptorEventDataFixPart
+	s.Require().NoError(binary.Write(body, common.Endian, legacyDescriptorEventDataFixPart{
+		BinlogVersion:   1,
+		ServerVersion:   2,
+		CommitID:        3,
+		HeaderLength:    int8(legacyEventHeaderSize),
+		CollectionID:    fixPart.CollectionID,
+		PartitionID:     fixPart.PartitionID,
+		SegmentID:       fixPart.SegmentID,
+		FieldID:         fixPart.FieldID,
+		StartTimestamp:  fixPart.StartTimestamp,
+		EndTimestamp:    fixPart.EndTimestamp,
+		PayloadDataType: fixPart.PayloadDataType,
+	}))
+	// the releases before index file events had 7 event types
+	body.Write(descriptor.PostHeaderLengths[:IndexFileEventType])
+	if withExtras {
+		extras := []byte(`{"original_size":"40"}`)
+		s.Require().NoError(binary.Write(body, common.Endian, int32(len(extras))))
+		body.Write(extras)
+	}
+
+	legacy := new(bytes.Buffer)
+	s.Require().NoError(binary.Write(legacy, common.Endian, MagicNumber))
+	writeEvent := func(typeCode EventTypeCode, body []byte) {
+		eventLength := int32(legacyEventHeaderSize + len(body))
+		s.Require().NoError(binary.Write(legacy, common.Endian, legacyEventHeader{
+			Timestamp:    descriptor.Timestamp,
+			TypeCode:     typeCode,
+			ServerID:     1,
+			EventLength:  eventLength,
+			NextPosition: int32(legacy.Len()) + eventLength,
+		}))
+		legacy.Write(body)
+	}
+	writeEvent(DescriptorEventType, body.Bytes())
+	for pos := int(descriptor.NextPosition); pos < len(data); {
+		header, err := readEventHeader(bytes.NewReader(data[pos:]))
+		s.Require().NoError(err)
+		writeEvent(header.TypeCode, data[pos+currentEventHeaderSize:pos+int(header.EventLength)])
+		pos = int(header.NextPosition)
+	}
+	return legacy.Bytes()
+}
+
+func (s *LegacyBinlogSuite) readAll(data []byte) (*BinlogReader, []int64) {
+	reader, err := NewBinlogReader(data)
+	s.Require().NoError(err)
+	var values []int64
+	for {
+		event, err := reader.NextEventReader()
+		s.Require().NoError(err)
+		if event == nil {
+			break
+		}
+		v, err := event.GetInt64FromPayload()
+		s.Require().NoError(err)
+		values = append(values, v...)
+	}
+	return reader, values
+}
+
+func (s *LegacyBinlogSuite) TestDetect() {
+	data := s.writeBinlog()
+	s.False(IsLegacyBinlog(data))
+	s.True(IsLegacyBinlog(s.toLegacy(data, true)))
+	s.False(IsLegacyBinlog(data[:magicNumberSize]))
+	s.False(IsLegacyBinlog(append([]byte("PAR1"), data[magicNumberSize:]...)))
+
+	_, err := MigrateLegacyBinlog(data)
+	s.Error(err)
+}
+
+func (s *LegacyBinlogSuite) TestRead() {
+	data := s.writeBinlog()
+	for _, withExtras := range []bool{true, false} {
+		reader, values := s.readAll(s.toLegacy(data, withExtras))
+		s.Equal([]int64{1, 2, 3, 4, 5}, values)
+		s.EqualValues(10, reader.CollectionID)
+		s.EqualValues(20, reader.PartitionID)
+		s.EqualValues(30, reader.SegmentID)
+		s.EqualValues(40, reader.FieldID)
+		s.EqualValues(1000, reader.StartTimestamp)
+		s.EqualValues(2000, reader.EndTimestamp)
+		s.Equal(schemapb.DataType_Int64, reader.PayloadDataType)
+		s.Equal(0, reader.FormatVersion())
+		if withExtras {
+			s.Equal("40", reader.Extras[originalSizeKey])
+		} else {
+			s.Empty(reader.Extras)
+		}
+		reader.Close()
+	}
+}
+
+func (s *LegacyBinlogSuite) TestMigrate() {
+	legacy := s.toLegacy(s.writeBinlog(), true)
+	migrated, err := MigrateLegacyBinlog(legacy)
+	s.Require().NoError(err)
+	s.False(IsLegacyBinlog(migrated))
+	s.Len(migrated, len(legacy)-3*binary.Size(int32(0))-legacyDescriptorFixPartSize+binary.Size(DescriptorEventDataFixPart{})+
+		int(EventTypeEnd-IndexFileEventType))
+	s.Equal(legacy[len(legacy)-16:], migrated[len(migrated)-16:])
+
+	_, values := s.readAll(migrated)
+	s.Equal([]int64{1, 2, 3, 4, 5}, values)
+
+	// a truncated legacy binlog is corrupted
+	_, err = MigrateLegacyBinlog(legacy[:len(legacy)-1])
+	s.Error(err)
+}
@@ -0,0 +1,126 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"strings"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// The lifecycle rules of the bucket expire the objects by the object storage itself, so the objects Milvus decides
+// to recycle are removed without a delete request of each. The rules of Milvus are told from the others of the bucket
+// by the ids, or by the condition isLive of GCS, which has no rule ids, so the others are kept when they are replaced.
+const lifecycleRuleIDPrefix = "milvus:"
+
+// LifecycleRule expires the objects under the prefix the days after they are written.
+type LifecycleRule struct {
+	Prefix string
+	Days   int
+}
+
+func (rule LifecycleRule) id() string {
+	return lifecycleRuleIDPrefix + rule.Prefix
+}
+
+// LifecycleChunkManager is the ChunkManager managing the lifecycle rules of the bucket.
+type LifecycleChunkManager interface {
+	ChunkManager
+	// LifecycleRules returns the lifecycle rules of Milvus of the bucket.
+	LifecycleRules(ctx context.Context) ([]LifecycleRule, error)
+	// SetLifecycleRules replaces the lifecycle rules of Milvus of the bucket, the other rules are kept.
+	SetLifecycleRules(ctx context.Context, rules []LifecycleRule) error
+}
+
+// lifecycleObjectStorage is the ObjectStorage supporting the lifecycle rules.
+type lifecycleObjectStorage interface {
+	GetLifecycleRules(ctx context.Context, bucketName string) ([]LifecycleRule, error)
+	SetLifecycleRules(ctx context.Context, bucketName string, rules []LifecycleRule) error
+}
+
+func errLifecycleNotSupported(provider string) error {
+	return merr.WrapErrServiceInternal("lifecycle rules not supported by " + provider)
+}
+
+// s3LifecycleRule translates the rule into the lifecycle rule of S3.
+func s3LifecycleRule(rule LifecycleRule) lifecycle.Rule {
+	return lifecycle.Rule{
+		ID:         rule.id(),
+		Status:     "Enabled",
+		RuleFilter: lifecycle.Filter{Prefix: rule.Prefix},
+		Expiration: lifecycle.Expiration{Days: lifecycle.ExpirationDays(rule.Days)},
+	}
+}
+
+// lifecycleRuleOfS3 translates the lifecycle rule of S3 back, false if it's not a rule of Milvus.
+func lifecycleRuleOfS3(s3Rule lifecycle.Rule) (LifecycleRule, bool) {
+	if !strings.HasPrefix(s3Rule.ID, lifecycleRuleIDPrefix) {
+		return LifecycleRule{}, false
+	}
+	return LifecycleRule{
+		Prefix: s3Rule.RuleFilter.Prefix,
+		Days:   int(s3Rule.Expiration.Days),
+	}, true
+}
+
+// gcsLifecycle is the lifecycle of the bucket of the JSON API of GCS, the rules are kept raw,
+// so the rules not of Milvus are written back as they are.
+type gcsLifecycle struct {
+	Lifecycle struct {
+		Rule []map[string]any `json:"rule"`
+	} `json:"lifecycle"`
+}
+
+const gcsDeleteAction = "Delete"
+
+// gcsRuleOf translates the rule into the lifecycle rule of GCS.
+func gcsRuleOf(rule LifecycleRule) map[string]any {
+	return map[string]any{
+		"action": map[string]any{"type": gcsDeleteAction},
+		"condition": map[string]any{
+			"age":           rule.Days,
+			"isLive":        true,
+			"matchesPrefix": []string{rule.Prefix},
+		},
+	}
+}
+
+// lifecycleRuleOfGCS translates the lifecycle rule of GCS back, false if it's not a rule of Milvus,
+// which deletes the live objects of a single prefix by the age.
+func lifecycleRuleOfGCS(gcsRule map[string]any) (LifecycleRule, bool) {
+	action, _ := gcsRule["action"].(map[string]any)
+	condition, _ := gcsRule["condition"].(map[string]any)
+	if action["type"] != gcsDeleteAction || len(condition) != 3 || condition["isLive"] != true {
+		return LifecycleRule{}, false
+	}
+	age, ok := condition["age"].(float64)
+	if !ok {
+		return LifecycleRule{}, false
+	}
+	prefixes, _ := condition["matchesPrefix"].([]any)
+	if len(prefixes) != 1 {
+		return LifecycleRule{}, false
+	}
+	prefix, ok := prefixes[0].(string)
+	if !ok {
+		return LifecycleRule{}, false
+	}
+	return LifecycleRule{Prefix: prefix, Days: int(age)}, true
+}
@@ -0,0 +1,79 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3LifecycleRule(t *testing.T) {
+	rules := []LifecycleRule{
+		{Prefix: "files/insert_log/1/", Days: 1},
+		{Prefix: "files/delta_log/1/", Days: 2},
+	}
+	for _, rule := range rules {
+		s3Rule := s3LifecycleRule(rule)
+		assert.Equal(t, "Enabled", s3Rule.Status)
+		back, ok := lifecycleRuleOfS3(s3Rule)
+		assert.True(t, ok)
+		assert.Equal(t, rule, back)
+	}
+
+	_, ok := lifecycleRuleOfS3(lifecycle.Rule{ID: "others", RuleFilter: lifecycle.Filter{Prefix: "files/"}})
+	assert.False(t, ok)
+}
+
+func TestGcsLifecycleRules(t *testing.T) {
+	ctx := context.Background()
+	bucketName := "gcs-bucket"
+	fake, server := newFakeGcsServer(bucketName)
+	defer server.Close()
+	gcs := newGcpNativeObjectStorage(server.Client(), server.URL, 1)
+
+	rules, err := gcs.GetLifecycleRules(ctx, bucketName)
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	// the rule of the others is kept
+	fake.lifecycle = []byte(`{"lifecycle":{"rule":[{"action":{"type":"Delete"},"condition":{"age":30,"matchesPrefix":["logs/"]}}]}}`)
+	rules = []LifecycleRule{{Prefix: "files/insert_log/1/", Days: 1}, {Prefix: "files/delta_log/1/", Days: 1}}
+	require.NoError(t, gcs.SetLifecycleRules(ctx, bucketName, rules))
+	got, err := gcs.GetLifecycleRules(ctx, bucketName)
+	require.NoError(t, err)
+	assert.Equal(t, rules, got)
+
+	require.NoError(t, gcs.SetLifecycleRules(ctx, bucketName, rules[1:]))
+	got, err = gcs.GetLifecycleRules(ctx, bucketName)
+	require.NoError(t, err)
+	assert.Equal(t, rules[1:], got)
+	bucket := &gcsLifecycle{}
+	require.NoError(t, json.Unmarshal(fake.lifecycle, bucket))
+	assert.Len(t, bucket.Lifecycle.Rule, 2)
+}
+
+func TestRemoteChunkManagerLifecycleNotSupported(t *testing.T) {
+	cm := &RemoteChunkManager{client: &AzureObjectStorage{}, bucketName: "bucket"}
+	_, err := cm.LifecycleRules(context.Background())
+	assert.Error(t, err)
+	assert.Error(t, cm.SetLifecycleRules(context.Background(), nil))
+}
@@ -18,6 +18,7 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"path"
@@ -38,7 +39,7 @@ type LocalChunkManager struct {
 	localPath string
 }
 
-var _ ChunkManager = (*LocalChunkManager)(nil)
+var _ EtagChunkManager = (*LocalChunkManager)(nil)
 
 // NewLocalChunkManager create a new local manager object.
 func NewLocalChunkManager(opts ...Option) *LocalChunkManager {
@@ -195,11 +196,12 @@ func (lcm *LocalChunkManager) ReadAt(ctx context.Context, filePath string, off i
 	defer file.Close()
 
 	res := make([]byte, length)
-	_, err = file.ReadAt(res, off)
-	if err != nil {
+	n, err := file.ReadAt(res, off)
+	// truncated at the end of the file as the ranged reads of the object storages
+	if err != nil && !errors.Is(err, io.EOF) {
 		return nil, merr.WrapErrIoFailed(filePath, err)
 	}
-	return res, nil
+	return res[:n], nil
 }
 
 func (lcm *LocalChunkManager) Mmap(ctx context.Context, filePath string) (*mmap.ReaderAt, error) {
@@ -224,6 +226,18 @@ func (lcm *LocalChunkManager) Size(ctx context.Context, filePath string) (int64,
 	return size, nil
 }
 
+// Etag returns the etag of the file by its size and modification time, as the local files have no etag.
+func (lcm *LocalChunkManager) Etag(ctx context.Context, filePath string) (string, error) {
+	fi, err := os.Stat(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", merr.WrapErrIoKeyNotFound(filePath, err.Error())
+		}
+		return "", merr.WrapErrIoFailed(filePath, err)
+	}
+	return fmt.Sprintf("%x-%x", fi.Size(), fi.ModTime().UnixNano()), nil
+}
+
 func (lcm *LocalChunkManager) Remove(ctx context.Context, filePath string) error {
 	err := os.RemoveAll(filePath)
 	return merr.WrapErrIoFailed(filePath, err)
@@ -255,6 +269,22 @@ func (lcm *LocalChunkManager) RemoveWithPrefix(ctx context.Context, prefix strin
 	return lcm.MultiRemove(ctx, filePaths)
 }
 
+func (lcm *LocalChunkManager) listSizesWithPrefix(ctx context.Context, prefix string, recursive bool) ([]string, []int64, []time.Time, error) {
+	filePaths, modTimes, err := lcm.ListWithPrefix(ctx, prefix, recursive)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sizes := make([]int64, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		size, err := lcm.Size(ctx, filePath)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		sizes = append(sizes, size)
+	}
+	return filePaths, sizes, modTimes, nil
+}
+
 func (lcm *LocalChunkManager) getModTime(filepath string) (time.Time, error) {
 	fi, err := os.Stat(filepath)
 	if err != nil {
@@ -24,6 +24,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
 )
 
 const (
@@ -330,6 +332,12 @@ func TestLocalCM(t *testing.T) {
 		assert.NoError(t, err)
 		assert.ElementsMatch(t, partial, value[off:off+length])
 
+		// truncated at the end of the file
+		off, length = 5, int64(len(value))
+		partial, err = testCM.ReadAt(ctx, key, off, length)
+		assert.NoError(t, err)
+		assert.Equal(t, value[off:], partial)
+
 		// error case
 		off, length = 5, -2
 		_, err = testCM.ReadAt(ctx, key, off, length)
@@ -373,6 +381,26 @@ func TestLocalCM(t *testing.T) {
 		assert.Equal(t, int64(0), size)
 	})
 
+	t.Run("test Etag", func(t *testing.T) {
+		testCM := NewLocalChunkManager(RootPath(localPath))
+		defer testCM.RemoveWithPrefix(ctx, testCM.RootPath())
+
+		key := path.Join(localPath, "get_etag", "key")
+		require.NoError(t, testCM.Write(ctx, key, []byte("value")))
+		etag, err := testCM.Etag(ctx, key)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, etag)
+
+		// the etag changes once the file is rewritten
+		require.NoError(t, testCM.Write(ctx, key, []byte("value2")))
+		etag2, err := testCM.Etag(ctx, key)
+		assert.NoError(t, err)
+		assert.NotEqual(t, etag, etag2)
+
+		_, err = testCM.Etag(ctx, path.Join(localPath, "get_etag", "not_exist"))
+		assert.ErrorIs(t, err, merr.ErrIoKeyNotFound)
+	})
+
 	t.Run("test read", func(t *testing.T) {
 		testGetSizeRoot := "get_path"
 
@@ -0,0 +1,161 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"path"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// MmapAdvice is the hint of the access pattern of a mapped file to the kernel.
+type MmapAdvice int
+
+const (
+	MmapAdviceNormal MmapAdvice = iota
+	// MmapAdviceSequential reads ahead aggressively and drops the pages read soon, i.e. for the compaction scans
+	MmapAdviceSequential
+	MmapAdviceRandom
+	// MmapAdviceWillNeed reads the whole file ahead, i.e. for the files deserialized as a whole once mapped
+	MmapAdviceWillNeed
+)
+
+// MappedFile is a local file mapped into memory read only, the bytes are backed by the page cache
+// rather than copied, and are invalid once released, the ones still referenced must be copied.
+type MappedFile struct {
+	path    string
+	data    []byte
+	release sync.Once
+	err     error
+}
+
+// Bytes returns the content of the file, which must not be modified nor referenced once released.
+func (f *MappedFile) Bytes() []byte {
+	return f.data
+}
+
+// Len returns the size of the file.
+func (f *MappedFile) Len() int {
+	return len(f.data)
+}
+
+// ReadAt copies length bytes from the offset, truncated at the end of the file as LocalChunkManager.ReadAt,
+// so the bytes returned are still valid once the file released.
+func (f *MappedFile) ReadAt(off int64, length int64) ([]byte, error) {
+	if off < 0 || length < 0 {
+		return nil, merr.WrapErrParameterInvalidMsg("invalid range [%d, %d) of %s", off, off+length, f.path)
+	}
+	if off >= int64(len(f.data)) {
+		return []byte{}, nil
+	}
+	end := off + length
+	if end > int64(len(f.data)) {
+		end = int64(len(f.data))
+	}
+	res := make([]byte, end-off)
+	copy(res, f.data[off:end])
+	return res, nil
+}
+
+// Release unmaps the file, it's safe to be called more than once.
+func (f *MappedFile) Release() error {
+	f.release.Do(func() {
+		if len(f.data) > 0 {
+			f.err = munmapFile(f.data)
+		}
+		f.data = nil
+	})
+	return f.err
+}
+
+// MappedReader is implemented by the ChunkManagers reading the files by mmap.
+type MappedReader interface {
+	// ReadMapped maps the file into memory with the advice, the MappedFile must be released once it's not used.
+	ReadMapped(ctx context.Context, filePath string, advice MmapAdvice) (*MappedFile, error)
+}
+
+// AsMappedReader returns the MappedReader of the chunk manager, false if the chunk manager doesn't read by mmap
+// or the mmap reads are disabled by localStorage.mmapRead.enabled.
+func AsMappedReader(cm ChunkManager) (MappedReader, bool) {
+	if !paramtable.Get().LocalStorageCfg.MmapReadEnabled.GetAsBool() {
+		return nil, false
+	}
+	reader, ok := cm.(MappedReader)
+	return reader, ok
+}
+
+func (lcm *LocalChunkManager) ReadMapped(ctx context.Context, filePath string, advice MmapAdvice) (*MappedFile, error) {
+	filePath = path.Clean(filePath)
+	file, err := Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, merr.WrapErrIoFailed(filePath, err)
+	}
+	if info.IsDir() {
+		return nil, merr.WrapErrIoFailed(filePath, errors.New("is a directory"))
+	}
+	// the empty files can't be mapped
+	if info.Size() == 0 {
+		return &MappedFile{path: filePath}, nil
+	}
+	data, err := mmapFile(file, int(info.Size()), advice)
+	if err != nil {
+		return nil, merr.WrapErrIoFailed(filePath, err)
+	}
+	return &MappedFile{path: filePath, data: data}, nil
+}
+
+// MultiReadMapped reads the files by mmap if the chunk manager reads by mmap, or by MultiRead otherwise.
+// The contents returned are invalid once released, so the release must be called after they're deserialized.
+func MultiReadMapped(ctx context.Context, cm ChunkManager, filePaths []string, advice MmapAdvice) ([][]byte, func(), error) {
+	reader, ok := AsMappedReader(cm)
+	if !ok {
+		values, err := cm.MultiRead(ctx, filePaths)
+		return values, func() {}, err
+	}
+
+	files := make([]*MappedFile, 0, len(filePaths))
+	release := func() {
+		for _, file := range files {
+			if err := file.Release(); err != nil {
+				log.Warn("failed to release mapped file", zap.String("path", file.path), zap.Error(err))
+			}
+		}
+	}
+	values := make([][]byte, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		file, err := reader.ReadMapped(ctx, filePath, advice)
+		if err != nil {
+			release()
+			return nil, func() {}, err
+		}
+		files = append(files, file)
+		values = append(values, file.Bytes())
+	}
+	return values, release, nil
+}
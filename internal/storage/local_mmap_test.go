@@ -0,0 +1,104 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestLocalReadMapped(t *testing.T) {
+	ctx := context.Background()
+	rootPath := t.TempDir()
+	lcm := NewLocalChunkManager(RootPath(rootPath))
+	key := path.Join(rootPath, "a")
+	empty := path.Join(rootPath, "empty")
+	require.NoError(t, lcm.MultiWrite(ctx, map[string][]byte{key: []byte("0123456789"), empty: {}}))
+
+	for _, advice := range []MmapAdvice{MmapAdviceNormal, MmapAdviceSequential, MmapAdviceRandom, MmapAdviceWillNeed} {
+		file, err := lcm.ReadMapped(ctx, key, advice)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("0123456789"), file.Bytes())
+		assert.Equal(t, 10, file.Len())
+
+		value, err := file.ReadAt(2, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("234"), value)
+		tail, err := file.ReadAt(8, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("89"), tail)
+		value, err = file.ReadAt(10, 5)
+		assert.NoError(t, err)
+		assert.Empty(t, value)
+		_, err = file.ReadAt(-1, 5)
+		assert.Error(t, err)
+
+		assert.NoError(t, file.Release())
+		assert.NoError(t, file.Release())
+		assert.Nil(t, file.Bytes())
+		// the copies are valid once released
+		assert.Equal(t, []byte("89"), tail)
+	}
+
+	file, err := lcm.ReadMapped(ctx, empty, MmapAdviceNormal)
+	require.NoError(t, err)
+	assert.Empty(t, file.Bytes())
+	assert.NoError(t, file.Release())
+
+	_, err = lcm.ReadMapped(ctx, path.Join(rootPath, "not_exist"), MmapAdviceNormal)
+	assert.ErrorIs(t, err, merr.ErrIoKeyNotFound)
+	_, err = lcm.ReadMapped(ctx, rootPath, MmapAdviceNormal)
+	assert.Error(t, err)
+}
+
+func TestMultiReadMapped(t *testing.T) {
+	ctx := context.Background()
+	paramtable.Init()
+	params := paramtable.Get()
+	rootPath := t.TempDir()
+	lcm := NewLocalChunkManager(RootPath(rootPath))
+	keys := []string{path.Join(rootPath, "a"), path.Join(rootPath, "b")}
+	require.NoError(t, lcm.MultiWrite(ctx, map[string][]byte{keys[0]: []byte("a"), keys[1]: []byte("bb")}))
+
+	_, ok := AsMappedReader(lcm)
+	assert.True(t, ok)
+	values, release, err := MultiReadMapped(ctx, lcm, keys, MmapAdviceWillNeed)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("bb")}, values)
+	release()
+	release()
+
+	_, _, err = MultiReadMapped(ctx, lcm, append(keys, path.Join(rootPath, "not_exist")), MmapAdviceWillNeed)
+	assert.Error(t, err)
+
+	// read by copies once disabled, or by the chunk managers not reading by mmap
+	params.Save(params.LocalStorageCfg.MmapReadEnabled.Key, "false")
+	defer params.Reset(params.LocalStorageCfg.MmapReadEnabled.Key)
+	_, ok = AsMappedReader(lcm)
+	assert.False(t, ok)
+	values, release, err = MultiReadMapped(ctx, lcm, keys, MmapAdviceWillNeed)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("bb")}, values)
+	release()
+}
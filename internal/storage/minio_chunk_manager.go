@@ -45,9 +45,10 @@ type MinioChunkManager struct {
 	//	ctx        context.Context
 	bucketName string
 	rootPath   string
+	policies   *objectPolicies
 }
 
-var _ ChunkManager = (*MinioChunkManager)(nil)
+var _ EtagChunkManager = (*MinioChunkManager)(nil)
 
 // NewMinioChunkManager create a new local manager object.
 // Deprecated: Do not call this directly! Use factory.NewPersistentStorageChunkManager instead.
@@ -61,6 +62,10 @@ func NewMinioChunkManager(ctx context.Context, opts ...Option) (*MinioChunkManag
 }
 
 func newMinioChunkManagerWithConfig(ctx context.Context, c *config) (*MinioChunkManager, error) {
+	policies, err := newObjectPolicies(c.objectPolicy, c.objectCategoryPolicies)
+	if err != nil {
+		return nil, err
+	}
 	minIOClient, err := newMinioClient(ctx, c)
 	if err != nil {
 		return nil, err
@@ -68,6 +73,7 @@ func newMinioChunkManagerWithConfig(ctx context.Context, c *config) (*MinioChunk
 	mcm := &MinioChunkManager{
 		Client:     minIOClient,
 		bucketName: c.bucketName,
+		policies:   policies,
 	}
 	mcm.rootPath = mcm.normalizeRootPath(c.rootPath)
 	log.Info("minio chunk manager init success.", zap.String("bucketname", c.bucketName), zap.String("root", mcm.RootPath()))
@@ -124,6 +130,16 @@ func (mcm *MinioChunkManager) Size(ctx context.Context, filePath string) (int64,
 	return objectInfo.Size, nil
 }
 
+func (mcm *MinioChunkManager) Etag(ctx context.Context, filePath string) (string, error) {
+	objectInfo, err := mcm.statMinioObject(ctx, mcm.bucketName, filePath, minio.StatObjectOptions{})
+	if err != nil {
+		log.Warn("failed to stat object", zap.String("bucket", mcm.bucketName), zap.String("path", filePath), zap.Error(err))
+		return "", err
+	}
+
+	return objectInfo.ETag, nil
+}
+
 // Write writes the data to minio storage.
 func (mcm *MinioChunkManager) Write(ctx context.Context, filePath string, content []byte) error {
 	_, err := mcm.putMinioObject(ctx, mcm.bucketName, filePath, bytes.NewReader(content), int64(len(content)), minio.PutObjectOptions{})
@@ -325,12 +341,18 @@ func (mcm *MinioChunkManager) RemoveWithPrefix(ctx context.Context, prefix strin
 // calling `ListWithPrefix` with `prefix` = a && `recursive` = false will only returns [a, ab]
 // If caller needs all objects without level limitation, `recursive` shall be true.
 func (mcm *MinioChunkManager) ListWithPrefix(ctx context.Context, prefix string, recursive bool) ([]string, []time.Time, error) {
+	objectsKeys, _, modTimes, err := mcm.listSizesWithPrefix(ctx, prefix, recursive)
+	return objectsKeys, modTimes, err
+}
+
+func (mcm *MinioChunkManager) listSizesWithPrefix(ctx context.Context, prefix string, recursive bool) ([]string, []int64, []time.Time, error) {
 	// cannot use ListObjects(ctx, bucketName, Opt{Prefix:prefix, Recursive:true})
 	// if minio has lots of objects under the provided path
 	// recursive = true may timeout during the recursive browsing the objects.
 	// See also: https://github.com/milvus-io/milvus/issues/19095
 
 	var objectsKeys []string
+	var sizes []int64
 	var modTimes []time.Time
 
 	tasks := list.New()
@@ -347,7 +369,7 @@ func (mcm *MinioChunkManager) ListWithPrefix(ctx context.Context, prefix string,
 		for object := range objects {
 			if object.Err != nil {
 				log.Warn("failed to list with prefix", zap.String("bucket", mcm.bucketName), zap.String("prefix", prefix), zap.Error(object.Err))
-				return nil, nil, object.Err
+				return nil, nil, nil, object.Err
 			}
 
 			// with tailing "/", object is a "directory"
@@ -359,11 +381,12 @@ func (mcm *MinioChunkManager) ListWithPrefix(ctx context.Context, prefix string,
 				continue
 			}
 			objectsKeys = append(objectsKeys, object.Key)
+			sizes = append(sizes, object.Size)
 			modTimes = append(modTimes, object.LastModified)
 		}
 	}
 
-	return objectsKeys, modTimes, nil
+	return objectsKeys, sizes, modTimes, nil
 }
 
 // Learn from file.ReadFile
@@ -410,6 +433,7 @@ func (mcm *MinioChunkManager) putMinioObject(ctx context.Context, bucketName, ob
 ) (minio.UploadInfo, error) {
 	start := timerecord.NewTimeRecorder("putMinioObject")
 
+	mcm.policies.apply(objectName, &opts)
 	info, err := mcm.Client.PutObject(ctx, bucketName, objectName, reader, objectSize, opts)
 	metrics.PersistentDataOpCounter.WithLabelValues(metrics.DataPutLabel, metrics.TotalLabel).Inc()
 	if err != nil {
@@ -26,6 +26,8 @@ import (
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/samber/lo"
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus/internal/storage/aliyun"
@@ -37,6 +39,8 @@ import (
 
 type MinioObjectStorage struct {
 	*minio.Client
+	uploader *uploader
+	policies *objectPolicies
 }
 
 func newMinioClient(ctx context.Context, c *config) (*minio.Client, error) {
@@ -56,18 +60,18 @@ func newMinioClient(ctx context.Context, c *config) (*minio.Client, error) {
 		if c.useIAM {
 			newMinioFn = aliyun.NewMinioClient
 		} else {
-			creds = credentials.NewStaticV4(c.accessKeyID, c.secretAccessKeyID, "")
+			creds = c.newCredentials(newStaticV4)
 		}
 	case CloudProviderGCP:
 		newMinioFn = gcp.NewMinioClient
 		if !c.useIAM {
-			creds = credentials.NewStaticV2(c.accessKeyID, c.secretAccessKeyID, "")
+			creds = c.newCredentials(newStaticV2)
 		}
 	case CloudProviderTencent:
 		bucketLookupType = minio.BucketLookupDNS
 		newMinioFn = tencent.NewMinioClient
 		if !c.useIAM {
-			creds = credentials.NewStaticV4(c.accessKeyID, c.secretAccessKeyID, "")
+			creds = c.newCredentials(newStaticV4)
 		}
 
 	default: // aws, minio
@@ -82,7 +86,7 @@ func newMinioClient(ctx context.Context, c *config) (*minio.Client, error) {
 		case strings.Contains(c.address, gcp.GcsDefaultAddress):
 			newMinioFn = gcp.NewMinioClient
 			if !c.useIAM {
-				creds = credentials.NewStaticV2(c.accessKeyID, c.secretAccessKeyID, "")
+				creds = c.newCredentials(newStaticV2)
 			}
 		case strings.Contains(c.address, aliyun.OSSAddressFeatureString):
 			// auto doesn't work for aliyun, so we set to dns deliberately
@@ -90,7 +94,7 @@ func newMinioClient(ctx context.Context, c *config) (*minio.Client, error) {
 			if c.useIAM {
 				newMinioFn = aliyun.NewMinioClient
 			} else {
-				creds = credentials.NewStaticV4(c.accessKeyID, c.secretAccessKeyID, "")
+				creds = c.newCredentials(newStaticV4)
 			}
 		default:
 			matchedDefault = true
@@ -100,9 +104,9 @@ func newMinioClient(ctx context.Context, c *config) (*minio.Client, error) {
 	if matchedDefault {
 		// aws, minio
 		if c.useIAM {
-			creds = credentials.NewIAM("")
+			creds = c.newCredentials(newIAM)
 		} else {
-			creds = credentials.NewStaticV4(c.accessKeyID, c.secretAccessKeyID, "")
+			creds = c.newCredentials(newStaticV4)
 		}
 	}
 	minioOpts := &minio.Options{
@@ -146,11 +150,15 @@ func newMinioClient(ctx context.Context, c *config) (*minio.Client, error) {
 }
 
 func newMinioObjectStorageWithConfig(ctx context.Context, c *config) (*MinioObjectStorage, error) {
+	policies, err := newObjectPolicies(c.objectPolicy, c.objectCategoryPolicies)
+	if err != nil {
+		return nil, err
+	}
 	minIOClient, err := newMinioClient(ctx, c)
 	if err != nil {
 		return nil, err
 	}
-	return &MinioObjectStorage{minIOClient}, nil
+	return &MinioObjectStorage{Client: minIOClient, uploader: newUploader(c), policies: policies}, nil
 }
 
 func (minioObjectStorage *MinioObjectStorage) GetObject(ctx context.Context, bucketName, objectName string, offset int64, size int64) (FileReader, error) {
@@ -170,7 +178,10 @@ func (minioObjectStorage *MinioObjectStorage) GetObject(ctx context.Context, buc
 }
 
 func (minioObjectStorage *MinioObjectStorage) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64) error {
-	_, err := minioObjectStorage.Client.PutObject(ctx, bucketName, objectName, reader, objectSize, minio.PutObjectOptions{})
+	opts := minioObjectStorage.uploader.options(objectSize)
+	minioObjectStorage.policies.apply(objectName, &opts)
+	_, err := minioObjectStorage.Client.PutObject(ctx, bucketName, objectName,
+		minioObjectStorage.uploader.wrap(ctx, reader), objectSize, opts)
 	return checkObjectStorageError(objectName, err)
 }
 
@@ -179,8 +190,20 @@ func (minioObjectStorage *MinioObjectStorage) StatObject(ctx context.Context, bu
 	return info.Size, checkObjectStorageError(objectName, err)
 }
 
+func (minioObjectStorage *MinioObjectStorage) EtagObject(ctx context.Context, bucketName, objectName string) (string, error) {
+	info, err := minioObjectStorage.Client.StatObject(ctx, bucketName, objectName, minio.StatObjectOptions{})
+	return info.ETag, checkObjectStorageError(objectName, err)
+}
+
 func (minioObjectStorage *MinioObjectStorage) ListObjects(ctx context.Context, bucketName string, prefix string, recursive bool) ([]string, []time.Time, error) {
+	objectsKeys, _, modTimes, err := minioObjectStorage.ListObjectSizes(ctx, bucketName, prefix, recursive)
+	return objectsKeys, modTimes, err
+}
+
+// ListObjectSizes lists the objects like ListObjects, along with the sizes of them.
+func (minioObjectStorage *MinioObjectStorage) ListObjectSizes(ctx context.Context, bucketName string, prefix string, recursive bool) ([]string, []int64, []time.Time, error) {
 	var objectsKeys []string
+	var sizes []int64
 	var modTimes []time.Time
 	tasks := list.New()
 	tasks.PushBack(prefix)
@@ -194,15 +217,15 @@ func (minioObjectStorage *MinioObjectStorage) ListObjects(ctx context.Context, b
 			Recursive: false,
 		})
 
-		objects := map[string]time.Time{}
+		objects := map[string]minio.ObjectInfo{}
 		for object := range res {
 			if object.Err != nil {
 				log.Warn("failed to list with prefix", zap.String("bucket", bucketName), zap.String("prefix", prefix), zap.Error(object.Err))
-				return []string{}, []time.Time{}, object.Err
+				return []string{}, []int64{}, []time.Time{}, object.Err
 			}
-			objects[object.Key] = object.LastModified
+			objects[object.Key] = object
 		}
-		for object, lastModified := range objects {
+		for object, info := range objects {
 			// with tailing "/", object is a "directory"
 			if strings.HasSuffix(object, "/") && recursive {
 				// enqueue when recursive is true
@@ -212,12 +235,55 @@ func (minioObjectStorage *MinioObjectStorage) ListObjects(ctx context.Context, b
 				continue
 			}
 			objectsKeys = append(objectsKeys, object)
-			modTimes = append(modTimes, lastModified)
+			sizes = append(sizes, info.Size)
+			modTimes = append(modTimes, info.LastModified)
 		}
 	}
-	return objectsKeys, modTimes, nil
+	return objectsKeys, sizes, modTimes, nil
 }
 
 func (minioObjectStorage *MinioObjectStorage) RemoveObject(ctx context.Context, bucketName, objectName string) error {
 	return minioObjectStorage.Client.RemoveObject(ctx, bucketName, objectName, minio.RemoveObjectOptions{})
 }
+
+// bucketLifecycle returns the lifecycle configuration of the bucket, empty if not configured.
+func (minioObjectStorage *MinioObjectStorage) bucketLifecycle(ctx context.Context, bucketName string) (*lifecycle.Configuration, error) {
+	config, err := minioObjectStorage.Client.GetBucketLifecycle(ctx, bucketName)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchLifecycleConfiguration" {
+			return lifecycle.NewConfiguration(), nil
+		}
+		return nil, err
+	}
+	return config, nil
+}
+
+func (minioObjectStorage *MinioObjectStorage) GetLifecycleRules(ctx context.Context, bucketName string) ([]LifecycleRule, error) {
+	config, err := minioObjectStorage.bucketLifecycle(ctx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	var rules []LifecycleRule
+	for _, s3Rule := range config.Rules {
+		if rule, ok := lifecycleRuleOfS3(s3Rule); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (minioObjectStorage *MinioObjectStorage) SetLifecycleRules(ctx context.Context, bucketName string, rules []LifecycleRule) error {
+	config, err := minioObjectStorage.bucketLifecycle(ctx, bucketName)
+	if err != nil {
+		return err
+	}
+	config.Rules = lo.Filter(config.Rules, func(s3Rule lifecycle.Rule, _ int) bool {
+		_, ok := lifecycleRuleOfS3(s3Rule)
+		return !ok
+	})
+	for _, rule := range rules {
+		config.Rules = append(config.Rules, s3LifecycleRule(rule))
+	}
+	// the lifecycle configuration of no rules is removed by minio-go
+	return minioObjectStorage.Client.SetBucketLifecycle(ctx, bucketName, config)
+}
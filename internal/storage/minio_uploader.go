@@ -0,0 +1,165 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus/pkg/util/ratelimitutil"
+)
+
+const (
+	// minUploadPartSize and maxUploadPartSize are the part size bounds of S3 multipart uploads.
+	minUploadPartSize = 5 << 20
+	maxUploadPartSize = 5 << 30
+	// maxUploadParts is the maximum number of parts of a single multipart upload.
+	maxUploadParts = 10000
+
+	defaultUploadConcurrency = 4
+	defaultUploadThreshold   = 16 << 20
+
+	uploadWaitInterval = 10 * time.Millisecond
+)
+
+// uploader decides how objects are put to object storage. Large objects are
+// uploaded with concurrent parts, sized adaptively to the object unless a
+// fixed part size is configured, and all uploads share a bandwidth limit.
+type uploader struct {
+	partSize    int64
+	concurrency int
+	threshold   int64
+	limiter     *ratelimitutil.Limiter
+}
+
+func newUploader(c *config) *uploader {
+	u := &uploader{
+		partSize:    c.uploadPartSize,
+		concurrency: c.uploadConcurrency,
+		threshold:   c.uploadThreshold,
+	}
+	if u.concurrency <= 0 {
+		u.concurrency = defaultUploadConcurrency
+	}
+	if u.threshold <= 0 {
+		u.threshold = defaultUploadThreshold
+	}
+	if c.uploadBandwidthLimitMB > 0 {
+		rate := c.uploadBandwidthLimitMB * 1024 * 1024
+		u.limiter = ratelimitutil.NewLimiter(ratelimitutil.Limit(rate), rate)
+	}
+	return u
+}
+
+// options returns the put options of an object of the given size, a negative
+// size means unknown.
+func (u *uploader) options(objectSize int64) minio.PutObjectOptions {
+	opts := minio.PutObjectOptions{}
+	if objectSize >= 0 && objectSize <= u.threshold {
+		// a single part upload, set the part size above the object size
+		// so that minio-go never splits it.
+		opts.PartSize = uint64(lo.Clamp(objectSize, minUploadPartSize, maxUploadPartSize))
+		return opts
+	}
+	opts.NumThreads = uint(u.concurrency)
+	opts.PartSize = uint64(u.partSizeOf(objectSize))
+	return opts
+}
+
+// partSizeOf returns the part size of a multipart upload of an object, so that
+// the object spreads over all concurrent uploads within the part limits.
+func (u *uploader) partSizeOf(objectSize int64) int64 {
+	partSize := u.partSize
+	if partSize <= 0 {
+		if objectSize < 0 {
+			// unknown size, leave the choice to minio-go
+			return 0
+		}
+		partSize = (objectSize + int64(u.concurrency) - 1) / int64(u.concurrency)
+	}
+	if objectSize > 0 {
+		partSize = lo.Max([]int64{partSize, (objectSize + maxUploadParts - 1) / maxUploadParts})
+	}
+	return lo.Clamp(partSize, minUploadPartSize, maxUploadPartSize)
+}
+
+// wrap applies the bandwidth limit to the reader of an upload.
+func (u *uploader) wrap(ctx context.Context, reader io.Reader) io.Reader {
+	if u.limiter == nil {
+		return reader
+	}
+	limited := &limitedReader{ctx: ctx, reader: reader, limiter: u.limiter}
+	// keep io.ReaderAt and io.Seeker, minio-go uploads the parts of a ReaderAt
+	// in parallel and retries a single part upload only if it can seek back.
+	if readSeekerAt, ok := reader.(readSeekerAt); ok {
+		return &limitedReadSeekerAt{limitedReader: limited, readSeekerAt: readSeekerAt}
+	}
+	return limited
+}
+
+type limitedReader struct {
+	ctx     context.Context
+	reader  io.Reader
+	limiter *ratelimitutil.Limiter
+}
+
+func (r *limitedReader) wait(n int) error {
+	for !r.limiter.AllowN(time.Now(), n) {
+		select {
+		case <-r.ctx.Done():
+			return r.ctx.Err()
+		case <-time.After(uploadWaitInterval):
+		}
+	}
+	return nil
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if err := r.wait(len(p)); err != nil {
+		return 0, err
+	}
+	n, err := r.reader.Read(p)
+	r.limiter.Cancel(len(p) - n)
+	return n, err
+}
+
+type readSeekerAt interface {
+	io.ReadSeeker
+	io.ReaderAt
+}
+
+type limitedReadSeekerAt struct {
+	*limitedReader
+	readSeekerAt readSeekerAt
+}
+
+func (r *limitedReadSeekerAt) ReadAt(p []byte, off int64) (int, error) {
+	if err := r.wait(len(p)); err != nil {
+		return 0, err
+	}
+	n, err := r.readSeekerAt.ReadAt(p, off)
+	r.limiter.Cancel(len(p) - n)
+	return n, err
+}
+
+func (r *limitedReadSeekerAt) Seek(offset int64, whence int) (int64, error) {
+	return r.readSeekerAt.Seek(offset, whence)
+}
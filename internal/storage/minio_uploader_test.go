@@ -0,0 +1,111 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploaderOptions(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		u := newUploader(newDefaultConfig())
+		assert.Equal(t, defaultUploadConcurrency, u.concurrency)
+		assert.EqualValues(t, defaultUploadThreshold, u.threshold)
+		assert.Nil(t, u.limiter)
+	})
+
+	t.Run("small object", func(t *testing.T) {
+		u := newUploader(newDefaultConfig())
+		opts := u.options(1024)
+		assert.EqualValues(t, 0, opts.NumThreads)
+		assert.EqualValues(t, minUploadPartSize, opts.PartSize)
+
+		opts = u.options(defaultUploadThreshold)
+		assert.EqualValues(t, defaultUploadThreshold, opts.PartSize)
+	})
+
+	t.Run("adaptive part size", func(t *testing.T) {
+		u := newUploader(&config{uploadConcurrency: 8})
+		opts := u.options(256 << 20)
+		assert.EqualValues(t, 8, opts.NumThreads)
+		assert.EqualValues(t, 32<<20, opts.PartSize)
+
+		// parts never go below the minimum part size
+		assert.EqualValues(t, minUploadPartSize, u.partSizeOf(20<<20))
+		// unknown size is left to minio-go
+		assert.EqualValues(t, 0, u.options(-1).PartSize)
+	})
+
+	t.Run("fixed part size", func(t *testing.T) {
+		u := newUploader(&config{uploadPartSize: 8 << 20, uploadThreshold: 1 << 20})
+		assert.EqualValues(t, 8<<20, u.options(256<<20).PartSize)
+		assert.EqualValues(t, 8<<20, u.options(-1).PartSize)
+		// parts grow to keep within the maximum number of parts
+		assert.EqualValues(t, 10<<20, u.partSizeOf(maxUploadParts*10<<20))
+
+		u = newUploader(&config{uploadPartSize: 1 << 20})
+		assert.EqualValues(t, minUploadPartSize, u.partSizeOf(256<<20))
+	})
+}
+
+func TestUploaderWrap(t *testing.T) {
+	ctx := context.Background()
+	content := []byte(strings.Repeat("a", 4096))
+
+	u := newUploader(newDefaultConfig())
+	reader := bytes.NewReader(content)
+	assert.Same(t, reader, u.wrap(ctx, reader))
+
+	u = newUploader(&config{uploadBandwidthLimitMB: 1})
+	assert.NotNil(t, u.limiter)
+
+	wrapped := u.wrap(ctx, bytes.NewReader(content))
+	_, ok := wrapped.(readSeekerAt)
+	assert.True(t, ok)
+	data, err := io.ReadAll(wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, content, data)
+
+	buf := make([]byte, 16)
+	n, err := wrapped.(io.ReaderAt).ReadAt(buf, 4090)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, 6, n)
+
+	wrapped = u.wrap(ctx, bytes.NewBuffer(content))
+	_, ok = wrapped.(io.ReaderAt)
+	assert.False(t, ok)
+	data, err = io.ReadAll(wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, content, data)
+
+	// waiting for bandwidth is canceled with the context
+	u = newUploader(&config{uploadBandwidthLimitMB: 0.001})
+	cctx, cancel := context.WithCancel(ctx)
+	wrapped = u.wrap(cctx, bytes.NewBuffer(content))
+	n, err = wrapped.Read(make([]byte, 4096))
+	assert.NoError(t, err)
+	assert.Equal(t, 4096, n)
+	cancel()
+	_, err = wrapped.Read(make([]byte, 4096))
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,48 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package storage
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+var madviseFlags = map[MmapAdvice]int{
+	MmapAdviceNormal:     unix.MADV_NORMAL,
+	MmapAdviceSequential: unix.MADV_SEQUENTIAL,
+	MmapAdviceRandom:     unix.MADV_RANDOM,
+	MmapAdviceWillNeed:   unix.MADV_WILLNEED,
+}
+
+// mmapFile maps the size bytes of the file read only, the advice is a hint only so its failure is ignored.
+func mmapFile(file *os.File, size int, advice MmapAdvice) ([]byte, error) {
+	data, err := unix.Mmap(int(file.Fd()), 0, size, unix.PROT_READ, unix.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	if flag, ok := madviseFlags[advice]; ok && advice != MmapAdviceNormal {
+		_ = unix.Madvise(data, flag)
+	}
+	return data, nil
+}
+
+func munmapFile(data []byte) error {
+	return unix.Munmap(data)
+}
@@ -0,0 +1,210 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// The server-side encryptions of the objects written to the S3 compatible storages.
+const (
+	SSES3  = "SSE-S3"
+	SSEKMS = "SSE-KMS"
+)
+
+// readableStorageClasses are the storage classes of the objects which could be read without a restore,
+// GLACIER and DEEP_ARCHIVE are not among them as the segments must stay loadable.
+var readableStorageClasses = typeutil.NewSet("STANDARD", "REDUCED_REDUNDANCY", "STANDARD_IA", "ONEZONE_IA",
+	"INTELLIGENT_TIERING", "GLACIER_IR")
+
+// ObjectPolicy is the server-side encryption, the storage class and the tags of the objects written.
+type ObjectPolicy struct {
+	// SSE is the server-side encryption, SSE-S3 or SSE-KMS, not encrypted if empty.
+	SSE string `json:"sse,omitempty"`
+	// KMSKeyID is the key of SSE-KMS, the default key of the bucket is used if empty.
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+	// StorageClass is the storage class, the default class of the bucket is used if empty.
+	StorageClass string            `json:"storageClass,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+}
+
+// IsEmpty returns whether the policy sets nothing, by which the objects are written as the bucket defaults.
+func (p ObjectPolicy) IsEmpty() bool {
+	return p.SSE == "" && p.KMSKeyID == "" && p.StorageClass == "" && len(p.Tags) == 0
+}
+
+// Tagging returns the tags in the form of the x-amz-tagging header, e.g. team=search&tier=hot.
+func (p ObjectPolicy) Tagging() string {
+	values := url.Values{}
+	for k, v := range p.Tags {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
+
+// overrideBy returns the policy overridden by the non empty fields of the other one, the tags are merged.
+func (p ObjectPolicy) overrideBy(other ObjectPolicy) ObjectPolicy {
+	if other.SSE != "" {
+		p.SSE, p.KMSKeyID = other.SSE, other.KMSKeyID
+	}
+	if other.StorageClass != "" {
+		p.StorageClass = other.StorageClass
+	}
+	if len(other.Tags) > 0 {
+		tags := make(map[string]string, len(p.Tags)+len(other.Tags))
+		for k, v := range p.Tags {
+			tags[k] = v
+		}
+		for k, v := range other.Tags {
+			tags[k] = v
+		}
+		p.Tags = tags
+	}
+	return p
+}
+
+// putOptions is the put options of an ObjectPolicy validated.
+type putOptions struct {
+	sse          encrypt.ServerSide
+	storageClass string
+	tags         map[string]string
+}
+
+func newPutOptions(policy ObjectPolicy) (*putOptions, error) {
+	opts := &putOptions{storageClass: strings.ToUpper(policy.StorageClass), tags: policy.Tags}
+	switch strings.ToUpper(policy.SSE) {
+	case "":
+		if policy.KMSKeyID != "" {
+			return nil, fmt.Errorf("kms key id is set without %s", SSEKMS)
+		}
+	case SSES3:
+		opts.sse = encrypt.NewSSE()
+	case SSEKMS:
+		sse, err := encrypt.NewSSEKMS(policy.KMSKeyID, nil)
+		if err != nil {
+			return nil, err
+		}
+		opts.sse = sse
+	default:
+		return nil, fmt.Errorf("unsupported server-side encryption: %s, only %s and %s are supported", policy.SSE, SSES3, SSEKMS)
+	}
+	if opts.storageClass != "" && !readableStorageClasses.Contain(opts.storageClass) {
+		return nil, fmt.Errorf("unsupported storage class: %s, the objects must be readable without a restore", policy.StorageClass)
+	}
+	return opts, nil
+}
+
+// objectPolicies applies the ObjectPolicy of the path category of an object to its put options.
+// The category is the first path element of the object name among the categories configured,
+// e.g. insert_log, delta_log and stats_log, the default policy is applied if none is.
+type objectPolicies struct {
+	defaultOptions *putOptions
+	categories     map[string]*putOptions
+}
+
+// newObjectPolicies validates the default policy and the policies of the path categories in json,
+// which override the default one, e.g. {"insert_log": {"storageClass": "STANDARD_IA"}}.
+func newObjectPolicies(defaultPolicy ObjectPolicy, categoryPolicies string) (*objectPolicies, error) {
+	defaultOptions, err := newPutOptions(defaultPolicy)
+	if err != nil {
+		return nil, err
+	}
+	policies := &objectPolicies{defaultOptions: defaultOptions, categories: make(map[string]*putOptions)}
+	categories, err := parseCategoryPolicies(categoryPolicies)
+	if err != nil {
+		return nil, err
+	}
+	for category, policy := range categories {
+		opts, err := newPutOptions(defaultPolicy.overrideBy(policy))
+		if err != nil {
+			return nil, fmt.Errorf("invalid object policy of %s: %w", category, err)
+		}
+		policies.categories[category] = opts
+	}
+	return policies, nil
+}
+
+func parseCategoryPolicies(categoryPolicies string) (map[string]ObjectPolicy, error) {
+	categories := make(map[string]ObjectPolicy)
+	if categoryPolicies == "" {
+		return categories, nil
+	}
+	if err := json.Unmarshal([]byte(categoryPolicies), &categories); err != nil {
+		return nil, fmt.Errorf("invalid object policies of the path categories: %w", err)
+	}
+	return categories, nil
+}
+
+// IndexFilesPolicy returns the ObjectPolicy of the index files, the default policy overridden by the one of
+// the path category index_files, which the segcore puts the index files built with.
+func IndexFilesPolicy(defaultPolicy ObjectPolicy, categoryPolicies string) (ObjectPolicy, error) {
+	categories, err := parseCategoryPolicies(categoryPolicies)
+	if err != nil {
+		return ObjectPolicy{}, err
+	}
+	policy := defaultPolicy.overrideBy(categories[common.SegmentIndexPath])
+	if _, err := newPutOptions(policy); err != nil {
+		return ObjectPolicy{}, fmt.Errorf("invalid object policy of %s: %w", common.SegmentIndexPath, err)
+	}
+	policy.SSE, policy.StorageClass = strings.ToUpper(policy.SSE), strings.ToUpper(policy.StorageClass)
+	return policy, nil
+}
+
+func errObjectPoliciesNotSupported(storage string) error {
+	return fmt.Errorf("the server-side encryption, the storage class and the tags of the objects are not supported by %s", storage)
+}
+
+func (p *objectPolicies) optionsOf(objectName string) *putOptions {
+	if len(p.categories) > 0 {
+		for _, elem := range strings.Split(objectName, "/") {
+			if opts, ok := p.categories[elem]; ok {
+				return opts
+			}
+		}
+	}
+	return p.defaultOptions
+}
+
+// apply sets the encryption, the storage class and the tags of the object to the put options.
+func (p *objectPolicies) apply(objectName string, opts *minio.PutObjectOptions) {
+	if p == nil {
+		return
+	}
+	policyOpts := p.optionsOf(objectName)
+	opts.ServerSideEncryption = policyOpts.sse
+	opts.StorageClass = policyOpts.storageClass
+	if len(policyOpts.tags) == 0 {
+		return
+	}
+	tags := make(map[string]string, len(policyOpts.tags)+len(opts.UserTags))
+	for k, v := range policyOpts.tags {
+		tags[k] = v
+	}
+	for k, v := range opts.UserTags {
+		tags[k] = v
+	}
+	opts.UserTags = tags
+}
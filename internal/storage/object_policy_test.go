@@ -0,0 +1,121 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectPolicies(t *testing.T) {
+	t.Run("default policy", func(t *testing.T) {
+		policies, err := newObjectPolicies(ObjectPolicy{}, "")
+		require.NoError(t, err)
+		opts := minio.PutObjectOptions{}
+		policies.apply("files/insert_log/1/2/3/100/1", &opts)
+		assert.Nil(t, opts.ServerSideEncryption)
+		assert.Empty(t, opts.StorageClass)
+		assert.Empty(t, opts.UserTags)
+
+		policies, err = newObjectPolicies(ObjectPolicy{SSE: "sse-s3", StorageClass: "standard_ia", Tags: map[string]string{"team": "search"}}, "")
+		require.NoError(t, err)
+		opts = minio.PutObjectOptions{UserTags: map[string]string{"owner": "milvus"}}
+		policies.apply("files/delta_log/1/2/3/1", &opts)
+		assert.Equal(t, encrypt.S3, opts.ServerSideEncryption.Type())
+		assert.Equal(t, "STANDARD_IA", opts.StorageClass)
+		assert.Equal(t, map[string]string{"team": "search", "owner": "milvus"}, opts.UserTags)
+	})
+
+	t.Run("category policies", func(t *testing.T) {
+		policies, err := newObjectPolicies(ObjectPolicy{SSE: SSES3, Tags: map[string]string{"team": "search"}},
+			`{"insert_log": {"storageClass": "GLACIER_IR"}, "delta_log": {"sse": "SSE-KMS", "kmsKeyID": "key1", "tags": {"tier": "hot"}}}`)
+		require.NoError(t, err)
+
+		opts := minio.PutObjectOptions{}
+		policies.apply("files/insert_log/1/2/3/100/1", &opts)
+		assert.Equal(t, encrypt.S3, opts.ServerSideEncryption.Type())
+		assert.Equal(t, "GLACIER_IR", opts.StorageClass)
+		assert.Equal(t, map[string]string{"team": "search"}, opts.UserTags)
+
+		opts = minio.PutObjectOptions{}
+		policies.apply("files/delta_log/1/2/3/1", &opts)
+		assert.Equal(t, encrypt.KMS, opts.ServerSideEncryption.Type())
+		header := http.Header{}
+		opts.ServerSideEncryption.Marshal(header)
+		assert.Equal(t, "key1", header.Get(encrypt.SseKmsKeyID))
+		assert.Empty(t, opts.StorageClass)
+		assert.Equal(t, map[string]string{"team": "search", "tier": "hot"}, opts.UserTags)
+
+		// the objects of the other categories take the default policy
+		opts = minio.PutObjectOptions{}
+		policies.apply("files/stats_log/1/2/3/100/1", &opts)
+		assert.Equal(t, encrypt.S3, opts.ServerSideEncryption.Type())
+		assert.Empty(t, opts.StorageClass)
+	})
+
+	t.Run("invalid policies", func(t *testing.T) {
+		_, err := newObjectPolicies(ObjectPolicy{SSE: "SSE-C"}, "")
+		assert.Error(t, err)
+		_, err = newObjectPolicies(ObjectPolicy{KMSKeyID: "key1"}, "")
+		assert.Error(t, err)
+		_, err = newObjectPolicies(ObjectPolicy{StorageClass: "GLACIER"}, "")
+		assert.Error(t, err)
+		_, err = newObjectPolicies(ObjectPolicy{}, `{"insert_log": {"storageClass": "DEEP_ARCHIVE"}}`)
+		assert.Error(t, err)
+		_, err = newObjectPolicies(ObjectPolicy{}, `{"insert_log"`)
+		assert.Error(t, err)
+
+		_, err = newMinioObjectStorageWithConfig(context.Background(), &config{objectPolicy: ObjectPolicy{SSE: "SSE-C"}})
+		assert.Error(t, err)
+		_, err = newMinioChunkManagerWithConfig(context.Background(), &config{objectPolicy: ObjectPolicy{SSE: "SSE-C"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("not supported storages", func(t *testing.T) {
+		for _, provider := range []string{CloudProviderAzure, CloudProviderGCPNative} {
+			_, err := NewRemoteChunkManager(context.Background(), &config{
+				cloudProvider: provider,
+				objectPolicy:  ObjectPolicy{StorageClass: "STANDARD_IA"},
+			})
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("index files policy", func(t *testing.T) {
+		policy, err := IndexFilesPolicy(ObjectPolicy{SSE: "sse-s3", Tags: map[string]string{"owner": "milvus"}},
+			`{"index_files": {"storageClass": "standard_ia"}, "insert_log": {"storageClass": "GLACIER_IR"}}`)
+		require.NoError(t, err)
+		assert.Equal(t, SSES3, policy.SSE)
+		assert.Equal(t, "STANDARD_IA", policy.StorageClass)
+		assert.Equal(t, "owner=milvus", policy.Tagging())
+		assert.False(t, policy.IsEmpty())
+
+		policy, err = IndexFilesPolicy(ObjectPolicy{}, "")
+		require.NoError(t, err)
+		assert.True(t, policy.IsEmpty())
+		assert.Equal(t, "", policy.Tagging())
+
+		_, err = IndexFilesPolicy(ObjectPolicy{}, `{"index_files": {"sse": "SSE-C"}}`)
+		assert.Error(t, err)
+	})
+}
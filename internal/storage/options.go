@@ -1,11 +1,14 @@
 package storage
 
+import "time"
+
 // Option for setting params used by chunk manager client.
 type config struct {
 	address           string
 	bucketName        string
 	accessKeyID       string
 	secretAccessKeyID string
+	credentials       *RotatingCredentials
 	useSSL            bool
 	createBucket      bool
 	rootPath          string
@@ -15,6 +18,26 @@ type config struct {
 	useVirtualHost    bool
 	region            string
 	requestTimeoutMs  int64
+	gcpCredentialJSON string
+
+	hdfsUser            string
+	hdfsDelegationToken string
+	hdfsBlockSize       int64
+	hdfsReplication     int
+
+	uploadPartSize         int64
+	uploadConcurrency      int
+	uploadThreshold        int64
+	uploadBandwidthLimitMB float64
+
+	objectPolicy           ObjectPolicy
+	objectCategoryPolicies string
+
+	tieredPath          string
+	tieredEvictInterval time.Duration
+
+	hedgedReadPercentile float64
+	hedgedReadMinDelay   time.Duration
 }
 
 func newDefaultConfig() *config {
@@ -48,6 +71,14 @@ func SecretAccessKeyID(secretAccessKeyID string) Option {
 	}
 }
 
+// Credentials sets the credentials of the S3 compatible storages rotated at runtime, which take precedence
+// over the access keys.
+func Credentials(creds *RotatingCredentials) Option {
+	return func(c *config) {
+		c.credentials = creds
+	}
+}
+
 func UseSSL(useSSL bool) Option {
 	return func(c *config) {
 		c.useSSL = useSSL
@@ -101,3 +132,99 @@ func RequestTimeout(requestTimeoutMs int64) Option {
 		c.requestTimeoutMs = requestTimeoutMs
 	}
 }
+
+// UploadPartSize sets the part size of multipart uploads, 0 means derived from object size.
+func UploadPartSize(partSize int64) Option {
+	return func(c *config) {
+		c.uploadPartSize = partSize
+	}
+}
+
+// UploadConcurrency sets the number of parts uploaded concurrently for a single object.
+func UploadConcurrency(concurrency int) Option {
+	return func(c *config) {
+		c.uploadConcurrency = concurrency
+	}
+}
+
+// UploadThreshold sets the object size above which objects are uploaded in parts.
+func UploadThreshold(threshold int64) Option {
+	return func(c *config) {
+		c.uploadThreshold = threshold
+	}
+}
+
+// UploadBandwidthLimit sets the upload bandwidth limit in MB/s, 0 means unlimited.
+func UploadBandwidthLimit(limitMB float64) Option {
+	return func(c *config) {
+		c.uploadBandwidthLimitMB = limitMB
+	}
+}
+
+// hasObjectPolicies returns whether the encryption, the storage class or the tags of the objects are configured.
+func (c *config) hasObjectPolicies() bool {
+	return !c.objectPolicy.IsEmpty() || c.objectCategoryPolicies != ""
+}
+
+// ObjectPolicies sets the server-side encryption, the storage class and the tags of the objects written to the S3 compatible
+// storages, categoryPolicies in json overrides the default policy by the path categories, e.g. insert_log and delta_log.
+func ObjectPolicies(defaultPolicy ObjectPolicy, categoryPolicies string) Option {
+	return func(c *config) {
+		c.objectPolicy = defaultPolicy
+		c.objectCategoryPolicies = categoryPolicies
+	}
+}
+
+// GcpCredentialJSON sets the json key of the service account of the native Google Cloud Storage.
+func GcpCredentialJSON(credentialJSON string) Option {
+	return func(c *config) {
+		c.gcpCredentialJSON = credentialJSON
+	}
+}
+
+// HdfsUser sets the user of the simple authentication of the HDFS.
+func HdfsUser(user string) Option {
+	return func(c *config) {
+		c.hdfsUser = user
+	}
+}
+
+// HdfsDelegationToken sets the delegation token of the kerberized HDFS, which is the only way to access it,
+// as the kerberos login is not supported.
+func HdfsDelegationToken(token string) Option {
+	return func(c *config) {
+		c.hdfsDelegationToken = token
+	}
+}
+
+// HdfsBlockSize sets the block size of the files written to the HDFS, 0 means the default of the cluster.
+func HdfsBlockSize(blockSize int64) Option {
+	return func(c *config) {
+		c.hdfsBlockSize = blockSize
+	}
+}
+
+// HdfsReplication sets the replication of the files written to the HDFS, 0 means the default of the cluster.
+func HdfsReplication(replication int) Option {
+	return func(c *config) {
+		c.hdfsReplication = replication
+	}
+}
+
+// TieredStorage caches the files written to the persistent storage in the hot tier under hotRoot,
+// which are evicted every evictInterval.
+func TieredStorage(hotRoot string, evictInterval time.Duration) Option {
+	return func(c *config) {
+		c.tieredPath = hotRoot
+		c.tieredEvictInterval = evictInterval
+	}
+}
+
+// HedgedRead hedges the reads not responded within the percentile of the latest read latencies by a duplicate
+// of each, and takes the first response, the hedge delay is at least minDelay.
+func HedgedRead(percentile float64, minDelay time.Duration) Option {
+	return func(c *config) {
+		c.hedgedReadPercentile = percentile
+		c.hedgedReadMinDelay = minDelay
+	}
+}
@@ -0,0 +1,119 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+)
+
+// orphanRemoveBatchSize is the number of objects removed by one MultiRemove call.
+const orphanRemoveBatchSize = 100
+
+// OrphanObject is an object under the root path which is not referenced by any meta.
+type OrphanObject struct {
+	FilePath string
+	ModTime  time.Time
+	Age      time.Duration
+}
+
+// ListRootPaths returns the root path of @cm and the root paths of the storage tenants under it,
+// the binlogs of the storage tenants are under the root paths of the tenants.
+// The root path of @cm is returned along with the error if the tenants fail to be listed.
+func ListRootPaths(ctx context.Context, cm ChunkManager) ([]string, error) {
+	rootPaths := []string{cm.RootPath()}
+	tenantRootPaths, _, err := cm.ListWithPrefix(ctx, metautil.TenantRootPathPrefix(cm.RootPath()), false)
+	if err != nil {
+		return rootPaths, err
+	}
+	for _, tenantRootPath := range tenantRootPaths {
+		if tenant := metautil.GetTenantFromRootPath(tenantRootPath); tenant != "" {
+			rootPaths = append(rootPaths, metautil.TenantRootPath(cm.RootPath(), tenant))
+		}
+	}
+	return rootPaths, nil
+}
+
+// OrphanScanPrefixes returns the prefixes under @rootPath which hold segment binlogs and index files,
+// including the static dir of the path layout if any.
+func OrphanScanPrefixes(rootPath string) []string {
+	prefixes := []string{
+		path.Join(rootPath, common.SegmentInsertLogPath) + "/",
+		path.Join(rootPath, common.SegmentStatslogPath) + "/",
+		path.Join(rootPath, common.SegmentDeltaLogPath) + "/",
+		path.Join(rootPath, common.SegmentQuantizedLogPath) + "/",
+		path.Join(rootPath, common.SegmentIndexPath) + "/",
+	}
+	if staticDir := GetPathLayout().StaticDir(); staticDir != "" {
+		prefixes = append(prefixes, path.Join(rootPath, staticDir)+"/")
+	}
+	return prefixes
+}
+
+// ScanOrphanObjects lists the objects under @prefixes and returns the ones rejected by @isReferenced.
+// Objects modified less than @minAge before @now are skipped, since they may belong to an upload
+// whose meta is not persisted yet. The result is ordered by path.
+func ScanOrphanObjects(ctx context.Context, cm ChunkManager, prefixes []string,
+	isReferenced func(filePath string) bool, minAge time.Duration, now time.Time,
+) ([]*OrphanObject, error) {
+	orphans := make([]*OrphanObject, 0)
+	for _, prefix := range prefixes {
+		filePaths, modTimes, err := cm.ListWithPrefix(ctx, prefix, true)
+		if err != nil {
+			return nil, err
+		}
+		for i, filePath := range filePaths {
+			if isReferenced(filePath) {
+				continue
+			}
+			age := now.Sub(modTimes[i])
+			if age < minAge {
+				continue
+			}
+			orphans = append(orphans, &OrphanObject{
+				FilePath: filePath,
+				ModTime:  modTimes[i],
+				Age:      age,
+			})
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].FilePath < orphans[j].FilePath })
+	return orphans, nil
+}
+
+// RemoveOrphanObjects removes @orphans from @cm in batches.
+func RemoveOrphanObjects(ctx context.Context, cm ChunkManager, orphans []*OrphanObject) error {
+	for start := 0; start < len(orphans); start += orphanRemoveBatchSize {
+		end := start + orphanRemoveBatchSize
+		if end > len(orphans) {
+			end = len(orphans)
+		}
+		filePaths := make([]string, 0, end-start)
+		for _, orphan := range orphans[start:end] {
+			filePaths = append(filePaths, orphan.FilePath)
+		}
+		if err := cm.MultiRemove(ctx, filePaths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+)
+
+func TestScanOrphanObjects(t *testing.T) {
+	ctx := context.Background()
+	cm := NewLocalChunkManager(RootPath(path.Join(localPath, "orphan_scanner")))
+	defer cm.RemoveWithPrefix(ctx, cm.RootPath())
+
+	referenced := metautil.BuildInsertLogPath(cm.RootPath(), CollectionID, PartitionID, SegmentID, Int64Field, 1)
+	orphanInsert := metautil.BuildInsertLogPath(cm.RootPath(), CollectionID, PartitionID, SegmentID, Int64Field, 2)
+	orphanDelta := metautil.BuildDeltaLogPath(cm.RootPath(), CollectionID, PartitionID, SegmentID+1, 3)
+	orphanIndex := metautil.BuildSegmentIndexFilePath(cm.RootPath(), 100, 1, PartitionID, SegmentID, "index")
+	orphanQuantized := path.Join(cm.RootPath(), common.SegmentQuantizedLogPath, metautil.JoinIDPath(CollectionID, PartitionID, SegmentID, Int64Field, 2))
+	unrelated := path.Join(cm.RootPath(), "unrelated", "file")
+	for _, p := range []string{referenced, orphanInsert, orphanDelta, orphanIndex, orphanQuantized, unrelated} {
+		require.NoError(t, cm.Write(ctx, p, []byte("data")))
+	}
+
+	isReferenced := func(filePath string) bool {
+		return filePath == referenced
+	}
+	prefixes := OrphanScanPrefixes(cm.RootPath())
+
+	t.Run("too young", func(t *testing.T) {
+		orphans, err := ScanOrphanObjects(ctx, cm, prefixes, isReferenced, time.Hour, time.Now())
+		require.NoError(t, err)
+		assert.Empty(t, orphans)
+	})
+
+	t.Run("scan and remove", func(t *testing.T) {
+		orphans, err := ScanOrphanObjects(ctx, cm, prefixes, isReferenced, time.Hour, time.Now().Add(2*time.Hour))
+		require.NoError(t, err)
+		filePaths := lo.Map(orphans, func(orphan *OrphanObject, _ int) string { return orphan.FilePath })
+		assert.ElementsMatch(t, []string{orphanInsert, orphanDelta, orphanIndex, orphanQuantized}, filePaths)
+		for _, orphan := range orphans {
+			assert.True(t, orphan.Age >= time.Hour)
+		}
+
+		err = RemoveOrphanObjects(ctx, cm, orphans)
+		require.NoError(t, err)
+		for _, p := range filePaths {
+			exist, err := cm.Exist(ctx, p)
+			require.NoError(t, err)
+			assert.False(t, exist)
+		}
+		for _, p := range []string{referenced, unrelated} {
+			exist, err := cm.Exist(ctx, p)
+			require.NoError(t, err)
+			assert.True(t, exist)
+		}
+	})
+}
+
+func TestListRootPaths(t *testing.T) {
+	ctx := context.Background()
+	cm := NewLocalChunkManager(RootPath(t.TempDir()))
+
+	rootPaths, err := ListRootPaths(ctx, cm)
+	require.NoError(t, err)
+	assert.Equal(t, []string{cm.RootPath()}, rootPaths)
+
+	tenantRootPath := metautil.TenantRootPath(cm.RootPath(), "tenant-1")
+	require.NoError(t, cm.Write(ctx, metautil.BuildInsertLogPath(tenantRootPath, CollectionID, PartitionID, SegmentID, Int64Field, 1), []byte("data")))
+	rootPaths, err = ListRootPaths(ctx, cm)
+	require.NoError(t, err)
+	assert.Equal(t, []string{cm.RootPath(), tenantRootPath}, rootPaths)
+}
@@ -0,0 +1,326 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/parquet"
+	"github.com/apache/arrow/go/v12/parquet/file"
+	"github.com/apache/arrow/go/v12/parquet/metadata"
+	"github.com/apache/arrow/go/v12/parquet/pqarrow"
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// The parquet binlog is a plain parquet file of a single column, one file per field as the native binlog.
+// The milvus meta, which is in the descriptor event of the native binlog, is kept in the key value metadata,
+// so it could be read by the external engines directly, and the row groups could be skipped by the column statistics.
+const (
+	parquetBinlogVersion = "1"
+
+	parquetBinlogVersionKey      = "milvus.binlog.version"
+	parquetBinlogCollectionIDKey = "milvus.collection_id"
+	parquetBinlogPartitionIDKey  = "milvus.partition_id"
+	parquetBinlogSegmentIDKey    = "milvus.segment_id"
+	parquetBinlogFieldIDKey      = "milvus.field_id"
+	parquetBinlogDataTypeKey     = "milvus.data_type"
+	parquetBinlogStartTsKey      = "milvus.start_timestamp"
+	parquetBinlogEndTsKey        = "milvus.end_timestamp"
+	parquetBinlogOriginalSizeKey = "milvus." + originalSizeKey
+)
+
+// parquetMagic is the magic number at the beginning of a parquet file.
+var parquetMagic = []byte("PAR1")
+
+// IsParquetBinlog returns whether the binlog is in the parquet binlog format.
+func IsParquetBinlog(data []byte) bool {
+	return bytes.HasPrefix(data, parquetMagic)
+}
+
+func (insertCodec *InsertCodec) serializeParquetColumn(partitionID UniqueID, segmentID UniqueID, field *schemapb.FieldSchema,
+	record *InsertRecord, startTs, endTs Timestamp,
+) (*Blob, error) {
+	column := record.Column(field.FieldID)
+	if column == nil {
+		return nil, fmt.Errorf("data of field %d not found", field.FieldID)
+	}
+
+	meta := arrow.NewMetadata([]string{
+		parquetBinlogVersionKey,
+		parquetBinlogCollectionIDKey,
+		parquetBinlogPartitionIDKey,
+		parquetBinlogSegmentIDKey,
+		parquetBinlogFieldIDKey,
+		parquetBinlogDataTypeKey,
+		parquetBinlogStartTsKey,
+		parquetBinlogEndTsKey,
+		parquetBinlogOriginalSizeKey,
+	}, []string{
+		parquetBinlogVersion,
+		strconv.FormatInt(insertCodec.Schema.ID, 10),
+		strconv.FormatInt(partitionID, 10),
+		strconv.FormatInt(segmentID, 10),
+		strconv.FormatInt(field.FieldID, 10),
+		strconv.FormatInt(int64(field.DataType), 10),
+		strconv.FormatUint(startTs, 10),
+		strconv.FormatUint(endTs, 10),
+		strconv.Itoa(record.FieldMemorySize(field.FieldID)),
+	})
+	zoneMap := ComputeZoneMap(field.DataType, column)
+	if zoneMap != nil {
+		value, err := marshalZoneMap(zoneMap)
+		if err != nil {
+			return nil, err
+		}
+		meta = arrow.NewMetadata(append(meta.Keys(), parquetBinlogZoneMapKey), append(meta.Values(), value))
+	}
+	nullable := isNullableField(field)
+	if column.NullN() > 0 && !nullable {
+		return nil, fmt.Errorf("null rows found in non-nullable field %d", field.FieldID)
+	}
+	schema := arrow.NewSchema([]arrow.Field{{
+		Name:     field.Name,
+		Type:     column.DataType(),
+		Nullable: nullable,
+		Metadata: arrow.NewMetadata([]string{fieldIDMetaKey}, []string{strconv.FormatInt(field.FieldID, 10)}),
+	}}, &meta)
+
+	chunked := arrow.NewChunked(column.DataType(), []arrow.Array{column})
+	defer chunked.Release()
+	col := arrow.NewColumn(schema.Field(0), chunked)
+	defer col.Release()
+	table := array.NewTable(schema, []arrow.Column{*col}, int64(column.Len()))
+	defer table.Release()
+
+	compression, encoding, err := fieldStorageOptions(field, column)
+	if err != nil {
+		return nil, err
+	}
+	if encoding == common.FieldEncodingDelta && !common.IsDeltaEncodingSupported(field.GetDataType()) {
+		return nil, fmt.Errorf("delta encoding is not supported for data type %s", field.GetDataType())
+	}
+	buffer := new(bytes.Buffer)
+	opts := append(columnWriterProperties(field.Name, compression, encoding), parquet.WithStats(true))
+	props := parquet.NewWriterProperties(opts...)
+	rowGroupRows := paramtable.Get().DataNodeCfg.ParquetRowGroupRows.GetAsInt64()
+	if rowGroupRows <= 0 {
+		rowGroupRows = parquet.DefaultMaxRowGroupLen
+	}
+	if err := pqarrow.WriteTable(table, buffer, rowGroupRows, props, pqarrow.DefaultWriterProps()); err != nil {
+		return nil, err
+	}
+
+	return &Blob{
+		Key:     fmt.Sprintf("%d", field.FieldID),
+		Value:   buffer.Bytes(),
+		RowNum:  int64(column.Len()),
+		ZoneMap: zoneMap,
+	}, nil
+}
+
+// newParquetBinlogReader returns the BinlogReader of the parquet binlog,
+// it has a single insert event whose payload is the whole file.
+func newParquetBinlogReader(data []byte) (*BinlogReader, error) {
+	reader, err := file.NewParquetReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	meta := reader.MetaData().KeyValueMetadata()
+	getInt := func(key string) (int64, error) {
+		value := meta.FindValue(key)
+		if value == nil {
+			return 0, fmt.Errorf("%s not found in parquet binlog metadata", key)
+		}
+		return strconv.ParseInt(*value, 10, 64)
+	}
+
+	binlogReader := &BinlogReader{
+		magicNumber:    MagicNumber,
+		buffer:         bytes.NewBuffer(nil),
+		parquetPayload: data,
+	}
+	descriptor := &binlogReader.descriptorEvent
+	descriptor.Extras = make(map[string]interface{})
+	fixPart := &descriptor.DescriptorEventDataFixPart
+	for key, target := range map[string]*int64{
+		parquetBinlogCollectionIDKey: &fixPart.CollectionID,
+		parquetBinlogPartitionIDKey:  &fixPart.PartitionID,
+		parquetBinlogSegmentIDKey:    &fixPart.SegmentID,
+		parquetBinlogFieldIDKey:      &fixPart.FieldID,
+	} {
+		if *target, err = getInt(key); err != nil {
+			return nil, err
+		}
+	}
+	dataType, err := getInt(parquetBinlogDataTypeKey)
+	if err != nil {
+		return nil, err
+	}
+	fixPart.PayloadDataType = schemapb.DataType(dataType)
+	startTs, err := getInt(parquetBinlogStartTsKey)
+	if err != nil {
+		return nil, err
+	}
+	endTs, err := getInt(parquetBinlogEndTsKey)
+	if err != nil {
+		return nil, err
+	}
+	fixPart.StartTimestamp, fixPart.EndTimestamp = Timestamp(startTs), Timestamp(endTs)
+	if originalSize := meta.FindValue(parquetBinlogOriginalSizeKey); originalSize != nil {
+		descriptor.Extras[originalSizeKey] = *originalSize
+	}
+	if zoneMap := meta.FindValue(parquetBinlogZoneMapKey); zoneMap != nil {
+		descriptor.Extras[zoneMapKey] = *zoneMap
+	}
+	return binlogReader, nil
+}
+
+// nextParquetEventReader returns the insert event of the parquet binlog.
+func (reader *BinlogReader) nextParquetEventReader() (*EventReader, error) {
+	payload := reader.parquetPayload
+	reader.parquetPayload = nil
+
+	payloadReader, err := NewPayloadReader(reader.PayloadDataType, payload)
+	if err != nil {
+		return nil, err
+	}
+	reader.eventReader = &EventReader{
+		eventHeader: eventHeader{
+			baseEventHeader: baseEventHeader{
+				Timestamp: reader.StartTimestamp,
+				TypeCode:  InsertEventType,
+			},
+		},
+		eventData: &insertEventData{
+			StartTimestamp: reader.StartTimestamp,
+			EndTimestamp:   reader.EndTimestamp,
+		},
+		PayloadReaderInterface: payloadReader,
+	}
+	return reader.eventReader, nil
+}
+
+// ParquetRowGroupStats is the statistics of a row group of the parquet binlog,
+// used to skip the row groups not matched without reading them.
+type ParquetRowGroupStats struct {
+	NumRows int64
+	// Min and Max are nil if not available, e.g. the vector, JSON and array fields.
+	Min any
+	Max any
+}
+
+// GetParquetBinlogStats returns the statistics of the row groups of the parquet binlog.
+func GetParquetBinlogStats(data []byte) ([]*ParquetRowGroupStats, error) {
+	if !IsParquetBinlog(data) {
+		return nil, errors.New("not a parquet binlog")
+	}
+	binlogReader, err := newParquetBinlogReader(data)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := file.NewParquetReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	result := make([]*ParquetRowGroupStats, 0, reader.NumRowGroups())
+	for i := 0; i < reader.NumRowGroups(); i++ {
+		rowGroup := reader.MetaData().RowGroup(i)
+		stats := &ParquetRowGroupStats{NumRows: rowGroup.NumRows()}
+		chunk, err := rowGroup.ColumnChunk(0)
+		if err != nil {
+			return nil, err
+		}
+		if ok, _ := chunk.StatsSet(); ok {
+			typedStats, err := chunk.Statistics()
+			if err != nil {
+				return nil, err
+			}
+			if typedStats.HasMinMax() {
+				stats.Min, stats.Max = parquetStatsMinMax(binlogReader.PayloadDataType, typedStats)
+			}
+		}
+		result = append(result, stats)
+	}
+	return result, nil
+}
+
+func parquetStatsMinMax(dataType schemapb.DataType, stats metadata.TypedStatistics) (any, any) {
+	switch s := stats.(type) {
+	case *metadata.BooleanStatistics:
+		return s.Min(), s.Max()
+	case *metadata.Int32Statistics:
+		switch dataType {
+		case schemapb.DataType_Int8:
+			return int8(s.Min()), int8(s.Max())
+		case schemapb.DataType_Int16:
+			return int16(s.Min()), int16(s.Max())
+		default:
+			return s.Min(), s.Max()
+		}
+	case *metadata.Int64Statistics:
+		return s.Min(), s.Max()
+	case *metadata.Float32Statistics:
+		return s.Min(), s.Max()
+	case *metadata.Float64Statistics:
+		return s.Min(), s.Max()
+	case *metadata.ByteArrayStatistics:
+		if dataType == schemapb.DataType_VarChar || dataType == schemapb.DataType_String {
+			return string(s.Min()), string(s.Max())
+		}
+	}
+	return nil, nil
+}
+
+// ReadParquetBinlogRowGroups reads the row groups of the parquet binlog only, in the order of the row groups given.
+// The caller should release the returned array.
+func ReadParquetBinlogRowGroups(ctx context.Context, data []byte, rowGroups []int) (*arrow.Chunked, error) {
+	if !IsParquetBinlog(data) {
+		return nil, errors.New("not a parquet binlog")
+	}
+	reader, err := file.NewParquetReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	fileReader, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, err
+	}
+	table, err := fileReader.ReadRowGroups(ctx, []int{0}, rowGroups)
+	if err != nil {
+		return nil, err
+	}
+	defer table.Release()
+	chunked := table.Column(0).Data()
+	chunked.Retain()
+	return chunked, nil
+}
@@ -0,0 +1,214 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/parquet"
+	"github.com/apache/arrow/go/v12/parquet/file"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/internal/proto/etcdpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type ParquetBinlogSuite struct {
+	suite.Suite
+	meta *etcdpb.CollectionMeta
+}
+
+func (s *ParquetBinlogSuite) SetupSuite() {
+	paramtable.Init()
+	s.meta = genTestCollectionMeta()
+}
+
+func (s *ParquetBinlogSuite) TearDownTest() {
+	paramtable.Get().Reset(paramtable.Get().DataNodeCfg.ParquetRowGroupRows.Key)
+	paramtable.Get().Reset(paramtable.Get().DataNodeCfg.DictionaryCardinality.Key)
+}
+
+func (s *ParquetBinlogSuite) genInsertData(rowIDs ...int64) *InsertData {
+	data, err := genSequentialInsertData(s.meta.GetSchema(), rowIDs...)
+	s.Require().NoError(err)
+	return data
+}
+
+func (s *ParquetBinlogSuite) serialize(rowIDs ...int64) []*Blob {
+	codec := NewInsertCodecWithSchema(s.meta)
+	codec.BinlogFormat = common.BinlogFormatParquet
+	record, err := NewSortedInsertRecord(context.Background(), s.meta.GetSchema(), s.genInsertData(rowIDs...))
+	s.Require().NoError(err)
+	defer record.Release()
+
+	blobs, err := codec.SerializeRecord(PartitionID, SegmentID, record)
+	s.Require().NoError(err)
+	s.Require().Len(blobs, len(s.meta.GetSchema().GetFields()))
+	return blobs
+}
+
+func (s *ParquetBinlogSuite) TestRoundTrip() {
+	blobs := s.serialize(3, 1, 2)
+	for _, blob := range blobs {
+		s.True(IsParquetBinlog(blob.Value))
+		s.EqualValues(3, blob.RowNum)
+	}
+
+	codec := NewInsertCodecWithSchema(s.meta)
+	expectedBlobs, err := codec.Serialize(PartitionID, SegmentID, s.genInsertData(3, 1, 2))
+	s.Require().NoError(err)
+	s.False(IsParquetBinlog(expectedBlobs[0].Value))
+	_, _, expected, err := codec.Deserialize(expectedBlobs)
+	s.Require().NoError(err)
+
+	collectionID, partitionID, segmentID, actual, err := codec.DeserializeAll(blobs)
+	s.Require().NoError(err)
+	s.EqualValues(s.meta.GetID(), collectionID)
+	s.EqualValues(PartitionID, partitionID)
+	s.EqualValues(SegmentID, segmentID)
+	s.Equal(expected.Data, actual.Data)
+
+	reader, err := NewBinlogReader(blobs[0].Value)
+	s.Require().NoError(err)
+	defer reader.Close()
+	s.EqualValues(RowIDField, reader.FieldID)
+	s.EqualValues(101, reader.StartTimestamp)
+	s.EqualValues(103, reader.EndTimestamp)
+	s.NotEmpty(reader.Extras[originalSizeKey])
+	eventReader, err := reader.NextEventReader()
+	s.Require().NoError(err)
+	s.Equal(InsertEventType, eventReader.TypeCode)
+	eventReader, err = reader.NextEventReader()
+	s.NoError(err)
+	s.Nil(eventReader)
+}
+
+func (s *ParquetBinlogSuite) TestFieldID() {
+	blobs := s.serialize(1)
+	reader, err := file.NewParquetReader(bytes.NewReader(blobs[len(blobs)-1].Value))
+	s.Require().NoError(err)
+	defer reader.Close()
+
+	fields := s.meta.GetSchema().GetFields()
+	s.EqualValues(fields[len(fields)-1].GetFieldID(), reader.MetaData().Schema.Column(0).SchemaNode().FieldID())
+}
+
+func (s *ParquetBinlogSuite) TestScalarDictionaryEncoding() {
+	blobs := s.serialize(1, 2, 3)
+	for _, blob := range blobs {
+		if blob.Key != "107" {
+			continue
+		}
+		reader, err := file.NewParquetReader(bytes.NewReader(blob.Value))
+		s.Require().NoError(err)
+		defer reader.Close()
+		chunk, err := reader.MetaData().RowGroup(0).ColumnChunk(0)
+		s.Require().NoError(err)
+		// the scalars are dictionary encoded with the RLE indices unless the plain encoding set
+		s.Contains(chunk.Encodings(), parquet.Encodings.RLEDict)
+	}
+}
+
+func (s *ParquetBinlogSuite) TestStringDictionaryCardinality() {
+	encodings := func() []parquet.Encoding {
+		for _, blob := range s.serialize(1, 2, 3) {
+			if blob.Key != "107" {
+				continue
+			}
+			reader, err := file.NewParquetReader(bytes.NewReader(blob.Value))
+			s.Require().NoError(err)
+			defer reader.Close()
+			chunk, err := reader.MetaData().RowGroup(0).ColumnChunk(0)
+			s.Require().NoError(err)
+			return chunk.Encodings()
+		}
+		return nil
+	}
+
+	s.Contains(encodings(), parquet.Encodings.RLEDict)
+	// the strings of more distinct values than the cardinality are plain encoded
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.DictionaryCardinality.Key, "2")
+	s.NotContains(encodings(), parquet.Encodings.RLEDict)
+}
+
+func (s *ParquetBinlogSuite) TestRowGroups() {
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.ParquetRowGroupRows.Key, "2")
+	blobs := s.serialize(1, 2, 3, 4, 5)
+
+	var int64Blob, stringBlob, vectorBlob *Blob
+	for _, blob := range blobs {
+		switch blob.Key {
+		case "104":
+			int64Blob = blob
+		case "107":
+			stringBlob = blob
+		case "109":
+			vectorBlob = blob
+		}
+	}
+
+	stats, err := GetParquetBinlogStats(int64Blob.Value)
+	s.Require().NoError(err)
+	s.Require().Len(stats, 3)
+	s.EqualValues(2, stats[0].NumRows)
+	s.EqualValues(1, stats[0].Min)
+	s.EqualValues(2, stats[0].Max)
+	s.EqualValues(1, stats[2].NumRows)
+	s.EqualValues(5, stats[2].Min)
+
+	stats, err = GetParquetBinlogStats(stringBlob.Value)
+	s.Require().NoError(err)
+	s.Equal("d", stats[1].Min)
+	s.Equal("e", stats[1].Max)
+
+	stats, err = GetParquetBinlogStats(vectorBlob.Value)
+	s.Require().NoError(err)
+	s.Nil(stats[0].Min)
+
+	chunked, err := ReadParquetBinlogRowGroups(context.Background(), int64Blob.Value, []int{2, 0})
+	s.Require().NoError(err)
+	defer chunked.Release()
+	s.Equal(3, chunked.Len())
+	values := make([]int64, 0, chunked.Len())
+	for _, chunk := range chunked.Chunks() {
+		values = append(values, chunk.(*array.Int64).Int64Values()...)
+	}
+	s.Equal([]int64{5, 1, 2}, values)
+
+	// the whole binlog is read as well
+	_, _, data, err := NewInsertCodecWithSchema(s.meta).Deserialize(blobs)
+	s.Require().NoError(err)
+	s.Equal([]int64{1, 2, 3, 4, 5}, data.Data[RowIDField].(*Int64FieldData).Data)
+}
+
+func (s *ParquetBinlogSuite) TestInvalidBinlog() {
+	s.False(IsParquetBinlog(nil))
+	_, err := GetParquetBinlogStats([]byte("invalid"))
+	s.Error(err)
+	_, err = ReadParquetBinlogRowGroups(context.Background(), []byte("invalid"), []int{0})
+	s.Error(err)
+	_, err = NewBinlogReader([]byte("PAR1invalid"))
+	s.Error(err)
+}
+
+func TestParquetBinlog(t *testing.T) {
+	suite.Run(t, new(ParquetBinlogSuite))
+}
@@ -0,0 +1,66 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"path"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+var pathLayoutCache struct {
+	sync.Mutex
+	key    [3]string
+	layout *metautil.PathLayout
+}
+
+// GetPathLayout returns the path layout of common.storage.pathTemplate, nil for the default layout.
+// The template failed to parse is taken as the default layout.
+func GetPathLayout() *metautil.PathLayout {
+	params := paramtable.Get()
+	key := [3]string{
+		params.CommonCfg.StoragePathTemplate.GetValue(),
+		params.CommonCfg.ClusterPrefix.GetValue(),
+		params.CommonCfg.StorageTier.GetValue(),
+	}
+
+	pathLayoutCache.Lock()
+	defer pathLayoutCache.Unlock()
+	if pathLayoutCache.key == key {
+		return pathLayoutCache.layout
+	}
+	layout, err := metautil.NewPathLayout(key[0], key[1], key[2])
+	if err != nil {
+		log.Warn("invalid storage path template, the default layout is used", zap.String("template", key[0]), zap.Error(err))
+		layout = nil
+	}
+	pathLayoutCache.key = key
+	pathLayoutCache.layout = layout
+	return layout
+}
+
+// LayoutRootPath returns the root path of the binlogs of the collection written now,
+// which is the root path joined with the layout dir if any.
+func LayoutRootPath(rootPath string, collectionID UniqueID) string {
+	return path.Join(rootPath, GetPathLayout().Dir(collectionID, time.Now()))
+}
@@ -17,6 +17,7 @@
 package storage
 
 import (
+	"github.com/apache/arrow/go/v12/arrow"
 	"github.com/apache/arrow/go/v12/parquet"
 	"github.com/apache/arrow/go/v12/parquet/file"
 	"github.com/apache/arrow/go/v12/parquet/pqarrow"
@@ -42,6 +43,11 @@ type PayloadWriterInterface interface {
 	AddFloatVectorToPayload(binVec []float32, dim int) error
 	AddFloat16VectorToPayload(binVec []byte, dim int) error
 	AddBFloat16VectorToPayload(binVec []byte, dim int) error
+	AddArrowArrayToPayload(arr arrow.Array) error
+	EnableDeltaEncoding() error
+	SetCompression(compression string) error
+	SetEncoding(encoding string) error
+	SetNullable(nullable bool) error
 	FinishPayloadWriter() error
 	GetPayloadBufferFromWriter() ([]byte, error)
 	GetPayloadLengthFromWriter() (int, error)
@@ -68,6 +74,7 @@ type PayloadReaderInterface interface {
 	GetBFloat16VectorFromPayload() ([]byte, int, error)
 	GetFloatVectorFromPayload() ([]float32, int, error)
 	GetPayloadLengthFromReader() (int, error)
+	GetValidDataFromPayload() ([]bool, error)
 
 	GetByteArrayDataSet() (*DataSet[parquet.ByteArray, *file.ByteArrayColumnChunkReader], error)
 	GetArrowRecordReader() (pqarrow.RecordReader, error)
@@ -21,6 +21,9 @@ type PayloadReader struct {
 	reader  *file.Reader
 	colType schemapb.DataType
 	numRows int64
+	// nullable is whether the column is optional, the validity of the rows is kept once the values read
+	nullable  bool
+	validData []bool
 }
 
 var _ PayloadReaderInterface = (*PayloadReader)(nil)
@@ -33,7 +36,12 @@ func NewPayloadReader(colType schemapb.DataType, buf []byte) (*PayloadReader, er
 	if err != nil {
 		return nil, err
 	}
-	return &PayloadReader{reader: parquetReader, colType: colType, numRows: parquetReader.NumRows()}, nil
+	return &PayloadReader{
+		reader:   parquetReader,
+		colType:  colType,
+		numRows:  parquetReader.NumRows(),
+		nullable: parquetReader.MetaData().Schema.Column(0).MaxDefinitionLevel() > 0,
+	}, nil
 }
 
 // GetDataFromPayload returns data,length from payload, returns err if failed
@@ -99,14 +107,9 @@ func (r *PayloadReader) GetBoolFromPayload() ([]bool, error) {
 	}
 
 	values := make([]bool, r.numRows)
-	valuesRead, err := ReadDataFromAllRowGroups[bool, *file.BooleanColumnChunkReader](r.reader, values, 0, r.numRows)
-	if err != nil {
+	if err := readPayloadValues[bool, *file.BooleanColumnChunkReader](r, values); err != nil {
 		return nil, err
 	}
-
-	if valuesRead != r.numRows {
-		return nil, fmt.Errorf("expect %d rows, but got valuesRead = %d", r.numRows, valuesRead)
-	}
 	return values, nil
 }
 
@@ -117,15 +120,10 @@ func (r *PayloadReader) GetByteFromPayload() ([]byte, error) {
 	}
 
 	values := make([]int32, r.numRows)
-	valuesRead, err := ReadDataFromAllRowGroups[int32, *file.Int32ColumnChunkReader](r.reader, values, 0, r.numRows)
-	if err != nil {
+	if err := readPayloadValues[int32, *file.Int32ColumnChunkReader](r, values); err != nil {
 		return nil, err
 	}
 
-	if valuesRead != r.numRows {
-		return nil, fmt.Errorf("expect %d rows, but got valuesRead = %d", r.numRows, valuesRead)
-	}
-
 	ret := make([]byte, r.numRows)
 	for i := int64(0); i < r.numRows; i++ {
 		ret[i] = byte(values[i])
@@ -140,15 +138,10 @@ func (r *PayloadReader) GetInt8FromPayload() ([]int8, error) {
 	}
 
 	values := make([]int32, r.numRows)
-	valuesRead, err := ReadDataFromAllRowGroups[int32, *file.Int32ColumnChunkReader](r.reader, values, 0, r.numRows)
-	if err != nil {
+	if err := readPayloadValues[int32, *file.Int32ColumnChunkReader](r, values); err != nil {
 		return nil, err
 	}
 
-	if valuesRead != r.numRows {
-		return nil, fmt.Errorf("expect %d rows, but got valuesRead = %d", r.numRows, valuesRead)
-	}
-
 	ret := make([]int8, r.numRows)
 	for i := int64(0); i < r.numRows; i++ {
 		ret[i] = int8(values[i])
@@ -162,15 +155,10 @@ func (r *PayloadReader) GetInt16FromPayload() ([]int16, error) {
 	}
 
 	values := make([]int32, r.numRows)
-	valuesRead, err := ReadDataFromAllRowGroups[int32, *file.Int32ColumnChunkReader](r.reader, values, 0, r.numRows)
-	if err != nil {
+	if err := readPayloadValues[int32, *file.Int32ColumnChunkReader](r, values); err != nil {
 		return nil, err
 	}
 
-	if valuesRead != r.numRows {
-		return nil, fmt.Errorf("expect %d rows, but got valuesRead = %d", r.numRows, valuesRead)
-	}
-
 	ret := make([]int16, r.numRows)
 	for i := int64(0); i < r.numRows; i++ {
 		ret[i] = int16(values[i])
@@ -184,14 +172,9 @@ func (r *PayloadReader) GetInt32FromPayload() ([]int32, error) {
 	}
 
 	values := make([]int32, r.numRows)
-	valuesRead, err := ReadDataFromAllRowGroups[int32, *file.Int32ColumnChunkReader](r.reader, values, 0, r.numRows)
-	if err != nil {
+	if err := readPayloadValues[int32, *file.Int32ColumnChunkReader](r, values); err != nil {
 		return nil, err
 	}
-
-	if valuesRead != r.numRows {
-		return nil, fmt.Errorf("expect %d rows, but got valuesRead = %d", r.numRows, valuesRead)
-	}
 	return values, nil
 }
 
@@ -201,15 +184,10 @@ func (r *PayloadReader) GetInt64FromPayload() ([]int64, error) {
 	}
 
 	values := make([]int64, r.numRows)
-	valuesRead, err := ReadDataFromAllRowGroups[int64, *file.Int64ColumnChunkReader](r.reader, values, 0, r.numRows)
-	if err != nil {
+	if err := readPayloadValues[int64, *file.Int64ColumnChunkReader](r, values); err != nil {
 		return nil, err
 	}
 
-	if valuesRead != r.numRows {
-		return nil, fmt.Errorf("expect %d rows, but got valuesRead = %d", r.numRows, valuesRead)
-	}
-
 	return values, nil
 }
 
@@ -219,15 +197,10 @@ func (r *PayloadReader) GetFloatFromPayload() ([]float32, error) {
 	}
 
 	values := make([]float32, r.numRows)
-	valuesRead, err := ReadDataFromAllRowGroups[float32, *file.Float32ColumnChunkReader](r.reader, values, 0, r.numRows)
-	if err != nil {
+	if err := readPayloadValues[float32, *file.Float32ColumnChunkReader](r, values); err != nil {
 		return nil, err
 	}
 
-	if valuesRead != r.numRows {
-		return nil, fmt.Errorf("expect %d rows, but got valuesRead = %d", r.numRows, valuesRead)
-	}
-
 	return values, nil
 }
 
@@ -237,14 +210,9 @@ func (r *PayloadReader) GetDoubleFromPayload() ([]float64, error) {
 	}
 
 	values := make([]float64, r.numRows)
-	valuesRead, err := ReadDataFromAllRowGroups[float64, *file.Float64ColumnChunkReader](r.reader, values, 0, r.numRows)
-	if err != nil {
+	if err := readPayloadValues[float64, *file.Float64ColumnChunkReader](r, values); err != nil {
 		return nil, err
 	}
-
-	if valuesRead != r.numRows {
-		return nil, fmt.Errorf("expect %d rows, but got valuesRead = %d", r.numRows, valuesRead)
-	}
 	return values, nil
 }
 
@@ -302,17 +270,16 @@ func (r *PayloadReader) GetJSONFromPayload() ([][]byte, error) {
 
 func readByteAndConvert[T any](r *PayloadReader, convert func(parquet.ByteArray) T) ([]T, error) {
 	values := make([]parquet.ByteArray, r.numRows)
-	valuesRead, err := ReadDataFromAllRowGroups[parquet.ByteArray, *file.ByteArrayColumnChunkReader](r.reader, values, 0, r.numRows)
-	if err != nil {
+	if err := readPayloadValues[parquet.ByteArray, *file.ByteArrayColumnChunkReader](r, values); err != nil {
 		return nil, err
 	}
 
-	if valuesRead != r.numRows {
-		return nil, fmt.Errorf("expect %d rows, but got valuesRead = %d", r.numRows, valuesRead)
-	}
-
 	ret := make([]T, r.numRows)
 	for i := 0; i < int(r.numRows); i++ {
+		// the null rows are left zero values
+		if isNullRow(r.validData, i) {
+			continue
+		}
 		ret[i] = convert(values[i])
 	}
 	return ret, nil
@@ -422,6 +389,8 @@ func (r *PayloadReader) GetFloatVectorFromPayload() ([]float32, int, error) {
 		return nil, -1, fmt.Errorf("expect %d rows, but got valuesRead = %d", r.numRows, valuesRead)
 	}
 
+	// the rows are little endian float32 values as written by AddFloatVectorToPayload, so they are copied
+	// into the result as is rather than converted one by one
 	ret := make([]float32, int64(dim)*r.numRows)
 	for i := 0; i < int(r.numRows); i++ {
 		copy(arrow.Float32Traits.CastToBytes(ret[i*dim:(i+1)*dim]), values[i])
@@ -433,6 +402,20 @@ func (r *PayloadReader) GetPayloadLengthFromReader() (int, error) {
 	return int(r.numRows), nil
 }
 
+// GetValidDataFromPayload returns the validity of the rows, in which false marks the null rows,
+// it returns nil if the payload isn't nullable.
+func (r *PayloadReader) GetValidDataFromPayload() ([]bool, error) {
+	if !r.nullable {
+		return nil, nil
+	}
+	if r.validData == nil {
+		if _, _, err := r.GetDataFromPayload(); err != nil {
+			return nil, err
+		}
+	}
+	return r.validData, nil
+}
+
 // Close closes the payload reader
 func (r *PayloadReader) Close() error {
 	return r.reader.Close()
@@ -470,6 +453,77 @@ func ReadDataFromAllRowGroups[T any, E interface {
 	return offset, nil
 }
 
+// readPayloadValues reads the values of all the rows into values, the null rows of the nullable payload
+// are left zero values, and their validity is kept for GetValidDataFromPayload.
+func readPayloadValues[T any, E interface {
+	ReadBatch(int64, []T, []int16, []int16) (int64, int, error)
+}](r *PayloadReader, values []T) error {
+	if r.nullable {
+		validData, err := readNullableFromAllRowGroups[T, E](r.reader, values, 0)
+		if err != nil {
+			return err
+		}
+		r.validData = validData
+		return nil
+	}
+
+	valuesRead, err := ReadDataFromAllRowGroups[T, E](r.reader, values, 0, r.numRows)
+	if err != nil {
+		return err
+	}
+	if valuesRead != r.numRows {
+		return fmt.Errorf("expect %d rows, but got valuesRead = %d", r.numRows, valuesRead)
+	}
+	return nil
+}
+
+// readNullableFromAllRowGroups reads the optional column of all row groups, the values of the valid rows are
+// spread to their positions in values by the definition levels, it returns the validity of the rows.
+func readNullableFromAllRowGroups[T any, E interface {
+	ReadBatch(int64, []T, []int16, []int16) (int64, int, error)
+}](reader *file.Reader, values []T, columnIdx int) ([]bool, error) {
+	maxDefLevel := reader.MetaData().Schema.Column(columnIdx).MaxDefinitionLevel()
+	validData := make([]bool, 0, len(values))
+	for i := 0; i < reader.NumRowGroups(); i++ {
+		rowGroup := reader.RowGroup(i)
+		column, err := rowGroup.Column(columnIdx)
+		if err != nil {
+			return nil, err
+		}
+		cReader, ok := column.(E)
+		if !ok {
+			return nil, fmt.Errorf("expect type %T, but got %T", *new(E), column)
+		}
+
+		rows := rowGroup.NumRows()
+		if int64(len(validData))+rows > int64(len(values)) {
+			return nil, fmt.Errorf("expect %d rows, but got more", len(values))
+		}
+		defLevels := make([]int16, rows)
+		buffer := make([]T, rows)
+		levelsRead, _, err := cReader.ReadBatch(rows, buffer, defLevels, nil)
+		if err != nil {
+			return nil, err
+		}
+		if levelsRead != rows {
+			return nil, fmt.Errorf("expect %d rows, but got levelsRead = %d", rows, levelsRead)
+		}
+		next := 0
+		for _, level := range defLevels {
+			valid := level == maxDefLevel
+			if valid {
+				values[len(validData)] = buffer[next]
+				next++
+			}
+			validData = append(validData, valid)
+		}
+	}
+	if len(validData) != len(values) {
+		return nil, fmt.Errorf("expect %d rows, but got %d", len(values), len(validData))
+	}
+	return validData, nil
+}
+
 type DataSet[T any, E interface {
 	ReadBatch(int64, []T, []int16, []int16) (int64, int, error)
 }] struct {
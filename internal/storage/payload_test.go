@@ -17,15 +17,21 @@
 package storage
 
 import (
+	"bytes"
 	"math"
 	"math/rand"
 	"testing"
 
 	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/parquet"
+	"github.com/apache/arrow/go/v12/parquet/compress"
+	"github.com/apache/arrow/go/v12/parquet/file"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
 )
 
 func TestPayload_ReaderAndWriter(t *testing.T) {
@@ -537,6 +543,38 @@ func TestPayload_ReaderAndWriter(t *testing.T) {
 		defer r.ReleasePayloadReader()
 	})
 
+	t.Run("TestFloatVectorLayout", func(t *testing.T) {
+		w, err := NewPayloadWriter(schemapb.DataType_FloatVector, 2)
+		require.Nil(t, err)
+		defer w.ReleasePayloadWriter()
+
+		vectors := []float32{1.5, -2.25, math.MaxFloat32, float32(math.Inf(-1))}
+		err = w.AddFloatVectorToPayload(vectors, 2)
+		assert.NoError(t, err)
+		err = w.FinishPayloadWriter()
+		assert.NoError(t, err)
+		buffer, err := w.GetPayloadBufferFromWriter()
+		assert.NoError(t, err)
+
+		r, err := NewPayloadReader(schemapb.DataType_FloatVector, buffer)
+		require.Nil(t, err)
+		defer r.ReleasePayloadReader()
+		rr, err := r.GetArrowRecordReader()
+		require.NoError(t, err)
+		defer rr.Release()
+		require.True(t, rr.Next())
+		column := rr.Record().Column(0).(*array.FixedSizeBinary)
+
+		// each row is stored as the little endian float32 values
+		for i := 0; i < column.Len(); i++ {
+			expected := make([]byte, 8)
+			for j, v := range vectors[i*2 : (i+1)*2] {
+				common.Endian.PutUint32(expected[j*4:], math.Float32bits(v))
+			}
+			assert.Equal(t, expected, column.Value(i))
+		}
+	})
+
 	t.Run("TestFloat16Vector", func(t *testing.T) {
 		w, err := NewPayloadWriter(schemapb.DataType_Float16Vector, 1)
 		require.Nil(t, err)
@@ -619,6 +657,201 @@ func TestPayload_ReaderAndWriter(t *testing.T) {
 		defer r.ReleasePayloadReader()
 	})
 
+	t.Run("TestAddArrowArray", func(t *testing.T) {
+		w, err := NewPayloadWriter(schemapb.DataType_Int64)
+		require.Nil(t, err)
+		require.NotNil(t, w)
+		defer w.ReleasePayloadWriter()
+
+		builder := array.NewInt64Builder(memory.DefaultAllocator)
+		builder.AppendValues([]int64{3, 4}, nil)
+		arr := builder.NewArray()
+		defer arr.Release()
+		builder.Release()
+
+		err = w.AddInt64ToPayload([]int64{1, 2})
+		assert.NoError(t, err)
+		err = w.AddArrowArrayToPayload(arr)
+		assert.NoError(t, err)
+		err = w.AddInt64ToPayload([]int64{5})
+		assert.NoError(t, err)
+
+		length, err := w.GetPayloadLengthFromWriter()
+		assert.NoError(t, err)
+		assert.Equal(t, 5, length)
+
+		err = w.FinishPayloadWriter()
+		assert.NoError(t, err)
+		length, err = w.GetPayloadLengthFromWriter()
+		assert.NoError(t, err)
+		assert.Equal(t, 5, length)
+
+		buffer, err := w.GetPayloadBufferFromWriter()
+		assert.NoError(t, err)
+
+		r, err := NewPayloadReader(schemapb.DataType_Int64, buffer)
+		require.Nil(t, err)
+		defer r.ReleasePayloadReader()
+		int64s, err := r.GetInt64FromPayload()
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3, 4, 5}, int64s)
+	})
+
+	t.Run("TestDeltaEncoding", func(t *testing.T) {
+		// the timestamps allocated in order with small jitters
+		timestamps := make([]int64, 10000)
+		for i := range timestamps {
+			timestamps[i] = int64(448000000000000000) + int64(i)<<18 + rand.Int63n(16)
+		}
+		write := func(deltaEncoding bool) []byte {
+			w, err := NewPayloadWriter(schemapb.DataType_Int64)
+			require.NoError(t, err)
+			defer w.ReleasePayloadWriter()
+			if deltaEncoding {
+				require.NoError(t, w.EnableDeltaEncoding())
+			}
+			require.NoError(t, w.AddInt64ToPayload(timestamps))
+			require.NoError(t, w.FinishPayloadWriter())
+			buffer, err := w.GetPayloadBufferFromWriter()
+			require.NoError(t, err)
+			return buffer
+		}
+
+		plain, encoded := write(false), write(true)
+		assert.Less(t, len(encoded), len(plain))
+
+		reader, err := file.NewParquetReader(bytes.NewReader(encoded))
+		require.NoError(t, err)
+		chunk, err := reader.MetaData().RowGroup(0).ColumnChunk(0)
+		require.NoError(t, err)
+		assert.Contains(t, chunk.Encodings(), parquet.Encodings.DeltaBinaryPacked)
+		reader.Close()
+
+		r, err := NewPayloadReader(schemapb.DataType_Int64, encoded)
+		require.NoError(t, err)
+		defer r.ReleasePayloadReader()
+		int64s, err := r.GetInt64FromPayload()
+		assert.NoError(t, err)
+		assert.Equal(t, timestamps, int64s)
+
+		w, err := NewPayloadWriter(schemapb.DataType_Float)
+		require.NoError(t, err)
+		defer w.ReleasePayloadWriter()
+		assert.Error(t, w.EnableDeltaEncoding())
+	})
+
+	t.Run("TestStorageOptions", func(t *testing.T) {
+		w, err := NewPayloadWriter(schemapb.DataType_Int32)
+		require.NoError(t, err)
+		defer w.ReleasePayloadWriter()
+		assert.NoError(t, w.SetCompression(common.FieldCompressionSnappy))
+		assert.NoError(t, w.SetEncoding(common.FieldEncodingDelta))
+		assert.Error(t, w.SetCompression("lzo"))
+		assert.Error(t, w.SetEncoding("rle"))
+		require.NoError(t, w.AddInt32ToPayload([]int32{1, 2, 3}))
+		require.NoError(t, w.FinishPayloadWriter())
+		assert.Error(t, w.SetCompression(common.FieldCompressionNone))
+		assert.Error(t, w.SetEncoding(common.FieldEncodingPlain))
+
+		buffer, err := w.GetPayloadBufferFromWriter()
+		require.NoError(t, err)
+		reader, err := file.NewParquetReader(bytes.NewReader(buffer))
+		require.NoError(t, err)
+		chunk, err := reader.MetaData().RowGroup(0).ColumnChunk(0)
+		require.NoError(t, err)
+		assert.Equal(t, compress.Codecs.Snappy, chunk.Compression())
+		assert.Contains(t, chunk.Encodings(), parquet.Encodings.DeltaBinaryPacked)
+		reader.Close()
+
+		r, err := NewPayloadReader(schemapb.DataType_Int32, buffer)
+		require.NoError(t, err)
+		defer r.ReleasePayloadReader()
+		int32s, err := r.GetInt32FromPayload()
+		assert.NoError(t, err)
+		assert.Equal(t, []int32{1, 2, 3}, int32s)
+
+		w, err = NewPayloadWriter(schemapb.DataType_VarChar)
+		require.NoError(t, err)
+		defer w.ReleasePayloadWriter()
+		assert.Error(t, w.SetEncoding(common.FieldEncodingDelta))
+		assert.NoError(t, w.SetEncoding(common.FieldEncodingPlain))
+	})
+
+	t.Run("TestNullable", func(t *testing.T) {
+		builder := array.NewInt64Builder(memory.DefaultAllocator)
+		defer builder.Release()
+		builder.AppendValues([]int64{1, 0, 3, 0}, []bool{true, false, true, false})
+		arr := builder.NewArray()
+		defer arr.Release()
+
+		w, err := NewPayloadWriter(schemapb.DataType_Int64)
+		require.NoError(t, err)
+		defer w.ReleasePayloadWriter()
+		assert.Error(t, w.AddArrowArrayToPayload(arr))
+		require.NoError(t, w.SetNullable(true))
+		require.NoError(t, w.AddArrowArrayToPayload(arr))
+		require.NoError(t, w.AddInt64ToPayload([]int64{5}))
+		require.NoError(t, w.FinishPayloadWriter())
+		assert.Error(t, w.SetNullable(false))
+		buffer, err := w.GetPayloadBufferFromWriter()
+		require.NoError(t, err)
+
+		r, err := NewPayloadReader(schemapb.DataType_Int64, buffer)
+		require.NoError(t, err)
+		defer r.ReleasePayloadReader()
+		validData, err := r.GetValidDataFromPayload()
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, false, true, false, true}, validData)
+		int64s, err := r.GetInt64FromPayload()
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 0, 3, 0, 5}, int64s)
+
+		// the non-nullable payload has no validity
+		w, err = NewPayloadWriter(schemapb.DataType_Int64)
+		require.NoError(t, err)
+		defer w.ReleasePayloadWriter()
+		require.NoError(t, w.AddInt64ToPayload([]int64{1}))
+		require.NoError(t, w.FinishPayloadWriter())
+		buffer, err = w.GetPayloadBufferFromWriter()
+		require.NoError(t, err)
+		r, err = NewPayloadReader(schemapb.DataType_Int64, buffer)
+		require.NoError(t, err)
+		defer r.ReleasePayloadReader()
+		validData, err = r.GetValidDataFromPayload()
+		assert.NoError(t, err)
+		assert.Nil(t, validData)
+
+		w, err = NewPayloadWriter(schemapb.DataType_FloatVector, 8)
+		require.NoError(t, err)
+		defer w.ReleasePayloadWriter()
+		assert.Error(t, w.SetNullable(true))
+	})
+
+	t.Run("TestAddArrowArrayError", func(t *testing.T) {
+		w, err := NewPayloadWriter(schemapb.DataType_Int32)
+		require.Nil(t, err)
+		require.NotNil(t, w)
+		defer w.ReleasePayloadWriter()
+
+		builder := array.NewInt64Builder(memory.DefaultAllocator)
+		defer builder.Release()
+		empty := builder.NewArray()
+		defer empty.Release()
+		err = w.AddArrowArrayToPayload(empty)
+		assert.Error(t, err)
+
+		builder.AppendValues([]int64{1}, nil)
+		arr := builder.NewArray()
+		defer arr.Release()
+		err = w.AddArrowArrayToPayload(arr)
+		assert.Error(t, err)
+
+		err = w.FinishPayloadWriter()
+		assert.NoError(t, err)
+		err = w.AddArrowArrayToPayload(arr)
+		assert.Error(t, err)
+	})
+
 	// t.Run("TestAddDataToPayload", func(t *testing.T) {
 	// 	w, err := NewPayloadWriter(schemapb.DataType_Bool)
 	// 	w.colType = 999
@@ -1560,3 +1793,20 @@ func BenchmarkArrowRecordReader(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkAddFloatVectorToPayload(b *testing.B) {
+	dim := 128
+	data := make([]float32, 10000*dim)
+	for i := range data {
+		data[i] = rand.Float32()
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w, err := NewPayloadWriter(schemapb.DataType_FloatVector, dim)
+		require.NoError(b, err)
+		err = w.AddFloatVectorToPayload(data, dim)
+		require.NoError(b, err)
+		w.ReleasePayloadWriter()
+	}
+}
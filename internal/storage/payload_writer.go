@@ -19,7 +19,6 @@ package storage
 import (
 	"bytes"
 	"fmt"
-	"math"
 	"sync"
 
 	"github.com/apache/arrow/go/v12/arrow"
@@ -31,6 +30,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/golang/protobuf/proto"
 
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
@@ -39,13 +39,20 @@ import (
 var _ PayloadWriterInterface = (*NativePayloadWriter)(nil)
 
 type NativePayloadWriter struct {
-	dataType    schemapb.DataType
-	arrowType   arrow.DataType
-	builder     array.Builder
+	dataType  schemapb.DataType
+	arrowType arrow.DataType
+	builder   array.Builder
+	// chunks are the arrays added or built before, written in order with the builder's
+	chunks      []arrow.Array
 	finished    bool
 	flushedRows int
 	output      *bytes.Buffer
 	releaseOnce sync.Once
+	// compression and encoding of the column, see SetCompression and SetEncoding
+	compression string
+	encoding    string
+	// nullable writes the column optional with the validity of the rows, see SetNullable
+	nullable bool
 }
 
 func NewPayloadWriter(colType schemapb.DataType, dim ...int) (PayloadWriterInterface, error) {
@@ -68,6 +75,7 @@ func NewPayloadWriter(colType schemapb.DataType, dim ...int) (PayloadWriterInter
 		finished:    false,
 		flushedRows: 0,
 		output:      new(bytes.Buffer),
+		compression: common.FieldCompressionZstd,
 	}, nil
 }
 
@@ -204,10 +212,7 @@ func (w *NativePayloadWriter) AddByteToPayload(data []byte) error {
 		return errors.New("failed to cast ArrayBuilder")
 	}
 
-	builder.Reserve(len(data))
-	for i := range data {
-		builder.Append(int8(data[i]))
-	}
+	builder.AppendValues(arrow.Int8Traits.CastFromBytes(data), nil)
 
 	return nil
 }
@@ -384,12 +389,7 @@ func (w *NativePayloadWriter) AddBinaryVectorToPayload(data []byte, dim int) err
 		return errors.New("failed to cast ArrayBuilder")
 	}
 
-	byteLength := dim / 8
-	length := len(data) / byteLength
-	builder.Reserve(length)
-	for i := 0; i < length; i++ {
-		builder.Append(data[i*byteLength : (i+1)*byteLength])
-	}
+	appendFixedSizeValues(builder, data, dim/8)
 
 	return nil
 }
@@ -408,19 +408,9 @@ func (w *NativePayloadWriter) AddFloatVectorToPayload(data []float32, dim int) e
 		return errors.New("failed to cast ArrayBuilder")
 	}
 
-	byteLength := dim * 4
-	length := len(data) / dim
-
-	builder.Reserve(length)
-	bytesData := make([]byte, byteLength)
-	for i := 0; i < length; i++ {
-		vec := data[i*dim : (i+1)*dim]
-		for j := range vec {
-			bytes := math.Float32bits(vec[j])
-			common.Endian.PutUint32(bytesData[j*4:], bytes)
-		}
-		builder.Append(bytesData)
-	}
+	// the float32 values are little endian in memory as common.Endian, so they are copied as is
+	// instead of being converted one by one
+	appendFixedSizeValues(builder, arrow.Float32Traits.CastToBytes(data), dim*4)
 
 	return nil
 }
@@ -439,13 +429,7 @@ func (w *NativePayloadWriter) AddFloat16VectorToPayload(data []byte, dim int) er
 		return errors.New("failed to cast ArrayBuilder")
 	}
 
-	byteLength := dim * 2
-	length := len(data) / byteLength
-
-	builder.Reserve(length)
-	for i := 0; i < length; i++ {
-		builder.Append(data[i*byteLength : (i+1)*byteLength])
-	}
+	appendFixedSizeValues(builder, data, dim*2)
 
 	return nil
 }
@@ -464,13 +448,115 @@ func (w *NativePayloadWriter) AddBFloat16VectorToPayload(data []byte, dim int) e
 		return errors.New("failed to cast ArrayBuilder")
 	}
 
-	byteLength := dim * 2
-	length := len(data) / byteLength
+	appendFixedSizeValues(builder, data, dim*2)
+
+	return nil
+}
 
+// appendFixedSizeValues appends the contiguous fixed size values to the builder, a copy per value.
+func appendFixedSizeValues(builder *array.FixedSizeBinaryBuilder, data []byte, byteLength int) {
+	length := len(data) / byteLength
 	builder.Reserve(length)
 	for i := 0; i < length; i++ {
 		builder.Append(data[i*byteLength : (i+1)*byteLength])
 	}
+}
+
+// EnableDeltaEncoding encodes the column by the deltas between the adjacent values, which are bit packed
+// after subtracting the minimal delta of each block, so the near-monotonic values, e.g. the timestamps and
+// the auto generated ids, take a few bits per row only. The readers decode it transparently.
+func (w *NativePayloadWriter) EnableDeltaEncoding() error {
+	return w.SetEncoding(common.FieldEncodingDelta)
+}
+
+// SetCompression sets the compression codec of the column, one of the common.FieldCompression* values.
+func (w *NativePayloadWriter) SetCompression(compression string) error {
+	if w.finished {
+		return errors.New("can't set compression of finished writer")
+	}
+	if _, err := common.GetFieldCompression(&commonpb.KeyValuePair{Key: common.FieldCompressionKey, Value: compression}); err != nil {
+		return err
+	}
+	w.compression = compression
+	return nil
+}
+
+// SetEncoding sets the encoding of the column, one of the common.FieldEncoding* values,
+// the delta encoding is supported for the Int32 and Int64 columns only.
+func (w *NativePayloadWriter) SetEncoding(encoding string) error {
+	if w.finished {
+		return errors.New("can't set encoding of finished writer")
+	}
+	if _, err := common.GetFieldEncoding(&commonpb.KeyValuePair{Key: common.FieldEncodingKey, Value: encoding}); err != nil {
+		return err
+	}
+	if encoding == common.FieldEncodingDelta && !common.IsDeltaEncodingSupported(w.dataType) {
+		return fmt.Errorf("delta encoding is not supported for data type %s", w.dataType)
+	}
+	w.encoding = encoding
+	return nil
+}
+
+// SetNullable makes the column nullable, so the arrays added could have null rows,
+// the vector columns couldn't be nullable.
+func (w *NativePayloadWriter) SetNullable(nullable bool) error {
+	if w.finished {
+		return errors.New("can't set nullable of finished writer")
+	}
+	if nullable && !common.IsNullableSupported(w.dataType) {
+		return fmt.Errorf("nullable is not supported for data type %s", w.dataType)
+	}
+	w.nullable = nullable
+	return nil
+}
+
+// columnWriterProperties returns the parquet writer properties of the column by its compression and encoding.
+func columnWriterProperties(column string, compression, encoding string) []parquet.WriterProperty {
+	var opts []parquet.WriterProperty
+	switch compression {
+	case common.FieldCompressionSnappy:
+		opts = append(opts, parquet.WithCompression(compress.Codecs.Snappy))
+	case common.FieldCompressionNone:
+		opts = append(opts, parquet.WithCompression(compress.Codecs.Uncompressed))
+	default:
+		opts = append(opts, parquet.WithCompression(compress.Codecs.Zstd), parquet.WithCompressionLevel(3))
+	}
+	switch encoding {
+	case common.FieldEncodingPlain:
+		opts = append(opts, parquet.WithDictionaryFor(column, false))
+	case common.FieldEncodingDelta:
+		opts = append(opts,
+			parquet.WithDictionaryFor(column, false),
+			parquet.WithEncodingFor(column, parquet.Encodings.DeltaBinaryPacked),
+		)
+	}
+	return opts
+}
+
+// AddArrowArrayToPayload adds the arrow array without copying, the array must be of the same arrow type as the payload.
+func (w *NativePayloadWriter) AddArrowArrayToPayload(data arrow.Array) error {
+	if w.finished {
+		return errors.New("can't append data to finished writer")
+	}
+
+	if data.Len() == 0 {
+		return errors.New("can't add empty msgs into payload")
+	}
+
+	if !arrow.TypeEqual(data.DataType(), w.arrowType) {
+		return fmt.Errorf("incorrect arrow type, expected %s, actual %s", w.arrowType, data.DataType())
+	}
+
+	if data.NullN() > 0 && !w.nullable {
+		return errors.New("can't add null rows into non-nullable payload")
+	}
+
+	// keep the order of the rows appended by the builder before
+	if w.builder.Len() > 0 {
+		w.chunks = append(w.chunks, w.builder.NewArray())
+	}
+	data.Retain()
+	w.chunks = append(w.chunks, data)
 
 	return nil
 }
@@ -483,26 +569,28 @@ func (w *NativePayloadWriter) FinishPayloadWriter() error {
 	w.finished = true
 
 	field := arrow.Field{
-		Name: "val",
-		Type: w.arrowType,
+		Name:     "val",
+		Type:     w.arrowType,
+		Nullable: w.nullable,
 	}
 	schema := arrow.NewSchema([]arrow.Field{
 		field,
 	}, nil)
 
-	w.flushedRows += w.builder.Len()
-	data := w.builder.NewArray()
-	defer data.Release()
-	column := arrow.NewColumnFromArr(field, data)
+	if w.builder.Len() > 0 || len(w.chunks) == 0 {
+		w.chunks = append(w.chunks, w.builder.NewArray())
+	}
+	chunked := arrow.NewChunked(w.arrowType, w.chunks)
+	defer chunked.Release()
+	w.flushedRows += chunked.Len()
+	w.releaseChunks()
+	column := arrow.NewColumn(field, chunked)
 	defer column.Release()
 
-	table := array.NewTable(schema, []arrow.Column{column}, int64(column.Len()))
+	table := array.NewTable(schema, []arrow.Column{*column}, int64(column.Len()))
 	defer table.Release()
 
-	props := parquet.NewWriterProperties(
-		parquet.WithCompression(compress.Codecs.Zstd),
-		parquet.WithCompressionLevel(3),
-	)
+	props := parquet.NewWriterProperties(columnWriterProperties(field.Name, w.compression, w.encoding)...)
 	return pqarrow.WriteTable(table,
 		w.output,
 		1024*1024*1024,
@@ -523,15 +611,27 @@ func (w *NativePayloadWriter) GetPayloadBufferFromWriter() ([]byte, error) {
 }
 
 func (w *NativePayloadWriter) GetPayloadLengthFromWriter() (int, error) {
-	return w.flushedRows + w.builder.Len(), nil
+	rows := w.flushedRows + w.builder.Len()
+	for _, chunk := range w.chunks {
+		rows += chunk.Len()
+	}
+	return rows, nil
 }
 
 func (w *NativePayloadWriter) ReleasePayloadWriter() {
 	w.releaseOnce.Do(func() {
 		w.builder.Release()
+		w.releaseChunks()
 	})
 }
 
+func (w *NativePayloadWriter) releaseChunks() {
+	for _, chunk := range w.chunks {
+		chunk.Release()
+	}
+	w.chunks = nil
+}
+
 func (w *NativePayloadWriter) Close() {
 	w.ReleasePayloadWriter()
 }
@@ -19,7 +19,9 @@ package storage
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"time"
 
@@ -43,12 +45,37 @@ const (
 	CloudProviderAliyun  = "aliyun"
 	CloudProviderAzure   = "azure"
 	CloudProviderTencent = "tencent"
+	// CloudProviderGCPNative is the GCS accessed by its JSON API rather than the S3 compatible one
+	CloudProviderGCPNative = "gcpnative"
+	// CloudProviderHDFS is the HDFS accessed by the WebHDFS, the storage of the hdfs storage type
+	CloudProviderHDFS = "hdfs"
 )
 
+// StorageTypeAzure is the storage type of the Azure Blob Storage, i.e. the remote storage of the azure cloud provider,
+// the containers are the buckets, and the account name and the endpoint suffix are the access key id and the address.
+const StorageTypeAzure = "azure"
+
+// StorageTypeHDFS is the storage type of the HDFS accessed by the WebHDFS.
+const StorageTypeHDFS = "hdfs"
+
+// RemoteStorageOf returns the storage type and the cloud provider of the remote storage understood by the segcore,
+// which knows the Azure Blob Storage only as the remote one of the azure cloud provider.
+func RemoteStorageOf(storageType, cloudProvider string) (string, string) {
+	if storageType == StorageTypeAzure {
+		return "remote", CloudProviderAzure
+	}
+	// segcore accesses the native GCS by the gcp provider
+	if cloudProvider == CloudProviderGCPNative {
+		return storageType, CloudProviderGCP
+	}
+	return storageType, cloudProvider
+}
+
 type ObjectStorage interface {
 	GetObject(ctx context.Context, bucketName, objectName string, offset int64, size int64) (FileReader, error)
 	PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64) error
 	StatObject(ctx context.Context, bucketName, objectName string) (int64, error)
+	EtagObject(ctx context.Context, bucketName, objectName string) (string, error)
 	ListObjects(ctx context.Context, bucketName string, prefix string, recursive bool) ([]string, []time.Time, error)
 	RemoveObject(ctx context.Context, bucketName, objectName string) error
 }
@@ -60,16 +87,33 @@ type RemoteChunkManager struct {
 	//	ctx        context.Context
 	bucketName string
 	rootPath   string
+
+	// hedger hedges the reads of MultiRead, nil if the reads are not hedged
+	hedger *readHedger
 }
 
-var _ ChunkManager = (*RemoteChunkManager)(nil)
+var (
+	_ EtagChunkManager      = (*RemoteChunkManager)(nil)
+	_ LifecycleChunkManager = (*RemoteChunkManager)(nil)
+)
 
 func NewRemoteChunkManager(ctx context.Context, c *config) (*RemoteChunkManager, error) {
 	var client ObjectStorage
 	var err error
-	if c.cloudProvider == CloudProviderAzure {
+	// only the S3 compatible storages put the objects with the encryption, the storage class and the tags
+	if c.cloudProvider == CloudProviderAzure || c.cloudProvider == CloudProviderGCPNative || c.cloudProvider == CloudProviderHDFS {
+		if c.hasObjectPolicies() {
+			return nil, errObjectPoliciesNotSupported(c.cloudProvider)
+		}
+	}
+	switch c.cloudProvider {
+	case CloudProviderAzure:
 		client, err = newAzureObjectStorageWithConfig(ctx, c)
-	} else {
+	case CloudProviderGCPNative:
+		client, err = newGcpNativeObjectStorageWithConfig(ctx, c)
+	case CloudProviderHDFS:
+		client, err = newHdfsObjectStorageWithConfig(ctx, c)
+	default:
 		client, err = newMinioObjectStorageWithConfig(ctx, c)
 	}
 	if err != nil {
@@ -80,6 +124,9 @@ func NewRemoteChunkManager(ctx context.Context, c *config) (*RemoteChunkManager,
 		bucketName: c.bucketName,
 		rootPath:   strings.TrimLeft(c.rootPath, "/"),
 	}
+	if c.hedgedReadPercentile > 0 {
+		mcm.hedger = newReadHedger(c.hedgedReadPercentile, c.hedgedReadMinDelay)
+	}
 	log.Info("remote chunk manager init success.", zap.String("remote", c.cloudProvider), zap.String("bucketname", c.bucketName), zap.String("root", mcm.RootPath()))
 	return mcm, nil
 }
@@ -121,6 +168,16 @@ func (mcm *RemoteChunkManager) Size(ctx context.Context, filePath string) (int64
 	return objectInfo, nil
 }
 
+func (mcm *RemoteChunkManager) Etag(ctx context.Context, filePath string) (string, error) {
+	etag, err := mcm.getObjectEtag(ctx, mcm.bucketName, filePath)
+	if err != nil {
+		log.Warn("failed to stat object", zap.String("bucket", mcm.bucketName), zap.String("path", filePath), zap.Error(err))
+		return "", err
+	}
+
+	return etag, nil
+}
+
 // Write writes the data to minio storage.
 func (mcm *RemoteChunkManager) Write(ctx context.Context, filePath string, content []byte) error {
 	err := mcm.putObject(ctx, mcm.bucketName, filePath, bytes.NewReader(content), int64(len(content)))
@@ -195,7 +252,7 @@ func (mcm *RemoteChunkManager) MultiRead(ctx context.Context, keys []string) ([]
 	var el error
 	var objectsValues [][]byte
 	for _, key := range keys {
-		objectValue, err := mcm.Read(ctx, key)
+		objectValue, err := mcm.hedgedRead(ctx, key)
 		if err != nil {
 			el = merr.Combine(el, errors.Wrapf(err, "failed to read %s", key))
 		}
@@ -205,6 +262,22 @@ func (mcm *RemoteChunkManager) MultiRead(ctx context.Context, keys []string) ([]
 	return objectsValues, el
 }
 
+// hedgedRead reads the object, hedged by a duplicate read if it's not responded in time. The size of the object
+// is stat ahead, so the read is hedged by the latencies of the reads of the objects of the similar sizes.
+func (mcm *RemoteChunkManager) hedgedRead(ctx context.Context, filePath string) ([]byte, error) {
+	if mcm.hedger == nil {
+		return mcm.Read(ctx, filePath)
+	}
+	size, err := mcm.getObjectSize(ctx, mcm.bucketName, filePath)
+	if err != nil {
+		log.Warn("failed to stat object", zap.String("bucket", mcm.bucketName), zap.String("path", filePath), zap.Error(err))
+		return nil, err
+	}
+	return mcm.hedger.read(ctx, size, func(ctx context.Context) ([]byte, error) {
+		return mcm.ReadAt(ctx, filePath, 0, size)
+	})
+}
+
 func (mcm *RemoteChunkManager) ReadWithPrefix(ctx context.Context, prefix string) ([]string, [][]byte, error) {
 	objectsKeys, _, err := mcm.ListWithPrefix(ctx, prefix, true)
 	if err != nil {
@@ -312,6 +385,28 @@ func (mcm *RemoteChunkManager) ListWithPrefix(ctx context.Context, prefix string
 	return mcm.listObjects(ctx, mcm.bucketName, prefix, recursive)
 }
 
+func (mcm *RemoteChunkManager) listSizesWithPrefix(ctx context.Context, prefix string, recursive bool) ([]string, []int64, []time.Time, error) {
+	return mcm.listObjectSizes(ctx, mcm.bucketName, prefix, recursive, true)
+}
+
+// LifecycleRules returns the lifecycle rules of Milvus of the bucket, error if the storage doesn't support them.
+func (mcm *RemoteChunkManager) LifecycleRules(ctx context.Context) ([]LifecycleRule, error) {
+	client, ok := mcm.client.(lifecycleObjectStorage)
+	if !ok {
+		return nil, errLifecycleNotSupported(fmt.Sprintf("%T", mcm.client))
+	}
+	return client.GetLifecycleRules(ctx, mcm.bucketName)
+}
+
+// SetLifecycleRules replaces the lifecycle rules of Milvus of the bucket, error if the storage doesn't support them.
+func (mcm *RemoteChunkManager) SetLifecycleRules(ctx context.Context, rules []LifecycleRule) error {
+	client, ok := mcm.client.(lifecycleObjectStorage)
+	if !ok {
+		return errLifecycleNotSupported(fmt.Sprintf("%T", mcm.client))
+	}
+	return client.SetLifecycleRules(ctx, mcm.bucketName, rules)
+}
+
 func (mcm *RemoteChunkManager) getObject(ctx context.Context, bucketName, objectName string,
 	offset int64, size int64,
 ) (FileReader, error) {
@@ -358,10 +453,43 @@ func (mcm *RemoteChunkManager) getObjectSize(ctx context.Context, bucketName, ob
 	return info, err
 }
 
+func (mcm *RemoteChunkManager) getObjectEtag(ctx context.Context, bucketName, objectName string) (string, error) {
+	start := timerecord.NewTimeRecorder("getObjectEtag")
+
+	etag, err := mcm.client.EtagObject(ctx, bucketName, objectName)
+	metrics.PersistentDataOpCounter.WithLabelValues(metrics.DataStatLabel, metrics.TotalLabel).Inc()
+	if err == nil {
+		metrics.PersistentDataRequestLatency.WithLabelValues(metrics.DataStatLabel).
+			Observe(float64(start.ElapseSpan().Milliseconds()))
+		metrics.PersistentDataOpCounter.WithLabelValues(metrics.DataStatLabel, metrics.SuccessLabel).Inc()
+	} else {
+		metrics.PersistentDataOpCounter.WithLabelValues(metrics.DataStatLabel, metrics.FailLabel).Inc()
+	}
+
+	return etag, err
+}
+
 func (mcm *RemoteChunkManager) listObjects(ctx context.Context, bucketName string, prefix string, recursive bool) ([]string, []time.Time, error) {
+	blobNames, _, lastModifiedTime, err := mcm.listObjectSizes(ctx, bucketName, prefix, recursive, false)
+	return blobNames, lastModifiedTime, err
+}
+
+// listObjectSizes lists the objects along with the sizes of them if withSizes and the client lists them,
+// the sizes are nil otherwise.
+func (mcm *RemoteChunkManager) listObjectSizes(ctx context.Context, bucketName string, prefix string, recursive bool,
+	withSizes bool,
+) ([]string, []int64, []time.Time, error) {
 	start := timerecord.NewTimeRecorder("listObjects")
 
-	blobNames, lastModifiedTime, err := mcm.client.ListObjects(ctx, bucketName, prefix, recursive)
+	var blobNames []string
+	var sizes []int64
+	var lastModifiedTime []time.Time
+	var err error
+	if client, ok := mcm.client.(sizedObjectStorage); ok && withSizes {
+		blobNames, sizes, lastModifiedTime, err = client.ListObjectSizes(ctx, bucketName, prefix, recursive)
+	} else {
+		blobNames, lastModifiedTime, err = mcm.client.ListObjects(ctx, bucketName, prefix, recursive)
+	}
 	metrics.PersistentDataOpCounter.WithLabelValues(metrics.DataListLabel, metrics.TotalLabel).Inc()
 	if err == nil {
 		metrics.PersistentDataRequestLatency.WithLabelValues(metrics.DataListLabel).
@@ -371,7 +499,7 @@ func (mcm *RemoteChunkManager) listObjects(ctx context.Context, bucketName strin
 		log.Warn("failed to list with prefix", zap.String("bucket", mcm.bucketName), zap.String("prefix", prefix), zap.Error(err))
 		metrics.PersistentDataOpCounter.WithLabelValues(metrics.DataListLabel, metrics.FailLabel).Inc()
 	}
-	return blobNames, lastModifiedTime, err
+	return blobNames, sizes, lastModifiedTime, err
 }
 
 func (mcm *RemoteChunkManager) removeObject(ctx context.Context, bucketName, objectName string) error {
@@ -406,6 +534,16 @@ func checkObjectStorageError(fileName string, err error) error {
 			return merr.WrapErrIoKeyNotFound(fileName, err.Error())
 		}
 		return merr.WrapErrIoFailed(fileName, err)
+	case *gcsError:
+		if err.StatusCode == http.StatusNotFound {
+			return merr.WrapErrIoKeyNotFound(fileName, err.Error())
+		}
+		return merr.WrapErrIoFailed(fileName, err)
+	case *hdfsError:
+		if err.StatusCode == http.StatusNotFound {
+			return merr.WrapErrIoKeyNotFound(fileName, err.Error())
+		}
+		return merr.WrapErrIoFailed(fileName, err)
 	}
 	return merr.WrapErrIoFailed(fileName, err)
 }
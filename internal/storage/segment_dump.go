@@ -0,0 +1,292 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+// SegmentBinlogPaths groups the object paths of one segment by binlog kind.
+type SegmentBinlogPaths struct {
+	CollectionID UniqueID
+	PartitionID  UniqueID
+	SegmentID    UniqueID
+
+	// Insert maps field id to the insert binlog paths of the field, ordered by log id.
+	Insert map[FieldID][]string
+	Stats  []string
+	Delta  []string
+}
+
+// IsEmpty returns true if no binlog path is recorded.
+func (p *SegmentBinlogPaths) IsEmpty() bool {
+	return len(p.Insert) == 0 && len(p.Stats) == 0 && len(p.Delta) == 0
+}
+
+// InsertPaths returns all insert binlog paths of the segment.
+func (p *SegmentBinlogPaths) InsertPaths() []string {
+	fieldIDs := lo.Keys(p.Insert)
+	sort.Slice(fieldIDs, func(i, j int) bool { return fieldIDs[i] < fieldIDs[j] })
+	paths := make([]string, 0)
+	for _, fieldID := range fieldIDs {
+		paths = append(paths, p.Insert[fieldID]...)
+	}
+	return paths
+}
+
+// NewSegmentBinlogPaths collects the binlog paths recorded in the meta of @segment, ordered by log id.
+// The log paths of @segment must be decompressed, since the paths of the tenants, the path templates and
+// the content-addressed binlogs can't be rebuilt from the ids.
+func NewSegmentBinlogPaths(segment *datapb.SegmentInfo) *SegmentBinlogPaths {
+	result := &SegmentBinlogPaths{
+		CollectionID: segment.GetCollectionID(),
+		PartitionID:  segment.GetPartitionID(),
+		SegmentID:    segment.GetID(),
+		Insert:       make(map[FieldID][]string),
+	}
+	for _, fieldBinlog := range segment.GetBinlogs() {
+		if paths := sortedLogPaths(fieldBinlog); len(paths) > 0 {
+			result.Insert[fieldBinlog.GetFieldID()] = append(result.Insert[fieldBinlog.GetFieldID()], paths...)
+		}
+	}
+	for _, fieldBinlog := range segment.GetStatslogs() {
+		result.Stats = append(result.Stats, sortedLogPaths(fieldBinlog)...)
+	}
+	for _, fieldBinlog := range segment.GetDeltalogs() {
+		result.Delta = append(result.Delta, sortedLogPaths(fieldBinlog)...)
+	}
+	return result
+}
+
+func sortedLogPaths(fieldBinlog *datapb.FieldBinlog) []string {
+	binlogs := lo.Filter(fieldBinlog.GetBinlogs(), func(binlog *datapb.Binlog, _ int) bool {
+		return binlog.GetLogPath() != ""
+	})
+	sort.Slice(binlogs, func(i, j int) bool { return binlogs[i].GetLogID() < binlogs[j].GetLogID() })
+	return lo.Map(binlogs, func(binlog *datapb.Binlog, _ int) string { return binlog.GetLogPath() })
+}
+
+// DumpSegmentBinlogs downloads the binlogs listed in @paths via @cm, decodes them with the
+// storage codecs and writes a human-readable report to @w.
+// At most @limit rows and delete entries are printed, a non-positive @limit prints them all.
+func DumpSegmentBinlogs(ctx context.Context, cm ChunkManager, paths *SegmentBinlogPaths, w io.Writer, limit int) error {
+	fmt.Fprintln(w, "================================================================================")
+	fmt.Fprintf(w, "Segment ID: %d\tCollection ID: %d\tPartition ID: %d\n", paths.SegmentID, paths.CollectionID, paths.PartitionID)
+
+	if err := dumpStatsLogs(ctx, cm, paths.Stats, w); err != nil {
+		return err
+	}
+	if err := dumpInsertLogs(ctx, cm, paths, w, limit); err != nil {
+		return err
+	}
+	return dumpDeltaLogs(ctx, cm, paths.Delta, w, limit)
+}
+
+func readBlobs(ctx context.Context, cm ChunkManager, paths []string) ([]*Blob, error) {
+	values, err := cm.MultiRead(ctx, paths)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(values, func(value []byte, i int) *Blob {
+		return &Blob{Key: paths[i], Value: value}
+	}), nil
+}
+
+// DownloadSegmentStats reads the stats logs in @paths and returns the pk statistics they contain.
+func DownloadSegmentStats(ctx context.Context, cm ChunkManager, paths []string) ([]*PrimaryKeyStats, error) {
+	blobs, err := readBlobs(ctx, cm, paths)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*PrimaryKeyStats, 0, len(blobs))
+	for _, blob := range blobs {
+		var stats []*PrimaryKeyStats
+		if path.Base(blob.Key) == CompoundStatsType.LogIdx() {
+			stats, err = DeserializeStatsList(blob)
+		} else {
+			stats, err = DeserializeStats([]*Blob{blob})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to deserialize stats log %s: %w", blob.Key, err)
+		}
+		result = append(result, stats...)
+	}
+	return result, nil
+}
+
+// DownloadSegmentInsertData reads the insert binlogs in @paths and decodes them into one InsertData.
+func DownloadSegmentInsertData(ctx context.Context, cm ChunkManager, paths []string) (*InsertData, error) {
+	blobs, err := readBlobs(ctx, cm, paths)
+	if err != nil {
+		return nil, err
+	}
+	_, _, _, data, err := NewInsertCodec().DeserializeAll(blobs)
+	return data, err
+}
+
+// DownloadSegmentDeleteData reads the delta logs in @paths and decodes them into one DeleteData,
+// and the deletes recorded by the row offsets into one DeleteBitmap, nil if there is none.
+func DownloadSegmentDeleteData(ctx context.Context, cm ChunkManager, paths []string) (*DeleteData, *DeleteBitmap, error) {
+	blobs, err := readBlobs(ctx, cm, paths)
+	if err != nil {
+		return nil, nil, err
+	}
+	codec := NewDeleteCodec()
+	blobs, bitmap, err := codec.DeserializeBitmaps(blobs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(blobs) == 0 {
+		return NewDeleteData(nil, nil), bitmap, nil
+	}
+	_, _, data, err := codec.Deserialize(blobs)
+	return data, bitmap, err
+}
+
+func dumpStatsLogs(ctx context.Context, cm ChunkManager, paths []string, w io.Writer) error {
+	fmt.Fprintln(w, "--------------------------------------------------------------------------------")
+	fmt.Fprintf(w, "Stats logs: %d\n", len(paths))
+	for _, p := range paths {
+		stats, err := DownloadSegmentStats(ctx, cm, []string{p})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\t%s\n", p)
+		for _, stat := range stats {
+			fmt.Fprintf(w, "\t\tPK FieldID: %d\tPK range: [%v, %v]\n", stat.FieldID, pkValue(stat.MinPk), pkValue(stat.MaxPk))
+		}
+	}
+	return nil
+}
+
+func dumpInsertLogs(ctx context.Context, cm ChunkManager, paths *SegmentBinlogPaths, w io.Writer, limit int) error {
+	insertPaths := paths.InsertPaths()
+	fmt.Fprintln(w, "--------------------------------------------------------------------------------")
+	fmt.Fprintf(w, "Insert logs: %d\n", len(insertPaths))
+	if len(insertPaths) == 0 {
+		return nil
+	}
+
+	data, err := DownloadSegmentInsertData(ctx, cm, insertPaths)
+	if err != nil {
+		return err
+	}
+
+	fieldIDs := lo.Keys(data.Data)
+	sort.Slice(fieldIDs, func(i, j int) bool { return fieldIDs[i] < fieldIDs[j] })
+	for _, fieldID := range fieldIDs {
+		field := data.Data[fieldID]
+		fmt.Fprintf(w, "\tField %d (%s): %d binlogs, %d rows\n", fieldID, field.GetDataType().String(), len(paths.Insert[fieldID]), field.RowNum())
+		if fieldID == common.TimeStampField {
+			if tss, ok := field.(*Int64FieldData); ok && len(tss.Data) > 0 {
+				minTs, maxTs := lo.Min(tss.Data), lo.Max(tss.Data)
+				fmt.Fprintf(w, "\t\tTimestamp range: [%s, %s]\n", formatTs(uint64(minTs)), formatTs(uint64(maxTs)))
+			}
+		}
+	}
+
+	rowNum := data.GetRowNum()
+	if limit > 0 && limit < rowNum {
+		rowNum = limit
+	}
+	fmt.Fprintf(w, "Rows (%d of %d):\n", rowNum, data.GetRowNum())
+	for i := 0; i < rowNum; i++ {
+		row := data.GetRow(i)
+		values := lo.Map(fieldIDs, func(fieldID FieldID, _ int) string {
+			return fmt.Sprintf("%d: %v", fieldID, row[fieldID])
+		})
+		fmt.Fprintf(w, "\t%d\t{%s}\n", i, strings.Join(values, ", "))
+	}
+	return nil
+}
+
+func dumpDeltaLogs(ctx context.Context, cm ChunkManager, paths []string, w io.Writer, limit int) error {
+	fmt.Fprintln(w, "--------------------------------------------------------------------------------")
+	fmt.Fprintf(w, "Delta logs: %d\n", len(paths))
+	if len(paths) == 0 {
+		return nil
+	}
+
+	data, bitmap, err := DownloadSegmentDeleteData(ctx, cm, paths)
+	if err != nil {
+		return err
+	}
+	if bitmap != nil {
+		dumpDeleteBitmap(bitmap, w, limit)
+	}
+	fmt.Fprintf(w, "\tEntries: %d\n", data.RowCount)
+	if data.RowCount == 0 {
+		return nil
+	}
+
+	minPk, maxPk := data.Pks[0], data.Pks[0]
+	for _, pk := range data.Pks {
+		if pk.LT(minPk) {
+			minPk = pk
+		}
+		if pk.GT(maxPk) {
+			maxPk = pk
+		}
+	}
+	fmt.Fprintf(w, "\tPK range: [%v, %v]\n", pkValue(minPk), pkValue(maxPk))
+	fmt.Fprintf(w, "\tTimestamp range: [%s, %s]\n", formatTs(lo.Min(data.Tss)), formatTs(lo.Max(data.Tss)))
+
+	rowNum := int(data.RowCount)
+	if limit > 0 && limit < rowNum {
+		rowNum = limit
+	}
+	fmt.Fprintf(w, "Deletes (%d of %d):\n", rowNum, data.RowCount)
+	for i := 0; i < rowNum; i++ {
+		fmt.Fprintf(w, "\t%d\tpk: %v\tts: %s\n", i, pkValue(data.Pks[i]), formatTs(data.Tss[i]))
+	}
+	return nil
+}
+
+func dumpDeleteBitmap(bitmap *DeleteBitmap, w io.Writer, limit int) {
+	offsets, tss := bitmap.Records()
+	fmt.Fprintf(w, "\tDeleted offsets: %d of %d rows\n", len(offsets), bitmap.NumRows())
+	num := len(offsets)
+	if limit > 0 && limit < num {
+		num = limit
+	}
+	for i := 0; i < num; i++ {
+		fmt.Fprintf(w, "\t%d\toffset: %d\tts: %s\n", i, offsets[i], formatTs(tss[i]))
+	}
+}
+
+func pkValue(pk PrimaryKey) interface{} {
+	if pk == nil {
+		return nil
+	}
+	return pk.GetValue()
+}
+
+func formatTs(ts Timestamp) string {
+	physical, _ := tsoutil.ParseTS(ts)
+	return fmt.Sprintf("%d(%s)", ts, physical.Format("2006-01-02 15:04:05.999 -0700"))
+}
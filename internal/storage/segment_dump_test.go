@@ -0,0 +1,151 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/etcdpb"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+)
+
+func genDumpTestCollectionMeta() *etcdpb.CollectionMeta {
+	return &etcdpb.CollectionMeta{
+		ID: CollectionID,
+		Schema: &schemapb.CollectionSchema{
+			Name: "dump",
+			Fields: []*schemapb.FieldSchema{
+				{FieldID: RowIDField, Name: "row_id", DataType: schemapb.DataType_Int64},
+				{FieldID: TimestampField, Name: "Timestamp", DataType: schemapb.DataType_Int64},
+				{FieldID: Int64Field, Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+				{FieldID: StringField, Name: "str", DataType: schemapb.DataType_VarChar},
+			},
+		},
+	}
+}
+
+// writeTestSegment serializes a small segment with one insert batch, its pk stats and one delta log,
+// and returns the segment meta recording the log paths.
+func writeTestSegment(t *testing.T, cm ChunkManager, insertData *InsertData, deleteData *DeleteData) *datapb.SegmentInfo {
+	ctx := context.Background()
+	codec := NewInsertCodecWithSchema(genDumpTestCollectionMeta())
+	segment := &datapb.SegmentInfo{
+		ID:           SegmentID,
+		CollectionID: CollectionID,
+		PartitionID:  PartitionID,
+	}
+
+	blobs, err := codec.Serialize(PartitionID, SegmentID, insertData)
+	require.NoError(t, err)
+	logID := int64(1000)
+	for _, blob := range blobs {
+		fieldID, err := strconv.ParseInt(blob.GetKey(), 10, 64)
+		require.NoError(t, err)
+		key := metautil.BuildInsertLogPath(cm.RootPath(), CollectionID, PartitionID, SegmentID, fieldID, logID)
+		require.NoError(t, cm.Write(ctx, key, blob.GetValue()))
+		segment.Binlogs = append(segment.Binlogs, &datapb.FieldBinlog{
+			FieldID: fieldID,
+			Binlogs: []*datapb.Binlog{{LogID: logID, LogPath: key}},
+		})
+		logID++
+	}
+
+	statsBlob, err := codec.SerializePkStatsByData(insertData)
+	require.NoError(t, err)
+	key := metautil.BuildStatsLogPath(cm.RootPath(), CollectionID, PartitionID, SegmentID, Int64Field, logID)
+	require.NoError(t, cm.Write(ctx, key, statsBlob.GetValue()))
+	segment.Statslogs = []*datapb.FieldBinlog{{
+		FieldID: Int64Field,
+		Binlogs: []*datapb.Binlog{{LogID: logID, LogPath: key}},
+	}}
+	logID++
+
+	if deleteData != nil {
+		deltaBlob, err := NewDeleteCodec().Serialize(CollectionID, PartitionID, SegmentID, deleteData)
+		require.NoError(t, err)
+		key = metautil.BuildDeltaLogPath(cm.RootPath(), CollectionID, PartitionID, SegmentID, logID)
+		require.NoError(t, cm.Write(ctx, key, deltaBlob.GetValue()))
+		segment.Deltalogs = []*datapb.FieldBinlog{{
+			Binlogs: []*datapb.Binlog{{LogID: logID, LogPath: key}},
+		}}
+	}
+	return segment
+}
+
+func TestDumpSegmentBinlogs(t *testing.T) {
+	ctx := context.Background()
+	cm := NewLocalChunkManager(RootPath(path.Join(localPath, "segment_dump")))
+	defer cm.RemoveWithPrefix(ctx, cm.RootPath())
+
+	insertData := &InsertData{
+		Data: map[int64]FieldData{
+			RowIDField:     &Int64FieldData{Data: []int64{1, 2, 3}},
+			TimestampField: &Int64FieldData{Data: []int64{100, 101, 102}},
+			Int64Field:     &Int64FieldData{Data: []int64{10, 20, 30}},
+			StringField:    &StringFieldData{Data: []string{"a", "b", "c"}, DataType: schemapb.DataType_VarChar},
+		},
+	}
+	deleteData := NewDeleteData([]PrimaryKey{NewInt64PrimaryKey(20)}, []Timestamp{200})
+	paths := NewSegmentBinlogPaths(writeTestSegment(t, cm, insertData, deleteData))
+	assert.False(t, paths.IsEmpty())
+	assert.Len(t, paths.Insert, 4)
+	assert.Len(t, paths.InsertPaths(), 4)
+	assert.Len(t, paths.Stats, 1)
+	assert.Len(t, paths.Delta, 1)
+
+	t.Run("dump all", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := DumpSegmentBinlogs(ctx, cm, paths, buf, 0)
+		require.NoError(t, err)
+		output := buf.String()
+		assert.Contains(t, output, "PK range: [10, 30]")
+		assert.Contains(t, output, "Rows (3 of 3)")
+		assert.Contains(t, output, "Entries: 1")
+		assert.Contains(t, output, "pk: 20")
+	})
+
+	t.Run("dump with limit", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := DumpSegmentBinlogs(ctx, cm, paths, buf, 1)
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "Rows (1 of 3)")
+	})
+
+	t.Run("empty segment", func(t *testing.T) {
+		paths := NewSegmentBinlogPaths(&datapb.SegmentInfo{ID: SegmentID + 1, CollectionID: CollectionID, PartitionID: PartitionID})
+		assert.True(t, paths.IsEmpty())
+
+		buf := &bytes.Buffer{}
+		err := DumpSegmentBinlogs(ctx, cm, paths, buf, 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("read failed", func(t *testing.T) {
+		broken := &SegmentBinlogPaths{Delta: []string{path.Join(cm.RootPath(), "not_exist")}}
+		err := DumpSegmentBinlogs(ctx, cm, broken, &bytes.Buffer{}, 0)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,156 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+)
+
+// InventoryBinlog is a binlog listed in the segment inventory.
+type InventoryBinlog struct {
+	Path     string `json:"path"`
+	LogType  string `json:"log_type"`
+	FieldID  int64  `json:"field_id"`
+	Size     int64  `json:"size"`
+	Checksum uint32 `json:"checksum,omitempty"`
+	RowCount int64  `json:"row_count"`
+}
+
+// SegmentInventory lists the binlogs of a segment uploaded together, it's uploaded along with the binlogs,
+// so the backup tools and the garbage collector read the binlogs of a segment from the storage alone.
+// A segment uploaded in several groups has an inventory of each group, see MergeSegmentInventories.
+type SegmentInventory struct {
+	CollectionID int64              `json:"collection_id"`
+	PartitionID  int64              `json:"partition_id"`
+	SegmentID    int64              `json:"segment_id"`
+	Binlogs      []*InventoryBinlog `json:"binlogs"`
+}
+
+// NewSegmentInventory returns an empty inventory of the segment.
+func NewSegmentInventory(collectionID, partitionID, segmentID int64) *SegmentInventory {
+	return &SegmentInventory{
+		CollectionID: collectionID,
+		PartitionID:  partitionID,
+		SegmentID:    segmentID,
+		Binlogs:      make([]*InventoryBinlog, 0),
+	}
+}
+
+// AddFieldBinlogs lists the binlogs of the fields of the log type, i.e. common.SegmentInsertLogPath and so on.
+func (inv *SegmentInventory) AddFieldBinlogs(logType string, fieldBinlogs ...*datapb.FieldBinlog) {
+	for _, fieldBinlog := range fieldBinlogs {
+		for _, binlog := range fieldBinlog.GetBinlogs() {
+			inv.Binlogs = append(inv.Binlogs, &InventoryBinlog{
+				Path:     binlog.GetLogPath(),
+				LogType:  logType,
+				FieldID:  fieldBinlog.GetFieldID(),
+				Size:     binlog.GetLogSize(),
+				Checksum: binlog.GetChecksum(),
+				RowCount: binlog.GetEntriesNum(),
+			})
+		}
+	}
+}
+
+// Name returns the name of the inventory, i.e. the greatest log id of the binlogs of it, so the inventory
+// of a group uploaded again is overwritten, and the compound statslog uploaded along with the binlogs of a batch
+// doesn't name the inventory of the batch after it.
+func (inv *SegmentInventory) Name() string {
+	var logID int64
+	for _, binlog := range inv.Binlogs {
+		if id, err := strconv.ParseInt(path.Base(binlog.Path), 10, 64); err == nil && id > logID {
+			logID = id
+		}
+	}
+	return strconv.FormatInt(logID, 10)
+}
+
+// Key returns the key of the inventory under the root path of the binlogs, with the layout dir if any.
+func (inv *SegmentInventory) Key(rootPath string) string {
+	return path.Join(SegmentInventoryPrefix(rootPath, inv.CollectionID, inv.PartitionID, inv.SegmentID), inv.Name())
+}
+
+// SegmentInventoryPrefix returns the prefix of the inventories of the segment under the root path.
+func SegmentInventoryPrefix(rootPath string, collectionID, partitionID, segmentID int64) string {
+	return path.Join(rootPath, common.SegmentInventoryPath, metautil.JoinIDPath(collectionID, partitionID, segmentID)) + "/"
+}
+
+// Marshal returns the inventory encoded in json.
+func (inv *SegmentInventory) Marshal() ([]byte, error) {
+	return json.Marshal(inv)
+}
+
+// UnmarshalSegmentInventory decodes the inventory in json.
+func UnmarshalSegmentInventory(data []byte) (*SegmentInventory, error) {
+	inv := &SegmentInventory{}
+	if err := json.Unmarshal(data, inv); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// MergeSegmentInventories merges the inventories of the groups of a segment into one, the binlogs listed
+// by several inventories, i.e. uploaded again, are listed once by the latter. The binlogs are sorted by the paths.
+func MergeSegmentInventories(invs ...*SegmentInventory) *SegmentInventory {
+	if len(invs) == 0 {
+		return nil
+	}
+	merged := NewSegmentInventory(invs[0].CollectionID, invs[0].PartitionID, invs[0].SegmentID)
+	binlogs := make(map[string]*InventoryBinlog)
+	for _, inv := range invs {
+		for _, binlog := range inv.Binlogs {
+			binlogs[binlog.Path] = binlog
+		}
+	}
+	for _, binlog := range binlogs {
+		merged.Binlogs = append(merged.Binlogs, binlog)
+	}
+	sort.Slice(merged.Binlogs, func(i, j int) bool {
+		return merged.Binlogs[i].Path < merged.Binlogs[j].Path
+	})
+	return merged
+}
+
+// ReadSegmentInventory reads the inventories of the segment under the root path and merges them,
+// nil is returned if the segment has no inventory.
+func ReadSegmentInventory(ctx context.Context, cm ChunkManager, rootPath string, collectionID, partitionID, segmentID int64) (*SegmentInventory, error) {
+	keys, _, err := cm.ListWithPrefix(ctx, SegmentInventoryPrefix(rootPath, collectionID, partitionID, segmentID), true)
+	if err != nil {
+		return nil, err
+	}
+	invs := make([]*SegmentInventory, 0, len(keys))
+	for _, key := range keys {
+		data, err := cm.Read(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		inv, err := UnmarshalSegmentInventory(data)
+		if err != nil {
+			return nil, err
+		}
+		invs = append(invs, inv)
+	}
+	return MergeSegmentInventories(invs...), nil
+}
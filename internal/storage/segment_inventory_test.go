@@ -0,0 +1,80 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+func TestSegmentInventory(t *testing.T) {
+	ctx := context.Background()
+	cm := NewLocalChunkManager(RootPath("/tmp/milvus_test/segment_inventory"))
+	defer cm.RemoveWithPrefix(ctx, cm.RootPath())
+
+	first := NewSegmentInventory(1, 2, 3)
+	first.AddFieldBinlogs(common.SegmentInsertLogPath,
+		&datapb.FieldBinlog{FieldID: 100, Binlogs: []*datapb.Binlog{{LogPath: "insert_log/1/2/3/100/1000", LogSize: 10, EntriesNum: 5, Checksum: 7}}},
+		&datapb.FieldBinlog{FieldID: 101, Binlogs: []*datapb.Binlog{{LogPath: "insert_log/1/2/3/101/1001", LogSize: 20, EntriesNum: 5}}},
+	)
+	first.AddFieldBinlogs(common.SegmentStatslogPath,
+		&datapb.FieldBinlog{FieldID: 100, Binlogs: []*datapb.Binlog{{LogPath: "stats_log/1/2/3/100/1", LogSize: 5, EntriesNum: 5}}},
+	)
+	// named after the greatest log id rather than the compound statslog
+	assert.Equal(t, "1001", first.Name())
+	assert.Equal(t, "root/segment_inventory/1/2/3/1001", first.Key("root"))
+
+	second := NewSegmentInventory(1, 2, 3)
+	second.AddFieldBinlogs(common.SegmentDeltaLogPath,
+		&datapb.FieldBinlog{FieldID: 100, Binlogs: []*datapb.Binlog{{LogPath: "delta_log/1/2/3/1002", LogSize: 8, EntriesNum: 1}}},
+	)
+	for _, inv := range []*SegmentInventory{first, second} {
+		data, err := inv.Marshal()
+		require.NoError(t, err)
+		require.NoError(t, cm.Write(ctx, inv.Key(cm.RootPath()), data))
+	}
+
+	merged, err := ReadSegmentInventory(ctx, cm, cm.RootPath(), 1, 2, 3)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, merged.SegmentID)
+	require.Equal(t, 4, len(merged.Binlogs))
+	assert.Equal(t, &InventoryBinlog{
+		Path:     "delta_log/1/2/3/1002",
+		LogType:  common.SegmentDeltaLogPath,
+		FieldID:  100,
+		Size:     8,
+		RowCount: 1,
+	}, merged.Binlogs[0])
+	assert.Equal(t, "insert_log/1/2/3/100/1000", merged.Binlogs[1].Path)
+	assert.EqualValues(t, 7, merged.Binlogs[1].Checksum)
+
+	// the binlogs uploaded again are listed once
+	assert.Equal(t, 4, len(MergeSegmentInventories(first, second, first).Binlogs))
+
+	inv, err := ReadSegmentInventory(ctx, cm, cm.RootPath(), 1, 2, 4)
+	require.NoError(t, err)
+	assert.Nil(t, inv)
+
+	_, err = UnmarshalSegmentInventory([]byte("invalid"))
+	assert.Error(t, err)
+}
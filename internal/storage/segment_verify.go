@@ -0,0 +1,166 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+// maxReportedPks limits how many offending primary keys are listed per mismatch kind.
+const maxReportedPks = 10
+
+// SegmentVerifyResult is the outcome of VerifySegmentBinlogs.
+type SegmentVerifyResult struct {
+	InsertRows int64
+	DeleteRows int64
+	// Mismatches lists every inconsistency found between stats, insert and delta logs.
+	Mismatches []string
+}
+
+// OK returns true if no mismatch was found.
+func (r *SegmentVerifyResult) OK() bool {
+	return len(r.Mismatches) == 0
+}
+
+func (r *SegmentVerifyResult) addMismatch(format string, args ...interface{}) {
+	r.Mismatches = append(r.Mismatches, fmt.Sprintf(format, args...))
+}
+
+// VerifySegmentBinlogs cross-checks the stats logs of a segment against its insert and delta logs.
+// It reports insert fields with different row counts, primary keys outside the stats pk range or
+// rejected by the bloom filter, and deleted primary keys which do not exist in the insert data.
+// If @expectedRows is not negative, it is compared with the number of rows in the insert logs.
+func VerifySegmentBinlogs(ctx context.Context, cm ChunkManager, paths *SegmentBinlogPaths, expectedRows int64) (*SegmentVerifyResult, error) {
+	result := &SegmentVerifyResult{}
+
+	stats, err := DownloadSegmentStats(ctx, cm, paths.Stats)
+	if err != nil {
+		return nil, err
+	}
+
+	var data *InsertData
+	if insertPaths := paths.InsertPaths(); len(insertPaths) > 0 {
+		data, err = DownloadSegmentInsertData(ctx, cm, insertPaths)
+		if err != nil {
+			return nil, err
+		}
+		result.InsertRows = int64(data.GetRowNum())
+		for fieldID, field := range data.Data {
+			if int64(field.RowNum()) != result.InsertRows {
+				result.addMismatch("field %d has %d rows, expected %d", fieldID, field.RowNum(), result.InsertRows)
+			}
+		}
+	}
+	if expectedRows >= 0 && expectedRows != result.InsertRows {
+		result.addMismatch("insert logs contain %d rows, expected %d", result.InsertRows, expectedRows)
+	}
+
+	var (
+		deleteData   *DeleteData
+		deleteBitmap *DeleteBitmap
+	)
+	if len(paths.Delta) > 0 {
+		deleteData, deleteBitmap, err = DownloadSegmentDeleteData(ctx, cm, paths.Delta)
+		if err != nil {
+			return nil, err
+		}
+		result.DeleteRows = deleteData.RowCount
+		if deleteBitmap != nil {
+			result.DeleteRows += int64(deleteBitmap.Len())
+			if deleteBitmap.NumRows() != result.InsertRows {
+				result.addMismatch("delete bitmap of %d rows, expected %d", deleteBitmap.NumRows(), result.InsertRows)
+			}
+		}
+	}
+
+	if result.InsertRows == 0 {
+		if len(stats) > 0 {
+			result.addMismatch("segment has %d stats but no insert data", len(stats))
+		}
+		return result, nil
+	}
+	if len(stats) == 0 {
+		result.addMismatch("segment has %d rows but no stats log", result.InsertRows)
+		return result, nil
+	}
+
+	pkFieldID := stats[0].FieldID
+	pkType := schemapb.DataType(stats[0].PkType)
+	pkField, ok := data.Data[pkFieldID]
+	if !ok {
+		result.addMismatch("primary key field %d in stats log not found in insert logs", pkFieldID)
+		return result, nil
+	}
+
+	pkStats := make([]*PkStatistics, 0, len(stats))
+	for _, stat := range stats {
+		if stat.FieldID != pkFieldID {
+			result.addMismatch("stats logs refer to different primary key fields %d and %d", pkFieldID, stat.FieldID)
+			continue
+		}
+		pkStats = append(pkStats, &PkStatistics{PkFilter: stat.BF, MinPK: stat.MinPk, MaxPK: stat.MaxPk})
+	}
+
+	insertedPks := make(map[interface{}]struct{}, pkField.RowNum())
+	missing := make([]interface{}, 0)
+	for i := 0; i < pkField.RowNum(); i++ {
+		pk, err := GenPrimaryKeyByRawData(pkField.GetRow(i), pkType)
+		if err != nil {
+			return nil, err
+		}
+		insertedPks[pk.GetValue()] = struct{}{}
+		if !pkExistInStats(pkStats, pk) {
+			missing = append(missing, pk.GetValue())
+		}
+	}
+	if len(missing) > 0 {
+		result.addMismatch("%d primary keys not covered by stats log, first ones: %v", len(missing), firstN(missing))
+	}
+
+	if deleteData != nil {
+		unknown := make([]interface{}, 0)
+		for _, pk := range deleteData.Pks {
+			if _, ok := insertedPks[pk.GetValue()]; !ok {
+				unknown = append(unknown, pk.GetValue())
+			}
+		}
+		if len(unknown) > 0 {
+			result.addMismatch("%d deleted primary keys not found in insert logs, first ones: %v", len(unknown), firstN(unknown))
+		}
+	}
+
+	return result, nil
+}
+
+func pkExistInStats(stats []*PkStatistics, pk PrimaryKey) bool {
+	for _, stat := range stats {
+		if stat.PkExist(pk) {
+			return true
+		}
+	}
+	return false
+}
+
+func firstN(values []interface{}) []interface{} {
+	if len(values) > maxReportedPks {
+		return values[:maxReportedPks]
+	}
+	return values
+}
@@ -0,0 +1,99 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+)
+
+func TestVerifySegmentBinlogs(t *testing.T) {
+	ctx := context.Background()
+	cm := NewLocalChunkManager(RootPath(path.Join(localPath, "segment_verify")))
+	defer cm.RemoveWithPrefix(ctx, cm.RootPath())
+
+	insertData := &InsertData{
+		Data: map[int64]FieldData{
+			RowIDField:     &Int64FieldData{Data: []int64{1, 2, 3}},
+			TimestampField: &Int64FieldData{Data: []int64{100, 101, 102}},
+			Int64Field:     &Int64FieldData{Data: []int64{10, 20, 30}},
+			StringField:    &StringFieldData{Data: []string{"a", "b", "c"}, DataType: schemapb.DataType_VarChar},
+		},
+	}
+	paths := NewSegmentBinlogPaths(writeTestSegment(t, cm, insertData, NewDeleteData([]PrimaryKey{NewInt64PrimaryKey(20)}, []Timestamp{200})))
+
+	t.Run("consistent", func(t *testing.T) {
+		result, err := VerifySegmentBinlogs(ctx, cm, paths, 3)
+		require.NoError(t, err)
+		assert.True(t, result.OK(), result.Mismatches)
+		assert.EqualValues(t, 3, result.InsertRows)
+		assert.EqualValues(t, 1, result.DeleteRows)
+	})
+
+	t.Run("row count mismatch", func(t *testing.T) {
+		result, err := VerifySegmentBinlogs(ctx, cm, paths, 4)
+		require.NoError(t, err)
+		assert.False(t, result.OK())
+		assert.Len(t, result.Mismatches, 1)
+	})
+
+	t.Run("missing stats", func(t *testing.T) {
+		noStats := *paths
+		noStats.Stats = nil
+		result, err := VerifySegmentBinlogs(ctx, cm, &noStats, -1)
+		require.NoError(t, err)
+		assert.False(t, result.OK())
+	})
+
+	t.Run("pk not covered by stats", func(t *testing.T) {
+		stats, err := NewPrimaryKeyStats(Int64Field, int64(schemapb.DataType_Int64), 1)
+		require.NoError(t, err)
+		stats.Update(NewInt64PrimaryKey(10))
+		blob, err := NewInsertCodec().SerializePkStats(stats, 1)
+		require.NoError(t, err)
+		key := metautil.BuildStatsLogPath(cm.RootPath(), CollectionID, PartitionID, SegmentID+1, Int64Field, 2000)
+		require.NoError(t, cm.Write(ctx, key, blob.GetValue()))
+
+		broken := *paths
+		broken.Stats = []string{key}
+		result, err := VerifySegmentBinlogs(ctx, cm, &broken, -1)
+		require.NoError(t, err)
+		assert.False(t, result.OK())
+		assert.Contains(t, result.Mismatches[0], "2 primary keys not covered by stats log")
+	})
+
+	t.Run("unknown deleted pk", func(t *testing.T) {
+		deltaBlob, err := NewDeleteCodec().Serialize(CollectionID, PartitionID, SegmentID, NewDeleteData([]PrimaryKey{NewInt64PrimaryKey(40)}, []Timestamp{200}))
+		require.NoError(t, err)
+		key := metautil.BuildDeltaLogPath(cm.RootPath(), CollectionID, PartitionID, SegmentID+1, 2001)
+		require.NoError(t, cm.Write(ctx, key, deltaBlob.GetValue()))
+
+		broken := *paths
+		broken.Delta = []string{key}
+		result, err := VerifySegmentBinlogs(ctx, cm, &broken, -1)
+		require.NoError(t, err)
+		assert.False(t, result.OK())
+		assert.Contains(t, result.Mismatches[0], "deleted primary keys not found")
+	})
+}
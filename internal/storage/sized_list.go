@@ -0,0 +1,43 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// sizedLister is the ChunkManager listing the sizes of the objects along with the keys,
+// which spares a stat of every object listed.
+type sizedLister interface {
+	listSizesWithPrefix(ctx context.Context, prefix string, recursive bool) ([]string, []int64, []time.Time, error)
+}
+
+// sizedObjectStorage is the ObjectStorage listing the sizes of the objects along with the keys.
+type sizedObjectStorage interface {
+	ListObjectSizes(ctx context.Context, bucketName string, prefix string, recursive bool) ([]string, []int64, []time.Time, error)
+}
+
+// ListSizesWithPrefix lists the objects with @prefix like ChunkManager.ListWithPrefix, along with the sizes of them
+// if the ChunkManager lists them, the sizes are nil otherwise.
+func ListSizesWithPrefix(ctx context.Context, cm ChunkManager, prefix string, recursive bool) ([]string, []int64, []time.Time, error) {
+	if lister, ok := cm.(sizedLister); ok {
+		return lister.listSizesWithPrefix(ctx, prefix, recursive)
+	}
+	keys, modTimes, err := cm.ListWithPrefix(ctx, prefix, recursive)
+	return keys, nil, modTimes, err
+}
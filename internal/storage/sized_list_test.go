@@ -0,0 +1,50 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSizesWithPrefix(t *testing.T) {
+	ctx := context.Background()
+	rootPath := t.TempDir()
+	cm := NewLocalChunkManager(RootPath(rootPath))
+	require.NoError(t, cm.Write(ctx, path.Join(rootPath, "a", "1"), []byte("data")))
+	require.NoError(t, cm.Write(ctx, path.Join(rootPath, "a", "2"), []byte("datadata")))
+
+	keys, sizes, modTimes, err := ListSizesWithPrefix(ctx, cm, path.Join(rootPath, "a"), true)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{path.Join(rootPath, "a", "1"), path.Join(rootPath, "a", "2")}, keys)
+	assert.Len(t, modTimes, 2)
+	for i, key := range keys {
+		size, err := cm.Size(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, size, sizes[i])
+	}
+
+	// the sizes are not listed by the chunk managers not supporting it
+	keys, sizes, _, err = ListSizesWithPrefix(ctx, NewFaultInjectChunkManager(cm), path.Join(rootPath, "a"), true)
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Nil(t, sizes)
+}
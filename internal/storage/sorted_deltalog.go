@@ -0,0 +1,283 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+// The sorted deltalog is the v2 layout of the deltalog, the deletes are sorted by the primary keys and then
+// the timestamps, and the payload is of the type of the primary key, the primary keys followed by the timestamps,
+// instead of the json of each delete. The primary keys at every deltalogIndexInterval rows are kept in the
+// descriptor extras as the sparse index, by which the latest delete of a primary key is looked up.
+const (
+	deltalogPKIndexKey    = "pk_index"
+	deltalogIndexInterval = 1024
+)
+
+// SortedDeleteData is the delete data sorted by the primary keys and then the timestamps.
+type SortedDeleteData struct {
+	Pks []PrimaryKey
+	Tss []Timestamp
+	// index is the primary keys at every deltalogIndexInterval rows
+	index []PrimaryKey
+}
+
+// NewSortedDeleteData sorts the delete data into SortedDeleteData, the delete data is not modified.
+func NewSortedDeleteData(data *DeleteData) *SortedDeleteData {
+	order := make([]int, len(data.Pks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		pi, pj := data.Pks[order[i]], data.Pks[order[j]]
+		if !pi.EQ(pj) {
+			return pi.LT(pj)
+		}
+		return data.Tss[order[i]] < data.Tss[order[j]]
+	})
+	sorted := &SortedDeleteData{
+		Pks: make([]PrimaryKey, 0, len(order)),
+		Tss: make([]Timestamp, 0, len(order)),
+	}
+	for _, i := range order {
+		sorted.Pks = append(sorted.Pks, data.Pks[i])
+		sorted.Tss = append(sorted.Tss, data.Tss[i])
+	}
+	sorted.buildIndex()
+	return sorted
+}
+
+func (d *SortedDeleteData) buildIndex() {
+	d.index = make([]PrimaryKey, 0, (len(d.Pks)+deltalogIndexInterval-1)/deltalogIndexInterval)
+	for i := 0; i < len(d.Pks); i += deltalogIndexInterval {
+		d.index = append(d.index, d.Pks[i])
+	}
+}
+
+// Len returns the number of the deletes.
+func (d *SortedDeleteData) Len() int {
+	return len(d.Pks)
+}
+
+// search returns the position of the first delete whose primary key is beyond pk, i.e. greater than pk if after,
+// or not less than pk otherwise. The block of it is found by the sparse index first, and then the position in the block.
+func (d *SortedDeleteData) search(pk PrimaryKey, after bool) int {
+	beyond := func(key PrimaryKey) bool {
+		if after {
+			return key.GT(pk)
+		}
+		return key.GE(pk)
+	}
+	// the first block whose first primary key is beyond pk, the position is in the block before it
+	block := sort.Search(len(d.index), func(i int) bool { return beyond(d.index[i]) })
+	if block == 0 {
+		return 0
+	}
+	start := (block - 1) * deltalogIndexInterval
+	end := lo.Min([]int{block * deltalogIndexInterval, len(d.Pks)})
+	return start + sort.Search(end-start, func(i int) bool { return beyond(d.Pks[start+i]) })
+}
+
+// LatestTs returns the timestamp of the latest delete of the primary key, false if it's not deleted.
+func (d *SortedDeleteData) LatestTs(pk PrimaryKey) (Timestamp, bool) {
+	// the position after the last delete of pk
+	pos := d.search(pk, true)
+	if pos == 0 || !d.Pks[pos-1].EQ(pk) {
+		return 0, false
+	}
+	return d.Tss[pos-1], true
+}
+
+// Range returns the positions [start, end) of the deletes whose primary keys are within [lower, upper].
+func (d *SortedDeleteData) Range(lower PrimaryKey, upper PrimaryKey) (int, int) {
+	start, end := d.search(lower, false), d.search(upper, true)
+	if end < start {
+		return start, start
+	}
+	return start, end
+}
+
+// DeleteData returns the deletes as the DeleteData in the sorted order.
+func (d *SortedDeleteData) DeleteData() *DeleteData {
+	return NewDeleteData(d.Pks, d.Tss)
+}
+
+// serializeSorted serializes the deletes sorted by the primary keys into a sorted deltalog.
+func (deleteCodec *DeleteCodec) serializeSorted(collectionID UniqueID, partitionID UniqueID, segmentID UniqueID, data *SortedDeleteData, chunk *deleteChunk) (*Blob, error) {
+	if data.Len() == 0 {
+		return nil, fmt.Errorf("the delete data of segment %d is empty", segmentID)
+	}
+	pkType := data.Pks[0].Type()
+	binlogWriter := NewDeleteBinlogWriter(pkType, collectionID, partitionID, segmentID)
+	defer binlogWriter.Close()
+	eventWriter, err := binlogWriter.NextDeleteEventWriter()
+	if err != nil {
+		return nil, err
+	}
+	defer eventWriter.Close()
+
+	var startTs, endTs Timestamp = math.MaxUint64, 0
+	for _, ts := range data.Tss {
+		startTs = lo.Min([]Timestamp{startTs, ts})
+		endTs = lo.Max([]Timestamp{endTs, ts})
+	}
+	var index []byte
+	switch pkType {
+	case schemapb.DataType_Int64:
+		payload := make([]int64, 0, 2*data.Len())
+		for _, pk := range data.Pks {
+			payload = append(payload, pk.GetValue().(int64))
+		}
+		for _, ts := range data.Tss {
+			payload = append(payload, int64(ts))
+		}
+		if err = eventWriter.AddInt64ToPayload(payload); err != nil {
+			return nil, err
+		}
+		index, err = json.Marshal(lo.Map(data.index, func(pk PrimaryKey, _ int) int64 { return pk.GetValue().(int64) }))
+	case schemapb.DataType_VarChar:
+		for _, pk := range data.Pks {
+			if err = eventWriter.AddOneStringToPayload(pk.GetValue().(string)); err != nil {
+				return nil, err
+			}
+		}
+		for _, ts := range data.Tss {
+			if err = eventWriter.AddOneStringToPayload(strconv.FormatUint(ts, 10)); err != nil {
+				return nil, err
+			}
+		}
+		index, err = json.Marshal(lo.Map(data.index, func(pk PrimaryKey, _ int) string { return pk.GetValue().(string) }))
+	default:
+		return nil, fmt.Errorf("unsupported primary key type %s", pkType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	eventWriter.SetEventTimestamp(startTs, endTs)
+	if chunk != nil {
+		binlogWriter.SetEventTimeStamp(chunk.startTs, chunk.endTs)
+		binlogWriter.AddExtra(deltalogChunkIndexKey, strconv.Itoa(chunk.index))
+		binlogWriter.AddExtra(deltalogChunkNumKey, strconv.Itoa(chunk.num))
+		binlogWriter.AddFeature(BinlogFeatureDeltalogChunk, false)
+	} else {
+		binlogWriter.SetEventTimeStamp(startTs, endTs)
+	}
+	binlogWriter.AddExtra(deltalogPKIndexKey, string(index))
+	binlogWriter.AddFeature(BinlogFeatureDeltalogSorted, true)
+	size := lo.SumBy(data.Pks, func(pk PrimaryKey) int64 { return pk.Size() }) + int64(8*data.Len())
+	binlogWriter.AddExtra(originalSizeKey, fmt.Sprintf("%v", size))
+
+	if err = binlogWriter.Finish(); err != nil {
+		return nil, err
+	}
+	buffer, err := binlogWriter.GetBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return &Blob{
+		Value:  buffer,
+		RowNum: int64(data.Len()),
+	}, nil
+}
+
+// deserializeSorted reads the deletes of the sorted deltalog.
+func deserializeSorted(binlogReader *BinlogReader) (*SortedDeleteData, error) {
+	indexStr, ok := binlogReader.Extras[deltalogPKIndexKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%v not in extra of the sorted deltalog", deltalogPKIndexKey)
+	}
+	data := &SortedDeleteData{}
+	for {
+		eventReader, err := binlogReader.NextEventReader()
+		if err != nil {
+			return nil, err
+		}
+		if eventReader == nil {
+			break
+		}
+		err = func() error {
+			defer eventReader.Close()
+			switch binlogReader.PayloadDataType {
+			case schemapb.DataType_Int64:
+				payload, err := eventReader.GetInt64FromPayload()
+				if err != nil {
+					return err
+				}
+				if len(payload)%2 != 0 {
+					return fmt.Errorf("sorted deltalog has %d values, which are not pairs", len(payload))
+				}
+				n := len(payload) / 2
+				for i := 0; i < n; i++ {
+					data.Pks = append(data.Pks, NewInt64PrimaryKey(payload[i]))
+					data.Tss = append(data.Tss, Timestamp(payload[n+i]))
+				}
+			case schemapb.DataType_VarChar:
+				payload, err := eventReader.GetStringFromPayload()
+				if err != nil {
+					return err
+				}
+				if len(payload)%2 != 0 {
+					return fmt.Errorf("sorted deltalog has %d values, which are not pairs", len(payload))
+				}
+				n := len(payload) / 2
+				for i := 0; i < n; i++ {
+					ts, err := strconv.ParseUint(payload[n+i], 10, 64)
+					if err != nil {
+						return err
+					}
+					data.Pks = append(data.Pks, NewVarCharPrimaryKey(payload[i]))
+					data.Tss = append(data.Tss, ts)
+				}
+			default:
+				return fmt.Errorf("unsupported primary key type %s", binlogReader.PayloadDataType)
+			}
+			return nil
+		}()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch binlogReader.PayloadDataType {
+	case schemapb.DataType_Int64:
+		var index []int64
+		if err := json.Unmarshal([]byte(indexStr), &index); err != nil {
+			return nil, err
+		}
+		data.index = lo.Map(index, func(pk int64, _ int) PrimaryKey { return NewInt64PrimaryKey(pk) })
+	default:
+		var index []string
+		if err := json.Unmarshal([]byte(indexStr), &index); err != nil {
+			return nil, err
+		}
+		data.index = lo.Map(index, func(pk string, _ int) PrimaryKey { return NewVarCharPrimaryKey(pk) })
+	}
+	if len(data.index) != (data.Len()+deltalogIndexInterval-1)/deltalogIndexInterval {
+		return nil, fmt.Errorf("sorted deltalog of %d deletes has %d index entries", data.Len(), len(data.index))
+	}
+	return data, nil
+}
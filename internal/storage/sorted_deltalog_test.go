@@ -0,0 +1,150 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortedDeleteData(t *testing.T) {
+	data := NewDeleteData(nil, nil)
+	// the deletes of the even pks in the reverse order, each of them deleted twice
+	for i := 3000; i > 0; i-- {
+		data.Append(NewInt64PrimaryKey(int64(2*i)), Timestamp(i))
+		data.Append(NewInt64PrimaryKey(int64(2*i)), Timestamp(10000+i))
+	}
+	sorted := NewSortedDeleteData(data)
+	assert.Equal(t, 6000, sorted.Len())
+	assert.Len(t, sorted.index, 6)
+	assert.EqualValues(t, 2, sorted.Pks[0].GetValue())
+	assert.EqualValues(t, 1, sorted.Tss[0])
+	assert.EqualValues(t, 6000, data.Pks[0].GetValue())
+
+	for _, i := range []int64{1, 511, 512, 513, 1024, 3000} {
+		ts, ok := sorted.LatestTs(NewInt64PrimaryKey(2 * i))
+		assert.True(t, ok)
+		assert.EqualValues(t, 10000+i, ts)
+		_, ok = sorted.LatestTs(NewInt64PrimaryKey(2*i + 1))
+		assert.False(t, ok)
+	}
+	_, ok := sorted.LatestTs(NewInt64PrimaryKey(0))
+	assert.False(t, ok)
+	_, ok = sorted.LatestTs(NewInt64PrimaryKey(7000))
+	assert.False(t, ok)
+
+	start, end := sorted.Range(NewInt64PrimaryKey(1000), NewInt64PrimaryKey(2049))
+	assert.EqualValues(t, 1000, sorted.Pks[start].GetValue())
+	assert.EqualValues(t, 2048, sorted.Pks[end-1].GetValue())
+	assert.Equal(t, 1050, end-start)
+	start, end = sorted.Range(NewInt64PrimaryKey(0), NewInt64PrimaryKey(1))
+	assert.Equal(t, start, end)
+	start, end = sorted.Range(NewInt64PrimaryKey(0), NewInt64PrimaryKey(7000))
+	assert.Equal(t, 0, start)
+	assert.Equal(t, sorted.Len(), end)
+
+	empty := NewSortedDeleteData(NewDeleteData(nil, nil))
+	_, ok = empty.LatestTs(NewInt64PrimaryKey(1))
+	assert.False(t, ok)
+	start, end = empty.Range(NewInt64PrimaryKey(0), NewInt64PrimaryKey(1))
+	assert.Equal(t, start, end)
+}
+
+func TestDeleteCodecSorted(t *testing.T) {
+	codec := &DeleteCodec{SortedByPK: true}
+
+	t.Run("int64 pk", func(t *testing.T) {
+		data := NewDeleteData(nil, nil)
+		for i := 2000; i > 0; i-- {
+			data.Append(NewInt64PrimaryKey(int64(i)), Timestamp(100+i))
+		}
+		blob, err := codec.Serialize(CollectionID, 1, 2, data)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2000, blob.RowNum)
+
+		// the sorted deltalogs are read by the codec of either layout
+		pid, sid, read, err := NewDeleteCodec().Deserialize([]*Blob{blob})
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, pid)
+		assert.EqualValues(t, 2, sid)
+		assert.EqualValues(t, 2000, read.RowCount)
+		assert.EqualValues(t, 1, read.Pks[0].GetValue())
+		assert.EqualValues(t, 101, read.Tss[0])
+
+		_, _, sorted, err := codec.DeserializeSorted([]*Blob{blob})
+		assert.NoError(t, err)
+		assert.Len(t, sorted.index, 2)
+		ts, ok := sorted.LatestTs(NewInt64PrimaryKey(1500))
+		assert.True(t, ok)
+		assert.EqualValues(t, 1600, ts)
+	})
+
+	t.Run("varchar pk", func(t *testing.T) {
+		data := NewDeleteData(nil, nil)
+		data.Append(NewVarCharPrimaryKey("b"), 20)
+		data.Append(NewVarCharPrimaryKey("a"), 30)
+		data.Append(NewVarCharPrimaryKey("b"), 10)
+		blob, err := codec.Serialize(CollectionID, 1, 2, data)
+		require.NoError(t, err)
+
+		_, _, sorted, err := codec.DeserializeSorted([]*Blob{blob})
+		assert.NoError(t, err)
+		assert.Equal(t, []PrimaryKey{NewVarCharPrimaryKey("a"), NewVarCharPrimaryKey("b"), NewVarCharPrimaryKey("b")}, sorted.Pks)
+		assert.Equal(t, []Timestamp{30, 10, 20}, sorted.Tss)
+		ts, ok := sorted.LatestTs(NewVarCharPrimaryKey("b"))
+		assert.True(t, ok)
+		assert.EqualValues(t, 20, ts)
+		_, ok = sorted.LatestTs(NewVarCharPrimaryKey("c"))
+		assert.False(t, ok)
+	})
+
+	t.Run("chunks of pk ranges", func(t *testing.T) {
+		data := NewDeleteData(nil, nil)
+		for i := 10; i > 0; i-- {
+			data.Append(NewVarCharPrimaryKey(fmt.Sprintf("pk%02d", i)), Timestamp(i))
+		}
+		blobs, err := codec.SerializeChunks(CollectionID, 1, 2, data, 48)
+		require.NoError(t, err)
+		assert.Greater(t, len(blobs), 1)
+
+		// the chunks are read in the order of the pk ranges no matter in which order they are passed,
+		// and all of them are required
+		_, _, sorted, err := codec.DeserializeSorted(blobs[1:])
+		assert.Error(t, err)
+		assert.Nil(t, sorted)
+		reversed := lo.Reverse(append([]*Blob{}, blobs...))
+		_, _, sorted, err = codec.DeserializeSorted(reversed)
+		assert.NoError(t, err)
+		assert.Equal(t, "pk01", sorted.Pks[0].GetValue())
+		assert.Equal(t, "pk10", sorted.Pks[9].GetValue())
+		_, _, sorted, err = codec.DeserializeSorted(blobs)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, sorted.Len())
+		ts, ok := sorted.LatestTs(NewVarCharPrimaryKey("pk07"))
+		assert.True(t, ok)
+		assert.EqualValues(t, 7, ts)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := codec.Serialize(CollectionID, 1, 2, NewDeleteData(nil, nil))
+		assert.Error(t, err)
+	})
+}
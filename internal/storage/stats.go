@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/bits-and-blooms/bloom/v3"
+	"github.com/samber/lo"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/pkg/common"
@@ -38,6 +39,9 @@ type PrimaryKeyStats struct {
 	PkType  int64              `json:"pkType"`
 	MaxPk   PrimaryKey         `json:"maxPk"`
 	MinPk   PrimaryKey         `json:"minPk"`
+	// Version is StatsFormatV2 if the field stats are carried, StatsFormatV1 otherwise once written by StatsWriter
+	Version    int           `json:"version,omitempty"`
+	FieldStats []*FieldStats `json:"fieldStats,omitempty"`
 }
 
 // UnmarshalJSON unmarshal bytes to PrimaryKeyStats
@@ -118,9 +122,31 @@ func (stats *PrimaryKeyStats) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	if versionMessage, ok := messageMap["version"]; ok && versionMessage != nil {
+		err = json.Unmarshal(*versionMessage, &stats.Version)
+		if err != nil {
+			return err
+		}
+	}
+
+	if fieldStatsMessage, ok := messageMap["fieldStats"]; ok && fieldStatsMessage != nil {
+		err = json.Unmarshal(*fieldStatsMessage, &stats.FieldStats)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// SetFieldStats attaches the stats of the scalar fields, which makes the stats of v2.
+func (stats *PrimaryKeyStats) SetFieldStats(fieldStats []*FieldStats) {
+	stats.FieldStats = fieldStats
+	if len(fieldStats) > 0 {
+		stats.Version = StatsFormatV2
+	}
+}
+
 func (stats *PrimaryKeyStats) UpdateByMsgs(msgs FieldData) {
 	switch schemapb.DataType(stats.PkType) {
 	case schemapb.DataType_Int64:
@@ -208,7 +234,9 @@ func (sw *StatsWriter) GetBuffer() []byte {
 
 // GenerateList writes Stats slice to buffer
 func (sw *StatsWriter) GenerateList(stats []*PrimaryKeyStats) error {
-	b, err := json.Marshal(stats)
+	b, err := json.Marshal(lo.Map(stats, func(stats *PrimaryKeyStats, _ int) *PrimaryKeyStats {
+		return versionedStats(stats)
+	}))
 	if err != nil {
 		return err
 	}
@@ -218,7 +246,7 @@ func (sw *StatsWriter) GenerateList(stats []*PrimaryKeyStats) error {
 
 // Generate writes Stats to buffer
 func (sw *StatsWriter) Generate(stats *PrimaryKeyStats) error {
-	b, err := json.Marshal(stats)
+	b, err := json.Marshal(versionedStats(stats))
 	if err != nil {
 		return err
 	}
@@ -226,6 +254,16 @@ func (sw *StatsWriter) Generate(stats *PrimaryKeyStats) error {
 	return nil
 }
 
+// versionedStats returns the stats with the format version recorded.
+func versionedStats(stats *PrimaryKeyStats) *PrimaryKeyStats {
+	if stats == nil || stats.Version != 0 {
+		return stats
+	}
+	versioned := *stats
+	versioned.Version = StatsFormatV1
+	return &versioned
+}
+
 // GenerateByData writes Int64Stats or StringStats from @msgs with @fieldID to @buffer
 func (sw *StatsWriter) GenerateByData(fieldID int64, pkType schemapb.DataType, msgs FieldData) error {
 	stats := &PrimaryKeyStats{
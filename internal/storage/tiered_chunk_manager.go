@@ -0,0 +1,373 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+	"golang.org/x/exp/mmap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// tierPolicy decides when the files cached by the hot tier are evicted.
+type tierPolicy struct {
+	hotAge time.Duration
+	// capacity is the max bytes of the hot tier, unlimited if not positive
+	capacity int64
+	// collectionHotAge overrides the hot age of the binlogs of the collections, 0 for not cached
+	collectionHotAge map[int64]time.Duration
+}
+
+func (p tierPolicy) hotAgeOf(collectionID int64) time.Duration {
+	if age, ok := p.collectionHotAge[collectionID]; ok {
+		return age
+	}
+	return p.hotAge
+}
+
+func tierPolicyFromParams() tierPolicy {
+	params := &paramtable.Get().LocalStorageCfg
+	policy := tierPolicy{
+		hotAge:           params.TieredHotAge.GetAsDuration(time.Second),
+		capacity:         params.TieredHotCapacity.GetAsInt64() * 1024 * 1024,
+		collectionHotAge: make(map[int64]time.Duration),
+	}
+	for key, value := range params.TieredCollectionHotAge.GetAsJSONMap() {
+		collectionID, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			continue
+		}
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		policy.collectionHotAge[collectionID] = time.Duration(seconds) * time.Second
+	}
+	return policy
+}
+
+type tierEntry struct {
+	size         int64
+	writeTime    time.Time
+	collectionID int64
+}
+
+// TieredChunkManager writes the files through to the cold tier of the object storage and caches them in the hot tier
+// of the local disk, the reads are served by the hot tier first and fall back to the cold tier transparently.
+// A write is acknowledged once written to the cold tier, so the hot tier is only a read cache, whose files are
+// evicted by the age and the capacity of it. The cached files are taken as immutable, like the binlogs are.
+type TieredChunkManager struct {
+	ChunkManager
+	hot     *LocalChunkManager
+	hotRoot string
+	policy  func() tierPolicy
+
+	mu       sync.Mutex
+	entries  map[string]*tierEntry
+	hotBytes int64
+
+	closeCh   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+var _ ChunkManager = (*TieredChunkManager)(nil)
+
+// NewTieredChunkManager returns the TieredChunkManager over the cold ChunkManager with the hot tier under hotRoot,
+// the files of the hot tier left by the previous run are dropped, as they may be removed from the cold tier since.
+func NewTieredChunkManager(hotRoot string, cold ChunkManager) (*TieredChunkManager, error) {
+	cm := &TieredChunkManager{
+		ChunkManager: cold,
+		hot:          NewLocalChunkManager(RootPath(hotRoot)),
+		hotRoot:      path.Clean(hotRoot),
+		policy:       tierPolicyFromParams,
+		entries:      make(map[string]*tierEntry),
+		closeCh:      make(chan struct{}),
+	}
+	if err := cm.hot.RemoveWithPrefix(context.Background(), cm.hotRoot+"/"); err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
+
+// Start evicts the files of the hot tier in the background every interval.
+func (cm *TieredChunkManager) Start(interval time.Duration) {
+	cm.wg.Add(1)
+	go func() {
+		defer cm.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-cm.closeCh:
+				return
+			case <-ticker.C:
+				cm.evict(context.Background(), time.Now())
+			}
+		}
+	}()
+}
+
+// Close stops the background eviction.
+func (cm *TieredChunkManager) Close() {
+	cm.closeOnce.Do(func() {
+		close(cm.closeCh)
+	})
+	cm.wg.Wait()
+}
+
+func (cm *TieredChunkManager) hotPath(filePath string) string {
+	return path.Join(cm.hotRoot, filePath)
+}
+
+func (cm *TieredChunkManager) track(filePath string, size int64, writeTime time.Time) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if old, ok := cm.entries[filePath]; ok {
+		cm.hotBytes -= old.size
+	}
+	var collectionID int64 = -1
+	if info, ok := metautil.ParseLogPath(filePath); ok {
+		collectionID = info.CollectionID
+	}
+	cm.entries[filePath] = &tierEntry{size: size, writeTime: writeTime, collectionID: collectionID}
+	cm.hotBytes += size
+	metrics.StorageTierHotBytes.Set(float64(cm.hotBytes))
+}
+
+func (cm *TieredChunkManager) untrack(filePath string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if old, ok := cm.entries[filePath]; ok {
+		cm.hotBytes -= old.size
+		delete(cm.entries, filePath)
+		metrics.StorageTierHotBytes.Set(float64(cm.hotBytes))
+	}
+}
+
+func (cm *TieredChunkManager) isHot(filePath string) bool {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	_, ok := cm.entries[filePath]
+	return ok
+}
+
+// Write writes the file to the cold tier, and then caches it in the hot tier unless the hot age of its collection is 0.
+// The failure to cache is not returned, the file is read from the cold tier then.
+func (cm *TieredChunkManager) Write(ctx context.Context, filePath string, content []byte) error {
+	// the stale copy of the hot tier would shadow the new one
+	if err := cm.removeHot(ctx, filePath); err != nil {
+		return err
+	}
+	if err := cm.ChunkManager.Write(ctx, filePath, content); err != nil {
+		return err
+	}
+
+	collectionID := int64(-1)
+	if info, ok := metautil.ParseLogPath(filePath); ok {
+		collectionID = info.CollectionID
+	}
+	if cm.policy().hotAgeOf(collectionID) == 0 {
+		return nil
+	}
+	if err := cm.hot.Write(ctx, cm.hotPath(filePath), content); err != nil {
+		log.Warn("failed to cache the file in the hot tier", zap.String("path", filePath), zap.Error(err))
+		return nil
+	}
+	cm.track(filePath, int64(len(content)), time.Now())
+	return nil
+}
+
+func (cm *TieredChunkManager) MultiWrite(ctx context.Context, contents map[string][]byte) error {
+	var el error
+	for filePath, content := range contents {
+		if err := cm.Write(ctx, filePath, content); err != nil {
+			el = merr.Combine(el, errors.Wrapf(err, "failed to write %s", filePath))
+		}
+	}
+	return el
+}
+
+func (cm *TieredChunkManager) Size(ctx context.Context, filePath string) (int64, error) {
+	if cm.isHot(filePath) {
+		return cm.hot.Size(ctx, cm.hotPath(filePath))
+	}
+	return cm.ChunkManager.Size(ctx, filePath)
+}
+
+// listSizesWithPrefix lists the cold tier, which has all the files written.
+func (cm *TieredChunkManager) listSizesWithPrefix(ctx context.Context, prefix string, recursive bool) ([]string, []int64, []time.Time, error) {
+	return ListSizesWithPrefix(ctx, cm.ChunkManager, prefix, recursive)
+}
+
+func (cm *TieredChunkManager) Exist(ctx context.Context, filePath string) (bool, error) {
+	if cm.isHot(filePath) {
+		return true, nil
+	}
+	return cm.ChunkManager.Exist(ctx, filePath)
+}
+
+// readHot runs the read on the hot tier if the file is hot, the file evicted during the read is read from the cold tier.
+func readHot[T any](cm *TieredChunkManager, filePath string, hotRead func(string) (T, error), coldRead func() (T, error)) (T, error) {
+	if cm.isHot(filePath) {
+		result, err := hotRead(cm.hotPath(filePath))
+		if err == nil || !errors.Is(err, merr.ErrIoKeyNotFound) {
+			metrics.StorageTierReadCounter.WithLabelValues(metrics.StorageTierHotLabel).Inc()
+			return result, err
+		}
+	}
+	metrics.StorageTierReadCounter.WithLabelValues(metrics.StorageTierColdLabel).Inc()
+	return coldRead()
+}
+
+func (cm *TieredChunkManager) Read(ctx context.Context, filePath string) ([]byte, error) {
+	return readHot(cm, filePath, func(hotPath string) ([]byte, error) {
+		return cm.hot.Read(ctx, hotPath)
+	}, func() ([]byte, error) {
+		return cm.ChunkManager.Read(ctx, filePath)
+	})
+}
+
+func (cm *TieredChunkManager) Reader(ctx context.Context, filePath string) (FileReader, error) {
+	return readHot(cm, filePath, func(hotPath string) (FileReader, error) {
+		return cm.hot.Reader(ctx, hotPath)
+	}, func() (FileReader, error) {
+		return cm.ChunkManager.Reader(ctx, filePath)
+	})
+}
+
+func (cm *TieredChunkManager) ReadAt(ctx context.Context, filePath string, off int64, length int64) ([]byte, error) {
+	return readHot(cm, filePath, func(hotPath string) ([]byte, error) {
+		return cm.hot.ReadAt(ctx, hotPath, off, length)
+	}, func() ([]byte, error) {
+		return cm.ChunkManager.ReadAt(ctx, filePath, off, length)
+	})
+}
+
+func (cm *TieredChunkManager) Mmap(ctx context.Context, filePath string) (*mmap.ReaderAt, error) {
+	return readHot(cm, filePath, func(hotPath string) (*mmap.ReaderAt, error) {
+		return cm.hot.Mmap(ctx, hotPath)
+	}, func() (*mmap.ReaderAt, error) {
+		return cm.ChunkManager.Mmap(ctx, filePath)
+	})
+}
+
+func (cm *TieredChunkManager) MultiRead(ctx context.Context, filePaths []string) ([][]byte, error) {
+	results := make([][]byte, len(filePaths))
+	var el error
+	for i, filePath := range filePaths {
+		content, err := cm.Read(ctx, filePath)
+		if err != nil {
+			el = merr.Combine(el, errors.Wrapf(err, "failed to read %s", filePath))
+		}
+		results[i] = content
+	}
+	return results, el
+}
+
+func (cm *TieredChunkManager) removeHot(ctx context.Context, filePath string) error {
+	cm.untrack(filePath)
+	return cm.hot.Remove(ctx, cm.hotPath(filePath))
+}
+
+func (cm *TieredChunkManager) Remove(ctx context.Context, filePath string) error {
+	if err := cm.removeHot(ctx, filePath); err != nil {
+		return err
+	}
+	return cm.ChunkManager.Remove(ctx, filePath)
+}
+
+func (cm *TieredChunkManager) MultiRemove(ctx context.Context, filePaths []string) error {
+	var el error
+	for _, filePath := range filePaths {
+		if err := cm.removeHot(ctx, filePath); err != nil {
+			el = merr.Combine(el, err)
+		}
+	}
+	return merr.Combine(el, cm.ChunkManager.MultiRemove(ctx, filePaths))
+}
+
+func (cm *TieredChunkManager) RemoveWithPrefix(ctx context.Context, prefix string) error {
+	if len(prefix) == 0 {
+		return merr.WrapErrParameterInvalidMsg("empty prefix is not allowed for ChunkManager remove operation")
+	}
+	cm.mu.Lock()
+	hotPaths := make([]string, 0)
+	for filePath := range cm.entries {
+		if strings.HasPrefix(filePath, prefix) {
+			hotPaths = append(hotPaths, filePath)
+		}
+	}
+	cm.mu.Unlock()
+
+	var el error
+	for _, filePath := range hotPaths {
+		if err := cm.removeHot(ctx, filePath); err != nil {
+			el = merr.Combine(el, err)
+		}
+	}
+	return merr.Combine(el, cm.ChunkManager.RemoveWithPrefix(ctx, prefix))
+}
+
+// evict removes the files of the hot tier older than their hot age, and then the oldest ones
+// until the hot tier fits its capacity.
+func (cm *TieredChunkManager) evict(ctx context.Context, now time.Time) {
+	policy := cm.policy()
+
+	cm.mu.Lock()
+	type candidate struct {
+		filePath string
+		entry    tierEntry
+	}
+	candidates := make([]candidate, 0, len(cm.entries))
+	for filePath, entry := range cm.entries {
+		candidates = append(candidates, candidate{filePath: filePath, entry: *entry})
+	}
+	hotBytes := cm.hotBytes
+	cm.mu.Unlock()
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].entry.writeTime.Before(candidates[j].entry.writeTime)
+	})
+	for _, c := range candidates {
+		hotAge := policy.hotAgeOf(c.entry.collectionID)
+		expired := hotAge >= 0 && now.Sub(c.entry.writeTime) >= hotAge
+		overflow := policy.capacity > 0 && hotBytes > policy.capacity
+		if !expired && !overflow {
+			continue
+		}
+		if err := cm.removeHot(ctx, c.filePath); err != nil {
+			log.Warn("failed to evict the file from the hot tier", zap.String("path", c.filePath), zap.Error(err))
+			continue
+		}
+		hotBytes -= c.entry.size
+		metrics.StorageTierEvictedBytes.Add(float64(c.entry.size))
+	}
+}
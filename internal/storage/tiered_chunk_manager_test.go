@@ -0,0 +1,128 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTieredChunkManager(t *testing.T, policy tierPolicy) (*TieredChunkManager, *LocalChunkManager, string) {
+	coldRoot := t.TempDir()
+	cold := NewLocalChunkManager(RootPath(coldRoot))
+	cm, err := NewTieredChunkManager(t.TempDir(), cold)
+	require.NoError(t, err)
+	cm.policy = func() tierPolicy { return policy }
+	return cm, cold, coldRoot
+}
+
+func TestTieredChunkManager(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("write through and evict by age", func(t *testing.T) {
+		cm, cold, root := newTestTieredChunkManager(t, tierPolicy{hotAge: time.Hour})
+		filePath := path.Join(root, "insert_log/1/2/3/4/5")
+		require.NoError(t, cm.Write(ctx, filePath, []byte("abc")))
+
+		// written to the cold tier before acknowledged
+		content, err := cold.Read(ctx, filePath)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("abc"), content)
+		assert.True(t, cm.isHot(filePath))
+		content, err = cm.Read(ctx, filePath)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("abc"), content)
+		paths, _, err := cm.ListWithPrefix(ctx, root+"/insert_log/", true)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{filePath}, paths)
+
+		cm.evict(ctx, time.Now())
+		assert.True(t, cm.isHot(filePath))
+		cm.evict(ctx, time.Now().Add(time.Hour))
+		assert.False(t, cm.isHot(filePath))
+		assert.EqualValues(t, 0, cm.hotBytes)
+		content, err = cm.ReadAt(ctx, filePath, 1, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("bc"), content)
+
+		require.NoError(t, cm.Remove(ctx, filePath))
+		exist, err := cm.Exist(ctx, filePath)
+		assert.NoError(t, err)
+		assert.False(t, exist)
+	})
+
+	t.Run("evict oldest over capacity", func(t *testing.T) {
+		cm, cold, root := newTestTieredChunkManager(t, tierPolicy{hotAge: time.Hour, capacity: 4})
+		first, second := path.Join(root, "a"), path.Join(root, "b")
+		require.NoError(t, cm.Write(ctx, first, []byte("abc")))
+		require.NoError(t, cm.Write(ctx, second, []byte("de")))
+
+		cm.evict(ctx, time.Now())
+		assert.False(t, cm.isHot(first))
+		assert.True(t, cm.isHot(second))
+		assert.EqualValues(t, 2, cm.hotBytes)
+		exist, err := cold.Exist(ctx, first)
+		assert.NoError(t, err)
+		assert.True(t, exist)
+	})
+
+	t.Run("cold write failed", func(t *testing.T) {
+		cm, _, root := newTestTieredChunkManager(t, tierPolicy{hotAge: time.Hour})
+		filePath := path.Join(root, "a")
+		require.NoError(t, cm.Write(ctx, filePath, []byte("abc")))
+		// the cold tier fails to write a file over a dir
+		require.NoError(t, cm.ChunkManager.Write(ctx, path.Join(filePath+"dir", "b"), []byte("b")))
+		assert.Error(t, cm.Write(ctx, filePath+"dir", []byte("de")))
+		assert.False(t, cm.isHot(filePath+"dir"))
+	})
+
+	t.Run("collection policy", func(t *testing.T) {
+		cm, cold, root := newTestTieredChunkManager(t, tierPolicy{
+			hotAge:           time.Hour,
+			collectionHotAge: map[int64]time.Duration{1: 0, 2: -1},
+		})
+		direct := path.Join(root, "insert_log/1/2/3/4/5")
+		pinned := path.Join(root, "insert_log/2/2/3/4/5")
+		require.NoError(t, cm.Write(ctx, direct, []byte("abc")))
+		require.NoError(t, cm.Write(ctx, pinned, []byte("abc")))
+
+		assert.False(t, cm.isHot(direct))
+		exist, err := cold.Exist(ctx, direct)
+		assert.NoError(t, err)
+		assert.True(t, exist)
+		cm.evict(ctx, time.Now().Add(24*time.Hour))
+		assert.True(t, cm.isHot(pinned))
+	})
+
+	t.Run("restart drops hot files", func(t *testing.T) {
+		cm, cold, root := newTestTieredChunkManager(t, tierPolicy{hotAge: time.Hour})
+		filePath := path.Join(root, "insert_log/1/2/3/4/5")
+		require.NoError(t, cm.Write(ctx, filePath, []byte("abc")))
+
+		restarted, err := NewTieredChunkManager(cm.hotRoot, cold)
+		require.NoError(t, err)
+		assert.False(t, restarted.isHot(filePath))
+		exist, err := restarted.hot.Exist(ctx, restarted.hotPath(filePath))
+		assert.NoError(t, err)
+		assert.False(t, exist)
+	})
+}
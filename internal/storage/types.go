@@ -66,9 +66,9 @@ type ChunkManager interface {
 	// ReadWithPrefix reads files with same @prefix and returns contents.
 	ReadWithPrefix(ctx context.Context, prefix string) ([]string, [][]byte, error)
 	Mmap(ctx context.Context, filePath string) (*mmap.ReaderAt, error)
-	// ReadAt reads @filePath by offset @off, content stored in @p, return @n as the number of bytes read.
-	// if all bytes are read, @err is io.EOF.
-	// return other error if read failed.
+	// ReadAt reads @length bytes of @filePath from offset @off by a ranged read, so only the range is fetched
+	// from the storage. The content is truncated at the end of the file if the range exceeds it,
+	// @err is io.EOF if @off or @length is negative, and other errors if read failed.
 	ReadAt(ctx context.Context, filePath string, off int64, length int64) (p []byte, err error)
 	// Remove delete @filePath.
 	Remove(ctx context.Context, filePath string) error
@@ -77,3 +77,10 @@ type ChunkManager interface {
 	// RemoveWithPrefix remove files with same @prefix.
 	RemoveWithPrefix(ctx context.Context, prefix string) error
 }
+
+// EtagChunkManager is the ChunkManager telling the etags of the files, which change once the files are rewritten.
+type EtagChunkManager interface {
+	ChunkManager
+	// Etag returns the etag of @filePath.
+	Etag(ctx context.Context, filePath string) (string, error)
+}
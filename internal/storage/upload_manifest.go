@@ -0,0 +1,64 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+// UploadManifest is the write-ahead manifest of a group of binlogs uploaded together, it's written before
+// the binlogs and removed once they are all uploaded. The manifest left marks an incomplete upload group,
+// whose binlogs not referenced by the meta are removed by the garbage collector with the manifest.
+type UploadManifest struct {
+	Keys []string `json:"keys"`
+	// Aborted is set once the upload failed, so the group is recycled without waiting for the manifest to expire
+	Aborted bool `json:"aborted,omitempty"`
+}
+
+var uploadManifestSeq = atomic.NewInt64(0)
+
+// NewUploadManifestKey returns a unique key of the manifest under the root path written by the node.
+func NewUploadManifestKey(rootPath string, nodeID int64) string {
+	name := fmt.Sprintf("%d-%d-%d", nodeID, time.Now().UnixNano(), uploadManifestSeq.Inc())
+	return path.Join(rootPath, common.UploadManifestPath, name)
+}
+
+// UploadManifestPrefix returns the prefix of the manifests under the root path.
+func UploadManifestPrefix(rootPath string) string {
+	return path.Join(rootPath, common.UploadManifestPath) + "/"
+}
+
+// Marshal returns the manifest encoded in json.
+func (m *UploadManifest) Marshal() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalUploadManifest decodes the manifest in json.
+func UnmarshalUploadManifest(data []byte) (*UploadManifest, error) {
+	manifest := &UploadManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
@@ -0,0 +1,185 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+)
+
+// The zone map of a binlog is the min and max values of the scalar field in the binlog, it's computed
+// at serialize time and kept with the binlog, in the extras of the descriptor event of the native binlog
+// and in the key value metadata of the footer of the parquet binlog, and copied into the binlog meta,
+// so the binlogs which can't match a filter are skipped without reading them.
+const (
+	zoneMapKey              = "zone_map"
+	parquetBinlogZoneMapKey = "milvus." + zoneMapKey
+)
+
+// IsZoneMapSupported returns whether the zone maps of the data type are computed.
+func IsZoneMapSupported(dataType schemapb.DataType) bool {
+	switch dataType {
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32, schemapb.DataType_Int64,
+		schemapb.DataType_Float, schemapb.DataType_Double, schemapb.DataType_VarChar, schemapb.DataType_String:
+		return true
+	default:
+		return false
+	}
+}
+
+// ComputeZoneMap returns the zone map of the column of the field, nil if the data type is not supported
+// or there's no valid value in the column.
+func ComputeZoneMap(dataType schemapb.DataType, column arrow.Array) *datapb.ZoneMap {
+	if !IsZoneMapSupported(dataType) || column.Len() == column.NullN() {
+		return nil
+	}
+	zoneMap := &datapb.ZoneMap{NullCount: int64(column.NullN())}
+	first := true
+	for i := 0; i < column.Len(); i++ {
+		if column.IsNull(i) {
+			continue
+		}
+		switch col := column.(type) {
+		case *array.Int8:
+			updateIntZoneMap(zoneMap, int64(col.Value(i)), first)
+		case *array.Int16:
+			updateIntZoneMap(zoneMap, int64(col.Value(i)), first)
+		case *array.Int32:
+			updateIntZoneMap(zoneMap, int64(col.Value(i)), first)
+		case *array.Int64:
+			updateIntZoneMap(zoneMap, col.Value(i), first)
+		case *array.Float32:
+			value := float64(col.Value(i))
+			// NaN is not comparable and never matches the filters, so it's left out of the range
+			if math.IsNaN(value) {
+				continue
+			}
+			updateFloatZoneMap(zoneMap, value, first)
+		case *array.Float64:
+			if math.IsNaN(col.Value(i)) {
+				continue
+			}
+			updateFloatZoneMap(zoneMap, col.Value(i), first)
+		case *array.String:
+			value := col.Value(i)
+			if first || value < zoneMap.StringMin {
+				zoneMap.StringMin = value
+			}
+			if first || value > zoneMap.StringMax {
+				zoneMap.StringMax = value
+			}
+		default:
+			return nil
+		}
+		first = false
+	}
+	if first {
+		return nil
+	}
+	return zoneMap
+}
+
+func updateIntZoneMap(zoneMap *datapb.ZoneMap, value int64, first bool) {
+	if first || value < zoneMap.IntMin {
+		zoneMap.IntMin = value
+	}
+	if first || value > zoneMap.IntMax {
+		zoneMap.IntMax = value
+	}
+}
+
+func updateFloatZoneMap(zoneMap *datapb.ZoneMap, value float64, first bool) {
+	if first || value < zoneMap.FloatMin {
+		zoneMap.FloatMin = value
+	}
+	if first || value > zoneMap.FloatMax {
+		zoneMap.FloatMax = value
+	}
+}
+
+// MergeZoneMaps returns the zone map covering all the zone maps, nil if any of them is nil,
+// as the values of the binlog without the zone map are unknown.
+func MergeZoneMaps(zoneMaps ...*datapb.ZoneMap) *datapb.ZoneMap {
+	if len(zoneMaps) == 0 {
+		return nil
+	}
+	var merged *datapb.ZoneMap
+	for _, zoneMap := range zoneMaps {
+		if zoneMap == nil {
+			return nil
+		}
+		if merged == nil {
+			merged = &datapb.ZoneMap{
+				NullCount: zoneMap.GetNullCount(),
+				IntMin:    zoneMap.GetIntMin(),
+				IntMax:    zoneMap.GetIntMax(),
+				FloatMin:  zoneMap.GetFloatMin(),
+				FloatMax:  zoneMap.GetFloatMax(),
+				StringMin: zoneMap.GetStringMin(),
+				StringMax: zoneMap.GetStringMax(),
+			}
+			continue
+		}
+		merged.NullCount += zoneMap.GetNullCount()
+		merged.IntMin = lo.Min([]int64{merged.IntMin, zoneMap.GetIntMin()})
+		merged.IntMax = lo.Max([]int64{merged.IntMax, zoneMap.GetIntMax()})
+		merged.FloatMin = lo.Min([]float64{merged.FloatMin, zoneMap.GetFloatMin()})
+		merged.FloatMax = lo.Max([]float64{merged.FloatMax, zoneMap.GetFloatMax()})
+		merged.StringMin = lo.Min([]string{merged.StringMin, zoneMap.GetStringMin()})
+		merged.StringMax = lo.Max([]string{merged.StringMax, zoneMap.GetStringMax()})
+	}
+	return merged
+}
+
+func marshalZoneMap(zoneMap *datapb.ZoneMap) (string, error) {
+	data, err := json.Marshal(zoneMap)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GetBinlogZoneMap reads the zone map kept in the insert binlog, nil if not kept.
+func GetBinlogZoneMap(data []byte) (*datapb.ZoneMap, error) {
+	reader, err := NewBinlogReader(data)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	value, ok := reader.descriptor().Extras[zoneMapKey]
+	if !ok {
+		return nil, nil
+	}
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("value of %v must in string format", zoneMapKey)
+	}
+	zoneMap := &datapb.ZoneMap{}
+	if err := json.Unmarshal([]byte(str), zoneMap); err != nil {
+		return nil, fmt.Errorf("invalid %v: %w", zoneMapKey, err)
+	}
+	return zoneMap, nil
+}
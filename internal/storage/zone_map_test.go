@@ -0,0 +1,109 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestComputeZoneMap(t *testing.T) {
+	t.Run("float with null and NaN", func(t *testing.T) {
+		builder := array.NewFloat64Builder(memory.DefaultAllocator)
+		defer builder.Release()
+		builder.AppendValues([]float64{math.NaN(), 3, -1}, nil)
+		builder.AppendNull()
+		column := builder.NewArray()
+		defer column.Release()
+
+		zoneMap := ComputeZoneMap(schemapb.DataType_Double, column)
+		assert.Equal(t, &datapb.ZoneMap{NullCount: 1, FloatMin: -1, FloatMax: 3}, zoneMap)
+	})
+
+	t.Run("no valid value", func(t *testing.T) {
+		builder := array.NewFloat32Builder(memory.DefaultAllocator)
+		defer builder.Release()
+		builder.AppendValues([]float32{float32(math.NaN())}, nil)
+		column := builder.NewArray()
+		defer column.Release()
+		assert.Nil(t, ComputeZoneMap(schemapb.DataType_Float, column))
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		builder := array.NewBooleanBuilder(memory.DefaultAllocator)
+		defer builder.Release()
+		builder.Append(true)
+		column := builder.NewArray()
+		defer column.Release()
+		assert.Nil(t, ComputeZoneMap(schemapb.DataType_Bool, column))
+	})
+}
+
+func TestMergeZoneMaps(t *testing.T) {
+	merged := MergeZoneMaps(
+		&datapb.ZoneMap{NullCount: 1, IntMin: 5, IntMax: 10, StringMin: "b", StringMax: "c"},
+		&datapb.ZoneMap{IntMin: 0, IntMax: 7, StringMin: "c", StringMax: "d"},
+	)
+	assert.Equal(t, &datapb.ZoneMap{NullCount: 1, IntMin: 0, IntMax: 10, StringMin: "b", StringMax: "d"}, merged)
+	assert.Nil(t, MergeZoneMaps(&datapb.ZoneMap{}, nil))
+	assert.Nil(t, MergeZoneMaps())
+}
+
+func TestBinlogZoneMap(t *testing.T) {
+	paramtable.Init()
+	meta := genTestCollectionMeta()
+	for _, format := range []string{common.BinlogFormatNative, common.BinlogFormatParquet} {
+		t.Run(format, func(t *testing.T) {
+			data, err := genSequentialInsertData(meta.GetSchema(), 3, 1, 2)
+			require.NoError(t, err)
+			record, err := NewSortedInsertRecord(context.Background(), meta.GetSchema(), data)
+			require.NoError(t, err)
+			defer record.Release()
+
+			codec := NewInsertCodecWithSchema(meta)
+			codec.BinlogFormat = format
+			blobs, err := codec.SerializeRecord(PartitionID, SegmentID, record)
+			require.NoError(t, err)
+			for i, field := range meta.GetSchema().GetFields() {
+				zoneMap, err := GetBinlogZoneMap(blobs[i].Value)
+				assert.NoError(t, err)
+				assert.Equal(t, blobs[i].ZoneMap, zoneMap)
+				switch field.GetFieldID() {
+				case Int32Field:
+					assert.Equal(t, &datapb.ZoneMap{IntMin: 1, IntMax: 3}, zoneMap)
+				case DoubleField:
+					assert.Equal(t, &datapb.ZoneMap{FloatMin: 1, FloatMax: 3}, zoneMap)
+				case StringField:
+					assert.Equal(t, &datapb.ZoneMap{StringMin: "b", StringMax: "d"}, zoneMap)
+				case FloatVectorField, JSONField:
+					assert.Nil(t, zoneMap)
+				}
+			}
+		})
+	}
+}
@@ -101,28 +101,41 @@ func (f *DefaultFactory) initMQ(standalone bool, params *paramtable.ComponentPar
 	return nil
 }
 
+// SelectMQType returns the mq type initialized by the factory, or the error if no valid mq config found.
+func SelectMQType(standalone bool, params *paramtable.ComponentParam) (string, error) {
+	return selectMQType(standalone, params.MQCfg.Type.GetValue(), mqEnable{params.RocksmqEnable(), params.NatsmqEnable(), params.PulsarEnable(), params.KafkaEnable()})
+}
+
 // Select valid mq if mq type is default.
 func mustSelectMQType(standalone bool, mqType string, enable mqEnable) string {
+	mqType, err := selectMQType(standalone, mqType, enable)
+	if err != nil {
+		panic(err)
+	}
+	return mqType
+}
+
+func selectMQType(standalone bool, mqType string, enable mqEnable) (string, error) {
 	if mqType != mqTypeDefault {
 		if err := validateMQType(standalone, mqType); err != nil {
-			panic(err)
+			return "", err
 		}
-		return mqType
+		return mqType, nil
 	}
 
 	if standalone {
 		if enable.Rocksmq {
-			return mqTypeRocksmq
+			return mqTypeRocksmq, nil
 		}
 	}
 	if enable.Pulsar {
-		return mqTypePulsar
+		return mqTypePulsar, nil
 	}
 	if enable.Kafka {
-		return mqTypeKafka
+		return mqTypeKafka, nil
 	}
 
-	panic(errors.Errorf("no available mq config found, %s, enable: %+v", mqType, enable))
+	return "", errors.Errorf("no available mq config found, %s, enable: %+v", mqType, enable)
 }
 
 // Validate mq type.
@@ -131,7 +144,7 @@ func validateMQType(standalone bool, mqType string) error {
 		return errors.Newf("mq type %s is invalid", mqType)
 	}
 	if !standalone && (mqType == mqTypeRocksmq || mqType == mqTypeNatsmq) {
-		return errors.Newf("mq %s is only valid in standalone mode")
+		return errors.Newf("mq %s is only valid in standalone mode", mqType)
 	}
 	return nil
 }
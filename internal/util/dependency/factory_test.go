@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
 )
 
 func TestValidateMQType(t *testing.T) {
@@ -33,3 +35,16 @@ func TestSelectMQType(t *testing.T) {
 	assert.Equal(t, mustSelectMQType(false, mqTypePulsar, mqEnable{true, true, true, true}), mqTypePulsar)
 	assert.Equal(t, mustSelectMQType(false, mqTypeKafka, mqEnable{true, true, true, true}), mqTypeKafka)
 }
+
+func TestSelectMQTypeWithParams(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(params.MQCfg.Type.Key, mqTypeRocksmq)
+	defer params.Reset(params.MQCfg.Type.Key)
+
+	mqType, err := SelectMQType(true, params)
+	assert.NoError(t, err)
+	assert.Equal(t, mqTypeRocksmq, mqType)
+	_, err = SelectMQType(false, params)
+	assert.Error(t, err)
+}
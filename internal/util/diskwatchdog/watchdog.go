@@ -0,0 +1,223 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diskwatchdog monitors the disk holding the local storage path of datanode, querynode
+// and indexnode. Once the disk usage reaches the high watermark, the watchdog enters the degraded
+// mode and the disk consuming activities (chunk cache warming up, loading segments to disk,
+// disk index building and import) are paused until the usage drops below the low watermark,
+// so that the node won't crash because of a full disk.
+package diskwatchdog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/hardware"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// Watchdog checks the usage of the local disk periodically and switches the degraded mode.
+type Watchdog struct {
+	role string
+	path string
+
+	degraded   atomic.Bool
+	usageRatio atomic.Float64
+
+	// getDiskUsage returns the used and total bytes of the disk, replaced in unit tests
+	getDiskUsage func(path string) (uint64, uint64, error)
+
+	closeCh   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewWatchdog creates a Watchdog of the disk holding the local storage path.
+func NewWatchdog(role string) *Watchdog {
+	return &Watchdog{
+		role:         role,
+		path:         paramtable.Get().LocalStorageCfg.Path.GetValue(),
+		getDiskUsage: hardware.GetDiskUsageByPath,
+		closeCh:      make(chan struct{}),
+	}
+}
+
+func (w *Watchdog) Start() {
+	w.check()
+	w.wg.Add(1)
+	go w.loop()
+}
+
+func (w *Watchdog) Close() {
+	w.closeOnce.Do(func() {
+		close(w.closeCh)
+		w.wg.Wait()
+		nodeID := fmt.Sprint(paramtable.GetNodeID())
+		metrics.LocalDiskUsageRatio.DeleteLabelValues(nodeID, w.role)
+		metrics.LocalDiskDegraded.DeleteLabelValues(nodeID, w.role)
+	})
+}
+
+// IsDegraded returns whether the disk consuming activities should be paused.
+func (w *Watchdog) IsDegraded() bool {
+	return w.degraded.Load()
+}
+
+func (w *Watchdog) loop() {
+	defer w.wg.Done()
+	ticker := time.NewTicker(paramtable.Get().LocalStorageCfg.DiskWatchdogCheckInterval.GetAsDuration(time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.closeCh:
+			log.Info("disk watchdog quit", zap.String("role", w.role))
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+func (w *Watchdog) check() {
+	params := &paramtable.Get().LocalStorageCfg
+	log := log.With(zap.String("role", w.role), zap.String("path", w.path))
+	nodeID := fmt.Sprint(paramtable.GetNodeID())
+
+	if !params.DiskWatchdogEnabled.GetAsBool() {
+		if w.degraded.CompareAndSwap(true, false) {
+			log.Info("disk watchdog is disabled, resume disk consuming activities")
+			metrics.LocalDiskDegraded.WithLabelValues(nodeID, w.role).Set(0)
+		}
+		return
+	}
+
+	used, total, err := w.getDiskUsage(nearestExistingDir(w.path))
+	if err != nil || total == 0 {
+		log.Warn("failed to get local disk usage", zap.Uint64("total", total), zap.Error(err))
+		return
+	}
+	ratio := float64(used) / float64(total)
+	w.usageRatio.Store(ratio)
+	metrics.LocalDiskUsageRatio.WithLabelValues(nodeID, w.role).Set(ratio)
+
+	highWatermark := params.DiskWatchdogHighWatermark.GetAsFloat()
+	lowWatermark := params.DiskWatchdogLowWatermark.GetAsFloat()
+	switch {
+	case !w.degraded.Load() && ratio >= highWatermark:
+		w.degraded.Store(true)
+		metrics.LocalDiskDegraded.WithLabelValues(nodeID, w.role).Set(1)
+		log.Warn("local disk is almost full, pause disk consuming activities",
+			zap.Float64("usageRatio", ratio),
+			zap.Float64("highWatermark", highWatermark),
+			zap.Uint64("used", used),
+			zap.Uint64("total", total))
+	case w.degraded.Load() && ratio < lowWatermark:
+		w.degraded.Store(false)
+		metrics.LocalDiskDegraded.WithLabelValues(nodeID, w.role).Set(0)
+		log.Info("local disk usage drops below the low watermark, resume disk consuming activities",
+			zap.Float64("usageRatio", ratio),
+			zap.Float64("lowWatermark", lowWatermark))
+	case w.degraded.Load():
+		log.RatedWarn(60, "local disk is still almost full, disk consuming activities are paused",
+			zap.Float64("usageRatio", ratio),
+			zap.Float64("lowWatermark", lowWatermark))
+	}
+}
+
+// CheckAvailable returns ErrServiceDiskLimitExceeded if the watchdog is in degraded mode.
+func (w *Watchdog) CheckAvailable() error {
+	if !w.IsDegraded() {
+		return nil
+	}
+	return merr.WrapErrServiceDiskLimitExceeded(float32(w.usageRatio.Load()),
+		float32(paramtable.Get().LocalStorageCfg.DiskWatchdogHighWatermark.GetAsFloat()),
+		"local disk is almost full, disk consuming activities are paused")
+}
+
+// nearestExistingDir returns the path or its nearest existing ancestor,
+// the local storage path may not be created yet.
+func nearestExistingDir(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+var (
+	mu     sync.Mutex
+	refs   int
+	global *Watchdog
+)
+
+// Start starts the watchdog of the process, the components running in the same process
+// share one watchdog, which is closed once all of them call Stop.
+func Start(role string) {
+	mu.Lock()
+	defer mu.Unlock()
+	refs++
+	if global == nil {
+		global = NewWatchdog(role)
+		global.Start()
+		log.Info("disk watchdog started", zap.String("role", role), zap.String("path", global.path))
+	}
+}
+
+// Stop releases the watchdog started by Start.
+func Stop() {
+	mu.Lock()
+	defer mu.Unlock()
+	if refs == 0 {
+		return
+	}
+	refs--
+	if refs == 0 {
+		global.Close()
+		global = nil
+	}
+}
+
+// IsDegraded returns whether the disk consuming activities of the process should be paused,
+// it's always false if the watchdog is not started.
+func IsDegraded() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return global != nil && global.IsDegraded()
+}
+
+// CheckAvailable returns ErrServiceDiskLimitExceeded if the watchdog of the process is in degraded mode.
+func CheckAvailable() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if global == nil {
+		return nil
+	}
+	return global.CheckAvailable()
+}
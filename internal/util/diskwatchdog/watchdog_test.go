@@ -0,0 +1,132 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diskwatchdog
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+type WatchdogSuite struct {
+	suite.Suite
+
+	used     uint64
+	err      error
+	watchdog *Watchdog
+}
+
+func (s *WatchdogSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (s *WatchdogSuite) SetupTest() {
+	s.used = 0
+	s.err = nil
+	s.watchdog = NewWatchdog(typeutil.QueryNodeRole)
+	s.watchdog.getDiskUsage = func(string) (uint64, uint64, error) {
+		return s.used, 100, s.err
+	}
+}
+
+func (s *WatchdogSuite) TearDownTest() {
+	s.watchdog.Close()
+}
+
+func (s *WatchdogSuite) TestWatermarks() {
+	s.used = 89
+	s.watchdog.check()
+	s.False(s.watchdog.IsDegraded())
+	s.NoError(s.watchdog.CheckAvailable())
+
+	s.used = 90
+	s.watchdog.check()
+	s.True(s.watchdog.IsDegraded())
+	s.ErrorIs(s.watchdog.CheckAvailable(), merr.ErrServiceDiskLimitExceeded)
+
+	// keep degraded until the usage drops below the low watermark
+	s.used = 85
+	s.watchdog.check()
+	s.True(s.watchdog.IsDegraded())
+
+	// failing to get the disk usage doesn't change the mode
+	s.used = 10
+	s.err = errors.New("mock error")
+	s.watchdog.check()
+	s.True(s.watchdog.IsDegraded())
+
+	s.err = nil
+	s.watchdog.check()
+	s.False(s.watchdog.IsDegraded())
+	s.NoError(s.watchdog.CheckAvailable())
+}
+
+func (s *WatchdogSuite) TestDisabled() {
+	s.used = 95
+	s.watchdog.check()
+	s.True(s.watchdog.IsDegraded())
+
+	paramtable.Get().Save(paramtable.Get().LocalStorageCfg.DiskWatchdogEnabled.Key, "false")
+	defer paramtable.Get().Reset(paramtable.Get().LocalStorageCfg.DiskWatchdogEnabled.Key)
+	s.watchdog.check()
+	s.False(s.watchdog.IsDegraded())
+}
+
+func (s *WatchdogSuite) TestNearestExistingDir() {
+	dir := s.T().TempDir()
+	s.Equal(dir, nearestExistingDir(dir))
+	s.Equal(dir, nearestExistingDir(path.Join(dir, "not", "exist")))
+
+	file := path.Join(dir, "file")
+	s.Require().NoError(os.WriteFile(file, nil, 0o600))
+	s.Equal(file, nearestExistingDir(file))
+}
+
+func (s *WatchdogSuite) TestGlobal() {
+	s.False(IsDegraded())
+	s.NoError(CheckAvailable())
+
+	// degrade at any disk usage
+	paramtable.Get().Save(paramtable.Get().LocalStorageCfg.Path.Key, s.T().TempDir())
+	defer paramtable.Get().Reset(paramtable.Get().LocalStorageCfg.Path.Key)
+	paramtable.Get().Save(paramtable.Get().LocalStorageCfg.DiskWatchdogHighWatermark.Key, "0")
+	defer paramtable.Get().Reset(paramtable.Get().LocalStorageCfg.DiskWatchdogHighWatermark.Key)
+
+	Start(typeutil.DataNodeRole)
+	Start(typeutil.IndexNodeRole)
+	s.True(IsDegraded())
+	s.ErrorIs(CheckAvailable(), merr.ErrServiceDiskLimitExceeded)
+
+	Stop()
+	s.True(IsDegraded())
+	Stop()
+	s.False(IsDegraded())
+	// stop more times is a no-op
+	Stop()
+	s.NoError(CheckAvailable())
+}
+
+func TestWatchdog(t *testing.T) {
+	suite.Run(t, new(WatchdogSuite))
+}
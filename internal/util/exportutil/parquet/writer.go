@@ -0,0 +1,269 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"io"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/parquet"
+	"github.com/apache/arrow/go/v12/parquet/compress"
+	"github.com/apache/arrow/go/v12/parquet/pqarrow"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// Writer writes insert data as a parquet file. Only user fields are written, the layout
+// matches what the bulk insert parquet reader expects, so exported files can be imported back:
+//   - FloatVector is a list of float32
+//   - BinaryVector is a list of uint8
+//   - Float16Vector and BFloat16Vector are binary holding the raw little-endian vector
+//   - JSON is a string
+//   - Array is a list of its element type
+type Writer struct {
+	fields      []*schemapb.FieldSchema
+	arrowSchema *arrow.Schema
+	fileWriter  *pqarrow.FileWriter
+	mem         memory.Allocator
+}
+
+// NewWriter creates a Writer writing to @w with the user fields of @schema.
+func NewWriter(w io.Writer, schema *schemapb.CollectionSchema) (*Writer, error) {
+	fields := make([]*schemapb.FieldSchema, 0, len(schema.GetFields()))
+	arrowFields := make([]arrow.Field, 0, len(schema.GetFields()))
+	for _, field := range schema.GetFields() {
+		if common.IsSystemField(field.GetFieldID()) {
+			continue
+		}
+		dataType, err := convertToArrowType(field.GetDataType(), field.GetElementType())
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+		arrowFields = append(arrowFields, arrow.Field{
+			Name:     field.GetName(),
+			Type:     dataType,
+			Nullable: true,
+		})
+	}
+	arrowSchema := arrow.NewSchema(arrowFields, nil)
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Zstd))
+	fileWriter, err := pqarrow.NewFileWriter(arrowSchema, w, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{
+		fields:      fields,
+		arrowSchema: arrowSchema,
+		fileWriter:  fileWriter,
+		mem:         memory.NewGoAllocator(),
+	}, nil
+}
+
+func convertToArrowType(dataType, elementType schemapb.DataType) (arrow.DataType, error) {
+	switch dataType {
+	case schemapb.DataType_Bool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case schemapb.DataType_Int8:
+		return arrow.PrimitiveTypes.Int8, nil
+	case schemapb.DataType_Int16:
+		return arrow.PrimitiveTypes.Int16, nil
+	case schemapb.DataType_Int32:
+		return arrow.PrimitiveTypes.Int32, nil
+	case schemapb.DataType_Int64:
+		return arrow.PrimitiveTypes.Int64, nil
+	case schemapb.DataType_Float:
+		return arrow.PrimitiveTypes.Float32, nil
+	case schemapb.DataType_Double:
+		return arrow.PrimitiveTypes.Float64, nil
+	case schemapb.DataType_String, schemapb.DataType_VarChar, schemapb.DataType_JSON:
+		return arrow.BinaryTypes.String, nil
+	case schemapb.DataType_Array:
+		elemType, err := convertToArrowType(elementType, schemapb.DataType_None)
+		if err != nil {
+			return nil, err
+		}
+		return arrow.ListOf(elemType), nil
+	case schemapb.DataType_FloatVector:
+		return arrow.ListOf(arrow.PrimitiveTypes.Float32), nil
+	case schemapb.DataType_BinaryVector:
+		return arrow.ListOf(arrow.PrimitiveTypes.Uint8), nil
+	case schemapb.DataType_Float16Vector, schemapb.DataType_BFloat16Vector:
+		return arrow.BinaryTypes.Binary, nil
+	default:
+		return nil, merr.WrapErrParameterInvalidMsg("unsupported data type %s for parquet export", dataType.String())
+	}
+}
+
+// Write appends all the rows of @data to the file as one row group.
+func (w *Writer) Write(data *storage.InsertData) error {
+	rowNum := data.GetRowNum()
+	columns := make([]arrow.Array, 0, len(w.fields))
+	defer func() {
+		for _, column := range columns {
+			column.Release()
+		}
+	}()
+	for _, field := range w.fields {
+		fieldData, ok := data.Data[field.GetFieldID()]
+		if !ok {
+			return merr.WrapErrFieldNotFound(field.GetFieldID(), "field data not found")
+		}
+		if fieldData.RowNum() != rowNum {
+			return merr.WrapErrParameterInvalidMsg("row num of field %s is %d, expected %d", field.GetName(), fieldData.RowNum(), rowNum)
+		}
+		column, err := w.buildColumn(field, fieldData)
+		if err != nil {
+			return err
+		}
+		columns = append(columns, column)
+	}
+	record := array.NewRecord(w.arrowSchema, columns, int64(rowNum))
+	defer record.Release()
+	return w.fileWriter.Write(record)
+}
+
+// Close flushes the footer of the file, the underlying writer is not closed.
+func (w *Writer) Close() error {
+	return w.fileWriter.Close()
+}
+
+func (w *Writer) buildColumn(field *schemapb.FieldSchema, fieldData storage.FieldData) (arrow.Array, error) {
+	switch data := fieldData.(type) {
+	case *storage.BoolFieldData:
+		builder := array.NewBooleanBuilder(w.mem)
+		defer builder.Release()
+		builder.AppendValues(data.Data, nil)
+		return builder.NewArray(), nil
+	case *storage.Int8FieldData:
+		builder := array.NewInt8Builder(w.mem)
+		defer builder.Release()
+		builder.AppendValues(data.Data, nil)
+		return builder.NewArray(), nil
+	case *storage.Int16FieldData:
+		builder := array.NewInt16Builder(w.mem)
+		defer builder.Release()
+		builder.AppendValues(data.Data, nil)
+		return builder.NewArray(), nil
+	case *storage.Int32FieldData:
+		builder := array.NewInt32Builder(w.mem)
+		defer builder.Release()
+		builder.AppendValues(data.Data, nil)
+		return builder.NewArray(), nil
+	case *storage.Int64FieldData:
+		builder := array.NewInt64Builder(w.mem)
+		defer builder.Release()
+		builder.AppendValues(data.Data, nil)
+		return builder.NewArray(), nil
+	case *storage.FloatFieldData:
+		builder := array.NewFloat32Builder(w.mem)
+		defer builder.Release()
+		builder.AppendValues(data.Data, nil)
+		return builder.NewArray(), nil
+	case *storage.DoubleFieldData:
+		builder := array.NewFloat64Builder(w.mem)
+		defer builder.Release()
+		builder.AppendValues(data.Data, nil)
+		return builder.NewArray(), nil
+	case *storage.StringFieldData:
+		builder := array.NewStringBuilder(w.mem)
+		defer builder.Release()
+		builder.AppendValues(data.Data, nil)
+		return builder.NewArray(), nil
+	case *storage.JSONFieldData:
+		builder := array.NewStringBuilder(w.mem)
+		defer builder.Release()
+		for _, v := range data.Data {
+			builder.Append(string(v))
+		}
+		return builder.NewArray(), nil
+	case *storage.ArrayFieldData:
+		return w.buildArrayColumn(field, data)
+	case *storage.FloatVectorFieldData:
+		builder := array.NewListBuilder(w.mem, arrow.PrimitiveTypes.Float32)
+		defer builder.Release()
+		valueBuilder := builder.ValueBuilder().(*array.Float32Builder)
+		for i := 0; i < data.RowNum(); i++ {
+			builder.Append(true)
+			valueBuilder.AppendValues(data.GetRow(i).([]float32), nil)
+		}
+		return builder.NewArray(), nil
+	case *storage.BinaryVectorFieldData:
+		builder := array.NewListBuilder(w.mem, arrow.PrimitiveTypes.Uint8)
+		defer builder.Release()
+		valueBuilder := builder.ValueBuilder().(*array.Uint8Builder)
+		for i := 0; i < data.RowNum(); i++ {
+			builder.Append(true)
+			valueBuilder.AppendValues(data.GetRow(i).([]byte), nil)
+		}
+		return builder.NewArray(), nil
+	case *storage.Float16VectorFieldData, *storage.BFloat16VectorFieldData:
+		builder := array.NewBinaryBuilder(w.mem, arrow.BinaryTypes.Binary)
+		defer builder.Release()
+		for i := 0; i < data.RowNum(); i++ {
+			builder.Append(data.GetRow(i).([]byte))
+		}
+		return builder.NewArray(), nil
+	default:
+		return nil, merr.WrapErrParameterInvalidMsg("unsupported data type %s of field %s for parquet export",
+			fieldData.GetDataType().String(), field.GetName())
+	}
+}
+
+func (w *Writer) buildArrayColumn(field *schemapb.FieldSchema, data *storage.ArrayFieldData) (arrow.Array, error) {
+	elemType, err := convertToArrowType(field.GetElementType(), schemapb.DataType_None)
+	if err != nil {
+		return nil, err
+	}
+	builder := array.NewListBuilder(w.mem, elemType)
+	defer builder.Release()
+	valueBuilder := builder.ValueBuilder()
+	for _, row := range data.Data {
+		builder.Append(true)
+		switch field.GetElementType() {
+		case schemapb.DataType_Bool:
+			valueBuilder.(*array.BooleanBuilder).AppendValues(row.GetBoolData().GetData(), nil)
+		case schemapb.DataType_Int8:
+			for _, v := range row.GetIntData().GetData() {
+				valueBuilder.(*array.Int8Builder).Append(int8(v))
+			}
+		case schemapb.DataType_Int16:
+			for _, v := range row.GetIntData().GetData() {
+				valueBuilder.(*array.Int16Builder).Append(int16(v))
+			}
+		case schemapb.DataType_Int32:
+			valueBuilder.(*array.Int32Builder).AppendValues(row.GetIntData().GetData(), nil)
+		case schemapb.DataType_Int64:
+			valueBuilder.(*array.Int64Builder).AppendValues(row.GetLongData().GetData(), nil)
+		case schemapb.DataType_Float:
+			valueBuilder.(*array.Float32Builder).AppendValues(row.GetFloatData().GetData(), nil)
+		case schemapb.DataType_Double:
+			valueBuilder.(*array.Float64Builder).AppendValues(row.GetDoubleData().GetData(), nil)
+		case schemapb.DataType_String, schemapb.DataType_VarChar:
+			valueBuilder.(*array.StringBuilder).AppendValues(row.GetStringData().GetData(), nil)
+		default:
+			return nil, merr.WrapErrParameterInvalidMsg("unsupported element type %s of field %s for parquet export",
+				field.GetElementType().String(), field.GetName())
+		}
+	}
+	return builder.NewArray(), nil
+}
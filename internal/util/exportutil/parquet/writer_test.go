@@ -0,0 +1,100 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	importparquet "github.com/milvus-io/milvus/internal/util/importutilv2/parquet"
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+type bytesFileReader struct {
+	*bytes.Reader
+}
+
+func (r *bytesFileReader) Close() error {
+	return nil
+}
+
+func TestWriter(t *testing.T) {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: common.RowIDField, Name: common.RowIDFieldName, DataType: schemapb.DataType_Int64},
+			{FieldID: common.TimeStampField, Name: common.TimeStampFieldName, DataType: schemapb.DataType_Int64},
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "name", DataType: schemapb.DataType_VarChar, TypeParams: []*commonpb.KeyValuePair{{Key: common.MaxLengthKey, Value: "64"}}},
+			{FieldID: 102, Name: "meta", DataType: schemapb.DataType_JSON},
+			{FieldID: 103, Name: "tags", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Int32},
+			{FieldID: 104, Name: "vec", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}}},
+			{FieldID: 105, Name: "bvec", DataType: schemapb.DataType_BinaryVector, TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "8"}}},
+		},
+	}
+	data := &storage.InsertData{Data: map[storage.FieldID]storage.FieldData{
+		common.RowIDField:     &storage.Int64FieldData{Data: []int64{1, 2}},
+		common.TimeStampField: &storage.Int64FieldData{Data: []int64{10, 20}},
+		100:                   &storage.Int64FieldData{Data: []int64{1000, 1001}},
+		101:                   &storage.StringFieldData{Data: []string{"a", "b"}},
+		102:                   &storage.JSONFieldData{Data: [][]byte{[]byte(`{"x":1}`), []byte(`{"y":2}`)}},
+		103: &storage.ArrayFieldData{ElementType: schemapb.DataType_Int32, Data: []*schemapb.ScalarField{
+			{Data: &schemapb.ScalarField_IntData{IntData: &schemapb.IntArray{Data: []int32{1, 2}}}},
+			{Data: &schemapb.ScalarField_IntData{IntData: &schemapb.IntArray{Data: []int32{3}}}},
+		}},
+		104: &storage.FloatVectorFieldData{Dim: 2, Data: []float32{0.1, 0.2, 0.3, 0.4}},
+		105: &storage.BinaryVectorFieldData{Dim: 8, Data: []byte{0x0f, 0xf0}},
+	}}
+
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf, schema)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Write(data))
+	assert.NoError(t, w.Close())
+
+	// the exported file can be read back by the bulk insert reader with the user fields
+	userSchema := &schemapb.CollectionSchema{Fields: schema.GetFields()[2:]}
+	r, err := importparquet.NewReader(context.Background(), userSchema, &bytesFileReader{bytes.NewReader(buf.Bytes())}, 64*1024*1024)
+	assert.NoError(t, err)
+	defer r.Close()
+	actual, err := r.Read()
+	assert.NoError(t, err)
+	for _, field := range userSchema.GetFields() {
+		assert.Equal(t, data.Data[field.GetFieldID()].GetRows(), actual.Data[field.GetFieldID()].GetRows(), field.GetName())
+	}
+
+	t.Run("missing field", func(t *testing.T) {
+		w, err := NewWriter(&bytes.Buffer{}, schema)
+		assert.NoError(t, err)
+		err = w.Write(&storage.InsertData{Data: map[storage.FieldID]storage.FieldData{
+			100: &storage.Int64FieldData{Data: []int64{1}},
+		}})
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := NewWriter(&bytes.Buffer{}, &schemapb.CollectionSchema{
+			Fields: []*schemapb.FieldSchema{{FieldID: 100, Name: "f", DataType: schemapb.DataType_None}},
+		})
+		assert.Error(t, err)
+	})
+}
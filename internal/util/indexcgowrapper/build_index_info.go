@@ -33,13 +33,16 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proto/indexcgopb"
 	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/internal/storage"
 )
 
 type BuildIndexInfo struct {
 	cBuildIndexInfo C.CBuildIndexInfo
 }
 
-func NewBuildIndexInfo(config *indexpb.StorageConfig) (*BuildIndexInfo, error) {
+// NewBuildIndexInfo returns the info of building the index, the index files are put by the segcore
+// with the encryption, the storage class and the tags of the policy.
+func NewBuildIndexInfo(config *indexpb.StorageConfig, policy storage.ObjectPolicy) (*BuildIndexInfo, error) {
 	var cBuildIndexInfo C.CBuildIndexInfo
 
 	cAddress := C.CString(config.Address)
@@ -51,6 +54,10 @@ func NewBuildIndexInfo(config *indexpb.StorageConfig) (*BuildIndexInfo, error) {
 	cIamEndPoint := C.CString(config.IAMEndpoint)
 	cRegion := C.CString(config.Region)
 	cCloudProvider := C.CString(config.CloudProvider)
+	cSSEType := C.CString(policy.SSE)
+	cSSEKMSKeyID := C.CString(policy.KMSKeyID)
+	cStorageClass := C.CString(policy.StorageClass)
+	cObjectTagging := C.CString(policy.Tagging())
 	defer C.free(unsafe.Pointer(cAddress))
 	defer C.free(unsafe.Pointer(cBucketName))
 	defer C.free(unsafe.Pointer(cAccessKey))
@@ -60,6 +67,10 @@ func NewBuildIndexInfo(config *indexpb.StorageConfig) (*BuildIndexInfo, error) {
 	defer C.free(unsafe.Pointer(cIamEndPoint))
 	defer C.free(unsafe.Pointer(cRegion))
 	defer C.free(unsafe.Pointer(cCloudProvider))
+	defer C.free(unsafe.Pointer(cSSEType))
+	defer C.free(unsafe.Pointer(cSSEKMSKeyID))
+	defer C.free(unsafe.Pointer(cStorageClass))
+	defer C.free(unsafe.Pointer(cObjectTagging))
 	storageConfig := C.CStorageConfig{
 		address:          cAddress,
 		bucket_name:      cBucketName,
@@ -74,6 +85,10 @@ func NewBuildIndexInfo(config *indexpb.StorageConfig) (*BuildIndexInfo, error) {
 		region:           cRegion,
 		useVirtualHost:   C.bool(config.UseVirtualHost),
 		requestTimeoutMs: C.int64_t(config.RequestTimeoutMs),
+		sse_type:         cSSEType,
+		sse_kms_key_id:   cSSEKMSKeyID,
+		storage_class:    cStorageClass,
+		object_tagging:   cObjectTagging,
 	}
 
 	status := C.NewBuildIndexInfo(&cBuildIndexInfo, storageConfig)
@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/errors"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 )
@@ -65,16 +66,33 @@ func InitTraceConfig(params *paramtable.ComponentParam) {
 }
 
 func InitRemoteChunkManager(params *paramtable.ComponentParam) error {
+	policy, err := storage.SegcoreIndexFilesPolicy(params)
+	if err != nil {
+		return err
+	}
 	cAddress := C.CString(params.MinioCfg.Address.GetValue())
 	cBucketName := C.CString(params.MinioCfg.BucketName.GetValue())
 	cAccessKey := C.CString(params.MinioCfg.AccessKeyID.GetValue())
 	cAccessValue := C.CString(params.MinioCfg.SecretAccessKey.GetValue())
 	cRootPath := C.CString(params.MinioCfg.RootPath.GetValue())
-	cStorageType := C.CString(params.CommonCfg.StorageType.GetValue())
+	// the segcore knows the azure storage only as the remote storage of the azure cloud provider,
+	// and the native gcs as the gcp cloud provider
+	storageType, cloudProvider := params.CommonCfg.StorageType.GetValue(), params.MinioCfg.CloudProvider.GetValue()
+	if storageType == "azure" {
+		storageType, cloudProvider = "remote", "azure"
+	}
+	if cloudProvider == "gcpnative" {
+		cloudProvider = "gcp"
+	}
+	cStorageType := C.CString(storageType)
 	cIamEndPoint := C.CString(params.MinioCfg.IAMEndpoint.GetValue())
-	cCloudProvider := C.CString(params.MinioCfg.CloudProvider.GetValue())
+	cCloudProvider := C.CString(cloudProvider)
 	cLogLevel := C.CString(params.MinioCfg.LogLevel.GetValue())
 	cRegion := C.CString(params.MinioCfg.Region.GetValue())
+	cSSEType := C.CString(policy.SSE)
+	cSSEKMSKeyID := C.CString(policy.KMSKeyID)
+	cStorageClass := C.CString(policy.StorageClass)
+	cObjectTagging := C.CString(policy.Tagging())
 	defer C.free(unsafe.Pointer(cAddress))
 	defer C.free(unsafe.Pointer(cBucketName))
 	defer C.free(unsafe.Pointer(cAccessKey))
@@ -85,6 +103,10 @@ func InitRemoteChunkManager(params *paramtable.ComponentParam) error {
 	defer C.free(unsafe.Pointer(cLogLevel))
 	defer C.free(unsafe.Pointer(cRegion))
 	defer C.free(unsafe.Pointer(cCloudProvider))
+	defer C.free(unsafe.Pointer(cSSEType))
+	defer C.free(unsafe.Pointer(cSSEKMSKeyID))
+	defer C.free(unsafe.Pointer(cStorageClass))
+	defer C.free(unsafe.Pointer(cObjectTagging))
 	storageConfig := C.CStorageConfig{
 		address:          cAddress,
 		bucket_name:      cBucketName,
@@ -100,6 +122,10 @@ func InitRemoteChunkManager(params *paramtable.ComponentParam) error {
 		region:           cRegion,
 		useVirtualHost:   C.bool(params.MinioCfg.UseVirtualHost.GetAsBool()),
 		requestTimeoutMs: C.int64_t(params.MinioCfg.RequestTimeoutMs.GetAsInt64()),
+		sse_type:         cSSEType,
+		sse_kms_key_id:   cSSEKMSKeyID,
+		storage_class:    cStorageClass,
+		object_tagging:   cObjectTagging,
 	}
 
 	status := C.InitRemoteChunkManagerSingleton(storageConfig)
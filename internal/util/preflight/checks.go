@@ -0,0 +1,330 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/dependency"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper"
+	"github.com/milvus-io/milvus/pkg/util"
+	"github.com/milvus-io/milvus/pkg/util/etcd"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tikv"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const probePrefix = "preflight"
+
+// probeKey returns a unique key for the probes of this run.
+func probeKey() string {
+	return fmt.Sprintf("%s-%d-%d", probePrefix, os.Getpid(), time.Now().UnixNano())
+}
+
+// checkConfig validates the params which the components fail or misbehave with.
+func checkConfig(ctx context.Context, params *paramtable.ComponentParam, standalone bool) (string, error) {
+	var errs []error
+	if !params.EtcdCfg.UseEmbedEtcd.GetAsBool() && len(params.EtcdCfg.Endpoints.GetAsStrings()) == 0 {
+		errs = append(errs, errors.New("etcd.endpoints is empty"))
+	}
+	if params.EtcdCfg.UseEmbedEtcd.GetAsBool() && !standalone {
+		errs = append(errs, errors.New("etcd.use.embed is only valid in standalone mode"))
+	}
+	switch metaType := params.MetaStoreCfg.MetaStoreType.GetValue(); metaType {
+	case util.MetaStoreTypeEtcd:
+	case util.MetaStoreTypeTiKV:
+		if len(params.TiKVCfg.Endpoints.GetAsStrings()) == 0 {
+			errs = append(errs, errors.New("tikv.endpoints is empty"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("metastore.type %s is invalid, available values are [%s, %s]", metaType, util.MetaStoreTypeEtcd, util.MetaStoreTypeTiKV))
+	}
+
+	if _, err := dependency.SelectMQType(standalone, params); err != nil {
+		errs = append(errs, err)
+	}
+
+	switch storageType := params.CommonCfg.StorageType.GetValue(); storageType {
+	case "local":
+		if params.LocalStorageCfg.Path.GetValue() == "" {
+			errs = append(errs, errors.New("localStorage.path is empty"))
+		}
+	case "minio", "remote", "opendal", "azure":
+		if params.MinioCfg.Address.GetValue() == "" {
+			errs = append(errs, errors.New("minio.address is empty"))
+		}
+		if params.MinioCfg.BucketName.GetValue() == "" {
+			errs = append(errs, errors.New("minio.bucketName is empty"))
+		}
+	case "hdfs":
+		if params.HDFSCfg.Address.GetValue() == "" {
+			errs = append(errs, errors.New("hdfs.address is empty"))
+		}
+		if params.HDFSCfg.BaseDir.GetValue() == "" {
+			errs = append(errs, errors.New("hdfs.baseDir is empty"))
+		}
+	default:
+		// the storage types of the backends compiled in as plugins are registered
+		storageTypes := storage.RegisteredStorageTypes()
+		if !lo.Contains(storageTypes, storageType) {
+			errs = append(errs, fmt.Errorf("common.storageType %s is invalid, available values are [%s]", storageType, strings.Join(storageTypes, ", ")))
+		} else if err := storage.CheckSegcoreStorageType(storageType); err != nil &&
+			(standalone || lo.Contains([]string{typeutil.QueryNodeRole, typeutil.IndexNodeRole}, paramtable.GetRole())) {
+			// the segcore of the querynode and the indexnode can't access the plugins
+			errs = append(errs, err)
+		}
+	}
+
+	for _, item := range []*paramtable.ParamItem{&params.ProxyGrpcServerCfg.Port, &params.ProxyGrpcServerCfg.InternalPort} {
+		if port := item.GetAsInt(); port <= 0 || port > 65535 {
+			errs = append(errs, fmt.Errorf("%s %s is not a valid port", item.Key, item.GetValue()))
+		}
+	}
+	return "milvus.yaml", merr.Combine(errs...)
+}
+
+// checkEtcd writes, reads and deletes a probe key under the meta root path.
+func checkEtcd(ctx context.Context, params *paramtable.ComponentParam, standalone bool) (string, error) {
+	if params.EtcdCfg.UseEmbedEtcd.GetAsBool() {
+		dataDir := params.EtcdCfg.DataDir.GetValue()
+		return "embedded etcd " + dataDir, checkWritableDir(dataDir)
+	}
+
+	target := strings.Join(params.EtcdCfg.Endpoints.GetAsStrings(), ",")
+	cli, err := etcd.GetEtcdClient(
+		false,
+		params.EtcdCfg.EtcdUseSSL.GetAsBool(),
+		params.EtcdCfg.Endpoints.GetAsStrings(),
+		params.EtcdCfg.EtcdTLSCert.GetValue(),
+		params.EtcdCfg.EtcdTLSKey.GetValue(),
+		params.EtcdCfg.EtcdTLSCACert.GetValue(),
+		params.EtcdCfg.EtcdTLSMinVersion.GetValue())
+	if err != nil {
+		return target, err
+	}
+	defer cli.Close()
+
+	key := path.Join(params.EtcdCfg.MetaRootPath.GetValue(), probeKey())
+	value := time.Now().String()
+	if _, err := cli.Put(ctx, key, value); err != nil {
+		return target, errors.Wrap(err, "failed to write")
+	}
+	defer cli.Delete(context.Background(), key)
+	resp, err := cli.Get(ctx, key)
+	if err != nil {
+		return target, errors.Wrap(err, "failed to read")
+	}
+	if len(resp.Kvs) != 1 || string(resp.Kvs[0].Value) != value {
+		return target, errors.New("the value read mismatches the one written")
+	}
+	if _, err := cli.Delete(ctx, key); err != nil {
+		return target, errors.Wrap(err, "failed to delete")
+	}
+	return target, nil
+}
+
+// checkTiKV writes, reads and deletes a probe key if TiKV is the meta store.
+func checkTiKV(ctx context.Context, params *paramtable.ComponentParam, standalone bool) (string, error) {
+	if params.MetaStoreCfg.MetaStoreType.GetValue() != util.MetaStoreTypeTiKV {
+		return "metastore.type is " + params.MetaStoreCfg.MetaStoreType.GetValue(), errSkipped
+	}
+
+	target := params.TiKVCfg.Endpoints.GetValue()
+	cli, err := tikv.GetTiKVClient(&params.TiKVCfg)
+	if err != nil {
+		return target, err
+	}
+	defer cli.Close()
+
+	key := []byte(path.Join(params.TiKVCfg.MetaRootPath.GetValue(), probeKey()))
+	value := []byte(time.Now().String())
+	txn, err := cli.Begin()
+	if err != nil {
+		return target, err
+	}
+	if err := txn.Set(key, value); err != nil {
+		return target, errors.Wrap(err, "failed to write")
+	}
+	if err := txn.Commit(ctx); err != nil {
+		return target, errors.Wrap(err, "failed to write")
+	}
+
+	txn, err = cli.Begin()
+	if err != nil {
+		return target, err
+	}
+	read, err := txn.Get(ctx, key)
+	if err != nil {
+		return target, errors.Wrap(err, "failed to read")
+	}
+	if !bytes.Equal(read, value) {
+		return target, errors.New("the value read mismatches the one written")
+	}
+	if err := txn.Delete(key); err != nil {
+		return target, errors.Wrap(err, "failed to delete")
+	}
+	if err := txn.Commit(ctx); err != nil {
+		return target, errors.Wrap(err, "failed to delete")
+	}
+	return target, nil
+}
+
+// checkMQ produces a message and consumes it by the msgstream of the selected MQ,
+// the embedded MQs could not be opened while Milvus running, so only their directories are checked.
+func checkMQ(ctx context.Context, params *paramtable.ComponentParam, standalone bool) (string, error) {
+	mqType, err := dependency.SelectMQType(standalone, params)
+	if err != nil {
+		return params.MQCfg.Type.GetValue(), err
+	}
+
+	var factory msgstream.Factory
+	var target string
+	switch mqType {
+	case "rocksmq":
+		dir := params.RocksmqCfg.Path.GetValue()
+		return "rocksmq " + dir, checkWritableDir(dir)
+	case "natsmq":
+		dir := params.NatsmqCfg.ServerStoreDir.GetValue()
+		return "natsmq " + dir, checkWritableDir(dir)
+	case "pulsar":
+		target = "pulsar " + params.PulsarCfg.Address.GetValue()
+		factory = msgstream.NewPmsFactory(&params.ServiceParam)
+	case "kafka":
+		target = "kafka " + params.KafkaCfg.Address.GetValue()
+		factory = msgstream.NewKmsFactory(&params.ServiceParam)
+	}
+	return target, probeMsgStream(ctx, factory, params.CommonCfg.ClusterPrefix.GetValue()+"-"+probePrefix)
+}
+
+func probeMsgStream(ctx context.Context, factory msgstream.Factory, channel string) error {
+	subName := probeKey()
+	consumer, err := factory.NewMsgStream(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create consumer")
+	}
+	defer consumer.Close()
+	// subscribe from latest before producing, so the messages of the other probes are not consumed
+	if err := consumer.AsConsumer(ctx, []string{channel}, subName, mqwrapper.SubscriptionPositionLatest); err != nil {
+		return errors.Wrap(err, "failed to subscribe")
+	}
+	defer factory.NewMsgStreamDisposer(context.Background())([]string{channel}, subName)
+
+	producer, err := factory.NewMsgStream(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create producer")
+	}
+	defer producer.Close()
+	producer.AsProducer([]string{channel})
+
+	ts := uint64(time.Now().UnixNano())
+	msg := &msgstream.TimeTickMsg{
+		BaseMsg: msgstream.BaseMsg{
+			BeginTimestamp: ts,
+			EndTimestamp:   ts,
+			HashValues:     []uint32{0},
+		},
+		TimeTickMsg: msgpb.TimeTickMsg{
+			Base: &commonpb.MsgBase{
+				MsgType:   commonpb.MsgType_TimeTick,
+				Timestamp: ts,
+			},
+		},
+	}
+	if err := producer.Produce(&msgstream.MsgPack{BeginTs: ts, EndTs: ts, Msgs: []msgstream.TsMsg{msg}}); err != nil {
+		return errors.Wrap(err, "failed to produce")
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "failed to consume the message produced")
+		case pack, ok := <-consumer.Chan():
+			if !ok {
+				return errors.New("consumer closed before the message produced consumed")
+			}
+			for _, msg := range pack.Msgs {
+				if tt, ok := msg.(*msgstream.TimeTickMsg); ok && tt.GetBase().GetTimestamp() == ts {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// checkStorage writes, reads and removes an object by the ChunkManager.
+func checkStorage(ctx context.Context, params *paramtable.ComponentParam, standalone bool) (string, error) {
+	storageType := params.CommonCfg.StorageType.GetValue()
+	target := storageType + " " + params.LocalStorageCfg.Path.GetValue()
+	if storageType == "hdfs" {
+		target = fmt.Sprintf("%s %s%s", storageType, params.HDFSCfg.Address.GetValue(), params.HDFSCfg.BaseDir.GetValue())
+	} else if storageType != "local" {
+		target = fmt.Sprintf("%s %s/%s", storageType, params.MinioCfg.Address.GetValue(), params.MinioCfg.BucketName.GetValue())
+	}
+
+	cm, err := storage.NewChunkManagerFactoryWithParam(params).NewPersistentStorageChunkManager(ctx)
+	if err != nil {
+		return target, err
+	}
+	filePath := path.Join(cm.RootPath(), probePrefix, probeKey())
+	content := []byte(time.Now().String())
+	if err := cm.Write(ctx, filePath, content); err != nil {
+		return target, errors.Wrap(err, "failed to write")
+	}
+	defer cm.Remove(context.Background(), filePath)
+	read, err := cm.Read(ctx, filePath)
+	if err != nil {
+		return target, errors.Wrap(err, "failed to read")
+	}
+	if !bytes.Equal(read, content) {
+		return target, errors.New("the content read mismatches the one written")
+	}
+	if err := cm.Remove(ctx, filePath); err != nil {
+		return target, errors.Wrap(err, "failed to remove")
+	}
+	return target, nil
+}
+
+// checkWritableDir creates the dir if not exist, and checks a file could be created in it.
+func checkWritableDir(dir string) error {
+	if dir == "" {
+		return errors.New("the directory is empty")
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, probePrefix)
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(filepath.Clean(f.Name()))
+}
@@ -0,0 +1,154 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package preflight validates the config and probes the dependencies, i.e. the meta store, MQ and object storage,
+// so the misconfiguration is reported with actionable errors before serving the traffic.
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// Result is the result of a check.
+type Result struct {
+	Name string
+	// Target describes what is checked, e.g. the endpoints.
+	Target  string
+	Elapsed time.Duration
+	// Skipped is true if the check doesn't apply to the config.
+	Skipped bool
+	Err     error
+	// Hint is the suggestion to fix the failure.
+	Hint string
+}
+
+// check probes a dependency, it returns the target checked,
+// and the hint to fix it if the check fails.
+type check struct {
+	name string
+	run  func(ctx context.Context, params *paramtable.ComponentParam, standalone bool) (target string, err error)
+	hint string
+}
+
+// errSkipped means the check doesn't apply to the config.
+var errSkipped = errors.New("skipped")
+
+var checks = []check{
+	{
+		name: "config",
+		run:  checkConfig,
+		hint: "fix the params in milvus.yaml or the environment variables overriding them",
+	},
+	{
+		name: "etcd",
+		run:  checkEtcd,
+		hint: "check etcd.endpoints is reachable, and etcd.ssl if TLS enabled",
+	},
+	{
+		name: "tikv",
+		run:  checkTiKV,
+		hint: "check tikv.endpoints is reachable, or set metastore.type to etcd",
+	},
+	{
+		name: "mq",
+		run:  checkMQ,
+		hint: "check mq.type and the address and authentication of the selected MQ, e.g. pulsar.address or kafka.brokerList",
+	},
+	{
+		name: "storage",
+		run:  checkStorage,
+		hint: "check the address, bucket and credentials of minio, or the permission of localStorage.path if local storage used",
+	},
+}
+
+// Run runs all the checks and returns their results, each check is timed out by common.preflight.timeout.
+// The standalone mode decides which MQ is used.
+func Run(ctx context.Context, params *paramtable.ComponentParam, standalone bool) []*Result {
+	timeout := params.CommonCfg.PreflightTimeout.GetAsDuration(time.Second)
+	results := make([]*Result, 0, len(checks))
+	for _, c := range checks {
+		results = append(results, runCheck(ctx, c, params, standalone, timeout))
+	}
+	return results
+}
+
+func runCheck(ctx context.Context, c check, params *paramtable.ComponentParam, standalone bool, timeout time.Duration) *Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := &Result{Name: c.name}
+	result.Target, result.Err = c.run(ctx, params, standalone)
+	result.Elapsed = time.Since(start)
+	if errors.Is(result.Err, errSkipped) {
+		result.Skipped = true
+		result.Err = nil
+	}
+	if result.Err != nil {
+		result.Hint = c.hint
+	}
+	return result
+}
+
+// Check runs all the checks and logs the results, it returns the error if any check fails.
+func Check(ctx context.Context, params *paramtable.ComponentParam, standalone bool) error {
+	var errs []error
+	for _, result := range Run(ctx, params, standalone) {
+		fields := []zap.Field{zap.String("check", result.Name), zap.String("target", result.Target), zap.Duration("elapsed", result.Elapsed)}
+		switch {
+		case result.Skipped:
+			log.Info("preflight check skipped", fields...)
+		case result.Err != nil:
+			log.Error("preflight check failed", append(fields, zap.Error(result.Err), zap.String("hint", result.Hint))...)
+			errs = append(errs, fmt.Errorf("%s: %w", result.Name, result.Err))
+		default:
+			log.Info("preflight check passed", fields...)
+		}
+	}
+	if len(errs) > 0 {
+		return merr.WrapErrServiceInternal("preflight check failed", merr.Combine(errs...).Error())
+	}
+	return nil
+}
+
+// Report prints the results, it returns true if all the checks passed.
+func Report(w io.Writer, results []*Result) bool {
+	passed := true
+	for _, result := range results {
+		switch {
+		case result.Skipped:
+			fmt.Fprintf(w, "[SKIP] %-8s %s\n", result.Name, result.Target)
+		case result.Err != nil:
+			passed = false
+			fmt.Fprintf(w, "[FAIL] %-8s %s (%s)\n", result.Name, result.Target, result.Elapsed.Round(time.Millisecond))
+			fmt.Fprintf(w, "       error: %s\n", result.Err.Error())
+			fmt.Fprintf(w, "       hint:  %s\n", result.Hint)
+		default:
+			fmt.Fprintf(w, "[ OK ] %-8s %s (%s)\n", result.Name, result.Target, result.Elapsed.Round(time.Millisecond))
+		}
+	}
+	return passed
+}
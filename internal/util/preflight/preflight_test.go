@@ -0,0 +1,138 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preflight
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type PreflightSuite struct {
+	suite.Suite
+	params *paramtable.ComponentParam
+}
+
+func (s *PreflightSuite) SetupSuite() {
+	paramtable.Init()
+	s.params = paramtable.Get()
+}
+
+func (s *PreflightSuite) SetupTest() {
+	dir := s.T().TempDir()
+	s.params.Save(s.params.CommonCfg.StorageType.Key, "local")
+	s.params.Save(s.params.LocalStorageCfg.Path.Key, filepath.Join(dir, "data"))
+	s.params.Save(s.params.RocksmqCfg.Path.Key, filepath.Join(dir, "rocksmq"))
+	s.params.Save(s.params.MQCfg.Type.Key, "rocksmq")
+	s.params.Save(s.params.CommonCfg.PreflightTimeout.Key, "5")
+}
+
+func (s *PreflightSuite) TearDownTest() {
+	for _, key := range []string{
+		s.params.CommonCfg.StorageType.Key,
+		s.params.LocalStorageCfg.Path.Key,
+		s.params.RocksmqCfg.Path.Key,
+		s.params.MQCfg.Type.Key,
+		s.params.CommonCfg.PreflightTimeout.Key,
+		s.params.EtcdCfg.Endpoints.Key,
+		s.params.MetaStoreCfg.MetaStoreType.Key,
+	} {
+		s.params.Reset(key)
+	}
+}
+
+func (s *PreflightSuite) getResult(results []*Result, name string) *Result {
+	for _, result := range results {
+		if result.Name == name {
+			return result
+		}
+	}
+	s.FailNow("result not found", name)
+	return nil
+}
+
+func (s *PreflightSuite) TestPassed() {
+	results := Run(context.Background(), s.params, true)
+	s.Len(results, len(checks))
+	for _, result := range results {
+		s.NoError(result.Err, result.Name)
+	}
+	s.True(s.getResult(results, "tikv").Skipped)
+	s.NoError(Check(context.Background(), s.params, true))
+
+	// the probes are cleaned up
+	entries, err := os.ReadDir(filepath.Join(s.params.LocalStorageCfg.Path.GetValue(), probePrefix))
+	s.NoError(err)
+	s.Empty(entries)
+
+	out := &strings.Builder{}
+	s.True(Report(out, results))
+	s.Contains(out.String(), "[ OK ] storage")
+	s.Contains(out.String(), "[SKIP] tikv")
+}
+
+func (s *PreflightSuite) TestInvalidConfig() {
+	s.params.Save(s.params.CommonCfg.StorageType.Key, "hdfs")
+	s.params.Save(s.params.MetaStoreCfg.MetaStoreType.Key, "mysql")
+
+	// rocksmq is not available in cluster mode
+	results := Run(context.Background(), s.params, false)
+	result := s.getResult(results, "config")
+	s.Error(result.Err)
+	s.NotEmpty(result.Hint)
+	s.Contains(result.Err.Error(), "common.storageType")
+	s.Contains(result.Err.Error(), "metastore.type")
+	s.Contains(result.Err.Error(), "rocksmq")
+	s.Error(s.getResult(results, "mq").Err)
+	s.Error(s.getResult(results, "storage").Err)
+	s.Error(Check(context.Background(), s.params, false))
+
+	out := &strings.Builder{}
+	s.False(Report(out, results))
+	s.Contains(out.String(), "[FAIL] config")
+	s.Contains(out.String(), "hint:")
+}
+
+func (s *PreflightSuite) TestUnreachableEtcd() {
+	s.params.Save(s.params.EtcdCfg.Endpoints.Key, "localhost:1")
+	s.params.Save(s.params.CommonCfg.PreflightTimeout.Key, "1")
+
+	results := Run(context.Background(), s.params, true)
+	result := s.getResult(results, "etcd")
+	s.Error(result.Err)
+	s.Equal("localhost:1", result.Target)
+	s.NoError(s.getResult(results, "storage").Err)
+}
+
+func (s *PreflightSuite) TestUnwritableDir() {
+	file := filepath.Join(s.T().TempDir(), "file")
+	s.Require().NoError(os.WriteFile(file, nil, 0o600))
+	s.params.Save(s.params.RocksmqCfg.Path.Key, file)
+
+	results := Run(context.Background(), s.params, true)
+	s.Error(s.getResult(results, "mq").Err)
+}
+
+func TestPreflight(t *testing.T) {
+	suite.Run(t, new(PreflightSuite))
+}
@@ -0,0 +1,144 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// Diff is the difference between the results of the target and the baseline.
+type Diff struct {
+	// Recall is the ratio of the results returned by both clusters,
+	// divided by the larger number of results.
+	Recall float64
+	// Reason describes why the results could not be compared, empty if compared.
+	Reason string
+}
+
+// diffSearchResults returns the average recall of the queries, the order and scores are ignored.
+func diffSearchResults(target, baseline *schemapb.SearchResultData) *Diff {
+	if target.GetNumQueries() != baseline.GetNumQueries() {
+		return &Diff{Reason: fmt.Sprintf("nq mismatch, target: %d, baseline: %d", target.GetNumQueries(), baseline.GetNumQueries())}
+	}
+	if target.GetNumQueries() == 0 {
+		return &Diff{Recall: 1}
+	}
+
+	targetIDs := splitSearchIDs(target)
+	baselineIDs := splitSearchIDs(baseline)
+	if len(targetIDs) != len(baselineIDs) {
+		return &Diff{Reason: fmt.Sprintf("topks mismatch, target: %d, baseline: %d", len(targetIDs), len(baselineIDs))}
+	}
+	recall := 0.0
+	for i := range targetIDs {
+		recall += calcRecall(targetIDs[i], baselineIDs[i])
+	}
+	return &Diff{Recall: recall / float64(len(targetIDs))}
+}
+
+// splitSearchIDs returns the ids of each query.
+func splitSearchIDs(result *schemapb.SearchResultData) [][]string {
+	ids := make([][]string, 0, len(result.GetTopks()))
+	size := int64(typeutil.GetSizeOfIDs(result.GetIds()))
+	offset := int64(0)
+	for _, topk := range result.GetTopks() {
+		queryIDs := make([]string, 0, topk)
+		for i := offset; i < offset+topk && i < size; i++ {
+			queryIDs = append(queryIDs, fmt.Sprint(typeutil.GetPK(result.GetIds(), i)))
+		}
+		ids = append(ids, queryIDs)
+		offset += topk
+	}
+	return ids
+}
+
+// diffQueryResults returns the recall of the rows, the order of the rows is ignored.
+func diffQueryResults(target, baseline []*schemapb.FieldData) *Diff {
+	targetRows, err := splitQueryRows(target)
+	if err != nil {
+		return &Diff{Reason: fmt.Sprintf("invalid target results, %s", err.Error())}
+	}
+	baselineRows, err := splitQueryRows(baseline)
+	if err != nil {
+		return &Diff{Reason: fmt.Sprintf("invalid baseline results, %s", err.Error())}
+	}
+	return &Diff{Recall: calcRecall(targetRows, baselineRows)}
+}
+
+// splitQueryRows returns the rows printed with the fields sorted by name.
+func splitQueryRows(fields []*schemapb.FieldData) ([]string, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	fields = append([]*schemapb.FieldData{}, fields...)
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].GetFieldName() < fields[j].GetFieldName()
+	})
+
+	numRows, err := funcutil.GetNumRowOfFieldData(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields[1:] {
+		n, err := funcutil.GetNumRowOfFieldData(field)
+		if err != nil {
+			return nil, err
+		}
+		if n != numRows {
+			return nil, fmt.Errorf("field %s has %d rows, field %s has %d rows", fields[0].GetFieldName(), numRows, field.GetFieldName(), n)
+		}
+	}
+
+	rows := make([]string, 0, numRows)
+	for i := 0; i < int(numRows); i++ {
+		values := make([]string, 0, len(fields))
+		for _, field := range fields {
+			values = append(values, fmt.Sprintf("%s=%v", field.GetFieldName(), typeutil.GetData(field, i)))
+		}
+		rows = append(rows, strings.Join(values, ","))
+	}
+	return rows, nil
+}
+
+func calcRecall(target, baseline []string) float64 {
+	total := len(target)
+	if len(baseline) > total {
+		total = len(baseline)
+	}
+	if total == 0 {
+		return 1
+	}
+
+	counts := make(map[string]int, len(baseline))
+	for _, value := range baseline {
+		counts[value]++
+	}
+	hits := 0
+	for _, value := range target {
+		if counts[value] > 0 {
+			counts[value]--
+			hits++
+		}
+	}
+	return float64(hits) / float64(total)
+}
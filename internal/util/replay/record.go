@@ -0,0 +1,235 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replay replays the search and query workload captured by the proxy access log
+// against a cluster, and diffs the results with a baseline cluster.
+//
+// The requests are captured by the `$method_request` metric, e.g.
+//
+//	proxy:
+//	  accessLog:
+//	    formatters:
+//	      replay:
+//	        format: "[$time_start] $method_name [timeCost: $time_cost] [request: $method_request]"
+//	        methods: ["Search", "HybridSearch", "Query"]
+//
+// The vectors searched are redacted by the access log, which are replaced by random ones
+// of the same type and dim, so the recalls diffed are of the random vectors.
+package replay
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/proxy/accesslog"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+const (
+	metricMethodName = "$method_name"
+	metricRequest    = "$method_request"
+	metricTimeStart  = "$time_start"
+	metricTimeNow    = "$time_now"
+	metricTimeCost   = "$time_cost"
+
+	// the base64 encoded requests may carry lots of vectors
+	maxLineSize = 64 * 1024 * 1024
+)
+
+// Methods are the methods could be replayed.
+var Methods = []string{"Search", "HybridSearch", "Query"}
+
+// Record is a request captured by the access log.
+type Record struct {
+	// Line is the line number in the access log.
+	Line   int
+	Method string
+	// Time is when the request was received, zero if not logged.
+	Time time.Time
+	// Cost is the latency of the request when captured, zero if not logged.
+	Cost    time.Duration
+	Request proto.Message
+}
+
+// Reader reads the replayable records from the access log.
+type Reader struct {
+	scanner   *bufio.Scanner
+	formatter *accesslog.Formatter
+	line      int
+	skipped   int
+	// rand generates the vectors in place of the redacted ones, seeded by a constant to replay the same vectors
+	rand *rand.Rand
+}
+
+// NewReader returns a Reader parsing the lines by the access log format,
+// which must contain the $method_name and $method_request metrics.
+func NewReader(r io.Reader, format string) (*Reader, error) {
+	formatter := accesslog.NewFormatter(format)
+	// parsing the format itself yields the metrics in it
+	values, err := formatter.Parse(format)
+	if err != nil {
+		return nil, err
+	}
+	for _, metric := range []string{metricMethodName, metricRequest} {
+		if _, ok := values[metric]; !ok {
+			return nil, merr.WrapErrParameterInvalidMsg("access log format %s has no %s", format, metric)
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &Reader{
+		scanner:   scanner,
+		formatter: formatter,
+		rand:      rand.New(rand.NewSource(0)),
+	}, nil
+}
+
+// Next returns the next replayable record, or io.EOF at the end of the log.
+// The lines of other formats or methods are skipped.
+func (r *Reader) Next() (*Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		record, err := r.parse(r.scanner.Text())
+		if err != nil {
+			r.skipped++
+			continue
+		}
+		return record, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read line %d", r.line+1)
+	}
+	return nil, io.EOF
+}
+
+// Skipped returns the number of lines skipped so far.
+func (r *Reader) Skipped() int {
+	return r.skipped
+}
+
+func (r *Reader) parse(line string) (*Record, error) {
+	values, err := r.formatter.Parse(line)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := decodeRequest(values[metricMethodName], values[metricRequest])
+	if err != nil {
+		return nil, err
+	}
+	fillRedactedVectors(request, r.rand)
+	record := &Record{
+		Line:    r.line,
+		Method:  values[metricMethodName],
+		Request: request,
+	}
+	if value, ok := values[metricTimeStart]; ok {
+		record.Time, _ = time.Parse(accesslog.TimePrintFormat, value)
+	} else if value, ok := values[metricTimeNow]; ok {
+		record.Time, _ = time.Parse(accesslog.TimePrintFormat, value)
+	}
+	if value, ok := values[metricTimeCost]; ok {
+		record.Cost, _ = time.ParseDuration(value)
+	}
+	return record, nil
+}
+
+func decodeRequest(method string, value string) (proto.Message, error) {
+	var request proto.Message
+	switch method {
+	case "Search":
+		request = &milvuspb.SearchRequest{}
+	case "HybridSearch":
+		request = &milvuspb.HybridSearchRequest{}
+	case "Query":
+		request = &milvuspb.QueryRequest{}
+	default:
+		return nil, merr.WrapErrParameterInvalid(fmt.Sprint(Methods), method, "method could not be replayed")
+	}
+
+	bytes, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode request")
+	}
+	if err := proto.Unmarshal(bytes, request); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal request")
+	}
+	return request, nil
+}
+
+// fillRedactedVectors replaces the vectors redacted by the access log with random ones of the same type and dim,
+// the record is parsed once, so the target and the baseline search the same vectors.
+func fillRedactedVectors(request proto.Message, r *rand.Rand) {
+	switch req := request.(type) {
+	case *milvuspb.SearchRequest:
+		req.PlaceholderGroup = fillPlaceholderGroup(req.GetPlaceholderGroup(), r)
+	case *milvuspb.HybridSearchRequest:
+		for _, sub := range req.GetRequests() {
+			sub.PlaceholderGroup = fillPlaceholderGroup(sub.GetPlaceholderGroup(), r)
+		}
+	}
+}
+
+func fillPlaceholderGroup(group []byte, r *rand.Rand) []byte {
+	placeholderGroup := &commonpb.PlaceholderGroup{}
+	if err := proto.Unmarshal(group, placeholderGroup); err != nil {
+		return group
+	}
+	for _, placeholder := range placeholderGroup.GetPlaceholders() {
+		for _, value := range placeholder.GetValues() {
+			fillRandomVector(placeholder.GetType(), value, r)
+		}
+	}
+	filled, err := proto.Marshal(placeholderGroup)
+	if err != nil {
+		return group
+	}
+	return filled
+}
+
+// fillRandomVector fills the vector with random values in (-1, 1), the other placeholders are kept.
+func fillRandomVector(placeholderType commonpb.PlaceholderType, value []byte, r *rand.Rand) {
+	switch placeholderType {
+	case commonpb.PlaceholderType_FloatVector:
+		for i := 0; i+4 <= len(value); i += 4 {
+			common.Endian.PutUint32(value[i:], math.Float32bits(r.Float32()*2-1))
+		}
+	case commonpb.PlaceholderType_BFloat16Vector:
+		for i := 0; i+2 <= len(value); i += 2 {
+			common.Endian.PutUint16(value[i:], uint16(math.Float32bits(r.Float32()*2-1)>>16))
+		}
+	case commonpb.PlaceholderType_Float16Vector:
+		for i := 0; i+2 <= len(value); i += 2 {
+			// sign, exponent of 2^-14 to 2^-1 and mantissa of a normal half float
+			bits := uint16(r.Intn(2))<<15 | uint16(1+r.Intn(14))<<10 | uint16(r.Intn(1<<10))
+			common.Endian.PutUint16(value[i:], bits)
+		}
+	case commonpb.PlaceholderType_BinaryVector:
+		r.Read(value)
+	}
+}
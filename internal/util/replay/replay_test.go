@@ -0,0 +1,279 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proxy/accesslog"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const testFormat = "[$time_start] $method_name [timeCost: $time_cost] [request: $method_request]"
+
+type mockTarget struct {
+	searchIDs []int64
+	queryIDs  []int64
+	err       error
+	calls     atomic.Int32
+}
+
+func (t *mockTarget) Search(ctx context.Context, req *milvuspb.SearchRequest, opts ...grpc.CallOption) (*milvuspb.SearchResults, error) {
+	t.calls.Inc()
+	if t.err != nil {
+		return nil, t.err
+	}
+	return &milvuspb.SearchResults{
+		Status: merr.Success(),
+		Results: &schemapb.SearchResultData{
+			NumQueries: 1,
+			TopK:       int64(len(t.searchIDs)),
+			Topks:      []int64{int64(len(t.searchIDs))},
+			Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: t.searchIDs}}},
+		},
+	}, nil
+}
+
+func (t *mockTarget) HybridSearch(ctx context.Context, req *milvuspb.HybridSearchRequest, opts ...grpc.CallOption) (*milvuspb.SearchResults, error) {
+	return t.Search(ctx, nil)
+}
+
+func (t *mockTarget) Query(ctx context.Context, req *milvuspb.QueryRequest, opts ...grpc.CallOption) (*milvuspb.QueryResults, error) {
+	t.calls.Inc()
+	if t.err != nil {
+		return nil, t.err
+	}
+	return &milvuspb.QueryResults{
+		Status:     merr.Success(),
+		FieldsData: []*schemapb.FieldData{newLongField("pk", t.queryIDs...)},
+	}, nil
+}
+
+func newLongField(name string, values ...int64) *schemapb.FieldData {
+	return &schemapb.FieldData{
+		Type:      schemapb.DataType_Int64,
+		FieldName: name,
+		Field: &schemapb.FieldData_Scalars{
+			Scalars: &schemapb.ScalarField{Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: values}}},
+		},
+	}
+}
+
+func newSearchResultData(topks []int64, ids ...int64) *schemapb.SearchResultData {
+	return &schemapb.SearchResultData{
+		NumQueries: int64(len(topks)),
+		Topks:      topks,
+		Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+	}
+}
+
+type ReplaySuite struct {
+	suite.Suite
+}
+
+func (s *ReplaySuite) SetupSuite() {
+	paramtable.Init()
+}
+
+// formatLines returns the access log lines of the requests, which are sent with the interval.
+func (s *ReplaySuite) formatLines(format string, interval time.Duration, methods []string, reqs []interface{}) string {
+	formatter := accesslog.NewFormatter(format)
+	lines := strings.Builder{}
+	for i, req := range reqs {
+		info := accesslog.NewGrpcAccessInfo(context.Background(), &grpc.UnaryServerInfo{
+			FullMethod: "/milvus.proto.milvus.MilvusService/" + methods[i],
+		}, req)
+		info.SetResult(merr.Success(), nil)
+		lines.WriteString(formatter.Format(info))
+		time.Sleep(interval)
+	}
+	return lines.String()
+}
+
+func (s *ReplaySuite) TestReader() {
+	_, err := NewReader(strings.NewReader(""), "$method_name $method_expr")
+	s.Error(err)
+	_, err = NewReader(strings.NewReader(""), "$method_name$method_request")
+	s.Error(err)
+
+	log := s.formatLines(testFormat, 0, []string{"Search", "Query", "Delete", "HybridSearch"}, []interface{}{
+		&milvuspb.SearchRequest{CollectionName: "c1", Dsl: "a > 1", GuaranteeTimestamp: 1000},
+		&milvuspb.QueryRequest{CollectionName: "c2", Expr: "pk in [1, 2]"},
+		&milvuspb.DeleteRequest{CollectionName: "c1", Expr: "pk in [1, 2]"},
+		&milvuspb.HybridSearchRequest{CollectionName: "c3"},
+	})
+	log += s.formatLines("[$time_now] $method_name", 0, []string{"Search"}, []interface{}{&milvuspb.SearchRequest{}})
+	log += "[time] Search [timeCost: 1ms] [request: invalid]\n"
+
+	reader, err := NewReader(strings.NewReader(log), testFormat)
+	s.Require().NoError(err)
+	records := make([]*Record, 0)
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		s.Require().NoError(err)
+		records = append(records, record)
+	}
+	s.Require().Len(records, 3)
+	s.Equal(3, reader.Skipped())
+
+	s.Equal("Search", records[0].Method)
+	s.Equal(1, records[0].Line)
+	s.False(records[0].Time.IsZero())
+	s.Positive(records[0].Cost)
+	s.Equal("c1", records[0].Request.(*milvuspb.SearchRequest).GetCollectionName())
+	s.Equal("a > 1", records[0].Request.(*milvuspb.SearchRequest).GetDsl())
+	s.Equal("pk in [1, 2]", records[1].Request.(*milvuspb.QueryRequest).GetExpr())
+	s.Equal(4, records[2].Line)
+	s.Equal("c3", records[2].Request.(*milvuspb.HybridSearchRequest).GetCollectionName())
+
+	// the redacted vectors are replaced by random ones
+	placeholderGroup, err := proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{{
+			Tag:    "$0",
+			Type:   commonpb.PlaceholderType_FloatVector,
+			Values: [][]byte{{1, 2, 3, 4, 5, 6, 7, 8}},
+		}},
+	})
+	s.Require().NoError(err)
+	log = s.formatLines(testFormat, 0, []string{"Search"}, []interface{}{
+		&milvuspb.SearchRequest{CollectionName: "c1", PlaceholderGroup: placeholderGroup},
+	})
+	reader, err = NewReader(strings.NewReader(log), testFormat)
+	s.Require().NoError(err)
+	record, err := reader.Next()
+	s.Require().NoError(err)
+	replaced := &commonpb.PlaceholderGroup{}
+	s.Require().NoError(proto.Unmarshal(record.Request.(*milvuspb.SearchRequest).GetPlaceholderGroup(), replaced))
+	s.Require().Len(replaced.GetPlaceholders(), 1)
+	s.Equal(commonpb.PlaceholderType_FloatVector, replaced.GetPlaceholders()[0].GetType())
+	vector := replaced.GetPlaceholders()[0].GetValues()[0]
+	s.Require().Len(vector, 8)
+	s.NotEqual([]byte{1, 2, 3, 4, 5, 6, 7, 8}, vector)
+	s.NotEqual(make([]byte, 8), vector)
+
+	// the session timestamp is cleared before replay
+	s.Zero(prepareRequest(records[0].Request).(*milvuspb.SearchRequest).GetGuaranteeTimestamp())
+	s.EqualValues(1000, records[0].Request.(*milvuspb.SearchRequest).GetGuaranteeTimestamp())
+}
+
+func (s *ReplaySuite) TestDiffSearchResults() {
+	diff := diffSearchResults(newSearchResultData([]int64{2, 2}, 1, 2, 3, 4), newSearchResultData([]int64{2, 2}, 2, 1, 3, 5))
+	s.Empty(diff.Reason)
+	s.InDelta(0.75, diff.Recall, 1e-6)
+
+	diff = diffSearchResults(newSearchResultData([]int64{1}, 1), newSearchResultData([]int64{1, 1}, 1, 2))
+	s.NotEmpty(diff.Reason)
+
+	diff = diffSearchResults(nil, nil)
+	s.Empty(diff.Reason)
+	s.EqualValues(1, diff.Recall)
+}
+
+func (s *ReplaySuite) TestDiffQueryResults() {
+	diff := diffQueryResults(
+		[]*schemapb.FieldData{newLongField("pk", 1, 2, 3), newLongField("a", 10, 20, 30)},
+		[]*schemapb.FieldData{newLongField("a", 30, 10, 20), newLongField("pk", 3, 1, 2)},
+	)
+	s.Empty(diff.Reason)
+	s.EqualValues(1, diff.Recall)
+
+	diff = diffQueryResults([]*schemapb.FieldData{newLongField("pk", 1, 2)}, []*schemapb.FieldData{newLongField("pk", 1, 2, 3, 4)})
+	s.InDelta(0.5, diff.Recall, 1e-6)
+
+	diff = diffQueryResults([]*schemapb.FieldData{newLongField("pk", 1, 2), newLongField("a", 1)}, nil)
+	s.NotEmpty(diff.Reason)
+}
+
+func (s *ReplaySuite) TestReplay() {
+	reqs := []interface{}{
+		&milvuspb.SearchRequest{CollectionName: "c1"},
+		&milvuspb.QueryRequest{CollectionName: "c1"},
+		&milvuspb.SearchRequest{CollectionName: "c1"},
+	}
+	log := s.formatLines(testFormat, 100*time.Millisecond, []string{"Search", "Query", "Search"}, reqs)
+	newReader := func() *Reader {
+		reader, err := NewReader(strings.NewReader(log), testFormat)
+		s.Require().NoError(err)
+		return reader
+	}
+
+	s.Run("diff", func() {
+		target := &mockTarget{searchIDs: []int64{1, 2, 3, 4}, queryIDs: []int64{1, 2}}
+		baseline := &mockTarget{searchIDs: []int64{1, 2, 3, 5}, queryIDs: []int64{2, 1}}
+		report, err := NewReplayer(target, baseline, Config{Concurrency: 2, MinRecall: 0.9}).Replay(context.Background(), newReader())
+		s.Require().NoError(err)
+		s.Equal(3, report.Total)
+		s.Equal(3, report.Compared)
+		s.Equal(2, report.Mismatched)
+		s.Len(report.Mismatches, 2)
+		s.InDelta((0.75+1+0.75)/3, report.AvgRecall, 1e-6)
+		s.Equal(3, report.Latency.Count)
+		s.Equal(3, report.BaselineLatency.Count)
+		s.Equal(3, report.OriginalLatency.Count)
+		s.EqualValues(3, target.calls.Load())
+		s.EqualValues(3, baseline.calls.Load())
+	})
+
+	s.Run("failure", func() {
+		target := &mockTarget{err: errors.New("mock")}
+		report, err := NewReplayer(target, nil, Config{Timeout: time.Second}).Replay(context.Background(), newReader())
+		s.Require().NoError(err)
+		s.Equal(3, report.Total)
+		s.Equal(3, report.Failed)
+		s.Zero(report.Compared)
+		s.Len(report.Mismatches, 3)
+		s.Zero(report.Latency.Count)
+	})
+
+	s.Run("speed", func() {
+		report, err := NewReplayer(&mockTarget{}, nil, Config{Speed: 1}).Replay(context.Background(), newReader())
+		s.Require().NoError(err)
+		s.GreaterOrEqual(report.Elapsed, 150*time.Millisecond)
+
+		report, err = NewReplayer(&mockTarget{}, nil, Config{Speed: 100}).Replay(context.Background(), newReader())
+		s.Require().NoError(err)
+		s.Less(report.Elapsed, 150*time.Millisecond)
+	})
+
+	s.Run("canceled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := NewReplayer(&mockTarget{}, nil, Config{Speed: 1}).Replay(ctx, newReader())
+		s.ErrorIs(err, context.Canceled)
+	})
+}
+
+func TestReplay(t *testing.T) {
+	suite.Run(t, new(ReplaySuite))
+}
@@ -0,0 +1,331 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// the timestamps below are the consistency hints of the proxy instead of the session timestamps
+const maxHintTimestamp = 2
+
+// maxReportedMismatches limits the mismatches kept in the report.
+const maxReportedMismatches = 100
+
+// Target is the cluster the workload replayed against, implemented by milvuspb.MilvusServiceClient.
+type Target interface {
+	Search(ctx context.Context, req *milvuspb.SearchRequest, opts ...grpc.CallOption) (*milvuspb.SearchResults, error)
+	HybridSearch(ctx context.Context, req *milvuspb.HybridSearchRequest, opts ...grpc.CallOption) (*milvuspb.SearchResults, error)
+	Query(ctx context.Context, req *milvuspb.QueryRequest, opts ...grpc.CallOption) (*milvuspb.QueryResults, error)
+}
+
+// Config is the config of the Replayer.
+type Config struct {
+	// Speed scales the pace of the captured workload, e.g. 2 replays twice as fast,
+	// the records are replayed as fast as possible if not positive.
+	Speed float64
+	// Concurrency is the max number of the requests in flight.
+	Concurrency int
+	// Timeout is the timeout of each request, no timeout if not positive.
+	Timeout time.Duration
+	// MinRecall is the recall below which the results are reported as mismatched.
+	MinRecall float64
+}
+
+// Result is the replay result of a record.
+type Result struct {
+	Record          *Record
+	Latency         time.Duration
+	Err             error
+	BaselineLatency time.Duration
+	BaselineErr     error
+	// Diff is nil if the results are not compared.
+	Diff *Diff
+}
+
+// LatencyStats summarizes the latencies.
+type LatencyStats struct {
+	Count int
+	Avg   time.Duration
+	P50   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+// Report summarizes the replay.
+type Report struct {
+	Total          int
+	Skipped        int
+	Failed         int
+	BaselineFailed int
+	Compared       int
+	Mismatched     int
+	// AvgRecall is the average recall of the compared records.
+	AvgRecall float64
+	// Elapsed is the wall time of the replay.
+	Elapsed time.Duration
+
+	Latency         LatencyStats
+	BaselineLatency LatencyStats
+	// OriginalLatency is the latency when the records were captured.
+	OriginalLatency LatencyStats
+
+	// Mismatches are the first mismatched or failed results.
+	Mismatches []*Result
+}
+
+// Replayer replays the records against the target, and compares the results with the baseline if any.
+type Replayer struct {
+	cfg      Config
+	target   Target
+	baseline Target
+
+	mu                sync.Mutex
+	report            *Report
+	recallSum         float64
+	latencies         []time.Duration
+	baselineLatencies []time.Duration
+	originalLatencies []time.Duration
+}
+
+// NewReplayer returns a Replayer, the baseline could be nil to replay without diffing.
+func NewReplayer(target Target, baseline Target, cfg Config) *Replayer {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	return &Replayer{
+		cfg:      cfg,
+		target:   target,
+		baseline: baseline,
+		report:   &Report{},
+	}
+}
+
+// Replay replays all the records of the reader, it keeps the intervals between the records
+// divided by the speed, but a record waits if the concurrency is exhausted.
+func (r *Replayer) Replay(ctx context.Context, reader *Reader) (*Report, error) {
+	start := time.Now()
+	var first time.Time
+	sem := make(chan struct{}, r.cfg.Concurrency)
+	wg := sync.WaitGroup{}
+	defer wg.Wait()
+
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if r.cfg.Speed > 0 && !record.Time.IsZero() {
+			if first.IsZero() {
+				first = record.Time
+			}
+			offset := time.Duration(float64(record.Time.Sub(first)) / r.cfg.Speed)
+			if wait := time.Until(start.Add(offset)); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			r.collect(r.replay(ctx, record))
+		}()
+	}
+
+	wg.Wait()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report.Skipped = reader.Skipped()
+	r.report.Elapsed = time.Since(start)
+	if r.report.Compared > 0 {
+		r.report.AvgRecall = r.recallSum / float64(r.report.Compared)
+	}
+	r.report.Latency = newLatencyStats(r.latencies)
+	r.report.BaselineLatency = newLatencyStats(r.baselineLatencies)
+	r.report.OriginalLatency = newLatencyStats(r.originalLatencies)
+	return r.report, nil
+}
+
+func (r *Replayer) replay(ctx context.Context, record *Record) *Result {
+	result := &Result{Record: record}
+
+	var targetResp, baselineResp proto.Message
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		targetResp, result.Latency, result.Err = r.call(ctx, r.target, prepareRequest(record.Request))
+	}()
+	if r.baseline != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			baselineResp, result.BaselineLatency, result.BaselineErr = r.call(ctx, r.baseline, prepareRequest(record.Request))
+		}()
+	}
+	wg.Wait()
+
+	if r.baseline == nil || result.Err != nil || result.BaselineErr != nil {
+		return result
+	}
+	switch resp := targetResp.(type) {
+	case *milvuspb.SearchResults:
+		result.Diff = diffSearchResults(resp.GetResults(), baselineResp.(*milvuspb.SearchResults).GetResults())
+	case *milvuspb.QueryResults:
+		result.Diff = diffQueryResults(resp.GetFieldsData(), baselineResp.(*milvuspb.QueryResults).GetFieldsData())
+	}
+	return result
+}
+
+func (r *Replayer) call(ctx context.Context, target Target, request proto.Message) (proto.Message, time.Duration, error) {
+	if r.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.cfg.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	var resp proto.Message
+	var err error
+	switch req := request.(type) {
+	case *milvuspb.SearchRequest:
+		var results *milvuspb.SearchResults
+		results, err = target.Search(ctx, req)
+		err = merr.CheckRPCCall(results, err)
+		resp = results
+	case *milvuspb.HybridSearchRequest:
+		var results *milvuspb.SearchResults
+		results, err = target.HybridSearch(ctx, req)
+		err = merr.CheckRPCCall(results, err)
+		resp = results
+	case *milvuspb.QueryRequest:
+		var results *milvuspb.QueryResults
+		results, err = target.Query(ctx, req)
+		err = merr.CheckRPCCall(results, err)
+		resp = results
+	}
+	return resp, time.Since(start), err
+}
+
+func (r *Replayer) collect(result *Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.report.Total++
+	if result.Record.Cost > 0 {
+		r.originalLatencies = append(r.originalLatencies, result.Record.Cost)
+	}
+	if result.Err != nil {
+		r.report.Failed++
+	} else {
+		r.latencies = append(r.latencies, result.Latency)
+	}
+	if r.baseline != nil {
+		if result.BaselineErr != nil {
+			r.report.BaselineFailed++
+		} else {
+			r.baselineLatencies = append(r.baselineLatencies, result.BaselineLatency)
+		}
+	}
+
+	mismatched := result.Err != nil
+	if result.Diff != nil {
+		r.report.Compared++
+		r.recallSum += result.Diff.Recall
+		if result.Diff.Reason != "" || result.Diff.Recall < r.cfg.MinRecall {
+			r.report.Mismatched++
+			mismatched = true
+		}
+	}
+	if mismatched && len(r.report.Mismatches) < maxReportedMismatches {
+		r.report.Mismatches = append(r.report.Mismatches, result)
+	}
+}
+
+// prepareRequest clears the session timestamps of the captured cluster,
+// which mean nothing to the target, so the requests fall back to the strong consistency.
+func prepareRequest(request proto.Message) proto.Message {
+	request = proto.Clone(request)
+	switch req := request.(type) {
+	case *milvuspb.SearchRequest:
+		req.TravelTimestamp = 0
+		if req.GetGuaranteeTimestamp() > maxHintTimestamp {
+			req.GuaranteeTimestamp = 0
+		}
+	case *milvuspb.HybridSearchRequest:
+		req.TravelTimestamp = 0
+		if req.GetGuaranteeTimestamp() > maxHintTimestamp {
+			req.GuaranteeTimestamp = 0
+		}
+	case *milvuspb.QueryRequest:
+		req.TravelTimestamp = 0
+		if req.GetGuaranteeTimestamp() > maxHintTimestamp {
+			req.GuaranteeTimestamp = 0
+		}
+	}
+	return request
+}
+
+func newLatencyStats(latencies []time.Duration) LatencyStats {
+	if len(latencies) == 0 {
+		return LatencyStats{}
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+
+	var sum time.Duration
+	for _, latency := range latencies {
+		sum += latency
+	}
+	percentile := func(p float64) time.Duration {
+		return latencies[int(float64(len(latencies)-1)*p)]
+	}
+	return LatencyStats{
+		Count: len(latencies),
+		Avg:   sum / time.Duration(len(latencies)),
+		P50:   percentile(0.5),
+		P99:   percentile(0.99),
+		Max:   latencies[len(latencies)-1],
+	}
+}
@@ -0,0 +1,150 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionutil
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+)
+
+// CurrentProtocolVersion is the version of the protocol between components, it must be bumped
+// whenever a component starts to rely on a peer handling a new RPC or a new field of an RPC.
+// Sessions registered before the protocol version was introduced are of version 0.
+//
+// To support rolling upgrade, everything added since version 0 must be gated by a Feature,
+// so bumping the current version never drops the peers of the older versions.
+const CurrentProtocolVersion int32 = 6
+
+// MinCompatibleProtocolVersion is the oldest protocol version of peers a component works with,
+// it's raised only once the support of the versions older is removed, independent of CurrentProtocolVersion.
+const MinCompatibleProtocolVersion int32 = 0
+
+// IsProtocolCompatible returns whether a peer registered with @version could join the cluster.
+func IsProtocolCompatible(version int32) bool {
+	return version >= MinCompatibleProtocolVersion
+}
+
+// Feature is a capability of a component introduced by some protocol version.
+type Feature string
+
+const (
+	// FeatureLevelZeroCompaction is the support of Level0DeleteCompaction plans on datanode,
+	// which relies on the level, collectionID and partitionID of CompactionSegmentBinlogs.
+	FeatureLevelZeroCompaction Feature = "LevelZeroCompaction"
+	// FeatureClusteringCompaction is the support of ClusteringCompaction plans on datanode,
+	// which results in multiple segments.
+	FeatureClusteringCompaction Feature = "ClusteringCompaction"
+	// FeatureStatsRefresh is the support of StatsRefreshCompaction plans on datanode,
+	// which rewrite the statslogs of the segments in place.
+	FeatureStatsRefresh Feature = "StatsRefresh"
+	// FeatureCompactionStealing is the support of the compaction plans of the channels not watched by datanode,
+	// which carry the channel info to compact the segments without the flowgraph of the channel.
+	FeatureCompactionStealing Feature = "CompactionStealing"
+	// FeatureCompressedBinlog is the support of reading the binlogs compressed as a whole, see common.CollectionBinlogCompressionKey.
+	FeatureCompressedBinlog Feature = "CompressedBinlog"
+	// FeatureParquetBinlog is the support of reading the insert binlogs written as parquet files, see common.CollectionBinlogFormatKey.
+	FeatureParquetBinlog Feature = "ParquetBinlog"
+	// FeatureSortedDeltalog is the support of reading the deltalogs sorted by the primary keys.
+	FeatureSortedDeltalog Feature = "SortedDeltalog"
+	// FeatureDeltalogBitmap is the support of reading the deltalogs recording the deleted row offsets.
+	FeatureDeltalogBitmap Feature = "DeltalogBitmap"
+	// FeatureCompactionPreemption is the support of DropCompactionPlan on datanode,
+	// which stops the compaction plan preempted under flush pressure.
+	FeatureCompactionPreemption Feature = "CompactionPreemption"
+)
+
+// featureProtocolVersions records the protocol version which introduced each feature.
+var featureProtocolVersions = map[Feature]int32{
+	FeatureLevelZeroCompaction:  1,
+	FeatureClusteringCompaction: 2,
+	FeatureStatsRefresh:         3,
+	FeatureCompactionStealing:   4,
+	FeatureCompressedBinlog:     5,
+	FeatureParquetBinlog:        5,
+	FeatureSortedDeltalog:       5,
+	FeatureDeltalogBitmap:       5,
+	FeatureCompactionPreemption: 6,
+}
+
+// SupportFeature returns whether a peer at protocol @version supports @feature,
+// unknown features are never supported.
+func SupportFeature(version int32, feature Feature) bool {
+	required, ok := featureProtocolVersions[feature]
+	return ok && version >= required
+}
+
+// SupportFeature returns whether the component of the session supports @feature.
+func (s *SessionRaw) SupportFeature(feature Feature) bool {
+	return SupportFeature(s.ProtocolVersion, feature)
+}
+
+// FeatureGate tells whether all the running components of some roles support a feature,
+// it's used to hold back the writers of a new encoding until every reader of the cluster is upgraded.
+// The sessions are listed at most once per interval, the features are reported unsupported if the listing fails.
+type FeatureGate struct {
+	session  SessionInterface
+	roles    []string
+	interval time.Duration
+
+	mu        sync.Mutex
+	version   int32
+	checkTime time.Time
+}
+
+// NewFeatureGate creates a FeatureGate of the components of @roles.
+func NewFeatureGate(session SessionInterface, interval time.Duration, roles ...string) *FeatureGate {
+	return &FeatureGate{
+		session:  session,
+		roles:    roles,
+		interval: interval,
+	}
+}
+
+// Support returns whether all the running components of the roles support @feature.
+func (g *FeatureGate) Support(feature Feature) bool {
+	return SupportFeature(g.minProtocolVersion(), feature)
+}
+
+func (g *FeatureGate) minProtocolVersion() int32 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.checkTime.IsZero() && time.Since(g.checkTime) < g.interval {
+		return g.version
+	}
+	g.checkTime = time.Now()
+
+	version := CurrentProtocolVersion
+	for _, role := range g.roles {
+		sessions, _, err := g.session.GetSessions(role)
+		if err != nil {
+			log.Warn("failed to list sessions for feature gate", zap.String("role", role), zap.Error(err))
+			version = MinCompatibleProtocolVersion
+			break
+		}
+		for _, session := range sessions {
+			if session.ProtocolVersion < version {
+				version = session.ProtocolVersion
+			}
+		}
+	}
+	g.version = version
+	return version
+}
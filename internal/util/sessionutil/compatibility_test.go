@@ -0,0 +1,93 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionutil
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIsProtocolCompatible(t *testing.T) {
+	assert.True(t, IsProtocolCompatible(CurrentProtocolVersion))
+	assert.True(t, IsProtocolCompatible(CurrentProtocolVersion+1))
+	assert.True(t, IsProtocolCompatible(CurrentProtocolVersion-1))
+	// the sessions registered before the protocol version was introduced
+	assert.True(t, IsProtocolCompatible(0))
+	assert.False(t, IsProtocolCompatible(MinCompatibleProtocolVersion-1))
+}
+
+func TestSupportFeature(t *testing.T) {
+	for feature, version := range featureProtocolVersions {
+		assert.True(t, SupportFeature(version, feature))
+		assert.True(t, SupportFeature(CurrentProtocolVersion, feature))
+		assert.False(t, SupportFeature(version-1, feature))
+	}
+	assert.False(t, SupportFeature(CurrentProtocolVersion, Feature("unknown")))
+
+	// sessions registered before the protocol version was introduced are of version 0
+	session := &Session{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"ServerID": 1, "Version": "2.3.0"}`), session))
+	assert.EqualValues(t, 0, session.ProtocolVersion)
+	assert.False(t, session.SupportFeature(FeatureLevelZeroCompaction))
+
+	session = &Session{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"ServerID": 1, "Version": "2.4.0", "ProtocolVersion": 1}`), session))
+	assert.True(t, session.SupportFeature(FeatureLevelZeroCompaction))
+	assert.False(t, session.SupportFeature(FeatureClusteringCompaction))
+
+	session = &Session{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"ServerID": 1, "Version": "2.4.0", "ProtocolVersion": 2}`), session))
+	assert.True(t, session.SupportFeature(FeatureClusteringCompaction))
+	assert.False(t, session.SupportFeature(FeatureStatsRefresh))
+
+	session = &Session{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"ServerID": 1, "Version": "2.4.0", "ProtocolVersion": 3}`), session))
+	assert.True(t, session.SupportFeature(FeatureStatsRefresh))
+	assert.False(t, session.SupportFeature(FeatureCompactionStealing))
+}
+
+func TestFeatureGate(t *testing.T) {
+	session := NewMockSession(t)
+	session.EXPECT().GetSessions("querynode").Return(map[string]*Session{
+		"querynode-1": {SessionRaw: SessionRaw{ServerID: 1, ProtocolVersion: CurrentProtocolVersion}},
+		"querynode-2": {SessionRaw: SessionRaw{ServerID: 2, ProtocolVersion: 4}},
+	}, 0, nil).Once()
+	session.EXPECT().GetSessions("datanode").Return(map[string]*Session{
+		"datanode-3": {SessionRaw: SessionRaw{ServerID: 3, ProtocolVersion: CurrentProtocolVersion}},
+	}, 0, nil).Once()
+
+	gate := NewFeatureGate(session, time.Hour, "querynode", "datanode")
+	assert.True(t, gate.Support(FeatureCompactionStealing))
+	assert.False(t, gate.Support(FeatureSortedDeltalog))
+
+	// the sessions are listed again after the interval
+	gate.checkTime = time.Now().Add(-2 * time.Hour)
+	session.EXPECT().GetSessions(mock.Anything).Return(map[string]*Session{
+		"node-4": {SessionRaw: SessionRaw{ServerID: 4, ProtocolVersion: CurrentProtocolVersion}},
+	}, 0, nil).Twice()
+	assert.True(t, gate.Support(FeatureSortedDeltalog))
+
+	// nothing is supported if the sessions can't be listed
+	gate.checkTime = time.Now().Add(-2 * time.Hour)
+	session.EXPECT().GetSessions(mock.Anything).Return(nil, 0, errors.New("mock")).Once()
+	assert.False(t, gate.Support(FeatureLevelZeroCompaction))
+}
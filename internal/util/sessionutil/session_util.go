@@ -49,6 +49,9 @@ const (
 	DefaultServiceRoot = "session/"
 	// DefaultIDKey default id key for Session
 	DefaultIDKey = "id"
+	// ServerLabelEnvPrefix is the prefix of the envs registered as the labels of the server,
+	// MILVUS_SERVER_LABEL_RACK=r1 is registered as label rack=r1.
+	ServerLabelEnvPrefix = "MILVUS_SERVER_LABEL_"
 )
 
 // SessionEventType session event type
@@ -97,11 +100,15 @@ type SessionRaw struct {
 	Stopping           bool   `json:"Stopping,omitempty"`
 	TriggerKill        bool
 	Version            string             `json:"Version"`
+	ProtocolVersion    int32              `json:"ProtocolVersion,omitempty"`
 	IndexEngineVersion IndexEngineVersion `json:"IndexEngineVersion,omitempty"`
 	LeaseID            *clientv3.LeaseID  `json:"LeaseID,omitempty"`
 
 	HostName   string `json:"HostName,omitempty"`
 	EnableDisk bool   `json:"EnableDisk,omitempty"`
+	// ServerLabels are the labels of the server from the envs of ServerLabelEnvPrefix,
+	// by which the placement of the data on the server is customized.
+	ServerLabels map[string]string `json:"ServerLabels,omitempty"`
 }
 
 func (s *SessionRaw) GetAddress() string {
@@ -232,7 +239,9 @@ func NewSessionWithEtcd(ctx context.Context, metaRoot string, client *clientv3.C
 		Version:  common.Version,
 
 		SessionRaw: SessionRaw{
-			HostName: hostName,
+			HostName:        hostName,
+			ProtocolVersion: CurrentProtocolVersion,
+			ServerLabels:    GetServerLabelsFromEnv(),
 		},
 
 		// options
@@ -270,6 +279,24 @@ func NewSessionWithEtcd(ctx context.Context, metaRoot string, client *clientv3.C
 	return session
 }
 
+// GetServerLabelsFromEnv returns the server labels from the envs of ServerLabelEnvPrefix,
+// the keys of the labels are in lower case.
+func GetServerLabelsFromEnv() map[string]string {
+	labels := make(map[string]string)
+	for _, env := range os.Environ() {
+		kv := strings.SplitN(env, "=", 2)
+		if len(kv) != 2 || !strings.HasPrefix(kv[0], ServerLabelEnvPrefix) {
+			continue
+		}
+		key := strings.ToLower(strings.TrimPrefix(kv[0], ServerLabelEnvPrefix))
+		if key == "" {
+			continue
+		}
+		labels[key] = kv[1]
+	}
+	return labels
+}
+
 // Init will initialize base struct of the Session, including ServerName, ServerID,
 // Address, Exclusive. ServerID is obtained in getServerID.
 func (s *Session) Init(serverName, address string, exclusive bool, triggerKill bool) {
@@ -623,7 +650,8 @@ func (s *Session) GetSessions(prefix string) (map[string]*Session, int64, error)
 	return res, resp.Header.Revision, nil
 }
 
-// GetSessionsWithVersionRange will get all sessions with provided prefix and version range in etcd.
+// GetSessionsWithVersionRange will get all sessions with provided prefix and version range in etcd,
+// sessions with an incompatible protocol version are skipped as well.
 // Revision is returned for WatchServices to prevent missing events.
 func (s *Session) GetSessionsWithVersionRange(prefix string, r semver.Range) (map[string]*Session, int64, error) {
 	res := make(map[string]*Session)
@@ -643,6 +671,11 @@ func (s *Session) GetSessionsWithVersionRange(prefix string, r semver.Range) (ma
 			log.Debug("Session version out of range", zap.String("version", session.Version.String()), zap.Int64("serverID", session.ServerID))
 			continue
 		}
+		if !IsProtocolCompatible(session.ProtocolVersion) {
+			log.Warn("Session protocol version is incompatible", zap.Int32("protocolVersion", session.ProtocolVersion),
+				zap.Int32("minCompatibleVersion", MinCompatibleProtocolVersion), zap.Int64("serverID", session.ServerID))
+			continue
+		}
 		_, mapKey := path.Split(string(kv.Key))
 		log.Debug("SessionUtil GetSessions ", zap.String("prefix", prefix),
 			zap.String("key", mapKey),
@@ -741,7 +774,7 @@ func (s *Session) WatchServices(prefix string, revision int64, rewatch Rewatch)
 }
 
 // WatchServicesWithVersionRange watches the service's up and down in etcd, and sends event to event Channel.
-// Acts like WatchServices but with extra version range check.
+// Acts like WatchServices but with extra version range and protocol version check.
 // prefix is a parameter to know which service to watch and can be obtained in type util.type.go.
 // revision is a etcd reversion to prevent missing key events and can be obtained in GetSessions.
 // If a server up, an event will be add to channel with eventType SessionAddType.
@@ -753,7 +786,7 @@ func (s *Session) WatchServicesWithVersionRange(prefix string, r semver.Range, r
 		rch:      s.etcdCli.Watch(s.ctx, path.Join(s.metaRoot, DefaultServiceRoot, prefix), clientv3.WithPrefix(), clientv3.WithPrevKV(), clientv3.WithRev(revision)),
 		prefix:   prefix,
 		rewatch:  rewatch,
-		validate: func(s *Session) bool { return r(s.Version) },
+		validate: func(s *Session) bool { return r(s.Version) && IsProtocolCompatible(s.ProtocolVersion) },
 	}
 	w.start()
 	return w.eventCh
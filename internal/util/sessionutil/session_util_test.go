@@ -633,6 +633,24 @@ func (suite *SessionWithVersionSuite) TestGetSessionsWithRangeVersion() {
 	})
 }
 
+func (suite *SessionWithVersionSuite) TestGetSessionsWithIncompatibleProtocol() {
+	s := NewSessionWithEtcd(context.Background(), suite.metaRoot, suite.client, WithResueNodeID(false))
+	suite.EqualValues(CurrentProtocolVersion, s.ProtocolVersion)
+
+	incompatible := fmt.Sprintf(`{"ServerID": 100, "Version": "2.2.0", "ProtocolVersion": %d}`, MinCompatibleProtocolVersion-1)
+	_, err := suite.client.Put(context.Background(), path.Join(suite.metaRoot, DefaultServiceRoot, suite.serverName, "incompatible"), incompatible)
+	suite.Require().NoError(err)
+
+	r, err := semver.ParseRange(">=0.0.0")
+	suite.Require().NoError(err)
+	result, _, err := s.GetSessionsWithVersionRange(suite.serverName, r)
+	suite.Require().NoError(err)
+	suite.Equal(3, len(result))
+	for _, session := range result {
+		suite.NotEqualValues(100, session.ServerID)
+	}
+}
+
 func (suite *SessionWithVersionSuite) TestWatchServicesWithVersionRange() {
 	s := NewSessionWithEtcd(context.Background(), suite.metaRoot, suite.client, WithResueNodeID(false))
 
@@ -810,6 +828,20 @@ func TestSession_apply(t *testing.T) {
 	assert.Equal(t, int64(200), session.sessionRetryTimes)
 }
 
+func TestGetServerLabelsFromEnv(t *testing.T) {
+	t.Setenv(ServerLabelEnvPrefix+"RACK", "r1")
+	t.Setenv(ServerLabelEnvPrefix+"zone", "z1")
+	t.Setenv(ServerLabelEnvPrefix, "empty")
+	t.Setenv("MILVUS_SERVER_RACK", "r2")
+
+	labels := GetServerLabelsFromEnv()
+	assert.Equal(t, map[string]string{"rack": "r1", "zone": "z1"}, labels)
+
+	session := &Session{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"ServerID": 1, "ServerLabels": {"rack": "r1"}}`), session))
+	assert.Equal(t, "r1", session.ServerLabels["rack"])
+}
+
 func TestIntegrationMode(t *testing.T) {
 	ctx := context.Background()
 	paramtable.Init()
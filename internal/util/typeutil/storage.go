@@ -2,15 +2,14 @@ package typeutil
 
 import (
 	"fmt"
-	"math"
 	"path"
 
+	"github.com/apache/arrow/go/v12/arrow"
 	"github.com/apache/arrow/go/v12/arrow/array"
 	"github.com/golang/protobuf/proto"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/storage"
-	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
@@ -90,15 +89,11 @@ func BuildRecord(b *array.RecordBuilder, data *storage.InsertData, fields []*sch
 			byteLength := dim * 4
 			length := len(data) / dim
 
+			// the float32 values are little endian in memory as common.Endian, so each row is copied as is
+			bytesData := arrow.Float32Traits.CastToBytes(data)
 			builder.Reserve(length)
-			bytesData := make([]byte, byteLength)
 			for i := 0; i < length; i++ {
-				vec := data[i*dim : (i+1)*dim]
-				for j := range vec {
-					bytes := math.Float32bits(vec[j])
-					common.Endian.PutUint32(bytesData[j*4:], bytes)
-				}
-				builder.Append(bytesData)
+				builder.Append(bytesData[i*byteLength : (i+1)*byteLength])
 			}
 		case schemapb.DataType_Float16Vector:
 			vecData := data.Data[field.FieldID].(*storage.Float16VectorFieldData)
@@ -18,6 +18,10 @@ package common
 
 import (
 	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
@@ -82,8 +86,20 @@ const (
 	// SegmentStatslogPath storage path const for segment stats log.
 	SegmentStatslogPath = `stats_log`
 
+	// SegmentQuantizedLogPath storage path const for the quantized copies of segment insert binlogs.
+	SegmentQuantizedLogPath = `quantized_log`
+
 	// SegmentIndexPath storage path const for segment index files.
 	SegmentIndexPath = `index_files`
+
+	// UploadManifestPath storage path const for the write-ahead manifests of the binlog uploads.
+	UploadManifestPath = `upload_manifest`
+
+	// SegmentInventoryPath storage path const for the inventories of the segment binlogs.
+	SegmentInventoryPath = `segment_inventory`
+
+	// BackupPath storage path const for backup manifests.
+	BackupPath = `backup`
 )
 
 // Search, Index parameter keys
@@ -109,6 +125,10 @@ const (
 	CollectionTTLConfigKey      = "collection.ttl.seconds"
 	CollectionAutoCompactionKey = "collection.autocompaction.enabled"
 
+	// PartitionTTLConfigKeyPrefix is the prefix of the collection properties of the partition ttl,
+	// e.g. partition.ttl.seconds.<partitionID>, which overrides the collection ttl for the partition
+	PartitionTTLConfigKeyPrefix = "partition.ttl.seconds."
+
 	// rate limit
 	CollectionInsertRateMaxKey   = "collection.insertRate.max.mb"
 	CollectionInsertRateMinKey   = "collection.insertRate.min.mb"
@@ -123,6 +143,47 @@ const (
 	CollectionSearchRateMaxKey   = "collection.searchRate.max.vps"
 	CollectionSearchRateMinKey   = "collection.searchRate.min.vps"
 	CollectionDiskQuotaKey       = "collection.diskProtection.diskQuota.mb"
+
+	// CollectionBinlogFormatKey selects the format of the insert binlogs, it takes effect only when the collection created
+	CollectionBinlogFormatKey = "collection.binlog.format"
+	// CollectionBinlogCompressionKey selects the codec compressing the insert and delta binlogs before uploaded,
+	// it takes effect only when the collection created
+	CollectionBinlogCompressionKey = "collection.binlog.compression"
+	// CollectionStorageTenantKey adds the tenant component to the object keys of the binlogs,
+	// it takes effect only when the collection created
+	CollectionStorageTenantKey = "collection.storage.tenant"
+	// CollectionExternalPathKey makes the collection a read-only view of the parquet files
+	// under the path of the object storage, it takes effect only when the collection created
+	CollectionExternalPathKey = "collection.external.path"
+	// CollectionTimeBucketFieldKey buckets the segments by the value of an Int64 field holding
+	// the unix epoch milliseconds of the rows, it takes effect only when the collection created
+	CollectionTimeBucketFieldKey = "collection.timebucket.field"
+	// CollectionTimeBucketSecondsKey is the width of the time buckets in seconds
+	CollectionTimeBucketSecondsKey = "collection.timebucket.seconds"
+	// CollectionPKBucketNumKey routes the rows to the segments of the buckets by the hash of
+	// the primary key, it takes effect only when the collection created
+	CollectionPKBucketNumKey = "collection.pkbucket.num"
+	// CollectionClusteringFieldKey re-partitions the rows across the segments by the value of the scalar field
+	// when compacted, so the segments out of the range of a filter on the field are pruned,
+	// it takes effect only when the collection created
+	CollectionClusteringFieldKey = "collection.clustering.field"
+)
+
+// binlog formats
+const (
+	// BinlogFormatNative wraps the parquet payload by the milvus binlog events, the default format.
+	BinlogFormatNative = "native"
+	// BinlogFormatParquet writes the plain parquet files, which could be read by the external engines directly.
+	BinlogFormatParquet = "parquet"
+)
+
+// binlog compression codecs, the binlogs are self-describing so the binlogs of any codec could be read
+const (
+	// BinlogCompressionNone uploads the binlogs as is, the default.
+	BinlogCompressionNone   = "none"
+	BinlogCompressionZstd   = "zstd"
+	BinlogCompressionLz4    = "lz4"
+	BinlogCompressionSnappy = "snappy"
 )
 
 // common properties
@@ -130,6 +191,33 @@ const (
 	MmapEnabledKey = "mmap.enabled"
 )
 
+// field storage options, set in the type params of the fields when the collection created
+const (
+	// FieldCompressionKey is the compression codec of the field in binlogs, FieldCompressionZstd if not set.
+	FieldCompressionKey = "storage.compression"
+	// FieldEncodingKey is the encoding of the field in binlogs, chosen by the values of the field if not set.
+	FieldEncodingKey = "storage.encoding"
+	// FieldNullableKey makes the values of the scalar field nullable, the validity of the rows is kept in binlogs.
+	FieldNullableKey = "nullable"
+)
+
+// field compression codecs
+const (
+	FieldCompressionZstd   = "zstd"
+	FieldCompressionSnappy = "snappy"
+	FieldCompressionNone   = "none"
+)
+
+// field encodings
+const (
+	// FieldEncodingDictionary encodes the values by the dictionary, it falls back to plain if the dictionary is too large.
+	FieldEncodingDictionary = "dictionary"
+	// FieldEncodingPlain writes the values as is, for the high cardinality fields, e.g. vectors.
+	FieldEncodingPlain = "plain"
+	// FieldEncodingDelta encodes the deltas between the adjacent values, for the near-monotonic integer fields.
+	FieldEncodingDelta = "delta"
+)
+
 const (
 	PropertiesKey string = "properties"
 	TraceIDKey    string = "uber-trace-id"
@@ -157,6 +245,201 @@ func IsFieldMmapEnabled(schema *schemapb.CollectionSchema, fieldID int64) bool {
 	return false
 }
 
+// GetBinlogFormat returns the binlog format of the collection properties, it returns the native format if not set.
+func GetBinlogFormat(kvs ...*commonpb.KeyValuePair) (string, error) {
+	for _, kv := range kvs {
+		if kv.GetKey() != CollectionBinlogFormatKey {
+			continue
+		}
+		switch kv.GetValue() {
+		case BinlogFormatNative, BinlogFormatParquet:
+			return kv.GetValue(), nil
+		default:
+			return "", fmt.Errorf("invalid %s: %s, only %s and %s are supported",
+				CollectionBinlogFormatKey, kv.GetValue(), BinlogFormatNative, BinlogFormatParquet)
+		}
+	}
+	return BinlogFormatNative, nil
+}
+
+// GetBinlogCompression returns the binlog compression codec of the collection properties, it returns none if not set.
+func GetBinlogCompression(kvs ...*commonpb.KeyValuePair) (string, error) {
+	for _, kv := range kvs {
+		if kv.GetKey() != CollectionBinlogCompressionKey {
+			continue
+		}
+		switch kv.GetValue() {
+		case BinlogCompressionNone, BinlogCompressionZstd, BinlogCompressionLz4, BinlogCompressionSnappy:
+			return kv.GetValue(), nil
+		default:
+			return "", fmt.Errorf("invalid %s: %s, only %s, %s, %s and %s are supported", CollectionBinlogCompressionKey,
+				kv.GetValue(), BinlogCompressionNone, BinlogCompressionZstd, BinlogCompressionLz4, BinlogCompressionSnappy)
+		}
+	}
+	return BinlogCompressionNone, nil
+}
+
+// GetFieldCompression returns the compression codec of the field type params, it returns zstd if not set.
+func GetFieldCompression(kvs ...*commonpb.KeyValuePair) (string, error) {
+	for _, kv := range kvs {
+		if kv.GetKey() != FieldCompressionKey {
+			continue
+		}
+		switch kv.GetValue() {
+		case FieldCompressionZstd, FieldCompressionSnappy, FieldCompressionNone:
+			return kv.GetValue(), nil
+		default:
+			return "", fmt.Errorf("invalid %s: %s, only %s, %s and %s are supported", FieldCompressionKey,
+				kv.GetValue(), FieldCompressionZstd, FieldCompressionSnappy, FieldCompressionNone)
+		}
+	}
+	return FieldCompressionZstd, nil
+}
+
+// GetFieldEncoding returns the encoding of the field type params, it returns empty string if not set.
+func GetFieldEncoding(kvs ...*commonpb.KeyValuePair) (string, error) {
+	for _, kv := range kvs {
+		if kv.GetKey() != FieldEncodingKey {
+			continue
+		}
+		switch kv.GetValue() {
+		case FieldEncodingDictionary, FieldEncodingPlain, FieldEncodingDelta:
+			return kv.GetValue(), nil
+		default:
+			return "", fmt.Errorf("invalid %s: %s, only %s, %s and %s are supported", FieldEncodingKey,
+				kv.GetValue(), FieldEncodingDictionary, FieldEncodingPlain, FieldEncodingDelta)
+		}
+	}
+	return "", nil
+}
+
+// IsDeltaEncodingSupported returns whether the fields of the data type could be delta encoded.
+func IsDeltaEncodingSupported(dataType schemapb.DataType) bool {
+	return dataType == schemapb.DataType_Int32 || dataType == schemapb.DataType_Int64
+}
+
+// IsFieldNullable returns whether the field of the type params is nullable, it returns false if not set.
+func IsFieldNullable(kvs ...*commonpb.KeyValuePair) (bool, error) {
+	for _, kv := range kvs {
+		if kv.GetKey() != FieldNullableKey {
+			continue
+		}
+		nullable, err := strconv.ParseBool(kv.GetValue())
+		if err != nil {
+			return false, fmt.Errorf("invalid %s: %s, only true and false are supported", FieldNullableKey, kv.GetValue())
+		}
+		return nullable, nil
+	}
+	return false, nil
+}
+
+// IsNullableSupported returns whether the fields of the data type could be nullable, the vectors couldn't.
+func IsNullableSupported(dataType schemapb.DataType) bool {
+	switch dataType {
+	case schemapb.DataType_Bool, schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32,
+		schemapb.DataType_Int64, schemapb.DataType_Float, schemapb.DataType_Double, schemapb.DataType_String,
+		schemapb.DataType_VarChar, schemapb.DataType_Array, schemapb.DataType_JSON:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetStorageTenant returns the storage tenant of the collection properties, it returns empty string if not set.
+// The tenant is a component of the object keys, so only letters, digits, '_' and '-' are allowed.
+func GetStorageTenant(kvs ...*commonpb.KeyValuePair) (string, error) {
+	for _, kv := range kvs {
+		if kv.GetKey() != CollectionStorageTenantKey {
+			continue
+		}
+		tenant := kv.GetValue()
+		if len(tenant) == 0 || len(tenant) > 64 {
+			return "", fmt.Errorf("invalid %s: %s, the length should be in [1, 64]", CollectionStorageTenantKey, tenant)
+		}
+		for _, c := range tenant {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+				return "", fmt.Errorf("invalid %s: %s, only letters, digits, '_' and '-' are allowed", CollectionStorageTenantKey, tenant)
+			}
+		}
+		return tenant, nil
+	}
+	return "", nil
+}
+
+// GetExternalPath returns the path of the parquet files of an external collection,
+// empty if the collection is not an external one.
+func GetExternalPath(kvs ...*commonpb.KeyValuePair) (string, error) {
+	for _, kv := range kvs {
+		if kv.GetKey() != CollectionExternalPathKey {
+			continue
+		}
+		path := strings.Trim(kv.GetValue(), "/")
+		if len(path) == 0 {
+			return "", fmt.Errorf("invalid %s: %s, the path should not be empty", CollectionExternalPathKey, kv.GetValue())
+		}
+		for _, component := range strings.Split(path, "/") {
+			if component == "" || component == "." || component == ".." {
+				return "", fmt.Errorf("invalid %s: %s, empty, '.' and '..' components are not allowed", CollectionExternalPathKey, kv.GetValue())
+			}
+		}
+		return path, nil
+	}
+	return "", nil
+}
+
+// GetTimeBucket returns the field and the width in milliseconds of the time buckets of the collection,
+// the field is empty if the segments are not bucketed.
+func GetTimeBucket(kvs ...*commonpb.KeyValuePair) (string, int64, error) {
+	var field, seconds string
+	for _, kv := range kvs {
+		switch kv.GetKey() {
+		case CollectionTimeBucketFieldKey:
+			field = kv.GetValue()
+		case CollectionTimeBucketSecondsKey:
+			seconds = kv.GetValue()
+		}
+	}
+	if field == "" && seconds == "" {
+		return "", 0, nil
+	}
+	if field == "" || seconds == "" {
+		return "", 0, fmt.Errorf("%s and %s should be set together", CollectionTimeBucketFieldKey, CollectionTimeBucketSecondsKey)
+	}
+	width, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil || width <= 0 || width > math.MaxInt64/1000 {
+		return "", 0, fmt.Errorf("invalid %s: %s, should be a positive integer", CollectionTimeBucketSecondsKey, seconds)
+	}
+	return field, width * 1000, nil
+}
+
+// MaxPKBucketNum is the max number of primary key buckets of a collection.
+const MaxPKBucketNum = 1024
+
+// GetPKBucketNum returns the number of the primary key buckets of the collection, 0 if not bucketed.
+func GetPKBucketNum(kvs ...*commonpb.KeyValuePair) (int64, error) {
+	for _, kv := range kvs {
+		if kv.GetKey() != CollectionPKBucketNumKey {
+			continue
+		}
+		num, err := strconv.ParseInt(kv.GetValue(), 10, 64)
+		if err != nil || num <= 0 || num > MaxPKBucketNum {
+			return 0, fmt.Errorf("invalid %s: %s, should be an integer in [1, %d]", CollectionPKBucketNumKey, kv.GetValue(), MaxPKBucketNum)
+		}
+		return num, nil
+	}
+	return 0, nil
+}
+
+// GetClusteringField returns the name of the clustering field of the collection, empty if not clustered.
+func GetClusteringField(kvs ...*commonpb.KeyValuePair) string {
+	for _, kv := range kvs {
+		if kv.GetKey() == CollectionClusteringFieldKey {
+			return kv.GetValue()
+		}
+	}
+	return ""
+}
+
 const (
 	// LatestVerision is the magic number for watch latest revision
 	LatestRevision = int64(-1)
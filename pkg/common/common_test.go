@@ -1,9 +1,13 @@
 package common
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 )
 
 func TestIsSystemField(t *testing.T) {
@@ -38,3 +42,148 @@ func TestIsSystemField(t *testing.T) {
 		})
 	}
 }
+
+func TestGetBinlogFormat(t *testing.T) {
+	format, err := GetBinlogFormat()
+	assert.NoError(t, err)
+	assert.Equal(t, BinlogFormatNative, format)
+
+	format, err = GetBinlogFormat(&commonpb.KeyValuePair{Key: CollectionTTLConfigKey, Value: "10"},
+		&commonpb.KeyValuePair{Key: CollectionBinlogFormatKey, Value: BinlogFormatParquet})
+	assert.NoError(t, err)
+	assert.Equal(t, BinlogFormatParquet, format)
+
+	_, err = GetBinlogFormat(&commonpb.KeyValuePair{Key: CollectionBinlogFormatKey, Value: "orc"})
+	assert.Error(t, err)
+}
+
+func TestGetBinlogCompression(t *testing.T) {
+	compression, err := GetBinlogCompression()
+	assert.NoError(t, err)
+	assert.Equal(t, BinlogCompressionNone, compression)
+
+	compression, err = GetBinlogCompression(&commonpb.KeyValuePair{Key: CollectionTTLConfigKey, Value: "10"},
+		&commonpb.KeyValuePair{Key: CollectionBinlogCompressionKey, Value: BinlogCompressionLz4})
+	assert.NoError(t, err)
+	assert.Equal(t, BinlogCompressionLz4, compression)
+
+	_, err = GetBinlogCompression(&commonpb.KeyValuePair{Key: CollectionBinlogCompressionKey, Value: "gzip"})
+	assert.Error(t, err)
+}
+
+func TestGetFieldStorageOptions(t *testing.T) {
+	compression, err := GetFieldCompression()
+	assert.NoError(t, err)
+	assert.Equal(t, FieldCompressionZstd, compression)
+	compression, err = GetFieldCompression(&commonpb.KeyValuePair{Key: MmapEnabledKey, Value: "true"},
+		&commonpb.KeyValuePair{Key: FieldCompressionKey, Value: FieldCompressionSnappy})
+	assert.NoError(t, err)
+	assert.Equal(t, FieldCompressionSnappy, compression)
+	_, err = GetFieldCompression(&commonpb.KeyValuePair{Key: FieldCompressionKey, Value: "lzo"})
+	assert.Error(t, err)
+
+	encoding, err := GetFieldEncoding()
+	assert.NoError(t, err)
+	assert.Empty(t, encoding)
+	encoding, err = GetFieldEncoding(&commonpb.KeyValuePair{Key: FieldEncodingKey, Value: FieldEncodingDelta})
+	assert.NoError(t, err)
+	assert.Equal(t, FieldEncodingDelta, encoding)
+	_, err = GetFieldEncoding(&commonpb.KeyValuePair{Key: FieldEncodingKey, Value: "rle"})
+	assert.Error(t, err)
+
+	assert.True(t, IsDeltaEncodingSupported(schemapb.DataType_Int64))
+	assert.False(t, IsDeltaEncodingSupported(schemapb.DataType_Float))
+
+	nullable, err := IsFieldNullable()
+	assert.NoError(t, err)
+	assert.False(t, nullable)
+	nullable, err = IsFieldNullable(&commonpb.KeyValuePair{Key: FieldNullableKey, Value: "true"})
+	assert.NoError(t, err)
+	assert.True(t, nullable)
+	_, err = IsFieldNullable(&commonpb.KeyValuePair{Key: FieldNullableKey, Value: "maybe"})
+	assert.Error(t, err)
+
+	assert.True(t, IsNullableSupported(schemapb.DataType_JSON))
+	assert.False(t, IsNullableSupported(schemapb.DataType_FloatVector))
+}
+
+func TestGetStorageTenant(t *testing.T) {
+	tenant, err := GetStorageTenant()
+	assert.NoError(t, err)
+	assert.Empty(t, tenant)
+
+	tenant, err = GetStorageTenant(&commonpb.KeyValuePair{Key: CollectionTTLConfigKey, Value: "10"},
+		&commonpb.KeyValuePair{Key: CollectionStorageTenantKey, Value: "tenant_1-a"})
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant_1-a", tenant)
+
+	_, err = GetStorageTenant(&commonpb.KeyValuePair{Key: CollectionStorageTenantKey, Value: ""})
+	assert.Error(t, err)
+	_, err = GetStorageTenant(&commonpb.KeyValuePair{Key: CollectionStorageTenantKey, Value: "a/b"})
+	assert.Error(t, err)
+	_, err = GetStorageTenant(&commonpb.KeyValuePair{Key: CollectionStorageTenantKey, Value: strings.Repeat("a", 65)})
+	assert.Error(t, err)
+}
+
+func TestGetExternalPath(t *testing.T) {
+	path, err := GetExternalPath()
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+
+	path, err = GetExternalPath(&commonpb.KeyValuePair{Key: CollectionExternalPathKey, Value: "/lake/table_1/"})
+	assert.NoError(t, err)
+	assert.Equal(t, "lake/table_1", path)
+
+	for _, value := range []string{"", "/", "lake//table", "lake/../table", "./table"} {
+		_, err = GetExternalPath(&commonpb.KeyValuePair{Key: CollectionExternalPathKey, Value: value})
+		assert.Error(t, err, value)
+	}
+}
+
+func TestGetPKBucketNum(t *testing.T) {
+	num, err := GetPKBucketNum()
+	assert.NoError(t, err)
+	assert.Zero(t, num)
+
+	num, err = GetPKBucketNum(&commonpb.KeyValuePair{Key: CollectionPKBucketNumKey, Value: "16"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 16, num)
+
+	for _, value := range []string{"0", "-1", "x", "1025"} {
+		_, err = GetPKBucketNum(&commonpb.KeyValuePair{Key: CollectionPKBucketNumKey, Value: value})
+		assert.Error(t, err, value)
+	}
+}
+
+func TestGetClusteringField(t *testing.T) {
+	assert.Empty(t, GetClusteringField())
+	assert.Equal(t, "tenant_id", GetClusteringField(
+		&commonpb.KeyValuePair{Key: CollectionPKBucketNumKey, Value: "16"},
+		&commonpb.KeyValuePair{Key: CollectionClusteringFieldKey, Value: "tenant_id"},
+	))
+}
+
+func TestGetTimeBucket(t *testing.T) {
+	field, width, err := GetTimeBucket()
+	assert.NoError(t, err)
+	assert.Empty(t, field)
+	assert.Zero(t, width)
+
+	field, width, err = GetTimeBucket(
+		&commonpb.KeyValuePair{Key: CollectionTimeBucketFieldKey, Value: "event_time"},
+		&commonpb.KeyValuePair{Key: CollectionTimeBucketSecondsKey, Value: "3600"},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "event_time", field)
+	assert.EqualValues(t, 3600*1000, width)
+
+	_, _, err = GetTimeBucket(&commonpb.KeyValuePair{Key: CollectionTimeBucketFieldKey, Value: "event_time"})
+	assert.Error(t, err)
+	for _, value := range []string{"0", "-1", "1h", "9223372036854775807"} {
+		_, _, err = GetTimeBucket(
+			&commonpb.KeyValuePair{Key: CollectionTimeBucketFieldKey, Value: "event_time"},
+			&commonpb.KeyValuePair{Key: CollectionTimeBucketSecondsKey, Value: value},
+		)
+		assert.Error(t, err, value)
+	}
+}
@@ -29,6 +29,7 @@ const (
 	InsertFileLabel          = "insert_file"
 	DeleteFileLabel          = "delete_file"
 	StatFileLabel            = "stat_file"
+	QuantizedFileLabel       = "quantized_file"
 	IndexFileLabel           = "index_file"
 	segmentFileTypeLabelName = "segment_file_type"
 )
@@ -145,6 +146,48 @@ var (
 			segmentIDLabelName,
 		})
 
+	DataCoordSegmentDeletedRowRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.DataCoordRole,
+			Name:      "segment_deleted_row_ratio",
+			Help:      "ratio of deleted rows to total rows of healthy segments",
+		}, []string{
+			collectionIDLabelName,
+			segmentIDLabelName,
+		})
+
+	DataCoordSegmentDeltalogSizeRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.DataCoordRole,
+			Name:      "segment_deltalog_size_ratio",
+			Help:      "ratio of deltalog size to insert binlog size of healthy segments",
+		}, []string{
+			collectionIDLabelName,
+			segmentIDLabelName,
+		})
+
+	DataCoordCollectionDeletedRowRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.DataCoordRole,
+			Name:      "collection_deleted_row_ratio",
+			Help:      "ratio of deleted rows to total rows of the healthy segments of collections",
+		}, []string{
+			collectionIDLabelName,
+		})
+
+	DataCoordCollectionDeltalogSizeRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.DataCoordRole,
+			Name:      "collection_deltalog_size_ratio",
+			Help:      "ratio of deltalog size to insert binlog size of the healthy segments of collections",
+		}, []string{
+			collectionIDLabelName,
+		})
+
 	DataCoordDmlChannelNum = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: milvusNamespace,
@@ -176,6 +219,18 @@ var (
 			statusLabelName,
 		})
 
+	// DataCoordOutdatedSegmentNum records the number of inspected segments of outdated encodings
+	// waiting to be re-encoded, by the outdated encoding.
+	DataCoordOutdatedSegmentNum = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.DataCoordRole,
+			Name:      "outdated_segment_num",
+			Help:      "number of segments of outdated encodings to re-encode",
+		}, []string{
+			encodingLabelName,
+		})
+
 	FlushedSegmentFileNum = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: milvusNamespace,
@@ -281,9 +336,14 @@ func RegisterDataCoord(registry *prometheus.Registry) {
 	registry.MustRegister(DataCoordCheckpointUnixSeconds)
 	registry.MustRegister(DataCoordStoredBinlogSize)
 	registry.MustRegister(DataCoordSegmentBinLogFileCount)
+	registry.MustRegister(DataCoordSegmentDeletedRowRatio)
+	registry.MustRegister(DataCoordSegmentDeltalogSizeRatio)
+	registry.MustRegister(DataCoordCollectionDeletedRowRatio)
+	registry.MustRegister(DataCoordCollectionDeltalogSizeRatio)
 	registry.MustRegister(DataCoordDmlChannelNum)
 	registry.MustRegister(DataCoordCompactedSegmentSize)
 	registry.MustRegister(DataCoordCompactionTaskNum)
+	registry.MustRegister(DataCoordOutdatedSegmentNum)
 	registry.MustRegister(DataCoordSizeStoredL0Segment)
 	registry.MustRegister(DataCoordRateStoredL0Segment)
 	registry.MustRegister(FlushedSegmentFileNum)
@@ -303,6 +363,14 @@ func CleanupDataCoordSegmentMetrics(collectionID int64, segmentID int64) {
 		collectionIDLabelName: fmt.Sprint(collectionID),
 		segmentIDLabelName:    fmt.Sprint(segmentID),
 	})
+	DataCoordSegmentDeletedRowRatio.Delete(prometheus.Labels{
+		collectionIDLabelName: fmt.Sprint(collectionID),
+		segmentIDLabelName:    fmt.Sprint(segmentID),
+	})
+	DataCoordSegmentDeltalogSizeRatio.Delete(prometheus.Labels{
+		collectionIDLabelName: fmt.Sprint(collectionID),
+		segmentIDLabelName:    fmt.Sprint(segmentID),
+	})
 }
 
 func CleanupDataCoordNumStoredRows(collectionID int64) {
@@ -313,3 +381,12 @@ func CleanupDataCoordNumStoredRows(collectionID int64) {
 		})
 	}
 }
+
+func CleanupDataCoordCollectionDeleteRatio(collectionID int64) {
+	DataCoordCollectionDeletedRowRatio.Delete(prometheus.Labels{
+		collectionIDLabelName: fmt.Sprint(collectionID),
+	})
+	DataCoordCollectionDeltalogSizeRatio.Delete(prometheus.Labels{
+		collectionIDLabelName: fmt.Sprint(collectionID),
+	})
+}
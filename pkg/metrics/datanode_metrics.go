@@ -216,6 +216,82 @@ var (
 			collectionIDLabelName,
 		})
 
+	DataNodeMemoryBudgetUsage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.DataNodeRole,
+			Name:      "memory_budget_usage",
+			Help:      "the memory in bytes used from the shared memory budget",
+		}, []string{
+			nodeIDLabelName,
+			memoryCategoryLabelName,
+		})
+
+	DataNodeInsertChecksumMismatchCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.DataNodeRole,
+			Name:      "insert_checksum_mismatch_count",
+			Help:      "count of consumed insert messages mismatching the checksum carried",
+		}, []string{
+			nodeIDLabelName,
+			collectionIDLabelName,
+		})
+
+	DataNodeBinlogIOBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.DataNodeRole,
+			Name:      "binlog_io_bytes",
+			Help:      "bytes of the binlogs downloaded and uploaded by binlog io",
+		}, []string{
+			nodeIDLabelName,
+			ioOpLabelName,
+			collectionIDLabelName,
+			binlogTypeLabelName,
+		})
+
+	DataNodeBinlogIOLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.DataNodeRole,
+			Name:      "binlog_io_latency",
+			Help:      "latency of the binlog io requests including the retries, in milliseconds",
+			Buckets:   buckets,
+		}, []string{
+			nodeIDLabelName,
+			ioOpLabelName,
+			collectionIDLabelName,
+			binlogTypeLabelName,
+		})
+
+	DataNodeBinlogIORetryCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.DataNodeRole,
+			Name:      "binlog_io_retry_count",
+			Help:      "count of the retried binlog io requests",
+		}, []string{
+			nodeIDLabelName,
+			ioOpLabelName,
+			collectionIDLabelName,
+			binlogTypeLabelName,
+		})
+
+	DataNodeBinlogIOFailureCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.DataNodeRole,
+			Name:      "binlog_io_failure_count",
+			Help:      "count of the failed binlog io requests by the failure cause",
+		}, []string{
+			nodeIDLabelName,
+			ioOpLabelName,
+			collectionIDLabelName,
+			binlogTypeLabelName,
+			failureCauseLabelName,
+		})
+
 	DataNodeMsgDispatcherTtLag = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: milvusNamespace,
@@ -237,8 +313,10 @@ func RegisterDataNode(registry *prometheus.Registry) {
 	registry.MustRegister(DataNodeMsgDispatcherTtLag)
 	registry.MustRegister(DataNodeConsumeMsgCount)
 	registry.MustRegister(DataNodeConsumeBytesCount)
+	registry.MustRegister(DataNodeInsertChecksumMismatchCount)
 	// in memory
 	registry.MustRegister(DataNodeFlowGraphBufferDataSize)
+	registry.MustRegister(DataNodeMemoryBudgetUsage)
 	// output related
 	registry.MustRegister(DataNodeAutoFlushBufferCount)
 	registry.MustRegister(DataNodeEncodeBufferLatency)
@@ -246,6 +324,10 @@ func RegisterDataNode(registry *prometheus.Registry) {
 	registry.MustRegister(DataNodeFlushBufferCount)
 	registry.MustRegister(DataNodeFlushReqCounter)
 	registry.MustRegister(DataNodeFlushedSize)
+	registry.MustRegister(DataNodeBinlogIOBytes)
+	registry.MustRegister(DataNodeBinlogIOLatency)
+	registry.MustRegister(DataNodeBinlogIORetryCount)
+	registry.MustRegister(DataNodeBinlogIOFailureCount)
 	// compaction related
 	registry.MustRegister(DataNodeCompactionLatency)
 	registry.MustRegister(DataNodeCompactionLatencyInQueue)
@@ -286,4 +368,16 @@ func CleanupDataNodeCollectionMetrics(nodeID int64, collectionID int64, channel
 		nodeIDLabelName:       fmt.Sprint(nodeID),
 		collectionIDLabelName: fmt.Sprint(collectionID),
 	})
+
+	for _, vec := range []*prometheus.MetricVec{
+		DataNodeBinlogIOBytes.MetricVec,
+		DataNodeBinlogIOLatency.MetricVec,
+		DataNodeBinlogIORetryCount.MetricVec,
+		DataNodeBinlogIOFailureCount.MetricVec,
+	} {
+		vec.DeletePartialMatch(prometheus.Labels{
+			nodeIDLabelName:       fmt.Sprint(nodeID),
+			collectionIDLabelName: fmt.Sprint(collectionID),
+		})
+	}
 }
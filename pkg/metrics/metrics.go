@@ -70,6 +70,20 @@ const (
 	Executing = "executing"
 	Done      = "done"
 
+	DownloadLabel = "download"
+	UploadLabel   = "upload"
+
+	InsertBinlogLabel  = "insert"
+	DeltaBinlogLabel   = "delta"
+	StatsBinlogLabel   = "stats"
+	UnknownBinlogLabel = "unknown"
+
+	CanceledCauseLabel  = "canceled"
+	TimeoutCauseLabel   = "timeout"
+	NotFoundCauseLabel  = "not_found"
+	CorruptedCauseLabel = "corrupted"
+	StorageCauseLabel   = "storage"
+
 	compactionTypeLabelName  = "compaction_type"
 	nodeIDLabelName          = "node_id"
 	statusLabelName          = "status"
@@ -96,6 +110,11 @@ const (
 	lockSource               = "lock_source"
 	lockType                 = "lock_type"
 	lockOp                   = "lock_op"
+	encodingLabelName        = "encoding"
+	memoryCategoryLabelName  = "memory_category"
+	ioOpLabelName            = "io_op"
+	binlogTypeLabelName      = "binlog_type"
+	failureCauseLabelName    = "failure_cause"
 )
 
 var (
@@ -128,6 +147,20 @@ var (
 			lockOp,
 		})
 
+	LocalDiskUsageRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Name:      "local_disk_usage_ratio",
+			Help:      "usage ratio of the disk holding the local storage path",
+		}, []string{nodeIDLabelName, roleNameLabelName})
+
+	LocalDiskDegraded = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Name:      "local_disk_degraded",
+			Help:      "whether the disk consuming activities are paused because the local disk is almost full, 1 means paused",
+		}, []string{nodeIDLabelName, roleNameLabelName})
+
 	metricRegisterer prometheus.Registerer
 )
 
@@ -147,5 +180,7 @@ func Register(r prometheus.Registerer) {
 	r.MustRegister(LockCosts)
 	r.MustRegister(BuildInfo)
 	r.MustRegister(RuntimeInfo)
+	r.MustRegister(LocalDiskUsageRatio)
+	r.MustRegister(LocalDiskDegraded)
 	metricRegisterer = r
 }
@@ -26,6 +26,16 @@ const (
 	DataStatLabel   = "stat"
 
 	persistentDataOpType = "persistent_data_op_type"
+
+	StorageTierHotLabel  = "hot"
+	StorageTierColdLabel = "cold"
+
+	storageTierLabelName = "tier"
+
+	HedgeIssuedLabel = "issued"
+	HedgeWonLabel    = "won"
+
+	hedgeResultLabelName = "result"
 )
 
 var (
@@ -54,6 +64,38 @@ var (
 			Name:      "op_count",
 			Help:      "count of persistent data operation",
 		}, []string{persistentDataOpType, statusLabelName})
+
+	PersistentDataHedgedReadCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: "storage",
+			Name:      "hedged_read_count",
+			Help:      "count of the hedged reads issued, and of the ones responded before the reads they hedge",
+		}, []string{hedgeResultLabelName})
+
+	StorageTierReadCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: "storage",
+			Name:      "tier_read_count",
+			Help:      "count of the reads of the tiered storage served by each tier",
+		}, []string{storageTierLabelName})
+
+	StorageTierHotBytes = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: "storage",
+			Name:      "tier_hot_bytes",
+			Help:      "bytes of the files cached by the hot tier",
+		})
+
+	StorageTierEvictedBytes = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: "storage",
+			Name:      "tier_evicted_bytes",
+			Help:      "bytes of the files evicted from the hot tier",
+		})
 )
 
 // RegisterStorageMetrics registers storage metrics
@@ -61,4 +103,8 @@ func RegisterStorageMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(PersistentDataKvSize)
 	registry.MustRegister(PersistentDataRequestLatency)
 	registry.MustRegister(PersistentDataOpCounter)
+	registry.MustRegister(PersistentDataHedgedReadCounter)
+	registry.MustRegister(StorageTierReadCounter)
+	registry.MustRegister(StorageTierHotBytes)
+	registry.MustRegister(StorageTierEvictedBytes)
 }
@@ -0,0 +1,94 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgstream
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// InsertChecksumKey is the key of the msg base property carrying the checksum of the insert message.
+const InsertChecksumKey = "insert_checksum"
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ComputeInsertChecksum returns the CRC-32C checksum of the rows of the insert message,
+// which covers the row ids, the timestamps and the field data.
+func ComputeInsertChecksum(msg *InsertMsg) (uint32, error) {
+	hash := crc32.New(castagnoliTable)
+	buf := make([]byte, 8)
+	for _, rowID := range msg.GetRowIDs() {
+		binary.LittleEndian.PutUint64(buf, uint64(rowID))
+		hash.Write(buf)
+	}
+	for _, ts := range msg.GetTimestamps() {
+		binary.LittleEndian.PutUint64(buf, ts)
+		hash.Write(buf)
+	}
+	for _, fieldData := range msg.GetFieldsData() {
+		bs, err := proto.Marshal(fieldData)
+		if err != nil {
+			return 0, err
+		}
+		hash.Write(bs)
+	}
+	return hash.Sum32(), nil
+}
+
+// SetInsertChecksum computes the checksum of the insert message and carries it in the msg base properties.
+func SetInsertChecksum(msg *InsertMsg) error {
+	checksum, err := ComputeInsertChecksum(msg)
+	if err != nil {
+		return err
+	}
+	if msg.Base == nil {
+		msg.Base = &commonpb.MsgBase{}
+	}
+	if msg.Base.Properties == nil {
+		msg.Base.Properties = make(map[string]string)
+	}
+	msg.Base.Properties[InsertChecksumKey] = strconv.FormatUint(uint64(checksum), 10)
+	return nil
+}
+
+// VerifyInsertChecksum verifies the rows of the insert message against the checksum it carries,
+// returns false if the insert message carries no checksum.
+func VerifyInsertChecksum(msg *InsertMsg) (bool, error) {
+	value, ok := msg.GetBase().GetProperties()[InsertChecksumKey]
+	if !ok {
+		return false, nil
+	}
+	expected, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return true, merr.WrapErrParameterInvalidMsg("invalid insert checksum %s", value)
+	}
+	actual, err := ComputeInsertChecksum(msg)
+	if err != nil {
+		return true, err
+	}
+	if uint32(expected) != actual {
+		return true, merr.WrapErrIoChecksumMismatch(fmt.Sprintf("insert msg %d", msg.ID()), uint32(expected), actual)
+	}
+	return true, nil
+}
@@ -0,0 +1,79 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgstream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestInsertChecksum(t *testing.T) {
+	insertMsg := &InsertMsg{
+		BaseMsg: generateBaseMsg(),
+		InsertRequest: msgpb.InsertRequest{
+			Base: &commonpb.MsgBase{
+				MsgType: commonpb.MsgType_Insert,
+				MsgID:   1,
+			},
+			RowIDs:     []int64{1, 2},
+			Timestamps: []uint64{10, 10},
+			FieldsData: []*schemapb.FieldData{{
+				Type:    schemapb.DataType_Int64,
+				FieldId: 100,
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{1, 2}}},
+				}},
+			}},
+			Version: msgpb.InsertDataVersion_ColumnBased,
+			NumRows: 2,
+		},
+	}
+
+	// no checksum carried
+	carried, err := VerifyInsertChecksum(insertMsg)
+	assert.False(t, carried)
+	assert.NoError(t, err)
+
+	require.NoError(t, SetInsertChecksum(insertMsg))
+	assert.Contains(t, insertMsg.GetBase().GetProperties(), InsertChecksumKey)
+
+	// the checksum is carried through the message stream
+	bytes, err := insertMsg.Marshal(insertMsg)
+	require.NoError(t, err)
+	tsMsg, err := insertMsg.Unmarshal(bytes)
+	require.NoError(t, err)
+	carried, err = VerifyInsertChecksum(tsMsg.(*InsertMsg))
+	assert.True(t, carried)
+	assert.NoError(t, err)
+
+	// corrupted
+	insertMsg.GetFieldsData()[0].GetScalars().GetLongData().Data[1] = 3
+	carried, err = VerifyInsertChecksum(insertMsg)
+	assert.True(t, carried)
+	assert.ErrorIs(t, err, merr.ErrIoChecksumMismatch)
+
+	insertMsg.Base.Properties[InsertChecksumKey] = "invalid"
+	_, err = VerifyInsertChecksum(insertMsg)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
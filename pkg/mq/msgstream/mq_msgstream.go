@@ -36,6 +36,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/config"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper"
+	"github.com/milvus-io/milvus/pkg/util/faultinject"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/retry"
@@ -322,6 +323,11 @@ func (ms *mqMsgStream) Produce(msgPack *MsgPack) error {
 			msg := &mqwrapper.ProducerMessage{Payload: m, Properties: map[string]string{}}
 			InjectCtx(spanCtx, msg.Properties)
 
+			if err := faultinject.Inject(spanCtx, faultinject.MsgStreamProduce); err != nil {
+				sp.RecordError(err)
+				return err
+			}
+
 			ms.producerLock.RLock()
 			if _, err := ms.producers[channel].Send(spanCtx, msg); err != nil {
 				ms.producerLock.RUnlock()
@@ -365,6 +371,12 @@ func (ms *mqMsgStream) Broadcast(msgPack *MsgPack) (map[string][]MessageID, erro
 		msg := &mqwrapper.ProducerMessage{Payload: m, Properties: map[string]string{}}
 		InjectCtx(spanCtx, msg.Properties)
 
+		if err := faultinject.Inject(spanCtx, faultinject.MsgStreamProduce); err != nil {
+			sp.RecordError(err)
+			sp.End()
+			return ids, err
+		}
+
 		ms.producerLock.Lock()
 		for channel, producer := range ms.producers {
 			id, err := producer.Send(spanCtx, msg)
@@ -0,0 +1,68 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build faultinject
+
+package msgstream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper"
+	"github.com/milvus-io/milvus/pkg/util/faultinject"
+)
+
+type countingProducer struct {
+	sent int
+}
+
+func (p *countingProducer) Send(ctx context.Context, message *mqwrapper.ProducerMessage) (MessageID, error) {
+	p.sent++
+	return nil, nil
+}
+
+func (p *countingProducer) Close() {}
+
+func TestStream_FaultInject(t *testing.T) {
+	defer faultinject.Reset()
+	mockErr := errors.New("mock error")
+
+	factory := ProtoUDFactory{}
+	stream, err := NewMqMsgStream(context.Background(), 100, 100, nil, factory.NewUnmarshalDispatcher())
+	assert.NoError(t, err)
+	producer := &countingProducer{}
+	stream.producers["ch"] = producer
+	stream.producerChannels = []string{"ch"}
+
+	// the message before the failed one is sent
+	faultinject.Enable(faultinject.MsgStreamProduce, faultinject.Fault{Err: mockErr, Skip: 1, Times: 1})
+	assert.ErrorIs(t, stream.Produce(getInsertMsgPack([]int{1, 2, 3})), mockErr)
+	assert.Equal(t, 1, producer.sent)
+	assert.NoError(t, stream.Produce(getInsertMsgPack([]int{1, 2, 3})))
+	assert.Equal(t, 4, producer.sent)
+
+	faultinject.Enable(faultinject.MsgStreamProduce, faultinject.Fault{Err: mockErr, Times: 1})
+	_, err = stream.Broadcast(getTimeTickMsgPack(1))
+	assert.ErrorIs(t, err, mockErr)
+	assert.Equal(t, 4, producer.sent)
+	_, err = stream.Broadcast(getTimeTickMsgPack(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, producer.sent)
+}
@@ -0,0 +1,106 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build faultinject
+
+package faultinject
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+)
+
+// Enabled indicates whether the binary is built with the faultinject tag.
+const Enabled = true
+
+type point struct {
+	fault     Fault
+	evaluated int
+	triggered int
+}
+
+var (
+	mu     sync.Mutex
+	points = make(map[string]*point)
+)
+
+// Enable injects the fault into the point, replacing the previous one.
+func Enable(name string, fault Fault) {
+	mu.Lock()
+	defer mu.Unlock()
+	points[name] = &point{fault: fault}
+	log.Info("fault injected", zap.String("point", name), zap.Error(fault.Err),
+		zap.Duration("latency", fault.Latency), zap.Int("skip", fault.Skip), zap.Int("times", fault.Times))
+}
+
+// Disable removes the fault of the point.
+func Disable(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(points, name)
+}
+
+// Reset removes the faults of all the points.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	points = make(map[string]*point)
+}
+
+// Triggered returns the number of times the fault of the point has been triggered.
+func Triggered(name string) int {
+	mu.Lock()
+	defer mu.Unlock()
+	if p, ok := points[name]; ok {
+		return p.triggered
+	}
+	return 0
+}
+
+// Inject evaluates the point, it waits for the latency and returns the error of the fault if triggered.
+func Inject(ctx context.Context, name string) error {
+	mu.Lock()
+	p, ok := points[name]
+	if !ok {
+		mu.Unlock()
+		return nil
+	}
+	p.evaluated++
+	if p.evaluated <= p.fault.Skip || (p.fault.Times > 0 && p.triggered >= p.fault.Times) {
+		mu.Unlock()
+		return nil
+	}
+	p.triggered++
+	fault := p.fault
+	mu.Unlock()
+
+	if fault.Latency > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(fault.Latency):
+		}
+	}
+	if fault.Err != nil {
+		log.Ctx(ctx).Warn("fault triggered", zap.String("point", name), zap.Error(fault.Err))
+	}
+	return fault.Err
+}
@@ -0,0 +1,71 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build faultinject
+
+package faultinject
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInject(t *testing.T) {
+	defer Reset()
+	ctx := context.Background()
+	mockErr := errors.New("mock error")
+
+	assert.True(t, Enabled)
+	assert.NoError(t, Inject(ctx, ChunkManagerRead))
+
+	t.Run("skip and times", func(t *testing.T) {
+		Enable(ChunkManagerRead, Fault{Err: mockErr, Skip: 1, Times: 2})
+		assert.NoError(t, Inject(ctx, ChunkManagerRead))
+		assert.ErrorIs(t, Inject(ctx, ChunkManagerRead), mockErr)
+		assert.ErrorIs(t, Inject(ctx, ChunkManagerRead), mockErr)
+		assert.NoError(t, Inject(ctx, ChunkManagerRead))
+		assert.Equal(t, 2, Triggered(ChunkManagerRead))
+
+		// other points are not affected
+		assert.NoError(t, Inject(ctx, ChunkManagerWrite))
+		assert.Equal(t, 0, Triggered(ChunkManagerWrite))
+
+		Disable(ChunkManagerRead)
+		assert.Equal(t, 0, Triggered(ChunkManagerRead))
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		Enable(MsgStreamProduce, Fault{Latency: 50 * time.Millisecond})
+		start := time.Now()
+		assert.NoError(t, Inject(ctx, MsgStreamProduce))
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+		Enable(MsgStreamProduce, Fault{Latency: time.Hour})
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, Inject(ctx, MsgStreamProduce), context.DeadlineExceeded)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		Enable(ChunkManagerMultiWrite, Fault{Err: mockErr})
+		Reset()
+		assert.NoError(t, Inject(ctx, ChunkManagerMultiWrite))
+	})
+}
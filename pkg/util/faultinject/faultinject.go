@@ -0,0 +1,59 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package faultinject provides failpoint-style hooks to inject errors and latency into the
+// storage and mq paths, so that the retry and recovery logic could be covered by chaos tests.
+//
+// The hooks are only compiled with the `faultinject` build tag, e.g.
+//
+//	go test -tags faultinject ./internal/datanode/io/...
+//
+// Without the tag, Enabled is false and Inject always returns nil, which costs nothing in
+// the release binaries.
+package faultinject
+
+import (
+	"time"
+)
+
+// The injection points.
+const (
+	// ChunkManagerRead is evaluated by ChunkManager.Read.
+	ChunkManagerRead = "storage.read"
+	// ChunkManagerWrite is evaluated by ChunkManager.Write.
+	ChunkManagerWrite = "storage.write"
+	// ChunkManagerMultiRead is evaluated for each file of ChunkManager.MultiRead,
+	// the other files are still read, which simulates a partial failure.
+	ChunkManagerMultiRead = "storage.multiRead"
+	// ChunkManagerMultiWrite is evaluated for each file of ChunkManager.MultiWrite,
+	// the other files are still written, which simulates a partial failure.
+	ChunkManagerMultiWrite = "storage.multiWrite"
+	// MsgStreamProduce is evaluated for each message produced or broadcast by msgstream,
+	// the messages before the failed one are sent.
+	MsgStreamProduce = "msgstream.produce"
+)
+
+// Fault describes the fault of an injection point.
+type Fault struct {
+	// Err is returned by the point once the fault is triggered, nil to inject latency only.
+	Err error
+	// Latency delays the point once the fault is triggered.
+	Latency time.Duration
+	// Skip is the number of evaluations passing through before the fault is triggered.
+	Skip int
+	// Times is the number of evaluations triggering the fault, unlimited if not positive.
+	Times int
+}
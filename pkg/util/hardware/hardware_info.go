@@ -18,6 +18,7 @@ import (
 	"sync"
 
 	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/mem"
 	"go.uber.org/automaxprocs/maxprocs"
 	"go.uber.org/zap"
@@ -114,6 +115,15 @@ func GetDiskUsage() uint64 {
 	return 2 * 1024 * 1024
 }
 
+// GetDiskUsageByPath returns the used and total bytes of the filesystem which the path is on.
+func GetDiskUsageByPath(path string) (uint64, uint64, error) {
+	stats, err := disk.Usage(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	return stats.Used, stats.Total, nil
+}
+
 func GetMemoryUseRatio() float64 {
 	usedMemory := GetUsedMemoryCount()
 	totalMemory := GetMemoryCount()
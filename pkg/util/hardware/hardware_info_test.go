@@ -52,6 +52,16 @@ func Test_GetDiskUsage(t *testing.T) {
 		zap.Uint64("DiskUsage", GetDiskUsage()))
 }
 
+func Test_GetDiskUsageByPath(t *testing.T) {
+	used, total, err := GetDiskUsageByPath(t.TempDir())
+	assert.NoError(t, err)
+	assert.NotZero(t, total)
+	assert.LessOrEqual(t, used, total)
+
+	_, _, err = GetDiskUsageByPath("/not/exist/path")
+	assert.Error(t, err)
+}
+
 func Test_GetMemoryUsageRatio(t *testing.T) {
 	log.Info("TestGetMemoryUsageRatio",
 		zap.Float64("Memory usage ratio", GetMemoryUseRatio()))
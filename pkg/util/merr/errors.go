@@ -51,6 +51,7 @@ var (
 	ErrCollectionNotFullyLoaded   = newMilvusError("collection not fully loaded", 103, true)
 	ErrCollectionLoaded           = newMilvusError("collection already loaded", 104, false)
 	ErrCollectionIllegalSchema    = newMilvusError("illegal collection schema", 105, false)
+	ErrCollectionReadOnly         = newMilvusError("collection is read-only", 106, false)
 
 	// Partition related
 	ErrPartitionNotFound       = newMilvusError("partition not found", 200, false)
@@ -97,8 +98,9 @@ var (
 	ErrNodeNotAvailable = newMilvusError("node not available", 905, false)
 
 	// IO related
-	ErrIoKeyNotFound = newMilvusError("key not found", 1000, false)
-	ErrIoFailed      = newMilvusError("IO failed", 1001, false)
+	ErrIoKeyNotFound      = newMilvusError("key not found", 1000, false)
+	ErrIoFailed           = newMilvusError("IO failed", 1001, false)
+	ErrIoChecksumMismatch = newMilvusError("checksum mismatch", 1002, false)
 
 	// Parameter related
 	ErrParameterInvalid = newMilvusError("invalid parameter", 1100, false)
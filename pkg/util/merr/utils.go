@@ -445,6 +445,14 @@ func WrapErrCollectionNotLoaded(collection any, msg ...string) error {
 	return err
 }
 
+func WrapErrCollectionReadOnly(collection any, msg ...string) error {
+	err := wrapFields(ErrCollectionReadOnly, value("collection", collection))
+	if len(msg) > 0 {
+		err = errors.Wrap(err, strings.Join(msg, "->"))
+	}
+	return err
+}
+
 func WrapErrCollectionNumLimitExceeded(limit int, msg ...string) error {
 	err := wrapFields(ErrCollectionNumLimitExceeded, value("limit", limit))
 	if len(msg) > 0 {
@@ -766,6 +774,18 @@ func WrapErrIoFailed(key string, err error) error {
 	return wrapFieldsWithDesc(ErrIoFailed, err.Error(), value("key", key))
 }
 
+func WrapErrIoChecksumMismatch(key string, expected, actual uint32, msg ...string) error {
+	err := wrapFields(ErrIoChecksumMismatch,
+		value("key", key),
+		value("expected", expected),
+		value("actual", actual),
+	)
+	if len(msg) > 0 {
+		err = errors.Wrap(err, strings.Join(msg, "->"))
+	}
+	return err
+}
+
 func WrapErrIoFailedReason(reason string, msg ...string) error {
 	err := wrapFieldsWithDesc(ErrIoFailed, reason)
 	if len(msg) > 0 {
@@ -11,6 +11,58 @@ import (
 
 const pathSep = "/"
 
+// tenantPrefix prefixes the tenant component of the binlog paths, so that the tenant paths could be told
+// from the ones without the tenant component, no matter what the root path is.
+const tenantPrefix = "tenant="
+
+// TenantRootPath returns the root path of the binlogs of the tenant, which is the root path itself if no tenant,
+// the binlog paths are "{root}/tenant={tenant}/{log type}/{collection id}/..." then.
+func TenantRootPath(rootPath string, tenant string) string {
+	if tenant == "" {
+		return rootPath
+	}
+	return path.Join(rootPath, tenantPrefix+tenant)
+}
+
+// TenantRootPathPrefix returns the prefix of the root paths of all tenants.
+func TenantRootPathPrefix(rootPath string) string {
+	return path.Join(rootPath, tenantPrefix)
+}
+
+// GetTenantFromRootPath returns the tenant of the root path returned by TenantRootPath, empty string if no tenant.
+func GetTenantFromRootPath(tenantRootPath string) string {
+	base := path.Base(strings.TrimSuffix(tenantRootPath, pathSep))
+	if !strings.HasPrefix(base, tenantPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(base, tenantPrefix)
+}
+
+// GetTenantFromLogPath returns the tenant component of the binlog path, empty string if no tenant.
+func GetTenantFromLogPath(logPath string) string {
+	// the tenant component is followed by the layout dir if any
+	if info, ok := ParseLogPath(logPath); ok {
+		infos := strings.Split(info.Root, pathSep)
+		for i := len(infos) - 1; i >= 0; i-- {
+			if strings.HasPrefix(infos[i], tenantPrefix) {
+				return strings.TrimPrefix(infos[i], tenantPrefix)
+			}
+		}
+		return ""
+	}
+	infos := strings.Split(logPath, pathSep)
+	for i := 0; i+1 < len(infos); i++ {
+		if !strings.HasPrefix(infos[i], tenantPrefix) {
+			continue
+		}
+		switch infos[i+1] {
+		case common.SegmentInsertLogPath, common.SegmentStatslogPath, common.SegmentDeltaLogPath, common.SegmentQuantizedLogPath:
+			return strings.TrimPrefix(infos[i], tenantPrefix)
+		}
+	}
+	return ""
+}
+
 func BuildInsertLogPath(rootPath string, collectionID, partitionID, segmentID, fieldID, logID typeutil.UniqueID) string {
 	k := JoinIDPath(collectionID, partitionID, segmentID, fieldID, logID)
 	return path.Join(rootPath, common.SegmentInsertLogPath, k)
@@ -0,0 +1,218 @@
+package metautil
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// the placeholders of the path template
+const (
+	LayoutCluster    = "{cluster}"
+	LayoutTier       = "{tier}"
+	LayoutCollection = "{collection}"
+	LayoutYear       = "{yyyy}"
+	LayoutMonth      = "{mm}"
+	LayoutDay        = "{dd}"
+	LayoutYearMonth  = "{yyyy-mm}"
+	LayoutDate       = "{yyyy-mm-dd}"
+)
+
+// the placeholders rendered differently by the collections or the dates of the binlogs
+var layoutVariables = []string{LayoutCollection, LayoutYear, LayoutMonth, LayoutDay, LayoutYearMonth, LayoutDate}
+
+// PathLayout renders the directory between the root path and the binlog type of the binlog paths by the template,
+// the binlog paths are "{root}/[tenant={tenant}/]{layout dir}/{log type}/{collection id}/..." then,
+// so the buckets could be sharded by the cluster, the collection or the date for the lifecycle policies.
+// The leading component of the template is static, i.e. the same for all the binlogs of the deployment,
+// so the binlogs of the layout could be listed under it.
+type PathLayout struct {
+	template   string
+	components []string
+	cluster    string
+	tier       string
+}
+
+// NewPathLayout parses the template of the components separated by "/", each of which is a literal mixed with
+// the placeholders. Nil is returned if the template is empty, which is the default layout without the layout dir.
+func NewPathLayout(template, cluster, tier string) (*PathLayout, error) {
+	template = strings.Trim(template, pathSep)
+	if template == "" {
+		return nil, nil
+	}
+	layout := &PathLayout{
+		template:   template,
+		components: strings.Split(template, pathSep),
+		cluster:    cluster,
+		tier:       tier,
+	}
+	for i, component := range layout.components {
+		if layout.isVariable(component) && i == 0 {
+			return nil, fmt.Errorf("the leading component %s of path template %s is not static", component, template)
+		}
+		literal := strings.NewReplacer(LayoutCluster, "", LayoutTier, "", LayoutCollection, "", LayoutYearMonth, "",
+			LayoutDate, "", LayoutYear, "", LayoutMonth, "", LayoutDay, "").Replace(component)
+		if strings.ContainsAny(literal, "{}") {
+			return nil, fmt.Errorf("unknown placeholder in component %s of path template %s", component, template)
+		}
+		rendered := layout.render(component, 0, time.Time{})
+		switch {
+		case rendered == "" || rendered == "." || rendered == "..":
+			return nil, fmt.Errorf("component %s of path template %s is rendered as %q", component, template, rendered)
+		case isLogType(rendered) || strings.HasPrefix(rendered, tenantPrefix):
+			return nil, fmt.Errorf("component %s of path template %s conflicts with the binlog paths", component, template)
+		}
+	}
+	return layout, nil
+}
+
+// Template returns the template of the layout.
+func (l *PathLayout) Template() string {
+	return l.template
+}
+
+// Dir returns the layout dir of the binlogs of the collection written at the time,
+// empty string if the layout is nil.
+func (l *PathLayout) Dir(collectionID typeutil.UniqueID, t time.Time) string {
+	if l == nil {
+		return ""
+	}
+	rendered := make([]string, 0, len(l.components))
+	for _, component := range l.components {
+		rendered = append(rendered, l.render(component, collectionID, t))
+	}
+	return path.Join(rendered...)
+}
+
+// StaticDir returns the leading components of the layout dir which are the same for all the binlogs,
+// empty string if the layout is nil.
+func (l *PathLayout) StaticDir() string {
+	if l == nil {
+		return ""
+	}
+	rendered := make([]string, 0, len(l.components))
+	for _, component := range l.components {
+		if l.isVariable(component) {
+			break
+		}
+		rendered = append(rendered, l.render(component, 0, time.Time{}))
+	}
+	return path.Join(rendered...)
+}
+
+func (l *PathLayout) isVariable(component string) bool {
+	for _, variable := range layoutVariables {
+		if strings.Contains(component, variable) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *PathLayout) render(component string, collectionID typeutil.UniqueID, t time.Time) string {
+	t = t.UTC()
+	return strings.NewReplacer(
+		LayoutCluster, l.cluster,
+		LayoutTier, l.tier,
+		LayoutCollection, strconv.FormatInt(collectionID, 10),
+		LayoutYearMonth, t.Format("2006-01"),
+		LayoutDate, t.Format("2006-01-02"),
+		LayoutYear, t.Format("2006"),
+		LayoutMonth, t.Format("01"),
+		LayoutDay, t.Format("02"),
+	).Replace(component)
+}
+
+func isLogType(component string) bool {
+	switch component {
+	case common.SegmentInsertLogPath, common.SegmentStatslogPath, common.SegmentDeltaLogPath, common.SegmentQuantizedLogPath:
+		return true
+	}
+	return false
+}
+
+// LogPathInfo is the binlog path parsed by ParseLogPath.
+type LogPathInfo struct {
+	// Root is the path before the log type, i.e. the root path with the tenant and the layout dir if any
+	Root         string
+	LogType      string
+	CollectionID typeutil.UniqueID
+	PartitionID  typeutil.UniqueID
+	SegmentID    typeutil.UniqueID
+	// FieldID is 0 for the delta logs
+	FieldID typeutil.UniqueID
+	LogID   typeutil.UniqueID
+}
+
+// ParseLogPath parses the binlog path from the tail, so the paths of the default layout, the tenants
+// and the path layouts are all parsed no matter what the root path is.
+func ParseLogPath(logPath string) (*LogPathInfo, bool) {
+	infos := strings.Split(logPath, pathSep)
+	l := len(infos)
+	parseIDs := func(idx int) ([]typeutil.UniqueID, bool) {
+		ids := make([]typeutil.UniqueID, 0, l-idx)
+		for _, s := range infos[idx:] {
+			id, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return nil, false
+			}
+			ids = append(ids, id)
+		}
+		return ids, true
+	}
+
+	if l >= 6 && infos[l-6] != common.SegmentDeltaLogPath && isLogType(infos[l-6]) {
+		ids, ok := parseIDs(l - 5)
+		if !ok {
+			return nil, false
+		}
+		return &LogPathInfo{
+			Root:         strings.Join(infos[:l-6], pathSep),
+			LogType:      infos[l-6],
+			CollectionID: ids[0],
+			PartitionID:  ids[1],
+			SegmentID:    ids[2],
+			FieldID:      ids[3],
+			LogID:        ids[4],
+		}, true
+	}
+	if l >= 5 && infos[l-5] == common.SegmentDeltaLogPath {
+		ids, ok := parseIDs(l - 4)
+		if !ok {
+			return nil, false
+		}
+		return &LogPathInfo{
+			Root:         strings.Join(infos[:l-5], pathSep),
+			LogType:      infos[l-5],
+			CollectionID: ids[0],
+			PartitionID:  ids[1],
+			SegmentID:    ids[2],
+			LogID:        ids[3],
+		}, true
+	}
+	return nil, false
+}
+
+// HasLayoutDir returns whether the binlog path is under the root path with a layout dir,
+// the paths of which can't be rebuilt from the ids. The paths not under the root path are taken as not.
+func HasLayoutDir(rootPath string, logPath string) bool {
+	info, ok := ParseLogPath(logPath)
+	if !ok {
+		return false
+	}
+	rootPath = strings.TrimSuffix(rootPath, pathSep)
+	if !strings.HasPrefix(info.Root, rootPath+pathSep) {
+		return false
+	}
+	for _, component := range strings.Split(strings.TrimPrefix(info.Root, rootPath+pathSep), pathSep) {
+		if component != "" && !strings.HasPrefix(component, tenantPrefix) {
+			return true
+		}
+	}
+	return false
+}
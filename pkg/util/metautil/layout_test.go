@@ -0,0 +1,83 @@
+package metautil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathLayout(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		layout, err := NewPathLayout("", "by-dev", "")
+		assert.NoError(t, err)
+		assert.Nil(t, layout)
+		assert.Equal(t, "", layout.Dir(1, time.Now()))
+		assert.Equal(t, "", layout.StaticDir())
+	})
+
+	t.Run("render", func(t *testing.T) {
+		layout, err := NewPathLayout("/{cluster}/{tier}/coll-{collection}/{yyyy-mm}/", "by-dev", "hot")
+		require.NoError(t, err)
+		ts := time.Date(2024, 3, 9, 10, 0, 0, 0, time.UTC)
+		assert.Equal(t, "by-dev/hot/coll-100/2024-03", layout.Dir(100, ts))
+		assert.Equal(t, "by-dev/hot", layout.StaticDir())
+
+		layout, err = NewPathLayout("data/{yyyy}/{mm}/{dd}/{yyyy-mm-dd}", "by-dev", "")
+		require.NoError(t, err)
+		assert.Equal(t, "data/2024/03/09/2024-03-09", layout.Dir(100, ts))
+		assert.Equal(t, "data", layout.StaticDir())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, template := range []string{
+			"{collection}/{cluster}",  // leading component not static
+			"{cluster}/{unknown}",     // unknown placeholder
+			"{cluster}/{tier}",        // empty tier
+			"{cluster}/../{yyyy}",     // relative component
+			"{cluster}/insert_log",    // conflicts with log type
+			"{cluster}/tenant=foo",    // conflicts with tenant
+			"{cluster}//{collection}", // empty component
+		} {
+			_, err := NewPathLayout(template, "by-dev", "")
+			assert.Error(t, err, template)
+		}
+	})
+}
+
+func TestParseLogPath(t *testing.T) {
+	info, ok := ParseLogPath("files/insert_log/1/2/3/4/5")
+	require.True(t, ok)
+	assert.Equal(t, LogPathInfo{Root: "files", LogType: "insert_log", CollectionID: 1, PartitionID: 2, SegmentID: 3, FieldID: 4, LogID: 5}, *info)
+
+	info, ok = ParseLogPath("files/tenant=t1/by-dev/1/2024-03/delta_log/1/2/3/5")
+	require.True(t, ok)
+	assert.Equal(t, LogPathInfo{Root: "files/tenant=t1/by-dev/1/2024-03", LogType: "delta_log", CollectionID: 1, PartitionID: 2, SegmentID: 3, LogID: 5}, *info)
+	assert.Equal(t, "t1", GetTenantFromLogPath("files/tenant=t1/by-dev/1/2024-03/delta_log/1/2/3/5"))
+
+	info, ok = ParseLogPath("files/by-dev/stats_log/1/2/3/4/5")
+	require.True(t, ok)
+	assert.Equal(t, "files/by-dev", info.Root)
+	assert.Equal(t, "", GetTenantFromLogPath("files/by-dev/stats_log/1/2/3/4/5"))
+
+	for _, logPath := range []string{
+		"files/insert_log/1/2/3/4",
+		"files/insert_log/1/2/3/4/a",
+		"files/index_files/1/2/3/4/5",
+		"delta_log/1/2",
+	} {
+		_, ok := ParseLogPath(logPath)
+		assert.False(t, ok, logPath)
+	}
+
+}
+
+func TestHasLayoutDir(t *testing.T) {
+	assert.False(t, HasLayoutDir("files", "files/insert_log/1/2/3/4/5"))
+	assert.False(t, HasLayoutDir("files", "files/tenant=t1/insert_log/1/2/3/4/5"))
+	assert.False(t, HasLayoutDir("files", "other/by-dev/insert_log/1/2/3/4/5"))
+	assert.False(t, HasLayoutDir("files", "files/by-dev/index_files/1/2/3/4/5"))
+	assert.True(t, HasLayoutDir("files/", "files/by-dev/insert_log/1/2/3/4/5"))
+	assert.True(t, HasLayoutDir("files", "files/tenant=t1/by-dev/1/delta_log/1/2/3/5"))
+}
@@ -89,12 +89,23 @@ type DataCoordQuotaMetrics struct {
 	CollectionBinlogSize map[int64]int64
 }
 
+// ChannelPressure contains the write pressure of a channel in DataNode.
+type ChannelPressure struct {
+	Channel      string
+	CollectionID int64
+	BufferSize   int64
+	PendingSyncs int
+	// Pressure is in [0, 1], 1 means the buffer or the sync queue of the channel is saturated
+	Pressure float64
+}
+
 // DataNodeQuotaMetrics are metrics of DataNode.
 type DataNodeQuotaMetrics struct {
-	Hms    HardwareMetrics
-	Rms    []RateMetric
-	Fgm    FlowGraphMetric
-	Effect NodeEffect
+	Hms      HardwareMetrics
+	Rms      []RateMetric
+	Fgm      FlowGraphMetric
+	Effect   NodeEffect
+	Channels []ChannelPressure
 }
 
 // ProxyQuotaMetrics are metrics of Proxy.
@@ -238,10 +238,15 @@ type commonConfig struct {
 	StorageScheme         ParamItem `refreshable:"false"`
 	EnableStorageV2       ParamItem `refreshable:"false"`
 	StoragePathPrefix     ParamItem `refreshable:"false"`
+	StoragePathTemplate   ParamItem `refreshable:"false"`
+	StorageTier           ParamItem `refreshable:"false"`
 	TTMsgEnabled          ParamItem `refreshable:"true"`
 	TraceLogMode          ParamItem `refreshable:"true"`
 	BloomFilterSize       ParamItem `refreshable:"true"`
 	MaxBloomFalsePositive ParamItem `refreshable:"true"`
+
+	PreflightEnabled ParamItem `refreshable:"false"`
+	PreflightTimeout ParamItem `refreshable:"false"`
 }
 
 func (p *commonConfig) init(base *BaseTable) {
@@ -517,7 +522,7 @@ This configuration is only used by querynode and indexnode, it selects CPU instr
 		Key:          "common.storageType",
 		Version:      "2.0.0",
 		DefaultValue: "remote",
-		Doc:          "please adjust in embedded Milvus: local, available values are [local, remote, opendal], value minio is deprecated, use remote instead",
+		Doc:          "please adjust in embedded Milvus: local, available values are [local, remote, opendal, azure, hdfs], value minio is deprecated, use remote instead, or the storage type of a backend compiled in as a plugin, which is configured by the minio section and rejected by the querynode and the indexnode, as their segcore can't access it",
 		Export:       true,
 	}
 	p.StorageType.Init(base.mgr)
@@ -691,6 +696,27 @@ like the old password verification when updating the credential`,
 	}
 	p.StoragePathPrefix.Init(base.mgr)
 
+	p.StoragePathTemplate = ParamItem{
+		Key:          "common.storage.pathTemplate",
+		Version:      "2.4.0",
+		DefaultValue: "",
+		Doc: "the template of the dir between the root path and the binlog type of the binlog paths written, " +
+			"e.g. {cluster}/{tier}/{collection}/{yyyy-mm}, the placeholders are {cluster}, {tier}, {collection}, " +
+			"{yyyy}, {mm}, {dd}, {yyyy-mm} and {yyyy-mm-dd}, the leading component shall not vary by the collection or the date. " +
+			"The binlogs written by the former templates are still read, empty for no dir",
+		Export: true,
+	}
+	p.StoragePathTemplate.Init(base.mgr)
+
+	p.StorageTier = ParamItem{
+		Key:          "common.storage.tier",
+		Version:      "2.4.0",
+		DefaultValue: "",
+		Doc:          "the storage tier rendered as {tier} of common.storage.pathTemplate",
+		Export:       true,
+	}
+	p.StorageTier.Init(base.mgr)
+
 	p.TTMsgEnabled = ParamItem{
 		Key:          "common.ttMsgEnabled",
 		Version:      "2.3.2",
@@ -722,6 +748,24 @@ like the old password verification when updating the credential`,
 		Doc:          "max false positive rate for bloom filter",
 	}
 	p.MaxBloomFalsePositive.Init(base.mgr)
+
+	p.PreflightEnabled = ParamItem{
+		Key:          "common.preflight.enabled",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc:          "Whether to validate the config and probe etcd, MQ and object storage before starting the components, the startup fails if any check fails",
+		Export:       true,
+	}
+	p.PreflightEnabled.Init(base.mgr)
+
+	p.PreflightTimeout = ParamItem{
+		Key:          "common.preflight.timeout",
+		Version:      "2.4.0",
+		DefaultValue: "30",
+		Doc:          "The timeout in seconds of each preflight check",
+		Export:       true,
+	}
+	p.PreflightTimeout.Init(base.mgr)
 }
 
 type gpuConfig struct {
@@ -1006,6 +1050,13 @@ type AccessLogConfig struct {
 	Formatter     ParamGroup `refreshable:"false"`
 }
 
+type ConnectorConfig struct {
+	Enable        ParamItem  `refreshable:"false"`
+	BatchSize     ParamItem  `refreshable:"false"`
+	FlushInterval ParamItem  `refreshable:"false"`
+	Sources       ParamGroup `refreshable:"false"`
+}
+
 type proxyConfig struct {
 	// Alias  string
 	SoPath ParamItem `refreshable:"false"`
@@ -1033,8 +1084,10 @@ type proxyConfig struct {
 	RetryTimesOnReplica          ParamItem `refreshable:"true"`
 	RetryTimesOnHealthCheck      ParamItem `refreshable:"true"`
 	PartitionNameRegexp          ParamItem `refreshable:"true"`
+	InsertChecksumEnabled        ParamItem `refreshable:"true"`
 
 	AccessLog AccessLogConfig
+	Connector ConnectorConfig
 }
 
 func (p *proxyConfig) init(base *BaseTable) {
@@ -1286,6 +1339,39 @@ please adjust in embedded Milvus: false`,
 	}
 	p.AccessLog.Formatter.Init(base.mgr)
 
+	p.Connector.Enable = ParamItem{
+		Key:          "proxy.connector.enable",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc:          "whether to consume the connector sources and insert the records into collections",
+		Export:       true,
+	}
+	p.Connector.Enable.Init(base.mgr)
+
+	p.Connector.BatchSize = ParamItem{
+		Key:          "proxy.connector.batchSize",
+		Version:      "2.4.0",
+		DefaultValue: "1000",
+		Doc:          "the max number of records inserted by one request of a connector",
+		Export:       true,
+	}
+	p.Connector.BatchSize.Init(base.mgr)
+
+	p.Connector.FlushInterval = ParamItem{
+		Key:          "proxy.connector.flushInterval",
+		Version:      "2.4.0",
+		DefaultValue: "1000",
+		Doc:          "ms, the max interval between a record received and inserted by a connector",
+		Export:       true,
+	}
+	p.Connector.FlushInterval.Init(base.mgr)
+
+	p.Connector.Sources = ParamGroup{
+		KeyPrefix: "proxy.connector.sources.",
+		Version:   "2.4.0",
+	}
+	p.Connector.Sources.Init(base.mgr)
+
 	p.ShardLeaderCacheInterval = ParamItem{
 		Key:          "proxy.shardLeaderCacheInterval",
 		Version:      "2.2.4",
@@ -1341,6 +1427,15 @@ please adjust in embedded Milvus: false`,
 		Doc:          "switch for whether proxy shall use partition name as regexp when searching",
 	}
 	p.PartitionNameRegexp.Init(base.mgr)
+
+	p.InsertChecksumEnabled = ParamItem{
+		Key:          "proxy.insertChecksumEnabled",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc:          "whether to carry the checksum of each insert message, which is verified by datanodes to detect corruptions in the message stream",
+		Export:       true,
+	}
+	p.InsertChecksumEnabled.Init(base.mgr)
 }
 
 // /////////////////////////////////////////////////////////////////////////////
@@ -2389,6 +2484,10 @@ type dataCoordConfig struct {
 	ChannelCheckInterval         ParamItem `refreshable:"true"`
 	ChannelOperationRPCTimeout   ParamItem `refreshable:"true"`
 
+	// --- PLACEMENT ---
+	PlacementSoPath ParamItem  `refreshable:"false"`
+	PlacementParams ParamGroup `refreshable:"false"`
+
 	// --- SEGMENTS ---
 	SegmentMaxSize                 ParamItem `refreshable:"false"`
 	DiskSegmentMaxSize             ParamItem `refreshable:"true"`
@@ -2400,6 +2499,8 @@ type dataCoordConfig struct {
 	SegmentMinSizeFromIdleToSealed ParamItem `refreshable:"false"`
 	SegmentMaxBinlogFileNumber     ParamItem `refreshable:"false"`
 	AutoUpgradeSegmentIndex        ParamItem `refreshable:"true"`
+	SegmentExpirationEnabled       ParamItem `refreshable:"true"`
+	SegmentExpirationCheckInterval ParamItem `refreshable:"false"`
 
 	// compaction
 	EnableCompaction     ParamItem `refreshable:"false"`
@@ -2417,26 +2518,54 @@ type dataCoordConfig struct {
 	CompactionTimeoutInSeconds        ParamItem `refreshable:"true"`
 	CompactionCheckIntervalInSeconds  ParamItem `refreshable:"false"`
 	SingleCompactionRatioThreshold    ParamItem `refreshable:"true"`
+	SingleCompactionDeleteRatio       ParamItem `refreshable:"true"`
 	SingleCompactionDeltaLogMaxSize   ParamItem `refreshable:"true"`
 	SingleCompactionExpiredLogMaxSize ParamItem `refreshable:"true"`
 	SingleCompactionDeltalogMaxNum    ParamItem `refreshable:"true"`
 	GlobalCompactionInterval          ParamItem `refreshable:"false"`
 
+	// re-encode segments of outdated encodings
+	ReencodeEnabled        ParamItem `refreshable:"true"`
+	ReencodeCheckInterval  ParamItem `refreshable:"false"`
+	ReencodeInspectBatch   ParamItem `refreshable:"true"`
+	ReencodeMaxConcurrency ParamItem `refreshable:"true"`
+
+	// preempt the low priority compactions under flush pressure
+	CompactionPreemptionFlushingThreshold ParamItem `refreshable:"true"`
+	CompactionPreemptionMaxTimes          ParamItem `refreshable:"true"`
+	CompactionStealingEnabled             ParamItem `refreshable:"true"`
+
+	// major compaction merging all the sealed segments of a partition
+	MajorCompactionMaxConcurrency ParamItem `refreshable:"true"`
+	MajorCompactionCheckInterval  ParamItem `refreshable:"false"`
+
+	// clustering compaction re-partitioning the rows by the clustering field
+	ClusteringCompactionEnabled      ParamItem `refreshable:"true"`
+	ClusteringCompactionMaxInputSize ParamItem `refreshable:"true"`
+
 	// LevelZero Segment
 	EnableLevelZeroSegment                   ParamItem `refreshable:"false"`
 	LevelZeroCompactionTriggerMinSize        ParamItem `refreshable:"true"`
 	LevelZeroCompactionTriggerMaxSize        ParamItem `refreshable:"true"`
 	LevelZeroCompactionTriggerDeltalogMinNum ParamItem `refreshable:"true"`
 	LevelZeroCompactionTriggerDeltalogMaxNum ParamItem `refreshable:"true"`
+	LevelZeroCompactionTriggerMaxIdleTime    ParamItem `refreshable:"true"`
 
 	// Garbage Collection
 	EnableGarbageCollection ParamItem `refreshable:"false"`
 	GCInterval              ParamItem `refreshable:"false"`
 	GCMissingTolerance      ParamItem `refreshable:"false"`
 	GCDropTolerance         ParamItem `refreshable:"false"`
+	GCLifecycleRules        ParamItem `refreshable:"false"`
+	GCLifecycleMaxRules     ParamItem `refreshable:"false"`
 	GCRemoveConcurrent      ParamItem `refreshable:"false"`
 	EnableActiveStandby     ParamItem `refreshable:"false"`
 
+	GCReconcileInterval           ParamItem `refreshable:"true"`
+	GCReconcileAutoDelete         ParamItem `refreshable:"true"`
+	GCReconcileSafetyWindow       ParamItem `refreshable:"true"`
+	GCReconcileMaxReportedObjects ParamItem `refreshable:"true"`
+
 	BindIndexNodeMode          ParamItem `refreshable:"false"`
 	IndexNodeAddress           ParamItem `refreshable:"false"`
 	WithCredential             ParamItem `refreshable:"false"`
@@ -2449,6 +2578,20 @@ type dataCoordConfig struct {
 	// auto balance channel on datanode
 	AutoBalance                    ParamItem `refreshable:"true"`
 	CheckAutoBalanceConfigInterval ParamItem `refreshable:"false"`
+
+	// export
+	ExportConcurrency ParamItem `refreshable:"false"`
+
+	// decommission
+	DecommissionCheckInterval ParamItem `refreshable:"false"`
+
+	// segment events
+	SegmentEventEnabled   ParamItem `refreshable:"true"`
+	SegmentEventRetention ParamItem `refreshable:"true"`
+
+	// warm standby
+	WarmStandbyEnabled      ParamItem `refreshable:"false"`
+	WarmStandbySyncInterval ParamItem `refreshable:"false"`
 }
 
 func (p *dataCoordConfig) init(base *BaseTable) {
@@ -2497,6 +2640,22 @@ func (p *dataCoordConfig) init(base *BaseTable) {
 	}
 	p.ChannelOperationRPCTimeout.Init(base.mgr)
 
+	p.PlacementSoPath = ParamItem{
+		Key:          "dataCoord.placement.soPath",
+		Version:      "2.4.0",
+		DefaultValue: "",
+		Doc: `The path of the go plugin customizing the placement of the channels and the segments on the datanodes,
+the default placement if empty`,
+		Export: true,
+	}
+	p.PlacementSoPath.Init(base.mgr)
+
+	p.PlacementParams = ParamGroup{
+		KeyPrefix: "dataCoord.placement.params.",
+		Version:   "2.4.0",
+	}
+	p.PlacementParams.Init(base.mgr)
+
 	p.SegmentMaxSize = ParamItem{
 		Key:          "dataCoord.segment.maxSize",
 		Version:      "2.0.0",
@@ -2580,6 +2739,24 @@ the number of binlog file reaches to max value.`,
 	}
 	p.SegmentMaxBinlogFileNumber.Init(base.mgr)
 
+	p.SegmentExpirationEnabled = ParamItem{
+		Key:          "dataCoord.segment.expiration.enabled",
+		Version:      "2.4.0",
+		DefaultValue: "true",
+		Doc:          "Whether to drop the flushed segments whose rows are all expired by the ttl of the collection or of the partition",
+		Export:       true,
+	}
+	p.SegmentExpirationEnabled.Init(base.mgr)
+
+	p.SegmentExpirationCheckInterval = ParamItem{
+		Key:          "dataCoord.segment.expiration.checkInterval",
+		Version:      "2.4.0",
+		DefaultValue: "60",
+		Doc:          "The interval in seconds of checking the segments expired by the ttl",
+		Export:       true,
+	}
+	p.SegmentExpirationCheckInterval.Init(base.mgr)
+
 	p.EnableCompaction = ParamItem{
 		Key:          "dataCoord.enableCompaction",
 		Version:      "2.0.0",
@@ -2694,6 +2871,16 @@ During compaction, the size of segment # of rows is able to exceed segment max #
 	}
 	p.SingleCompactionRatioThreshold.Init(base.mgr)
 
+	p.SingleCompactionDeleteRatio = ParamItem{
+		Key:          "dataCoord.compaction.single.deleteRatio.threshold",
+		Version:      "2.4.0",
+		DefaultValue: "0.2",
+		FallbackKeys: []string{"dataCoord.compaction.single.ratio.threshold"},
+		Doc:          "The ratio of the deleted rows to the rows of a segment, over which the segment is rewritten alone by a compaction to reclaim the space",
+		Export:       true,
+	}
+	p.SingleCompactionDeleteRatio.Init(base.mgr)
+
 	p.SingleCompactionDeltaLogMaxSize = ParamItem{
 		Key:          "dataCoord.compaction.single.deltalog.maxsize",
 		Version:      "2.0.0",
@@ -2722,6 +2909,108 @@ During compaction, the size of segment # of rows is able to exceed segment max #
 	}
 	p.GlobalCompactionInterval.Init(base.mgr)
 
+	p.ReencodeEnabled = ParamItem{
+		Key:          "dataCoord.compaction.reencode.enabled",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc:          "Whether to rewrite the segments of outdated encodings into the current format by compaction in the background",
+		Export:       true,
+	}
+	p.ReencodeEnabled.Init(base.mgr)
+
+	p.ReencodeCheckInterval = ParamItem{
+		Key:          "dataCoord.compaction.reencode.checkInterval",
+		Version:      "2.4.0",
+		DefaultValue: "300",
+		Doc:          "The interval in seconds of inspecting segment encodings and triggering the re-encode compactions",
+		Export:       true,
+	}
+	p.ReencodeCheckInterval.Init(base.mgr)
+
+	p.ReencodeInspectBatch = ParamItem{
+		Key:          "dataCoord.compaction.reencode.inspectBatch",
+		Version:      "2.4.0",
+		DefaultValue: "20",
+		Doc:          "The maximum number of segments whose binlogs are inspected in each interval",
+		Export:       true,
+	}
+	p.ReencodeInspectBatch.Init(base.mgr)
+
+	p.ReencodeMaxConcurrency = ParamItem{
+		Key:          "dataCoord.compaction.reencode.maxConcurrency",
+		Version:      "2.4.0",
+		DefaultValue: "1",
+		Doc:          "The maximum number of re-encode compactions running at the same time",
+		Export:       true,
+	}
+	p.ReencodeMaxConcurrency.Init(base.mgr)
+
+	p.CompactionPreemptionFlushingThreshold = ParamItem{
+		Key:          "dataCoord.compaction.preemption.flushingSegmentsThreshold",
+		Version:      "2.4.0",
+		DefaultValue: "32",
+		Doc: `The number of the flushing segments of the channels watched by a datanode, over which the datanode is under flush pressure,
+the merge compactions executing on it are preempted and the queuing ones are held until the pressure falls, 0 means never preempt`,
+		Export: true,
+	}
+	p.CompactionPreemptionFlushingThreshold.Init(base.mgr)
+
+	p.CompactionPreemptionMaxTimes = ParamItem{
+		Key:          "dataCoord.compaction.preemption.maxTimes",
+		Version:      "2.4.0",
+		DefaultValue: "3",
+		Doc:          "The maximum number of times a merge compaction is preempted, after which it's neither preempted nor held under flush pressure",
+		Export:       true,
+	}
+	p.CompactionPreemptionMaxTimes.Init(base.mgr)
+
+	p.CompactionStealingEnabled = ParamItem{
+		Key:          "dataCoord.compaction.stealing.enabled",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc: `Whether the idle datanodes steal the merge compactions queuing on the datanodes at the parallel limit,
+the stolen compactions read and write the binlogs through the object storage without the channels watched`,
+		Export: true,
+	}
+	p.CompactionStealingEnabled.Init(base.mgr)
+
+	p.MajorCompactionMaxConcurrency = ParamItem{
+		Key:          "dataCoord.compaction.major.maxConcurrency",
+		Version:      "2.4.0",
+		DefaultValue: "2",
+		Doc:          "The maximum number of compactions of a major compaction job running at the same time",
+		Export:       true,
+	}
+	p.MajorCompactionMaxConcurrency.Init(base.mgr)
+
+	p.MajorCompactionCheckInterval = ParamItem{
+		Key:          "dataCoord.compaction.major.checkInterval",
+		Version:      "2.4.0",
+		DefaultValue: "10",
+		Doc:          "The interval in seconds of checking the progress of the major compaction jobs and submitting their compactions",
+		Export:       true,
+	}
+	p.MajorCompactionCheckInterval.Init(base.mgr)
+
+	p.ClusteringCompactionEnabled = ParamItem{
+		Key:          "dataCoord.compaction.clustering.enable",
+		Version:      "2.4.0",
+		DefaultValue: "true",
+		Doc: `Whether the major compaction jobs of the collections with the clustering field re-partition the rows by the field,
+it requires dataCoord.segment.enableLevelZero`,
+		Export: true,
+	}
+	p.ClusteringCompactionEnabled.Init(base.mgr)
+
+	p.ClusteringCompactionMaxInputSize = ParamItem{
+		Key:          "dataCoord.compaction.clustering.maxInputSize",
+		Version:      "2.4.0",
+		DefaultValue: "4096",
+		Doc:          "The maximum size in MB of the segments of a clustering compaction, whose rows are sorted in the memory of the datanode",
+		Export:       true,
+	}
+	p.ClusteringCompactionMaxInputSize.Init(base.mgr)
+
 	// LevelZeroCompaction
 	p.EnableLevelZeroSegment = ParamItem{
 		Key:          "dataCoord.segment.enableLevelZero",
@@ -2763,6 +3052,15 @@ During compaction, the size of segment # of rows is able to exceed segment max #
 	}
 	p.LevelZeroCompactionTriggerDeltalogMaxNum.Init(base.mgr)
 
+	p.LevelZeroCompactionTriggerMaxIdleTime = ParamItem{
+		Key:          "dataCoord.compaction.levelzero.forceTrigger.maxIdleTime",
+		Version:      "2.4.0",
+		Doc:          "The maximum time in seconds the deletes stay in the LevelZero segments below the minimum size and number, after which a LevelZero Compaction is forced, 0 means never",
+		DefaultValue: "1800",
+		Export:       true,
+	}
+	p.LevelZeroCompactionTriggerMaxIdleTime.Init(base.mgr)
+
 	p.EnableGarbageCollection = ParamItem{
 		Key:          "dataCoord.enableGarbageCollection",
 		Version:      "2.0.0",
@@ -2800,6 +3098,24 @@ During compaction, the size of segment # of rows is able to exceed segment max #
 	}
 	p.GCDropTolerance.Init(base.mgr)
 
+	p.GCLifecycleRules = ParamItem{
+		Key:          "dataCoord.gc.lifecycleRules",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc:          "Whether to expire the binlogs of the dropped collections by the lifecycle rules of the bucket rather than removing them one by one, only the S3 compatible storages and the native GCS support it",
+		Export:       true,
+	}
+	p.GCLifecycleRules.Init(base.mgr)
+
+	p.GCLifecycleMaxRules = ParamItem{
+		Key:          "dataCoord.gc.lifecycleMaxRules",
+		Version:      "2.4.0",
+		DefaultValue: "100",
+		Doc:          "The max number of the lifecycle rules installed, the binlogs of the dropped collections beyond it are removed one by one",
+		Export:       true,
+	}
+	p.GCLifecycleMaxRules.Init(base.mgr)
+
 	p.GCRemoveConcurrent = ParamItem{
 		Key:          "dataCoord.gc.removeConcurrent",
 		Version:      "2.3.4",
@@ -2809,6 +3125,42 @@ During compaction, the size of segment # of rows is able to exceed segment max #
 	}
 	p.GCRemoveConcurrent.Init(base.mgr)
 
+	p.GCReconcileInterval = ParamItem{
+		Key:          "dataCoord.gc.reconcile.interval",
+		Version:      "2.4.0",
+		DefaultValue: "0",
+		Doc:          "The interval in seconds of reconciling the object storage against the meta and reporting the orphan and the missing objects, 0 means reconciling on request only",
+		Export:       true,
+	}
+	p.GCReconcileInterval.Init(base.mgr)
+
+	p.GCReconcileAutoDelete = ParamItem{
+		Key:          "dataCoord.gc.reconcile.autoDelete",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc:          "Whether to remove the orphan objects older than the safety window found by the periodic reconciliation",
+		Export:       true,
+	}
+	p.GCReconcileAutoDelete.Init(base.mgr)
+
+	p.GCReconcileSafetyWindow = ParamItem{
+		Key:          "dataCoord.gc.reconcile.safetyWindow",
+		Version:      "2.4.0",
+		DefaultValue: "86400",
+		Doc:          "The duration in seconds since the last modification, after which the orphan objects are removed by the reconciliation",
+		Export:       true,
+	}
+	p.GCReconcileSafetyWindow.Init(base.mgr)
+
+	p.GCReconcileMaxReportedObjects = ParamItem{
+		Key:          "dataCoord.gc.reconcile.maxReportedObjects",
+		Version:      "2.4.0",
+		DefaultValue: "100",
+		Doc:          "The max number of the orphan and of the missing objects listed in the reconciliation report, the totals count all of them",
+		Export:       true,
+	}
+	p.GCReconcileMaxReportedObjects.Init(base.mgr)
+
 	p.EnableActiveStandby = ParamItem{
 		Key:          "dataCoord.enableActiveStandby",
 		Version:      "2.0.0",
@@ -2903,6 +3255,60 @@ During compaction, the size of segment # of rows is able to exceed segment max #
 		Export:       true,
 	}
 	p.AutoUpgradeSegmentIndex.Init(base.mgr)
+
+	p.ExportConcurrency = ParamItem{
+		Key:          "dataCoord.export.concurrency",
+		Version:      "2.4.0",
+		DefaultValue: "2",
+		Doc:          "The maximum number of export jobs running at the same time",
+		Export:       true,
+	}
+	p.ExportConcurrency.Init(base.mgr)
+
+	p.DecommissionCheckInterval = ParamItem{
+		Key:          "dataCoord.decommission.checkInterval",
+		Version:      "2.4.0",
+		DefaultValue: "3",
+		Doc:          "The interval in seconds of draining the decommissioning datanodes and indexnodes",
+		Export:       true,
+	}
+	p.DecommissionCheckInterval.Init(base.mgr)
+
+	p.SegmentEventEnabled = ParamItem{
+		Key:          "dataCoord.segmentEvent.enabled",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc:          "Whether to persist the lifecycle events of segments, which could be queried for post-incident analysis",
+		Export:       true,
+	}
+	p.SegmentEventEnabled.Init(base.mgr)
+
+	p.SegmentEventRetention = ParamItem{
+		Key:          "dataCoord.segmentEvent.retention",
+		Version:      "2.4.0",
+		DefaultValue: "604800",
+		Doc:          "The retention duration in seconds of the segment events, the expired events are removed by garbage collector",
+		Export:       true,
+	}
+	p.SegmentEventRetention.Init(base.mgr)
+
+	p.WarmStandbyEnabled = ParamItem{
+		Key:          "dataCoord.warmStandby.enabled",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc:          "Whether the standby DataCoord preloads the meta and keeps it in sync, so that the failover needs no full meta reload. Only works with enableActiveStandby and the etcd meta store",
+		Export:       true,
+	}
+	p.WarmStandbyEnabled.Init(base.mgr)
+
+	p.WarmStandbySyncInterval = ParamItem{
+		Key:          "dataCoord.warmStandby.syncInterval",
+		Version:      "2.4.0",
+		DefaultValue: "5",
+		Doc:          "The interval in seconds of the warm standby DataCoord applying the meta changes",
+		Export:       true,
+	}
+	p.WarmStandbySyncInterval.Init(base.mgr)
 }
 
 // /////////////////////////////////////////////////////////////////////////////
@@ -2913,6 +3319,13 @@ type dataNodeConfig struct {
 	MaxParallelSyncTaskNum  ParamItem `refreshable:"false"`
 	MaxParallelSyncMgrTasks ParamItem `refreshable:"true"`
 
+	// back pressure
+	MaxPendingSyncTasksPerChannel ParamItem `refreshable:"true"`
+
+	// adaptive sync
+	AdaptiveSyncEnabled       ParamItem `refreshable:"true"`
+	AdaptiveSyncTargetLatency ParamItem `refreshable:"true"`
+
 	// skip mode
 	FlowGraphSkipModeEnable   ParamItem `refreshable:"true"`
 	FlowGraphSkipModeSkipNum  ParamItem `refreshable:"true"`
@@ -2921,9 +3334,20 @@ type dataNodeConfig struct {
 	// segment
 	FlushInsertBufferSize  ParamItem `refreshable:"true"`
 	FlushDeleteBufferBytes ParamItem `refreshable:"true"`
+	DeltalogChunkSize      ParamItem `refreshable:"true"`
+	DeltalogDedupEnabled   ParamItem `refreshable:"true"`
 	BinLogMaxSize          ParamItem `refreshable:"true"`
+	ParquetRowGroupRows    ParamItem `refreshable:"true"`
+	DictionaryCardinality  ParamItem `refreshable:"true"`
+	DeltalogBitmapEnabled  ParamItem `refreshable:"true"`
+	DeltalogSortedEnabled  ParamItem `refreshable:"true"`
+	CompactionSortMaxSize  ParamItem `refreshable:"true"`
 	SyncPeriod             ParamItem `refreshable:"true"`
 
+	// thresholds of the sync policies besides the size and the age of the segment buffers
+	ChannelMemoryWatermark ParamItem `refreshable:"true"`
+	MaxCheckpointLag       ParamItem `refreshable:"true"`
+
 	// watchEvent
 	WatchEventTicklerInterval ParamItem `refreshable:"false"`
 
@@ -2933,11 +3357,46 @@ type dataNodeConfig struct {
 	// Concurrency to handle compaction file read
 	FileReadConcurrency ParamItem `refreshable:"false"`
 
+	// chunked upload of binlogs
+	UploadPartSize    ParamItem `refreshable:"true"`
+	UploadConcurrency ParamItem `refreshable:"true"`
+	UploadMaxInflight ParamItem `refreshable:"true"`
+	UploadManifest    ParamItem `refreshable:"true"`
+	UploadInventory   ParamItem `refreshable:"true"`
+
+	// id ranges leased from the allocator
+	IDLeaseSize ParamItem `refreshable:"true"`
+	IDLeaseTTL  ParamItem `refreshable:"true"`
+
+	// stats of the scalar fields in the statslogs
+	FieldStatsEnabled ParamItem `refreshable:"true"`
+	HLLPrecision      ParamItem `refreshable:"true"`
+
+	// bandwidth budgets of the binlog io
+	ReadBandwidthLimitMB  ParamItem `refreshable:"true"`
+	WriteBandwidthLimitMB ParamItem `refreshable:"true"`
+
+	// local disk cache of the binlogs downloaded
+	BinlogCacheEnabled    ParamItem `refreshable:"false"`
+	BinlogCachePath       ParamItem `refreshable:"false"`
+	BinlogCacheCapacityMB ParamItem `refreshable:"false"`
+	BinlogCacheTTL        ParamItem `refreshable:"false"`
+
+	// bounds of the binlog io retries by the operation class
+	FlushMaxRetryDuration      ParamItem `refreshable:"true"`
+	FlushMaxRetryCount         ParamItem `refreshable:"true"`
+	CompactionMaxRetryDuration ParamItem `refreshable:"true"`
+	CompactionMaxRetryCount    ParamItem `refreshable:"true"`
+
+	// read-ahead of the compaction downloads
+	CompactionPrefetchDepth ParamItem `refreshable:"true"`
+
 	// memory management
 	MemoryForceSyncEnable     ParamItem `refreshable:"true"`
 	MemoryForceSyncSegmentNum ParamItem `refreshable:"true"`
 	MemoryCheckInterval       ParamItem `refreshable:"true"`
 	MemoryWatermark           ParamItem `refreshable:"true"`
+	MemoryBudgetRatio         ParamItem `refreshable:"true"`
 
 	DataNodeTimeTickByRPC ParamItem `refreshable:"false"`
 	// DataNode send timetick interval per collection
@@ -2957,6 +3416,7 @@ type dataNodeConfig struct {
 	UpdateChannelCheckpointMaxParallel ParamItem `refreshable:"true"`
 	UpdateChannelCheckpointInterval    ParamItem `refreshable:"true"`
 	UpdateChannelCheckpointRPCTimeout  ParamItem `refreshable:"true"`
+	ChannelLazyLoadStatsEnabled        ParamItem `refreshable:"true"`
 
 	MaxConcurrentImportTaskNum ParamItem `refreshable:"true"`
 
@@ -3031,6 +3491,34 @@ func (p *dataNodeConfig) init(base *BaseTable) {
 	}
 	p.MaxParallelSyncMgrTasks.Init(base.mgr)
 
+	p.MaxPendingSyncTasksPerChannel = ParamItem{
+		Key:          "dataNode.dataSync.maxPendingSyncTasksPerChannel",
+		Version:      "2.4.0",
+		DefaultValue: "32",
+		Doc:          "Max number of the unfinished sync tasks of a single channel, the channel is reported fully back pressured to the quota center once it's reached. 0 means unlimited",
+		Export:       true,
+	}
+	p.MaxPendingSyncTasksPerChannel.Init(base.mgr)
+
+	p.AdaptiveSyncEnabled = ParamItem{
+		Key:          "dataNode.dataSync.adaptiveSync.enabled",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc: `Whether to adapt the segment sync size and concurrency to the measured latency of the object storage writes,
+smaller and more parallel syncs when the storage is slow, larger syncs when it is fast`,
+		Export: true,
+	}
+	p.AdaptiveSyncEnabled.Init(base.mgr)
+
+	p.AdaptiveSyncTargetLatency = ParamItem{
+		Key:          "dataNode.dataSync.adaptiveSync.targetLatency",
+		Version:      "2.4.0",
+		DefaultValue: "1000",
+		Doc:          "The expected latency of writing the files of a sync task in milliseconds, the sync size and concurrency are adapted towards it",
+		Export:       true,
+	}
+	p.AdaptiveSyncTargetLatency.Init(base.mgr)
+
 	p.FlushInsertBufferSize = ParamItem{
 		Key:          "dataNode.segment.insertBufSize",
 		Version:      "2.0.0",
@@ -3087,6 +3575,16 @@ func (p *dataNodeConfig) init(base *BaseTable) {
 	}
 	p.MemoryWatermark.Init(base.mgr)
 
+	p.MemoryBudgetRatio = ParamItem{
+		Key:          "datanode.memory.budgetRatio",
+		Version:      "2.4.0",
+		DefaultValue: "0.7",
+		Doc: `ratio of the memory shared by insert buffers, delete buffers, compaction and serialization,
+reservations of compaction and serialization beyond it are denied until memory is released, 0 means unlimited`,
+		Export: true,
+	}
+	p.MemoryBudgetRatio.Init(base.mgr)
+
 	p.FlushDeleteBufferBytes = ParamItem{
 		Key:          "dataNode.segment.deleteBufBytes",
 		Version:      "2.0.0",
@@ -3096,6 +3594,24 @@ func (p *dataNodeConfig) init(base *BaseTable) {
 	}
 	p.FlushDeleteBufferBytes.Init(base.mgr)
 
+	p.DeltalogChunkSize = ParamItem{
+		Key:          "dataNode.segment.deltalogChunkSize",
+		Version:      "2.4.0",
+		DefaultValue: "67108864",
+		Doc:          "Max size in bytes of the delete data serialized into a single deltalog, larger delete data is split into multiple deltalogs",
+		Export:       true,
+	}
+	p.DeltalogChunkSize.Init(base.mgr)
+
+	p.DeltalogDedupEnabled = ParamItem{
+		Key:          "dataNode.segment.deltalogDedup",
+		Version:      "2.4.0",
+		DefaultValue: "true",
+		Doc:          "Whether to keep the latest delete of each primary key only when the delete data is serialized into a deltalog",
+		Export:       true,
+	}
+	p.DeltalogDedupEnabled.Init(base.mgr)
+
 	p.BinLogMaxSize = ParamItem{
 		Key:          "dataNode.segment.binlog.maxsize",
 		Version:      "2.0.0",
@@ -3103,6 +3619,52 @@ func (p *dataNodeConfig) init(base *BaseTable) {
 	}
 	p.BinLogMaxSize.Init(base.mgr)
 
+	p.ParquetRowGroupRows = ParamItem{
+		Key:          "dataNode.segment.binlog.parquetRowGroupRows",
+		Version:      "2.4.0",
+		DefaultValue: "65536",
+		Doc:          "The max number of rows of a row group in the binlog of the collection in parquet binlog format",
+		Export:       true,
+	}
+	p.ParquetRowGroupRows.Init(base.mgr)
+
+	p.DictionaryCardinality = ParamItem{
+		Key:          "dataNode.segment.binlog.dictionaryCardinality",
+		Version:      "2.4.0",
+		DefaultValue: "1024",
+		Doc:          "The max number of the distinct values of a VarChar field in a binlog to be dictionary encoded if the encoding of the field is not set, the fields of more distinct values are plain encoded. 0 to leave it to the parquet writer",
+		Export:       true,
+	}
+	p.DictionaryCardinality.Init(base.mgr)
+
+	p.DeltalogBitmapEnabled = ParamItem{
+		Key:          "dataNode.segment.binlog.deltalogBitmap",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc:          "Whether to record the deletes of sealed segments by the offsets of the deleted rows at level zero compaction, which are applied on load without looking up the primary keys. Enable it after all the query nodes are able to read them",
+		Export:       true,
+	}
+	p.DeltalogBitmapEnabled.Init(base.mgr)
+
+	p.DeltalogSortedEnabled = ParamItem{
+		Key:          "dataNode.segment.binlog.deltalogSorted",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc:          "Whether to write the deltalogs sorted by the primary keys with a sparse index of them, by which the latest delete of a primary key is looked up by binary search. Enable it after all the nodes are able to read them",
+		Export:       true,
+	}
+	p.DeltalogSortedEnabled.Init(base.mgr)
+
+	p.CompactionSortMaxSize = ParamItem{
+		Key:          "dataNode.segment.compactionSortMaxSize",
+		Version:      "2.4.0",
+		DefaultValue: "268435456",
+		Doc: `Max size in bytes of the insert binlogs of the segments not sorted by the primary keys, which are sorted in memory
+to be merged by the primary keys by the mix compaction. The segments are merged one after another without sorted once exceeded`,
+		Export: true,
+	}
+	p.CompactionSortMaxSize.Init(base.mgr)
+
 	p.SyncPeriod = ParamItem{
 		Key:          "dataNode.segment.syncPeriod",
 		Version:      "2.0.0",
@@ -3112,6 +3674,24 @@ func (p *dataNodeConfig) init(base *BaseTable) {
 	}
 	p.SyncPeriod.Init(base.mgr)
 
+	p.ChannelMemoryWatermark = ParamItem{
+		Key:          "dataNode.segment.channelMemoryWatermark",
+		Version:      "2.4.0",
+		DefaultValue: "0",
+		Doc:          "Max size in bytes of the buffers of a single channel, the largest segment buffers of the channel are synced once it's exceeded. 0 means unlimited",
+		Export:       true,
+	}
+	p.ChannelMemoryWatermark.Init(base.mgr)
+
+	p.MaxCheckpointLag = ParamItem{
+		Key:          "dataNode.segment.maxCheckpointLag",
+		Version:      "2.4.0",
+		DefaultValue: "0",
+		Doc:          "Max lag in seconds of the checkpoint of a channel behind the current time, the segment buffers holding the checkpoint back further are synced. It bounds the data to replay on recovery while the channel is catching up, at the cost of smaller binlogs. 0 means unlimited",
+		Export:       true,
+	}
+	p.MaxCheckpointLag.Init(base.mgr)
+
 	p.WatchEventTicklerInterval = ParamItem{
 		Key:          "datanode.segment.watchEventTicklerInterval",
 		Version:      "2.2.3",
@@ -3133,6 +3713,201 @@ func (p *dataNodeConfig) init(base *BaseTable) {
 	}
 	p.FileReadConcurrency.Init(base.mgr)
 
+	p.UploadPartSize = ParamItem{
+		Key:          "dataNode.upload.partSize",
+		Version:      "2.4.0",
+		DefaultValue: "67108864",
+		Doc: `The max size in bytes of the binlogs written by a single request, larger uploads are split into parts
+uploaded concurrently, 0 means uploading the binlogs in a single request`,
+		Export: true,
+	}
+	p.UploadPartSize.Init(base.mgr)
+
+	p.UploadConcurrency = ParamItem{
+		Key:          "dataNode.upload.concurrency",
+		Version:      "2.4.0",
+		DefaultValue: "4",
+		Doc:          "The max number of parts of an upload written concurrently",
+		Export:       true,
+	}
+	p.UploadConcurrency.Init(base.mgr)
+
+	p.UploadMaxInflight = ParamItem{
+		Key:          "dataNode.upload.maxInflightSize",
+		Version:      "2.4.0",
+		DefaultValue: "268435456",
+		Doc: `The max size in bytes of the binlogs uploaded concurrently by the flush and compaction of the datanode,
+the uploads beyond it are queued and the flush ones go first, 0 means unlimited`,
+		Export: true,
+	}
+	p.UploadMaxInflight.Init(base.mgr)
+
+	p.UploadManifest = ParamItem{
+		Key:          "dataNode.upload.manifestEnabled",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc: `Whether to write a manifest of the binlogs before uploading them in a group, which is removed once they are
+all uploaded, so the binlogs of the incomplete uploads are recycled by the garbage collection at once`,
+		Export: true,
+	}
+	p.UploadManifest.Init(base.mgr)
+
+	p.UploadInventory = ParamItem{
+		Key:          "dataNode.upload.inventoryEnabled",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc: `Whether to upload an inventory of the binlogs along with each group of the binlogs of a segment, which lists
+the paths, sizes, checksums and row counts of them, so the backup tools read the binlogs of a segment from the storage alone. The inventories of the dropped segments
+are removed by the datacoord only while it's enabled`,
+		Export: true,
+	}
+	p.UploadInventory.Init(base.mgr)
+
+	p.IDLeaseSize = ParamItem{
+		Key:          "dataNode.idLease.size",
+		Version:      "2.4.0",
+		DefaultValue: "10000",
+		Doc: `The number of the ids leased from the rootcoord at once, the log ids are handed out of the lease locally
+and the next lease is renewed in the background ahead of the exhaustion, 0 means no lease`,
+		Export: true,
+	}
+	p.IDLeaseSize.Init(base.mgr)
+
+	p.IDLeaseTTL = ParamItem{
+		Key:          "dataNode.idLease.ttl",
+		Version:      "2.4.0",
+		DefaultValue: "600",
+		Doc: `The seconds a lease of the ids is used for, the rest of the lease expired is dropped, so the ids handed out
+don't fall far behind the ones allocated by the rootcoord`,
+		Export: true,
+	}
+	p.IDLeaseTTL.Init(base.mgr)
+
+	p.FieldStatsEnabled = ParamItem{
+		Key:          "dataNode.stats.fieldStatsEnabled",
+		Version:      "2.4.0",
+		DefaultValue: "true",
+		Doc: `Whether to write the min/max and the HyperLogLog cardinality sketch of the scalar fields into the statslogs
+along with the pk bloom filter, the statslogs of the field stats are read by the older versions as is`,
+		Export: true,
+	}
+	p.FieldStatsEnabled.Init(base.mgr)
+
+	p.HLLPrecision = ParamItem{
+		Key:          "dataNode.stats.hllPrecision",
+		Version:      "2.4.0",
+		DefaultValue: "12",
+		Doc: `The precision of the HyperLogLog sketches of the scalar fields in [4, 16], a sketch takes 2^precision bytes
+and estimates the cardinality with the standard error of 1.04/sqrt(2^precision)`,
+		Export: true,
+	}
+	p.HLLPrecision.Init(base.mgr)
+
+	p.ReadBandwidthLimitMB = ParamItem{
+		Key:          "dataNode.bandwidth.readLimitMB",
+		Version:      "2.4.0",
+		DefaultValue: "0",
+		Doc: `The max bandwidth in MB/s of reading binlogs from the object storage, shared by the flush and compaction
+of the datanode, 0 means unlimited`,
+		Export: true,
+	}
+	p.ReadBandwidthLimitMB.Init(base.mgr)
+
+	p.WriteBandwidthLimitMB = ParamItem{
+		Key:          "dataNode.bandwidth.writeLimitMB",
+		Version:      "2.4.0",
+		DefaultValue: "0",
+		Doc: `The max bandwidth in MB/s of writing binlogs to the object storage, shared by the flush and compaction
+of the datanode, 0 means unlimited`,
+		Export: true,
+	}
+	p.WriteBandwidthLimitMB.Init(base.mgr)
+
+	p.BinlogCacheEnabled = ParamItem{
+		Key:          "dataNode.binlogCache.enabled",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc: `Whether to cache the binlogs downloaded on the local disk, so the binlogs read repeatedly by compactions
+and retries are downloaded once`,
+		Export: true,
+	}
+	p.BinlogCacheEnabled.Init(base.mgr)
+
+	p.BinlogCachePath = ParamItem{
+		Key:          "dataNode.binlogCache.path",
+		Version:      "2.4.0",
+		DefaultValue: "",
+		Doc:          "The directory of the binlog cache, binlog_cache under localStorage.path if empty. The binlogs are cached in its binlogs subdirectory, which is cleared on start",
+		Export:       true,
+	}
+	p.BinlogCachePath.Init(base.mgr)
+
+	p.BinlogCacheCapacityMB = ParamItem{
+		Key:          "dataNode.binlogCache.capacityMB",
+		Version:      "2.4.0",
+		DefaultValue: "1024",
+		Doc:          "The max size in MB of the binlogs cached, the least recently used binlogs are evicted beyond it",
+		Export:       true,
+	}
+	p.BinlogCacheCapacityMB.Init(base.mgr)
+
+	p.BinlogCacheTTL = ParamItem{
+		Key:          "dataNode.binlogCache.ttl",
+		Version:      "2.4.0",
+		DefaultValue: "3600",
+		Doc:          "The seconds a cached binlog is kept since last accessed, 0 means no expiration",
+		Export:       true,
+	}
+	p.BinlogCacheTTL.Init(base.mgr)
+
+	p.FlushMaxRetryDuration = ParamItem{
+		Key:          "dataNode.binlogIO.flush.maxRetryDuration",
+		Version:      "2.4.0",
+		DefaultValue: "600",
+		Doc: `The max seconds a binlog request of the flush is retried for, the flush fails once exceeded and the channel is
+recovered from the checkpoint, so a stuck object storage doesn't hang the flush, 0 means no bound`,
+		Export: true,
+	}
+	p.FlushMaxRetryDuration.Init(base.mgr)
+
+	p.FlushMaxRetryCount = ParamItem{
+		Key:          "dataNode.binlogIO.flush.maxRetryCount",
+		Version:      "2.4.0",
+		DefaultValue: "10",
+		Doc:          "The max attempts of a binlog request of the flush, 0 means bounded by the maxRetryDuration only",
+		Export:       true,
+	}
+	p.FlushMaxRetryCount.Init(base.mgr)
+
+	p.CompactionMaxRetryDuration = ParamItem{
+		Key:          "dataNode.binlogIO.compaction.maxRetryDuration",
+		Version:      "2.4.0",
+		DefaultValue: "1800",
+		Doc: `The max seconds a binlog request of the compaction is retried for, the compaction fails once exceeded
+and is rescheduled by the datacoord, 0 means bounded by the compaction timeout only`,
+		Export: true,
+	}
+	p.CompactionMaxRetryDuration.Init(base.mgr)
+
+	p.CompactionMaxRetryCount = ParamItem{
+		Key:          "dataNode.binlogIO.compaction.maxRetryCount",
+		Version:      "2.4.0",
+		DefaultValue: "10",
+		Doc:          "The max attempts of a binlog request of the compaction, 0 means bounded by the maxRetryDuration only",
+		Export:       true,
+	}
+	p.CompactionMaxRetryCount.Init(base.mgr)
+
+	p.CompactionPrefetchDepth = ParamItem{
+		Key:          "dataNode.binlogIO.compaction.prefetchDepth",
+		Version:      "2.4.0",
+		DefaultValue: "0",
+		Doc: `The number of the batches of binlogs of a segment downloaded ahead of the one merged by the compaction, the batches
+prefetched are held in memory as a whole, 0 means the binlogs are streamed event by event without read-ahead`,
+		Export: true,
+	}
+	p.CompactionPrefetchDepth.Init(base.mgr)
+
 	p.DataNodeTimeTickByRPC = ParamItem{
 		Key:          "datanode.timetick.byRPC",
 		Version:      "2.2.9",
@@ -3213,6 +3988,16 @@ func (p *dataNodeConfig) init(base *BaseTable) {
 	}
 	p.UpdateChannelCheckpointRPCTimeout.Init(base.mgr)
 
+	p.ChannelLazyLoadStatsEnabled = ParamItem{
+		Key:          "datanode.channel.lazyLoadStatsEnabled",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc: `whether to load the pk stats of flushed segments in background when watching a channel,
+the channel is served once the growing segments are recovered, and deletes wait for the stats they are checked against`,
+		Export: true,
+	}
+	p.ChannelLazyLoadStatsEnabled.Init(base.mgr)
+
 	p.MaxConcurrentImportTaskNum = ParamItem{
 		Key:          "datanode.import.maxConcurrentTaskNum",
 		Version:      "2.4.0",
@@ -108,6 +108,9 @@ func TestComponentParam(t *testing.T) {
 
 		params.Save("common.preCreatedTopic.timeticker", "timeticker")
 		assert.Equal(t, []string{"timeticker"}, Params.TimeTicker.GetAsStrings())
+
+		assert.Equal(t, "", Params.StoragePathTemplate.GetValue())
+		assert.Equal(t, "", Params.StorageTier.GetValue())
 	})
 
 	t.Run("test rootCoordConfig", func(t *testing.T) {
@@ -155,6 +158,10 @@ func TestComponentParam(t *testing.T) {
 
 		t.Logf("AccessLog.MaxDays: %d", Params.AccessLog.RotatedTime.GetAsInt64())
 
+		assert.False(t, Params.Connector.Enable.GetAsBool())
+		assert.Equal(t, 1000, Params.Connector.BatchSize.GetAsInt())
+		assert.Equal(t, 1000, Params.Connector.FlushInterval.GetAsInt())
+
 		t.Logf("ShardLeaderCacheInterval: %d", Params.ShardLeaderCacheInterval.GetAsInt64())
 
 		assert.Equal(t, Params.ReplicaSelectionPolicy.GetValue(), "look_aside")
@@ -166,6 +173,7 @@ func TestComponentParam(t *testing.T) {
 		assert.Equal(t, Params.CostMetricsExpireTime.GetAsInt(), 1000)
 		assert.Equal(t, Params.RetryTimesOnReplica.GetAsInt(), 2)
 		assert.EqualValues(t, Params.HealthCheckTimeout.GetAsInt64(), 3000)
+		assert.False(t, Params.InsertChecksumEnabled.GetAsBool())
 	})
 
 	// t.Run("test proxyConfig panic", func(t *testing.T) {
@@ -355,12 +363,41 @@ func TestComponentParam(t *testing.T) {
 		Params := &params.DataCoordCfg
 		assert.Equal(t, 24*60*60*time.Second, Params.SegmentMaxLifetime.GetAsDuration(time.Second))
 		assert.True(t, Params.EnableGarbageCollection.GetAsBool())
+		assert.False(t, Params.GCLifecycleRules.GetAsBool())
+		assert.Equal(t, 100, Params.GCLifecycleMaxRules.GetAsInt())
 		assert.Equal(t, Params.EnableActiveStandby.GetAsBool(), false)
 		t.Logf("dataCoord EnableActiveStandby = %t", Params.EnableActiveStandby.GetAsBool())
 
 		assert.Equal(t, true, Params.AutoBalance.GetAsBool())
 		assert.Equal(t, 10, Params.CheckAutoBalanceConfigInterval.GetAsInt())
 		assert.Equal(t, false, Params.AutoUpgradeSegmentIndex.GetAsBool())
+		assert.Equal(t, 2, Params.ExportConcurrency.GetAsInt())
+		assert.Equal(t, 3*time.Second, Params.DecommissionCheckInterval.GetAsDuration(time.Second))
+		assert.False(t, Params.SegmentEventEnabled.GetAsBool())
+		assert.Equal(t, 7*24*time.Hour, Params.SegmentEventRetention.GetAsDuration(time.Second))
+		assert.False(t, Params.ReencodeEnabled.GetAsBool())
+		assert.Equal(t, 300*time.Second, Params.ReencodeCheckInterval.GetAsDuration(time.Second))
+		assert.Equal(t, 20, Params.ReencodeInspectBatch.GetAsInt())
+		assert.Equal(t, 1, Params.ReencodeMaxConcurrency.GetAsInt())
+		assert.Equal(t, 32, Params.CompactionPreemptionFlushingThreshold.GetAsInt())
+		assert.False(t, Params.CompactionStealingEnabled.GetAsBool())
+		assert.Equal(t, "", Params.PlacementSoPath.GetValue())
+		assert.Empty(t, Params.PlacementParams.GetValue())
+		assert.Equal(t, 2, Params.MajorCompactionMaxConcurrency.GetAsInt())
+		assert.Equal(t, 10*time.Second, Params.MajorCompactionCheckInterval.GetAsDuration(time.Second))
+		assert.True(t, Params.ClusteringCompactionEnabled.GetAsBool())
+		assert.Equal(t, int64(4096), Params.ClusteringCompactionMaxInputSize.GetAsInt64())
+		assert.Equal(t, 1800*time.Second, Params.LevelZeroCompactionTriggerMaxIdleTime.GetAsDuration(time.Second))
+		assert.Equal(t, 0.2, Params.SingleCompactionDeleteRatio.GetAsFloat())
+		params.Save(Params.SingleCompactionDeleteRatio.Key, "0.5")
+		assert.Equal(t, 0.5, Params.SingleCompactionDeleteRatio.GetAsFloat())
+		assert.Equal(t, time.Duration(0), Params.GCReconcileInterval.GetAsDuration(time.Second))
+		assert.False(t, Params.GCReconcileAutoDelete.GetAsBool())
+		assert.Equal(t, 86400*time.Second, Params.GCReconcileSafetyWindow.GetAsDuration(time.Second))
+		assert.Equal(t, 100, Params.GCReconcileMaxReportedObjects.GetAsInt())
+		assert.True(t, Params.SegmentExpirationEnabled.GetAsBool())
+		assert.Equal(t, time.Minute, Params.SegmentExpirationCheckInterval.GetAsDuration(time.Second))
+		params.Reset(Params.SingleCompactionDeleteRatio.Key)
 	})
 
 	t.Run("test dataNodeConfig", func(t *testing.T) {
@@ -395,6 +432,39 @@ func TestComponentParam(t *testing.T) {
 		period := &Params.SyncPeriod
 		t.Logf("SyncPeriod: %v", period)
 		assert.Equal(t, 10*time.Minute, Params.SyncPeriod.GetAsDuration(time.Second))
+		assert.Equal(t, int64(0), Params.ChannelMemoryWatermark.GetAsInt64())
+		assert.Equal(t, 32, Params.MaxPendingSyncTasksPerChannel.GetAsInt())
+		assert.Equal(t, time.Duration(0), Params.MaxCheckpointLag.GetAsDuration(time.Second))
+		assert.Equal(t, 65536, Params.ParquetRowGroupRows.GetAsInt())
+		assert.Equal(t, 1024, Params.DictionaryCardinality.GetAsInt())
+		assert.False(t, Params.DeltalogBitmapEnabled.GetAsBool())
+		assert.False(t, Params.DeltalogSortedEnabled.GetAsBool())
+		assert.Equal(t, int64(256*1024*1024), Params.CompactionSortMaxSize.GetAsInt64())
+		assert.False(t, Params.AdaptiveSyncEnabled.GetAsBool())
+		assert.Equal(t, 1000, Params.AdaptiveSyncTargetLatency.GetAsInt())
+		assert.Equal(t, int64(67108864), Params.DeltalogChunkSize.GetAsInt64())
+		assert.True(t, Params.DeltalogDedupEnabled.GetAsBool())
+		assert.Equal(t, 0.7, Params.MemoryBudgetRatio.GetAsFloat())
+		assert.Equal(t, int64(67108864), Params.UploadPartSize.GetAsInt64())
+		assert.Equal(t, 4, Params.UploadConcurrency.GetAsInt())
+		assert.Equal(t, int64(268435456), Params.UploadMaxInflight.GetAsInt64())
+		assert.False(t, Params.UploadManifest.GetAsBool())
+		assert.False(t, Params.UploadInventory.GetAsBool())
+		assert.Equal(t, int64(10000), Params.IDLeaseSize.GetAsInt64())
+		assert.Equal(t, 600*time.Second, Params.IDLeaseTTL.GetAsDuration(time.Second))
+		assert.True(t, Params.FieldStatsEnabled.GetAsBool())
+		assert.Equal(t, 12, Params.HLLPrecision.GetAsInt())
+		assert.Equal(t, 0.0, Params.ReadBandwidthLimitMB.GetAsFloat())
+		assert.Equal(t, 0.0, Params.WriteBandwidthLimitMB.GetAsFloat())
+		assert.False(t, Params.BinlogCacheEnabled.GetAsBool())
+		assert.Equal(t, "", Params.BinlogCachePath.GetValue())
+		assert.Equal(t, int64(1024), Params.BinlogCacheCapacityMB.GetAsInt64())
+		assert.Equal(t, 3600*time.Second, Params.BinlogCacheTTL.GetAsDuration(time.Second))
+		assert.Equal(t, 600*time.Second, Params.FlushMaxRetryDuration.GetAsDuration(time.Second))
+		assert.Equal(t, 10, Params.FlushMaxRetryCount.GetAsInt())
+		assert.Equal(t, 1800*time.Second, Params.CompactionMaxRetryDuration.GetAsDuration(time.Second))
+		assert.Equal(t, 10, Params.CompactionMaxRetryCount.GetAsInt())
+		assert.Equal(t, 0, Params.CompactionPrefetchDepth.GetAsInt())
 
 		bulkinsertTimeout := &Params.BulkInsertTimeoutSeconds
 		t.Logf("BulkInsertTimeoutSeconds: %v", bulkinsertTimeout)
@@ -407,6 +477,7 @@ func TestComponentParam(t *testing.T) {
 		updateChannelCheckpointMaxParallel := Params.UpdateChannelCheckpointMaxParallel.GetAsInt()
 		t.Logf("updateChannelCheckpointMaxParallel: %d", updateChannelCheckpointMaxParallel)
 		assert.Equal(t, 1000, Params.UpdateChannelCheckpointMaxParallel.GetAsInt())
+		assert.False(t, Params.ChannelLazyLoadStatsEnabled.GetAsBool())
 
 		maxConcurrentImportTaskNum := Params.MaxConcurrentImportTaskNum.GetAsInt()
 		t.Logf("maxConcurrentImportTaskNum: %d", maxConcurrentImportTaskNum)
@@ -112,6 +112,9 @@ type quotaConfig struct {
 	GrowingSegmentsSizeMinRateRatio      ParamItem `refreshable:"true"`
 	GrowingSegmentsSizeLowWaterLevel     ParamItem `refreshable:"true"`
 	GrowingSegmentsSizeHighWaterLevel    ParamItem `refreshable:"true"`
+	ChannelBackPressureEnabled           ParamItem `refreshable:"true"`
+	ChannelPressureLowWaterLevel         ParamItem `refreshable:"true"`
+	ChannelPressureHighWaterLevel        ParamItem `refreshable:"true"`
 	DiskProtectionEnabled                ParamItem `refreshable:"true"`
 	DiskQuota                            ParamItem `refreshable:"true"`
 	DiskQuotaPerCollection               ParamItem `refreshable:"true"`
@@ -1101,6 +1104,53 @@ but the rate will not be lower than minRateRatio * dmlRate.`,
 	}
 	p.GrowingSegmentsSizeHighWaterLevel.Init(base.mgr)
 
+	p.ChannelBackPressureEnabled = ParamItem{
+		Key:          "quotaAndLimits.limitWriting.channelBackPressure.enabled",
+		Version:      "2.4.0",
+		DefaultValue: "true",
+		Doc: `The pressure of a channel is the usage of its write buffer or sync queue in the DataNode, whichever is higher.
+No action will be taken if the pressure is less than the low watermark.
+When the pressure exceeds the low watermark, the dml rate of the collection will be reduced,
+when it exceeds the high watermark, the dml requests to the channel will be rejected with a retriable error.`,
+		Export: true,
+	}
+	p.ChannelBackPressureEnabled.Init(base.mgr)
+
+	defaultChannelPressureLowWaterLevel := "0.6"
+	p.ChannelPressureLowWaterLevel = ParamItem{
+		Key:          "quotaAndLimits.limitWriting.channelBackPressure.lowWaterLevel",
+		Version:      "2.4.0",
+		DefaultValue: defaultChannelPressureLowWaterLevel,
+		Formatter: func(v string) string {
+			level := getAsFloat(v)
+			if level <= 0 || level > 1 {
+				return defaultChannelPressureLowWaterLevel
+			}
+			return v
+		},
+		Export: true,
+	}
+	p.ChannelPressureLowWaterLevel.Init(base.mgr)
+
+	defaultChannelPressureHighWaterLevel := "0.9"
+	p.ChannelPressureHighWaterLevel = ParamItem{
+		Key:          "quotaAndLimits.limitWriting.channelBackPressure.highWaterLevel",
+		Version:      "2.4.0",
+		DefaultValue: defaultChannelPressureHighWaterLevel,
+		Formatter: func(v string) string {
+			level := getAsFloat(v)
+			if level <= 0 || level > 1 {
+				return defaultChannelPressureHighWaterLevel
+			}
+			if !p.checkMinMaxLegal(p.ChannelPressureLowWaterLevel.GetAsFloat(), getAsFloat(v)) {
+				return defaultChannelPressureHighWaterLevel
+			}
+			return v
+		},
+		Export: true,
+	}
+	p.ChannelPressureHighWaterLevel.Init(base.mgr)
+
 	p.DiskProtectionEnabled = ParamItem{
 		Key:          "quotaAndLimits.limitWriting.diskProtection.enabled",
 		Version:      "2.2.0",
@@ -191,6 +191,9 @@ func TestQuotaParam(t *testing.T) {
 		assert.Equal(t, 0.5, qc.GrowingSegmentsSizeMinRateRatio.GetAsFloat())
 		assert.Equal(t, 0.2, qc.GrowingSegmentsSizeLowWaterLevel.GetAsFloat())
 		assert.Equal(t, 0.4, qc.GrowingSegmentsSizeHighWaterLevel.GetAsFloat())
+		assert.Equal(t, true, qc.ChannelBackPressureEnabled.GetAsBool())
+		assert.Equal(t, 0.6, qc.ChannelPressureLowWaterLevel.GetAsFloat())
+		assert.Equal(t, 0.9, qc.ChannelPressureHighWaterLevel.GetAsFloat())
 		assert.Equal(t, true, qc.DiskProtectionEnabled.GetAsBool())
 		assert.Equal(t, defaultMax, qc.DiskQuota.GetAsFloat())
 		assert.Equal(t, defaultMax, qc.DiskQuotaPerCollection.GetAsFloat())
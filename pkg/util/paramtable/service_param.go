@@ -53,6 +53,7 @@ type ServiceParam struct {
 	RocksmqCfg      RocksmqConfig
 	NatsmqCfg       NatsmqConfig
 	MinioCfg        MinioConfig
+	HDFSCfg         HDFSConfig
 }
 
 func (p *ServiceParam) init(bt *BaseTable) {
@@ -66,6 +67,7 @@ func (p *ServiceParam) init(bt *BaseTable) {
 	p.RocksmqCfg.Init(bt)
 	p.NatsmqCfg.Init(bt)
 	p.MinioCfg.Init(bt)
+	p.HDFSCfg.Init(bt)
 }
 
 func (p *ServiceParam) RocksmqEnable() bool {
@@ -429,6 +431,20 @@ func (p *TiKVConfig) Init(base *BaseTable) {
 
 type LocalStorageConfig struct {
 	Path ParamItem `refreshable:"false"`
+
+	DiskWatchdogEnabled       ParamItem `refreshable:"true"`
+	DiskWatchdogCheckInterval ParamItem `refreshable:"false"`
+	DiskWatchdogHighWatermark ParamItem `refreshable:"true"`
+	DiskWatchdogLowWatermark  ParamItem `refreshable:"true"`
+
+	TieredEnabled          ParamItem `refreshable:"false"`
+	TieredPath             ParamItem `refreshable:"false"`
+	TieredHotAge           ParamItem `refreshable:"true"`
+	TieredHotCapacity      ParamItem `refreshable:"true"`
+	TieredEvictInterval    ParamItem `refreshable:"false"`
+	TieredCollectionHotAge ParamItem `refreshable:"true"`
+
+	MmapReadEnabled ParamItem `refreshable:"true"`
 }
 
 func (p *LocalStorageConfig) Init(base *BaseTable) {
@@ -440,6 +456,108 @@ func (p *LocalStorageConfig) Init(base *BaseTable) {
 		Export:       true,
 	}
 	p.Path.Init(base.mgr)
+
+	p.DiskWatchdogEnabled = ParamItem{
+		Key:          "localStorage.diskWatchdog.enabled",
+		Version:      "2.4.0",
+		DefaultValue: "true",
+		Doc:          "whether to pause the disk consuming activities of datanode, querynode and indexnode when the local disk is almost full",
+		Export:       true,
+	}
+	p.DiskWatchdogEnabled.Init(base.mgr)
+
+	p.DiskWatchdogCheckInterval = ParamItem{
+		Key:          "localStorage.diskWatchdog.checkInterval",
+		Version:      "2.4.0",
+		DefaultValue: "10",
+		Doc:          "the interval to check the usage of the local disk, in seconds",
+		Export:       true,
+	}
+	p.DiskWatchdogCheckInterval.Init(base.mgr)
+
+	p.DiskWatchdogHighWatermark = ParamItem{
+		Key:          "localStorage.diskWatchdog.highWatermark",
+		Version:      "2.4.0",
+		DefaultValue: "0.9",
+		Doc:          "the disk consuming activities are paused once the usage ratio of the local disk reaches the high watermark",
+		Export:       true,
+	}
+	p.DiskWatchdogHighWatermark.Init(base.mgr)
+
+	p.DiskWatchdogLowWatermark = ParamItem{
+		Key:          "localStorage.diskWatchdog.lowWatermark",
+		Version:      "2.4.0",
+		DefaultValue: "0.8",
+		Doc:          "the paused activities are resumed once the usage ratio of the local disk drops below the low watermark",
+		Export:       true,
+	}
+	p.DiskWatchdogLowWatermark.Init(base.mgr)
+
+	p.TieredEnabled = ParamItem{
+		Key:          "localStorage.tiered.enabled",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc: `whether to cache the binlogs written in the local disk as the hot tier, the binlogs are written through to
+the object storage before acknowledged, so the hot tier is only a read cache of each node`,
+		Export: true,
+	}
+	p.TieredEnabled.Init(base.mgr)
+
+	p.TieredPath = ParamItem{
+		Key:          "localStorage.tiered.path",
+		Version:      "2.4.0",
+		DefaultValue: "",
+		Doc:          "the directory of the hot tier, tiered under localStorage.path if empty",
+		Export:       true,
+	}
+	p.TieredPath.Init(base.mgr)
+
+	p.TieredHotAge = ParamItem{
+		Key:          "localStorage.tiered.hotAge",
+		Version:      "2.4.0",
+		DefaultValue: "3600",
+		Doc:          "the files cached longer than the hot age in seconds are evicted from the hot tier",
+		Export:       true,
+	}
+	p.TieredHotAge.Init(base.mgr)
+
+	p.TieredHotCapacity = ParamItem{
+		Key:          "localStorage.tiered.hotCapacity",
+		Version:      "2.4.0",
+		DefaultValue: "10240",
+		Doc:          "the capacity of the hot tier in MB, the oldest files are evicted ahead of the hot age once exceeded",
+		Export:       true,
+	}
+	p.TieredHotCapacity.Init(base.mgr)
+
+	p.TieredEvictInterval = ParamItem{
+		Key:          "localStorage.tiered.evictInterval",
+		Version:      "2.4.0",
+		DefaultValue: "10",
+		Doc:          "the interval to evict the files of the hot tier, in seconds",
+		Export:       true,
+	}
+	p.TieredEvictInterval.Init(base.mgr)
+
+	p.TieredCollectionHotAge = ParamItem{
+		Key:          "localStorage.tiered.collectionHotAge",
+		Version:      "2.4.0",
+		DefaultValue: "{}",
+		Doc: `the hot ages in seconds of the collections overriding hotAge, i.e. {"<collectionID>": "<seconds>"},
+0 doesn't cache the binlogs of the collection, and negative keeps them cached until the capacity exceeded`,
+		Export: true,
+	}
+	p.TieredCollectionHotAge.Init(base.mgr)
+
+	p.MmapReadEnabled = ParamItem{
+		Key:          "localStorage.mmapRead.enabled",
+		Version:      "2.4.0",
+		DefaultValue: "true",
+		Doc: `whether to read the binlogs of the local storage by mmap rather than copies, i.e. the stats logs loaded by querynode
+and the binlogs scanned by the compactions, which takes effect only if the storage type is local`,
+		Export: true,
+	}
+	p.MmapReadEnabled.Init(base.mgr)
 }
 
 type MetaStoreConfig struct {
@@ -983,8 +1101,8 @@ func (r *NatsmqConfig) Init(base *BaseTable) {
 type MinioConfig struct {
 	Address          ParamItem `refreshable:"false"`
 	Port             ParamItem `refreshable:"false"`
-	AccessKeyID      ParamItem `refreshable:"false"`
-	SecretAccessKey  ParamItem `refreshable:"false"`
+	AccessKeyID      ParamItem `refreshable:"true"`
+	SecretAccessKey  ParamItem `refreshable:"true"`
 	UseSSL           ParamItem `refreshable:"false"`
 	BucketName       ParamItem `refreshable:"false"`
 	RootPath         ParamItem `refreshable:"false"`
@@ -995,6 +1113,23 @@ type MinioConfig struct {
 	Region           ParamItem `refreshable:"false"`
 	UseVirtualHost   ParamItem `refreshable:"false"`
 	RequestTimeoutMs ParamItem `refreshable:"false"`
+
+	GcpCredentialJSON ParamItem `refreshable:"false"`
+
+	UploadPartSize         ParamItem `refreshable:"false"`
+	UploadConcurrency      ParamItem `refreshable:"false"`
+	UploadThreshold        ParamItem `refreshable:"false"`
+	UploadBandwidthLimitMB ParamItem `refreshable:"false"`
+
+	SSEType        ParamItem `refreshable:"false"`
+	SSEKMSKeyID    ParamItem `refreshable:"false"`
+	StorageClass   ParamItem `refreshable:"false"`
+	ObjectTags     ParamItem `refreshable:"false"`
+	ObjectPolicies ParamItem `refreshable:"false"`
+
+	HedgedReadEnabled    ParamItem `refreshable:"false"`
+	HedgedReadPercentile ParamItem `refreshable:"false"`
+	HedgedReadMinDelayMs ParamItem `refreshable:"false"`
 }
 
 func (p *MinioConfig) Init(base *BaseTable) {
@@ -1032,7 +1167,7 @@ func (p *MinioConfig) Init(base *BaseTable) {
 		Version:      "2.0.0",
 		DefaultValue: "minioadmin",
 		PanicIfEmpty: false, // tmp fix, need to be conditional
-		Doc:          "accessKeyID of MinIO/S3",
+		Doc:          "accessKeyID of MinIO/S3, the Go clients take the updated one without restart, but the segcore of the querynode and the indexnode needs a restart",
 		Export:       true,
 	}
 	p.AccessKeyID.Init(base.mgr)
@@ -1042,7 +1177,7 @@ func (p *MinioConfig) Init(base *BaseTable) {
 		Version:      "2.0.0",
 		DefaultValue: "minioadmin",
 		PanicIfEmpty: false, // tmp fix, need to be conditional
-		Doc:          "MinIO/S3 encryption string",
+		Doc:          "MinIO/S3 encryption string, the Go clients take the updated one without restart, but the segcore of the querynode and the indexnode needs a restart",
 		Export:       true,
 	}
 	p.SecretAccessKey.Init(base.mgr)
@@ -1105,7 +1240,8 @@ aliyun (ecs): https://www.alibabacloud.com/help/en/elastic-compute-service/lates
 You can use "aws" for other cloud provider supports S3 API with signature v4, e.g.: minio
 You can use "gcp" for other cloud provider supports S3 API with signature v2
 You can use "aliyun" for other cloud provider uses virtual host style bucket
-When useIAM enabled, only "aws", "gcp", "aliyun" is supported for now`,
+You can use "gcpnative" for the Google Cloud Storage by its JSON API rather than the S3 compatible one
+When useIAM enabled, only "aws", "gcp", "aliyun", "gcpnative" is supported for now`,
 		Export: true,
 	}
 	p.CloudProvider.Init(base.mgr)
@@ -1154,4 +1290,245 @@ Leave it empty if you want to use AWS default endpoint`,
 		Export:       true,
 	}
 	p.RequestTimeoutMs.Init(base.mgr)
+
+	p.UploadPartSize = ParamItem{
+		Key:          "minio.upload.partSize",
+		Version:      "2.4.0",
+		DefaultValue: "0",
+		Doc:          "part size in bytes of multipart uploads, 0 means derived from object size and upload concurrency",
+		Export:       true,
+	}
+	p.UploadPartSize.Init(base.mgr)
+
+	p.UploadConcurrency = ParamItem{
+		Key:          "minio.upload.concurrency",
+		Version:      "2.4.0",
+		DefaultValue: "4",
+		Doc:          "number of parts uploaded concurrently for a single object",
+		Export:       true,
+	}
+	p.UploadConcurrency.Init(base.mgr)
+
+	p.UploadThreshold = ParamItem{
+		Key:          "minio.upload.multipartThreshold",
+		Version:      "2.4.0",
+		DefaultValue: "16777216",
+		Doc:          "objects larger than this size in bytes are uploaded in concurrent parts",
+		Export:       true,
+	}
+	p.UploadThreshold.Init(base.mgr)
+
+	p.UploadBandwidthLimitMB = ParamItem{
+		Key:          "minio.upload.bandwidthLimitMB",
+		Version:      "2.4.0",
+		DefaultValue: "0",
+		Doc:          "upload bandwidth limit in MB/s shared by all uploads of a chunk manager, 0 means unlimited",
+		Export:       true,
+	}
+	p.UploadBandwidthLimitMB.Init(base.mgr)
+
+	p.SSEType = ParamItem{
+		Key:          "minio.sse.type",
+		Version:      "2.4.0",
+		DefaultValue: "",
+		Doc:          "server-side encryption of the objects written, SSE-S3 or SSE-KMS, not encrypted if empty",
+		Export:       true,
+	}
+	p.SSEType.Init(base.mgr)
+
+	p.SSEKMSKeyID = ParamItem{
+		Key:          "minio.sse.kmsKeyID",
+		Version:      "2.4.0",
+		DefaultValue: "",
+		Doc:          "the kms key id of SSE-KMS, the default key of the bucket is used if empty",
+		Export:       true,
+	}
+	p.SSEKMSKeyID.Init(base.mgr)
+
+	p.StorageClass = ParamItem{
+		Key:          "minio.storageClass",
+		Version:      "2.4.0",
+		DefaultValue: "",
+		Doc:          "storage class of the objects written, e.g. STANDARD_IA and GLACIER_IR, the default class of the bucket is used if empty",
+		Export:       true,
+	}
+	p.StorageClass.Init(base.mgr)
+
+	p.ObjectTags = ParamItem{
+		Key:          "minio.objectTags",
+		Version:      "2.4.0",
+		DefaultValue: "",
+		Doc:          `tags of the objects written in json, e.g. {"team": "search"}`,
+		Export:       true,
+	}
+	p.ObjectTags.Init(base.mgr)
+
+	p.ObjectPolicies = ParamItem{
+		Key:          "minio.objectPolicies",
+		Version:      "2.4.0",
+		DefaultValue: "",
+		Doc: `the sse, kmsKeyID, storageClass and tags of the objects by the path categories in json, which override the defaults above,
+e.g. {"insert_log": {"storageClass": "STANDARD_IA"}, "delta_log": {"sse": "SSE-KMS", "tags": {"tier": "hot"}}},
+the index files built by the segcore take the index_files category. The policies apply to the S3 compatible storages only,
+Milvus fails to start if any policy is set with the azure, the native gcs, the hdfs or the opendal storage`,
+		Export: true,
+	}
+	p.ObjectPolicies.Init(base.mgr)
+
+	p.GcpCredentialJSON = ParamItem{
+		Key:          "minio.gcpCredentialJSON",
+		Version:      "2.4.0",
+		DefaultValue: "",
+		Doc: `The json key of the service account of the Google Cloud Storage if cloudProvider is "gcpnative" and useIAM is false,
+the application default credentials are used if empty, i.e. the key of GOOGLE_APPLICATION_CREDENTIALS or the workload identity`,
+		Export: true,
+	}
+	p.GcpCredentialJSON.Init(base.mgr)
+
+	p.HedgedReadEnabled = ParamItem{
+		Key:          "minio.hedgedRead.enabled",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc:          "whether to hedge the reads of the objects loaded in batch, which issues a duplicate of the read not responded in time and takes the first response",
+		Export:       true,
+	}
+	p.HedgedReadEnabled.Init(base.mgr)
+
+	p.HedgedReadPercentile = ParamItem{
+		Key:          "minio.hedgedRead.percentile",
+		Version:      "2.4.0",
+		DefaultValue: "0.99",
+		Doc:          "the reads not responded within this percentile of the latest read latencies of the objects of the similar sizes are hedged",
+		Export:       true,
+	}
+	p.HedgedReadPercentile.Init(base.mgr)
+
+	p.HedgedReadMinDelayMs = ParamItem{
+		Key:          "minio.hedgedRead.minDelayMs",
+		Version:      "2.4.0",
+		DefaultValue: "20",
+		Doc:          "the min delay in milliseconds before a read is hedged",
+		Export:       true,
+	}
+	p.HedgedReadMinDelayMs.Init(base.mgr)
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// --- hdfs ---
+type HDFSConfig struct {
+	Address          ParamItem `refreshable:"false"`
+	UseSSL           ParamItem `refreshable:"false"`
+	BaseDir          ParamItem `refreshable:"false"`
+	User             ParamItem `refreshable:"false"`
+	DelegationToken  ParamItem `refreshable:"false"`
+	BlockSize        ParamItem `refreshable:"false"`
+	Replication      ParamItem `refreshable:"false"`
+	RequestTimeoutMs ParamItem `refreshable:"false"`
+
+	HedgedReadEnabled    ParamItem `refreshable:"false"`
+	HedgedReadPercentile ParamItem `refreshable:"false"`
+	HedgedReadMinDelayMs ParamItem `refreshable:"false"`
+}
+
+func (p *HDFSConfig) Init(base *BaseTable) {
+	p.Address = ParamItem{
+		Key:          "hdfs.address",
+		Version:      "2.4.0",
+		DefaultValue: "localhost:9870",
+		Doc:          "http addresses of the WebHDFS of the namenodes, separated by comma, the standby namenodes of HA are skipped",
+		Export:       true,
+	}
+	p.Address.Init(base.mgr)
+
+	p.UseSSL = ParamItem{
+		Key:          "hdfs.useSSL",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc:          "access the WebHDFS by https, i.e. swebhdfs",
+		Export:       true,
+	}
+	p.UseSSL.Init(base.mgr)
+
+	p.BaseDir = ParamItem{
+		Key:          "hdfs.baseDir",
+		Version:      "2.4.0",
+		DefaultValue: "/milvus",
+		Doc:          "the directory of hdfs storing the data of milvus under minio.rootPath, created if not exists",
+		Export:       true,
+	}
+	p.BaseDir.Init(base.mgr)
+
+	p.User = ParamItem{
+		Key:          "hdfs.user",
+		Version:      "2.4.0",
+		DefaultValue: "",
+		Doc:          "the user of the simple authentication, ignored if delegationToken is set",
+		Export:       true,
+	}
+	p.User.Init(base.mgr)
+
+	p.DelegationToken = ParamItem{
+		Key:          "hdfs.delegationToken",
+		Version:      "2.4.0",
+		DefaultValue: "",
+		Doc: `the delegation token of the kerberized cluster, i.e. the url encoded token fetched by the kerberos principal of milvus.
+Milvus doesn't login by kerberos itself, neither by a keytab nor by SPNEGO, so the token must be fetched and renewed
+outside of milvus before it expires, the requests fail once it expires`,
+		Export: true,
+	}
+	p.DelegationToken.Init(base.mgr)
+
+	p.BlockSize = ParamItem{
+		Key:          "hdfs.blockSize",
+		Version:      "2.4.0",
+		DefaultValue: "134217728",
+		Doc:          "the block size in bytes of the files written, 0 means the default of the cluster",
+		Export:       true,
+	}
+	p.BlockSize.Init(base.mgr)
+
+	p.Replication = ParamItem{
+		Key:          "hdfs.replication",
+		Version:      "2.4.0",
+		DefaultValue: "0",
+		Doc:          "the replication of the files written, 0 means the default of the cluster",
+		Export:       true,
+	}
+	p.Replication.Init(base.mgr)
+
+	p.RequestTimeoutMs = ParamItem{
+		Key:          "hdfs.requestTimeoutMs",
+		Version:      "2.4.0",
+		DefaultValue: "60000",
+		Doc:          "timeout for request time in milliseconds",
+		Export:       true,
+	}
+	p.RequestTimeoutMs.Init(base.mgr)
+
+	p.HedgedReadEnabled = ParamItem{
+		Key:          "hdfs.hedgedRead.enabled",
+		Version:      "2.4.0",
+		DefaultValue: "false",
+		Doc:          "whether to hedge the reads of the files loaded in batch, which issues a duplicate of the read not responded in time and takes the first response",
+		Export:       true,
+	}
+	p.HedgedReadEnabled.Init(base.mgr)
+
+	p.HedgedReadPercentile = ParamItem{
+		Key:          "hdfs.hedgedRead.percentile",
+		Version:      "2.4.0",
+		DefaultValue: "0.99",
+		Doc:          "the reads not responded within this percentile of the latest read latencies of the objects of the similar sizes are hedged",
+		Export:       true,
+	}
+	p.HedgedReadPercentile.Init(base.mgr)
+
+	p.HedgedReadMinDelayMs = ParamItem{
+		Key:          "hdfs.hedgedRead.minDelayMs",
+		Version:      "2.4.0",
+		DefaultValue: "50",
+		Doc:          "the min delay in milliseconds before a read is hedged",
+		Export:       true,
+	}
+	p.HedgedReadMinDelayMs.Init(base.mgr)
 }
@@ -159,6 +159,24 @@ func TestServiceParam(t *testing.T) {
 		t.Logf("rocksmq path = %s", Params.Path.GetValue())
 	})
 
+	t.Run("test localStorageConfig", func(t *testing.T) {
+		Params := &SParams.LocalStorageCfg
+
+		assert.True(t, Params.DiskWatchdogEnabled.GetAsBool())
+		assert.Equal(t, 10*time.Second, Params.DiskWatchdogCheckInterval.GetAsDuration(time.Second))
+		assert.Equal(t, 0.9, Params.DiskWatchdogHighWatermark.GetAsFloat())
+		assert.Equal(t, 0.8, Params.DiskWatchdogLowWatermark.GetAsFloat())
+
+		assert.False(t, Params.TieredEnabled.GetAsBool())
+		assert.Equal(t, "", Params.TieredPath.GetValue())
+		assert.Equal(t, time.Hour, Params.TieredHotAge.GetAsDuration(time.Second))
+		assert.Equal(t, int64(10240), Params.TieredHotCapacity.GetAsInt64())
+		assert.Equal(t, 10*time.Second, Params.TieredEvictInterval.GetAsDuration(time.Second))
+		assert.Empty(t, Params.TieredCollectionHotAge.GetAsJSONMap())
+
+		assert.True(t, Params.MmapReadEnabled.GetAsBool())
+	})
+
 	t.Run("test kafkaConfig", func(t *testing.T) {
 		// test default value
 		{
@@ -195,5 +213,34 @@ func TestServiceParam(t *testing.T) {
 		t.Logf("Minio BucketName = %s", Params.BucketName.GetValue())
 
 		t.Logf("Minio rootpath = %s", Params.RootPath.GetValue())
+
+		assert.Equal(t, int64(0), Params.UploadPartSize.GetAsInt64())
+		assert.Equal(t, 4, Params.UploadConcurrency.GetAsInt())
+		assert.Equal(t, int64(16777216), Params.UploadThreshold.GetAsInt64())
+		assert.Equal(t, float64(0), Params.UploadBandwidthLimitMB.GetAsFloat())
+		assert.Equal(t, "", Params.SSEType.GetValue())
+		assert.Equal(t, "", Params.SSEKMSKeyID.GetValue())
+		assert.Equal(t, "", Params.StorageClass.GetValue())
+		assert.Empty(t, Params.ObjectTags.GetAsJSONMap())
+		assert.Equal(t, "", Params.ObjectPolicies.GetValue())
+		assert.Equal(t, "", Params.GcpCredentialJSON.GetValue())
+		assert.False(t, Params.HedgedReadEnabled.GetAsBool())
+		assert.Equal(t, 0.99, Params.HedgedReadPercentile.GetAsFloat())
+		assert.Equal(t, int64(20), Params.HedgedReadMinDelayMs.GetAsInt64())
+	})
+
+	t.Run("test hdfs config", func(t *testing.T) {
+		Params := &SParams.HDFSCfg
+
+		assert.Equal(t, "localhost:9870", Params.Address.GetValue())
+		assert.False(t, Params.UseSSL.GetAsBool())
+		assert.Equal(t, "/milvus", Params.BaseDir.GetValue())
+		assert.Equal(t, "", Params.DelegationToken.GetValue())
+		assert.Equal(t, int64(134217728), Params.BlockSize.GetAsInt64())
+		assert.Equal(t, 0, Params.Replication.GetAsInt())
+		assert.Equal(t, int64(60000), Params.RequestTimeoutMs.GetAsInt64())
+		assert.False(t, Params.HedgedReadEnabled.GetAsBool())
+		assert.Equal(t, 0.99, Params.HedgedReadPercentile.GetAsFloat())
+		assert.Equal(t, int64(50), Params.HedgedReadMinDelayMs.GetAsInt64())
 	})
 }
@@ -0,0 +1,67 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package placement defines the interface of the plugins customizing the placement of the channels
+// and the segments on the datanodes, such as bin-packing by memory, rack awareness or label affinity.
+//
+// A placement plugin is a go plugin built with `go build -buildmode=plugin`, which exports a variable
+// named by PolicySymbol implementing Policy. DataCoord loads the plugin from dataCoord.placement.soPath,
+// and initializes it with the params under dataCoord.placement.params.
+package placement
+
+// PolicySymbol is the name of the Policy variable exported by the placement plugins.
+const PolicySymbol = "MilvusPlacementPolicy"
+
+// Node is a datanode the channels and the segments are placed on.
+type Node struct {
+	NodeID  int64
+	Address string
+	// Labels are the server labels of the node, registered from the MILVUS_SERVER_LABEL_ prefixed envs.
+	Labels map[string]string
+	// Channels are the names of the channels watched by the node.
+	Channels []string
+}
+
+// Channel is a virtual channel to be watched by a datanode.
+type Channel struct {
+	Name         string
+	CollectionID int64
+}
+
+// Segment is a sealed segment to be compacted by a datanode.
+type Segment struct {
+	ID           int64
+	CollectionID int64
+	PartitionID  int64
+	Channel      string
+	// Size is the size of the insert binlogs of the segment in bytes.
+	Size int64
+}
+
+// Policy is the placement strategy of a plugin, the placements not made by the policy are made
+// by the default policies of DataCoord. The methods are called concurrently.
+type Policy interface {
+	// Init initializes the policy with the params under dataCoord.placement.params, the keys are in lower case.
+	Init(params map[string]string) error
+
+	// AssignChannels returns the nodes to watch the channels by the channel names, @nodes are the candidates.
+	// It's called when the channels are created, or released by the nodes gone or failed to watch them.
+	AssignChannels(nodes []Node, channels []Channel) map[string]int64
+
+	// AssignSegments returns the node to compact the segments, @nodes are the idle candidates
+	// not watching the channel of the segments. It returns false if no node is picked.
+	AssignSegments(nodes []Node, segments []Segment) (int64, bool)
+}
@@ -99,6 +99,17 @@ func HashPK2Channels(primaryKeys *schemapb.IDs, shardNames []string) []uint32 {
 	return hashValues
 }
 
+// HashInt64PK2Bucket hashes an int64 primary key to a bucket of the collection bucketing segments by primary key.
+func HashInt64PK2Bucket(pk int64, numBuckets int64) int64 {
+	value, _ := Hash32Int64(pk)
+	return int64(value) % numBuckets
+}
+
+// HashVarCharPK2Bucket hashes a varchar primary key to a bucket of the collection bucketing segments by primary key.
+func HashVarCharPK2Bucket(pk string, numBuckets int64) int64 {
+	return int64(HashString2Uint32(pk)) % numBuckets
+}
+
 // HashKey2Partitions hash partition keys to partitions
 func HashKey2Partitions(keys *schemapb.FieldData, partitionNames []string) ([]uint32, error) {
 	var hashValues []uint32
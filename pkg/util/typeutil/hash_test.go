@@ -167,6 +167,19 @@ func TestHashPK2Channels(t *testing.T) {
 	assert.Equal(t, ret[1], ret[2])
 }
 
+func TestHashPK2Bucket(t *testing.T) {
+	for _, pk := range []int64{0, 1, -1, 100, 1 << 40} {
+		bucket := HashInt64PK2Bucket(pk, 16)
+		assert.True(t, bucket >= 0 && bucket < 16)
+		assert.Equal(t, bucket, HashInt64PK2Bucket(pk, 16))
+	}
+	for _, pk := range []string{"", "ab", "milvus"} {
+		bucket := HashVarCharPK2Bucket(pk, 16)
+		assert.True(t, bucket >= 0 && bucket < 16)
+	}
+	assert.EqualValues(t, 0, HashVarCharPK2Bucket("milvus", 1))
+}
+
 func TestRearrangePartitionsForPartitionKey(t *testing.T) {
 	// invalid partition name
 	partitions := map[string]int64{
@@ -164,6 +164,15 @@ function test_metastore()
 go test -race -cover -tags dynamic "${MILVUS_DIR}/metastore/..." -failfast -count=1 -ldflags="-r ${RPATH}"
 }
 
+# tests of the retry and recovery paths with the faults injected into storage and mq
+function test_faultinject()
+{
+go test -race -cover -tags dynamic,faultinject "${PKG_DIR}/util/faultinject/..." -failfast -count=1 -ldflags="-r ${RPATH}"
+go test -race -cover -tags dynamic,faultinject "${PKG_DIR}/mq/msgstream/..." -run FaultInject -failfast -count=1 -ldflags="-r ${RPATH}"
+go test -race -cover -tags dynamic,faultinject "${MILVUS_DIR}/storage" -run FaultInject -failfast -count=1 -ldflags="-r ${RPATH}"
+go test -race -cover -tags dynamic,faultinject "${MILVUS_DIR}/datanode/io/..." -failfast -count=1 -ldflags="-r ${RPATH}"
+}
+
 function test_all()
 {
 test_proxy
@@ -183,6 +192,7 @@ test_config
 test_util
 test_pkg
 test_metastore
+test_faultinject
 }
 
 
@@ -239,6 +249,9 @@ case "${TEST_TAG}" in
     metastore)
 	test_metastore
         ;;
+    faultinject)
+	test_faultinject
+        ;;
     *)   echo "Test All";
 	test_all
     ;;